@@ -0,0 +1,58 @@
+package app
+
+import (
+	"strings"
+
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/model"
+	"github.com/ggonzalez94/defi-cli/internal/paymasters"
+	"github.com/ggonzalez94/defi-cli/internal/schema"
+	"github.com/spf13/cobra"
+)
+
+func (s *runtimeState) newAACommand() *cobra.Command {
+	root := &cobra.Command{Use: "aa", Short: "Account abstraction (ERC-4337) commands"}
+
+	var chainArg, mode string
+	paymastersCmd := &cobra.Command{
+		Use:   "paymasters",
+		Short: "List configured and known paymasters for a chain (no keys required)",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			chain, err := id.ParseChain(chainArg)
+			if err != nil {
+				return err
+			}
+			if !chain.IsEVM() {
+				return clierr.New(clierr.CodeUnsupported, "aa paymasters is only supported for EVM chains")
+			}
+			mode = strings.ToLower(strings.TrimSpace(mode))
+			if mode != "" && mode != paymasters.ModeSponsored && mode != paymasters.ModeERC20 {
+				return clierr.New(clierr.CodeUsage, "--mode must be sponsored or erc20")
+			}
+			items, err := paymasters.ForChain(chain, s.settings.Paymasters)
+			if err != nil {
+				return clierr.Wrap(clierr.CodeUsage, "resolve configured paymasters", err)
+			}
+			if mode != "" {
+				filtered := items[:0]
+				for _, item := range items {
+					if item.Mode == mode {
+						filtered = append(filtered, item)
+					}
+				}
+				items = filtered
+			}
+			return s.emitSuccess(trimRootPath(cmd.CommandPath()), items, nil, cacheMetaBypass(), nil, false)
+		},
+	}
+	paymastersCmd.Flags().StringVar(&chainArg, "chain", "", "Chain id/name/CAIP-2")
+	paymastersCmd.Flags().StringVar(&mode, "mode", "", "Filter by sponsorship mode (sponsored|erc20)")
+	_ = paymastersCmd.MarkFlagRequired("chain")
+	_ = schema.SetFlagMetadata(paymastersCmd.Flags(), "mode", schema.FlagMetadata{Enum: []string{paymasters.ModeSponsored, paymasters.ModeERC20}})
+	paymastersResponse := schema.SchemaFromType([]model.Paymaster{})
+	_ = schema.SetCommandMetadata(paymastersCmd, schema.CommandMetadata{Response: &paymastersResponse})
+
+	root.AddCommand(paymastersCmd)
+	return root
+}
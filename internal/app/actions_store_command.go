@@ -0,0 +1,214 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ggonzalez94/defi-cli/internal/clock"
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/execution"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/schema"
+	"github.com/spf13/cobra"
+)
+
+// actionFilterFlags are the filters shared by actions list and export.
+type actionFilterFlags struct {
+	status  string
+	intent  string
+	chain   string
+	since   string
+	address string
+}
+
+func (f *actionFilterFlags) register(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&f.status, "status", "", "Optional action status filter")
+	cmd.Flags().StringVar(&f.intent, "intent", "", "Optional intent filter (swap|bridge|lend|...); a family such as lend matches lend_supply")
+	cmd.Flags().StringVar(&f.chain, "chain", "", "Optional chain filter (CAIP-2, chain ID, or slug)")
+	cmd.Flags().StringVar(&f.since, "since", "", "Only actions updated since an RFC3339 time or a lookback window (for example 24h,7d)")
+	cmd.Flags().StringVar(&f.address, "address", "", "Only actions sent from or to this address")
+	_ = schema.SetFlagMetadata(cmd.Flags(), "chain", schema.FlagMetadata{Format: "chain"})
+	_ = schema.SetFlagMetadata(cmd.Flags(), "address", schema.FlagMetadata{Format: "evm-address"})
+}
+
+// cacheKeyParts are the filter values that identify a query, for paging.
+func (f actionFilterFlags) cacheKeyParts() map[string]any {
+	return map[string]any{
+		"status":  strings.TrimSpace(f.status),
+		"intent":  strings.TrimSpace(f.intent),
+		"chain":   strings.TrimSpace(f.chain),
+		"since":   strings.TrimSpace(f.since),
+		"address": strings.ToLower(strings.TrimSpace(f.address)),
+	}
+}
+
+func (f actionFilterFlags) filter(limit int) (execution.ActionFilter, error) {
+	filter := execution.ActionFilter{
+		Status:     strings.TrimSpace(f.status),
+		IntentType: strings.ToLower(strings.TrimSpace(f.intent)),
+		Limit:      limit,
+	}
+	if chainArg := strings.TrimSpace(f.chain); chainArg != "" {
+		chain, err := id.ParseChain(chainArg)
+		if err != nil {
+			return execution.ActionFilter{}, err
+		}
+		filter.ChainID = chain.CAIP2
+	}
+	if since := strings.TrimSpace(f.since); since != "" {
+		if ts, err := parseRFC3339(since); err == nil {
+			filter.Since = ts
+		} else {
+			window, err := parseLookbackWindow(since)
+			if err != nil {
+				return execution.ActionFilter{}, clierr.New(clierr.CodeUsage, "--since must be an RFC3339 time or a window such as 24h or 7d")
+			}
+			filter.Since = clock.Now().Add(-window)
+		}
+	}
+	if address := strings.TrimSpace(f.address); address != "" {
+		if !common.IsHexAddress(address) {
+			return execution.ActionFilter{}, clierr.New(clierr.CodeUsage, "--address must be an EVM address")
+		}
+		filter.Address = address
+	}
+	return filter, nil
+}
+
+// actionsPruneResult reports an actions prune run.
+type actionsPruneResult struct {
+	OlderThan string   `json:"older_than"`
+	Cutoff    string   `json:"cutoff"`
+	DryRun    bool     `json:"dry_run"`
+	Pruned    int      `json:"pruned"`
+	ActionIDs []string `json:"action_ids"`
+}
+
+func (s *runtimeState) newActionsPruneCommand() *cobra.Command {
+	var olderThan string
+	var dryRun bool
+	cmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Delete persisted actions not updated within a retention window",
+		Long:  "Deletes actions last updated before --older-than ago. Running actions are kept. Spend and audit logs are not touched, so guardrail windows still count pruned actions.",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if strings.TrimSpace(olderThan) == "" {
+				return clierr.New(clierr.CodeUsage, "--older-than is required")
+			}
+			window, err := parseLookbackWindow(olderThan)
+			if err != nil {
+				return clierr.Wrap(clierr.CodeUsage, "parse --older-than", err)
+			}
+			if err := s.ensureActionStore(); err != nil {
+				return err
+			}
+			cutoff := clock.Now().UTC().Add(-window)
+			ids, err := s.actionStore.Prune(cutoff, dryRun)
+			if err != nil {
+				return clierr.Wrap(clierr.CodeInternal, "prune actions", err)
+			}
+			result := actionsPruneResult{
+				OlderThan: strings.TrimSpace(olderThan),
+				Cutoff:    cutoff.Format(time.RFC3339),
+				DryRun:    dryRun,
+				Pruned:    len(ids),
+				ActionIDs: ids,
+			}
+			return s.emitSuccess(trimRootPath(cmd.CommandPath()), result, nil, cacheMetaBypass(), nil, false)
+		},
+	}
+	cmd.Flags().StringVar(&olderThan, "older-than", "", "Retention window (for example 30d, 12w, 720h)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "List the actions that would be deleted without deleting them")
+	response := schema.SchemaFromType(actionsPruneResult{})
+	_ = schema.SetCommandMetadata(cmd, schema.CommandMetadata{Mutation: true, Response: &response})
+	return cmd
+}
+
+func (s *runtimeState) newActionsExportCommand() *cobra.Command {
+	var filters actionFilterFlags
+	var format string
+	var limit int
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export persisted actions for audit pipelines",
+		Long:  "Exports every matching action, most recently updated first. --format json returns them in the usual envelope; --format ndjson streams one action per line with no envelope.",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			format = strings.ToLower(strings.TrimSpace(format))
+			if format != "json" && format != "ndjson" {
+				return clierr.New(clierr.CodeUsage, "--format must be one of: json,ndjson")
+			}
+			if limit < 0 {
+				return clierr.New(clierr.CodeUsage, "--limit must be >= 0")
+			}
+			filter, err := filters.filter(limit)
+			if err != nil {
+				return err
+			}
+			if err := s.ensureActionStore(); err != nil {
+				return err
+			}
+			if format == "json" {
+				items, err := s.actionStore.Query(filter)
+				if err != nil {
+					return clierr.Wrap(clierr.CodeInternal, "export actions", err)
+				}
+				return s.emitSuccess(trimRootPath(cmd.CommandPath()), items, nil, cacheMetaBypass(), nil, false)
+			}
+			out := cmd.OutOrStdout()
+			err = s.actionStore.Scan(filter, func(action execution.Action) error {
+				line, err := json.Marshal(action)
+				if err != nil {
+					return err
+				}
+				_, err = out.Write(append(line, '\n'))
+				return err
+			})
+			if err != nil {
+				return clierr.Wrap(clierr.CodeInternal, "export actions", err)
+			}
+			return nil
+		},
+	}
+	filters.register(cmd)
+	cmd.Flags().StringVar(&format, "format", "json", "Output format (json|ndjson)")
+	cmd.Flags().IntVar(&limit, "limit", 0, "Maximum actions to export (0 exports all)")
+	_ = schema.SetFlagMetadata(cmd.Flags(), "format", schema.FlagMetadata{Enum: []string{"json", "ndjson"}})
+	return cmd
+}
+
+func (s *runtimeState) newActionsEncryptCommand() *cobra.Command {
+	var keySource string
+	cmd := &cobra.Command{
+		Use:   "encrypt",
+		Short: "Encrypt the persisted action store at rest",
+		Long: "Encrypts action, template, schedule, and audit payloads with AES-256-GCM and records the key source so later commands decrypt transparently. " +
+			"--key-source keyring generates a key in the OS keyring (macOS Keychain or Linux Secret Service); --key-source passphrase derives it from " +
+			execution.EnvActionsPassphrase + " or " + execution.EnvActionsPassphraseFile + ". " +
+			"Migrates a plaintext store in place; running it again re-encrypts under the new key source. Index columns and the spend log stay readable.",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			source := strings.ToLower(strings.TrimSpace(keySource))
+			if source != execution.StoreKeySourceKeyring && source != execution.StoreKeySourcePassphrase {
+				return clierr.New(clierr.CodeUsage, "--key-source must be one of: keyring,passphrase")
+			}
+			if err := s.ensureActionStore(); err != nil {
+				return err
+			}
+			result, err := s.actionStore.Encrypt(source)
+			if err != nil {
+				if errors.Is(err, execution.ErrStoreKeyUnavailable) {
+					return clierr.Wrap(clierr.CodeAuth, "encrypt action store", err)
+				}
+				return clierr.Wrap(clierr.CodeInternal, "encrypt action store", err)
+			}
+			return s.emitSuccess(trimRootPath(cmd.CommandPath()), result, nil, cacheMetaBypass(), nil, false)
+		},
+	}
+	cmd.Flags().StringVar(&keySource, "key-source", execution.StoreKeySourceKeyring, "Where the encryption key comes from (keyring|passphrase)")
+	_ = schema.SetFlagMetadata(cmd.Flags(), "key-source", schema.FlagMetadata{Enum: []string{execution.StoreKeySourceKeyring, execution.StoreKeySourcePassphrase}})
+	response := schema.SchemaFromType(execution.StoreEncryptResult{})
+	_ = schema.SetCommandMetadata(cmd, schema.CommandMetadata{Mutation: true, Response: &response})
+	return cmd
+}
@@ -0,0 +1,84 @@
+package app
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/model"
+	"github.com/ggonzalez94/defi-cli/internal/providers"
+	"github.com/ggonzalez94/defi-cli/internal/schema"
+	"github.com/spf13/cobra"
+)
+
+const maxActivityLimit = 100
+
+func (s *runtimeState) newActivityCommand() *cobra.Command {
+	var addressArg string
+	var chainArg string
+	var limit int
+
+	cmd := &cobra.Command{
+		Use:   "activity",
+		Short: "List recent transfers, swaps, and protocol calls for an address",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			address := strings.TrimSpace(addressArg)
+			if address == "" {
+				return clierr.New(clierr.CodeUsage, "--address is required")
+			}
+			if !common.IsHexAddress(address) {
+				return clierr.New(clierr.CodeUsage, "--address must be a valid EVM hex address")
+			}
+			if strings.TrimSpace(chainArg) == "" {
+				return clierr.New(clierr.CodeUsage, "--chain is required")
+			}
+			if limit <= 0 || limit > maxActivityLimit {
+				return clierr.New(clierr.CodeUsage, "--limit must be between 1 and 100")
+			}
+			chain, err := id.ParseChain(chainArg)
+			if err != nil {
+				return err
+			}
+			if !chain.IsEVM() {
+				return clierr.New(clierr.CodeUnsupported, "activity currently supports EVM chains only")
+			}
+			address = strings.ToLower(address)
+
+			key := cacheKey(trimRootPath(cmd.CommandPath()), map[string]any{
+				"chain":   chain.CAIP2,
+				"address": address,
+				"limit":   limit,
+			})
+			return s.runCachedCommand(trimRootPath(cmd.CommandPath()), key, 30*time.Second, func(ctx context.Context) (any, []model.ProviderStatus, []string, bool, error) {
+				if s.activityProvider == nil {
+					return nil, nil, nil, false, clierr.New(clierr.CodeUnsupported, "no activity provider is configured")
+				}
+				start := time.Now()
+				entries, err := s.activityProvider.Activity(ctx, providers.ActivityRequest{Chain: chain, Address: address, Limit: limit})
+				name := s.activityProvider.Info().Name
+				status := []model.ProviderStatus{{Name: name, Status: statusFromErr(err), LatencyMS: time.Since(start).Milliseconds()}}
+				if err != nil {
+					return nil, status, nil, false, err
+				}
+				return model.ActivityFeed{
+					Address:   address,
+					ChainID:   chain.CAIP2,
+					Provider:  name,
+					Entries:   entries,
+					FetchedAt: s.runner.now().UTC().Format(time.RFC3339),
+				}, status, nil, false, nil
+			})
+		},
+	}
+	cmd.Flags().StringVar(&addressArg, "address", "", "Wallet address to list activity for")
+	cmd.Flags().StringVar(&chainArg, "chain", "", "Chain identifier (CAIP-2, chain ID, or slug)")
+	cmd.Flags().IntVar(&limit, "limit", 20, "Maximum entries to return (1-100)")
+	_ = schema.SetFlagMetadata(cmd.Flags(), "address", schema.FlagMetadata{Required: true, Format: "evm-address"})
+	_ = schema.SetFlagMetadata(cmd.Flags(), "chain", schema.FlagMetadata{Required: true, Format: "chain"})
+	response := schema.SchemaFromType(model.ActivityFeed{})
+	_ = schema.SetCommandMetadata(cmd, schema.CommandMetadata{Response: &response})
+	return cmd
+}
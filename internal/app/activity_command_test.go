@@ -0,0 +1,29 @@
+package app
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestActivityCommandValidatesInputs(t *testing.T) {
+	cases := []struct {
+		name string
+		args []string
+		code int
+	}{
+		{name: "missing address", args: []string{"activity", "--chain", "1"}, code: 2},
+		{name: "invalid address", args: []string{"activity", "--chain", "1", "--address", "not-an-address"}, code: 2},
+		{name: "missing chain", args: []string{"activity", "--address", "0x000000000000000000000000000000000000dEaD"}, code: 2},
+		{name: "limit too large", args: []string{"activity", "--chain", "1", "--address", "0x000000000000000000000000000000000000dEaD", "--limit", "101"}, code: 2},
+		{name: "solana", args: []string{"activity", "--chain", "solana", "--address", "0x000000000000000000000000000000000000dEaD"}, code: 13},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			code := NewRunnerWithWriters(&stdout, &stderr).Run(tc.args)
+			if code != tc.code {
+				t.Fatalf("expected exit %d, got %d stderr=%s", tc.code, code, stderr.String())
+			}
+		})
+	}
+}
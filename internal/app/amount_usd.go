@@ -0,0 +1,72 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/execution"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+)
+
+// normalizeAmountInput resolves --amount, --amount-decimal, or --amount-usd
+// to base and decimal units of asset. amountUSD is the parsed USD notional,
+// or zero when the amount was given in token units.
+func (s *runtimeState) normalizeAmountInput(ctx context.Context, chain id.Chain, asset id.Asset, amountBase, amountDecimal, amountUSDArg string, decimals int) (base, decimal string, amountUSD float64, err error) {
+	amountUSDArg = strings.TrimSpace(amountUSDArg)
+	if amountUSDArg == "" {
+		base, decimal, err = id.NormalizeAmount(amountBase, amountDecimal, decimals)
+		return base, decimal, 0, err
+	}
+	if amountBase != "" || amountDecimal != "" {
+		return "", "", 0, clierr.New(clierr.CodeUsage, "use only one of --amount, --amount-decimal, or --amount-usd")
+	}
+	notional, ok := new(big.Rat).SetString(amountUSDArg)
+	if !ok || notional.Sign() <= 0 || strings.ContainsAny(amountUSDArg, "eE/") {
+		return "", "", 0, clierr.New(clierr.CodeUsage, "--amount-usd must be a positive decimal like 250.50")
+	}
+	priceUSD, err := s.tokenPriceUSD(ctx, chain, asset)
+	if err != nil {
+		return "", "", 0, clierr.Wrap(clierr.CodeUnavailable, fmt.Sprintf("price %s for --amount-usd", asset.Symbol), err)
+	}
+	base, err = usdToBaseUnits(notional, priceUSD, decimals)
+	if err != nil {
+		return "", "", 0, err
+	}
+	base, decimal, err = id.NormalizeAmount(base, "", decimals)
+	if err != nil {
+		return "", "", 0, err
+	}
+	amountUSD, _ = notional.Float64()
+	return base, decimal, amountUSD, nil
+}
+
+// usdToBaseUnits converts a USD notional to base units at priceUSD,
+// rounding down so the converted amount never exceeds the notional.
+func usdToBaseUnits(notional *big.Rat, priceUSD float64, decimals int) (string, error) {
+	price := new(big.Rat)
+	if priceUSD <= 0 || price.SetFloat64(priceUSD) == nil {
+		return "", clierr.New(clierr.CodeUnavailable, "price unavailable for --amount-usd")
+	}
+	units := new(big.Rat).Quo(notional, price)
+	units.Mul(units, new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)))
+	base := new(big.Int).Quo(units.Num(), units.Denom())
+	if base.Sign() <= 0 {
+		return "", clierr.New(clierr.CodeUsage, "--amount-usd is below the smallest unit of the asset")
+	}
+	return base.String(), nil
+}
+
+// applyActionAmountUSD records the --amount-usd notional of a planned action
+// next to its base-unit input amount.
+func applyActionAmountUSD(action *execution.Action, amountUSD float64) {
+	if amountUSD <= 0 {
+		return
+	}
+	if action.Metadata == nil {
+		action.Metadata = map[string]any{}
+	}
+	action.Metadata["amount_usd"] = amountUSD
+}
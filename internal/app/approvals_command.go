@@ -24,6 +24,7 @@ func (s *runtimeState) newApprovalsCommand() *cobra.Command {
 		WalletRef     string `json:"wallet" flag:"wallet" format:"identifier"`
 		FromAddress   string `json:"from_address" flag:"from-address" format:"evm-address"`
 		Simulate      bool   `json:"simulate" flag:"simulate"`
+		Preview       bool   `json:"preview" flag:"preview"`
 		RPCURL        string `json:"rpc_url" flag:"rpc-url" format:"url"`
 	}
 	type approvalSubmitArgs struct {
@@ -92,10 +93,12 @@ func (s *runtimeState) newApprovalsCommand() *cobra.Command {
 			if err := s.ensureActionStore(); err != nil {
 				return err
 			}
+			recordPlanInvocation(cmd, &action)
 			if err := s.actionStore.Save(action); err != nil {
 				return clierr.Wrap(clierr.CodeInternal, "persist planned action", err)
 			}
 			s.captureCommandDiagnostics(nil, status, false)
+			attachPlanPreview(&action, plan.Preview)
 			return s.emitSuccess(trimRootPath(cmd.CommandPath()), action, identity.Warnings, cacheMetaBypass(), status, false)
 		},
 	}
@@ -107,6 +110,7 @@ func (s *runtimeState) newApprovalsCommand() *cobra.Command {
 	planCmd.Flags().StringVar(&plan.WalletRef, "wallet", "", "Wallet identifier or name")
 	planCmd.Flags().StringVar(&plan.FromAddress, "from-address", "", "Sender EOA address")
 	planCmd.Flags().BoolVar(&plan.Simulate, "simulate", true, "Include simulation checks during execution")
+	planCmd.Flags().BoolVar(&plan.Preview, "preview", false, "Decode step calldata and attach a human-readable preview")
 	planCmd.Flags().StringVar(&plan.RPCURL, "rpc-url", "", "RPC URL override for the selected chain")
 	_ = planCmd.MarkFlagRequired("chain")
 	_ = planCmd.MarkFlagRequired("asset")
@@ -0,0 +1,486 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/httpx"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/model"
+	"github.com/ggonzalez94/defi-cli/internal/registry"
+	"github.com/ggonzalez94/defi-cli/internal/rpcx"
+	"github.com/ggonzalez94/defi-cli/internal/schema"
+	"github.com/ggonzalez94/defi-cli/internal/tokenlist"
+	"github.com/ggonzalez94/defi-cli/internal/tokenlookup"
+	"github.com/ggonzalez94/defi-cli/internal/tokensafety"
+	"github.com/spf13/cobra"
+)
+
+const (
+	tokenSourceBuiltin = "builtin"
+	tokenSourceUser    = "user"
+)
+
+// loadUserTokens reads the user token list and registers its tokens on top
+// of the bootstrap registry.
+func (s *runtimeState) loadUserTokens() error {
+	path := strings.TrimSpace(s.settings.TokensPath)
+	if path == "" {
+		id.SetUserTokens(nil)
+		return nil
+	}
+	list, err := tokenlist.Load(path)
+	if err != nil {
+		return clierr.Wrap(clierr.CodeUsage, "load user token list", err)
+	}
+	s.userTokens = list
+	id.SetUserTokens(list.Registry())
+	return nil
+}
+
+// tokenLookupWarnings describes every --resolve-unknown lookup of this
+// invocation so agents know which token metadata is not curated.
+func (s *runtimeState) tokenLookupWarnings() []string {
+	if s.tokenLookup == nil {
+		return nil
+	}
+	var warnings []string
+	for _, lookup := range s.tokenLookup.Lookups() {
+		switch {
+		case lookup.Err != nil && lookup.Source == tokenlookup.SourceOnChain:
+			warnings = append(warnings, fmt.Sprintf("could not read token metadata for %s on %s: %v", lookup.Input, lookup.ChainID, lookup.Err))
+		case lookup.Err != nil:
+			// Symbol lookups that fail surface as the command error.
+		case lookup.Source == tokenlookup.SourceOnChain:
+			warnings = append(warnings, fmt.Sprintf("token %s on %s is not in the registry; read %s (%d decimals) from the contract", lookup.Address, lookup.ChainID, lookup.Symbol, lookup.Decimals))
+		default:
+			warnings = append(warnings, fmt.Sprintf("symbol %s on %s is not in the registry; resolved to %s (%d decimals) from token list %s", lookup.Input, lookup.ChainID, lookup.Address, lookup.Decimals, lookup.List))
+		}
+	}
+	return warnings
+}
+
+// tokenLookupSource returns how address was resolved when it came from a
+// --resolve-unknown lookup, or "".
+func (s *runtimeState) tokenLookupSource(chainID, address string) string {
+	if s.tokenLookup == nil {
+		return ""
+	}
+	for _, lookup := range s.tokenLookup.Lookups() {
+		if lookup.Err == nil && lookup.ChainID == chainID && strings.EqualFold(lookup.Address, address) {
+			return lookup.Source
+		}
+	}
+	return ""
+}
+
+func (s *runtimeState) newAssetsAddCommand() *cobra.Command {
+	var chainArg, addressArg, symbolArg, nameArg, tokenListArg string
+	var decimalsArg int
+	cmd := &cobra.Command{
+		Use:   "add",
+		Short: "Add a token, or import a token list, into the user token registry",
+		Long:  "Adds one token (--chain --address --symbol --decimals) or every token of a Uniswap-style token list (--token-list URL or file, optionally filtered by --chain) to the user token list. Tokens already in the built-in registry are skipped.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := s.userTokensPath()
+			if err != nil {
+				return err
+			}
+			var chain id.Chain
+			if strings.TrimSpace(chainArg) != "" {
+				if chain, err = id.ParseChain(chainArg); err != nil {
+					return err
+				}
+			}
+
+			var candidates []tokenlist.Token
+			if source := strings.TrimSpace(tokenListArg); source != "" {
+				if strings.TrimSpace(addressArg) != "" || strings.TrimSpace(symbolArg) != "" {
+					return clierr.New(clierr.CodeUsage, "--token-list cannot be combined with --address or --symbol")
+				}
+				imported, err := s.readTokenList(cmd.Context(), source)
+				if err != nil {
+					return err
+				}
+				listName := strings.TrimSpace(imported.Name)
+				if listName == "" {
+					listName = source
+				}
+				for _, token := range imported.Tokens {
+					token.Source = listName
+					candidates = append(candidates, token)
+				}
+			} else {
+				if chain.CAIP2 == "" {
+					return clierr.New(clierr.CodeUsage, "--chain is required")
+				}
+				if strings.TrimSpace(addressArg) == "" || strings.TrimSpace(symbolArg) == "" {
+					return clierr.New(clierr.CodeUsage, "--address and --symbol are required unless --token-list is set")
+				}
+				if !cmd.Flags().Changed("decimals") {
+					return clierr.New(clierr.CodeUsage, "--decimals is required unless --token-list is set")
+				}
+				chainID, err := tokenlist.TokenChainID(chain)
+				if err != nil {
+					return clierr.Wrap(clierr.CodeUnsupported, "add token", err)
+				}
+				token, err := tokenlist.Token{ChainID: chainID, Address: addressArg, Symbol: symbolArg, Name: nameArg, Decimals: decimalsArg}.Normalize()
+				if err != nil {
+					return clierr.Wrap(clierr.CodeUsage, "add token", err)
+				}
+				if id.IsBuiltinToken(chain.CAIP2, token.Address) {
+					return clierr.New(clierr.CodeUsage, fmt.Sprintf("%s is already in the built-in registry for %s", token.Address, chain.CAIP2))
+				}
+				candidates = append(candidates, token)
+			}
+
+			list := s.userTokens
+			result := model.TokenListChange{Path: path, Tokens: []model.RegistryToken{}}
+			for _, token := range candidates {
+				normalized, err := token.Normalize()
+				if err != nil {
+					result.Skipped++
+					continue
+				}
+				tokenChain, _ := normalized.Chain()
+				if (chain.CAIP2 != "" && tokenChain.CAIP2 != chain.CAIP2) || id.IsBuiltinToken(tokenChain.CAIP2, normalized.Address) {
+					result.Skipped++
+					continue
+				}
+				if list.Upsert(normalized) {
+					result.Updated++
+				} else {
+					result.Added++
+				}
+				result.Tokens = append(result.Tokens, userRegistryToken(normalized))
+			}
+			if result.Added+result.Updated > 0 {
+				if list.Name == "" {
+					list.Name = tokenlist.ListUser
+				}
+				if err := tokenlist.Save(path, list); err != nil {
+					return clierr.Wrap(clierr.CodeInternal, "save user token list", err)
+				}
+				s.userTokens = list
+				id.SetUserTokens(list.Registry())
+			}
+
+			var warnings []string
+			if result.Skipped > 0 {
+				warnings = append(warnings, fmt.Sprintf("skipped %d tokens that were invalid, on another chain, or already built in", result.Skipped))
+			}
+			if strings.TrimSpace(tokenListArg) == "" && len(result.Tokens) == 1 {
+				token := result.Tokens[0]
+				if _, ok := id.KnownToken(token.ChainID, token.Symbol); !ok {
+					warnings = append(warnings, fmt.Sprintf("symbol %s matches more than one token on %s; use the address or --resolve-policy to pick one", token.Symbol, token.ChainID))
+				}
+			}
+			return s.emitSuccess(trimRootPath(cmd.CommandPath()), result, warnings, cacheMetaBypass(), nil, false)
+		},
+	}
+	cmd.Flags().StringVar(&chainArg, "chain", "", "Chain identifier (CAIP-2, chain ID, or slug); filters --token-list imports")
+	cmd.Flags().StringVar(&addressArg, "address", "", "Token contract address or SPL mint")
+	cmd.Flags().StringVar(&symbolArg, "symbol", "", "Token symbol")
+	cmd.Flags().IntVar(&decimalsArg, "decimals", 0, "Token decimals")
+	cmd.Flags().StringVar(&nameArg, "name", "", "Token name")
+	cmd.Flags().StringVar(&tokenListArg, "token-list", "", "Uniswap-style token list URL or file to import")
+	_ = schema.SetFlagMetadata(cmd.Flags(), "chain", schema.FlagMetadata{Format: "chain"})
+	_ = schema.SetFlagMetadata(cmd.Flags(), "address", schema.FlagMetadata{Format: "address"})
+	response := schema.SchemaFromType(model.TokenListChange{})
+	_ = schema.SetCommandMetadata(cmd, schema.CommandMetadata{Mutation: true, Response: &response})
+	return cmd
+}
+
+func (s *runtimeState) newAssetsRemoveCommand() *cobra.Command {
+	var chainArg, assetArg string
+	cmd := &cobra.Command{
+		Use:   "remove",
+		Short: "Remove tokens from the user token registry",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := s.userTokensPath()
+			if err != nil {
+				return err
+			}
+			if strings.TrimSpace(chainArg) == "" || strings.TrimSpace(assetArg) == "" {
+				return clierr.New(clierr.CodeUsage, "--chain and --asset are required")
+			}
+			chain, err := id.ParseChain(chainArg)
+			if err != nil {
+				return err
+			}
+			chainID, err := tokenlist.TokenChainID(chain)
+			if err != nil {
+				return clierr.Wrap(clierr.CodeUnsupported, "remove token", err)
+			}
+			list := s.userTokens
+			removed := list.Remove(chainID, assetArg)
+			if len(removed) == 0 {
+				if id.IsBuiltinToken(chain.CAIP2, assetArg) {
+					return clierr.New(clierr.CodeUsage, "built-in registry tokens cannot be removed")
+				}
+				return clierr.New(clierr.CodeUsage, fmt.Sprintf("%s is not in the user token list for %s", assetArg, chain.CAIP2))
+			}
+			if err := tokenlist.Save(path, list); err != nil {
+				return clierr.Wrap(clierr.CodeInternal, "save user token list", err)
+			}
+			result := model.TokenListChange{Path: path, Removed: len(removed), Tokens: []model.RegistryToken{}}
+			for _, token := range removed {
+				if normalized, err := token.Normalize(); err == nil {
+					token = normalized
+				}
+				result.Tokens = append(result.Tokens, userRegistryToken(token))
+			}
+			return s.emitSuccess(trimRootPath(cmd.CommandPath()), result, nil, cacheMetaBypass(), nil, false)
+		},
+	}
+	cmd.Flags().StringVar(&chainArg, "chain", "", "Chain identifier (CAIP-2, chain ID, or slug)")
+	cmd.Flags().StringVar(&assetArg, "asset", "", "Token symbol or address to remove")
+	_ = schema.SetFlagMetadata(cmd.Flags(), "chain", schema.FlagMetadata{Required: true, Format: "chain"})
+	_ = schema.SetFlagMetadata(cmd.Flags(), "asset", schema.FlagMetadata{Required: true})
+	response := schema.SchemaFromType(model.TokenListChange{})
+	_ = schema.SetCommandMetadata(cmd, schema.CommandMetadata{Mutation: true, Response: &response})
+	return cmd
+}
+
+func (s *runtimeState) newAssetsListCommand() *cobra.Command {
+	var chainArg, sourceArg string
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List registry tokens, built-in and user-added",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			source := strings.ToLower(strings.TrimSpace(sourceArg))
+			if source != "all" && source != tokenSourceBuiltin && source != tokenSourceUser {
+				return clierr.New(clierr.CodeUsage, "--source must be all, builtin, or user")
+			}
+			chainIDs := id.TokenChainIDs()
+			if strings.TrimSpace(chainArg) != "" {
+				chain, err := id.ParseChain(chainArg)
+				if err != nil {
+					return err
+				}
+				chainIDs = []string{chain.CAIP2}
+			}
+
+			names := map[string]string{}
+			for _, token := range s.userTokens.Tokens {
+				if normalized, err := token.Normalize(); err == nil {
+					chain, _ := normalized.Chain()
+					names[chain.CAIP2+"|"+normalized.Address] = normalized.Name
+				}
+			}
+			tokens := []model.RegistryToken{}
+			for _, chainID := range chainIDs {
+				for _, token := range id.ChainTokens(chainID) {
+					row := model.RegistryToken{
+						ChainID:  chainID,
+						Symbol:   token.Symbol,
+						AssetID:  registryAssetID(chainID, token.Address),
+						Address:  token.Address,
+						Decimals: token.Decimals,
+						Source:   tokenSourceBuiltin,
+					}
+					if id.IsUserToken(chainID, token.Address) {
+						row.Source = tokenSourceUser
+						row.Name = names[chainID+"|"+token.Address]
+						if len(token.Lists) > 0 {
+							row.List = token.Lists[0]
+						}
+					}
+					if source == "all" || source == row.Source {
+						tokens = append(tokens, row)
+					}
+				}
+			}
+			return s.emitSuccess(trimRootPath(cmd.CommandPath()), tokens, nil, cacheMetaBypass(), nil, false)
+		},
+	}
+	cmd.Flags().StringVar(&chainArg, "chain", "", "Only list tokens on this chain")
+	cmd.Flags().StringVar(&sourceArg, "source", "all", "Token source: all, builtin, or user")
+	_ = schema.SetFlagMetadata(cmd.Flags(), "chain", schema.FlagMetadata{Format: "chain"})
+	_ = schema.SetFlagMetadata(cmd.Flags(), "source", schema.FlagMetadata{Enum: []string{"all", tokenSourceBuiltin, tokenSourceUser}})
+	response := schema.SchemaFromType([]model.RegistryToken{})
+	_ = schema.SetCommandMetadata(cmd, schema.CommandMetadata{Response: &response})
+	return cmd
+}
+
+func (s *runtimeState) newAssetsInspectCommand() *cobra.Command {
+	var chainArg, assetArg, rpcURLArg string
+	cmd := &cobra.Command{
+		Use:   "inspect",
+		Short: "Check an ERC-20 before trading: metadata, proxy/owner state, verification, and risk flags",
+		Long:  "Reads decimals, total supply, owner, and EIP-1967 proxy state over RPC, looks up source verification on the explorer (DEFI_ETHERSCAN_API_KEY), and flags privileged functions that enable honeypots or transfer taxes. Flags are heuristics from bytecode selectors and the verified ABI, not a simulation.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			chain, asset, err := parseChainAsset(chainArg, assetArg)
+			if err != nil {
+				return err
+			}
+			if !chain.IsEVM() {
+				return clierr.New(clierr.CodeUnsupported, "assets inspect supports only EVM chains")
+			}
+			if asset.IsNative() {
+				return clierr.New(clierr.CodeUsage, "the native gas token has no contract to inspect")
+			}
+			key := cacheKey(trimRootPath(cmd.CommandPath()), map[string]any{
+				"chain":   chain.CAIP2,
+				"asset":   asset.AssetID,
+				"rpc_url": strings.TrimSpace(rpcURLArg),
+			})
+			return s.runCachedCommand(trimRootPath(cmd.CommandPath()), key, 5*time.Minute, func(ctx context.Context) (any, []model.ProviderStatus, []string, bool, error) {
+				return s.inspectToken(ctx, chain, asset, rpcURLArg)
+			})
+		},
+	}
+	cmd.Flags().StringVar(&chainArg, "chain", "", "EVM chain identifier (CAIP-2, chain ID, or slug)")
+	cmd.Flags().StringVar(&assetArg, "asset", "", "Token symbol/address/CAIP-19")
+	cmd.Flags().StringVar(&rpcURLArg, "rpc-url", "", "RPC URL override")
+	_ = cmd.MarkFlagRequired("chain")
+	_ = cmd.MarkFlagRequired("asset")
+	_ = schema.SetFlagMetadata(cmd.Flags(), "chain", schema.FlagMetadata{Format: "chain"})
+	_ = schema.SetFlagMetadata(cmd.Flags(), "rpc-url", schema.FlagMetadata{Format: "url"})
+	response := schema.SchemaFromType(model.TokenSafetyReport{})
+	_ = schema.SetCommandMetadata(cmd, schema.CommandMetadata{Response: &response})
+	return cmd
+}
+
+// inspectToken builds the assets inspect report. Explorer failures (for
+// example a missing API key) leave verification unknown and mark the result
+// partial rather than failing the command.
+func (s *runtimeState) inspectToken(ctx context.Context, chain id.Chain, asset id.Asset, rpcURLArg string) (any, []model.ProviderStatus, []string, bool, error) {
+	rpcURL, err := registry.ResolveRPCURL(rpcURLArg, chain.EVMChainID)
+	if err != nil {
+		return nil, nil, nil, false, clierr.Wrap(clierr.CodeUnsupported, "resolve rpc", err)
+	}
+	start := time.Now()
+	client, err := rpcx.DialContext(ctx, rpcURL)
+	if err != nil {
+		return nil, nil, nil, false, clierr.Wrap(clierr.CodeUnavailable, "dial rpc", err)
+	}
+	defer client.Close()
+	report, code, err := tokensafety.Inspect(ctx, client, chain, common.HexToAddress(asset.Address))
+	statuses := []model.ProviderStatus{{Name: "rpc:" + chain.Slug, Status: statusFromErr(err), LatencyMS: time.Since(start).Milliseconds()}}
+	if err != nil {
+		return nil, statuses, nil, false, err
+	}
+
+	var warnings []string
+	var abis []string
+	partial := false
+	if s.contractSourceProvider == nil {
+		warnings = append(warnings, "no explorer configured; source verification was not checked")
+	} else {
+		start := time.Now()
+		abis, err = s.applyContractSources(ctx, chain, &report)
+		statuses = append(statuses, model.ProviderStatus{Name: s.contractSourceProvider.Info().Name, Status: statusFromErr(err), LatencyMS: time.Since(start).Milliseconds()})
+		if err != nil {
+			partial = true
+			warnings = append(warnings, fmt.Sprintf("source verification was not checked: %v", err))
+		}
+	}
+	tokensafety.Assess(&report, code, abis...)
+	report.FetchedAt = s.runner.now().UTC().Format(time.RFC3339)
+	return report, statuses, warnings, partial, nil
+}
+
+// applyContractSources sets report verification from the explorer and
+// returns the verified ABIs. A proxy counts as verified only when its
+// implementation is verified too.
+func (s *runtimeState) applyContractSources(ctx context.Context, chain id.Chain, report *model.TokenSafetyReport) ([]string, error) {
+	source, err := s.contractSourceProvider.ContractSource(ctx, chain, report.Address)
+	if err != nil {
+		return nil, err
+	}
+	report.ContractName = source.ContractName
+	if !report.IsProxy && source.Implementation != "" {
+		report.IsProxy = true
+		report.Implementation = source.Implementation
+	}
+	if !source.Verified {
+		report.Verification = tokensafety.Unverified
+		return nil, nil
+	}
+	abis := []string{source.ABI}
+	report.Verification = tokensafety.Verified
+	if report.Implementation == "" {
+		return abis, nil
+	}
+	implementation, err := s.contractSourceProvider.ContractSource(ctx, chain, report.Implementation)
+	if err != nil {
+		report.Verification = tokensafety.Unknown
+		return abis, err
+	}
+	if !implementation.Verified {
+		report.Verification = tokensafety.Unverified
+		return abis, nil
+	}
+	if implementation.ContractName != "" {
+		report.ContractName = implementation.ContractName
+	}
+	return append(abis, implementation.ABI), nil
+}
+
+func (s *runtimeState) userTokensPath() (string, error) {
+	path := strings.TrimSpace(s.settings.TokensPath)
+	if path == "" {
+		return "", clierr.New(clierr.CodeUsage, "no user token list path; set assets.tokens_path or DEFI_TOKENS_PATH")
+	}
+	return path, nil
+}
+
+// readTokenList loads a token list from an http(s) URL or a local file.
+func (s *runtimeState) readTokenList(ctx context.Context, source string) (tokenlist.List, error) {
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		ctx, cancel := context.WithTimeout(ctx, s.settings.Timeout)
+		defer cancel()
+		list, err := tokenlist.Fetch(ctx, httpx.New(s.settings.Timeout, s.settings.Retries), source)
+		if err != nil {
+			return tokenlist.List{}, clierr.Wrap(clierr.CodeUnavailable, "fetch token list", err)
+		}
+		return list, nil
+	}
+	buf, err := os.ReadFile(source)
+	if err != nil {
+		return tokenlist.List{}, clierr.Wrap(clierr.CodeUsage, "read token list", err)
+	}
+	list, err := tokenlist.Parse(buf)
+	if err != nil {
+		return tokenlist.List{}, clierr.Wrap(clierr.CodeUsage, "read token list", err)
+	}
+	return list, nil
+}
+
+func userRegistryToken(token tokenlist.Token) model.RegistryToken {
+	chain, _ := token.Chain()
+	list := token.Source
+	if list == "" {
+		list = tokenlist.ListUser
+	}
+	return model.RegistryToken{
+		ChainID:  chain.CAIP2,
+		Symbol:   token.Symbol,
+		Name:     token.Name,
+		AssetID:  registryAssetID(chain.CAIP2, token.Address),
+		Address:  token.Address,
+		Decimals: token.Decimals,
+		Source:   tokenSourceUser,
+		List:     list,
+	}
+}
+
+func registryAssetID(chainID, address string) string {
+	chain, err := id.ParseChain(chainID)
+	if err != nil {
+		return ""
+	}
+	opts := id.DefaultResolveOptions()
+	opts.ResolveUnknown = false
+	asset, err := id.ParseAssetWithOptions(address, chain, opts)
+	if err != nil {
+		return ""
+	}
+	return asset.AssetID
+}
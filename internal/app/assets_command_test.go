@@ -0,0 +1,153 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ggonzalez94/defi-cli/internal/id"
+)
+
+func TestAssetsAddListRemove(t *testing.T) {
+	dir := t.TempDir()
+	tokensPath := filepath.Join(dir, "tokens.json")
+	t.Setenv("DEFI_TOKENS_PATH", tokensPath)
+	t.Setenv("DEFI_CACHE_PATH", filepath.Join(dir, "cache.db"))
+	t.Cleanup(func() { id.SetUserTokens(nil) })
+
+	run := func(args ...string) (int, []byte, string) {
+		var stdout, stderr bytes.Buffer
+		code := NewRunnerWithWriters(&stdout, &stderr).Run(append(args, "--results-only"))
+		return code, stdout.Bytes(), stderr.String()
+	}
+
+	if code, _, stderr := run("assets", "resolve", "--chain", "base", "--symbol", "DEGEN"); code != 2 {
+		t.Fatalf("expected DEGEN to be unknown before add, got code=%d stderr=%s", code, stderr)
+	}
+	if code, _, stderr := run("assets", "add", "--chain", "base", "--address", "0x4ED4E862860beD51a9570b96d89aF5E1B0Efefed", "--symbol", "degen", "--decimals", "18", "--name", "Degen"); code != 0 {
+		t.Fatalf("add failed: code=%d stderr=%s", code, stderr)
+	}
+	code, stdout, stderr := run("assets", "resolve", "--chain", "base", "--symbol", "DEGEN")
+	if code != 0 {
+		t.Fatalf("resolve failed: code=%d stderr=%s", code, stderr)
+	}
+	var resolved struct {
+		Address    string `json:"address"`
+		Decimals   int    `json:"decimals"`
+		ResolvedBy string `json:"resolved_by"`
+	}
+	if err := json.Unmarshal(stdout, &resolved); err != nil {
+		t.Fatalf("decode resolve: %v (%s)", err, stdout)
+	}
+	if resolved.Address != "0x4ed4e862860bed51a9570b96d89af5e1b0efefed" || resolved.Decimals != 18 || resolved.ResolvedBy != "user_registry" {
+		t.Fatalf("unexpected resolution %+v", resolved)
+	}
+
+	code, stdout, stderr = run("assets", "list", "--chain", "base", "--source", "user")
+	if code != 0 {
+		t.Fatalf("list failed: code=%d stderr=%s", code, stderr)
+	}
+	var rows []struct {
+		Symbol string `json:"symbol"`
+		Name   string `json:"name"`
+		Source string `json:"source"`
+	}
+	if err := json.Unmarshal(stdout, &rows); err != nil {
+		t.Fatalf("decode list: %v (%s)", err, stdout)
+	}
+	if len(rows) != 1 || rows[0].Symbol != "DEGEN" || rows[0].Name != "Degen" || rows[0].Source != "user" {
+		t.Fatalf("unexpected user tokens %+v", rows)
+	}
+
+	if code, _, _ := run("assets", "add", "--chain", "base", "--address", "0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913", "--symbol", "USDC", "--decimals", "6"); code != 2 {
+		t.Fatalf("expected built-in token add to fail with usage error, got %d", code)
+	}
+	if code, _, stderr := run("assets", "remove", "--chain", "base", "--asset", "DEGEN"); code != 0 {
+		t.Fatalf("remove failed: code=%d stderr=%s", code, stderr)
+	}
+	if code, _, _ := run("assets", "remove", "--chain", "base", "--asset", "DEGEN"); code != 2 {
+		t.Fatalf("expected removing a missing token to fail, got %d", code)
+	}
+}
+
+func TestAssetsAddImportsTokenList(t *testing.T) {
+	dir := t.TempDir()
+	tokensPath := filepath.Join(dir, "tokens.json")
+	listPath := filepath.Join(dir, "list.json")
+	t.Setenv("DEFI_TOKENS_PATH", tokensPath)
+	t.Cleanup(func() { id.SetUserTokens(nil) })
+	list := `{"name":"Example","tokens":[
+		{"chainId":8453,"address":"0x4ED4E862860beD51a9570b96d89aF5E1B0Efefed","symbol":"DEGEN","decimals":18},
+		{"chainId":8453,"address":"0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913","symbol":"USDC","decimals":6},
+		{"chainId":1,"address":"0x6B175474E89094C44Da98b954EedeAC495271d0F","symbol":"DAI","decimals":18}
+	]}`
+	if err := os.WriteFile(listPath, []byte(list), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := NewRunnerWithWriters(&stdout, &stderr).Run([]string{"assets", "add", "--token-list", listPath, "--chain", "base", "--results-only"}); code != 0 {
+		t.Fatalf("import failed: code=%d stderr=%s", code, stderr.String())
+	}
+	var out struct {
+		Added   int `json:"added"`
+		Skipped int `json:"skipped"`
+		Tokens  []struct {
+			Symbol string `json:"symbol"`
+			List   string `json:"list"`
+		} `json:"tokens"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+		t.Fatalf("decode output: %v (%s)", err, stdout.String())
+	}
+	if out.Added != 1 || out.Skipped != 2 || out.Tokens[0].Symbol != "DEGEN" || out.Tokens[0].List != "Example" {
+		t.Fatalf("unexpected import result %+v", out)
+	}
+}
+
+func TestAssetsResolveUnknownSymbolFromTokenList(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"name":"Test List","tokens":[{"chainId":8453,"address":"0x4ED4E862860beD51a9570b96d89aF5E1B0Efefed","symbol":"DEGEN","decimals":18}]}`))
+	}))
+	defer srv.Close()
+	dir := t.TempDir()
+	t.Setenv("DEFI_TOKENS_PATH", filepath.Join(dir, "tokens.json"))
+	t.Setenv("DEFI_TOKEN_LIST_URL", srv.URL)
+	t.Cleanup(func() {
+		id.SetUnknownResolver(nil)
+		id.SetDefaultResolveOptions(id.ResolveOptions{})
+	})
+
+	var stdout, stderr bytes.Buffer
+	if code := NewRunnerWithWriters(&stdout, &stderr).Run([]string{"assets", "resolve", "--chain", "base", "--symbol", "DEGEN"}); code != 2 {
+		t.Fatalf("expected unknown symbol without --resolve-unknown, got code=%d", code)
+	}
+
+	stdout.Reset()
+	if code := NewRunnerWithWriters(&stdout, &stderr).Run([]string{"assets", "resolve", "--chain", "base", "--symbol", "DEGEN", "--resolve-unknown"}); code != 0 {
+		t.Fatalf("resolve failed: code=%d stderr=%s", code, stderr.String())
+	}
+	var env struct {
+		Data struct {
+			Address    string `json:"address"`
+			Decimals   int    `json:"decimals"`
+			ResolvedBy string `json:"resolved_by"`
+		} `json:"data"`
+		Warnings []string `json:"warnings"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &env); err != nil {
+		t.Fatalf("decode output: %v (%s)", err, stdout.String())
+	}
+	if env.Data.Address != "0x4ed4e862860bed51a9570b96d89af5e1b0efefed" || env.Data.Decimals != 18 || env.Data.ResolvedBy != "token_list" {
+		t.Fatalf("unexpected resolution %+v", env.Data)
+	}
+	if len(env.Warnings) != 1 || !strings.Contains(env.Warnings[0], "token list Test List") {
+		t.Fatalf("expected a token list warning, got %v", env.Warnings)
+	}
+}
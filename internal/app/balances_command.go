@@ -0,0 +1,507 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/httpx"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/model"
+	"github.com/ggonzalez94/defi-cli/internal/providers"
+	"github.com/ggonzalez94/defi-cli/internal/registry"
+	"github.com/ggonzalez94/defi-cli/internal/rpcx"
+	"github.com/ggonzalez94/defi-cli/internal/schema"
+	"github.com/spf13/cobra"
+)
+
+var multicall3ABI = mustABI(registry.Multicall3ABI)
+
+func (s *runtimeState) newBalancesCommand() *cobra.Command {
+	var addressArgs []string
+	var chainsArg string
+	var minUSD float64
+	var solanaRPCURLArg string
+
+	cmd := &cobra.Command{
+		Use:   "balances",
+		Short: "List native and token balances for a wallet across chains",
+		Long:  "Reads native balances and registered token balances with one Multicall3 batch per EVM chain and every SPL token account on Solana, then prices them from DefiLlama.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if minUSD < 0 {
+				return clierr.New(clierr.CodeUsage, "--min-usd must be >= 0")
+			}
+			chains, err := parseBalanceChains(chainsArg)
+			if err != nil {
+				return err
+			}
+			accounts, err := balanceAccounts(addressArgs, chains)
+			if err != nil {
+				return err
+			}
+			addresses := []string{}
+			chainIDs := make([]string, 0, len(chains))
+			for _, chain := range chains {
+				chainIDs = append(chainIDs, chain.CAIP2)
+				if !slices.Contains(addresses, accounts[chain.CAIP2]) {
+					addresses = append(addresses, accounts[chain.CAIP2])
+				}
+			}
+
+			key := cacheKey(trimRootPath(cmd.CommandPath()), map[string]any{
+				"addresses":      addresses,
+				"chains":         chainIDs,
+				"min_usd":        minUSD,
+				"solana_rpc_url": strings.TrimSpace(solanaRPCURLArg),
+			})
+			return s.runCachedCommand(trimRootPath(cmd.CommandPath()), key, 15*time.Second, func(ctx context.Context) (any, []model.ProviderStatus, []string, bool, error) {
+				statuses := make([]model.ProviderStatus, 0, len(chains)+1)
+				warnings := []string{}
+				partial := false
+				var firstErr error
+				holdings := []model.AssetBalance{}
+				fetched := []id.Chain{}
+
+				for _, chain := range chains {
+					start := time.Now()
+					balances, fetchErr := s.fetchChainBalances(ctx, chain, accounts[chain.CAIP2], solanaRPCURLArg)
+					statuses = append(statuses, model.ProviderStatus{Name: "rpc:" + chain.Slug, Status: statusFromErr(fetchErr), LatencyMS: time.Since(start).Milliseconds()})
+					if fetchErr != nil {
+						partial = true
+						warnings = append(warnings, fmt.Sprintf("balances on %s failed: %v", chain.CAIP2, fetchErr))
+						if firstErr == nil {
+							firstErr = fetchErr
+						}
+						continue
+					}
+					holdings = append(holdings, balances...)
+					fetched = append(fetched, chain)
+				}
+				if len(fetched) == 0 && firstErr != nil {
+					return nil, statuses, warnings, partial, firstErr
+				}
+
+				prices, priceStatus, priceWarnings := s.priceBalances(ctx, fetched, holdings)
+				if priceStatus != nil {
+					statuses = append(statuses, *priceStatus)
+				}
+				warnings = append(warnings, priceWarnings...)
+
+				result := summarizeBalances(holdings, prices, minUSD)
+				result.Addresses = addresses
+				result.Chains = chainIDs
+				result.FetchedAt = s.runner.now().UTC().Format(time.RFC3339)
+				return result, statuses, warnings, partial, nil
+			})
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&addressArgs, "address", nil, "Wallet address; pass an EVM and a Solana address (repeat or comma-separate) to cover both")
+	cmd.Flags().StringVar(&chainsArg, "chains", "", "Comma-separated chains (CAIP-2, chain ID, or slug), e.g. 1,8453,solana")
+	cmd.Flags().Float64Var(&minUSD, "min-usd", 0, "Hide priced balances worth less than this many USD")
+	cmd.Flags().StringVar(&solanaRPCURLArg, "solana-rpc-url", "", "Override the default Solana RPC endpoint")
+	_ = schema.SetFlagMetadata(cmd.Flags(), "address", schema.FlagMetadata{Required: true})
+	_ = schema.SetFlagMetadata(cmd.Flags(), "chains", schema.FlagMetadata{Required: true})
+	_ = schema.SetFlagMetadata(cmd.Flags(), "solana-rpc-url", schema.FlagMetadata{Format: "url"})
+
+	response := schema.TypeSchema{
+		Type:        "object",
+		Description: "Non-zero native and token balances across chains with USD values and total",
+	}
+	_ = schema.SetCommandMetadata(cmd, schema.CommandMetadata{Response: &response})
+	return cmd
+}
+
+// parseBalanceChains resolves the --chains list, dropping duplicates.
+func parseBalanceChains(raw string) ([]id.Chain, error) {
+	inputs := splitCSV(raw)
+	if len(inputs) == 0 {
+		return nil, clierr.New(clierr.CodeUsage, "--chains is required")
+	}
+	chains := make([]id.Chain, 0, len(inputs))
+	seen := map[string]bool{}
+	for _, input := range inputs {
+		chain, err := id.ParseChain(input)
+		if err != nil {
+			return nil, err
+		}
+		if !chain.IsEVM() && !chain.IsSolana() {
+			return nil, clierr.New(clierr.CodeUnsupported, fmt.Sprintf("balances does not support %s", chain.CAIP2))
+		}
+		if seen[chain.CAIP2] {
+			continue
+		}
+		seen[chain.CAIP2] = true
+		chains = append(chains, chain)
+	}
+	return chains, nil
+}
+
+// balanceAccounts matches each chain with the --address of its family,
+// keyed by CAIP-2 chain ID.
+func balanceAccounts(addressArgs []string, chains []id.Chain) (map[string]string, error) {
+	var evmAddress, solanaAddress string
+	for _, raw := range addressArgs {
+		address := strings.TrimSpace(raw)
+		switch {
+		case address == "":
+			continue
+		case common.IsHexAddress(address):
+			if evmAddress != "" && !strings.EqualFold(evmAddress, address) {
+				return nil, clierr.New(clierr.CodeUsage, "--address accepts at most one EVM address")
+			}
+			evmAddress = strings.ToLower(address)
+		case id.IsSolanaAddress(address):
+			if solanaAddress != "" && solanaAddress != address {
+				return nil, clierr.New(clierr.CodeUsage, "--address accepts at most one Solana address")
+			}
+			solanaAddress = address
+		default:
+			return nil, clierr.New(clierr.CodeUsage, fmt.Sprintf("--address %q is neither an EVM nor a Solana address", address))
+		}
+	}
+	if evmAddress == "" && solanaAddress == "" {
+		return nil, clierr.New(clierr.CodeUsage, "--address is required")
+	}
+	accounts := make(map[string]string, len(chains))
+	for _, chain := range chains {
+		account, family := evmAddress, "an EVM"
+		if chain.IsSolana() {
+			account, family = solanaAddress, "a Solana"
+		}
+		if account == "" {
+			return nil, clierr.New(clierr.CodeUsage, fmt.Sprintf("--address needs %s address for %s", family, chain.CAIP2))
+		}
+		accounts[chain.CAIP2] = account
+	}
+	return accounts, nil
+}
+
+func (s *runtimeState) fetchChainBalances(ctx context.Context, chain id.Chain, account, solanaRPCURL string) ([]model.AssetBalance, error) {
+	if chain.IsSolana() {
+		rpcURL := strings.TrimSpace(solanaRPCURL)
+		if rpcURL == "" {
+			rpcURL = registry.SolanaMainnetRPCURL
+		}
+		return fetchSolanaBalances(ctx, httpx.New(s.settings.Timeout, s.settings.Retries), rpcURL, chain, account)
+	}
+	rpcURL, err := registry.ResolveRPCURL("", chain.EVMChainID)
+	if err != nil {
+		return nil, clierr.Wrap(clierr.CodeUnsupported, "resolve rpc", err)
+	}
+	client, err := rpcx.DialContext(ctx, rpcURL)
+	if err != nil {
+		return nil, clierr.Wrap(clierr.CodeUnavailable, "dial rpc", err)
+	}
+	defer client.Close()
+	return fetchEVMBalances(ctx, client, chain, common.HexToAddress(account))
+}
+
+type multicall3Call struct {
+	Target       common.Address `abi:"target"`
+	AllowFailure bool           `abi:"allowFailure"`
+	CallData     []byte         `abi:"callData"`
+}
+
+type multicall3Result struct {
+	Success    bool   `abi:"success"`
+	ReturnData []byte `abi:"returnData"`
+}
+
+// fetchEVMBalances reads the native balance and every registered token
+// balance of account in a single Multicall3 aggregate3 call. Zero balances
+// and tokens whose call reverts are left out.
+func fetchEVMBalances(ctx context.Context, caller ethereum.ContractCaller, chain id.Chain, account common.Address) ([]model.AssetBalance, error) {
+	multicall := common.HexToAddress(registry.Multicall3Address)
+	nativeCall, err := multicall3ABI.Pack("getEthBalance", account)
+	if err != nil {
+		return nil, clierr.Wrap(clierr.CodeInternal, "pack getEthBalance", err)
+	}
+	tokens := id.ChainTokens(chain.CAIP2)
+	calls := make([]multicall3Call, 0, len(tokens)+1)
+	calls = append(calls, multicall3Call{Target: multicall, AllowFailure: true, CallData: nativeCall})
+	for _, token := range tokens {
+		calldata := make([]byte, 4+32)
+		copy(calldata[:4], erc20BalanceOfSelector)
+		copy(calldata[4+12:], account.Bytes())
+		calls = append(calls, multicall3Call{Target: common.HexToAddress(token.Address), AllowFailure: true, CallData: calldata})
+	}
+	data, err := multicall3ABI.Pack("aggregate3", calls)
+	if err != nil {
+		return nil, clierr.Wrap(clierr.CodeInternal, "pack aggregate3", err)
+	}
+	raw, err := caller.CallContract(ctx, ethereum.CallMsg{To: &multicall, Data: data}, nil)
+	if err != nil {
+		return nil, clierr.Wrap(clierr.CodeUnavailable, "call aggregate3", err)
+	}
+	var results []multicall3Result
+	if err := multicall3ABI.UnpackIntoInterface(&results, "aggregate3", raw); err != nil {
+		return nil, clierr.Wrap(clierr.CodeUnavailable, "decode aggregate3", err)
+	}
+	if len(results) != len(calls) {
+		return nil, clierr.New(clierr.CodeUnavailable, fmt.Sprintf("aggregate3 returned %d results for %d calls", len(results), len(calls)))
+	}
+
+	owner := strings.ToLower(account.Hex())
+	balances := []model.AssetBalance{}
+	if amount, ok := multicallUint(results[0]); ok {
+		balances = append(balances, model.AssetBalance{
+			ChainID:        chain.CAIP2,
+			AccountAddress: owner,
+			AssetType:      "native",
+			AssetID:        nativeAssetID(chain),
+			Symbol:         nativeSymbol(chain),
+			Balance:        balanceAmount(amount, 18),
+		})
+	}
+	for i, token := range tokens {
+		amount, ok := multicallUint(results[i+1])
+		if !ok {
+			continue
+		}
+		balances = append(balances, model.AssetBalance{
+			ChainID:        chain.CAIP2,
+			AccountAddress: owner,
+			AssetType:      "erc20",
+			AssetID:        chain.CAIP2 + "/erc20:" + token.Address,
+			Symbol:         token.Symbol,
+			Balance:        balanceAmount(amount, token.Decimals),
+		})
+	}
+	return balances, nil
+}
+
+// multicallUint decodes a successful uint256 return and reports whether it
+// is a positive balance.
+func multicallUint(result multicall3Result) (*big.Int, bool) {
+	if !result.Success || len(result.ReturnData) < 32 {
+		return nil, false
+	}
+	amount := new(big.Int).SetBytes(result.ReturnData[:32])
+	return amount, amount.Sign() > 0
+}
+
+type solanaRPCResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+type solanaTokenAccounts struct {
+	Value []struct {
+		Account struct {
+			Data struct {
+				Parsed struct {
+					Info struct {
+						Mint        string `json:"mint"`
+						TokenAmount struct {
+							Amount   string `json:"amount"`
+							Decimals int    `json:"decimals"`
+						} `json:"tokenAmount"`
+					} `json:"info"`
+				} `json:"parsed"`
+			} `json:"data"`
+		} `json:"account"`
+	} `json:"value"`
+}
+
+// fetchSolanaBalances reads the SOL balance and every SPL token account
+// owned by owner. Accounts of the same mint are summed.
+func fetchSolanaBalances(ctx context.Context, client *httpx.Client, rpcURL string, chain id.Chain, owner string) ([]model.AssetBalance, error) {
+	var native struct {
+		Value uint64 `json:"value"`
+	}
+	if err := solanaRPC(ctx, client, rpcURL, "getBalance", []any{owner}, &native); err != nil {
+		return nil, err
+	}
+	var accounts solanaTokenAccounts
+	params := []any{owner, map[string]string{"programId": registry.SolanaTokenProgram}, map[string]string{"encoding": "jsonParsed"}}
+	if err := solanaRPC(ctx, client, rpcURL, "getTokenAccountsByOwner", params, &accounts); err != nil {
+		return nil, err
+	}
+
+	balances := []model.AssetBalance{}
+	if native.Value > 0 {
+		balances = append(balances, model.AssetBalance{
+			ChainID:        chain.CAIP2,
+			AccountAddress: owner,
+			AssetType:      "native",
+			AssetID:        chain.CAIP2 + "/slip44:501",
+			Symbol:         "SOL",
+			Balance:        balanceAmount(new(big.Int).SetUint64(native.Value), 9),
+		})
+	}
+	totals := map[string]*big.Int{}
+	decimals := map[string]int{}
+	mints := []string{}
+	for _, entry := range accounts.Value {
+		info := entry.Account.Data.Parsed.Info
+		amount, ok := new(big.Int).SetString(info.TokenAmount.Amount, 10)
+		if !ok || amount.Sign() <= 0 || info.Mint == "" {
+			continue
+		}
+		if _, seen := totals[info.Mint]; !seen {
+			totals[info.Mint] = new(big.Int)
+			decimals[info.Mint] = info.TokenAmount.Decimals
+			mints = append(mints, info.Mint)
+		}
+		totals[info.Mint].Add(totals[info.Mint], amount)
+	}
+	for _, mint := range mints {
+		symbol := ""
+		if token, ok := id.LookupByAddress(chain.CAIP2, mint); ok {
+			symbol = token.Symbol
+		}
+		balances = append(balances, model.AssetBalance{
+			ChainID:        chain.CAIP2,
+			AccountAddress: owner,
+			AssetType:      "spl",
+			AssetID:        chain.CAIP2 + "/token:" + mint,
+			Symbol:         symbol,
+			Balance:        balanceAmount(totals[mint], decimals[mint]),
+		})
+	}
+	return balances, nil
+}
+
+func solanaRPC(ctx context.Context, client *httpx.Client, rpcURL, method string, params []any, out any) error {
+	body, err := json.Marshal(map[string]any{"jsonrpc": "2.0", "id": 1, "method": method, "params": params})
+	if err != nil {
+		return clierr.Wrap(clierr.CodeInternal, "encode solana rpc request", err)
+	}
+	var resp solanaRPCResponse
+	if _, err := httpx.DoBodyJSON(ctx, client, http.MethodPost, rpcURL, body, nil, &resp); err != nil {
+		return err
+	}
+	if resp.Error != nil {
+		return clierr.New(clierr.CodeUnavailable, fmt.Sprintf("solana %s: %s", method, resp.Error.Message))
+	}
+	if err := json.Unmarshal(resp.Result, out); err != nil {
+		return clierr.Wrap(clierr.CodeUnavailable, "decode solana "+method, err)
+	}
+	return nil
+}
+
+// priceBalances fetches USD prices for holdings on each chain, keyed by
+// asset ID. Price failures only warn: the balances stay, unpriced.
+func (s *runtimeState) priceBalances(ctx context.Context, chains []id.Chain, holdings []model.AssetBalance) (map[string]model.TokenPrice, *model.ProviderStatus, []string) {
+	if len(holdings) == 0 {
+		return nil, nil, nil
+	}
+	provider, ok := s.marketProvider.(providers.TokenPriceProvider)
+	if !ok {
+		return nil, nil, []string{"market data provider does not support token prices; balances are unpriced"}
+	}
+	prices := map[string]model.TokenPrice{}
+	warnings := []string{}
+	var priceErr error
+	start := time.Now()
+	for _, chain := range chains {
+		byAddress := map[string]string{}
+		addresses := []string{}
+		for _, holding := range holdings {
+			if holding.ChainID != chain.CAIP2 {
+				continue
+			}
+			address := balancePriceAddress(chain, holding)
+			byAddress[address] = holding.AssetID
+			addresses = append(addresses, address)
+		}
+		if len(addresses) == 0 {
+			continue
+		}
+		chainPrices, err := provider.TokenPrices(ctx, chain, addresses)
+		if err != nil {
+			priceErr = err
+			warnings = append(warnings, fmt.Sprintf("prices on %s failed: %v", chain.CAIP2, err))
+			continue
+		}
+		for address, price := range chainPrices {
+			if assetID, ok := byAddress[address]; ok {
+				prices[assetID] = price
+			}
+		}
+	}
+	status := model.ProviderStatus{Name: "defillama", Status: statusFromErr(priceErr), LatencyMS: time.Since(start).Milliseconds()}
+	return prices, &status, warnings
+}
+
+// balancePriceAddress is the address the coins API keys a holding by: the
+// zero address for EVM native coins and the wrapped SOL mint for SOL.
+func balancePriceAddress(chain id.Chain, holding model.AssetBalance) string {
+	if holding.AssetType == "native" {
+		if chain.IsSolana() {
+			return registry.SolanaWrappedSOLMint
+		}
+		return strings.ToLower(common.Address{}.Hex())
+	}
+	address := holding.AssetID[strings.LastIndex(holding.AssetID, ":")+1:]
+	if chain.IsEVM() {
+		address = strings.ToLower(address)
+	}
+	return address
+}
+
+// summarizeBalances applies prices keyed by asset ID, drops priced holdings
+// below minUSD, and sorts by value.
+func summarizeBalances(holdings []model.AssetBalance, prices map[string]model.TokenPrice, minUSD float64) model.WalletBalances {
+	out := model.WalletBalances{Balances: []model.AssetBalance{}, MinUSD: minUSD}
+	for _, holding := range holdings {
+		if price, ok := prices[holding.AssetID]; ok {
+			if holding.Symbol == "" {
+				holding.Symbol = price.Symbol
+			}
+			units, _ := new(big.Float).SetString(holding.Balance.AmountDecimal)
+			if units != nil {
+				amount, _ := units.Float64()
+				priceUSD, valueUSD := price.PriceUSD, amount*price.PriceUSD
+				holding.PriceUSD, holding.ValueUSD = &priceUSD, &valueUSD
+			}
+		}
+		if holding.ValueUSD != nil && *holding.ValueUSD < minUSD {
+			out.DustFiltered++
+			continue
+		}
+		if holding.ValueUSD != nil {
+			out.TotalUSD += *holding.ValueUSD
+		}
+		out.Balances = append(out.Balances, holding)
+	}
+	sort.SliceStable(out.Balances, func(i, j int) bool {
+		a, b := out.Balances[i].ValueUSD, out.Balances[j].ValueUSD
+		if a == nil || b == nil {
+			return a != nil && b == nil
+		}
+		return *a > *b
+	})
+	return out
+}
+
+func balanceAmount(amount *big.Int, decimals int) model.AmountInfo {
+	baseUnits := amount.String()
+	return model.AmountInfo{
+		AmountBaseUnits: baseUnits,
+		AmountDecimal:   id.FormatDecimalCompat(baseUnits, decimals),
+		Decimals:        decimals,
+	}
+}
+
+func mustABI(raw string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(raw))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
@@ -0,0 +1,160 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ggonzalez94/defi-cli/internal/httpx"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/model"
+)
+
+func TestFetchEVMBalancesDecodesMulticall(t *testing.T) {
+	chain, err := id.ParseChain("base")
+	if err != nil {
+		t.Fatal(err)
+	}
+	usdc, ok := id.KnownToken(chain.CAIP2, "USDC")
+	if !ok {
+		t.Fatal("expected USDC in the base registry")
+	}
+	account := common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+	client := stubWalletRPC{
+		callContract: func(_ context.Context, msg ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+			if *msg.To != common.HexToAddress("0xcA11bde05977b3631167028862bE2a173976CA11") {
+				t.Fatalf("expected a multicall3 call, got %s", msg.To.Hex())
+			}
+			var calls []multicall3Call
+			if err := multicall3ABI.Methods["aggregate3"].Inputs.Copy(&calls, mustUnpackInputs(t, msg.Data)); err != nil {
+				t.Fatalf("decode aggregate3 calls: %v", err)
+			}
+			results := make([]multicall3Result, len(calls))
+			for i, call := range calls {
+				switch {
+				case i == 0:
+					results[i] = multicall3Result{Success: true, ReturnData: common.LeftPadBytes(big.NewInt(2_000_000_000_000_000_000).Bytes(), 32)}
+				case call.Target == common.HexToAddress(usdc.Address):
+					results[i] = multicall3Result{Success: true, ReturnData: common.LeftPadBytes(big.NewInt(12_500_000).Bytes(), 32)}
+				default:
+					results[i] = multicall3Result{Success: true, ReturnData: make([]byte, 32)}
+				}
+			}
+			return multicall3ABI.Methods["aggregate3"].Outputs.Pack(results)
+		},
+	}
+
+	balances, err := fetchEVMBalances(context.Background(), client, chain, account)
+	if err != nil {
+		t.Fatalf("fetchEVMBalances failed: %v", err)
+	}
+	if len(balances) != 2 {
+		t.Fatalf("expected native and USDC balances only, got %+v", balances)
+	}
+	if balances[0].AssetType != "native" || balances[0].Balance.AmountDecimal != "2" {
+		t.Fatalf("unexpected native balance: %+v", balances[0])
+	}
+	if balances[1].Symbol != "USDC" || balances[1].Balance.AmountDecimal != "12.5" || balances[1].AssetID != "eip155:8453/erc20:"+usdc.Address {
+		t.Fatalf("unexpected USDC balance: %+v", balances[1])
+	}
+}
+
+func mustUnpackInputs(t *testing.T, data []byte) []any {
+	t.Helper()
+	args, err := multicall3ABI.Methods["aggregate3"].Inputs.Unpack(data[4:])
+	if err != nil {
+		t.Fatalf("unpack aggregate3 input: %v", err)
+	}
+	return args
+}
+
+func TestFetchSolanaBalancesSumsTokenAccounts(t *testing.T) {
+	const owner = "9WzDXwBbmkg8ZTbNMqUxvQRAyrZzDsGYdLVL9zYtAWWM"
+	const mint = "EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string `json:"method"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		switch req.Method {
+		case "getBalance":
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"context":{"slot":1},"value":1500000000}}`))
+		case "getTokenAccountsByOwner":
+			account := func(amount string) string {
+				return `{"account":{"data":{"parsed":{"info":{"mint":"` + mint + `","tokenAmount":{"amount":"` + amount + `","decimals":6}}}}}}`
+			}
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"value":[` + account("1000000") + `,` + account("250000") + `,` + account("0") + `]}}`))
+		default:
+			t.Fatalf("unexpected method %s", req.Method)
+		}
+	}))
+	defer srv.Close()
+
+	chain, _ := id.ParseChain("solana")
+	balances, err := fetchSolanaBalances(context.Background(), httpx.New(5*time.Second, 0), srv.URL, chain, owner)
+	if err != nil {
+		t.Fatalf("fetchSolanaBalances failed: %v", err)
+	}
+	if len(balances) != 2 {
+		t.Fatalf("expected SOL and one SPL balance, got %+v", balances)
+	}
+	if balances[0].Symbol != "SOL" || balances[0].Balance.AmountDecimal != "1.5" {
+		t.Fatalf("unexpected SOL balance: %+v", balances[0])
+	}
+	if balances[1].AssetType != "spl" || balances[1].Balance.AmountBaseUnits != "1250000" || balances[1].Symbol != "USDC" {
+		t.Fatalf("expected token accounts of one mint to be summed, got %+v", balances[1])
+	}
+}
+
+func TestSummarizeBalancesFiltersDust(t *testing.T) {
+	holdings := []model.AssetBalance{
+		{AssetID: "a", Symbol: "DUST", Balance: model.AmountInfo{AmountDecimal: "0.5"}},
+		{AssetID: "b", Symbol: "ETH", Balance: model.AmountInfo{AmountDecimal: "2"}},
+		{AssetID: "c", Balance: model.AmountInfo{AmountDecimal: "100"}},
+		{AssetID: "d", Balance: model.AmountInfo{AmountDecimal: "10"}},
+	}
+	prices := map[string]model.TokenPrice{
+		"a": {PriceUSD: 1},
+		"b": {PriceUSD: 2000},
+		"d": {PriceUSD: 1, Symbol: "USDC"},
+	}
+	got := summarizeBalances(holdings, prices, 5)
+	if got.DustFiltered != 1 || len(got.Balances) != 3 {
+		t.Fatalf("expected one dust balance filtered, got %+v", got)
+	}
+	if got.Balances[0].Symbol != "ETH" || got.Balances[1].Symbol != "USDC" || got.Balances[2].ValueUSD != nil {
+		t.Fatalf("expected value order with unpriced last, got %+v", got.Balances)
+	}
+	if got.TotalUSD != 4010 {
+		t.Fatalf("unexpected total %v", got.TotalUSD)
+	}
+}
+
+func TestBalancesCommandValidatesInputs(t *testing.T) {
+	cases := []struct {
+		name string
+		args []string
+	}{
+		{name: "missing chains", args: []string{"balances", "--address", "0x000000000000000000000000000000000000dEaD"}},
+		{name: "missing address", args: []string{"balances", "--chains", "1"}},
+		{name: "invalid address", args: []string{"balances", "--chains", "1", "--address", "not-an-address"}},
+		{name: "no solana address", args: []string{"balances", "--chains", "1,solana", "--address", "0x000000000000000000000000000000000000dEaD"}},
+		{name: "negative min usd", args: []string{"balances", "--chains", "1", "--address", "0x000000000000000000000000000000000000dEaD", "--min-usd", "-1"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			code := NewRunnerWithWriters(&stdout, &stderr).Run(tc.args)
+			if code != 2 {
+				t.Fatalf("expected exit 2, got %d stderr=%s", code, stderr.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,249 @@
+package app
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/policy"
+	"github.com/ggonzalez94/defi-cli/internal/schema"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// batchRequest is one NDJSON input line of `defi batch`.
+type batchRequest struct {
+	ID      json.RawMessage `json:"id"`
+	Command string          `json:"command"`
+	Args    []string        `json:"args"`
+}
+
+// batchResult is one NDJSON output line: the request id, the exit code the
+// command would have exited with, and its envelope.
+type batchResult struct {
+	ID       json.RawMessage `json:"id"`
+	ExitCode int             `json:"exit_code"`
+	Envelope json.RawMessage `json:"envelope"`
+}
+
+// batchProcessFlags configure process-wide state or the output format. They
+// come from the batch invocation and are rejected inside requests.
+var batchProcessFlags = map[string]struct{}{
+	"json":              {},
+	"plain":             {},
+	"results-only":      {},
+	"lang":              {},
+	"config":            {},
+	"profile":           {},
+	"enable-commands":   {},
+	"resolve-policy":    {},
+	"prefer-token-list": {},
+	"strict-asset":      {},
+	"resolve-unknown":   {},
+	"verbose":           {},
+	"log-level":         {},
+	"log-file":          {},
+	"trace":             {},
+	"offline":           {},
+	"seed":              {},
+	"record-fixtures":   {},
+	"replay-fixtures":   {},
+	"notify-url":        {},
+}
+
+// batchOutputFlags shape the batch invocation's own output and are not
+// forwarded to requests.
+var batchOutputFlags = map[string]struct{}{
+	"json":         {},
+	"plain":        {},
+	"results-only": {},
+	"select":       {},
+	"query":        {},
+	"compact":      {},
+	"compact-gzip": {},
+	"lang":         {},
+	"verbose":      {},
+	"log-level":    {},
+	"log-file":     {},
+	"trace":        {},
+}
+
+func (s *runtimeState) newBatchCommand() *cobra.Command {
+	var file string
+	var parallel int
+	cmd := &cobra.Command{
+		Use:   "batch",
+		Short: "Run read-only commands from NDJSON in one process",
+		Long: `Reads one request per line from --file (or stdin) and runs them in this process with a shared cache and at most --parallel at a time. Each request is {"id": ..., "command": "lend markets", "args": ["--provider", "aave"]}, or {"id": ..., "args": ["lend", "markets", "--provider", "aave"]}.
+
+Writes one line per request as it completes: {"id": ..., "exit_code": 0, "envelope": {...}}. A missing id is replaced by the line number. Only read-only commands run; mutations and execution commands are answered with a command_blocked envelope. Global flags given to batch (cache, timeout, retries, config, profile) apply to every request; flags that configure the process or output format (for example --config, --plain, --results-only, --log-level) are rejected inside requests.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if parallel < 1 {
+				return clierr.New(clierr.CodeUsage, "--parallel must be at least 1")
+			}
+			in := cmd.InOrStdin()
+			if file != "" && file != "-" {
+				f, err := os.Open(file)
+				if err != nil {
+					return clierr.Wrap(clierr.CodeUsage, "open batch file", err)
+				}
+				defer f.Close()
+				in = f
+			}
+			return s.runBatch(in, cmd.OutOrStdout(), batchForwardedFlags(cmd), parallel)
+		},
+	}
+	cmd.Flags().StringVar(&file, "file", "", "NDJSON file of requests (default stdin; - for stdin)")
+	cmd.Flags().IntVar(&parallel, "parallel", 4, "Maximum requests running at once")
+	_ = schema.SetFlagMetadata(cmd.Flags(), "file", schema.FlagMetadata{Format: "path"})
+	return cmd
+}
+
+// batchForwardedFlags returns the global flags set on the batch invocation,
+// such as --config, --no-cache, or --timeout, as --name=value for every
+// request.
+func batchForwardedFlags(cmd *cobra.Command) []string {
+	forwarded := []string{}
+	cmd.InheritedFlags().Visit(func(flag *pflag.Flag) {
+		if _, ok := batchOutputFlags[flag.Name]; ok {
+			return
+		}
+		forwarded = append(forwarded, "--"+flag.Name+"="+flag.Value.String())
+	})
+	return forwarded
+}
+
+// runBatch answers every request line of in on out. Requests run as nested
+// invocations with this one as parent, so they share its cache, payload
+// cache, and process-wide setup; results are written in completion order.
+func (s *runtimeState) runBatch(in io.Reader, out io.Writer, forwarded []string, parallel int) error {
+	forwarded = append([]string{"--json"}, forwarded...)
+
+	var writeMu sync.Mutex
+	var writeErr error
+	write := func(result batchResult) {
+		if !json.Valid(result.Envelope) {
+			result = s.batchRejection(result.ID, "batch", clierr.New(clierr.CodeInternal, "command did not write a JSON envelope"))
+		}
+		line, _ := json.Marshal(result)
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		if _, err := out.Write(append(line, '\n')); err != nil && writeErr == nil {
+			writeErr = err
+		}
+	}
+
+	sem := make(chan struct{}, parallel)
+	var wg sync.WaitGroup
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		raw := bytes.TrimSpace(scanner.Bytes())
+		if len(raw) == 0 {
+			continue
+		}
+		var req batchRequest
+		if err := json.Unmarshal(raw, &req); err != nil {
+			write(s.batchRejection(json.RawMessage(strconv.Itoa(lineNo)), "batch", clierr.Wrap(clierr.CodeUsage, fmt.Sprintf("line %d is not a JSON request", lineNo), err)))
+			continue
+		}
+		if len(req.ID) == 0 || string(req.ID) == "null" {
+			req.ID = json.RawMessage(strconv.Itoa(lineNo))
+		}
+		args := append(strings.Fields(req.Command), req.Args...)
+		path, err := s.checkBatchRequest(args)
+		if err != nil {
+			write(s.batchRejection(req.ID, path, err))
+			continue
+		}
+
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(id json.RawMessage, args []string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			var stdout, stderr bytes.Buffer
+			runner := NewRunnerWithWriters(&stdout, &stderr)
+			runner.now = s.runner.now
+			runner.parent = s
+			// Forwarded flags go first so request flags override them.
+			code := runner.Run(append(append([]string{}, forwarded...), args...))
+			envelope := stdout.Bytes()
+			if stdout.Len() == 0 {
+				envelope = stderr.Bytes()
+			}
+			write(batchResult{ID: id, ExitCode: code, Envelope: envelope})
+		}(req.ID, args)
+	}
+	wg.Wait()
+	if err := scanner.Err(); err != nil {
+		return clierr.Wrap(clierr.CodeUsage, "read batch input", err)
+	}
+	if writeErr != nil {
+		return clierr.Wrap(clierr.CodeInternal, "write batch output", writeErr)
+	}
+	return nil
+}
+
+// checkBatchRequest resolves args to a command and rejects anything batch
+// does not run: unknown or group commands, mutations, and process flags.
+// It returns the command path for the rejection envelope.
+func (s *runtimeState) checkBatchRequest(args []string) (string, error) {
+	if len(args) == 0 {
+		return "batch", clierr.New(clierr.CodeUsage, "request has no command; set command or args")
+	}
+	cmd, _, err := s.root.Find(args)
+	if err != nil || cmd == s.root {
+		return "batch", clierr.New(clierr.CodeUsage, fmt.Sprintf("unknown command %q", strings.Join(args, " ")))
+	}
+	path := trimRootPath(cmd.CommandPath())
+	if !cmd.Runnable() {
+		return path, clierr.New(clierr.CodeUsage, fmt.Sprintf("%s requires a subcommand", path))
+	}
+	switch {
+	case path == "batch" || path == "mcp" || path == "tui":
+		return path, clierr.New(clierr.CodeBlocked, fmt.Sprintf("%s cannot run inside batch", path))
+	case schema.CommandMetadataFor(cmd).Mutation || isExecutionCommandPath(path):
+		return path, clierr.New(clierr.CodeBlocked, fmt.Sprintf("batch runs read-only commands only; %s changes state", path))
+	}
+	if err := policy.CheckCommandAllowed(s.settings.EnableCommands, path); err != nil {
+		return path, err
+	}
+	for _, arg := range args {
+		name, ok := strings.CutPrefix(arg, "--")
+		if !ok {
+			continue
+		}
+		name, _, _ = strings.Cut(name, "=")
+		if _, reserved := batchProcessFlags[name]; reserved {
+			return path, clierr.New(clierr.CodeUsage, fmt.Sprintf("--%s is set on the batch invocation, not per request", name))
+		}
+	}
+	return path, nil
+}
+
+func (s *runtimeState) batchRejection(id json.RawMessage, commandPath string, err error) batchResult {
+	return batchResult{ID: id, ExitCode: clierr.ExitCode(err), Envelope: s.batchErrorEnvelope(commandPath, err)}
+}
+
+// batchErrorEnvelope renders err as the JSON error envelope a command would
+// have written.
+func (s *runtimeState) batchErrorEnvelope(commandPath string, err error) json.RawMessage {
+	var buf bytes.Buffer
+	state := &runtimeState{runner: NewRunnerWithWriters(&buf, &buf), settings: s.settings}
+	state.runner.now = s.runner.now
+	state.settings.OutputMode = "json"
+	state.settings.Compact = false
+	state.renderError(commandPath, err, nil, nil, false)
+	return buf.Bytes()
+}
@@ -0,0 +1,80 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBatchRunsReadOnlyRequestsAndTagsResults(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	input := strings.Join([]string{
+		`{"id":"providers","command":"providers list"}`,
+		`{"id":7,"args":["chains","list","--select","slug"]}`,
+		``,
+		`{"args":["providers","health"]}`,
+		`{"id":"plan","command":"yield deposit plan","args":["--provider","aave"]}`,
+		`{"id":"plain","command":"providers list","args":["--plain"]}`,
+		`{"id":"unknown","command":"nope"}`,
+		`not json`,
+	}, "\n")
+	path := filepath.Join(t.TempDir(), "requests.ndjson")
+	if err := os.WriteFile(path, []byte(input), 0o600); err != nil {
+		t.Fatalf("write requests: %v", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	r := NewRunnerWithWriters(&stdout, &stderr)
+	if code := r.Run([]string{"batch", "--file", path, "--parallel", "3", "--no-cache"}); code != 0 {
+		t.Fatalf("batch failed: %d stderr=%s", code, stderr.String())
+	}
+
+	type result struct {
+		ID       json.RawMessage `json:"id"`
+		ExitCode int             `json:"exit_code"`
+		Envelope struct {
+			Success bool `json:"success"`
+			Error   *struct {
+				Type string `json:"type"`
+			} `json:"error"`
+			Meta struct {
+				Command string `json:"command"`
+			} `json:"meta"`
+		} `json:"envelope"`
+	}
+	results := map[string]result{}
+	for _, line := range strings.Split(strings.TrimSpace(stdout.String()), "\n") {
+		var res result
+		if err := json.Unmarshal([]byte(line), &res); err != nil {
+			t.Fatalf("output line is not JSON: %v (%s)", err, line)
+		}
+		results[string(res.ID)] = res
+	}
+	if len(results) != 7 {
+		t.Fatalf("expected 7 results, got %d: %s", len(results), stdout.String())
+	}
+	for id, command := range map[string]string{`"providers"`: "providers list", `7`: "chains list", `4`: "providers health"} {
+		res := results[id]
+		if res.ExitCode != 0 || !res.Envelope.Success || res.Envelope.Meta.Command != command {
+			t.Fatalf("expected %s to succeed as %q, got %+v", id, command, res)
+		}
+	}
+	for id, want := range map[string]struct {
+		code int
+		typ  string
+	}{
+		`"plan"`:    {16, "command_blocked"},
+		`"plain"`:   {2, "usage_error"},
+		`"unknown"`: {2, "usage_error"},
+		`8`:         {2, "usage_error"},
+	} {
+		res := results[id]
+		if res.ExitCode != want.code || res.Envelope.Success || res.Envelope.Error == nil || res.Envelope.Error.Type != want.typ {
+			t.Fatalf("expected %s to fail with %d/%s, got %+v", id, want.code, want.typ, res)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package app
 
 import (
 	"context"
+	"fmt"
 	"strings"
 	"time"
 
@@ -15,37 +16,30 @@ import (
 )
 
 func (s *runtimeState) addBridgeExecutionSubcommands(root *cobra.Command) {
-	buildRequest := func(fromArg, toArg, assetArg, toAssetArg, amountBase, amountDecimal, fromAmountForGas string) (providers.BridgeQuoteRequest, error) {
+	buildRequest := func(ctx context.Context, fromArg, toArg, assetArg, toAssetArg, amountBase, amountDecimal, amountUSD, fromAmountForGas string) (providers.BridgeQuoteRequest, float64, error) {
 		fromChain, err := id.ParseChain(fromArg)
 		if err != nil {
-			return providers.BridgeQuoteRequest{}, err
+			return providers.BridgeQuoteRequest{}, 0, err
 		}
 		toChain, err := id.ParseChain(toArg)
 		if err != nil {
-			return providers.BridgeQuoteRequest{}, err
+			return providers.BridgeQuoteRequest{}, 0, err
 		}
 		fromAsset, err := id.ParseAsset(assetArg, fromChain)
 		if err != nil {
-			return providers.BridgeQuoteRequest{}, err
+			return providers.BridgeQuoteRequest{}, 0, err
 		}
-		toAssetInput := strings.TrimSpace(toAssetArg)
-		if toAssetInput == "" {
-			if fromAsset.Symbol == "" {
-				return providers.BridgeQuoteRequest{}, clierr.New(clierr.CodeUsage, "destination asset cannot be inferred, provide --to-asset")
-			}
-			toAssetInput = fromAsset.Symbol
-		}
-		toAsset, err := id.ParseAsset(toAssetInput, toChain)
+		toAsset, err := parseBridgeDestinationAsset(fromAsset, toAssetArg, toChain)
 		if err != nil {
-			return providers.BridgeQuoteRequest{}, clierr.Wrap(clierr.CodeUsage, "resolve destination asset", err)
+			return providers.BridgeQuoteRequest{}, 0, err
 		}
 		decimals := fromAsset.Decimals
 		if decimals <= 0 {
 			decimals = 18
 		}
-		base, decimal, err := id.NormalizeAmount(amountBase, amountDecimal, decimals)
+		base, decimal, amountUSDValue, err := s.normalizeAmountInput(ctx, fromChain, fromAsset, amountBase, amountDecimal, amountUSD, decimals)
 		if err != nil {
-			return providers.BridgeQuoteRequest{}, err
+			return providers.BridgeQuoteRequest{}, 0, err
 		}
 		return providers.BridgeQuoteRequest{
 			FromChain:        fromChain,
@@ -55,23 +49,25 @@ func (s *runtimeState) addBridgeExecutionSubcommands(root *cobra.Command) {
 			AmountBaseUnits:  base,
 			AmountDecimal:    decimal,
 			FromAmountForGas: strings.TrimSpace(fromAmountForGas),
-		}, nil
+		}, amountUSDValue, nil
 	}
 
 	type bridgePlanArgs struct {
-		Provider         string `json:"provider" flag:"provider" required:"true" enum:"across,lifi"`
+		Provider         string `json:"provider" flag:"provider" required:"true" enum:"across,lifi,wormhole,cctp"`
 		FromArg          string `json:"from" flag:"from" required:"true" format:"chain"`
 		ToArg            string `json:"to" flag:"to" required:"true" format:"chain"`
 		AssetArg         string `json:"asset" flag:"asset" required:"true" format:"asset"`
 		ToAssetArg       string `json:"to_asset" flag:"to-asset" format:"asset"`
 		AmountBase       string `json:"amount" flag:"amount" format:"base-units"`
 		AmountDecimal    string `json:"amount_decimal" flag:"amount-decimal" format:"decimal-amount"`
+		AmountUSD        string `json:"amount_usd" flag:"amount-usd" format:"decimal-amount"`
 		FromAmountForGas string `json:"from_amount_for_gas" flag:"from-amount-for-gas" format:"base-units"`
 		WalletRef        string `json:"wallet" flag:"wallet" format:"identifier"`
 		FromAddress      string `json:"from_address" flag:"from-address" format:"evm-address"`
-		Recipient        string `json:"recipient" flag:"recipient" format:"evm-address"`
+		Recipient        string `json:"recipient" flag:"recipient" format:"address"`
 		SlippageBps      int64  `json:"slippage_bps" flag:"slippage-bps"`
 		Simulate         bool   `json:"simulate" flag:"simulate"`
+		Preview          bool   `json:"preview" flag:"preview"`
 		RPCURL           string `json:"rpc_url" flag:"rpc-url" format:"url"`
 	}
 	type bridgeSubmitArgs struct {
@@ -89,6 +85,10 @@ func (s *runtimeState) addBridgeExecutionSubcommands(root *cobra.Command) {
 		AllowMaxApproval   bool    `json:"allow_max_approval" flag:"allow-max-approval"`
 		UnsafeProviderTx   bool    `json:"unsafe_provider_tx" flag:"unsafe-provider-tx"`
 		FeeToken           string  `json:"fee_token" flag:"fee-token" format:"evm-address"`
+		PrivateTx          bool    `json:"private_tx" flag:"private-tx"`
+		PrivateRelay       string  `json:"private_relay" flag:"private-relay"`
+		PrivateFallback    bool    `json:"private_fallback" flag:"private-fallback"`
+		Fresh              bool    `json:"fresh" flag:"fresh"`
 	}
 	var plan bridgePlanArgs
 	planCmd := &cobra.Command{
@@ -103,12 +103,12 @@ func (s *runtimeState) addBridgeExecutionSubcommands(root *cobra.Command) {
 			if err != nil {
 				return err
 			}
-			reqStruct, err := buildRequest(plan.FromArg, plan.ToArg, plan.AssetArg, plan.ToAssetArg, plan.AmountBase, plan.AmountDecimal, plan.FromAmountForGas)
+			ctx, cancel := context.WithTimeout(context.Background(), s.settings.Timeout)
+			defer cancel()
+			reqStruct, amountUSD, err := buildRequest(ctx, plan.FromArg, plan.ToArg, plan.AssetArg, plan.ToAssetArg, plan.AmountBase, plan.AmountDecimal, plan.AmountUSD, plan.FromAmountForGas)
 			if err != nil {
 				return err
 			}
-			ctx, cancel := context.WithTimeout(context.Background(), s.settings.Timeout)
-			defer cancel()
 			start := time.Now()
 			action, providerInfoName, err := s.actionBuilderRegistry().BuildBridgeAction(ctx, providerName, reqStruct, providers.BridgeExecutionOptions{
 				Sender:           identity.FromAddress,
@@ -127,29 +127,38 @@ func (s *runtimeState) addBridgeExecutionSubcommands(root *cobra.Command) {
 				return err
 			}
 			applyExecutionIdentityToAction(&action, identity)
+			applyActionAmountUSD(&action, amountUSD)
+			if err := s.checkActionProtocolPolicy(action); err != nil {
+				return err
+			}
+			warnings := append(identity.Warnings, prefetchPlannedAction(ctx, &action)...)
 			if err := s.ensureActionStore(); err != nil {
 				return err
 			}
+			recordPlanInvocation(cmd, &action)
 			if err := s.actionStore.Save(action); err != nil {
 				return clierr.Wrap(clierr.CodeInternal, "persist planned action", err)
 			}
 			s.captureCommandDiagnostics(nil, statuses, false)
-			return s.emitSuccess(trimRootPath(cmd.CommandPath()), action, identity.Warnings, cacheMetaBypass(), statuses, false)
+			attachPlanPreview(&action, plan.Preview)
+			return s.emitSuccess(trimRootPath(cmd.CommandPath()), action, warnings, cacheMetaBypass(), statuses, false)
 		},
 	}
-	planCmd.Flags().StringVar(&plan.Provider, "provider", "", "Bridge provider (across|lifi)")
+	planCmd.Flags().StringVar(&plan.Provider, "provider", "", "Bridge provider (across|lifi|wormhole|cctp)")
 	planCmd.Flags().StringVar(&plan.FromArg, "from", "", "Source chain")
 	planCmd.Flags().StringVar(&plan.ToArg, "to", "", "Destination chain")
 	planCmd.Flags().StringVar(&plan.AssetArg, "asset", "", "Asset on source chain")
 	planCmd.Flags().StringVar(&plan.ToAssetArg, "to-asset", "", "Destination asset override")
 	planCmd.Flags().StringVar(&plan.AmountBase, "amount", "", "Amount in base units")
 	planCmd.Flags().StringVar(&plan.AmountDecimal, "amount-decimal", "", "Amount in decimal units")
+	planCmd.Flags().StringVar(&plan.AmountUSD, "amount-usd", "", "Amount as a USD notional, converted at the current asset price")
 	planCmd.Flags().StringVar(&plan.FromAmountForGas, "from-amount-for-gas", "", "Optional amount in source token base units to reserve for destination native gas (LiFi)")
 	planCmd.Flags().StringVar(&plan.WalletRef, "wallet", "", "Wallet identifier or name")
 	planCmd.Flags().StringVar(&plan.FromAddress, "from-address", "", "Sender EOA address")
-	planCmd.Flags().StringVar(&plan.Recipient, "recipient", "", "Recipient address (defaults to the resolved sender address)")
+	planCmd.Flags().StringVar(&plan.Recipient, "recipient", "", "Recipient address (defaults to the resolved sender address; Solana wallet required for wormhole routes to Solana)")
 	planCmd.Flags().Int64Var(&plan.SlippageBps, "slippage-bps", 50, "Max slippage in basis points")
 	planCmd.Flags().BoolVar(&plan.Simulate, "simulate", true, "Include simulation checks during execution")
+	planCmd.Flags().BoolVar(&plan.Preview, "preview", false, "Decode step calldata and attach a human-readable preview")
 	planCmd.Flags().StringVar(&plan.RPCURL, "rpc-url", "", "RPC URL override for source chain")
 	_ = planCmd.MarkFlagRequired("from")
 	_ = planCmd.MarkFlagRequired("to")
@@ -208,10 +217,21 @@ func (s *runtimeState) addBridgeExecutionSubcommands(root *cobra.Command) {
 			if err != nil {
 				return err
 			}
+			execOpts.PrivateTx, err = resolvePrivateTxOptions(action, submit.PrivateTx, submit.PrivateRelay, submit.PrivateFallback)
+			if err != nil {
+				return err
+			}
+			execOpts.Fresh = submit.Fresh
 			if err := s.executeActionWithTimeout(&action, resolvedExec.txSigner, resolvedExec.evmBackend, execOpts); err != nil {
 				return err
 			}
-			return s.emitSuccess(trimRootPath(cmd.CommandPath()), action, nil, cacheMetaBypass(), nil, false)
+			ctx, cancel := context.WithTimeout(cmd.Context(), s.settings.Timeout)
+			defer cancel()
+			warnings, err := s.verifyBridgeDelivery(ctx, &action)
+			if err != nil {
+				return err
+			}
+			return s.emitSuccess(trimRootPath(cmd.CommandPath()), action, warnings, cacheMetaBypass(), nil, false)
 		},
 	}
 	submitCmd.Flags().StringVar(&submit.ActionID, "action-id", "", "Action identifier returned by bridge plan")
@@ -228,6 +248,10 @@ func (s *runtimeState) addBridgeExecutionSubcommands(root *cobra.Command) {
 	submitCmd.Flags().BoolVar(&submit.AllowMaxApproval, "allow-max-approval", false, "Allow approval amounts greater than planned input amount (needed for some provider routes, e.g. Across max approvals)")
 	submitCmd.Flags().BoolVar(&submit.UnsafeProviderTx, "unsafe-provider-tx", false, "Bypass provider transaction guardrails for bridge/aggregator payloads")
 	submitCmd.Flags().StringVar(&submit.FeeToken, "fee-token", "", "Fee token address for Tempo chains (defaults to chain USDC.e)")
+	submitCmd.Flags().BoolVar(&submit.PrivateTx, "private-tx", false, "Broadcast through a private relay instead of the public mempool")
+	submitCmd.Flags().StringVar(&submit.PrivateRelay, "private-relay", "flashbots", "Private relay for --private-tx (flashbots|mevblocker|<rpc-url>)")
+	submitCmd.Flags().BoolVar(&submit.PrivateFallback, "private-fallback", false, "Rebroadcast through the public RPC when the private relay rejects or drops the transaction")
+	submitCmd.Flags().BoolVar(&submit.Fresh, "fresh", false, "Ignore chain state prefetched at plan time and re-read it before submitting")
 	annotateStructuredSubmitCommand(submitCmd, bridgeSubmitArgs{})
 
 	var statusActionID string
@@ -249,7 +273,25 @@ func (s *runtimeState) addBridgeExecutionSubcommands(root *cobra.Command) {
 			if action.IntentType != "bridge" {
 				return clierr.New(clierr.CodeUsage, "action is not a bridge intent")
 			}
-			return s.emitSuccess(trimRootPath(cmd.CommandPath()), action, nil, cacheMetaBypass(), nil, false)
+			var warnings []string
+			ctx, cancel := context.WithTimeout(cmd.Context(), s.settings.Timeout)
+			defer cancel()
+			changed, err := execution.RefreshBridgeSettlement(ctx, &action)
+			if err != nil {
+				warnings = append(warnings, "settlement refresh failed: "+err.Error())
+			}
+			if changed {
+				action.Touch()
+				if err := s.actionStore.Save(action); err != nil {
+					return clierr.Wrap(clierr.CodeInternal, "persist action state", err)
+				}
+			}
+			deliveryWarnings, err := s.verifyBridgeDelivery(ctx, &action)
+			if err != nil {
+				return err
+			}
+			warnings = append(warnings, deliveryWarnings...)
+			return s.emitSuccess(trimRootPath(cmd.CommandPath()), action, warnings, cacheMetaBypass(), nil, false)
 		},
 	}
 	statusCmd.Flags().StringVar(&statusActionID, "action-id", "", "Action identifier returned by bridge plan")
@@ -259,3 +301,28 @@ func (s *runtimeState) addBridgeExecutionSubcommands(root *cobra.Command) {
 	root.AddCommand(submitCmd)
 	root.AddCommand(statusCmd)
 }
+
+// verifyBridgeDelivery records what the recipient received on the destination
+// chain once a bridge action has completed. Read failures and shortfalls are
+// returned as warnings; only persisting the action can fail the command.
+func (s *runtimeState) verifyBridgeDelivery(ctx context.Context, action *execution.Action) ([]string, error) {
+	if action.Status != execution.ActionStatusCompleted {
+		return nil, nil
+	}
+	var warnings []string
+	changed, err := execution.VerifyBridgeDelivery(ctx, action)
+	if err != nil {
+		warnings = append(warnings, "delivery verification failed: "+err.Error())
+	}
+	if delivery := action.BridgeDelivery; delivery != nil && delivery.Status == execution.BridgeDeliveryShortfall {
+		warnings = append(warnings, fmt.Sprintf("bridge delivered %s base units, %s below the planned minimum %s", delivery.ReceivedBaseUnits, delivery.ShortfallBaseUnits, delivery.ExpectedMinBaseUnits))
+	}
+	if changed {
+		action.UpdateRealized()
+		action.Touch()
+		if err := s.actionStore.Save(*action); err != nil {
+			return warnings, clierr.Wrap(clierr.CodeInternal, "persist action state", err)
+		}
+	}
+	return warnings, nil
+}
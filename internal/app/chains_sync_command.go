@@ -0,0 +1,148 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ggonzalez94/defi-cli/internal/chainlist"
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/httpx"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/model"
+	"github.com/ggonzalez94/defi-cli/internal/registry"
+	"github.com/ggonzalez94/defi-cli/internal/schema"
+	"github.com/spf13/cobra"
+)
+
+func (s *runtimeState) newChainsSyncCommand() *cobra.Command {
+	var urlArg string
+	cmd := &cobra.Command{
+		Use:   "sync",
+		Short: "Sync chain metadata from chainlist into the local chain registry",
+		Long:  "Downloads the ethereum-lists chain registry (the data behind chainlist.org) and stores it next to the cache. Synced chains resolve by name, slug, or short name in every chain flag, and their public RPCs are used when a chain has no configured or built-in endpoint. Built-in chains always take precedence.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := s.chainsRegistryPath()
+			if path == "" {
+				return clierr.New(clierr.CodeUsage, "no chain registry path; set chains.registry_path or DEFI_CHAINS_PATH")
+			}
+			listURL := strings.TrimSpace(urlArg)
+			if listURL == "" {
+				listURL = s.chainListURL()
+			}
+			reg, err := s.syncChains(cmd.Context(), path, listURL)
+			if err != nil {
+				return err
+			}
+			withRPC := 0
+			for _, entry := range id.SyncedChains() {
+				if len(registry.SyncedRPCURLs(entry.Chain.EVMChainID)) > 0 {
+					withRPC++
+				}
+			}
+			result := model.ChainSyncResult{
+				Source:   reg.Source,
+				Path:     path,
+				SyncedAt: reg.SyncedAt.Format(time.RFC3339),
+				Chains:   len(reg.Chains),
+				Added:    len(id.SyncedChains()),
+				WithRPC:  withRPC,
+			}
+			return s.emitSuccess(trimRootPath(cmd.CommandPath()), result, nil, cacheMetaBypass(), nil, false)
+		},
+	}
+	cmd.Flags().StringVar(&urlArg, "url", "", "Chain list URL (default: chains.list_url or chainid.network)")
+	_ = schema.SetFlagMetadata(cmd.Flags(), "url", schema.FlagMetadata{Format: "url"})
+	response := schema.SchemaFromType(model.ChainSyncResult{})
+	_ = schema.SetCommandMetadata(cmd, schema.CommandMetadata{Mutation: true, Response: &response})
+	return cmd
+}
+
+// loadSyncedChains registers the synced chain list on top of the built-in
+// chains. With auto refresh on, an unknown chain name re-syncs a missing or
+// stale list once per invocation. The list is a cache, so an unreadable file
+// counts as never synced.
+func (s *runtimeState) loadSyncedChains() {
+	id.SetChainRefresher(nil)
+	path := s.chainsRegistryPath()
+	if path == "" {
+		s.applySyncedChains(chainlist.Registry{})
+		return
+	}
+	reg, _ := chainlist.Load(path)
+	s.applySyncedChains(reg)
+	if s.settings.ChainsRefresh {
+		var once sync.Once
+		id.SetChainRefresher(func() bool {
+			refreshed := false
+			once.Do(func() { refreshed = s.refreshSyncedChains(path) })
+			return refreshed
+		})
+	}
+}
+
+func (s *runtimeState) refreshSyncedChains(path string) bool {
+	if !s.syncedChains.Stale(s.runner.now(), chainlist.MaxAge) {
+		return false
+	}
+	listURL := s.chainListURL()
+	reg, err := s.syncChains(context.Background(), path, listURL)
+	if err != nil {
+		s.chainSyncWarnings = append(s.chainSyncWarnings, fmt.Sprintf("could not refresh chain registry: %v", err))
+		return false
+	}
+	s.chainSyncWarnings = append(s.chainSyncWarnings, fmt.Sprintf("refreshed chain registry from %s (%d chains)", reg.Source, len(reg.Chains)))
+	return true
+}
+
+// syncChains downloads the chain list, saves it to path, and registers it.
+func (s *runtimeState) syncChains(ctx context.Context, path, listURL string) (chainlist.Registry, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.settings.Timeout)
+	defer cancel()
+	chains, err := chainlist.Fetch(ctx, httpx.New(s.settings.Timeout, s.settings.Retries), listURL)
+	if err != nil {
+		return chainlist.Registry{}, clierr.Wrap(clierr.CodeUnavailable, "fetch chain list "+listURL, err)
+	}
+	reg := chainlist.Registry{Source: listURL, SyncedAt: s.runner.now().UTC(), Chains: chains}
+	if err := chainlist.Save(path, reg); err != nil {
+		return chainlist.Registry{}, clierr.Wrap(clierr.CodeInternal, "save chain registry", err)
+	}
+	s.applySyncedChains(reg)
+	return reg, nil
+}
+
+// applySyncedChains registers reg's chains and, for chains that are not
+// built in, their public RPC endpoints.
+func (s *runtimeState) applySyncedChains(reg chainlist.Registry) {
+	s.syncedChains = reg
+	id.SetSyncedChains(reg.Entries())
+	rpcURLs := map[int64][]string{}
+	for chainID, urls := range reg.RPCURLs() {
+		if id.IsSyncedChain(chainID) {
+			rpcURLs[chainID] = urls
+		}
+	}
+	registry.SetSyncedRPCURLs(rpcURLs)
+}
+
+// chainsRegistryPath keeps the synced chain list next to the cache database
+// unless chains.registry_path is set.
+func (s *runtimeState) chainsRegistryPath() string {
+	if path := strings.TrimSpace(s.settings.ChainsPath); path != "" {
+		return path
+	}
+	if strings.TrimSpace(s.settings.CachePath) == "" {
+		return ""
+	}
+	return filepath.Join(filepath.Dir(s.settings.CachePath), "chains.json")
+}
+
+func (s *runtimeState) chainListURL() string {
+	if listURL := strings.TrimSpace(s.settings.ChainListURL); listURL != "" {
+		return listURL
+	}
+	return registry.DefaultChainListURL
+}
@@ -0,0 +1,106 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/registry"
+)
+
+func TestChainsSyncAndLazyRefresh(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[
+			{"name": "Ethereum Mainnet", "shortName": "eth", "chainId": 1, "rpc": ["https://eth.example"]},
+			{"name": "Unichain", "shortName": "unichain", "chainId": 130, "nativeCurrency": {"name": "Ether", "symbol": "ETH", "decimals": 18}, "rpc": ["https://mainnet.unichain.org"]}
+		]`))
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	t.Setenv("DEFI_CACHE_PATH", filepath.Join(dir, "cache.db"))
+	t.Setenv("DEFI_CHAIN_LIST_URL", srv.URL)
+	t.Cleanup(func() {
+		id.SetSyncedChains(nil)
+		id.SetChainRefresher(nil)
+		registry.SetSyncedRPCURLs(nil)
+	})
+
+	run := func(args ...string) (int, []byte, string) {
+		var stdout, stderr bytes.Buffer
+		code := NewRunnerWithWriters(&stdout, &stderr).Run(args)
+		return code, stdout.Bytes(), stderr.String()
+	}
+
+	// An unknown chain name syncs the missing registry once and resolves.
+	code, stdout, stderr := run("rpc", "list", "--chain", "unichain")
+	if code != 0 {
+		t.Fatalf("rpc list failed: code=%d stderr=%s", code, stderr)
+	}
+	var env struct {
+		Data []struct {
+			ChainID string `json:"chain_id"`
+			URL     string `json:"url"`
+			Source  string `json:"source"`
+		} `json:"data"`
+		Warnings []string `json:"warnings"`
+	}
+	if err := json.Unmarshal(stdout, &env); err != nil {
+		t.Fatalf("decode rpc list: %v (%s)", err, stdout)
+	}
+	if len(env.Data) != 1 || env.Data[0].ChainID != "eip155:130" || env.Data[0].Source != "chainlist" {
+		t.Fatalf("unexpected endpoints %+v", env.Data)
+	}
+	if len(env.Warnings) != 1 || !strings.Contains(env.Warnings[0], "refreshed chain registry") {
+		t.Fatalf("expected refresh warning, got %v", env.Warnings)
+	}
+	if hits.Load() != 1 {
+		t.Fatalf("expected one chain list fetch, got %d", hits.Load())
+	}
+
+	// A fresh registry is used without refetching.
+	if code, _, stderr := run("rpc", "list", "--chain", "unichain"); code != 0 || hits.Load() != 1 {
+		t.Fatalf("expected cached registry, code=%d hits=%d stderr=%s", code, hits.Load(), stderr)
+	}
+
+	code, stdout, stderr = run("chains", "sync", "--results-only")
+	if code != 0 {
+		t.Fatalf("chains sync failed: code=%d stderr=%s", code, stderr)
+	}
+	var result struct {
+		Chains  int `json:"chains"`
+		Added   int `json:"added"`
+		WithRPC int `json:"with_rpc"`
+	}
+	if err := json.Unmarshal(stdout, &result); err != nil {
+		t.Fatalf("decode sync: %v (%s)", err, stdout)
+	}
+	if result.Chains != 2 || result.Added != 1 || result.WithRPC != 1 || hits.Load() != 2 {
+		t.Fatalf("unexpected sync result %+v hits=%d", result, hits.Load())
+	}
+
+	code, stdout, stderr = run("chains", "list", "--include-synced", "--results-only")
+	if code != 0 {
+		t.Fatalf("chains list failed: code=%d stderr=%s", code, stderr)
+	}
+	var chains []struct {
+		Slug   string `json:"slug"`
+		Synced bool   `json:"synced"`
+	}
+	if err := json.Unmarshal(stdout, &chains); err != nil {
+		t.Fatalf("decode chains: %v (%s)", err, stdout)
+	}
+	last := chains[len(chains)-1]
+	if last.Slug != "unichain" || !last.Synced {
+		t.Fatalf("expected synced unichain at the end, got %+v", last)
+	}
+}
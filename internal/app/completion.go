@@ -0,0 +1,130 @@
+package app
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/schema"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// registerCompletions attaches dynamic shell completion to the flags of every
+// command under root: chain flags complete chain slugs, asset flags the token
+// symbols known for the selected chain, provider flags the configured
+// providers, and enum flags their values. The completion command cobra adds
+// generates the bash, zsh, fish, and PowerShell scripts that call back into
+// these.
+func (s *runtimeState) registerCompletions(root *cobra.Command) {
+	var walk func(cmd *cobra.Command)
+	walk = func(cmd *cobra.Command) {
+		cmd.Flags().VisitAll(func(flag *pflag.Flag) {
+			if fn := s.flagCompletion(flag); fn != nil {
+				_ = cmd.RegisterFlagCompletionFunc(flag.Name, fn)
+			}
+		})
+		for _, child := range cmd.Commands() {
+			walk(child)
+		}
+	}
+	walk(root)
+}
+
+// flagCompletion picks the completion for flag, or nil to leave cobra's
+// default file completion.
+func (s *runtimeState) flagCompletion(flag *pflag.Flag) cobra.CompletionFunc {
+	meta := schema.MergedFlagMetadata(flag)
+	switch {
+	case meta.Format == "chain" || flag.Name == "chain" || strings.HasSuffix(flag.Name, "-chain"):
+		return completeChains
+	case meta.Format == "asset" || flag.Name == "asset" || strings.HasSuffix(flag.Name, "-asset"):
+		return completeAssets
+	case flag.Name == "provider" || flag.Name == "providers":
+		return s.providerCompletion(flag)
+	case len(meta.Enum) > 0:
+		return cobra.FixedCompletions(meta.Enum, cobra.ShellCompDirectiveNoFileComp)
+	}
+	return nil
+}
+
+// completeChains offers every registry chain slug, described by its name and
+// CAIP-2 ID.
+func completeChains(_ *cobra.Command, _ []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	var out []cobra.Completion
+	for _, entry := range id.ListChains() {
+		if strings.HasPrefix(entry.Chain.Slug, strings.ToLower(toComplete)) {
+			out = append(out, cobra.CompletionWithDesc(entry.Chain.Slug, fmt.Sprintf("%s (%s)", entry.Chain.Name, entry.Chain.CAIP2)))
+		}
+	}
+	return out, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeAssets offers the registry token symbols of the chain already given
+// on the command line, or of every chain when none is.
+func completeAssets(cmd *cobra.Command, _ []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	var chainIDs []string
+	for _, name := range []string{"chain", "from-chain"} {
+		flag := cmd.Flags().Lookup(name)
+		if flag == nil || !flag.Changed {
+			continue
+		}
+		if chain, err := id.ParseChain(flag.Value.String()); err == nil {
+			chainIDs = append(chainIDs, chain.CAIP2)
+			break
+		}
+	}
+	if len(chainIDs) == 0 {
+		for _, entry := range id.ListChains() {
+			chainIDs = append(chainIDs, entry.Chain.CAIP2)
+		}
+	}
+	prefix := strings.ToUpper(toComplete)
+	seen := map[string]bool{}
+	var out []cobra.Completion
+	for _, chainID := range chainIDs {
+		for _, token := range id.ChainTokens(chainID) {
+			if seen[token.Symbol] || !strings.HasPrefix(token.Symbol, prefix) {
+				continue
+			}
+			seen[token.Symbol] = true
+			out = append(out, token.Symbol)
+		}
+	}
+	sort.Strings(out)
+	return out, cobra.ShellCompDirectiveNoFileComp
+}
+
+// providerCompletion offers the providers named in the flag's usage, or every
+// configured provider when the usage names none. The provider table is built
+// by the root pre-run, which cobra runs for the completion request too.
+func (s *runtimeState) providerCompletion(flag *pflag.Flag) cobra.CompletionFunc {
+	return func(_ *cobra.Command, _ []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+		mentioned := map[string]bool{}
+		if start, end := strings.Index(flag.Usage, "("), strings.LastIndex(flag.Usage, ")"); start >= 0 && end > start {
+			for _, word := range strings.FieldsFunc(flag.Usage[start+1:end], func(r rune) bool {
+				return r == '|' || r == ',' || r == ';' || r == ' '
+			}) {
+				mentioned[strings.ToLower(word)] = true
+			}
+		}
+		seen := map[string]bool{}
+		var all, named []cobra.Completion
+		for _, info := range s.providerInfos {
+			if seen[info.Name] || !strings.HasPrefix(info.Name, strings.ToLower(toComplete)) {
+				continue
+			}
+			seen[info.Name] = true
+			completion := cobra.CompletionWithDesc(info.Name, info.Type)
+			all = append(all, completion)
+			if mentioned[info.Name] {
+				named = append(named, completion)
+			}
+		}
+		if len(named) > 0 {
+			return named, cobra.ShellCompDirectiveNoFileComp
+		}
+		return all, cobra.ShellCompDirectiveNoFileComp
+	}
+}
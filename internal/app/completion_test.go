@@ -0,0 +1,50 @@
+package app
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestCompletionOffersChainsAssetsAndProviders(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	for _, tc := range []struct {
+		args    []string
+		want    []string
+		exclude []string
+	}{
+		{[]string{"swap", "quote", "--chain", "ba"}, []string{"base\tBase (eip155:8453)"}, []string{"ethereum"}},
+		{[]string{"swap", "quote", "--chain", "base", "--from-asset", "USD"}, []string{"USDC"}, []string{"WETH"}},
+		{[]string{"bridge", "quote", "--provider", ""}, []string{"across", "lifi"}, []string{"odos"}},
+		{[]string{"yield", "opportunities", "--sort", ""}, []string{"apy_total"}, nil},
+	} {
+		var stdout, stderr bytes.Buffer
+		r := NewRunnerWithWriters(&stdout, &stderr)
+		if code := r.Run(append([]string{"__complete"}, tc.args...)); code != 0 {
+			t.Fatalf("%v: completion failed: %d stderr=%s", tc.args, code, stderr.String())
+		}
+		lines := strings.Split(stdout.String(), "\n")
+		for _, want := range tc.want {
+			if !containsLine(lines, want) {
+				t.Fatalf("%v: expected %q in completions:\n%s", tc.args, want, stdout.String())
+			}
+		}
+		for _, exclude := range tc.exclude {
+			for _, line := range lines {
+				if strings.HasPrefix(line, exclude) {
+					t.Fatalf("%v: unexpected %q in completions:\n%s", tc.args, exclude, stdout.String())
+				}
+			}
+		}
+	}
+}
+
+func containsLine(lines []string, want string) bool {
+	for _, line := range lines {
+		if line == want || strings.HasPrefix(line, want+"\t") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,128 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ggonzalez94/defi-cli/internal/config"
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/model"
+	"github.com/ggonzalez94/defi-cli/internal/schema"
+	"github.com/spf13/cobra"
+)
+
+func (s *runtimeState) newConfigCommand() *cobra.Command {
+	root := &cobra.Command{Use: "config", Short: "Configuration commands"}
+	profilesCmd := &cobra.Command{Use: "profiles", Short: "Inspect and switch config profiles"}
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List profiles defined in the config file",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			list, err := config.ListProfiles(s.flags)
+			if err != nil {
+				return clierr.Wrap(clierr.CodeUsage, "list config profiles", err)
+			}
+			return s.emitSuccess(trimRootPath(cmd.CommandPath()), configProfilesResult(list), nil, cacheMetaBypass(), nil, false)
+		},
+	}
+	listResponse := schema.SchemaFromType(model.ConfigProfiles{})
+	_ = schema.SetCommandMetadata(listCmd, schema.CommandMetadata{Response: &listResponse})
+
+	var nameArg string
+	useCmd := &cobra.Command{
+		Use:   "use",
+		Short: "Make a profile the default in the config file",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := strings.TrimSpace(nameArg)
+			if name == "" {
+				return clierr.New(clierr.CodeUsage, "--name is required")
+			}
+			if _, err := config.UseProfile(s.flags, name); err != nil {
+				return clierr.Wrap(clierr.CodeUsage, "use config profile", err)
+			}
+			// --profile and DEFI_PROFILE still win over the file default.
+			flags := s.flags
+			flags.Profile = ""
+			list, err := config.ListProfiles(flags)
+			if err != nil {
+				return clierr.Wrap(clierr.CodeUsage, "list config profiles", err)
+			}
+			var warnings []string
+			if list.Active != name {
+				warnings = append(warnings, fmt.Sprintf("DEFI_PROFILE=%s overrides the config file default", list.Active))
+			} else if strings.TrimSpace(s.flags.Profile) != "" && s.flags.Profile != name {
+				warnings = append(warnings, fmt.Sprintf("--profile %s only applied to this invocation", s.flags.Profile))
+			}
+			return s.emitSuccess(trimRootPath(cmd.CommandPath()), configProfilesResult(list), warnings, cacheMetaBypass(), nil, false)
+		},
+	}
+	useCmd.Flags().StringVar(&nameArg, "name", "", "Profile name to make the default")
+	_ = schema.SetFlagMetadata(useCmd.Flags(), "name", schema.FlagMetadata{Required: true})
+	useResponse := schema.SchemaFromType(model.ConfigProfiles{})
+	_ = schema.SetCommandMetadata(useCmd, schema.CommandMetadata{Mutation: true, Response: &useResponse})
+
+	profilesCmd.AddCommand(listCmd)
+	profilesCmd.AddCommand(useCmd)
+	root.AddCommand(profilesCmd)
+	return root
+}
+
+func configProfilesResult(list config.ProfileList) model.ConfigProfiles {
+	result := model.ConfigProfiles{
+		ConfigPath:   list.ConfigPath,
+		Active:       list.Active,
+		ActiveSource: list.ActiveSource,
+		Profiles:     make([]model.ConfigProfile, 0, len(list.Profiles)),
+	}
+	for _, profile := range list.Profiles {
+		result.Profiles = append(result.Profiles, model.ConfigProfile{
+			Name:             profile.Name,
+			Active:           profile.Name == list.Active,
+			APIKeys:          profile.APIKeys,
+			RPCChains:        profile.RPCChains,
+			DefaultProviders: profile.DefaultProviders,
+			CachePath:        profile.CachePath,
+			PolicyPath:       profile.PolicyPath,
+		})
+	}
+	return result
+}
+
+// configuredRPCURLs parses the chain keys of the configured RPC overrides.
+func configuredRPCURLs(urls map[string][]string) (map[int64][]string, error) {
+	out := make(map[int64][]string, len(urls))
+	for chainArg, list := range urls {
+		chain, err := id.ParseChain(chainArg)
+		if err != nil {
+			return nil, clierr.Wrap(clierr.CodeUsage, fmt.Sprintf("config rpc %s", chainArg), err)
+		}
+		if !chain.IsEVM() {
+			return nil, clierr.New(clierr.CodeUsage, fmt.Sprintf("config rpc %s: only EVM chains take RPC overrides", chainArg))
+		}
+		out[chain.EVMChainID] = append(out[chain.EVMChainID], list...)
+	}
+	return out, nil
+}
+
+// applyDefaultProvider fills --provider from the configured default for the
+// command path or its top-level command when the flag was not given.
+func (s *runtimeState) applyDefaultProvider(cmd *cobra.Command) error {
+	flag := cmd.Flags().Lookup("provider")
+	if flag == nil || flag.Changed || len(s.settings.DefaultProviders) == 0 {
+		return nil
+	}
+	path := normalizeCommandPath(trimRootPath(cmd.CommandPath()))
+	provider, ok := s.settings.DefaultProviders[path]
+	if !ok {
+		provider, ok = s.settings.DefaultProviders[strings.SplitN(path, " ", 2)[0]]
+	}
+	if !ok {
+		return nil
+	}
+	if err := cmd.Flags().Set("provider", provider); err != nil {
+		return clierr.Wrap(clierr.CodeUsage, "apply default provider", err)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ggonzalez94/defi-cli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+func TestConfigProfilesListAndUse(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configPath, []byte("profiles:\n  dev: {}\n  prod:\n    providers:\n      oneinch:\n        api_key: secret-key\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := NewRunnerWithWriters(&stdout, &stderr).Run([]string{"config", "profiles", "use", "--name", "prod", "--config", configPath}); code != 0 {
+		t.Fatalf("use failed: code=%d stderr=%s", code, stderr.String())
+	}
+
+	stdout.Reset()
+	if code := NewRunnerWithWriters(&stdout, &stderr).Run([]string{"config", "profiles", "list", "--config", configPath, "--results-only"}); code != 0 {
+		t.Fatalf("list failed: code=%d stderr=%s", code, stderr.String())
+	}
+	if bytes.Contains(stdout.Bytes(), []byte("secret-key")) {
+		t.Fatalf("profile list leaked an API key: %s", stdout.String())
+	}
+	var out struct {
+		Active   string `json:"active"`
+		Profiles []struct {
+			Name    string   `json:"name"`
+			Active  bool     `json:"active"`
+			APIKeys []string `json:"api_keys"`
+		} `json:"profiles"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+		t.Fatalf("decode output: %v (%s)", err, stdout.String())
+	}
+	if out.Active != "prod" || len(out.Profiles) != 2 || !out.Profiles[1].Active || out.Profiles[1].APIKeys[0] != "oneinch" {
+		t.Fatalf("unexpected profiles output %+v", out)
+	}
+
+	if code := NewRunnerWithWriters(&stdout, &stderr).Run([]string{"config", "profiles", "use", "--name", "staging", "--config", configPath}); code != 2 {
+		t.Fatalf("expected exit 2 for an unknown profile, got %d", code)
+	}
+}
+
+func TestApplyDefaultProvider(t *testing.T) {
+	state := &runtimeState{settings: config.Settings{DefaultProviders: map[string]string{"swap": "uniswap", "swap plan": "odos"}}}
+	root := &cobra.Command{Use: "defi"}
+	swap := &cobra.Command{Use: "swap"}
+	root.AddCommand(swap)
+	var quoteProvider, planProvider string
+	quote := &cobra.Command{Use: "quote"}
+	quote.Flags().StringVar(&quoteProvider, "provider", "", "")
+	plan := &cobra.Command{Use: "plan"}
+	plan.Flags().StringVar(&planProvider, "provider", "", "")
+	swap.AddCommand(quote, plan)
+
+	for _, cmd := range []*cobra.Command{quote, plan} {
+		if err := state.applyDefaultProvider(cmd); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if quoteProvider != "uniswap" || planProvider != "odos" {
+		t.Fatalf("expected group and path defaults, got quote=%s plan=%s", quoteProvider, planProvider)
+	}
+
+	_ = plan.Flags().Set("provider", "paraswap")
+	if err := state.applyDefaultProvider(plan); err != nil || planProvider != "paraswap" {
+		t.Fatalf("expected explicit --provider to win, got %s err=%v", planProvider, err)
+	}
+}
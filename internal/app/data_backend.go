@@ -0,0 +1,33 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/providers"
+)
+
+func parseDataBackend(input string) (providers.DataBackend, error) {
+	switch strings.ToLower(strings.TrimSpace(input)) {
+	case "", string(providers.DataBackendAPI):
+		return providers.DataBackendAPI, nil
+	case string(providers.DataBackendSubgraph):
+		return providers.DataBackendSubgraph, nil
+	default:
+		return "", clierr.New(clierr.CodeUsage, "--backend must be api or subgraph")
+	}
+}
+
+// selectLendingProviderBackend returns the lending provider that reads from
+// backend. Subgraph readers exist only for providers with a known subgraph.
+func (s *runtimeState) selectLendingProviderBackend(providerName string, backend providers.DataBackend) (providers.LendingProvider, error) {
+	if backend != providers.DataBackendSubgraph {
+		return s.selectLendingProvider(providerName)
+	}
+	provider, ok := s.subgraphLenders[providerName]
+	if !ok {
+		return nil, clierr.New(clierr.CodeUnsupported, fmt.Sprintf("--backend subgraph is not available for lending provider %s (supported: aave)", providerName))
+	}
+	return provider, nil
+}
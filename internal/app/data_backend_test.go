@@ -0,0 +1,39 @@
+package app
+
+import (
+	"testing"
+
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/providers"
+)
+
+func TestSelectLendingProviderBackend(t *testing.T) {
+	api := &fakeLendingProvider{name: "aave"}
+	subgraph := &fakeLendingProvider{name: "aave"}
+	s := &runtimeState{
+		lendingProviders: map[string]providers.LendingProvider{"aave": api, "morpho": &fakeLendingProvider{name: "morpho"}},
+		subgraphLenders:  map[string]providers.LendingProvider{"aave": subgraph},
+	}
+	if got, err := s.selectLendingProviderBackend("aave", providers.DataBackendAPI); err != nil || got != api {
+		t.Fatalf("expected the api provider, got %v err=%v", got, err)
+	}
+	if got, err := s.selectLendingProviderBackend("aave", providers.DataBackendSubgraph); err != nil || got != subgraph {
+		t.Fatalf("expected the subgraph provider, got %v err=%v", got, err)
+	}
+	_, err := s.selectLendingProviderBackend("morpho", providers.DataBackendSubgraph)
+	if cErr, ok := clierr.As(err); !ok || cErr.Code != clierr.CodeUnsupported {
+		t.Fatalf("expected unsupported subgraph backend for morpho, got %v", err)
+	}
+}
+
+func TestParseDataBackend(t *testing.T) {
+	if backend, err := parseDataBackend(""); err != nil || backend != providers.DataBackendAPI {
+		t.Fatalf("expected api default, got %q err=%v", backend, err)
+	}
+	if backend, err := parseDataBackend(" Subgraph "); err != nil || backend != providers.DataBackendSubgraph {
+		t.Fatalf("expected subgraph, got %q err=%v", backend, err)
+	}
+	if _, err := parseDataBackend("rpc"); err == nil {
+		t.Fatal("expected unknown backend to be rejected")
+	}
+}
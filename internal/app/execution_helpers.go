@@ -2,13 +2,17 @@ package app
 
 import (
 	"context"
+	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
 	"github.com/ggonzalez94/defi-cli/internal/execution"
 	execsigner "github.com/ggonzalez94/defi-cli/internal/execution/signer"
+	"github.com/ggonzalez94/defi-cli/internal/httpx"
 	"github.com/ggonzalez94/defi-cli/internal/ows"
 	"github.com/spf13/cobra"
 )
@@ -29,12 +33,49 @@ type resolvedSubmitExecution struct {
 }
 
 func (s *runtimeState) executeActionWithTimeout(action *execution.Action, txSigner execsigner.Signer, evmBackend execution.EVMSubmitBackend, opts execution.ExecuteOptions) error {
+	if err := s.checkActionProtocolPolicy(*action); err != nil {
+		return err
+	}
+	if opts.Guardrails == nil {
+		guardrails, err := s.loadExecutionGuardrails()
+		if err != nil {
+			return err
+		}
+		opts.Guardrails = guardrails
+	}
 	timeout := estimateExecutionTimeout(action, opts)
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
+	if opts.Interrupt == nil {
+		interrupt, stop := notifyShutdown()
+		defer stop()
+		opts.Interrupt = interrupt
+	}
 	return execution.ExecuteAction(ctx, s.actionStore, action, txSigner, evmBackend, opts)
 }
 
+// notifyShutdown returns a channel closed on the first SIGINT or SIGTERM.
+// Signal handling is released once it fires, so a second signal terminates
+// the process immediately.
+func notifyShutdown() (<-chan struct{}, func()) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	interrupt := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-signals:
+			signal.Stop(signals)
+			close(interrupt)
+		case <-done:
+		}
+	}()
+	return interrupt, func() {
+		close(done)
+		signal.Stop(signals)
+	}
+}
+
 func resolveActionExecutionBackend(cmd *cobra.Command, action execution.Action, input submitExecutionInputs) (resolvedSubmitExecution, error) {
 	switch strings.ToLower(strings.TrimSpace(string(action.ExecutionBackend))) {
 	case "", string(execution.ExecutionBackendLegacyLocal):
@@ -149,14 +190,15 @@ func estimateExecutionTimeout(action *execution.Action, opts execution.ExecuteOp
 	stages := 0
 	steps := 0
 	if action != nil {
-		for _, step := range action.Steps {
+		for _, step := range action.ExecutionSteps() {
 			if step.Status == execution.StepStatusConfirmed {
 				continue
 			}
 			steps++
 			stages++
-			if step.Type == execution.StepTypeBridge {
-				// Bridge steps wait for source receipt and destination settlement.
+			if step.Type == execution.StepTypeBridge || step.Type == execution.StepTypeOrder {
+				// Bridge steps wait for source receipt and destination settlement;
+				// order steps wait for submission and solver settlement.
 				stages++
 			}
 		}
@@ -169,5 +211,31 @@ func estimateExecutionTimeout(action *execution.Action, opts execution.ExecuteOp
 	}
 	// Add per-step RPC headroom for chain-id/simulation/gas/fee/nonce/broadcast work
 	// so long-running receipt/settlement waits are less likely to be cut off early.
-	return time.Duration(stages)*stepTimeout + time.Duration(steps)*executionStepRPCOverhead
+	// TWAP composites also wait out the interval before each pending slice.
+	return time.Duration(stages)*stepTimeout + time.Duration(steps)*executionStepRPCOverhead + twapWaitBudget(action)
+}
+
+// prefetchPlannedAction embeds the chain state submit needs (chain ID, fees,
+// nonce, allowances, token decimals) into a planned swap or bridge action.
+// It is best-effort: a failed read leaves the action without prefetched state
+// and returns a warning. Offline runs and Tempo actions skip it.
+func prefetchPlannedAction(ctx context.Context, action *execution.Action) []string {
+	if httpx.Offline() || action.ExecutionBackend == execution.ExecutionBackendTempo {
+		return nil
+	}
+	if err := execution.PrefetchAction(ctx, action); err != nil {
+		return []string{"prefetch skipped; submit will read chain state: " + err.Error()}
+	}
+	return nil
+}
+
+// attachPlanPreview decodes the planned action's calldata into its output when
+// plan --preview is set. The preview is attached after the action is saved so
+// it is never persisted.
+func attachPlanPreview(action *execution.Action, preview bool) {
+	if !preview {
+		return
+	}
+	decoded := execution.PreviewAction(*action)
+	action.Preview = &decoded
 }
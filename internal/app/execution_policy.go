@@ -0,0 +1,97 @@
+package app
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/execution"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/policy"
+)
+
+// loadExecutionGuardrails reads the execution policy file and resolves its
+// chains and assets. It returns nil when no policy file exists.
+func (s *runtimeState) loadExecutionGuardrails() (*execution.Guardrails, error) {
+	path := strings.TrimSpace(s.settings.PolicyPath)
+	if path == "" {
+		return nil, nil
+	}
+	file, ok, err := policy.LoadExecutionFile(path)
+	if err != nil {
+		return nil, clierr.Wrap(clierr.CodeUsage, "load execution policy", err)
+	}
+	if !ok {
+		return nil, nil
+	}
+	return newExecutionGuardrails(file)
+}
+
+func newExecutionGuardrails(file policy.ExecutionFile) (*execution.Guardrails, error) {
+	guardrails := &execution.Guardrails{RequireSimulation: file.RequireSimulation}
+	for _, raw := range file.AllowedChains {
+		chain, err := id.ParseChain(raw)
+		if err != nil {
+			return nil, clierr.Wrap(clierr.CodeUsage, "execution policy allowed_chains", err)
+		}
+		guardrails.AllowedChains = append(guardrails.AllowedChains, chain.CAIP2)
+	}
+	for _, raw := range file.AllowedSpenders {
+		if !common.IsHexAddress(strings.TrimSpace(raw)) {
+			return nil, clierr.New(clierr.CodeUsage, fmt.Sprintf("execution policy allowed_spenders: invalid address %q", raw))
+		}
+		guardrails.AllowedSpenders = append(guardrails.AllowedSpenders, common.HexToAddress(strings.TrimSpace(raw)).Hex())
+	}
+	for i, entry := range file.Tokens {
+		token, err := newTokenGuardrail(entry)
+		if err != nil {
+			return nil, clierr.Wrap(clierr.CodeUsage, fmt.Sprintf("execution policy tokens[%d]", i), err)
+		}
+		guardrails.Tokens = append(guardrails.Tokens, token)
+	}
+	return guardrails, nil
+}
+
+func newTokenGuardrail(entry policy.ExecutionToken) (execution.TokenGuardrail, error) {
+	chain, err := id.ParseChain(entry.Chain)
+	if err != nil {
+		return execution.TokenGuardrail{}, err
+	}
+	out := execution.TokenGuardrail{ChainID: chain.CAIP2}
+	decimals := 18
+	if strings.EqualFold(strings.TrimSpace(entry.Asset), execution.NativeToken) {
+		out.Token = execution.NativeToken
+		out.Symbol = execution.NativeToken
+	} else {
+		asset, err := id.ParseAsset(entry.Asset, chain)
+		if err != nil {
+			return execution.TokenGuardrail{}, err
+		}
+		out.Token = strings.ToLower(asset.Address)
+		out.Symbol = asset.Symbol
+		if asset.Decimals > 0 {
+			decimals = asset.Decimals
+		}
+	}
+	for _, limit := range []struct {
+		name  string
+		value string
+		dest  **big.Int
+	}{
+		{"max_per_tx", entry.MaxPerTx, &out.MaxPerTx},
+		{"max_per_day", entry.MaxPerDay, &out.MaxPerDay},
+	} {
+		if strings.TrimSpace(limit.value) == "" {
+			continue
+		}
+		base, _, err := id.NormalizeAmount("", limit.value, decimals)
+		if err != nil {
+			return execution.TokenGuardrail{}, fmt.Errorf("%s: %w", limit.name, err)
+		}
+		parsed, _ := new(big.Int).SetString(base, 10)
+		*limit.dest = parsed
+	}
+	return out, nil
+}
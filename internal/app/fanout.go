@@ -0,0 +1,57 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ggonzalez94/defi-cli/internal/config"
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+)
+
+// providerCall is the outcome of one provider in a fan-out.
+type providerCall[T any] struct {
+	Name    string
+	Value   T
+	Err     error
+	Latency time.Duration
+}
+
+// fanOutProviders calls fetch once per provider name with at most
+// settings.Concurrency calls in flight. Each call gets its own deadline when
+// settings.ProviderTimeout is set, and an expired one is reported as an
+// unavailable error naming the provider. Results keep the order of names so
+// statuses and warnings stay deterministic.
+func fanOutProviders[T any](ctx context.Context, settings config.Settings, names []string, fetch func(ctx context.Context, name string) (T, error)) []providerCall[T] {
+	results := make([]providerCall[T], len(names))
+	limit := settings.Concurrency
+	if limit < 1 {
+		limit = 1
+	}
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			callCtx, cancel := ctx, context.CancelFunc(func() {})
+			if settings.ProviderTimeout > 0 {
+				callCtx, cancel = context.WithTimeout(ctx, settings.ProviderTimeout)
+			}
+			defer cancel()
+			start := time.Now()
+			value, err := fetch(callCtx, name)
+			if err != nil && ctx.Err() == nil && errors.Is(callCtx.Err(), context.DeadlineExceeded) {
+				err = clierr.Wrap(clierr.CodeUnavailable, fmt.Sprintf("provider %s timed out after %s", name, settings.ProviderTimeout), err)
+			}
+			results[i] = providerCall[T]{Name: name, Value: value, Err: err, Latency: time.Since(start)}
+		}(i, name)
+	}
+	wg.Wait()
+	return results
+}
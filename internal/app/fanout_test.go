@@ -0,0 +1,70 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ggonzalez94/defi-cli/internal/config"
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+)
+
+func TestFanOutProvidersBoundsConcurrencyAndKeepsOrder(t *testing.T) {
+	var inFlight, peak atomic.Int32
+	names := []string{"aave", "morpho", "kamino", "moonwell", "staking"}
+	calls := fanOutProviders(context.Background(), config.Settings{Concurrency: 2}, names, func(ctx context.Context, name string) (string, error) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		if name == "kamino" {
+			return "", errors.New("boom")
+		}
+		return name + "-data", nil
+	})
+	if got := peak.Load(); got != 2 {
+		t.Fatalf("expected at most 2 providers in flight, peak was %d", got)
+	}
+	for i, call := range calls {
+		if call.Name != names[i] {
+			t.Fatalf("expected results in provider order, got %s at %d", call.Name, i)
+		}
+		if call.Name == "kamino" {
+			if call.Err == nil {
+				t.Fatal("expected kamino error")
+			}
+			continue
+		}
+		if call.Err != nil || call.Value != call.Name+"-data" {
+			t.Fatalf("unexpected result %+v", call)
+		}
+	}
+}
+
+func TestFanOutProvidersAppliesProviderTimeout(t *testing.T) {
+	settings := config.Settings{Concurrency: 4, ProviderTimeout: 20 * time.Millisecond}
+	calls := fanOutProviders(context.Background(), settings, []string{"fast", "slow"}, func(ctx context.Context, name string) (int, error) {
+		if name == "fast" {
+			return 1, nil
+		}
+		<-ctx.Done()
+		return 0, ctx.Err()
+	})
+	if calls[0].Err != nil || calls[0].Value != 1 {
+		t.Fatalf("expected fast provider to succeed, got %+v", calls[0])
+	}
+	cErr, ok := clierr.As(calls[1].Err)
+	if !ok || cErr.Code != clierr.CodeUnavailable {
+		t.Fatalf("expected unavailable timeout error, got %v", calls[1].Err)
+	}
+	if statusFromErr(calls[1].Err) != "unavailable" {
+		t.Fatalf("expected unavailable status, got %s", statusFromErr(calls[1].Err))
+	}
+}
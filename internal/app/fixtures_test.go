@@ -0,0 +1,95 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/registry"
+)
+
+func TestFixturesRecordAndReplayDeterministically(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"name": "Unichain", "shortName": "unichain", "chainId": 130, "rpc": ["https://mainnet.unichain.org"]}]`))
+	}))
+
+	dir := t.TempDir()
+	fixtureDir := filepath.Join(dir, "fixtures")
+	t.Setenv("DEFI_CACHE_PATH", filepath.Join(dir, "cache.db"))
+	t.Setenv("DEFI_CHAINS_PATH", filepath.Join(dir, "chains.json"))
+	t.Setenv("DEFI_CHAIN_LIST_URL", srv.URL)
+	t.Cleanup(func() {
+		id.SetSyncedChains(nil)
+		id.SetChainRefresher(nil)
+		registry.SetSyncedRPCURLs(nil)
+	})
+
+	run := func(args ...string) (int, string, string) {
+		var stdout, stderr bytes.Buffer
+		code := NewRunnerWithWriters(&stdout, &stderr).Run(args)
+		return code, stdout.String(), stderr.String()
+	}
+
+	if code, _, stderr := run("chains", "sync", "--record-fixtures", fixtureDir); code != 0 {
+		t.Fatalf("record failed: code=%d stderr=%s", code, stderr)
+	}
+	srv.Close()
+
+	code, first, stderr := run("chains", "sync", "--replay-fixtures", fixtureDir)
+	if code != 0 {
+		t.Fatalf("replay failed: code=%d stderr=%s", code, stderr)
+	}
+	_, second, _ := run("chains", "sync", "--replay-fixtures", fixtureDir)
+	if first != second {
+		t.Fatalf("expected identical replays:\n%s\n%s", first, second)
+	}
+	var env struct {
+		Data struct {
+			Chains int `json:"chains"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(first), &env); err != nil || env.Data.Chains != 1 {
+		t.Fatalf("unexpected replay envelope %s (%v)", first, err)
+	}
+
+	t.Setenv("DEFI_CHAIN_LIST_URL", srv.URL+"/unrecorded")
+	if code, _, stderr := run("chains", "sync", "--replay-fixtures", fixtureDir); code != int(clierr.CodeUnavailable) {
+		t.Fatalf("expected a missing fixture to fail as unavailable, got code=%d stderr=%s", code, stderr)
+	}
+	if code, _, _ := run("chains", "sync", "--replay-fixtures", filepath.Join(dir, "missing")); code != 2 {
+		t.Fatalf("expected replay without a recording to be a usage error, got %d", code)
+	}
+	if code, _, _ := run("chains", "sync", "--replay-fixtures", fixtureDir, "--record-fixtures", fixtureDir); code != 2 {
+		t.Fatalf("expected record and replay together to be rejected, got %d", code)
+	}
+}
+
+func TestSeedMakesRequestIDsReproducible(t *testing.T) {
+	requestID := func(args ...string) string {
+		var stdout, stderr bytes.Buffer
+		if code := NewRunnerWithWriters(&stdout, &stderr).Run(args); code != 0 {
+			t.Fatalf("run failed: code=%d stderr=%s", code, stderr.String())
+		}
+		var env struct {
+			Meta struct {
+				RequestID string `json:"request_id"`
+			} `json:"meta"`
+		}
+		if err := json.Unmarshal(stdout.Bytes(), &env); err != nil {
+			t.Fatalf("decode envelope: %v", err)
+		}
+		return env.Meta.RequestID
+	}
+	if a, b := requestID("providers", "list", "--seed", "7"), requestID("providers", "list", "--seed", "7"); a != b {
+		t.Fatalf("expected the same request id for the same seed, got %s and %s", a, b)
+	}
+	if a, b := requestID("providers", "list"), requestID("providers", "list"); a == b {
+		t.Fatalf("expected random request ids without a seed, got %s twice", a)
+	}
+}
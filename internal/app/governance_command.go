@@ -0,0 +1,145 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/model"
+	"github.com/ggonzalez94/defi-cli/internal/providers"
+	"github.com/ggonzalez94/defi-cli/internal/providers/governance"
+	"github.com/ggonzalez94/defi-cli/internal/schema"
+	"github.com/spf13/cobra"
+)
+
+func (s *runtimeState) newGovernanceCommand() *cobra.Command {
+	root := &cobra.Command{Use: "governance", Short: "Protocol governance proposals"}
+
+	var protocolsArg, sourceArg, statusArg, rpcURL string
+	var limit int
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List governance proposals with deadlines and quorum progress",
+		Long:  "Lists proposals from each protocol's Snapshot space and, for Uniswap, its on-chain GovernorBravo on Ethereum. Open proposals come first, soonest deadline first, then the rest newest first.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			protocols := governance.Protocols()
+			if strings.TrimSpace(protocolsArg) != "" {
+				protocols = []string{}
+				for _, name := range splitCSV(protocolsArg) {
+					name = strings.ToLower(name)
+					if !slices.Contains(governance.Protocols(), name) {
+						return clierr.New(clierr.CodeUnsupported, fmt.Sprintf("no governance source for protocol %s; supported: %s", name, strings.Join(governance.Protocols(), ",")))
+					}
+					if !slices.Contains(protocols, name) {
+						protocols = append(protocols, name)
+					}
+				}
+			}
+			source := strings.ToLower(strings.TrimSpace(sourceArg))
+			switch source {
+			case "all":
+				source = ""
+			case governance.SourceSnapshot, governance.SourceOnchain:
+			default:
+				return clierr.New(clierr.CodeUsage, "--source must be one of: snapshot,onchain,all")
+			}
+			status := strings.ToLower(strings.TrimSpace(statusArg))
+			switch status {
+			case "all":
+				status = ""
+			case governance.StatusActive, governance.StatusPending, governance.StatusClosed:
+			default:
+				return clierr.New(clierr.CodeUsage, "--status must be one of: active,pending,closed,all")
+			}
+			if limit <= 0 {
+				return clierr.New(clierr.CodeUsage, "--limit must be positive")
+			}
+
+			key := cacheKey(trimRootPath(cmd.CommandPath()), map[string]any{
+				"protocols": protocols,
+				"source":    source,
+				"status":    status,
+				"limit":     limit,
+				"rpc_url":   strings.TrimSpace(rpcURL),
+			})
+			return s.runCachedCommand(trimRootPath(cmd.CommandPath()), key, 60*time.Second, func(ctx context.Context) (any, []model.ProviderStatus, []string, bool, error) {
+				provider := s.governanceProvider
+				if provider == nil {
+					return nil, nil, nil, false, clierr.New(clierr.CodeUnsupported, "governance provider is not configured")
+				}
+				applyRPCOverride(provider, rpcURL)
+				start := time.Now()
+				warnings := []string{}
+				proposals := []model.GovernanceProposal{}
+				partial := false
+				var firstErr error
+				for _, protocol := range protocols {
+					if source == governance.SourceOnchain && !governance.HasGovernor(protocol) && strings.TrimSpace(protocolsArg) == "" {
+						// Only explicitly requested protocols fail for a missing governor.
+						continue
+					}
+					items, err := provider.GovernanceProposals(ctx, providers.GovernanceRequest{Protocol: protocol, Source: source, Status: status, Limit: limit})
+					if err != nil {
+						if len(protocols) == 1 {
+							return nil, []model.ProviderStatus{{Name: provider.Info().Name, Status: statusFromErr(err), LatencyMS: time.Since(start).Milliseconds()}}, nil, false, err
+						}
+						warnings = append(warnings, fmt.Sprintf("protocol %s failed: %v", protocol, err))
+						partial = true
+						if firstErr == nil {
+							firstErr = err
+						}
+						continue
+					}
+					proposals = append(proposals, items...)
+				}
+				var statusErr error
+				if partial {
+					statusErr = firstErr
+				}
+				statuses := []model.ProviderStatus{{Name: provider.Info().Name, Status: statusFromErr(statusErr), LatencyMS: time.Since(start).Milliseconds()}}
+				if len(proposals) == 0 && firstErr != nil {
+					return nil, statuses, warnings, false, firstErr
+				}
+				sortGovernanceProposals(proposals)
+				if len(proposals) > limit {
+					proposals = proposals[:limit]
+				}
+				return proposals, statuses, warnings, partial, nil
+			})
+		},
+	}
+	listCmd.Flags().StringVar(&protocolsArg, "protocol", "", "Protocols (comma-separated; aave, morpho, uniswap); default all")
+	listCmd.Flags().StringVar(&sourceArg, "source", "all", "Proposal source (snapshot|onchain|all)")
+	listCmd.Flags().StringVar(&statusArg, "status", "active", "Proposal status (active|pending|closed|all)")
+	listCmd.Flags().IntVar(&limit, "limit", 20, "Maximum proposals to return")
+	listCmd.Flags().StringVar(&rpcURL, "rpc-url", "", "RPC URL override for on-chain governor reads")
+	listResponse := schema.SchemaFromType([]model.GovernanceProposal{})
+	_ = schema.SetCommandMetadata(listCmd, schema.CommandMetadata{Response: &listResponse})
+	root.AddCommand(listCmd)
+	return root
+}
+
+// sortGovernanceProposals puts open proposals first, soonest deadline first,
+// then closed proposals newest first.
+func sortGovernanceProposals(items []model.GovernanceProposal) {
+	open := func(p model.GovernanceProposal) bool {
+		return p.Status == governance.StatusActive || p.Status == governance.StatusPending
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := items[i], items[j]
+		if open(a) != open(b) {
+			return open(a)
+		}
+		if open(a) && a.EndsAt != b.EndsAt {
+			return a.EndsAt < b.EndsAt
+		}
+		if a.StartsAt != b.StartsAt {
+			return a.StartsAt > b.StartsAt
+		}
+		return a.ProposalID < b.ProposalID
+	})
+}
@@ -0,0 +1,157 @@
+package app
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/model"
+	"github.com/ggonzalez94/defi-cli/internal/providers"
+	"github.com/ggonzalez94/defi-cli/internal/schema"
+	"github.com/spf13/cobra"
+)
+
+func (s *runtimeState) newHistoryCommand() *cobra.Command {
+	root := &cobra.Command{Use: "history", Short: "Historical TVL and price series"}
+
+	var tvlChainArg, tvlProtocolArg, tvlIntervalArg, tvlWindowArg, tvlFromArg, tvlToArg string
+	tvlCmd := &cobra.Command{
+		Use:   "tvl",
+		Short: "Historical TVL for a chain and/or protocol",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			protocol := strings.ToLower(strings.TrimSpace(tvlProtocolArg))
+			if strings.TrimSpace(tvlChainArg) == "" && protocol == "" {
+				return clierr.New(clierr.CodeUsage, "history tvl requires --chain and/or --protocol")
+			}
+			var chain id.Chain
+			if strings.TrimSpace(tvlChainArg) != "" {
+				parsed, err := id.ParseChain(tvlChainArg)
+				if err != nil {
+					return err
+				}
+				chain = parsed
+			}
+			interval, err := parseYieldHistoryInterval(tvlIntervalArg)
+			if err != nil {
+				return err
+			}
+			if interval != providers.YieldHistoryIntervalDay {
+				return clierr.New(clierr.CodeUnsupported, "history tvl supports --interval day only")
+			}
+			startTime, endTime, err := resolveYieldHistoryRange(tvlFromArg, tvlToArg, tvlWindowArg, s.runner.now().UTC())
+			if err != nil {
+				return err
+			}
+
+			key := cacheKey(trimRootPath(cmd.CommandPath()), map[string]any{
+				"chain":      chain.CAIP2,
+				"protocol":   protocol,
+				"interval":   interval,
+				"start_time": startTime.UTC().Format(time.RFC3339),
+				"end_time":   endTime.UTC().Format(time.RFC3339),
+			})
+			return s.runCachedCommand(trimRootPath(cmd.CommandPath()), key, 5*time.Minute, func(ctx context.Context) (any, []model.ProviderStatus, []string, bool, error) {
+				provider, err := s.marketHistoryProvider()
+				if err != nil {
+					return nil, nil, nil, false, err
+				}
+				start := time.Now()
+				data, err := provider.TVLHistory(ctx, providers.TVLHistoryRequest{
+					Chain:     chain,
+					Protocol:  protocol,
+					StartTime: startTime,
+					EndTime:   endTime,
+				})
+				status := []model.ProviderStatus{{Name: provider.Info().Name, Status: statusFromErr(err), LatencyMS: time.Since(start).Milliseconds()}}
+				return data, status, nil, false, err
+			})
+		},
+	}
+	tvlCmd.Flags().StringVar(&tvlChainArg, "chain", "", "Chain identifier (omit with --protocol for all-chain protocol TVL)")
+	tvlCmd.Flags().StringVar(&tvlProtocolArg, "protocol", "", "DefiLlama protocol slug (for example aave-v3)")
+	tvlCmd.Flags().StringVar(&tvlIntervalArg, "interval", "day", "Point interval (day)")
+	tvlCmd.Flags().StringVar(&tvlWindowArg, "window", "30d", "Lookback window (for example 7d,30d,90d)")
+	tvlCmd.Flags().StringVar(&tvlFromArg, "from", "", "Start time (RFC3339). Overrides --window when set")
+	tvlCmd.Flags().StringVar(&tvlToArg, "to", "", "End time (RFC3339). Defaults to now")
+	_ = schema.SetFlagMetadata(tvlCmd.Flags(), "interval", schema.FlagMetadata{Enum: []string{"day"}})
+	tvlResponse := schema.SchemaFromType(model.MarketHistorySeries{})
+	_ = schema.SetCommandMetadata(tvlCmd, schema.CommandMetadata{Response: &tvlResponse})
+	root.AddCommand(tvlCmd)
+
+	var priceChainArg, priceAssetArg, priceIntervalArg, priceWindowArg, priceFromArg, priceToArg string
+	priceCmd := &cobra.Command{
+		Use:   "price",
+		Short: "Historical USD price for an asset",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			chain, asset, err := parseChainAsset(priceChainArg, priceAssetArg)
+			if err != nil {
+				return err
+			}
+			interval, err := parseYieldHistoryInterval(priceIntervalArg)
+			if err != nil {
+				return err
+			}
+			startTime, endTime, err := resolveYieldHistoryRange(priceFromArg, priceToArg, priceWindowArg, s.runner.now().UTC())
+			if err != nil {
+				return err
+			}
+
+			key := cacheKey(trimRootPath(cmd.CommandPath()), map[string]any{
+				"chain":      chain.CAIP2,
+				"asset":      asset.AssetID,
+				"interval":   interval,
+				"start_time": startTime.UTC().Format(time.RFC3339),
+				"end_time":   endTime.UTC().Format(time.RFC3339),
+			})
+			return s.runCachedCommand(trimRootPath(cmd.CommandPath()), key, 5*time.Minute, func(ctx context.Context) (any, []model.ProviderStatus, []string, bool, error) {
+				provider, err := s.priceHistoryProvider(chain)
+				if err != nil {
+					return nil, nil, nil, false, err
+				}
+				start := time.Now()
+				data, err := provider.PriceHistory(ctx, providers.PriceHistoryRequest{
+					Chain:     chain,
+					Asset:     asset,
+					StartTime: startTime,
+					EndTime:   endTime,
+					Interval:  interval,
+				})
+				status := []model.ProviderStatus{{Name: provider.Info().Name, Status: statusFromErr(err), LatencyMS: time.Since(start).Milliseconds()}}
+				return data, status, nil, false, err
+			})
+		},
+	}
+	priceCmd.Flags().StringVar(&priceChainArg, "chain", "", "Chain identifier")
+	priceCmd.Flags().StringVar(&priceAssetArg, "asset", "", "Asset symbol/address/CAIP-19")
+	priceCmd.Flags().StringVar(&priceIntervalArg, "interval", "day", "Point interval (hour|day)")
+	priceCmd.Flags().StringVar(&priceWindowArg, "window", "7d", "Lookback window (for example 24h,7d,30d)")
+	priceCmd.Flags().StringVar(&priceFromArg, "from", "", "Start time (RFC3339). Overrides --window when set")
+	priceCmd.Flags().StringVar(&priceToArg, "to", "", "End time (RFC3339). Defaults to now")
+	_ = priceCmd.MarkFlagRequired("chain")
+	_ = priceCmd.MarkFlagRequired("asset")
+	_ = schema.SetFlagMetadata(priceCmd.Flags(), "interval", schema.FlagMetadata{Enum: []string{"hour", "day"}})
+	priceResponse := schema.SchemaFromType(model.MarketHistorySeries{})
+	_ = schema.SetCommandMetadata(priceCmd, schema.CommandMetadata{Response: &priceResponse})
+	root.AddCommand(priceCmd)
+
+	return root
+}
+
+func (s *runtimeState) marketHistoryProvider() (providers.MarketHistoryProvider, error) {
+	provider, ok := s.marketProvider.(providers.MarketHistoryProvider)
+	if !ok {
+		return nil, clierr.New(clierr.CodeUnsupported, "market data provider does not support history")
+	}
+	return provider, nil
+}
+
+// priceHistoryProvider prefers a chain's spot venue (Hyperliquid for
+// HyperEVM) over the market data provider.
+func (s *runtimeState) priceHistoryProvider(chain id.Chain) (providers.PriceHistoryProvider, error) {
+	if provider, ok := s.spotPriceProviders[chain.CAIP2]; ok {
+		return provider, nil
+	}
+	return s.marketHistoryProvider()
+}
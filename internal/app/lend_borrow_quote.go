@@ -0,0 +1,243 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/model"
+	"github.com/ggonzalez94/defi-cli/internal/providers"
+	"github.com/ggonzalez94/defi-cli/internal/schema"
+	"github.com/spf13/cobra"
+)
+
+func (s *runtimeState) newLendBorrowQuoteCommand() *cobra.Command {
+	var providerArg, compareArg, chainArg, assetArg, collateralArg, amountBase, amountDecimal, amountUSDArg, rpcURL string
+	var limit int
+	cmd := &cobra.Command{
+		Use:   "borrow-quote",
+		Short: "Compare borrow venues by cost, liquidity, and LTV",
+		Long:  "Lists the markets of --provider, and of every --compare provider, that lend --asset against --collateral, ranked by whether the available liquidity covers the amount, then lowest borrow APY, then highest max LTV. With a USD price for the asset, each venue also has the annual interest cost and the minimum collateral value for the amount at its max LTV.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			providerName := normalizeLendingProvider(providerArg)
+			if providerName == "" {
+				return clierr.New(clierr.CodeUsage, "--provider is required")
+			}
+			providerNames := []string{providerName}
+			for _, name := range splitCSV(compareArg) {
+				name = normalizeLendingProvider(name)
+				if !slices.Contains(providerNames, name) {
+					providerNames = append(providerNames, name)
+				}
+			}
+			for _, name := range providerNames {
+				if _, err := s.selectLendingProvider(name); err != nil {
+					return err
+				}
+				if err := s.protocolRules().Check(name); err != nil {
+					return err
+				}
+			}
+			chain, asset, err := parseChainAsset(chainArg, assetArg)
+			if err != nil {
+				return err
+			}
+			collateral, err := parseOptionalChainAsset(chain, collateralArg)
+			if err != nil {
+				return err
+			}
+			decimals := asset.Decimals
+			if decimals <= 0 {
+				decimals = 18
+			}
+			amountCtx, cancel := context.WithTimeout(context.Background(), s.settings.Timeout)
+			base, decimal, amountUSD, err := s.normalizeAmountInput(amountCtx, chain, asset, amountBase, amountDecimal, amountUSDArg, decimals)
+			cancel()
+			if err != nil {
+				return err
+			}
+			amount := model.AmountInfo{AmountBaseUnits: base, AmountDecimal: decimal, Decimals: decimals, AmountUSD: amountUSD}
+
+			key := cacheKey(trimRootPath(cmd.CommandPath()), map[string]any{
+				"provider":   providerName,
+				"compare":    providerNames[1:],
+				"chain":      chain.CAIP2,
+				"asset":      asset.AssetID,
+				"collateral": chainAssetFilterCacheValue(collateral, collateralArg),
+				"amount":     base,
+				"amount_usd": amountUSD,
+				"limit":      limit,
+				"rpc_url":    strings.TrimSpace(rpcURL),
+			})
+			return s.runCachedCommand(trimRootPath(cmd.CommandPath()), key, 30*time.Second, func(ctx context.Context) (any, []model.ProviderStatus, []string, bool, error) {
+				req := providers.BorrowQuoteRequest{Chain: chain, Asset: asset, Collateral: collateral}
+				statuses := make([]model.ProviderStatus, 0, len(providerNames))
+				warnings := []string{}
+				quotes := []model.BorrowQuote{}
+				partial := false
+				var firstErr error
+				for _, name := range providerNames {
+					data, status, providerWarnings, err := s.borrowQuotes(ctx, name, req, rpcURL)
+					statuses = append(statuses, status)
+					if err != nil {
+						if len(providerNames) == 1 {
+							return nil, statuses, nil, false, err
+						}
+						warnings = append(warnings, fmt.Sprintf("provider %s failed: %v", name, err))
+						partial = true
+						if firstErr == nil {
+							firstErr = err
+						}
+						continue
+					}
+					warnings = append(warnings, providerWarnings...)
+					quotes = append(quotes, data...)
+				}
+				if len(quotes) == 0 {
+					return nil, statuses, warnings, false, firstErr
+				}
+				priceUSD, err := s.tokenPriceUSD(ctx, chain, asset)
+				if err != nil {
+					warnings = append(warnings, fmt.Sprintf("no USD price for %s; amount_usd, fits_liquidity, and min_collateral_usd are omitted: %v", asset.Symbol, err))
+				}
+				for i := range quotes {
+					applyBorrowAmount(&quotes[i], amount, priceUSD)
+				}
+				quotes = rankBorrowQuotes(quotes)
+				if limit > 0 && len(quotes) > limit {
+					quotes = quotes[:limit]
+				}
+				return quotes, statuses, warnings, partial, nil
+			})
+		},
+	}
+	cmd.Flags().StringVar(&providerArg, "provider", "", "Lending provider (aave, morpho, kamino, moonwell)")
+	cmd.Flags().StringVar(&compareArg, "compare", "", "Also quote these lending providers (comma-separated) and rank all venues together")
+	cmd.Flags().StringVar(&chainArg, "chain", "", "Chain identifier")
+	cmd.Flags().StringVar(&assetArg, "asset", "", "Asset to borrow (symbol/address/CAIP-19)")
+	cmd.Flags().StringVar(&collateralArg, "collateral", "", "Collateral asset (symbol/address/CAIP-19); providers without collateral-specific markets ignore it")
+	cmd.Flags().StringVar(&amountBase, "amount", "", "Borrow amount in base units")
+	cmd.Flags().StringVar(&amountDecimal, "amount-decimal", "", "Borrow amount in decimal units")
+	cmd.Flags().StringVar(&amountUSDArg, "amount-usd", "", "Borrow amount as a USD notional, converted at the current asset price")
+	cmd.Flags().IntVar(&limit, "limit", 10, "Maximum venues to return")
+	cmd.Flags().StringVar(&rpcURL, "rpc-url", "", "Optional RPC URL override for on-chain providers")
+	_ = cmd.MarkFlagRequired("provider")
+	_ = cmd.MarkFlagRequired("chain")
+	_ = cmd.MarkFlagRequired("asset")
+	_ = schema.SetFlagMetadata(cmd.Flags(), "collateral", schema.FlagMetadata{Format: "asset"})
+	_ = schema.SetFlagMetadata(cmd.Flags(), "amount-usd", schema.FlagMetadata{Format: "decimal-amount"})
+	response := schema.SchemaFromType([]model.BorrowQuote{})
+	_ = schema.SetCommandMetadata(cmd, schema.CommandMetadata{Response: &response})
+	return cmd
+}
+
+// borrowQuotes returns the venues of one provider. Providers without LTV
+// data are quoted from their lending markets, without collateral filtering.
+func (s *runtimeState) borrowQuotes(ctx context.Context, name string, req providers.BorrowQuoteRequest, rpcURL string) ([]model.BorrowQuote, model.ProviderStatus, []string, error) {
+	provider, err := s.selectLendingProvider(name)
+	if err != nil {
+		return nil, model.ProviderStatus{Name: name, Status: statusFromErr(err)}, nil, err
+	}
+	applyRPCOverride(provider, rpcURL)
+	start := time.Now()
+	if quoter, ok := provider.(providers.LendingBorrowQuoteProvider); ok {
+		data, err := quoter.BorrowQuotes(ctx, req)
+		status := model.ProviderStatus{Name: provider.Info().Name, Status: statusFromErr(err), LatencyMS: time.Since(start).Milliseconds()}
+		return data, status, nil, err
+	}
+	markets, err := provider.LendMarkets(ctx, name, req.Chain, req.Asset)
+	status := model.ProviderStatus{Name: provider.Info().Name, Status: statusFromErr(err), LatencyMS: time.Since(start).Milliseconds()}
+	if err != nil {
+		return nil, status, nil, err
+	}
+	warnings := []string{fmt.Sprintf("provider %s does not report LTV caps; its venues have no max_ltv_pct", name)}
+	if strings.TrimSpace(req.Collateral.AssetID) != "" {
+		warnings = append(warnings, fmt.Sprintf("provider %s venues are not filtered by --collateral", name))
+	}
+	out := make([]model.BorrowQuote, 0, len(markets))
+	for _, market := range markets {
+		if market.BorrowAPY <= 0 {
+			continue
+		}
+		out = append(out, model.BorrowQuote{
+			Protocol:              market.Protocol,
+			Provider:              market.Provider,
+			ChainID:               market.ChainID,
+			AssetID:               market.AssetID,
+			ProviderNativeID:      market.ProviderNativeID,
+			ProviderNativeIDKind:  market.ProviderNativeIDKind,
+			BorrowAPY:             market.BorrowAPY,
+			AvailableLiquidityUSD: market.LiquidityUSD,
+			SourceURL:             market.SourceURL,
+			FetchedAt:             market.FetchedAt,
+		})
+	}
+	return out, status, warnings, nil
+}
+
+// tokenPriceUSD returns the USD price of asset from the market data provider.
+func (s *runtimeState) tokenPriceUSD(ctx context.Context, chain id.Chain, asset id.Asset) (float64, error) {
+	provider, ok := s.marketProvider.(providers.TokenPriceProvider)
+	if !ok {
+		return 0, clierr.New(clierr.CodeUnsupported, "market data provider does not support token prices")
+	}
+	address := priceCheckAddress(asset.Address)
+	prices, err := provider.TokenPrices(ctx, chain, []string{address})
+	if err != nil {
+		return 0, err
+	}
+	price, ok := prices[address]
+	if !ok || price.PriceUSD <= 0 {
+		return 0, clierr.New(clierr.CodeUnavailable, "price unavailable")
+	}
+	return price.PriceUSD, nil
+}
+
+// applyBorrowAmount sets the amount of quote and, with a USD price, the
+// amount-dependent cost, liquidity, and collateral fields.
+func applyBorrowAmount(quote *model.BorrowQuote, amount model.AmountInfo, priceUSD float64) {
+	quote.Amount = amount
+	if priceUSD <= 0 {
+		return
+	}
+	units, _ := strconv.ParseFloat(amount.AmountDecimal, 64)
+	quote.AmountUSD = units * priceUSD
+	quote.AnnualCostUSD = quote.AmountUSD * quote.BorrowAPY / 100
+	fits := quote.AvailableLiquidityUSD >= quote.AmountUSD
+	quote.FitsLiquidity = &fits
+	if quote.MaxLTVPct > 0 {
+		quote.MinCollateralUSD = quote.AmountUSD / (quote.MaxLTVPct / 100)
+	}
+}
+
+// rankBorrowQuotes orders venues that can fill the amount first, then by
+// lowest borrow APY, highest max LTV, and deepest liquidity.
+func rankBorrowQuotes(items []model.BorrowQuote) []model.BorrowQuote {
+	short := func(q model.BorrowQuote) bool { return q.FitsLiquidity != nil && !*q.FitsLiquidity }
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := items[i], items[j]
+		if short(a) != short(b) {
+			return !short(a)
+		}
+		if a.BorrowAPY != b.BorrowAPY {
+			return a.BorrowAPY < b.BorrowAPY
+		}
+		if a.MaxLTVPct != b.MaxLTVPct {
+			return a.MaxLTVPct > b.MaxLTVPct
+		}
+		if a.AvailableLiquidityUSD != b.AvailableLiquidityUSD {
+			return a.AvailableLiquidityUSD > b.AvailableLiquidityUSD
+		}
+		return a.ProviderNativeID < b.ProviderNativeID
+	})
+	for i := range items {
+		items[i].Rank = i + 1
+	}
+	return items
+}
@@ -9,7 +9,6 @@ import (
 	"github.com/ggonzalez94/defi-cli/internal/execution/actionbuilder"
 	"github.com/ggonzalez94/defi-cli/internal/execution/planner"
 	execsigner "github.com/ggonzalez94/defi-cli/internal/execution/signer"
-	"github.com/ggonzalez94/defi-cli/internal/id"
 	"github.com/ggonzalez94/defi-cli/internal/model"
 	"github.com/spf13/cobra"
 )
@@ -35,12 +34,14 @@ func (s *runtimeState) newLendVerbExecutionCommand(verb planner.AaveLendVerb, sh
 		MarketID            string `json:"market_id" flag:"market-id" format:"bytes32"`
 		AmountBase          string `json:"amount" flag:"amount" format:"base-units"`
 		AmountDecimal       string `json:"amount_decimal" flag:"amount-decimal" format:"decimal-amount"`
+		AmountUSD           string `json:"amount_usd" flag:"amount-usd" format:"decimal-amount"`
 		WalletRef           string `json:"wallet" flag:"wallet" format:"identifier"`
 		FromAddress         string `json:"from_address" flag:"from-address" format:"evm-address"`
 		Recipient           string `json:"recipient" flag:"recipient" format:"evm-address"`
 		OnBehalfOf          string `json:"on_behalf_of" flag:"on-behalf-of" format:"evm-address"`
 		InterestRateMode    int64  `json:"interest_rate_mode" flag:"interest-rate-mode"`
 		Simulate            bool   `json:"simulate" flag:"simulate"`
+		Preview             bool   `json:"preview" flag:"preview"`
 		RPCURL              string `json:"rpc_url" flag:"rpc-url" format:"url"`
 		PoolAddress         string `json:"pool_address" flag:"pool-address" format:"evm-address"`
 		PoolAddressProvider string `json:"pool_address_provider" flag:"pool-address-provider" format:"evm-address"`
@@ -60,6 +61,7 @@ func (s *runtimeState) newLendVerbExecutionCommand(verb planner.AaveLendVerb, sh
 		AllowMaxApproval   bool    `json:"allow_max_approval" flag:"allow-max-approval"`
 		UnsafeProviderTx   bool    `json:"unsafe_provider_tx" flag:"unsafe-provider-tx"`
 		FeeToken           string  `json:"fee_token" flag:"fee-token" format:"evm-address"`
+		PostState          bool    `json:"post_state" flag:"post-state"`
 	}
 	buildAction := func(ctx context.Context, args lendArgs) (execution.Action, error) {
 		chain, asset, err := parseChainAsset(args.ChainArg, args.AssetArg)
@@ -70,11 +72,11 @@ func (s *runtimeState) newLendVerbExecutionCommand(verb planner.AaveLendVerb, sh
 		if decimals <= 0 {
 			decimals = 18
 		}
-		base, _, err := id.NormalizeAmount(args.AmountBase, args.AmountDecimal, decimals)
+		base, _, amountUSD, err := s.normalizeAmountInput(ctx, chain, asset, args.AmountBase, args.AmountDecimal, args.AmountUSD, decimals)
 		if err != nil {
 			return execution.Action{}, err
 		}
-		return s.actionBuilderRegistry().BuildLendAction(ctx, actionbuilder.LendRequest{
+		action, err := s.actionBuilderRegistry().BuildLendAction(ctx, actionbuilder.LendRequest{
 			Provider:            args.Provider,
 			Verb:                verb,
 			Chain:               chain,
@@ -90,6 +92,11 @@ func (s *runtimeState) newLendVerbExecutionCommand(verb planner.AaveLendVerb, sh
 			PoolAddress:         args.PoolAddress,
 			PoolAddressProvider: args.PoolAddressProvider,
 		})
+		if err != nil {
+			return execution.Action{}, err
+		}
+		applyActionAmountUSD(&action, amountUSD)
+		return action, nil
 	}
 
 	var plan lendArgs
@@ -117,13 +124,18 @@ func (s *runtimeState) newLendVerbExecutionCommand(verb planner.AaveLendVerb, sh
 				return err
 			}
 			applyExecutionIdentityToAction(&action, identity)
+			if err := s.checkActionProtocolPolicy(action); err != nil {
+				return err
+			}
 			if err := s.ensureActionStore(); err != nil {
 				return err
 			}
+			recordPlanInvocation(cmd, &action)
 			if err := s.actionStore.Save(action); err != nil {
 				return clierr.Wrap(clierr.CodeInternal, "persist planned action", err)
 			}
 			s.captureCommandDiagnostics(nil, statuses, false)
+			attachPlanPreview(&action, plan.Preview)
 			return s.emitSuccess(trimRootPath(cmd.CommandPath()), action, identity.Warnings, cacheMetaBypass(), statuses, false)
 		},
 	}
@@ -133,12 +145,14 @@ func (s *runtimeState) newLendVerbExecutionCommand(verb planner.AaveLendVerb, sh
 	planCmd.Flags().StringVar(&plan.MarketID, "market-id", "", "Morpho market unique key (required for --provider morpho)")
 	planCmd.Flags().StringVar(&plan.AmountBase, "amount", "", "Amount in base units")
 	planCmd.Flags().StringVar(&plan.AmountDecimal, "amount-decimal", "", "Amount in decimal units")
+	planCmd.Flags().StringVar(&plan.AmountUSD, "amount-usd", "", "Amount as a USD notional, converted at the current asset price")
 	planCmd.Flags().StringVar(&plan.WalletRef, "wallet", "", "Wallet identifier or name")
 	planCmd.Flags().StringVar(&plan.FromAddress, "from-address", "", "Sender EOA address")
 	planCmd.Flags().StringVar(&plan.Recipient, "recipient", "", "Recipient address (defaults to the resolved sender address)")
 	planCmd.Flags().StringVar(&plan.OnBehalfOf, "on-behalf-of", "", "Position owner address (defaults to the resolved sender address)")
 	planCmd.Flags().Int64Var(&plan.InterestRateMode, "interest-rate-mode", 2, "Aave borrow/repay mode (1=stable,2=variable)")
 	planCmd.Flags().BoolVar(&plan.Simulate, "simulate", true, "Include simulation checks during execution")
+	planCmd.Flags().BoolVar(&plan.Preview, "preview", false, "Decode step calldata and attach a human-readable preview")
 	planCmd.Flags().StringVar(&plan.RPCURL, "rpc-url", "", "RPC URL override for the selected chain")
 	planCmd.Flags().StringVar(&plan.PoolAddress, "pool-address", "", "Aave pool address override")
 	planCmd.Flags().StringVar(&plan.PoolAddressProvider, "pool-address-provider", "", "Aave pool address provider override")
@@ -198,10 +212,11 @@ func (s *runtimeState) newLendVerbExecutionCommand(verb planner.AaveLendVerb, sh
 			if err != nil {
 				return err
 			}
-			if err := s.executeActionWithTimeout(&action, resolvedExec.txSigner, resolvedExec.evmBackend, execOpts); err != nil {
+			warnings, err := s.executeActionWithPostState(&action, resolvedExec.txSigner, resolvedExec.evmBackend, execOpts, submit.PostState)
+			if err != nil {
 				return err
 			}
-			return s.emitSuccess(trimRootPath(cmd.CommandPath()), action, nil, cacheMetaBypass(), nil, false)
+			return s.emitSuccess(trimRootPath(cmd.CommandPath()), action, warnings, cacheMetaBypass(), nil, false)
 		},
 	}
 	submitCmd.Flags().StringVar(&submit.ActionID, "action-id", "", "Action identifier returned by lend plan")
@@ -218,6 +233,7 @@ func (s *runtimeState) newLendVerbExecutionCommand(verb planner.AaveLendVerb, sh
 	submitCmd.Flags().BoolVar(&submit.AllowMaxApproval, "allow-max-approval", false, "Allow approval amounts greater than planned input amount")
 	submitCmd.Flags().BoolVar(&submit.UnsafeProviderTx, "unsafe-provider-tx", false, "Bypass provider transaction guardrails for bridge/aggregator payloads")
 	submitCmd.Flags().StringVar(&submit.FeeToken, "fee-token", "", "Fee token address for Tempo chains (defaults to chain USDC.e)")
+	submitCmd.Flags().BoolVar(&submit.PostState, "post-state", false, "Re-read affected balances and positions after execution and attach them as post_state")
 	annotateStructuredSubmitCommand(submitCmd, lendSubmitArgs{})
 
 	var statusActionID string
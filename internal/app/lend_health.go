@@ -0,0 +1,147 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/model"
+	"github.com/ggonzalez94/defi-cli/internal/providers"
+	"github.com/ggonzalez94/defi-cli/internal/schema"
+	"github.com/spf13/cobra"
+)
+
+func (s *runtimeState) newLendHealthCommand() *cobra.Command {
+	var providerArg, chainArg, addressArg, intervalArg string
+	var alertBelow float64
+	var watch bool
+	cmd := &cobra.Command{
+		Use:   "health",
+		Short: "Report health factor and liquidation distance for an account",
+		Long:  "Returns one row per market with debt or collateral: health factor, current, max, and liquidation LTV, each collateral's liquidation threshold, and the price move that triggers liquidation (of all collateral together and of each collateral alone).\n\nWith --alert-below, exits with code 17 (health_alert) when any health factor is below the threshold. --watch re-checks every --interval and writes one envelope per check until the alert fires or the process is interrupted.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			providerName := normalizeLendingProvider(providerArg)
+			if providerName == "" {
+				return clierr.New(clierr.CodeUsage, "--provider is required")
+			}
+			chain, err := id.ParseChain(chainArg)
+			if err != nil {
+				return err
+			}
+			account := strings.TrimSpace(addressArg)
+			if !common.IsHexAddress(account) {
+				return clierr.New(clierr.CodeUsage, "--address must be a valid EVM hex address")
+			}
+			if alertBelow < 0 {
+				return clierr.New(clierr.CodeUsage, "--alert-below must be positive")
+			}
+			interval, err := time.ParseDuration(strings.TrimSpace(intervalArg))
+			if err != nil || interval <= 0 {
+				return clierr.New(clierr.CodeUsage, "--interval must be a positive duration")
+			}
+			path := trimRootPath(cmd.CommandPath())
+			if !watch && alertBelow == 0 {
+				key := cacheKey(path, map[string]any{
+					"provider": providerName,
+					"chain":    chain.CAIP2,
+					"address":  strings.ToLower(account),
+				})
+				return s.runCachedCommand(path, key, 30*time.Second, func(ctx context.Context) (any, []model.ProviderStatus, []string, bool, error) {
+					data, statuses, err := s.fetchLendHealth(ctx, providerName, chain, account)
+					return data, statuses, nil, false, err
+				})
+			}
+			return s.watchLendHealth(path, providerName, chain, account, alertBelow, watch, interval)
+		},
+	}
+	cmd.Flags().StringVar(&providerArg, "provider", "", "Lending provider (aave)")
+	cmd.Flags().StringVar(&chainArg, "chain", "", "Chain identifier")
+	cmd.Flags().StringVar(&addressArg, "address", "", "Position owner address")
+	cmd.Flags().Float64Var(&alertBelow, "alert-below", 0, "Exit with code 17 when a health factor falls below this value")
+	cmd.Flags().BoolVar(&watch, "watch", false, "Re-check every --interval until the alert fires or the process is interrupted")
+	cmd.Flags().StringVar(&intervalArg, "interval", "60s", "Time between checks with --watch")
+	_ = cmd.MarkFlagRequired("provider")
+	_ = cmd.MarkFlagRequired("chain")
+	_ = cmd.MarkFlagRequired("address")
+	_ = schema.SetFlagMetadata(cmd.Flags(), "interval", schema.FlagMetadata{Format: "duration"})
+	response := schema.SchemaFromType([]model.LendHealth{})
+	_ = schema.SetCommandMetadata(cmd, schema.CommandMetadata{Response: &response})
+	return cmd
+}
+
+// watchLendHealth checks health without the cache, once or every interval
+// with watch, and returns a health_alert error when a health factor falls
+// below alertBelow. An interrupt ends the watch successfully.
+func (s *runtimeState) watchLendHealth(path, providerName string, chain id.Chain, account string, alertBelow float64, watch bool, interval time.Duration) error {
+	interrupt, stop := notifyShutdown()
+	defer stop()
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), s.settings.Timeout)
+		data, statuses, err := s.fetchLendHealth(ctx, providerName, chain, account)
+		cancel()
+		s.captureCommandDiagnostics(nil, statuses, false)
+		if err != nil {
+			return err
+		}
+		if err := lendHealthAlert(data, alertBelow); err != nil {
+			return err
+		}
+		if err := s.emitSuccess(path, data, nil, cacheMetaBypass(), statuses, false); err != nil {
+			return err
+		}
+		if !watch {
+			return nil
+		}
+		select {
+		case <-interrupt:
+			return nil
+		case <-time.After(interval):
+		}
+	}
+}
+
+func (s *runtimeState) fetchLendHealth(ctx context.Context, providerName string, chain id.Chain, account string) ([]model.LendHealth, []model.ProviderStatus, error) {
+	provider, err := s.selectLendingProvider(providerName)
+	if err != nil {
+		return nil, nil, err
+	}
+	healthProvider, ok := provider.(providers.LendingHealthProvider)
+	if !ok {
+		return nil, nil, clierr.New(clierr.CodeUnsupported, fmt.Sprintf("lending provider %s does not support health; use aave", providerName))
+	}
+	start := time.Now()
+	data, err := healthProvider.LendHealth(ctx, providers.LendHealthRequest{Chain: chain, Account: account})
+	statuses := []model.ProviderStatus{{Name: provider.Info().Name, Status: statusFromErr(err), LatencyMS: time.Since(start).Milliseconds()}}
+	return data, statuses, err
+}
+
+// lendHealthAlert returns a health_alert error listing every market whose
+// health factor is below alertBelow. Markets without debt never alert.
+func lendHealthAlert(rows []model.LendHealth, alertBelow float64) error {
+	if alertBelow <= 0 {
+		return nil
+	}
+	breached := []model.LendHealth{}
+	for _, row := range rows {
+		if row.HealthFactor != nil && *row.HealthFactor < alertBelow {
+			breached = append(breached, row)
+		}
+	}
+	if len(breached) == 0 {
+		return nil
+	}
+	worst := breached[0]
+	for _, row := range breached[1:] {
+		if *row.HealthFactor < *worst.HealthFactor {
+			worst = row
+		}
+	}
+	return clierr.New(clierr.CodeHealthAlert, fmt.Sprintf("health factor %.4f in market %s is below --alert-below %g", *worst.HealthFactor, worst.MarketAddress, alertBelow)).WithDetails(map[string]any{
+		"alert_below": alertBelow,
+		"markets":     breached,
+	})
+}
@@ -0,0 +1,63 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/model"
+	"github.com/ggonzalez94/defi-cli/internal/providers"
+)
+
+// onchainLendRatesProvider reads lending rates from protocol contracts. It
+// serves lend rates when a protocol's hosted API is down and for protocols
+// without an API provider.
+type onchainLendRatesProvider interface {
+	Info() model.ProviderInfo
+	Supports(provider string, chain id.Chain) bool
+	LendRates(ctx context.Context, provider string, chain id.Chain, asset id.Asset) ([]model.LendRate, error)
+}
+
+// lendRates serves lend rates from the provider's backend, falling back to
+// on-chain reads when it is unavailable or rate limited.
+func (s *runtimeState) lendRates(ctx context.Context, providerName string, backend providers.DataBackend, chain id.Chain, asset id.Asset, rpcURL string) ([]model.LendRate, []model.ProviderStatus, []string, error) {
+	fallback := s.lendRatesFallback
+	canFallback := fallback != nil && fallback.Supports(providerName, chain)
+
+	provider, err := s.selectLendingProviderBackend(providerName, backend)
+	if err != nil {
+		if !canFallback || backend != providers.DataBackendAPI {
+			return nil, nil, nil, err
+		}
+		applyRPCOverride(fallback, rpcURL)
+		start := time.Now()
+		data, err := fallback.LendRates(ctx, providerName, chain, asset)
+		statuses := []model.ProviderStatus{{Name: fallback.Info().Name, Status: statusFromErr(err), LatencyMS: time.Since(start).Milliseconds()}}
+		return data, statuses, nil, err
+	}
+	applyRPCOverride(provider, rpcURL)
+
+	start := time.Now()
+	data, err := provider.LendRates(ctx, providerName, chain, asset)
+	statuses := []model.ProviderStatus{{Name: provider.Info().Name, Status: statusFromErr(err), LatencyMS: time.Since(start).Milliseconds()}}
+	if err == nil || !canFallback || !isLendRatesOutage(err) {
+		return data, statuses, nil, err
+	}
+
+	applyRPCOverride(fallback, rpcURL)
+	start = time.Now()
+	data, fallbackErr := fallback.LendRates(ctx, providerName, chain, asset)
+	statuses = append(statuses, model.ProviderStatus{Name: fallback.Info().Name, Status: statusFromErr(fallbackErr), LatencyMS: time.Since(start).Milliseconds()})
+	if fallbackErr != nil {
+		return nil, statuses, nil, err
+	}
+	warning := fmt.Sprintf("%s %s unavailable; rates read on-chain", providerName, backend)
+	return data, statuses, []string{warning}, nil
+}
+
+func isLendRatesOutage(err error) bool {
+	cErr, ok := clierr.As(err)
+	return ok && (cErr.Code == clierr.CodeUnavailable || cErr.Code == clierr.CodeRateLimited)
+}
@@ -0,0 +1,90 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/model"
+	"github.com/ggonzalez94/defi-cli/internal/providers"
+)
+
+type failingLendRatesProvider struct {
+	*fakeLendingProvider
+	err error
+}
+
+func (f *failingLendRatesProvider) LendRates(context.Context, string, id.Chain, id.Asset) ([]model.LendRate, error) {
+	return nil, f.err
+}
+
+type fakeOnchainLendRates struct {
+	supported map[string]bool
+	calls     int
+}
+
+func (f *fakeOnchainLendRates) Info() model.ProviderInfo {
+	return model.ProviderInfo{Name: "onchain", Type: "lending", Capabilities: []string{"lend.rates"}}
+}
+
+func (f *fakeOnchainLendRates) Supports(provider string, _ id.Chain) bool {
+	return f.supported[provider]
+}
+
+func (f *fakeOnchainLendRates) LendRates(_ context.Context, provider string, chain id.Chain, _ id.Asset) ([]model.LendRate, error) {
+	f.calls++
+	return []model.LendRate{{Provider: provider, ChainID: chain.CAIP2, SupplyAPY: 4, Source: "onchain"}}, nil
+}
+
+func TestLendRatesFallsBackOnchainWhenAPIUnavailable(t *testing.T) {
+	fallback := &fakeOnchainLendRates{supported: map[string]bool{"aave": true}}
+	s := &runtimeState{
+		lendingProviders: map[string]providers.LendingProvider{"aave": &failingLendRatesProvider{
+			fakeLendingProvider: &fakeLendingProvider{name: "aave"},
+			err:                 clierr.New(clierr.CodeUnavailable, "aave graphql down"),
+		}},
+		lendRatesFallback: fallback,
+	}
+	chain := id.Chain{CAIP2: "eip155:1", EVMChainID: 1}
+	data, statuses, warnings, err := s.lendRates(context.Background(), "aave", providers.DataBackendAPI, chain, id.Asset{}, "")
+	if err != nil {
+		t.Fatalf("expected fallback rates, got %v", err)
+	}
+	if len(data) != 1 || data[0].Source != "onchain" {
+		t.Fatalf("expected on-chain rates, got %+v", data)
+	}
+	if len(statuses) != 2 || statuses[0].Status != "unavailable" || statuses[1].Name != "onchain" || statuses[1].Status != "ok" {
+		t.Fatalf("expected api and onchain statuses, got %+v", statuses)
+	}
+	if len(warnings) != 1 {
+		t.Fatalf("expected a fallback warning, got %v", warnings)
+	}
+}
+
+func TestLendRatesKeepsAPIErrorsThatAreNotOutages(t *testing.T) {
+	fallback := &fakeOnchainLendRates{supported: map[string]bool{"aave": true}}
+	s := &runtimeState{
+		lendingProviders: map[string]providers.LendingProvider{"aave": &failingLendRatesProvider{
+			fakeLendingProvider: &fakeLendingProvider{name: "aave"},
+			err:                 clierr.New(clierr.CodeUnsupported, "no aave market"),
+		}},
+		lendRatesFallback: fallback,
+	}
+	_, _, _, err := s.lendRates(context.Background(), "aave", providers.DataBackendAPI, id.Chain{CAIP2: "eip155:1", EVMChainID: 1}, id.Asset{}, "")
+	if cErr, ok := clierr.As(err); !ok || cErr.Code != clierr.CodeUnsupported || fallback.calls != 0 {
+		t.Fatalf("expected the api error without fallback, got %v (fallback calls %d)", err, fallback.calls)
+	}
+}
+
+func TestLendRatesServesCompoundOnchain(t *testing.T) {
+	fallback := &fakeOnchainLendRates{supported: map[string]bool{"compound": true}}
+	s := &runtimeState{lendingProviders: map[string]providers.LendingProvider{}, lendRatesFallback: fallback}
+	data, statuses, warnings, err := s.lendRates(context.Background(), normalizeLendingProvider("comet"), providers.DataBackendAPI, id.Chain{CAIP2: "eip155:8453", EVMChainID: 8453}, id.Asset{}, "")
+	if err != nil {
+		t.Fatalf("expected compound rates, got %v", err)
+	}
+	if len(data) != 1 || data[0].Provider != "compound" || len(statuses) != 1 || statuses[0].Name != "onchain" || len(warnings) != 0 {
+		t.Fatalf("unexpected compound result data=%+v statuses=%+v warnings=%v", data, statuses, warnings)
+	}
+}
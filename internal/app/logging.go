@@ -0,0 +1,50 @@
+package app
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/logx"
+)
+
+// configureLogging installs the diagnostic logger for this invocation. Logs
+// are appended to the log file as JSON lines when one is set and written to
+// stderr as text otherwise; stdout only ever carries the envelope.
+func (s *runtimeState) configureLogging() error {
+	level, enabled, err := logx.ParseLevel(s.settings.LogLevel)
+	if err != nil {
+		return clierr.Wrap(clierr.CodeUsage, "configure logging", err)
+	}
+	if !enabled {
+		logx.Set(nil)
+		return nil
+	}
+	if s.settings.LogFile == "" {
+		logx.Set(logx.New(s.runner.stderr, level, false))
+		return nil
+	}
+	if s.logFile == nil {
+		if err := os.MkdirAll(filepath.Dir(s.settings.LogFile), 0o755); err != nil {
+			return clierr.Wrap(clierr.CodeUsage, "create log file directory", err)
+		}
+		file, err := os.OpenFile(s.settings.LogFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+		if err != nil {
+			return clierr.Wrap(clierr.CodeUsage, "open log file", err)
+		}
+		s.logFile = file
+	}
+	logx.Set(logx.New(s.logFile, level, true))
+	return nil
+}
+
+// closeLogging reinstates the logger that was active before this invocation,
+// so nested runs (MCP tool calls) do not silence the outer one.
+func (s *runtimeState) closeLogging(previous *slog.Logger) {
+	logx.Set(previous)
+	if s.logFile != nil {
+		_ = s.logFile.Close()
+		s.logFile = nil
+	}
+}
@@ -0,0 +1,77 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ggonzalez94/defi-cli/internal/logx"
+)
+
+func TestRunnerVerboseLogsToStderrOnly(t *testing.T) {
+	setUnopenableCacheEnv(t)
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	r := NewRunnerWithWriters(&stdout, &stderr)
+	code := r.Run([]string{"providers", "list", "--results-only", "--verbose"})
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d stderr=%s", code, stderr.String())
+	}
+	var out []map[string]any
+	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+		t.Fatalf("expected stdout to hold only the payload: %v output=%s", err, stdout.String())
+	}
+	if got := stderr.String(); !strings.Contains(got, "level=DEBUG") || !strings.Contains(got, `msg="command started"`) || !strings.Contains(got, `command="providers list"`) {
+		t.Fatalf("expected debug logs on stderr, got %s", got)
+	}
+	if logx.Enabled(slog.LevelError) {
+		t.Fatal("expected logger to be reset after the run")
+	}
+}
+
+func TestRunnerLogFileWritesJSONLines(t *testing.T) {
+	setUnopenableCacheEnv(t)
+	logPath := filepath.Join(t.TempDir(), "logs", "defi.log")
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	r := NewRunnerWithWriters(&stdout, &stderr)
+	code := r.Run([]string{"providers", "list", "--results-only", "--log-file", logPath, "--log-level", "debug"})
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d stderr=%s", code, stderr.String())
+	}
+	if stderr.Len() != 0 {
+		t.Fatalf("expected no stderr output with --log-file, got %s", stderr.String())
+	}
+	buf, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("read log file: %v", err)
+	}
+	var messages []string
+	for _, line := range strings.Split(strings.TrimSpace(string(buf)), "\n") {
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("expected JSON log line, got %q: %v", line, err)
+		}
+		messages = append(messages, entry["msg"].(string))
+	}
+	if len(messages) < 2 || messages[0] != "command started" || messages[len(messages)-1] != "command finished" {
+		t.Fatalf("unexpected log messages: %v", messages)
+	}
+}
+
+func TestRunnerRejectsUnknownLogLevel(t *testing.T) {
+	setUnopenableCacheEnv(t)
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	r := NewRunnerWithWriters(&stdout, &stderr)
+	if code := r.Run([]string{"providers", "list", "--log-level", "trace"}); code != 2 {
+		t.Fatalf("expected usage exit, got %d stderr=%s", code, stderr.String())
+	}
+}
@@ -0,0 +1,94 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/logx"
+	"github.com/ggonzalez94/defi-cli/internal/mcp"
+	"github.com/ggonzalez94/defi-cli/internal/policy"
+	"github.com/ggonzalez94/defi-cli/internal/schema"
+	"github.com/ggonzalez94/defi-cli/internal/version"
+	"github.com/spf13/cobra"
+)
+
+func (s *runtimeState) newMCPCommand() *cobra.Command {
+	var metricsListen, metricsOTLP string
+	cmd := &cobra.Command{
+		Use:   "mcp",
+		Short: "Serve commands as MCP tools over stdio",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			doc, err := schema.Build(s.root, "")
+			if err != nil {
+				return clierr.Wrap(clierr.CodeInternal, "build schema", err)
+			}
+			allowlist := s.settings.EnableCommands
+			allow := func(path string) bool {
+				return policy.CheckCommandAllowed(allowlist, path) == nil
+			}
+			if metricsListen != "" {
+				s.settings.MetricsListen = metricsListen
+			}
+			if metricsOTLP != "" {
+				s.settings.MetricsOTLPURL = metricsOTLP
+			}
+			served := newServeMetrics()
+			stopMetrics, err := s.startMetrics(cmd.Context(), served)
+			if err != nil {
+				return err
+			}
+			defer stopMetrics()
+			server := mcp.NewServer(version.CLIName, version.CLIVersion, doc, allow, s.mcpExecutor(served))
+			if err := server.Serve(cmd.Context(), cmd.InOrStdin(), cmd.OutOrStdout()); err != nil {
+				return clierr.Wrap(clierr.CodeInternal, "serve mcp", err)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&metricsListen, "metrics-listen", "", "Serve Prometheus metrics at /metrics on this address (host:port)")
+	cmd.Flags().StringVar(&metricsOTLP, "metrics-otlp-endpoint", "", "Push metrics to this OTLP/HTTP metrics endpoint")
+	return cmd
+}
+
+// mcpExecutor runs each tool call as an isolated CLI invocation so every call
+// gets the same envelope, cache, and policy handling as the shell interface.
+// Config, command allowlist, and --notify-url from the mcp invocation are
+// forwarded. Tool
+// calls log only to the mcp log file, if any, since their stderr is returned
+// to the client. Each call is recorded in served.
+func (s *runtimeState) mcpExecutor(served *serveMetrics) mcp.Executor {
+	forwarded := []string{"--json"}
+	if s.flags.ConfigPath != "" {
+		forwarded = append(forwarded, "--config="+s.flags.ConfigPath)
+	}
+	if s.flags.EnableCommands != "" {
+		forwarded = append(forwarded, "--enable-commands="+s.flags.EnableCommands)
+	}
+	if s.settings.LogFile != "" {
+		forwarded = append(forwarded, "--log-file="+s.settings.LogFile, "--log-level="+s.settings.LogLevel)
+	} else {
+		forwarded = append(forwarded, "--log-level="+logx.LevelOff)
+	}
+	if s.settings.Offline {
+		forwarded = append(forwarded, "--offline")
+	}
+	if s.flags.NotifyURL != "" {
+		forwarded = append(forwarded, "--notify-url="+s.flags.NotifyURL)
+	}
+	forwarded = append(forwarded, s.fixtureForwardedFlags()...)
+	return func(_ context.Context, args []string) ([]byte, int) {
+		var stdout, stderr bytes.Buffer
+		runner := NewRunnerWithWriters(&stdout, &stderr)
+		runner.now = s.runner.now
+		started := time.Now()
+		code := runner.Run(append(append([]string{}, args...), forwarded...))
+		out := stdout.Bytes()
+		if stdout.Len() == 0 {
+			out = stderr.Bytes()
+		}
+		served.observe(args, out, code, time.Since(started))
+		return out, code
+	}
+}
@@ -0,0 +1,136 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMCPServesSchemaToolsAndRunsCommands(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	var stdout, stderr bytes.Buffer
+	state := &runtimeState{runner: NewRunnerWithWriters(&stdout, &stderr)}
+	root := state.newRootCommand()
+	state.root = root
+	root.SetOut(&stdout)
+	root.SetErr(&stderr)
+	root.SetIn(strings.NewReader(strings.Join([]string{
+		`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"providers_list","arguments":{}}}`,
+	}, "\n") + "\n"))
+	root.SetArgs([]string{"mcp", "--enable-commands", "mcp,providers list,yield deposit plan"})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("mcp failed: %v stderr=%s", err, stderr.String())
+	}
+
+	type mcpResponse struct {
+		Result struct {
+			Tools []struct {
+				Name        string `json:"name"`
+				Annotations struct {
+					ReadOnlyHint bool `json:"readOnlyHint"`
+				} `json:"annotations"`
+			} `json:"tools"`
+			Content []struct {
+				Text string `json:"text"`
+			} `json:"content"`
+			IsError bool `json:"isError"`
+		} `json:"result"`
+	}
+	var responses []mcpResponse
+	decoder := json.NewDecoder(&stdout)
+	for decoder.More() {
+		var resp mcpResponse
+		if err := decoder.Decode(&resp); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		responses = append(responses, resp)
+	}
+	if len(responses) != 2 {
+		t.Fatalf("expected 2 responses, got %d: %s", len(responses), stdout.String())
+	}
+
+	tools := responses[0].Result.Tools
+	if len(tools) != 2 || tools[0].Name != "providers_list" || tools[1].Name != "yield_deposit_plan" {
+		t.Fatalf("expected allowlisted tools only, got %+v", tools)
+	}
+	if !tools[0].Annotations.ReadOnlyHint || tools[1].Annotations.ReadOnlyHint {
+		t.Fatalf("unexpected read-only hints: %+v", tools)
+	}
+
+	call := responses[1].Result
+	if call.IsError || len(call.Content) != 1 {
+		t.Fatalf("unexpected tool call result: %+v", call)
+	}
+	var env map[string]any
+	if err := json.Unmarshal([]byte(call.Content[0].Text), &env); err != nil {
+		t.Fatalf("tool output is not a JSON envelope: %v (%s)", err, call.Content[0].Text)
+	}
+	if env["success"] != true {
+		t.Fatalf("expected successful envelope, got %+v", env)
+	}
+}
+
+func TestMCPPushesMetricsOnExit(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	var pushed []byte
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pushed, _ = io.ReadAll(r.Body)
+	}))
+	defer collector.Close()
+
+	var stdout, stderr bytes.Buffer
+	state := &runtimeState{runner: NewRunnerWithWriters(&stdout, &stderr)}
+	root := state.newRootCommand()
+	state.root = root
+	root.SetOut(&stdout)
+	root.SetErr(&stderr)
+	root.SetIn(strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"providers_list","arguments":{}}}` + "\n"))
+	root.SetArgs([]string{"mcp", "--metrics-otlp-endpoint", collector.URL + "/v1/metrics"})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("mcp failed: %v stderr=%s", err, stderr.String())
+	}
+	for _, want := range []string{`"defi_commands_total"`, `"stringValue":"providers list"`, `"stringValue":"success"`} {
+		if !strings.Contains(string(pushed), want) {
+			t.Fatalf("expected %s in pushed metrics, got %s", want, pushed)
+		}
+	}
+}
+
+func TestServeMetricsObserveEnvelopes(t *testing.T) {
+	m := newServeMetrics()
+	m.observe([]string{"lend", "markets"}, []byte(`{"success":true,"data":[],"error":null,"meta":{"command":"lend markets","providers":[{"name":"aave","status":"ok","latency_ms":120}],"cache":{"status":"write"}}}`), 0, 200*time.Millisecond)
+	m.observe([]string{"lend", "markets"}, []byte(`{"success":true,"data":[],"error":null,"meta":{"command":"lend markets","cache":{"status":"hit","stale":true}}}`), 0, time.Millisecond)
+	m.observe([]string{"swap", "submit", "--action-id", "act_1"}, []byte(`{"success":false,"error":{"code":13,"type":"action_timeout","message":"timed out"},"meta":{"command":"swap submit","cache":{"status":"bypass"}}}`), 13, time.Second)
+	m.observe([]string{"bridge", "submit"}, []byte(`{"success":true,"data":{"action_id":"act_2","status":"completed"},"error":null,"meta":{"command":"bridge submit","cache":{"status":"bypass"}}}`), 0, time.Second)
+	m.observe([]string{"lend", "--help"}, []byte("Usage: defi lend"), 0, time.Millisecond)
+
+	var out bytes.Buffer
+	if err := m.registry.WritePrometheus(&out); err != nil {
+		t.Fatalf("write metrics: %v", err)
+	}
+	for _, want := range []string{
+		`defi_commands_total{command="lend markets",outcome="success"} 2`,
+		`defi_commands_total{command="lend",outcome="success"} 1`,
+		`defi_command_errors_total{command="swap submit",type="action_timeout",code="13"} 1`,
+		`defi_provider_calls_total{provider="aave",status="ok"} 1`,
+		`defi_provider_latency_seconds_bucket{provider="aave",le="0.25"} 1`,
+		`defi_cache_lookups_total{status="stale"} 1`,
+		`defi_execution_actions_total{command="swap submit",status="failed"} 1`,
+		`defi_execution_actions_total{command="bridge submit",status="completed"} 1`,
+		"defi_cache_hit_ratio 0.5",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("expected %q in metrics:\n%s", want, out.String())
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/logx"
+	"github.com/ggonzalez94/defi-cli/internal/metrics"
+	"github.com/ggonzalez94/defi-cli/internal/model"
+)
+
+// serveMetrics aggregates the envelopes of every command run by a long-lived
+// mode (mcp) into counters and histograms.
+type serveMetrics struct {
+	registry  *metrics.Registry
+	commands  *metrics.Counter
+	errors    *metrics.Counter
+	duration  *metrics.Histogram
+	providers *metrics.Counter
+	latency   *metrics.Histogram
+	cache     *metrics.Counter
+	actions   *metrics.Counter
+}
+
+func newServeMetrics() *serveMetrics {
+	r := metrics.NewRegistry()
+	m := &serveMetrics{
+		registry:  r,
+		commands:  r.Counter("defi_commands_total", "Commands run, by command and outcome.", "command", "outcome"),
+		errors:    r.Counter("defi_command_errors_total", "Failed commands, by command, error type, and exit code.", "command", "type", "code"),
+		duration:  r.Histogram("defi_command_duration_seconds", "Command wall time.", metrics.DefaultBuckets, "command"),
+		providers: r.Counter("defi_provider_calls_total", "Provider calls reported in meta.providers, by provider and status.", "provider", "status"),
+		latency:   r.Histogram("defi_provider_latency_seconds", "Provider call latency reported in meta.providers.", metrics.DefaultBuckets, "provider"),
+		cache:     r.Counter("defi_cache_lookups_total", "Cache outcomes reported in meta.cache, by status.", "status"),
+		actions:   r.Counter("defi_execution_actions_total", "Execution outcomes of submit and resume commands, by command and action status.", "command", "status"),
+	}
+	r.GaugeFunc("defi_cache_hit_ratio", "Share of cacheable lookups served from cache, stale hits included.", m.cacheHitRatio)
+	return m
+}
+
+// observe records one command from its envelope. Output that is not an
+// envelope (help text, for example) only counts toward the command totals.
+func (m *serveMetrics) observe(args []string, out []byte, code int, elapsed time.Duration) {
+	var env struct {
+		Success bool               `json:"success"`
+		Data    json.RawMessage    `json:"data"`
+		Error   *model.ErrorBody   `json:"error"`
+		Meta    model.EnvelopeMeta `json:"meta"`
+	}
+	if err := json.Unmarshal(out, &env); err != nil || env.Meta.Command == "" {
+		env.Meta.Command = strings.Join(commandWords(args), " ")
+		env.Success = code == 0
+	}
+	command := env.Meta.Command
+
+	outcome := "success"
+	if !env.Success {
+		outcome = "error"
+		errType := "unknown"
+		if env.Error != nil {
+			errType = env.Error.Type
+		}
+		m.errors.Inc(command, errType, strconv.Itoa(code))
+	}
+	m.commands.Inc(command, outcome)
+	m.duration.Observe(elapsed.Seconds(), command)
+
+	for _, p := range env.Meta.Providers {
+		m.providers.Inc(p.Name, p.Status)
+		m.latency.Observe(float64(p.LatencyMS)/1000, p.Name)
+	}
+	if status := env.Meta.Cache.Status; status != "" {
+		if status == "hit" && env.Meta.Cache.Stale {
+			status = "stale"
+		}
+		m.cache.Inc(status)
+	}
+	if isExecutionCommand(command) {
+		status := "failed"
+		if env.Success {
+			var action struct {
+				Status string `json:"status"`
+			}
+			if json.Unmarshal(env.Data, &action) == nil && action.Status != "" {
+				status = action.Status
+			}
+		}
+		m.actions.Inc(command, status)
+	}
+}
+
+func (m *serveMetrics) cacheHitRatio() float64 {
+	hits := m.cache.Value("hit") + m.cache.Value("stale")
+	total := hits + m.cache.Value("write") + m.cache.Value("miss")
+	if total == 0 {
+		return 0
+	}
+	return hits / total
+}
+
+// commandWords returns the leading non-flag arguments, the command path of a
+// tool call.
+func commandWords(args []string) []string {
+	var words []string
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "-") {
+			break
+		}
+		words = append(words, arg)
+	}
+	return words
+}
+
+func isExecutionCommand(command string) bool {
+	return strings.HasSuffix(command, " submit") || command == "actions resume"
+}
+
+// startMetrics serves /metrics on settings.MetricsListen and pushes to
+// settings.MetricsOTLPURL until ctx ends. The returned stop function sends a
+// final push and shuts the listener down. Both are no-ops when neither is
+// configured.
+func (s *runtimeState) startMetrics(ctx context.Context, m *serveMetrics) (func(), error) {
+	var stops []func()
+	if addr := s.settings.MetricsListen; addr != "" {
+		listener, err := net.Listen("tcp", addr)
+		if err != nil {
+			return nil, clierr.Wrap(clierr.CodeUsage, "listen for metrics", err)
+		}
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", m.registry.Handler())
+		server := &http.Server{Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+		go func() {
+			if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				logx.L().Warn("metrics listener stopped", "error", err)
+			}
+		}()
+		logx.L().Info("serving metrics", "addr", listener.Addr().String())
+		stops = append(stops, func() {
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+			_ = server.Shutdown(shutdownCtx)
+		})
+	}
+	if endpoint := s.settings.MetricsOTLPURL; endpoint != "" && !s.settings.Offline {
+		client := &http.Client{Timeout: s.settings.Timeout}
+		push := func() {
+			pushCtx, cancel := context.WithTimeout(context.Background(), s.settings.Timeout)
+			defer cancel()
+			if err := m.registry.PushOTLP(pushCtx, client, endpoint, s.settings.MetricsHeaders); err != nil {
+				logx.L().Warn("metrics push failed", "endpoint", logx.SanitizeURL(endpoint), "error", err)
+			}
+		}
+		done := make(chan struct{})
+		stopped := make(chan struct{})
+		go func() {
+			defer close(stopped)
+			interval := s.settings.MetricsInterval
+			if interval <= 0 {
+				interval = time.Minute
+			}
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ticker.C:
+					push()
+				case <-done:
+					return
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+		stops = append(stops, func() {
+			close(done)
+			<-stopped
+			push()
+		})
+	}
+	return func() {
+		for _, stop := range stops {
+			stop()
+		}
+	}, nil
+}
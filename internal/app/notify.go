@@ -0,0 +1,120 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/ggonzalez94/defi-cli/internal/config"
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/execution"
+	"github.com/ggonzalez94/defi-cli/internal/logx"
+	"github.com/ggonzalez94/defi-cli/internal/model"
+	"github.com/ggonzalez94/defi-cli/internal/notify"
+)
+
+// notifyWarnings collects webhook delivery failures for the envelope. Saves
+// can happen on executor goroutines, hence the lock.
+type notifyWarnings struct {
+	mu       sync.Mutex
+	messages []string
+}
+
+func (w *notifyWarnings) add(message string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.messages = append(w.messages, message)
+}
+
+func (w *notifyWarnings) list() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return slices.Clone(w.messages)
+}
+
+// actionWebhooks returns the notify.webhooks entries plus --notify-url
+// (signed with DEFI_NOTIFY_SECRET).
+func (s *runtimeState) actionWebhooks() ([]config.Webhook, error) {
+	hooks := slices.Clone(s.settings.Webhooks)
+	if raw := strings.TrimSpace(s.settings.NotifyURL); raw != "" {
+		parsed, err := url.Parse(raw)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return nil, clierr.New(clierr.CodeUsage, "--notify-url must be an http(s) URL")
+		}
+		hooks = append(hooks, config.Webhook{URL: raw, Secret: s.settings.NotifySecret})
+	}
+	return hooks, nil
+}
+
+// watchActionTransitions posts every action status change saved to store to
+// the configured webhooks. Deliveries are skipped with --offline.
+func (s *runtimeState) watchActionTransitions(store *execution.Store) error {
+	hooks, err := s.actionWebhooks()
+	if err != nil || len(hooks) == 0 || s.settings.Offline {
+		return err
+	}
+	for _, hook := range hooks {
+		logx.RegisterSecret(hook.Secret)
+	}
+	store.OnTransition(func(previous execution.ActionStatus, action execution.Action) {
+		s.postActionTransition(hooks, previous, action)
+	})
+	return nil
+}
+
+// postActionTransition sends the action, in the envelope actions show would
+// print, to each webhook subscribed to its new status. A failed delivery is
+// reported as a warning and never fails the command.
+func (s *runtimeState) postActionTransition(hooks []config.Webhook, previous execution.ActionStatus, action execution.Action) {
+	now := s.runner.now().UTC()
+	body, err := json.Marshal(model.Envelope{
+		Version: model.EnvelopeVersion,
+		Success: true,
+		Data:    action,
+		Meta: model.EnvelopeMeta{
+			RequestID: newRequestID(),
+			Timestamp: now,
+			Command:   "actions show",
+			Cache:     cacheMetaBypass(),
+		},
+	})
+	if err != nil {
+		s.notifyWarnings.add(fmt.Sprintf("webhook not sent for %s: %v", action.ActionID, err))
+		return
+	}
+	status := string(action.Status)
+	// Deliveries block the save that triggered them, so they get their own
+	// short timeout rather than the command's --timeout.
+	timeout := notify.Timeout
+	if s.settings.Timeout > 0 && s.settings.Timeout < timeout {
+		timeout = s.settings.Timeout
+	}
+	client := &http.Client{Timeout: timeout}
+	for _, hook := range hooks {
+		if len(hook.Events) > 0 && !slices.Contains(hook.Events, status) {
+			continue
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		err := notify.Post(ctx, client, notify.Delivery{
+			URL:            hook.URL,
+			Secret:         hook.Secret,
+			Event:          "action." + status,
+			PreviousStatus: string(previous),
+			Body:           body,
+			At:             now,
+		})
+		cancel()
+		if err != nil {
+			// Transport errors quote the URL, which may carry a token.
+			target := logx.SanitizeURL(hook.URL)
+			message := strings.ReplaceAll(err.Error(), hook.URL, target)
+			logx.L().Warn("webhook delivery failed", "url", target, "action_id", action.ActionID, "status", status, "error", message)
+			s.notifyWarnings.add(fmt.Sprintf("webhook %s not notified of %s %s: %s", target, action.ActionID, status, message))
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package app
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ggonzalez94/defi-cli/internal/config"
+	"github.com/ggonzalez94/defi-cli/internal/execution"
+)
+
+type webhookRequest struct {
+	path   string
+	header http.Header
+	body   []byte
+}
+
+func TestActionTransitionsPostSignedWebhooks(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		received []webhookRequest
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		received = append(received, webhookRequest{path: r.URL.Path, header: r.Header.Clone(), body: body})
+		mu.Unlock()
+		if r.URL.Path == "/down" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	store, err := execution.OpenStore(filepath.Join(dir, "actions.db"), filepath.Join(dir, "actions.lock"))
+	if err != nil {
+		t.Fatalf("open action store: %v", err)
+	}
+	defer store.Close()
+	state := &runtimeState{
+		runner: NewRunnerWithWriters(io.Discard, io.Discard),
+		settings: config.Settings{
+			Timeout: 5 * time.Second,
+			Webhooks: []config.Webhook{
+				{URL: server.URL + "/done", Secret: "hook-secret", Events: []string{"completed", "failed"}},
+				{URL: server.URL + "/down", Events: []string{"failed"}},
+			},
+			NotifyURL: server.URL + "/all",
+		},
+	}
+	if err := state.watchActionTransitions(store); err != nil {
+		t.Fatalf("watchActionTransitions failed: %v", err)
+	}
+
+	action := execution.NewAction("act_notify", "swap", "eip155:8453", execution.Constraints{})
+	for _, status := range []execution.ActionStatus{execution.ActionStatusPlanned, execution.ActionStatusPlanned, execution.ActionStatusRunning, execution.ActionStatusCompleted} {
+		action.Status = status
+		if err := store.Save(action); err != nil {
+			t.Fatalf("save action: %v", err)
+		}
+	}
+
+	var paths, events []string
+	for _, req := range received {
+		paths = append(paths, req.path)
+		events = append(events, req.header.Get("X-Defi-Event"))
+	}
+	if got := strings.Join(paths, ","); got != "/all,/all,/done,/all" {
+		t.Fatalf("unexpected deliveries %s (events %v)", got, events)
+	}
+	if got := strings.Join(events, ","); got != "action.planned,action.running,action.completed,action.completed" {
+		t.Fatalf("unexpected events %s", got)
+	}
+	if received[0].header.Get("X-Defi-Signature") != "" || received[0].header.Get("X-Defi-Previous-Status") != "" {
+		t.Fatalf("expected an unsigned first delivery without a previous status, got %v", received[0].header)
+	}
+
+	done := received[2]
+	if done.header.Get("X-Defi-Previous-Status") != "running" {
+		t.Fatalf("unexpected previous status %q", done.header.Get("X-Defi-Previous-Status"))
+	}
+	mac := hmac.New(sha256.New, []byte("hook-secret"))
+	mac.Write([]byte(done.header.Get("X-Defi-Timestamp") + ".action.completed.running."))
+	mac.Write(done.body)
+	if want := "sha256=" + hex.EncodeToString(mac.Sum(nil)); done.header.Get("X-Defi-Signature") != want {
+		t.Fatalf("unexpected signature %q, want %q", done.header.Get("X-Defi-Signature"), want)
+	}
+	var env struct {
+		Success bool             `json:"success"`
+		Data    execution.Action `json:"data"`
+		Meta    struct {
+			Command string `json:"command"`
+		} `json:"meta"`
+	}
+	if err := json.Unmarshal(done.body, &env); err != nil {
+		t.Fatalf("decode webhook body: %v", err)
+	}
+	if !env.Success || env.Meta.Command != "actions show" || env.Data.ActionID != "act_notify" || env.Data.Status != execution.ActionStatusCompleted {
+		t.Fatalf("unexpected webhook envelope: %+v", env)
+	}
+
+	action.ActionID = "act_notify_failed"
+	action.Status = execution.ActionStatusFailed
+	if err := store.Save(action); err != nil {
+		t.Fatalf("save failed action: %v", err)
+	}
+	warnings := state.notifyWarnings.list()
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "status 503") {
+		t.Fatalf("expected one delivery warning, got %v", warnings)
+	}
+}
+
+func TestNotifyURLMustBeHTTP(t *testing.T) {
+	state := &runtimeState{settings: config.Settings{NotifyURL: "ftp://example.com/hook"}}
+	if _, err := state.actionWebhooks(); err == nil {
+		t.Fatal("expected a non-http --notify-url to be rejected")
+	}
+}
@@ -0,0 +1,19 @@
+package app
+
+import (
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+)
+
+// offlineRunError reports a failed --offline run as offline_unavailable when
+// the command needed the network: its providers were unreachable by design,
+// and no cached response within the stale budget could stand in.
+func (s *runtimeState) offlineRunError(err error) error {
+	if err == nil || !s.settings.Offline {
+		return err
+	}
+	cErr, ok := clierr.As(err)
+	if !ok || (cErr.Code != clierr.CodeUnavailable && cErr.Code != clierr.CodeStale) {
+		return err
+	}
+	return clierr.Wrap(clierr.CodeOffline, "offline and no usable cached response", err).WithDetails(cErr.Details)
+}
@@ -0,0 +1,65 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+)
+
+func TestOfflineServesCacheAndNeverCallsProviders(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":"0","message":"No transactions found","result":[]}`))
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	t.Setenv("DEFI_CACHE_PATH", filepath.Join(dir, "cache.db"))
+	t.Setenv("DEFI_CACHE_LOCK_PATH", filepath.Join(dir, "cache.lock"))
+	t.Setenv("DEFI_ETHERSCAN_API_KEY", "test-key")
+	t.Setenv("DEFI_ETHERSCAN_BASE_URL", srv.URL)
+
+	run := func(args ...string) (int, string, string) {
+		var stdout, stderr bytes.Buffer
+		code := NewRunnerWithWriters(&stdout, &stderr).Run(args)
+		return code, stdout.String(), stderr.String()
+	}
+	const address = "0x000000000000000000000000000000000000dEaD"
+
+	if code, _, stderr := run("activity", "--address", address, "--chain", "base"); code != 0 {
+		t.Fatalf("online run failed: code=%d stderr=%s", code, stderr)
+	}
+	online := hits.Load()
+
+	code, stdout, stderr := run("activity", "--address", address, "--chain", "base", "--offline")
+	if code != 0 {
+		t.Fatalf("offline cache hit failed: code=%d stderr=%s", code, stderr)
+	}
+	var env struct {
+		Meta struct {
+			Cache struct {
+				Status string `json:"status"`
+			} `json:"cache"`
+		} `json:"meta"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &env); err != nil || env.Meta.Cache.Status != "hit" {
+		t.Fatalf("expected a cached response, got %s (%v)", stdout, err)
+	}
+
+	code, _, stderr = run("activity", "--address", address, "--chain", "base", "--limit", "5", "--offline")
+	if code != int(clierr.CodeOffline) || !strings.Contains(stderr, `"offline_unavailable"`) {
+		t.Fatalf("expected offline_unavailable for an uncached query, got code=%d stderr=%s", code, stderr)
+	}
+	if hits.Load() != online {
+		t.Fatalf("expected no provider requests while offline, got %d more", hits.Load()-online)
+	}
+}
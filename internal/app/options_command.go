@@ -0,0 +1,267 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/model"
+	"github.com/ggonzalez94/defi-cli/internal/schema"
+	"github.com/spf13/cobra"
+)
+
+func (s *runtimeState) newOptionsCommand() *cobra.Command {
+	root := &cobra.Command{Use: "options", Short: "Option chains and implied volatility"}
+
+	var chainsUnderlying, chainsProvidersArg, chainsExpiry, chainsType string
+	chainsCmd := &cobra.Command{
+		Use:   "chains",
+		Short: "List option strikes and expiries with mark price, IV, and greeks",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			optionType := strings.ToLower(strings.TrimSpace(chainsType))
+			if optionType != "" && optionType != "call" && optionType != "put" {
+				return clierr.New(clierr.CodeUsage, "--type must be call or put")
+			}
+			return s.runOptionsCommand(cmd, chainsUnderlying, chainsProvidersArg, chainsExpiry, optionType, func(contracts []model.OptionContract) any {
+				sortOptionContracts(contracts)
+				return contracts
+			})
+		},
+	}
+	chainsCmd.Flags().StringVar(&chainsUnderlying, "underlying", "", "Underlying asset symbol (e.g. ETH, BTC)")
+	chainsCmd.Flags().StringVar(&chainsProvidersArg, "providers", "", "Filter by provider names (aevo)")
+	chainsCmd.Flags().StringVar(&chainsExpiry, "expiry", "", "Only contracts expiring on this UTC date (YYYY-MM-DD)")
+	chainsCmd.Flags().StringVar(&chainsType, "type", "", "Only calls or puts (call|put)")
+	_ = chainsCmd.MarkFlagRequired("underlying")
+	chainsResponse := schema.SchemaFromType([]model.OptionContract{})
+	_ = schema.SetCommandMetadata(chainsCmd, schema.CommandMetadata{Response: &chainsResponse})
+	root.AddCommand(chainsCmd)
+
+	var ivUnderlying, ivProvidersArg, ivExpiry string
+	ivCmd := &cobra.Command{
+		Use:   "iv",
+		Short: "Implied volatility surface by expiry and strike",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return s.runOptionsCommand(cmd, ivUnderlying, ivProvidersArg, ivExpiry, "", func(contracts []model.OptionContract) any {
+				return buildOptionIVSurfaces(contracts)
+			})
+		},
+	}
+	ivCmd.Flags().StringVar(&ivUnderlying, "underlying", "", "Underlying asset symbol (e.g. ETH, BTC)")
+	ivCmd.Flags().StringVar(&ivProvidersArg, "providers", "", "Filter by provider names (aevo)")
+	ivCmd.Flags().StringVar(&ivExpiry, "expiry", "", "Only the smile for this UTC expiry date (YYYY-MM-DD)")
+	_ = ivCmd.MarkFlagRequired("underlying")
+	ivResponse := schema.SchemaFromType([]model.OptionIVSurface{})
+	_ = schema.SetCommandMetadata(ivCmd, schema.CommandMetadata{Response: &ivResponse})
+	root.AddCommand(ivCmd)
+
+	return root
+}
+
+// runOptionsCommand fetches the option chain for underlying from the selected
+// providers, applies the expiry/type filters and protocol policy, and hands
+// the contracts to shape.
+func (s *runtimeState) runOptionsCommand(cmd *cobra.Command, underlying, providersArg, expiry, optionType string, shape func([]model.OptionContract) any) error {
+	underlying = strings.ToUpper(strings.TrimSpace(underlying))
+	if underlying == "" {
+		return clierr.New(clierr.CodeUsage, "--underlying is required")
+	}
+	expiry = strings.TrimSpace(expiry)
+	if expiry != "" {
+		if _, err := time.Parse("2006-01-02", expiry); err != nil {
+			return clierr.New(clierr.CodeUsage, "--expiry must be a date in YYYY-MM-DD format")
+		}
+	}
+	selected, err := s.selectOptionsProviders(splitCSV(providersArg))
+	if err != nil {
+		return err
+	}
+
+	key := cacheKey(trimRootPath(cmd.CommandPath()), map[string]any{
+		"underlying":      underlying,
+		"providers":       selected,
+		"expiry":          expiry,
+		"type":            optionType,
+		"allow_protocols": s.settings.AllowProtocols,
+		"deny_protocols":  s.settings.DenyProtocols,
+	})
+	return s.runCachedCommand(trimRootPath(cmd.CommandPath()), key, 30*time.Second, func(ctx context.Context) (any, []model.ProviderStatus, []string, bool, error) {
+		statuses := make([]model.ProviderStatus, 0, len(selected))
+		warnings := []string{}
+		combined := make([]model.OptionContract, 0)
+		partial := false
+		var firstErr error
+
+		for _, name := range selected {
+			provider := s.optionsProviders[name]
+			start := time.Now()
+			contracts, providerErr := provider.OptionChain(ctx, underlying)
+			statuses = append(statuses, model.ProviderStatus{Name: provider.Info().Name, Status: statusFromErr(providerErr), LatencyMS: time.Since(start).Milliseconds()})
+			if providerErr != nil {
+				partial = true
+				warnings = append(warnings, fmt.Sprintf("provider %s failed: %v", provider.Info().Name, providerErr))
+				if firstErr == nil {
+					firstErr = providerErr
+				}
+				continue
+			}
+			combined = append(combined, contracts...)
+		}
+		if len(combined) == 0 && firstErr != nil {
+			return nil, statuses, warnings, partial, firstErr
+		}
+
+		rules := s.protocolRules()
+		out := make([]model.OptionContract, 0, len(combined))
+		excluded := 0
+		for _, contract := range combined {
+			if expiry != "" && !strings.HasPrefix(contract.Expiry, expiry) {
+				continue
+			}
+			if optionType != "" && contract.OptionType != optionType {
+				continue
+			}
+			if rules.Check(contract.Provider) != nil {
+				excluded++
+				continue
+			}
+			out = append(out, contract)
+		}
+		if excluded > 0 {
+			warnings = append(warnings, fmt.Sprintf("%d option contracts excluded by protocol policy", excluded))
+		}
+		if len(out) == 0 {
+			return nil, statuses, warnings, partial, clierr.New(clierr.CodeUnavailable, fmt.Sprintf("no %s option contracts match the requested filters", underlying))
+		}
+		return shape(out), statuses, warnings, partial, nil
+	})
+}
+
+// selectOptionsProviders validates an explicit provider filter, or returns
+// every configured options provider in name order.
+func (s *runtimeState) selectOptionsProviders(filter []string) ([]string, error) {
+	if len(filter) == 0 {
+		names := make([]string, 0, len(s.optionsProviders))
+		for name := range s.optionsProviders {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return names, nil
+	}
+	out := make([]string, 0, len(filter))
+	seen := map[string]struct{}{}
+	for _, name := range filter {
+		name = strings.ToLower(name)
+		if _, ok := s.optionsProviders[name]; !ok {
+			return nil, clierr.New(clierr.CodeUnsupported, fmt.Sprintf("unsupported options provider %q (aevo)", name))
+		}
+		if err := s.protocolRules().Check(name); err != nil {
+			return nil, err
+		}
+		if _, dup := seen[name]; dup {
+			continue
+		}
+		seen[name] = struct{}{}
+		out = append(out, name)
+	}
+	return out, nil
+}
+
+func sortOptionContracts(contracts []model.OptionContract) {
+	sort.SliceStable(contracts, func(i, j int) bool {
+		a, b := contracts[i], contracts[j]
+		if a.Provider != b.Provider {
+			return a.Provider < b.Provider
+		}
+		if a.Expiry != b.Expiry {
+			return a.Expiry < b.Expiry
+		}
+		if a.Strike != b.Strike {
+			return a.Strike < b.Strike
+		}
+		return a.OptionType < b.OptionType
+	})
+}
+
+// buildOptionIVSurfaces groups contracts into one surface per provider with a
+// smile per expiry. Contracts without a mark IV are left out of the surface.
+func buildOptionIVSurfaces(contracts []model.OptionContract) []model.OptionIVSurface {
+	sortOptionContracts(contracts)
+	out := []model.OptionIVSurface{}
+	for _, contract := range contracts {
+		if contract.MarkIV <= 0 {
+			continue
+		}
+		if len(out) == 0 || out[len(out)-1].Provider != contract.Provider {
+			out = append(out, model.OptionIVSurface{
+				Provider:   contract.Provider,
+				Underlying: contract.Underlying,
+				Expiries:   []model.OptionIVSmile{},
+				FetchedAt:  contract.FetchedAt,
+			})
+		}
+		surface := &out[len(out)-1]
+		if surface.IndexPrice == 0 {
+			surface.IndexPrice = contract.IndexPrice
+		}
+		if len(surface.Expiries) == 0 || surface.Expiries[len(surface.Expiries)-1].Expiry != contract.Expiry {
+			surface.Expiries = append(surface.Expiries, model.OptionIVSmile{
+				Expiry:       contract.Expiry,
+				DaysToExpiry: contract.DaysToExpiry,
+				Points:       []model.OptionIVPoint{},
+			})
+		}
+		smile := &surface.Expiries[len(surface.Expiries)-1]
+		if smile.ForwardPrice == 0 {
+			smile.ForwardPrice = contract.ForwardPrice
+		}
+		if len(smile.Points) == 0 || smile.Points[len(smile.Points)-1].Strike != contract.Strike {
+			smile.Points = append(smile.Points, model.OptionIVPoint{Strike: contract.Strike})
+		}
+		point := &smile.Points[len(smile.Points)-1]
+		if contract.OptionType == "call" {
+			point.CallIV = contract.MarkIV
+		} else {
+			point.PutIV = contract.MarkIV
+		}
+	}
+
+	for i := range out {
+		for j := range out[i].Expiries {
+			smile := &out[i].Expiries[j]
+			reference := smile.ForwardPrice
+			if reference <= 0 {
+				reference = out[i].IndexPrice
+			}
+			bestDistance := math.Inf(1)
+			for k := range smile.Points {
+				point := &smile.Points[k]
+				if reference <= 0 {
+					continue
+				}
+				point.Moneyness = point.Strike / reference
+				if distance := math.Abs(point.Strike - reference); distance < bestDistance {
+					bestDistance = distance
+					smile.ATMStrike = point.Strike
+					smile.ATMIV = averageOptionIV(point.CallIV, point.PutIV)
+				}
+			}
+		}
+	}
+	return out
+}
+
+func averageOptionIV(callIV, putIV float64) float64 {
+	switch {
+	case callIV > 0 && putIV > 0:
+		return (callIV + putIV) / 2
+	case callIV > 0:
+		return callIV
+	default:
+		return putIV
+	}
+}
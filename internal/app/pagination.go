@@ -0,0 +1,132 @@
+package app
+
+import (
+	"encoding/base64"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/spf13/cobra"
+)
+
+// maxPagedItems caps the result set a paged listing walks when --limit is
+// not given, and bounds --page-size.
+const maxPagedItems = 500
+
+// pageFlags are the --cursor/--page-size flags of a list command.
+type pageFlags struct {
+	cursor   string
+	pageSize int
+}
+
+func (f *pageFlags) register(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&f.cursor, "cursor", "", "Continue a paged listing from meta.next_cursor")
+	cmd.Flags().IntVar(&f.pageSize, "page-size", 0, fmt.Sprintf("Return results in pages of this size (max %d); meta.next_cursor fetches the next page", maxPagedItems))
+}
+
+func (f pageFlags) paging() bool {
+	return f.pageSize != 0 || strings.TrimSpace(f.cursor) != ""
+}
+
+// limit returns the result cap of the underlying query. Paging without an
+// explicit --limit walks up to maxPagedItems results instead of the default
+// --limit.
+func (f pageFlags) limit(cmd *cobra.Command, limit int) int {
+	if f.paging() && !cmd.Flags().Changed("limit") {
+		return maxPagedItems
+	}
+	return limit
+}
+
+// listPage is the page of a list result to emit. Cursors are opaque to
+// callers: they carry the offset of the next item, the page size, and a
+// fingerprint of the query so a cursor only continues the listing it came
+// from.
+type listPage struct {
+	offset      int
+	size        int
+	fingerprint string
+}
+
+// startListPage validates the paging flags against queryKey (the command's
+// cache key) and installs the page emitSuccess slices the result with.
+func (s *runtimeState) startListPage(f pageFlags, queryKey string) error {
+	if !f.paging() {
+		return nil
+	}
+	if f.pageSize < 0 || f.pageSize > maxPagedItems {
+		return clierr.New(clierr.CodeUsage, fmt.Sprintf("--page-size must be between 1 and %d", maxPagedItems))
+	}
+	page := &listPage{size: f.pageSize, fingerprint: queryFingerprint(queryKey)}
+	if cursor := strings.TrimSpace(f.cursor); cursor != "" {
+		decoded, err := decodePageCursor(cursor)
+		if err != nil {
+			return err
+		}
+		if decoded.fingerprint != page.fingerprint {
+			return clierr.New(clierr.CodeUsage, "--cursor belongs to a different query; repeat the original flags with the cursor")
+		}
+		page.offset = decoded.offset
+		if page.size == 0 {
+			page.size = decoded.size
+		}
+	}
+	if page.size == 0 {
+		return clierr.New(clierr.CodeUsage, "--page-size is required to start a paged listing")
+	}
+	s.listPage = page
+	return nil
+}
+
+// apply returns the page of data, a slice, and the cursor of the following
+// page ("" on the last page). Non-slice data is returned unchanged.
+func (p *listPage) apply(data any) (any, string) {
+	value := reflect.ValueOf(data)
+	if value.Kind() != reflect.Slice {
+		return data, ""
+	}
+	total := value.Len()
+	start := min(p.offset, total)
+	end := min(start+p.size, total)
+	page := value.Slice(start, end).Interface()
+	if end >= total {
+		return page, ""
+	}
+	next := listPage{offset: end, size: p.size, fingerprint: p.fingerprint}
+	return page, next.encode()
+}
+
+func (p listPage) encode() string {
+	raw := strconv.Itoa(p.offset) + ":" + strconv.Itoa(p.size) + ":" + p.fingerprint
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodePageCursor(cursor string) (listPage, error) {
+	invalid := clierr.New(clierr.CodeUsage, "invalid --cursor; pass meta.next_cursor from the previous page")
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return listPage{}, invalid
+	}
+	parts := strings.Split(string(raw), ":")
+	if len(parts) != 3 {
+		return listPage{}, invalid
+	}
+	offset, err := strconv.Atoi(parts[0])
+	if err != nil || offset < 0 {
+		return listPage{}, invalid
+	}
+	size, err := strconv.Atoi(parts[1])
+	if err != nil || size < 1 || size > maxPagedItems {
+		return listPage{}, invalid
+	}
+	return listPage{offset: offset, size: size, fingerprint: parts[2]}, nil
+}
+
+func queryFingerprint(queryKey string) string {
+	if len(queryKey) > 16 {
+		return queryKey[:16]
+	}
+	return queryKey
+}
@@ -0,0 +1,144 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/ggonzalez94/defi-cli/internal/execution"
+)
+
+func TestActionsListPagesWithCursor(t *testing.T) {
+	actionStorePath := filepath.Join(t.TempDir(), "actions.db")
+	actionLockPath := filepath.Join(t.TempDir(), "actions.lock")
+	t.Setenv("DEFI_ACTIONS_PATH", actionStorePath)
+	t.Setenv("DEFI_ACTIONS_LOCK_PATH", actionLockPath)
+
+	store, err := execution.OpenStore(actionStorePath, actionLockPath)
+	if err != nil {
+		t.Fatalf("open action store: %v", err)
+	}
+	for i := 0; i < 5; i++ {
+		action := execution.NewAction(fmt.Sprintf("act_%032d", i), "transfer", "eip155:8453", execution.Constraints{Simulate: true})
+		if err := store.Save(action); err != nil {
+			t.Fatalf("save action: %v", err)
+		}
+	}
+	_ = store.Close()
+
+	type envelope struct {
+		Data []struct {
+			ActionID string `json:"action_id"`
+		} `json:"data"`
+		Meta struct {
+			NextCursor string `json:"next_cursor"`
+		} `json:"meta"`
+	}
+	run := func(args ...string) (int, envelope, string) {
+		var stdout, stderr bytes.Buffer
+		code := NewRunnerWithWriters(&stdout, &stderr).Run(append([]string{"actions", "list"}, args...))
+		var env envelope
+		if code == 0 {
+			if err := json.Unmarshal(stdout.Bytes(), &env); err != nil {
+				t.Fatalf("decode %s: %v", stdout.String(), err)
+			}
+		}
+		return code, env, stderr.String()
+	}
+
+	seen := []string{}
+	code, env, stderr := run("--page-size", "2")
+	for pages := 1; ; pages++ {
+		if code != 0 {
+			t.Fatalf("page %d failed: code=%d stderr=%s", pages, code, stderr)
+		}
+		for _, item := range env.Data {
+			seen = append(seen, item.ActionID)
+		}
+		if env.Meta.NextCursor == "" {
+			if pages != 3 {
+				t.Fatalf("expected 3 pages, got %d", pages)
+			}
+			break
+		}
+		code, env, stderr = run("--cursor", env.Meta.NextCursor)
+	}
+	if len(seen) != 5 || seen[0] != fmt.Sprintf("act_%032d", 0) {
+		t.Fatalf("expected every action once in a stable order, got %v", seen)
+	}
+
+	_, first, _ := run("--page-size", "2")
+	if code, _, _ := run("--cursor", first.Meta.NextCursor, "--status", "completed"); code != 2 {
+		t.Fatalf("expected a cursor from another query to be rejected, got %d", code)
+	}
+	if code, _, _ := run("--cursor", "not-a-cursor"); code != 2 {
+		t.Fatalf("expected a malformed cursor to be rejected, got %d", code)
+	}
+	if code, _, _ := run("--page-size", "1000"); code != 2 {
+		t.Fatalf("expected an oversized page to be rejected, got %d", code)
+	}
+}
+
+func TestActionsExportAndPrune(t *testing.T) {
+	actionStorePath := filepath.Join(t.TempDir(), "actions.db")
+	actionLockPath := filepath.Join(t.TempDir(), "actions.lock")
+	t.Setenv("DEFI_ACTIONS_PATH", actionStorePath)
+	t.Setenv("DEFI_ACTIONS_LOCK_PATH", actionLockPath)
+
+	store, err := execution.OpenStore(actionStorePath, actionLockPath)
+	if err != nil {
+		t.Fatalf("open action store: %v", err)
+	}
+	for i, intent := range []string{"swap", "bridge", "lend_supply"} {
+		action := execution.NewAction(fmt.Sprintf("act_%032d", i), intent, "eip155:8453", execution.Constraints{Simulate: true})
+		if i == 2 {
+			action.UpdatedAt = "2020-01-01T00:00:00Z"
+		}
+		if err := store.Save(action); err != nil {
+			t.Fatalf("save action: %v", err)
+		}
+	}
+	_ = store.Close()
+
+	var stdout, stderr bytes.Buffer
+	if code := NewRunnerWithWriters(&stdout, &stderr).Run([]string{"actions", "export", "--format", "ndjson", "--chain", "base"}); code != 0 {
+		t.Fatalf("export failed: code=%d stderr=%s", code, stderr.String())
+	}
+	lines := bytes.Split(bytes.TrimSpace(stdout.Bytes()), []byte("\n"))
+	if len(lines) != 3 {
+		t.Fatalf("expected one line per action, got %q", stdout.String())
+	}
+	for _, line := range lines {
+		var action execution.Action
+		if err := json.Unmarshal(line, &action); err != nil || action.ActionID == "" {
+			t.Fatalf("expected a bare action per line, got %s (%v)", line, err)
+		}
+	}
+
+	stdout.Reset()
+	if code := NewRunnerWithWriters(&stdout, &stderr).Run([]string{"actions", "prune", "--older-than", "30d"}); code != 0 {
+		t.Fatalf("prune failed: code=%d stderr=%s", code, stderr.String())
+	}
+	var pruned struct {
+		Data actionsPruneResult `json:"data"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &pruned); err != nil {
+		t.Fatalf("decode prune output: %v", err)
+	}
+	if pruned.Data.Pruned != 1 || pruned.Data.ActionIDs[0] != fmt.Sprintf("act_%032d", 2) {
+		t.Fatalf("expected the stale lend action to be pruned, got %+v", pruned.Data)
+	}
+
+	stdout.Reset()
+	if code := NewRunnerWithWriters(&stdout, &stderr).Run([]string{"actions", "list", "--intent", "lend"}); code != 0 {
+		t.Fatalf("list failed: code=%d stderr=%s", code, stderr.String())
+	}
+	var listed struct {
+		Data []execution.Action `json:"data"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &listed); err != nil || len(listed.Data) != 0 {
+		t.Fatalf("expected no lend actions after pruning, got %s", stdout.String())
+	}
+}
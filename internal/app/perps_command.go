@@ -0,0 +1,192 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/model"
+	"github.com/ggonzalez94/defi-cli/internal/schema"
+	"github.com/spf13/cobra"
+)
+
+func (s *runtimeState) newPerpsCommand() *cobra.Command {
+	root := &cobra.Command{Use: "perps", Short: "Perpetual futures markets and funding rates"}
+
+	var ratesProvidersArg, ratesSymbolsArg string
+	var ratesLimit int
+	ratesCmd := &cobra.Command{
+		Use:   "rates",
+		Short: "Rank perpetual funding rates across venues",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return s.runPerpsCommand(cmd, ratesProvidersArg, ratesSymbolsArg, ratesLimit, func(markets []model.PerpMarket) any {
+				sort.SliceStable(markets, func(i, j int) bool {
+					if markets[i].FundingAPR != markets[j].FundingAPR {
+						return markets[i].FundingAPR > markets[j].FundingAPR
+					}
+					return markets[i].OpenInterestUSD > markets[j].OpenInterestUSD
+				})
+				markets = applyPerpLimit(markets, ratesLimit)
+				out := make([]model.PerpFundingRate, 0, len(markets))
+				for _, market := range markets {
+					out = append(out, model.PerpFundingRate{
+						Provider:             market.Provider,
+						Market:               market.Market,
+						BaseSymbol:           market.BaseSymbol,
+						FundingRate:          market.FundingRate,
+						FundingIntervalHours: market.FundingIntervalHours,
+						FundingAPR:           market.FundingAPR,
+						MarkPrice:            market.MarkPrice,
+						IndexPrice:           market.IndexPrice,
+						OpenInterestUSD:      market.OpenInterestUSD,
+						FetchedAt:            market.FetchedAt,
+					})
+				}
+				return out
+			})
+		},
+	}
+	ratesCmd.Flags().StringVar(&ratesProvidersArg, "providers", "", "Filter by provider names (hyperliquid,dydx)")
+	ratesCmd.Flags().StringVar(&ratesSymbolsArg, "symbols", "", "Filter by base symbol (comma-separated, e.g. BTC,ETH)")
+	ratesCmd.Flags().IntVar(&ratesLimit, "limit", 20, "Maximum rates to return (0 for all)")
+	ratesResponse := schema.SchemaFromType([]model.PerpFundingRate{})
+	_ = schema.SetCommandMetadata(ratesCmd, schema.CommandMetadata{Response: &ratesResponse})
+	root.AddCommand(ratesCmd)
+
+	var marketsProvidersArg, marketsSymbolsArg string
+	var marketsLimit int
+	marketsCmd := &cobra.Command{
+		Use:   "markets",
+		Short: "List perpetual markets with prices, funding, and open interest",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return s.runPerpsCommand(cmd, marketsProvidersArg, marketsSymbolsArg, marketsLimit, func(markets []model.PerpMarket) any {
+				sort.SliceStable(markets, func(i, j int) bool {
+					if markets[i].OpenInterestUSD != markets[j].OpenInterestUSD {
+						return markets[i].OpenInterestUSD > markets[j].OpenInterestUSD
+					}
+					return markets[i].Market < markets[j].Market
+				})
+				return applyPerpLimit(markets, marketsLimit)
+			})
+		},
+	}
+	marketsCmd.Flags().StringVar(&marketsProvidersArg, "providers", "", "Filter by provider names (hyperliquid,dydx)")
+	marketsCmd.Flags().StringVar(&marketsSymbolsArg, "symbols", "", "Filter by base symbol (comma-separated, e.g. BTC,ETH)")
+	marketsCmd.Flags().IntVar(&marketsLimit, "limit", 20, "Maximum markets to return (0 for all)")
+	marketsResponse := schema.SchemaFromType([]model.PerpMarket{})
+	_ = schema.SetCommandMetadata(marketsCmd, schema.CommandMetadata{Response: &marketsResponse})
+	root.AddCommand(marketsCmd)
+
+	return root
+}
+
+// runPerpsCommand fetches markets from the selected perps providers, applies
+// the symbol filter and protocol policy, and hands the rows to shape.
+func (s *runtimeState) runPerpsCommand(cmd *cobra.Command, providersArg, symbolsArg string, limit int, shape func([]model.PerpMarket) any) error {
+	if limit < 0 {
+		return clierr.New(clierr.CodeUsage, "--limit must be >= 0")
+	}
+	selected, err := s.selectPerpsProviders(splitCSV(providersArg))
+	if err != nil {
+		return err
+	}
+	symbols := map[string]struct{}{}
+	for _, symbol := range splitCSV(symbolsArg) {
+		symbols[strings.ToUpper(symbol)] = struct{}{}
+	}
+
+	key := cacheKey(trimRootPath(cmd.CommandPath()), map[string]any{
+		"providers":       selected,
+		"symbols":         splitCSV(strings.ToUpper(symbolsArg)),
+		"limit":           limit,
+		"allow_protocols": s.settings.AllowProtocols,
+		"deny_protocols":  s.settings.DenyProtocols,
+	})
+	return s.runCachedCommand(trimRootPath(cmd.CommandPath()), key, 30*time.Second, func(ctx context.Context) (any, []model.ProviderStatus, []string, bool, error) {
+		statuses := make([]model.ProviderStatus, 0, len(selected))
+		warnings := []string{}
+		combined := make([]model.PerpMarket, 0)
+		partial := false
+		var firstErr error
+
+		for _, name := range selected {
+			provider := s.perpsProviders[name]
+			start := time.Now()
+			markets, providerErr := provider.PerpMarkets(ctx)
+			statuses = append(statuses, model.ProviderStatus{Name: provider.Info().Name, Status: statusFromErr(providerErr), LatencyMS: time.Since(start).Milliseconds()})
+			if providerErr != nil {
+				partial = true
+				warnings = append(warnings, fmt.Sprintf("provider %s failed: %v", provider.Info().Name, providerErr))
+				if firstErr == nil {
+					firstErr = providerErr
+				}
+				continue
+			}
+			combined = append(combined, markets...)
+		}
+		if len(combined) == 0 && firstErr != nil {
+			return nil, statuses, warnings, partial, firstErr
+		}
+
+		rules := s.protocolRules()
+		out := make([]model.PerpMarket, 0, len(combined))
+		excluded := 0
+		for _, market := range combined {
+			if _, ok := symbols[strings.ToUpper(market.BaseSymbol)]; len(symbols) > 0 && !ok {
+				continue
+			}
+			if rules.Check(market.Provider) != nil {
+				excluded++
+				continue
+			}
+			out = append(out, market)
+		}
+		if excluded > 0 {
+			warnings = append(warnings, fmt.Sprintf("%d perps markets excluded by protocol policy", excluded))
+		}
+		if len(out) == 0 {
+			return nil, statuses, warnings, partial, clierr.New(clierr.CodeUnavailable, "no perpetual markets match the requested symbols")
+		}
+		return shape(out), statuses, warnings, partial, nil
+	})
+}
+
+// selectPerpsProviders validates an explicit provider filter, or returns every
+// configured perps provider in name order.
+func (s *runtimeState) selectPerpsProviders(filter []string) ([]string, error) {
+	if len(filter) == 0 {
+		names := make([]string, 0, len(s.perpsProviders))
+		for name := range s.perpsProviders {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return names, nil
+	}
+	out := make([]string, 0, len(filter))
+	seen := map[string]struct{}{}
+	for _, name := range filter {
+		name = strings.ToLower(name)
+		if _, ok := s.perpsProviders[name]; !ok {
+			return nil, clierr.New(clierr.CodeUnsupported, fmt.Sprintf("unsupported perps provider %q (hyperliquid,dydx)", name))
+		}
+		if err := s.protocolRules().Check(name); err != nil {
+			return nil, err
+		}
+		if _, dup := seen[name]; dup {
+			continue
+		}
+		seen[name] = struct{}{}
+		out = append(out, name)
+	}
+	return out, nil
+}
+
+func applyPerpLimit(markets []model.PerpMarket, limit int) []model.PerpMarket {
+	if limit > 0 && len(markets) > limit {
+		return markets[:limit]
+	}
+	return markets
+}
@@ -0,0 +1,167 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/model"
+	"github.com/ggonzalez94/defi-cli/internal/providers"
+	"github.com/ggonzalez94/defi-cli/internal/schema"
+	"github.com/spf13/cobra"
+)
+
+func (s *runtimeState) newPoolsCommand() *cobra.Command {
+	root := &cobra.Command{Use: "pools", Short: "DEX liquidity pool analytics"}
+
+	var listDex, listChain, listPair, listVersion string
+	var listLimit int
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List pools for a token pair with fee tier, TVL, 24h volume, and fee APR",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			provider, err := s.selectPoolsProvider(listDex)
+			if err != nil {
+				return err
+			}
+			chain, err := id.ParseChain(listChain)
+			if err != nil {
+				return err
+			}
+			tokenA, tokenB, err := parsePoolPair(listPair, chain)
+			if err != nil {
+				return err
+			}
+			version, err := parsePoolVersion(listVersion)
+			if err != nil {
+				return err
+			}
+			if listLimit <= 0 {
+				return clierr.New(clierr.CodeUsage, "--limit must be greater than zero")
+			}
+			req := providers.PoolsRequest{Chain: chain, TokenA: tokenA, TokenB: tokenB, Version: version, Limit: listLimit}
+			key := cacheKey(trimRootPath(cmd.CommandPath()), map[string]any{
+				"dex":     provider.Info().Name,
+				"chain":   chain.CAIP2,
+				"pair":    []string{tokenA.AssetID, tokenB.AssetID},
+				"version": version,
+				"limit":   listLimit,
+			})
+			return s.runCachedCommand(trimRootPath(cmd.CommandPath()), key, 60*time.Second, func(ctx context.Context) (any, []model.ProviderStatus, []string, bool, error) {
+				start := time.Now()
+				data, err := provider.ListPools(ctx, req)
+				statuses := []model.ProviderStatus{{Name: provider.Info().Name, Status: statusFromErr(err), LatencyMS: time.Since(start).Milliseconds()}}
+				return data, statuses, nil, false, err
+			})
+		},
+	}
+	listCmd.Flags().StringVar(&listDex, "dex", "", "DEX to query (uniswap)")
+	listCmd.Flags().StringVar(&listChain, "chain", "", "Chain identifier")
+	listCmd.Flags().StringVar(&listPair, "pair", "", "Token pair as A/B (symbol/address/CAIP-19, e.g. USDC/WETH)")
+	listCmd.Flags().StringVar(&listVersion, "version", "", "Protocol version (v3|v4); default queries every version on the chain")
+	listCmd.Flags().IntVar(&listLimit, "limit", 20, "Maximum pools to return")
+	_ = listCmd.MarkFlagRequired("dex")
+	_ = listCmd.MarkFlagRequired("chain")
+	_ = listCmd.MarkFlagRequired("pair")
+	listResponse := schema.SchemaFromType([]model.DexPool{})
+	_ = schema.SetCommandMetadata(listCmd, schema.CommandMetadata{Response: &listResponse})
+	root.AddCommand(listCmd)
+
+	var detailsDex, detailsChain, detailsPool string
+	detailsCmd := &cobra.Command{
+		Use:   "details",
+		Short: "Show one pool's fee tier, TVL, 24h volume, fee APR, and current tick/price",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			provider, err := s.selectPoolsProvider(detailsDex)
+			if err != nil {
+				return err
+			}
+			chain, err := id.ParseChain(detailsChain)
+			if err != nil {
+				return err
+			}
+			poolID := strings.ToLower(strings.TrimSpace(detailsPool))
+			if poolID == "" {
+				return clierr.New(clierr.CodeUsage, "--pool is required")
+			}
+			key := cacheKey(trimRootPath(cmd.CommandPath()), map[string]any{
+				"dex":   provider.Info().Name,
+				"chain": chain.CAIP2,
+				"pool":  poolID,
+			})
+			return s.runCachedCommand(trimRootPath(cmd.CommandPath()), key, 60*time.Second, func(ctx context.Context) (any, []model.ProviderStatus, []string, bool, error) {
+				start := time.Now()
+				data, err := provider.PoolDetails(ctx, chain, poolID)
+				statuses := []model.ProviderStatus{{Name: provider.Info().Name, Status: statusFromErr(err), LatencyMS: time.Since(start).Milliseconds()}}
+				if err != nil {
+					return nil, statuses, nil, false, err
+				}
+				return data, statuses, nil, false, nil
+			})
+		},
+	}
+	detailsCmd.Flags().StringVar(&detailsDex, "dex", "", "DEX to query (uniswap)")
+	detailsCmd.Flags().StringVar(&detailsChain, "chain", "", "Chain identifier")
+	detailsCmd.Flags().StringVar(&detailsPool, "pool", "", "Pool address (v3) or pool id (v4)")
+	_ = detailsCmd.MarkFlagRequired("dex")
+	_ = detailsCmd.MarkFlagRequired("chain")
+	_ = detailsCmd.MarkFlagRequired("pool")
+	detailsResponse := schema.SchemaFromType(model.DexPool{})
+	_ = schema.SetCommandMetadata(detailsCmd, schema.CommandMetadata{Response: &detailsResponse})
+	root.AddCommand(detailsCmd)
+
+	return root
+}
+
+func (s *runtimeState) selectPoolsProvider(name string) (providers.PoolsProvider, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return nil, clierr.New(clierr.CodeUsage, "--dex is required")
+	}
+	provider, ok := s.poolsProviders[name]
+	if !ok {
+		names := make([]string, 0, len(s.poolsProviders))
+		for known := range s.poolsProviders {
+			names = append(names, known)
+		}
+		sort.Strings(names)
+		return nil, clierr.New(clierr.CodeUnsupported, fmt.Sprintf("unsupported pools dex %q (%s)", name, strings.Join(names, ", ")))
+	}
+	if err := s.protocolRules().Check(name); err != nil {
+		return nil, err
+	}
+	return provider, nil
+}
+
+// parsePoolPair resolves a --pair value such as USDC/WETH into its two assets.
+func parsePoolPair(pair string, chain id.Chain) (id.Asset, id.Asset, error) {
+	parts := strings.Split(strings.TrimSpace(pair), "/")
+	if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" || strings.TrimSpace(parts[1]) == "" {
+		return id.Asset{}, id.Asset{}, clierr.New(clierr.CodeUsage, "--pair must be two assets separated by / (e.g. USDC/WETH)")
+	}
+	tokenA, err := id.ParseAsset(strings.TrimSpace(parts[0]), chain)
+	if err != nil {
+		return id.Asset{}, id.Asset{}, err
+	}
+	tokenB, err := id.ParseAsset(strings.TrimSpace(parts[1]), chain)
+	if err != nil {
+		return id.Asset{}, id.Asset{}, err
+	}
+	if strings.EqualFold(tokenA.AssetID, tokenB.AssetID) {
+		return id.Asset{}, id.Asset{}, clierr.New(clierr.CodeUsage, "--pair must name two different assets")
+	}
+	return tokenA, tokenB, nil
+}
+
+func parsePoolVersion(input string) (string, error) {
+	switch version := strings.ToLower(strings.TrimSpace(input)); version {
+	case "", "v3", "v4":
+		return version, nil
+	default:
+		return "", clierr.New(clierr.CodeUsage, "--version must be v3 or v4")
+	}
+}
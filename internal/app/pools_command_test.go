@@ -0,0 +1,25 @@
+package app
+
+import (
+	"testing"
+
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+)
+
+func TestParsePoolPair(t *testing.T) {
+	chain, _ := id.ParseChain("ethereum")
+	tokenA, tokenB, err := parsePoolPair(" USDC / WETH ", chain)
+	if err != nil {
+		t.Fatalf("parsePoolPair failed: %v", err)
+	}
+	if tokenA.Symbol != "USDC" || tokenB.Symbol != "WETH" {
+		t.Fatalf("unexpected pair %s/%s", tokenA.Symbol, tokenB.Symbol)
+	}
+	for _, bad := range []string{"USDC", "USDC/", "USDC/WETH/DAI", "USDC/usdc"} {
+		_, _, err := parsePoolPair(bad, chain)
+		if cErr, ok := clierr.As(err); !ok || cErr.Code != clierr.CodeUsage {
+			t.Fatalf("expected usage error for %q, got %v", bad, err)
+		}
+	}
+}
@@ -0,0 +1,283 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ggonzalez94/defi-cli/internal/clock"
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/execution"
+	execsigner "github.com/ggonzalez94/defi-cli/internal/execution/signer"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/model"
+	"github.com/ggonzalez94/defi-cli/internal/providers"
+	"github.com/ggonzalez94/defi-cli/internal/registry"
+)
+
+const (
+	postStateAttempts = 4
+	postStateDelay    = 3 * time.Second
+)
+
+// postStateTarget describes the account state affected by an action.
+type postStateTarget struct {
+	Chain        id.Chain
+	Account      common.Address
+	Assets       []id.Asset
+	PositionType providers.LendPositionType
+}
+
+// postStateSnapshot is the account state read before execution.
+type postStateSnapshot struct {
+	Balances      map[string]model.WalletBalance
+	Positions     string
+	PositionsRead bool
+}
+
+// postStateCollector re-reads balances and positions after execution. The
+// provider is polled a few times to absorb indexing lag; when it never
+// reflects the change, on-chain balances are used to verify the execution.
+type postStateCollector struct {
+	balance   func(ctx context.Context, chain id.Chain, account common.Address, asset id.Asset) (model.WalletBalance, error)
+	positions providers.LendingPositionsProvider
+	attempts  int
+	delay     time.Duration
+	now       func() time.Time
+}
+
+// newPostStateCollector resolves the assets and position provider affected by action.
+func (s *runtimeState) newPostStateCollector(action execution.Action) (postStateCollector, postStateTarget, error) {
+	chain, err := id.ParseChain(action.ChainID)
+	if err != nil {
+		return postStateCollector{}, postStateTarget{}, err
+	}
+	if !chain.IsEVM() {
+		return postStateCollector{}, postStateTarget{}, clierr.New(clierr.CodeUnsupported, "post-state reads are supported only on EVM chains")
+	}
+	if !common.IsHexAddress(action.FromAddress) {
+		return postStateCollector{}, postStateTarget{}, clierr.New(clierr.CodeUsage, "action is missing a valid from_address for post-state reads")
+	}
+	target := postStateTarget{Chain: chain, Account: common.HexToAddress(action.FromAddress)}
+
+	var assetInputs []string
+	collector := postStateCollector{
+		balance: func(ctx context.Context, chain id.Chain, account common.Address, asset id.Asset) (model.WalletBalance, error) {
+			rpcURL, err := registry.ResolveRPCURL("", chain.EVMChainID)
+			if err != nil {
+				return model.WalletBalance{}, err
+			}
+			return fetchBalance(ctx, rpcURL, chain, account, &asset)
+		},
+		attempts: postStateAttempts,
+		delay:    postStateDelay,
+		now:      clock.Now,
+	}
+	switch {
+	case action.IntentType == "swap":
+		assetInputs = []string{metadataString(action.Metadata, "token_in"), metadataString(action.Metadata, "token_out")}
+	case strings.HasPrefix(action.IntentType, "lend_"):
+		assetInputs = []string{metadataString(action.Metadata, "asset_id")}
+		switch strings.TrimPrefix(action.IntentType, "lend_") {
+		case "borrow", "repay":
+			target.PositionType = providers.LendPositionTypeBorrow
+		default:
+			target.PositionType = providers.LendPositionTypeSupply
+		}
+		if provider, ok := s.lendingProviders[normalizeLendingProvider(action.Provider)]; ok {
+			if positions, ok := provider.(providers.LendingPositionsProvider); ok {
+				collector.positions = positions
+			}
+		}
+	default:
+		return postStateCollector{}, postStateTarget{}, clierr.New(clierr.CodeUnsupported, fmt.Sprintf("post-state reads are not supported for %s actions", action.IntentType))
+	}
+	for _, input := range assetInputs {
+		if strings.TrimSpace(input) == "" {
+			continue
+		}
+		asset, err := id.ParseAsset(input, chain)
+		if err != nil {
+			return postStateCollector{}, postStateTarget{}, err
+		}
+		target.Assets = append(target.Assets, asset)
+	}
+	if len(target.Assets) == 0 {
+		return postStateCollector{}, postStateTarget{}, clierr.New(clierr.CodeUnsupported, "action metadata does not identify the affected assets")
+	}
+	return collector, target, nil
+}
+
+// executeActionWithPostState executes action and, when postState is set,
+// attaches the account state re-read after execution to it.
+func (s *runtimeState) executeActionWithPostState(action *execution.Action, txSigner execsigner.Signer, evmBackend execution.EVMSubmitBackend, opts execution.ExecuteOptions, postState bool) ([]string, error) {
+	if !postState {
+		return nil, s.executeActionWithTimeout(action, txSigner, evmBackend, opts)
+	}
+	collector, target, err := s.newPostStateCollector(*action)
+	if err != nil {
+		return nil, err
+	}
+	readTimeout := s.settings.Timeout + time.Duration(collector.attempts)*collector.delay
+	ctx, cancel := context.WithTimeout(context.Background(), readTimeout)
+	before, warnings := collector.snapshot(ctx, target)
+	cancel()
+
+	if err := s.executeActionWithTimeout(action, txSigner, evmBackend, opts); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), readTimeout)
+	defer cancel()
+	state, collectWarnings := collector.collect(ctx, target, before)
+	action.PostState = state
+	if err := s.actionStore.Save(*action); err != nil {
+		return nil, clierr.Wrap(clierr.CodeInternal, "persist action post-state", err)
+	}
+	return append(warnings, collectWarnings...), nil
+}
+
+// snapshot reads the state used as the baseline for change detection.
+func (c postStateCollector) snapshot(ctx context.Context, target postStateTarget) (postStateSnapshot, []string) {
+	balances, warnings := c.readBalances(ctx, target)
+	out := postStateSnapshot{Balances: balances}
+	if c.positions != nil {
+		positions, err := c.readPositions(ctx, target)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("post-state: read positions before execution: %v", err))
+		} else {
+			out.Positions = positionsFingerprint(positions)
+			out.PositionsRead = true
+		}
+	}
+	return out, warnings
+}
+
+// collect reads the state after execution and compares it with before.
+func (c postStateCollector) collect(ctx context.Context, target postStateTarget, before postStateSnapshot) (*execution.PostState, []string) {
+	after, warnings := c.readBalances(ctx, target)
+	state := &execution.PostState{Status: execution.PostStateUnverified}
+	onchainChanged := false
+	for _, asset := range target.Assets {
+		current, ok := after[asset.AssetID]
+		if !ok {
+			continue
+		}
+		item := execution.PostStateBalance{
+			AssetID:        current.AssetID,
+			Symbol:         current.Symbol,
+			Decimals:       current.Balance.Decimals,
+			AfterBaseUnits: current.Balance.AmountBaseUnits,
+			AfterDecimal:   current.Balance.AmountDecimal,
+		}
+		if previous, ok := before.Balances[asset.AssetID]; ok {
+			item.BeforeBaseUnits = previous.Balance.AmountBaseUnits
+			if item.BeforeBaseUnits != item.AfterBaseUnits {
+				onchainChanged = true
+			}
+		}
+		state.Balances = append(state.Balances, item)
+	}
+
+	if c.positions != nil && !before.PositionsRead {
+		warnings = append(warnings, "post-state: provider positions skipped; no baseline to detect lag against")
+	}
+	if c.positions != nil && before.PositionsRead {
+		for attempt := 1; attempt <= c.attempts; attempt++ {
+			state.Attempts = attempt
+			positions, err := c.readPositions(ctx, target)
+			if err == nil && positionsFingerprint(positions) != before.Positions {
+				state.Status = execution.PostStateProviderConfirmed
+				state.Positions = postStatePositions(positions)
+				break
+			}
+			if attempt == c.attempts || !sleepContext(ctx, c.delay) {
+				if err != nil {
+					warnings = append(warnings, fmt.Sprintf("post-state: read positions: %v", err))
+				} else {
+					warnings = append(warnings, fmt.Sprintf("post-state: provider positions unchanged after %d attempts; provider data may lag on-chain state", attempt))
+				}
+				break
+			}
+		}
+	}
+	if state.Status == execution.PostStateUnverified && onchainChanged {
+		state.Status = execution.PostStateOnchainVerified
+	}
+	if state.Status == execution.PostStateUnverified {
+		warnings = append(warnings, "post-state: no balance or position change observed yet")
+	}
+	state.FetchedAt = c.now().UTC().Format(time.RFC3339)
+	return state, warnings
+}
+
+func (c postStateCollector) readBalances(ctx context.Context, target postStateTarget) (map[string]model.WalletBalance, []string) {
+	out := make(map[string]model.WalletBalance, len(target.Assets))
+	var warnings []string
+	for _, asset := range target.Assets {
+		balance, err := c.balance(ctx, target.Chain, target.Account, asset)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("post-state: read %s balance: %v", asset.AssetID, err))
+			continue
+		}
+		out[asset.AssetID] = balance
+	}
+	return out, warnings
+}
+
+func (c postStateCollector) readPositions(ctx context.Context, target postStateTarget) ([]model.LendPosition, error) {
+	return c.positions.LendPositions(ctx, providers.LendPositionsRequest{
+		Chain:        target.Chain,
+		Account:      target.Account.Hex(),
+		Asset:        target.Assets[0],
+		PositionType: target.PositionType,
+		Limit:        20,
+	})
+}
+
+// positionsFingerprint summarizes positions so snapshots can be compared
+// without depending on provider ordering.
+func positionsFingerprint(positions []model.LendPosition) string {
+	parts := make([]string, 0, len(positions))
+	for _, item := range positions {
+		parts = append(parts, strings.Join([]string{item.PositionType, strings.ToLower(item.AssetID), item.ProviderNativeID, item.Amount.AmountBaseUnits}, "|"))
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ";")
+}
+
+func postStatePositions(positions []model.LendPosition) []execution.PostStatePosition {
+	out := make([]execution.PostStatePosition, 0, len(positions))
+	for _, item := range positions {
+		out = append(out, execution.PostStatePosition{
+			Provider:         item.Provider,
+			Protocol:         item.Protocol,
+			PositionType:     item.PositionType,
+			AssetID:          item.AssetID,
+			ProviderNativeID: item.ProviderNativeID,
+			AmountBaseUnits:  item.Amount.AmountBaseUnits,
+			AmountDecimal:    item.Amount.AmountDecimal,
+			AmountUSD:        item.AmountUSD,
+		})
+	}
+	return out
+}
+
+func metadataString(metadata map[string]any, key string) string {
+	value, _ := metadata[key].(string)
+	return strings.TrimSpace(value)
+}
+
+func sleepContext(ctx context.Context, delay time.Duration) bool {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
@@ -0,0 +1,117 @@
+package app
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ggonzalez94/defi-cli/internal/execution"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/model"
+	"github.com/ggonzalez94/defi-cli/internal/providers"
+)
+
+func newTestPostStateCollector(t *testing.T, positions *fakeLendingProvider, balances *[]string) (postStateCollector, postStateTarget) {
+	t.Helper()
+	chain, err := id.ParseChain("ethereum")
+	if err != nil {
+		t.Fatalf("parse chain: %v", err)
+	}
+	usdc, err := id.ParseAsset("USDC", chain)
+	if err != nil {
+		t.Fatalf("parse asset: %v", err)
+	}
+	collector := postStateCollector{
+		balance: func(_ context.Context, _ id.Chain, _ common.Address, asset id.Asset) (model.WalletBalance, error) {
+			next := (*balances)[0]
+			if len(*balances) > 1 {
+				*balances = (*balances)[1:]
+			}
+			return model.WalletBalance{AssetID: asset.AssetID, Symbol: asset.Symbol, Balance: model.AmountInfo{AmountBaseUnits: next, Decimals: 6}}, nil
+		},
+		positions: positions,
+		attempts:  3,
+		delay:     time.Millisecond,
+		now:       func() time.Time { return time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC) },
+	}
+	target := postStateTarget{
+		Chain:        chain,
+		Account:      common.HexToAddress("0x000000000000000000000000000000000000dEaD"),
+		Assets:       []id.Asset{usdc},
+		PositionType: providers.LendPositionTypeSupply,
+	}
+	return collector, target
+}
+
+func TestPostStateCollectorConfirmsProviderPositions(t *testing.T) {
+	provider := &fakeLendingProvider{name: "aave"}
+	balances := []string{"5000000", "4000000"}
+	collector, target := newTestPostStateCollector(t, provider, &balances)
+
+	before, warnings := collector.snapshot(context.Background(), target)
+	if len(warnings) != 0 {
+		t.Fatalf("unexpected snapshot warnings: %v", warnings)
+	}
+	provider.positions = []model.LendPosition{{Provider: "aave", PositionType: "supply", AssetID: target.Assets[0].AssetID, Amount: model.AmountInfo{AmountBaseUnits: "1000000", AmountDecimal: "1"}}}
+
+	state, warnings := collector.collect(context.Background(), target, before)
+	if len(warnings) != 0 {
+		t.Fatalf("unexpected collect warnings: %v", warnings)
+	}
+	if state.Status != execution.PostStateProviderConfirmed || state.Attempts != 1 {
+		t.Fatalf("expected provider confirmation on first attempt, got %+v", state)
+	}
+	if len(state.Positions) != 1 || state.Positions[0].AmountBaseUnits != "1000000" {
+		t.Fatalf("unexpected positions: %+v", state.Positions)
+	}
+	if len(state.Balances) != 1 || state.Balances[0].BeforeBaseUnits != "5000000" || state.Balances[0].AfterBaseUnits != "4000000" {
+		t.Fatalf("unexpected balances: %+v", state.Balances)
+	}
+}
+
+func TestPostStateCollectorFallsBackToOnchainWhenProviderLags(t *testing.T) {
+	provider := &fakeLendingProvider{name: "aave", positions: []model.LendPosition{{Provider: "aave", PositionType: "supply", AssetID: "eip155:1/erc20:0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48", Amount: model.AmountInfo{AmountBaseUnits: "1000000"}}}}
+	balances := []string{"5000000", "4000000"}
+	collector, target := newTestPostStateCollector(t, provider, &balances)
+
+	before, _ := collector.snapshot(context.Background(), target)
+	state, warnings := collector.collect(context.Background(), target, before)
+	if state.Status != execution.PostStateOnchainVerified {
+		t.Fatalf("expected onchain_verified, got %s", state.Status)
+	}
+	if state.Attempts != 3 || provider.calls != 4 {
+		t.Fatalf("expected 3 post-execution attempts after the snapshot, got attempts=%d calls=%d", state.Attempts, provider.calls)
+	}
+	if len(state.Positions) != 0 {
+		t.Fatalf("expected stale positions to be omitted, got %+v", state.Positions)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "may lag") {
+		t.Fatalf("expected provider lag warning, got %v", warnings)
+	}
+}
+
+func TestPostStateCollectorReportsUnverifiedWithoutChanges(t *testing.T) {
+	balances := []string{"5000000"}
+	collector, target := newTestPostStateCollector(t, nil, &balances)
+	collector.positions = nil
+
+	before, _ := collector.snapshot(context.Background(), target)
+	state, warnings := collector.collect(context.Background(), target, before)
+	if state.Status != execution.PostStateUnverified || state.Attempts != 0 {
+		t.Fatalf("expected unverified without provider attempts, got %+v", state)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "no balance or position change") {
+		t.Fatalf("unexpected warnings: %v", warnings)
+	}
+}
+
+func TestNewPostStateCollectorRejectsUnsupportedIntent(t *testing.T) {
+	state := &runtimeState{}
+	action := execution.NewAction("act_test", "bridge", "eip155:1", execution.Constraints{})
+	action.FromAddress = "0x000000000000000000000000000000000000dEaD"
+	if _, _, err := state.newPostStateCollector(action); err == nil {
+		t.Fatal("expected unsupported intent error")
+	}
+}
@@ -0,0 +1,89 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/execution"
+	"github.com/ggonzalez94/defi-cli/internal/pricecheck"
+	"github.com/ggonzalez94/defi-cli/internal/providers"
+)
+
+// checkSwapPriceImpact re-prices a planned swap from DefiLlama coin prices and
+// refuses it when the quoted output falls more than maxPct percent below the
+// independent estimate. The check is recorded on the action either way. A
+// non-positive maxPct disables the check.
+func (s *runtimeState) checkSwapPriceImpact(ctx context.Context, action *execution.Action, maxPct float64) error {
+	if maxPct <= 0 {
+		return nil
+	}
+	subject, err := quoteSubjectFromAction(*action)
+	if err != nil {
+		return err
+	}
+	provider, ok := s.marketProvider.(providers.TokenPriceProvider)
+	if !ok {
+		return clierr.New(clierr.CodeUnsupported, "market data provider does not support token prices")
+	}
+	inAddress, outAddress := priceCheckAddress(subject.in.Address), priceCheckAddress(subject.out.Address)
+	prices, err := provider.TokenPrices(ctx, subject.chain, []string{inAddress, outAddress})
+	if err != nil {
+		return clierr.Wrap(clierr.CodeUnavailable, "fetch independent prices for price impact check", err)
+	}
+	priceIn, okIn := prices[inAddress]
+	priceOut, okOut := prices[outAddress]
+	if !okIn || !okOut {
+		return clierr.New(clierr.CodeUnavailable, "independent prices are unavailable for this pair; set --max-price-impact-pct 0 to skip the check")
+	}
+	if subject.in.Decimals == 0 {
+		subject.in.Decimals = priceIn.Decimals
+	}
+	if subject.out.Decimals == 0 {
+		subject.out.Decimals = priceOut.Decimals
+	}
+	if subject.in.Symbol == "" {
+		subject.in.Symbol = priceIn.Symbol
+	}
+	if subject.out.Symbol == "" {
+		subject.out.Symbol = priceOut.Symbol
+	}
+	ref, err := pricecheck.USDPrices(pricecheck.SourceDefiLlama, subject.in, subject.out, subject.amountIn, priceIn.PriceUSD, priceOut.PriceUSD)
+	if err != nil {
+		return err
+	}
+
+	check := &execution.PriceCheck{
+		Source:            ref.Source,
+		InputPriceUSD:     priceIn.PriceUSD,
+		OutputPriceUSD:    priceOut.PriceUSD,
+		QuotedOut:         subject.quotedOut.String(),
+		ExpectedOut:       ref.AmountOut.String(),
+		PriceImpactPct:    -pricecheck.DeviationPct(subject.quotedOut, ref.AmountOut),
+		MaxPriceImpactPct: maxPct,
+		CheckedAt:         s.runner.now().UTC().Format(time.RFC3339),
+	}
+	check.Passed = check.PriceImpactPct <= maxPct
+	action.PriceCheck = check
+	action.Touch()
+	if err := s.actionStore.Save(*action); err != nil {
+		return clierr.Wrap(clierr.CodeInternal, "persist price check", err)
+	}
+	if !check.Passed {
+		return clierr.New(clierr.CodeActionPolicy, fmt.Sprintf("quoted output is %.2f%% below the %s estimate (limit %.2f%%); re-plan the swap or raise --max-price-impact-pct", check.PriceImpactPct, ref.Source, maxPct)).WithDetails(check)
+	}
+	return nil
+}
+
+// priceCheckAddress maps the 0xEeee native placeholder to the zero address
+// the coins API uses for native coins.
+func priceCheckAddress(address string) string {
+	address = strings.ToLower(strings.TrimSpace(address))
+	if common.IsHexAddress(address) && common.HexToAddress(address) == common.HexToAddress("0xEeeeeEeeeEeEeeEeEeEeeEEEeeeeEeeeeeeeEEeE") {
+		return strings.ToLower(common.Address{}.Hex())
+	}
+	return address
+}
@@ -0,0 +1,43 @@
+package app
+
+import (
+	"net/url"
+	"strings"
+
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/execution"
+	"github.com/ggonzalez94/defi-cli/internal/registry"
+)
+
+const ethereumMainnetChainID = "eip155:1"
+
+// resolvePrivateTxOptions maps --private-tx/--private-relay/--private-fallback
+// to executor options. Named relays only serve Ethereum mainnet; a custom
+// relay URL is trusted to serve the action's source chain.
+func resolvePrivateTxOptions(action execution.Action, enabled bool, relay string, fallback bool) (*execution.PrivateTxOptions, error) {
+	if !enabled {
+		return nil, nil
+	}
+	if action.ExecutionBackend == execution.ExecutionBackendTempo {
+		return nil, clierr.New(clierr.CodeUnsupported, "--private-tx is not supported for Tempo actions")
+	}
+	chainID := strings.TrimSpace(action.ChainID)
+	opts := &execution.PrivateTxOptions{ChainID: chainID, Fallback: fallback}
+	switch name := strings.ToLower(strings.TrimSpace(relay)); name {
+	case "", "flashbots":
+		opts.Relay, opts.RPCURL, opts.StatusURL = "flashbots", registry.FlashbotsProtectRPCURL, registry.FlashbotsProtectStatusURL
+	case "mevblocker":
+		opts.Relay, opts.RPCURL = "mevblocker", registry.MEVBlockerRPCURL
+	default:
+		parsed, err := url.Parse(strings.TrimSpace(relay))
+		if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+			return nil, clierr.New(clierr.CodeUsage, "--private-relay must be flashbots, mevblocker, or an http(s) RPC URL")
+		}
+		opts.Relay, opts.RPCURL = "custom", parsed.String()
+		return opts, nil
+	}
+	if !strings.EqualFold(chainID, ethereumMainnetChainID) {
+		return nil, clierr.New(clierr.CodeUnsupported, "private relay "+opts.Relay+" only serves Ethereum mainnet; pass --private-relay <rpc-url> for "+chainID)
+	}
+	return opts, nil
+}
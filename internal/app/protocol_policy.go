@@ -0,0 +1,58 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ggonzalez94/defi-cli/internal/execution"
+	"github.com/ggonzalez94/defi-cli/internal/model"
+	"github.com/ggonzalez94/defi-cli/internal/policy"
+)
+
+func (s *runtimeState) protocolRules() policy.ProtocolRules {
+	return policy.ProtocolRules{Allow: s.settings.AllowProtocols, Deny: s.settings.DenyProtocols}
+}
+
+// checkActionProtocolPolicy enforces protocol allow/deny rules on a planned
+// action, including the provider of each leg of a route action and of each
+// sub-action of a composite. Native transfers and approvals do not route
+// through a protocol and are always allowed.
+func (s *runtimeState) checkActionProtocolPolicy(action execution.Action) error {
+	for _, sub := range action.SubActions {
+		if err := s.checkActionProtocolPolicy(sub); err != nil {
+			return err
+		}
+	}
+	rules := s.protocolRules()
+	for _, step := range action.Steps {
+		if provider := strings.TrimSpace(step.ExpectedOutputs[execution.StepOutputLegProvider]); provider != "" {
+			if err := rules.Check(provider); err != nil {
+				return err
+			}
+		}
+	}
+	provider := strings.TrimSpace(action.Provider)
+	if provider == "" || strings.EqualFold(provider, "native") {
+		return nil
+	}
+	return rules.Check(provider)
+}
+
+// filterYieldByProtocolPolicy drops opportunities whose provider or protocol
+// is blocked and reports how many were removed.
+func (s *runtimeState) filterYieldByProtocolPolicy(items []model.YieldOpportunity) ([]model.YieldOpportunity, []string) {
+	rules := s.protocolRules()
+	if rules.Empty() {
+		return items, nil
+	}
+	out := make([]model.YieldOpportunity, 0, len(items))
+	for _, item := range items {
+		if rules.Check(item.Provider, item.Protocol) == nil {
+			out = append(out, item)
+		}
+	}
+	if removed := len(items) - len(out); removed > 0 {
+		return out, []string{fmt.Sprintf("%d opportunities excluded by protocol policy", removed)}
+	}
+	return out, nil
+}
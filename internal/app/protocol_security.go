@@ -0,0 +1,97 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/model"
+	"github.com/ggonzalez94/defi-cli/internal/providers"
+	"github.com/ggonzalez94/defi-cli/internal/providers/yieldutil"
+	"github.com/ggonzalez94/defi-cli/internal/schema"
+	"github.com/spf13/cobra"
+)
+
+func (s *runtimeState) newProtocolsSecurityCommand() *cobra.Command {
+	var protocolArg string
+	cmd := &cobra.Command{
+		Use:   "security",
+		Short: "Audit count, exploit history, and listing age for a protocol",
+		Long:  "Returns audit count and links, listing date and age, and hallmarked exploits from DefiLlama protocol metadata, merged across every listing of the protocol (by slug, name, or parent protocol).",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			protocol := strings.TrimSpace(protocolArg)
+			if protocol == "" {
+				return clierr.New(clierr.CodeUsage, "--protocol is required")
+			}
+			provider, err := s.protocolSecurityProvider()
+			if err != nil {
+				return err
+			}
+			key := cacheKey(trimRootPath(cmd.CommandPath()), map[string]any{"protocol": strings.ToLower(protocol)})
+			return s.runCachedCommand(trimRootPath(cmd.CommandPath()), key, 5*time.Minute, func(ctx context.Context) (any, []model.ProviderStatus, []string, bool, error) {
+				start := time.Now()
+				data, err := provider.ProtocolSecurity(ctx, protocol)
+				status := []model.ProviderStatus{{Name: provider.Info().Name, Status: statusFromErr(err), LatencyMS: time.Since(start).Milliseconds()}}
+				if err != nil {
+					return nil, status, nil, false, err
+				}
+				return data, status, nil, false, nil
+			})
+		},
+	}
+	cmd.Flags().StringVar(&protocolArg, "protocol", "", "Protocol name, slug, or parent (e.g. aave, morpho, lido)")
+	_ = cmd.MarkFlagRequired("protocol")
+	response := schema.SchemaFromType(model.ProtocolSecurity{})
+	_ = schema.SetCommandMetadata(cmd, schema.CommandMetadata{Response: &response})
+	return cmd
+}
+
+func (s *runtimeState) protocolSecurityProvider() (providers.ProtocolSecurityProvider, error) {
+	provider, ok := s.marketProvider.(providers.ProtocolSecurityProvider)
+	if !ok {
+		return nil, clierr.New(clierr.CodeUnsupported, "market data provider does not report protocol security")
+	}
+	return provider, nil
+}
+
+// attachProtocolSecurity sets security on items from their protocol's
+// metadata and rescores them. Lookups are best effort: without a security
+// provider items keep their scores, and failed protocols are warnings.
+func (s *runtimeState) attachProtocolSecurity(ctx context.Context, items []model.YieldOpportunity) []string {
+	provider, err := s.protocolSecurityProvider()
+	if err != nil {
+		return nil
+	}
+	warnings := []string{}
+	byProtocol := map[string]*model.YieldSecurity{}
+	for i := range items {
+		protocol := strings.ToLower(strings.TrimSpace(items[i].Protocol))
+		security, seen := byProtocol[protocol]
+		if !seen {
+			data, err := provider.ProtocolSecurity(ctx, protocol)
+			if err != nil {
+				warnings = append(warnings, fmt.Sprintf("security metadata unavailable for protocol %s: %v", protocol, err))
+			} else {
+				security = yieldSecurity(data)
+			}
+			byProtocol[protocol] = security
+		}
+		items[i].Security = security
+	}
+	yieldutil.ApplyRisk(items)
+	return warnings
+}
+
+func yieldSecurity(data model.ProtocolSecurity) *model.YieldSecurity {
+	security := &model.YieldSecurity{
+		Audits:         data.Audits,
+		ListingAgeDays: data.ListingAgeDays,
+		Exploits:       len(data.Exploits),
+	}
+	if len(data.Exploits) > 0 {
+		security.LastExploitAt = data.Exploits[len(data.Exploits)-1].Date
+	}
+	return security
+}
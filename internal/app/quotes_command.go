@@ -0,0 +1,143 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ggonzalez94/defi-cli/internal/config"
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/model"
+	"github.com/ggonzalez94/defi-cli/internal/quotes"
+	"github.com/ggonzalez94/defi-cli/internal/schema"
+	"github.com/spf13/cobra"
+)
+
+func (s *runtimeState) newQuotesCommand() *cobra.Command {
+	root := &cobra.Command{Use: "quotes", Short: "Archived quote analytics"}
+
+	var pairArg, chainArg, toChainArg, providerArg, kindArg, windowArg, fromArg, toArg string
+	var limit int
+	historyCmd := &cobra.Command{
+		Use:   "history",
+		Short: "List archived swap/bridge quotes for a pair (recorded with --archive-quotes)",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			chain, err := id.ParseChain(chainArg)
+			if err != nil {
+				return err
+			}
+			toChain := chain
+			if strings.TrimSpace(toChainArg) != "" {
+				if toChain, err = id.ParseChain(toChainArg); err != nil {
+					return err
+				}
+			}
+			fromAsset, toAsset, err := parseQuotePair(pairArg, chain, toChain)
+			if err != nil {
+				return err
+			}
+			kind := strings.ToLower(strings.TrimSpace(kindArg))
+			switch kind {
+			case "", quotes.KindSwap, quotes.KindBridge:
+			default:
+				return clierr.New(clierr.CodeUsage, "--kind must be one of: swap,bridge")
+			}
+			startTime, endTime, err := resolveYieldHistoryRange(fromArg, toArg, windowArg, s.runner.now().UTC())
+			if err != nil {
+				return err
+			}
+			if err := s.ensureQuoteStore(); err != nil {
+				return err
+			}
+			items, err := s.quoteStore.History(quotes.Query{
+				Kind:        kind,
+				Provider:    strings.ToLower(strings.TrimSpace(providerArg)),
+				ChainID:     chain.CAIP2,
+				FromAssetID: fromAsset.AssetID,
+				ToAssetID:   toAsset.AssetID,
+				Start:       startTime,
+				End:         endTime,
+				Limit:       limit,
+			})
+			if err != nil {
+				return clierr.Wrap(clierr.CodeInternal, "read quote archive", err)
+			}
+			return s.emitSuccess(trimRootPath(cmd.CommandPath()), items, nil, cacheMetaBypass(), nil, false)
+		},
+	}
+	historyCmd.Flags().StringVar(&pairArg, "pair", "", "Asset pair as FROM/TO (FROM on --chain, TO on --to-chain)")
+	historyCmd.Flags().StringVar(&chainArg, "chain", "", "Chain identifier (source chain for bridge quotes)")
+	historyCmd.Flags().StringVar(&toChainArg, "to-chain", "", "Destination chain of bridge quotes; TO resolves on this chain (defaults to --chain)")
+	historyCmd.Flags().StringVar(&providerArg, "provider", "", "Optional provider filter")
+	historyCmd.Flags().StringVar(&kindArg, "kind", "", "Optional quote kind filter (swap|bridge)")
+	historyCmd.Flags().StringVar(&windowArg, "window", "7d", "Lookback window (for example 24h,7d,30d)")
+	historyCmd.Flags().StringVar(&fromArg, "from", "", "Start time (RFC3339). Overrides --window when set")
+	historyCmd.Flags().StringVar(&toArg, "to", "", "End time (RFC3339). Defaults to now")
+	historyCmd.Flags().IntVar(&limit, "limit", 100, "Maximum archived quotes to return (most recent)")
+	_ = historyCmd.MarkFlagRequired("pair")
+	_ = historyCmd.MarkFlagRequired("chain")
+	_ = schema.SetFlagMetadata(historyCmd.Flags(), "chain", schema.FlagMetadata{Required: true, Format: "chain"})
+	_ = schema.SetFlagMetadata(historyCmd.Flags(), "to-chain", schema.FlagMetadata{Format: "chain"})
+	_ = schema.SetFlagMetadata(historyCmd.Flags(), "kind", schema.FlagMetadata{Enum: []string{quotes.KindSwap, quotes.KindBridge}})
+	historyResponse := schema.SchemaFromType([]model.ArchivedQuote{})
+	_ = schema.SetCommandMetadata(historyCmd, schema.CommandMetadata{Response: &historyResponse})
+	root.AddCommand(historyCmd)
+
+	return root
+}
+
+// parseQuotePair resolves FROM on chain and TO on toChain, the destination of
+// a bridge quote, so the pair matches the asset ids the archive recorded.
+func parseQuotePair(pairArg string, chain, toChain id.Chain) (id.Asset, id.Asset, error) {
+	parts := strings.Split(strings.TrimSpace(pairArg), "/")
+	if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" || strings.TrimSpace(parts[1]) == "" {
+		return id.Asset{}, id.Asset{}, clierr.New(clierr.CodeUsage, "--pair must be FROM/TO (for example USDC/WETH)")
+	}
+	fromAsset, err := id.ParseAsset(strings.TrimSpace(parts[0]), chain)
+	if err != nil {
+		return id.Asset{}, id.Asset{}, clierr.Wrap(clierr.CodeUsage, "resolve pair input asset", err)
+	}
+	toAsset, err := id.ParseAsset(strings.TrimSpace(parts[1]), toChain)
+	if err != nil {
+		return id.Asset{}, id.Asset{}, clierr.Wrap(clierr.CodeUsage, "resolve pair output asset", err)
+	}
+	return fromAsset, toAsset, nil
+}
+
+func (s *runtimeState) ensureQuoteStore() error {
+	if s.quoteStore != nil {
+		return nil
+	}
+	path := strings.TrimSpace(s.settings.QuotesPath)
+	lockPath := strings.TrimSpace(s.settings.QuotesLockPath)
+	if path == "" || lockPath == "" {
+		defaults, err := config.Load(config.GlobalFlags{})
+		if err != nil {
+			return clierr.Wrap(clierr.CodeInternal, "resolve default quote archive settings", err)
+		}
+		if path == "" {
+			path = defaults.QuotesPath
+		}
+		if lockPath == "" {
+			lockPath = defaults.QuotesLockPath
+		}
+	}
+	store, err := quotes.OpenStore(path, lockPath)
+	if err != nil {
+		return clierr.Wrap(clierr.CodeInternal, "open quote archive", err)
+	}
+	s.quoteStore = store
+	return nil
+}
+
+// archiveQuote persists a quote on a best-effort basis. Failures are surfaced
+// as warnings so quote commands still succeed when the archive is unavailable.
+func (s *runtimeState) archiveQuote(entry model.ArchivedQuote) []string {
+	if err := s.ensureQuoteStore(); err != nil {
+		return []string{fmt.Sprintf("quote archive unavailable: %v", err)}
+	}
+	if err := s.quoteStore.Record(entry); err != nil {
+		return []string{fmt.Sprintf("quote archive write failed: %v", err)}
+	}
+	return nil
+}
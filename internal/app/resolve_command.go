@@ -0,0 +1,217 @@
+package app
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/httpx"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/labels"
+	"github.com/ggonzalez94/defi-cli/internal/model"
+	"github.com/ggonzalez94/defi-cli/internal/names"
+	"github.com/ggonzalez94/defi-cli/internal/registry"
+	"github.com/ggonzalez94/defi-cli/internal/rpcx"
+	"github.com/ggonzalez94/defi-cli/internal/schema"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// addressNameFlags are the account flags that accept ENS and SNS names in
+// place of an address.
+var addressNameFlags = []string{"address", "from-address", "recipient"}
+
+func (s *runtimeState) newResolveCommand() *cobra.Command {
+	root := &cobra.Command{Use: "resolve", Short: "Resolve human-readable names and label addresses"}
+
+	var nameArg string
+	nameCmd := &cobra.Command{
+		Use:   "name",
+		Short: "Resolve an ENS (.eth) or SNS (.sol) name to an address",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := names.Normalize(nameArg)
+			if name == "" {
+				return clierr.New(clierr.CodeUsage, "--name is required")
+			}
+			if !names.IsName(name) {
+				return clierr.New(clierr.CodeUsage, "--name must be an ENS name (e.g. vitalik.eth) or a .sol name")
+			}
+			key := cacheKey(trimRootPath(cmd.CommandPath()), map[string]any{"name": name})
+			return s.runCachedCommand(trimRootPath(cmd.CommandPath()), key, 5*time.Minute, func(ctx context.Context) (any, []model.ProviderStatus, []string, bool, error) {
+				start := time.Now()
+				resolved, err := s.resolveName(ctx, name)
+				status := []model.ProviderStatus{{Name: names.Service(name), Status: statusFromErr(err), LatencyMS: time.Since(start).Milliseconds()}}
+				if err != nil {
+					return nil, status, nil, false, err
+				}
+				return resolved, status, nil, false, nil
+			})
+		},
+	}
+	nameCmd.Flags().StringVar(&nameArg, "name", "", "ENS or SNS name to resolve")
+	_ = schema.SetFlagMetadata(nameCmd.Flags(), "name", schema.FlagMetadata{Required: true})
+	response := schema.SchemaFromType(model.ResolvedName{})
+	_ = schema.SetCommandMetadata(nameCmd, schema.CommandMetadata{Response: &response})
+
+	var addressArg, chainArg string
+	addressCmd := &cobra.Command{
+		Use:   "address",
+		Short: "Look up the label of a contract address in the address directory",
+		Long:  "Looks up an EVM address in the bundled directory of known routers, pools, bridges, and protocol contracts, extended by the user labels file (labels.path, default ~/.defi/labels.json). Without --chain every labeled chain is listed.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			address := strings.TrimSpace(addressArg)
+			if !common.IsHexAddress(address) {
+				return clierr.New(clierr.CodeUsage, "--address must be an EVM address")
+			}
+			found := labels.LookupAll(address)
+			if strings.TrimSpace(chainArg) != "" {
+				chain, err := id.ParseChain(chainArg)
+				if err != nil {
+					return err
+				}
+				if !chain.IsEVM() {
+					return clierr.New(clierr.CodeUnsupported, "address labels are available only for EVM chains")
+				}
+				found = []model.AddressLabel{}
+				if label, ok := labels.Lookup(chain.CAIP2, address); ok {
+					found = append(found, label)
+				}
+			}
+			var warnings []string
+			if len(found) == 0 {
+				warnings = append(warnings, "address "+common.HexToAddress(address).Hex()+" is not in the address directory")
+			}
+			return s.emitSuccess(trimRootPath(cmd.CommandPath()), found, warnings, cacheMetaBypass(), nil, false)
+		},
+	}
+	addressCmd.Flags().StringVar(&addressArg, "address", "", "EVM address to look up")
+	addressCmd.Flags().StringVar(&chainArg, "chain", "", "Only return the label on this chain")
+	_ = schema.SetFlagMetadata(addressCmd.Flags(), "address", schema.FlagMetadata{Required: true})
+	_ = schema.SetFlagMetadata(addressCmd.Flags(), "chain", schema.FlagMetadata{Format: "chain"})
+	labelsResponse := schema.SchemaFromType([]model.AddressLabel{})
+	_ = schema.SetCommandMetadata(addressCmd, schema.CommandMetadata{Response: &labelsResponse})
+
+	root.AddCommand(nameCmd)
+	root.AddCommand(addressCmd)
+	return root
+}
+
+// loadUserLabels installs the user labels file on top of the bundled address
+// directory.
+func (s *runtimeState) loadUserLabels() error {
+	path := strings.TrimSpace(s.settings.LabelsPath)
+	if path == "" {
+		labels.SetUser(nil)
+		return nil
+	}
+	entries, err := labels.Load(path)
+	if err != nil {
+		return clierr.Wrap(clierr.CodeUsage, "load user labels", err)
+	}
+	labels.SetUser(entries)
+	return nil
+}
+
+// resolveName resolves an ENS name against Ethereum mainnet or a .sol name
+// through the SNS proxy.
+func (s *runtimeState) resolveName(ctx context.Context, name string) (model.ResolvedName, error) {
+	if s.nameResolver != nil {
+		return s.nameResolver(ctx, name)
+	}
+	name = names.Normalize(name)
+	resolved := model.ResolvedName{Name: name, Service: names.Service(name)}
+	switch resolved.Service {
+	case names.ServiceSNS:
+		address, err := names.ResolveSNS(ctx, httpx.New(s.settings.Timeout, s.settings.Retries), "", name)
+		if err != nil {
+			return model.ResolvedName{}, err
+		}
+		resolved.Address = address
+	case names.ServiceENS:
+		rpcURL, err := registry.ResolveRPCURL("", 1)
+		if err != nil {
+			return model.ResolvedName{}, clierr.Wrap(clierr.CodeUnsupported, "resolve rpc", err)
+		}
+		client, err := rpcx.DialContext(ctx, rpcURL)
+		if err != nil {
+			return model.ResolvedName{}, clierr.Wrap(clierr.CodeUnavailable, "dial rpc", err)
+		}
+		defer client.Close()
+		address, err := names.ResolveENS(ctx, client, name)
+		if err != nil {
+			return model.ResolvedName{}, err
+		}
+		resolved.Address = address.Hex()
+	default:
+		return model.ResolvedName{}, clierr.New(clierr.CodeUsage, "not an ENS or SNS name: "+name)
+	}
+	return resolved, nil
+}
+
+// finalizeCommandFlags applies profile defaults and resolves names once every
+// flag value, including structured input, is in place.
+func (s *runtimeState) finalizeCommandFlags(cmd *cobra.Command) error {
+	if err := s.applyDefaultProvider(cmd); err != nil {
+		return err
+	}
+	return s.resolveAddressFlags(cmd)
+}
+
+// resolveAddressFlags replaces ENS/SNS names given to address flags with the
+// addresses they resolve to, before the command validates them. Resolutions
+// are reported in the envelope meta.
+func (s *runtimeState) resolveAddressFlags(cmd *cobra.Command) error {
+	ctx, cancel := context.WithTimeout(cmd.Context(), s.settings.Timeout)
+	defer cancel()
+	for _, flagName := range addressNameFlags {
+		flag := cmd.Flags().Lookup(flagName)
+		if flag == nil || !flag.Changed {
+			continue
+		}
+		if slice, ok := flag.Value.(pflag.SliceValue); ok {
+			values := slice.GetSlice()
+			changed := false
+			for i, value := range values {
+				if !names.IsName(value) {
+					continue
+				}
+				resolved, err := s.resolveFlagName(ctx, flagName, value)
+				if err != nil {
+					return err
+				}
+				values[i] = resolved
+				changed = true
+			}
+			if changed {
+				if err := slice.Replace(values); err != nil {
+					return clierr.Wrap(clierr.CodeUsage, "set --"+flagName, err)
+				}
+			}
+			continue
+		}
+		value := flag.Value.String()
+		if !names.IsName(value) {
+			continue
+		}
+		resolved, err := s.resolveFlagName(ctx, flagName, value)
+		if err != nil {
+			return err
+		}
+		if err := flag.Value.Set(resolved); err != nil {
+			return clierr.Wrap(clierr.CodeUsage, "set --"+flagName, err)
+		}
+	}
+	return nil
+}
+
+func (s *runtimeState) resolveFlagName(ctx context.Context, flagName, value string) (string, error) {
+	resolved, err := s.resolveName(ctx, strings.TrimSpace(value))
+	if err != nil {
+		return "", err
+	}
+	resolved.Flag = flagName
+	s.resolvedNames = append(s.resolvedNames, resolved)
+	return resolved.Address, nil
+}
@@ -0,0 +1,104 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ggonzalez94/defi-cli/internal/config"
+	"github.com/ggonzalez94/defi-cli/internal/labels"
+	"github.com/ggonzalez94/defi-cli/internal/model"
+	"github.com/spf13/cobra"
+)
+
+func TestResolveAddressFlagsSubstitutesNames(t *testing.T) {
+	lookups := 0
+	state := &runtimeState{
+		runner:   NewRunnerWithWriters(&bytes.Buffer{}, &bytes.Buffer{}),
+		settings: config.Settings{Timeout: time.Second},
+		nameResolver: func(_ context.Context, name string) (model.ResolvedName, error) {
+			lookups++
+			if strings.HasSuffix(name, ".sol") {
+				return model.ResolvedName{Name: name, Service: "sns", Address: "HKKp49qGWXd639QsuH7JiLijfVW5UtCVY4s1n2HANwEA"}, nil
+			}
+			return model.ResolvedName{Name: name, Service: "ens", Address: "0xd8dA6BF26964aF9D7eEd9e03E53415D37aA96045"}, nil
+		},
+	}
+	var from string
+	var recipient string
+	var addresses []string
+	cmd := &cobra.Command{Use: "test"}
+	cmd.SetContext(context.Background())
+	cmd.Flags().StringVar(&from, "from-address", "", "")
+	cmd.Flags().StringVar(&recipient, "recipient", "", "")
+	cmd.Flags().StringSliceVar(&addresses, "address", nil, "")
+	if err := cmd.Flags().Parse([]string{"--from-address", "vitalik.eth", "--recipient", "0x000000000000000000000000000000000000dEaD", "--address", "vitalik.eth,bonfida.sol"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := state.resolveAddressFlags(cmd); err != nil {
+		t.Fatalf("resolveAddressFlags failed: %v", err)
+	}
+	if from != "0xd8dA6BF26964aF9D7eEd9e03E53415D37aA96045" || recipient != "0x000000000000000000000000000000000000dEaD" {
+		t.Fatalf("unexpected flag values from=%s recipient=%s", from, recipient)
+	}
+	if len(addresses) != 2 || addresses[1] != "HKKp49qGWXd639QsuH7JiLijfVW5UtCVY4s1n2HANwEA" {
+		t.Fatalf("unexpected --address values %v", addresses)
+	}
+	if lookups != 3 || len(state.resolvedNames) != 3 || state.resolvedNames[0].Flag != "address" {
+		t.Fatalf("expected three recorded resolutions, got %+v", state.resolvedNames)
+	}
+}
+
+func TestResolveNameRejectsNonNames(t *testing.T) {
+	for _, args := range [][]string{
+		{"resolve", "name"},
+		{"resolve", "name", "--name", "0x000000000000000000000000000000000000dEaD"},
+	} {
+		var stdout, stderr bytes.Buffer
+		if code := NewRunnerWithWriters(&stdout, &stderr).Run(args); code != 2 {
+			t.Fatalf("expected exit 2 for %v, got %d stderr=%s", args, code, stderr.String())
+		}
+	}
+}
+
+func TestResolveAddressUsesBundledAndUserLabels(t *testing.T) {
+	dir := t.TempDir()
+	labelsPath := filepath.Join(dir, "labels.json")
+	t.Setenv("DEFI_LABELS_PATH", labelsPath)
+	t.Cleanup(func() { labels.SetUser(nil) })
+	doc := `{"labels":[{"chainId":8453,"address":"0x00000000000000000000000000000000000000aa","label":"Ops Safe","category":"multisig"}]}`
+	if err := os.WriteFile(labelsPath, []byte(doc), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	run := func(args ...string) []model.AddressLabel {
+		t.Helper()
+		var stdout, stderr bytes.Buffer
+		if code := NewRunnerWithWriters(&stdout, &stderr).Run(append(args, "--results-only")); code != 0 {
+			t.Fatalf("%v failed: code=%d stderr=%s", args, code, stderr.String())
+		}
+		var out []model.AddressLabel
+		if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+			t.Fatalf("decode %s: %v", stdout.String(), err)
+		}
+		return out
+	}
+
+	found := run("resolve", "address", "--address", "0x28b5a0e9C621a5BadaA536219b3a228C8168cf5d", "--chain", "base")
+	if len(found) != 1 || found[0].Label != "CCTP TokenMessengerV2" || found[0].Source != labels.SourceBuiltin {
+		t.Fatalf("unexpected bundled label %+v", found)
+	}
+	found = run("resolve", "address", "--address", "0x00000000000000000000000000000000000000aa")
+	if len(found) != 1 || found[0].Label != "Ops Safe" || found[0].ChainID != "eip155:8453" {
+		t.Fatalf("unexpected user label %+v", found)
+	}
+	if found = run("resolve", "address", "--address", "0x00000000000000000000000000000000000000aa", "--chain", "ethereum"); len(found) != 0 {
+		t.Fatalf("expected no label on another chain, got %+v", found)
+	}
+}
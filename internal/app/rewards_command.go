@@ -2,39 +2,104 @@ package app
 
 import (
 	"context"
+	"fmt"
 	"strings"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
 	"github.com/ggonzalez94/defi-cli/internal/execution"
 	"github.com/ggonzalez94/defi-cli/internal/execution/actionbuilder"
 	execsigner "github.com/ggonzalez94/defi-cli/internal/execution/signer"
 	"github.com/ggonzalez94/defi-cli/internal/id"
 	"github.com/ggonzalez94/defi-cli/internal/model"
+	"github.com/ggonzalez94/defi-cli/internal/providers"
+	"github.com/ggonzalez94/defi-cli/internal/schema"
 	"github.com/spf13/cobra"
 )
 
 func (s *runtimeState) newRewardsCommand() *cobra.Command {
-	root := &cobra.Command{Use: "rewards", Short: "Rewards claim and compound execution commands"}
+	root := &cobra.Command{Use: "rewards", Short: "Claimable rewards and claim/compound execution commands"}
+	root.AddCommand(s.newRewardsClaimableCommand())
 	root.AddCommand(s.newRewardsClaimCommand())
 	root.AddCommand(s.newRewardsCompoundCommand())
 	return root
 }
 
+func (s *runtimeState) newRewardsClaimableCommand() *cobra.Command {
+	var providerArg, chainArg, addressArg, rpcURL string
+	cmd := &cobra.Command{
+		Use:   "claimable",
+		Short: "List accrued, unclaimed incentives for an account",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			providerName := normalizeLendingProvider(providerArg)
+			if providerName == "" {
+				return clierr.New(clierr.CodeUsage, "--provider is required")
+			}
+			chain, err := id.ParseChain(chainArg)
+			if err != nil {
+				return err
+			}
+			account := strings.TrimSpace(addressArg)
+			if !common.IsHexAddress(account) {
+				return clierr.New(clierr.CodeUsage, "--address must be a valid EVM hex address")
+			}
+			key := cacheKey(trimRootPath(cmd.CommandPath()), map[string]any{
+				"provider": providerName,
+				"chain":    chain.CAIP2,
+				"address":  strings.ToLower(account),
+				"rpc_url":  strings.TrimSpace(rpcURL),
+			})
+			return s.runCachedCommand(trimRootPath(cmd.CommandPath()), key, 30*time.Second, func(ctx context.Context) (any, []model.ProviderStatus, []string, bool, error) {
+				start := time.Now()
+				data, err := s.claimableRewards(ctx, providerName, chain, account, rpcURL)
+				statuses := []model.ProviderStatus{{Name: providerName, Status: statusFromErr(err), LatencyMS: time.Since(start).Milliseconds()}}
+				return data, statuses, nil, false, err
+			})
+		},
+	}
+	cmd.Flags().StringVar(&providerArg, "provider", "", "Rewards provider (aave|morpho)")
+	cmd.Flags().StringVar(&chainArg, "chain", "", "Chain identifier")
+	cmd.Flags().StringVar(&addressArg, "address", "", "Account address")
+	cmd.Flags().StringVar(&rpcURL, "rpc-url", "", "RPC URL override for on-chain reads")
+	_ = cmd.MarkFlagRequired("provider")
+	_ = cmd.MarkFlagRequired("chain")
+	_ = cmd.MarkFlagRequired("address")
+	response := schema.SchemaFromType([]model.ClaimableReward{})
+	_ = schema.SetCommandMetadata(cmd, schema.CommandMetadata{Response: &response})
+	return cmd
+}
+
+func (s *runtimeState) claimableRewards(ctx context.Context, providerName string, chain id.Chain, account, rpcURL string) ([]model.ClaimableReward, error) {
+	provider, ok := s.rewardsProviders[providerName]
+	if !ok {
+		return nil, clierr.New(clierr.CodeUnsupported, fmt.Sprintf("rewards provider %s is not supported; use aave or morpho", providerName))
+	}
+	if !chain.IsEVM() {
+		return nil, clierr.New(clierr.CodeUnsupported, "claimable rewards support only EVM chains")
+	}
+	return provider.ClaimableRewards(ctx, providers.RewardsClaimableRequest{
+		Chain:   chain,
+		Account: account,
+		RPCURL:  strings.TrimSpace(rpcURL),
+	})
+}
+
 func (s *runtimeState) newRewardsClaimCommand() *cobra.Command {
 	root := &cobra.Command{Use: "claim", Short: "Claim rewards"}
 	const expectedIntent = "claim_rewards"
 
 	type claimArgs struct {
-		Provider            string   `json:"provider" flag:"provider" required:"true" enum:"aave"`
+		Provider            string   `json:"provider" flag:"provider" required:"true" enum:"aave,morpho"`
 		ChainArg            string   `json:"chain" flag:"chain" required:"true" format:"chain"`
 		WalletRef           string   `json:"wallet" flag:"wallet" format:"identifier"`
 		FromAddress         string   `json:"from_address" flag:"from-address" format:"evm-address"`
 		Recipient           string   `json:"recipient" flag:"recipient" format:"evm-address"`
-		Assets              []string `json:"assets" flag:"assets" required:"true" format:"evm-address"`
-		RewardToken         string   `json:"reward_token" flag:"reward-token" required:"true" format:"evm-address"`
+		Assets              []string `json:"assets" flag:"assets" format:"evm-address"`
+		RewardToken         string   `json:"reward_token" flag:"reward-token" format:"evm-address"`
 		AmountBase          string   `json:"amount" flag:"amount" format:"base-units"`
 		Simulate            bool     `json:"simulate" flag:"simulate"`
+		Preview             bool     `json:"preview" flag:"preview"`
 		RPCURL              string   `json:"rpc_url" flag:"rpc-url" format:"url"`
 		ControllerAddress   string   `json:"controller_address" flag:"controller-address" format:"evm-address"`
 		PoolAddressProvider string   `json:"pool_address_provider" flag:"pool-address-provider" format:"evm-address"`
@@ -60,9 +125,35 @@ func (s *runtimeState) newRewardsClaimCommand() *cobra.Command {
 		if err != nil {
 			return execution.Action{}, err
 		}
+		providerName := normalizeLendingProvider(args.Provider)
 		assets := normalizeStringSlice(args.Assets)
-		if len(assets) == 0 {
-			return execution.Action{}, clierr.New(clierr.CodeUsage, "--assets is required")
+		rewardToken := strings.TrimSpace(args.RewardToken)
+		var rewards []model.ClaimableReward
+		switch {
+		case providerName == "morpho":
+			if recipient := strings.TrimSpace(args.Recipient); recipient != "" && !strings.EqualFold(recipient, args.FromAddress) {
+				return execution.Action{}, clierr.New(clierr.CodeUsage, "morpho rewards are claimed to the sender; --recipient is not supported")
+			}
+			rewards, err = s.selectClaimableRewards(ctx, providerName, chain, args.FromAddress, args.RPCURL, rewardToken)
+			if err != nil {
+				return execution.Action{}, err
+			}
+		case len(assets) == 0 || rewardToken == "":
+			// Without explicit sources, claim the accrued reward from every
+			// reserve token that can carry it.
+			claimable, err := s.selectClaimableRewards(ctx, providerName, chain, args.FromAddress, args.RPCURL, rewardToken)
+			if err != nil {
+				return execution.Action{}, err
+			}
+			if len(claimable) > 1 {
+				return execution.Action{}, clierr.New(clierr.CodeUsage, fmt.Sprintf("%d reward tokens are claimable; set --reward-token to choose one", len(claimable)))
+			}
+			if rewardToken == "" {
+				rewardToken = rewardTokenAddress(claimable[0])
+			}
+			if len(assets) == 0 {
+				assets = claimable[0].Sources
+			}
 		}
 		amount := strings.TrimSpace(args.AmountBase)
 		if amount == "" {
@@ -74,7 +165,8 @@ func (s *runtimeState) newRewardsClaimCommand() *cobra.Command {
 			Sender:              args.FromAddress,
 			Recipient:           args.Recipient,
 			Assets:              assets,
-			RewardToken:         args.RewardToken,
+			RewardToken:         rewardToken,
+			Rewards:             rewards,
 			AmountBaseUnits:     amount,
 			Simulate:            args.Simulate,
 			RPCURL:              args.RPCURL,
@@ -111,31 +203,35 @@ func (s *runtimeState) newRewardsClaimCommand() *cobra.Command {
 				return err
 			}
 			applyExecutionIdentityToAction(&action, identity)
+			if err := s.checkActionProtocolPolicy(action); err != nil {
+				return err
+			}
 			if err := s.ensureActionStore(); err != nil {
 				return err
 			}
+			recordPlanInvocation(cmd, &action)
 			if err := s.actionStore.Save(action); err != nil {
 				return clierr.Wrap(clierr.CodeInternal, "persist planned action", err)
 			}
 			s.captureCommandDiagnostics(nil, statuses, false)
+			attachPlanPreview(&action, plan.Preview)
 			return s.emitSuccess(trimRootPath(cmd.CommandPath()), action, identity.Warnings, cacheMetaBypass(), statuses, false)
 		},
 	}
-	planCmd.Flags().StringVar(&plan.Provider, "provider", "", "Rewards provider (aave)")
+	planCmd.Flags().StringVar(&plan.Provider, "provider", "", "Rewards provider (aave|morpho)")
 	planCmd.Flags().StringVar(&plan.ChainArg, "chain", "", "Chain identifier")
 	planCmd.Flags().StringVar(&plan.WalletRef, "wallet", "", "Wallet identifier or name")
 	planCmd.Flags().StringVar(&plan.FromAddress, "from-address", "", "Sender EOA address")
 	planCmd.Flags().StringVar(&plan.Recipient, "recipient", "", "Recipient address (defaults to the resolved sender address)")
-	planCmd.Flags().StringSliceVar(&plan.Assets, "assets", nil, "Comma-separated rewards source asset addresses")
-	planCmd.Flags().StringVar(&plan.RewardToken, "reward-token", "", "Reward token address")
+	planCmd.Flags().StringSliceVar(&plan.Assets, "assets", nil, "Comma-separated rewards source asset addresses (defaults to every source of the claimable reward)")
+	planCmd.Flags().StringVar(&plan.RewardToken, "reward-token", "", "Reward token address (defaults to the only claimable reward; filters morpho rewards)")
 	planCmd.Flags().StringVar(&plan.AmountBase, "amount", "", "Claim amount in base units (defaults to max)")
 	planCmd.Flags().BoolVar(&plan.Simulate, "simulate", true, "Include simulation checks during execution")
+	planCmd.Flags().BoolVar(&plan.Preview, "preview", false, "Decode step calldata and attach a human-readable preview")
 	planCmd.Flags().StringVar(&plan.RPCURL, "rpc-url", "", "RPC URL override for the selected chain")
 	planCmd.Flags().StringVar(&plan.ControllerAddress, "controller-address", "", "Aave incentives controller address override")
 	planCmd.Flags().StringVar(&plan.PoolAddressProvider, "pool-address-provider", "", "Aave pool address provider override")
 	_ = planCmd.MarkFlagRequired("chain")
-	_ = planCmd.MarkFlagRequired("assets")
-	_ = planCmd.MarkFlagRequired("reward-token")
 	_ = planCmd.MarkFlagRequired("provider")
 	configureStructuredInput[claimArgs](planCmd, structuredInputOptions{
 		Mutation:         true,
@@ -258,6 +354,7 @@ func (s *runtimeState) newRewardsCompoundCommand() *cobra.Command {
 		RewardToken         string   `json:"reward_token" flag:"reward-token" required:"true" format:"evm-address"`
 		AmountBase          string   `json:"amount" flag:"amount" required:"true" format:"base-units"`
 		Simulate            bool     `json:"simulate" flag:"simulate"`
+		Preview             bool     `json:"preview" flag:"preview"`
 		RPCURL              string   `json:"rpc_url" flag:"rpc-url" format:"url"`
 		ControllerAddress   string   `json:"controller_address" flag:"controller-address" format:"evm-address"`
 		PoolAddress         string   `json:"pool_address" flag:"pool-address" format:"evm-address"`
@@ -337,13 +434,18 @@ func (s *runtimeState) newRewardsCompoundCommand() *cobra.Command {
 				return err
 			}
 			applyExecutionIdentityToAction(&action, identity)
+			if err := s.checkActionProtocolPolicy(action); err != nil {
+				return err
+			}
 			if err := s.ensureActionStore(); err != nil {
 				return err
 			}
+			recordPlanInvocation(cmd, &action)
 			if err := s.actionStore.Save(action); err != nil {
 				return clierr.Wrap(clierr.CodeInternal, "persist planned action", err)
 			}
 			s.captureCommandDiagnostics(nil, statuses, false)
+			attachPlanPreview(&action, plan.Preview)
 			return s.emitSuccess(trimRootPath(cmd.CommandPath()), action, identity.Warnings, cacheMetaBypass(), statuses, false)
 		},
 	}
@@ -357,6 +459,7 @@ func (s *runtimeState) newRewardsCompoundCommand() *cobra.Command {
 	planCmd.Flags().StringVar(&plan.RewardToken, "reward-token", "", "Reward token address")
 	planCmd.Flags().StringVar(&plan.AmountBase, "amount", "", "Compound amount in base units")
 	planCmd.Flags().BoolVar(&plan.Simulate, "simulate", true, "Include simulation checks during execution")
+	planCmd.Flags().BoolVar(&plan.Preview, "preview", false, "Decode step calldata and attach a human-readable preview")
 	planCmd.Flags().StringVar(&plan.RPCURL, "rpc-url", "", "RPC URL override for the selected chain")
 	planCmd.Flags().StringVar(&plan.ControllerAddress, "controller-address", "", "Aave incentives controller address override")
 	planCmd.Flags().StringVar(&plan.PoolAddress, "pool-address", "", "Aave pool address override")
@@ -471,3 +574,32 @@ func (s *runtimeState) newRewardsCompoundCommand() *cobra.Command {
 	root.AddCommand(statusCmd)
 	return root
 }
+
+// selectClaimableRewards returns the account's claimable rewards, limited to
+// rewardToken when set. It fails when nothing is left to claim.
+func (s *runtimeState) selectClaimableRewards(ctx context.Context, providerName string, chain id.Chain, account, rpcURL, rewardToken string) ([]model.ClaimableReward, error) {
+	rewards, err := s.claimableRewards(ctx, providerName, chain, account, rpcURL)
+	if err != nil {
+		return nil, err
+	}
+	selected := make([]model.ClaimableReward, 0, len(rewards))
+	for _, reward := range rewards {
+		if rewardToken == "" || strings.EqualFold(rewardTokenAddress(reward), rewardToken) {
+			selected = append(selected, reward)
+		}
+	}
+	if len(selected) == 0 {
+		if rewardToken != "" {
+			return nil, clierr.New(clierr.CodeUsage, fmt.Sprintf("no claimable %s rewards for reward token %s", providerName, rewardToken))
+		}
+		return nil, clierr.New(clierr.CodeUsage, fmt.Sprintf("no claimable %s rewards for %s", providerName, account))
+	}
+	return selected, nil
+}
+
+func rewardTokenAddress(reward model.ClaimableReward) string {
+	if _, address, ok := strings.Cut(reward.RewardAssetID, "/erc20:"); ok {
+		return address
+	}
+	return reward.RewardAssetID
+}
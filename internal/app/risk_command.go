@@ -0,0 +1,320 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/model"
+	"github.com/ggonzalez94/defi-cli/internal/providers"
+	"github.com/ggonzalez94/defi-cli/internal/providers/yieldutil"
+	"github.com/ggonzalez94/defi-cli/internal/schema"
+	"github.com/spf13/cobra"
+)
+
+// defaultDepegThresholdBps is how far from $1 a stablecoin may trade before
+// it counts as depegged, in risk depeg and in yield opportunities.
+const defaultDepegThresholdBps = 50
+
+// Depeg statuses of model.DepegReport.
+const (
+	depegStatusPegged   = "pegged"
+	depegStatusWatch    = "watch"
+	depegStatusDepegged = "depegged"
+)
+
+// depegDepthDex is the DEX whose pools measure stablecoin liquidity depth.
+const depegDepthDex = "uniswap"
+
+func (s *runtimeState) newRiskCommand() *cobra.Command {
+	root := &cobra.Command{Use: "risk", Short: "Asset risk monitoring"}
+
+	var chainArg, assetsArg, windowArg string
+	var thresholdBps, depthBps int
+	depegCmd := &cobra.Command{
+		Use:   "depeg",
+		Short: "Report stablecoin deviation from $1, its recent maximum, and DEX depth",
+		Long: `Reports, for each USD stablecoin in --assets, its current deviation from $1, the largest deviation in hourly prices over --window, and how much can be sold or bought in Uniswap pools against USDC (USDT for USDC) before the price moves --depth-bps.
+
+Depth uses each pool's in-range liquidity at the current tick, so it ignores liquidity beyond the nearest initialized ticks, and values the reference side at $1. A stablecoin is depegged when it trades --threshold-bps or more from $1 and on watch when it crossed that over the window. Yield opportunities flag depegged stablecoins in risk_reasons.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			chain, err := id.ParseChain(chainArg)
+			if err != nil {
+				return err
+			}
+			symbols := splitCSV(assetsArg)
+			if len(symbols) == 0 {
+				return clierr.New(clierr.CodeUsage, "--assets is required")
+			}
+			assets := make([]id.Asset, 0, len(symbols))
+			for _, symbol := range symbols {
+				asset, err := id.ParseAsset(symbol, chain)
+				if err != nil {
+					return err
+				}
+				assets = append(assets, asset)
+			}
+			window, err := parseLookbackWindow(windowArg)
+			if err != nil {
+				return clierr.Wrap(clierr.CodeUsage, "parse --window", err)
+			}
+			if thresholdBps <= 0 || depthBps <= 0 || depthBps >= 10_000 {
+				return clierr.New(clierr.CodeUsage, "--threshold-bps and --depth-bps must be positive and --depth-bps below 10000")
+			}
+			assetIDs := make([]string, 0, len(assets))
+			for _, asset := range assets {
+				assetIDs = append(assetIDs, asset.AssetID)
+			}
+
+			key := cacheKey(trimRootPath(cmd.CommandPath()), map[string]any{
+				"chain":         chain.CAIP2,
+				"assets":        assetIDs,
+				"window":        window.String(),
+				"threshold_bps": thresholdBps,
+				"depth_bps":     depthBps,
+			})
+			return s.runCachedCommand(trimRootPath(cmd.CommandPath()), key, 60*time.Second, func(ctx context.Context) (any, []model.ProviderStatus, []string, bool, error) {
+				history, err := s.priceHistoryProvider(chain)
+				if err != nil {
+					return nil, nil, nil, false, err
+				}
+				endTime := s.runner.now().UTC()
+				statuses := []model.ProviderStatus{}
+				warnings := []string{}
+				partial := false
+				var firstErr error
+				out := []model.DepegReport{}
+				for _, asset := range assets {
+					report, assetStatuses, assetWarnings, err := s.depegReport(ctx, history, chain, asset, endTime.Add(-window), endTime, thresholdBps, depthBps)
+					statuses = append(statuses, assetStatuses...)
+					warnings = append(warnings, assetWarnings...)
+					partial = partial || len(assetWarnings) > 0
+					if err != nil {
+						warnings = append(warnings, fmt.Sprintf("asset %s failed: %v", asset.Symbol, err))
+						partial = true
+						if firstErr == nil {
+							firstErr = err
+						}
+						continue
+					}
+					out = append(out, report)
+				}
+				if len(out) == 0 {
+					return nil, statuses, warnings, partial, firstErr
+				}
+				return out, statuses, warnings, partial, nil
+			})
+		},
+	}
+	depegCmd.Flags().StringVar(&assetsArg, "assets", "", "Comma-separated USD stablecoins (symbol/address/CAIP-19)")
+	depegCmd.Flags().StringVar(&chainArg, "chain", "ethereum", "Chain whose prices and pools are read")
+	depegCmd.Flags().StringVar(&windowArg, "window", "30d", "Lookback window for the max deviation")
+	depegCmd.Flags().IntVar(&thresholdBps, "threshold-bps", defaultDepegThresholdBps, "Deviation from $1, in basis points, that counts as a depeg")
+	depegCmd.Flags().IntVar(&depthBps, "depth-bps", 50, "Price move, in basis points, to measure liquidity depth at")
+	_ = depegCmd.MarkFlagRequired("assets")
+	depegResponse := schema.SchemaFromType([]model.DepegReport{})
+	_ = schema.SetCommandMetadata(depegCmd, schema.CommandMetadata{Response: &depegResponse})
+	root.AddCommand(depegCmd)
+	return root
+}
+
+// depegReport builds the report of one asset. The current price is required;
+// the window history and pool depth are best effort and warn when missing.
+func (s *runtimeState) depegReport(ctx context.Context, history providers.PriceHistoryProvider, chain id.Chain, asset id.Asset, startTime, endTime time.Time, thresholdBps, depthBps int) (model.DepegReport, []model.ProviderStatus, []string, error) {
+	statuses := []model.ProviderStatus{}
+	warnings := []string{}
+	start := time.Now()
+	priceUSD, err := s.tokenPriceUSD(ctx, chain, asset)
+	if s.marketProvider != nil {
+		statuses = append(statuses, model.ProviderStatus{Name: s.marketProvider.Info().Name, Status: statusFromErr(err), LatencyMS: time.Since(start).Milliseconds()})
+	}
+	if err != nil {
+		return model.DepegReport{}, statuses, nil, err
+	}
+	report := model.DepegReport{
+		Symbol:       asset.Symbol,
+		ChainID:      chain.CAIP2,
+		AssetID:      asset.AssetID,
+		PriceUSD:     priceUSD,
+		DeviationPct: pegDeviationPct(priceUSD),
+		WindowDays:   int(math.Round(endTime.Sub(startTime).Hours() / 24)),
+		DepthBps:     depthBps,
+		ThresholdBps: thresholdBps,
+		FetchedAt:    endTime.Format(time.RFC3339),
+	}
+	report.MaxDeviationPct = report.DeviationPct
+	report.MaxDeviationAt = report.FetchedAt
+
+	start = time.Now()
+	series, err := history.PriceHistory(ctx, providers.PriceHistoryRequest{
+		Chain:     chain,
+		Asset:     asset,
+		StartTime: startTime,
+		EndTime:   endTime,
+		Interval:  providers.YieldHistoryIntervalHour,
+	})
+	statuses = append(statuses, model.ProviderStatus{Name: history.Info().Name, Status: statusFromErr(err), LatencyMS: time.Since(start).Milliseconds()})
+	if err != nil {
+		warnings = append(warnings, fmt.Sprintf("price history unavailable for %s; max_deviation_pct covers the current price only: %v", asset.Symbol, err))
+	}
+	for _, point := range series.Points {
+		if point.Value <= 0 {
+			continue
+		}
+		if deviation := pegDeviationPct(point.Value); math.Abs(deviation) > math.Abs(report.MaxDeviationPct) {
+			report.MaxDeviationPct = deviation
+			report.MaxDeviationAt = point.Timestamp
+		}
+	}
+
+	reference, err := depegReferenceAsset(chain, asset)
+	if err != nil {
+		warnings = append(warnings, fmt.Sprintf("no reference stablecoin for %s depth on %s: %v", asset.Symbol, chain.Slug, err))
+	} else {
+		report.ReferenceAssetID = reference.AssetID
+		depthStatus, err := s.attachDepegDepth(ctx, &report, chain, asset, reference)
+		if depthStatus != nil {
+			statuses = append(statuses, *depthStatus)
+		}
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("pool depth unavailable for %s: %v", asset.Symbol, err))
+		}
+	}
+
+	threshold := float64(thresholdBps) / 100
+	switch {
+	case math.Abs(report.DeviationPct) >= threshold:
+		report.Status = depegStatusDepegged
+	case math.Abs(report.MaxDeviationPct) >= threshold:
+		report.Status = depegStatusWatch
+	default:
+		report.Status = depegStatusPegged
+	}
+	return report, statuses, warnings, nil
+}
+
+// depegReferenceAsset is the stablecoin depth is measured against: USDC, or
+// USDT for USDC itself.
+func depegReferenceAsset(chain id.Chain, asset id.Asset) (id.Asset, error) {
+	reference, err := id.ParseAsset("USDC", chain)
+	if err != nil || reference.AssetID == asset.AssetID {
+		return id.ParseAsset("USDT", chain)
+	}
+	return reference, nil
+}
+
+// attachDepegDepth sums the depth of every pool pairing asset with
+// reference.
+func (s *runtimeState) attachDepegDepth(ctx context.Context, report *model.DepegReport, chain id.Chain, asset, reference id.Asset) (*model.ProviderStatus, error) {
+	provider, ok := s.poolsProviders[depegDepthDex]
+	if !ok {
+		return nil, clierr.New(clierr.CodeUnsupported, fmt.Sprintf("pools dex %s not configured", depegDepthDex))
+	}
+	start := time.Now()
+	pools, err := provider.ListPools(ctx, providers.PoolsRequest{Chain: chain, TokenA: asset, TokenB: reference, Limit: 10})
+	status := &model.ProviderStatus{Name: provider.Info().Name, Status: statusFromErr(err), LatencyMS: time.Since(start).Milliseconds()}
+	if err != nil {
+		return status, err
+	}
+	for _, pool := range pools {
+		down, up, ok := poolDepthUSD(pool, asset.AssetID, report.DepthBps, report.PriceUSD)
+		if !ok {
+			continue
+		}
+		report.DepthDownUSD += down
+		report.DepthUpUSD += up
+		report.DepthPools++
+	}
+	if report.DepthPools == 0 {
+		return status, clierr.New(clierr.CodeUnavailable, "no pool reports in-range liquidity")
+	}
+	return status, nil
+}
+
+// poolDepthUSD returns the USD value of assetID that must be sold into pool
+// to lower its price by bps (down), and of the other token that must be paid
+// in to raise it by bps (up), valued at $1. It holds the pool's in-range
+// liquidity L constant: moving the raw price P (token1 per token0) to P'
+// takes L*(sqrt(P')-sqrt(P)) of token1 when it rises and
+// L*(1/sqrt(P')-1/sqrt(P)) of token0 when it falls.
+func poolDepthUSD(pool model.DexPool, assetID string, bps int, assetPriceUSD float64) (float64, float64, bool) {
+	liquidity, err := strconv.ParseFloat(pool.Liquidity, 64)
+	if err != nil || liquidity <= 0 {
+		return 0, 0, false
+	}
+	price := math.Pow(1.0001, float64(pool.Tick))
+	move := float64(bps) / 10_000
+	amountIn := func(target float64) float64 {
+		if target > price {
+			return liquidity * (math.Sqrt(target) - math.Sqrt(price))
+		}
+		return liquidity * (1/math.Sqrt(target) - 1/math.Sqrt(price))
+	}
+	switch {
+	case strings.EqualFold(pool.Token0.AssetID, assetID):
+		down := amountIn(price*(1-move)) / math.Pow10(pool.Token0.Decimals) * assetPriceUSD
+		up := amountIn(price*(1+move)) / math.Pow10(pool.Token1.Decimals)
+		return down, up, true
+	case strings.EqualFold(pool.Token1.AssetID, assetID):
+		down := amountIn(price/(1-move)) / math.Pow10(pool.Token1.Decimals) * assetPriceUSD
+		up := amountIn(price/(1+move)) / math.Pow10(pool.Token0.Decimals)
+		return down, up, true
+	}
+	return 0, 0, false
+}
+
+// pegDeviationPct is the deviation of a USD price from $1 in percentage
+// points.
+func pegDeviationPct(priceUSD float64) float64 {
+	return (priceUSD - 1) * 100
+}
+
+// isUSDStable reports whether symbol is a stablecoin pegged to $1.
+func isUSDStable(symbol string) bool {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+	return yieldutil.AssetClass(symbol) == yieldutil.AssetClassStable && !strings.HasPrefix(symbol, "EUR")
+}
+
+// attachDepegRisk adds a risk reason to the items of a USD stablecoin asset
+// trading defaultDepegThresholdBps or more from $1. It is best effort: without
+// a price provider items are left as they are.
+func (s *runtimeState) attachDepegRisk(ctx context.Context, chain id.Chain, asset id.Asset, items []model.YieldOpportunity) []string {
+	if !isUSDStable(asset.Symbol) {
+		return nil
+	}
+	if _, ok := s.marketProvider.(providers.TokenPriceProvider); !ok {
+		return nil
+	}
+	priceUSD, err := s.tokenPriceUSD(ctx, chain, asset)
+	if err != nil {
+		return []string{fmt.Sprintf("depeg check unavailable for %s: %v", asset.Symbol, err)}
+	}
+	deviation := pegDeviationPct(priceUSD)
+	if math.Abs(deviation) < float64(defaultDepegThresholdBps)/100 {
+		return nil
+	}
+	reason := fmt.Sprintf("depeg: %s trades at $%.4f, %+.2f%% from $1", asset.Symbol, priceUSD, deviation)
+	for i := range items {
+		if yieldHoldsAsset(items[i], asset.AssetID) {
+			items[i].RiskReasons = append(items[i].RiskReasons, reason)
+		}
+	}
+	return nil
+}
+
+func yieldHoldsAsset(item model.YieldOpportunity, assetID string) bool {
+	if strings.EqualFold(item.AssetID, assetID) {
+		return true
+	}
+	for _, backing := range item.BackingAssets {
+		if strings.EqualFold(backing.AssetID, assetID) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,193 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ggonzalez94/defi-cli/internal/config"
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/model"
+	"github.com/ggonzalez94/defi-cli/internal/providers"
+	"github.com/spf13/cobra"
+)
+
+type fakeDepegMarketProvider struct {
+	fakeTokenPriceProvider
+	history map[string][]model.MarketHistoryPoint
+}
+
+func (f fakeDepegMarketProvider) TVLHistory(context.Context, providers.TVLHistoryRequest) (model.MarketHistorySeries, error) {
+	return model.MarketHistorySeries{}, nil
+}
+
+func (f fakeDepegMarketProvider) PriceHistory(_ context.Context, req providers.PriceHistoryRequest) (model.MarketHistorySeries, error) {
+	return model.MarketHistorySeries{Metric: "price_usd", AssetID: req.Asset.AssetID, Points: f.history[req.Asset.Symbol]}, nil
+}
+
+type fakePoolsProvider struct {
+	pools map[string][]model.DexPool
+}
+
+func (f fakePoolsProvider) Info() model.ProviderInfo {
+	return model.ProviderInfo{Name: "uniswap", Type: "pools", Capabilities: []string{"pools.list"}}
+}
+
+func (f fakePoolsProvider) ListPools(_ context.Context, req providers.PoolsRequest) ([]model.DexPool, error) {
+	pools, ok := f.pools[req.TokenA.Symbol+"/"+req.TokenB.Symbol]
+	if !ok {
+		return nil, clierr.New(clierr.CodeUnsupported, "no uniswap pools for requested pair")
+	}
+	return pools, nil
+}
+
+func (f fakePoolsProvider) PoolDetails(context.Context, id.Chain, string) (model.DexPool, error) {
+	return model.DexPool{}, clierr.New(clierr.CodeUnsupported, "not implemented")
+}
+
+func TestPoolDepthUSDHoldsInRangeLiquidity(t *testing.T) {
+	stable := model.DexPoolToken{AssetID: "stable", Decimals: 6}
+	reference := model.DexPoolToken{AssetID: "reference", Decimals: 6}
+	pool := model.DexPool{Token0: stable, Token1: reference, Tick: 0, Liquidity: "1000000000000"}
+
+	down, up, ok := poolDepthUSD(pool, "stable", 50, 1)
+	wantDown := 1e6 * (1/math.Sqrt(0.995) - 1)
+	wantUp := 1e6 * (math.Sqrt(1.005) - 1)
+	if !ok || math.Abs(down-wantDown) > 1e-6 || math.Abs(up-wantUp) > 1e-6 {
+		t.Fatalf("unexpected depth down=%f up=%f (want %f %f)", down, up, wantDown, wantUp)
+	}
+
+	// With the tokens swapped the stablecoin is priced by 1/P, and the same
+	// liquidity gives nearly the same depth.
+	pool.Token0, pool.Token1 = reference, stable
+	flippedDown, flippedUp, ok := poolDepthUSD(pool, "stable", 50, 1)
+	if !ok || math.Abs(flippedDown-down)/down > 0.01 || math.Abs(flippedUp-up)/up > 0.01 {
+		t.Fatalf("expected symmetric depth, got down=%f up=%f", flippedDown, flippedUp)
+	}
+
+	if _, _, ok := poolDepthUSD(model.DexPool{Token0: stable, Token1: reference}, "stable", 50, 1); ok {
+		t.Fatal("expected a pool without liquidity to be skipped")
+	}
+}
+
+func TestRiskDepegReportsDeviationHistoryAndDepth(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	const dai = "0x6b175474e89094c44da98b954eedeac495271d0f"
+	const usdt = "0xdac17f958d2ee523a2206206994597c13d831ec7"
+	chain, _ := id.ParseChain("ethereum")
+	daiAsset, _ := id.ParseAsset("DAI", chain)
+	usdcAsset, _ := id.ParseAsset("USDC", chain)
+	daiPool := model.DexPool{
+		Token0:    model.DexPoolToken{AssetID: daiAsset.AssetID, Symbol: "DAI", Decimals: 18},
+		Token1:    model.DexPoolToken{AssetID: usdcAsset.AssetID, Symbol: "USDC", Decimals: 6},
+		Tick:      -276324,
+		Liquidity: "5000000000000000000",
+	}
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	state := &runtimeState{
+		runner: &Runner{
+			stdout: &stdout,
+			stderr: &stderr,
+			now:    func() time.Time { return now },
+		},
+		settings: config.Settings{
+			OutputMode:   "json",
+			Timeout:      2 * time.Second,
+			CacheEnabled: false,
+		},
+		marketProvider: fakeDepegMarketProvider{
+			fakeTokenPriceProvider: fakeTokenPriceProvider{prices: map[string]model.TokenPrice{
+				dai:  {PriceUSD: 0.99},
+				usdt: {PriceUSD: 1.0001},
+			}},
+			history: map[string][]model.MarketHistoryPoint{
+				"USDT": {
+					{Timestamp: "2026-02-10T00:00:00Z", Value: 0.994},
+					{Timestamp: "2026-02-11T00:00:00Z", Value: 1.002},
+				},
+			},
+		},
+		poolsProviders: map[string]providers.PoolsProvider{
+			"uniswap": fakePoolsProvider{pools: map[string][]model.DexPool{"DAI/USDC": {daiPool}}},
+		},
+	}
+
+	root := &cobra.Command{Use: "defi"}
+	root.SilenceUsage = true
+	root.SilenceErrors = true
+	root.SetOut(&stdout)
+	root.SetErr(&stderr)
+	root.AddCommand(state.newRiskCommand())
+	root.SetArgs([]string{"risk", "depeg", "--assets", "DAI,USDT"})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("risk depeg failed: %v stderr=%s", err, stderr.String())
+	}
+	var env struct {
+		Data     []model.DepegReport `json:"data"`
+		Warnings []string            `json:"warnings"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &env); err != nil {
+		t.Fatalf("decode output: %v output=%s", err, stdout.String())
+	}
+	if len(env.Data) != 2 {
+		t.Fatalf("expected a report per asset, got %+v", env.Data)
+	}
+
+	daiReport := env.Data[0]
+	if daiReport.Status != depegStatusDepegged || math.Abs(daiReport.DeviationPct+1) > 1e-9 || daiReport.WindowDays != 30 {
+		t.Fatalf("expected DAI at $0.99 to be depegged, got %+v", daiReport)
+	}
+	wantDown, wantUp, _ := poolDepthUSD(daiPool, daiAsset.AssetID, 50, 0.99)
+	if daiReport.DepthPools != 1 || daiReport.DepthDownUSD != wantDown || daiReport.DepthUpUSD != wantUp || wantDown <= 0 {
+		t.Fatalf("unexpected DAI depth %+v (want %f %f)", daiReport, wantDown, wantUp)
+	}
+
+	usdtReport := env.Data[1]
+	if usdtReport.Status != depegStatusWatch || math.Abs(usdtReport.MaxDeviationPct+0.6) > 1e-9 || usdtReport.MaxDeviationAt != "2026-02-10T00:00:00Z" {
+		t.Fatalf("expected USDT to be on watch after its dip, got %+v", usdtReport)
+	}
+	if usdtReport.ReferenceAssetID != usdcAsset.AssetID || usdtReport.DepthPools != 0 {
+		t.Fatalf("expected USDT depth against USDC to be missing, got %+v", usdtReport)
+	}
+	if !strings.Contains(strings.Join(env.Warnings, "\n"), "pool depth unavailable for USDT") {
+		t.Fatalf("expected a depth warning for USDT, got %v", env.Warnings)
+	}
+}
+
+func TestAttachDepegRiskFlagsStablecoinOpportunities(t *testing.T) {
+	chain, _ := id.ParseChain("ethereum")
+	dai, _ := id.ParseAsset("DAI", chain)
+	state := &runtimeState{
+		marketProvider: fakeTokenPriceProvider{prices: map[string]model.TokenPrice{
+			"0x6b175474e89094c44da98b954eedeac495271d0f": {PriceUSD: 0.993},
+		}},
+	}
+	items := []model.YieldOpportunity{
+		{OpportunityID: "dai-vault", AssetID: dai.AssetID},
+		{OpportunityID: "other", AssetID: "eip155:1/erc20:0x0000000000000000000000000000000000000001"},
+	}
+	if warnings := state.attachDepegRisk(context.Background(), chain, dai, items); len(warnings) != 0 {
+		t.Fatalf("unexpected warnings %v", warnings)
+	}
+	if len(items[0].RiskReasons) != 1 || !strings.Contains(items[0].RiskReasons[0], "depeg: DAI trades at $0.9930, -0.70% from $1") {
+		t.Fatalf("expected a depeg reason on the DAI opportunity, got %+v", items[0].RiskReasons)
+	}
+	if len(items[1].RiskReasons) != 0 {
+		t.Fatalf("expected other assets to be left alone, got %+v", items[1].RiskReasons)
+	}
+
+	state.marketProvider = fakeTokenPriceProvider{prices: map[string]model.TokenPrice{
+		"0x6b175474e89094c44da98b954eedeac495271d0f": {PriceUSD: 0.999},
+	}}
+	items[0].RiskReasons = nil
+	state.attachDepegRisk(context.Background(), chain, dai, items)
+	if len(items[0].RiskReasons) != 0 {
+		t.Fatalf("expected a 10bps deviation not to be flagged, got %+v", items[0].RiskReasons)
+	}
+}
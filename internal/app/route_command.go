@@ -0,0 +1,705 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/execution"
+	"github.com/ggonzalez94/defi-cli/internal/execution/actionbuilder"
+	execsigner "github.com/ggonzalez94/defi-cli/internal/execution/signer"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/model"
+	"github.com/ggonzalez94/defi-cli/internal/providers"
+	"github.com/ggonzalez94/defi-cli/internal/schema"
+	"github.com/spf13/cobra"
+)
+
+// routeRequest is a parsed source/destination pair. BridgeAsset is the
+// destination-chain counterpart of the source asset, which a two-leg route
+// bridges into before swapping to ToAsset.
+type routeRequest struct {
+	FromChain     id.Chain
+	ToChain       id.Chain
+	FromAsset     id.Asset
+	ToAsset       id.Asset
+	BridgeAsset   *id.Asset
+	AmountBase    string
+	AmountDecimal string
+	ToRPCURL      string
+}
+
+// routeCandidate pairs a quoted route with the providers that build it.
+type routeCandidate struct {
+	Quote          model.RouteQuote
+	BridgeProvider string
+	SwapProvider   string
+	BridgeAsset    id.Asset
+}
+
+func (s *runtimeState) newRouteCommand() *cobra.Command {
+	root := &cobra.Command{Use: "route", Short: "Best-route planning across bridge and swap providers"}
+
+	var quoteFromArg, quoteToArg, quoteAssetArg, quoteToAssetArg, quoteAmountBase, quoteAmountDecimal string
+	var quoteBridgesArg, quoteSwapsArg, quoteToRPCURL string
+	quoteCmd := &cobra.Command{
+		Use:   "quote",
+		Short: "Compare bridge and bridge+swap routes by output net of gas",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			req, err := parseRouteRequest(quoteFromArg, quoteToArg, quoteAssetArg, quoteToAssetArg, quoteAmountBase, quoteAmountDecimal, quoteToRPCURL)
+			if err != nil {
+				return err
+			}
+			bridgeNames, err := s.selectRouteProviders("bridge", splitCSV(quoteBridgesArg), s.actionBuilderRegistry().BridgeExecutionProviderNames(), func(name string) bool {
+				_, ok := s.bridgeProviders[name]
+				return ok
+			})
+			if err != nil {
+				return err
+			}
+			swapNames, err := s.selectRouteProviders("swap", splitCSV(quoteSwapsArg), actionbuilder.RouteSwapProviders, func(name string) bool {
+				_, ok := s.swapProviders[name]
+				return ok
+			})
+			if err != nil {
+				return err
+			}
+			key := cacheKey(trimRootPath(cmd.CommandPath()), map[string]any{
+				"from":             req.FromChain.CAIP2,
+				"to":               req.ToChain.CAIP2,
+				"from_asset":       req.FromAsset.AssetID,
+				"to_asset":         req.ToAsset.AssetID,
+				"amount":           req.AmountBase,
+				"bridge_providers": bridgeNames,
+				"swap_providers":   swapNames,
+				"allow_protocols":  s.settings.AllowProtocols,
+				"deny_protocols":   s.settings.DenyProtocols,
+			})
+			return s.runCachedCommand(trimRootPath(cmd.CommandPath()), key, 15*time.Second, func(ctx context.Context) (any, []model.ProviderStatus, []string, bool, error) {
+				candidates, statuses, warnings, partial, err := s.quoteRoutes(ctx, req, bridgeNames, swapNames)
+				if err != nil {
+					return nil, statuses, warnings, partial, err
+				}
+				return rankedRouteComparison(candidates), statuses, warnings, partial, nil
+			})
+		},
+	}
+	quoteCmd.Flags().StringVar(&quoteFromArg, "from", "", "Source chain")
+	quoteCmd.Flags().StringVar(&quoteToArg, "to", "", "Destination chain")
+	quoteCmd.Flags().StringVar(&quoteAssetArg, "asset", "", "Asset (symbol/address/CAIP-19) on source chain")
+	quoteCmd.Flags().StringVar(&quoteToAssetArg, "to-asset", "", "Asset (symbol/address/CAIP-19) to receive on destination chain")
+	quoteCmd.Flags().StringVar(&quoteAmountBase, "amount", "", "Amount in base units")
+	quoteCmd.Flags().StringVar(&quoteAmountDecimal, "amount-decimal", "", "Amount in decimal units")
+	quoteCmd.Flags().StringVar(&quoteBridgesArg, "bridge-providers", "", "Bridge providers to compare (comma-separated; defaults to execution-capable bridges)")
+	quoteCmd.Flags().StringVar(&quoteSwapsArg, "swap-providers", "", "Destination swap providers to compare (comma-separated; defaults to odos,paraswap,taikoswap)")
+	quoteCmd.Flags().StringVar(&quoteToRPCURL, "to-rpc-url", "", "RPC URL override for destination chain swap quotes")
+	_ = quoteCmd.MarkFlagRequired("from")
+	_ = quoteCmd.MarkFlagRequired("to")
+	_ = quoteCmd.MarkFlagRequired("asset")
+	_ = quoteCmd.MarkFlagRequired("to-asset")
+	_ = schema.SetFlagMetadata(quoteCmd.Flags(), "from", schema.FlagMetadata{Required: true, Format: "chain"})
+	_ = schema.SetFlagMetadata(quoteCmd.Flags(), "to", schema.FlagMetadata{Required: true, Format: "chain"})
+	_ = schema.SetFlagMetadata(quoteCmd.Flags(), "asset", schema.FlagMetadata{Required: true, Format: "asset"})
+	_ = schema.SetFlagMetadata(quoteCmd.Flags(), "to-asset", schema.FlagMetadata{Required: true, Format: "asset"})
+	_ = schema.SetFlagMetadata(quoteCmd.Flags(), "amount", schema.FlagMetadata{Format: "base-units"})
+	_ = schema.SetFlagMetadata(quoteCmd.Flags(), "amount-decimal", schema.FlagMetadata{Format: "decimal-amount"})
+	_ = schema.SetFlagMetadata(quoteCmd.Flags(), "to-rpc-url", schema.FlagMetadata{Format: "url"})
+	routeQuoteResponse := schema.SchemaFromType(model.RouteComparison{})
+	annotateStructuredFlagCommand(quoteCmd, structuredInputOptions{Response: &routeQuoteResponse})
+	root.AddCommand(quoteCmd)
+
+	s.addRouteExecutionSubcommands(root)
+	return root
+}
+
+func (s *runtimeState) addRouteExecutionSubcommands(root *cobra.Command) {
+	type routePlanArgs struct {
+		FromArg         string `json:"from" flag:"from" required:"true" format:"chain"`
+		ToArg           string `json:"to" flag:"to" required:"true" format:"chain"`
+		AssetArg        string `json:"asset" flag:"asset" required:"true" format:"asset"`
+		ToAssetArg      string `json:"to_asset" flag:"to-asset" required:"true" format:"asset"`
+		AmountBase      string `json:"amount" flag:"amount" format:"base-units"`
+		AmountDecimal   string `json:"amount_decimal" flag:"amount-decimal" format:"decimal-amount"`
+		BridgeProviders string `json:"bridge_providers" flag:"bridge-providers"`
+		SwapProviders   string `json:"swap_providers" flag:"swap-providers"`
+		WalletRef       string `json:"wallet" flag:"wallet" format:"identifier"`
+		FromAddress     string `json:"from_address" flag:"from-address" format:"evm-address"`
+		Recipient       string `json:"recipient" flag:"recipient" format:"address"`
+		SlippageBps     int64  `json:"slippage_bps" flag:"slippage-bps"`
+		Simulate        bool   `json:"simulate" flag:"simulate"`
+		Preview         bool   `json:"preview" flag:"preview"`
+		RPCURL          string `json:"rpc_url" flag:"rpc-url" format:"url"`
+		ToRPCURL        string `json:"to_rpc_url" flag:"to-rpc-url" format:"url"`
+	}
+	type routeSubmitArgs struct {
+		ActionID           string  `json:"action_id" flag:"action-id" required:"true" format:"action-id"`
+		Simulate           bool    `json:"simulate" flag:"simulate"`
+		Signer             string  `json:"signer" flag:"signer" enum:"local,tempo"`
+		KeySource          string  `json:"key_source" flag:"key-source" enum:"auto,env,file,keystore"`
+		PrivateKey         string  `json:"private_key" flag:"private-key" format:"hex"`
+		FromAddress        string  `json:"from_address" flag:"from-address" format:"evm-address"`
+		PollInterval       string  `json:"poll_interval" flag:"poll-interval" format:"duration"`
+		StepTimeout        string  `json:"step_timeout" flag:"step-timeout" format:"duration"`
+		GasMultiplier      float64 `json:"gas_multiplier" flag:"gas-multiplier"`
+		MaxFeeGwei         string  `json:"max_fee_gwei" flag:"max-fee-gwei"`
+		MaxPriorityFeeGwei string  `json:"max_priority_fee_gwei" flag:"max-priority-fee-gwei"`
+		AllowMaxApproval   bool    `json:"allow_max_approval" flag:"allow-max-approval"`
+		UnsafeProviderTx   bool    `json:"unsafe_provider_tx" flag:"unsafe-provider-tx"`
+		FeeToken           string  `json:"fee_token" flag:"fee-token" format:"evm-address"`
+	}
+
+	var plan routePlanArgs
+	planCmd := &cobra.Command{
+		Use:   "plan",
+		Short: "Pick the best route and persist it as one multi-leg action",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			identity, err := resolveExecutionIdentity(plan.WalletRef, plan.FromAddress, plan.FromArg)
+			if err != nil {
+				return err
+			}
+			req, err := parseRouteRequest(plan.FromArg, plan.ToArg, plan.AssetArg, plan.ToAssetArg, plan.AmountBase, plan.AmountDecimal, plan.ToRPCURL)
+			if err != nil {
+				return err
+			}
+			executableBridges := s.actionBuilderRegistry().BridgeExecutionProviderNames()
+			bridgeNames, err := s.selectRouteProviders("bridge", splitCSV(plan.BridgeProviders), executableBridges, func(name string) bool {
+				return slices.Contains(executableBridges, name)
+			})
+			if err != nil {
+				return err
+			}
+			swapNames, err := s.selectRouteProviders("swap", splitCSV(plan.SwapProviders), actionbuilder.RouteSwapProviders, func(name string) bool {
+				return slices.Contains(actionbuilder.RouteSwapProviders, name)
+			})
+			if err != nil {
+				return err
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), s.settings.Timeout)
+			defer cancel()
+			candidates, statuses, warnings, _, err := s.quoteRoutes(ctx, req, bridgeNames, swapNames)
+			if err != nil {
+				s.captureCommandDiagnostics(warnings, statuses, false)
+				return err
+			}
+			best := candidates[0]
+			bridgeReq := providers.BridgeQuoteRequest{
+				FromChain:       req.FromChain,
+				ToChain:         req.ToChain,
+				FromAsset:       req.FromAsset,
+				ToAsset:         best.BridgeAsset,
+				AmountBaseUnits: req.AmountBase,
+				AmountDecimal:   req.AmountDecimal,
+			}
+			start := time.Now()
+			action, err := s.actionBuilderRegistry().BuildRouteAction(ctx, actionbuilder.RouteRequest{
+				BridgeProvider:    best.BridgeProvider,
+				SwapProvider:      best.SwapProvider,
+				Bridge:            bridgeReq,
+				ToAsset:           req.ToAsset,
+				Sender:            identity.FromAddress,
+				Recipient:         plan.Recipient,
+				SlippageBps:       plan.SlippageBps,
+				Simulate:          plan.Simulate,
+				RPCURL:            plan.RPCURL,
+				DestinationRPCURL: plan.ToRPCURL,
+			})
+			statuses = append(statuses, model.ProviderStatus{Name: best.Quote.Route, Status: statusFromErr(err), LatencyMS: time.Since(start).Milliseconds()})
+			if err != nil {
+				s.captureCommandDiagnostics(warnings, statuses, false)
+				return err
+			}
+			action.Metadata["route_estimated_out"] = best.Quote.EstimatedOut.AmountBaseUnits
+			if best.Quote.NetOutUSD != 0 {
+				action.Metadata["route_net_out_usd"] = best.Quote.NetOutUSD
+			}
+			applyExecutionIdentityToAction(&action, identity)
+			if err := s.checkActionProtocolPolicy(action); err != nil {
+				return err
+			}
+			if err := s.ensureActionStore(); err != nil {
+				return err
+			}
+			recordPlanInvocation(cmd, &action)
+			if err := s.actionStore.Save(action); err != nil {
+				return clierr.Wrap(clierr.CodeInternal, "persist planned action", err)
+			}
+			warnings = append(warnings, identity.Warnings...)
+			s.captureCommandDiagnostics(warnings, statuses, false)
+			attachPlanPreview(&action, plan.Preview)
+			return s.emitSuccess(trimRootPath(cmd.CommandPath()), action, warnings, cacheMetaBypass(), statuses, false)
+		},
+	}
+	planCmd.Flags().StringVar(&plan.FromArg, "from", "", "Source chain")
+	planCmd.Flags().StringVar(&plan.ToArg, "to", "", "Destination chain")
+	planCmd.Flags().StringVar(&plan.AssetArg, "asset", "", "Asset on source chain")
+	planCmd.Flags().StringVar(&plan.ToAssetArg, "to-asset", "", "Asset to receive on destination chain")
+	planCmd.Flags().StringVar(&plan.AmountBase, "amount", "", "Amount in base units")
+	planCmd.Flags().StringVar(&plan.AmountDecimal, "amount-decimal", "", "Amount in decimal units")
+	planCmd.Flags().StringVar(&plan.BridgeProviders, "bridge-providers", "", "Bridge providers to consider (comma-separated; defaults to execution-capable bridges)")
+	planCmd.Flags().StringVar(&plan.SwapProviders, "swap-providers", "", "Destination swap providers to consider (odos,paraswap,taikoswap)")
+	planCmd.Flags().StringVar(&plan.WalletRef, "wallet", "", "Wallet identifier or name")
+	planCmd.Flags().StringVar(&plan.FromAddress, "from-address", "", "Sender EOA address")
+	planCmd.Flags().StringVar(&plan.Recipient, "recipient", "", "Final recipient address (defaults to the resolved sender address)")
+	planCmd.Flags().Int64Var(&plan.SlippageBps, "slippage-bps", 50, "Max slippage in basis points, applied to each leg")
+	planCmd.Flags().BoolVar(&plan.Simulate, "simulate", true, "Include simulation checks during execution")
+	planCmd.Flags().BoolVar(&plan.Preview, "preview", false, "Decode step calldata and attach a human-readable preview")
+	planCmd.Flags().StringVar(&plan.RPCURL, "rpc-url", "", "RPC URL override for source chain")
+	planCmd.Flags().StringVar(&plan.ToRPCURL, "to-rpc-url", "", "RPC URL override for destination chain swap leg")
+	_ = planCmd.MarkFlagRequired("from")
+	_ = planCmd.MarkFlagRequired("to")
+	_ = planCmd.MarkFlagRequired("asset")
+	_ = planCmd.MarkFlagRequired("to-asset")
+	configureStructuredInput[routePlanArgs](planCmd, structuredInputOptions{
+		Mutation:         true,
+		InputConstraints: standardExecutionIdentityInputConstraints(),
+	})
+
+	var submit routeSubmitArgs
+	submitCmd := &cobra.Command{
+		Use:   "submit",
+		Short: "Execute an existing route action leg by leg",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			actionID, err := resolveActionID(submit.ActionID)
+			if err != nil {
+				return err
+			}
+			if err := s.ensureActionStore(); err != nil {
+				return err
+			}
+			action, err := s.actionStore.Get(actionID)
+			if err != nil {
+				return clierr.Wrap(clierr.CodeUsage, "load action", err)
+			}
+			if action.IntentType != "route" {
+				return clierr.New(clierr.CodeUsage, "action is not a route intent")
+			}
+			if action.Status == execution.ActionStatusCompleted {
+				return s.emitSuccess(trimRootPath(cmd.CommandPath()), action, []string{"action already completed"}, cacheMetaBypass(), nil, false)
+			}
+			resolvedExec, err := resolveActionExecutionBackend(cmd, action, submitExecutionInputs{
+				Signer:      submit.Signer,
+				KeySource:   submit.KeySource,
+				PrivateKey:  submit.PrivateKey,
+				FromAddress: submit.FromAddress,
+			})
+			if err != nil {
+				return err
+			}
+			if err := validateExecutionSender(action, submit.FromAddress, resolvedExec.sender); err != nil {
+				return err
+			}
+			execOpts, err := parseExecuteOptions(
+				submit.Simulate,
+				submit.PollInterval,
+				submit.StepTimeout,
+				submit.GasMultiplier,
+				submit.MaxFeeGwei,
+				submit.MaxPriorityFeeGwei,
+				submit.AllowMaxApproval,
+				submit.UnsafeProviderTx,
+				submit.FeeToken,
+			)
+			if err != nil {
+				return err
+			}
+			execOpts.PrepareStep = s.prepareRouteSwapLeg
+			if err := s.executeActionWithTimeout(&action, resolvedExec.txSigner, resolvedExec.evmBackend, execOpts); err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), s.settings.Timeout)
+			defer cancel()
+			warnings, err := s.verifyBridgeDelivery(ctx, &action)
+			if err != nil {
+				return err
+			}
+			return s.emitSuccess(trimRootPath(cmd.CommandPath()), action, warnings, cacheMetaBypass(), nil, false)
+		},
+	}
+	submitCmd.Flags().StringVar(&submit.ActionID, "action-id", "", "Action identifier returned by route plan")
+	submitCmd.Flags().BoolVar(&submit.Simulate, "simulate", true, "Run preflight simulation before submission")
+	submitCmd.Flags().StringVar(&submit.Signer, "signer", "local", "Signer backend (local|tempo)")
+	submitCmd.Flags().StringVar(&submit.KeySource, "key-source", execsigner.KeySourceAuto, "Key source (auto|env|file|keystore)")
+	submitCmd.Flags().StringVar(&submit.PrivateKey, "private-key", "", "Private key hex override for local signer (less safe)")
+	submitCmd.Flags().StringVar(&submit.FromAddress, "from-address", "", "Expected sender EOA address")
+	submitCmd.Flags().StringVar(&submit.PollInterval, "poll-interval", "2s", "Receipt polling interval")
+	submitCmd.Flags().StringVar(&submit.StepTimeout, "step-timeout", "2m", "Timeout per wait stage (receipt or bridge settlement polling)")
+	submitCmd.Flags().Float64Var(&submit.GasMultiplier, "gas-multiplier", 1.2, "Gas estimate safety multiplier")
+	submitCmd.Flags().StringVar(&submit.MaxFeeGwei, "max-fee-gwei", "", "Optional EIP-1559 max fee (gwei)")
+	submitCmd.Flags().StringVar(&submit.MaxPriorityFeeGwei, "max-priority-fee-gwei", "", "Optional EIP-1559 max priority fee (gwei)")
+	submitCmd.Flags().BoolVar(&submit.AllowMaxApproval, "allow-max-approval", false, "Allow approval amounts greater than each leg's planned input amount")
+	submitCmd.Flags().BoolVar(&submit.UnsafeProviderTx, "unsafe-provider-tx", false, "Bypass provider transaction guardrails for bridge/aggregator payloads")
+	submitCmd.Flags().StringVar(&submit.FeeToken, "fee-token", "", "Fee token address for Tempo chains (defaults to chain USDC.e)")
+	annotateStructuredSubmitCommand(submitCmd, routeSubmitArgs{})
+
+	var statusActionID string
+	statusCmd := &cobra.Command{
+		Use:   "status",
+		Short: "Get route action status",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			actionID, err := resolveActionID(statusActionID)
+			if err != nil {
+				return err
+			}
+			if err := s.ensureActionStore(); err != nil {
+				return err
+			}
+			action, err := s.actionStore.Get(actionID)
+			if err != nil {
+				return clierr.Wrap(clierr.CodeUsage, "load action", err)
+			}
+			if action.IntentType != "route" {
+				return clierr.New(clierr.CodeUsage, "action is not a route intent")
+			}
+			var warnings []string
+			ctx, cancel := context.WithTimeout(cmd.Context(), s.settings.Timeout)
+			defer cancel()
+			changed, err := execution.RefreshBridgeSettlement(ctx, &action)
+			if err != nil {
+				warnings = append(warnings, "settlement refresh failed: "+err.Error())
+			}
+			if changed {
+				action.Touch()
+				if err := s.actionStore.Save(action); err != nil {
+					return clierr.Wrap(clierr.CodeInternal, "persist action state", err)
+				}
+			}
+			deliveryWarnings, err := s.verifyBridgeDelivery(ctx, &action)
+			if err != nil {
+				return err
+			}
+			warnings = append(warnings, deliveryWarnings...)
+			return s.emitSuccess(trimRootPath(cmd.CommandPath()), action, warnings, cacheMetaBypass(), nil, false)
+		},
+	}
+	statusCmd.Flags().StringVar(&statusActionID, "action-id", "", "Action identifier returned by route plan")
+	annotateExecutionStatusCommand(statusCmd)
+
+	root.AddCommand(planCmd)
+	root.AddCommand(submitCmd)
+	root.AddCommand(statusCmd)
+}
+
+// prepareRouteSwapLeg re-quotes the swap leg right before its first step
+// runs, sized to what the bridge actually delivered (or its planned minimum
+// when the delivery cannot be read), so the swap is not built from a quote
+// that went stale while the bridge settled.
+func (s *runtimeState) prepareRouteSwapLeg(ctx context.Context, action *execution.Action, index int) error {
+	swapSteps := execution.RouteLegSteps(action, execution.RouteLegSwap)
+	if len(swapSteps) == 0 || swapSteps[0] != index {
+		return nil
+	}
+	for _, i := range swapSteps {
+		switch action.Steps[i].Status {
+		case execution.StepStatusSubmitted, execution.StepStatusConfirmed:
+			return nil
+		}
+	}
+	amount := ""
+	for _, i := range execution.RouteLegSteps(action, execution.RouteLegBridge) {
+		step := action.Steps[i]
+		if step.Status != execution.StepStatusConfirmed {
+			return clierr.New(clierr.CodeActionPlan, "route bridge leg has not completed")
+		}
+		if step.Type == execution.StepTypeBridge {
+			amount = strings.TrimSpace(step.ExpectedOutputs["to_amount_min"])
+		}
+	}
+	if _, err := execution.VerifyBridgeDelivery(ctx, action); err == nil {
+		if delivery := action.BridgeDelivery; delivery != nil && delivery.Status != execution.BridgeDeliveryUnverified && delivery.ReceivedBaseUnits != "" {
+			amount = delivery.ReceivedBaseUnits
+		}
+	}
+	if amount == "" {
+		return clierr.New(clierr.CodeActionPlan, "route bridge leg does not report an amount to swap")
+	}
+	return s.actionBuilderRegistry().RefreshRouteSwapLeg(ctx, action, amount)
+}
+
+func parseRouteRequest(fromArg, toArg, assetArg, toAssetArg, amountBase, amountDecimal, toRPCURL string) (routeRequest, error) {
+	fromChain, err := id.ParseChain(fromArg)
+	if err != nil {
+		return routeRequest{}, err
+	}
+	toChain, err := id.ParseChain(toArg)
+	if err != nil {
+		return routeRequest{}, err
+	}
+	fromAsset, err := id.ParseAsset(assetArg, fromChain)
+	if err != nil {
+		return routeRequest{}, err
+	}
+	toAsset, err := id.ParseAsset(toAssetArg, toChain)
+	if err != nil {
+		return routeRequest{}, err
+	}
+	decimals := fromAsset.Decimals
+	if decimals <= 0 {
+		decimals = 18
+	}
+	base, decimal, err := id.NormalizeAmount(amountBase, amountDecimal, decimals)
+	if err != nil {
+		return routeRequest{}, err
+	}
+	req := routeRequest{
+		FromChain:     fromChain,
+		ToChain:       toChain,
+		FromAsset:     fromAsset,
+		ToAsset:       toAsset,
+		AmountBase:    base,
+		AmountDecimal: decimal,
+		ToRPCURL:      strings.TrimSpace(toRPCURL),
+	}
+	if bridgeAsset, err := parseBridgeDestinationAsset(fromAsset, "", toChain); err == nil && !strings.EqualFold(bridgeAsset.AssetID, toAsset.AssetID) {
+		req.BridgeAsset = &bridgeAsset
+	}
+	return req, nil
+}
+
+// selectRouteProviders validates an explicit provider filter against valid,
+// or returns defaults minus providers blocked by protocol policy.
+func (s *runtimeState) selectRouteProviders(kind string, filter, defaults []string, valid func(string) bool) ([]string, error) {
+	rules := s.protocolRules()
+	if len(filter) == 0 {
+		out := make([]string, 0, len(defaults))
+		for _, name := range defaults {
+			if rules.Check(name) == nil {
+				out = append(out, name)
+			}
+		}
+		if len(out) == 0 {
+			return nil, clierr.New(clierr.CodeBlocked, fmt.Sprintf("every default route %s provider is excluded by protocol policy", kind))
+		}
+		return out, nil
+	}
+	out := make([]string, 0, len(filter))
+	for _, name := range filter {
+		name = strings.ToLower(name)
+		if !valid(name) {
+			return nil, clierr.New(clierr.CodeUnsupported, fmt.Sprintf("unsupported route %s provider %q", kind, name))
+		}
+		if err := rules.Check(name); err != nil {
+			return nil, err
+		}
+		if !slices.Contains(out, name) {
+			out = append(out, name)
+		}
+	}
+	return out, nil
+}
+
+// quoteRoutes quotes every direct bridge route and, when the source asset's
+// destination counterpart differs from the requested asset, every bridge+swap
+// pair. Candidates are returned ranked best first; failed legs become
+// warnings and mark the result partial.
+func (s *runtimeState) quoteRoutes(ctx context.Context, req routeRequest, bridgeNames, swapNames []string) ([]routeCandidate, []model.ProviderStatus, []string, bool, error) {
+	statuses := []model.ProviderStatus{}
+	warnings := []string{}
+	candidates := []routeCandidate{}
+	partial := false
+	var firstErr error
+	fail := func(route string, err error) {
+		warnings = append(warnings, fmt.Sprintf("route %s failed: %v", route, err))
+		partial = true
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	quoteBridge := func(name string, toAsset id.Asset) (model.BridgeQuote, error) {
+		provider := s.bridgeProviders[name]
+		start := time.Now()
+		quote, err := provider.QuoteBridge(ctx, providers.BridgeQuoteRequest{
+			FromChain:       req.FromChain,
+			ToChain:         req.ToChain,
+			FromAsset:       req.FromAsset,
+			ToAsset:         toAsset,
+			AmountBaseUnits: req.AmountBase,
+			AmountDecimal:   req.AmountDecimal,
+		})
+		statuses = append(statuses, model.ProviderStatus{Name: provider.Info().Name, Status: statusFromErr(err), LatencyMS: time.Since(start).Milliseconds()})
+		if err == nil {
+			err = s.protocolRules().CheckRoute(quote.Route)
+		}
+		return quote, err
+	}
+
+	for _, bridgeName := range bridgeNames {
+		direct, err := quoteBridge(bridgeName, req.ToAsset)
+		if err != nil {
+			fail(bridgeName, err)
+		} else {
+			candidates = append(candidates, routeCandidate{
+				Quote:          newRouteQuote(req, bridgeName, direct, "", nil),
+				BridgeProvider: bridgeName,
+				BridgeAsset:    req.ToAsset,
+			})
+		}
+		if req.BridgeAsset == nil || len(swapNames) == 0 {
+			continue
+		}
+		bridged, err := quoteBridge(bridgeName, *req.BridgeAsset)
+		if err != nil {
+			fail(bridgeName+">*", err)
+			continue
+		}
+		for _, swapName := range swapNames {
+			provider := s.swapProviders[swapName]
+			start := time.Now()
+			swap, err := provider.QuoteSwap(ctx, providers.SwapQuoteRequest{
+				Chain:           req.ToChain,
+				FromAsset:       *req.BridgeAsset,
+				ToAsset:         req.ToAsset,
+				AmountBaseUnits: bridged.EstimatedOut.AmountBaseUnits,
+				AmountDecimal:   bridged.EstimatedOut.AmountDecimal,
+				RPCURL:          req.ToRPCURL,
+				TradeType:       providers.SwapTradeTypeExactInput,
+			})
+			statuses = append(statuses, model.ProviderStatus{Name: provider.Info().Name, Status: statusFromErr(err), LatencyMS: time.Since(start).Milliseconds()})
+			if err == nil {
+				err = s.protocolRules().CheckRoute(swap.Route)
+			}
+			if err != nil {
+				fail(bridgeName+">"+swapName, err)
+				continue
+			}
+			candidates = append(candidates, routeCandidate{
+				Quote:          newRouteQuote(req, bridgeName, bridged, swapName, &swap),
+				BridgeProvider: bridgeName,
+				SwapProvider:   swapName,
+				BridgeAsset:    *req.BridgeAsset,
+			})
+		}
+	}
+	if len(candidates) == 0 {
+		if firstErr == nil {
+			firstErr = clierr.New(clierr.CodeUnavailable, "no route candidates for the requested providers")
+		}
+		return nil, statuses, warnings, partial, firstErr
+	}
+
+	price, err := s.routeOutputPriceUSD(ctx, req)
+	if err != nil {
+		warnings = append(warnings, fmt.Sprintf("output price unavailable, routes ranked by estimated output: %v", err))
+	}
+	for i := range candidates {
+		applyRoutePrice(&candidates[i].Quote, price)
+	}
+	rankRouteCandidates(candidates, price > 0)
+	return candidates, statuses, warnings, partial, nil
+}
+
+// routeOutputPriceUSD is the latest hourly USD price of the destination
+// asset over the past day.
+func (s *runtimeState) routeOutputPriceUSD(ctx context.Context, req routeRequest) (float64, error) {
+	provider, err := s.marketHistoryProvider()
+	if err != nil {
+		return 0, err
+	}
+	end := s.runner.now().UTC()
+	series, err := provider.PriceHistory(ctx, providers.PriceHistoryRequest{
+		Chain:     req.ToChain,
+		Asset:     req.ToAsset,
+		StartTime: end.Add(-24 * time.Hour),
+		EndTime:   end,
+		Interval:  providers.YieldHistoryIntervalHour,
+	})
+	if err != nil {
+		return 0, err
+	}
+	for i := len(series.Points) - 1; i >= 0; i-- {
+		if series.Points[i].Value > 0 {
+			return series.Points[i].Value, nil
+		}
+	}
+	return 0, clierr.New(clierr.CodeUnavailable, "no price points returned for "+req.ToAsset.AssetID)
+}
+
+func newRouteQuote(req routeRequest, bridgeProvider string, bridge model.BridgeQuote, swapProvider string, swap *model.SwapQuote) model.RouteQuote {
+	quote := model.RouteQuote{
+		Route:          bridgeProvider,
+		FromChainID:    req.FromChain.CAIP2,
+		ToChainID:      req.ToChain.CAIP2,
+		FromAssetID:    req.FromAsset.AssetID,
+		ToAssetID:      req.ToAsset.AssetID,
+		InputAmount:    bridge.InputAmount,
+		EstimatedOut:   bridge.EstimatedOut,
+		BridgeFeeUSD:   bridge.EstimatedFeeUSD,
+		EstimatedTimeS: bridge.EstimatedTimeS,
+		Legs: []model.RouteLeg{{
+			Type:           execution.RouteLegBridge,
+			Provider:       bridgeProvider,
+			FromChainID:    bridge.FromChainID,
+			ToChainID:      bridge.ToChainID,
+			FromAssetID:    bridge.FromAssetID,
+			ToAssetID:      bridge.ToAssetID,
+			InputAmount:    bridge.InputAmount,
+			EstimatedOut:   bridge.EstimatedOut,
+			FeeUSD:         bridge.EstimatedFeeUSD,
+			EstimatedTimeS: bridge.EstimatedTimeS,
+		}},
+	}
+	if swap == nil {
+		return quote
+	}
+	quote.Route = bridgeProvider + ">" + swapProvider
+	quote.EstimatedOut = swap.EstimatedOut
+	quote.GasUSD = swap.EstimatedGasUSD
+	quote.Legs = append(quote.Legs, model.RouteLeg{
+		Type:         execution.RouteLegSwap,
+		Provider:     swapProvider,
+		FromChainID:  swap.ChainID,
+		ToChainID:    swap.ChainID,
+		FromAssetID:  swap.FromAssetID,
+		ToAssetID:    swap.ToAssetID,
+		InputAmount:  swap.InputAmount,
+		EstimatedOut: swap.EstimatedOut,
+		GasUSD:       swap.EstimatedGasUSD,
+	})
+	return quote
+}
+
+func applyRoutePrice(quote *model.RouteQuote, priceUSD float64) {
+	if priceUSD <= 0 {
+		return
+	}
+	quote.OutputPriceUSD = priceUSD
+	quote.EstimatedOutUSD = routeOutDecimal(*quote) * priceUSD
+	quote.NetOutUSD = quote.EstimatedOutUSD - quote.GasUSD
+}
+
+// rankRouteCandidates orders candidates by net USD output when priced,
+// otherwise by estimated output. All candidates end in the same asset, so
+// decimal outputs are directly comparable.
+func rankRouteCandidates(candidates []routeCandidate, priced bool) {
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i].Quote, candidates[j].Quote
+		if priced {
+			return a.NetOutUSD > b.NetOutUSD
+		}
+		return routeOutDecimal(a) > routeOutDecimal(b)
+	})
+}
+
+func rankedRouteComparison(candidates []routeCandidate) model.RouteComparison {
+	out := model.RouteComparison{Routes: make([]model.RouteQuote, 0, len(candidates)), RankedBy: "estimated_out"}
+	for _, candidate := range candidates {
+		out.Routes = append(out.Routes, candidate.Quote)
+	}
+	if len(out.Routes) > 0 {
+		out.BestRoute = out.Routes[0].Route
+		if out.Routes[0].OutputPriceUSD > 0 {
+			out.RankedBy = "net_out_usd"
+		}
+	}
+	return out
+}
+
+func routeOutDecimal(quote model.RouteQuote) float64 {
+	v, _ := strconv.ParseFloat(quote.EstimatedOut.AmountDecimal, 64)
+	return v
+}
@@ -0,0 +1,254 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ggonzalez94/defi-cli/internal/config"
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/fixtures"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/model"
+	"github.com/ggonzalez94/defi-cli/internal/registry"
+	"github.com/ggonzalez94/defi-cli/internal/rpcx"
+	"github.com/ggonzalez94/defi-cli/internal/schema"
+	"github.com/spf13/cobra"
+)
+
+// maxParallelRPCProbes bounds concurrent endpoint checks in `rpc test`.
+const maxParallelRPCProbes = 8
+
+func (s *runtimeState) newRPCCommand() *cobra.Command {
+	root := &cobra.Command{Use: "rpc", Short: "Manage per-chain RPC endpoints"}
+
+	var listChainArg string
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List RPC endpoints per chain in selection order with observed health",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			chainIDs, err := rpcChainIDs(listChainArg)
+			if err != nil {
+				return err
+			}
+			endpoints := []model.RPCEndpoint{}
+			for _, chainID := range chainIDs {
+				endpoints = append(endpoints, rpcEndpointRows(chainID, s.runner.now())...)
+			}
+			return s.emitSuccess(trimRootPath(cmd.CommandPath()), endpoints, nil, cacheMetaBypass(), nil, false)
+		},
+	}
+	listCmd.Flags().StringVar(&listChainArg, "chain", "", "Only list endpoints for this chain")
+	_ = schema.SetFlagMetadata(listCmd.Flags(), "chain", schema.FlagMetadata{Format: "chain"})
+	listResponse := schema.SchemaFromType([]model.RPCEndpoint{})
+	_ = schema.SetCommandMetadata(listCmd, schema.CommandMetadata{Response: &listResponse})
+
+	var addChainArg, addURLArg string
+	var addPrimary bool
+	addCmd := &cobra.Command{
+		Use:   "add",
+		Short: "Add an RPC endpoint for a chain to the config file",
+		Long:  "Adds the endpoint to the rpc block of the active profile, or the top-level rpc block when no profile is active. Endpoints are appended as fallbacks unless --primary is set.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if strings.TrimSpace(addChainArg) == "" {
+				return clierr.New(clierr.CodeUsage, "--chain is required")
+			}
+			chain, err := id.ParseChain(addChainArg)
+			if err != nil {
+				return err
+			}
+			if !chain.IsEVM() {
+				return clierr.New(clierr.CodeUnsupported, "rpc endpoints are only configurable for EVM chains")
+			}
+			rawURL := strings.TrimSpace(addURLArg)
+			if parsed, err := url.Parse(rawURL); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https" && parsed.Scheme != "ws" && parsed.Scheme != "wss") || parsed.Host == "" {
+				return clierr.New(clierr.CodeUsage, "--url must be an http(s) or ws(s) URL")
+			}
+			sameChain := func(key string) bool {
+				other, err := id.ParseChain(key)
+				return err == nil && other.CAIP2 == chain.CAIP2
+			}
+			target, err := config.AddRPCURL(s.flags, chain.Slug, sameChain, rawURL, addPrimary)
+			if err != nil {
+				return clierr.Wrap(clierr.CodeUsage, "add rpc endpoint", err)
+			}
+
+			configured := s.settings.RPCURLs
+			if configured == nil {
+				configured = map[string][]string{}
+			}
+			configured[target.Key] = target.URLs
+			rpcURLs, err := configuredRPCURLs(configured)
+			if err != nil {
+				return err
+			}
+			registry.SetConfiguredRPCURLs(rpcURLs)
+
+			var warnings []string
+			if target.Profile != "" {
+				warnings = append(warnings, fmt.Sprintf("added to profile %s in %s", target.Profile, target.ConfigPath))
+			}
+			return s.emitSuccess(trimRootPath(cmd.CommandPath()), rpcEndpointRows(chain.EVMChainID, s.runner.now()), warnings, cacheMetaBypass(), nil, false)
+		},
+	}
+	addCmd.Flags().StringVar(&addChainArg, "chain", "", "Chain identifier (CAIP-2, chain ID, or slug)")
+	addCmd.Flags().StringVar(&addURLArg, "url", "", "RPC endpoint URL")
+	addCmd.Flags().BoolVar(&addPrimary, "primary", false, "Put the endpoint first instead of appending it as a fallback")
+	_ = schema.SetFlagMetadata(addCmd.Flags(), "chain", schema.FlagMetadata{Required: true, Format: "chain"})
+	_ = schema.SetFlagMetadata(addCmd.Flags(), "url", schema.FlagMetadata{Required: true, Format: "url"})
+	addResponse := schema.SchemaFromType([]model.RPCEndpoint{})
+	_ = schema.SetCommandMetadata(addCmd, schema.CommandMetadata{Mutation: true, Response: &addResponse})
+
+	var testChainArg string
+	testCmd := &cobra.Command{
+		Use:   "test",
+		Short: "Check RPC endpoints for chain ID, head block, and latency",
+		Long:  "Calls eth_chainId and eth_blockNumber on every endpoint directly, without failover. Results update the health used to pick the primary endpoint.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			chainIDs, err := rpcChainIDs(testChainArg)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), s.settings.Timeout)
+			defer cancel()
+
+			type job struct {
+				chainID  int64
+				endpoint string
+				index    int
+			}
+			jobs := []job{}
+			for _, chainID := range chainIDs {
+				for _, endpoint := range registry.RPCEndpoints(chainID) {
+					jobs = append(jobs, job{chainID: chainID, endpoint: endpoint, index: len(jobs)})
+				}
+			}
+			probes := make([]model.RPCProbe, len(jobs))
+			sem := make(chan struct{}, maxParallelRPCProbes)
+			var wg sync.WaitGroup
+			for _, j := range jobs {
+				wg.Add(1)
+				go func(j job) {
+					defer wg.Done()
+					sem <- struct{}{}
+					defer func() { <-sem }()
+					probe := rpcx.ProbeEndpoint(ctx, j.endpoint, j.chainID)
+					chainID, chainName := rpcChainLabel(j.chainID)
+					row := model.RPCProbe{
+						ChainID:     chainID,
+						Chain:       chainName,
+						URL:         rpcx.Redact(j.endpoint),
+						Source:      rpcEndpointSource(j.chainID, j.endpoint),
+						OK:          probe.Err == nil,
+						LatencyMS:   probe.Latency.Milliseconds(),
+						BlockNumber: probe.BlockNumber,
+					}
+					if probe.Err != nil {
+						row.Error = probe.Err.Error()
+					}
+					probes[j.index] = row
+				}(j)
+			}
+			wg.Wait()
+
+			var warnings []string
+			failed := 0
+			for _, probe := range probes {
+				if !probe.OK {
+					failed++
+				}
+			}
+			if failed > 0 {
+				warnings = append(warnings, fmt.Sprintf("%d of %d endpoints failed", failed, len(probes)))
+			}
+			return s.emitSuccess(trimRootPath(cmd.CommandPath()), probes, warnings, cacheMetaBypass(), nil, failed > 0)
+		},
+	}
+	testCmd.Flags().StringVar(&testChainArg, "chain", "", "Only test endpoints for this chain (default: every chain)")
+	_ = schema.SetFlagMetadata(testCmd.Flags(), "chain", schema.FlagMetadata{Format: "chain"})
+	testResponse := schema.SchemaFromType([]model.RPCProbe{})
+	_ = schema.SetCommandMetadata(testCmd, schema.CommandMetadata{Response: &testResponse})
+
+	root.AddCommand(listCmd)
+	root.AddCommand(addCmd)
+	root.AddCommand(testCmd)
+	return root
+}
+
+// rpcChainIDs returns the chain selected by chainArg, or every chain with an
+// endpoint when chainArg is empty.
+func rpcChainIDs(chainArg string) ([]int64, error) {
+	if strings.TrimSpace(chainArg) == "" {
+		return registry.RPCChainIDs(), nil
+	}
+	chain, err := id.ParseChain(chainArg)
+	if err != nil {
+		return nil, err
+	}
+	if !chain.IsEVM() {
+		return nil, clierr.New(clierr.CodeUnsupported, "rpc endpoints are only tracked for EVM chains")
+	}
+	if len(registry.RPCEndpoints(chain.EVMChainID)) == 0 {
+		return nil, clierr.New(clierr.CodeUnsupported, fmt.Sprintf("no rpc endpoints registered for %s; add one with `rpc add`", chain.Slug))
+	}
+	return []int64{chain.EVMChainID}, nil
+}
+
+func rpcEndpointRows(chainID int64, now time.Time) []model.RPCEndpoint {
+	caip2, chainName := rpcChainLabel(chainID)
+	ordered := rpcx.Endpoints(chainID)
+	rows := make([]model.RPCEndpoint, 0, len(ordered))
+	for i, endpoint := range ordered {
+		row := model.RPCEndpoint{
+			ChainID: caip2,
+			Chain:   chainName,
+			URL:     rpcx.Redact(endpoint),
+			Source:  rpcEndpointSource(chainID, endpoint),
+			Rank:    i + 1,
+			Primary: i == 0,
+		}
+		if h, ok := rpcx.HealthOf(endpoint); ok {
+			row.LatencyMS = h.LatencyMS
+			row.Failures = h.Failures
+			row.LastError = h.LastError
+			row.CheckedAt = h.CheckedAt.UTC().Format(time.RFC3339)
+			row.CoolingDown = h.CooldownUntil.After(now)
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func rpcEndpointSource(chainID int64, endpoint string) string {
+	if slices.Contains(registry.ConfiguredRPCURLs(chainID), endpoint) {
+		return "config"
+	}
+	if slices.Contains(registry.SyncedRPCURLs(chainID), endpoint) {
+		return "chainlist"
+	}
+	return "default"
+}
+
+func rpcChainLabel(chainID int64) (string, string) {
+	chain, err := id.ParseChain(strconv.FormatInt(chainID, 10))
+	if err != nil {
+		return fmt.Sprintf("eip155:%d", chainID), ""
+	}
+	return chain.CAIP2, chain.Slug
+}
+
+// rpcHealthPath keeps endpoint health next to the cache database. Fixture and
+// offline runs neither read nor write it: fixture runs try endpoints in
+// configured order, and offline runs observe no endpoint health.
+func (s *runtimeState) rpcHealthPath() string {
+	if strings.TrimSpace(s.settings.CachePath) == "" || fixtures.Active() != nil || s.settings.Offline {
+		return ""
+	}
+	return filepath.Join(filepath.Dir(s.settings.CachePath), "rpc-health.json")
+}
@@ -0,0 +1,78 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ggonzalez94/defi-cli/internal/registry"
+)
+
+func TestRPCAddAndList(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.yaml")
+	t.Setenv("DEFI_CACHE_PATH", filepath.Join(dir, "cache.db"))
+	t.Cleanup(func() { registry.SetConfiguredRPCURLs(nil) })
+
+	var stdout, stderr bytes.Buffer
+	args := []string{"rpc", "add", "--chain", "base", "--url", "https://base.example/rpc", "--config", configPath, "--results-only"}
+	if code := NewRunnerWithWriters(&stdout, &stderr).Run(args); code != 0 {
+		t.Fatalf("add failed: code=%d stderr=%s", code, stderr.String())
+	}
+	args = []string{"rpc", "add", "--chain", "8453", "--url", "https://primary.example/rpc", "--primary", "--config", configPath, "--results-only"}
+	if code := NewRunnerWithWriters(&stdout, &stderr).Run(args); code != 0 {
+		t.Fatalf("add primary failed: code=%d stderr=%s", code, stderr.String())
+	}
+	buf, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(buf), "base:\n    - https://primary.example/rpc\n    - https://base.example/rpc\n") {
+		t.Fatalf("unexpected config file:\n%s", buf)
+	}
+
+	stdout.Reset()
+	if code := NewRunnerWithWriters(&stdout, &stderr).Run([]string{"rpc", "list", "--chain", "base", "--config", configPath, "--results-only"}); code != 0 {
+		t.Fatalf("list failed: code=%d stderr=%s", code, stderr.String())
+	}
+	var rows []struct {
+		ChainID string `json:"chain_id"`
+		URL     string `json:"url"`
+		Source  string `json:"source"`
+		Rank    int    `json:"rank"`
+		Primary bool   `json:"primary"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &rows); err != nil {
+		t.Fatalf("decode output: %v (%s)", err, stdout.String())
+	}
+	if len(rows) != 3 {
+		t.Fatalf("expected two configured endpoints plus the default, got %+v", rows)
+	}
+	if rows[0].URL != "https://primary.example/rpc" || !rows[0].Primary || rows[0].Source != "config" || rows[0].ChainID != "eip155:8453" {
+		t.Fatalf("unexpected primary endpoint %+v", rows[0])
+	}
+	if rows[2].Source != "default" || rows[2].Rank != 3 {
+		t.Fatalf("expected built-in endpoint last, got %+v", rows[2])
+	}
+}
+
+func TestRPCAddRejectsInvalidInput(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	t.Cleanup(func() { registry.SetConfiguredRPCURLs(nil) })
+	cases := [][]string{
+		{"rpc", "add", "--chain", "base", "--url", "not-a-url", "--config", configPath},
+		{"rpc", "add", "--chain", "solana", "--url", "https://api.mainnet-beta.solana.com", "--config", configPath},
+	}
+	for _, args := range cases {
+		var stdout, stderr bytes.Buffer
+		if code := NewRunnerWithWriters(&stdout, &stderr).Run(args); code == 0 {
+			t.Fatalf("expected %v to fail", args)
+		}
+	}
+	if _, err := os.Stat(configPath); !os.IsNotExist(err) {
+		t.Fatalf("expected config file to stay untouched, got %v", err)
+	}
+}
@@ -2,49 +2,81 @@ package app
 
 import (
 	"context"
-	"crypto/rand"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"math/big"
 	"os"
+	"path/filepath"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ggonzalez94/defi-cli/internal/breaker"
+	"github.com/ggonzalez94/defi-cli/internal/bridgesafety"
 	"github.com/ggonzalez94/defi-cli/internal/cache"
+	"github.com/ggonzalez94/defi-cli/internal/chainlist"
+	"github.com/ggonzalez94/defi-cli/internal/clock"
 	"github.com/ggonzalez94/defi-cli/internal/config"
 	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
 	"github.com/ggonzalez94/defi-cli/internal/execution"
 	"github.com/ggonzalez94/defi-cli/internal/execution/actionbuilder"
 	execsigner "github.com/ggonzalez94/defi-cli/internal/execution/signer"
+	"github.com/ggonzalez94/defi-cli/internal/fixtures"
 	"github.com/ggonzalez94/defi-cli/internal/httpx"
+	"github.com/ggonzalez94/defi-cli/internal/i18n"
 	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/logx"
 	"github.com/ggonzalez94/defi-cli/internal/model"
 	"github.com/ggonzalez94/defi-cli/internal/out"
 	"github.com/ggonzalez94/defi-cli/internal/policy"
 	"github.com/ggonzalez94/defi-cli/internal/providers"
 	"github.com/ggonzalez94/defi-cli/internal/providers/aave"
 	"github.com/ggonzalez94/defi-cli/internal/providers/across"
+	"github.com/ggonzalez94/defi-cli/internal/providers/aerodrome"
+	"github.com/ggonzalez94/defi-cli/internal/providers/aevo"
 	"github.com/ggonzalez94/defi-cli/internal/providers/bungee"
+	"github.com/ggonzalez94/defi-cli/internal/providers/cctp"
+	"github.com/ggonzalez94/defi-cli/internal/providers/cowswap"
 	"github.com/ggonzalez94/defi-cli/internal/providers/defillama"
+	"github.com/ggonzalez94/defi-cli/internal/providers/dydx"
+	"github.com/ggonzalez94/defi-cli/internal/providers/etherscan"
 	"github.com/ggonzalez94/defi-cli/internal/providers/fibrous"
+	"github.com/ggonzalez94/defi-cli/internal/providers/governance"
+	"github.com/ggonzalez94/defi-cli/internal/providers/hyperliquid"
 	"github.com/ggonzalez94/defi-cli/internal/providers/jupiter"
 	"github.com/ggonzalez94/defi-cli/internal/providers/kamino"
 	"github.com/ggonzalez94/defi-cli/internal/providers/lifi"
 	"github.com/ggonzalez94/defi-cli/internal/providers/moonwell"
 	"github.com/ggonzalez94/defi-cli/internal/providers/morpho"
+	"github.com/ggonzalez94/defi-cli/internal/providers/odos"
+	"github.com/ggonzalez94/defi-cli/internal/providers/onchainlend"
 	"github.com/ggonzalez94/defi-cli/internal/providers/oneinch"
+	"github.com/ggonzalez94/defi-cli/internal/providers/paraswap"
+	"github.com/ggonzalez94/defi-cli/internal/providers/spark"
+	"github.com/ggonzalez94/defi-cli/internal/providers/staking"
 	"github.com/ggonzalez94/defi-cli/internal/providers/taikoswap"
 	"github.com/ggonzalez94/defi-cli/internal/providers/tempo"
 	"github.com/ggonzalez94/defi-cli/internal/providers/uniswap"
+	"github.com/ggonzalez94/defi-cli/internal/providers/wormhole"
+	"github.com/ggonzalez94/defi-cli/internal/providers/yieldutil"
+	"github.com/ggonzalez94/defi-cli/internal/quotes"
+	"github.com/ggonzalez94/defi-cli/internal/randx"
+	"github.com/ggonzalez94/defi-cli/internal/ratebudget"
 	"github.com/ggonzalez94/defi-cli/internal/registry"
+	"github.com/ggonzalez94/defi-cli/internal/rpcx"
 	"github.com/ggonzalez94/defi-cli/internal/schema"
+	"github.com/ggonzalez94/defi-cli/internal/tokenlist"
+	"github.com/ggonzalez94/defi-cli/internal/tokenlookup"
+	"github.com/ggonzalez94/defi-cli/internal/tracex"
 	"github.com/ggonzalez94/defi-cli/internal/version"
 	"github.com/spf13/cobra"
 )
@@ -53,6 +85,9 @@ type Runner struct {
 	stdout io.Writer
 	stderr io.Writer
 	now    func() time.Time
+	// parent is the invocation this run executes inside, for batch
+	// requests. See runtimeState.inheritProcess.
+	parent *runtimeState
 }
 
 func NewRunner() *Runner {
@@ -63,7 +98,7 @@ func NewRunnerWithWriters(stdout, stderr io.Writer) *Runner {
 	return &Runner{
 		stdout: stdout,
 		stderr: stderr,
-		now:    time.Now,
+		now:    clock.Now,
 	}
 }
 
@@ -73,6 +108,7 @@ type runtimeState struct {
 	settings      config.Settings
 	cache         *cache.Store
 	actionStore   *execution.Store
+	quoteStore    *quotes.Store
 	actionBuilder *actionbuilder.Registry
 	root          *cobra.Command
 	lastCommand   string
@@ -82,45 +118,101 @@ type runtimeState struct {
 
 	marketProvider      providers.MarketDataProvider
 	lendingProviders    map[string]providers.LendingProvider
+	lendRatesFallback   onchainLendRatesProvider
+	subgraphLenders     map[string]providers.LendingProvider
 	yieldProviders      map[string]providers.YieldProvider
+	spotPriceProviders  map[string]providers.PriceHistoryProvider
+	rewardsProviders    map[string]providers.RewardsProvider
 	bridgeProviders     map[string]providers.BridgeProvider
 	bridgeDataProviders map[string]providers.BridgeDataProvider
 	swapProviders       map[string]providers.SwapProvider
-	providerInfos       []model.ProviderInfo
+	stakingProvider     providers.StakingProvider
+	perpsProviders      map[string]providers.PerpsProvider
+	optionsProviders    map[string]providers.OptionsProvider
+	poolsProviders      map[string]providers.PoolsProvider
+	activityProvider    providers.ActivityProvider
+	// contractSourceProvider is the explorer used by assets inspect.
+	contractSourceProvider providers.ContractSourceProvider
+	governanceProvider     providers.GovernanceProvider
+	providerInfos          []model.ProviderInfo
+
+	nameResolver  func(context.Context, string) (model.ResolvedName, error)
+	resolvedNames []model.ResolvedName
+	userTokens    tokenlist.List
+	tokenLookup   *tokenlookup.Resolver
+
+	// listPage is the page a paged list command emits (see pagination.go).
+	listPage *listPage
+
+	syncedChains      chainlist.Registry
+	chainSyncWarnings []string
+	// notifyWarnings are webhook delivery failures (see notify.go).
+	notifyWarnings notifyWarnings
+
+	logFile *os.File
+	trace   *tracex.Recorder
 }
 
-const cachePayloadSchemaVersion = "v2"
+const cachePayloadSchemaVersion = "v3"
 
 func (r *Runner) Run(args []string) int {
 	state := &runtimeState{runner: r}
 	root := state.newRootCommand()
 	state.root = root
 	state.resetCommandDiagnostics()
+	if len(args) > 0 && (args[0] == cobra.ShellCompRequestCmd || args[0] == cobra.ShellCompNoDescRequestCmd) {
+		// Cobra keeps flag completions in a package-level table, so they are
+		// only registered for the shell's completion requests.
+		state.registerCompletions(root)
+	}
 	root.SetArgs(args)
 	root.SetOut(r.stdout)
 	root.SetErr(r.stderr)
 	root.SilenceUsage = true
 	root.SilenceErrors = true
+	defer state.closeLogging(logx.L())
+	defer tracex.Set(tracex.Active())
+	defer cache.SetShared(cache.ActiveShared())
+	defer httpx.SetOffline(httpx.Offline())
+	if r.parent == nil {
+		defer resetFixtures()
+	}
 
 	err := root.Execute()
-	err = normalizeRunError(err)
+	err = state.offlineRunError(normalizeRunError(err))
+	if err != nil {
+		logx.L().Debug("command failed", "command", state.lastCommand, "exit_code", clierr.ExitCode(err), "error", err)
+	} else {
+		logx.L().Debug("command finished", "command", state.lastCommand)
+	}
+	if r.parent == nil {
+		state.saveHealth()
+	}
 	if err == nil {
+		state.waitSharedRefresh()
 		if state.cache != nil {
 			_ = state.cache.Close()
 		}
 		if state.actionStore != nil {
 			_ = state.actionStore.Close()
 		}
+		if state.quoteStore != nil {
+			_ = state.quoteStore.Close()
+		}
 		return 0
 	}
 
 	state.renderError("", err, state.lastWarnings, state.lastProviders, state.lastPartial)
+	state.waitSharedRefresh()
 	if state.cache != nil {
 		_ = state.cache.Close()
 	}
 	if state.actionStore != nil {
 		_ = state.actionStore.Close()
 	}
+	if state.quoteStore != nil {
+		_ = state.quoteStore.Close()
+	}
 	return clierr.ExitCode(err)
 }
 
@@ -142,6 +234,12 @@ func (s *runtimeState) newRootCommand() *cobra.Command {
 				return clierr.Wrap(clierr.CodeUsage, "load configuration", err)
 			}
 			s.settings = settings
+			if parent := s.runner.parent; parent != nil {
+				s.inheritProcess(parent)
+			} else if err := s.configureProcess(); err != nil {
+				return err
+			}
+			logx.L().Debug("command started", "command", trimRootPath(cmd.CommandPath()), "output", settings.OutputMode, "cache", settings.CacheEnabled, "timeout", settings.Timeout.String(), "retries", settings.Retries)
 
 			path := trimRootPath(cmd.CommandPath())
 			s.lastCommand = path
@@ -149,46 +247,95 @@ func (s *runtimeState) newRootCommand() *cobra.Command {
 				return err
 			}
 
+			httpx.SetMaxConnsPerHost(settings.MaxConnsPerHost)
 			if s.marketProvider == nil {
 				httpClient := httpx.New(settings.Timeout, settings.Retries)
-				llama := defillama.New(httpClient, settings.DefiLlamaAPIKey)
-				aaveProvider := aave.New(httpClient)
-				morphoProvider := morpho.New(httpClient)
-				kaminoProvider := kamino.New(httpClient)
+				forProvider := func(name string) *httpx.Client {
+					return s.providerHTTP(httpClient, name)
+				}
+				llama := defillama.New(forProvider("defillama"), settings.DefiLlamaAPIKey)
+				aaveProvider := aave.New(forProvider("aave"))
+				morphoProvider := morpho.New(forProvider("morpho"))
+				kaminoProvider := kamino.New(forProvider("kamino"))
 				moonwellProvider := moonwell.New()
-				jupiterProvider := jupiter.New(httpClient, settings.JupiterAPIKey)
+				sparkProvider := spark.New()
+				onchainLendProvider := onchainlend.New()
+				jupiterProvider := jupiter.New(forProvider("jupiter"), settings.JupiterAPIKey)
 				tempoProvider := tempo.New()
 				taikoSwapProvider := taikoswap.New()
+				stakingProvider := staking.New(forProvider("staking"))
+				aerodromeProvider := aerodrome.New(forProvider("defillama"))
+				velodromeProvider := aerodrome.NewVelodrome(forProvider("defillama"))
+				uniswapProvider := uniswap.New(forProvider("uniswap"), settings.UniswapAPIKey)
+				uniswapProvider.SetSubgraph(forProvider("thegraph"), settings.TheGraphAPIKey)
+				hyperliquidProvider := hyperliquid.New(forProvider("hyperliquid"))
 				s.marketProvider = llama
+				s.stakingProvider = stakingProvider
 				s.lendingProviders = map[string]providers.LendingProvider{
 					"aave":     aaveProvider,
 					"morpho":   morphoProvider,
 					"kamino":   kaminoProvider,
 					"moonwell": moonwellProvider,
+					"spark":    sparkProvider,
+				}
+				s.lendRatesFallback = onchainLendProvider
+				s.subgraphLenders = map[string]providers.LendingProvider{
+					"aave": aave.NewSubgraph(forProvider("thegraph"), settings.TheGraphAPIKey),
 				}
 				s.yieldProviders = map[string]providers.YieldProvider{
-					"aave":     aaveProvider,
-					"morpho":   morphoProvider,
-					"kamino":   kaminoProvider,
-					"moonwell": moonwellProvider,
+					"aave":        aaveProvider,
+					"morpho":      morphoProvider,
+					"kamino":      kaminoProvider,
+					"moonwell":    moonwellProvider,
+					"spark":       sparkProvider,
+					"staking":     stakingProvider,
+					"aerodrome":   aerodromeProvider,
+					"velodrome":   velodromeProvider,
+					"hyperliquid": hyperliquidProvider,
+				}
+				s.spotPriceProviders = map[string]providers.PriceHistoryProvider{
+					"eip155:999": hyperliquidProvider,
+				}
+				s.rewardsProviders = map[string]providers.RewardsProvider{
+					"aave":   aaveProvider,
+					"morpho": morphoProvider,
 				}
 
 				s.bridgeProviders = map[string]providers.BridgeProvider{
-					"across": across.New(httpClient),
-					"lifi":   lifi.New(httpClient),
-					"bungee": bungee.NewBridge(httpClient, settings.BungeeAPIKey, settings.BungeeAffiliate),
+					"across":   across.New(forProvider("across")),
+					"lifi":     lifi.New(forProvider("lifi")),
+					"bungee":   bungee.NewBridge(forProvider("bungee"), settings.BungeeAPIKey, settings.BungeeAffiliate),
+					"wormhole": wormhole.New(),
+					"cctp":     cctp.New(forProvider("cctp")),
+				}
+				s.perpsProviders = map[string]providers.PerpsProvider{
+					"hyperliquid": hyperliquidProvider,
+					"dydx":        dydx.New(forProvider("dydx")),
+				}
+				s.optionsProviders = map[string]providers.OptionsProvider{
+					"aevo": aevo.New(forProvider("aevo")),
+				}
+				s.poolsProviders = map[string]providers.PoolsProvider{
+					"uniswap": uniswapProvider,
 				}
 				s.bridgeDataProviders = map[string]providers.BridgeDataProvider{
 					"defillama": llama,
 				}
+				etherscanProvider := etherscan.New(forProvider("etherscan"), settings.EtherscanAPIKey, settings.EtherscanBaseURL)
+				s.activityProvider = etherscanProvider
+				s.contractSourceProvider = etherscanProvider
+				s.governanceProvider = governance.New(forProvider("governance"))
 				s.swapProviders = map[string]providers.SwapProvider{
-					"1inch":     oneinch.New(httpClient, settings.OneInchAPIKey),
-					"uniswap":   uniswap.New(httpClient, settings.UniswapAPIKey),
+					"1inch":     oneinch.New(forProvider("1inch"), settings.OneInchAPIKey),
+					"uniswap":   uniswapProvider,
 					"tempo":     tempoProvider,
 					"taikoswap": taikoSwapProvider,
 					"jupiter":   jupiterProvider,
-					"bungee":    bungee.NewSwap(httpClient, settings.BungeeAPIKey, settings.BungeeAffiliate),
-					"fibrous":   fibrous.New(httpClient),
+					"bungee":    bungee.NewSwap(forProvider("bungee"), settings.BungeeAPIKey, settings.BungeeAffiliate),
+					"fibrous":   fibrous.New(forProvider("fibrous")),
+					"cowswap":   cowswap.New(forProvider("cowswap")),
+					"paraswap":  paraswap.New(forProvider("paraswap")),
+					"odos":      odos.New(forProvider("odos")),
 				}
 				s.providerInfos = []model.ProviderInfo{
 					llama.Info(),
@@ -196,9 +343,16 @@ func (s *runtimeState) newRootCommand() *cobra.Command {
 					morphoProvider.Info(),
 					kaminoProvider.Info(),
 					moonwellProvider.Info(),
+					sparkProvider.Info(),
+					onchainLendProvider.Info(),
+					stakingProvider.Info(),
+					aerodromeProvider.Info(),
+					velodromeProvider.Info(),
 					s.bridgeProviders["across"].Info(),
 					s.bridgeProviders["lifi"].Info(),
 					s.bridgeProviders["bungee"].Info(),
+					s.bridgeProviders["wormhole"].Info(),
+					s.bridgeProviders["cctp"].Info(),
 					s.swapProviders["1inch"].Info(),
 					s.swapProviders["uniswap"].Info(),
 					s.swapProviders["tempo"].Info(),
@@ -206,6 +360,14 @@ func (s *runtimeState) newRootCommand() *cobra.Command {
 					s.swapProviders["jupiter"].Info(),
 					s.swapProviders["bungee"].Info(),
 					s.swapProviders["fibrous"].Info(),
+					s.swapProviders["cowswap"].Info(),
+					s.swapProviders["paraswap"].Info(),
+					s.swapProviders["odos"].Info(),
+					s.perpsProviders["hyperliquid"].Info(),
+					s.perpsProviders["dydx"].Info(),
+					s.optionsProviders["aevo"].Info(),
+					s.activityProvider.Info(),
+					s.governanceProvider.Info(),
 				}
 			}
 			if s.actionBuilder == nil {
@@ -223,14 +385,47 @@ func (s *runtimeState) newRootCommand() *cobra.Command {
 					s.cache = cacheStore
 				}
 			}
+			switch {
+			case s.runner.parent != nil:
+				// Batch requests share the parent's payload cache.
+			case settings.CacheEnabled:
+				// Without a store (metadata commands, or the cache failed to
+				// open) the shared cache still dedupes within the process.
+				shared := cache.NewShared(s.cache, settings.MaxStale, settings.StaleRevalidate && !settings.NoStale)
+				if settings.ForceFresh {
+					shared.SkipReads()
+				}
+				cache.SetShared(shared)
+			default:
+				cache.SetShared(nil)
+			}
 			if shouldOpenActionStore(path) && s.actionStore == nil {
 				actionStore, err := execution.OpenStore(settings.ActionStorePath, settings.ActionLockPath)
 				if err != nil {
-					return clierr.Wrap(clierr.CodeInternal, "open action store", err)
+					return wrapOpenActionStoreError(err)
+				}
+				if err := s.watchActionTransitions(actionStore); err != nil {
+					_ = actionStore.Close()
+					return err
 				}
 				s.actionStore = actionStore
 			}
-			return nil
+
+			// Structured input fills flags in PreRunE, so profile defaults and
+			// names from JSON input are applied after it runs.
+			if commandUsesStructuredInput(cmd) {
+				next := cmd.PreRunE
+				cmd.PreRunE = func(cmd *cobra.Command, args []string) error {
+					if next != nil {
+						if err := next(cmd, args); err != nil {
+							return err
+						}
+					}
+					return s.finalizeCommandFlags(cmd)
+				}
+				return nil
+			}
+			return s.finalizeCommandFlags(cmd)
 		},
 	}
 	cmd.SetFlagErrorFunc(func(_ *cobra.Command, err error) error {
@@ -239,39 +434,149 @@ func (s *runtimeState) newRootCommand() *cobra.Command {
 
 	cmd.PersistentFlags().BoolVar(&s.flags.JSON, "json", false, "Output JSON (default)")
 	cmd.PersistentFlags().BoolVar(&s.flags.Plain, "plain", false, "Output plain text")
-	cmd.PersistentFlags().StringVar(&s.flags.Select, "select", "", "Select fields from data (comma-separated)")
+	cmd.PersistentFlags().StringVar(&s.flags.Lang, "lang", "", "Language for plain output labels (en|es|zh|ja); JSON is unaffected")
+	cmd.PersistentFlags().StringVar(&s.flags.Select, "select", "", "Select top-level fields from data (comma-separated; see --query for nested fields)")
+	cmd.PersistentFlags().StringVar(&s.flags.Query, "query", "", "JMESPath expression applied to data before rendering (filters, projections, nested fields)")
 	cmd.PersistentFlags().BoolVar(&s.flags.ResultsOnly, "results-only", false, "Output only data payload")
+	cmd.PersistentFlags().BoolVar(&s.flags.Compact, "compact", false, "Minimal output: strip null/zero fields and abbreviate keys and enum values")
+	cmd.PersistentFlags().IntVar(&s.flags.CompactGzip, "compact-gzip", 0, "With --compact, gzip+base64 arrays larger than this many bytes (0 disables)")
 	cmd.PersistentFlags().StringVar(&s.flags.EnableCommands, "enable-commands", "", "Allowlist command paths (comma-separated)")
 	cmd.PersistentFlags().BoolVar(&s.flags.Strict, "strict", false, "Fail on partial results")
 	cmd.PersistentFlags().StringVar(&s.flags.Timeout, "timeout", "", "Provider request timeout")
 	cmd.PersistentFlags().IntVar(&s.flags.Retries, "retries", -1, "Retries per provider request")
+	cmd.PersistentFlags().IntVar(&s.flags.Concurrency, "concurrency", 0, "Maximum providers queried in parallel by multi-provider commands (default 4)")
+	cmd.PersistentFlags().StringVar(&s.flags.ProviderTimeout, "provider-timeout", "", "Time limit for each provider in multi-provider commands (default: none beyond --timeout)")
 	cmd.PersistentFlags().StringVar(&s.flags.MaxStale, "max-stale", "", "Maximum stale fallback window after TTL expiry")
 	cmd.PersistentFlags().BoolVar(&s.flags.NoStale, "no-stale", false, "Reject stale cache entries")
 	cmd.PersistentFlags().BoolVar(&s.flags.NoCache, "no-cache", false, "Disable cache reads and writes")
+	cmd.PersistentFlags().StringVar(&s.flags.CacheTTL, "cache-ttl", "", "Override the command's cache TTL (e.g. 5s, 10m); reported as meta.cache.ttl_ms")
+	cmd.PersistentFlags().BoolVar(&s.flags.ForceFresh, "force-fresh", false, "Skip cache reads and fetch from providers, still writing the fresh response to cache")
+	cmd.PersistentFlags().BoolVar(&s.flags.Offline, "offline", false, "Make no network calls: serve cached responses (within --max-stale) or fail with offline_unavailable")
+	cmd.PersistentFlags().StringVar(&s.flags.ResolvePolicy, "resolve-policy", "", "Ambiguous symbol policy (error|first; default error)")
+	cmd.PersistentFlags().StringVar(&s.flags.PreferTokenList, "prefer-token-list", "", "Prefer candidates from this token list (builtin or an imported list) when a symbol is ambiguous")
+	cmd.PersistentFlags().BoolVar(&s.flags.StrictAsset, "strict-asset", false, "Disable wrapped/bridged asset equivalence (USDC vs USDC.e, WETH vs ETH)")
+	cmd.PersistentFlags().BoolVar(&s.flags.ResolveUnknown, "resolve-unknown", false, "Look up tokens missing from the registry on-chain (addresses) or in a token list (symbols)")
+	cmd.PersistentFlags().BoolVar(&s.flags.Verbose, "verbose", false, "Log debug diagnostics to stderr (same as --log-level debug)")
+	cmd.PersistentFlags().StringVar(&s.flags.LogLevel, "log-level", "", "Diagnostic log level (off|error|warn|info|debug; default off)")
+	cmd.PersistentFlags().StringVar(&s.flags.LogFile, "log-file", "", "Append diagnostic logs to this file as JSON lines instead of stderr")
+	cmd.PersistentFlags().BoolVar(&s.flags.Trace, "trace", false, "Include a request trace (HTTP phase timings, retries, cache timings) in meta; exported via OTLP when configured")
+	cmd.PersistentFlags().StringVar(&s.flags.NotifyURL, "notify-url", "", "POST the action envelope to this URL on every action status change (signed with DEFI_NOTIFY_SECRET)")
+	cmd.PersistentFlags().StringVar(&s.flags.Seed, "seed", "", "Seed request, action, and trace IDs for reproducible output")
+	cmd.PersistentFlags().StringVar(&s.flags.RecordFixtures, "record-fixtures", "", "Record provider HTTP responses to this directory (bypasses the cache)")
+	cmd.PersistentFlags().StringVar(&s.flags.ReplayFixtures, "replay-fixtures", "", "Serve provider HTTP responses from a --record-fixtures directory with the clock frozen at recording time")
 	cmd.PersistentFlags().StringVar(&s.flags.ConfigPath, "config", "", "Path to config file")
+	cmd.PersistentFlags().StringVar(&s.flags.Profile, "profile", "", "Config profile to apply (overrides DEFI_PROFILE and the config file default)")
 	_ = schema.SetFlagMetadata(cmd.PersistentFlags(), "config", schema.FlagMetadata{Format: "path"})
+	_ = schema.SetFlagMetadata(cmd.PersistentFlags(), "log-file", schema.FlagMetadata{Format: "path"})
+	_ = schema.SetFlagMetadata(cmd.PersistentFlags(), "record-fixtures", schema.FlagMetadata{Format: "path"})
+	_ = schema.SetFlagMetadata(cmd.PersistentFlags(), "replay-fixtures", schema.FlagMetadata{Format: "path"})
+	_ = schema.SetFlagMetadata(cmd.PersistentFlags(), "log-level", schema.FlagMetadata{Enum: logx.Levels()})
+	_ = schema.SetFlagMetadata(cmd.PersistentFlags(), "lang", schema.FlagMetadata{Enum: i18n.Supported()})
+	_ = schema.SetFlagMetadata(cmd.PersistentFlags(), "resolve-policy", schema.FlagMetadata{Enum: []string{string(id.ResolvePolicyError), string(id.ResolvePolicyFirst)}})
 
 	cmd.AddCommand(s.newSchemaCommand())
 	cmd.AddCommand(s.newProvidersCommand())
 	cmd.AddCommand(s.newChainsCommand())
 	cmd.AddCommand(s.newProtocolsCommand())
 	cmd.AddCommand(s.newDexesCommand())
+	cmd.AddCommand(s.newPoolsCommand())
 	cmd.AddCommand(s.newStablecoinsCommand())
+	cmd.AddCommand(s.newRiskCommand())
 	cmd.AddCommand(s.newAssetsCommand())
 	cmd.AddCommand(s.newLendCommand())
 	cmd.AddCommand(s.newRewardsCommand())
+	cmd.AddCommand(s.newGovernanceCommand())
 	cmd.AddCommand(s.newBridgeCommand())
 	cmd.AddCommand(s.newSwapCommand())
+	cmd.AddCommand(s.newRouteCommand())
 	cmd.AddCommand(s.newApprovalsCommand())
 	cmd.AddCommand(s.newTransferCommand())
+	cmd.AddCommand(s.newTxCommand())
 	cmd.AddCommand(s.newActionsCommand())
+	cmd.AddCommand(s.newTemplatesCommand())
+	cmd.AddCommand(s.newScheduleCommand())
+	cmd.AddCommand(s.newSignerCommand())
+	cmd.AddCommand(s.newAACommand())
 	cmd.AddCommand(s.newYieldCommand())
+	cmd.AddCommand(s.newStakeCommand())
+	cmd.AddCommand(s.newPerpsCommand())
+	cmd.AddCommand(s.newOptionsCommand())
+	cmd.AddCommand(s.newHistoryCommand())
+	cmd.AddCommand(s.newVerifyCommand())
 	cmd.AddCommand(s.newWalletCommand())
+	cmd.AddCommand(s.newBalancesCommand())
+	cmd.AddCommand(s.newActivityCommand())
+	cmd.AddCommand(s.newResolveCommand())
+	cmd.AddCommand(s.newConfigCommand())
+	cmd.AddCommand(s.newRPCCommand())
+	cmd.AddCommand(s.newQuotesCommand())
+	cmd.AddCommand(s.newMCPCommand())
+	cmd.AddCommand(s.newBatchCommand())
+	cmd.AddCommand(s.newTUICommand())
 	cmd.AddCommand(newVersionCommand())
 
 	return cmd
 }
 
+// configureProcess installs the process-wide state that commands read
+// through package globals: logging, tracing, asset resolution, synced chains,
+// configured RPC URLs, and endpoint and provider health.
+func (s *runtimeState) configureProcess() error {
+	if err := s.configureLogging(); err != nil {
+		return err
+	}
+	s.startTrace()
+	if err := s.configureFixtures(); err != nil {
+		return err
+	}
+	httpx.SetOffline(s.settings.Offline)
+	resolvePolicy, err := id.ParseResolvePolicy(s.settings.ResolvePolicy)
+	if err != nil {
+		return err
+	}
+	id.SetDefaultResolveOptions(id.ResolveOptions{Policy: resolvePolicy, PreferTokenList: s.settings.PreferTokenList, StrictAsset: s.settings.StrictAsset, ResolveUnknown: s.settings.ResolveUnknown})
+	s.tokenLookup = nil
+	if s.settings.ResolveUnknown {
+		s.tokenLookup = tokenlookup.New(httpx.New(s.settings.Timeout, s.settings.Retries), s.settings.TokenListURL, s.settings.Timeout)
+		id.SetUnknownResolver(s.tokenLookup)
+	} else {
+		id.SetUnknownResolver(nil)
+	}
+	if err := s.loadUserTokens(); err != nil {
+		return err
+	}
+	if err := s.loadUserLabels(); err != nil {
+		return err
+	}
+	s.loadSyncedChains()
+	rpcURLs, err := configuredRPCURLs(s.settings.RPCURLs)
+	if err != nil {
+		return err
+	}
+	registry.SetConfiguredRPCURLs(rpcURLs)
+	if path := s.rpcHealthPath(); path != "" {
+		// Health only reorders endpoints; a bad file is ignored.
+		_ = rpcx.LoadHealth(path)
+	}
+	breaker.Configure(s.settings.BreakerFailures, s.settings.BreakerCooldown)
+	if path := s.providerHealthPath(); path != "" {
+		// A bad file only loses failure history.
+		_ = breaker.Load(path)
+		_ = ratebudget.Load(providerBudgetsPath(path))
+	}
+	return nil
+}
+
+// inheritProcess prepares a run started inside another invocation (a batch
+// request). Such runs may execute concurrently, so they keep the process-wide
+// state parent installed instead of replacing it, and copy what commands read
+// from the runtime state.
+func (s *runtimeState) inheritProcess(parent *runtimeState) {
+	s.userTokens = parent.userTokens
+	s.syncedChains = parent.syncedChains
+	s.tokenLookup = parent.tokenLookup
+}
+
 func newVersionCommand() *cobra.Command {
 	var long bool
 	cmd := &cobra.Command{
@@ -289,12 +594,74 @@ func newVersionCommand() *cobra.Command {
 	return cmd
 }
 
+// outputModels lists the response data models published by
+// `schema --output-models`. The envelope comes first and is the document root.
+var outputModels = []any{
+	model.Envelope{},
+	model.APYConsistencyReport{},
+	model.ActivityFeed{},
+	model.ArchivedQuote{},
+	model.BridgeDetails{},
+	model.BridgeQuote{},
+	model.BridgeSummary{},
+	model.ChainAssetTVL{},
+	model.ChainSyncResult{},
+	model.ConfigProfiles{},
+	model.DepegReport{},
+	model.GasPrice{},
+	model.LendMarket{},
+	model.LendPosition{},
+	model.LendRate{},
+	model.MarketHistorySeries{},
+	model.OptionContract{},
+	model.OptionIVSurface{},
+	model.Paymaster{},
+	model.PerpFundingRate{},
+	model.PerpMarket{},
+	model.ProviderHealth{},
+	model.ProviderInfo{},
+	model.ProviderKeyCheck{},
+	model.QuoteVerification{},
+	model.RPCEndpoint{},
+	model.RPCProbe{},
+	model.RegistryToken{},
+	model.ResolvedName{},
+	model.RouteComparison{},
+	model.StablecoinDetails{},
+	model.StakeRate{},
+	model.SupportedChain{},
+	model.SwapQuote{},
+	model.TokenListChange{},
+	model.YieldBacktest{},
+	model.YieldHistorySeries{},
+	model.YieldLoop{},
+	model.YieldOpportunity{},
+	model.YieldPosition{},
+	execution.Action{},
+	execution.AuditEvent{},
+	execution.Schedule{},
+	execution.ScheduleRun{},
+	execution.SignerRotation{},
+	execution.Template{},
+	execution.TxList{},
+	execution.TxReplacementResult{},
+}
+
 func (s *runtimeState) newSchemaCommand() *cobra.Command {
+	var outputModelsArg bool
 	cmd := &cobra.Command{
 		Use:   "schema [command path]",
 		Short: "Print machine-readable command schema",
+		Long:  "Prints the command tree with flags, request, and response metadata. With --output-models, prints a JSON Schema (draft 2020-12) document for the envelope and every response data model instead, versioned with the envelope version.",
 		Args:  cobra.ArbitraryArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if outputModelsArg {
+				if len(args) > 0 {
+					return clierr.New(clierr.CodeUsage, "--output-models does not take a command path")
+				}
+				data := schema.OutputModels(model.EnvelopeVersion, outputModels...)
+				return s.emitSuccess(trimRootPath(cmd.CommandPath()), data, nil, cacheMetaBypass(), nil, false)
+			}
 			path := ""
 			if len(args) > 0 {
 				path = strings.Join(args, " ")
@@ -306,34 +673,342 @@ func (s *runtimeState) newSchemaCommand() *cobra.Command {
 			return s.emitSuccess(trimRootPath(cmd.CommandPath()), data, nil, cacheMetaBypass(), nil, false)
 		},
 	}
-	schemaResponse := schema.TypeSchema{Type: "object", Description: "Machine-readable command schema document"}
+	cmd.Flags().BoolVar(&outputModelsArg, "output-models", false, "Print JSON Schema definitions for the envelope and all response data models")
+	schemaResponse := schema.TypeSchema{Type: "object", Description: "Machine-readable command schema document, or a JSON Schema document with --output-models"}
 	_ = schema.SetCommandMetadata(cmd, schema.CommandMetadata{Response: &schemaResponse})
 	return cmd
 }
 
 func (s *runtimeState) newProvidersCommand() *cobra.Command {
 	root := &cobra.Command{Use: "providers", Short: "Provider commands"}
+	var listChain string
 	list := &cobra.Command{
 		Use:   "list",
 		Short: "List supported providers and API key metadata (no keys required)",
+		Long:  "Lists every provider with its capabilities and, under support, the chains and features (exact-input, exact-output) each capability serves. --chain keeps only the capabilities available on that chain.",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return s.emitSuccess(trimRootPath(cmd.CommandPath()), s.providerInfos, nil, cacheMetaBypass(), nil, false)
+			infos := s.providerInfos
+			if strings.TrimSpace(listChain) != "" {
+				chain, err := id.ParseChain(listChain)
+				if err != nil {
+					return err
+				}
+				infos = providerInfosForChain(infos, chain)
+			}
+			return s.emitSuccess(trimRootPath(cmd.CommandPath()), infos, nil, cacheMetaBypass(), nil, false)
 		},
 	}
+	list.Flags().StringVar(&listChain, "chain", "", "Only list capabilities supported on this chain")
+	_ = schema.SetFlagMetadata(list.Flags(), "chain", schema.FlagMetadata{Format: "chain"})
 	providersResponse := schema.SchemaFromType([]model.ProviderInfo{})
 	_ = schema.SetCommandMetadata(list, schema.CommandMetadata{Response: &providersResponse})
 	root.AddCommand(list)
+
+	var reset bool
+	var resetProvider string
+	health := &cobra.Command{
+		Use:   "health",
+		Short: "Show provider circuit breaker and rate limit budget state per capability",
+		Long:  "Lists every provider capability called recently with its consecutive failure count and circuit state. An open circuit skips calls to that capability until its cool-down ends; half_open means the next call decides. rate_limit is the budget the provider last advertised in its rate-limit headers: low budgets pace requests until the reset and exhausted ones skip them; providers with a budget but no tracked capability are listed with capability \"*\". --reset clears the recorded state.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if reset {
+				provider := strings.ToLower(strings.TrimSpace(resetProvider))
+				breaker.Reset(provider)
+				ratebudget.Reset(provider)
+			}
+			return s.emitSuccess(trimRootPath(cmd.CommandPath()), providerHealthRows(s.runner.now()), nil, cacheMetaBypass(), nil, false)
+		},
+	}
+	health.Flags().BoolVar(&reset, "reset", false, "Clear recorded failures and rate limit budgets and close every circuit before listing")
+	health.Flags().StringVar(&resetProvider, "provider", "", "Limit --reset to one provider")
+	healthResponse := schema.SchemaFromType([]model.ProviderHealth{})
+	_ = schema.SetCommandMetadata(health, schema.CommandMetadata{Response: &healthResponse})
+	root.AddCommand(health)
+
+	var verifyProvider string
+	verify := &cobra.Command{
+		Use:   "verify",
+		Short: "Check configured provider API keys with a cheap authenticated call",
+		Long:  "Calls each keyed provider (1inch, DefiLlama pro, Bungee, Uniswap, Jupiter) once with its configured key and reports valid, invalid, rate_limited, missing (no key set), or unverified (the check itself failed). Invalid keys are also listed as warnings.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			verifiers, err := s.keyVerifiers(verifyProvider)
+			if err != nil {
+				return err
+			}
+			// Transport errors can quote a URL that carries the key.
+			for _, key := range []string{s.settings.DefiLlamaAPIKey, s.settings.OneInchAPIKey, s.settings.UniswapAPIKey, s.settings.JupiterAPIKey, s.settings.BungeeAPIKey} {
+				logx.RegisterSecret(key)
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), s.settings.Timeout)
+			defer cancel()
+			rows := s.verifyProviderKeys(ctx, verifiers)
+			var warnings []string
+			for _, row := range rows {
+				if row.Status == keyStatusInvalid {
+					warnings = append(warnings, fmt.Sprintf("provider %s rejected %s", row.Provider, row.KeyEnvVar))
+				}
+			}
+			return s.emitSuccess(trimRootPath(cmd.CommandPath()), rows, warnings, cacheMetaBypass(), nil, false)
+		},
+	}
+	verify.Flags().StringVar(&verifyProvider, "provider", "", "Only verify this provider's key")
+	verifyResponse := schema.SchemaFromType([]model.ProviderKeyCheck{})
+	_ = schema.SetCommandMetadata(verify, schema.CommandMetadata{Response: &verifyResponse})
+	root.AddCommand(verify)
 	return root
 }
 
+// providers verify statuses.
+const (
+	keyStatusValid       = "valid"
+	keyStatusInvalid     = "invalid"
+	keyStatusRateLimited = "rate_limited"
+	keyStatusMissing     = "missing"
+	keyStatusUnverified  = "unverified"
+)
+
+// keyVerifiers returns the providers whose API key can be verified, in
+// provider name order, limited to name when it is set.
+func (s *runtimeState) keyVerifiers(name string) ([]providers.KeyVerifier, error) {
+	candidates := []providers.Provider{s.marketProvider}
+	for _, key := range slices.Sorted(maps.Keys(s.swapProviders)) {
+		candidates = append(candidates, s.swapProviders[key])
+	}
+	for _, key := range slices.Sorted(maps.Keys(s.bridgeProviders)) {
+		candidates = append(candidates, s.bridgeProviders[key])
+	}
+	name = strings.ToLower(strings.TrimSpace(name))
+	seen := map[string]bool{}
+	var out []providers.KeyVerifier
+	for _, candidate := range candidates {
+		verifier, ok := candidate.(providers.KeyVerifier)
+		if !ok {
+			continue
+		}
+		provider := verifier.Info().Name
+		if seen[provider] || (name != "" && provider != name) {
+			continue
+		}
+		seen[provider] = true
+		out = append(out, verifier)
+	}
+	if name != "" && len(out) == 0 {
+		return nil, clierr.New(clierr.CodeUsage, fmt.Sprintf("provider %q has no API key to verify", name))
+	}
+	slices.SortFunc(out, func(a, b providers.KeyVerifier) int {
+		return strings.Compare(a.Info().Name, b.Info().Name)
+	})
+	return out, nil
+}
+
+// verifyProviderKeys checks every key concurrently. A failed check is a row,
+// never a command error.
+func (s *runtimeState) verifyProviderKeys(ctx context.Context, verifiers []providers.KeyVerifier) []model.ProviderKeyCheck {
+	rows := make([]model.ProviderKeyCheck, len(verifiers))
+	var wg sync.WaitGroup
+	for i, verifier := range verifiers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			info := verifier.Info()
+			started := time.Now()
+			err := verifier.VerifyKey(ctx)
+			status, message := keyCheckStatus(err)
+			rows[i] = model.ProviderKeyCheck{
+				Provider:  info.Name,
+				KeyEnvVar: info.KeyEnvVarName,
+				Status:    status,
+				Message:   logx.RedactSecrets(message),
+				LatencyMS: time.Since(started).Milliseconds(),
+				CheckedAt: s.runner.now().UTC().Format(time.RFC3339),
+			}
+		}()
+	}
+	wg.Wait()
+	return rows
+}
+
+func keyCheckStatus(err error) (status, message string) {
+	if err == nil {
+		return keyStatusValid, ""
+	}
+	if errors.Is(err, providers.ErrNoAPIKey) {
+		return keyStatusMissing, ""
+	}
+	if typed, ok := clierr.As(err); ok {
+		switch typed.Code {
+		case clierr.CodeAuth:
+			return keyStatusInvalid, err.Error()
+		case clierr.CodeRateLimited:
+			return keyStatusRateLimited, err.Error()
+		}
+	}
+	return keyStatusUnverified, err.Error()
+}
+
+// providerInfosForChain narrows each provider to the capabilities it supports
+// on chain, with support entries reduced to that chain. Capabilities without
+// support metadata are kept; providers left without capabilities are dropped.
+func providerInfosForChain(infos []model.ProviderInfo, chain id.Chain) []model.ProviderInfo {
+	out := make([]model.ProviderInfo, 0, len(infos))
+	for _, info := range infos {
+		restricted := map[string]bool{}
+		supported := map[string]bool{}
+		var support []model.CapabilitySupport
+		for _, entry := range info.Support {
+			restricted[entry.Capability] = true
+			if providers.SupportsChain(entry, chain) {
+				supported[entry.Capability] = true
+				entry.Chains = []string{chain.CAIP2}
+				support = append(support, entry)
+			}
+		}
+		keep := func(capability string) bool {
+			return !restricted[capability] || supported[capability]
+		}
+		var capabilities []string
+		for _, capability := range info.Capabilities {
+			if keep(capability) {
+				capabilities = append(capabilities, capability)
+			}
+		}
+		if len(capabilities) == 0 {
+			continue
+		}
+		var auth []model.ProviderCapabilityAuth
+		for _, entry := range info.CapabilityAuth {
+			if keep(entry.Capability) {
+				auth = append(auth, entry)
+			}
+		}
+		info.Capabilities, info.CapabilityAuth, info.Support = capabilities, auth, support
+		out = append(out, info)
+	}
+	return out
+}
+
+func providerHealthRows(now time.Time) []model.ProviderHealth {
+	circuits := breaker.Circuits()
+	budgets := map[string]model.RateLimitBudget{}
+	for _, b := range ratebudget.Budgets() {
+		budget := model.RateLimitBudget{
+			State:      b.State(now),
+			Limit:      b.Limit,
+			Remaining:  b.Remaining,
+			ObservedAt: b.ObservedAt.UTC().Format(time.RFC3339),
+		}
+		if !b.ResetAt.IsZero() {
+			budget.ResetAt = b.ResetAt.UTC().Format(time.RFC3339)
+		}
+		budgets[b.Provider] = budget
+	}
+	rows := make([]model.ProviderHealth, 0, len(circuits))
+	listed := map[string]bool{}
+	for _, c := range circuits {
+		row := model.ProviderHealth{
+			Provider:   c.Provider,
+			Capability: c.Capability,
+			State:      c.State(now),
+			Failures:   c.Failures,
+			LastError:  c.LastError,
+			CheckedAt:  c.CheckedAt.UTC().Format(time.RFC3339),
+		}
+		if row.State == breaker.StateOpen {
+			row.OpenUntil = c.OpenUntil.UTC().Format(time.RFC3339)
+		}
+		if budget, ok := budgets[c.Provider]; ok {
+			row.RateLimit = &budget
+		}
+		listed[c.Provider] = true
+		rows = append(rows, row)
+	}
+	// Budgets are per provider, so one seen only on untracked calls gets a
+	// provider-wide row.
+	for provider, budget := range budgets {
+		if listed[provider] {
+			continue
+		}
+		rows = append(rows, model.ProviderHealth{
+			Provider:   provider,
+			Capability: "*",
+			State:      breaker.StateClosed,
+			CheckedAt:  budget.ObservedAt,
+			RateLimit:  &budget,
+		})
+	}
+	sort.SliceStable(rows, func(i, j int) bool { return rows[i].Provider < rows[j].Provider })
+	return rows
+}
+
+// saveHealth persists endpoint and provider health observed by this
+// invocation for the next one.
+func (s *runtimeState) saveHealth() {
+	if path := s.rpcHealthPath(); path != "" {
+		_ = rpcx.SaveHealth(path)
+	}
+	if path := s.providerHealthPath(); path != "" {
+		_ = breaker.Save(path)
+		_ = ratebudget.Save(providerBudgetsPath(path))
+	}
+}
+
+// providerBudgetsPath keeps provider rate limit budgets next to circuit
+// state.
+func providerBudgetsPath(healthPath string) string {
+	return filepath.Join(filepath.Dir(healthPath), "provider-budgets.json")
+}
+
+// providerHealthPath keeps provider circuit state next to the cache database,
+// like rpcHealthPath.
+func (s *runtimeState) providerHealthPath() string {
+	if strings.TrimSpace(s.settings.CachePath) == "" || fixtures.Active() != nil || s.settings.Offline {
+		return ""
+	}
+	return filepath.Join(filepath.Dir(s.settings.CachePath), "provider-health.json")
+}
+
+// circuitWarnings explains providers skipped by an open circuit or an
+// exhausted rate limit budget, which otherwise only show up as a
+// circuit_open or rate_limited status.
+func circuitWarnings(commandPath string, statuses []model.ProviderStatus) []string {
+	var warnings []string
+	for _, status := range statuses {
+		if status.Status == "rate_limited" {
+			if b, ok := ratebudget.Get(status.Name); ok && b.State(time.Now()) == ratebudget.StateExhausted {
+				warnings = append(warnings, fmt.Sprintf("provider %s skipped: rate limit budget exhausted until %s; see `defi providers health`", status.Name, b.ResetAt.UTC().Format(time.RFC3339)))
+			}
+			continue
+		}
+		if status.Status != "circuit_open" {
+			continue
+		}
+		msg := fmt.Sprintf("provider %s skipped: circuit open after repeated failures", status.Name)
+		if c, ok := breaker.Get(status.Name, capabilityOf(commandPath)); ok {
+			msg = fmt.Sprintf("provider %s skipped: circuit open after %d consecutive failures until %s; see `defi providers health`", status.Name, c.Failures, c.OpenUntil.UTC().Format(time.RFC3339))
+		}
+		warnings = append(warnings, msg)
+	}
+	return warnings
+}
+
+// capabilityOf maps a command path to the capability name used in
+// ProviderInfo.Capabilities, e.g. "lend markets" to "lend.markets".
+func capabilityOf(commandPath string) string {
+	return strings.ReplaceAll(normalizeCommandPath(commandPath), " ", ".")
+}
+
 func (s *runtimeState) newChainsCommand() *cobra.Command {
 	root := &cobra.Command{Use: "chains", Short: "Chain market data"}
 
+	var includeSynced bool
 	listCmd := &cobra.Command{
 		Use:   "list",
 		Short: "List all supported chains with aliases (no keys required)",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			entries := id.ListChains()
+			if includeSynced {
+				entries = append(entries, id.SyncedChains()...)
+			}
 			result := make([]model.SupportedChain, 0, len(entries))
 			for _, e := range entries {
 				result = append(result, model.SupportedChain{
@@ -343,31 +1018,62 @@ func (s *runtimeState) newChainsCommand() *cobra.Command {
 					Namespace:  e.Chain.Namespace(),
 					EVMChainID: e.Chain.EVMChainID,
 					Aliases:    e.Aliases,
+					Synced:     id.IsSyncedChain(e.Chain.EVMChainID),
 				})
 			}
 			return s.emitSuccess(trimRootPath(cmd.CommandPath()), result, nil, cacheMetaBypass(), nil, false)
 		},
 	}
+	listCmd.Flags().BoolVar(&includeSynced, "include-synced", false, "Also list chains learned with `chains sync`")
 	listResponse := schema.SchemaFromType([]model.SupportedChain{})
 	_ = schema.SetCommandMetadata(listCmd, schema.CommandMetadata{Response: &listResponse})
 	root.AddCommand(listCmd)
+	root.AddCommand(s.newChainsSyncCommand())
 
 	var limit int
+	var topChangeWindow string
+	var topAlertDropPct float64
 	topCmd := &cobra.Command{
 		Use:   "top",
 		Short: "Top chains by TVL",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			req := map[string]any{"limit": limit}
+			window, err := parseTVLChangeWindow(topChangeWindow, topAlertDropPct)
+			if err != nil {
+				return err
+			}
+			req := map[string]any{"limit": limit, "change_window": window, "alert_drop_pct": topAlertDropPct}
 			key := cacheKey(trimRootPath(cmd.CommandPath()), req)
 			return s.runCachedCommand(trimRootPath(cmd.CommandPath()), key, 5*time.Minute, func(ctx context.Context) (any, []model.ProviderStatus, []string, bool, error) {
 				start := time.Now()
 				data, err := s.marketProvider.ChainsTop(ctx, limit)
-				status := []model.ProviderStatus{{Name: s.marketProvider.Info().Name, Status: statusFromErr(err), LatencyMS: time.Since(start).Milliseconds()}}
-				return data, status, nil, false, err
+				if err != nil || window == "" {
+					status := []model.ProviderStatus{{Name: s.marketProvider.Info().Name, Status: statusFromErr(err), LatencyMS: time.Since(start).Milliseconds()}}
+					return data, status, nil, false, err
+				}
+				names := make([]string, len(data))
+				for i, item := range data {
+					names[i] = item.Chain
+				}
+				changes, warnings, partial := s.fetchTVLChanges(func(provider providers.TVLChangeProvider) (map[string]float64, error) {
+					return provider.ChainsTVLChange(ctx, names, window)
+				})
+				status := []model.ProviderStatus{{Name: s.marketProvider.Info().Name, Status: statusFromErr(nil), LatencyMS: time.Since(start).Milliseconds()}}
+				if partial {
+					return data, status, warnings, true, nil
+				}
+				rows, warnings := annotateTVLChanges("chain", names, changes, window, topAlertDropPct)
+				for i, row := range rows {
+					data[i].TVLChangePct = row.changePct
+					data[i].TVLChangeWindow = string(window)
+					data[i].DropAlert = row.dropAlert
+				}
+				return data, status, warnings, false, nil
 			})
 		},
 	}
 	topCmd.Flags().IntVar(&limit, "limit", 20, "Number of chains to return")
+	topCmd.Flags().StringVar(&topChangeWindow, "change-window", "", "Add TVL change over this window (24h|7d)")
+	topCmd.Flags().Float64Var(&topAlertDropPct, "alert-drop-pct", 0, "Flag chains whose TVL fell more than this percent over --change-window (default window 24h)")
 	root.AddCommand(topCmd)
 
 	var assetsChainArg string
@@ -449,7 +1155,7 @@ func (s *runtimeState) newChainsCommand() *cobra.Command {
 				if err != nil {
 					return err
 				}
-				if chain.Namespace() != "eip155" {
+				if !chain.IsEVM() {
 					return clierr.New(clierr.CodeUnsupported, "chains gas is only supported for EVM chains: "+raw)
 				}
 				rpcURL, err := registry.ResolveRPCURL(gasRPCURL, chain.EVMChainID)
@@ -525,23 +1231,51 @@ func (s *runtimeState) newProtocolsCommand() *cobra.Command {
 	var limit int
 	var category string
 	var chain string
+	var changeWindow string
+	var alertDropPct float64
 	cmd := &cobra.Command{
 		Use:   "top",
 		Short: "Top protocols by TVL",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			req := map[string]any{"category": category, "chain": chain, "limit": limit}
+			window, err := parseTVLChangeWindow(changeWindow, alertDropPct)
+			if err != nil {
+				return err
+			}
+			req := map[string]any{"category": category, "chain": chain, "limit": limit, "change_window": window, "alert_drop_pct": alertDropPct}
 			key := cacheKey(trimRootPath(cmd.CommandPath()), req)
 			return s.runCachedCommand(trimRootPath(cmd.CommandPath()), key, 5*time.Minute, func(ctx context.Context) (any, []model.ProviderStatus, []string, bool, error) {
 				start := time.Now()
 				data, err := s.marketProvider.ProtocolsTop(ctx, category, chain, limit)
-				status := []model.ProviderStatus{{Name: s.marketProvider.Info().Name, Status: statusFromErr(err), LatencyMS: time.Since(start).Milliseconds()}}
-				return data, status, nil, false, err
+				if err != nil || window == "" {
+					status := []model.ProviderStatus{{Name: s.marketProvider.Info().Name, Status: statusFromErr(err), LatencyMS: time.Since(start).Milliseconds()}}
+					return data, status, nil, false, err
+				}
+				names := make([]string, len(data))
+				for i, item := range data {
+					names[i] = item.Protocol
+				}
+				changes, warnings, partial := s.fetchTVLChanges(func(provider providers.TVLChangeProvider) (map[string]float64, error) {
+					return provider.ProtocolsTVLChange(ctx, chain, names, window)
+				})
+				status := []model.ProviderStatus{{Name: s.marketProvider.Info().Name, Status: statusFromErr(nil), LatencyMS: time.Since(start).Milliseconds()}}
+				if partial {
+					return data, status, warnings, true, nil
+				}
+				rows, warnings := annotateTVLChanges("protocol", names, changes, window, alertDropPct)
+				for i, row := range rows {
+					data[i].TVLChangePct = row.changePct
+					data[i].TVLChangeWindow = string(window)
+					data[i].DropAlert = row.dropAlert
+				}
+				return data, status, warnings, false, nil
 			})
 		},
 	}
 	cmd.Flags().IntVar(&limit, "limit", 20, "Number of protocols to return")
 	cmd.Flags().StringVar(&category, "category", "", "Filter by protocol category (e.g. lending)")
 	cmd.Flags().StringVar(&chain, "chain", "", "Filter by DefiLlama chain name (e.g. Ethereum, Arbitrum, Polygon)")
+	cmd.Flags().StringVar(&changeWindow, "change-window", "", "Add TVL change over this window (24h|7d); on the --chain TVL when set")
+	cmd.Flags().Float64Var(&alertDropPct, "alert-drop-pct", 0, "Flag protocols whose TVL fell more than this percent over --change-window (default window 24h)")
 	root.AddCommand(cmd)
 
 	catCmd := &cobra.Command{
@@ -558,19 +1292,27 @@ func (s *runtimeState) newProtocolsCommand() *cobra.Command {
 		},
 	}
 	root.AddCommand(catCmd)
+	root.AddCommand(s.newProtocolsSecurityCommand())
 
 	var feesLimit int
 	var feesCategory string
 	var feesChain string
+	var feesProtocol string
+	var feesWindow string
 	feesCmd := &cobra.Command{
 		Use:   "fees",
-		Short: "Top protocols by 24h fees",
+		Short: "Top protocols by fees over a 24h, 7d, or 30d window",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			req := map[string]any{"category": feesCategory, "chain": feesChain, "limit": feesLimit}
+			window, err := parseMetricsWindow(feesWindow)
+			if err != nil {
+				return err
+			}
+			metricsReq := providers.ProtocolMetricsRequest{Category: feesCategory, Chain: feesChain, Protocol: feesProtocol, Window: window, Limit: feesLimit}
+			req := map[string]any{"category": feesCategory, "chain": feesChain, "protocol": strings.ToLower(strings.TrimSpace(feesProtocol)), "window": window, "limit": feesLimit}
 			key := cacheKey(trimRootPath(cmd.CommandPath()), req)
 			return s.runCachedCommand(trimRootPath(cmd.CommandPath()), key, 5*time.Minute, func(ctx context.Context) (any, []model.ProviderStatus, []string, bool, error) {
 				start := time.Now()
-				data, err := s.marketProvider.ProtocolsFees(ctx, feesCategory, feesChain, feesLimit)
+				data, err := s.marketProvider.ProtocolsFees(ctx, metricsReq)
 				status := []model.ProviderStatus{{Name: s.marketProvider.Info().Name, Status: statusFromErr(err), LatencyMS: time.Since(start).Milliseconds()}}
 				return data, status, nil, false, err
 			})
@@ -579,8 +1321,41 @@ func (s *runtimeState) newProtocolsCommand() *cobra.Command {
 	feesCmd.Flags().IntVar(&feesLimit, "limit", 20, "Number of protocols to return")
 	feesCmd.Flags().StringVar(&feesCategory, "category", "", "Filter by protocol category (e.g. Dexs, Lending)")
 	feesCmd.Flags().StringVar(&feesChain, "chain", "", "Filter by DefiLlama chain name (e.g. Ethereum, Arbitrum, Polygon)")
+	feesCmd.Flags().StringVar(&feesProtocol, "protocol", "", "Filter by protocol name or DefiLlama slug (e.g. uniswap)")
+	feesCmd.Flags().StringVar(&feesWindow, "window", "24h", "Ranking window (24h|7d|30d)")
+	_ = schema.SetFlagMetadata(feesCmd.Flags(), "window", schema.FlagMetadata{Enum: metricsWindowValues()})
 	root.AddCommand(feesCmd)
 
+	var volumeLimit int
+	var volumeChain string
+	var volumeProtocol string
+	var volumeWindow string
+	volumeCmd := &cobra.Command{
+		Use:   "volume",
+		Short: "Top DEX protocols by trading volume over a 24h, 7d, or 30d window",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			window, err := parseMetricsWindow(volumeWindow)
+			if err != nil {
+				return err
+			}
+			metricsReq := providers.ProtocolMetricsRequest{Chain: volumeChain, Protocol: volumeProtocol, Window: window, Limit: volumeLimit}
+			req := map[string]any{"chain": volumeChain, "protocol": strings.ToLower(strings.TrimSpace(volumeProtocol)), "window": window, "limit": volumeLimit}
+			key := cacheKey(trimRootPath(cmd.CommandPath()), req)
+			return s.runCachedCommand(trimRootPath(cmd.CommandPath()), key, 5*time.Minute, func(ctx context.Context) (any, []model.ProviderStatus, []string, bool, error) {
+				start := time.Now()
+				data, err := s.marketProvider.ProtocolsVolume(ctx, metricsReq)
+				status := []model.ProviderStatus{{Name: s.marketProvider.Info().Name, Status: statusFromErr(err), LatencyMS: time.Since(start).Milliseconds()}}
+				return data, status, nil, false, err
+			})
+		},
+	}
+	volumeCmd.Flags().IntVar(&volumeLimit, "limit", 20, "Number of protocols to return")
+	volumeCmd.Flags().StringVar(&volumeChain, "chain", "", "Filter by DefiLlama chain name (e.g. Ethereum, Arbitrum, Polygon)")
+	volumeCmd.Flags().StringVar(&volumeProtocol, "protocol", "", "Filter by protocol name or DefiLlama slug (e.g. uniswap)")
+	volumeCmd.Flags().StringVar(&volumeWindow, "window", "24h", "Ranking window (24h|7d|30d)")
+	_ = schema.SetFlagMetadata(volumeCmd.Flags(), "window", schema.FlagMetadata{Enum: metricsWindowValues()})
+	root.AddCommand(volumeCmd)
+
 	var revLimit int
 	var revCategory string
 	var revChain string
@@ -636,8 +1411,9 @@ func (s *runtimeState) newStablecoinsCommand() *cobra.Command {
 	var limit int
 	var pegType string
 	cmd := &cobra.Command{
-		Use:   "top",
-		Short: "Top stablecoins by circulating market cap",
+		Use:     "top",
+		Aliases: []string{"list"},
+		Short:   "Top stablecoins by circulating market cap with peg deviation",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			req := map[string]any{"peg_type": pegType, "limit": limit}
 			key := cacheKey(trimRootPath(cmd.CommandPath()), req)
@@ -671,6 +1447,31 @@ func (s *runtimeState) newStablecoinsCommand() *cobra.Command {
 	chainsCmd.Flags().IntVar(&chainsLimit, "limit", 20, "Number of chains to return")
 	root.AddCommand(chainsCmd)
 
+	var detailsArg string
+	detailsCmd := &cobra.Command{
+		Use:   "details",
+		Short: "Supply, peg deviation, and chain distribution for one stablecoin",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ref := strings.TrimSpace(detailsArg)
+			if ref == "" {
+				return clierr.New(clierr.CodeUsage, "--stablecoin is required")
+			}
+			req := map[string]any{"stablecoin": strings.ToLower(ref)}
+			key := cacheKey(trimRootPath(cmd.CommandPath()), req)
+			return s.runCachedCommand(trimRootPath(cmd.CommandPath()), key, 5*time.Minute, func(ctx context.Context) (any, []model.ProviderStatus, []string, bool, error) {
+				start := time.Now()
+				data, err := s.marketProvider.StablecoinDetails(ctx, ref)
+				status := []model.ProviderStatus{{Name: s.marketProvider.Info().Name, Status: statusFromErr(err), LatencyMS: time.Since(start).Milliseconds()}}
+				return data, status, nil, false, err
+			})
+		},
+	}
+	detailsCmd.Flags().StringVar(&detailsArg, "stablecoin", "", "Stablecoin symbol, name, or DefiLlama id (for example USDC)")
+	_ = detailsCmd.MarkFlagRequired("stablecoin")
+	detailsResponse := schema.SchemaFromType(model.StablecoinDetails{})
+	_ = schema.SetCommandMetadata(detailsCmd, schema.CommandMetadata{Response: &detailsResponse})
+	root.AddCommand(detailsCmd)
+
 	return root
 }
 
@@ -711,6 +1512,19 @@ func (s *runtimeState) newAssetsCommand() *cobra.Command {
 				ResolvedBy:  "registry",
 				Unambiguous: true,
 			}
+			if id.IsUserToken(chain.CAIP2, asset.Address) {
+				result.ResolvedBy = "user_registry"
+			} else if source := s.tokenLookupSource(chain.CAIP2, asset.Address); source != "" {
+				result.ResolvedBy = source
+			}
+			for _, equivalent := range id.EquivalentAssets(asset, chain, id.DefaultResolveOptions()) {
+				result.Equivalents = append(result.Equivalents, model.AssetEquivalent{
+					Symbol:   equivalent.Symbol,
+					AssetID:  equivalent.AssetID,
+					Address:  equivalent.Address,
+					Decimals: equivalent.Decimals,
+				})
+			}
 			return s.emitSuccess(trimRootPath(cmd.CommandPath()), result, nil, cacheMetaBypass(), nil, false)
 		},
 	}
@@ -718,6 +1532,10 @@ func (s *runtimeState) newAssetsCommand() *cobra.Command {
 	cmd.Flags().StringVar(&symbol, "symbol", "", "Asset symbol (e.g., USDC)")
 	cmd.Flags().StringVar(&input, "asset", "", "Asset as CAIP-19 or token address")
 	root.AddCommand(cmd)
+	root.AddCommand(s.newAssetsAddCommand())
+	root.AddCommand(s.newAssetsRemoveCommand())
+	root.AddCommand(s.newAssetsListCommand())
+	root.AddCommand(s.newAssetsInspectCommand())
 	return root
 }
 
@@ -728,6 +1546,8 @@ func (s *runtimeState) newLendCommand() *cobra.Command {
 	var assetArg string
 	var marketsLimit int
 	var marketsRPCURL string
+	var marketsBackend string
+	var marketsPage pageFlags
 
 	marketsCmd := &cobra.Command{
 		Use:   "markets",
@@ -737,14 +1557,22 @@ func (s *runtimeState) newLendCommand() *cobra.Command {
 			if providerName == "" {
 				return clierr.New(clierr.CodeUsage, "--provider is required")
 			}
+			backend, err := parseDataBackend(marketsBackend)
+			if err != nil {
+				return err
+			}
 			chain, asset, err := parseChainAsset(chainArg, assetArg)
 			if err != nil {
 				return err
 			}
-			req := map[string]any{"provider": providerName, "chain": chain.CAIP2, "asset": asset.AssetID, "limit": marketsLimit, "rpc_url": strings.TrimSpace(marketsRPCURL)}
+			limit := marketsPage.limit(cmd, marketsLimit)
+			req := map[string]any{"provider": providerName, "chain": chain.CAIP2, "asset": asset.AssetID, "limit": limit, "rpc_url": strings.TrimSpace(marketsRPCURL), "backend": backend}
 			key := cacheKey(trimRootPath(cmd.CommandPath()), req)
+			if err := s.startListPage(marketsPage, key); err != nil {
+				return err
+			}
 			return s.runCachedCommand(trimRootPath(cmd.CommandPath()), key, 60*time.Second, func(ctx context.Context) (any, []model.ProviderStatus, []string, bool, error) {
-				provider, err := s.selectLendingProvider(providerName)
+				provider, err := s.selectLendingProviderBackend(providerName, backend)
 				if err != nil {
 					return nil, nil, nil, false, err
 				}
@@ -756,23 +1584,28 @@ func (s *runtimeState) newLendCommand() *cobra.Command {
 				if err != nil {
 					return nil, statuses, nil, false, err
 				}
-				data = applyLendMarketLimit(data, marketsLimit)
+				data = applyLendMarketLimit(data, limit)
 				return data, statuses, nil, false, nil
 			})
 		},
 	}
-	marketsCmd.Flags().StringVar(&providerArg, "provider", "", "Lending provider (aave, morpho, kamino, moonwell)")
+	marketsCmd.Flags().StringVar(&providerArg, "provider", "", "Lending provider (aave, morpho, kamino, moonwell, spark)")
 	marketsCmd.Flags().StringVar(&chainArg, "chain", "", "Chain identifier")
 	marketsCmd.Flags().StringVar(&assetArg, "asset", "", "Asset (symbol/address/CAIP-19)")
 	marketsCmd.Flags().IntVar(&marketsLimit, "limit", 20, "Maximum lending markets to return")
 	marketsCmd.Flags().StringVar(&marketsRPCURL, "rpc-url", "", "Optional RPC URL override for on-chain providers")
+	marketsCmd.Flags().StringVar(&marketsBackend, "backend", "api", "Data backend: api|subgraph (subgraph reads The Graph; aave only, needs DEFI_THEGRAPH_API_KEY)")
+	marketsPage.register(marketsCmd)
 	_ = marketsCmd.MarkFlagRequired("provider")
 	_ = marketsCmd.MarkFlagRequired("chain")
 	_ = marketsCmd.MarkFlagRequired("asset")
+	marketsResponse := schema.SchemaFromType([]model.LendMarket{})
+	_ = schema.SetCommandMetadata(marketsCmd, schema.CommandMetadata{Response: &marketsResponse})
 
 	var ratesProvider, ratesChain, ratesAsset string
 	var ratesLimit int
 	var ratesRPCURL string
+	var ratesBackend string
 	ratesCmd := &cobra.Command{
 		Use:   "rates",
 		Short: "List lending rates",
@@ -781,38 +1614,38 @@ func (s *runtimeState) newLendCommand() *cobra.Command {
 			if providerName == "" {
 				return clierr.New(clierr.CodeUsage, "--provider is required")
 			}
+			backend, err := parseDataBackend(ratesBackend)
+			if err != nil {
+				return err
+			}
 			chain, asset, err := parseChainAsset(ratesChain, ratesAsset)
 			if err != nil {
 				return err
 			}
-			req := map[string]any{"provider": providerName, "chain": chain.CAIP2, "asset": asset.AssetID, "limit": ratesLimit, "rpc_url": strings.TrimSpace(ratesRPCURL)}
+			req := map[string]any{"provider": providerName, "chain": chain.CAIP2, "asset": asset.AssetID, "limit": ratesLimit, "rpc_url": strings.TrimSpace(ratesRPCURL), "backend": backend}
 			key := cacheKey(trimRootPath(cmd.CommandPath()), req)
 			return s.runCachedCommand(trimRootPath(cmd.CommandPath()), key, 30*time.Second, func(ctx context.Context) (any, []model.ProviderStatus, []string, bool, error) {
-				provider, err := s.selectLendingProvider(providerName)
-				if err != nil {
-					return nil, nil, nil, false, err
-				}
-				applyRPCOverride(provider, ratesRPCURL)
-
-				start := time.Now()
-				data, err := provider.LendRates(ctx, providerName, chain, asset)
-				statuses := []model.ProviderStatus{{Name: provider.Info().Name, Status: statusFromErr(err), LatencyMS: time.Since(start).Milliseconds()}}
+				data, statuses, warnings, err := s.lendRates(ctx, providerName, backend, chain, asset, ratesRPCURL)
 				if err != nil {
-					return nil, statuses, nil, false, err
+					return nil, statuses, warnings, false, err
 				}
+				yieldutil.ApplyLendRateEffectiveAPY(data)
 				data = applyLendRateLimit(data, ratesLimit)
-				return data, statuses, nil, false, nil
+				return data, statuses, warnings, false, nil
 			})
 		},
 	}
-	ratesCmd.Flags().StringVar(&ratesProvider, "provider", "", "Lending provider (aave, morpho, kamino, moonwell)")
+	ratesCmd.Flags().StringVar(&ratesProvider, "provider", "", "Lending provider (aave, morpho, kamino, moonwell, spark, compound)")
 	ratesCmd.Flags().StringVar(&ratesChain, "chain", "", "Chain identifier")
 	ratesCmd.Flags().StringVar(&ratesAsset, "asset", "", "Asset (symbol/address/CAIP-19)")
 	ratesCmd.Flags().IntVar(&ratesLimit, "limit", 20, "Maximum lending rates to return")
 	ratesCmd.Flags().StringVar(&ratesRPCURL, "rpc-url", "", "Optional RPC URL override for on-chain providers")
+	ratesCmd.Flags().StringVar(&ratesBackend, "backend", "api", "Data backend: api|subgraph (subgraph reads The Graph; aave only, needs DEFI_THEGRAPH_API_KEY)")
 	_ = ratesCmd.MarkFlagRequired("provider")
 	_ = ratesCmd.MarkFlagRequired("chain")
 	_ = ratesCmd.MarkFlagRequired("asset")
+	ratesResponse := schema.SchemaFromType([]model.LendRate{})
+	_ = schema.SetCommandMetadata(ratesCmd, schema.CommandMetadata{Response: &ratesResponse})
 
 	var positionsProvider, positionsChain, positionsAddress, positionsAsset, positionsType, positionsRPCURL string
 	var positionsLimit int
@@ -883,7 +1716,7 @@ func (s *runtimeState) newLendCommand() *cobra.Command {
 			})
 		},
 	}
-	positionsCmd.Flags().StringVar(&positionsProvider, "provider", "", "Lending provider (aave, morpho, moonwell)")
+	positionsCmd.Flags().StringVar(&positionsProvider, "provider", "", "Lending provider (aave, morpho, moonwell, kamino)")
 	positionsCmd.Flags().StringVar(&positionsChain, "chain", "", "Chain identifier")
 	positionsCmd.Flags().StringVar(&positionsAddress, "address", "", "Position owner address")
 	positionsCmd.Flags().StringVar(&positionsAsset, "asset", "", "Optional asset filter (symbol/address/CAIP-19)")
@@ -893,10 +1726,14 @@ func (s *runtimeState) newLendCommand() *cobra.Command {
 	_ = positionsCmd.MarkFlagRequired("provider")
 	_ = positionsCmd.MarkFlagRequired("chain")
 	_ = positionsCmd.MarkFlagRequired("address")
+	positionsResponse := schema.SchemaFromType([]model.LendPosition{})
+	_ = schema.SetCommandMetadata(positionsCmd, schema.CommandMetadata{Response: &positionsResponse})
 
 	root.AddCommand(marketsCmd)
 	root.AddCommand(ratesCmd)
 	root.AddCommand(positionsCmd)
+	root.AddCommand(s.newLendHealthCommand())
+	root.AddCommand(s.newLendBorrowQuoteCommand())
 	s.addLendExecutionSubcommands(root)
 	return root
 }
@@ -904,19 +1741,30 @@ func (s *runtimeState) newLendCommand() *cobra.Command {
 func (s *runtimeState) newBridgeCommand() *cobra.Command {
 	root := &cobra.Command{Use: "bridge", Short: "Bridge quote and analytics commands"}
 
-	var quoteProviderArg, fromArg, toArg, assetArg, toAssetArg, fromAmountForGas string
-	var amountBase, amountDecimal string
+	var quoteProviderArg, compareArg, fromArg, toArg, assetArg, toAssetArg, fromAmountForGas string
+	var amountBase, amountDecimal, amountUSDArg string
+	var archiveBridgeQuote bool
 	quoteCmd := &cobra.Command{
 		Use:   "quote",
 		Short: "Get bridge quote",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			providerName := strings.ToLower(strings.TrimSpace(quoteProviderArg))
 			if providerName == "" {
-				return clierr.New(clierr.CodeUsage, "--provider is required (across|lifi)")
+				return clierr.New(clierr.CodeUsage, "--provider is required (across|lifi|bungee|wormhole|cctp)")
 			}
-			provider, ok := s.bridgeProviders[providerName]
-			if !ok {
-				return clierr.New(clierr.CodeUnsupported, "unsupported bridge provider")
+			providerNames := []string{providerName}
+			for _, name := range splitCSV(compareArg) {
+				if !slices.Contains(providerNames, name) {
+					providerNames = append(providerNames, name)
+				}
+			}
+			for _, name := range providerNames {
+				if _, ok := s.bridgeProviders[name]; !ok {
+					return clierr.New(clierr.CodeUnsupported, "unsupported bridge provider "+name)
+				}
+				if err := s.protocolRules().Check(name); err != nil {
+					return err
+				}
 			}
 			fromChain, err := id.ParseChain(fromArg)
 			if err != nil {
@@ -930,24 +1778,18 @@ func (s *runtimeState) newBridgeCommand() *cobra.Command {
 			if err != nil {
 				return err
 			}
-			toAssetInput := strings.TrimSpace(toAssetArg)
-			if toAssetInput == "" {
-				if fromAsset.Symbol != "" {
-					toAssetInput = fromAsset.Symbol
-				} else {
-					return clierr.New(clierr.CodeUsage, "destination asset cannot be inferred, provide --to-asset")
-				}
-			}
-			toAsset, err := id.ParseAsset(toAssetInput, toChain)
+			toAsset, err := parseBridgeDestinationAsset(fromAsset, toAssetArg, toChain)
 			if err != nil {
-				return clierr.Wrap(clierr.CodeUsage, "resolve destination asset", err)
+				return err
 			}
 
 			decimals := fromAsset.Decimals
 			if decimals <= 0 {
 				decimals = 18
 			}
-			base, decimal, err := id.NormalizeAmount(amountBase, amountDecimal, decimals)
+			amountCtx, cancel := context.WithTimeout(context.Background(), s.settings.Timeout)
+			base, decimal, amountUSD, err := s.normalizeAmountInput(amountCtx, fromChain, fromAsset, amountBase, amountDecimal, amountUSDArg, decimals)
+			cancel()
 			if err != nil {
 				return err
 			}
@@ -963,29 +1805,88 @@ func (s *runtimeState) newBridgeCommand() *cobra.Command {
 			}
 			key := cacheKey(trimRootPath(cmd.CommandPath()), map[string]any{
 				"provider":            providerName,
+				"compare":             providerNames[1:],
 				"from":                fromChain.CAIP2,
 				"to":                  toChain.CAIP2,
 				"from_asset":          fromAsset.AssetID,
 				"to_asset":            toAsset.AssetID,
 				"amount":              base,
+				"amount_usd":          amountUSD,
 				"from_amount_for_gas": reqStruct.FromAmountForGas,
+				"deny_protocols":      s.settings.DenyProtocols,
 			})
-			return s.runCachedCommand(trimRootPath(cmd.CommandPath()), key, 15*time.Second, func(ctx context.Context) (any, []model.ProviderStatus, []string, bool, error) {
+			if archiveBridgeQuote {
+				// Only fresh quotes are archived, so a cached response
+				// would leave the archive without this observation.
+				s.settings.ForceFresh = true
+			}
+			quoteOne := func(ctx context.Context, name string) (model.BridgeQuote, model.ProviderStatus, []string, error) {
+				provider := s.bridgeProviders[name]
 				start := time.Now()
 				data, err := provider.QuoteBridge(ctx, reqStruct)
-				status := []model.ProviderStatus{{Name: provider.Info().Name, Status: statusFromErr(err), LatencyMS: time.Since(start).Milliseconds()}}
-				return data, status, nil, false, err
+				status := model.ProviderStatus{Name: provider.Info().Name, Status: statusFromErr(err), LatencyMS: time.Since(start).Milliseconds()}
+				if err == nil {
+					err = s.protocolRules().CheckRoute(data.Route)
+				}
+				if err != nil {
+					return data, status, nil, err
+				}
+				data.InputAmount.AmountUSD = amountUSD
+				providers.ApplyBridgeQuoteExpiry(&data)
+				var warnings []string
+				if safety, ok := bridgesafety.ForQuote(data.Provider, data.Route); ok {
+					data.Safety = &safety
+					if safety.MaxRecommendedUSD == 0 {
+						warnings = append(warnings, fmt.Sprintf("bridge %s is not recommended for transfers: %s", safety.Bridge, safety.Notes))
+					}
+				}
+				if archiveBridgeQuote {
+					warnings = append(warnings, s.archiveQuote(quotes.FromBridgeQuote(data, s.runner.now().UTC().Format(time.RFC3339)))...)
+				}
+				return data, status, warnings, nil
+			}
+			return s.runCachedCommand(trimRootPath(cmd.CommandPath()), key, 15*time.Second, func(ctx context.Context) (any, []model.ProviderStatus, []string, bool, error) {
+				if len(providerNames) == 1 {
+					data, status, warnings, err := quoteOne(ctx, providerName)
+					return data, []model.ProviderStatus{status}, warnings, false, err
+				}
+				statuses := make([]model.ProviderStatus, 0, len(providerNames))
+				warnings := []string{}
+				quoted := make([]model.BridgeQuote, 0, len(providerNames))
+				partial := false
+				var firstErr error
+				for _, name := range providerNames {
+					data, status, quoteWarnings, err := quoteOne(ctx, name)
+					statuses = append(statuses, status)
+					if err != nil {
+						warnings = append(warnings, fmt.Sprintf("provider %s failed: %v", name, err))
+						partial = true
+						if firstErr == nil {
+							firstErr = err
+						}
+						continue
+					}
+					warnings = append(warnings, quoteWarnings...)
+					quoted = append(quoted, data)
+				}
+				if len(quoted) == 0 {
+					return nil, statuses, warnings, false, firstErr
+				}
+				return rankBridgeQuotes(quoted), statuses, warnings, partial, nil
 			})
 		},
 	}
-	quoteCmd.Flags().StringVar(&quoteProviderArg, "provider", "", "Bridge provider (across|lifi|bungee; no API key required)")
+	quoteCmd.Flags().StringVar(&quoteProviderArg, "provider", "", "Bridge provider (across|lifi|bungee|wormhole|cctp; no API key required)")
+	quoteCmd.Flags().StringVar(&compareArg, "compare", "", "Also quote these bridge providers (comma-separated) and rank all quotes by output, fee, and ETA")
 	quoteCmd.Flags().StringVar(&fromArg, "from", "", "Source chain")
 	quoteCmd.Flags().StringVar(&toArg, "to", "", "Destination chain")
 	quoteCmd.Flags().StringVar(&assetArg, "asset", "", "Asset (symbol/address/CAIP-19) on source chain")
 	quoteCmd.Flags().StringVar(&toAssetArg, "to-asset", "", "Destination asset override (symbol/address/CAIP-19)")
 	quoteCmd.Flags().StringVar(&amountBase, "amount", "", "Amount in base units")
 	quoteCmd.Flags().StringVar(&amountDecimal, "amount-decimal", "", "Amount in decimal units")
+	quoteCmd.Flags().StringVar(&amountUSDArg, "amount-usd", "", "Amount as a USD notional, converted at the current asset price")
 	quoteCmd.Flags().StringVar(&fromAmountForGas, "from-amount-for-gas", "", "Optional amount in source token base units to reserve for destination native gas (LiFi)")
+	quoteCmd.Flags().BoolVar(&archiveBridgeQuote, "archive-quotes", false, "Persist the returned quote to the local quote archive (see quotes history)")
 	_ = quoteCmd.MarkFlagRequired("from")
 	_ = quoteCmd.MarkFlagRequired("to")
 	_ = quoteCmd.MarkFlagRequired("asset")
@@ -996,12 +1897,14 @@ func (s *runtimeState) newBridgeCommand() *cobra.Command {
 	_ = schema.SetFlagMetadata(quoteCmd.Flags(), "to-asset", schema.FlagMetadata{Format: "asset"})
 	_ = schema.SetFlagMetadata(quoteCmd.Flags(), "amount", schema.FlagMetadata{Format: "base-units"})
 	_ = schema.SetFlagMetadata(quoteCmd.Flags(), "amount-decimal", schema.FlagMetadata{Format: "decimal-amount"})
+	_ = schema.SetFlagMetadata(quoteCmd.Flags(), "amount-usd", schema.FlagMetadata{Format: "decimal-amount"})
 	_ = schema.SetFlagMetadata(quoteCmd.Flags(), "from-amount-for-gas", schema.FlagMetadata{Format: "base-units"})
 	bridgeQuoteResponse := schema.SchemaFromType(model.BridgeQuote{})
 	annotateStructuredFlagCommand(quoteCmd, structuredInputOptions{Response: &bridgeQuoteResponse})
 
 	var listLimit int
 	var includeChains bool
+	var listPage pageFlags
 	listCmd := &cobra.Command{
 		Use:   "list",
 		Short: "List bridge volumes and coverage (DefiLlama key required)",
@@ -1012,7 +1915,7 @@ func (s *runtimeState) newBridgeCommand() *cobra.Command {
 				return clierr.New(clierr.CodeUnsupported, "bridge data provider is not configured")
 			}
 			req := providers.BridgeListRequest{
-				Limit:         listLimit,
+				Limit:         listPage.limit(cmd, listLimit),
 				IncludeChains: includeChains,
 			}
 			key := cacheKey(trimRootPath(cmd.CommandPath()), map[string]any{
@@ -1020,16 +1923,25 @@ func (s *runtimeState) newBridgeCommand() *cobra.Command {
 				"limit":          req.Limit,
 				"include_chains": req.IncludeChains,
 			})
+			if err := s.startListPage(listPage, key); err != nil {
+				return err
+			}
 			return s.runCachedCommand(trimRootPath(cmd.CommandPath()), key, 60*time.Second, func(ctx context.Context) (any, []model.ProviderStatus, []string, bool, error) {
 				start := time.Now()
 				data, err := provider.ListBridges(ctx, req)
 				status := []model.ProviderStatus{{Name: provider.Info().Name, Status: statusFromErr(err), LatencyMS: time.Since(start).Milliseconds()}}
+				for i := range data {
+					if safety, ok := bridgesafety.Lookup(data[i].Name + " " + data[i].DisplayName); ok {
+						data[i].Safety = &safety
+					}
+				}
 				return data, status, nil, false, err
 			})
 		},
 	}
 	listCmd.Flags().IntVar(&listLimit, "limit", 20, "Maximum bridges to return")
 	listCmd.Flags().BoolVar(&includeChains, "include-chains", true, "Include chain coverage for each bridge")
+	listPage.register(listCmd)
 	bridgeListResponse := schema.SchemaFromType([]model.BridgeSummary{})
 	_ = schema.SetCommandMetadata(listCmd, schema.CommandMetadata{
 		Auth: []schema.AuthRequirement{{
@@ -1088,6 +2000,39 @@ func (s *runtimeState) newBridgeCommand() *cobra.Command {
 	return root
 }
 
+// rankBridgeQuotes orders quotes by estimated output and names the best
+// output, lowest USD fee, and fastest provider. Outputs are compared in
+// decimal units because destination tokens may differ in decimals.
+func rankBridgeQuotes(items []model.BridgeQuote) model.BridgeQuoteComparison {
+	outDecimal := func(q model.BridgeQuote) float64 {
+		v, _ := strconv.ParseFloat(q.EstimatedOut.AmountDecimal, 64)
+		return v
+	}
+	sorted := append([]model.BridgeQuote(nil), items...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return outDecimal(sorted[i]) > outDecimal(sorted[j])
+	})
+	out := model.BridgeQuoteComparison{Quotes: sorted}
+	if len(sorted) == 0 {
+		return out
+	}
+	out.BestOutputProvider = sorted[0].Provider
+	cheapest, fastest := -1, -1
+	for i, q := range sorted {
+		if cheapest < 0 || q.EstimatedFeeUSD < sorted[cheapest].EstimatedFeeUSD {
+			cheapest = i
+		}
+		if q.EstimatedTimeS > 0 && (fastest < 0 || q.EstimatedTimeS < sorted[fastest].EstimatedTimeS) {
+			fastest = i
+		}
+	}
+	out.CheapestProvider = sorted[cheapest].Provider
+	if fastest >= 0 {
+		out.FastestProvider = sorted[fastest].Provider
+	}
+	return out
+}
+
 func (s *runtimeState) newSwapCommand() *cobra.Command {
 	root := &cobra.Command{Use: "swap", Short: "Swap quote and execution commands"}
 
@@ -1105,7 +2050,7 @@ func (s *runtimeState) newSwapCommand() *cobra.Command {
 
 	swapProviderSupportsExactOutput := func(providerName string) bool {
 		switch providers.NormalizeSwapProvider(providerName) {
-		case "uniswap", "tempo":
+		case "uniswap", "tempo", "1inch", "jupiter":
 			return true
 		default:
 			return false
@@ -1115,41 +2060,44 @@ func (s *runtimeState) newSwapCommand() *cobra.Command {
 	parseSwapRequest := func(
 		chainArg, fromAssetArg, toAssetArg string,
 		tradeType providers.SwapTradeType,
-		amountBase, amountDecimal, amountOutBase, amountOutDecimal, rpcURL string,
-	) (providers.SwapQuoteRequest, error) {
+		amountBase, amountDecimal, amountUSD, amountOutBase, amountOutDecimal, rpcURL string,
+	) (providers.SwapQuoteRequest, float64, error) {
 		chain, err := id.ParseChain(chainArg)
 		if err != nil {
-			return providers.SwapQuoteRequest{}, err
+			return providers.SwapQuoteRequest{}, 0, err
 		}
 		fromAsset, err := id.ParseAsset(fromAssetArg, chain)
 		if err != nil {
-			return providers.SwapQuoteRequest{}, err
+			return providers.SwapQuoteRequest{}, 0, err
 		}
 		toAsset, err := id.ParseAsset(toAssetArg, chain)
 		if err != nil {
-			return providers.SwapQuoteRequest{}, err
+			return providers.SwapQuoteRequest{}, 0, err
 		}
 
 		var base, decimal string
+		var amountUSDValue float64
 		switch tradeType {
 		case providers.SwapTradeTypeExactInput:
 			if amountOutBase != "" || amountOutDecimal != "" {
-				return providers.SwapQuoteRequest{}, clierr.New(clierr.CodeUsage, "--amount-out/--amount-out-decimal are only valid with --type exact-output")
+				return providers.SwapQuoteRequest{}, 0, clierr.New(clierr.CodeUsage, "--amount-out/--amount-out-decimal are only valid with --type exact-output")
 			}
 			decimals := fromAsset.Decimals
 			if decimals <= 0 {
 				decimals = 18
 			}
-			base, decimal, err = id.NormalizeAmount(amountBase, amountDecimal, decimals)
+			ctx, cancel := context.WithTimeout(context.Background(), s.settings.Timeout)
+			base, decimal, amountUSDValue, err = s.normalizeAmountInput(ctx, chain, fromAsset, amountBase, amountDecimal, amountUSD, decimals)
+			cancel()
 			if err != nil {
-				return providers.SwapQuoteRequest{}, err
+				return providers.SwapQuoteRequest{}, 0, err
 			}
 		case providers.SwapTradeTypeExactOutput:
-			if amountBase != "" || amountDecimal != "" {
-				return providers.SwapQuoteRequest{}, clierr.New(clierr.CodeUsage, "--amount/--amount-decimal are only valid with --type exact-input")
+			if amountBase != "" || amountDecimal != "" || amountUSD != "" {
+				return providers.SwapQuoteRequest{}, 0, clierr.New(clierr.CodeUsage, "--amount/--amount-decimal/--amount-usd are only valid with --type exact-input")
 			}
 			if amountOutBase == "" && amountOutDecimal == "" {
-				return providers.SwapQuoteRequest{}, clierr.New(clierr.CodeUsage, "exact-output requires --amount-out or --amount-out-decimal")
+				return providers.SwapQuoteRequest{}, 0, clierr.New(clierr.CodeUsage, "exact-output requires --amount-out or --amount-out-decimal")
 			}
 			decimals := toAsset.Decimals
 			if decimals <= 0 {
@@ -1157,10 +2105,10 @@ func (s *runtimeState) newSwapCommand() *cobra.Command {
 			}
 			base, decimal, err = id.NormalizeAmount(amountOutBase, amountOutDecimal, decimals)
 			if err != nil {
-				return providers.SwapQuoteRequest{}, err
+				return providers.SwapQuoteRequest{}, 0, err
 			}
 		default:
-			return providers.SwapQuoteRequest{}, clierr.New(clierr.CodeUsage, "--type must be exact-input or exact-output")
+			return providers.SwapQuoteRequest{}, 0, clierr.New(clierr.CodeUsage, "--type must be exact-input or exact-output")
 		}
 
 		return providers.SwapQuoteRequest{
@@ -1171,39 +2119,69 @@ func (s *runtimeState) newSwapCommand() *cobra.Command {
 			AmountDecimal:   decimal,
 			RPCURL:          strings.TrimSpace(rpcURL),
 			TradeType:       tradeType,
-		}, nil
+		}, amountUSDValue, nil
 	}
 
 	var quoteProviderArg, quoteChainArg, quoteFromAssetArg, quoteToAssetArg, quoteTradeTypeArg string
-	var quoteAmountBase, quoteAmountDecimal, quoteAmountOutBase, quoteAmountOutDecimal, quoteRPCURL string
-	var quoteFromAddress string
+	var quoteAmountBase, quoteAmountDecimal, quoteAmountUSD, quoteAmountOutBase, quoteAmountOutDecimal, quoteRPCURL string
+	var quoteFromAddress, quoteFromAssetsArg, quoteFromChainArg, quoteToChainArg string
 	var quoteSlippagePct float64
+	var quoteArchive bool
 	quoteCmd := &cobra.Command{
 		Use:   "quote",
 		Short: "Get swap quote",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			providerName := providers.NormalizeSwapProvider(quoteProviderArg)
 			if providerName == "" {
-				return clierr.New(clierr.CodeUsage, "--provider is required (1inch|uniswap|tempo|taikoswap|jupiter|fibrous|bungee)")
+				return clierr.New(clierr.CodeUsage, "--provider is required (1inch|uniswap|tempo|taikoswap|jupiter|fibrous|bungee|cowswap|paraswap|odos|lifi)")
+			}
+			crossChain, err := validateCrossChainSwapFlags(quoteChainArg, quoteFromChainArg, quoteToChainArg)
+			if err != nil {
+				return err
+			}
+			if crossChain {
+				if tradeType, err := normalizeTradeType(quoteTradeTypeArg); err != nil || tradeType != providers.SwapTradeTypeExactInput {
+					return clierr.New(clierr.CodeUnsupported, "cross-chain swap quotes support only --type exact-input")
+				}
+				if strings.TrimSpace(quoteFromAssetsArg) != "" {
+					return clierr.New(clierr.CodeUsage, "--from-assets cannot be combined with --from-chain/--to-chain")
+				}
+				in := crossChainSwapQuoteInput{
+					Provider:      providerName,
+					FromChain:     quoteFromChainArg,
+					ToChain:       quoteToChainArg,
+					FromAsset:     quoteFromAssetArg,
+					ToAsset:       quoteToAssetArg,
+					AmountBase:    quoteAmountBase,
+					AmountDecimal: quoteAmountDecimal,
+					AmountUSD:     quoteAmountUSD,
+				}
+				if cmd.Flags().Changed("slippage-pct") {
+					if quoteSlippagePct <= 0 || quoteSlippagePct > 100 {
+						return clierr.New(clierr.CodeUsage, "--slippage-pct must be > 0 and <= 100")
+					}
+					in.SlippagePct = &quoteSlippagePct
+				}
+				return s.quoteCrossChainSwap(cmd, in)
 			}
 			provider, ok := s.swapProviders[providerName]
 			if !ok {
 				return clierr.New(clierr.CodeUnsupported, "unsupported swap provider")
 			}
+			if err := s.protocolRules().Check(providerName); err != nil {
+				return err
+			}
 			tradeType, err := normalizeTradeType(quoteTradeTypeArg)
 			if err != nil {
 				return err
 			}
 			if tradeType == providers.SwapTradeTypeExactOutput && !swapProviderSupportsExactOutput(providerName) {
-				return clierr.New(clierr.CodeUnsupported, "exact-output swap quotes currently support only --provider uniswap or --provider tempo")
+				return clierr.New(clierr.CodeUnsupported, "exact-output swap quotes currently support only --provider uniswap, tempo, 1inch, or jupiter")
 			}
 
 			var slippagePtr *float64
 			slippageMode := "auto"
 			if cmd.Flags().Changed("slippage-pct") {
-				if providerName != "uniswap" {
-					return clierr.New(clierr.CodeUsage, "--slippage-pct is supported only with --provider uniswap")
-				}
 				if quoteSlippagePct <= 0 || quoteSlippagePct > 100 {
 					return clierr.New(clierr.CodeUsage, "--slippage-pct must be > 0 and <= 100")
 				}
@@ -1219,70 +2197,148 @@ func (s *runtimeState) newSwapCommand() *cobra.Command {
 				return clierr.New(clierr.CodeUsage, "--from-address is required for --provider uniswap")
 			}
 
-			reqStruct, err := parseSwapRequest(
-				quoteChainArg,
-				quoteFromAssetArg,
-				quoteToAssetArg,
-				tradeType,
-				quoteAmountBase,
-				quoteAmountDecimal,
-				quoteAmountOutBase,
-				quoteAmountOutDecimal,
-				quoteRPCURL,
-			)
-			if err != nil {
-				return err
+			var reqStruct providers.SwapQuoteRequest
+			var amountUSD float64
+			if strings.TrimSpace(quoteFromAssetsArg) != "" {
+				if providerName != "odos" {
+					return clierr.New(clierr.CodeUnsupported, "--from-assets is supported only with --provider odos")
+				}
+				if quoteFromAssetArg != "" || quoteAmountBase != "" || quoteAmountDecimal != "" || quoteAmountUSD != "" {
+					return clierr.New(clierr.CodeUsage, "--from-assets cannot be combined with --from-asset, --amount, --amount-decimal, or --amount-usd")
+				}
+				if tradeType != providers.SwapTradeTypeExactInput {
+					return clierr.New(clierr.CodeUsage, "--from-assets requires --type exact-input")
+				}
+				chain, err := id.ParseChain(quoteChainArg)
+				if err != nil {
+					return err
+				}
+				toAsset, err := id.ParseAsset(quoteToAssetArg, chain)
+				if err != nil {
+					return err
+				}
+				inputs, err := parseSwapInputs(quoteFromAssetsArg, chain)
+				if err != nil {
+					return err
+				}
+				reqStruct = providers.SwapQuoteRequest{
+					Chain:           chain,
+					FromAsset:       inputs[0].Asset,
+					ToAsset:         toAsset,
+					AmountBaseUnits: inputs[0].AmountBaseUnits,
+					AmountDecimal:   inputs[0].AmountDecimal,
+					RPCURL:          strings.TrimSpace(quoteRPCURL),
+					TradeType:       tradeType,
+					Inputs:          inputs,
+				}
+			} else {
+				if strings.TrimSpace(quoteFromAssetArg) == "" {
+					return clierr.New(clierr.CodeUsage, "--from-asset or --from-assets is required")
+				}
+				reqStruct, amountUSD, err = parseSwapRequest(
+					quoteChainArg,
+					quoteFromAssetArg,
+					quoteToAssetArg,
+					tradeType,
+					quoteAmountBase,
+					quoteAmountDecimal,
+					quoteAmountUSD,
+					quoteAmountOutBase,
+					quoteAmountOutDecimal,
+					quoteRPCURL,
+				)
+				if err != nil {
+					return err
+				}
 			}
 			reqStruct.SlippagePct = slippagePtr
 			reqStruct.Swapper = swapper
 			key := cacheKey(trimRootPath(cmd.CommandPath()), map[string]any{
-				"provider":      providerName,
-				"chain":         reqStruct.Chain.CAIP2,
-				"from":          reqStruct.FromAsset.AssetID,
-				"to":            reqStruct.ToAsset.AssetID,
-				"trade_type":    reqStruct.TradeType,
-				"amount":        reqStruct.AmountBaseUnits,
-				"slippage_mode": slippageMode,
-				"slippage_pct":  reqStruct.SlippagePct,
-				"swapper":       strings.ToLower(reqStruct.Swapper),
-				"rpc_url":       reqStruct.RPCURL,
+				"provider":       providerName,
+				"chain":          reqStruct.Chain.CAIP2,
+				"from":           reqStruct.FromAsset.AssetID,
+				"inputs":         swapInputKeys(reqStruct.Inputs),
+				"to":             reqStruct.ToAsset.AssetID,
+				"trade_type":     reqStruct.TradeType,
+				"amount":         reqStruct.AmountBaseUnits,
+				"amount_usd":     amountUSD,
+				"slippage_mode":  slippageMode,
+				"slippage_pct":   reqStruct.SlippagePct,
+				"swapper":        strings.ToLower(reqStruct.Swapper),
+				"rpc_url":        reqStruct.RPCURL,
+				"deny_protocols": s.settings.DenyProtocols,
 			})
+			if quoteArchive {
+				// Only fresh quotes are archived, so a cached response
+				// would leave the archive without this observation.
+				s.settings.ForceFresh = true
+			}
 			return s.runCachedCommand(trimRootPath(cmd.CommandPath()), key, 15*time.Second, func(ctx context.Context) (any, []model.ProviderStatus, []string, bool, error) {
 				start := time.Now()
 				data, err := provider.QuoteSwap(ctx, reqStruct)
 				status := []model.ProviderStatus{{Name: provider.Info().Name, Status: statusFromErr(err), LatencyMS: time.Since(start).Milliseconds()}}
-				return data, status, nil, false, err
+				if err == nil {
+					err = s.protocolRules().CheckRoute(data.Route)
+					data.InputAmount.AmountUSD = amountUSD
+					if data.SlippagePct == 0 {
+						providers.ApplySwapSlippage(&data, providers.SwapSlippagePct(reqStruct))
+					}
+					providers.ApplySwapQuoteExpiry(&data)
+				}
+				if err != nil || !quoteArchive {
+					return data, status, nil, false, err
+				}
+				warnings := s.archiveQuote(quotes.FromSwapQuote(data, s.runner.now().UTC().Format(time.RFC3339)))
+				return data, status, warnings, false, nil
 			})
 		},
 	}
-	quoteCmd.Flags().StringVar(&quoteProviderArg, "provider", "", "Swap provider (1inch|uniswap|tempo|taikoswap|jupiter|fibrous|bungee)")
+	quoteCmd.Flags().StringVar(&quoteProviderArg, "provider", "", "Swap provider (1inch|uniswap|tempo|taikoswap|jupiter|fibrous|bungee|cowswap|paraswap|odos; lifi|bungee for cross-chain)")
 	quoteCmd.Flags().StringVar(&quoteChainArg, "chain", "", "Chain identifier")
+	quoteCmd.Flags().StringVar(&quoteFromChainArg, "from-chain", "", "Source chain of a cross-chain swap (with --to-chain, instead of --chain)")
+	quoteCmd.Flags().StringVar(&quoteToChainArg, "to-chain", "", "Destination chain of a cross-chain swap; --to-asset resolves on this chain")
 	quoteCmd.Flags().StringVar(&quoteFromAssetArg, "from-asset", "", "Input asset")
+	quoteCmd.Flags().StringVar(&quoteFromAssetsArg, "from-assets", "", "Multi-input legs as asset:decimal-amount pairs, comma-separated (Odos only; e.g. USDC:500,DAI:500)")
 	quoteCmd.Flags().StringVar(&quoteToAssetArg, "to-asset", "", "Output asset")
 	quoteCmd.Flags().StringVar(&quoteTradeTypeArg, "type", string(providers.SwapTradeTypeExactInput), "Swap type (exact-input|exact-output)")
 	quoteCmd.Flags().StringVar(&quoteAmountBase, "amount", "", "Exact-input amount in base units")
 	quoteCmd.Flags().StringVar(&quoteAmountDecimal, "amount-decimal", "", "Exact-input amount in decimal units")
+	quoteCmd.Flags().StringVar(&quoteAmountUSD, "amount-usd", "", "Exact-input amount as a USD notional, converted at the current input asset price")
 	quoteCmd.Flags().StringVar(&quoteAmountOutBase, "amount-out", "", "Exact-output amount in base units")
 	quoteCmd.Flags().StringVar(&quoteAmountOutDecimal, "amount-out-decimal", "", "Exact-output amount in decimal units")
-	quoteCmd.Flags().Float64Var(&quoteSlippagePct, "slippage-pct", 0, "Manual max slippage percent override (Uniswap only; default uses provider auto slippage)")
+	quoteCmd.Flags().Float64Var(&quoteSlippagePct, "slippage-pct", 0, "Max slippage percent for min_out (default 0.5; Uniswap defaults to its auto slippage)")
 	quoteCmd.Flags().StringVar(&quoteFromAddress, "from-address", "", "Swapper/sender EOA address (required for --provider uniswap)")
 	quoteCmd.Flags().StringVar(&quoteRPCURL, "rpc-url", "", "RPC URL override for on-chain quote providers")
-	_ = quoteCmd.MarkFlagRequired("chain")
-	_ = quoteCmd.MarkFlagRequired("from-asset")
+	quoteCmd.Flags().BoolVar(&quoteArchive, "archive-quotes", false, "Persist the returned quote to the local quote archive (see quotes history)")
 	_ = quoteCmd.MarkFlagRequired("to-asset")
 	_ = quoteCmd.MarkFlagRequired("provider")
-	_ = schema.SetFlagMetadata(quoteCmd.Flags(), "chain", schema.FlagMetadata{Required: true, Format: "chain"})
-	_ = schema.SetFlagMetadata(quoteCmd.Flags(), "from-asset", schema.FlagMetadata{Required: true, Format: "asset"})
+	_ = schema.SetFlagMetadata(quoteCmd.Flags(), "chain", schema.FlagMetadata{Format: "chain"})
+	_ = schema.SetFlagMetadata(quoteCmd.Flags(), "from-chain", schema.FlagMetadata{Format: "chain"})
+	_ = schema.SetFlagMetadata(quoteCmd.Flags(), "to-chain", schema.FlagMetadata{Format: "chain"})
+	_ = schema.SetFlagMetadata(quoteCmd.Flags(), "from-asset", schema.FlagMetadata{Format: "asset"})
 	_ = schema.SetFlagMetadata(quoteCmd.Flags(), "to-asset", schema.FlagMetadata{Required: true, Format: "asset"})
 	_ = schema.SetFlagMetadata(quoteCmd.Flags(), "type", schema.FlagMetadata{Enum: []string{string(providers.SwapTradeTypeExactInput), string(providers.SwapTradeTypeExactOutput)}})
 	_ = schema.SetFlagMetadata(quoteCmd.Flags(), "amount", schema.FlagMetadata{Format: "base-units"})
 	_ = schema.SetFlagMetadata(quoteCmd.Flags(), "amount-decimal", schema.FlagMetadata{Format: "decimal-amount"})
+	_ = schema.SetFlagMetadata(quoteCmd.Flags(), "amount-usd", schema.FlagMetadata{Format: "decimal-amount"})
 	_ = schema.SetFlagMetadata(quoteCmd.Flags(), "amount-out", schema.FlagMetadata{Format: "base-units"})
 	_ = schema.SetFlagMetadata(quoteCmd.Flags(), "amount-out-decimal", schema.FlagMetadata{Format: "decimal-amount"})
 	_ = schema.SetFlagMetadata(quoteCmd.Flags(), "from-address", schema.FlagMetadata{Format: "evm-address"})
 	_ = schema.SetFlagMetadata(quoteCmd.Flags(), "rpc-url", schema.FlagMetadata{Format: "url"})
 	swapQuoteResponse := schema.SchemaFromType(model.SwapQuote{})
 	annotateStructuredFlagCommand(quoteCmd, structuredInputOptions{
+		InputConstraints: []schema.InputConstraint{
+			{
+				Kind:        "exactly_one_of",
+				Fields:      []string{"from_asset", "from_assets"},
+				Description: "Quotes take a single `from_asset` or, with provider `odos`, multi-input `from_assets` legs.",
+			},
+			{
+				Kind:        "exactly_one_of",
+				Fields:      []string{"chain", "from_chain"},
+				Description: "Single-chain quotes take `chain`; cross-chain quotes take `from_chain` and `to_chain` with provider `lifi` or `bungee`.",
+			},
+		},
 		Auth: []schema.AuthRequirement{
 			{
 				Kind:        "api_key",
@@ -1308,13 +2364,16 @@ func (s *runtimeState) newSwapCommand() *cobra.Command {
 	})
 
 	type swapPlanArgs struct {
-		Provider         string `json:"provider" flag:"provider" required:"true" enum:"taikoswap,tempo"`
-		ChainArg         string `json:"chain" flag:"chain" required:"true" format:"chain"`
+		Provider         string `json:"provider" flag:"provider" required:"true" enum:"taikoswap,tempo,cowswap,paraswap,odos,lifi"`
+		ChainArg         string `json:"chain" flag:"chain" format:"chain"`
+		FromChainArg     string `json:"from_chain" flag:"from-chain" format:"chain"`
+		ToChainArg       string `json:"to_chain" flag:"to-chain" format:"chain"`
 		FromAssetArg     string `json:"from_asset" flag:"from-asset" required:"true" format:"asset"`
 		ToAssetArg       string `json:"to_asset" flag:"to-asset" required:"true" format:"asset"`
 		TradeType        string `json:"type" flag:"type" enum:"exact-input,exact-output"`
 		AmountBase       string `json:"amount" flag:"amount" format:"base-units"`
 		AmountDecimal    string `json:"amount_decimal" flag:"amount-decimal" format:"decimal-amount"`
+		AmountUSD        string `json:"amount_usd" flag:"amount-usd" format:"decimal-amount"`
 		AmountOutBase    string `json:"amount_out" flag:"amount-out" format:"base-units"`
 		AmountOutDecimal string `json:"amount_out_decimal" flag:"amount-out-decimal" format:"decimal-amount"`
 		WalletRef        string `json:"wallet" flag:"wallet" format:"identifier"`
@@ -1322,6 +2381,7 @@ func (s *runtimeState) newSwapCommand() *cobra.Command {
 		Recipient        string `json:"recipient" flag:"recipient" format:"evm-address"`
 		SlippageBps      int64  `json:"slippage_bps" flag:"slippage-bps"`
 		Simulate         bool   `json:"simulate" flag:"simulate"`
+		Preview          bool   `json:"preview" flag:"preview"`
 		RPCURL           string `json:"rpc_url" flag:"rpc-url" format:"url"`
 	}
 	type swapSubmitArgs struct {
@@ -1339,6 +2399,13 @@ func (s *runtimeState) newSwapCommand() *cobra.Command {
 		AllowMaxApproval   bool    `json:"allow_max_approval" flag:"allow-max-approval"`
 		UnsafeProviderTx   bool    `json:"unsafe_provider_tx" flag:"unsafe-provider-tx"`
 		FeeToken           string  `json:"fee_token" flag:"fee-token" format:"evm-address"`
+		PostState          bool    `json:"post_state" flag:"post-state"`
+		MaxPriceImpactPct  float64 `json:"max_price_impact_pct" flag:"max-price-impact-pct"`
+		PrivateTx          bool    `json:"private_tx" flag:"private-tx"`
+		PrivateRelay       string  `json:"private_relay" flag:"private-relay"`
+		PrivateFallback    bool    `json:"private_fallback" flag:"private-fallback"`
+		Fresh              bool    `json:"fresh" flag:"fresh"`
+		MaxRequoteDriftBps int64   `json:"max_requote_drift_bps" flag:"max-requote-drift-bps"`
 	}
 	var plan swapPlanArgs
 	planCmd := &cobra.Command{
@@ -1353,16 +2420,48 @@ func (s *runtimeState) newSwapCommand() *cobra.Command {
 			if err != nil {
 				return err
 			}
+			crossChain, err := validateCrossChainSwapFlags(plan.ChainArg, plan.FromChainArg, plan.ToChainArg)
+			if err != nil {
+				return err
+			}
+			if crossChain {
+				if tradeType != providers.SwapTradeTypeExactInput {
+					return clierr.New(clierr.CodeUnsupported, "cross-chain swap planning supports only --type exact-input")
+				}
+				return s.planCrossChainSwap(cmd, crossChainSwapPlanInput{
+					crossChainSwapQuoteInput: crossChainSwapQuoteInput{
+						Provider:      providerName,
+						FromChain:     plan.FromChainArg,
+						ToChain:       plan.ToChainArg,
+						FromAsset:     plan.FromAssetArg,
+						ToAsset:       plan.ToAssetArg,
+						AmountBase:    plan.AmountBase,
+						AmountDecimal: plan.AmountDecimal,
+						AmountUSD:     plan.AmountUSD,
+					},
+					WalletRef:   plan.WalletRef,
+					FromAddress: plan.FromAddress,
+					Recipient:   plan.Recipient,
+					SlippageBps: plan.SlippageBps,
+					Simulate:    plan.Simulate,
+					Preview:     plan.Preview,
+					RPCURL:      plan.RPCURL,
+				})
+			}
+			if providerName == "lifi" {
+				return clierr.New(clierr.CodeUnsupported, "--provider lifi plans only cross-chain swaps; set --from-chain and --to-chain")
+			}
 			if tradeType == providers.SwapTradeTypeExactOutput && !swapProviderSupportsExactOutput(providerName) {
 				return clierr.New(clierr.CodeUnsupported, "exact-output swap planning currently supports only --provider tempo")
 			}
-			reqStruct, err := parseSwapRequest(
+			reqStruct, amountUSD, err := parseSwapRequest(
 				plan.ChainArg,
 				plan.FromAssetArg,
 				plan.ToAssetArg,
 				tradeType,
 				plan.AmountBase,
 				plan.AmountDecimal,
+				plan.AmountUSD,
 				plan.AmountOutBase,
 				plan.AmountOutDecimal,
 				plan.RPCURL,
@@ -1388,6 +2487,9 @@ func (s *runtimeState) newSwapCommand() *cobra.Command {
 				}
 				sender = common.HexToAddress(plan.FromAddress).Hex()
 			} else {
+				if providerName == "cowswap" && strings.TrimSpace(plan.WalletRef) != "" {
+					return clierr.New(clierr.CodeUnsupported, "cowswap orders are signed off-chain and --wallet cannot sign them yet; use --from-address")
+				}
 				identity, err = resolveExecutionIdentity(plan.WalletRef, plan.FromAddress, plan.ChainArg)
 				if err != nil {
 					return err
@@ -1420,23 +2522,34 @@ func (s *runtimeState) newSwapCommand() *cobra.Command {
 			} else {
 				applyExecutionIdentityToAction(&action, identity)
 			}
+			applyActionAmountUSD(&action, amountUSD)
+			stampSwapQuoteExpiry(&action, reqStruct, s.runner.now())
+			if err := s.checkActionProtocolPolicy(action); err != nil {
+				return err
+			}
+			warnings = append(warnings, prefetchPlannedAction(ctx, &action)...)
 			if err := s.ensureActionStore(); err != nil {
 				return err
 			}
+			recordPlanInvocation(cmd, &action)
 			if err := s.actionStore.Save(action); err != nil {
 				return clierr.Wrap(clierr.CodeInternal, "persist planned action", err)
 			}
 			s.captureCommandDiagnostics(nil, statuses, false)
+			attachPlanPreview(&action, plan.Preview)
 			return s.emitSuccess(trimRootPath(cmd.CommandPath()), action, warnings, cacheMetaBypass(), statuses, false)
 		},
 	}
-	planCmd.Flags().StringVar(&plan.Provider, "provider", "", "Swap execution provider (taikoswap|tempo)")
+	planCmd.Flags().StringVar(&plan.Provider, "provider", "", "Swap execution provider (taikoswap|tempo|cowswap|paraswap|odos; lifi for cross-chain)")
 	planCmd.Flags().StringVar(&plan.ChainArg, "chain", "", "Chain identifier")
+	planCmd.Flags().StringVar(&plan.FromChainArg, "from-chain", "", "Source chain of a cross-chain swap (with --to-chain, instead of --chain)")
+	planCmd.Flags().StringVar(&plan.ToChainArg, "to-chain", "", "Destination chain of a cross-chain swap; --to-asset resolves on this chain")
 	planCmd.Flags().StringVar(&plan.FromAssetArg, "from-asset", "", "Input asset")
 	planCmd.Flags().StringVar(&plan.ToAssetArg, "to-asset", "", "Output asset")
 	planCmd.Flags().StringVar(&plan.TradeType, "type", string(providers.SwapTradeTypeExactInput), "Swap type (exact-input|exact-output)")
 	planCmd.Flags().StringVar(&plan.AmountBase, "amount", "", "Exact-input amount in base units")
 	planCmd.Flags().StringVar(&plan.AmountDecimal, "amount-decimal", "", "Exact-input amount in decimal units")
+	planCmd.Flags().StringVar(&plan.AmountUSD, "amount-usd", "", "Exact-input amount as a USD notional, converted at the current input asset price")
 	planCmd.Flags().StringVar(&plan.AmountOutBase, "amount-out", "", "Exact-output amount in base units")
 	planCmd.Flags().StringVar(&plan.AmountOutDecimal, "amount-out-decimal", "", "Exact-output amount in decimal units")
 	planCmd.Flags().StringVar(&plan.WalletRef, "wallet", "", "Wallet identifier or name")
@@ -1444,8 +2557,8 @@ func (s *runtimeState) newSwapCommand() *cobra.Command {
 	planCmd.Flags().StringVar(&plan.Recipient, "recipient", "", "Recipient address (defaults to the resolved sender address)")
 	planCmd.Flags().Int64Var(&plan.SlippageBps, "slippage-bps", 50, "Max slippage in basis points")
 	planCmd.Flags().BoolVar(&plan.Simulate, "simulate", true, "Include simulation checks during execution")
+	planCmd.Flags().BoolVar(&plan.Preview, "preview", false, "Decode step calldata and attach a human-readable preview")
 	planCmd.Flags().StringVar(&plan.RPCURL, "rpc-url", "", "RPC URL override for the selected chain")
-	_ = planCmd.MarkFlagRequired("chain")
 	_ = planCmd.MarkFlagRequired("from-asset")
 	_ = planCmd.MarkFlagRequired("to-asset")
 	_ = planCmd.MarkFlagRequired("provider")
@@ -1470,12 +2583,16 @@ func (s *runtimeState) newSwapCommand() *cobra.Command {
 			if err != nil {
 				return clierr.Wrap(clierr.CodeUsage, "load action", err)
 			}
-			if action.IntentType != "swap" {
+			crossChain := isCrossChainSwapAction(action)
+			if action.IntentType != "swap" && !crossChain {
 				return clierr.New(clierr.CodeUsage, "action is not a swap intent")
 			}
 			if action.Status == execution.ActionStatusCompleted {
 				return s.emitSuccess(trimRootPath(cmd.CommandPath()), action, []string{"action already completed"}, cacheMetaBypass(), nil, false)
 			}
+			if crossChain && (submit.PostState || submit.MaxPriceImpactPct > 0) {
+				return clierr.New(clierr.CodeUnsupported, "--post-state and --max-price-impact-pct are not supported for cross-chain swaps")
+			}
 
 			resolvedExec, err := resolveActionExecutionBackend(cmd, action, submitExecutionInputs{
 				Signer:      submit.Signer,
@@ -1503,10 +2620,33 @@ func (s *runtimeState) newSwapCommand() *cobra.Command {
 			if err != nil {
 				return err
 			}
-			if err := s.executeActionWithTimeout(&action, resolvedExec.txSigner, resolvedExec.evmBackend, execOpts); err != nil {
+			execOpts.PrivateTx, err = resolvePrivateTxOptions(action, submit.PrivateTx, submit.PrivateRelay, submit.PrivateFallback)
+			if err != nil {
+				return err
+			}
+			execOpts.Fresh = submit.Fresh
+			ctx, cancel := context.WithTimeout(cmd.Context(), s.settings.Timeout)
+			requoteWarnings, err := s.requoteExpiredSwap(ctx, &action, submit.MaxRequoteDriftBps)
+			if err == nil {
+				err = s.checkSwapPriceImpact(ctx, &action, submit.MaxPriceImpactPct)
+			}
+			cancel()
+			if err != nil {
 				return err
 			}
-			return s.emitSuccess(trimRootPath(cmd.CommandPath()), action, nil, cacheMetaBypass(), nil, false)
+			warnings, err := s.executeActionWithPostState(&action, resolvedExec.txSigner, resolvedExec.evmBackend, execOpts, submit.PostState)
+			if err != nil {
+				return err
+			}
+			warnings = append(requoteWarnings, warnings...)
+			if crossChain {
+				ctx, cancel := context.WithTimeout(cmd.Context(), s.settings.Timeout)
+				defer cancel()
+				if warnings, err = s.verifyBridgeDelivery(ctx, &action); err != nil {
+					return err
+				}
+			}
+			return s.emitSuccess(trimRootPath(cmd.CommandPath()), action, warnings, cacheMetaBypass(), nil, false)
 		},
 	}
 	submitCmd.Flags().StringVar(&submit.ActionID, "action-id", "", "Action identifier returned by swap plan")
@@ -1523,6 +2663,13 @@ func (s *runtimeState) newSwapCommand() *cobra.Command {
 	submitCmd.Flags().BoolVar(&submit.AllowMaxApproval, "allow-max-approval", false, "Allow approval amounts greater than planned input amount")
 	submitCmd.Flags().BoolVar(&submit.UnsafeProviderTx, "unsafe-provider-tx", false, "Bypass provider transaction guardrails for bridge/aggregator payloads")
 	submitCmd.Flags().StringVar(&submit.FeeToken, "fee-token", "", "Fee token address for Tempo chains (defaults to chain USDC.e)")
+	submitCmd.Flags().BoolVar(&submit.PostState, "post-state", false, "Re-read affected balances and positions after execution and attach them as post_state")
+	submitCmd.Flags().Float64Var(&submit.MaxPriceImpactPct, "max-price-impact-pct", 0, "Refuse to submit when the quoted output is this many percent below a DefiLlama price estimate (0 disables)")
+	submitCmd.Flags().BoolVar(&submit.PrivateTx, "private-tx", false, "Broadcast through a private relay instead of the public mempool")
+	submitCmd.Flags().StringVar(&submit.PrivateRelay, "private-relay", "flashbots", "Private relay for --private-tx (flashbots|mevblocker|<rpc-url>)")
+	submitCmd.Flags().BoolVar(&submit.PrivateFallback, "private-fallback", false, "Rebroadcast through the public RPC when the private relay rejects or drops the transaction")
+	submitCmd.Flags().BoolVar(&submit.Fresh, "fresh", false, "Ignore chain state prefetched at plan time and re-read it before submitting")
+	submitCmd.Flags().Int64Var(&submit.MaxRequoteDriftBps, "max-requote-drift-bps", defaultMaxRequoteDriftBps, "Refuse to submit when re-quoting an expired plan lands this many basis points below the planned output")
 	annotateStructuredSubmitCommand(submitCmd, swapSubmitArgs{})
 
 	var statusActionID string
@@ -1541,7 +2688,7 @@ func (s *runtimeState) newSwapCommand() *cobra.Command {
 			if err != nil {
 				return clierr.Wrap(clierr.CodeUsage, "load action", err)
 			}
-			if action.IntentType != "swap" {
+			if action.IntentType != "swap" && !isCrossChainSwapAction(action) {
 				return clierr.New(clierr.CodeUsage, "action is not a swap intent")
 			}
 			return s.emitSuccess(trimRootPath(cmd.CommandPath()), action, nil, cacheMetaBypass(), nil, false)
@@ -1553,6 +2700,8 @@ func (s *runtimeState) newSwapCommand() *cobra.Command {
 	root.AddCommand(quoteCmd)
 	root.AddCommand(planCmd)
 	root.AddCommand(submitCmd)
+	root.AddCommand(s.newSwapRunCommand())
+	root.AddCommand(s.newSwapLimitCommand())
 	root.AddCommand(statusCmd)
 	return root
 }
@@ -1569,24 +2718,40 @@ func (s *runtimeState) newActionsCommand() *cobra.Command {
 		},
 	}
 
-	var listStatus string
+	var listFilters actionFilterFlags
 	var listLimit int
+	var listPage pageFlags
 	listCmd := &cobra.Command{
 		Use:   "list",
 		Short: "List persisted actions",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			limit := listPage.limit(cmd, listLimit)
+			if limit <= 0 {
+				return clierr.New(clierr.CodeUsage, "--limit must be > 0")
+			}
+			keyParts := listFilters.cacheKeyParts()
+			keyParts["limit"] = limit
+			key := cacheKey(trimRootPath(cmd.CommandPath()), keyParts)
+			if err := s.startListPage(listPage, key); err != nil {
+				return err
+			}
+			filter, err := listFilters.filter(limit)
+			if err != nil {
+				return err
+			}
 			if err := s.ensureActionStore(); err != nil {
 				return err
 			}
-			items, err := s.actionStore.List(strings.TrimSpace(listStatus), listLimit)
+			items, err := s.actionStore.Query(filter)
 			if err != nil {
 				return clierr.Wrap(clierr.CodeInternal, "list actions", err)
 			}
 			return s.emitSuccess(trimRootPath(cmd.CommandPath()), items, nil, cacheMetaBypass(), nil, false)
 		},
 	}
-	listCmd.Flags().StringVar(&listStatus, "status", "", "Optional action status filter")
+	listFilters.register(listCmd)
 	listCmd.Flags().IntVar(&listLimit, "limit", 20, "Maximum actions to return")
+	listPage.register(listCmd)
 
 	lookupAction := func(cmd *cobra.Command, actionIDArg string) error {
 		actionID, err := resolveActionID(actionIDArg)
@@ -1613,6 +2778,32 @@ func (s *runtimeState) newActionsCommand() *cobra.Command {
 	}
 	showCmd.Flags().StringVar(&showActionID, "action-id", "", "Action identifier")
 
+	var previewActionID string
+	previewCmd := &cobra.Command{
+		Use:   "preview",
+		Short: "Decode each step's calldata into a human-readable summary",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			actionID, err := resolveActionID(previewActionID)
+			if err != nil {
+				return err
+			}
+			if err := s.ensureActionStore(); err != nil {
+				return err
+			}
+			action, err := s.actionStore.Get(actionID)
+			if err != nil {
+				return clierr.Wrap(clierr.CodeUsage, "load action", err)
+			}
+			preview := execution.PreviewAction(action)
+			var warnings []string
+			if preview.UndecodedCalls > 0 {
+				warnings = append(warnings, fmt.Sprintf("%d call(s) did not match a known ABI; review their calldata before signing", preview.UndecodedCalls))
+			}
+			return s.emitSuccess(trimRootPath(cmd.CommandPath()), preview, warnings, cacheMetaBypass(), nil, false)
+		},
+	}
+	previewCmd.Flags().StringVar(&previewActionID, "action-id", "", "Action identifier")
+
 	var estimateActionID, estimateStepIDs, estimateMaxFeeGwei, estimateMaxPriorityFeeGwei, estimateBlockTag string
 	var estimateGasMultiplier float64
 	estimateCmd := &cobra.Command{
@@ -1656,20 +2847,188 @@ func (s *runtimeState) newActionsCommand() *cobra.Command {
 	estimateCmd.Flags().StringVar(&estimateMaxPriorityFeeGwei, "max-priority-fee-gwei", "", "Optional EIP-1559 max priority fee (gwei)")
 	estimateCmd.Flags().StringVar(&estimateBlockTag, "block-tag", "pending", "Block tag used for estimation (pending|latest)")
 
+	var composeActionIDs string
+	composeCmd := &cobra.Command{
+		Use:   "compose",
+		Short: "Join planned actions into one composite action that runs them in order",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			actionIDs := splitCSV(composeActionIDs)
+			if len(actionIDs) < 2 {
+				return clierr.New(clierr.CodeUsage, "--action-ids needs at least two action ids")
+			}
+			if err := s.ensureActionStore(); err != nil {
+				return err
+			}
+			subActions := make([]execution.Action, 0, len(actionIDs))
+			for _, actionIDArg := range actionIDs {
+				actionID, err := resolveActionID(actionIDArg)
+				if err != nil {
+					return err
+				}
+				item, err := s.actionStore.Get(actionID)
+				if err != nil {
+					return clierr.Wrap(clierr.CodeUsage, "load action", err)
+				}
+				subActions = append(subActions, item)
+			}
+			action, err := execution.NewCompositeAction(execution.NewActionID(), subActions)
+			if err != nil {
+				return err
+			}
+			if err := s.checkActionProtocolPolicy(action); err != nil {
+				return err
+			}
+			if err := s.actionStore.SaveComposite(action); err != nil {
+				return clierr.Wrap(clierr.CodeInternal, "persist composite action", err)
+			}
+			return s.emitSuccess(trimRootPath(cmd.CommandPath()), action, nil, cacheMetaBypass(), nil, false)
+		},
+	}
+	composeCmd.Flags().StringVar(&composeActionIDs, "action-ids", "", "Planned action ids to run in order (comma-separated)")
+	_ = composeCmd.MarkFlagRequired("action-ids")
+
+	type resumeArgs struct {
+		ActionID           string  `json:"action_id" flag:"action-id" required:"true" format:"action-id"`
+		Simulate           bool    `json:"simulate" flag:"simulate"`
+		Signer             string  `json:"signer" flag:"signer" enum:"local,tempo"`
+		KeySource          string  `json:"key_source" flag:"key-source" enum:"auto,env,file,keystore"`
+		PrivateKey         string  `json:"private_key" flag:"private-key" format:"hex"`
+		FromAddress        string  `json:"from_address" flag:"from-address" format:"evm-address"`
+		PollInterval       string  `json:"poll_interval" flag:"poll-interval" format:"duration"`
+		StepTimeout        string  `json:"step_timeout" flag:"step-timeout" format:"duration"`
+		GasMultiplier      float64 `json:"gas_multiplier" flag:"gas-multiplier"`
+		MaxFeeGwei         string  `json:"max_fee_gwei" flag:"max-fee-gwei"`
+		MaxPriorityFeeGwei string  `json:"max_priority_fee_gwei" flag:"max-priority-fee-gwei"`
+		AllowMaxApproval   bool    `json:"allow_max_approval" flag:"allow-max-approval"`
+		UnsafeProviderTx   bool    `json:"unsafe_provider_tx" flag:"unsafe-provider-tx"`
+		FeeToken           string  `json:"fee_token" flag:"fee-token" format:"evm-address"`
+	}
+	var resume resumeArgs
+	resumeCmd := &cobra.Command{
+		Use:   "resume",
+		Short: "Resume any failed or interrupted action from its first unconfirmed step",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			actionID, err := resolveActionID(resume.ActionID)
+			if err != nil {
+				return err
+			}
+			if err := s.ensureActionStore(); err != nil {
+				return err
+			}
+			action, err := s.actionStore.Get(actionID)
+			if err != nil {
+				return clierr.Wrap(clierr.CodeUsage, "load action", err)
+			}
+			if action.Status == execution.ActionStatusCompleted {
+				return s.emitSuccess(trimRootPath(cmd.CommandPath()), action, []string{"action already completed"}, cacheMetaBypass(), nil, false)
+			}
+			if action.IntentType == execution.IntentLimitOrder {
+				return clierr.New(clierr.CodeUsage, "limit orders are tracked with swap limit status and cancelled with swap limit cancel")
+			}
+			resolvedExec, err := resolveActionExecutionBackend(cmd, action, submitExecutionInputs{
+				Signer:      resume.Signer,
+				KeySource:   resume.KeySource,
+				PrivateKey:  resume.PrivateKey,
+				FromAddress: resume.FromAddress,
+			})
+			if err != nil {
+				return err
+			}
+			if err := validateExecutionSender(action, resume.FromAddress, resolvedExec.sender); err != nil {
+				return err
+			}
+			execOpts, err := parseExecuteOptions(
+				resume.Simulate,
+				resume.PollInterval,
+				resume.StepTimeout,
+				resume.GasMultiplier,
+				resume.MaxFeeGwei,
+				resume.MaxPriorityFeeGwei,
+				resume.AllowMaxApproval,
+				resume.UnsafeProviderTx,
+				resume.FeeToken,
+			)
+			if err != nil {
+				return err
+			}
+			interrupt, stop := notifyShutdown()
+			defer stop()
+			execOpts.Interrupt = interrupt
+			prepareTWAPSlice := s.newTWAPSlicePreparer(interrupt)
+			execOpts.PrepareStep = func(ctx context.Context, item *execution.Action, index int) error {
+				if err := prepareTWAPSlice(ctx, item, index); err != nil {
+					return err
+				}
+				return s.prepareRouteSwapLeg(ctx, item, index)
+			}
+			execErr := s.executeActionWithTimeout(&action, resolvedExec.txSigner, resolvedExec.evmBackend, execOpts)
+			if reason := summarizeTWAPFill(&action); reason != "" {
+				if err := s.actionStore.Save(action); err != nil {
+					return clierr.Wrap(clierr.CodeInternal, "persist action state", err)
+				}
+				return s.emitSuccess(trimRootPath(cmd.CommandPath()), action, []string{"twap aborted: " + reason}, cacheMetaBypass(), nil, true)
+			}
+			if execErr != nil {
+				return execErr
+			}
+			if action.Metadata["strategy"] == swapStrategyTWAP {
+				if err := s.actionStore.Save(action); err != nil {
+					return clierr.Wrap(clierr.CodeInternal, "persist action state", err)
+				}
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), s.settings.Timeout)
+			defer cancel()
+			warnings, err := s.verifyBridgeDelivery(ctx, &action)
+			if err != nil {
+				return err
+			}
+			for i := range action.SubActions {
+				subWarnings, err := s.verifyBridgeDelivery(ctx, &action.SubActions[i])
+				if err != nil {
+					return err
+				}
+				warnings = append(warnings, subWarnings...)
+			}
+			return s.emitSuccess(trimRootPath(cmd.CommandPath()), action, warnings, cacheMetaBypass(), nil, false)
+		},
+	}
+	resumeCmd.Flags().StringVar(&resume.ActionID, "action-id", "", "Action identifier of a planned, failed, or interrupted action")
+	resumeCmd.Flags().BoolVar(&resume.Simulate, "simulate", true, "Run preflight simulation before submission")
+	resumeCmd.Flags().StringVar(&resume.Signer, "signer", "local", "Signer backend (local|tempo)")
+	resumeCmd.Flags().StringVar(&resume.KeySource, "key-source", execsigner.KeySourceAuto, "Key source (auto|env|file|keystore)")
+	resumeCmd.Flags().StringVar(&resume.PrivateKey, "private-key", "", "Private key hex override for local signer (less safe)")
+	resumeCmd.Flags().StringVar(&resume.FromAddress, "from-address", "", "Expected sender EOA address")
+	resumeCmd.Flags().StringVar(&resume.PollInterval, "poll-interval", "2s", "Receipt polling interval")
+	resumeCmd.Flags().StringVar(&resume.StepTimeout, "step-timeout", "2m", "Timeout per wait stage (receipt or settlement polling)")
+	resumeCmd.Flags().Float64Var(&resume.GasMultiplier, "gas-multiplier", 1.2, "Gas estimate safety multiplier")
+	resumeCmd.Flags().StringVar(&resume.MaxFeeGwei, "max-fee-gwei", "", "Optional EIP-1559 max fee (gwei)")
+	resumeCmd.Flags().StringVar(&resume.MaxPriorityFeeGwei, "max-priority-fee-gwei", "", "Optional EIP-1559 max priority fee (gwei)")
+	resumeCmd.Flags().BoolVar(&resume.AllowMaxApproval, "allow-max-approval", false, "Allow approval amounts greater than planned input amount")
+	resumeCmd.Flags().BoolVar(&resume.UnsafeProviderTx, "unsafe-provider-tx", false, "Bypass provider transaction guardrails for bridge/aggregator payloads")
+	resumeCmd.Flags().StringVar(&resume.FeeToken, "fee-token", "", "Fee token address for Tempo chains (defaults to chain USDC.e)")
+	annotateStructuredSubmitCommand(resumeCmd, resumeArgs{})
+
 	root.AddCommand(listCmd)
 	root.AddCommand(showCmd)
+	root.AddCommand(previewCmd)
 	root.AddCommand(estimateCmd)
+	root.AddCommand(composeCmd)
+	root.AddCommand(resumeCmd)
+	root.AddCommand(s.newActionsPruneCommand())
+	root.AddCommand(s.newActionsExportCommand())
+	root.AddCommand(s.newActionsEncryptCommand())
 	return root
 }
 
 func (s *runtimeState) newYieldCommand() *cobra.Command {
 	root := &cobra.Command{Use: "yield", Short: "Yield opportunities, positions, history, and execution"}
 
-	var opportunitiesChainArg, opportunitiesAssetArg, opportunitiesProvidersArg, opportunitiesSortArg string
+	var opportunitiesChainArg, opportunitiesAssetArg, opportunitiesProvidersArg, opportunitiesSortArg, opportunitiesMaxRiskArg string
 	var opportunitiesLimit int
-	var opportunitiesMinTVL, opportunitiesMinAPY float64
-	var opportunitiesIncludeIncomplete bool
+	var opportunitiesMinTVL, opportunitiesMinAPY, opportunitiesMinExitLiquidityPct, opportunitiesMinStability float64
+	var opportunitiesIncludeIncomplete, opportunitiesWithStability, opportunitiesIncludeEquivalents bool
 	var opportunitiesRPCURL string
+	var opportunitiesPage pageFlags
 	opportunitiesCmd := &cobra.Command{
 		Use:   "opportunities",
 		Short: "Rank yield opportunities",
@@ -1678,19 +3037,42 @@ func (s *runtimeState) newYieldCommand() *cobra.Command {
 			if err != nil {
 				return err
 			}
+			if opportunitiesMinExitLiquidityPct < 0 || opportunitiesMinExitLiquidityPct > 100 {
+				return clierr.New(clierr.CodeUsage, "--min-exit-liquidity-pct must be between 0 and 100")
+			}
+			if opportunitiesMinStability < 0 || opportunitiesMinStability > 1 {
+				return clierr.New(clierr.CodeUsage, "--min-stability must be between 0 and 1")
+			}
+			maxRisk := 100.0
+			if strings.TrimSpace(opportunitiesMaxRiskArg) != "" {
+				maxRisk, err = yieldutil.ParseMaxRisk(opportunitiesMaxRiskArg)
+				if err != nil {
+					return clierr.Wrap(clierr.CodeUsage, "invalid --max-risk", err)
+				}
+			}
+			withStability := opportunitiesWithStability || opportunitiesMinStability > 0
 			req := providers.YieldRequest{
 				Chain:             chain,
 				Asset:             asset,
-				Limit:             opportunitiesLimit,
+				Limit:             opportunitiesPage.limit(cmd, opportunitiesLimit),
 				MinTVLUSD:         opportunitiesMinTVL,
 				MinAPY:            opportunitiesMinAPY,
 				Providers:         splitCSV(opportunitiesProvidersArg),
 				SortBy:            opportunitiesSortArg,
 				IncludeIncomplete: opportunitiesIncludeIncomplete,
 			}
+			var equivalents []id.Asset
+			if opportunitiesIncludeEquivalents {
+				equivalents = id.EquivalentAssets(asset, chain, id.DefaultResolveOptions())
+			}
+			equivalentIDs := make([]string, 0, len(equivalents))
+			for _, equivalent := range equivalents {
+				equivalentIDs = append(equivalentIDs, equivalent.AssetID)
+			}
 			key := cacheKey(trimRootPath(cmd.CommandPath()), map[string]any{
 				"chain":              req.Chain.CAIP2,
 				"asset":              req.Asset.AssetID,
+				"equivalents":        equivalentIDs,
 				"limit":              req.Limit,
 				"min_tvl_usd":        req.MinTVLUSD,
 				"min_apy":            req.MinAPY,
@@ -1698,12 +3080,26 @@ func (s *runtimeState) newYieldCommand() *cobra.Command {
 				"sort":               req.SortBy,
 				"include_incomplete": req.IncludeIncomplete,
 				"rpc_url":            strings.TrimSpace(opportunitiesRPCURL),
+				"min_exit_liq_pct":   opportunitiesMinExitLiquidityPct,
+				"with_stability":     withStability,
+				"min_stability":      opportunitiesMinStability,
+				"max_risk":           maxRisk,
+				"allow_protocols":    s.settings.AllowProtocols,
+				"deny_protocols":     s.settings.DenyProtocols,
 			})
+			if err := s.startListPage(opportunitiesPage, key); err != nil {
+				return err
+			}
 			return s.runCachedCommand(trimRootPath(cmd.CommandPath()), key, 60*time.Second, func(ctx context.Context) (any, []model.ProviderStatus, []string, bool, error) {
 				selectedProviders, err := s.selectYieldProviders(req.Providers, req.Chain)
 				if err != nil {
 					return nil, nil, nil, false, err
 				}
+				for _, name := range req.Providers {
+					if err := s.protocolRules().Check(name); err != nil {
+						return nil, nil, nil, false, err
+					}
+				}
 				warnings := []string{}
 				statuses := make([]model.ProviderStatus, 0, len(selectedProviders))
 				combined := make([]model.YieldOpportunity, 0)
@@ -1711,22 +3107,47 @@ func (s *runtimeState) newYieldCommand() *cobra.Command {
 				var firstErr error
 
 				for _, providerName := range selectedProviders {
+					applyRPCOverride(s.yieldProviders[providerName], opportunitiesRPCURL)
+				}
+				reqCopy := req
+				reqCopy.Providers = nil
+				type providerYield struct {
+					items    []model.YieldOpportunity
+					warnings []string
+				}
+				calls := fanOutProviders(ctx, s.settings, selectedProviders, func(ctx context.Context, providerName string) (providerYield, error) {
 					provider := s.yieldProviders[providerName]
-					applyRPCOverride(provider, opportunitiesRPCURL)
-					reqCopy := req
-					reqCopy.Providers = nil
-					start := time.Now()
 					items, providerErr := provider.YieldOpportunities(ctx, reqCopy)
-					statuses = append(statuses, model.ProviderStatus{Name: provider.Info().Name, Status: statusFromErr(providerErr), LatencyMS: time.Since(start).Milliseconds()})
-					if providerErr != nil {
+					out := providerYield{items: items}
+					// Equivalent assets (USDbC for USDC on Base) do not fail the
+					// provider; their rows are marked and their errors warned.
+					for _, equivalent := range equivalents {
+						equivalentReq := reqCopy
+						equivalentReq.Asset = equivalent
+						equivalentItems, err := provider.YieldOpportunities(ctx, equivalentReq)
+						if err != nil {
+							out.warnings = append(out.warnings, fmt.Sprintf("provider %s failed for equivalent %s: %v", provider.Info().Name, equivalent.Symbol, err))
+							continue
+						}
+						for i := range equivalentItems {
+							equivalentItems[i].EquivalentOf = asset.AssetID
+						}
+						out.items = append(out.items, equivalentItems...)
+					}
+					return out, providerErr
+				})
+				for _, call := range calls {
+					name := s.yieldProviders[call.Name].Info().Name
+					statuses = append(statuses, model.ProviderStatus{Name: name, Status: statusFromErr(call.Err), LatencyMS: call.Latency.Milliseconds()})
+					combined = append(combined, call.Value.items...)
+					warnings = append(warnings, call.Value.warnings...)
+					if call.Err != nil {
 						partial = true
-						warnings = append(warnings, fmt.Sprintf("provider %s failed: %v", provider.Info().Name, providerErr))
+						warnings = append(warnings, fmt.Sprintf("provider %s failed: %v", name, call.Err))
 						if firstErr == nil {
-							firstErr = providerErr
+							firstErr = call.Err
 						}
-						continue
 					}
-					combined = append(combined, items...)
 				}
 
 				if opportunitiesIncludeIncomplete {
@@ -1741,10 +3162,42 @@ func (s *runtimeState) newYieldCommand() *cobra.Command {
 				}
 
 				combined = dedupeYieldByOpportunityID(combined)
+				combined, policyWarnings := s.filterYieldByProtocolPolicy(combined)
+				warnings = append(warnings, policyWarnings...)
+				if len(combined) == 0 {
+					return nil, statuses, warnings, partial, clierr.New(clierr.CodeBlocked, "all yield opportunities excluded by protocol policy")
+				}
+				warnings = append(warnings, s.attachProtocolSecurity(ctx, combined)...)
+				warnings = append(warnings, s.attachDepegRisk(ctx, chain, asset, combined)...)
+				if opportunitiesMinExitLiquidityPct > 0 {
+					combined = filterYieldByExitLiquidity(combined, opportunitiesMinExitLiquidityPct, opportunitiesIncludeIncomplete)
+					if len(combined) == 0 {
+						return nil, statuses, warnings, partial, clierr.New(clierr.CodeUnavailable, "no yield opportunities meet --min-exit-liquidity-pct")
+					}
+				}
+				if maxRisk < 100 {
+					combined = filterYieldByRisk(combined, maxRisk)
+					if len(combined) == 0 {
+						return nil, statuses, warnings, partial, clierr.New(clierr.CodeUnavailable, "no yield opportunities meet --max-risk")
+					}
+				}
 				sortYieldOpportunities(combined, req.SortBy)
+				if withStability {
+					var stabilityWarnings []string
+					var stabilityPartial bool
+					combined, stabilityWarnings, stabilityPartial = s.attachYieldStability(ctx, combined, opportunitiesMinStability, req.Limit, opportunitiesIncludeIncomplete)
+					warnings = append(warnings, stabilityWarnings...)
+					partial = partial || stabilityPartial
+					if len(combined) == 0 {
+						return nil, statuses, warnings, partial, clierr.New(clierr.CodeUnavailable, "no yield opportunities meet --min-stability")
+					}
+				}
 				if req.Limit > 0 && len(combined) > req.Limit {
 					combined = combined[:req.Limit]
 				}
+				lpWarnings, lpPartial := s.attachLPAnalytics(ctx, combined)
+				warnings = append(warnings, lpWarnings...)
+				partial = partial || lpPartial
 				if opportunitiesIncludeIncomplete {
 					warnings = append(warnings, fmt.Sprintf("returned %d combined opportunities across %d provider(s)", len(combined), len(selectedProviders)))
 				}
@@ -1757,12 +3210,20 @@ func (s *runtimeState) newYieldCommand() *cobra.Command {
 	opportunitiesCmd.Flags().IntVar(&opportunitiesLimit, "limit", 20, "Maximum opportunities to return")
 	opportunitiesCmd.Flags().Float64Var(&opportunitiesMinTVL, "min-tvl-usd", 0, "Minimum TVL in USD")
 	opportunitiesCmd.Flags().Float64Var(&opportunitiesMinAPY, "min-apy", 0, "Minimum total APY percent")
-	opportunitiesCmd.Flags().StringVar(&opportunitiesProvidersArg, "providers", "", "Filter by provider names (aave,morpho,kamino,moonwell)")
-	opportunitiesCmd.Flags().StringVar(&opportunitiesSortArg, "sort", "apy_total", "Sort key (apy_total|tvl_usd|liquidity_usd)")
+	opportunitiesCmd.Flags().Float64Var(&opportunitiesMinExitLiquidityPct, "min-exit-liquidity-pct", 0, "Minimum percent of TVL withdrawable immediately (0-100)")
+	opportunitiesCmd.Flags().BoolVar(&opportunitiesWithStability, "with-stability", false, "Attach 30d apy_volatility from providers with yield history")
+	opportunitiesCmd.Flags().Float64Var(&opportunitiesMinStability, "min-stability", 0, "Minimum apy_volatility.stability_score (0-1); implies --with-stability")
+	opportunitiesCmd.Flags().StringVar(&opportunitiesProvidersArg, "providers", "", "Filter by provider names (aave,morpho,kamino,moonwell,spark,staking,aerodrome,velodrome,hyperliquid)")
+	opportunitiesCmd.Flags().StringVar(&opportunitiesSortArg, "sort", "apy_total", "Sort key (apy_total|apy_effective|tvl_usd|liquidity_usd|score); apy_effective compounds APRs so providers compare evenly, score ranks lowest risk first")
+	opportunitiesCmd.Flags().StringVar(&opportunitiesMaxRiskArg, "max-risk", "", "Maximum risk: low, medium, high, or a risk_score from 0 to 100")
 	opportunitiesCmd.Flags().BoolVar(&opportunitiesIncludeIncomplete, "include-incomplete", false, "Include opportunities missing APY/TVL")
+	opportunitiesCmd.Flags().BoolVar(&opportunitiesIncludeEquivalents, "include-equivalents", false, "Also query wrapped/bridged equivalents of --asset on the same chain; their rows set equivalent_of")
 	opportunitiesCmd.Flags().StringVar(&opportunitiesRPCURL, "rpc-url", "", "Optional RPC URL override for on-chain providers")
+	opportunitiesPage.register(opportunitiesCmd)
 	_ = opportunitiesCmd.MarkFlagRequired("chain")
 	_ = opportunitiesCmd.MarkFlagRequired("asset")
+	opportunitiesResponse := schema.SchemaFromType([]model.YieldOpportunity{})
+	_ = schema.SetCommandMetadata(opportunitiesCmd, schema.CommandMetadata{Response: &opportunitiesResponse})
 	root.AddCommand(opportunitiesCmd)
 
 	var positionsChainArg, positionsAddressArg, positionsAssetArg, positionsProvidersArg string
@@ -1872,6 +3333,8 @@ func (s *runtimeState) newYieldCommand() *cobra.Command {
 	positionsCmd.Flags().StringVar(&positionsRPCURL, "rpc-url", "", "Optional RPC URL override used by providers that need on-chain valuation")
 	_ = positionsCmd.MarkFlagRequired("chain")
 	_ = positionsCmd.MarkFlagRequired("address")
+	positionsResponse := schema.SchemaFromType([]model.YieldPosition{})
+	_ = schema.SetCommandMetadata(positionsCmd, schema.CommandMetadata{Response: &positionsResponse})
 	root.AddCommand(positionsCmd)
 
 	var historyChainArg, historyAssetArg, historyProvidersArg, historyMetricsArg string
@@ -1927,19 +3390,21 @@ func (s *runtimeState) newYieldCommand() *cobra.Command {
 				partial := false
 				var firstErr error
 
-				for _, providerName := range selectedProviders {
+				// historyFetch is what one provider contributes; the error
+				// returned alongside it sets the provider status.
+				type historyFetch struct {
+					series   []model.YieldHistorySeries
+					warnings []string
+					partial  bool
+				}
+				calls := fanOutProviders(ctx, s.settings, selectedProviders, func(ctx context.Context, providerName string) (historyFetch, error) {
 					provider := s.yieldProviders[providerName]
 					historyProvider, ok := provider.(providers.YieldHistoryProvider)
-					providerStart := time.Now()
 					if !ok {
-						providerErr := clierr.New(clierr.CodeUnsupported, fmt.Sprintf("yield provider %s does not support history", providerName))
-						statuses = append(statuses, model.ProviderStatus{Name: provider.Info().Name, Status: statusFromErr(providerErr), LatencyMS: time.Since(providerStart).Milliseconds()})
-						warnings = append(warnings, fmt.Sprintf("provider %s does not support yield history", provider.Info().Name))
-						partial = true
-						if firstErr == nil {
-							firstErr = providerErr
-						}
-						continue
+						return historyFetch{
+							warnings: []string{fmt.Sprintf("provider %s does not support yield history", provider.Info().Name)},
+							partial:  true,
+						}, clierr.New(clierr.CodeUnsupported, fmt.Sprintf("yield provider %s does not support history", providerName))
 					}
 
 					discoveryReq := providers.YieldRequest{
@@ -1956,13 +3421,10 @@ func (s *runtimeState) newYieldCommand() *cobra.Command {
 					}
 					opportunities, providerErr := provider.YieldOpportunities(ctx, discoveryReq)
 					if providerErr != nil {
-						statuses = append(statuses, model.ProviderStatus{Name: provider.Info().Name, Status: statusFromErr(providerErr), LatencyMS: time.Since(providerStart).Milliseconds()})
-						warnings = append(warnings, fmt.Sprintf("provider %s failed during opportunity lookup: %v", provider.Info().Name, providerErr))
-						partial = true
-						if firstErr == nil {
-							firstErr = providerErr
-						}
-						continue
+						return historyFetch{
+							warnings: []string{fmt.Sprintf("provider %s failed during opportunity lookup: %v", provider.Info().Name, providerErr)},
+							partial:  true,
+						}, providerErr
 					}
 					if len(opportunityIDSet) > 0 {
 						opportunities = filterYieldOpportunitiesByID(opportunities, opportunityIDSet)
@@ -1971,17 +3433,13 @@ func (s *runtimeState) newYieldCommand() *cobra.Command {
 						opportunities = opportunities[:historyLimit]
 					}
 					if len(opportunities) == 0 {
-						providerErr = clierr.New(clierr.CodeUnavailable, fmt.Sprintf("provider %s returned no matching opportunities", providerName))
-						statuses = append(statuses, model.ProviderStatus{Name: provider.Info().Name, Status: statusFromErr(providerErr), LatencyMS: time.Since(providerStart).Milliseconds()})
-						warnings = append(warnings, fmt.Sprintf("provider %s returned no matching opportunities", provider.Info().Name))
-						partial = true
-						if firstErr == nil {
-							firstErr = providerErr
-						}
-						continue
+						return historyFetch{
+							warnings: []string{fmt.Sprintf("provider %s returned no matching opportunities", provider.Info().Name)},
+							partial:  true,
+						}, clierr.New(clierr.CodeUnavailable, fmt.Sprintf("provider %s returned no matching opportunities", providerName))
 					}
 
-					providerSeries := make([]model.YieldHistorySeries, 0, len(opportunities)*len(metrics))
+					out := historyFetch{series: make([]model.YieldHistorySeries, 0, len(opportunities)*len(metrics))}
 					var providerHistoryErr error
 					for _, opportunity := range opportunities {
 						series, err := historyProvider.YieldHistory(ctx, providers.YieldHistoryRequest{
@@ -1992,25 +3450,28 @@ func (s *runtimeState) newYieldCommand() *cobra.Command {
 							Metrics:     metrics,
 						})
 						if err != nil {
-							partial = true
-							warnings = append(warnings, fmt.Sprintf("provider %s failed history for opportunity %s: %v", provider.Info().Name, opportunity.OpportunityID, err))
+							out.partial = true
+							out.warnings = append(out.warnings, fmt.Sprintf("provider %s failed history for opportunity %s: %v", provider.Info().Name, opportunity.OpportunityID, err))
 							if providerHistoryErr == nil {
 								providerHistoryErr = err
 							}
 							continue
 						}
-						providerSeries = append(providerSeries, series...)
+						out.series = append(out.series, series...)
 					}
-
-					statusErr := providerHistoryErr
-					if len(providerSeries) == 0 && statusErr == nil {
-						statusErr = clierr.New(clierr.CodeUnavailable, fmt.Sprintf("provider %s returned no historical points", providerName))
+					if len(out.series) == 0 && providerHistoryErr == nil {
+						providerHistoryErr = clierr.New(clierr.CodeUnavailable, fmt.Sprintf("provider %s returned no historical points", providerName))
 					}
-					statuses = append(statuses, model.ProviderStatus{Name: provider.Info().Name, Status: statusFromErr(statusErr), LatencyMS: time.Since(providerStart).Milliseconds()})
-					if statusErr != nil && firstErr == nil {
-						firstErr = statusErr
+					return out, providerHistoryErr
+				})
+				for _, call := range calls {
+					statuses = append(statuses, model.ProviderStatus{Name: s.yieldProviders[call.Name].Info().Name, Status: statusFromErr(call.Err), LatencyMS: call.Latency.Milliseconds()})
+					warnings = append(warnings, call.Value.warnings...)
+					partial = partial || call.Value.partial
+					if call.Err != nil && firstErr == nil {
+						firstErr = call.Err
 					}
-					combined = append(combined, providerSeries...)
+					combined = append(combined, call.Value.series...)
 				}
 
 				if len(combined) == 0 {
@@ -2037,7 +3498,12 @@ func (s *runtimeState) newYieldCommand() *cobra.Command {
 	historyCmd.Flags().IntVar(&historyLimit, "limit", 20, "Maximum opportunities per provider to fetch history for")
 	_ = historyCmd.MarkFlagRequired("chain")
 	_ = historyCmd.MarkFlagRequired("asset")
+	historyResponse := schema.SchemaFromType([]model.YieldHistorySeries{})
+	_ = schema.SetCommandMetadata(historyCmd, schema.CommandMetadata{Response: &historyResponse})
 	root.AddCommand(historyCmd)
+	root.AddCommand(s.newYieldILSimulateCommand())
+	root.AddCommand(s.newYieldBacktestCommand())
+	root.AddCommand(s.newYieldLoopsCommand())
 
 	s.addYieldExecutionSubcommands(root)
 	return root
@@ -2047,21 +3513,44 @@ type fetchFn func(ctx context.Context) (data any, providerStatus []model.Provide
 
 func (s *runtimeState) runCachedCommand(commandPath, key string, ttl time.Duration, fetch fetchFn) error {
 	s.resetCommandDiagnostics()
+	if s.settings.CacheTTL > 0 {
+		ttl = s.settings.CacheTTL
+	}
 	cacheStatus := cacheMetaMiss()
+	cacheStatus.TTLMS = ttl.Milliseconds()
 	warnings := []string{}
 	var staleData any
 	staleAvailable := false
 	staleObservedAge := time.Duration(0)
 	staleObservedAt := time.Time{}
 	staleCacheStatus := cacheMetaMiss()
+	log := logx.L().With("command", commandPath, "cache_key", key)
 
-	if s.settings.CacheEnabled && s.cache != nil {
+	if s.settings.CacheEnabled && s.cache != nil && !s.settings.ForceFresh {
+		readStarted := time.Now()
 		cached, err := s.cache.Get(key, s.settings.MaxStale)
+		if err == nil && cached.Hit && s.settings.CacheTTL > 0 {
+			// Entries keep the TTL they were written with; --cache-ttl
+			// judges their freshness for this command instead.
+			cached.Stale = cached.Age > ttl
+		}
+		switch {
+		case err != nil:
+			tracex.RecordCache("get", "error", time.Since(readStarted))
+			log.Warn("cache read failed", "error", err)
+		case !cached.Hit:
+			tracex.RecordCache("get", "miss", time.Since(readStarted))
+			log.Debug("cache miss")
+		default:
+			tracex.RecordCache("get", cacheHitTraceStatus(cached.Stale), time.Since(readStarted))
+			log.Debug("cache hit", "age_ms", cached.Age.Milliseconds(), "stale", cached.Stale)
+		}
 		if err == nil && cached.Hit {
-			entryStatus := model.CacheStatus{Status: "hit", AgeMS: cached.Age.Milliseconds(), Stale: cached.Stale}
+			entryStatus := model.CacheStatus{Status: "hit", AgeMS: cached.Age.Milliseconds(), Stale: cached.Stale, TTLMS: ttl.Milliseconds()}
 			if !cached.Stale {
 				var data any
 				if err := json.Unmarshal(cached.Value, &data); err == nil {
+					log.Info("serving cached response", "age_ms", cached.Age.Milliseconds())
 					s.captureCommandDiagnostics(warnings, nil, false)
 					return s.emitSuccess(commandPath, data, warnings, entryStatus, nil, false)
 				}
@@ -2076,12 +3565,15 @@ func (s *runtimeState) runCachedCommand(commandPath, key string, ttl time.Durati
 				}
 			}
 		}
+	} else {
+		log.Debug("cache bypassed", "enabled", s.settings.CacheEnabled, "force_fresh", s.settings.ForceFresh)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), s.settings.Timeout)
+	ctx, cancel := context.WithTimeout(breaker.WithCapability(context.Background(), capabilityOf(commandPath)), s.settings.Timeout)
 	defer cancel()
 	data, providerStatus, providerWarnings, partial, err := fetch(ctx)
 	warnings = append(warnings, providerWarnings...)
+	warnings = append(warnings, circuitWarnings(commandPath, providerStatus)...)
 	s.captureCommandDiagnostics(warnings, providerStatus, partial)
 	if err != nil {
 		if staleAvailable {
@@ -2099,7 +3591,12 @@ func (s *runtimeState) runCachedCommand(commandPath, key string, ttl time.Durati
 			if staleExceedsBudget(currentStaleAge, ttl, s.settings.MaxStale) {
 				return clierr.Wrap(clierr.CodeStale, "fresh provider fetch failed and cached data exceeded stale budget", err)
 			}
-			warnings = append(warnings, "provider fetch failed; serving stale data within max-stale budget")
+			log.Warn("provider fetch failed; serving stale cache entry", "age_ms", currentStaleAge.Milliseconds(), "error", err)
+			if s.settings.Offline {
+				warnings = append(warnings, "offline; serving stale data within max-stale budget")
+			} else {
+				warnings = append(warnings, "provider fetch failed; serving stale data within max-stale budget")
+			}
 			s.captureCommandDiagnostics(warnings, providerStatus, false)
 			return s.emitSuccess(commandPath, staleData, warnings, staleCacheStatus, providerStatus, false)
 		}
@@ -2113,8 +3610,15 @@ func (s *runtimeState) runCachedCommand(commandPath, key string, ttl time.Durati
 
 	if s.settings.CacheEnabled && s.cache != nil {
 		if payload, err := json.Marshal(data); err == nil {
-			_ = s.cache.Set(key, payload, ttl)
-			cacheStatus = model.CacheStatus{Status: "write", AgeMS: 0, Stale: false}
+			writeStarted := time.Now()
+			if err := s.cache.Set(key, payload, ttl); err != nil {
+				tracex.RecordCache("set", "error", time.Since(writeStarted))
+				log.Warn("cache write failed", "error", err)
+			} else {
+				tracex.RecordCache("set", "write", time.Since(writeStarted))
+				log.Debug("cache write", "ttl", ttl.String(), "bytes", len(payload))
+			}
+			cacheStatus = model.CacheStatus{Status: "write", AgeMS: 0, Stale: false, TTLMS: ttl.Milliseconds()}
 		}
 	}
 
@@ -2123,6 +3627,17 @@ func (s *runtimeState) runCachedCommand(commandPath, key string, ttl time.Durati
 }
 
 func (s *runtimeState) emitSuccess(commandPath string, data any, warnings []string, cacheStatus model.CacheStatus, providers []model.ProviderStatus, partial bool) error {
+	if notes := slices.Concat(s.chainSyncWarnings, s.tokenLookupWarnings(), s.notifyWarnings.list()); len(notes) > 0 {
+		warnings = append(slices.Clip(warnings), notes...)
+	}
+	trace, traceWarnings := s.finishTrace(commandPath, nil)
+	if len(traceWarnings) > 0 {
+		warnings = append(slices.Clip(warnings), traceWarnings...)
+	}
+	var nextCursor string
+	if s.listPage != nil {
+		data, nextCursor = s.listPage.apply(data)
+	}
 	env := model.Envelope{
 		Version:  model.EnvelopeVersion,
 		Success:  true,
@@ -2133,9 +3648,13 @@ func (s *runtimeState) emitSuccess(commandPath string, data any, warnings []stri
 			RequestID: newRequestID(),
 			Timestamp: s.runner.now().UTC(),
 			Command:   commandPath,
-			Providers: providers,
+			Providers: replayStatuses(providers),
 			Cache:     cacheStatus,
 			Partial:   partial,
+
+			ResolvedNames: s.resolvedNames,
+			NextCursor:    nextCursor,
+			Trace:         trace,
 		},
 	}
 	return out.Render(s.runner.stdout, env, s.settings)
@@ -2151,8 +3670,10 @@ func (s *runtimeState) renderError(commandPath string, err error, warnings []str
 	code := clierr.ExitCode(err)
 	typ := "internal_error"
 	message := err.Error()
+	var details any
 	if cErr, ok := clierr.As(err); ok {
 		message = cErr.Message
+		details = cErr.Details
 		if cErr.Cause != nil {
 			message = fmt.Sprintf("%s: %v", cErr.Message, cErr.Cause)
 		}
@@ -2173,6 +3694,10 @@ func (s *runtimeState) renderError(commandPath string, err error, warnings []str
 			typ = "partial_results"
 		case clierr.CodeBlocked:
 			typ = "command_blocked"
+		case clierr.CodeHealthAlert:
+			typ = "health_alert"
+		case clierr.CodeOffline:
+			typ = "offline_unavailable"
 		case clierr.CodeActionPlan:
 			typ = "action_plan_error"
 		case clierr.CodeActionSim:
@@ -2183,6 +3708,8 @@ func (s *runtimeState) renderError(commandPath string, err error, warnings []str
 			typ = "action_timeout"
 		case clierr.CodeSigner:
 			typ = "signer_error"
+		case clierr.CodeInterrupted:
+			typ = "action_interrupted"
 		}
 	}
 
@@ -2192,6 +3719,11 @@ func (s *runtimeState) renderError(commandPath string, err error, warnings []str
 	}
 	settings.ResultsOnly = false
 	settings.SelectFields = nil
+	settings.Query = ""
+	trace, traceWarnings := s.finishTrace(commandPath, err)
+	if len(traceWarnings) > 0 {
+		warnings = append(slices.Clip(warnings), traceWarnings...)
+	}
 	env := model.Envelope{
 		Version: model.EnvelopeVersion,
 		Success: false,
@@ -2200,15 +3732,17 @@ func (s *runtimeState) renderError(commandPath string, err error, warnings []str
 			Code:    code,
 			Type:    typ,
 			Message: message,
+			Details: details,
 		},
 		Warnings: warnings,
 		Meta: model.EnvelopeMeta{
 			RequestID: newRequestID(),
 			Timestamp: s.runner.now().UTC(),
 			Command:   commandPath,
-			Providers: providers,
+			Providers: replayStatuses(providers),
 			Cache:     cacheMetaBypass(),
 			Partial:   partial,
+			Trace:     trace,
 		},
 	}
 	_ = out.Render(s.runner.stderr, env, settings)
@@ -2279,6 +3813,14 @@ func yieldProviderSupportsChain(name string, chain id.Chain) bool {
 		return chain.IsEVM()
 	case "moonwell":
 		return chain.IsEVM() && (chain.EVMChainID == 8453 || chain.EVMChainID == 10)
+	case "spark", "staking":
+		return chain.IsEVM() && chain.EVMChainID == 1
+	case "aerodrome":
+		return chain.IsEVM() && chain.EVMChainID == 8453
+	case "velodrome":
+		return chain.IsEVM() && chain.EVMChainID == 10
+	case "hyperliquid":
+		return chain.IsEVM() && chain.EVMChainID == 999
 	default:
 		return true
 	}
@@ -2314,6 +3856,10 @@ func sortYieldOpportunities(items []model.YieldOpportunity, sortBy string) {
 
 func compareYieldOpportunities(a, b model.YieldOpportunity, sortBy string) bool {
 	switch sortBy {
+	case "apy_effective":
+		if a.APYEffective != b.APYEffective {
+			return a.APYEffective > b.APYEffective
+		}
 	case "tvl_usd":
 		if a.TVLUSD != b.TVLUSD {
 			return a.TVLUSD > b.TVLUSD
@@ -2322,6 +3868,10 @@ func compareYieldOpportunities(a, b model.YieldOpportunity, sortBy string) bool
 		if a.LiquidityUSD != b.LiquidityUSD {
 			return a.LiquidityUSD > b.LiquidityUSD
 		}
+	case "score", "risk_score":
+		if a.RiskScore != b.RiskScore {
+			return a.RiskScore < b.RiskScore
+		}
 	default:
 		if a.APYTotal != b.APYTotal {
 			return a.APYTotal > b.APYTotal
@@ -2339,6 +3889,36 @@ func compareYieldOpportunities(a, b model.YieldOpportunity, sortBy string) bool
 	return strings.Compare(a.OpportunityID, b.OpportunityID) < 0
 }
 
+// filterYieldByRisk drops opportunities whose risk_score exceeds maxScore.
+func filterYieldByRisk(items []model.YieldOpportunity, maxScore float64) []model.YieldOpportunity {
+	out := make([]model.YieldOpportunity, 0, len(items))
+	for _, item := range items {
+		if item.RiskScore <= maxScore {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// filterYieldByExitLiquidity drops opportunities whose immediately withdrawable
+// share of TVL is below minPct. Opportunities without exit liquidity estimates
+// are kept only when includeIncomplete is set.
+func filterYieldByExitLiquidity(items []model.YieldOpportunity, minPct float64, includeIncomplete bool) []model.YieldOpportunity {
+	out := make([]model.YieldOpportunity, 0, len(items))
+	for _, item := range items {
+		if item.ExitLiquidity == nil {
+			if includeIncomplete {
+				out = append(out, item)
+			}
+			continue
+		}
+		if item.ExitLiquidity.ImmediatePct >= minPct {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
 func filterYieldOpportunitiesByID(items []model.YieldOpportunity, ids map[string]struct{}) []model.YieldOpportunity {
 	if len(ids) == 0 {
 		return items
@@ -2431,6 +4011,23 @@ func parseYieldHistoryInterval(input string) (providers.YieldHistoryInterval, er
 	}
 }
 
+func parseMetricsWindow(input string) (providers.MetricsWindow, error) {
+	switch strings.ToLower(strings.TrimSpace(input)) {
+	case "", "24h", "1d":
+		return providers.MetricsWindow24h, nil
+	case "7d":
+		return providers.MetricsWindow7d, nil
+	case "30d", "1m":
+		return providers.MetricsWindow30d, nil
+	default:
+		return "", clierr.New(clierr.CodeUsage, "--window must be one of: 24h,7d,30d")
+	}
+}
+
+func metricsWindowValues() []string {
+	return []string{string(providers.MetricsWindow24h), string(providers.MetricsWindow7d), string(providers.MetricsWindow30d)}
+}
+
 func resolveYieldHistoryRange(fromArg, toArg, windowArg string, now time.Time) (time.Time, time.Time, error) {
 	endTime := now.UTC()
 	if strings.TrimSpace(toArg) != "" {
@@ -2560,6 +4157,29 @@ func parseChainAsset(chainArg, assetArg string) (id.Chain, id.Asset, error) {
 	return chain, asset, nil
 }
 
+// parseBridgeDestinationAsset resolves --to-asset on the destination chain.
+// When it is omitted the source symbol is reused, falling back to an
+// equivalent bridged or wrapped symbol if the destination registers the asset
+// under a different ticker.
+func parseBridgeDestinationAsset(fromAsset id.Asset, toAssetArg string, toChain id.Chain) (id.Asset, error) {
+	var (
+		toAsset id.Asset
+		err     error
+	)
+	if toAssetInput := strings.TrimSpace(toAssetArg); toAssetInput != "" {
+		toAsset, err = id.ParseAsset(toAssetInput, toChain)
+	} else {
+		if fromAsset.Symbol == "" {
+			return id.Asset{}, clierr.New(clierr.CodeUsage, "destination asset cannot be inferred, provide --to-asset")
+		}
+		toAsset, err = id.ParseEquivalentAsset(fromAsset.Symbol, toChain, id.DefaultResolveOptions())
+	}
+	if err != nil {
+		return id.Asset{}, clierr.Wrap(clierr.CodeUsage, "resolve destination asset", err)
+	}
+	return toAsset, nil
+}
+
 func parseOptionalChainAsset(chain id.Chain, assetArg string) (id.Asset, error) {
 	assetArg = strings.TrimSpace(assetArg)
 	if assetArg == "" {
@@ -2635,7 +4255,7 @@ func chainAssetFilterCacheValue(asset id.Asset, rawInput string) string {
 }
 
 func fetchGasPrice(ctx context.Context, chain id.Chain, rpcURL string, now func() time.Time) (model.GasPrice, error) {
-	client, err := ethclient.DialContext(ctx, rpcURL)
+	client, err := rpcx.DialContext(ctx, rpcURL)
 	if err != nil {
 		return model.GasPrice{}, clierr.Wrap(clierr.CodeUnavailable, "connect rpc", err)
 	}
@@ -2688,6 +4308,50 @@ func weiToGwei(wei *big.Int) string {
 	return gwei.Text('f', 6)
 }
 
+// parseSwapInputs parses --from-assets legs of the form asset:amount, where
+// amount is decimal. The last colon splits each leg so CAIP-19 asset IDs work.
+func parseSwapInputs(raw string, chain id.Chain) ([]providers.SwapInput, error) {
+	var inputs []providers.SwapInput
+	for _, leg := range strings.Split(raw, ",") {
+		leg = strings.TrimSpace(leg)
+		if leg == "" {
+			continue
+		}
+		sep := strings.LastIndex(leg, ":")
+		if sep <= 0 || sep == len(leg)-1 {
+			return nil, clierr.New(clierr.CodeUsage, "--from-assets legs must be asset:amount, got "+leg)
+		}
+		asset, err := id.ParseAsset(strings.TrimSpace(leg[:sep]), chain)
+		if err != nil {
+			return nil, err
+		}
+		decimals := asset.Decimals
+		if decimals <= 0 {
+			decimals = 18
+		}
+		base, decimal, err := id.NormalizeAmount("", strings.TrimSpace(leg[sep+1:]), decimals)
+		if err != nil {
+			return nil, err
+		}
+		inputs = append(inputs, providers.SwapInput{Asset: asset, AmountBaseUnits: base, AmountDecimal: decimal})
+	}
+	if len(inputs) == 0 {
+		return nil, clierr.New(clierr.CodeUsage, "--from-assets requires at least one asset:amount leg")
+	}
+	return inputs, nil
+}
+
+func swapInputKeys(inputs []providers.SwapInput) []string {
+	if len(inputs) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(inputs))
+	for _, in := range inputs {
+		keys = append(keys, in.Asset.AssetID+"="+in.AmountBaseUnits)
+	}
+	return keys
+}
+
 func cacheKey(commandPath string, req any) string {
 	buf, _ := json.Marshal(req)
 	prefix := []byte(commandPath + "|" + cachePayloadSchemaVersion + "|")
@@ -2697,7 +4361,7 @@ func cacheKey(commandPath string, req any) string {
 
 func newRequestID() string {
 	buf := make([]byte, 16)
-	_, _ = rand.Read(buf)
+	_, _ = randx.Read(buf)
 	return hex.EncodeToString(buf)
 }
 
@@ -2735,7 +4399,15 @@ func statusFromErr(err error) string {
 		case clierr.CodeRateLimited:
 			return "rate_limited"
 		case clierr.CodeUnavailable:
+			if errors.Is(err, breaker.ErrOpen) {
+				return "circuit_open"
+			}
+			if errors.Is(err, httpx.ErrOffline) {
+				return "offline"
+			}
 			return "unavailable"
+		case clierr.CodeOffline:
+			return "offline"
 		default:
 			return "error"
 		}
@@ -2743,6 +4415,13 @@ func statusFromErr(err error) string {
 	return "error"
 }
 
+func cacheHitTraceStatus(stale bool) string {
+	if stale {
+		return "stale"
+	}
+	return "hit"
+}
+
 func cacheMetaBypass() model.CacheStatus {
 	return model.CacheStatus{Status: "bypass", AgeMS: 0, Stale: false}
 }
@@ -2809,7 +4488,7 @@ func staleFallbackAllowed(err error) bool {
 func shouldOpenCache(commandPath string) bool {
 	path := normalizeCommandPath(commandPath)
 	switch path {
-	case "", "version", "schema", "providers", "providers list", "chains list", "chains gas":
+	case "", "version", "schema", "providers", "providers list", "providers health", "providers verify", "config", "config profiles", "config profiles list", "config profiles use", "assets add", "assets remove", "assets list", "rpc", "rpc list", "rpc add", "rpc test", "chains list", "chains sync", "chains gas", "aa paymasters", "verify quote", "quotes", "quotes history", "mcp", "completion", "completion bash", "completion zsh", "completion fish", "completion powershell", cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd:
 		return false
 	}
 	if isExecutionCommandPath(path) {
@@ -2828,7 +4507,12 @@ func normalizeCommandPath(commandPath string) string {
 
 func isExecutionCommandPath(path string) bool {
 	switch path {
-	case "actions", "actions list", "actions show", "actions estimate":
+	case "actions", "actions list", "actions show", "actions preview", "actions estimate", "actions compose", "actions resume", "actions prune", "actions export", "actions encrypt",
+		"swap run", "swap limit", "swap limit create", "swap limit list", "swap limit status", "swap limit cancel",
+		"templates", "templates create", "templates list", "templates run", "templates delete",
+		"schedule", "schedule create", "schedule list", "schedule run", "schedule delete",
+		"signer rotate", "signer history",
+		"tx", "tx list", "tx speedup", "tx cancel":
 		return true
 	}
 	parts := strings.Fields(path)
@@ -2836,7 +4520,7 @@ func isExecutionCommandPath(path string) bool {
 		return false
 	}
 	switch parts[0] {
-	case "swap", "bridge", "approvals", "transfer", "lend", "rewards", "yield":
+	case "swap", "bridge", "route", "approvals", "transfer", "lend", "rewards", "yield":
 		last := parts[len(parts)-1]
 		return last == "plan" || last == "submit" || last == "status"
 	default:
@@ -2868,12 +4552,25 @@ func (s *runtimeState) ensureActionStore() error {
 	}
 	store, err := execution.OpenStore(path, lockPath)
 	if err != nil {
-		return clierr.Wrap(clierr.CodeInternal, "open action store", err)
+		return wrapOpenActionStoreError(err)
+	}
+	if err := s.watchActionTransitions(store); err != nil {
+		_ = store.Close()
+		return err
 	}
 	s.actionStore = store
 	return nil
 }
 
+// wrapOpenActionStoreError reports a missing or wrong encryption key as an
+// auth error so callers can tell it from a corrupt store.
+func wrapOpenActionStoreError(err error) error {
+	if errors.Is(err, execution.ErrStoreKeyUnavailable) {
+		return clierr.Wrap(clierr.CodeAuth, "open action store", err)
+	}
+	return clierr.Wrap(clierr.CodeInternal, "open action store", err)
+}
+
 func (s *runtimeState) actionBuilderRegistry() *actionbuilder.Registry {
 	if s.actionBuilder == nil {
 		s.actionBuilder = actionbuilder.New(s.swapProviders, s.bridgeProviders)
@@ -3010,6 +4707,25 @@ func parseActionEstimateOptions(
 	return opts, nil
 }
 
+// providerHTTP returns base tuned for provider by http.providers.<name>:
+// its own rate limit and retry count.
+func (s *runtimeState) providerHTTP(base *httpx.Client, provider string) *httpx.Client {
+	policy := s.settings.ProviderHTTP[provider]
+	return base.ForProvider(provider, httpx.Policy{RateLimit: policy.RateLimit, Burst: policy.Burst, Retries: policy.Retries})
+}
+
+// waitSharedRefresh lets stale-while-revalidate refreshes started by this
+// invocation finish writing before the cache store closes. The envelope has
+// already been written, so only process exit waits.
+func (s *runtimeState) waitSharedRefresh() {
+	if s.runner.parent != nil {
+		return
+	}
+	if shared := cache.ActiveShared(); shared != nil && s.cache != nil {
+		shared.Wait(s.settings.Timeout)
+	}
+}
+
 func (s *runtimeState) resetCommandDiagnostics() {
 	s.lastWarnings = nil
 	s.lastProviders = nil
@@ -5,15 +5,20 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/ggonzalez94/defi-cli/internal/config"
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
 	"github.com/ggonzalez94/defi-cli/internal/execution"
+	execsigner "github.com/ggonzalez94/defi-cli/internal/execution/signer"
+	"github.com/ggonzalez94/defi-cli/internal/id"
 	"github.com/ggonzalez94/defi-cli/internal/model"
 	"github.com/ggonzalez94/defi-cli/internal/ows"
+	"github.com/ggonzalez94/defi-cli/internal/policy"
 	"github.com/ggonzalez94/defi-cli/internal/providers"
 	"github.com/ggonzalez94/defi-cli/internal/schema"
 	"github.com/spf13/cobra"
@@ -89,6 +94,12 @@ func TestShouldOpenActionStore(t *testing.T) {
 	if !shouldOpenActionStore("actions estimate") {
 		t.Fatal("expected actions estimate to require action store")
 	}
+	if !shouldOpenActionStore("actions compose") {
+		t.Fatal("expected actions compose to require action store")
+	}
+	if !shouldOpenActionStore("actions resume") {
+		t.Fatal("expected actions resume to require action store")
+	}
 	if shouldOpenActionStore("swap quote") {
 		t.Fatal("did not expect swap quote to require action store")
 	}
@@ -142,6 +153,9 @@ func TestShouldOpenCacheBypassesExecutionCommands(t *testing.T) {
 	if shouldOpenCache("rewards compound status") {
 		t.Fatal("did not expect rewards compound status to open cache")
 	}
+	if shouldOpenCache("aa paymasters") {
+		t.Fatal("did not expect aa paymasters to open cache")
+	}
 	if shouldOpenCache("actions show") {
 		t.Fatal("did not expect actions show to open cache")
 	}
@@ -476,6 +490,61 @@ func TestRunnerTransferPlanAcceptsStructuredInputJSON(t *testing.T) {
 	}
 }
 
+func TestTransferPlanPreviewAndActionsPreview(t *testing.T) {
+	t.Setenv("DEFI_ACTIONS_PATH", filepath.Join(t.TempDir(), "actions.db"))
+	t.Setenv("DEFI_ACTIONS_LOCK_PATH", filepath.Join(t.TempDir(), "actions.lock"))
+	run := func(args ...string) (int, string, string) {
+		var stdout, stderr bytes.Buffer
+		code := NewRunnerWithWriters(&stdout, &stderr).Run(args)
+		return code, stdout.String(), stderr.String()
+	}
+
+	code, out, stderr := run(
+		"transfer", "plan",
+		"--chain", "taiko",
+		"--asset", "USDC",
+		"--amount", "1000000",
+		"--from-address", "0x00000000000000000000000000000000000000aa",
+		"--recipient", "0x00000000000000000000000000000000000000bb",
+		"--preview",
+		"--results-only",
+	)
+	if code != 0 {
+		t.Fatalf("transfer plan failed: code=%d stderr=%s", code, stderr)
+	}
+	var action execution.Action
+	if err := json.Unmarshal([]byte(out), &action); err != nil {
+		t.Fatalf("decode plan: %v output=%s", err, out)
+	}
+	if action.Preview == nil || len(action.Preview.Steps) != 1 {
+		t.Fatalf("expected plan output to carry a one-step preview, got %+v", action.Preview)
+	}
+	call := action.Preview.Steps[0].Calls[0]
+	if call.Method != "transfer" || call.Amount != "1000000" || !strings.EqualFold(call.Recipient, "0x00000000000000000000000000000000000000bb") {
+		t.Fatalf("unexpected transfer preview: %+v", call)
+	}
+	if _, ok := action.Metadata[execution.MetadataPlanFlags].(map[string]any)["preview"]; ok {
+		t.Fatal("expected --preview to be left out of the recorded plan flags")
+	}
+
+	code, out, stderr = run("actions", "preview", "--action-id", action.ActionID, "--results-only")
+	if code != 0 {
+		t.Fatalf("actions preview failed: code=%d stderr=%s", code, stderr)
+	}
+	var preview execution.ActionPreview
+	if err := json.Unmarshal([]byte(out), &preview); err != nil {
+		t.Fatalf("decode preview: %v output=%s", err, out)
+	}
+	if preview.UndecodedCalls != 0 || len(preview.Steps) != 1 || !strings.HasPrefix(preview.Steps[0].Calls[0].Summary, "transfer 1000000 of ") {
+		t.Fatalf("unexpected preview: %+v", preview)
+	}
+
+	code, out, _ = run("actions", "show", "--action-id", action.ActionID, "--results-only")
+	if code != 0 || strings.Contains(out, `"preview"`) {
+		t.Fatalf("expected the stored action to omit the preview, got code=%d output=%s", code, out)
+	}
+}
+
 func TestBridgePlanAcceptsStructuredWalletInput(t *testing.T) {
 	home := t.TempDir()
 	t.Setenv("HOME", home)
@@ -788,7 +857,7 @@ func TestRunnerBridgeQuoteSchemaIncludesRequiredProviderMetadata(t *testing.T) {
 		}
 		schemaDoc, _ := field["schema"].(map[string]any)
 		enumValues, _ := schemaDoc["enum"].([]any)
-		if len(enumValues) != 3 || enumValues[0] != "across" || enumValues[1] != "lifi" || enumValues[2] != "bungee" {
+		if len(enumValues) != 5 || enumValues[0] != "across" || enumValues[1] != "lifi" || enumValues[2] != "bungee" || enumValues[3] != "wormhole" || enumValues[4] != "cctp" {
 			t.Fatalf("unexpected provider enum: %#v", schemaDoc["enum"])
 		}
 	}
@@ -1058,6 +1127,64 @@ func TestRunnerActionsEstimateTempoActionsNoSteps(t *testing.T) {
 	}
 }
 
+func TestRunnerActionsComposeJoinsPlannedActions(t *testing.T) {
+	actionStorePath := filepath.Join(t.TempDir(), "actions.db")
+	actionLockPath := filepath.Join(t.TempDir(), "actions.lock")
+	t.Setenv("DEFI_ACTIONS_PATH", actionStorePath)
+	t.Setenv("DEFI_ACTIONS_LOCK_PATH", actionLockPath)
+
+	store, err := execution.OpenStore(actionStorePath, actionLockPath)
+	if err != nil {
+		t.Fatalf("open action store: %v", err)
+	}
+	ids := []string{"act_0123456789abcdef0123456789abcde1", "act_0123456789abcdef0123456789abcde2"}
+	for _, actionID := range ids {
+		action := execution.NewAction(actionID, "swap", "eip155:1", execution.Constraints{Simulate: true})
+		action.Status = execution.ActionStatusPlanned
+		action.FromAddress = "0x00000000000000000000000000000000000000aa"
+		action.Steps = append(action.Steps, execution.ActionStep{
+			StepID:  "swap-1",
+			Type:    execution.StepTypeSwap,
+			Status:  execution.StepStatusPending,
+			ChainID: "eip155:1",
+			Target:  "0x00000000000000000000000000000000000000bb",
+			Data:    "0x",
+			Value:   "0",
+		})
+		if err := store.Save(action); err != nil {
+			t.Fatalf("save action: %v", err)
+		}
+	}
+	_ = store.Close()
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	r := NewRunnerWithWriters(&stdout, &stderr)
+	code := r.Run([]string{"actions", "compose", "--action-ids", strings.Join(ids, ",")})
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d stderr=%s", code, stderr.String())
+	}
+	var env struct {
+		Data execution.Action `json:"data"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &env); err != nil {
+		t.Fatalf("failed to parse output: %v output=%s", err, stdout.String())
+	}
+	if env.Data.IntentType != execution.IntentComposite || len(env.Data.SubActions) != 2 {
+		t.Fatalf("unexpected composite action: %+v", env.Data)
+	}
+	if got := env.Data.SubActions[1].DependsOn; len(got) != 1 || got[0] != ids[0] {
+		t.Fatalf("expected second sub-action to depend on the first, got %v", got)
+	}
+
+	stdout.Reset()
+	stderr.Reset()
+	code = r.Run([]string{"actions", "show", "--action-id", ids[0]})
+	if code == 0 {
+		t.Fatalf("expected composed sub-action to be unavailable standalone, stdout=%s", stdout.String())
+	}
+}
+
 func TestRunnerExecutionStatusBypassesCacheOpen(t *testing.T) {
 	setUnopenableCacheEnv(t)
 
@@ -1186,3 +1313,394 @@ func (stubSwapExecutionProvider) BuildSwapAction(_ context.Context, req provider
 	action.InputAmount = req.AmountBaseUnits
 	return action, nil
 }
+
+func TestRunnerTemplatesCreateAndRun(t *testing.T) {
+	t.Setenv("DEFI_ACTIONS_PATH", filepath.Join(t.TempDir(), "actions.db"))
+	t.Setenv("DEFI_ACTIONS_LOCK_PATH", filepath.Join(t.TempDir(), "actions.lock"))
+
+	run := func(args ...string) (int, map[string]any, string) {
+		var stdout bytes.Buffer
+		var stderr bytes.Buffer
+		code := NewRunnerWithWriters(&stdout, &stderr).Run(args)
+		var data map[string]any
+		if code == 0 {
+			if err := json.Unmarshal(stdout.Bytes(), &data); err != nil {
+				t.Fatalf("failed to parse output: %v output=%s", err, stdout.String())
+			}
+		}
+		return code, data, stderr.String()
+	}
+
+	code, planned, stderr := run(
+		"transfer", "plan",
+		"--chain", "taiko",
+		"--asset", "USDC",
+		"--amount", "1000000",
+		"--from-address", "0x00000000000000000000000000000000000000aa",
+		"--recipient", "0x00000000000000000000000000000000000000bb",
+		"--results-only",
+	)
+	if code != 0 {
+		t.Fatalf("transfer plan failed: code=%d stderr=%s", code, stderr)
+	}
+	actionID, _ := planned["action_id"].(string)
+
+	code, tmpl, stderr := run("templates", "create", "--from-action", actionID, "--name", "payout", "--results-only")
+	if code != 0 {
+		t.Fatalf("templates create failed: code=%d stderr=%s", code, stderr)
+	}
+	if tmpl["command"] != "transfer plan" {
+		t.Fatalf("unexpected template command: %#v", tmpl["command"])
+	}
+	fixed, _ := tmpl["fixed"].(map[string]any)
+	if fixed["recipient"] != "0x00000000000000000000000000000000000000bb" {
+		t.Fatalf("expected recipient to be fixed, got %#v", tmpl["fixed"])
+	}
+
+	code, action, stderr := run("templates", "run", "payout", "--set", "amount-decimal=2.5", "--results-only")
+	if code != 0 {
+		t.Fatalf("templates run failed: code=%d stderr=%s", code, stderr)
+	}
+	if action["action_id"] == actionID || action["intent_type"] != "transfer" {
+		t.Fatalf("expected a new transfer action, got %#v", action)
+	}
+	if action["input_amount"] != "2500000" {
+		t.Fatalf("expected input_amount 2500000, got %#v", action["input_amount"])
+	}
+
+	code, _, stderr = run("templates", "run", "payout", "--set", "recipient=0x00000000000000000000000000000000000000cc")
+	if code != 2 {
+		t.Fatalf("expected usage exit for fixed flag override, got %d stderr=%s", code, stderr)
+	}
+	if !strings.Contains(stderr, "fixed") {
+		t.Fatalf("expected fixed-flag error, got %s", stderr)
+	}
+}
+
+func TestRunnerScheduleCreateAndRunSkipsUndue(t *testing.T) {
+	actionStorePath := filepath.Join(t.TempDir(), "actions.db")
+	actionLockPath := filepath.Join(t.TempDir(), "actions.lock")
+	t.Setenv("DEFI_ACTIONS_PATH", actionStorePath)
+	t.Setenv("DEFI_ACTIONS_LOCK_PATH", actionLockPath)
+
+	store, err := execution.OpenStore(actionStorePath, actionLockPath)
+	if err != nil {
+		t.Fatalf("open action store: %v", err)
+	}
+	action := execution.NewAction("act_0123456789abcdef0123456789abcdef", "swap", "eip155:8453", execution.Constraints{Simulate: true})
+	action.Metadata = map[string]any{
+		execution.MetadataPlanCommand: "swap plan",
+		execution.MetadataPlanFlags: map[string]any{
+			"provider":   "odos",
+			"chain":      "base",
+			"from-asset": "USDC",
+			"to-asset":   "WETH",
+			"amount":     "100000000",
+		},
+	}
+	if err := store.Save(action); err != nil {
+		t.Fatalf("save action: %v", err)
+	}
+	_ = store.Close()
+
+	run := func(args ...string) (int, string, string) {
+		var stdout bytes.Buffer
+		var stderr bytes.Buffer
+		code := NewRunnerWithWriters(&stdout, &stderr).Run(args)
+		return code, stdout.String(), stderr.String()
+	}
+
+	code, out, stderr := run("schedule", "create", "--from-action", action.ActionID, "--name", "weth-dca", "--every", "1d", "--start-at", "2099-01-01T00:00:00Z", "--max-total", "700000000", "--results-only")
+	if code != 0 {
+		t.Fatalf("schedule create failed: code=%d stderr=%s", code, stderr)
+	}
+	var schedule execution.Schedule
+	if err := json.Unmarshal([]byte(out), &schedule); err != nil {
+		t.Fatalf("failed to parse schedule: %v output=%s", err, out)
+	}
+	if schedule.Template.Command != "swap plan" || schedule.IntervalSeconds != 86400 || schedule.MaxTotal != "700000000" {
+		t.Fatalf("unexpected schedule: %+v", schedule)
+	}
+
+	code, out, stderr = run("schedule", "run", "--results-only")
+	if code != 0 {
+		t.Fatalf("schedule run failed: code=%d stderr=%s", code, stderr)
+	}
+	var results []execution.ScheduleRun
+	if err := json.Unmarshal([]byte(out), &results); err != nil {
+		t.Fatalf("failed to parse run results: %v output=%s", err, out)
+	}
+	if len(results) != 0 {
+		t.Fatalf("expected no due schedules, got %+v", results)
+	}
+
+	code, _, stderr = run("schedule", "create", "--from-action", action.ActionID, "--name", "weth-dca", "--every", "1d")
+	if code != 2 || !strings.Contains(stderr, "already exists") {
+		t.Fatalf("expected duplicate schedule usage error, got code=%d stderr=%s", code, stderr)
+	}
+}
+
+func TestRunnerSignerRotateUpdatesTemplatesAndAuditLog(t *testing.T) {
+	actionsPath := filepath.Join(t.TempDir(), "actions.db")
+	actionsLockPath := filepath.Join(t.TempDir(), "actions.lock")
+	t.Setenv("DEFI_ACTIONS_PATH", actionsPath)
+	t.Setenv("DEFI_ACTIONS_LOCK_PATH", actionsLockPath)
+	keyDir := t.TempDir()
+	oldKeyFile := filepath.Join(keyDir, "key.hex")
+	newKeyFile := filepath.Join(keyDir, "next.hex")
+	if err := os.WriteFile(oldKeyFile, []byte("59c6995e998f97a5a0044976f0945388cf9b7e5e5f4f9d2d9d8f1f5b7f6d11d1"), 0o600); err != nil {
+		t.Fatalf("write old key: %v", err)
+	}
+	if err := os.WriteFile(newKeyFile, []byte("8b3a350cf5c34c9194ca85829a2df0ec3153be0318b5e2d3348e872092edffba"), 0o600); err != nil {
+		t.Fatalf("write new key: %v", err)
+	}
+	t.Setenv("DEFI_PRIVATE_KEY", "")
+	t.Setenv("DEFI_PRIVATE_KEY_FILE", oldKeyFile)
+	oldSigner, err := execsigner.NewLocalSigner(execsigner.LocalSignerConfig{PrivateKeyFile: oldKeyFile})
+	if err != nil {
+		t.Fatalf("load old key: %v", err)
+	}
+	oldAddress := oldSigner.Address().Hex()
+	policyPath := filepath.Join(t.TempDir(), "policy.yaml")
+	if err := os.WriteFile(policyPath, []byte("# treasury limits\nallowed_spenders:\n  - "+strings.ToLower(oldAddress)+"\n  - 0x00000000000000000000000000000000000000cc\n"), 0o600); err != nil {
+		t.Fatalf("write policy: %v", err)
+	}
+	t.Setenv("DEFI_POLICY_PATH", policyPath)
+
+	run := func(args ...string) (int, string, string) {
+		var stdout bytes.Buffer
+		var stderr bytes.Buffer
+		code := NewRunnerWithWriters(&stdout, &stderr).Run(args)
+		return code, stdout.String(), stderr.String()
+	}
+
+	code, out, stderr := run(
+		"transfer", "plan",
+		"--chain", "taiko",
+		"--asset", "USDC",
+		"--amount", "1000000",
+		"--from-address", oldAddress,
+		"--recipient", "0x00000000000000000000000000000000000000bb",
+		"--results-only",
+	)
+	if code != 0 {
+		t.Fatalf("transfer plan failed: code=%d stderr=%s", code, stderr)
+	}
+	var planned execution.Action
+	if err := json.Unmarshal([]byte(out), &planned); err != nil {
+		t.Fatalf("decode planned action: %v", err)
+	}
+	if code, _, stderr := run("templates", "create", "--from-action", planned.ActionID, "--name", "payout", "--results-only"); code != 0 {
+		t.Fatalf("templates create failed: code=%d stderr=%s", code, stderr)
+	}
+	store, err := execution.OpenStore(actionsPath, actionsLockPath)
+	if err != nil {
+		t.Fatalf("open action store: %v", err)
+	}
+	payout, err := store.GetTemplate("payout")
+	if err != nil {
+		t.Fatalf("get template: %v", err)
+	}
+	if err := store.CreateSchedule(execution.Schedule{Name: "weekly-payout", Template: payout, Every: "7d", IntervalSeconds: 7 * 24 * 3600, Spent: "0"}); err != nil {
+		t.Fatalf("create schedule: %v", err)
+	}
+	_ = store.Close()
+
+	code, out, stderr = run("signer", "rotate", "--new-key-source", "file", "--new-key-file", newKeyFile)
+	if code != 0 {
+		t.Fatalf("signer rotate failed: code=%d stderr=%s", code, stderr)
+	}
+	var env struct {
+		Data     execution.SignerRotation `json:"data"`
+		Warnings []string                 `json:"warnings"`
+	}
+	if err := json.Unmarshal([]byte(out), &env); err != nil {
+		t.Fatalf("decode rotate output: %v output=%s", err, out)
+	}
+	rotation := env.Data
+	if rotation.OldAddress != oldAddress || rotation.NewAddress == oldAddress || rotation.KeyFile != oldKeyFile || rotation.BackupFile == "" {
+		t.Fatalf("unexpected rotation: %+v", rotation)
+	}
+	if len(rotation.TemplatesUpdated) != 1 || rotation.TemplatesUpdated[0] != "payout" {
+		t.Fatalf("expected payout template update, got %+v", rotation.TemplatesUpdated)
+	}
+	if len(rotation.SchedulesUpdated) != 1 || rotation.SchedulesUpdated[0] != "weekly-payout" {
+		t.Fatalf("expected weekly-payout schedule update, got %+v", rotation.SchedulesUpdated)
+	}
+	if len(rotation.PolicyUpdated) != 1 || rotation.PolicyUpdated[0] != "allowed_spenders[0]" {
+		t.Fatalf("expected allowed_spenders[0] policy update, got %+v", rotation.PolicyUpdated)
+	}
+	policyFile, _, err := policy.LoadExecutionFile(policyPath)
+	if err != nil || len(policyFile.AllowedSpenders) != 2 || policyFile.AllowedSpenders[0] != rotation.NewAddress {
+		t.Fatalf("expected policy spender to be rotated, got %+v err=%v", policyFile, err)
+	}
+	if buf, _ := os.ReadFile(policyPath); !strings.Contains(string(buf), "# treasury limits") {
+		t.Fatalf("expected policy comments to be kept, got %s", buf)
+	}
+	if !strings.Contains(strings.Join(env.Warnings, "\n"), "1 planned actions still use") {
+		t.Fatalf("expected stale planned action warning, got %v", env.Warnings)
+	}
+	installed, err := execsigner.NewLocalSigner(execsigner.LocalSignerConfig{PrivateKeyFile: oldKeyFile})
+	if err != nil || installed.Address().Hex() != rotation.NewAddress {
+		t.Fatalf("expected configured key file to hold the new key: %v", err)
+	}
+
+	code, out, stderr = run("templates", "list", "--results-only")
+	if code != 0 {
+		t.Fatalf("templates list failed: code=%d stderr=%s", code, stderr)
+	}
+	var templates []execution.Template
+	if err := json.Unmarshal([]byte(out), &templates); err != nil {
+		t.Fatalf("decode templates: %v", err)
+	}
+	if len(templates) != 1 || templates[0].Fixed["from-address"] != rotation.NewAddress {
+		t.Fatalf("expected template sender to be rotated, got %+v", templates)
+	}
+
+	code, out, stderr = run("signer", "history", "--results-only")
+	if code != 0 {
+		t.Fatalf("signer history failed: code=%d stderr=%s", code, stderr)
+	}
+	var events []execution.AuditEvent
+	if err := json.Unmarshal([]byte(out), &events); err != nil {
+		t.Fatalf("decode history: %v", err)
+	}
+	if len(events) != 1 || events[0].Event != execution.AuditEventSignerRotated || events[0].Details["new_address"] != rotation.NewAddress {
+		t.Fatalf("unexpected audit log: %+v", events)
+	}
+
+	code, _, stderr = run("signer", "rotate", "--new-key-source", "file", "--new-key-file", newKeyFile)
+	if code != 2 || !strings.Contains(stderr, "same address") {
+		t.Fatalf("expected rotating to the current key to fail with usage error, got code=%d stderr=%s", code, stderr)
+	}
+}
+
+func TestRunnerSignerRotateRefusesProtocolPolicyReferences(t *testing.T) {
+	t.Setenv("DEFI_ACTIONS_PATH", filepath.Join(t.TempDir(), "actions.db"))
+	t.Setenv("DEFI_ACTIONS_LOCK_PATH", filepath.Join(t.TempDir(), "actions.lock"))
+	keyDir := t.TempDir()
+	oldKeyFile := filepath.Join(keyDir, "key.hex")
+	newKeyFile := filepath.Join(keyDir, "next.hex")
+	if err := os.WriteFile(oldKeyFile, []byte("59c6995e998f97a5a0044976f0945388cf9b7e5e5f4f9d2d9d8f1f5b7f6d11d1"), 0o600); err != nil {
+		t.Fatalf("write old key: %v", err)
+	}
+	if err := os.WriteFile(newKeyFile, []byte("8b3a350cf5c34c9194ca85829a2df0ec3153be0318b5e2d3348e872092edffba"), 0o600); err != nil {
+		t.Fatalf("write new key: %v", err)
+	}
+	t.Setenv("DEFI_PRIVATE_KEY", "")
+	t.Setenv("DEFI_PRIVATE_KEY_FILE", oldKeyFile)
+	oldSigner, err := execsigner.NewLocalSigner(execsigner.LocalSignerConfig{PrivateKeyFile: oldKeyFile})
+	if err != nil {
+		t.Fatalf("load old key: %v", err)
+	}
+	t.Setenv("DEFI_DENY_PROTOCOLS", strings.ToLower(oldSigner.Address().Hex()))
+
+	var stdout, stderr bytes.Buffer
+	code := NewRunnerWithWriters(&stdout, &stderr).Run([]string{"signer", "rotate", "--new-key-source", "file", "--new-key-file", newKeyFile})
+	if code != 2 || !strings.Contains(stderr.String(), "deny_protocols") {
+		t.Fatalf("expected protocol policy usage error, got code=%d stderr=%s", code, stderr.String())
+	}
+	current, err := execsigner.NewLocalSigner(execsigner.LocalSignerConfig{PrivateKeyFile: oldKeyFile})
+	if err != nil || current.Address() != oldSigner.Address() {
+		t.Fatalf("expected the current key to stay installed: %v", err)
+	}
+	if matches, _ := filepath.Glob(oldKeyFile + ".rotated-*"); len(matches) != 0 {
+		t.Fatalf("expected no key backup, got %v", matches)
+	}
+}
+
+type fakeTokenPriceProvider struct {
+	fakeMarketProvider
+	prices map[string]model.TokenPrice
+}
+
+func (f fakeTokenPriceProvider) TokenPrices(context.Context, id.Chain, []string) (map[string]model.TokenPrice, error) {
+	return f.prices, nil
+}
+
+func TestCheckSwapPriceImpactRecordsAndRefuses(t *testing.T) {
+	state, _, _ := newExecutionTestState(filepath.Join(t.TempDir(), "actions.db"), filepath.Join(t.TempDir(), "actions.lock"))
+	if err := state.ensureActionStore(); err != nil {
+		t.Fatalf("open action store: %v", err)
+	}
+	defer state.actionStore.Close()
+	const weth, usdc = "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2", "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"
+	state.marketProvider = fakeTokenPriceProvider{prices: map[string]model.TokenPrice{
+		weth: {Address: weth, Decimals: 18, PriceUSD: 3000},
+		usdc: {Address: usdc, Decimals: 6, PriceUSD: 1},
+	}}
+
+	action := execution.NewAction(execution.NewActionID(), "swap", "eip155:1", execution.Constraints{Simulate: true})
+	action.InputAmount = "1000000000000000000"
+	action.Metadata = map[string]any{"token_in": weth, "token_out": usdc, "quoted_amount": "2900000000"}
+	if err := state.actionStore.Save(action); err != nil {
+		t.Fatalf("save action: %v", err)
+	}
+
+	if err := state.checkSwapPriceImpact(context.Background(), &action, 0); err != nil || action.PriceCheck != nil {
+		t.Fatalf("expected a zero limit to skip the check, got err=%v check=%+v", err, action.PriceCheck)
+	}
+	if err := state.checkSwapPriceImpact(context.Background(), &action, 5); err != nil {
+		t.Fatalf("expected 3.3%% impact to pass a 5%% limit, got %v", err)
+	}
+	if action.PriceCheck == nil || !action.PriceCheck.Passed || action.PriceCheck.ExpectedOut != "3000000000" {
+		t.Fatalf("unexpected passing price check: %+v", action.PriceCheck)
+	}
+
+	err := state.checkSwapPriceImpact(context.Background(), &action, 2)
+	typed, ok := clierr.As(err)
+	if !ok || typed.Code != clierr.CodeActionPolicy {
+		t.Fatalf("expected action policy error, got %v", err)
+	}
+	stored, err := state.actionStore.Get(action.ActionID)
+	if err != nil {
+		t.Fatalf("load action: %v", err)
+	}
+	if stored.PriceCheck == nil || stored.PriceCheck.Passed || stored.PriceCheck.PriceImpactPct < 3.3 || stored.PriceCheck.PriceImpactPct > 3.4 {
+		t.Fatalf("expected the failed check to be persisted, got %+v", stored.PriceCheck)
+	}
+}
+
+func TestResolvePrivateTxOptions(t *testing.T) {
+	mainnet := execution.Action{ChainID: "eip155:1"}
+	if opts, err := resolvePrivateTxOptions(mainnet, false, "flashbots", false); err != nil || opts != nil {
+		t.Fatalf("expected disabled private tx to resolve to nil, got %+v %v", opts, err)
+	}
+	opts, err := resolvePrivateTxOptions(mainnet, true, "flashbots", true)
+	if err != nil {
+		t.Fatalf("resolve flashbots: %v", err)
+	}
+	if opts.Relay != "flashbots" || opts.StatusURL == "" || !opts.Fallback || opts.ChainID != "eip155:1" {
+		t.Fatalf("unexpected flashbots options: %+v", opts)
+	}
+
+	base := execution.Action{ChainID: "eip155:8453"}
+	_, err = resolvePrivateTxOptions(base, true, "mevblocker", false)
+	if typed, ok := clierr.As(err); !ok || typed.Code != clierr.CodeUnsupported {
+		t.Fatalf("expected unsupported error for mevblocker on base, got %v", err)
+	}
+	opts, err = resolvePrivateTxOptions(base, true, "https://relay.example/rpc", false)
+	if err != nil || opts.Relay != "custom" || opts.RPCURL != "https://relay.example/rpc" || opts.ChainID != "eip155:8453" {
+		t.Fatalf("unexpected custom relay options: %+v %v", opts, err)
+	}
+	_, err = resolvePrivateTxOptions(base, true, "not-a-relay", false)
+	if typed, ok := clierr.As(err); !ok || typed.Code != clierr.CodeUsage {
+		t.Fatalf("expected usage error for unknown relay, got %v", err)
+	}
+}
+
+func TestTxReplaceCommandsValidateInputs(t *testing.T) {
+	t.Setenv("DEFI_ACTIONS_PATH", filepath.Join(t.TempDir(), "actions.db"))
+	t.Setenv("DEFI_ACTIONS_LOCK_PATH", filepath.Join(t.TempDir(), "actions.lock"))
+	cases := [][]string{
+		{"tx", "speedup", "--chain", "ethereum", "--tx-hash", "0x1234"},
+		{"tx", "cancel", "--chain", "ethereum", "--tx-hash", "0x" + strings.Repeat("ab", 32), "--poll-interval", "0s"},
+		{"tx", "list", "--chain", "ethereum", "--address", "not-an-address"},
+	}
+	for _, args := range cases {
+		var stdout, stderr bytes.Buffer
+		if code := NewRunnerWithWriters(&stdout, &stderr).Run(args); code != 2 {
+			t.Fatalf("%v: expected exit 2, got %d stderr=%s", args, code, stderr.String())
+		}
+	}
+}
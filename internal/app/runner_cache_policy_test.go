@@ -60,6 +60,46 @@ func TestRunCachedCommandFetchesProviderAfterTTLExpiry(t *testing.T) {
 	}
 }
 
+func TestRunCachedCommandHonorsCacheTTLAndForceFresh(t *testing.T) {
+	state, stdout := newCachePolicyTestState(t, 5*time.Minute, false)
+	key := "runner-cache-policy-ttl-override"
+	if err := state.cache.Set(key, []byte(`{"source":"cache"}`), time.Second); err != nil {
+		t.Fatalf("cache set failed: %v", err)
+	}
+	time.Sleep(1200 * time.Millisecond)
+
+	fetchCalls := 0
+	fetch := func(ctx context.Context) (any, []model.ProviderStatus, []string, bool, error) {
+		fetchCalls++
+		return map[string]any{"source": "provider"}, nil, nil, false, nil
+	}
+	state.settings.CacheTTL = time.Hour
+	if err := state.runCachedCommand("test command", key, time.Second, fetch); err != nil {
+		t.Fatalf("runCachedCommand failed: %v", err)
+	}
+	env := decodeCachePolicyEnvelope(t, stdout)
+	if fetchCalls != 0 || env.Data["source"] != "cache" {
+		t.Fatalf("expected --cache-ttl to keep the entry fresh, got calls=%d data=%#v", fetchCalls, env.Data)
+	}
+	if env.Meta.Cache.Status != "hit" || env.Meta.Cache.Stale || env.Meta.Cache.TTLMS != time.Hour.Milliseconds() {
+		t.Fatalf("expected a fresh hit with the overridden ttl, got %+v", env.Meta.Cache)
+	}
+
+	stdout.Reset()
+	state.settings.ForceFresh = true
+	if err := state.runCachedCommand("test command", key, time.Second, fetch); err != nil {
+		t.Fatalf("runCachedCommand failed: %v", err)
+	}
+	env = decodeCachePolicyEnvelope(t, stdout)
+	if fetchCalls != 1 || env.Data["source"] != "provider" || env.Meta.Cache.Status != "write" {
+		t.Fatalf("expected --force-fresh to fetch and write, got calls=%d env=%+v", fetchCalls, env)
+	}
+	cached, err := state.cache.Get(key, 0)
+	if err != nil || cached.TTL != time.Hour || !strings.Contains(string(cached.Value), "provider") {
+		t.Fatalf("expected the fresh response cached for the overridden ttl, got %+v err %v", cached, err)
+	}
+}
+
 func TestRunCachedCommandFallsBackToStaleOnProviderFailure(t *testing.T) {
 	state, stdout := newCachePolicyTestState(t, 5*time.Second, false)
 	key := "runner-cache-policy-fallback-stale"
@@ -5,18 +5,24 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ggonzalez94/defi-cli/internal/cache"
 	"github.com/ggonzalez94/defi-cli/internal/config"
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
 	"github.com/ggonzalez94/defi-cli/internal/execution"
 	"github.com/ggonzalez94/defi-cli/internal/id"
 	"github.com/ggonzalez94/defi-cli/internal/model"
 	"github.com/ggonzalez94/defi-cli/internal/ows"
+	"github.com/ggonzalez94/defi-cli/internal/policy"
 	"github.com/ggonzalez94/defi-cli/internal/providers"
+	"github.com/ggonzalez94/defi-cli/internal/ratebudget"
 	"github.com/ggonzalez94/defi-cli/internal/version"
 	"github.com/spf13/cobra"
 )
@@ -338,271 +344,1482 @@ func TestYieldPositionsCommandCallsProvider(t *testing.T) {
 	}
 }
 
-func TestParseChainAssetFilterAllowsUnknownSymbol(t *testing.T) {
-	chain, err := id.ParseChain("ethereum")
-	if err != nil {
-		t.Fatalf("parse chain: %v", err)
+func TestYieldOpportunitiesFiltersByExitLiquidity(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	fakeProvider := &fakeYieldHistoryProvider{
+		name: "morpho",
+		opportunities: []model.YieldOpportunity{
+			{
+				OpportunityID: "liquid",
+				Provider:      "morpho",
+				APYTotal:      4,
+				TVLUSD:        100,
+				ExitLiquidity: &model.YieldExitLiquidity{ImmediateUSD: 80, QueuedUSD: 20, ImmediatePct: 80},
+			},
+			{
+				OpportunityID: "illiquid",
+				Provider:      "morpho",
+				APYTotal:      9,
+				TVLUSD:        100,
+				ExitLiquidity: &model.YieldExitLiquidity{ImmediateUSD: 10, QueuedUSD: 90, ImmediatePct: 10},
+			},
+			{
+				OpportunityID: "unknown",
+				Provider:      "morpho",
+				APYTotal:      6,
+			},
+		},
 	}
-	asset, err := parseChainAssetFilter(chain, "NOTAREALTOKEN")
-	if err != nil {
-		t.Fatalf("expected unknown symbol to be accepted, got err=%v", err)
+	state := &runtimeState{
+		runner: &Runner{
+			stdout: &stdout,
+			stderr: &stderr,
+			now:    time.Now,
+		},
+		settings: config.Settings{
+			OutputMode:   "json",
+			ResultsOnly:  true,
+			Timeout:      2 * time.Second,
+			CacheEnabled: false,
+		},
+		yieldProviders: map[string]providers.YieldProvider{
+			"morpho": fakeProvider,
+		},
 	}
-	if asset.Symbol != "NOTAREALTOKEN" {
-		t.Fatalf("expected NOTAREALTOKEN symbol, got %+v", asset)
+
+	root := &cobra.Command{Use: "defi"}
+	root.SilenceUsage = true
+	root.SilenceErrors = true
+	root.SetOut(&stdout)
+	root.SetErr(&stderr)
+	root.AddCommand(state.newYieldCommand())
+	root.SetArgs([]string{
+		"yield", "opportunities",
+		"--chain", "1",
+		"--asset", "USDC",
+		"--providers", "morpho",
+		"--min-exit-liquidity-pct", "50",
+	})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("yield opportunities command failed: %v stderr=%s", err, stderr.String())
 	}
-	if asset.AssetID != "" {
-		t.Fatalf("expected empty asset id for non-registry symbol, got %s", asset.AssetID)
+
+	var out []map[string]any
+	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+		t.Fatalf("failed parsing output json: %v output=%s", err, stdout.String())
+	}
+	if len(out) != 1 || out[0]["opportunity_id"] != "liquid" {
+		t.Fatalf("expected only the liquid opportunity, got %+v", out)
+	}
+	exit, ok := out[0]["exit_liquidity"].(map[string]any)
+	if !ok || exit["immediate_pct"] != float64(80) {
+		t.Fatalf("expected exit_liquidity in output, got %+v", out[0])
 	}
 }
 
-func TestParseChainAssetFilterRejectsUnknownAddress(t *testing.T) {
-	chain, err := id.ParseChain("ethereum")
-	if err != nil {
-		t.Fatalf("parse chain: %v", err)
+func TestYieldOpportunitiesMaxRiskAndScoreSort(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	fakeProvider := &fakeYieldHistoryProvider{
+		name: "morpho",
+		opportunities: []model.YieldOpportunity{
+			{OpportunityID: "medium", Provider: "morpho", APYTotal: 6, RiskScore: 50, RiskLevel: "medium"},
+			{OpportunityID: "low", Provider: "morpho", APYTotal: 3, RiskScore: 20, RiskLevel: "low"},
+			{OpportunityID: "high", Provider: "morpho", APYTotal: 12, RiskScore: 80, RiskLevel: "high"},
+		},
 	}
-	_, err = parseChainAssetFilter(chain, "0x0000000000000000000000000000000000000001")
-	if err == nil {
-		t.Fatal("expected error for address without known chain symbol")
+	state := &runtimeState{
+		runner: &Runner{
+			stdout: &stdout,
+			stderr: &stderr,
+			now:    time.Now,
+		},
+		settings: config.Settings{
+			OutputMode:   "json",
+			ResultsOnly:  true,
+			Timeout:      2 * time.Second,
+			CacheEnabled: false,
+		},
+		yieldProviders: map[string]providers.YieldProvider{
+			"morpho": fakeProvider,
+		},
+	}
+
+	root := &cobra.Command{Use: "defi"}
+	root.SilenceUsage = true
+	root.SilenceErrors = true
+	root.SetOut(&stdout)
+	root.SetErr(&stderr)
+	root.AddCommand(state.newYieldCommand())
+	root.SetArgs([]string{
+		"yield", "opportunities",
+		"--chain", "1",
+		"--asset", "USDC",
+		"--providers", "morpho",
+		"--max-risk", "medium",
+		"--sort", "score",
+	})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("yield opportunities command failed: %v stderr=%s", err, stderr.String())
+	}
+
+	var out []map[string]any
+	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+		t.Fatalf("failed parsing output json: %v output=%s", err, stdout.String())
+	}
+	if len(out) != 2 || out[0]["opportunity_id"] != "low" || out[1]["opportunity_id"] != "medium" {
+		t.Fatalf("expected low then medium risk opportunities, got %+v", out)
+	}
+
+	root.SetArgs([]string{"yield", "opportunities", "--chain", "1", "--asset", "USDC", "--max-risk", "risky"})
+	if err := root.Execute(); err == nil {
+		t.Fatal("expected invalid --max-risk to fail")
 	}
 }
 
-func TestRunnerProvidersList(t *testing.T) {
+func TestYieldOpportunitiesAppliesProtocolPolicy(t *testing.T) {
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
-	r := NewRunnerWithWriters(&stdout, &stderr)
-	code := r.Run([]string{"providers", "list", "--results-only"})
-	if code != 0 {
-		t.Fatalf("expected exit 0, got %d stderr=%s", code, stderr.String())
+	fakeProvider := &fakeYieldHistoryProvider{
+		name: "morpho",
+		opportunities: []model.YieldOpportunity{
+			{OpportunityID: "steakhouse", Provider: "morpho", Protocol: "morpho", APYTotal: 4},
+			{OpportunityID: "gauntlet", Provider: "morpho", Protocol: "gauntlet-vaults", APYTotal: 6},
+		},
+	}
+	state := &runtimeState{
+		runner: &Runner{
+			stdout: &stdout,
+			stderr: &stderr,
+			now:    time.Now,
+		},
+		settings: config.Settings{
+			OutputMode:    "json",
+			Timeout:       2 * time.Second,
+			CacheEnabled:  false,
+			DenyProtocols: []string{"gauntlet*"},
+		},
+		yieldProviders: map[string]providers.YieldProvider{
+			"morpho": fakeProvider,
+		},
+	}
+
+	root := &cobra.Command{Use: "defi"}
+	root.SilenceUsage = true
+	root.SilenceErrors = true
+	root.SetOut(&stdout)
+	root.SetErr(&stderr)
+	root.AddCommand(state.newYieldCommand())
+	root.SetArgs([]string{"yield", "opportunities", "--chain", "1", "--asset", "USDC", "--providers", "morpho"})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("yield opportunities command failed: %v stderr=%s", err, stderr.String())
+	}
+
+	var env struct {
+		Data     []map[string]any `json:"data"`
+		Warnings []string         `json:"warnings"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &env); err != nil {
+		t.Fatalf("failed parsing output json: %v output=%s", err, stdout.String())
+	}
+	if len(env.Data) != 1 || env.Data[0]["opportunity_id"] != "steakhouse" {
+		t.Fatalf("expected denied protocol to be excluded, got %+v", env.Data)
+	}
+	if len(env.Warnings) == 0 || !strings.Contains(env.Warnings[0], "excluded by protocol policy") {
+		t.Fatalf("expected protocol policy warning, got %+v", env.Warnings)
+	}
+}
+
+// fakeAssetYieldProvider answers yield opportunities per requested asset
+// symbol and records which symbols were asked for.
+type fakeAssetYieldProvider struct {
+	name          string
+	opportunities map[string][]model.YieldOpportunity
+	errs          map[string]error
+	symbols       []string
+}
+
+func (f *fakeAssetYieldProvider) Info() model.ProviderInfo {
+	return model.ProviderInfo{Name: f.name, Type: "yield", Capabilities: []string{"yield.opportunities"}}
+}
+
+func (f *fakeAssetYieldProvider) YieldOpportunities(_ context.Context, req providers.YieldRequest) ([]model.YieldOpportunity, error) {
+	f.symbols = append(f.symbols, req.Asset.Symbol)
+	if err := f.errs[req.Asset.Symbol]; err != nil {
+		return nil, err
+	}
+	return append([]model.YieldOpportunity(nil), f.opportunities[req.Asset.Symbol]...), nil
+}
+
+func TestYieldOpportunitiesIncludeEquivalents(t *testing.T) {
+	run := func(t *testing.T, provider *fakeAssetYieldProvider, args ...string) ([]map[string]any, []string) {
+		t.Helper()
+		var stdout, stderr bytes.Buffer
+		state := &runtimeState{
+			runner:         &Runner{stdout: &stdout, stderr: &stderr, now: time.Now},
+			settings:       config.Settings{OutputMode: "json", Timeout: 2 * time.Second},
+			yieldProviders: map[string]providers.YieldProvider{"aave": provider},
+		}
+		root := &cobra.Command{Use: "defi", SilenceUsage: true, SilenceErrors: true}
+		root.AddCommand(state.newYieldCommand())
+		root.SetArgs(append([]string{"yield", "opportunities", "--chain", "base", "--asset", "USDC", "--providers", "aave"}, args...))
+		if err := root.Execute(); err != nil {
+			t.Fatalf("yield opportunities command failed: %v stderr=%s", err, stderr.String())
+		}
+		var env struct {
+			Data     []map[string]any `json:"data"`
+			Warnings []string         `json:"warnings"`
+		}
+		if err := json.Unmarshal(stdout.Bytes(), &env); err != nil {
+			t.Fatalf("failed parsing output json: %v output=%s", err, stdout.String())
+		}
+		return env.Data, env.Warnings
+	}
+	newProvider := func() *fakeAssetYieldProvider {
+		return &fakeAssetYieldProvider{
+			name: "aave",
+			opportunities: map[string][]model.YieldOpportunity{
+				"USDC":  {{OpportunityID: "usdc", Provider: "aave", APYTotal: 4}},
+				"USDBC": {{OpportunityID: "usdbc", Provider: "aave", APYTotal: 5}},
+			},
+		}
+	}
+
+	t.Run("off by default", func(t *testing.T) {
+		provider := newProvider()
+		data, _ := run(t, provider)
+		if len(data) != 1 || data[0]["opportunity_id"] != "usdc" || len(provider.symbols) != 1 {
+			t.Fatalf("expected only the requested asset to be queried, got data=%+v symbols=%v", data, provider.symbols)
+		}
+	})
+
+	t.Run("marks equivalent rows", func(t *testing.T) {
+		data, _ := run(t, newProvider(), "--include-equivalents")
+		if len(data) != 2 || data[0]["opportunity_id"] != "usdbc" {
+			t.Fatalf("expected the USDbC opportunity to be included, got %+v", data)
+		}
+		if data[0]["equivalent_of"] != "eip155:8453/erc20:0x833589fcd6edb6e08f4c7c32d4f71b54bda02913" || data[1]["equivalent_of"] != nil {
+			t.Fatalf("expected only the equivalent row to set equivalent_of, got %+v", data)
+		}
+	})
+
+	t.Run("warns on equivalent failure", func(t *testing.T) {
+		provider := newProvider()
+		provider.errs = map[string]error{"USDBC": fmt.Errorf("reserve not listed")}
+		data, warnings := run(t, provider, "--include-equivalents")
+		if len(data) != 1 || data[0]["opportunity_id"] != "usdc" {
+			t.Fatalf("expected the requested asset rows to survive, got %+v", data)
+		}
+		if len(warnings) != 1 || !strings.Contains(warnings[0], "failed for equivalent USDBC: reserve not listed") {
+			t.Fatalf("expected an equivalent failure warning, got %+v", warnings)
+		}
+	})
+}
+
+func TestYieldOpportunitiesFiltersByMinStability(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	fixedNow := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	dailySeries := func(values ...float64) []model.YieldHistorySeries {
+		points := make([]model.YieldHistoryPoint, 0, len(values))
+		for i, value := range values {
+			points = append(points, model.YieldHistoryPoint{Timestamp: fixedNow.AddDate(0, 0, i-len(values)).Format(time.RFC3339), Value: value})
+		}
+		return []model.YieldHistorySeries{{Metric: "apy_total", Interval: "day", Points: points}}
+	}
+	steady := &fakeYieldHistoryProvider{
+		name:          "aave",
+		opportunities: []model.YieldOpportunity{{OpportunityID: "steady", Provider: "aave", APYTotal: 5}},
+		series:        dailySeries(5, 5.1, 4.9, 5),
+	}
+	teaser := &fakeYieldHistoryProvider{
+		name:          "morpho",
+		opportunities: []model.YieldOpportunity{{OpportunityID: "teaser", Provider: "morpho", APYTotal: 20}},
+		series:        dailySeries(40, 30, 20),
+	}
+	state := &runtimeState{
+		runner: &Runner{
+			stdout: &stdout,
+			stderr: &stderr,
+			now:    func() time.Time { return fixedNow },
+		},
+		settings: config.Settings{
+			OutputMode:   "json",
+			ResultsOnly:  true,
+			Timeout:      2 * time.Second,
+			CacheEnabled: false,
+		},
+		yieldProviders: map[string]providers.YieldProvider{
+			"aave":   steady,
+			"morpho": teaser,
+		},
+	}
+
+	root := &cobra.Command{Use: "defi"}
+	root.SilenceUsage = true
+	root.SilenceErrors = true
+	root.SetOut(&stdout)
+	root.SetErr(&stderr)
+	root.AddCommand(state.newYieldCommand())
+	root.SetArgs([]string{
+		"yield", "opportunities",
+		"--chain", "1",
+		"--asset", "USDC",
+		"--providers", "aave,morpho",
+		"--min-stability", "0.8",
+	})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("yield opportunities command failed: %v stderr=%s", err, stderr.String())
 	}
+
 	var out []map[string]any
 	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
-		t.Fatalf("failed to parse output json: %v output=%s", err, stdout.String())
+		t.Fatalf("failed parsing output json: %v output=%s", err, stdout.String())
 	}
-	if len(out) == 0 {
-		t.Fatalf("expected providers output, got empty")
+	if len(out) != 1 || out[0]["opportunity_id"] != "steady" {
+		t.Fatalf("expected only the steady opportunity, got %+v", out)
+	}
+	volatility, ok := out[0]["apy_volatility"].(map[string]any)
+	if !ok || volatility["window_days"] != float64(30) || volatility["points"] != float64(4) {
+		t.Fatalf("expected apy_volatility in output, got %+v", out[0])
+	}
+	if teaser.historyCalls != 1 || teaser.lastHistoryReq.Interval != providers.YieldHistoryIntervalDay {
+		t.Fatalf("expected one daily history call, got calls=%d req=%+v", teaser.historyCalls, teaser.lastHistoryReq)
+	}
+	if got := teaser.lastHistoryReq.StartTime; !got.Equal(fixedNow.AddDate(0, 0, -30)) {
+		t.Fatalf("expected 30d window start, got %s", got)
+	}
+}
+
+func TestComputeAPYVolatilityPenalizesDrawdown(t *testing.T) {
+	if got := computeAPYVolatility([]model.YieldHistoryPoint{{Timestamp: "2026-01-01T00:00:00Z", Value: 5}}); got != nil {
+		t.Fatalf("expected nil for a single point, got %+v", got)
+	}
+	flat := computeAPYVolatility([]model.YieldHistoryPoint{
+		{Timestamp: "2026-01-01T00:00:00Z", Value: 4},
+		{Timestamp: "2026-01-02T00:00:00Z", Value: 4},
+	})
+	if flat == nil || flat.StabilityScore != 1 || flat.MaxDrawdownPct != 0 {
+		t.Fatalf("expected flat series to score 1, got %+v", flat)
+	}
+	// Out-of-order points are sorted before measuring the peak-to-trough drop.
+	collapse := computeAPYVolatility([]model.YieldHistoryPoint{
+		{Timestamp: "2026-01-03T00:00:00Z", Value: 10},
+		{Timestamp: "2026-01-01T00:00:00Z", Value: 20},
+		{Timestamp: "2026-01-02T00:00:00Z", Value: 20},
+	})
+	if collapse == nil || collapse.MaxDrawdownPct != 50 || collapse.StabilityScore != 0.5 {
+		t.Fatalf("expected 50%% drawdown and 0.5 score, got %+v", collapse)
+	}
+}
+
+func TestSwapQuoteBlockedByProtocolPolicy(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("DEFI_DENY_PROTOCOLS", "uniswap")
+
+	var stdout, stderr bytes.Buffer
+	r := NewRunnerWithWriters(&stdout, &stderr)
+	code := r.Run([]string{"swap", "quote", "--provider", "uniswap", "--chain", "1", "--from-asset", "USDC", "--to-asset", "DAI", "--amount", "1000000"})
+	if code != 16 {
+		t.Fatalf("expected exit 16, got %d stdout=%s stderr=%s", code, stdout.String(), stderr.String())
+	}
+	var env map[string]any
+	if err := json.Unmarshal(stderr.Bytes(), &env); err != nil {
+		t.Fatalf("failed parsing error envelope: %v stderr=%s", err, stderr.String())
+	}
+	errBody, _ := env["error"].(map[string]any)
+	details, _ := errBody["details"].(map[string]any)
+	if details["list"] != "deny" || details["rule"] != "uniswap" {
+		t.Fatalf("expected deny details, got %+v", errBody)
+	}
+}
+
+func TestYieldOpportunitiesRejectsInvalidExitLiquidityPct(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	fakeProvider := &fakeYieldHistoryProvider{name: "morpho"}
+	state := &runtimeState{
+		runner: &Runner{
+			stdout: &stdout,
+			stderr: &stderr,
+			now:    time.Now,
+		},
+		settings: config.Settings{
+			OutputMode:   "json",
+			Timeout:      2 * time.Second,
+			CacheEnabled: false,
+		},
+		yieldProviders: map[string]providers.YieldProvider{
+			"morpho": fakeProvider,
+		},
+	}
+
+	root := &cobra.Command{Use: "defi"}
+	root.SilenceUsage = true
+	root.SilenceErrors = true
+	root.SetOut(&stdout)
+	root.SetErr(&stderr)
+	root.AddCommand(state.newYieldCommand())
+	root.SetArgs([]string{
+		"yield", "opportunities",
+		"--chain", "1",
+		"--asset", "USDC",
+		"--min-exit-liquidity-pct", "150",
+	})
+	if err := root.Execute(); err == nil {
+		t.Fatalf("expected validation error, stderr=%s", stderr.String())
+	}
+	if fakeProvider.calls != 0 {
+		t.Fatalf("expected provider not to be called, got %d calls", fakeProvider.calls)
+	}
+}
+
+func TestParseChainAssetFilterAllowsUnknownSymbol(t *testing.T) {
+	chain, err := id.ParseChain("ethereum")
+	if err != nil {
+		t.Fatalf("parse chain: %v", err)
+	}
+	asset, err := parseChainAssetFilter(chain, "NOTAREALTOKEN")
+	if err != nil {
+		t.Fatalf("expected unknown symbol to be accepted, got err=%v", err)
+	}
+	if asset.Symbol != "NOTAREALTOKEN" {
+		t.Fatalf("expected NOTAREALTOKEN symbol, got %+v", asset)
+	}
+	if asset.AssetID != "" {
+		t.Fatalf("expected empty asset id for non-registry symbol, got %s", asset.AssetID)
+	}
+}
+
+func TestParseChainAssetFilterRejectsUnknownAddress(t *testing.T) {
+	chain, err := id.ParseChain("ethereum")
+	if err != nil {
+		t.Fatalf("parse chain: %v", err)
+	}
+	_, err = parseChainAssetFilter(chain, "0x0000000000000000000000000000000000000001")
+	if err == nil {
+		t.Fatal("expected error for address without known chain symbol")
+	}
+}
+
+func TestParseSwapInputsSplitsOnLastColon(t *testing.T) {
+	chain, err := id.ParseChain("ethereum")
+	if err != nil {
+		t.Fatalf("parse chain: %v", err)
+	}
+	inputs, err := parseSwapInputs("USDC:500, eip155:1/erc20:0x6b175474e89094c44da98b954eedeac495271d0f:1.5", chain)
+	if err != nil {
+		t.Fatalf("parseSwapInputs failed: %v", err)
+	}
+	if len(inputs) != 2 || inputs[0].AmountBaseUnits != "500000000" || inputs[1].Asset.Symbol != "DAI" || inputs[1].AmountBaseUnits != "1500000000000000000" {
+		t.Fatalf("unexpected inputs: %+v", inputs)
+	}
+	for _, raw := range []string{"", "USDC", "USDC:", ":5"} {
+		if _, err := parseSwapInputs(raw, chain); err == nil {
+			t.Fatalf("expected error for %q", raw)
+		}
+	}
+}
+
+func TestRunnerProvidersList(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	r := NewRunnerWithWriters(&stdout, &stderr)
+	code := r.Run([]string{"providers", "list", "--results-only"})
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d stderr=%s", code, stderr.String())
+	}
+	var out []map[string]any
+	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+		t.Fatalf("failed to parse output json: %v output=%s", err, stdout.String())
+	}
+	if len(out) == 0 {
+		t.Fatalf("expected providers output, got empty")
+	}
+	tempoInfo, ok := findProviderInfo(out, "tempo")
+	if !ok {
+		t.Fatalf("expected tempo provider in providers list, got %#v", out)
+	}
+	if requiresKey, ok := tempoInfo["requires_key"].(bool); !ok || requiresKey {
+		t.Fatalf("expected tempo requires_key=false, got %#v", tempoInfo["requires_key"])
+	}
+	fibrousInfo, ok := findProviderInfo(out, "fibrous")
+	if !ok {
+		t.Fatalf("expected fibrous provider in providers list, got %#v", out)
+	}
+	if requiresKey, ok := fibrousInfo["requires_key"].(bool); !ok || requiresKey {
+		t.Fatalf("expected fibrous requires_key=false, got %#v", fibrousInfo["requires_key"])
+	}
+	jupiterCount := 0
+	for _, item := range out {
+		name, _ := item["name"].(string)
+		if strings.EqualFold(name, "jupiter") {
+			jupiterCount++
+		}
+	}
+	if jupiterCount != 1 {
+		t.Fatalf("expected exactly one jupiter provider entry, got %d", jupiterCount)
+	}
+}
+
+type fakeKeyedSwapProvider struct {
+	fakeSwapProvider
+	keyErr error
+}
+
+func (f *fakeKeyedSwapProvider) VerifyKey(context.Context) error {
+	return f.keyErr
+}
+
+func TestProvidersVerifyReportsKeyStatus(t *testing.T) {
+	state := &runtimeState{
+		runner:   NewRunnerWithWriters(&bytes.Buffer{}, &bytes.Buffer{}),
+		settings: config.Settings{Timeout: time.Second},
+		swapProviders: map[string]providers.SwapProvider{
+			"odos":    &fakeSwapProvider{name: "odos"},
+			"1inch":   &fakeKeyedSwapProvider{fakeSwapProvider: fakeSwapProvider{name: "1inch"}, keyErr: clierr.New(clierr.CodeAuth, "provider authentication failed")},
+			"jupiter": &fakeKeyedSwapProvider{fakeSwapProvider: fakeSwapProvider{name: "jupiter"}, keyErr: providers.ErrNoAPIKey},
+			"uniswap": &fakeKeyedSwapProvider{fakeSwapProvider: fakeSwapProvider{name: "uniswap"}},
+			"bungee":  &fakeKeyedSwapProvider{fakeSwapProvider: fakeSwapProvider{name: "bungee"}, keyErr: clierr.New(clierr.CodeRateLimited, "provider rate limited request")},
+		},
+	}
+	verifiers, err := state.keyVerifiers("")
+	if err != nil {
+		t.Fatalf("keyVerifiers failed: %v", err)
+	}
+	var got []string
+	for _, row := range state.verifyProviderKeys(context.Background(), verifiers) {
+		got = append(got, row.Provider+"="+row.Status)
+	}
+	if want := "1inch=invalid,bungee=rate_limited,jupiter=missing,uniswap=valid"; strings.Join(got, ",") != want {
+		t.Fatalf("unexpected key statuses %v, want %s", got, want)
+	}
+	if verifiers, err := state.keyVerifiers("Uniswap"); err != nil || len(verifiers) != 1 {
+		t.Fatalf("expected --provider to select uniswap, got %d verifiers err %v", len(verifiers), err)
+	}
+	if _, err := state.keyVerifiers("odos"); err == nil {
+		t.Fatal("expected a provider without a key to be rejected")
+	}
+}
+
+func TestRunnerProvidersListFiltersByChain(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	r := NewRunnerWithWriters(&stdout, &stderr)
+	code := r.Run([]string{"providers", "list", "--chain", "base", "--results-only"})
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d stderr=%s", code, stderr.String())
+	}
+	var out []model.ProviderInfo
+	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+		t.Fatalf("failed to parse output json: %v output=%s", err, stdout.String())
+	}
+	byName := map[string]model.ProviderInfo{}
+	for _, info := range out {
+		byName[info.Name+"/"+info.Type] = info
+	}
+	for _, absent := range []string{"jupiter/swap", "kamino/lending+yield", "tempo/swap", "taikoswap/swap", "velodrome/yield"} {
+		if _, ok := byName[absent]; ok {
+			t.Fatalf("expected %s to be filtered out on base", absent)
+		}
+	}
+	cow, ok := byName["cowswap/swap"]
+	if !ok || len(cow.Support) != 3 || cow.Support[0].Chains[0] != "eip155:8453" || cow.Support[0].Features[0] != "exact-input" {
+		t.Fatalf("expected cowswap support narrowed to base, got %+v", cow)
+	}
+	uniswap := byName["uniswap/swap"]
+	if !slices.Contains(uniswap.Support[0].Features, "exact-output") {
+		t.Fatalf("expected uniswap exact-output support, got %+v", uniswap.Support)
+	}
+	if _, ok := byName["defillama/market+bridge-data"]; !ok {
+		t.Fatal("expected providers without chain metadata to be kept")
+	}
+	wormhole := byName["wormhole/bridge"]
+	stdout.Reset()
+	if code := r.Run([]string{"providers", "list", "--chain", "solana", "--results-only"}); code != 0 {
+		t.Fatalf("expected exit 0, got %d stderr=%s", code, stderr.String())
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+		t.Fatalf("failed to parse output json: %v", err)
+	}
+	for _, info := range out {
+		if info.Name == "wormhole" && (len(wormhole.Capabilities) != 3 || !slices.Equal(info.Capabilities, []string{"bridge.quote"})) {
+			t.Fatalf("expected wormhole to only quote from solana, got %+v", info)
+		}
+	}
+}
+
+func TestRunnerChainsList(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	r := NewRunnerWithWriters(&stdout, &stderr)
+	code := r.Run([]string{"chains", "list", "--results-only"})
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d stderr=%s", code, stderr.String())
+	}
+	var out []map[string]any
+	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+		t.Fatalf("failed to parse output json: %v output=%s", err, stdout.String())
+	}
+	if len(out) == 0 {
+		t.Fatal("expected at least one chain in output")
+	}
+
+	// Verify each entry has required fields.
+	for _, item := range out {
+		if _, ok := item["name"].(string); !ok {
+			t.Fatalf("missing name field: %+v", item)
+		}
+		if _, ok := item["slug"].(string); !ok {
+			t.Fatalf("missing slug field: %+v", item)
+		}
+		if _, ok := item["caip2"].(string); !ok {
+			t.Fatalf("missing caip2 field: %+v", item)
+		}
+		if _, ok := item["namespace"].(string); !ok {
+			t.Fatalf("missing namespace field: %+v", item)
+		}
+	}
+
+	// Verify Ethereum is present.
+	var ethFound bool
+	for _, item := range out {
+		if item["slug"] == "ethereum" {
+			ethFound = true
+			if item["caip2"] != "eip155:1" {
+				t.Fatalf("expected ethereum caip2 eip155:1, got %v", item["caip2"])
+			}
+			if item["namespace"] != "eip155" {
+				t.Fatalf("expected eip155 namespace, got %v", item["namespace"])
+			}
+		}
+	}
+	if !ethFound {
+		t.Fatal("expected ethereum in chains list output")
+	}
+}
+
+func TestRunnerChainsListBypassesCache(t *testing.T) {
+	if shouldOpenCache("chains list") {
+		t.Fatal("chains list should bypass cache initialization")
+	}
+}
+
+func TestRunnerErrorEnvelopeIgnoresResultsOnly(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	r := NewRunnerWithWriters(&stdout, &stderr)
+	code := r.Run([]string{"chains", "top", "--enable-commands", "yield opportunities", "--results-only"})
+	if code != 16 {
+		t.Fatalf("expected exit 16, got %d stderr=%s", code, stderr.String())
+	}
+	var env map[string]any
+	if err := json.Unmarshal(stderr.Bytes(), &env); err != nil {
+		t.Fatalf("failed to parse error envelope: %v output=%s", err, stderr.String())
+	}
+	if env["success"] != false {
+		t.Fatalf("expected success=false, got %v", env["success"])
+	}
+}
+
+func TestRunnerVersion(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	r := NewRunnerWithWriters(&stdout, &stderr)
+	code := r.Run([]string{"version"})
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d stderr=%s", code, stderr.String())
+	}
+	if got := stdout.String(); got != version.CLIVersion+"\n" {
+		t.Fatalf("unexpected version output: %q", got)
+	}
+}
+
+func TestRunnerVersionLong(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	r := NewRunnerWithWriters(&stdout, &stderr)
+	code := r.Run([]string{"version", "--long"})
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d stderr=%s", code, stderr.String())
+	}
+	out := stdout.String()
+	if !strings.Contains(out, version.CLIVersion) {
+		t.Fatalf("expected long version output to include version %q, got %q", version.CLIVersion, out)
+	}
+	if !strings.Contains(out, "commit:") {
+		t.Fatalf("expected long version output to include commit field, got %q", out)
+	}
+	if !strings.Contains(out, "built:") {
+		t.Fatalf("expected long version output to include build date field, got %q", out)
+	}
+}
+
+func TestRunnerVersionBypassesCacheOpen(t *testing.T) {
+	setUnopenableCacheEnv(t)
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	r := NewRunnerWithWriters(&stdout, &stderr)
+	code := r.Run([]string{"version"})
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d stderr=%s", code, stderr.String())
+	}
+	if got := strings.TrimSpace(stdout.String()); got != version.CLIVersion {
+		t.Fatalf("unexpected version output: %q", got)
+	}
+}
+
+func TestRunnerSchemaBypassesCacheOpen(t *testing.T) {
+	setUnopenableCacheEnv(t)
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	r := NewRunnerWithWriters(&stdout, &stderr)
+	code := r.Run([]string{"schema", "yield opportunities", "--results-only"})
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d stderr=%s", code, stderr.String())
+	}
+	var schema map[string]any
+	if err := json.Unmarshal(stdout.Bytes(), &schema); err != nil {
+		t.Fatalf("failed to parse schema json: %v output=%s", err, stdout.String())
+	}
+	if schema["path"] != "defi yield opportunities" {
+		t.Fatalf("unexpected schema path: %v", schema["path"])
+	}
+}
+
+func TestRunnerSchemaOutputModels(t *testing.T) {
+	setUnopenableCacheEnv(t)
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	r := NewRunnerWithWriters(&stdout, &stderr)
+	code := r.Run([]string{"schema", "--output-models", "--results-only"})
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d stderr=%s", code, stderr.String())
+	}
+	var doc struct {
+		Version string                    `json:"version"`
+		Ref     string                    `json:"$ref"`
+		Defs    map[string]map[string]any `json:"$defs"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &doc); err != nil {
+		t.Fatalf("failed to parse output models json: %v output=%s", err, stdout.String())
+	}
+	if doc.Version != model.EnvelopeVersion || doc.Ref != "#/$defs/Envelope" {
+		t.Fatalf("unexpected document header: version=%q ref=%q", doc.Version, doc.Ref)
+	}
+	for _, name := range []string{"Envelope", "SwapQuote", "LendMarket", "YieldOpportunity", "Action"} {
+		if _, ok := doc.Defs[name]; !ok {
+			t.Fatalf("expected %s definition, got %d defs", name, len(doc.Defs))
+		}
+	}
+
+	stdout.Reset()
+	stderr.Reset()
+	if code := r.Run([]string{"schema", "swap quote", "--output-models"}); code != 2 {
+		t.Fatalf("expected usage exit for command path with --output-models, got %d", code)
+	}
+}
+
+func TestRunnerProvidersListBypassesCacheOpen(t *testing.T) {
+	setUnopenableCacheEnv(t)
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	r := NewRunnerWithWriters(&stdout, &stderr)
+	code := r.Run([]string{"providers", "list", "--results-only"})
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d stderr=%s", code, stderr.String())
+	}
+	var out []map[string]any
+	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+		t.Fatalf("failed to parse providers output json: %v output=%s", err, stdout.String())
+	}
+	if len(out) == 0 {
+		t.Fatalf("expected providers output, got empty")
+	}
+	tempoInfo, ok := findProviderInfo(out, "tempo")
+	if !ok {
+		t.Fatalf("expected tempo provider in providers list, got %#v", out)
+	}
+	if requiresKey, ok := tempoInfo["requires_key"].(bool); !ok || requiresKey {
+		t.Fatalf("expected tempo requires_key=false, got %#v", tempoInfo["requires_key"])
+	}
+	fibrousInfo, ok := findProviderInfo(out, "fibrous")
+	if !ok {
+		t.Fatalf("expected fibrous provider in providers list, got %#v", out)
+	}
+	if requiresKey, ok := fibrousInfo["requires_key"].(bool); !ok || requiresKey {
+		t.Fatalf("expected fibrous requires_key=false, got %#v", fibrousInfo["requires_key"])
+	}
+}
+
+func TestRunnerAssetsResolveFallsBackWhenCacheUnavailable(t *testing.T) {
+	setUnopenableCacheEnv(t)
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	r := NewRunnerWithWriters(&stdout, &stderr)
+	code := r.Run([]string{"assets", "resolve", "--chain", "1", "--asset", "USDC", "--results-only"})
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d stderr=%s", code, stderr.String())
+	}
+	var out map[string]any
+	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+		t.Fatalf("failed to parse assets resolve output json: %v output=%s", err, stdout.String())
+	}
+	if out["asset_id"] == "" {
+		t.Fatalf("expected asset_id in output, got %+v", out)
+	}
+	if chainID, _ := out["chain_id"].(string); chainID != "eip155:1" {
+		t.Fatalf("expected chain_id eip155:1, got %q", chainID)
+	}
+}
+
+func TestRunnerAssetsResolveListsEquivalents(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	run := func(args ...string) map[string]any {
+		t.Helper()
+		var stdout, stderr bytes.Buffer
+		r := NewRunnerWithWriters(&stdout, &stderr)
+		if code := r.Run(append([]string{"assets", "resolve", "--chain", "base", "--asset", "USDC", "--results-only"}, args...)); code != 0 {
+			t.Fatalf("expected exit 0, got %d stderr=%s", code, stderr.String())
+		}
+		var out map[string]any
+		if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+			t.Fatalf("failed to parse assets resolve output json: %v output=%s", err, stdout.String())
+		}
+		return out
+	}
+
+	out := run()
+	equivalents, _ := out["equivalents"].([]any)
+	if len(equivalents) != 1 {
+		t.Fatalf("expected USDbC equivalent on base, got %+v", out["equivalents"])
+	}
+	if symbol := equivalents[0].(map[string]any)["symbol"]; symbol != "USDBC" {
+		t.Fatalf("unexpected equivalent symbol: %v", symbol)
+	}
+
+	if strict := run("--strict-asset"); strict["equivalents"] != nil {
+		t.Fatalf("expected no equivalents with --strict-asset, got %+v", strict["equivalents"])
+	}
+}
+
+func TestRunnerProtocolsCategories(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	state := &runtimeState{
+		runner: &Runner{
+			stdout: &stdout,
+			stderr: &stderr,
+			now:    time.Now,
+		},
+		settings: config.Settings{
+			OutputMode:   "json",
+			Timeout:      2 * time.Second,
+			CacheEnabled: false,
+		},
+		marketProvider: fakeMarketProvider{
+			categories: []model.ProtocolCategory{
+				{Name: "Lending", Protocols: 2, TVLUSD: 15000},
+			},
+		},
+	}
+	root := &cobra.Command{Use: "defi"}
+	root.SilenceUsage = true
+	root.SilenceErrors = true
+	root.SetOut(&stdout)
+	root.SetErr(&stderr)
+	root.AddCommand(state.newProtocolsCommand())
+	root.SetArgs([]string{"protocols", "categories"})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("expected protocols categories command success, err=%v stderr=%s", err, stderr.String())
+	}
+
+	var env map[string]any
+	if err := json.Unmarshal(stdout.Bytes(), &env); err != nil {
+		t.Fatalf("failed to parse output json: %v output=%s", err, stdout.String())
+	}
+	if env["success"] != true {
+		t.Fatalf("expected success=true, got %v", env["success"])
+	}
+	data, ok := env["data"].([]any)
+	if !ok {
+		t.Fatalf("expected data to be an array, got %T", env["data"])
+	}
+	if len(data) == 0 {
+		t.Fatalf("expected non-empty categories list")
+	}
+	first, ok := data[0].(map[string]any)
+	if !ok {
+		t.Fatalf("expected first item to be object, got %T", data[0])
+	}
+	if _, ok := first["name"]; !ok {
+		t.Fatalf("expected 'name' field in category, got %+v", first)
+	}
+	if _, ok := first["protocols"]; !ok {
+		t.Fatalf("expected 'protocols' field in category, got %+v", first)
+	}
+	if _, ok := first["tvl_usd"]; !ok {
+		t.Fatalf("expected 'tvl_usd' field in category, got %+v", first)
+	}
+}
+
+func TestRunnerProtocolsFees(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	state := &runtimeState{
+		runner: &Runner{
+			stdout: &stdout,
+			stderr: &stderr,
+			now:    time.Now,
+		},
+		settings: config.Settings{
+			OutputMode:   "json",
+			Timeout:      2 * time.Second,
+			CacheEnabled: false,
+		},
+		marketProvider: fakeMarketProvider{
+			protocolFees: []model.ProtocolFees{
+				{Rank: 1, Protocol: "Lido", Category: "Liquid Staking", Fees24hUSD: 8000000, Fees7dUSD: 55000000, Fees30dUSD: 200000000, Chains: 1},
+			},
+		},
+	}
+	root := &cobra.Command{Use: "defi"}
+	root.SilenceUsage = true
+	root.SilenceErrors = true
+	root.SetOut(&stdout)
+	root.SetErr(&stderr)
+	root.AddCommand(state.newProtocolsCommand())
+	root.SetArgs([]string{"protocols", "fees"})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("expected protocols fees command success, err=%v stderr=%s", err, stderr.String())
+	}
+
+	var env map[string]any
+	if err := json.Unmarshal(stdout.Bytes(), &env); err != nil {
+		t.Fatalf("failed to parse output json: %v output=%s", err, stdout.String())
+	}
+	if env["success"] != true {
+		t.Fatalf("expected success=true, got %v", env["success"])
+	}
+	data, ok := env["data"].([]any)
+	if !ok {
+		t.Fatalf("expected data to be an array, got %T", env["data"])
+	}
+	if len(data) == 0 {
+		t.Fatalf("expected non-empty fees list")
+	}
+	first, ok := data[0].(map[string]any)
+	if !ok {
+		t.Fatalf("expected first item to be object, got %T", data[0])
+	}
+	if _, ok := first["protocol"]; !ok {
+		t.Fatalf("expected 'protocol' field, got %+v", first)
+	}
+	if _, ok := first["fees_24h_usd"]; !ok {
+		t.Fatalf("expected 'fees_24h_usd' field, got %+v", first)
+	}
+}
+
+func TestRunnerProtocolsVolumePassesFilters(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	var gotReq providers.ProtocolMetricsRequest
+	state := &runtimeState{
+		runner: &Runner{
+			stdout: &stdout,
+			stderr: &stderr,
+			now:    time.Now,
+		},
+		settings: config.Settings{
+			OutputMode:   "json",
+			Timeout:      2 * time.Second,
+			CacheEnabled: false,
+		},
+		marketProvider: fakeMarketProvider{
+			protocolVolume: []model.DexVolume{
+				{Rank: 1, Protocol: "Uniswap V3", Volume24hUSD: 1500000000, Volume7dUSD: 9000000000, Chains: 12},
+			},
+			metricsReq: &gotReq,
+		},
+	}
+	root := &cobra.Command{Use: "defi"}
+	root.SilenceUsage = true
+	root.SilenceErrors = true
+	root.SetOut(&stdout)
+	root.SetErr(&stderr)
+	root.AddCommand(state.newProtocolsCommand())
+	root.SetArgs([]string{"protocols", "volume", "--chain", "Ethereum", "--protocol", "uniswap-v3", "--window", "7d", "--limit", "5"})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("expected protocols volume command success, err=%v stderr=%s", err, stderr.String())
+	}
+	want := providers.ProtocolMetricsRequest{Chain: "Ethereum", Protocol: "uniswap-v3", Window: providers.MetricsWindow7d, Limit: 5}
+	if gotReq != want {
+		t.Fatalf("unexpected metrics request: %+v", gotReq)
+	}
+
+	stdout.Reset()
+	root.SetArgs([]string{"protocols", "volume", "--window", "90d"})
+	err := root.Execute()
+	if code := clierr.ExitCode(err); code != int(clierr.CodeUsage) {
+		t.Fatalf("expected usage error for invalid window, got %v", err)
+	}
+}
+
+func TestRunnerProtocolsRevenue(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	state := &runtimeState{
+		runner: &Runner{
+			stdout: &stdout,
+			stderr: &stderr,
+			now:    time.Now,
+		},
+		settings: config.Settings{
+			OutputMode:   "json",
+			Timeout:      2 * time.Second,
+			CacheEnabled: false,
+		},
+		marketProvider: fakeMarketProvider{
+			protocolRevenue: []model.ProtocolRevenue{
+				{Rank: 1, Protocol: "Lido", Category: "Liquid Staking", Revenue24hUSD: 5000000, Revenue7dUSD: 35000000, Revenue30dUSD: 130000000, Chains: 1},
+			},
+		},
+	}
+	root := &cobra.Command{Use: "defi"}
+	root.SilenceUsage = true
+	root.SilenceErrors = true
+	root.SetOut(&stdout)
+	root.SetErr(&stderr)
+	root.AddCommand(state.newProtocolsCommand())
+	root.SetArgs([]string{"protocols", "revenue"})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("expected protocols revenue command success, err=%v stderr=%s", err, stderr.String())
+	}
+
+	var env map[string]any
+	if err := json.Unmarshal(stdout.Bytes(), &env); err != nil {
+		t.Fatalf("failed to parse output json: %v output=%s", err, stdout.String())
+	}
+	if env["success"] != true {
+		t.Fatalf("expected success=true, got %v", env["success"])
+	}
+	data, ok := env["data"].([]any)
+	if !ok {
+		t.Fatalf("expected data to be an array, got %T", env["data"])
+	}
+	if len(data) == 0 {
+		t.Fatalf("expected non-empty revenue list")
+	}
+	first, ok := data[0].(map[string]any)
+	if !ok {
+		t.Fatalf("expected first item to be object, got %T", data[0])
+	}
+	if _, ok := first["protocol"]; !ok {
+		t.Fatalf("expected 'protocol' field, got %+v", first)
+	}
+	if _, ok := first["revenue_24h_usd"]; !ok {
+		t.Fatalf("expected 'revenue_24h_usd' field, got %+v", first)
+	}
+}
+
+func TestRunnerChainsAssets(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	state := &runtimeState{
+		runner: &Runner{
+			stdout: &stdout,
+			stderr: &stderr,
+			now:    time.Now,
+		},
+		settings: config.Settings{
+			OutputMode:   "json",
+			Timeout:      2 * time.Second,
+			CacheEnabled: false,
+		},
+		marketProvider: fakeMarketProvider{
+			chainAssets: []model.ChainAssetTVL{
+				{
+					Rank:    1,
+					Chain:   "Ethereum",
+					ChainID: "eip155:1",
+					Asset:   "USDC",
+					AssetID: "eip155:1/erc20:0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48",
+					TVLUSD:  12345.67,
+				},
+			},
+		},
+	}
+	root := &cobra.Command{Use: "defi"}
+	root.SilenceUsage = true
+	root.SilenceErrors = true
+	root.SetOut(&stdout)
+	root.SetErr(&stderr)
+	root.AddCommand(state.newChainsCommand())
+	root.SetArgs([]string{"chains", "assets", "--chain", "1", "--asset", "USDC"})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("expected chains assets command success, err=%v stderr=%s", err, stderr.String())
+	}
+
+	var env map[string]any
+	if err := json.Unmarshal(stdout.Bytes(), &env); err != nil {
+		t.Fatalf("failed to parse output json: %v output=%s", err, stdout.String())
+	}
+	if env["success"] != true {
+		t.Fatalf("expected success=true, got %v", env["success"])
+	}
+	data, ok := env["data"].([]any)
+	if !ok {
+		t.Fatalf("expected data to be an array, got %T", env["data"])
+	}
+	if len(data) != 1 {
+		t.Fatalf("expected one chain asset item, got %d", len(data))
+	}
+	first, ok := data[0].(map[string]any)
+	if !ok {
+		t.Fatalf("expected first item to be object, got %T", data[0])
+	}
+	if _, ok := first["asset"]; !ok {
+		t.Fatalf("expected 'asset' field in output, got %+v", first)
+	}
+	if _, ok := first["asset_id"]; !ok {
+		t.Fatalf("expected 'asset_id' field in output, got %+v", first)
+	}
+	if _, ok := first["tvl_usd"]; !ok {
+		t.Fatalf("expected 'tvl_usd' field in output, got %+v", first)
+	}
+}
+
+func TestRunnerChainsAssetsAllowsUnknownSymbolFilter(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	state := &runtimeState{
+		runner: &Runner{
+			stdout: &stdout,
+			stderr: &stderr,
+			now:    time.Now,
+		},
+		settings: config.Settings{
+			OutputMode:   "json",
+			Timeout:      2 * time.Second,
+			CacheEnabled: false,
+		},
+		marketProvider: fakeMarketProvider{
+			expectedAssetSymbol: "UNI",
+			chainAssets: []model.ChainAssetTVL{
+				{
+					Rank:    1,
+					Chain:   "Ethereum",
+					ChainID: "eip155:1",
+					Asset:   "UNI",
+					AssetID: "",
+					TVLUSD:  456.78,
+				},
+			},
+		},
+	}
+	root := &cobra.Command{Use: "defi"}
+	root.SilenceUsage = true
+	root.SilenceErrors = true
+	root.SetOut(&stdout)
+	root.SetErr(&stderr)
+	root.AddCommand(state.newChainsCommand())
+	root.SetArgs([]string{"chains", "assets", "--chain", "1", "--asset", "UNI"})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("expected chains assets command success for unknown symbol, err=%v stderr=%s", err, stderr.String())
+	}
+
+	var env map[string]any
+	if err := json.Unmarshal(stdout.Bytes(), &env); err != nil {
+		t.Fatalf("failed to parse output json: %v output=%s", err, stdout.String())
+	}
+	if env["success"] != true {
+		t.Fatalf("expected success=true, got %v", env["success"])
+	}
+}
+
+func TestRunnerLendPositionsCallsProvider(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	aaveProvider := &fakeLendingProvider{
+		name: "aave",
+		positions: []model.LendPosition{
+			{
+				Provider:       "aave",
+				ChainID:        "eip155:1",
+				AccountAddress: "0x000000000000000000000000000000000000dead",
+				PositionType:   "collateral",
+				AssetID:        "eip155:1/erc20:0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48",
+			},
+		},
+	}
+	state := &runtimeState{
+		runner: &Runner{
+			stdout: &stdout,
+			stderr: &stderr,
+			now:    time.Now,
+		},
+		settings: config.Settings{
+			OutputMode:   "json",
+			Timeout:      2 * time.Second,
+			CacheEnabled: false,
+		},
+		lendingProviders: map[string]providers.LendingProvider{
+			"aave": aaveProvider,
+		},
+	}
+
+	root := &cobra.Command{Use: "defi"}
+	root.SilenceUsage = true
+	root.SilenceErrors = true
+	root.SetOut(&stdout)
+	root.SetErr(&stderr)
+	root.AddCommand(state.newLendCommand())
+	root.SetArgs([]string{
+		"lend", "positions",
+		"--provider", "aave",
+		"--chain", "1",
+		"--address", "0x000000000000000000000000000000000000dEaD",
+		"--asset", "USDC",
+		"--type", "collateral",
+		"--limit", "5",
+	})
+
+	if err := root.Execute(); err != nil {
+		t.Fatalf("lend positions command failed: %v stderr=%s", err, stderr.String())
 	}
-	tempoInfo, ok := findProviderInfo(out, "tempo")
-	if !ok {
-		t.Fatalf("expected tempo provider in providers list, got %#v", out)
+	if aaveProvider.calls != 1 {
+		t.Fatalf("expected provider call once, got %d", aaveProvider.calls)
 	}
-	if requiresKey, ok := tempoInfo["requires_key"].(bool); !ok || requiresKey {
-		t.Fatalf("expected tempo requires_key=false, got %#v", tempoInfo["requires_key"])
+	if aaveProvider.lastReq.PositionType != providers.LendPositionTypeCollateral {
+		t.Fatalf("expected collateral request type, got %s", aaveProvider.lastReq.PositionType)
 	}
-	fibrousInfo, ok := findProviderInfo(out, "fibrous")
-	if !ok {
-		t.Fatalf("expected fibrous provider in providers list, got %#v", out)
+	if !strings.EqualFold(aaveProvider.lastReq.Account, "0x000000000000000000000000000000000000dead") {
+		t.Fatalf("unexpected account passed to provider: %s", aaveProvider.lastReq.Account)
 	}
-	if requiresKey, ok := fibrousInfo["requires_key"].(bool); !ok || requiresKey {
-		t.Fatalf("expected fibrous requires_key=false, got %#v", fibrousInfo["requires_key"])
+	if !strings.EqualFold(aaveProvider.lastReq.Asset.Symbol, "USDC") {
+		t.Fatalf("expected USDC asset filter, got %+v", aaveProvider.lastReq.Asset)
 	}
-	jupiterCount := 0
-	for _, item := range out {
-		name, _ := item["name"].(string)
-		if strings.EqualFold(name, "jupiter") {
-			jupiterCount++
-		}
+
+	var env map[string]any
+	if err := json.Unmarshal(stdout.Bytes(), &env); err != nil {
+		t.Fatalf("failed to parse output json: %v output=%s", err, stdout.String())
 	}
-	if jupiterCount != 1 {
-		t.Fatalf("expected exactly one jupiter provider entry, got %d", jupiterCount)
+	if env["success"] != true {
+		t.Fatalf("expected success=true, got %v", env["success"])
 	}
 }
 
-func TestRunnerChainsList(t *testing.T) {
+func TestRunnerVerifyAPYFlagsUnitMismatch(t *testing.T) {
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
-	r := NewRunnerWithWriters(&stdout, &stderr)
-	code := r.Run([]string{"chains", "list", "--results-only"})
-	if code != 0 {
-		t.Fatalf("expected exit 0, got %d stderr=%s", code, stderr.String())
-	}
-	var out []map[string]any
-	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
-		t.Fatalf("failed to parse output json: %v output=%s", err, stdout.String())
-	}
-	if len(out) == 0 {
-		t.Fatal("expected at least one chain in output")
+	state := &runtimeState{
+		runner: &Runner{
+			stdout: &stdout,
+			stderr: &stderr,
+			now:    time.Now,
+		},
+		settings: config.Settings{
+			OutputMode:   "json",
+			Timeout:      2 * time.Second,
+			CacheEnabled: false,
+		},
+		lendingProviders: map[string]providers.LendingProvider{
+			"aave":   &fakeLendingProvider{name: "aave", rates: []model.LendRate{{Provider: "aave", SupplyAPY: 4.5, BorrowAPY: 6.1}}},
+			"morpho": &fakeLendingProvider{name: "morpho", rates: []model.LendRate{{Provider: "morpho", SupplyAPY: 0.045, BorrowAPY: 5.8}}},
+		},
 	}
 
-	// Verify each entry has required fields.
-	for _, item := range out {
-		if _, ok := item["name"].(string); !ok {
-			t.Fatalf("missing name field: %+v", item)
-		}
-		if _, ok := item["slug"].(string); !ok {
-			t.Fatalf("missing slug field: %+v", item)
-		}
-		if _, ok := item["caip2"].(string); !ok {
-			t.Fatalf("missing caip2 field: %+v", item)
-		}
-		if _, ok := item["namespace"].(string); !ok {
-			t.Fatalf("missing namespace field: %+v", item)
-		}
-	}
+	root := &cobra.Command{Use: "defi"}
+	root.SilenceUsage = true
+	root.SilenceErrors = true
+	root.SetOut(&stdout)
+	root.SetErr(&stderr)
+	root.AddCommand(state.newVerifyCommand())
+	root.SetArgs([]string{"verify", "apy", "--chain", "1", "--asset", "USDC", "--providers", "aave,morpho"})
 
-	// Verify Ethereum is present.
-	var ethFound bool
-	for _, item := range out {
-		if item["slug"] == "ethereum" {
-			ethFound = true
-			if item["caip2"] != "eip155:1" {
-				t.Fatalf("expected ethereum caip2 eip155:1, got %v", item["caip2"])
-			}
-			if item["namespace"] != "eip155" {
-				t.Fatalf("expected eip155 namespace, got %v", item["namespace"])
-			}
-		}
+	if err := root.Execute(); err != nil {
+		t.Fatalf("verify apy command failed: %v stderr=%s", err, stderr.String())
 	}
-	if !ethFound {
-		t.Fatal("expected ethereum in chains list output")
+	var env struct {
+		Data     model.APYConsistencyReport `json:"data"`
+		Warnings []string                   `json:"warnings"`
 	}
-}
-
-func TestRunnerChainsListBypassesCache(t *testing.T) {
-	if shouldOpenCache("chains list") {
-		t.Fatal("chains list should bypass cache initialization")
+	if err := json.Unmarshal(stdout.Bytes(), &env); err != nil {
+		t.Fatalf("failed to parse output json: %v output=%s", err, stdout.String())
 	}
-}
-
-func TestRunnerErrorEnvelopeIgnoresResultsOnly(t *testing.T) {
-	var stdout bytes.Buffer
-	var stderr bytes.Buffer
-	r := NewRunnerWithWriters(&stdout, &stderr)
-	code := r.Run([]string{"chains", "top", "--enable-commands", "yield opportunities", "--results-only"})
-	if code != 16 {
-		t.Fatalf("expected exit 16, got %d stderr=%s", code, stderr.String())
+	if env.Data.Consistent {
+		t.Fatalf("expected inconsistent report, got %+v", env.Data)
 	}
-	var env map[string]any
-	if err := json.Unmarshal(stderr.Bytes(), &env); err != nil {
-		t.Fatalf("failed to parse error envelope: %v output=%s", err, stderr.String())
+	if len(env.Data.Discrepancies) != 1 || env.Data.Discrepancies[0].Metric != "supply_apy" {
+		t.Fatalf("expected one supply_apy discrepancy, got %+v", env.Data.Discrepancies)
 	}
-	if env["success"] != false {
-		t.Fatalf("expected success=false, got %v", env["success"])
+	if len(env.Warnings) != 1 || !strings.Contains(env.Warnings[0], "supply_apy") {
+		t.Fatalf("expected discrepancy warning, got %v", env.Warnings)
 	}
 }
 
-func TestRunnerVersion(t *testing.T) {
-	var stdout bytes.Buffer
-	var stderr bytes.Buffer
-	r := NewRunnerWithWriters(&stdout, &stderr)
-	code := r.Run([]string{"version"})
-	if code != 0 {
-		t.Fatalf("expected exit 0, got %d stderr=%s", code, stderr.String())
+func TestQuoteSubjectNormalizesActionAndQuote(t *testing.T) {
+	action := execution.Action{
+		ActionID:    "act_1",
+		IntentType:  "swap",
+		Provider:    "paraswap",
+		ChainID:     "eip155:1",
+		InputAmount: "1000000000000000000",
+		Metadata: map[string]any{
+			"token_in":      "0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2",
+			"token_out":     "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48",
+			"quoted_amount": "3000000000",
+		},
 	}
-	if got := stdout.String(); got != version.CLIVersion+"\n" {
-		t.Fatalf("unexpected version output: %q", got)
+	subject, err := quoteSubjectFromAction(action)
+	if err != nil {
+		t.Fatalf("quoteSubjectFromAction failed: %v", err)
+	}
+	if subject.in.Symbol != "WETH" || subject.out.Decimals != 6 || subject.quotedOut.String() != "3000000000" || subject.actionID != "act_1" {
+		t.Fatalf("unexpected subject: %+v", subject)
 	}
-}
 
-func TestRunnerVersionLong(t *testing.T) {
-	var stdout bytes.Buffer
-	var stderr bytes.Buffer
-	r := NewRunnerWithWriters(&stdout, &stderr)
-	code := r.Run([]string{"version", "--long"})
-	if code != 0 {
-		t.Fatalf("expected exit 0, got %d stderr=%s", code, stderr.String())
+	delete(action.Metadata, "quoted_amount")
+	if _, err := quoteSubjectFromAction(action); err == nil {
+		t.Fatal("expected error for action without quoted_amount")
 	}
-	out := stdout.String()
-	if !strings.Contains(out, version.CLIVersion) {
-		t.Fatalf("expected long version output to include version %q, got %q", version.CLIVersion, out)
+
+	quote := model.SwapQuote{
+		Provider:     "odos",
+		ChainID:      "eip155:1",
+		FromAssetID:  subject.in.AssetID,
+		ToAssetID:    subject.out.AssetID,
+		TradeType:    "exact-input",
+		InputAmount:  model.AmountInfo{AmountBaseUnits: "1000000000000000000"},
+		EstimatedOut: model.AmountInfo{AmountBaseUnits: "2990000000"},
 	}
-	if !strings.Contains(out, "commit:") {
-		t.Fatalf("expected long version output to include commit field, got %q", out)
+	if _, err := quoteSubjectFromSwapQuote(quote); err != nil {
+		t.Fatalf("quoteSubjectFromSwapQuote failed: %v", err)
 	}
-	if !strings.Contains(out, "built:") {
-		t.Fatalf("expected long version output to include build date field, got %q", out)
+	quote.InputLegs = []model.SwapInputLeg{{AssetID: subject.in.AssetID}, {AssetID: subject.out.AssetID}}
+	if _, err := quoteSubjectFromSwapQuote(quote); err == nil {
+		t.Fatal("expected multi-input quote to be rejected")
 	}
 }
 
-func TestRunnerVersionBypassesCacheOpen(t *testing.T) {
-	setUnopenableCacheEnv(t)
-
+func TestRunnerStakeRatesAppliesProtocolPolicy(t *testing.T) {
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
-	r := NewRunnerWithWriters(&stdout, &stderr)
-	code := r.Run([]string{"version"})
-	if code != 0 {
-		t.Fatalf("expected exit 0, got %d stderr=%s", code, stderr.String())
-	}
-	if got := strings.TrimSpace(stdout.String()); got != version.CLIVersion {
-		t.Fatalf("unexpected version output: %q", got)
+	state := &runtimeState{
+		runner: &Runner{
+			stdout: &stdout,
+			stderr: &stderr,
+			now:    time.Now,
+		},
+		settings: config.Settings{
+			OutputMode:    "json",
+			Timeout:       2 * time.Second,
+			CacheEnabled:  false,
+			DenyProtocols: []string{"etherfi"},
+		},
+		stakingProvider: &fakeStakingProvider{rates: []model.StakeRate{
+			{Provider: "staking", Protocol: "lido", Symbol: "stETH", APR: 2.9},
+			{Provider: "staking", Protocol: "etherfi", Symbol: "eETH", APR: 2.7},
+			{Provider: "staking", Protocol: "rocketpool", Symbol: "rETH", APR: 2.6},
+		}},
 	}
-}
 
-func TestRunnerSchemaBypassesCacheOpen(t *testing.T) {
-	setUnopenableCacheEnv(t)
+	root := &cobra.Command{Use: "defi"}
+	root.SilenceUsage = true
+	root.SilenceErrors = true
+	root.SetOut(&stdout)
+	root.SetErr(&stderr)
+	root.AddCommand(state.newStakeCommand())
+	root.SetArgs([]string{"stake", "rates"})
 
-	var stdout bytes.Buffer
-	var stderr bytes.Buffer
-	r := NewRunnerWithWriters(&stdout, &stderr)
-	code := r.Run([]string{"schema", "yield opportunities", "--results-only"})
-	if code != 0 {
-		t.Fatalf("expected exit 0, got %d stderr=%s", code, stderr.String())
+	if err := root.Execute(); err != nil {
+		t.Fatalf("stake rates command failed: %v stderr=%s", err, stderr.String())
 	}
-	var schema map[string]any
-	if err := json.Unmarshal(stdout.Bytes(), &schema); err != nil {
-		t.Fatalf("failed to parse schema json: %v output=%s", err, stdout.String())
+	var env struct {
+		Data     []model.StakeRate `json:"data"`
+		Warnings []string          `json:"warnings"`
 	}
-	if schema["path"] != "defi yield opportunities" {
-		t.Fatalf("unexpected schema path: %v", schema["path"])
+	if err := json.Unmarshal(stdout.Bytes(), &env); err != nil {
+		t.Fatalf("failed to parse output json: %v output=%s", err, stdout.String())
+	}
+	if len(env.Data) != 2 || env.Data[0].Protocol != "lido" || env.Data[1].Protocol != "rocketpool" {
+		t.Fatalf("expected lido and rocketpool rates, got %+v", env.Data)
+	}
+	if len(env.Warnings) != 1 {
+		t.Fatalf("expected protocol policy warning, got %v", env.Warnings)
 	}
 }
 
-func TestRunnerProvidersListBypassesCacheOpen(t *testing.T) {
-	setUnopenableCacheEnv(t)
-
+func TestRunnerPerpsRatesRanksAcrossProvidersAndReportsFailures(t *testing.T) {
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
-	r := NewRunnerWithWriters(&stdout, &stderr)
-	code := r.Run([]string{"providers", "list", "--results-only"})
-	if code != 0 {
-		t.Fatalf("expected exit 0, got %d stderr=%s", code, stderr.String())
-	}
-	var out []map[string]any
-	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
-		t.Fatalf("failed to parse providers output json: %v output=%s", err, stdout.String())
+	state := &runtimeState{
+		runner: &Runner{
+			stdout: &stdout,
+			stderr: &stderr,
+			now:    time.Now,
+		},
+		settings: config.Settings{
+			OutputMode:   "json",
+			Timeout:      2 * time.Second,
+			CacheEnabled: false,
+		},
+		perpsProviders: map[string]providers.PerpsProvider{
+			"hyperliquid": &fakePerpsProvider{name: "hyperliquid", markets: []model.PerpMarket{
+				{Provider: "hyperliquid", Market: "BTC-USD", BaseSymbol: "BTC", FundingAPR: 10.95},
+				{Provider: "hyperliquid", Market: "SOL-USD", BaseSymbol: "SOL", FundingAPR: 40},
+			}},
+			"dydx": &fakePerpsProvider{name: "dydx", markets: []model.PerpMarket{
+				{Provider: "dydx", Market: "BTC-USD", BaseSymbol: "BTC", FundingAPR: 21.9},
+			}},
+			"broken": &fakePerpsProvider{name: "broken", err: clierr.New(clierr.CodeUnavailable, "down")},
+		},
 	}
-	if len(out) == 0 {
-		t.Fatalf("expected providers output, got empty")
+
+	root := &cobra.Command{Use: "defi"}
+	root.SilenceUsage = true
+	root.SilenceErrors = true
+	root.SetOut(&stdout)
+	root.SetErr(&stderr)
+	root.AddCommand(state.newPerpsCommand())
+	root.SetArgs([]string{"perps", "rates", "--symbols", "btc"})
+
+	if err := root.Execute(); err != nil {
+		t.Fatalf("perps rates command failed: %v stderr=%s", err, stderr.String())
 	}
-	tempoInfo, ok := findProviderInfo(out, "tempo")
-	if !ok {
-		t.Fatalf("expected tempo provider in providers list, got %#v", out)
+	var env struct {
+		Data     []model.PerpFundingRate `json:"data"`
+		Warnings []string                `json:"warnings"`
+		Meta     model.EnvelopeMeta      `json:"meta"`
 	}
-	if requiresKey, ok := tempoInfo["requires_key"].(bool); !ok || requiresKey {
-		t.Fatalf("expected tempo requires_key=false, got %#v", tempoInfo["requires_key"])
+	if err := json.Unmarshal(stdout.Bytes(), &env); err != nil {
+		t.Fatalf("failed to parse output json: %v output=%s", err, stdout.String())
 	}
-	fibrousInfo, ok := findProviderInfo(out, "fibrous")
-	if !ok {
-		t.Fatalf("expected fibrous provider in providers list, got %#v", out)
+	if len(env.Data) != 2 || env.Data[0].Provider != "dydx" || env.Data[1].Provider != "hyperliquid" {
+		t.Fatalf("expected BTC rates ranked dydx then hyperliquid, got %+v", env.Data)
 	}
-	if requiresKey, ok := fibrousInfo["requires_key"].(bool); !ok || requiresKey {
-		t.Fatalf("expected fibrous requires_key=false, got %#v", fibrousInfo["requires_key"])
+	if !env.Meta.Partial || len(env.Warnings) != 1 || !strings.Contains(env.Warnings[0], "provider broken failed") {
+		t.Fatalf("expected partial result with provider warning, got partial=%v warnings=%v", env.Meta.Partial, env.Warnings)
 	}
 }
 
-func TestRunnerAssetsResolveFallsBackWhenCacheUnavailable(t *testing.T) {
-	setUnopenableCacheEnv(t)
-
-	var stdout bytes.Buffer
-	var stderr bytes.Buffer
-	r := NewRunnerWithWriters(&stdout, &stderr)
-	code := r.Run([]string{"assets", "resolve", "--chain", "1", "--asset", "USDC", "--results-only"})
-	if code != 0 {
-		t.Fatalf("expected exit 0, got %d stderr=%s", code, stderr.String())
-	}
-	var out map[string]any
-	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
-		t.Fatalf("failed to parse assets resolve output json: %v output=%s", err, stdout.String())
-	}
-	if out["asset_id"] == "" {
-		t.Fatalf("expected asset_id in output, got %+v", out)
-	}
-	if chainID, _ := out["chain_id"].(string); chainID != "eip155:1" {
-		t.Fatalf("expected chain_id eip155:1, got %q", chainID)
+func TestRunnerPerpsRejectsUnknownProvider(t *testing.T) {
+	state := &runtimeState{perpsProviders: map[string]providers.PerpsProvider{"dydx": &fakePerpsProvider{name: "dydx"}}}
+	if _, err := state.selectPerpsProviders([]string{"gmx"}); err == nil {
+		t.Fatal("expected unknown perps provider to be rejected")
 	}
 }
 
-func TestRunnerProtocolsCategories(t *testing.T) {
+func TestRunnerOptionsIVBuildsSurfaceFromChain(t *testing.T) {
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
 	state := &runtimeState{
@@ -616,53 +1833,62 @@ func TestRunnerProtocolsCategories(t *testing.T) {
 			Timeout:      2 * time.Second,
 			CacheEnabled: false,
 		},
-		marketProvider: fakeMarketProvider{
-			categories: []model.ProtocolCategory{
-				{Name: "Lending", Protocols: 2, TVLUSD: 15000},
-			},
+		optionsProviders: map[string]providers.OptionsProvider{
+			"aevo": &fakeOptionsProvider{name: "aevo", contracts: []model.OptionContract{
+				{Provider: "aevo", Underlying: "ETH", OptionType: "put", Strike: 3000, Expiry: "2026-01-30T08:00:00Z", ForwardPrice: 3100, IndexPrice: 3050, MarkIV: 60},
+				{Provider: "aevo", Underlying: "ETH", OptionType: "call", Strike: 3000, Expiry: "2026-01-30T08:00:00Z", ForwardPrice: 3100, IndexPrice: 3050, MarkIV: 56},
+				{Provider: "aevo", Underlying: "ETH", OptionType: "call", Strike: 3200, Expiry: "2026-01-30T08:00:00Z", ForwardPrice: 3100, IndexPrice: 3050, MarkIV: 52},
+				{Provider: "aevo", Underlying: "ETH", OptionType: "call", Strike: 3000, Expiry: "2026-01-02T08:00:00Z", ForwardPrice: 3055, IndexPrice: 3050, MarkIV: 45},
+			}},
 		},
 	}
+
 	root := &cobra.Command{Use: "defi"}
 	root.SilenceUsage = true
 	root.SilenceErrors = true
 	root.SetOut(&stdout)
 	root.SetErr(&stderr)
-	root.AddCommand(state.newProtocolsCommand())
-	root.SetArgs([]string{"protocols", "categories"})
+	root.AddCommand(state.newOptionsCommand())
+	root.SetArgs([]string{"options", "iv", "--underlying", "eth", "--expiry", "2026-01-30"})
+
 	if err := root.Execute(); err != nil {
-		t.Fatalf("expected protocols categories command success, err=%v stderr=%s", err, stderr.String())
+		t.Fatalf("options iv command failed: %v stderr=%s", err, stderr.String())
+	}
+	var env struct {
+		Data []model.OptionIVSurface `json:"data"`
 	}
-
-	var env map[string]any
 	if err := json.Unmarshal(stdout.Bytes(), &env); err != nil {
 		t.Fatalf("failed to parse output json: %v output=%s", err, stdout.String())
 	}
-	if env["success"] != true {
-		t.Fatalf("expected success=true, got %v", env["success"])
-	}
-	data, ok := env["data"].([]any)
-	if !ok {
-		t.Fatalf("expected data to be an array, got %T", env["data"])
-	}
-	if len(data) == 0 {
-		t.Fatalf("expected non-empty categories list")
+	if len(env.Data) != 1 || env.Data[0].IndexPrice != 3050 || len(env.Data[0].Expiries) != 1 {
+		t.Fatalf("expected one surface with the filtered expiry, got %+v", env.Data)
 	}
-	first, ok := data[0].(map[string]any)
-	if !ok {
-		t.Fatalf("expected first item to be object, got %T", data[0])
+	smile := env.Data[0].Expiries[0]
+	if smile.ATMStrike != 3000 || smile.ATMIV != 58 || len(smile.Points) != 2 {
+		t.Fatalf("expected ATM at 3000 averaging call/put IV, got %+v", smile)
 	}
-	if _, ok := first["name"]; !ok {
-		t.Fatalf("expected 'name' field in category, got %+v", first)
+	if smile.Points[0].CallIV != 56 || smile.Points[0].PutIV != 60 || smile.Points[1].PutIV != 0 {
+		t.Fatalf("unexpected smile points: %+v", smile.Points)
 	}
-	if _, ok := first["protocols"]; !ok {
-		t.Fatalf("expected 'protocols' field in category, got %+v", first)
+}
+
+func TestRunnerOptionsChainsRejectsInvalidType(t *testing.T) {
+	state := &runtimeState{optionsProviders: map[string]providers.OptionsProvider{"aevo": &fakeOptionsProvider{name: "aevo"}}}
+	root := &cobra.Command{Use: "defi"}
+	root.SilenceUsage = true
+	root.SilenceErrors = true
+	root.AddCommand(state.newOptionsCommand())
+	root.SetArgs([]string{"options", "chains", "--underlying", "ETH", "--type", "straddle"})
+	err := root.Execute()
+	if err == nil {
+		t.Fatal("expected invalid --type to fail")
 	}
-	if _, ok := first["tvl_usd"]; !ok {
-		t.Fatalf("expected 'tvl_usd' field in category, got %+v", first)
+	if code := clierr.ExitCode(err); code != int(clierr.CodeUsage) {
+		t.Fatalf("expected usage exit code, got %d (%v)", code, err)
 	}
 }
 
-func TestRunnerProtocolsFees(t *testing.T) {
+func TestYieldILSimulateUsesPairVolatility(t *testing.T) {
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
 	state := &runtimeState{
@@ -673,55 +1899,59 @@ func TestRunnerProtocolsFees(t *testing.T) {
 		},
 		settings: config.Settings{
 			OutputMode:   "json",
+			ResultsOnly:  true,
 			Timeout:      2 * time.Second,
 			CacheEnabled: false,
 		},
-		marketProvider: fakeMarketProvider{
-			protocolFees: []model.ProtocolFees{
-				{Rank: 1, Protocol: "Lido", Category: "Liquid Staking", Fees24hUSD: 8000000, Fees7dUSD: 55000000, Fees30dUSD: 200000000, Chains: 1},
-			},
-		},
+		marketProvider: fakePairPriceProvider{prices: map[string][]float64{
+			"WETH": {3000, 3300, 3000, 3300, 3000},
+			"USDC": {1, 1, 1, 1, 1},
+		}},
 	}
+
 	root := &cobra.Command{Use: "defi"}
 	root.SilenceUsage = true
 	root.SilenceErrors = true
 	root.SetOut(&stdout)
 	root.SetErr(&stderr)
-	root.AddCommand(state.newProtocolsCommand())
-	root.SetArgs([]string{"protocols", "fees"})
+	root.AddCommand(state.newYieldCommand())
+	root.SetArgs([]string{
+		"yield", "il-simulate",
+		"--chain", "1", "--asset-a", "WETH", "--asset-b", "USDC",
+		"--moves", "-50,+100", "--fee-apr", "12", "--reward-apr", "3",
+	})
 	if err := root.Execute(); err != nil {
-		t.Fatalf("expected protocols fees command success, err=%v stderr=%s", err, stderr.String())
+		t.Fatalf("yield il-simulate failed: %v stderr=%s", err, stderr.String())
 	}
 
-	var env map[string]any
-	if err := json.Unmarshal(stdout.Bytes(), &env); err != nil {
-		t.Fatalf("failed to parse output json: %v output=%s", err, stdout.String())
-	}
-	if env["success"] != true {
-		t.Fatalf("expected success=true, got %v", env["success"])
-	}
-	data, ok := env["data"].([]any)
-	if !ok {
-		t.Fatalf("expected data to be an array, got %T", env["data"])
-	}
-	if len(data) == 0 {
-		t.Fatalf("expected non-empty fees list")
+	var out model.ILSimulation
+	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+		t.Fatalf("failed parsing output json: %v output=%s", err, stdout.String())
 	}
-	first, ok := data[0].(map[string]any)
-	if !ok {
-		t.Fatalf("expected first item to be object, got %T", data[0])
+	lp := out.LPAnalytics
+	if len(lp.ILScenarios) != 2 || lp.ILScenarios[0].ILPct != -5.72 || lp.ILScenarios[1].ILPct != -5.72 {
+		t.Fatalf("expected symmetric loss for halving and doubling, got %+v", lp.ILScenarios)
 	}
-	if _, ok := first["protocol"]; !ok {
-		t.Fatalf("expected 'protocol' field, got %+v", first)
+	if lp.PairVolatilityPct <= 0 || lp.ExpectedILPct >= 0 || lp.FeeAPR != 12 || lp.RewardAPR != 3 {
+		t.Fatalf("expected pair volatility and expected loss, got %+v", lp)
 	}
-	if _, ok := first["fees_24h_usd"]; !ok {
-		t.Fatalf("expected 'fees_24h_usd' field, got %+v", first)
+	if lp.NetAPYAfterIL >= 15 {
+		t.Fatalf("expected net apy to subtract expected loss, got %+v", lp)
 	}
 }
 
-func TestRunnerProtocolsRevenue(t *testing.T) {
+func TestRewardsClaimableUsesRewardsProvider(t *testing.T) {
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
+	fake := &fakeRewardsProvider{rewards: []model.ClaimableReward{{
+		Provider:      "aave",
+		ChainID:       "eip155:1",
+		Account:       "0x00000000000000000000000000000000000000aa",
+		RewardAssetID: "eip155:1/erc20:0x7fc66500c84a76ad7e9c93437bfc5ac33e2ddae9",
+		Symbol:        "AAVE",
+		Claimable:     model.AmountInfo{AmountBaseUnits: "1000000000000000000", AmountDecimal: "1", Decimals: 18},
+		Sources:       []string{"0x98c23e9d8f34fefb1b7bd6a91b7ff122f4e16f5c"},
+	}}}
 	state := &runtimeState{
 		runner: &Runner{
 			stdout: &stdout,
@@ -730,55 +1960,121 @@ func TestRunnerProtocolsRevenue(t *testing.T) {
 		},
 		settings: config.Settings{
 			OutputMode:   "json",
+			ResultsOnly:  true,
 			Timeout:      2 * time.Second,
 			CacheEnabled: false,
 		},
-		marketProvider: fakeMarketProvider{
-			protocolRevenue: []model.ProtocolRevenue{
-				{Rank: 1, Protocol: "Lido", Category: "Liquid Staking", Revenue24hUSD: 5000000, Revenue7dUSD: 35000000, Revenue30dUSD: 130000000, Chains: 1},
-			},
-		},
+		rewardsProviders: map[string]providers.RewardsProvider{"aave": fake},
 	}
+
 	root := &cobra.Command{Use: "defi"}
 	root.SilenceUsage = true
 	root.SilenceErrors = true
 	root.SetOut(&stdout)
 	root.SetErr(&stderr)
-	root.AddCommand(state.newProtocolsCommand())
-	root.SetArgs([]string{"protocols", "revenue"})
+	root.AddCommand(state.newRewardsCommand())
+	root.SetArgs([]string{"rewards", "claimable", "--provider", "aave", "--chain", "1", "--address", "0x00000000000000000000000000000000000000AA"})
 	if err := root.Execute(); err != nil {
-		t.Fatalf("expected protocols revenue command success, err=%v stderr=%s", err, stderr.String())
+		t.Fatalf("rewards claimable failed: %v stderr=%s", err, stderr.String())
+	}
+	if fake.lastReq.Account != "0x00000000000000000000000000000000000000AA" || fake.lastReq.Chain.EVMChainID != 1 {
+		t.Fatalf("unexpected provider request: %+v", fake.lastReq)
+	}
+	var out []model.ClaimableReward
+	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+		t.Fatalf("failed parsing output json: %v output=%s", err, stdout.String())
+	}
+	if len(out) != 1 || out[0].Symbol != "AAVE" || out[0].Claimable.AmountDecimal != "1" {
+		t.Fatalf("unexpected claimable rewards: %+v", out)
 	}
 
-	var env map[string]any
-	if err := json.Unmarshal(stdout.Bytes(), &env); err != nil {
-		t.Fatalf("failed to parse output json: %v output=%s", err, stdout.String())
+	root.SetArgs([]string{"rewards", "claimable", "--provider", "kamino", "--chain", "1", "--address", "0x00000000000000000000000000000000000000AA"})
+	err := root.Execute()
+	cErr, ok := clierr.As(err)
+	if !ok || cErr.Code != clierr.CodeUnsupported {
+		t.Fatalf("expected unsupported provider error, got %v", err)
 	}
-	if env["success"] != true {
-		t.Fatalf("expected success=true, got %v", env["success"])
+}
+
+func TestLendHealthWatchExitsOnAlert(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	healthy, risky := 1.5, 1.1
+	provider := &fakeLendingHealthProvider{
+		fakeLendingProvider: &fakeLendingProvider{name: "aave"},
+		healthFactors:       []*float64{&healthy, &risky},
 	}
-	data, ok := env["data"].([]any)
-	if !ok {
-		t.Fatalf("expected data to be an array, got %T", env["data"])
+	state := &runtimeState{
+		runner: &Runner{
+			stdout: &stdout,
+			stderr: &stderr,
+			now:    time.Now,
+		},
+		settings: config.Settings{
+			OutputMode:   "json",
+			ResultsOnly:  true,
+			Timeout:      2 * time.Second,
+			CacheEnabled: false,
+		},
+		lendingProviders: map[string]providers.LendingProvider{"aave": provider},
 	}
-	if len(data) == 0 {
-		t.Fatalf("expected non-empty revenue list")
+
+	root := &cobra.Command{Use: "defi"}
+	root.SilenceUsage = true
+	root.SilenceErrors = true
+	root.SetOut(&stdout)
+	root.SetErr(&stderr)
+	root.AddCommand(state.newLendCommand())
+	root.SetArgs([]string{
+		"lend", "health",
+		"--provider", "aave",
+		"--chain", "1",
+		"--address", "0x000000000000000000000000000000000000dEaD",
+		"--watch", "--interval", "1ms", "--alert-below", "1.2",
+	})
+	err := root.Execute()
+	cErr, ok := clierr.As(err)
+	if !ok || cErr.Code != clierr.CodeHealthAlert {
+		t.Fatalf("expected health alert error, got %v", err)
 	}
-	first, ok := data[0].(map[string]any)
-	if !ok {
-		t.Fatalf("expected first item to be object, got %T", data[0])
+	if clierr.ExitCode(err) != 17 {
+		t.Fatalf("expected exit code 17, got %d", clierr.ExitCode(err))
 	}
-	if _, ok := first["protocol"]; !ok {
-		t.Fatalf("expected 'protocol' field, got %+v", first)
+	if provider.calls != 2 {
+		t.Fatalf("expected watch to poll until the alert, got %d polls", provider.calls)
 	}
-	if _, ok := first["revenue_24h_usd"]; !ok {
-		t.Fatalf("expected 'revenue_24h_usd' field, got %+v", first)
+	var first []model.LendHealth
+	if err := json.Unmarshal(stdout.Bytes(), &first); err != nil {
+		t.Fatalf("expected the healthy poll on stdout: %v output=%s", err, stdout.String())
+	}
+	if len(first) != 1 || *first[0].HealthFactor != 1.5 {
+		t.Fatalf("unexpected healthy poll output: %+v", first)
 	}
 }
 
-func TestRunnerChainsAssets(t *testing.T) {
+func TestLendBorrowQuoteRanksAcrossProviders(t *testing.T) {
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
+	const usdc = "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"
+	aave := &fakeBorrowQuoteProvider{
+		fakeLendingProvider: &fakeLendingProvider{name: "aave"},
+		quotes: []model.BorrowQuote{
+			{Provider: "aave", ProviderNativeID: "aave-core", BorrowAPY: 5, AvailableLiquidityUSD: 5_000_000, MaxLTVPct: 80, LiquidationLTVPct: 83},
+		},
+	}
+	morpho := &fakeBorrowQuoteProvider{
+		fakeLendingProvider: &fakeLendingProvider{name: "morpho"},
+		quotes: []model.BorrowQuote{
+			{Provider: "morpho", ProviderNativeID: "morpho-thin", BorrowAPY: 4, AvailableLiquidityUSD: 500_000, MaxLTVPct: 86, LiquidationLTVPct: 86},
+			{Provider: "morpho", ProviderNativeID: "morpho-deep", BorrowAPY: 5, AvailableLiquidityUSD: 3_000_000, MaxLTVPct: 86, LiquidationLTVPct: 86},
+		},
+	}
+	moonwell := &fakeLendingProvider{
+		name: "moonwell",
+		markets: []model.LendMarket{
+			{Provider: "moonwell", ProviderNativeID: "moonwell-usdc", BorrowAPY: 6, LiquidityUSD: 10_000_000},
+		},
+	}
 	state := &runtimeState{
 		runner: &Runner{
 			stdout: &stdout,
@@ -787,63 +2083,65 @@ func TestRunnerChainsAssets(t *testing.T) {
 		},
 		settings: config.Settings{
 			OutputMode:   "json",
+			ResultsOnly:  false,
 			Timeout:      2 * time.Second,
 			CacheEnabled: false,
 		},
-		marketProvider: fakeMarketProvider{
-			chainAssets: []model.ChainAssetTVL{
-				{
-					Rank:    1,
-					Chain:   "Ethereum",
-					ChainID: "eip155:1",
-					Asset:   "USDC",
-					AssetID: "eip155:1/erc20:0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48",
-					TVLUSD:  12345.67,
-				},
-			},
-		},
+		lendingProviders: map[string]providers.LendingProvider{"aave": aave, "morpho": morpho, "moonwell": moonwell},
+		marketProvider:   fakeTokenPriceProvider{prices: map[string]model.TokenPrice{usdc: {PriceUSD: 1, Decimals: 6}}},
 	}
+
 	root := &cobra.Command{Use: "defi"}
 	root.SilenceUsage = true
 	root.SilenceErrors = true
 	root.SetOut(&stdout)
 	root.SetErr(&stderr)
-	root.AddCommand(state.newChainsCommand())
-	root.SetArgs([]string{"chains", "assets", "--chain", "1", "--asset", "USDC"})
+	root.AddCommand(state.newLendCommand())
+	root.SetArgs([]string{
+		"lend", "borrow-quote",
+		"--provider", "aave",
+		"--compare", "morpho,moonwell",
+		"--chain", "1",
+		"--asset", "USDC",
+		"--collateral", "WETH",
+		"--amount-decimal", "1000000",
+	})
 	if err := root.Execute(); err != nil {
-		t.Fatalf("expected chains assets command success, err=%v stderr=%s", err, stderr.String())
+		t.Fatalf("borrow-quote failed: %v stderr=%s", err, stderr.String())
 	}
-
-	var env map[string]any
-	if err := json.Unmarshal(stdout.Bytes(), &env); err != nil {
-		t.Fatalf("failed to parse output json: %v output=%s", err, stdout.String())
+	if morpho.lastReq.Collateral.Symbol != "WETH" {
+		t.Fatalf("expected collateral to reach the provider, got %+v", morpho.lastReq)
 	}
-	if env["success"] != true {
-		t.Fatalf("expected success=true, got %v", env["success"])
+	var env struct {
+		Data     []model.BorrowQuote `json:"data"`
+		Warnings []string            `json:"warnings"`
 	}
-	data, ok := env["data"].([]any)
-	if !ok {
-		t.Fatalf("expected data to be an array, got %T", env["data"])
+	if err := json.Unmarshal(stdout.Bytes(), &env); err != nil {
+		t.Fatalf("decode output: %v output=%s", err, stdout.String())
 	}
-	if len(data) != 1 {
-		t.Fatalf("expected one chain asset item, got %d", len(data))
+	order := []string{}
+	for _, quote := range env.Data {
+		order = append(order, quote.ProviderNativeID)
 	}
-	first, ok := data[0].(map[string]any)
-	if !ok {
-		t.Fatalf("expected first item to be object, got %T", data[0])
+	if strings.Join(order, ",") != "morpho-deep,aave-core,moonwell-usdc,morpho-thin" {
+		t.Fatalf("unexpected ranking: %v", order)
 	}
-	if _, ok := first["asset"]; !ok {
-		t.Fatalf("expected 'asset' field in output, got %+v", first)
+	best := env.Data[0]
+	if best.Rank != 1 || best.AmountUSD != 1_000_000 || best.AnnualCostUSD != 50_000 {
+		t.Fatalf("unexpected best venue: %+v", best)
 	}
-	if _, ok := first["asset_id"]; !ok {
-		t.Fatalf("expected 'asset_id' field in output, got %+v", first)
+	if best.FitsLiquidity == nil || !*best.FitsLiquidity || best.MinCollateralUSD < 1_162_790 || best.MinCollateralUSD > 1_162_791 {
+		t.Fatalf("unexpected best venue liquidity or collateral: %+v", best)
 	}
-	if _, ok := first["tvl_usd"]; !ok {
-		t.Fatalf("expected 'tvl_usd' field in output, got %+v", first)
+	if thin := env.Data[3]; thin.FitsLiquidity == nil || *thin.FitsLiquidity {
+		t.Fatalf("expected thin market to be flagged as short of liquidity: %+v", thin)
+	}
+	if !strings.Contains(strings.Join(env.Warnings, "\n"), "provider moonwell venues are not filtered by --collateral") {
+		t.Fatalf("expected fallback warning for moonwell, got %v", env.Warnings)
 	}
 }
 
-func TestRunnerChainsAssetsAllowsUnknownSymbolFilter(t *testing.T) {
+func TestProtocolsTopFlagsTVLDrops(t *testing.T) {
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
 	state := &runtimeState{
@@ -857,55 +2155,80 @@ func TestRunnerChainsAssetsAllowsUnknownSymbolFilter(t *testing.T) {
 			Timeout:      2 * time.Second,
 			CacheEnabled: false,
 		},
-		marketProvider: fakeMarketProvider{
-			expectedAssetSymbol: "UNI",
-			chainAssets: []model.ChainAssetTVL{
-				{
-					Rank:    1,
-					Chain:   "Ethereum",
-					ChainID: "eip155:1",
-					Asset:   "UNI",
-					AssetID: "",
-					TVLUSD:  456.78,
-				},
-			},
+		marketProvider: fakeTVLChangeProvider{
+			fakeMarketProvider: fakeMarketProvider{protocols: []model.ProtocolTVL{
+				{Rank: 1, Protocol: "Steady", TVLUSD: 1000},
+				{Rank: 2, Protocol: "Collapsing", TVLUSD: 500},
+				{Rank: 3, Protocol: "New", TVLUSD: 100},
+			}},
+			changes: map[string]float64{"Steady": -2.5, "Collapsing": -42},
 		},
 	}
+
 	root := &cobra.Command{Use: "defi"}
 	root.SilenceUsage = true
 	root.SilenceErrors = true
 	root.SetOut(&stdout)
 	root.SetErr(&stderr)
-	root.AddCommand(state.newChainsCommand())
-	root.SetArgs([]string{"chains", "assets", "--chain", "1", "--asset", "UNI"})
+	root.AddCommand(state.newProtocolsCommand())
+	root.SetArgs([]string{"protocols", "top", "--change-window", "7d", "--alert-drop-pct", "20"})
 	if err := root.Execute(); err != nil {
-		t.Fatalf("expected chains assets command success for unknown symbol, err=%v stderr=%s", err, stderr.String())
+		t.Fatalf("protocols top failed: %v", err)
+	}
+	var env struct {
+		Data     []model.ProtocolTVL `json:"data"`
+		Warnings []string            `json:"warnings"`
 	}
-
-	var env map[string]any
 	if err := json.Unmarshal(stdout.Bytes(), &env); err != nil {
-		t.Fatalf("failed to parse output json: %v output=%s", err, stdout.String())
+		t.Fatalf("decode output: %v output=%s", err, stdout.String())
 	}
-	if env["success"] != true {
-		t.Fatalf("expected success=true, got %v", env["success"])
+	if len(env.Data) != 3 {
+		t.Fatalf("unexpected rows: %+v", env.Data)
+	}
+	steady, collapsing, fresh := env.Data[0], env.Data[1], env.Data[2]
+	if steady.TVLChangePct == nil || *steady.TVLChangePct != -2.5 || steady.DropAlert || steady.TVLChangeWindow != "7d" {
+		t.Fatalf("unexpected steady row: %+v", steady)
+	}
+	if collapsing.TVLChangePct == nil || !collapsing.DropAlert {
+		t.Fatalf("expected collapsing protocol to be flagged: %+v", collapsing)
+	}
+	if fresh.TVLChangePct != nil || fresh.DropAlert {
+		t.Fatalf("expected no change for protocol without history: %+v", fresh)
+	}
+	warnings := strings.Join(env.Warnings, "\n")
+	if !strings.Contains(warnings, "protocol Collapsing TVL dropped 42.00% over 7d") || !strings.Contains(warnings, "no 7d TVL change for protocols: New") {
+		t.Fatalf("unexpected warnings: %v", env.Warnings)
 	}
 }
 
-func TestRunnerLendPositionsCallsProvider(t *testing.T) {
+func TestAttachProtocolSecurityRescoresOpportunities(t *testing.T) {
+	provider := &fakeSecurityProvider{security: map[string]model.ProtocolSecurity{
+		"aave": {Audits: 3, ListingAgeDays: 2000, Exploits: []model.ProtocolIncident{{Date: "2023-04-01", Description: "Oracle exploit"}}},
+	}}
+	state := &runtimeState{marketProvider: provider}
+	items := []model.YieldOpportunity{
+		{OpportunityID: "a", Protocol: "aave", FetchedAt: "2026-01-01T00:00:00Z"},
+		{OpportunityID: "b", Protocol: "aave", FetchedAt: "2026-01-01T00:00:00Z"},
+		{OpportunityID: "c", Protocol: "unknown", FetchedAt: "2026-01-01T00:00:00Z"},
+	}
+	warnings := state.attachProtocolSecurity(context.Background(), items)
+	if provider.calls != 2 {
+		t.Fatalf("expected one lookup per protocol, got %d", provider.calls)
+	}
+	if items[0].Security == nil || items[0].Security.Exploits != 1 || items[0].Security.LastExploitAt != "2023-04-01" {
+		t.Fatalf("unexpected security: %+v", items[0].Security)
+	}
+	if items[1].RiskFactors == nil || items[1].RiskFactors.Exploits != 1 {
+		t.Fatalf("expected exploits to feed risk factors: %+v", items[1].RiskFactors)
+	}
+	if items[2].Security != nil || len(warnings) != 1 || !strings.Contains(warnings[0], "protocol unknown") {
+		t.Fatalf("expected a warning for the unknown protocol, got %+v %v", items[2].Security, warnings)
+	}
+}
+
+func TestRunnerRouteQuoteRanksByNetOutput(t *testing.T) {
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
-	aaveProvider := &fakeLendingProvider{
-		name: "aave",
-		positions: []model.LendPosition{
-			{
-				Provider:       "aave",
-				ChainID:        "eip155:1",
-				AccountAddress: "0x000000000000000000000000000000000000dead",
-				PositionType:   "collateral",
-				AssetID:        "eip155:1/erc20:0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48",
-			},
-		},
-	}
 	state := &runtimeState{
 		runner: &Runner{
 			stdout: &stdout,
@@ -917,8 +2240,13 @@ func TestRunnerLendPositionsCallsProvider(t *testing.T) {
 			Timeout:      2 * time.Second,
 			CacheEnabled: false,
 		},
-		lendingProviders: map[string]providers.LendingProvider{
-			"aave": aaveProvider,
+		marketProvider: fakeRoutePriceProvider{priceUSD: 3000},
+		bridgeProviders: map[string]providers.BridgeProvider{
+			"across": &fakeRouteBridgeProvider{name: "across", feeUSD: 1, outDecimal: "999"},
+		},
+		swapProviders: map[string]providers.SwapProvider{
+			"odos":     &fakeRouteSwapProvider{name: "odos", outDecimal: "0.3", gasUSD: 5},
+			"paraswap": &fakeRouteSwapProvider{name: "paraswap", outDecimal: "0.301", gasUSD: 10},
 		},
 	}
 
@@ -927,39 +2255,66 @@ func TestRunnerLendPositionsCallsProvider(t *testing.T) {
 	root.SilenceErrors = true
 	root.SetOut(&stdout)
 	root.SetErr(&stderr)
-	root.AddCommand(state.newLendCommand())
+	root.AddCommand(state.newRouteCommand())
 	root.SetArgs([]string{
-		"lend", "positions",
-		"--provider", "aave",
-		"--chain", "1",
-		"--address", "0x000000000000000000000000000000000000dEaD",
-		"--asset", "USDC",
-		"--type", "collateral",
-		"--limit", "5",
+		"route", "quote",
+		"--from", "ethereum", "--to", "arbitrum",
+		"--asset", "USDC", "--to-asset", "WETH", "--amount-decimal", "1000",
+		"--bridge-providers", "across", "--swap-providers", "odos,paraswap",
 	})
 
 	if err := root.Execute(); err != nil {
-		t.Fatalf("lend positions command failed: %v stderr=%s", err, stderr.String())
+		t.Fatalf("route quote command failed: %v stderr=%s", err, stderr.String())
 	}
-	if aaveProvider.calls != 1 {
-		t.Fatalf("expected provider call once, got %d", aaveProvider.calls)
+	var env struct {
+		Data     model.RouteComparison `json:"data"`
+		Warnings []string              `json:"warnings"`
+		Meta     struct {
+			Partial bool `json:"partial"`
+		} `json:"meta"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &env); err != nil {
+		t.Fatalf("failed to parse output json: %v output=%s", err, stdout.String())
+	}
+	if env.Data.RankedBy != "net_out_usd" || env.Data.BestRoute != "across>odos" || len(env.Data.Routes) != 2 {
+		t.Fatalf("expected odos to win on output net of gas, got %+v", env.Data)
 	}
-	if aaveProvider.lastReq.PositionType != providers.LendPositionTypeCollateral {
-		t.Fatalf("expected collateral request type, got %s", aaveProvider.lastReq.PositionType)
+	best := env.Data.Routes[0]
+	if best.NetOutUSD != 895 || best.GasUSD != 5 || best.BridgeFeeUSD != 1 || len(best.Legs) != 2 {
+		t.Fatalf("unexpected best route economics: %+v", best)
 	}
-	if !strings.EqualFold(aaveProvider.lastReq.Account, "0x000000000000000000000000000000000000dead") {
-		t.Fatalf("unexpected account passed to provider: %s", aaveProvider.lastReq.Account)
+	if best.Legs[0].Type != "bridge" || best.Legs[1].Type != "swap" || best.Legs[1].InputAmount.AmountDecimal != "999" {
+		t.Fatalf("expected bridge leg output to feed the swap leg, got %+v", best.Legs)
 	}
-	if !strings.EqualFold(aaveProvider.lastReq.Asset.Symbol, "USDC") {
-		t.Fatalf("expected USDC asset filter, got %+v", aaveProvider.lastReq.Asset)
+	if !env.Meta.Partial || len(env.Warnings) != 1 || !strings.Contains(env.Warnings[0], "route across failed") {
+		t.Fatalf("expected the failed direct bridge route as a partial warning, got partial=%v warnings=%v", env.Meta.Partial, env.Warnings)
 	}
+}
 
-	var env map[string]any
-	if err := json.Unmarshal(stdout.Bytes(), &env); err != nil {
-		t.Fatalf("failed to parse output json: %v output=%s", err, stdout.String())
+func TestRunnerRouteQuoteRejectsUnknownSwapProvider(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	state := &runtimeState{
+		runner:   &Runner{stdout: &stdout, stderr: &stderr, now: time.Now},
+		settings: config.Settings{OutputMode: "json", Timeout: 2 * time.Second},
+		bridgeProviders: map[string]providers.BridgeProvider{
+			"across": &fakeRouteBridgeProvider{name: "across", outDecimal: "999"},
+		},
+		swapProviders: map[string]providers.SwapProvider{},
 	}
-	if env["success"] != true {
-		t.Fatalf("expected success=true, got %v", env["success"])
+	root := &cobra.Command{Use: "defi"}
+	root.SilenceUsage = true
+	root.SilenceErrors = true
+	root.AddCommand(state.newRouteCommand())
+	root.SetArgs([]string{
+		"route", "quote",
+		"--from", "ethereum", "--to", "arbitrum",
+		"--asset", "USDC", "--to-asset", "WETH", "--amount-decimal", "1000",
+		"--bridge-providers", "across", "--swap-providers", "nope",
+	})
+	err := root.Execute()
+	if clierr.ExitCode(err) != int(clierr.CodeUnsupported) {
+		t.Fatalf("expected unsupported error, got %v", err)
 	}
 }
 
@@ -1229,6 +2584,70 @@ func TestSwapQuoteWithOneInchForEVM(t *testing.T) {
 	}
 }
 
+func TestSwapQuoteConvertsAmountUSD(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	const weth = "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2"
+	oneinch := &fakeSwapProvider{name: "1inch"}
+	state := &runtimeState{
+		runner: &Runner{
+			stdout: &stdout,
+			stderr: &stderr,
+			now:    time.Now,
+		},
+		settings: config.Settings{
+			OutputMode:   "json",
+			Timeout:      2 * time.Second,
+			CacheEnabled: false,
+		},
+		swapProviders:  map[string]providers.SwapProvider{"1inch": oneinch},
+		marketProvider: fakeTokenPriceProvider{prices: map[string]model.TokenPrice{weth: {PriceUSD: 2000, Decimals: 18}}},
+	}
+	root := &cobra.Command{Use: "defi"}
+	root.SilenceUsage = true
+	root.SilenceErrors = true
+	root.SetOut(&stdout)
+	root.SetErr(&stderr)
+	root.AddCommand(state.newSwapCommand())
+	root.SetArgs([]string{
+		"swap", "quote",
+		"--provider", "1inch",
+		"--chain", "1",
+		"--from-asset", "WETH",
+		"--to-asset", "USDC",
+		"--amount-usd", "2500",
+	})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("swap command failed: %v stderr=%s", err, stderr.String())
+	}
+	if oneinch.lastReq.AmountBaseUnits != "1250000000000000000" || oneinch.lastReq.AmountDecimal != "1.25" {
+		t.Fatalf("expected 2500 USD to convert to 1.25 WETH, got %+v", oneinch.lastReq)
+	}
+	var env struct {
+		Data model.SwapQuote `json:"data"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &env); err != nil {
+		t.Fatalf("decode output: %v output=%s", err, stdout.String())
+	}
+	if env.Data.InputAmount.AmountUSD != 2500 || env.Data.InputAmount.AmountDecimal != "1.25" {
+		t.Fatalf("expected input amount in USD and token units, got %+v", env.Data.InputAmount)
+	}
+
+	stdout.Reset()
+	root.SetArgs([]string{
+		"swap", "quote",
+		"--provider", "1inch",
+		"--chain", "1",
+		"--from-asset", "WETH",
+		"--to-asset", "USDC",
+		"--amount-decimal", "1",
+		"--amount-usd", "2500",
+	})
+	if err := root.Execute(); err == nil || !strings.Contains(err.Error(), "--amount-usd") {
+		t.Fatalf("expected conflicting amount flags to fail, got %v", err)
+	}
+}
+
 func TestSwapSlippageOverridePassedToProvider(t *testing.T) {
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
@@ -1507,15 +2926,281 @@ func TestSwapExactOutputWithoutProviderRejectedOnSolana(t *testing.T) {
 	if err := root.Execute(); err == nil {
 		t.Fatalf("expected provider requirement error, stderr=%s", stderr.String())
 	}
-	if jupiter.calls != 0 {
-		t.Fatalf("expected jupiter not to be called, got %d calls", jupiter.calls)
+	if jupiter.calls != 0 {
+		t.Fatalf("expected jupiter not to be called, got %d calls", jupiter.calls)
+	}
+}
+
+func TestSwapExactOutputRequiresOutputAmount(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	uniswap := &fakeSwapProvider{name: "uniswap"}
+	state := &runtimeState{
+		runner: &Runner{
+			stdout: &stdout,
+			stderr: &stderr,
+			now:    time.Now,
+		},
+		settings: config.Settings{
+			OutputMode:   "json",
+			Timeout:      2 * time.Second,
+			CacheEnabled: false,
+		},
+		swapProviders: map[string]providers.SwapProvider{
+			"uniswap": uniswap,
+		},
+	}
+	root := &cobra.Command{Use: "defi"}
+	root.SilenceUsage = true
+	root.SilenceErrors = true
+	root.SetOut(&stdout)
+	root.SetErr(&stderr)
+	root.AddCommand(state.newSwapCommand())
+	root.SetArgs([]string{
+		"swap", "quote",
+		"--provider", "uniswap",
+		"--chain", "1",
+		"--from-asset", "USDC",
+		"--to-asset", "DAI",
+		"--type", "exact-output",
+		"--from-address", "0x000000000000000000000000000000000000dEaD",
+	})
+	if err := root.Execute(); err == nil {
+		t.Fatalf("expected validation error, stderr=%s", stderr.String())
+	}
+	if uniswap.calls != 0 {
+		t.Fatalf("expected provider not to be called on invalid amount flags, got %d calls", uniswap.calls)
+	}
+}
+
+func TestSwapTypeValidation(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	uniswap := &fakeSwapProvider{name: "uniswap"}
+	state := &runtimeState{
+		runner: &Runner{
+			stdout: &stdout,
+			stderr: &stderr,
+			now:    time.Now,
+		},
+		settings: config.Settings{
+			OutputMode:   "json",
+			Timeout:      2 * time.Second,
+			CacheEnabled: false,
+		},
+		swapProviders: map[string]providers.SwapProvider{
+			"uniswap": uniswap,
+		},
+	}
+	root := &cobra.Command{Use: "defi"}
+	root.SilenceUsage = true
+	root.SilenceErrors = true
+	root.SetOut(&stdout)
+	root.SetErr(&stderr)
+	root.AddCommand(state.newSwapCommand())
+	root.SetArgs([]string{
+		"swap", "quote",
+		"--provider", "uniswap",
+		"--chain", "1",
+		"--from-asset", "USDC",
+		"--to-asset", "DAI",
+		"--type", "limit-order",
+		"--amount", "1000000",
+		"--from-address", "0x000000000000000000000000000000000000dEaD",
+	})
+	if err := root.Execute(); err == nil {
+		t.Fatalf("expected validation error, stderr=%s", stderr.String())
+	}
+	if uniswap.calls != 0 {
+		t.Fatalf("expected provider not to be called on invalid type, got %d calls", uniswap.calls)
+	}
+}
+
+func TestSwapQuoteArchiveQuotesFeedsQuotesHistory(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	dir := t.TempDir()
+	responseCache, err := cache.Open(filepath.Join(dir, "cache.db"), filepath.Join(dir, "cache.lock"), time.Hour)
+	if err != nil {
+		t.Fatalf("open cache failed: %v", err)
+	}
+	t.Cleanup(func() { _ = responseCache.Close() })
+	provider := &fakeSwapProvider{name: "1inch"}
+	state := &runtimeState{
+		runner: &Runner{
+			stdout: &stdout,
+			stderr: &stderr,
+			now:    time.Now,
+		},
+		settings: config.Settings{
+			OutputMode:     "json",
+			ResultsOnly:    true,
+			Timeout:        2 * time.Second,
+			CacheEnabled:   true,
+			QuotesPath:     filepath.Join(dir, "quotes.db"),
+			QuotesLockPath: filepath.Join(dir, "quotes.lock"),
+		},
+		cache: responseCache,
+		swapProviders: map[string]providers.SwapProvider{
+			"1inch": provider,
+		},
+	}
+	t.Cleanup(func() { _ = state.quoteStore.Close() })
+	root := &cobra.Command{Use: "defi"}
+	root.SilenceUsage = true
+	root.SilenceErrors = true
+	root.SetOut(&stdout)
+	root.SetErr(&stderr)
+	root.AddCommand(state.newSwapCommand())
+	root.AddCommand(state.newQuotesCommand())
+
+	quoteArgs := []string{
+		"swap", "quote",
+		"--provider", "1inch",
+		"--chain", "base",
+		"--from-asset", "USDC",
+		"--to-asset", "WETH",
+		"--amount", "1000000",
+	}
+	root.SetArgs(quoteArgs)
+	if err := root.Execute(); err != nil {
+		t.Fatalf("swap quote failed: %v stderr=%s", err, stderr.String())
+	}
+	root.SetArgs(append(quoteArgs, "--archive-quotes"))
+	if err := root.Execute(); err != nil {
+		t.Fatalf("swap quote with archive failed: %v stderr=%s", err, stderr.String())
+	}
+	if provider.calls != 2 {
+		t.Fatalf("expected --archive-quotes to bypass the cached quote, got %d provider calls", provider.calls)
+	}
+
+	stdout.Reset()
+	root.SetArgs([]string{"quotes", "history", "--pair", "USDC/WETH", "--chain", "8453", "--window", "1d"})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("quotes history failed: %v stderr=%s", err, stderr.String())
+	}
+	var out []map[string]any
+	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+		t.Fatalf("failed parsing output json: %v output=%s", err, stdout.String())
+	}
+	if len(out) != 1 {
+		t.Fatalf("expected only the opt-in quote to be archived, got %+v", out)
+	}
+	if out[0]["provider"] != "1inch" || out[0]["kind"] != "swap" || out[0]["chain_id"] != "eip155:8453" {
+		t.Fatalf("unexpected archived quote: %+v", out[0])
+	}
+}
+
+func TestSwapQuoteChecksRouteDenyRulesOnCachedQuotes(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	dir := t.TempDir()
+	responseCache, err := cache.Open(filepath.Join(dir, "cache.db"), filepath.Join(dir, "cache.lock"), time.Hour)
+	if err != nil {
+		t.Fatalf("open cache failed: %v", err)
+	}
+	t.Cleanup(func() { _ = responseCache.Close() })
+	provider := &fakeSwapProvider{name: "1inch"}
+	state := &runtimeState{
+		runner: &Runner{
+			stdout: &stdout,
+			stderr: &stderr,
+			now:    time.Now,
+		},
+		settings: config.Settings{
+			OutputMode:   "json",
+			ResultsOnly:  true,
+			Timeout:      2 * time.Second,
+			CacheEnabled: true,
+		},
+		cache: responseCache,
+		swapProviders: map[string]providers.SwapProvider{
+			"1inch": provider,
+		},
+	}
+	root := &cobra.Command{Use: "defi"}
+	root.SilenceUsage = true
+	root.SilenceErrors = true
+	root.SetOut(&stdout)
+	root.SetErr(&stderr)
+	root.AddCommand(state.newSwapCommand())
+	root.SetArgs([]string{"swap", "quote", "--provider", "1inch", "--chain", "base", "--from-asset", "USDC", "--to-asset", "WETH", "--amount", "1000000"})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("swap quote failed: %v stderr=%s", err, stderr.String())
+	}
+
+	// The route was cached before it was denied; the deny rule must still
+	// apply to the next request.
+	state.settings.DenyProtocols = []string{"test"}
+	err = root.Execute()
+	if typed, ok := clierr.As(err); !ok || typed.Code != clierr.CodeActionPolicy {
+		t.Fatalf("expected the denied route to fail with an action policy error, got %v", err)
+	}
+	if provider.calls != 2 {
+		t.Fatalf("expected the denied rules to miss the cached quote, got %d provider calls", provider.calls)
+	}
+}
+
+func TestQuotesHistoryMatchesBridgeQuotesOnDestinationChain(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	dir := t.TempDir()
+	state := &runtimeState{
+		runner: &Runner{
+			stdout: &stdout,
+			stderr: &stderr,
+			now:    time.Now,
+		},
+		settings: config.Settings{
+			OutputMode:     "json",
+			ResultsOnly:    true,
+			Timeout:        2 * time.Second,
+			QuotesPath:     filepath.Join(dir, "quotes.db"),
+			QuotesLockPath: filepath.Join(dir, "quotes.lock"),
+		},
+		bridgeProviders: map[string]providers.BridgeProvider{
+			"across": &fakeRouteBridgeProvider{name: "across", outDecimal: "0.99"},
+		},
+	}
+	t.Cleanup(func() { _ = state.quoteStore.Close() })
+	root := &cobra.Command{Use: "defi"}
+	root.SilenceUsage = true
+	root.SilenceErrors = true
+	root.SetOut(&stdout)
+	root.SetErr(&stderr)
+	root.AddCommand(state.newBridgeCommand())
+	root.AddCommand(state.newQuotesCommand())
+
+	root.SetArgs([]string{"bridge", "quote", "--provider", "across", "--from", "base", "--to", "arbitrum", "--asset", "USDC", "--amount", "1000000", "--archive-quotes"})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("bridge quote failed: %v stderr=%s", err, stderr.String())
+	}
+
+	history := func(args ...string) []map[string]any {
+		t.Helper()
+		stdout.Reset()
+		root.SetArgs(append([]string{"quotes", "history", "--pair", "USDC/USDC", "--chain", "base", "--kind", "bridge"}, args...))
+		if err := root.Execute(); err != nil {
+			t.Fatalf("quotes history failed: %v stderr=%s", err, stderr.String())
+		}
+		var out []map[string]any
+		if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+			t.Fatalf("failed parsing output json: %v output=%s", err, stdout.String())
+		}
+		return out
+	}
+	if out := history(); len(out) != 0 {
+		t.Fatalf("expected no base-to-base rows, got %+v", out)
+	}
+	out := history("--to-chain", "arbitrum")
+	if len(out) != 1 || out[0]["to_chain_id"] != "eip155:42161" {
+		t.Fatalf("expected the bridge quote to match on its destination chain, got %+v", out)
 	}
 }
 
-func TestSwapExactOutputRequiresOutputAmount(t *testing.T) {
+func TestQuotesHistoryRejectsMalformedPair(t *testing.T) {
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
-	uniswap := &fakeSwapProvider{name: "uniswap"}
 	state := &runtimeState{
 		runner: &Runner{
 			stdout: &stdout,
@@ -1523,12 +3208,8 @@ func TestSwapExactOutputRequiresOutputAmount(t *testing.T) {
 			now:    time.Now,
 		},
 		settings: config.Settings{
-			OutputMode:   "json",
-			Timeout:      2 * time.Second,
-			CacheEnabled: false,
-		},
-		swapProviders: map[string]providers.SwapProvider{
-			"uniswap": uniswap,
+			OutputMode: "json",
+			Timeout:    2 * time.Second,
 		},
 	}
 	root := &cobra.Command{Use: "defi"}
@@ -1536,68 +3217,75 @@ func TestSwapExactOutputRequiresOutputAmount(t *testing.T) {
 	root.SilenceErrors = true
 	root.SetOut(&stdout)
 	root.SetErr(&stderr)
-	root.AddCommand(state.newSwapCommand())
-	root.SetArgs([]string{
-		"swap", "quote",
-		"--provider", "uniswap",
-		"--chain", "1",
-		"--from-asset", "USDC",
-		"--to-asset", "DAI",
-		"--type", "exact-output",
-		"--from-address", "0x000000000000000000000000000000000000dEaD",
-	})
-	if err := root.Execute(); err == nil {
-		t.Fatalf("expected validation error, stderr=%s", stderr.String())
+	root.AddCommand(state.newQuotesCommand())
+	root.SetArgs([]string{"quotes", "history", "--pair", "USDC", "--chain", "8453"})
+	err := root.Execute()
+	if err == nil {
+		t.Fatal("expected malformed pair to fail")
 	}
-	if uniswap.calls != 0 {
-		t.Fatalf("expected provider not to be called on invalid amount flags, got %d calls", uniswap.calls)
+	if code := clierr.ExitCode(err); code != int(clierr.CodeUsage) {
+		t.Fatalf("expected usage exit code, got %d (%v)", code, err)
 	}
 }
 
-func TestSwapTypeValidation(t *testing.T) {
+func TestHistoryTVLPassesRangeToProvider(t *testing.T) {
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
-	uniswap := &fakeSwapProvider{name: "uniswap"}
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	var gotReq providers.TVLHistoryRequest
 	state := &runtimeState{
 		runner: &Runner{
 			stdout: &stdout,
 			stderr: &stderr,
-			now:    time.Now,
+			now:    func() time.Time { return now },
 		},
 		settings: config.Settings{
 			OutputMode:   "json",
+			ResultsOnly:  true,
 			Timeout:      2 * time.Second,
 			CacheEnabled: false,
 		},
-		swapProviders: map[string]providers.SwapProvider{
-			"uniswap": uniswap,
-		},
+		marketProvider: fakeMarketHistoryProvider{tvlReq: &gotReq},
 	}
-	root := &cobra.Command{Use: "defi"}
-	root.SilenceUsage = true
-	root.SilenceErrors = true
-	root.SetOut(&stdout)
-	root.SetErr(&stderr)
-	root.AddCommand(state.newSwapCommand())
-	root.SetArgs([]string{
-		"swap", "quote",
-		"--provider", "uniswap",
-		"--chain", "1",
-		"--from-asset", "USDC",
-		"--to-asset", "DAI",
-		"--type", "limit-order",
-		"--amount", "1000000",
-		"--from-address", "0x000000000000000000000000000000000000dEaD",
-	})
-	if err := root.Execute(); err == nil {
-		t.Fatalf("expected validation error, stderr=%s", stderr.String())
+	newRoot := func(args ...string) *cobra.Command {
+		root := &cobra.Command{Use: "defi"}
+		root.SilenceUsage = true
+		root.SilenceErrors = true
+		root.SetOut(&stdout)
+		root.SetErr(&stderr)
+		root.AddCommand(state.newHistoryCommand())
+		root.SetArgs(args)
+		return root
 	}
-	if uniswap.calls != 0 {
-		t.Fatalf("expected provider not to be called on invalid type, got %d calls", uniswap.calls)
+
+	if err := newRoot("history", "tvl", "--chain", "1", "--protocol", "Aave-V3", "--window", "7d").Execute(); err != nil {
+		t.Fatalf("history tvl failed: %v stderr=%s", err, stderr.String())
+	}
+	if gotReq.Chain.CAIP2 != "eip155:1" || gotReq.Protocol != "aave-v3" {
+		t.Fatalf("unexpected provider request: %+v", gotReq)
+	}
+	if !gotReq.EndTime.Equal(now) || !gotReq.StartTime.Equal(now.Add(-7*24*time.Hour)) {
+		t.Fatalf("unexpected range: %s - %s", gotReq.StartTime, gotReq.EndTime)
+	}
+	var series model.MarketHistorySeries
+	if err := json.Unmarshal(stdout.Bytes(), &series); err != nil {
+		t.Fatalf("failed to decode output: %v output=%s", err, stdout.String())
+	}
+	if series.Metric != "tvl_usd" || len(series.Points) != 1 {
+		t.Fatalf("unexpected series: %+v", series)
+	}
+
+	err := newRoot("history", "tvl", "--window", "7d").Execute()
+	if code := clierr.ExitCode(err); code != int(clierr.CodeUsage) {
+		t.Fatalf("expected usage error without --chain/--protocol, got %d (%v)", code, err)
+	}
+	err = newRoot("history", "tvl", "--chain", "1", "--interval", "hour").Execute()
+	if code := clierr.ExitCode(err); code != int(clierr.CodeUnsupported) {
+		t.Fatalf("expected unsupported error for hourly tvl, got %d (%v)", code, err)
 	}
 }
 
-func TestSwapSlippageOverrideRejectedForNonUniswap(t *testing.T) {
+func TestSwapSlippageOverrideSetsMinOutForAnyProvider(t *testing.T) {
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
 	oneinch := &fakeSwapProvider{name: "1inch"}
@@ -1631,20 +3319,32 @@ func TestSwapSlippageOverrideRejectedForNonUniswap(t *testing.T) {
 		"--amount", "1000000",
 		"--slippage-pct", "1.0",
 	})
-	if err := root.Execute(); err == nil {
-		t.Fatalf("expected validation error, stderr=%s", stderr.String())
+	if err := root.Execute(); err != nil {
+		t.Fatalf("swap command failed: %v stderr=%s", err, stderr.String())
 	}
-	if oneinch.calls != 0 {
-		t.Fatalf("expected provider not to be called with unsupported slippage override, got %d calls", oneinch.calls)
+	if oneinch.lastReq.SlippagePct == nil || *oneinch.lastReq.SlippagePct != 1 {
+		t.Fatalf("expected slippage override to reach 1inch, got %+v", oneinch.lastReq.SlippagePct)
+	}
+	var env struct {
+		Data model.SwapQuote `json:"data"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &env); err != nil {
+		t.Fatalf("decode output: %v output=%s", err, stdout.String())
+	}
+	if env.Data.SlippagePct != 1 || env.Data.MinOut == nil || env.Data.MinOut.AmountBaseUnits != "990000" {
+		t.Fatalf("expected min_out at 1%% slippage, got %+v", env.Data)
 	}
 }
 
 type fakeMarketProvider struct {
 	categories          []model.ProtocolCategory
+	protocols           []model.ProtocolTVL
 	chainAssets         []model.ChainAssetTVL
 	expectedAssetSymbol string
 	protocolFees        []model.ProtocolFees
 	protocolRevenue     []model.ProtocolRevenue
+	protocolVolume      []model.DexVolume
+	metricsReq          *providers.ProtocolMetricsRequest
 }
 
 func (f fakeMarketProvider) Info() model.ProviderInfo {
@@ -1671,7 +3371,7 @@ func (f fakeMarketProvider) ChainsAssets(ctx context.Context, chain id.Chain, as
 }
 
 func (f fakeMarketProvider) ProtocolsTop(context.Context, string, string, int) ([]model.ProtocolTVL, error) {
-	return nil, nil
+	return f.protocols, nil
 }
 
 func (f fakeMarketProvider) ProtocolsCategories(context.Context) ([]model.ProtocolCategory, error) {
@@ -1686,10 +3386,24 @@ func (f fakeMarketProvider) StablecoinChains(context.Context, int) ([]model.Stab
 	return nil, nil
 }
 
-func (f fakeMarketProvider) ProtocolsFees(context.Context, string, string, int) ([]model.ProtocolFees, error) {
+func (f fakeMarketProvider) StablecoinDetails(context.Context, string) (model.StablecoinDetails, error) {
+	return model.StablecoinDetails{}, nil
+}
+
+func (f fakeMarketProvider) ProtocolsFees(_ context.Context, req providers.ProtocolMetricsRequest) ([]model.ProtocolFees, error) {
+	if f.metricsReq != nil {
+		*f.metricsReq = req
+	}
 	return f.protocolFees, nil
 }
 
+func (f fakeMarketProvider) ProtocolsVolume(_ context.Context, req providers.ProtocolMetricsRequest) ([]model.DexVolume, error) {
+	if f.metricsReq != nil {
+		*f.metricsReq = req
+	}
+	return f.protocolVolume, nil
+}
+
 func (f fakeMarketProvider) ProtocolsRevenue(context.Context, string, string, int) ([]model.ProtocolRevenue, error) {
 	return f.protocolRevenue, nil
 }
@@ -1698,6 +3412,27 @@ func (f fakeMarketProvider) DexesVolume(context.Context, string, int) ([]model.D
 	return nil, nil
 }
 
+type fakeMarketHistoryProvider struct {
+	fakeMarketProvider
+	tvlReq *providers.TVLHistoryRequest
+}
+
+func (f fakeMarketHistoryProvider) TVLHistory(_ context.Context, req providers.TVLHistoryRequest) (model.MarketHistorySeries, error) {
+	*f.tvlReq = req
+	return model.MarketHistorySeries{
+		Metric:   "tvl_usd",
+		Interval: "day",
+		ChainID:  req.Chain.CAIP2,
+		Protocol: req.Protocol,
+		Points:   []model.MarketHistoryPoint{{Timestamp: req.EndTime.Format(time.RFC3339), Value: 1000}},
+		Provider: "fake-market",
+	}, nil
+}
+
+func (f fakeMarketHistoryProvider) PriceHistory(context.Context, providers.PriceHistoryRequest) (model.MarketHistorySeries, error) {
+	return model.MarketHistorySeries{}, clierr.New(clierr.CodeUnavailable, "not implemented")
+}
+
 type fakeSwapProvider struct {
 	name    string
 	calls   int
@@ -1742,6 +3477,8 @@ func (f *fakeSwapProvider) QuoteSwap(_ context.Context, req providers.SwapQuoteR
 
 type fakeLendingProvider struct {
 	name      string
+	markets   []model.LendMarket
+	rates     []model.LendRate
 	positions []model.LendPosition
 	err       error
 	calls     int
@@ -1758,11 +3495,11 @@ func (f *fakeLendingProvider) Info() model.ProviderInfo {
 }
 
 func (f *fakeLendingProvider) LendMarkets(context.Context, string, id.Chain, id.Asset) ([]model.LendMarket, error) {
-	return nil, nil
+	return f.markets, nil
 }
 
 func (f *fakeLendingProvider) LendRates(context.Context, string, id.Chain, id.Asset) ([]model.LendRate, error) {
-	return nil, nil
+	return f.rates, nil
 }
 
 func (f *fakeLendingProvider) LendPositions(_ context.Context, req providers.LendPositionsRequest) ([]model.LendPosition, error) {
@@ -1774,6 +3511,216 @@ func (f *fakeLendingProvider) LendPositions(_ context.Context, req providers.Len
 	return f.positions, nil
 }
 
+type fakeStakingProvider struct {
+	rates []model.StakeRate
+}
+
+func (f *fakeStakingProvider) Info() model.ProviderInfo {
+	return model.ProviderInfo{Name: "staking", Type: "yield", Capabilities: []string{"stake.rates"}}
+}
+
+func (f *fakeStakingProvider) StakeRates(context.Context, id.Chain) ([]model.StakeRate, error) {
+	return f.rates, nil
+}
+
+type fakePerpsProvider struct {
+	name    string
+	markets []model.PerpMarket
+	err     error
+}
+
+func (f *fakePerpsProvider) Info() model.ProviderInfo {
+	return model.ProviderInfo{Name: f.name, Type: "perps", Capabilities: []string{"perps.markets", "perps.rates"}}
+}
+
+func (f *fakePerpsProvider) PerpMarkets(context.Context) ([]model.PerpMarket, error) {
+	return f.markets, f.err
+}
+
+type fakeOptionsProvider struct {
+	name      string
+	contracts []model.OptionContract
+	err       error
+}
+
+func (f *fakeOptionsProvider) Info() model.ProviderInfo {
+	return model.ProviderInfo{Name: f.name, Type: "options", Capabilities: []string{"options.chains", "options.iv"}}
+}
+
+func (f *fakeOptionsProvider) OptionChain(context.Context, string) ([]model.OptionContract, error) {
+	return f.contracts, f.err
+}
+
+// fakeRouteBridgeProvider only bridges an asset into its own counterpart, so
+// direct cross-asset routes fail the way they do for Across.
+type fakeRouteBridgeProvider struct {
+	name       string
+	feeUSD     float64
+	outDecimal string
+}
+
+func (f *fakeRouteBridgeProvider) Info() model.ProviderInfo {
+	return model.ProviderInfo{Name: f.name, Type: "bridge", Capabilities: []string{"bridge.quote"}}
+}
+
+func (f *fakeRouteBridgeProvider) QuoteBridge(_ context.Context, req providers.BridgeQuoteRequest) (model.BridgeQuote, error) {
+	if !strings.EqualFold(req.FromAsset.Symbol, req.ToAsset.Symbol) {
+		return model.BridgeQuote{}, clierr.New(clierr.CodeUnsupported, "asset pair not supported")
+	}
+	base, decimal, err := id.NormalizeAmount("", f.outDecimal, req.ToAsset.Decimals)
+	if err != nil {
+		return model.BridgeQuote{}, err
+	}
+	return model.BridgeQuote{
+		Provider:        f.name,
+		FromChainID:     req.FromChain.CAIP2,
+		ToChainID:       req.ToChain.CAIP2,
+		FromAssetID:     req.FromAsset.AssetID,
+		ToAssetID:       req.ToAsset.AssetID,
+		InputAmount:     model.AmountInfo{AmountBaseUnits: req.AmountBaseUnits, AmountDecimal: req.AmountDecimal, Decimals: req.FromAsset.Decimals},
+		EstimatedOut:    model.AmountInfo{AmountBaseUnits: base, AmountDecimal: decimal, Decimals: req.ToAsset.Decimals},
+		EstimatedFeeUSD: f.feeUSD,
+		EstimatedTimeS:  60,
+		Route:           f.name,
+	}, nil
+}
+
+type fakeRouteSwapProvider struct {
+	name       string
+	outDecimal string
+	gasUSD     float64
+}
+
+func (f *fakeRouteSwapProvider) Info() model.ProviderInfo {
+	return model.ProviderInfo{Name: f.name, Type: "swap", Capabilities: []string{"swap.quote"}}
+}
+
+func (f *fakeRouteSwapProvider) QuoteSwap(_ context.Context, req providers.SwapQuoteRequest) (model.SwapQuote, error) {
+	base, decimal, err := id.NormalizeAmount("", f.outDecimal, req.ToAsset.Decimals)
+	if err != nil {
+		return model.SwapQuote{}, err
+	}
+	return model.SwapQuote{
+		Provider:        f.name,
+		ChainID:         req.Chain.CAIP2,
+		FromAssetID:     req.FromAsset.AssetID,
+		ToAssetID:       req.ToAsset.AssetID,
+		TradeType:       string(providers.SwapTradeTypeExactInput),
+		InputAmount:     model.AmountInfo{AmountBaseUnits: req.AmountBaseUnits, AmountDecimal: req.AmountDecimal, Decimals: req.FromAsset.Decimals},
+		EstimatedOut:    model.AmountInfo{AmountBaseUnits: base, AmountDecimal: decimal, Decimals: req.ToAsset.Decimals},
+		EstimatedGasUSD: f.gasUSD,
+		Route:           f.name,
+	}, nil
+}
+
+type fakeRoutePriceProvider struct {
+	fakeMarketProvider
+	priceUSD float64
+}
+
+func (f fakeRoutePriceProvider) TVLHistory(context.Context, providers.TVLHistoryRequest) (model.MarketHistorySeries, error) {
+	return model.MarketHistorySeries{}, clierr.New(clierr.CodeUnavailable, "not implemented")
+}
+
+func (f fakeRoutePriceProvider) PriceHistory(_ context.Context, req providers.PriceHistoryRequest) (model.MarketHistorySeries, error) {
+	return model.MarketHistorySeries{
+		Metric:   "price_usd",
+		Interval: string(req.Interval),
+		AssetID:  req.Asset.AssetID,
+		Points:   []model.MarketHistoryPoint{{Timestamp: req.EndTime.Format(time.RFC3339), Value: f.priceUSD}},
+		Provider: "fake-market",
+	}, nil
+}
+
+type fakeRewardsProvider struct {
+	rewards []model.ClaimableReward
+	lastReq providers.RewardsClaimableRequest
+}
+
+func (f *fakeRewardsProvider) Info() model.ProviderInfo {
+	return model.ProviderInfo{Name: "aave", Type: "lending"}
+}
+
+func (f *fakeRewardsProvider) ClaimableRewards(_ context.Context, req providers.RewardsClaimableRequest) ([]model.ClaimableReward, error) {
+	f.lastReq = req
+	return f.rewards, nil
+}
+
+type fakePairPriceProvider struct {
+	fakeMarketProvider
+	prices map[string][]float64
+}
+
+func (f fakePairPriceProvider) TVLHistory(context.Context, providers.TVLHistoryRequest) (model.MarketHistorySeries, error) {
+	return model.MarketHistorySeries{}, clierr.New(clierr.CodeUnavailable, "not implemented")
+}
+
+func (f fakePairPriceProvider) PriceHistory(_ context.Context, req providers.PriceHistoryRequest) (model.MarketHistorySeries, error) {
+	values := f.prices[req.Asset.Symbol]
+	points := make([]model.MarketHistoryPoint, 0, len(values))
+	for i, value := range values {
+		at := req.EndTime.Add(-time.Duration(len(values)-1-i) * 24 * time.Hour)
+		points = append(points, model.MarketHistoryPoint{Timestamp: at.Format(time.RFC3339), Value: value})
+	}
+	return model.MarketHistorySeries{Metric: "price_usd", AssetID: req.Asset.AssetID, Points: points, Provider: "fake-market"}, nil
+}
+
+type fakeLendingHealthProvider struct {
+	*fakeLendingProvider
+	healthFactors []*float64
+}
+
+func (f *fakeLendingHealthProvider) LendHealth(_ context.Context, req providers.LendHealthRequest) ([]model.LendHealth, error) {
+	healthFactor := f.healthFactors[min(f.calls, len(f.healthFactors)-1)]
+	f.calls++
+	return []model.LendHealth{{
+		Provider:       f.name,
+		ChainID:        req.Chain.CAIP2,
+		AccountAddress: req.Account,
+		MarketAddress:  "0x1111111111111111111111111111111111111111",
+		HealthFactor:   healthFactor,
+	}}, nil
+}
+
+type fakeBorrowQuoteProvider struct {
+	*fakeLendingProvider
+	quotes  []model.BorrowQuote
+	lastReq providers.BorrowQuoteRequest
+}
+
+func (f *fakeBorrowQuoteProvider) BorrowQuotes(_ context.Context, req providers.BorrowQuoteRequest) ([]model.BorrowQuote, error) {
+	f.lastReq = req
+	return f.quotes, nil
+}
+
+type fakeTVLChangeProvider struct {
+	fakeMarketProvider
+	changes map[string]float64
+}
+
+func (f fakeTVLChangeProvider) ChainsTVLChange(context.Context, []string, providers.MetricsWindow) (map[string]float64, error) {
+	return f.changes, nil
+}
+
+func (f fakeTVLChangeProvider) ProtocolsTVLChange(context.Context, string, []string, providers.MetricsWindow) (map[string]float64, error) {
+	return f.changes, nil
+}
+
+type fakeSecurityProvider struct {
+	fakeMarketProvider
+	security map[string]model.ProtocolSecurity
+	calls    int
+}
+
+func (f *fakeSecurityProvider) ProtocolSecurity(_ context.Context, protocol string) (model.ProtocolSecurity, error) {
+	f.calls++
+	data, ok := f.security[protocol]
+	if !ok {
+		return model.ProtocolSecurity{}, clierr.New(clierr.CodeUsage, "protocol not found: "+protocol)
+	}
+	return data, nil
+}
+
 type fakeLendingProviderNoPositions struct {
 	name string
 }
@@ -1945,6 +3892,92 @@ func TestLegacySubmitStillLoadsLocalSigner(t *testing.T) {
 	}
 }
 
+func TestSubmitEnforcesExecutionPolicyFile(t *testing.T) {
+	actionStorePath := filepath.Join(t.TempDir(), "actions.db")
+	actionLockPath := filepath.Join(t.TempDir(), "actions.lock")
+	t.Setenv("DEFI_ACTIONS_PATH", actionStorePath)
+	t.Setenv("DEFI_ACTIONS_LOCK_PATH", actionLockPath)
+	policyPath := filepath.Join(t.TempDir(), "policy.yaml")
+	if err := os.WriteFile(policyPath, []byte("require_simulation: true\n"), 0o600); err != nil {
+		t.Fatalf("write policy: %v", err)
+	}
+	t.Setenv("DEFI_POLICY_PATH", policyPath)
+
+	privateKeyHex := "ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80"
+	privateKey, err := crypto.HexToECDSA(privateKeyHex)
+	if err != nil {
+		t.Fatalf("parse private key: %v", err)
+	}
+	t.Setenv("DEFI_PRIVATE_KEY", privateKeyHex)
+
+	store, err := execution.OpenStore(actionStorePath, actionLockPath)
+	if err != nil {
+		t.Fatalf("open action store: %v", err)
+	}
+	defer store.Close()
+
+	action := execution.NewAction("act_fedcba9876543210fedcba9876543210", "transfer", "eip155:167000", execution.Constraints{Simulate: true})
+	action.FromAddress = crypto.PubkeyToAddress(privateKey.PublicKey).Hex()
+	action.ExecutionBackend = execution.ExecutionBackendLegacyLocal
+	action.Steps = append(action.Steps, execution.ActionStep{
+		StepID:  "transfer-1",
+		Type:    execution.StepTypeTransfer,
+		Status:  execution.StepStatusPending,
+		ChainID: "eip155:167000",
+		RPCURL:  "http://127.0.0.1:65535",
+		Target:  "0x00000000000000000000000000000000000000bb",
+		Data:    "0x",
+		Value:   "0",
+	})
+	if err := store.Save(action); err != nil {
+		t.Fatalf("save action: %v", err)
+	}
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	r := NewRunnerWithWriters(&stdout, &stderr)
+	code := r.Run([]string{
+		"transfer", "submit",
+		"--action-id", action.ActionID,
+		"--simulate=false",
+	})
+	if code != 22 {
+		t.Fatalf("expected action policy exit 22, got %d stderr=%s", code, stderr.String())
+	}
+	if !strings.Contains(stderr.String(), "action_policy_error") || !strings.Contains(stderr.String(), "requires simulation") {
+		t.Fatalf("expected simulation policy error, got stderr=%s", stderr.String())
+	}
+}
+
+func TestNewExecutionGuardrailsResolvesAssets(t *testing.T) {
+	guardrails, err := newExecutionGuardrails(policy.ExecutionFile{
+		AllowedChains:   []string{"base"},
+		AllowedSpenders: []string{"0x00000000000000000000000000000000000000bb"},
+		Tokens: []policy.ExecutionToken{
+			{Chain: "base", Asset: "USDC", MaxPerTx: "1000", MaxPerDay: "2500.5"},
+			{Chain: "base", Asset: "native", MaxPerTx: "0.1"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("newExecutionGuardrails failed: %v", err)
+	}
+	if len(guardrails.AllowedChains) != 1 || guardrails.AllowedChains[0] != "eip155:8453" {
+		t.Fatalf("unexpected chains: %v", guardrails.AllowedChains)
+	}
+	usdc := guardrails.Tokens[0]
+	if usdc.Token != "0x833589fcd6edb6e08f4c7c32d4f71b54bda02913" || usdc.MaxPerTx.String() != "1000000000" || usdc.MaxPerDay.String() != "2500500000" {
+		t.Fatalf("unexpected USDC guardrail: %+v", usdc)
+	}
+	native := guardrails.Tokens[1]
+	if native.Token != execution.NativeToken || native.MaxPerTx.String() != "100000000000000000" || native.MaxPerDay != nil {
+		t.Fatalf("unexpected native guardrail: %+v", native)
+	}
+
+	if _, err := newExecutionGuardrails(policy.ExecutionFile{AllowedSpenders: []string{"router"}}); err == nil {
+		t.Fatal("expected invalid spender to be rejected")
+	}
+}
+
 func TestLegacySubmitRejectsTempoSignerOverride(t *testing.T) {
 	actionStorePath := filepath.Join(t.TempDir(), "actions.db")
 	actionLockPath := filepath.Join(t.TempDir(), "actions.lock")
@@ -2043,3 +4076,114 @@ func TestResolveActionExecutionBackendOWSRejectsSenderMismatch(t *testing.T) {
 		t.Fatalf("expected wallet sender mismatch error, got %v", err)
 	}
 }
+
+func TestRankBridgeQuotesOrdersByOutputAndNamesLeaders(t *testing.T) {
+	quote := func(provider, out string, feeUSD float64, etaS int64) model.BridgeQuote {
+		return model.BridgeQuote{Provider: provider, EstimatedOut: model.AmountInfo{AmountDecimal: out}, EstimatedFeeUSD: feeUSD, EstimatedTimeS: etaS}
+	}
+	ranked := rankBridgeQuotes([]model.BridgeQuote{
+		quote("across", "999.2", 0.8, 4),
+		quote("cctp", "999.9", 0.1, 20),
+		quote("lifi", "998.5", 1.5, 0),
+	})
+	if ranked.Quotes[0].Provider != "cctp" || ranked.Quotes[2].Provider != "lifi" {
+		t.Fatalf("unexpected order: %+v", ranked.Quotes)
+	}
+	if ranked.BestOutputProvider != "cctp" || ranked.CheapestProvider != "cctp" || ranked.FastestProvider != "across" {
+		t.Fatalf("unexpected leaders: %+v", ranked)
+	}
+}
+
+func TestRunnerOpenCircuitSkipsProviderAndShowsHealth(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	cacheHome := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", cacheHome)
+	healthPath := filepath.Join(cacheHome, "defi", "provider-health.json")
+	saved := fmt.Sprintf(`[{"provider":"defillama","capability":"chains.top","failures":5,"last_error":"provider unavailable (status 503)","checked_at":%q,"open_until":%q}]`,
+		time.Now().UTC().Format(time.RFC3339), time.Now().Add(time.Hour).UTC().Format(time.RFC3339))
+	if err := os.MkdirAll(filepath.Dir(healthPath), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(healthPath, []byte(saved), 0o600); err != nil {
+		t.Fatalf("write health: %v", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	r := NewRunnerWithWriters(&stdout, &stderr)
+	if code := r.Run([]string{"chains", "top", "--limit", "1"}); code != 12 {
+		t.Fatalf("expected unavailable exit for open circuit, got %d stdout=%s stderr=%s", code, stdout.String(), stderr.String())
+	}
+	if !strings.Contains(stderr.String()+stdout.String(), "circuit open") {
+		t.Fatalf("expected circuit open error, got stdout=%s stderr=%s", stdout.String(), stderr.String())
+	}
+
+	stdout.Reset()
+	stderr.Reset()
+	if code := r.Run([]string{"providers", "health", "--results-only"}); code != 0 {
+		t.Fatalf("providers health failed: %d stderr=%s", code, stderr.String())
+	}
+	var rows []model.ProviderHealth
+	if err := json.Unmarshal(stdout.Bytes(), &rows); err != nil {
+		t.Fatalf("decode health: %v output=%s", err, stdout.String())
+	}
+	if len(rows) != 1 || rows[0].Provider != "defillama" || rows[0].Capability != "chains.top" || rows[0].State != "open" || rows[0].Failures != 5 {
+		t.Fatalf("unexpected health rows: %+v", rows)
+	}
+
+	stdout.Reset()
+	if code := r.Run([]string{"providers", "health", "--reset", "--provider", "defillama", "--results-only"}); code != 0 {
+		t.Fatalf("providers health --reset failed: %d stderr=%s", code, stderr.String())
+	}
+	if strings.TrimSpace(stdout.String()) != "[]" {
+		t.Fatalf("expected reset to clear circuits, got %s", stdout.String())
+	}
+	if buf, err := os.ReadFile(healthPath); err != nil || strings.TrimSpace(string(buf)) != "[]" {
+		t.Fatalf("expected reset to persist, got %s err=%v", buf, err)
+	}
+}
+
+func TestRunnerExhaustedBudgetSkipsProviderAndShowsHealth(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	cacheHome := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", cacheHome)
+	t.Cleanup(func() { ratebudget.Reset("") })
+	budgetsPath := filepath.Join(cacheHome, "defi", "provider-budgets.json")
+	saved := fmt.Sprintf(`[{"provider":"defillama","limit":300,"remaining":0,"reset_at":%q,"observed_at":%q}]`,
+		time.Now().Add(time.Hour).UTC().Format(time.RFC3339), time.Now().UTC().Format(time.RFC3339))
+	if err := os.MkdirAll(filepath.Dir(budgetsPath), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(budgetsPath, []byte(saved), 0o600); err != nil {
+		t.Fatalf("write budgets: %v", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	r := NewRunnerWithWriters(&stdout, &stderr)
+	if code := r.Run([]string{"chains", "top", "--limit", "1"}); code != 11 {
+		t.Fatalf("expected rate limited exit for an exhausted budget, got %d stdout=%s stderr=%s", code, stdout.String(), stderr.String())
+	}
+	if !strings.Contains(stderr.String()+stdout.String(), "rate limit budget exhausted") {
+		t.Fatalf("expected budget exhausted error, got stdout=%s stderr=%s", stdout.String(), stderr.String())
+	}
+
+	stdout.Reset()
+	stderr.Reset()
+	if code := r.Run([]string{"providers", "health", "--results-only"}); code != 0 {
+		t.Fatalf("providers health failed: %d stderr=%s", code, stderr.String())
+	}
+	var rows []model.ProviderHealth
+	if err := json.Unmarshal(stdout.Bytes(), &rows); err != nil {
+		t.Fatalf("decode health: %v output=%s", err, stdout.String())
+	}
+	if len(rows) != 1 || rows[0].Capability != "*" || rows[0].State != "closed" || rows[0].RateLimit == nil || rows[0].RateLimit.State != "exhausted" || rows[0].RateLimit.Limit != 300 {
+		t.Fatalf("expected a provider-wide exhausted budget row without a circuit failure, got %+v", rows)
+	}
+
+	stdout.Reset()
+	if code := r.Run([]string{"providers", "health", "--reset", "--results-only"}); code != 0 {
+		t.Fatalf("providers health --reset failed: %d stderr=%s", code, stderr.String())
+	}
+	if buf, err := os.ReadFile(budgetsPath); err != nil || strings.TrimSpace(string(buf)) != "[]" {
+		t.Fatalf("expected reset to clear persisted budgets, got %s err=%v", buf, err)
+	}
+}
@@ -0,0 +1,332 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/execution"
+	execsigner "github.com/ggonzalez94/defi-cli/internal/execution/signer"
+	"github.com/ggonzalez94/defi-cli/internal/schema"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+type scheduleRunArgs struct {
+	Name               string  `json:"name" flag:"name"`
+	Simulate           bool    `json:"simulate" flag:"simulate"`
+	Signer             string  `json:"signer" flag:"signer" enum:"local,tempo"`
+	KeySource          string  `json:"key_source" flag:"key-source" enum:"auto,env,file,keystore"`
+	PrivateKey         string  `json:"private_key" flag:"private-key" format:"hex"`
+	FromAddress        string  `json:"from_address" flag:"from-address" format:"evm-address"`
+	PollInterval       string  `json:"poll_interval" flag:"poll-interval" format:"duration"`
+	StepTimeout        string  `json:"step_timeout" flag:"step-timeout" format:"duration"`
+	GasMultiplier      float64 `json:"gas_multiplier" flag:"gas-multiplier"`
+	MaxFeeGwei         string  `json:"max_fee_gwei" flag:"max-fee-gwei"`
+	MaxPriorityFeeGwei string  `json:"max_priority_fee_gwei" flag:"max-priority-fee-gwei"`
+	AllowMaxApproval   bool    `json:"allow_max_approval" flag:"allow-max-approval"`
+	UnsafeProviderTx   bool    `json:"unsafe_provider_tx" flag:"unsafe-provider-tx"`
+	FeeToken           string  `json:"fee_token" flag:"fee-token" format:"evm-address"`
+}
+
+func (s *runtimeState) newScheduleCommand() *cobra.Command {
+	root := &cobra.Command{Use: "schedule", Short: "Recurring swap and lend actions (DCA)"}
+
+	var createActionID, createName, createEvery, createStartAt, createMaxPerRun, createMaxTotal string
+	var createMaxRuns int
+	createCmd := &cobra.Command{
+		Use:   "create",
+		Short: "Schedule a planned swap or lend action to repeat at a fixed interval",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			actionID, err := resolveActionID(createActionID)
+			if err != nil {
+				return err
+			}
+			name := strings.TrimSpace(createName)
+			if err := execution.ValidateTemplateName(name); err != nil {
+				return clierr.Wrap(clierr.CodeUsage, "invalid schedule name", err)
+			}
+			start := s.runner.now().UTC()
+			if value := strings.TrimSpace(createStartAt); value != "" {
+				start, err = time.Parse(time.RFC3339, value)
+				if err != nil {
+					return clierr.Wrap(clierr.CodeUsage, "--start-at must be an RFC3339 timestamp", err)
+				}
+			}
+			if err := s.ensureActionStore(); err != nil {
+				return err
+			}
+			action, err := s.actionStore.Get(actionID)
+			if err != nil {
+				return clierr.Wrap(clierr.CodeUsage, "load action", err)
+			}
+			schedule, err := execution.NewSchedule(name, action, createEvery, start, s.runner.now(), execution.ScheduleCaps{
+				MaxPerRun: createMaxPerRun,
+				MaxTotal:  createMaxTotal,
+				MaxRuns:   createMaxRuns,
+			})
+			if err != nil {
+				return clierr.Wrap(clierr.CodeUsage, "create schedule", err)
+			}
+			if err := s.actionStore.CreateSchedule(schedule); err != nil {
+				if errors.Is(err, execution.ErrScheduleExists) {
+					return clierr.Wrap(clierr.CodeUsage, "create schedule", err)
+				}
+				return clierr.Wrap(clierr.CodeInternal, "persist schedule", err)
+			}
+			var warnings []string
+			if createMaxPerRun == "" && createMaxTotal == "" && createMaxRuns == 0 {
+				warnings = append(warnings, "schedule has no spend cap; set --max-total or --max-runs to bound it")
+			}
+			return s.emitSuccess(trimRootPath(cmd.CommandPath()), schedule, warnings, cacheMetaBypass(), nil, false)
+		},
+	}
+	createCmd.Flags().StringVar(&createActionID, "from-action", "", "Planned swap or lend action identifier to repeat")
+	createCmd.Flags().StringVar(&createName, "name", "", "Schedule name (lowercase letters, digits, - and _)")
+	createCmd.Flags().StringVar(&createEvery, "every", "", "Repeat interval (e.g. 1h, 12h, 1d, 1w)")
+	createCmd.Flags().StringVar(&createStartAt, "start-at", "", "First run time as RFC3339 (defaults to now)")
+	createCmd.Flags().StringVar(&createMaxPerRun, "max-per-run", "", "Maximum input amount per run in base units")
+	createCmd.Flags().StringVar(&createMaxTotal, "max-total", "", "Maximum cumulative input amount across runs in base units")
+	createCmd.Flags().IntVar(&createMaxRuns, "max-runs", 0, "Maximum number of completed runs (0 = unlimited)")
+	_ = createCmd.MarkFlagRequired("from-action")
+	_ = createCmd.MarkFlagRequired("name")
+	_ = createCmd.MarkFlagRequired("every")
+	scheduleResponse := schema.SchemaFromType(execution.Schedule{})
+	_ = schema.SetCommandMetadata(createCmd, schema.CommandMetadata{Mutation: true, Response: &scheduleResponse})
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List schedules with spend and next run time",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if err := s.ensureActionStore(); err != nil {
+				return err
+			}
+			items, err := s.actionStore.ListSchedules()
+			if err != nil {
+				return clierr.Wrap(clierr.CodeInternal, "list schedules", err)
+			}
+			return s.emitSuccess(trimRootPath(cmd.CommandPath()), items, nil, cacheMetaBypass(), nil, false)
+		},
+	}
+	listResponse := schema.SchemaFromType([]execution.Schedule{})
+	_ = schema.SetCommandMetadata(listCmd, schema.CommandMetadata{Response: &listResponse})
+
+	var run scheduleRunArgs
+	runCmd := &cobra.Command{
+		Use:   "run",
+		Short: "Plan and submit every due schedule (run from cron or a systemd timer)",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if err := s.ensureActionStore(); err != nil {
+				return err
+			}
+			var schedules []execution.Schedule
+			if name := strings.TrimSpace(run.Name); name != "" {
+				schedule, err := s.actionStore.GetSchedule(name)
+				if err != nil {
+					if errors.Is(err, execution.ErrScheduleNotFound) {
+						return clierr.Wrap(clierr.CodeUsage, "load schedule", err)
+					}
+					return clierr.Wrap(clierr.CodeInternal, "load schedule", err)
+				}
+				schedules = append(schedules, schedule)
+			} else {
+				items, err := s.actionStore.ListSchedules()
+				if err != nil {
+					return clierr.Wrap(clierr.CodeInternal, "list schedules", err)
+				}
+				schedules = items
+			}
+
+			now := s.runner.now().UTC()
+			results := make([]execution.ScheduleRun, 0)
+			var warnings []string
+			partial := false
+			for _, schedule := range schedules {
+				if !schedule.Due(now) {
+					continue
+				}
+				// Claiming advances NextRunAt before anything is planned,
+				// so an overlapping invocation skips this slot.
+				claimed, ok, err := s.actionStore.ClaimSchedule(schedule, now)
+				if err != nil {
+					return clierr.Wrap(clierr.CodeInternal, "claim schedule", err)
+				}
+				if !ok {
+					continue
+				}
+				result, record, runErr := s.runSchedule(cmd, claimed, run, now)
+				updated, err := s.actionStore.ModifySchedule(claimed.Name, record)
+				if err != nil {
+					return clierr.Wrap(clierr.CodeInternal, "persist schedule", err)
+				}
+				result.Spent = updated.Spent
+				result.NextRunAt = updated.NextRunAt
+				if runErr != nil {
+					partial = true
+					warnings = append(warnings, fmt.Sprintf("schedule %s failed: %v", claimed.Name, runErr))
+				}
+				results = append(results, result)
+			}
+			return s.emitSuccess(trimRootPath(cmd.CommandPath()), results, warnings, cacheMetaBypass(), nil, partial)
+		},
+	}
+	runCmd.Flags().StringVar(&run.Name, "name", "", "Only run this schedule (still only when due)")
+	runCmd.Flags().BoolVar(&run.Simulate, "simulate", true, "Run preflight simulation before submission")
+	runCmd.Flags().StringVar(&run.Signer, "signer", "local", "Signer backend (local|tempo)")
+	runCmd.Flags().StringVar(&run.KeySource, "key-source", execsigner.KeySourceAuto, "Key source (auto|env|file|keystore)")
+	runCmd.Flags().StringVar(&run.PrivateKey, "private-key", "", "Private key hex override for local signer (less safe)")
+	runCmd.Flags().StringVar(&run.FromAddress, "from-address", "", "Expected sender EOA address")
+	runCmd.Flags().StringVar(&run.PollInterval, "poll-interval", "2s", "Receipt polling interval")
+	runCmd.Flags().StringVar(&run.StepTimeout, "step-timeout", "2m", "Timeout per wait stage (receipt or settlement polling)")
+	runCmd.Flags().Float64Var(&run.GasMultiplier, "gas-multiplier", 1.2, "Gas estimate safety multiplier")
+	runCmd.Flags().StringVar(&run.MaxFeeGwei, "max-fee-gwei", "", "Optional EIP-1559 max fee (gwei)")
+	runCmd.Flags().StringVar(&run.MaxPriorityFeeGwei, "max-priority-fee-gwei", "", "Optional EIP-1559 max priority fee (gwei)")
+	runCmd.Flags().BoolVar(&run.AllowMaxApproval, "allow-max-approval", false, "Allow approval amounts greater than planned input amount")
+	runCmd.Flags().BoolVar(&run.UnsafeProviderTx, "unsafe-provider-tx", false, "Bypass provider transaction guardrails for bridge/aggregator payloads")
+	runCmd.Flags().StringVar(&run.FeeToken, "fee-token", "", "Fee token address for Tempo chains (defaults to chain USDC.e)")
+	runResponse := schema.SchemaFromType([]execution.ScheduleRun{})
+	configureStructuredInput[scheduleRunArgs](runCmd, structuredInputOptions{
+		Mutation: true,
+		Auth:     executionSubmitAuthRequirements(),
+		Response: &runResponse,
+	})
+
+	deleteCmd := &cobra.Command{
+		Use:   "delete <name>",
+		Short: "Delete a schedule",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := s.ensureActionStore(); err != nil {
+				return err
+			}
+			name := strings.TrimSpace(args[0])
+			if err := s.actionStore.DeleteSchedule(name); err != nil {
+				if errors.Is(err, execution.ErrScheduleNotFound) {
+					return clierr.Wrap(clierr.CodeUsage, "delete schedule", err)
+				}
+				return clierr.Wrap(clierr.CodeInternal, "delete schedule", err)
+			}
+			return s.emitSuccess(trimRootPath(cmd.CommandPath()), map[string]any{"name": name, "deleted": true}, nil, cacheMetaBypass(), nil, false)
+		},
+	}
+	_ = schema.SetCommandMetadata(deleteCmd, schema.CommandMetadata{Mutation: true})
+
+	root.AddCommand(createCmd)
+	root.AddCommand(listCmd)
+	root.AddCommand(runCmd)
+	root.AddCommand(deleteCmd)
+	return root
+}
+
+// runSchedule plans one claimed schedule, checks the planned amount against
+// its caps, and submits it. It returns the outcome to book on the stored
+// schedule; the caller applies it to the latest row.
+func (s *runtimeState) runSchedule(cmd *cobra.Command, schedule execution.Schedule, run scheduleRunArgs, now time.Time) (execution.ScheduleRun, func(*execution.Schedule), error) {
+	result := execution.ScheduleRun{Name: schedule.Name}
+	finish := func(status, actionID string, record func(*execution.Schedule), err error) (execution.ScheduleRun, func(*execution.Schedule), error) {
+		result.Status = status
+		result.ActionID = actionID
+		if err != nil {
+			result.Error = err.Error()
+		}
+		return result, record, err
+	}
+	failed := func(action execution.Action, err error) func(*execution.Schedule) {
+		spent := ""
+		if action.Broadcast() {
+			spent = action.InputAmount
+		}
+		return func(current *execution.Schedule) { current.RecordFailure(now, action.ActionID, spent, err) }
+	}
+	if schedule.Exhausted() {
+		return finish(execution.ScheduleRunCapped, "", nil, nil)
+	}
+
+	flagValues, err := schedule.Template.Args(nil)
+	if err != nil {
+		return finish(execution.ScheduleRunFailed, "", failed(execution.Action{}, err), err)
+	}
+	action, err := s.planScheduledAction(cmd, schedule.Template, flagValues)
+	if err != nil {
+		return finish(execution.ScheduleRunFailed, "", failed(execution.Action{}, err), err)
+	}
+	result.Amount = action.InputAmount
+	if err := schedule.CheckSpend(action.InputAmount); err != nil {
+		return finish(execution.ScheduleRunCapped, action.ActionID, failed(action, err), nil)
+	}
+
+	resolvedExec, err := resolveActionExecutionBackend(cmd, action, submitExecutionInputs{
+		Signer:      run.Signer,
+		KeySource:   run.KeySource,
+		PrivateKey:  run.PrivateKey,
+		FromAddress: run.FromAddress,
+	})
+	if err == nil {
+		err = validateExecutionSender(action, run.FromAddress, resolvedExec.sender)
+	}
+	var execOpts execution.ExecuteOptions
+	if err == nil {
+		execOpts, err = parseExecuteOptions(
+			run.Simulate,
+			run.PollInterval,
+			run.StepTimeout,
+			run.GasMultiplier,
+			run.MaxFeeGwei,
+			run.MaxPriorityFeeGwei,
+			run.AllowMaxApproval,
+			run.UnsafeProviderTx,
+			run.FeeToken,
+		)
+	}
+	if err == nil {
+		err = s.executeActionWithTimeout(&action, resolvedExec.txSigner, resolvedExec.evmBackend, execOpts)
+	}
+	if err != nil {
+		return finish(execution.ScheduleRunFailed, action.ActionID, failed(action, err), err)
+	}
+	return finish(execution.ScheduleRunCompleted, action.ActionID, func(current *execution.Schedule) {
+		current.RecordRun(now, action.ActionID, action.InputAmount)
+	}, nil)
+}
+
+// planScheduledAction runs the template's plan command with its output
+// captured and returns the planned action. Flags left over from an earlier
+// schedule planned through the same command are reset first.
+func (s *runtimeState) planScheduledAction(cmd *cobra.Command, tmpl execution.Template, flagValues map[string]string) (execution.Action, error) {
+	if target, _, err := cmd.Root().Find(strings.Fields(tmpl.Command)); err == nil && target != nil {
+		target.Flags().VisitAll(func(flag *pflag.Flag) {
+			if !flag.Changed {
+				return
+			}
+			if slice, ok := flag.Value.(pflag.SliceValue); ok {
+				_ = slice.Replace(nil)
+			} else {
+				_ = flag.Value.Set(flag.DefValue)
+			}
+			flag.Changed = false
+		})
+	}
+
+	var buf bytes.Buffer
+	stdout, settings := s.runner.stdout, s.settings
+	s.runner.stdout = &buf
+	s.settings.OutputMode = "json"
+	s.settings.ResultsOnly = true
+	s.settings.Compact = false
+	s.settings.SelectFields = nil
+	s.settings.Query = ""
+	err := s.runTemplatePlan(cmd, tmpl, flagValues)
+	s.runner.stdout, s.settings = stdout, settings
+	if err != nil {
+		return execution.Action{}, err
+	}
+	var action execution.Action
+	if err := json.Unmarshal(buf.Bytes(), &action); err != nil {
+		return execution.Action{}, clierr.Wrap(clierr.CodeInternal, "decode scheduled plan", err)
+	}
+	return action, nil
+}
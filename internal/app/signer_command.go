@@ -0,0 +1,249 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/execution"
+	execsigner "github.com/ggonzalez94/defi-cli/internal/execution/signer"
+	"github.com/ggonzalez94/defi-cli/internal/fsutil"
+	"github.com/ggonzalez94/defi-cli/internal/policy"
+	"github.com/ggonzalez94/defi-cli/internal/schema"
+	"github.com/spf13/cobra"
+)
+
+// signerRotationPlannedScan bounds how many planned actions are checked for
+// references to the outgoing signer address.
+const signerRotationPlannedScan = 500
+
+func (s *runtimeState) newSignerCommand() *cobra.Command {
+	root := &cobra.Command{Use: "signer", Short: "Local signer key management"}
+
+	var keySource, privateKey, newKeySource, newKeyFile string
+	var dryRun bool
+	rotateCmd := &cobra.Command{
+		Use:   "rotate",
+		Short: "Replace the configured local signer key",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if strings.ToLower(strings.TrimSpace(newKeySource)) != execsigner.KeySourceFile {
+				return clierr.New(clierr.CodeUnsupported, "signer rotate supports only --new-key-source file")
+			}
+			if strings.TrimSpace(newKeyFile) == "" {
+				return clierr.New(clierr.CodeUsage, "--new-key-file is required")
+			}
+			newKeyPath, err := fsutil.NormalizePath(newKeyFile)
+			if err != nil {
+				return clierr.Wrap(clierr.CodeUsage, "normalize --new-key-file", err)
+			}
+			oldSigner, err := execsigner.NewLocalSignerFromInputs(keySource, privateKey)
+			if err != nil {
+				return clierr.Wrap(clierr.CodeSigner, "load current signer", err)
+			}
+			newSigner, err := execsigner.NewLocalSigner(execsigner.LocalSignerConfig{PrivateKeyFile: newKeyPath})
+			if err != nil {
+				return clierr.Wrap(clierr.CodeSigner, "load new signer", err)
+			}
+			if oldSigner.Address() == newSigner.Address() {
+				return clierr.New(clierr.CodeUsage, "new key controls the same address as the current signer")
+			}
+			keyFile, err := execsigner.ConfiguredPrivateKeyFile()
+			if err != nil {
+				return clierr.Wrap(clierr.CodeSigner, "resolve configured key file", err)
+			}
+
+			// Both keys sign the same statement; verifying the recovered
+			// addresses proves the new key is usable before it is installed.
+			now := s.runner.now().UTC()
+			message := execsigner.RotationMessage(oldSigner.Address(), newSigner.Address(), now)
+			oldSignature, err := oldSigner.SignMessage([]byte(message))
+			if err != nil {
+				return clierr.Wrap(clierr.CodeSigner, "sign rotation with current key", err)
+			}
+			newSignature, err := newSigner.SignMessage([]byte(message))
+			if err != nil {
+				return clierr.Wrap(clierr.CodeSigner, "sign rotation with new key", err)
+			}
+			if err := execsigner.VerifyMessage(oldSigner.Address(), []byte(message), oldSignature); err != nil {
+				return clierr.Wrap(clierr.CodeSigner, "verify current key signature", err)
+			}
+			if err := execsigner.VerifyMessage(newSigner.Address(), []byte(message), newSignature); err != nil {
+				return clierr.Wrap(clierr.CodeSigner, "verify new key signature", err)
+			}
+
+			result := execution.SignerRotation{
+				OldAddress:       oldSigner.Address().Hex(),
+				NewAddress:       newSigner.Address().Hex(),
+				KeyFile:          keyFile,
+				Message:          message,
+				OldSignature:     execsigner.EncodeSignature(oldSignature),
+				NewSignature:     execsigner.EncodeSignature(newSignature),
+				TemplatesUpdated: []string{},
+				SchedulesUpdated: []string{},
+				PolicyUpdated:    []string{},
+				DryRun:           dryRun,
+				RotatedAt:        now.Format(time.RFC3339),
+			}
+			// Protocol rules come from config, env, or flags, which this
+			// command does not own; refuse rather than leave them stale.
+			if refs := s.protocolRules().References(result.OldAddress); len(refs) > 0 {
+				return clierr.New(clierr.CodeUsage, fmt.Sprintf("protocol policy references %s (%s); update it before rotating", result.OldAddress, strings.Join(refs, "; ")))
+			}
+			policyPath := strings.TrimSpace(s.settings.PolicyPath)
+			var policyContent []byte
+			if policyPath != "" {
+				policyContent, result.PolicyUpdated, err = policy.ReplaceExecutionAddress(policyPath, result.OldAddress, result.NewAddress)
+				if err != nil {
+					return clierr.Wrap(clierr.CodeUsage, "rewrite execution policy", err)
+				}
+				if result.PolicyUpdated == nil {
+					result.PolicyUpdated = []string{}
+				}
+			}
+
+			if err := s.ensureActionStore(); err != nil {
+				return err
+			}
+			templates, err := s.actionStore.ListTemplates()
+			if err != nil {
+				return clierr.Wrap(clierr.CodeInternal, "list templates", err)
+			}
+			changed := make([]execution.Template, 0)
+			for _, tmpl := range templates {
+				if tmpl.ReplaceSender(result.OldAddress, result.NewAddress) {
+					changed = append(changed, tmpl)
+					result.TemplatesUpdated = append(result.TemplatesUpdated, tmpl.Name)
+				}
+			}
+			schedules, err := s.actionStore.ListSchedules()
+			if err != nil {
+				return clierr.Wrap(clierr.CodeInternal, "list schedules", err)
+			}
+			changedSchedules := make([]execution.Schedule, 0)
+			for _, schedule := range schedules {
+				if schedule.Template.ReplaceSender(result.OldAddress, result.NewAddress) {
+					changedSchedules = append(changedSchedules, schedule)
+					result.SchedulesUpdated = append(result.SchedulesUpdated, schedule.Name)
+				}
+			}
+
+			var warnings []string
+			planned, err := s.actionStore.List(string(execution.ActionStatusPlanned), signerRotationPlannedScan)
+			if err != nil {
+				return clierr.Wrap(clierr.CodeInternal, "list planned actions", err)
+			}
+			stale := 0
+			for _, action := range planned {
+				if strings.EqualFold(strings.TrimSpace(action.FromAddress), result.OldAddress) && action.ExecutionBackend != execution.ExecutionBackendOWS {
+					stale++
+				}
+			}
+			if stale > 0 {
+				warnings = append(warnings, fmt.Sprintf("%d planned actions still use %s as sender; re-plan them before submitting", stale, result.OldAddress))
+			}
+			if dryRun {
+				return s.emitSuccess(trimRootPath(cmd.CommandPath()), result, warnings, cacheMetaBypass(), nil, false)
+			}
+
+			// Templates, schedules, and the audit event are staged in one
+			// transaction; the policy file and then the key are written just
+			// before it commits, and both are restored if anything fails.
+			event := execution.AuditEvent{
+				Event:     execution.AuditEventSignerRotated,
+				CreatedAt: result.RotatedAt,
+				Details: map[string]string{
+					"old_address":       result.OldAddress,
+					"new_address":       result.NewAddress,
+					"key_file":          result.KeyFile,
+					"message":           result.Message,
+					"old_signature":     result.OldSignature,
+					"new_signature":     result.NewSignature,
+					"templates_updated": strings.Join(result.TemplatesUpdated, ","),
+					"schedules_updated": strings.Join(result.SchedulesUpdated, ","),
+					"policy_updated":    strings.Join(result.PolicyUpdated, ","),
+				},
+			}
+			var originalPolicy []byte
+			policyWritten, installed := false, false
+			restore := func() {
+				if installed {
+					_ = execsigner.RestorePrivateKeyFile(keyFile, result.BackupFile)
+				}
+				if policyWritten {
+					_ = policy.WriteExecutionFile(policyPath, originalPolicy)
+				}
+			}
+			err = s.actionStore.ApplySignerRotation(changed, changedSchedules, event, func(event *execution.AuditEvent) error {
+				if policyContent != nil {
+					buf, err := os.ReadFile(policyPath)
+					if err != nil {
+						return clierr.Wrap(clierr.CodeInternal, "read execution policy", err)
+					}
+					originalPolicy = buf
+					if err := policy.WriteExecutionFile(policyPath, policyContent); err != nil {
+						return clierr.Wrap(clierr.CodeInternal, "update execution policy", err)
+					}
+					policyWritten = true
+				}
+				backup, err := execsigner.InstallPrivateKeyFile(keyFile, newSigner.PrivateKey(), now)
+				if err != nil {
+					return clierr.Wrap(clierr.CodeSigner, "install new key", err)
+				}
+				installed = true
+				result.BackupFile = backup
+				event.Details["backup_file"] = backup
+				return nil
+			})
+			if err != nil {
+				restore()
+				if _, ok := clierr.As(err); ok {
+					return err
+				}
+				return clierr.Wrap(clierr.CodeInternal, "record signer rotation", err)
+			}
+			backup := result.BackupFile
+			if backup != "" {
+				warnings = append(warnings, fmt.Sprintf("previous key kept at %s; move it offline once funds are migrated", backup))
+			}
+			if newKeyPath != keyFile {
+				warnings = append(warnings, fmt.Sprintf("new key copied to %s; delete %s once it is backed up", keyFile, newKeyPath))
+			}
+			return s.emitSuccess(trimRootPath(cmd.CommandPath()), result, warnings, cacheMetaBypass(), nil, false)
+		},
+	}
+	rotateCmd.Flags().StringVar(&keySource, "key-source", execsigner.KeySourceAuto, "Current key source (auto|env|file|keystore)")
+	rotateCmd.Flags().StringVar(&privateKey, "private-key", "", "Current private key hex override (prefer env/file)")
+	rotateCmd.Flags().StringVar(&newKeySource, "new-key-source", "", "New key source (file)")
+	rotateCmd.Flags().StringVar(&newKeyFile, "new-key-file", "", "Path to the new private key hex file")
+	rotateCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Verify both keys and report changes without installing the new key")
+	_ = rotateCmd.MarkFlagRequired("new-key-source")
+	_ = schema.SetFlagMetadata(rotateCmd.Flags(), "key-source", schema.FlagMetadata{Enum: []string{execsigner.KeySourceAuto, execsigner.KeySourceEnv, execsigner.KeySourceFile, execsigner.KeySourceKeystore}})
+	_ = schema.SetFlagMetadata(rotateCmd.Flags(), "new-key-source", schema.FlagMetadata{Enum: []string{execsigner.KeySourceFile}})
+	rotateResponse := schema.SchemaFromType(execution.SignerRotation{})
+	_ = schema.SetCommandMetadata(rotateCmd, schema.CommandMetadata{Mutation: true, Response: &rotateResponse})
+
+	var historyLimit int
+	historyCmd := &cobra.Command{
+		Use:   "history",
+		Short: "List signer rotations recorded in the audit log",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if err := s.ensureActionStore(); err != nil {
+				return err
+			}
+			items, err := s.actionStore.ListAuditEvents(execution.AuditEventSignerRotated, historyLimit)
+			if err != nil {
+				return clierr.Wrap(clierr.CodeInternal, "list audit events", err)
+			}
+			return s.emitSuccess(trimRootPath(cmd.CommandPath()), items, nil, cacheMetaBypass(), nil, false)
+		},
+	}
+	historyCmd.Flags().IntVar(&historyLimit, "limit", 20, "Number of rotations to return")
+	historyResponse := schema.SchemaFromType([]execution.AuditEvent{})
+	_ = schema.SetCommandMetadata(historyCmd, schema.CommandMetadata{Response: &historyResponse})
+
+	root.AddCommand(rotateCmd)
+	root.AddCommand(historyCmd)
+	return root
+}
@@ -0,0 +1,86 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/model"
+	"github.com/ggonzalez94/defi-cli/internal/schema"
+	"github.com/spf13/cobra"
+)
+
+func (s *runtimeState) newStakeCommand() *cobra.Command {
+	root := &cobra.Command{Use: "stake", Short: "Liquid staking rates"}
+
+	var chainArg, protocolsArg string
+	ratesCmd := &cobra.Command{
+		Use:   "rates",
+		Short: "Show stETH, eETH, and rETH staking APRs and TVL",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			chain, err := id.ParseChain(chainArg)
+			if err != nil {
+				return err
+			}
+			protocols := splitCSV(protocolsArg)
+			for _, name := range protocols {
+				if err := s.protocolRules().Check(name); err != nil {
+					return err
+				}
+			}
+			if s.stakingProvider == nil {
+				return clierr.New(clierr.CodeUnsupported, "staking provider not configured")
+			}
+
+			key := cacheKey(trimRootPath(cmd.CommandPath()), map[string]any{
+				"chain":           chain.CAIP2,
+				"protocols":       protocols,
+				"allow_protocols": s.settings.AllowProtocols,
+				"deny_protocols":  s.settings.DenyProtocols,
+			})
+			return s.runCachedCommand(trimRootPath(cmd.CommandPath()), key, 60*time.Second, func(ctx context.Context) (any, []model.ProviderStatus, []string, bool, error) {
+				start := time.Now()
+				rates, err := s.stakingProvider.StakeRates(ctx, chain)
+				statuses := []model.ProviderStatus{{Name: s.stakingProvider.Info().Name, Status: statusFromErr(err), LatencyMS: time.Since(start).Milliseconds()}}
+				if err != nil {
+					return nil, statuses, nil, false, err
+				}
+
+				filter := map[string]struct{}{}
+				for _, name := range protocols {
+					filter[name] = struct{}{}
+				}
+				rules := s.protocolRules()
+				out := make([]model.StakeRate, 0, len(rates))
+				excluded := 0
+				for _, rate := range rates {
+					if _, ok := filter[rate.Protocol]; len(filter) > 0 && !ok {
+						continue
+					}
+					if rules.Check(rate.Provider, rate.Protocol) != nil {
+						excluded++
+						continue
+					}
+					out = append(out, rate)
+				}
+				warnings := []string{}
+				if excluded > 0 {
+					warnings = append(warnings, fmt.Sprintf("%d staking rates excluded by protocol policy", excluded))
+				}
+				if len(out) == 0 {
+					return nil, statuses, warnings, false, clierr.New(clierr.CodeUnavailable, "no staking rates for requested protocols")
+				}
+				return out, statuses, warnings, false, nil
+			})
+		},
+	}
+	ratesCmd.Flags().StringVar(&chainArg, "chain", "ethereum", "Chain identifier")
+	ratesCmd.Flags().StringVar(&protocolsArg, "protocols", "", "Filter by protocol (lido,etherfi,rocketpool)")
+	ratesResponse := schema.SchemaFromType([]model.StakeRate{})
+	_ = schema.SetCommandMetadata(ratesCmd, schema.CommandMetadata{Response: &ratesResponse})
+	root.AddCommand(ratesCmd)
+
+	return root
+}
@@ -194,6 +194,30 @@ func swapPlanIdentityInputConstraints() []schema.InputConstraint {
 			When:        map[string][]string{"provider": {"taikoswap"}},
 			Description: "TaikoSwap planning requires exactly one execution identity input: `wallet` (OWS, recommended) or `from_address` (local signer).",
 		},
+		{
+			Kind:        "exactly_one_of",
+			Fields:      []string{"wallet", "from_address"},
+			When:        map[string][]string{"provider": {"paraswap"}},
+			Description: "ParaSwap planning requires exactly one execution identity input: `wallet` (OWS, recommended) or `from_address` (local signer).",
+		},
+		{
+			Kind:        "exactly_one_of",
+			Fields:      []string{"wallet", "from_address"},
+			When:        map[string][]string{"provider": {"odos"}},
+			Description: "Odos planning requires exactly one execution identity input: `wallet` (OWS, recommended) or `from_address` (local signer).",
+		},
+		{
+			Kind:        "required",
+			Fields:      []string{"from_address"},
+			When:        map[string][]string{"provider": {"cowswap"}},
+			Description: "CoW Protocol orders are signed off-chain by the local signer; planning requires `from_address`.",
+		},
+		{
+			Kind:        "forbidden",
+			Fields:      []string{"wallet"},
+			When:        map[string][]string{"provider": {"cowswap"}},
+			Description: "CoW Protocol planning rejects `wallet`; OWS wallets cannot sign off-chain orders yet.",
+		},
 	}
 }
 
@@ -0,0 +1,251 @@
+package app
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/execution"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/model"
+	"github.com/ggonzalez94/defi-cli/internal/providers"
+	"github.com/spf13/cobra"
+)
+
+// actionMetaCrossChainSwap marks a bridge action planned by swap plan
+// --from-chain/--to-chain, so swap submit and status accept it.
+const actionMetaCrossChainSwap = "cross_chain_swap"
+
+// crossChainSwapQuoteInput holds the swap quote flags used by a cross-chain
+// quote.
+type crossChainSwapQuoteInput struct {
+	Provider      string
+	FromChain     string
+	ToChain       string
+	FromAsset     string
+	ToAsset       string
+	AmountBase    string
+	AmountDecimal string
+	AmountUSD     string
+	SlippagePct   *float64
+}
+
+// crossChainSwapPlanInput holds the swap plan flags used by a cross-chain
+// plan.
+type crossChainSwapPlanInput struct {
+	crossChainSwapQuoteInput
+	WalletRef   string
+	FromAddress string
+	Recipient   string
+	SlippageBps int64
+	Simulate    bool
+	Preview     bool
+	RPCURL      string
+}
+
+func isCrossChainSwapAction(action execution.Action) bool {
+	marked, _ := action.Metadata[actionMetaCrossChainSwap].(bool)
+	return action.IntentType == "bridge" && marked
+}
+
+// validateCrossChainSwapFlags checks the chain flags of swap quote and plan:
+// either --chain, or both --from-chain and --to-chain.
+func validateCrossChainSwapFlags(chainArg, fromChainArg, toChainArg string) (bool, error) {
+	chainArg, fromChainArg, toChainArg = strings.TrimSpace(chainArg), strings.TrimSpace(fromChainArg), strings.TrimSpace(toChainArg)
+	if fromChainArg == "" && toChainArg == "" {
+		if chainArg == "" {
+			return false, clierr.New(clierr.CodeUsage, "--chain is required (or --from-chain and --to-chain for a cross-chain swap)")
+		}
+		return false, nil
+	}
+	if chainArg != "" {
+		return false, clierr.New(clierr.CodeUsage, "use --chain for a single-chain swap or --from-chain and --to-chain for a cross-chain swap, not both")
+	}
+	if fromChainArg == "" || toChainArg == "" {
+		return false, clierr.New(clierr.CodeUsage, "cross-chain swaps require both --from-chain and --to-chain")
+	}
+	return true, nil
+}
+
+// crossChainSwapBridgeProvider returns the bridge provider that quotes
+// cross-chain swaps for name.
+func (s *runtimeState) crossChainSwapBridgeProvider(name string) (providers.BridgeProvider, error) {
+	switch name {
+	case "lifi", "bungee":
+	default:
+		return nil, clierr.New(clierr.CodeUnsupported, "cross-chain swap quotes support only --provider lifi or bungee")
+	}
+	provider, ok := s.bridgeProviders[name]
+	if !ok {
+		return nil, clierr.New(clierr.CodeUnsupported, "unsupported cross-chain swap provider")
+	}
+	if err := s.protocolRules().Check(name); err != nil {
+		return nil, err
+	}
+	return provider, nil
+}
+
+func (s *runtimeState) parseCrossChainSwapRequest(ctx context.Context, in crossChainSwapQuoteInput) (providers.BridgeQuoteRequest, float64, error) {
+	fromChain, err := id.ParseChain(in.FromChain)
+	if err != nil {
+		return providers.BridgeQuoteRequest{}, 0, err
+	}
+	toChain, err := id.ParseChain(in.ToChain)
+	if err != nil {
+		return providers.BridgeQuoteRequest{}, 0, err
+	}
+	if fromChain.CAIP2 == toChain.CAIP2 {
+		return providers.BridgeQuoteRequest{}, 0, clierr.New(clierr.CodeUsage, "--from-chain and --to-chain must differ; use --chain for a single-chain swap")
+	}
+	fromAsset, err := id.ParseAsset(in.FromAsset, fromChain)
+	if err != nil {
+		return providers.BridgeQuoteRequest{}, 0, err
+	}
+	toAsset, err := id.ParseAsset(in.ToAsset, toChain)
+	if err != nil {
+		return providers.BridgeQuoteRequest{}, 0, clierr.Wrap(clierr.CodeUsage, "resolve --to-asset on --to-chain", err)
+	}
+	decimals := fromAsset.Decimals
+	if decimals <= 0 {
+		decimals = 18
+	}
+	base, decimal, amountUSD, err := s.normalizeAmountInput(ctx, fromChain, fromAsset, in.AmountBase, in.AmountDecimal, in.AmountUSD, decimals)
+	if err != nil {
+		return providers.BridgeQuoteRequest{}, 0, err
+	}
+	return providers.BridgeQuoteRequest{
+		FromChain:       fromChain,
+		ToChain:         toChain,
+		FromAsset:       fromAsset,
+		ToAsset:         toAsset,
+		AmountBaseUnits: base,
+		AmountDecimal:   decimal,
+	}, amountUSD, nil
+}
+
+// quoteCrossChainSwap serves swap quote --from-chain/--to-chain from a bridge
+// provider route that bridges and swaps in one transfer.
+func (s *runtimeState) quoteCrossChainSwap(cmd *cobra.Command, in crossChainSwapQuoteInput) error {
+	provider, err := s.crossChainSwapBridgeProvider(in.Provider)
+	if err != nil {
+		return err
+	}
+	amountCtx, cancel := context.WithTimeout(context.Background(), s.settings.Timeout)
+	req, amountUSD, err := s.parseCrossChainSwapRequest(amountCtx, in)
+	cancel()
+	if err != nil {
+		return err
+	}
+	slippagePct := providers.DefaultSwapSlippagePct
+	if in.SlippagePct != nil {
+		slippagePct = *in.SlippagePct
+	}
+	key := cacheKey(trimRootPath(cmd.CommandPath()), map[string]any{
+		"provider":       in.Provider,
+		"from_chain":     req.FromChain.CAIP2,
+		"to_chain":       req.ToChain.CAIP2,
+		"from":           req.FromAsset.AssetID,
+		"to":             req.ToAsset.AssetID,
+		"amount":         req.AmountBaseUnits,
+		"amount_usd":     amountUSD,
+		"slippage_pct":   slippagePct,
+		"deny_protocols": s.settings.DenyProtocols,
+	})
+	return s.runCachedCommand(trimRootPath(cmd.CommandPath()), key, 15*time.Second, func(ctx context.Context) (any, []model.ProviderStatus, []string, bool, error) {
+		start := time.Now()
+		bridgeQuote, err := provider.QuoteBridge(ctx, req)
+		status := []model.ProviderStatus{{Name: provider.Info().Name, Status: statusFromErr(err), LatencyMS: time.Since(start).Milliseconds()}}
+		if err != nil {
+			return nil, status, nil, false, err
+		}
+		data := swapQuoteFromBridgeQuote(bridgeQuote)
+		if err := s.protocolRules().CheckRoute(data.Route); err != nil {
+			return nil, status, nil, false, err
+		}
+		data.InputAmount.AmountUSD = amountUSD
+		providers.ApplySwapSlippage(&data, slippagePct)
+		providers.ApplySwapQuoteExpiry(&data)
+		return data, status, nil, false, nil
+	})
+}
+
+// swapQuoteFromBridgeQuote reports a bridge+swap route in the swap quote
+// shape. Bridge fees are already reflected in the estimated output.
+func swapQuoteFromBridgeQuote(quote model.BridgeQuote) model.SwapQuote {
+	return model.SwapQuote{
+		Provider:        quote.Provider,
+		ChainID:         quote.FromChainID,
+		ToChainID:       quote.ToChainID,
+		FromAssetID:     quote.FromAssetID,
+		ToAssetID:       quote.ToAssetID,
+		TradeType:       string(providers.SwapTradeTypeExactInput),
+		InputAmount:     quote.InputAmount,
+		EstimatedOut:    quote.EstimatedOut,
+		EstimatedFeeUSD: quote.EstimatedFeeUSD,
+		EstimatedTimeS:  quote.EstimatedTimeS,
+		Route:           quote.Route,
+		SourceURL:       quote.SourceURL,
+		FetchedAt:       quote.FetchedAt,
+		ExpiresAt:       quote.ExpiresAt,
+		Firmness:        quote.Firmness,
+	}
+}
+
+// planCrossChainSwap serves swap plan --from-chain/--to-chain. The action is
+// a LiFi bridge action that swaps on the way, so it settles and reports
+// delivery like bridge plan; it is tagged so swap submit accepts it.
+func (s *runtimeState) planCrossChainSwap(cmd *cobra.Command, in crossChainSwapPlanInput) error {
+	if in.Provider != "lifi" {
+		return clierr.New(clierr.CodeUnsupported, "cross-chain swap planning supports only --provider lifi")
+	}
+	if err := s.protocolRules().Check(in.Provider); err != nil {
+		return err
+	}
+	identity, err := resolveExecutionIdentity(in.WalletRef, in.FromAddress, in.FromChain)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), s.settings.Timeout)
+	defer cancel()
+	req, amountUSD, err := s.parseCrossChainSwapRequest(ctx, in.crossChainSwapQuoteInput)
+	if err != nil {
+		return err
+	}
+	start := time.Now()
+	action, providerInfoName, err := s.actionBuilderRegistry().BuildBridgeAction(ctx, in.Provider, req, providers.BridgeExecutionOptions{
+		Sender:      identity.FromAddress,
+		Recipient:   in.Recipient,
+		SlippageBps: in.SlippageBps,
+		Simulate:    in.Simulate,
+		RPCURL:      in.RPCURL,
+	})
+	if strings.TrimSpace(providerInfoName) == "" {
+		providerInfoName = in.Provider
+	}
+	statuses := []model.ProviderStatus{{Name: providerInfoName, Status: statusFromErr(err), LatencyMS: time.Since(start).Milliseconds()}}
+	if err != nil {
+		s.captureCommandDiagnostics(nil, statuses, false)
+		return err
+	}
+	if action.Metadata == nil {
+		action.Metadata = map[string]any{}
+	}
+	action.Metadata[actionMetaCrossChainSwap] = true
+	applyExecutionIdentityToAction(&action, identity)
+	applyActionAmountUSD(&action, amountUSD)
+	if err := s.checkActionProtocolPolicy(action); err != nil {
+		return err
+	}
+	warnings := append(identity.Warnings, prefetchPlannedAction(ctx, &action)...)
+	if err := s.ensureActionStore(); err != nil {
+		return err
+	}
+	recordPlanInvocation(cmd, &action)
+	if err := s.actionStore.Save(action); err != nil {
+		return clierr.Wrap(clierr.CodeInternal, "persist planned action", err)
+	}
+	s.captureCommandDiagnostics(nil, statuses, false)
+	attachPlanPreview(&action, in.Preview)
+	return s.emitSuccess(trimRootPath(cmd.CommandPath()), action, warnings, cacheMetaBypass(), statuses, false)
+}
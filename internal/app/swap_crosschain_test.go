@@ -0,0 +1,106 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ggonzalez94/defi-cli/internal/config"
+	"github.com/ggonzalez94/defi-cli/internal/execution"
+	"github.com/ggonzalez94/defi-cli/internal/model"
+	"github.com/ggonzalez94/defi-cli/internal/providers"
+	"github.com/spf13/cobra"
+)
+
+type fakeCrossChainBridgeProvider struct {
+	name    string
+	lastReq providers.BridgeQuoteRequest
+}
+
+func (f *fakeCrossChainBridgeProvider) Info() model.ProviderInfo {
+	return model.ProviderInfo{Name: f.name, Type: "bridge", Capabilities: []string{"bridge.quote"}}
+}
+
+func (f *fakeCrossChainBridgeProvider) QuoteBridge(_ context.Context, req providers.BridgeQuoteRequest) (model.BridgeQuote, error) {
+	f.lastReq = req
+	return model.BridgeQuote{
+		Provider:        f.name,
+		FromChainID:     req.FromChain.CAIP2,
+		ToChainID:       req.ToChain.CAIP2,
+		FromAssetID:     req.FromAsset.AssetID,
+		ToAssetID:       req.ToAsset.AssetID,
+		InputAmount:     model.AmountInfo{AmountBaseUnits: req.AmountBaseUnits, AmountDecimal: req.AmountDecimal, Decimals: req.FromAsset.Decimals},
+		EstimatedOut:    model.AmountInfo{AmountBaseUnits: "300000000000000000", AmountDecimal: "0.3", Decimals: 18},
+		EstimatedFeeUSD: 2.5,
+		EstimatedTimeS:  90,
+		Route:           "stargate>uniswap",
+	}, nil
+}
+
+func TestRunnerSwapQuoteCrossChainUsesBridgeRoute(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	bridge := &fakeCrossChainBridgeProvider{name: "lifi"}
+	state := &runtimeState{
+		runner:          &Runner{stdout: &stdout, stderr: &stderr, now: time.Now},
+		settings:        config.Settings{OutputMode: "json", Timeout: 2 * time.Second},
+		bridgeProviders: map[string]providers.BridgeProvider{"lifi": bridge},
+		swapProviders:   map[string]providers.SwapProvider{},
+	}
+	root := &cobra.Command{Use: "defi"}
+	root.SilenceUsage = true
+	root.SilenceErrors = true
+	root.SetOut(&stdout)
+	root.SetErr(&stderr)
+	root.AddCommand(state.newSwapCommand())
+	root.SetArgs([]string{
+		"swap", "quote", "--provider", "lifi",
+		"--from-chain", "1", "--to-chain", "8453",
+		"--from-asset", "USDC", "--to-asset", "WETH", "--amount-decimal", "1000",
+		"--slippage-pct", "1",
+	})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("swap quote failed: %v stderr=%s", err, stderr.String())
+	}
+	var env struct {
+		Data model.SwapQuote `json:"data"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &env); err != nil {
+		t.Fatalf("failed to parse output json: %v output=%s", err, stdout.String())
+	}
+	if bridge.lastReq.ToChain.CAIP2 != "eip155:8453" || bridge.lastReq.ToAsset.Symbol != "WETH" || bridge.lastReq.AmountBaseUnits != "1000000000" {
+		t.Fatalf("expected WETH on Base in the bridge request, got %+v", bridge.lastReq)
+	}
+	got := env.Data
+	if got.ChainID != "eip155:1" || got.ToChainID != "eip155:8453" || got.EstimatedFeeUSD != 2.5 || got.EstimatedTimeS != 90 {
+		t.Fatalf("unexpected cross-chain quote %+v", got)
+	}
+	if got.MinOut == nil || got.MinOut.AmountBaseUnits != "297000000000000000" || got.SlippagePct != 1 {
+		t.Fatalf("expected min_out at 1%% slippage, got %+v", got.MinOut)
+	}
+}
+
+func TestValidateCrossChainSwapFlags(t *testing.T) {
+	if cross, err := validateCrossChainSwapFlags("base", "", ""); err != nil || cross {
+		t.Fatalf("expected a single-chain swap, got cross=%v err=%v", cross, err)
+	}
+	if cross, err := validateCrossChainSwapFlags("", "1", "8453"); err != nil || !cross {
+		t.Fatalf("expected a cross-chain swap, got cross=%v err=%v", cross, err)
+	}
+	for _, args := range [][3]string{{"", "", ""}, {"base", "1", "8453"}, {"", "1", ""}} {
+		if _, err := validateCrossChainSwapFlags(args[0], args[1], args[2]); err == nil {
+			t.Fatalf("expected %v to be rejected", args)
+		}
+	}
+}
+
+func TestIsCrossChainSwapActionRequiresBridgeIntentAndMarker(t *testing.T) {
+	marked := map[string]any{actionMetaCrossChainSwap: true}
+	if !isCrossChainSwapAction(execution.Action{IntentType: "bridge", Metadata: marked}) {
+		t.Fatal("expected a marked bridge action to be a cross-chain swap")
+	}
+	if isCrossChainSwapAction(execution.Action{IntentType: "bridge"}) || isCrossChainSwapAction(execution.Action{IntentType: "swap", Metadata: marked}) {
+		t.Fatal("expected unmarked bridges and swap intents not to be cross-chain swaps")
+	}
+}
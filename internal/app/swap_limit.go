@@ -0,0 +1,529 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/execution"
+	execsigner "github.com/ggonzalez94/defi-cli/internal/execution/signer"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/model"
+	"github.com/ggonzalez94/defi-cli/internal/providers"
+	"github.com/ggonzalez94/defi-cli/internal/registry"
+	"github.com/ggonzalez94/defi-cli/internal/schema"
+	"github.com/spf13/cobra"
+)
+
+// limitOrderActionScan bounds how many recent actions swap limit list
+// searches for limit orders.
+const limitOrderActionScan = 500
+
+func (s *runtimeState) newSwapLimitCommand() *cobra.Command {
+	root := &cobra.Command{Use: "limit", Short: "Place and track resting limit orders (1inch on EVM, Jupiter on Solana)"}
+	root.AddCommand(s.newSwapLimitCreateCommand())
+	root.AddCommand(s.newSwapLimitListCommand())
+	root.AddCommand(s.newSwapLimitStatusCommand())
+	root.AddCommand(s.newSwapLimitCancelCommand())
+	return root
+}
+
+func (s *runtimeState) newSwapLimitCreateCommand() *cobra.Command {
+	type limitCreateArgs struct {
+		Provider         string `json:"provider" flag:"provider" enum:"1inch,jupiter"`
+		ChainArg         string `json:"chain" flag:"chain" required:"true" format:"chain"`
+		FromAssetArg     string `json:"from_asset" flag:"from-asset" required:"true" format:"asset"`
+		ToAssetArg       string `json:"to_asset" flag:"to-asset" required:"true" format:"asset"`
+		AmountBase       string `json:"amount" flag:"amount" format:"base-units"`
+		AmountDecimal    string `json:"amount_decimal" flag:"amount-decimal" format:"decimal-amount"`
+		ToAmountBase     string `json:"to_amount" flag:"to-amount" format:"base-units"`
+		ToAmountDecimal  string `json:"to_amount_decimal" flag:"to-amount-decimal" format:"decimal-amount"`
+		ExpiresIn        string `json:"expires_in" flag:"expires-in" format:"duration"`
+		AllowPartialFill bool   `json:"allow_partial_fill" flag:"allow-partial-fill"`
+		FromAddress      string `json:"from_address" flag:"from-address" format:"address"`
+		Recipient        string `json:"recipient" flag:"recipient" format:"address"`
+		KeySource        string `json:"key_source" flag:"key-source" enum:"auto,env,file,keystore"`
+		PrivateKey       string `json:"private_key" flag:"private-key" format:"hex"`
+	}
+	var args limitCreateArgs
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Place a limit order that sells --amount of --from-asset for at least --to-amount of --to-asset",
+		Long:  "On EVM chains the order is signed with the local signer and posted to the 1inch Limit Order Protocol order book; the maker must approve the 1inch router for --from-asset. On Solana the order is created through Jupiter's Trigger API, which returns an unsigned transaction the maker signs and sends with their own wallet. The order is saved as a limit_order action for swap limit status and cancel.",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			chain, err := id.ParseChain(args.ChainArg)
+			if err != nil {
+				return err
+			}
+			providerName, err := limitOrderProviderForChain(args.Provider, chain)
+			if err != nil {
+				return err
+			}
+			provider, err := s.limitOrderProvider(providerName)
+			if err != nil {
+				return err
+			}
+			fromAsset, err := id.ParseAsset(args.FromAssetArg, chain)
+			if err != nil {
+				return err
+			}
+			toAsset, err := id.ParseAsset(args.ToAssetArg, chain)
+			if err != nil {
+				return err
+			}
+			making, _, err := id.NormalizeAmount(args.AmountBase, args.AmountDecimal, assetDecimals(fromAsset, chain))
+			if err != nil {
+				return err
+			}
+			taking, _, err := id.NormalizeAmount(args.ToAmountBase, args.ToAmountDecimal, assetDecimals(toAsset, chain))
+			if err != nil {
+				return clierr.Wrap(clierr.CodeUsage, "--to-amount or --to-amount-decimal is required", err)
+			}
+			expiresIn, err := time.ParseDuration(strings.TrimSpace(args.ExpiresIn))
+			if err != nil || expiresIn <= 0 {
+				return clierr.New(clierr.CodeUsage, "--expires-in must be a positive duration like 24h")
+			}
+
+			maker := strings.TrimSpace(args.FromAddress)
+			var sign providers.LimitOrderSigner
+			if chain.IsEVM() {
+				txSigner, err := newExecutionSigner("local", args.KeySource, args.PrivateKey)
+				if err != nil {
+					return err
+				}
+				hashSigner, ok := txSigner.(execsigner.HashSigner)
+				if !ok {
+					return clierr.New(clierr.CodeSigner, "signer cannot sign off-chain limit orders")
+				}
+				signerAddress := hashSigner.Address().Hex()
+				if maker != "" && !strings.EqualFold(common.HexToAddress(maker).Hex(), signerAddress) {
+					return clierr.New(clierr.CodeSigner, fmt.Sprintf("signer %s does not match --from-address %s", signerAddress, maker))
+				}
+				maker = signerAddress
+				sign = hashSigner.SignHash
+			} else if maker == "" {
+				return clierr.New(clierr.CodeUsage, "--from-address is required for Solana limit orders")
+			}
+
+			// Policy, guardrails, and the action record all come before the
+			// order is signed: once posted it is live on the order book, and
+			// only a saved action lets swap limit cancel reach it.
+			action := execution.NewAction(execution.NewActionID(), execution.IntentLimitOrder, chain.CAIP2, execution.Constraints{})
+			action.Provider = providerName
+			action.FromAddress = maker
+			action.ToAddress = strings.TrimSpace(args.Recipient)
+			action.InputAmount = making
+			action.Metadata = map[string]any{
+				"from_asset_id": fromAsset.AssetID,
+				"to_asset_id":   toAsset.AssetID,
+			}
+			if err := s.checkActionProtocolPolicy(action); err != nil {
+				return err
+			}
+			if err := s.ensureActionStore(); err != nil {
+				return err
+			}
+			guardrails, err := s.loadExecutionGuardrails()
+			if err != nil {
+				return err
+			}
+			if err := guardrails.CheckActionSpend(s.actionStore, &action); err != nil {
+				return err
+			}
+			recordPlanInvocation(cmd, &action)
+			if err := s.actionStore.Save(action); err != nil {
+				return clierr.Wrap(clierr.CodeInternal, "persist limit order", err)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), s.settings.Timeout)
+			defer cancel()
+			start := time.Now()
+			order, err := provider.CreateLimitOrder(ctx, providers.LimitOrderRequest{
+				Chain:            chain,
+				FromAsset:        fromAsset,
+				ToAsset:          toAsset,
+				MakingAmount:     making,
+				TakingAmount:     taking,
+				Maker:            maker,
+				Receiver:         args.Recipient,
+				ExpiresAt:        s.runner.now().Add(expiresIn),
+				AllowPartialFill: args.AllowPartialFill,
+			}, sign)
+			statuses := []model.ProviderStatus{{Name: provider.Info().Name, Status: statusFromErr(err), LatencyMS: time.Since(start).Milliseconds()}}
+			if err != nil {
+				s.captureCommandDiagnostics(nil, statuses, false)
+				action.Status = execution.ActionStatusFailed
+				action.Metadata["error"] = err.Error()
+				action.Touch()
+				if saveErr := s.actionStore.Save(action); saveErr != nil {
+					return clierr.Wrap(clierr.CodeInternal, "persist limit order", saveErr)
+				}
+				return err
+			}
+
+			action.ToAddress = order.Receiver
+			action.LimitOrder = &order
+			syncLimitOrderActionStatus(&action)
+			if err := s.actionStore.Save(action); err != nil {
+				return clierr.Wrap(clierr.CodeInternal, fmt.Sprintf("persist posted limit order %s", order.OrderID), err)
+			}
+			if err := execution.RecordActionSpend(s.actionStore, &action, s.runner.now()); err != nil {
+				return clierr.Wrap(clierr.CodeInternal, "record spend", err)
+			}
+			var warnings []string
+			if chain.IsEVM() {
+				if router, ok := registry.OneInchLimitOrderRouter(chain.EVMChainID); ok {
+					warnings = append(warnings, fmt.Sprintf("the order fills only while the 1inch router %s may spend %s; use approvals plan --spender %s if needed", router, fromAsset.Address, router))
+				}
+			}
+			return s.emitSuccess(trimRootPath(cmd.CommandPath()), action, warnings, cacheMetaBypass(), statuses, false)
+		},
+	}
+	cmd.Flags().StringVar(&args.Provider, "provider", "", "Limit order provider (1inch|jupiter; defaults by chain)")
+	cmd.Flags().StringVar(&args.ChainArg, "chain", "", "Chain identifier")
+	cmd.Flags().StringVar(&args.FromAssetArg, "from-asset", "", "Asset to sell")
+	cmd.Flags().StringVar(&args.ToAssetArg, "to-asset", "", "Asset to buy")
+	cmd.Flags().StringVar(&args.AmountBase, "amount", "", "Amount to sell in base units")
+	cmd.Flags().StringVar(&args.AmountDecimal, "amount-decimal", "", "Amount to sell in decimal units")
+	cmd.Flags().StringVar(&args.ToAmountBase, "to-amount", "", "Minimum amount to receive in base units")
+	cmd.Flags().StringVar(&args.ToAmountDecimal, "to-amount-decimal", "", "Minimum amount to receive in decimal units")
+	cmd.Flags().StringVar(&args.ExpiresIn, "expires-in", "24h", "Order lifetime")
+	cmd.Flags().BoolVar(&args.AllowPartialFill, "allow-partial-fill", true, "Allow the order to fill in parts (1inch only)")
+	cmd.Flags().StringVar(&args.FromAddress, "from-address", "", "Maker address (required on Solana; checked against the signer on EVM)")
+	cmd.Flags().StringVar(&args.Recipient, "recipient", "", "Address that receives the bought asset (defaults to the maker)")
+	cmd.Flags().StringVar(&args.KeySource, "key-source", execsigner.KeySourceAuto, "Key source (auto|env|file|keystore)")
+	cmd.Flags().StringVar(&args.PrivateKey, "private-key", "", "Private key hex override for local signer (less safe)")
+	_ = cmd.MarkFlagRequired("chain")
+	_ = cmd.MarkFlagRequired("from-asset")
+	_ = cmd.MarkFlagRequired("to-asset")
+	configureStructuredInput[limitCreateArgs](cmd, structuredInputOptions{
+		Mutation: true,
+		Auth:     executionSubmitAuthRequirements(),
+	})
+	return cmd
+}
+
+func (s *runtimeState) newSwapLimitListCommand() *cobra.Command {
+	var statusArg string
+	var limit int
+	var refresh bool
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List saved limit orders",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if limit <= 0 {
+				return clierr.New(clierr.CodeUsage, "--limit must be > 0")
+			}
+			statusArg = strings.ToLower(strings.TrimSpace(statusArg))
+			if err := s.ensureActionStore(); err != nil {
+				return err
+			}
+			actions, err := s.actionStore.List("", limitOrderActionScan)
+			if err != nil {
+				return clierr.Wrap(clierr.CodeInternal, "list actions", err)
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), s.settings.Timeout)
+			defer cancel()
+			orders := []execution.Action{}
+			var warnings []string
+			for _, action := range actions {
+				if action.IntentType != execution.IntentLimitOrder || action.LimitOrder == nil {
+					continue
+				}
+				if refresh && !action.LimitOrder.Final() {
+					if err := s.refreshLimitOrderAction(ctx, &action); err != nil {
+						warnings = append(warnings, fmt.Sprintf("refresh %s: %v", action.ActionID, err))
+					}
+				}
+				if statusArg != "" && action.LimitOrder.Status != statusArg {
+					continue
+				}
+				orders = append(orders, action)
+				if len(orders) >= limit {
+					break
+				}
+			}
+			return s.emitSuccess(trimRootPath(cmd.CommandPath()), orders, warnings, cacheMetaBypass(), nil, false)
+		},
+	}
+	cmd.Flags().StringVar(&statusArg, "status", "", "Filter by order status (pending_signature|open|partially_filled|filled|cancelled|expired)")
+	cmd.Flags().IntVar(&limit, "limit", 50, "Maximum orders to return")
+	cmd.Flags().BoolVar(&refresh, "refresh", false, "Poll the order book for orders that are not final before listing")
+	_ = schema.SetFlagMetadata(cmd.Flags(), "status", schema.FlagMetadata{Enum: []string{
+		execution.LimitOrderStatusPendingSignature,
+		execution.LimitOrderStatusOpen,
+		execution.LimitOrderStatusPartiallyFilled,
+		execution.LimitOrderStatusFilled,
+		execution.LimitOrderStatusCancelled,
+		execution.LimitOrderStatusExpired,
+	}})
+	response := schema.SchemaFromType([]execution.Action{})
+	_ = schema.SetCommandMetadata(cmd, schema.CommandMetadata{Response: &response})
+	return cmd
+}
+
+func (s *runtimeState) newSwapLimitStatusCommand() *cobra.Command {
+	var actionID, waitArg, pollArg string
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Poll the fill status of a limit order",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			wait, err := time.ParseDuration(strings.TrimSpace(waitArg))
+			if err != nil || wait < 0 {
+				return clierr.New(clierr.CodeUsage, "--wait must be a non-negative duration")
+			}
+			poll, err := time.ParseDuration(strings.TrimSpace(pollArg))
+			if err != nil || poll <= 0 {
+				return clierr.New(clierr.CodeUsage, "--poll-interval must be a positive duration")
+			}
+			action, err := s.loadLimitOrderAction(actionID)
+			if err != nil {
+				return err
+			}
+			deadline := time.Now().Add(wait)
+			for {
+				if !action.LimitOrder.Final() {
+					ctx, cancel := context.WithTimeout(context.Background(), s.settings.Timeout)
+					err := s.refreshLimitOrderAction(ctx, &action)
+					cancel()
+					if err != nil {
+						return err
+					}
+				}
+				if action.LimitOrder.Final() || !time.Now().Add(poll).Before(deadline) {
+					break
+				}
+				time.Sleep(poll)
+			}
+			return s.emitSuccess(trimRootPath(cmd.CommandPath()), action, nil, cacheMetaBypass(), nil, false)
+		},
+	}
+	cmd.Flags().StringVar(&actionID, "action-id", "", "Action identifier returned by swap limit create")
+	cmd.Flags().StringVar(&waitArg, "wait", "0s", "Keep polling until the order is final or this long has passed")
+	cmd.Flags().StringVar(&pollArg, "poll-interval", "15s", "Polling interval while waiting")
+	_ = schema.SetFlagMetadata(cmd.Flags(), "wait", schema.FlagMetadata{Format: "duration"})
+	_ = schema.SetFlagMetadata(cmd.Flags(), "poll-interval", schema.FlagMetadata{Format: "duration"})
+	annotateExecutionStatusCommand(cmd)
+	return cmd
+}
+
+func (s *runtimeState) newSwapLimitCancelCommand() *cobra.Command {
+	type limitCancelArgs struct {
+		ActionID           string  `json:"action_id" flag:"action-id" required:"true" format:"action-id"`
+		RPCURL             string  `json:"rpc_url" flag:"rpc-url" format:"url"`
+		Simulate           bool    `json:"simulate" flag:"simulate"`
+		KeySource          string  `json:"key_source" flag:"key-source" enum:"auto,env,file,keystore"`
+		PrivateKey         string  `json:"private_key" flag:"private-key" format:"hex"`
+		PollInterval       string  `json:"poll_interval" flag:"poll-interval" format:"duration"`
+		StepTimeout        string  `json:"step_timeout" flag:"step-timeout" format:"duration"`
+		GasMultiplier      float64 `json:"gas_multiplier" flag:"gas-multiplier"`
+		MaxFeeGwei         string  `json:"max_fee_gwei" flag:"max-fee-gwei"`
+		MaxPriorityFeeGwei string  `json:"max_priority_fee_gwei" flag:"max-priority-fee-gwei"`
+	}
+	var args limitCancelArgs
+	cmd := &cobra.Command{
+		Use:   "cancel",
+		Short: "Cancel a limit order",
+		Long:  "1inch orders are cancelled on-chain with a cancelOrder transaction sent by the maker. Jupiter orders return an unsigned cancel transaction for the maker to sign and send; run swap limit status afterwards to confirm.",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			action, err := s.loadLimitOrderAction(args.ActionID)
+			if err != nil {
+				return err
+			}
+			if action.LimitOrder.Final() {
+				return clierr.New(clierr.CodeUsage, fmt.Sprintf("limit order is already %s", action.LimitOrder.Status))
+			}
+			provider, err := s.limitOrderProvider(action.Provider)
+			if err != nil {
+				return err
+			}
+			chain, err := id.ParseChain(action.ChainID)
+			if err != nil {
+				return clierr.Wrap(clierr.CodeUsage, "limit order has invalid chain_id", err)
+			}
+			// A cancel step left by an earlier failed or interrupted run is
+			// retried rather than planned again.
+			var steps []execution.ActionStep
+			if !hasCancelOrderStep(action) {
+				ctx, cancel := context.WithTimeout(context.Background(), s.settings.Timeout)
+				steps, err = provider.CancelLimitOrder(ctx, chain, action.LimitOrder)
+				cancel()
+				if err != nil {
+					return err
+				}
+			}
+			if len(steps) == 0 && !hasCancelOrderStep(action) {
+				if err := s.actionStore.Save(action); err != nil {
+					return clierr.Wrap(clierr.CodeInternal, "persist limit order", err)
+				}
+				return s.emitSuccess(trimRootPath(cmd.CommandPath()), action, []string{"sign and send limit_order.unsigned_transaction with the maker wallet to cancel the order"}, cacheMetaBypass(), nil, false)
+			}
+			if rpcURL := strings.TrimSpace(args.RPCURL); rpcURL != "" {
+				for i := range steps {
+					steps[i].RPCURL = rpcURL
+				}
+			}
+			action.Steps = append(action.Steps, steps...)
+			action.Constraints.Simulate = args.Simulate
+
+			resolvedExec, err := resolveActionExecutionBackend(cmd, action, submitExecutionInputs{
+				Signer:     "local",
+				KeySource:  args.KeySource,
+				PrivateKey: args.PrivateKey,
+			})
+			if err != nil {
+				return err
+			}
+			if err := validateExecutionSender(action, "", resolvedExec.sender); err != nil {
+				return err
+			}
+			execOpts, err := parseExecuteOptions(
+				args.Simulate,
+				args.PollInterval,
+				args.StepTimeout,
+				args.GasMultiplier,
+				args.MaxFeeGwei,
+				args.MaxPriorityFeeGwei,
+				false,
+				false,
+				"",
+			)
+			if err != nil {
+				return err
+			}
+			if err := s.executeActionWithTimeout(&action, resolvedExec.txSigner, resolvedExec.evmBackend, execOpts); err != nil {
+				return err
+			}
+			action.LimitOrder.Status = execution.LimitOrderStatusCancelled
+			action.LimitOrder.StatusReason = ""
+			action.LimitOrder.CheckedAt = s.runner.now().UTC().Format(time.RFC3339)
+			syncLimitOrderActionStatus(&action)
+			if err := s.actionStore.Save(action); err != nil {
+				return clierr.Wrap(clierr.CodeInternal, "persist limit order", err)
+			}
+			return s.emitSuccess(trimRootPath(cmd.CommandPath()), action, nil, cacheMetaBypass(), nil, false)
+		},
+	}
+	cmd.Flags().StringVar(&args.ActionID, "action-id", "", "Action identifier returned by swap limit create")
+	cmd.Flags().StringVar(&args.RPCURL, "rpc-url", "", "RPC URL override for the cancel transaction")
+	cmd.Flags().BoolVar(&args.Simulate, "simulate", true, "Run preflight simulation before submitting")
+	cmd.Flags().StringVar(&args.KeySource, "key-source", execsigner.KeySourceAuto, "Key source (auto|env|file|keystore)")
+	cmd.Flags().StringVar(&args.PrivateKey, "private-key", "", "Private key hex override for local signer (less safe)")
+	cmd.Flags().StringVar(&args.PollInterval, "poll-interval", "2s", "Receipt polling interval")
+	cmd.Flags().StringVar(&args.StepTimeout, "step-timeout", "2m", "Per-step receipt timeout")
+	cmd.Flags().Float64Var(&args.GasMultiplier, "gas-multiplier", 1.2, "Gas estimate safety multiplier")
+	cmd.Flags().StringVar(&args.MaxFeeGwei, "max-fee-gwei", "", "Optional EIP-1559 max fee (gwei)")
+	cmd.Flags().StringVar(&args.MaxPriorityFeeGwei, "max-priority-fee-gwei", "", "Optional EIP-1559 max priority fee (gwei)")
+	_ = cmd.MarkFlagRequired("action-id")
+	configureStructuredInput[limitCancelArgs](cmd, structuredInputOptions{
+		Mutation: true,
+		Auth:     executionSubmitAuthRequirements(),
+	})
+	return cmd
+}
+
+func hasCancelOrderStep(action execution.Action) bool {
+	for _, step := range action.Steps {
+		if step.Type == execution.StepTypeCancelOrder {
+			return true
+		}
+	}
+	return false
+}
+
+// limitOrderProviderForChain resolves --provider, defaulting to 1inch on EVM
+// chains and Jupiter on Solana.
+func limitOrderProviderForChain(providerArg string, chain id.Chain) (string, error) {
+	name := providers.NormalizeSwapProvider(providerArg)
+	if name == "" {
+		switch {
+		case chain.IsEVM():
+			name = "1inch"
+		case chain.IsSolana():
+			name = "jupiter"
+		default:
+			return "", clierr.New(clierr.CodeUnsupported, "limit orders support EVM chains and Solana")
+		}
+	}
+	switch {
+	case name == "1inch" && !chain.IsEVM():
+		return "", clierr.New(clierr.CodeUnsupported, "1inch limit orders support only EVM chains")
+	case name == "jupiter" && !chain.IsSolana():
+		return "", clierr.New(clierr.CodeUnsupported, "jupiter limit orders support only Solana")
+	}
+	return name, nil
+}
+
+func (s *runtimeState) limitOrderProvider(name string) (providers.LimitOrderProvider, error) {
+	provider, ok := s.swapProviders[name].(providers.LimitOrderProvider)
+	if !ok {
+		return nil, clierr.New(clierr.CodeUnsupported, fmt.Sprintf("provider %q does not support limit orders (expected 1inch|jupiter)", name))
+	}
+	return provider, nil
+}
+
+func (s *runtimeState) loadLimitOrderAction(actionID string) (execution.Action, error) {
+	if strings.TrimSpace(actionID) == "" {
+		return execution.Action{}, clierr.New(clierr.CodeUsage, "--action-id is required")
+	}
+	if err := s.ensureActionStore(); err != nil {
+		return execution.Action{}, err
+	}
+	action, err := s.actionStore.Get(actionID)
+	if err != nil {
+		return execution.Action{}, clierr.Wrap(clierr.CodeUsage, "load action", err)
+	}
+	if action.IntentType != execution.IntentLimitOrder || action.LimitOrder == nil {
+		return execution.Action{}, clierr.New(clierr.CodeUsage, "action is not a limit order")
+	}
+	return action, nil
+}
+
+// refreshLimitOrderAction polls the order book for the action's order and
+// persists the new status.
+func (s *runtimeState) refreshLimitOrderAction(ctx context.Context, action *execution.Action) error {
+	provider, err := s.limitOrderProvider(action.Provider)
+	if err != nil {
+		return err
+	}
+	chain, err := id.ParseChain(action.ChainID)
+	if err != nil {
+		return clierr.Wrap(clierr.CodeUsage, "limit order has invalid chain_id", err)
+	}
+	if err := provider.RefreshLimitOrder(ctx, chain, action.LimitOrder); err != nil {
+		return err
+	}
+	syncLimitOrderActionStatus(action)
+	if err := s.actionStore.Save(*action); err != nil {
+		return clierr.Wrap(clierr.CodeInternal, "persist limit order", err)
+	}
+	return nil
+}
+
+// syncLimitOrderActionStatus keeps the action status in step with its order:
+// the action runs while the order can fill and completes once it is final.
+// limit_order.status carries the outcome.
+func syncLimitOrderActionStatus(action *execution.Action) {
+	if action.LimitOrder == nil {
+		return
+	}
+	if action.LimitOrder.Final() {
+		action.Status = execution.ActionStatusCompleted
+	} else {
+		action.Status = execution.ActionStatusRunning
+	}
+	action.Touch()
+}
+
+func assetDecimals(asset id.Asset, chain id.Chain) int {
+	if asset.Decimals > 0 {
+		return asset.Decimals
+	}
+	if chain.IsSolana() {
+		return 9
+	}
+	return 18
+}
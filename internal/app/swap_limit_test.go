@@ -0,0 +1,145 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/execution"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/model"
+	"github.com/ggonzalez94/defi-cli/internal/providers"
+	"github.com/spf13/cobra"
+)
+
+// stubLimitOrderProvider records CreateLimitOrder calls and the limit order
+// actions saved when each call is made.
+type stubLimitOrderProvider struct {
+	state   *runtimeState
+	err     error
+	calls   int
+	present []execution.ActionStatus
+}
+
+func (p *stubLimitOrderProvider) Info() model.ProviderInfo {
+	return model.ProviderInfo{Name: "1inch"}
+}
+
+func (p *stubLimitOrderProvider) QuoteSwap(context.Context, providers.SwapQuoteRequest) (model.SwapQuote, error) {
+	return model.SwapQuote{}, nil
+}
+
+func (p *stubLimitOrderProvider) CreateLimitOrder(_ context.Context, req providers.LimitOrderRequest, _ providers.LimitOrderSigner) (execution.LimitOrder, error) {
+	p.calls++
+	if actions, err := p.state.actionStore.List("", 10); err == nil {
+		for _, action := range actions {
+			p.present = append(p.present, action.Status)
+		}
+	}
+	if p.err != nil {
+		return execution.LimitOrder{}, p.err
+	}
+	return execution.LimitOrder{
+		Protocol:     "1inch",
+		OrderID:      "0xorder",
+		Maker:        req.Maker,
+		MakerAsset:   req.FromAsset.AssetID,
+		TakerAsset:   req.ToAsset.AssetID,
+		MakingAmount: req.MakingAmount,
+		TakingAmount: req.TakingAmount,
+		Status:       execution.LimitOrderStatusOpen,
+	}, nil
+}
+
+func (p *stubLimitOrderProvider) RefreshLimitOrder(context.Context, id.Chain, *execution.LimitOrder) error {
+	return nil
+}
+
+func (p *stubLimitOrderProvider) CancelLimitOrder(context.Context, id.Chain, *execution.LimitOrder) ([]execution.ActionStep, error) {
+	return nil, nil
+}
+
+func TestSwapLimitCreateChecksPolicyAndSavesBeforePosting(t *testing.T) {
+	t.Setenv("DEFI_PRIVATE_KEY", "59c6995e998f97a5a0044976f0945388cf9b7e5e5f4f9d2d9d8f1f5b7f6d11d1")
+	policyPath := filepath.Join(t.TempDir(), "policy.yaml")
+	if err := os.WriteFile(policyPath, []byte("tokens:\n  - chain: base\n    asset: USDC\n    max_per_tx: \"10\"\n"), 0o600); err != nil {
+		t.Fatalf("write policy: %v", err)
+	}
+
+	newState := func(t *testing.T) (*runtimeState, *stubLimitOrderProvider) {
+		state, _, _ := newExecutionTestState(filepath.Join(t.TempDir(), "actions.db"), filepath.Join(t.TempDir(), "actions.lock"))
+		state.settings.PolicyPath = policyPath
+		provider := &stubLimitOrderProvider{state: state}
+		state.swapProviders = map[string]providers.SwapProvider{"1inch": provider}
+		t.Cleanup(func() {
+			if state.actionStore != nil {
+				_ = state.actionStore.Close()
+			}
+		})
+		return state, provider
+	}
+	create := func(state *runtimeState, amount string) error {
+		root := &cobra.Command{Use: "defi", SilenceErrors: true, SilenceUsage: true}
+		root.AddCommand(state.newSwapLimitCreateCommand())
+		root.SetArgs([]string{"create", "--chain", "base", "--from-asset", "USDC", "--to-asset", "WETH", "--amount-decimal", amount, "--to-amount-decimal", "0.001"})
+		return root.Execute()
+	}
+
+	t.Run("denied provider", func(t *testing.T) {
+		state, provider := newState(t)
+		state.settings.DenyProtocols = []string{"1inch"}
+		err := create(state, "5")
+		if typed, ok := clierr.As(err); !ok || typed.Code != clierr.CodeBlocked {
+			t.Fatalf("expected protocol policy error, got %v", err)
+		}
+		if provider.calls != 0 {
+			t.Fatalf("expected no order to be posted, got %d calls", provider.calls)
+		}
+	})
+
+	t.Run("over max_per_tx", func(t *testing.T) {
+		state, provider := newState(t)
+		err := create(state, "50")
+		if typed, ok := clierr.As(err); !ok || typed.Code != clierr.CodeActionPolicy || !strings.Contains(err.Error(), "per transaction") {
+			t.Fatalf("expected max_per_tx violation, got %v", err)
+		}
+		if provider.calls != 0 {
+			t.Fatalf("expected no order to be posted, got %d calls", provider.calls)
+		}
+	})
+
+	t.Run("posted", func(t *testing.T) {
+		state, provider := newState(t)
+		if err := create(state, "5"); err != nil {
+			t.Fatalf("limit create failed: %v", err)
+		}
+		if provider.calls != 1 || len(provider.present) != 1 || provider.present[0] != execution.ActionStatusPlanned {
+			t.Fatalf("expected a planned action to be saved before posting, got %+v", provider.present)
+		}
+		actions, err := state.actionStore.List("", 10)
+		if err != nil || len(actions) != 1 || actions[0].Status != execution.ActionStatusRunning || actions[0].LimitOrder == nil {
+			t.Fatalf("expected the posted order to be saved, got %+v err=%v", actions, err)
+		}
+		spent, err := state.actionStore.SpentSince("eip155:8453", "0x833589fcd6edb6e08f4c7c32d4f71b54bda02913", time.Now().Add(-time.Hour))
+		if err != nil || spent.String() != "5000000" {
+			t.Fatalf("expected the order to count toward daily spend, got %v err=%v", spent, err)
+		}
+	})
+
+	t.Run("post failure", func(t *testing.T) {
+		state, provider := newState(t)
+		provider.err = errors.New("order book unavailable")
+		if err := create(state, "5"); err == nil {
+			t.Fatal("expected the post error to be returned")
+		}
+		actions, err := state.actionStore.List("", 10)
+		if err != nil || len(actions) != 1 || actions[0].Status != execution.ActionStatusFailed {
+			t.Fatalf("expected the failed order to be recorded, got %+v err=%v", actions, err)
+		}
+	})
+}
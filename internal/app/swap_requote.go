@@ -0,0 +1,178 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/execution"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/providers"
+)
+
+// Swap plan metadata that lets swap submit detect an expired quote and
+// re-quote it for the planned input.
+const (
+	quoteMetaExpiresAt   = "quote_expires_at"
+	quoteMetaFirmness    = "quote_firmness"
+	quoteMetaFromAssetID = "quote_from_asset_id"
+	quoteMetaToAssetID   = "quote_to_asset_id"
+	quoteMetaRPCURL      = "quote_rpc_url"
+	quoteMetaPlannedOut  = "quote_planned_amount"
+	quoteMetaRequotedAt  = "quote_requoted_at"
+	quoteMetaDriftBps    = "quote_drift_bps"
+)
+
+// defaultMaxRequoteDriftBps is how far below the planned output a re-quote of
+// an expired swap plan may land before swap submit refuses it.
+const defaultMaxRequoteDriftBps = 100
+
+// stampSwapQuoteExpiry records when the quote behind a swap plan expires and
+// whether it is firm. CoW orders commit to their quoted buy amount until
+// valid_to; other providers quote indicatively. Single-input exact-input
+// plans also record their request so swap submit can re-quote them.
+func stampSwapQuoteExpiry(action *execution.Action, req providers.SwapQuoteRequest, now time.Time) {
+	if action.Metadata == nil {
+		action.Metadata = map[string]any{}
+	}
+	expiresAt := now.Add(providers.DefaultQuoteValidity)
+	firmness := providers.QuoteFirmnessIndicative
+	if validTo, ok := metadataUnix(action.Metadata["valid_to"]); ok {
+		expiresAt = validTo
+		firmness = providers.QuoteFirmnessFirm
+	}
+	action.Metadata[quoteMetaExpiresAt] = expiresAt.UTC().Format(time.RFC3339)
+	action.Metadata[quoteMetaFirmness] = firmness
+	if req.TradeType != providers.SwapTradeTypeExactInput || len(req.Inputs) > 1 {
+		return
+	}
+	action.Metadata[quoteMetaFromAssetID] = req.FromAsset.AssetID
+	action.Metadata[quoteMetaToAssetID] = req.ToAsset.AssetID
+	action.Metadata[quoteMetaRPCURL] = strings.TrimSpace(req.RPCURL)
+}
+
+// requoteExpiredSwap re-quotes a swap plan whose quote expired before any of
+// its steps was broadcast and replaces its steps, so the swap executes with a
+// fresh min-out. It refuses the fresh quote when its output is more than
+// maxDriftBps below the originally planned output. Plans that cannot be
+// re-quoted keep their steps and return a warning; their planned min-out
+// still bounds the fill.
+func (s *runtimeState) requoteExpiredSwap(ctx context.Context, action *execution.Action, maxDriftBps int64) ([]string, error) {
+	if maxDriftBps < 0 {
+		return nil, clierr.New(clierr.CodeUsage, "--max-requote-drift-bps must be non-negative")
+	}
+	expiresArg, _ := action.Metadata[quoteMetaExpiresAt].(string)
+	expiresAt, err := time.Parse(time.RFC3339, expiresArg)
+	now := s.runner.now()
+	if err != nil || now.Before(expiresAt) || swapStepsBroadcast(*action) {
+		return nil, nil
+	}
+	fromAssetID, _ := action.Metadata[quoteMetaFromAssetID].(string)
+	toAssetID, _ := action.Metadata[quoteMetaToAssetID].(string)
+	if fromAssetID == "" || toAssetID == "" {
+		return []string{fmt.Sprintf("swap quote expired at %s and this plan cannot be re-quoted; executing with its planned min-out", expiresArg)}, nil
+	}
+	chain, err := id.ParseChain(action.ChainID)
+	if err != nil {
+		return nil, clierr.Wrap(clierr.CodeActionPlan, "swap action has invalid chain_id", err)
+	}
+	fromAsset, err := id.ParseAsset(fromAssetID, chain)
+	if err != nil {
+		return nil, clierr.Wrap(clierr.CodeActionPlan, "swap action has invalid from asset", err)
+	}
+	toAsset, err := id.ParseAsset(toAssetID, chain)
+	if err != nil {
+		return nil, clierr.Wrap(clierr.CodeActionPlan, "swap action has invalid to asset", err)
+	}
+	decimals := fromAsset.Decimals
+	if decimals <= 0 {
+		decimals = 18
+	}
+	rpcURL, _ := action.Metadata[quoteMetaRPCURL].(string)
+	fresh, _, err := s.actionBuilderRegistry().BuildSwapAction(ctx, action.Provider, "plan", providers.SwapQuoteRequest{
+		Chain:           chain,
+		FromAsset:       fromAsset,
+		ToAsset:         toAsset,
+		AmountBaseUnits: action.InputAmount,
+		AmountDecimal:   id.FormatDecimalCompat(action.InputAmount, decimals),
+		RPCURL:          rpcURL,
+		TradeType:       providers.SwapTradeTypeExactInput,
+	}, providers.SwapExecutionOptions{
+		Sender:      action.FromAddress,
+		Recipient:   action.ToAddress,
+		SlippageBps: action.Constraints.SlippageBps,
+		Simulate:    action.Constraints.Simulate,
+		RPCURL:      rpcURL,
+	})
+	if err != nil {
+		return nil, clierr.Wrap(clierr.CodeActionPlan, "re-quote expired swap", err)
+	}
+
+	// Drift is measured against the first plan so repeated re-quotes cannot
+	// ratchet the price down.
+	plannedArg, _ := action.Metadata[quoteMetaPlannedOut].(string)
+	if plannedArg == "" {
+		plannedArg, _ = swapActionQuotedOut(*action)
+	}
+	freshArg, _ := swapActionQuotedOut(fresh)
+	planned, okPlanned := new(big.Int).SetString(plannedArg, 10)
+	quoted, okFresh := new(big.Int).SetString(freshArg, 10)
+	if !okPlanned || !okFresh || planned.Sign() <= 0 {
+		return nil, clierr.New(clierr.CodeActionPlan, "expired swap quote cannot be re-validated: quoted output is missing")
+	}
+	drift := new(big.Int).Sub(planned, quoted)
+	drift.Mul(drift, big.NewInt(10_000))
+	drift.Quo(drift, planned)
+	driftBps := drift.Int64()
+	if driftBps > maxDriftBps {
+		return nil, clierr.New(clierr.CodeActionPolicy, fmt.Sprintf("swap quote expired at %s and the re-quote is %d bps below the planned output (limit %d bps); plan the swap again or raise --max-requote-drift-bps", expiresArg, driftBps, maxDriftBps))
+	}
+
+	for key, value := range fresh.Metadata {
+		action.Metadata[key] = value
+	}
+	stampSwapQuoteExpiry(action, providers.SwapQuoteRequest{
+		FromAsset: fromAsset,
+		ToAsset:   toAsset,
+		RPCURL:    rpcURL,
+		TradeType: providers.SwapTradeTypeExactInput,
+	}, now)
+	action.Metadata[quoteMetaPlannedOut] = planned.String()
+	action.Metadata[quoteMetaRequotedAt] = now.UTC().Format(time.RFC3339)
+	action.Metadata[quoteMetaDriftBps] = driftBps
+	action.Steps = fresh.Steps
+	return []string{fmt.Sprintf("swap quote expired at %s; re-quoted with %d bps drift from the planned output", expiresArg, driftBps)}, nil
+}
+
+// swapStepsBroadcast reports whether any step of action has left the CLI.
+func swapStepsBroadcast(action execution.Action) bool {
+	for _, step := range action.Steps {
+		if step.TxHash != "" || (step.Order != nil && step.Order.UID != "") {
+			return true
+		}
+		if step.Status != execution.StepStatusPending && step.Status != execution.StepStatusSimulated {
+			return true
+		}
+	}
+	return false
+}
+
+// metadataUnix reads a unix timestamp from action metadata, which holds an
+// integer before the action is stored and a float64 after.
+func metadataUnix(value any) (time.Time, bool) {
+	switch typed := value.(type) {
+	case uint32:
+		return time.Unix(int64(typed), 0), true
+	case int64:
+		return time.Unix(typed, 0), true
+	case int:
+		return time.Unix(int64(typed), 0), true
+	case float64:
+		return time.Unix(int64(typed), 0), true
+	default:
+		return time.Time{}, false
+	}
+}
@@ -0,0 +1,97 @@
+package app
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/execution"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/providers"
+)
+
+func TestRequoteExpiredSwapReplacesStepsWithinDrift(t *testing.T) {
+	provider := &fakeSwapExecutionProvider{fakeSwapProvider: fakeSwapProvider{name: "odos"}, outPerIn: 99}
+	runner := NewRunnerWithWriters(io.Discard, io.Discard)
+	planned := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	runner.now = func() time.Time { return planned }
+	state := &runtimeState{runner: runner, swapProviders: map[string]providers.SwapProvider{"odos": provider}}
+
+	chain, err := id.ParseChain("eip155:1")
+	if err != nil {
+		t.Fatalf("parse chain: %v", err)
+	}
+	usdc, err := id.ParseAsset("USDC", chain)
+	if err != nil {
+		t.Fatalf("parse asset: %v", err)
+	}
+	usdt, err := id.ParseAsset("USDT", chain)
+	if err != nil {
+		t.Fatalf("parse asset: %v", err)
+	}
+	newPlan := func() execution.Action {
+		action := execution.NewAction("act_swap", "swap", "eip155:1", execution.Constraints{SlippageBps: 50})
+		action.Provider = "odos"
+		action.InputAmount = "1000000"
+		action.Metadata = map[string]any{"quoted_amount": "1000000"}
+		action.Steps = []execution.ActionStep{{StepID: "stale", Status: execution.StepStatusPending}}
+		stampSwapQuoteExpiry(&action, providers.SwapQuoteRequest{FromAsset: usdc, ToAsset: usdt, TradeType: providers.SwapTradeTypeExactInput}, planned)
+		return action
+	}
+
+	action := newPlan()
+	if action.Metadata[quoteMetaFirmness] != providers.QuoteFirmnessIndicative || action.Metadata[quoteMetaExpiresAt] != "2026-10-01T12:00:30Z" {
+		t.Fatalf("unexpected quote expiry stamps %+v", action.Metadata)
+	}
+	warnings, err := state.requoteExpiredSwap(context.Background(), &action, 100)
+	if err != nil || len(warnings) != 0 || provider.calls != 0 {
+		t.Fatalf("expected a live quote to be left alone, got warnings %v err %v calls %d", warnings, err, provider.calls)
+	}
+
+	runner.now = func() time.Time { return planned.Add(time.Minute) }
+	warnings, err = state.requoteExpiredSwap(context.Background(), &action, 100)
+	if err != nil {
+		t.Fatalf("expected a 100 bps drift to pass a 100 bps limit, got %v", err)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "100 bps drift") {
+		t.Fatalf("unexpected warnings %v", warnings)
+	}
+	if action.ActionID != "act_swap" || action.Steps[0].StepID != "swap" || action.Metadata["quoted_amount"] != "990000" {
+		t.Fatalf("expected the plan to be re-quoted in place, got %+v", action)
+	}
+	if action.Metadata[quoteMetaPlannedOut] != "1000000" || action.Metadata[quoteMetaExpiresAt] != "2026-10-01T12:01:30Z" {
+		t.Fatalf("unexpected re-quote metadata %+v", action.Metadata)
+	}
+
+	provider.outPerIn = 98
+	runner.now = func() time.Time { return planned.Add(time.Hour) }
+	action = newPlan()
+	_, err = state.requoteExpiredSwap(context.Background(), &action, 100)
+	if typed, ok := clierr.As(err); !ok || typed.Code != clierr.CodeActionPolicy {
+		t.Fatalf("expected a 200 bps drift to be refused, got %v", err)
+	}
+	if action.Steps[0].StepID != "stale" {
+		t.Fatalf("expected a refused re-quote to keep the planned steps, got %+v", action.Steps)
+	}
+
+	action = newPlan()
+	action.Steps[0].Status = execution.StepStatusSubmitted
+	calls := provider.calls
+	if _, err := state.requoteExpiredSwap(context.Background(), &action, 100); err != nil || provider.calls != calls {
+		t.Fatalf("expected a broadcast plan not to be re-quoted, got err %v", err)
+	}
+}
+
+func TestStampSwapQuoteExpiryTreatsCowOrdersAsFirm(t *testing.T) {
+	action := execution.Action{Metadata: map[string]any{"valid_to": float64(1900000000)}}
+	stampSwapQuoteExpiry(&action, providers.SwapQuoteRequest{TradeType: providers.SwapTradeTypeExactOutput}, time.Now())
+	if action.Metadata[quoteMetaFirmness] != providers.QuoteFirmnessFirm || action.Metadata[quoteMetaExpiresAt] != "2030-03-17T17:46:40Z" {
+		t.Fatalf("unexpected quote expiry stamps %+v", action.Metadata)
+	}
+	if _, ok := action.Metadata[quoteMetaFromAssetID]; ok {
+		t.Fatal("expected exact-output plans not to record a re-quote request")
+	}
+}
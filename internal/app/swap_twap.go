@@ -0,0 +1,474 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/execution"
+	execsigner "github.com/ggonzalez94/defi-cli/internal/execution/signer"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/model"
+	"github.com/ggonzalez94/defi-cli/internal/pricecheck"
+	"github.com/ggonzalez94/defi-cli/internal/providers"
+	"github.com/spf13/cobra"
+)
+
+const (
+	swapStrategyTWAP = "twap"
+	// maxTWAPSlices bounds the quotes one TWAP run requests up front. Each
+	// slice quote gets its own --timeout.
+	maxTWAPSlices = 50
+)
+
+// Sub-action metadata keys of a TWAP slice. They make a slice
+// self-describing, so actions resume re-quotes and checks it the same way
+// swap run does.
+const (
+	twapMetaSlice           = "twap_slice"
+	twapMetaSlices          = "twap_slices"
+	twapMetaInterval        = "twap_interval"
+	twapMetaMaxDeviationPct = "twap_max_deviation_pct"
+	twapMetaReferenceOut    = "twap_reference_out"
+	twapMetaFromAssetID     = "twap_from_asset_id"
+	twapMetaToAssetID       = "twap_to_asset_id"
+	twapMetaRPCURL          = "twap_rpc_url"
+	twapMetaDeviationPct    = "twap_deviation_pct"
+	twapMetaAbortReason     = "twap_abort_reason"
+)
+
+func (s *runtimeState) newSwapRunCommand() *cobra.Command {
+	type swapRunArgs struct {
+		Provider           string  `json:"provider" flag:"provider" required:"true" enum:"taikoswap,paraswap,odos"`
+		ChainArg           string  `json:"chain" flag:"chain" required:"true" format:"chain"`
+		FromAssetArg       string  `json:"from_asset" flag:"from-asset" required:"true" format:"asset"`
+		ToAssetArg         string  `json:"to_asset" flag:"to-asset" required:"true" format:"asset"`
+		AmountBase         string  `json:"amount" flag:"amount" format:"base-units"`
+		AmountDecimal      string  `json:"amount_decimal" flag:"amount-decimal" format:"decimal-amount"`
+		AmountUSD          string  `json:"amount_usd" flag:"amount-usd" format:"decimal-amount"`
+		Strategy           string  `json:"strategy" flag:"strategy" enum:"twap"`
+		Slices             int     `json:"slices" flag:"slices"`
+		Interval           string  `json:"interval" flag:"interval" format:"duration"`
+		MaxDeviationPct    float64 `json:"max_deviation_pct" flag:"max-deviation-pct"`
+		WalletRef          string  `json:"wallet" flag:"wallet" format:"identifier"`
+		FromAddress        string  `json:"from_address" flag:"from-address" format:"evm-address"`
+		Recipient          string  `json:"recipient" flag:"recipient" format:"evm-address"`
+		SlippageBps        int64   `json:"slippage_bps" flag:"slippage-bps"`
+		Simulate           bool    `json:"simulate" flag:"simulate"`
+		RPCURL             string  `json:"rpc_url" flag:"rpc-url" format:"url"`
+		Signer             string  `json:"signer" flag:"signer" enum:"local"`
+		KeySource          string  `json:"key_source" flag:"key-source" enum:"auto,env,file,keystore"`
+		PrivateKey         string  `json:"private_key" flag:"private-key" format:"hex"`
+		PollInterval       string  `json:"poll_interval" flag:"poll-interval" format:"duration"`
+		StepTimeout        string  `json:"step_timeout" flag:"step-timeout" format:"duration"`
+		GasMultiplier      float64 `json:"gas_multiplier" flag:"gas-multiplier"`
+		MaxFeeGwei         string  `json:"max_fee_gwei" flag:"max-fee-gwei"`
+		MaxPriorityFeeGwei string  `json:"max_priority_fee_gwei" flag:"max-priority-fee-gwei"`
+		AllowMaxApproval   bool    `json:"allow_max_approval" flag:"allow-max-approval"`
+		UnsafeProviderTx   bool    `json:"unsafe_provider_tx" flag:"unsafe-provider-tx"`
+	}
+	var run swapRunArgs
+	cmd := &cobra.Command{
+		Use:   "run",
+		Short: "Plan and execute a swap in timed slices (TWAP)",
+		Long:  "Splits --amount into --slices equal slices, plans each as a swap sub-action of one composite action, and executes them --interval apart. Each slice is re-quoted right before it runs and the run aborts when the fresh quote is more than --max-deviation-pct below the slice's planned output; slices that already ran stay filled.",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if strategy := strings.ToLower(strings.TrimSpace(run.Strategy)); strategy != swapStrategyTWAP {
+				return clierr.New(clierr.CodeUsage, "--strategy must be twap")
+			}
+			providerName := providers.NormalizeSwapProvider(run.Provider)
+			switch providerName {
+			case "":
+				return clierr.New(clierr.CodeUsage, "--provider is required")
+			case "cowswap", "tempo":
+				return clierr.New(clierr.CodeUnsupported, "swap run supports --provider taikoswap, paraswap, or odos")
+			}
+			if run.Slices < 2 || run.Slices > maxTWAPSlices {
+				return clierr.New(clierr.CodeUsage, fmt.Sprintf("--slices must be between 2 and %d", maxTWAPSlices))
+			}
+			interval, err := time.ParseDuration(strings.TrimSpace(run.Interval))
+			if err != nil || interval < 0 {
+				return clierr.New(clierr.CodeUsage, "--interval must be a non-negative duration like 5m")
+			}
+			if run.MaxDeviationPct < 0 {
+				return clierr.New(clierr.CodeUsage, "--max-deviation-pct must be >= 0")
+			}
+			identity, err := resolveExecutionIdentity(run.WalletRef, run.FromAddress, run.ChainArg)
+			if err != nil {
+				return err
+			}
+			chain, err := id.ParseChain(run.ChainArg)
+			if err != nil {
+				return err
+			}
+			fromAsset, err := id.ParseAsset(run.FromAssetArg, chain)
+			if err != nil {
+				return err
+			}
+			toAsset, err := id.ParseAsset(run.ToAssetArg, chain)
+			if err != nil {
+				return err
+			}
+			decimals := fromAsset.Decimals
+			if decimals <= 0 {
+				decimals = 18
+			}
+			amountCtx, cancel := context.WithTimeout(context.Background(), s.settings.Timeout)
+			base, _, amountUSD, err := s.normalizeAmountInput(amountCtx, chain, fromAsset, run.AmountBase, run.AmountDecimal, run.AmountUSD, decimals)
+			cancel()
+			if err != nil {
+				return err
+			}
+			sliceAmounts, err := splitTWAPAmount(base, run.Slices)
+			if err != nil {
+				return err
+			}
+
+			start := time.Now()
+			subActions := make([]execution.Action, 0, len(sliceAmounts))
+			providerInfoName := providerName
+			var buildErr error
+			for i, amount := range sliceAmounts {
+				// Slices are quoted one after another, so a shared deadline
+				// would starve the later ones.
+				ctx, cancel := context.WithTimeout(context.Background(), s.settings.Timeout)
+				slice, infoName, err := s.actionBuilderRegistry().BuildSwapAction(ctx, providerName, "plan", providers.SwapQuoteRequest{
+					Chain:           chain,
+					FromAsset:       fromAsset,
+					ToAsset:         toAsset,
+					AmountBaseUnits: amount,
+					AmountDecimal:   id.FormatDecimalCompat(amount, decimals),
+					RPCURL:          run.RPCURL,
+					TradeType:       providers.SwapTradeTypeExactInput,
+				}, providers.SwapExecutionOptions{
+					Sender:      identity.FromAddress,
+					Recipient:   run.Recipient,
+					SlippageBps: run.SlippageBps,
+					Simulate:    run.Simulate,
+					RPCURL:      run.RPCURL,
+				})
+				cancel()
+				if strings.TrimSpace(infoName) != "" {
+					providerInfoName = infoName
+				}
+				if err != nil {
+					buildErr = err
+					break
+				}
+				applyExecutionIdentityToAction(&slice, identity)
+				referenceOut, _ := swapActionQuotedOut(slice)
+				if slice.Metadata == nil {
+					slice.Metadata = map[string]any{}
+				}
+				slice.Metadata[twapMetaSlice] = i + 1
+				slice.Metadata[twapMetaSlices] = len(sliceAmounts)
+				slice.Metadata[twapMetaInterval] = interval.String()
+				slice.Metadata[twapMetaMaxDeviationPct] = run.MaxDeviationPct
+				slice.Metadata[twapMetaReferenceOut] = referenceOut
+				slice.Metadata[twapMetaFromAssetID] = fromAsset.AssetID
+				slice.Metadata[twapMetaToAssetID] = toAsset.AssetID
+				slice.Metadata[twapMetaRPCURL] = strings.TrimSpace(run.RPCURL)
+				subActions = append(subActions, slice)
+			}
+			statuses := []model.ProviderStatus{{Name: providerInfoName, Status: statusFromErr(buildErr), LatencyMS: time.Since(start).Milliseconds()}}
+			if buildErr != nil {
+				s.captureCommandDiagnostics(nil, statuses, false)
+				return buildErr
+			}
+			action, err := execution.NewCompositeAction(execution.NewActionID(), subActions)
+			if err != nil {
+				return err
+			}
+			action.Provider = providerName
+			action.InputAmount = base
+			action.Metadata = map[string]any{
+				"strategy":              swapStrategyTWAP,
+				twapMetaSlices:          len(sliceAmounts),
+				twapMetaInterval:        interval.String(),
+				twapMetaMaxDeviationPct: run.MaxDeviationPct,
+				twapMetaFromAssetID:     fromAsset.AssetID,
+				twapMetaToAssetID:       toAsset.AssetID,
+				"twap_filled_slices":    0,
+				"twap_filled_amount_in": "0",
+			}
+			applyActionAmountUSD(&action, amountUSD)
+			if err := s.checkActionProtocolPolicy(action); err != nil {
+				return err
+			}
+			if err := s.ensureActionStore(); err != nil {
+				return err
+			}
+			recordPlanInvocation(cmd, &action)
+			if err := s.actionStore.SaveComposite(action); err != nil {
+				return clierr.Wrap(clierr.CodeInternal, "persist composite action", err)
+			}
+			s.captureCommandDiagnostics(nil, statuses, false)
+
+			resolvedExec, err := resolveActionExecutionBackend(cmd, action, submitExecutionInputs{
+				Signer:      run.Signer,
+				KeySource:   run.KeySource,
+				PrivateKey:  run.PrivateKey,
+				FromAddress: run.FromAddress,
+			})
+			if err != nil {
+				return err
+			}
+			if err := validateExecutionSender(action, run.FromAddress, resolvedExec.sender); err != nil {
+				return err
+			}
+			execOpts, err := parseExecuteOptions(
+				run.Simulate,
+				run.PollInterval,
+				run.StepTimeout,
+				run.GasMultiplier,
+				run.MaxFeeGwei,
+				run.MaxPriorityFeeGwei,
+				run.AllowMaxApproval,
+				run.UnsafeProviderTx,
+				"",
+			)
+			if err != nil {
+				return err
+			}
+			interrupt, stop := notifyShutdown()
+			defer stop()
+			execOpts.Interrupt = interrupt
+			execOpts.PrepareStep = s.newTWAPSlicePreparer(interrupt)
+			execErr := s.executeActionWithTimeout(&action, resolvedExec.txSigner, resolvedExec.evmBackend, execOpts)
+			reason := summarizeTWAPFill(&action)
+			if execErr != nil && reason == "" {
+				return execErr
+			}
+			if err := s.actionStore.Save(action); err != nil {
+				return clierr.Wrap(clierr.CodeInternal, "persist action state", err)
+			}
+			if reason != "" {
+				return s.emitSuccess(trimRootPath(cmd.CommandPath()), action, []string{"twap aborted: " + reason}, cacheMetaBypass(), statuses, true)
+			}
+			return s.emitSuccess(trimRootPath(cmd.CommandPath()), action, identity.Warnings, cacheMetaBypass(), statuses, false)
+		},
+	}
+	cmd.Flags().StringVar(&run.Provider, "provider", "", "Swap execution provider (taikoswap|paraswap|odos)")
+	cmd.Flags().StringVar(&run.ChainArg, "chain", "", "Chain identifier")
+	cmd.Flags().StringVar(&run.FromAssetArg, "from-asset", "", "Input asset")
+	cmd.Flags().StringVar(&run.ToAssetArg, "to-asset", "", "Output asset")
+	cmd.Flags().StringVar(&run.AmountBase, "amount", "", "Total input amount in base units")
+	cmd.Flags().StringVar(&run.AmountDecimal, "amount-decimal", "", "Total input amount in decimal units")
+	cmd.Flags().StringVar(&run.AmountUSD, "amount-usd", "", "Total input amount as a USD notional, converted at the current input asset price")
+	cmd.Flags().StringVar(&run.Strategy, "strategy", swapStrategyTWAP, "Execution strategy (twap)")
+	cmd.Flags().IntVar(&run.Slices, "slices", 10, fmt.Sprintf("Number of equal slices (2-%d)", maxTWAPSlices))
+	cmd.Flags().StringVar(&run.Interval, "interval", "5m", "Wait between the start of consecutive slices")
+	cmd.Flags().Float64Var(&run.MaxDeviationPct, "max-deviation-pct", 2, "Abort when a slice's fresh quote is this many percent below its planned output (0 disables)")
+	cmd.Flags().StringVar(&run.WalletRef, "wallet", "", "Wallet identifier or name")
+	cmd.Flags().StringVar(&run.FromAddress, "from-address", "", "Sender EOA address")
+	cmd.Flags().StringVar(&run.Recipient, "recipient", "", "Recipient address (defaults to the resolved sender address)")
+	cmd.Flags().Int64Var(&run.SlippageBps, "slippage-bps", 50, "Max slippage in basis points, applied to each slice")
+	cmd.Flags().BoolVar(&run.Simulate, "simulate", true, "Run preflight simulation before each slice")
+	cmd.Flags().StringVar(&run.RPCURL, "rpc-url", "", "RPC URL override for the selected chain")
+	cmd.Flags().StringVar(&run.Signer, "signer", "local", "Signer backend (local)")
+	cmd.Flags().StringVar(&run.KeySource, "key-source", execsigner.KeySourceAuto, "Key source (auto|env|file|keystore)")
+	cmd.Flags().StringVar(&run.PrivateKey, "private-key", "", "Private key hex override for local signer (less safe)")
+	cmd.Flags().StringVar(&run.PollInterval, "poll-interval", "2s", "Receipt polling interval")
+	cmd.Flags().StringVar(&run.StepTimeout, "step-timeout", "2m", "Per-step receipt timeout")
+	cmd.Flags().Float64Var(&run.GasMultiplier, "gas-multiplier", 1.2, "Gas estimate safety multiplier")
+	cmd.Flags().StringVar(&run.MaxFeeGwei, "max-fee-gwei", "", "Optional EIP-1559 max fee (gwei)")
+	cmd.Flags().StringVar(&run.MaxPriorityFeeGwei, "max-priority-fee-gwei", "", "Optional EIP-1559 max priority fee (gwei)")
+	cmd.Flags().BoolVar(&run.AllowMaxApproval, "allow-max-approval", false, "Allow approval amounts greater than each slice's input amount")
+	cmd.Flags().BoolVar(&run.UnsafeProviderTx, "unsafe-provider-tx", false, "Bypass provider transaction guardrails for aggregator payloads")
+	_ = cmd.MarkFlagRequired("provider")
+	_ = cmd.MarkFlagRequired("chain")
+	_ = cmd.MarkFlagRequired("from-asset")
+	_ = cmd.MarkFlagRequired("to-asset")
+	configureStructuredInput[swapRunArgs](cmd, structuredInputOptions{
+		Mutation:         true,
+		InputConstraints: standardExecutionIdentityInputConstraints(),
+		Auth:             executionSubmitAuthRequirements(),
+	})
+	return cmd
+}
+
+// splitTWAPAmount splits amountBaseUnits into slices equal parts; the last
+// slice takes the remainder.
+func splitTWAPAmount(amountBaseUnits string, slices int) ([]string, error) {
+	total, ok := new(big.Int).SetString(strings.TrimSpace(amountBaseUnits), 10)
+	if !ok || total.Sign() <= 0 {
+		return nil, clierr.New(clierr.CodeUsage, "twap amount must be a positive integer in base units")
+	}
+	each := new(big.Int).Quo(total, big.NewInt(int64(slices)))
+	if each.Sign() <= 0 {
+		return nil, clierr.New(clierr.CodeUsage, fmt.Sprintf("amount is too small to split into %d slices", slices))
+	}
+	out := make([]string, slices)
+	for i := range out {
+		out[i] = each.String()
+	}
+	last := new(big.Int).Sub(total, new(big.Int).Mul(each, big.NewInt(int64(slices-1))))
+	out[slices-1] = last.String()
+	return out, nil
+}
+
+// newTWAPSlicePreparer returns a PrepareStep hook for TWAP slices. Before the
+// first step of a slice it waits until the slice interval has passed since
+// the previous slice started, re-quotes the slice, and aborts when the fresh
+// quote is more than the slice's max deviation below its planned output.
+// Actions that are not TWAP slices pass through untouched.
+func (s *runtimeState) newTWAPSlicePreparer(interrupt <-chan struct{}) func(context.Context, *execution.Action, int) error {
+	var lastStart time.Time
+	return func(ctx context.Context, action *execution.Action, index int) error {
+		intervalArg, ok := action.Metadata[twapMetaInterval].(string)
+		if !ok || strings.TrimSpace(action.ParentActionID) == "" {
+			return nil
+		}
+		for _, step := range action.Steps {
+			switch step.Status {
+			case execution.StepStatusSubmitted, execution.StepStatusConfirmed:
+				return nil
+			}
+		}
+		if index != 0 {
+			return nil
+		}
+		interval, err := time.ParseDuration(intervalArg)
+		if err != nil {
+			return clierr.Wrap(clierr.CodeActionPlan, "twap slice has invalid interval", err)
+		}
+		if !lastStart.IsZero() {
+			if wait := time.Until(lastStart.Add(interval)); wait > 0 {
+				timer := time.NewTimer(wait)
+				select {
+				case <-timer.C:
+				case <-ctx.Done():
+					timer.Stop()
+					return clierr.Wrap(clierr.CodeActionPlan, "waiting for the next twap slice", ctx.Err())
+				case <-interrupt:
+					timer.Stop()
+					return clierr.New(clierr.CodeActionPlan, "interrupted while waiting for the next twap slice; resume with actions resume")
+				}
+			}
+		}
+		lastStart = time.Now()
+		return s.refreshTWAPSlice(ctx, action)
+	}
+}
+
+// refreshTWAPSlice re-quotes a TWAP slice for its planned input and replaces
+// its steps. It records the deviation of the fresh quote from the slice's
+// planned output and refuses the slice when it exceeds the slice limit.
+func (s *runtimeState) refreshTWAPSlice(ctx context.Context, action *execution.Action) error {
+	chain, err := id.ParseChain(action.ChainID)
+	if err != nil {
+		return clierr.Wrap(clierr.CodeActionPlan, "twap slice has invalid chain_id", err)
+	}
+	fromAssetID, _ := action.Metadata[twapMetaFromAssetID].(string)
+	fromAsset, err := id.ParseAsset(fromAssetID, chain)
+	if err != nil {
+		return clierr.Wrap(clierr.CodeActionPlan, "twap slice has invalid from asset", err)
+	}
+	toAssetID, _ := action.Metadata[twapMetaToAssetID].(string)
+	toAsset, err := id.ParseAsset(toAssetID, chain)
+	if err != nil {
+		return clierr.Wrap(clierr.CodeActionPlan, "twap slice has invalid to asset", err)
+	}
+	decimals := fromAsset.Decimals
+	if decimals <= 0 {
+		decimals = 18
+	}
+	rpcURL, _ := action.Metadata[twapMetaRPCURL].(string)
+	fresh, _, err := s.actionBuilderRegistry().BuildSwapAction(ctx, action.Provider, "plan", providers.SwapQuoteRequest{
+		Chain:           chain,
+		FromAsset:       fromAsset,
+		ToAsset:         toAsset,
+		AmountBaseUnits: action.InputAmount,
+		AmountDecimal:   id.FormatDecimalCompat(action.InputAmount, decimals),
+		RPCURL:          rpcURL,
+		TradeType:       providers.SwapTradeTypeExactInput,
+	}, providers.SwapExecutionOptions{
+		Sender:      action.FromAddress,
+		Recipient:   action.ToAddress,
+		SlippageBps: action.Constraints.SlippageBps,
+		Simulate:    action.Constraints.Simulate,
+		RPCURL:      rpcURL,
+	})
+	if err != nil {
+		return err
+	}
+
+	referenceArg, _ := action.Metadata[twapMetaReferenceOut].(string)
+	reference, okRef := new(big.Int).SetString(referenceArg, 10)
+	freshArg, _ := swapActionQuotedOut(fresh)
+	quoted, okFresh := new(big.Int).SetString(freshArg, 10)
+	if okRef && okFresh && reference.Sign() > 0 {
+		deviation := -pricecheck.DeviationPct(quoted, reference)
+		action.Metadata[twapMetaDeviationPct] = deviation
+		maxDeviation, _ := action.Metadata[twapMetaMaxDeviationPct].(float64)
+		if maxDeviation > 0 && deviation > maxDeviation {
+			reason := fmt.Sprintf("slice %v of %v quotes %.2f%% below its planned output (limit %.2f%%)", action.Metadata[twapMetaSlice], action.Metadata[twapMetaSlices], deviation, maxDeviation)
+			action.Metadata[twapMetaAbortReason] = reason
+			return clierr.New(clierr.CodeActionPolicy, reason)
+		}
+	}
+	delete(action.Metadata, twapMetaAbortReason)
+	for key, value := range fresh.Metadata {
+		action.Metadata[key] = value
+	}
+	action.Steps = fresh.Steps
+	return nil
+}
+
+// summarizeTWAPFill records how much of a TWAP composite filled and returns
+// the abort reason of the slice that stopped it, if any.
+func summarizeTWAPFill(action *execution.Action) string {
+	if action.Metadata == nil || action.Metadata["strategy"] != swapStrategyTWAP {
+		return ""
+	}
+	filled := 0
+	filledIn := new(big.Int)
+	reason := ""
+	for _, sub := range action.SubActions {
+		if sub.Status == execution.ActionStatusCompleted {
+			filled++
+			if amount, ok := new(big.Int).SetString(sub.InputAmount, 10); ok {
+				filledIn.Add(filledIn, amount)
+			}
+			continue
+		}
+		if abort, _ := sub.Metadata[twapMetaAbortReason].(string); abort != "" && reason == "" {
+			reason = abort
+		}
+	}
+	action.Metadata["twap_filled_slices"] = filled
+	action.Metadata["twap_filled_amount_in"] = filledIn.String()
+	return reason
+}
+
+// twapWaitBudget is the longest a TWAP composite can spend waiting between
+// its unfinished slices.
+func twapWaitBudget(action *execution.Action) time.Duration {
+	if action == nil {
+		return 0
+	}
+	var total time.Duration
+	for _, sub := range action.SubActions {
+		if sub.Status == execution.ActionStatusCompleted {
+			continue
+		}
+		intervalArg, _ := sub.Metadata[twapMetaInterval].(string)
+		if interval, err := time.ParseDuration(intervalArg); err == nil {
+			total += interval
+		}
+	}
+	return total
+}
+
+// swapActionQuotedOut returns the quoted output a planned swap action records.
+func swapActionQuotedOut(action execution.Action) (string, bool) {
+	for _, key := range []string{"quoted_amount", "quoted_amount_out"} {
+		if value, ok := action.Metadata[key].(string); ok && strings.TrimSpace(value) != "" {
+			return strings.TrimSpace(value), true
+		}
+	}
+	return "", false
+}
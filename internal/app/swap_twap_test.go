@@ -0,0 +1,151 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/execution"
+	"github.com/ggonzalez94/defi-cli/internal/providers"
+	"github.com/spf13/cobra"
+)
+
+type fakeSwapExecutionProvider struct {
+	fakeSwapProvider
+	// outPerIn is the quoted output per unit of input, in percent.
+	outPerIn int64
+	// delay is how long each build takes, bounded by the context.
+	delay time.Duration
+}
+
+func (f *fakeSwapExecutionProvider) BuildSwapAction(ctx context.Context, req providers.SwapQuoteRequest, opts providers.SwapExecutionOptions) (execution.Action, error) {
+	f.calls++
+	select {
+	case <-time.After(f.delay):
+	case <-ctx.Done():
+		return execution.Action{}, ctx.Err()
+	}
+	f.lastReq = req
+	amount, _ := new(big.Int).SetString(req.AmountBaseUnits, 10)
+	out := new(big.Int).Mul(amount, big.NewInt(f.outPerIn))
+	out.Quo(out, big.NewInt(100))
+	action := execution.NewAction(execution.NewActionID(), "swap", req.Chain.CAIP2, execution.Constraints{SlippageBps: opts.SlippageBps, Simulate: opts.Simulate})
+	action.Provider = f.name
+	action.FromAddress = opts.Sender
+	action.InputAmount = req.AmountBaseUnits
+	action.Metadata = map[string]any{"quoted_amount": out.String()}
+	action.Steps = []execution.ActionStep{{StepID: "swap", Type: execution.StepTypeSwap, Status: execution.StepStatusPending, ChainID: req.Chain.CAIP2}}
+	return action, nil
+}
+
+func TestSplitTWAPAmountGivesRemainderToLastSlice(t *testing.T) {
+	slices, err := splitTWAPAmount("1003", 4)
+	if err != nil {
+		t.Fatalf("splitTWAPAmount failed: %v", err)
+	}
+	want := []string{"250", "250", "250", "253"}
+	if strings.Join(slices, ",") != strings.Join(want, ",") {
+		t.Fatalf("unexpected slices %v, want %v", slices, want)
+	}
+	if _, err := splitTWAPAmount("3", 4); err == nil {
+		t.Fatal("expected an amount smaller than the slice count to fail")
+	}
+}
+
+func TestSwapRunGivesEachSliceQuoteItsOwnTimeout(t *testing.T) {
+	state, _, _ := newExecutionTestState(filepath.Join(t.TempDir(), "actions.db"), filepath.Join(t.TempDir(), "actions.lock"))
+	state.settings.Timeout = 150 * time.Millisecond
+	provider := &fakeSwapExecutionProvider{fakeSwapProvider: fakeSwapProvider{name: "odos"}, outPerIn: 100, delay: 60 * time.Millisecond}
+	state.swapProviders = map[string]providers.SwapProvider{"odos": provider}
+	t.Cleanup(func() {
+		if state.actionStore != nil {
+			_ = state.actionStore.Close()
+		}
+	})
+
+	root := &cobra.Command{Use: "defi", SilenceErrors: true, SilenceUsage: true}
+	root.AddCommand(state.newSwapRunCommand())
+	root.SetArgs([]string{"run", "--provider", "odos", "--chain", "1", "--from-asset", "USDC", "--to-asset", "USDT", "--amount", "4000000", "--slices", "4", "--from-address", "0x00000000000000000000000000000000000000AA", "--private-key", "0x1234"})
+	err := root.Execute()
+	if errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected slice quotes not to share one deadline, got %v", err)
+	}
+	if provider.calls != 4 {
+		t.Fatalf("expected all 4 slices to be quoted, got %d calls err=%v", provider.calls, err)
+	}
+	actions, listErr := state.actionStore.List("", 10)
+	if listErr != nil || len(actions) != 1 || len(actions[0].SubActions) != 4 {
+		t.Fatalf("expected the planned twap to be saved, got %+v err=%v", actions, listErr)
+	}
+}
+
+func TestTWAPSlicePreparerRequotesAndAbortsOnDeviation(t *testing.T) {
+	provider := &fakeSwapExecutionProvider{fakeSwapProvider: fakeSwapProvider{name: "odos"}, outPerIn: 99}
+	state := &runtimeState{swapProviders: map[string]providers.SwapProvider{"odos": provider}}
+	slice := func(n int) execution.Action {
+		action := execution.NewAction("act_slice", "swap", "eip155:1", execution.Constraints{SlippageBps: 50})
+		action.ParentActionID = "act_twap"
+		action.Provider = "odos"
+		action.InputAmount = "1000000"
+		action.Metadata = map[string]any{
+			twapMetaSlice:           n,
+			twapMetaSlices:          2,
+			twapMetaInterval:        "0s",
+			twapMetaMaxDeviationPct: 2.0,
+			twapMetaReferenceOut:    "1000000",
+			twapMetaFromAssetID:     "eip155:1/erc20:0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48",
+			twapMetaToAssetID:       "eip155:1/erc20:0xdac17f958d2ee523a2206206994597c13d831ec7",
+		}
+		action.Steps = []execution.ActionStep{{StepID: "stale", Status: execution.StepStatusPending}}
+		return action
+	}
+	prepare := state.newTWAPSlicePreparer(nil)
+
+	first := slice(1)
+	if err := prepare(context.Background(), &first, 0); err != nil {
+		t.Fatalf("expected a 1%% deviation to pass a 2%% limit, got %v", err)
+	}
+	if first.Steps[0].StepID != "swap" || first.Metadata["quoted_amount"] != "990000" {
+		t.Fatalf("expected the slice to be re-quoted, got steps %+v metadata %+v", first.Steps, first.Metadata)
+	}
+	if deviation, _ := first.Metadata[twapMetaDeviationPct].(float64); deviation < 0.99 || deviation > 1.01 {
+		t.Fatalf("expected a recorded deviation of 1%%, got %v", first.Metadata[twapMetaDeviationPct])
+	}
+
+	provider.outPerIn = 95
+	second := slice(2)
+	err := prepare(context.Background(), &second, 0)
+	if err == nil {
+		t.Fatal("expected a 5% deviation to abort the slice")
+	}
+	if typed, ok := clierr.As(err); !ok || typed.Code != clierr.CodeActionPolicy {
+		t.Fatalf("expected an action policy error, got %v", err)
+	}
+	if second.Steps[0].StepID != "stale" {
+		t.Fatalf("expected an aborted slice to keep its planned steps, got %+v", second.Steps)
+	}
+
+	action := execution.Action{Metadata: map[string]any{"strategy": swapStrategyTWAP}, SubActions: []execution.Action{first, second}}
+	action.SubActions[0].Status = execution.ActionStatusCompleted
+	if reason := summarizeTWAPFill(&action); !strings.Contains(reason, "slice 2 of 2") {
+		t.Fatalf("unexpected abort reason %q", reason)
+	}
+	if action.Metadata["twap_filled_slices"] != 1 || action.Metadata["twap_filled_amount_in"] != "1000000" {
+		t.Fatalf("unexpected fill summary %+v", action.Metadata)
+	}
+}
+
+func TestTWAPWaitBudgetCountsPendingSlices(t *testing.T) {
+	pending := execution.Action{Status: execution.ActionStatusPlanned, Metadata: map[string]any{twapMetaInterval: "5m0s"}}
+	done := pending
+	done.Status = execution.ActionStatusCompleted
+	action := &execution.Action{SubActions: []execution.Action{done, pending, pending}}
+	if got := twapWaitBudget(action); got != 10*time.Minute {
+		t.Fatalf("expected 10m wait budget, got %s", got)
+	}
+}
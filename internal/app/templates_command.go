@@ -0,0 +1,214 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/execution"
+	"github.com/ggonzalez94/defi-cli/internal/logx"
+	"github.com/ggonzalez94/defi-cli/internal/policy"
+	"github.com/ggonzalez94/defi-cli/internal/schema"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// recordPlanInvocation stores the plan command path and explicitly set flags
+// on the action so it can later be turned into a template. Global flags are
+// not part of the plan and are left out.
+func recordPlanInvocation(cmd *cobra.Command, action *execution.Action) {
+	flags := map[string]any{}
+	local := cmd.LocalFlags()
+	cmd.Flags().Visit(func(flag *pflag.Flag) {
+		if flag.Name == inputJSONFlagName || flag.Name == inputFileFlagName || flag.Name == "preview" || local.Lookup(flag.Name) == nil {
+			return
+		}
+		// Key material is never replayable from a template.
+		if logx.IsPrivateKeyField(flag.Name) {
+			return
+		}
+		if slice, ok := flag.Value.(pflag.SliceValue); ok {
+			flags[flag.Name] = strings.Join(slice.GetSlice(), ",")
+			return
+		}
+		flags[flag.Name] = flag.Value.String()
+	})
+	if action.Metadata == nil {
+		action.Metadata = map[string]any{}
+	}
+	action.Metadata[execution.MetadataPlanCommand] = trimRootPath(cmd.CommandPath())
+	action.Metadata[execution.MetadataPlanFlags] = flags
+}
+
+func (s *runtimeState) newTemplatesCommand() *cobra.Command {
+	root := &cobra.Command{Use: "templates", Short: "Parameterized action templates"}
+
+	var createActionID, createName string
+	createCmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create a template from a planned action",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			actionID, err := resolveActionID(createActionID)
+			if err != nil {
+				return err
+			}
+			name := strings.TrimSpace(createName)
+			if err := execution.ValidateTemplateName(name); err != nil {
+				return clierr.Wrap(clierr.CodeUsage, "invalid template name", err)
+			}
+			if err := s.ensureActionStore(); err != nil {
+				return err
+			}
+			action, err := s.actionStore.Get(actionID)
+			if err != nil {
+				return clierr.Wrap(clierr.CodeUsage, "load action", err)
+			}
+			tmpl, err := execution.NewTemplate(name, action)
+			if err != nil {
+				return clierr.Wrap(clierr.CodeUsage, "create template", err)
+			}
+			if err := s.actionStore.CreateTemplate(tmpl); err != nil {
+				if errors.Is(err, execution.ErrTemplateExists) {
+					return clierr.Wrap(clierr.CodeUsage, "create template", err)
+				}
+				return clierr.Wrap(clierr.CodeInternal, "persist template", err)
+			}
+			return s.emitSuccess(trimRootPath(cmd.CommandPath()), tmpl, nil, cacheMetaBypass(), nil, false)
+		},
+	}
+	createCmd.Flags().StringVar(&createActionID, "from-action", "", "Planned action identifier to templatize")
+	createCmd.Flags().StringVar(&createName, "name", "", "Template name (lowercase letters, digits, - and _)")
+	_ = createCmd.MarkFlagRequired("from-action")
+	_ = createCmd.MarkFlagRequired("name")
+	templateResponse := schema.SchemaFromType(execution.Template{})
+	_ = schema.SetCommandMetadata(createCmd, schema.CommandMetadata{Mutation: true, Response: &templateResponse})
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List saved templates",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if err := s.ensureActionStore(); err != nil {
+				return err
+			}
+			items, err := s.actionStore.ListTemplates()
+			if err != nil {
+				return clierr.Wrap(clierr.CodeInternal, "list templates", err)
+			}
+			return s.emitSuccess(trimRootPath(cmd.CommandPath()), items, nil, cacheMetaBypass(), nil, false)
+		},
+	}
+	listResponse := schema.SchemaFromType([]execution.Template{})
+	_ = schema.SetCommandMetadata(listCmd, schema.CommandMetadata{Response: &listResponse})
+
+	var runSets []string
+	runCmd := &cobra.Command{
+		Use:   "run <name>",
+		Short: "Plan a new action from a template, overriding only its variables",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			overrides, err := parseTemplateSets(runSets)
+			if err != nil {
+				return err
+			}
+			if err := s.ensureActionStore(); err != nil {
+				return err
+			}
+			tmpl, err := s.actionStore.GetTemplate(strings.TrimSpace(args[0]))
+			if err != nil {
+				if errors.Is(err, execution.ErrTemplateNotFound) {
+					return clierr.Wrap(clierr.CodeUsage, "load template", err)
+				}
+				return clierr.Wrap(clierr.CodeInternal, "load template", err)
+			}
+			flagValues, err := tmpl.Args(overrides)
+			if err != nil {
+				return clierr.Wrap(clierr.CodeUsage, "apply template variables", err)
+			}
+			return s.runTemplatePlan(cmd, tmpl, flagValues)
+		},
+	}
+	runCmd.Flags().StringArrayVar(&runSets, "set", nil, "Variable override name=value (repeatable)")
+	runResponse := schema.SchemaFromType(execution.Action{})
+	_ = schema.SetCommandMetadata(runCmd, schema.CommandMetadata{Mutation: true, Response: &runResponse})
+
+	deleteCmd := &cobra.Command{
+		Use:   "delete <name>",
+		Short: "Delete a saved template",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := s.ensureActionStore(); err != nil {
+				return err
+			}
+			name := strings.TrimSpace(args[0])
+			if err := s.actionStore.DeleteTemplate(name); err != nil {
+				if errors.Is(err, execution.ErrTemplateNotFound) {
+					return clierr.Wrap(clierr.CodeUsage, "delete template", err)
+				}
+				return clierr.Wrap(clierr.CodeInternal, "delete template", err)
+			}
+			return s.emitSuccess(trimRootPath(cmd.CommandPath()), map[string]any{"name": name, "deleted": true}, nil, cacheMetaBypass(), nil, false)
+		},
+	}
+	_ = schema.SetCommandMetadata(deleteCmd, schema.CommandMetadata{Mutation: true})
+
+	root.AddCommand(createCmd)
+	root.AddCommand(listCmd)
+	root.AddCommand(runCmd)
+	root.AddCommand(deleteCmd)
+	return root
+}
+
+// runTemplatePlan resolves the template's plan command, applies flagValues,
+// and runs it as if invoked directly, including flag validation.
+func (s *runtimeState) runTemplatePlan(cmd *cobra.Command, tmpl execution.Template, flagValues map[string]string) error {
+	if err := policy.CheckCommandAllowed(s.settings.EnableCommands, tmpl.Command); err != nil {
+		return err
+	}
+	target, _, err := cmd.Root().Find(strings.Fields(tmpl.Command))
+	if err != nil || target == nil || trimRootPath(target.CommandPath()) != tmpl.Command || target.RunE == nil {
+		return clierr.New(clierr.CodeUnsupported, fmt.Sprintf("template %s references unknown command %q", tmpl.Name, tmpl.Command))
+	}
+	names := make([]string, 0, len(flagValues))
+	for name := range flagValues {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if target.Flags().Lookup(name) == nil {
+			return clierr.New(clierr.CodeUsage, fmt.Sprintf("template %s: %s does not accept --%s", tmpl.Name, tmpl.Command, name))
+		}
+		if err := target.Flags().Set(name, flagValues[name]); err != nil {
+			return clierr.Wrap(clierr.CodeUsage, fmt.Sprintf("template %s: set --%s", tmpl.Name, name), err)
+		}
+	}
+	if err := target.ValidateRequiredFlags(); err != nil {
+		return clierr.Wrap(clierr.CodeUsage, "template flags", err)
+	}
+	if err := target.ValidateFlagGroups(); err != nil {
+		return clierr.Wrap(clierr.CodeUsage, "template flags", err)
+	}
+	if target.PreRunE != nil {
+		if err := target.PreRunE(target, nil); err != nil {
+			return err
+		}
+	}
+	return target.RunE(target, nil)
+}
+
+func parseTemplateSets(values []string) (map[string]string, error) {
+	out := make(map[string]string, len(values))
+	for _, item := range values {
+		key, value, ok := strings.Cut(item, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, clierr.New(clierr.CodeUsage, fmt.Sprintf("--set expects name=value, got %q", item))
+		}
+		if _, exists := out[key]; exists {
+			return nil, clierr.New(clierr.CodeUsage, fmt.Sprintf("--set %s given more than once", key))
+		}
+		out[key] = strings.TrimSpace(value)
+	}
+	return out, nil
+}
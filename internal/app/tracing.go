@@ -0,0 +1,42 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/ggonzalez94/defi-cli/internal/model"
+	"github.com/ggonzalez94/defi-cli/internal/tracex"
+)
+
+// startTrace begins the --trace recording for this invocation, joining the
+// caller's trace when TRACEPARENT is set.
+func (s *runtimeState) startTrace() {
+	if !s.settings.Trace || s.trace != nil {
+		return
+	}
+	s.trace = tracex.New(s.settings.TraceParent)
+	tracex.Set(s.trace)
+}
+
+// finishTrace ends the trace for the envelope being rendered and exports it
+// when an OTLP endpoint is configured. An export failure is returned as a
+// warning; it never fails the command.
+func (s *runtimeState) finishTrace(commandPath string, failure error) (*model.Trace, []string) {
+	if s.trace == nil {
+		return nil, nil
+	}
+	trace := s.trace.Finish()
+	s.trace = nil
+	if s.settings.OTLPEndpoint == "" || s.settings.Offline {
+		return &trace, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), s.settings.Timeout)
+	defer cancel()
+	client := &http.Client{Timeout: s.settings.Timeout}
+	if err := tracex.Export(ctx, client, s.settings.OTLPEndpoint, s.settings.OTLPHeaders, commandPath, failure, trace); err != nil {
+		return &trace, []string{fmt.Sprintf("trace not exported: %v", err)}
+	}
+	trace.Exported = true
+	return &trace, nil
+}
@@ -0,0 +1,94 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/registry"
+	"github.com/ggonzalez94/defi-cli/internal/tracex"
+)
+
+func TestRunnerTraceReportsRequestsAndExports(t *testing.T) {
+	chainList := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[{"name": "Unichain", "shortName": "unichain", "chainId": 130}]`))
+	}))
+	defer chainList.Close()
+	var exported atomic.Int32
+	var exportedTraceID atomic.Value
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			ResourceSpans []struct {
+				ScopeSpans []struct {
+					Spans []struct {
+						TraceID string `json:"traceId"`
+					} `json:"spans"`
+				} `json:"scopeSpans"`
+			} `json:"resourceSpans"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		exportedTraceID.Store(body.ResourceSpans[0].ScopeSpans[0].Spans[0].TraceID)
+		exported.Add(1)
+	}))
+	defer collector.Close()
+
+	t.Setenv("DEFI_CACHE_PATH", filepath.Join(t.TempDir(), "cache.db"))
+	t.Setenv("TRACEPARENT", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", collector.URL)
+	t.Cleanup(func() {
+		id.SetSyncedChains(nil)
+		id.SetChainRefresher(nil)
+		registry.SetSyncedRPCURLs(nil)
+	})
+
+	var stdout, stderr bytes.Buffer
+	code := NewRunnerWithWriters(&stdout, &stderr).Run([]string{"chains", "sync", "--url", chainList.URL, "--trace"})
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d stderr=%s", code, stderr.String())
+	}
+	var env struct {
+		Meta struct {
+			Trace struct {
+				TraceID      string `json:"trace_id"`
+				ParentSpanID string `json:"parent_span_id"`
+				Exported     bool   `json:"exported"`
+				Requests     []struct {
+					Method  string  `json:"method"`
+					Status  int     `json:"status"`
+					Attempt int     `json:"attempt"`
+					TotalMS float64 `json:"total_ms"`
+				} `json:"requests"`
+			} `json:"trace"`
+		} `json:"meta"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &env); err != nil {
+		t.Fatalf("decode envelope: %v (%s)", err, stdout.String())
+	}
+	trace := env.Meta.Trace
+	if trace.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" || trace.ParentSpanID != "00f067aa0ba902b7" || !trace.Exported {
+		t.Fatalf("unexpected trace header: %+v", trace)
+	}
+	if len(trace.Requests) != 1 || trace.Requests[0].Status != 200 || trace.Requests[0].Attempt != 1 || trace.Requests[0].TotalMS <= 0 {
+		t.Fatalf("unexpected traced requests: %+v", trace.Requests)
+	}
+	if exported.Load() != 1 || exportedTraceID.Load() != trace.TraceID {
+		t.Fatalf("expected one OTLP export for trace %s, got %d", trace.TraceID, exported.Load())
+	}
+	if tracex.Active() != nil {
+		t.Fatal("expected the recorder to be cleared after the run")
+	}
+
+	stdout.Reset()
+	code = NewRunnerWithWriters(&stdout, &stderr).Run([]string{"chains", "sync", "--url", chainList.URL})
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d stderr=%s", code, stderr.String())
+	}
+	if bytes.Contains(stdout.Bytes(), []byte(`"trace"`)) || exported.Load() != 1 {
+		t.Fatalf("expected no trace without --trace, got %s", stdout.String())
+	}
+}
@@ -0,0 +1,360 @@
+package app
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/execution"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/model"
+	"github.com/ggonzalez94/defi-cli/internal/schema"
+	"github.com/spf13/cobra"
+)
+
+// tuiArgs selects what the dashboard shows.
+type tuiArgs struct {
+	Chain    string
+	Asset    string
+	Address  string
+	Provider string
+	Limit    int
+}
+
+// tuiPanel is one dashboard section rendered from a nested command.
+type tuiPanel struct {
+	Title string
+	Args  []string
+	// Each, when set, runs Args once per value with the value appended, and
+	// Render receives the data arrays of all runs concatenated.
+	Each []string
+	// Skip replaces the panel body when the panel cannot run.
+	Skip   string
+	Render func(w io.Writer, data json.RawMessage) error
+}
+
+// tuiEnvelope is the part of a nested command's envelope the dashboard
+// reads.
+type tuiEnvelope struct {
+	Success bool            `json:"success"`
+	Data    json.RawMessage `json:"data"`
+	Error   *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+	Warnings []string `json:"warnings"`
+}
+
+// ANSI sequences that clear the screen and home the cursor before a frame.
+const tuiClearScreen = "\x1b[H\x1b[2J"
+
+func (s *runtimeState) newTUICommand() *cobra.Command {
+	var args tuiArgs
+	var refreshArg string
+	var once bool
+	cmd := &cobra.Command{
+		Use:   "tui",
+		Short: "Interactive dashboard of top yields, positions, and pending actions",
+		Long: `Shows top yield opportunities for --asset on --chain, the lending positions of --address on --provider, and planned, running, or interrupted actions, refreshed every --refresh. Panels run the same commands an agent would (yield opportunities, lend positions, actions list) with the shared cache, so the dashboard shows what agents see.
+
+Type r and Enter to refresh now, q and Enter (or Ctrl-C) to quit. Input is read a line at a time, so a key does nothing until Enter is pressed. --once prints a single frame without screen control, for piping or logs. The dashboard is for people; it writes no JSON envelope.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if _, err := id.ParseChain(args.Chain); err != nil {
+				return err
+			}
+			if args.Limit <= 0 {
+				return clierr.New(clierr.CodeUsage, "--limit must be positive")
+			}
+			refresh, err := time.ParseDuration(strings.TrimSpace(refreshArg))
+			if err != nil || refresh <= 0 {
+				return clierr.New(clierr.CodeUsage, "--refresh must be a positive duration")
+			}
+			out := cmd.OutOrStdout()
+			forwarded := batchForwardedFlags(cmd)
+			if once {
+				return s.renderTUIFrame(out, args, forwarded)
+			}
+			if !isTerminal(out) {
+				return clierr.New(clierr.CodeUsage, "defi tui needs an interactive terminal; use --once to print a single frame")
+			}
+			return s.runTUI(cmd.InOrStdin(), out, args, forwarded, refresh)
+		},
+	}
+	cmd.Flags().StringVar(&args.Chain, "chain", "ethereum", "Chain for yields and positions")
+	cmd.Flags().StringVar(&args.Asset, "asset", "USDC", "Asset for top yields")
+	cmd.Flags().StringVar(&args.Address, "address", "", "Account whose lending positions are shown")
+	cmd.Flags().StringVar(&args.Provider, "provider", "aave", "Lending provider for positions (aave, morpho, moonwell, kamino)")
+	cmd.Flags().IntVar(&args.Limit, "limit", 5, "Rows per panel")
+	cmd.Flags().StringVar(&refreshArg, "refresh", "30s", "Time between refreshes")
+	cmd.Flags().BoolVar(&once, "once", false, "Print one frame and exit")
+	_ = schema.SetFlagMetadata(cmd.Flags(), "chain", schema.FlagMetadata{Format: "chain"})
+	_ = schema.SetFlagMetadata(cmd.Flags(), "asset", schema.FlagMetadata{Format: "asset"})
+	_ = schema.SetFlagMetadata(cmd.Flags(), "address", schema.FlagMetadata{Format: "address"})
+	_ = schema.SetFlagMetadata(cmd.Flags(), "refresh", schema.FlagMetadata{Format: "duration"})
+	return cmd
+}
+
+// runTUI redraws the dashboard every refresh, or on r, until q, end of
+// input, or an interrupt.
+func (s *runtimeState) runTUI(in io.Reader, out io.Writer, args tuiArgs, forwarded []string, refresh time.Duration) error {
+	interrupt, stop := notifyShutdown()
+	defer stop()
+	keys := make(chan string)
+	go func() {
+		defer close(keys)
+		scanner := bufio.NewScanner(in)
+		for scanner.Scan() {
+			keys <- strings.ToLower(strings.TrimSpace(scanner.Text()))
+		}
+	}()
+	for {
+		var frame bytes.Buffer
+		frame.WriteString(tuiClearScreen)
+		if err := s.renderTUIFrame(&frame, args, forwarded); err != nil {
+			return err
+		}
+		fmt.Fprintf(&frame, "\n[r] refresh  [q] quit  (auto-refresh every %s)\n", refresh)
+		if _, err := out.Write(frame.Bytes()); err != nil {
+			return clierr.Wrap(clierr.CodeInternal, "write dashboard", err)
+		}
+	wait:
+		for {
+			select {
+			case <-interrupt:
+				return nil
+			case key, ok := <-keys:
+				if !ok || key == "q" {
+					return nil
+				}
+				if key == "r" {
+					break wait
+				}
+			case <-time.After(refresh):
+				break wait
+			}
+		}
+	}
+}
+
+// renderTUIFrame writes every panel once. forwarded holds the global flags
+// given to tui, which apply to every panel like they do to batch requests.
+func (s *runtimeState) renderTUIFrame(w io.Writer, args tuiArgs, forwarded []string) error {
+	fmt.Fprintf(w, "defi dashboard  %s  chain=%s asset=%s\n", s.runner.now().UTC().Format(time.RFC3339), args.Chain, args.Asset)
+	for _, panel := range tuiPanels(args) {
+		fmt.Fprintf(w, "\n== %s ==\n", panel.Title)
+		if panel.Skip != "" {
+			fmt.Fprintln(w, panel.Skip)
+			continue
+		}
+		env := s.runTUIPanelArgs(forwarded, panel)
+		for _, warning := range env.Warnings {
+			fmt.Fprintf(w, "! %s\n", warning)
+		}
+		if !env.Success {
+			message := "command failed"
+			if env.Error != nil {
+				message = env.Error.Message
+			}
+			fmt.Fprintf(w, "unavailable: %s\n", message)
+			continue
+		}
+		if err := panel.Render(w, env.Data); err != nil {
+			fmt.Fprintf(w, "unreadable response: %v\n", err)
+		}
+	}
+	return nil
+}
+
+// runTUIPanelArgs runs panel once, or once per Each value, and merges the
+// data arrays of the runs. The first failed run fails the panel.
+func (s *runtimeState) runTUIPanelArgs(forwarded []string, panel tuiPanel) tuiEnvelope {
+	base := append(append([]string{}, forwarded...), panel.Args...)
+	if len(panel.Each) == 0 {
+		return s.runTUIPanel(base)
+	}
+	merged := tuiEnvelope{Success: true}
+	var rows []json.RawMessage
+	for _, value := range panel.Each {
+		env := s.runTUIPanel(append(append([]string{}, base...), value))
+		merged.Warnings = append(merged.Warnings, env.Warnings...)
+		if !env.Success {
+			env.Warnings = merged.Warnings
+			return env
+		}
+		var part []json.RawMessage
+		if err := json.Unmarshal(env.Data, &part); err != nil {
+			merged.Success = false
+			merged.Error = &struct {
+				Message string `json:"message"`
+			}{Message: "command did not return a list"}
+			return merged
+		}
+		rows = append(rows, part...)
+	}
+	data, err := json.Marshal(rows)
+	if err != nil {
+		merged.Success = false
+		merged.Error = &struct {
+			Message string `json:"message"`
+		}{Message: err.Error()}
+		return merged
+	}
+	merged.Data = data
+	return merged
+}
+
+// runTUIPanel runs args as a nested invocation sharing this one's cache and
+// process setup, like a batch request, and returns its envelope.
+func (s *runtimeState) runTUIPanel(args []string) tuiEnvelope {
+	var stdout, stderr bytes.Buffer
+	runner := NewRunnerWithWriters(&stdout, &stderr)
+	runner.now = s.runner.now
+	runner.parent = s
+	runner.Run(append([]string{"--json"}, args...))
+	raw := stdout.Bytes()
+	if stdout.Len() == 0 {
+		raw = stderr.Bytes()
+	}
+	var env tuiEnvelope
+	if err := json.Unmarshal(raw, &env); err != nil {
+		env.Success = false
+		env.Error = &struct {
+			Message string `json:"message"`
+		}{Message: "command did not write a JSON envelope"}
+	}
+	return env
+}
+
+// tuiPendingStatuses are the action statuses the pending panel shows:
+// actions that still need a submit or a resume.
+var tuiPendingStatuses = []string{
+	string(execution.ActionStatusPlanned),
+	string(execution.ActionStatusRunning),
+	string(execution.ActionStatusInterrupted),
+}
+
+func tuiPanels(args tuiArgs) []tuiPanel {
+	limit := strconv.Itoa(args.Limit)
+	positions := tuiPanel{
+		Title:  fmt.Sprintf("Positions (%s)", args.Provider),
+		Args:   []string{"lend", "positions", "--provider", args.Provider, "--chain", args.Chain, "--address", args.Address, "--limit", limit},
+		Render: renderTUIPositions,
+	}
+	if strings.TrimSpace(args.Address) == "" {
+		positions.Skip = "set --address to show lending positions"
+	}
+	return []tuiPanel{
+		{
+			Title:  "Top yields",
+			Args:   []string{"yield", "opportunities", "--chain", args.Chain, "--asset", args.Asset, "--limit", limit},
+			Render: renderTUIYields,
+		},
+		positions,
+		{
+			Title: "Pending actions",
+			// actions list takes one status, so each pending status is
+			// listed on its own and the renderer keeps the newest.
+			Args:   []string{"actions", "list", "--limit", limit, "--status"},
+			Each:   tuiPendingStatuses,
+			Render: tuiActionsRenderer(args.Limit),
+		},
+	}
+}
+
+func renderTUIYields(w io.Writer, data json.RawMessage) error {
+	var rows []model.YieldOpportunity
+	if err := json.Unmarshal(data, &rows); err != nil {
+		return err
+	}
+	if len(rows) == 0 {
+		_, err := fmt.Fprintln(w, "no opportunities")
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "PROVIDER\tTYPE\tAPY %\tTVL USD\tRISK")
+	for _, row := range rows {
+		fmt.Fprintf(tw, "%s\t%s\t%.2f\t%s\t%s\n", row.Provider, row.Type, row.APYTotal, formatTUIUSD(row.TVLUSD), row.RiskLevel)
+	}
+	return tw.Flush()
+}
+
+func renderTUIPositions(w io.Writer, data json.RawMessage) error {
+	var rows []model.LendPosition
+	if err := json.Unmarshal(data, &rows); err != nil {
+		return err
+	}
+	if len(rows) == 0 {
+		_, err := fmt.Fprintln(w, "no positions")
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TYPE\tASSET\tAMOUNT\tUSD\tAPY %")
+	for _, row := range rows {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%.2f\n", row.PositionType, row.AssetID, row.Amount.AmountDecimal, formatTUIUSD(row.AmountUSD), row.APY)
+	}
+	return tw.Flush()
+}
+
+func tuiActionsRenderer(limit int) func(io.Writer, json.RawMessage) error {
+	return func(w io.Writer, data json.RawMessage) error {
+		var actions []execution.Action
+		if err := json.Unmarshal(data, &actions); err != nil {
+			return err
+		}
+		pending := make([]execution.Action, 0, len(actions))
+		for _, action := range actions {
+			if slices.Contains(tuiPendingStatuses, string(action.Status)) {
+				pending = append(pending, action)
+			}
+		}
+		sort.SliceStable(pending, func(i, j int) bool { return pending[i].UpdatedAt > pending[j].UpdatedAt })
+		if len(pending) > limit {
+			pending = pending[:limit]
+		}
+		if len(pending) == 0 {
+			_, err := fmt.Fprintln(w, "no planned, running, or interrupted actions")
+			return err
+		}
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "ACTION\tINTENT\tSTATUS\tCHAIN\tUPDATED")
+		for _, action := range pending {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", action.ActionID, action.IntentType, action.Status, action.ChainID, action.UpdatedAt)
+		}
+		return tw.Flush()
+	}
+}
+
+// formatTUIUSD abbreviates a dollar amount to thousands, millions, or
+// billions.
+func formatTUIUSD(v float64) string {
+	switch {
+	case v >= 1e9:
+		return fmt.Sprintf("%.2fB", v/1e9)
+	case v >= 1e6:
+		return fmt.Sprintf("%.2fM", v/1e6)
+	case v >= 1e3:
+		return fmt.Sprintf("%.2fK", v/1e3)
+	default:
+		return fmt.Sprintf("%.2f", v)
+	}
+}
+
+// isTerminal reports whether w is a character device such as a terminal.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,84 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ggonzalez94/defi-cli/internal/execution"
+)
+
+func TestTUIOncePrintsPanelsWithPendingActions(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	actionStorePath := filepath.Join(t.TempDir(), "actions.db")
+	actionLockPath := filepath.Join(t.TempDir(), "actions.lock")
+	t.Setenv("DEFI_ACTIONS_PATH", actionStorePath)
+	t.Setenv("DEFI_ACTIONS_LOCK_PATH", actionLockPath)
+
+	store, err := execution.OpenStore(actionStorePath, actionLockPath)
+	if err != nil {
+		t.Fatalf("open action store: %v", err)
+	}
+	planned := execution.NewAction("act_0123456789abcdef0123456789abcdef", "swap", "eip155:8453", execution.Constraints{})
+	done := execution.NewAction("act_fedcba9876543210fedcba9876543210", "transfer", "eip155:8453", execution.Constraints{})
+	done.Status = execution.ActionStatusCompleted
+	interrupted := execution.NewAction("act_00112233445566778899aabbccddeeff", "bridge", "eip155:8453", execution.Constraints{})
+	interrupted.Status = execution.ActionStatusInterrupted
+	for _, action := range []execution.Action{planned, done, interrupted} {
+		if err := store.Save(action); err != nil {
+			t.Fatalf("save action: %v", err)
+		}
+	}
+	_ = store.Close()
+
+	var stdout, stderr bytes.Buffer
+	r := NewRunnerWithWriters(&stdout, &stderr)
+	if code := r.Run([]string{"tui", "--once", "--offline", "--chain", "base"}); code != 0 {
+		t.Fatalf("tui --once failed: %d stderr=%s", code, stderr.String())
+	}
+	out := stdout.String()
+	for _, want := range []string{"== Top yields ==", "unavailable:", "set --address to show lending positions", planned.ActionID, interrupted.ActionID} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in frame:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, done.ActionID) || strings.Contains(out, tuiClearScreen) {
+		t.Fatalf("expected only pending actions and no screen control in --once output:\n%s", out)
+	}
+
+	stdout.Reset()
+	stderr.Reset()
+	if code := r.Run([]string{"tui"}); code != 2 || !strings.Contains(stderr.String(), "--once") {
+		t.Fatalf("expected a usage error off a terminal, got %d stderr=%s", code, stderr.String())
+	}
+}
+
+func TestTUIActionsRendererKeepsNewestPending(t *testing.T) {
+	actions := []execution.Action{
+		{ActionID: "act_old", Status: execution.ActionStatusPlanned, UpdatedAt: "2026-01-01T00:00:00Z"},
+		{ActionID: "act_done", Status: execution.ActionStatusCompleted, UpdatedAt: "2026-01-04T00:00:00Z"},
+		{ActionID: "act_interrupted", Status: execution.ActionStatusInterrupted, UpdatedAt: "2026-01-03T00:00:00Z"},
+		{ActionID: "act_running", Status: execution.ActionStatusRunning, UpdatedAt: "2026-01-02T00:00:00Z"},
+	}
+	data, err := json.Marshal(actions)
+	if err != nil {
+		t.Fatalf("marshal actions: %v", err)
+	}
+	var out bytes.Buffer
+	if err := tuiActionsRenderer(2)(&out, data); err != nil {
+		t.Fatalf("render actions: %v", err)
+	}
+	frame := out.String()
+	if !strings.Contains(frame, "act_interrupted") || !strings.Contains(frame, "act_running") {
+		t.Fatalf("expected the two newest pending actions:\n%s", frame)
+	}
+	if strings.Contains(frame, "act_old") || strings.Contains(frame, "act_done") {
+		t.Fatalf("expected older and finished actions to be left out:\n%s", frame)
+	}
+	if strings.Index(frame, "act_interrupted") > strings.Index(frame, "act_running") {
+		t.Fatalf("expected newest first:\n%s", frame)
+	}
+}
@@ -0,0 +1,76 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/providers"
+)
+
+// parseTVLChangeWindow validates --change-window and --alert-drop-pct. It
+// returns an empty window when neither is set; an alert alone compares
+// against 24h ago.
+func parseTVLChangeWindow(windowArg string, alertDropPct float64) (providers.MetricsWindow, error) {
+	if alertDropPct < 0 || alertDropPct > 100 {
+		return "", clierr.New(clierr.CodeUsage, "--alert-drop-pct must be between 0 and 100")
+	}
+	switch strings.ToLower(strings.TrimSpace(windowArg)) {
+	case "":
+		if alertDropPct > 0 {
+			return providers.MetricsWindow24h, nil
+		}
+		return "", nil
+	case "24h", "1d":
+		return providers.MetricsWindow24h, nil
+	case "7d":
+		return providers.MetricsWindow7d, nil
+	default:
+		return "", clierr.New(clierr.CodeUsage, "--change-window must be one of: 24h,7d")
+	}
+}
+
+// tvlChangeRow is the change columns of one ranked chain or protocol.
+type tvlChangeRow struct {
+	changePct *float64
+	dropAlert bool
+}
+
+// annotateTVLChanges returns the change columns for names in order and the
+// warnings for rows without history and rows that dropped by more than
+// alertDropPct.
+func annotateTVLChanges(kind string, names []string, changes map[string]float64, window providers.MetricsWindow, alertDropPct float64) ([]tvlChangeRow, []string) {
+	rows := make([]tvlChangeRow, len(names))
+	missing := []string{}
+	warnings := []string{}
+	for i, name := range names {
+		change, ok := changes[name]
+		if !ok {
+			missing = append(missing, name)
+			continue
+		}
+		rows[i].changePct = &change
+		if alertDropPct > 0 && change <= -alertDropPct {
+			rows[i].dropAlert = true
+			warnings = append(warnings, fmt.Sprintf("%s %s TVL dropped %.2f%% over %s (--alert-drop-pct %g)", kind, name, -change, window, alertDropPct))
+		}
+	}
+	if len(missing) > 0 {
+		warnings = append(warnings, fmt.Sprintf("no %s TVL change for %s: %s", window, kind+"s", strings.Join(missing, ", ")))
+	}
+	return rows, warnings
+}
+
+// fetchTVLChanges calls fetch on the market provider. A failure becomes a
+// warning so the ranking is still returned, marked partial.
+func (s *runtimeState) fetchTVLChanges(fetch func(providers.TVLChangeProvider) (map[string]float64, error)) (map[string]float64, []string, bool) {
+	provider, ok := s.marketProvider.(providers.TVLChangeProvider)
+	if !ok {
+		return nil, []string{"tvl change unavailable: market data provider does not report TVL history"}, true
+	}
+	changes, err := fetch(provider)
+	if err != nil {
+		return nil, []string{fmt.Sprintf("tvl change unavailable: %v", err)}, true
+	}
+	return changes, nil, false
+}
@@ -0,0 +1,434 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/execution"
+	execsigner "github.com/ggonzalez94/defi-cli/internal/execution/signer"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/registry"
+	"github.com/ggonzalez94/defi-cli/internal/rpcx"
+	"github.com/ggonzalez94/defi-cli/internal/schema"
+	"github.com/spf13/cobra"
+)
+
+// txActionScan bounds how many recent actions are searched for the steps
+// that own a transaction.
+const txActionScan = 500
+
+func (s *runtimeState) newTxCommand() *cobra.Command {
+	root := &cobra.Command{Use: "tx", Short: "Inspect and replace pending transactions"}
+
+	var listChain, listAddress, listRPCURL string
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List action transactions and pending nonces for an address",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			chain, err := parseTxChain(listChain)
+			if err != nil {
+				return err
+			}
+			if !common.IsHexAddress(listAddress) {
+				return clierr.New(clierr.CodeUsage, "--address must be an EVM address")
+			}
+			address := common.HexToAddress(listAddress)
+			if err := s.ensureActionStore(); err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), s.settings.Timeout)
+			defer cancel()
+			client, _, err := dialTxChain(ctx, chain, listRPCURL)
+			if err != nil {
+				return err
+			}
+			defer client.Close()
+
+			latest, err := client.NonceAt(ctx, address, nil)
+			if err != nil {
+				return clierr.Wrap(clierr.CodeUnavailable, "fetch latest nonce", err)
+			}
+			pending, err := client.PendingNonceAt(ctx, address)
+			if err != nil {
+				return clierr.Wrap(clierr.CodeUnavailable, "fetch pending nonce", err)
+			}
+			result := execution.TxList{
+				Address:      address.Hex(),
+				ChainID:      chain.CAIP2,
+				LatestNonce:  latest,
+				PendingNonce: pending,
+				Transactions: []execution.TrackedTx{},
+				FetchedAt:    s.runner.now().UTC().Format(time.RFC3339),
+			}
+			actions, err := s.actionStore.List("", txActionScan)
+			if err != nil {
+				return clierr.Wrap(clierr.CodeInternal, "list actions", err)
+			}
+			var warnings []string
+			tracked := 0
+			for _, action := range actions {
+				if !strings.EqualFold(strings.TrimSpace(action.FromAddress), address.Hex()) {
+					continue
+				}
+				changed := false
+				for i := range action.Steps {
+					step := &action.Steps[i]
+					if step.Status != execution.StepStatusSubmitted || !strings.EqualFold(txStepChainID(action, *step), chain.CAIP2) {
+						continue
+					}
+					entry, resolved, err := trackStepTx(ctx, client, &action, i)
+					if err != nil {
+						return err
+					}
+					changed = changed || resolved
+					if entry.Status == execution.TxStatePending {
+						tracked++
+					}
+					result.Transactions = append(result.Transactions, entry)
+				}
+				if changed {
+					if err := s.actionStore.Save(action); err != nil {
+						return clierr.Wrap(clierr.CodeInternal, "persist action", err)
+					}
+				}
+			}
+			if untracked := int(pending) - int(latest) - tracked; untracked > 0 {
+				warnings = append(warnings, fmt.Sprintf("%d pending transactions for %s are not tracked by the action store", untracked, address.Hex()))
+			}
+			return s.emitSuccess(trimRootPath(cmd.CommandPath()), result, warnings, cacheMetaBypass(), nil, false)
+		},
+	}
+	listCmd.Flags().StringVar(&listChain, "chain", "", "Chain identifier (CAIP-2, chain ID, or slug)")
+	listCmd.Flags().StringVar(&listAddress, "address", "", "Sender address to inspect")
+	listCmd.Flags().StringVar(&listRPCURL, "rpc-url", "", "Override chain default RPC endpoint")
+	_ = listCmd.MarkFlagRequired("chain")
+	_ = listCmd.MarkFlagRequired("address")
+	_ = schema.SetFlagMetadata(listCmd.Flags(), "chain", schema.FlagMetadata{Required: true, Format: "chain"})
+	_ = schema.SetFlagMetadata(listCmd.Flags(), "address", schema.FlagMetadata{Required: true, Format: "evm-address"})
+	_ = schema.SetFlagMetadata(listCmd.Flags(), "rpc-url", schema.FlagMetadata{Format: "url"})
+	listResponse := schema.SchemaFromType(execution.TxList{})
+	_ = schema.SetCommandMetadata(listCmd, schema.CommandMetadata{Response: &listResponse})
+
+	root.AddCommand(listCmd)
+	root.AddCommand(s.newTxReplaceCommand(execution.ReplacementSpeedup, "speedup", "Resend a pending transaction with higher fees"))
+	root.AddCommand(s.newTxReplaceCommand(execution.ReplacementCancel, "cancel", "Replace a pending transaction with a zero-value self-transfer"))
+	return root
+}
+
+func (s *runtimeState) newTxReplaceCommand(kind, use, short string) *cobra.Command {
+	var (
+		chainArg, txHashArg, rpcURLArg     string
+		maxFeeGwei, maxPriorityFeeGwei     string
+		keySource, privateKey, fromAddress string
+		pollInterval, stepTimeout          string
+		feeBumpPct                         float64
+	)
+	cmd := &cobra.Command{
+		Use:   use,
+		Short: short,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			chain, err := parseTxChain(chainArg)
+			if err != nil {
+				return err
+			}
+			txHash, err := parseTxHash(txHashArg)
+			if err != nil {
+				return err
+			}
+			poll, err := time.ParseDuration(strings.TrimSpace(pollInterval))
+			if err != nil || poll <= 0 {
+				return clierr.New(clierr.CodeUsage, "--poll-interval must be a positive duration")
+			}
+			wait, err := time.ParseDuration(strings.TrimSpace(stepTimeout))
+			if err != nil || wait < 0 {
+				return clierr.New(clierr.CodeUsage, "--step-timeout must be a non-negative duration")
+			}
+			if err := s.ensureActionStore(); err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), s.settings.Timeout)
+			defer cancel()
+			client, rpcURL, err := dialTxChain(ctx, chain, rpcURLArg)
+			if err != nil {
+				return err
+			}
+			defer client.Close()
+
+			original, isPending, err := client.TransactionByHash(ctx, txHash)
+			if errors.Is(err, ethereum.NotFound) {
+				return clierr.New(clierr.CodeUsage, fmt.Sprintf("transaction %s not found on %s; it may have been dropped", txHash.Hex(), chain.CAIP2))
+			}
+			if err != nil {
+				return clierr.Wrap(clierr.CodeUnavailable, "fetch transaction", err)
+			}
+			if !isPending {
+				return clierr.New(clierr.CodeUsage, "transaction is already mined; nothing to replace")
+			}
+			from, err := types.Sender(types.LatestSignerForChainID(original.ChainId()), original)
+			if err != nil {
+				return clierr.Wrap(clierr.CodeUsage, "recover transaction sender", err)
+			}
+
+			// The owning action, when there is one, decides the submit backend
+			// and receives the replacement.
+			action, stepIndex, found, err := s.findTxAction(txHash.Hex())
+			if err != nil {
+				return err
+			}
+			resolvedExec, err := resolveActionExecutionBackend(cmd, action, submitExecutionInputs{
+				Signer:      "local",
+				KeySource:   keySource,
+				PrivateKey:  privateKey,
+				FromAddress: fromAddress,
+			})
+			if err != nil {
+				return err
+			}
+			if resolvedExec.evmBackend == nil {
+				return clierr.New(clierr.CodeUnsupported, "tx "+use+" does not support Tempo actions")
+			}
+			if sender := resolvedExec.evmBackend.EffectiveSender(); sender != from {
+				return clierr.New(clierr.CodeSigner, fmt.Sprintf("signer %s does not control transaction sender %s", sender.Hex(), from.Hex()))
+			}
+
+			replacement, err := execution.BuildReplacementTx(ctx, client, original, from, kind, execution.ReplaceOptions{
+				BumpPct:            feeBumpPct,
+				MaxFeeGwei:         maxFeeGwei,
+				MaxPriorityFeeGwei: maxPriorityFeeGwei,
+			})
+			if err != nil {
+				return err
+			}
+			replacementHash, err := resolvedExec.evmBackend.SubmitDynamicFeeTx(ctx, rpcURL, original.ChainId(), replacement)
+			if err != nil {
+				return err
+			}
+			now := s.runner.now().UTC().Format(time.RFC3339)
+			result := execution.TxReplacementResult{
+				Kind:                 kind,
+				ChainID:              chain.CAIP2,
+				From:                 from.Hex(),
+				Nonce:                original.Nonce(),
+				OriginalTxHash:       txHash.Hex(),
+				TxHash:               replacementHash.Hex(),
+				MaxFeePerGas:         replacement.GasFeeCap().String(),
+				MaxPriorityFeePerGas: replacement.GasTipCap().String(),
+				Status:               execution.TxStatePending,
+				SubmittedAt:          now,
+			}
+			candidates := []string{txHash.Hex(), replacementHash.Hex()}
+			if found {
+				result.ActionID = action.ActionID
+				result.StepID = action.Steps[stepIndex].StepID
+				action.RecordReplacement(stepIndex, execution.TxReplacement{
+					Kind:           kind,
+					OriginalTxHash: txHash.Hex(),
+					TxHash:         replacementHash.Hex(),
+					CreatedAt:      now,
+				})
+				if err := s.actionStore.Save(action); err != nil {
+					return clierr.Wrap(clierr.CodeInternal, "persist replacement", err)
+				}
+				candidates = action.Steps[stepIndex].CandidateTxHashes()
+			}
+
+			var warnings []string
+			waitCtx, waitCancel := context.WithTimeout(cmd.Context(), wait)
+			defer waitCancel()
+			mined, err := awaitMinedTx(waitCtx, client, candidates, poll)
+			if err != nil {
+				warnings = append(warnings, fmt.Sprintf("no transaction at nonce %d was mined within %s; run tx list to refresh its status", original.Nonce(), wait))
+				return s.emitSuccess(trimRootPath(cmd.CommandPath()), result, warnings, cacheMetaBypass(), nil, false)
+			}
+			result.MinedTxHash = mined
+			result.Status = execution.TxStateDropped
+			if strings.EqualFold(mined, replacementHash.Hex()) {
+				result.Status = execution.TxStateMined
+			}
+			if found {
+				action.ResolveReplacements(stepIndex, mined)
+				if err := s.actionStore.Save(action); err != nil {
+					return clierr.Wrap(clierr.CodeInternal, "persist replacement outcome", err)
+				}
+				if action.Steps[stepIndex].Status == execution.StepStatusFailed {
+					warnings = append(warnings, fmt.Sprintf("step %s was cancelled; run actions resume --action-id %s to rebuild it", result.StepID, action.ActionID))
+				} else {
+					warnings = append(warnings, fmt.Sprintf("run actions resume --action-id %s to confirm step %s and continue", action.ActionID, result.StepID))
+				}
+			}
+			if result.Status == execution.TxStateDropped {
+				warnings = append(warnings, "the original transaction was mined before the replacement")
+			}
+			return s.emitSuccess(trimRootPath(cmd.CommandPath()), result, warnings, cacheMetaBypass(), nil, false)
+		},
+	}
+	cmd.Flags().StringVar(&chainArg, "chain", "", "Chain identifier (CAIP-2, chain ID, or slug)")
+	cmd.Flags().StringVar(&txHashArg, "tx-hash", "", "Hash of the pending transaction to replace")
+	cmd.Flags().StringVar(&rpcURLArg, "rpc-url", "", "Override chain default RPC endpoint")
+	cmd.Flags().Float64Var(&feeBumpPct, "fee-bump-pct", 20, "Percent to raise both fee caps over the original (min 10)")
+	cmd.Flags().StringVar(&maxFeeGwei, "max-fee-gwei", "", "Optional minimum EIP-1559 max fee for the replacement (gwei)")
+	cmd.Flags().StringVar(&maxPriorityFeeGwei, "max-priority-fee-gwei", "", "Optional minimum EIP-1559 max priority fee for the replacement (gwei)")
+	cmd.Flags().StringVar(&keySource, "key-source", execsigner.KeySourceAuto, "Key source (auto|env|file|keystore)")
+	cmd.Flags().StringVar(&privateKey, "private-key", "", "Private key hex override for local signer (less safe)")
+	cmd.Flags().StringVar(&fromAddress, "from-address", "", "Expected sender EOA address")
+	cmd.Flags().StringVar(&pollInterval, "poll-interval", "2s", "Receipt polling interval")
+	cmd.Flags().StringVar(&stepTimeout, "step-timeout", "2m", "How long to wait for either transaction to be mined (0 returns immediately)")
+	_ = cmd.MarkFlagRequired("chain")
+	_ = cmd.MarkFlagRequired("tx-hash")
+	_ = schema.SetFlagMetadata(cmd.Flags(), "chain", schema.FlagMetadata{Required: true, Format: "chain"})
+	_ = schema.SetFlagMetadata(cmd.Flags(), "tx-hash", schema.FlagMetadata{Required: true, Format: "tx-hash"})
+	_ = schema.SetFlagMetadata(cmd.Flags(), "rpc-url", schema.FlagMetadata{Format: "url"})
+	_ = schema.SetFlagMetadata(cmd.Flags(), "key-source", schema.FlagMetadata{Enum: []string{execsigner.KeySourceAuto, execsigner.KeySourceEnv, execsigner.KeySourceFile, execsigner.KeySourceKeystore}})
+	_ = schema.SetFlagMetadata(cmd.Flags(), "private-key", schema.FlagMetadata{Format: "hex"})
+	_ = schema.SetFlagMetadata(cmd.Flags(), "from-address", schema.FlagMetadata{Format: "evm-address"})
+	_ = schema.SetFlagMetadata(cmd.Flags(), "poll-interval", schema.FlagMetadata{Format: "duration"})
+	_ = schema.SetFlagMetadata(cmd.Flags(), "step-timeout", schema.FlagMetadata{Format: "duration"})
+	response := schema.SchemaFromType(execution.TxReplacementResult{})
+	_ = schema.SetCommandMetadata(cmd, schema.CommandMetadata{Mutation: true, Response: &response})
+	return cmd
+}
+
+// findTxAction returns the recent action whose step sent txHash. Without a
+// match the zero action selects the legacy local signer.
+func (s *runtimeState) findTxAction(txHash string) (execution.Action, int, bool, error) {
+	actions, err := s.actionStore.List("", txActionScan)
+	if err != nil {
+		return execution.Action{}, -1, false, clierr.Wrap(clierr.CodeInternal, "list actions", err)
+	}
+	for _, action := range actions {
+		if index, ok := action.StepIndexForTxHash(txHash); ok {
+			return action, index, true, nil
+		}
+	}
+	return execution.Action{}, -1, false, nil
+}
+
+// trackStepTx reports the state of a submitted step's transaction. When any
+// candidate at the step's nonce has been mined, pending replacements are
+// settled and resolved is true.
+func trackStepTx(ctx context.Context, client *ethclient.Client, action *execution.Action, index int) (execution.TrackedTx, bool, error) {
+	step := action.Steps[index]
+	entry := execution.TrackedTx{ActionID: action.ActionID, StepID: step.StepID, TxHash: step.TxHash}
+	mined, err := findMinedTx(ctx, client, step.CandidateTxHashes())
+	if err != nil {
+		return entry, false, err
+	}
+	resolved := false
+	if mined != "" {
+		if step.PendingReplacement() != nil {
+			action.ResolveReplacements(index, mined)
+			resolved = true
+		}
+		entry.TxHash = mined
+		entry.Status = execution.TxStateMined
+	}
+	if len(action.Steps[index].Replacements) > 0 {
+		last := action.Steps[index].Replacements[len(action.Steps[index].Replacements)-1]
+		entry.Replacement = &last
+	}
+	if entry.TxHash == "" {
+		return entry, resolved, nil
+	}
+	tx, isPending, err := client.TransactionByHash(ctx, common.HexToHash(entry.TxHash))
+	switch {
+	case errors.Is(err, ethereum.NotFound):
+		if entry.Status == "" {
+			entry.Status = execution.TxStateDropped
+		}
+		return entry, resolved, nil
+	case err != nil:
+		return entry, resolved, clierr.Wrap(clierr.CodeUnavailable, "fetch transaction", err)
+	}
+	nonce := tx.Nonce()
+	entry.Nonce = &nonce
+	entry.MaxFeePerGas = tx.GasFeeCap().String()
+	entry.MaxPriorityFeePerGas = tx.GasTipCap().String()
+	if entry.Status == "" {
+		entry.Status = execution.TxStateMined
+		if isPending {
+			entry.Status = execution.TxStatePending
+		}
+	}
+	return entry, resolved, nil
+}
+
+// findMinedTx returns the first of hashes with a receipt, or "" when none is
+// mined yet.
+func findMinedTx(ctx context.Context, client *ethclient.Client, hashes []string) (string, error) {
+	for _, hash := range hashes {
+		receipt, err := client.TransactionReceipt(ctx, common.HexToHash(hash))
+		if errors.Is(err, ethereum.NotFound) {
+			continue
+		}
+		if err != nil {
+			return "", clierr.Wrap(clierr.CodeUnavailable, "fetch transaction receipt", err)
+		}
+		if receipt != nil {
+			return common.HexToHash(hash).Hex(), nil
+		}
+	}
+	return "", nil
+}
+
+// awaitMinedTx polls until one of hashes is mined or ctx ends. Transient RPC
+// failures are retried until then.
+func awaitMinedTx(ctx context.Context, client *ethclient.Client, hashes []string, interval time.Duration) (string, error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if mined, err := findMinedTx(ctx, client, hashes); err == nil && mined != "" {
+			return mined, nil
+		}
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func parseTxChain(chainArg string) (id.Chain, error) {
+	chain, err := id.ParseChain(chainArg)
+	if err != nil {
+		return id.Chain{}, err
+	}
+	if !chain.IsEVM() {
+		return id.Chain{}, clierr.New(clierr.CodeUnsupported, "tx commands support only EVM chains")
+	}
+	return chain, nil
+}
+
+func parseTxHash(raw string) (common.Hash, error) {
+	raw = strings.TrimSpace(raw)
+	if !strings.HasPrefix(raw, "0x") || len(common.FromHex(raw)) != common.HashLength || len(raw) != 2+2*common.HashLength {
+		return common.Hash{}, clierr.New(clierr.CodeUsage, "--tx-hash must be a 0x-prefixed 32-byte hash")
+	}
+	return common.HexToHash(raw), nil
+}
+
+func dialTxChain(ctx context.Context, chain id.Chain, rpcURLArg string) (*ethclient.Client, string, error) {
+	rpcURL, err := registry.ResolveRPCURL(rpcURLArg, chain.EVMChainID)
+	if err != nil {
+		return nil, "", clierr.Wrap(clierr.CodeUsage, "resolve rpc url", err)
+	}
+	client, err := rpcx.DialContext(ctx, rpcURL)
+	if err != nil {
+		return nil, "", clierr.Wrap(clierr.CodeUnavailable, "connect rpc", err)
+	}
+	return client, rpcURL, nil
+}
+
+func txStepChainID(action execution.Action, step execution.ActionStep) string {
+	if chainID := strings.TrimSpace(step.ChainID); chainID != "" {
+		return chainID
+	}
+	return strings.TrimSpace(action.ChainID)
+}
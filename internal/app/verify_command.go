@@ -0,0 +1,411 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/execution"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/model"
+	"github.com/ggonzalez94/defi-cli/internal/pricecheck"
+	"github.com/ggonzalez94/defi-cli/internal/providers"
+	"github.com/ggonzalez94/defi-cli/internal/providers/yieldutil"
+	"github.com/ggonzalez94/defi-cli/internal/registry"
+	"github.com/ggonzalez94/defi-cli/internal/rpcx"
+	"github.com/ggonzalez94/defi-cli/internal/schema"
+	"github.com/spf13/cobra"
+)
+
+func (s *runtimeState) newVerifyCommand() *cobra.Command {
+	root := &cobra.Command{Use: "verify", Short: "Cross-provider data consistency checks"}
+
+	var chainArg, assetArg, providersArg string
+	apyCmd := &cobra.Command{
+		Use:   "apy",
+		Short: "Compare lending APYs for the same asset across providers and flag unit mismatches",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			chain, asset, err := parseChainAsset(chainArg, assetArg)
+			if err != nil {
+				return err
+			}
+			selected, err := s.selectVerifyProviders(splitCSV(providersArg), chain)
+			if err != nil {
+				return err
+			}
+			if len(selected) == 0 {
+				return clierr.New(clierr.CodeUnsupported, fmt.Sprintf("no lending providers support %s", chain.CAIP2))
+			}
+
+			key := cacheKey(trimRootPath(cmd.CommandPath()), map[string]any{
+				"chain":     chain.CAIP2,
+				"asset":     asset.AssetID,
+				"providers": selected,
+			})
+			return s.runCachedCommand(trimRootPath(cmd.CommandPath()), key, 30*time.Second, func(ctx context.Context) (any, []model.ProviderStatus, []string, bool, error) {
+				statuses := make([]model.ProviderStatus, 0, len(selected))
+				warnings := []string{}
+				samples := make([]model.APYSample, 0, len(selected))
+				partial := false
+				var firstErr error
+				for _, name := range selected {
+					provider := s.lendingProviders[name]
+					start := time.Now()
+					rates, err := provider.LendRates(ctx, name, chain, asset)
+					statuses = append(statuses, model.ProviderStatus{Name: provider.Info().Name, Status: statusFromErr(err), LatencyMS: time.Since(start).Milliseconds()})
+					if err != nil {
+						warnings = append(warnings, fmt.Sprintf("provider %s failed: %v", name, err))
+						partial = true
+						if firstErr == nil {
+							firstErr = err
+						}
+						continue
+					}
+					samples = append(samples, apySample(name, rates))
+				}
+				if len(samples) == 0 {
+					return nil, statuses, warnings, false, firstErr
+				}
+				if len(samples) < 2 {
+					warnings = append(warnings, "fewer than two providers returned rates; nothing to compare")
+				}
+
+				report := model.APYConsistencyReport{
+					ChainID:       chain.CAIP2,
+					AssetID:       asset.AssetID,
+					Threshold:     yieldutil.APYDiscrepancyThreshold,
+					Samples:       samples,
+					Discrepancies: compareAPYSamples(samples),
+					FetchedAt:     s.runner.now().UTC().Format(time.RFC3339),
+				}
+				report.Consistent = len(report.Discrepancies) == 0
+				for _, d := range report.Discrepancies {
+					warnings = append(warnings, fmt.Sprintf("%s differs %.1fx between %s (%.4g%%) and %s (%.4g%%); likely percent-vs-fraction unit bug", d.Metric, d.Ratio, d.ProviderA, d.ValueA, d.ProviderB, d.ValueB))
+				}
+				return report, statuses, warnings, partial, nil
+			})
+		},
+	}
+	apyCmd.Flags().StringVar(&chainArg, "chain", "", "Chain identifier")
+	apyCmd.Flags().StringVar(&assetArg, "asset", "", "Asset symbol/address/CAIP-19")
+	apyCmd.Flags().StringVar(&providersArg, "providers", "", "Lending providers to compare (aave,morpho,kamino,moonwell,spark); defaults to all that support the chain")
+	_ = apyCmd.MarkFlagRequired("chain")
+	_ = apyCmd.MarkFlagRequired("asset")
+	apyResponse := schema.SchemaFromType(model.APYConsistencyReport{})
+	_ = schema.SetCommandMetadata(apyCmd, schema.CommandMetadata{Response: &apyResponse})
+	root.AddCommand(apyCmd)
+
+	var actionID, quoteFile, rpcURL string
+	var maxDeviationPct float64
+	quoteCmd := &cobra.Command{
+		Use:   "quote",
+		Short: "Re-price a saved swap quote or planned swap against independent on-chain references",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if (strings.TrimSpace(actionID) == "") == (strings.TrimSpace(quoteFile) == "") {
+				return clierr.New(clierr.CodeUsage, "exactly one of --action-id or --quote-file is required")
+			}
+			if maxDeviationPct <= 0 {
+				return clierr.New(clierr.CodeUsage, "--max-deviation-pct must be > 0")
+			}
+			var subject quoteSubject
+			var err error
+			if strings.TrimSpace(actionID) != "" {
+				if err := s.ensureActionStore(); err != nil {
+					return err
+				}
+				action, err := s.actionStore.Get(strings.TrimSpace(actionID))
+				if err != nil {
+					return clierr.Wrap(clierr.CodeUsage, "load action", err)
+				}
+				subject, err = quoteSubjectFromAction(action)
+				if err != nil {
+					return err
+				}
+			} else {
+				buf, err := os.ReadFile(quoteFile)
+				if err != nil {
+					return clierr.Wrap(clierr.CodeUsage, "read --quote-file", err)
+				}
+				var quote model.SwapQuote
+				if err := json.Unmarshal(buf, &quote); err != nil {
+					return clierr.Wrap(clierr.CodeUsage, "parse --quote-file as a swap quote", err)
+				}
+				subject, err = quoteSubjectFromSwapQuote(quote)
+				if err != nil {
+					return err
+				}
+			}
+			resolvedRPC, err := registry.ResolveRPCURL(rpcURL, subject.chain.EVMChainID)
+			if err != nil {
+				return clierr.Wrap(clierr.CodeUsage, "resolve rpc url", err)
+			}
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), s.settings.Timeout)
+			defer cancel()
+			client, err := rpcx.DialContext(ctx, resolvedRPC)
+			if err != nil {
+				return clierr.Wrap(clierr.CodeUnavailable, "connect rpc", err)
+			}
+			defer client.Close()
+
+			now := s.runner.now().UTC()
+			report := model.QuoteVerification{
+				Source:          subject.source,
+				ActionID:        subject.actionID,
+				Provider:        subject.provider,
+				ChainID:         subject.chain.CAIP2,
+				FromAssetID:     subject.in.AssetID,
+				ToAssetID:       subject.out.AssetID,
+				InputAmount:     amountInfo(subject.amountIn, subject.in.Decimals),
+				QuotedOut:       amountInfo(subject.quotedOut, subject.out.Decimals),
+				MaxDeviationPct: maxDeviationPct,
+				References:      []model.QuoteReference{},
+				FetchedAt:       now.Format(time.RFC3339),
+			}
+			var warnings []string
+			statuses := make([]model.ProviderStatus, 0, 2)
+			checks := []struct {
+				source string
+				run    func() (pricecheck.Reference, error)
+			}{
+				{pricecheck.SourceUniswapV3, func() (pricecheck.Reference, error) {
+					return pricecheck.UniswapV3(ctx, client, subject.chain.EVMChainID, subject.in, subject.out, subject.amountIn)
+				}},
+				{pricecheck.SourceChainlink, func() (pricecheck.Reference, error) {
+					return pricecheck.Chainlink(ctx, client, subject.chain.EVMChainID, subject.in, subject.out, subject.amountIn, now)
+				}},
+			}
+			for _, check := range checks {
+				start := time.Now()
+				ref, err := check.run()
+				statuses = append(statuses, model.ProviderStatus{Name: check.source, Status: statusFromErr(err), LatencyMS: time.Since(start).Milliseconds()})
+				if err != nil {
+					warnings = append(warnings, fmt.Sprintf("%s reference unavailable: %v", check.source, err))
+					continue
+				}
+				deviation := pricecheck.DeviationPct(subject.quotedOut, ref.AmountOut)
+				within := math.Abs(deviation) <= maxDeviationPct
+				report.References = append(report.References, model.QuoteReference{
+					Source:          ref.Source,
+					EstimatedOut:    amountInfo(ref.AmountOut, subject.out.Decimals),
+					DeviationPct:    deviation,
+					WithinThreshold: within,
+					Detail:          ref.Detail,
+				})
+				if !within {
+					warnings = append(warnings, fmt.Sprintf("quoted output deviates %+.2f%% from %s reference (limit %.2f%%)", deviation, ref.Source, maxDeviationPct))
+				}
+			}
+			report.Verified = len(report.References) > 0
+			for _, ref := range report.References {
+				report.Verified = report.Verified && ref.WithinThreshold
+			}
+			if len(report.References) == 0 {
+				warnings = append(warnings, "no independent reference could price this swap; quote is unverified")
+			}
+			s.captureCommandDiagnostics(nil, statuses, false)
+			return s.emitSuccess(trimRootPath(cmd.CommandPath()), report, warnings, cacheMetaBypass(), statuses, false)
+		},
+	}
+	quoteCmd.Flags().StringVar(&actionID, "action-id", "", "Planned swap action to verify")
+	quoteCmd.Flags().StringVar(&quoteFile, "quote-file", "", "Path to a saved swap quote JSON (swap quote --results-only output)")
+	quoteCmd.Flags().StringVar(&rpcURL, "rpc-url", "", "RPC URL override for on-chain references")
+	quoteCmd.Flags().Float64Var(&maxDeviationPct, "max-deviation-pct", 2, "Largest deviation from a reference, in percent, that still counts as verified")
+	_ = schema.SetFlagMetadata(quoteCmd.Flags(), "action-id", schema.FlagMetadata{Format: "action-id"})
+	_ = schema.SetFlagMetadata(quoteCmd.Flags(), "quote-file", schema.FlagMetadata{Format: "path"})
+	_ = schema.SetFlagMetadata(quoteCmd.Flags(), "rpc-url", schema.FlagMetadata{Format: "url"})
+	quoteResponse := schema.SchemaFromType(model.QuoteVerification{})
+	_ = schema.SetCommandMetadata(quoteCmd, schema.CommandMetadata{
+		InputConstraints: []schema.InputConstraint{{
+			Kind:        "exactly_one_of",
+			Fields:      []string{"action_id", "quote_file"},
+			Description: "Verify either a planned swap (`action_id`) or a saved swap quote (`quote_file`).",
+		}},
+		Response: &quoteResponse,
+	})
+	root.AddCommand(quoteCmd)
+
+	return root
+}
+
+// quoteSubject is the swap under verification, normalized from either a
+// planned action or a saved quote.
+type quoteSubject struct {
+	source    string
+	actionID  string
+	provider  string
+	chain     id.Chain
+	in        id.Asset
+	out       id.Asset
+	amountIn  *big.Int
+	quotedOut *big.Int
+}
+
+func quoteSubjectFromAction(action execution.Action) (quoteSubject, error) {
+	if action.IntentType != "swap" {
+		return quoteSubject{}, clierr.New(clierr.CodeUsage, "action is not a swap intent")
+	}
+	if _, multi := action.Metadata["tokens_in"]; multi {
+		return quoteSubject{}, clierr.New(clierr.CodeUnsupported, "multi-input swaps cannot be verified yet")
+	}
+	tokenIn, _ := action.Metadata["token_in"].(string)
+	tokenOut, _ := action.Metadata["token_out"].(string)
+	amountIn := action.InputAmount
+	quoted, _ := action.Metadata["quoted_amount"].(string)
+	if quoted == "" {
+		// Tempo records its own quote keys, and exact-output plans quote the input.
+		quoted, _ = action.Metadata["quoted_amount_out"].(string)
+		if desired, _ := action.Metadata["desired_amount_out"].(string); desired != "" {
+			quoted = desired
+			amountIn, _ = action.Metadata["quoted_amount_in"].(string)
+		}
+	}
+	if tokenIn == "" || tokenOut == "" || quoted == "" {
+		return quoteSubject{}, clierr.New(clierr.CodeUnsupported, "action does not record token_in, token_out, and quoted_amount")
+	}
+	return newQuoteSubject("action", action.ActionID, action.Provider, action.ChainID, tokenIn, tokenOut, amountIn, quoted)
+}
+
+func quoteSubjectFromSwapQuote(quote model.SwapQuote) (quoteSubject, error) {
+	if quote.TradeType != "" && quote.TradeType != string(providers.SwapTradeTypeExactInput) {
+		return quoteSubject{}, clierr.New(clierr.CodeUnsupported, "only exact-input quotes can be verified")
+	}
+	if len(quote.InputLegs) > 1 {
+		return quoteSubject{}, clierr.New(clierr.CodeUnsupported, "multi-input swaps cannot be verified yet")
+	}
+	return newQuoteSubject("quote", "", quote.Provider, quote.ChainID, quote.FromAssetID, quote.ToAssetID, quote.InputAmount.AmountBaseUnits, quote.EstimatedOut.AmountBaseUnits)
+}
+
+func newQuoteSubject(source, actionID, provider, chainArg, inArg, outArg, amountIn, quotedOut string) (quoteSubject, error) {
+	chain, err := id.ParseChain(chainArg)
+	if err != nil {
+		return quoteSubject{}, err
+	}
+	if !chain.IsEVM() {
+		return quoteSubject{}, clierr.New(clierr.CodeUnsupported, "verify quote supports only EVM chains")
+	}
+	in, err := id.ParseAsset(inArg, chain)
+	if err != nil {
+		return quoteSubject{}, err
+	}
+	out, err := id.ParseAsset(outArg, chain)
+	if err != nil {
+		return quoteSubject{}, err
+	}
+	amount, ok := new(big.Int).SetString(strings.TrimSpace(amountIn), 10)
+	if !ok || amount.Sign() <= 0 {
+		return quoteSubject{}, clierr.New(clierr.CodeUsage, "quote input amount must be a positive integer in base units")
+	}
+	quoted, ok := new(big.Int).SetString(strings.TrimSpace(quotedOut), 10)
+	if !ok || quoted.Sign() <= 0 {
+		return quoteSubject{}, clierr.New(clierr.CodeUsage, "quoted output must be a positive integer in base units")
+	}
+	return quoteSubject{source: source, actionID: actionID, provider: provider, chain: chain, in: in, out: out, amountIn: amount, quotedOut: quoted}, nil
+}
+
+func amountInfo(amount *big.Int, decimals int) model.AmountInfo {
+	return model.AmountInfo{
+		AmountBaseUnits: amount.String(),
+		AmountDecimal:   id.FormatDecimalCompat(amount.String(), decimals),
+		Decimals:        decimals,
+	}
+}
+
+func (s *runtimeState) selectVerifyProviders(filter []string, chain id.Chain) ([]string, error) {
+	if len(filter) == 0 {
+		names := make([]string, 0, len(s.lendingProviders))
+		for name := range s.lendingProviders {
+			if yieldProviderSupportsChain(name, chain) {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+		return names, nil
+	}
+	seen := map[string]struct{}{}
+	names := make([]string, 0, len(filter))
+	for _, item := range filter {
+		name := normalizeLendingProvider(item)
+		if _, ok := s.lendingProviders[name]; !ok {
+			return nil, clierr.New(clierr.CodeUsage, fmt.Sprintf("unsupported lending provider: %s", item))
+		}
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		seen[name] = struct{}{}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func apySample(provider string, rates []model.LendRate) model.APYSample {
+	supply := make([]float64, 0, len(rates))
+	borrow := make([]float64, 0, len(rates))
+	for _, rate := range rates {
+		if rate.SupplyAPY > 0 {
+			supply = append(supply, rate.SupplyAPY)
+		}
+		if rate.BorrowAPY > 0 {
+			borrow = append(borrow, rate.BorrowAPY)
+		}
+	}
+	return model.APYSample{
+		Provider:        provider,
+		Markets:         len(rates),
+		MedianSupplyAPY: median(supply),
+		MedianBorrowAPY: median(borrow),
+	}
+}
+
+// compareAPYSamples flags provider pairs whose median APYs differ by more
+// than yieldutil.APYDiscrepancyThreshold.
+func compareAPYSamples(samples []model.APYSample) []model.APYDiscrepancy {
+	out := []model.APYDiscrepancy{}
+	for i := 0; i < len(samples); i++ {
+		for j := i + 1; j < len(samples); j++ {
+			a, b := samples[i], samples[j]
+			for _, metric := range []struct {
+				name string
+				a, b float64
+			}{
+				{"supply_apy", a.MedianSupplyAPY, b.MedianSupplyAPY},
+				{"borrow_apy", a.MedianBorrowAPY, b.MedianBorrowAPY},
+			} {
+				ratio := yieldutil.APYDiscrepancy(metric.a, metric.b)
+				if ratio <= yieldutil.APYDiscrepancyThreshold {
+					continue
+				}
+				out = append(out, model.APYDiscrepancy{
+					Metric:    metric.name,
+					ProviderA: a.Provider,
+					ProviderB: b.Provider,
+					ValueA:    metric.a,
+					ValueB:    metric.b,
+					Ratio:     ratio,
+				})
+			}
+		}
+	}
+	return out
+}
+
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return sorted[mid]
+	}
+	return (sorted[mid-1] + sorted[mid]) / 2
+}
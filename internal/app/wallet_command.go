@@ -9,11 +9,11 @@ import (
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/ethclient"
 	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
 	"github.com/ggonzalez94/defi-cli/internal/id"
 	"github.com/ggonzalez94/defi-cli/internal/model"
 	"github.com/ggonzalez94/defi-cli/internal/registry"
+	"github.com/ggonzalez94/defi-cli/internal/rpcx"
 	"github.com/ggonzalez94/defi-cli/internal/schema"
 	"github.com/spf13/cobra"
 )
@@ -117,13 +117,13 @@ type walletRPCClient interface {
 
 // fetchBalance queries the on-chain balance for a native token or ERC-20.
 func fetchBalance(ctx context.Context, rpcURL string, chain id.Chain, address common.Address, asset *id.Asset) (model.WalletBalance, error) {
-	client, err := ethclient.DialContext(ctx, rpcURL)
+	client, err := rpcx.DialContext(ctx, rpcURL)
 	if err != nil {
 		return model.WalletBalance{}, fmt.Errorf("dial rpc: %w", err)
 	}
 	defer client.Close()
 
-	if asset == nil {
+	if asset == nil || asset.IsNative() {
 		return fetchNativeBalance(ctx, client, chain, address)
 	}
 	return fetchERC20Balance(ctx, client, chain, address, *asset)
@@ -228,45 +228,38 @@ func fetchERC20Decimals(ctx context.Context, client walletRPCClient, token commo
 }
 
 func nativeAssetID(chain id.Chain) string {
-	_, slip44Ref := nativeAssetInfo(chain)
-	return chain.CAIP2 + "/slip44:" + slip44Ref
+	return chain.CAIP2 + "/slip44:" + nativeSlip44Ref(chain)
 }
 
-func nativeAssetInfo(chain id.Chain) (symbol string, slip44Ref string) {
+// nativeSlip44Ref returns the SLIP-44 coin type of a chain's native token.
+func nativeSlip44Ref(chain id.Chain) string {
 	switch chain.EVMChainID {
-	case 1, 10, 324, 480, 4217, 4326, 31318, 42431, 534352, 57073, 59144, 81457, 167000, 167013, 42161, 8453:
-		return "ETH", "60"
 	case 56:
-		return "BNB", "714"
+		return "714"
 	case 100:
-		return "XDAI", "700"
+		return "700"
 	case 137:
-		return "POL", "966"
+		return "966"
 	case 143:
-		return "MON", "268435779"
+		return "268435779"
 	case 146:
-		return "S", "10007"
-	case 252:
-		return "frxETH", "60"
+		return "10007"
 	case 999:
-		return "HYPE", "2457"
-	case 4114:
-		return "cBTC", "60"
+		return "2457"
 	case 5000:
-		return "MNT", "614"
+		return "614"
 	case 42220:
-		return "CELO", "52752"
+		return "52752"
 	case 43114:
-		return "AVAX", "9000"
+		return "9000"
 	case 80094:
-		return "BERA", "8008"
+		return "8008"
 	default:
-		return "ETH", "60"
+		return "60"
 	}
 }
 
 // nativeSymbol returns the conventional native token symbol for a chain.
 func nativeSymbol(chain id.Chain) string {
-	symbol, _ := nativeAssetInfo(chain)
-	return symbol
+	return id.NativeSymbol(chain)
 }
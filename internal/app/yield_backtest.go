@@ -0,0 +1,371 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/model"
+	"github.com/ggonzalez94/defi-cli/internal/providers"
+	"github.com/ggonzalez94/defi-cli/internal/schema"
+	"github.com/spf13/cobra"
+)
+
+// defaultRotationGasUSD is the assumed cost of one rotation, a withdraw and a
+// deposit, at L2 gas prices.
+const defaultRotationGasUSD = 2.0
+
+// backtestStep is the simulation step; yield history is fetched daily.
+const backtestStep = 24 * time.Hour
+
+// backtestSeries is the apy_total history of one candidate opportunity,
+// sorted by time.
+type backtestSeries struct {
+	opportunity model.YieldOpportunity
+	times       []time.Time
+	apys        []float64
+}
+
+// apyAt returns the last APY observed at or before t, so the simulation never
+// looks ahead. It reports false before the first observation.
+func (b backtestSeries) apyAt(t time.Time) (float64, bool) {
+	i := sort.Search(len(b.times), func(i int) bool { return b.times[i].After(t) })
+	if i == 0 {
+		return 0, false
+	}
+	return b.apys[i-1], true
+}
+
+func newBacktestSeries(opportunity model.YieldOpportunity, points []model.YieldHistoryPoint) backtestSeries {
+	out := backtestSeries{opportunity: opportunity}
+	sorted := append([]model.YieldHistoryPoint(nil), points...)
+	sortYieldHistorySeries([]model.YieldHistorySeries{{Points: sorted}})
+	for _, point := range sorted {
+		at, err := time.Parse(time.RFC3339, point.Timestamp)
+		if err != nil {
+			continue
+		}
+		out.times = append(out.times, at.UTC())
+		out.apys = append(out.apys, point.Value)
+	}
+	return out
+}
+
+func (s *runtimeState) newYieldBacktestCommand() *cobra.Command {
+	var chainArg, assetArg, providersArg, holdArg, windowArg, fromArg, toArg, rotateEveryArg string
+	var amountUSD, rotationGasUSD, minTVLUSD float64
+	var limit int
+	cmd := &cobra.Command{
+		Use:   "backtest",
+		Short: "Backtest holding one yield opportunity against rotating to the best APY",
+		Long: `Replays apy_total history for --asset on --chain over --window (or --from/--to) and compares two strategies for --amount-usd: holding one opportunity for the whole window, and rotating every --rotate-every to whichever candidate had the highest APY at that moment. Each rotation costs --rotation-gas-usd.
+
+Candidates are the top --limit opportunities per provider today, so pools that closed during the window are missing. The held opportunity is --hold, or the best APY at the start of the window. Decisions only use APYs observed up to the decision time; returns compound the APY in effect each day.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			chain, asset, err := parseChainAsset(chainArg, assetArg)
+			if err != nil {
+				return err
+			}
+			startTime, endTime, err := resolveYieldHistoryRange(fromArg, toArg, windowArg, s.runner.now().UTC())
+			if err != nil {
+				return err
+			}
+			rotateEvery, err := parseLookbackWindow(rotateEveryArg)
+			if err != nil {
+				return clierr.Wrap(clierr.CodeUsage, "parse --rotate-every", err)
+			}
+			if rotateEvery < backtestStep {
+				return clierr.New(clierr.CodeUsage, "--rotate-every must be at least 1d")
+			}
+			if amountUSD <= 0 {
+				return clierr.New(clierr.CodeUsage, "--amount-usd must be positive")
+			}
+			if rotationGasUSD < 0 {
+				return clierr.New(clierr.CodeUsage, "--rotation-gas-usd must be non-negative")
+			}
+			providerFilter := splitCSV(providersArg)
+			hold := strings.TrimSpace(holdArg)
+
+			key := cacheKey(trimRootPath(cmd.CommandPath()), map[string]any{
+				"chain":            chain.CAIP2,
+				"asset":            asset.AssetID,
+				"providers":        providerFilter,
+				"hold":             hold,
+				"start_time":       startTime.Format(time.RFC3339),
+				"end_time":         endTime.Format(time.RFC3339),
+				"rotate_every":     rotateEvery.String(),
+				"amount_usd":       amountUSD,
+				"rotation_gas_usd": rotationGasUSD,
+				"min_tvl_usd":      minTVLUSD,
+				"limit":            limit,
+			})
+			return s.runCachedCommand(trimRootPath(cmd.CommandPath()), key, 5*time.Minute, func(ctx context.Context) (any, []model.ProviderStatus, []string, bool, error) {
+				selectedProviders, err := s.selectYieldProviders(providerFilter, chain)
+				if err != nil {
+					return nil, nil, nil, false, err
+				}
+
+				type backtestFetch struct {
+					series   []backtestSeries
+					warnings []string
+					partial  bool
+				}
+				calls := fanOutProviders(ctx, s.settings, selectedProviders, func(ctx context.Context, providerName string) (backtestFetch, error) {
+					provider := s.yieldProviders[providerName]
+					historyProvider, ok := provider.(providers.YieldHistoryProvider)
+					if !ok {
+						return backtestFetch{
+							warnings: []string{fmt.Sprintf("provider %s does not support yield history", provider.Info().Name)},
+							partial:  true,
+						}, clierr.New(clierr.CodeUnsupported, fmt.Sprintf("yield provider %s does not support history", providerName))
+					}
+					opportunities, err := provider.YieldOpportunities(ctx, providers.YieldRequest{
+						Chain:             chain,
+						Asset:             asset,
+						Limit:             limit,
+						MinTVLUSD:         minTVLUSD,
+						SortBy:            "apy_total",
+						IncludeIncomplete: true,
+					})
+					if err != nil {
+						return backtestFetch{
+							warnings: []string{fmt.Sprintf("provider %s failed during opportunity lookup: %v", provider.Info().Name, err)},
+							partial:  true,
+						}, err
+					}
+					out := backtestFetch{}
+					var historyErr error
+					for _, opportunity := range opportunities {
+						series, err := historyProvider.YieldHistory(ctx, providers.YieldHistoryRequest{
+							Opportunity: opportunity,
+							StartTime:   startTime,
+							EndTime:     endTime,
+							Interval:    providers.YieldHistoryIntervalDay,
+							Metrics:     []providers.YieldHistoryMetric{providers.YieldHistoryMetricAPYTotal},
+						})
+						if err != nil {
+							out.partial = true
+							out.warnings = append(out.warnings, fmt.Sprintf("provider %s failed history for opportunity %s: %v", provider.Info().Name, opportunity.OpportunityID, err))
+							if historyErr == nil {
+								historyErr = err
+							}
+							continue
+						}
+						for _, entry := range series {
+							if strings.EqualFold(entry.Metric, string(providers.YieldHistoryMetricAPYTotal)) && len(entry.Points) > 0 {
+								out.series = append(out.series, newBacktestSeries(opportunity, entry.Points))
+								break
+							}
+						}
+					}
+					if len(out.series) == 0 && historyErr == nil {
+						historyErr = clierr.New(clierr.CodeUnavailable, fmt.Sprintf("provider %s returned no historical points", providerName))
+					}
+					return out, historyErr
+				})
+
+				statuses := make([]model.ProviderStatus, 0, len(calls))
+				warnings := []string{}
+				partial := false
+				var firstErr error
+				candidates := []backtestSeries{}
+				for _, call := range calls {
+					statuses = append(statuses, model.ProviderStatus{Name: s.yieldProviders[call.Name].Info().Name, Status: statusFromErr(call.Err), LatencyMS: call.Latency.Milliseconds()})
+					warnings = append(warnings, call.Value.warnings...)
+					partial = partial || call.Value.partial
+					if call.Err != nil && firstErr == nil {
+						firstErr = call.Err
+					}
+					candidates = append(candidates, call.Value.series...)
+				}
+				if len(candidates) == 0 {
+					if firstErr != nil {
+						return nil, statuses, warnings, partial, firstErr
+					}
+					return nil, statuses, warnings, partial, clierr.New(clierr.CodeUnavailable, "no yield history returned by selected providers")
+				}
+				sort.Slice(candidates, func(i, j int) bool {
+					return candidates[i].opportunity.OpportunityID < candidates[j].opportunity.OpportunityID
+				})
+
+				held, err := backtestHoldTarget(candidates, hold, startTime)
+				if err != nil {
+					return nil, statuses, warnings, partial, err
+				}
+				if _, ok := held.apyAt(startTime); !ok {
+					warnings = append(warnings, fmt.Sprintf("opportunity %s has no history at the start of the window; it earns nothing until its first point", held.opportunity.OpportunityID))
+				}
+				result := simulateYieldBacktest(candidates, held, startTime, endTime, rotateEvery, amountUSD, rotationGasUSD)
+				result.ChainID = chain.CAIP2
+				result.AssetID = asset.AssetID
+				result.RotateEvery = rotateEveryArg
+				result.FetchedAt = s.runner.now().UTC().Format(time.RFC3339)
+				if len(candidates) == 1 {
+					warnings = append(warnings, "only one candidate has history; hold and rotate are the same strategy")
+				}
+				return result, statuses, warnings, partial, nil
+			})
+		},
+	}
+	cmd.Flags().StringVar(&chainArg, "chain", "", "Chain identifier")
+	cmd.Flags().StringVar(&assetArg, "asset", "", "Asset symbol/address/CAIP-19")
+	cmd.Flags().StringVar(&providersArg, "providers", "", "Filter by provider names (aave,morpho,kamino)")
+	cmd.Flags().StringVar(&holdArg, "hold", "", "Opportunity ID to hold (default: best APY at the start of the window)")
+	cmd.Flags().StringVar(&windowArg, "window", "90d", "Backtest window (for example 30d,90d,26w)")
+	cmd.Flags().StringVar(&fromArg, "from", "", "Start time (RFC3339). Overrides --window when set")
+	cmd.Flags().StringVar(&toArg, "to", "", "End time (RFC3339). Defaults to now")
+	cmd.Flags().StringVar(&rotateEveryArg, "rotate-every", "7d", "Rotation interval for the rotate strategy (for example 1d,7d,2w)")
+	cmd.Flags().Float64Var(&amountUSD, "amount-usd", 10000, "Starting position size in USD")
+	cmd.Flags().Float64Var(&rotationGasUSD, "rotation-gas-usd", defaultRotationGasUSD, "USD gas cost of one rotation (withdraw and deposit)")
+	cmd.Flags().Float64Var(&minTVLUSD, "min-tvl-usd", 0, "Minimum current TVL in USD for candidates")
+	cmd.Flags().IntVar(&limit, "limit", 10, "Maximum candidate opportunities per provider")
+	_ = cmd.MarkFlagRequired("chain")
+	_ = cmd.MarkFlagRequired("asset")
+	_ = schema.SetFlagMetadata(cmd.Flags(), "rotate-every", schema.FlagMetadata{Format: "duration"})
+	response := schema.SchemaFromType(model.YieldBacktest{})
+	_ = schema.SetCommandMetadata(cmd, schema.CommandMetadata{Response: &response})
+	return cmd
+}
+
+// backtestHoldTarget returns the candidate with opportunity ID hold, or the
+// one with the highest APY at start when hold is empty.
+func backtestHoldTarget(candidates []backtestSeries, hold string, start time.Time) (backtestSeries, error) {
+	if hold != "" {
+		for _, candidate := range candidates {
+			if candidate.opportunity.OpportunityID == hold {
+				return candidate, nil
+			}
+		}
+		return backtestSeries{}, clierr.New(clierr.CodeUsage, fmt.Sprintf("--hold opportunity %s has no history among the candidates; check the ID or raise --limit", hold))
+	}
+	if best, ok := bestBacktestCandidate(candidates, start); ok {
+		return candidates[best], nil
+	}
+	return candidates[0], nil
+}
+
+// bestBacktestCandidate returns the index of the candidate with the highest
+// APY observed at t, or false when none has been observed yet.
+func bestBacktestCandidate(candidates []backtestSeries, t time.Time) (int, bool) {
+	best, bestAPY := -1, math.Inf(-1)
+	for i, candidate := range candidates {
+		if apy, ok := candidate.apyAt(t); ok && apy > bestAPY {
+			best, bestAPY = i, apy
+		}
+	}
+	return best, best >= 0
+}
+
+// simulateYieldBacktest runs the hold and rotate strategies over [start,
+// end) in daily steps. Each step compounds the APY in effect at its start.
+// The rotate strategy re-ranks candidates every rotateEvery and moves, paying
+// gasUSD, only when another candidate's APY is strictly higher.
+func simulateYieldBacktest(candidates []backtestSeries, held backtestSeries, start, end time.Time, rotateEvery time.Duration, amountUSD, gasUSD float64) model.YieldBacktest {
+	hold := newBacktestRun("hold", amountUSD)
+	hold.enter(held, start, held.apyOrZero(start))
+
+	rotate := newBacktestRun("rotate", amountUSD)
+	current := held
+	if best, ok := bestBacktestCandidate(candidates, start); ok {
+		current = candidates[best]
+	}
+	rotate.enter(current, start, current.apyOrZero(start))
+	nextRotation := start.Add(rotateEvery)
+
+	for t := start; t.Before(end); t = t.Add(backtestStep) {
+		if !t.Before(nextRotation) {
+			nextRotation = nextRotation.Add(rotateEvery)
+			currentAPY, _ := current.apyAt(t)
+			if best, ok := bestBacktestCandidate(candidates, t); ok {
+				next := candidates[best]
+				nextAPY, _ := next.apyAt(t)
+				if next.opportunity.OpportunityID != current.opportunity.OpportunityID && nextAPY > currentAPY {
+					rotate.exit(t)
+					rotate.value -= gasUSD
+					rotate.strategy.Rotations++
+					rotate.strategy.GasDragUSD += gasUSD
+					rotate.enter(next, t, nextAPY)
+					current = next
+				}
+			}
+		}
+		years := math.Min(backtestStep.Hours(), end.Sub(t).Hours()) / (365 * 24)
+		hold.grow(held, t, years)
+		rotate.grow(current, t, years)
+	}
+	hold.exit(end)
+	rotate.exit(end)
+
+	years := end.Sub(start).Hours() / (365 * 24)
+	result := model.YieldBacktest{
+		StartTime:      start.UTC().Format(time.RFC3339),
+		EndTime:        end.UTC().Format(time.RFC3339),
+		AmountUSD:      amountUSD,
+		RotationGasUSD: gasUSD,
+		Candidates:     len(candidates),
+		Strategies:     []model.YieldBacktestStrategy{hold.finish(years), rotate.finish(years)},
+	}
+	result.RotationEdgePct = result.Strategies[1].CumulativeReturnPct - result.Strategies[0].CumulativeReturnPct
+	return result
+}
+
+func (b backtestSeries) apyOrZero(t time.Time) float64 {
+	apy, _ := b.apyAt(t)
+	return apy
+}
+
+// backtestRun tracks one strategy's position value and holdings.
+type backtestRun struct {
+	strategy   model.YieldBacktestStrategy
+	amountUSD  float64
+	value      float64
+	entryValue float64
+}
+
+func newBacktestRun(name string, amountUSD float64) *backtestRun {
+	return &backtestRun{
+		strategy:  model.YieldBacktestStrategy{Strategy: name, Holdings: []model.YieldBacktestHolding{}},
+		amountUSD: amountUSD,
+		value:     amountUSD,
+	}
+}
+
+func (r *backtestRun) enter(series backtestSeries, t time.Time, apy float64) {
+	r.entryValue = r.value
+	r.strategy.Holdings = append(r.strategy.Holdings, model.YieldBacktestHolding{
+		OpportunityID: series.opportunity.OpportunityID,
+		Provider:      series.opportunity.Provider,
+		Protocol:      series.opportunity.Protocol,
+		From:          t.UTC().Format(time.RFC3339),
+		APYAtEntry:    apy,
+	})
+}
+
+func (r *backtestRun) exit(t time.Time) {
+	holding := &r.strategy.Holdings[len(r.strategy.Holdings)-1]
+	holding.To = t.UTC().Format(time.RFC3339)
+	if r.entryValue > 0 {
+		holding.ReturnPct = (r.value/r.entryValue - 1) * 100
+	}
+}
+
+func (r *backtestRun) grow(series backtestSeries, t time.Time, years float64) {
+	if apy, ok := series.apyAt(t); ok && r.value > 0 {
+		r.value *= math.Pow(1+apy/100, years)
+	}
+}
+
+func (r *backtestRun) finish(years float64) model.YieldBacktestStrategy {
+	out := r.strategy
+	out.FinalValueUSD = r.value
+	out.CumulativeReturnPct = (r.value/r.amountUSD - 1) * 100
+	if years > 0 && r.value > 0 {
+		out.AnnualizedReturnPct = (math.Pow(r.value/r.amountUSD, 1/years) - 1) * 100
+	}
+	out.GasDragPct = out.GasDragUSD / r.amountUSD * 100
+	return out
+}
@@ -0,0 +1,64 @@
+package app
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/ggonzalez94/defi-cli/internal/model"
+)
+
+func TestSimulateYieldBacktestRotatesToBetterAPYAndPaysGas(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(14 * 24 * time.Hour)
+	daily := func(apy func(day int) float64) []model.YieldHistoryPoint {
+		points := make([]model.YieldHistoryPoint, 0, 14)
+		for day := 0; day < 14; day++ {
+			points = append(points, model.YieldHistoryPoint{Timestamp: start.Add(time.Duration(day) * 24 * time.Hour).Format(time.RFC3339), Value: apy(day)})
+		}
+		return points
+	}
+	steady := newBacktestSeries(model.YieldOpportunity{OpportunityID: "steady", Provider: "aave"}, daily(func(int) float64 { return 5 }))
+	rising := newBacktestSeries(model.YieldOpportunity{OpportunityID: "rising", Provider: "morpho"}, daily(func(day int) float64 {
+		if day < 7 {
+			return 2
+		}
+		return 20
+	}))
+	candidates := []backtestSeries{rising, steady}
+
+	held, err := backtestHoldTarget(candidates, "", start)
+	if err != nil || held.opportunity.OpportunityID != "steady" {
+		t.Fatalf("expected the best APY at the start to be held, got %+v %v", held.opportunity, err)
+	}
+	result := simulateYieldBacktest(candidates, held, start, end, 7*24*time.Hour, 10000, 2)
+
+	hold, rotate := result.Strategies[0], result.Strategies[1]
+	wantHold := 10000 * math.Pow(1.05, 14.0/365)
+	if hold.Strategy != "hold" || math.Abs(hold.FinalValueUSD-wantHold) > 1e-6 || hold.Rotations != 0 || len(hold.Holdings) != 1 {
+		t.Fatalf("unexpected hold strategy %+v (want final %f)", hold, wantHold)
+	}
+	wantRotate := (10000*math.Pow(1.05, 7.0/365) - 2) * math.Pow(1.20, 7.0/365)
+	if rotate.Strategy != "rotate" || math.Abs(rotate.FinalValueUSD-wantRotate) > 1e-6 {
+		t.Fatalf("unexpected rotate final value %f, want %f", rotate.FinalValueUSD, wantRotate)
+	}
+	if rotate.Rotations != 1 || rotate.GasDragUSD != 2 || rotate.GasDragPct != 0.02 {
+		t.Fatalf("expected one paid rotation, got %+v", rotate)
+	}
+	if len(rotate.Holdings) != 2 || rotate.Holdings[1].OpportunityID != "rising" || rotate.Holdings[1].From != "2026-01-08T00:00:00Z" || rotate.Holdings[1].APYAtEntry != 20 {
+		t.Fatalf("unexpected rotate holdings %+v", rotate.Holdings)
+	}
+	if math.Abs(result.RotationEdgePct-(rotate.CumulativeReturnPct-hold.CumulativeReturnPct)) > 1e-9 || result.RotationEdgePct <= 0 {
+		t.Fatalf("unexpected rotation edge %f", result.RotationEdgePct)
+	}
+
+	// Gas larger than the APY gain makes rotating lose to holding.
+	costly := simulateYieldBacktest(candidates, held, start, end, 7*24*time.Hour, 10000, 50)
+	if costly.RotationEdgePct >= 0 {
+		t.Fatalf("expected expensive rotations to lose to holding, got edge %f", costly.RotationEdgePct)
+	}
+
+	if _, err := backtestHoldTarget(candidates, "missing", start); err == nil {
+		t.Fatal("expected an unknown --hold opportunity to be rejected")
+	}
+}
@@ -0,0 +1,306 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/model"
+	"github.com/ggonzalez94/defi-cli/internal/providers"
+	"github.com/ggonzalez94/defi-cli/internal/providers/yieldutil"
+	"github.com/ggonzalez94/defi-cli/internal/schema"
+	"github.com/spf13/cobra"
+)
+
+func (s *runtimeState) newYieldLoopsCommand() *cobra.Command {
+	var chainArg, collateralArg, borrowAssetArg, providersArg, loopsArg, rpcURL string
+	var bufferPct float64
+	var limit int
+	cmd := &cobra.Command{
+		Use:   "loops",
+		Short: "Find profitable lend-borrow loops and their max safe leverage",
+		Long: `Quotes loops that supply --collateral, borrow --borrow-asset against it, and supply the proceeds again, such as supplying stETH and borrowing WETH. Each venue of --providers that lends --borrow-asset against --collateral is one loop.
+
+The collateral yield is the venue's supply APY for the collateral plus its liquid staking APY, when it has one. Loops target the max LTV, capped --ltv-buffer-pct below the liquidation LTV. After n loops the leverage is 1 + t + ... + t^n at target LTV t, up to 1/(1-t), and the net APY is leverage × collateral yield - (leverage - 1) × borrow APY.
+
+Leverage assumes the two assets keep their exchange rate; loops of uncorrelated assets are liquidated by price moves long before interest matters. Venues without LTV caps are skipped.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			chain, collateral, err := parseChainAsset(chainArg, collateralArg)
+			if err != nil {
+				return err
+			}
+			borrowAsset, err := id.ParseAsset(borrowAssetArg, chain)
+			if err != nil {
+				return err
+			}
+			loops, err := parseLoopCounts(loopsArg)
+			if err != nil {
+				return err
+			}
+			if bufferPct < 0 || bufferPct >= 100 {
+				return clierr.New(clierr.CodeUsage, "--ltv-buffer-pct must be between 0 and 100")
+			}
+			providerNames := []string{}
+			for _, name := range splitCSV(providersArg) {
+				name = normalizeLendingProvider(name)
+				if slices.Contains(providerNames, name) {
+					continue
+				}
+				if _, err := s.selectLendingProvider(name); err != nil {
+					return err
+				}
+				if err := s.protocolRules().Check(name); err != nil {
+					return err
+				}
+				providerNames = append(providerNames, name)
+			}
+			if len(providerNames) == 0 {
+				return clierr.New(clierr.CodeUsage, "--providers is required")
+			}
+
+			key := cacheKey(trimRootPath(cmd.CommandPath()), map[string]any{
+				"chain":          chain.CAIP2,
+				"collateral":     collateral.AssetID,
+				"borrow_asset":   borrowAsset.AssetID,
+				"providers":      providerNames,
+				"loops":          loops,
+				"ltv_buffer_pct": bufferPct,
+				"limit":          limit,
+				"rpc_url":        strings.TrimSpace(rpcURL),
+			})
+			return s.runCachedCommand(trimRootPath(cmd.CommandPath()), key, 60*time.Second, func(ctx context.Context) (any, []model.ProviderStatus, []string, bool, error) {
+				req := providers.BorrowQuoteRequest{Chain: chain, Asset: borrowAsset, Collateral: collateral}
+				statuses := []model.ProviderStatus{}
+				warnings := []string{}
+				partial := false
+				var firstErr error
+				out := []model.YieldLoop{}
+				for _, name := range providerNames {
+					quotes, status, providerWarnings, err := s.borrowQuotes(ctx, name, req, rpcURL)
+					statuses = append(statuses, status)
+					if err != nil {
+						warnings = append(warnings, fmt.Sprintf("provider %s failed: %v", name, err))
+						partial = true
+						if firstErr == nil {
+							firstErr = err
+						}
+						continue
+					}
+					warnings = append(warnings, providerWarnings...)
+					var supplyRates []model.LendRate
+					rates, rateStatuses, rateWarnings, err := s.lendRates(ctx, name, providers.DataBackendAPI, chain, collateral, rpcURL)
+					statuses = append(statuses, rateStatuses...)
+					warnings = append(warnings, rateWarnings...)
+					if err != nil {
+						warnings = append(warnings, fmt.Sprintf("provider %s has no %s supply rate; collateral_supply_apy is zero: %v", name, collateral.Symbol, err))
+					} else {
+						yieldutil.ApplyLendRateEffectiveAPY(rates)
+						supplyRates = rates
+					}
+					skipped := 0
+					for _, quote := range quotes {
+						if quote.MaxLTVPct <= 0 {
+							skipped++
+							continue
+						}
+						out = append(out, newYieldLoop(quote, collateral, borrowAsset, collateralSupplyAPY(quote, supplyRates), bufferPct))
+					}
+					if skipped > 0 {
+						warnings = append(warnings, fmt.Sprintf("%d %s venues without LTV caps skipped", skipped, name))
+					}
+				}
+				if len(out) == 0 {
+					if firstErr != nil {
+						return nil, statuses, warnings, partial, firstErr
+					}
+					return nil, statuses, warnings, partial, clierr.New(clierr.CodeUnavailable, "no loopable venue for requested collateral and borrow asset")
+				}
+
+				stakingAPY, stakingStatus, err := s.collateralStakingAPY(ctx, chain, collateral)
+				if stakingStatus != nil {
+					statuses = append(statuses, *stakingStatus)
+				}
+				if err != nil {
+					warnings = append(warnings, fmt.Sprintf("staking rates unavailable; collateral_staking_apy is zero: %v", err))
+				}
+				for i := range out {
+					applyYieldLoopRates(&out[i], stakingAPY, loops)
+				}
+				out = rankYieldLoops(out)
+				if limit > 0 && len(out) > limit {
+					out = out[:limit]
+				}
+				return out, statuses, warnings, partial, nil
+			})
+		},
+	}
+	cmd.Flags().StringVar(&chainArg, "chain", "", "Chain identifier")
+	cmd.Flags().StringVar(&collateralArg, "collateral", "", "Asset to supply as collateral (symbol/address/CAIP-19)")
+	cmd.Flags().StringVar(&borrowAssetArg, "borrow-asset", "", "Asset to borrow and loop back into collateral (symbol/address/CAIP-19)")
+	cmd.Flags().StringVar(&providersArg, "providers", "aave,morpho", "Lending providers to search (aave, morpho)")
+	cmd.Flags().StringVar(&loopsArg, "loops", "1,2,3,5", "Loop counts to report net APY for (comma-separated)")
+	cmd.Flags().Float64Var(&bufferPct, "ltv-buffer-pct", 5, "Percentage points to stay below the liquidation LTV")
+	cmd.Flags().IntVar(&limit, "limit", 10, "Maximum loops to return")
+	cmd.Flags().StringVar(&rpcURL, "rpc-url", "", "Optional RPC URL override for on-chain providers")
+	_ = cmd.MarkFlagRequired("chain")
+	_ = cmd.MarkFlagRequired("collateral")
+	_ = cmd.MarkFlagRequired("borrow-asset")
+	_ = schema.SetFlagMetadata(cmd.Flags(), "collateral", schema.FlagMetadata{Format: "asset"})
+	_ = schema.SetFlagMetadata(cmd.Flags(), "borrow-asset", schema.FlagMetadata{Format: "asset"})
+	response := schema.SchemaFromType([]model.YieldLoop{})
+	_ = schema.SetCommandMetadata(cmd, schema.CommandMetadata{Response: &response})
+	return cmd
+}
+
+// parseLoopCounts parses --loops into sorted, distinct positive counts.
+func parseLoopCounts(v string) ([]int, error) {
+	out := []int{}
+	for _, part := range splitCSV(v) {
+		n, err := strconv.Atoi(part)
+		if err != nil || n <= 0 {
+			return nil, clierr.New(clierr.CodeUsage, fmt.Sprintf("--loops must be positive integers, got %q", part))
+		}
+		if !slices.Contains(out, n) {
+			out = append(out, n)
+		}
+	}
+	if len(out) == 0 {
+		return nil, clierr.New(clierr.CodeUsage, "--loops is required")
+	}
+	sort.Ints(out)
+	return out, nil
+}
+
+// collateralSupplyAPY returns the effective supply APY of the collateral in
+// the market of quote. Rates match when their native IDs share the market
+// prefix, as Aave's market:asset IDs do; venues that pay no interest on
+// collateral, such as Morpho Blue markets, match nothing and earn zero.
+func collateralSupplyAPY(quote model.BorrowQuote, rates []model.LendRate) float64 {
+	market := nativeIDMarket(quote.ProviderNativeID)
+	if market == "" {
+		return 0
+	}
+	for _, rate := range rates {
+		if nativeIDMarket(rate.ProviderNativeID) == market {
+			return yieldutil.PositiveFirst(rate.SupplyAPYEffective, rate.SupplyAPY)
+		}
+	}
+	return 0
+}
+
+// nativeIDMarket strips the asset from a composite market:asset native ID.
+func nativeIDMarket(nativeID string) string {
+	i := strings.LastIndex(nativeID, ":")
+	if i <= 0 {
+		return ""
+	}
+	return strings.ToLower(nativeID[:i])
+}
+
+// collateralStakingAPY returns the compounded staking APY of collateral when
+// it is a liquid staking token, or zero when it is not.
+func (s *runtimeState) collateralStakingAPY(ctx context.Context, chain id.Chain, collateral id.Asset) (float64, *model.ProviderStatus, error) {
+	if s.stakingProvider == nil {
+		return 0, nil, nil
+	}
+	start := time.Now()
+	rates, err := s.stakingProvider.StakeRates(ctx, chain)
+	status := &model.ProviderStatus{Name: s.stakingProvider.Info().Name, Status: statusFromErr(err), LatencyMS: time.Since(start).Milliseconds()}
+	if err != nil {
+		return 0, status, err
+	}
+	for _, rate := range rates {
+		if strings.EqualFold(rate.AssetID, collateral.AssetID) {
+			return yieldutil.EffectiveAPY(rate.APR, yieldutil.RateBasisAPR, yieldutil.CompoundingDaily), status, nil
+		}
+	}
+	return 0, status, nil
+}
+
+func newYieldLoop(quote model.BorrowQuote, collateral, borrowAsset id.Asset, supplyAPY, bufferPct float64) model.YieldLoop {
+	target := quote.MaxLTVPct
+	if quote.LiquidationLTVPct > 0 {
+		target = math.Min(target, quote.LiquidationLTVPct-bufferPct)
+	}
+	collateralID := quote.CollateralAssetID
+	if collateralID == "" {
+		collateralID = collateral.AssetID
+	}
+	borrowID := quote.AssetID
+	if borrowID == "" {
+		borrowID = borrowAsset.AssetID
+	}
+	return model.YieldLoop{
+		Protocol:              quote.Protocol,
+		Provider:              quote.Provider,
+		ChainID:               quote.ChainID,
+		CollateralAssetID:     collateralID,
+		BorrowAssetID:         borrowID,
+		ProviderNativeID:      quote.ProviderNativeID,
+		ProviderNativeIDKind:  quote.ProviderNativeIDKind,
+		CollateralSupplyAPY:   supplyAPY,
+		BorrowAPY:             quote.BorrowAPY,
+		MaxLTVPct:             quote.MaxLTVPct,
+		LiquidationLTVPct:     quote.LiquidationLTVPct,
+		BufferPct:             bufferPct,
+		TargetLTVPct:          math.Max(target, 0),
+		AvailableLiquidityUSD: quote.AvailableLiquidityUSD,
+		SourceURL:             quote.SourceURL,
+		FetchedAt:             quote.FetchedAt,
+	}
+}
+
+// applyYieldLoopRates sets the collateral yield, spread, and leverage of loop
+// at each loop count and in the limit.
+func applyYieldLoopRates(loop *model.YieldLoop, stakingAPY float64, loops []int) {
+	loop.CollateralStakingAPY = stakingAPY
+	loop.CollateralYieldPct = loop.CollateralSupplyAPY + stakingAPY
+	loop.SpreadPct = loop.CollateralYieldPct - loop.BorrowAPY
+	loop.Profitable = loop.SpreadPct > 0
+	t := loop.TargetLTVPct / 100
+	loop.MaxLeverage = 1 / (1 - t)
+	loop.MaxNetAPY = loopNetAPY(loop.MaxLeverage, loop.CollateralYieldPct, loop.BorrowAPY)
+	loop.Steps = make([]model.YieldLoopStep, 0, len(loops))
+	for _, n := range loops {
+		leverage := (1 - math.Pow(t, float64(n+1))) / (1 - t)
+		loop.Steps = append(loop.Steps, model.YieldLoopStep{
+			Loops:    n,
+			Leverage: leverage,
+			LTVPct:   (leverage - 1) / leverage * 100,
+			NetAPY:   loopNetAPY(leverage, loop.CollateralYieldPct, loop.BorrowAPY),
+		})
+	}
+}
+
+func loopNetAPY(leverage, collateralYield, borrowAPY float64) float64 {
+	return leverage*collateralYield - (leverage-1)*borrowAPY
+}
+
+// rankYieldLoops orders loops by highest net APY at max leverage, then
+// widest spread and deepest liquidity.
+func rankYieldLoops(items []model.YieldLoop) []model.YieldLoop {
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := items[i], items[j]
+		if a.MaxNetAPY != b.MaxNetAPY {
+			return a.MaxNetAPY > b.MaxNetAPY
+		}
+		if a.SpreadPct != b.SpreadPct {
+			return a.SpreadPct > b.SpreadPct
+		}
+		if a.AvailableLiquidityUSD != b.AvailableLiquidityUSD {
+			return a.AvailableLiquidityUSD > b.AvailableLiquidityUSD
+		}
+		return a.ProviderNativeID < b.ProviderNativeID
+	})
+	for i := range items {
+		items[i].Rank = i + 1
+	}
+	return items
+}
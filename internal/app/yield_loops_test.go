@@ -0,0 +1,110 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/ggonzalez94/defi-cli/internal/config"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/model"
+	"github.com/ggonzalez94/defi-cli/internal/providers"
+	"github.com/spf13/cobra"
+)
+
+func TestYieldLoopsCombinesSupplyStakingAndBorrowRates(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	chain, err := id.ParseChain("ethereum")
+	if err != nil {
+		t.Fatal(err)
+	}
+	steth, err := id.ParseAsset("STETH", chain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	aave := &fakeBorrowQuoteProvider{
+		fakeLendingProvider: &fakeLendingProvider{
+			name: "aave",
+			rates: []model.LendRate{
+				{Provider: "aave", ProviderNativeID: "aave:eip155:1:0xother:0xsteth", SupplyAPY: 9, RateBasis: "apy"},
+				{Provider: "aave", ProviderNativeID: "aave:eip155:1:0xcore:0xsteth", SupplyAPY: 0.1, RateBasis: "apy"},
+			},
+		},
+		quotes: []model.BorrowQuote{
+			{Provider: "aave", ProviderNativeID: "aave:eip155:1:0xcore:0xweth", BorrowAPY: 2.5, MaxLTVPct: 80, LiquidationLTVPct: 83, AvailableLiquidityUSD: 1_000_000},
+		},
+	}
+	morpho := &fakeBorrowQuoteProvider{
+		fakeLendingProvider: &fakeLendingProvider{name: "morpho"},
+		quotes: []model.BorrowQuote{
+			{Provider: "morpho", ProviderNativeID: "0xmarket", BorrowAPY: 4, MaxLTVPct: 86, LiquidationLTVPct: 86},
+			{Provider: "morpho", ProviderNativeID: "0xnoltv", BorrowAPY: 1},
+		},
+	}
+	state := &runtimeState{
+		runner: &Runner{
+			stdout: &stdout,
+			stderr: &stderr,
+			now:    time.Now,
+		},
+		settings: config.Settings{
+			OutputMode:   "json",
+			Timeout:      2 * time.Second,
+			CacheEnabled: false,
+		},
+		lendingProviders: map[string]providers.LendingProvider{"aave": aave, "morpho": morpho},
+		stakingProvider:  &fakeStakingProvider{rates: []model.StakeRate{{AssetID: steth.AssetID, APR: 3}}},
+	}
+
+	root := &cobra.Command{Use: "defi"}
+	root.SilenceUsage = true
+	root.SilenceErrors = true
+	root.SetOut(&stdout)
+	root.SetErr(&stderr)
+	root.AddCommand(state.newYieldCommand())
+	root.SetArgs([]string{"yield", "loops", "--chain", "ethereum", "--collateral", "STETH", "--borrow-asset", "WETH", "--loops", "3,1"})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("yield loops failed: %v stderr=%s", err, stderr.String())
+	}
+	var env struct {
+		Data     []model.YieldLoop `json:"data"`
+		Warnings []string          `json:"warnings"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &env); err != nil {
+		t.Fatalf("decode output: %v output=%s", err, stdout.String())
+	}
+	if len(env.Data) != 2 {
+		t.Fatalf("expected the venue without LTV caps to be skipped, got %+v", env.Data)
+	}
+
+	stakingAPY := (math.Pow(1+0.03/365, 365) - 1) * 100
+	best := env.Data[0]
+	if best.Rank != 1 || best.Provider != "aave" || !best.Profitable {
+		t.Fatalf("expected the aave loop to rank first and be profitable, got %+v", best)
+	}
+	if best.CollateralSupplyAPY != 0.1 || math.Abs(best.CollateralStakingAPY-stakingAPY) > 1e-9 {
+		t.Fatalf("expected the core market supply rate and staking yield, got %+v", best)
+	}
+	if best.TargetLTVPct != 78 || math.Abs(best.MaxLeverage-1/0.22) > 1e-9 {
+		t.Fatalf("expected the target LTV to stay 5 points below liquidation, got %+v", best)
+	}
+	if len(best.Steps) != 2 || best.Steps[0].Loops != 1 || best.Steps[1].Loops != 3 {
+		t.Fatalf("expected steps for 1 and 3 loops, got %+v", best.Steps)
+	}
+	one := best.Steps[0]
+	wantNet := 1.78*best.CollateralYieldPct - 0.78*2.5
+	if math.Abs(one.Leverage-1.78) > 1e-9 || math.Abs(one.NetAPY-wantNet) > 1e-9 || math.Abs(one.LTVPct-0.78/1.78*100) > 1e-9 {
+		t.Fatalf("unexpected one-loop step %+v (want net %f)", one, wantNet)
+	}
+
+	worst := env.Data[1]
+	if worst.Provider != "morpho" || worst.Profitable || worst.CollateralSupplyAPY != 0 || worst.TargetLTVPct != 81 {
+		t.Fatalf("expected the morpho loop to earn no supply APY and lose money, got %+v", worst)
+	}
+	if worst.MaxNetAPY >= worst.Steps[0].NetAPY {
+		t.Fatalf("expected an unprofitable loop to lose more at higher leverage, got %+v", worst)
+	}
+}
@@ -0,0 +1,189 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/model"
+	"github.com/ggonzalez94/defi-cli/internal/providers"
+	"github.com/ggonzalez94/defi-cli/internal/providers/yieldutil"
+	"github.com/ggonzalez94/defi-cli/internal/schema"
+	"github.com/spf13/cobra"
+)
+
+const lpVolatilityWindowDays = 30
+
+// attachLPAnalytics adds lp_analytics to liquidity pool opportunities: the
+// 30-day volatility of their first two backing assets against each other,
+// impermanent loss at the default price moves, and the fee and reward APR
+// split. Rows whose price history is unavailable keep the scenarios without
+// volatility and a warning.
+func (s *runtimeState) attachLPAnalytics(ctx context.Context, items []model.YieldOpportunity) ([]string, bool) {
+	warnings := []string{}
+	partial := false
+	for i := range items {
+		item := &items[i]
+		if !yieldutil.IsLP(item.Type) {
+			continue
+		}
+		var volatility float64
+		if len(item.BackingAssets) < 2 {
+			warnings = append(warnings, fmt.Sprintf("opportunity %s has fewer than two backing assets; lp_analytics has no pair volatility", item.OpportunityID))
+		} else {
+			vol, _, err := s.pairVolatility(ctx, item.ChainID, item.BackingAssets[0].AssetID, item.BackingAssets[1].AssetID, lpVolatilityWindowDays*24*time.Hour)
+			if err != nil {
+				partial = true
+				warnings = append(warnings, fmt.Sprintf("pair volatility unavailable for opportunity %s: %v", item.OpportunityID, err))
+			}
+			volatility = vol
+		}
+		item.LPAnalytics = yieldutil.LPAnalytics(volatility, lpVolatilityWindowDays, yieldutil.DefaultILMoves, item.APYBase, item.APYReward)
+	}
+	return warnings, partial
+}
+
+// pairVolatility returns the annualized volatility of assetA priced in assetB
+// over window from daily USD price history, and the history provider name.
+func (s *runtimeState) pairVolatility(ctx context.Context, chainID, assetA, assetB string, window time.Duration) (float64, string, error) {
+	provider, err := s.marketHistoryProvider()
+	if err != nil {
+		return 0, "", err
+	}
+	chain, err := id.ParseChain(chainID)
+	if err != nil {
+		return 0, "", err
+	}
+	endTime := s.runner.now().UTC()
+	series := make([][]model.MarketHistoryPoint, 0, 2)
+	for _, assetArg := range []string{assetA, assetB} {
+		asset, err := id.ParseAsset(assetArg, chain)
+		if err != nil {
+			return 0, provider.Info().Name, err
+		}
+		history, err := provider.PriceHistory(ctx, providers.PriceHistoryRequest{
+			Chain:     chain,
+			Asset:     asset,
+			StartTime: endTime.Add(-window),
+			EndTime:   endTime,
+			Interval:  providers.YieldHistoryIntervalDay,
+		})
+		if err != nil {
+			return 0, provider.Info().Name, err
+		}
+		series = append(series, history.Points)
+	}
+	volatility, ok := yieldutil.PairVolatilityPct(series[0], series[1], 24*time.Hour)
+	if !ok {
+		return 0, provider.Info().Name, clierr.New(clierr.CodeUnavailable, "not enough overlapping price points for pair volatility")
+	}
+	return volatility, provider.Info().Name, nil
+}
+
+func (s *runtimeState) newYieldILSimulateCommand() *cobra.Command {
+	var chainArg, assetAArg, assetBArg, movesArg, windowArg string
+	var feeAPR, rewardAPR float64
+	cmd := &cobra.Command{
+		Use:   "il-simulate",
+		Short: "Simulate impermanent loss for a two-asset pool",
+		Long:  "Computes the impermanent loss of a 50/50 constant-product position in --asset-a/--asset-b at each --moves price move of asset A against asset B, the pair's annualized volatility from daily USD price history over --window, the loss after a one standard deviation annual move, and the net APR after that loss given --fee-apr and --reward-apr.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			chain, assetA, err := parseChainAsset(chainArg, assetAArg)
+			if err != nil {
+				return err
+			}
+			if strings.TrimSpace(assetBArg) == "" {
+				return clierr.New(clierr.CodeUsage, "--asset-b is required")
+			}
+			assetB, err := id.ParseAsset(assetBArg, chain)
+			if err != nil {
+				return err
+			}
+			if assetA.AssetID == assetB.AssetID {
+				return clierr.New(clierr.CodeUsage, "--asset-a and --asset-b must differ")
+			}
+			moves, err := parseILMoves(movesArg)
+			if err != nil {
+				return err
+			}
+			startTime, endTime, err := resolveYieldHistoryRange("", "", windowArg, s.runner.now().UTC())
+			if err != nil {
+				return err
+			}
+			windowDays := int(endTime.Sub(startTime).Hours() / 24)
+
+			key := cacheKey(trimRootPath(cmd.CommandPath()), map[string]any{
+				"chain":      chain.CAIP2,
+				"asset_a":    assetA.AssetID,
+				"asset_b":    assetB.AssetID,
+				"moves":      moves,
+				"window":     windowArg,
+				"fee_apr":    feeAPR,
+				"reward_apr": rewardAPR,
+			})
+			return s.runCachedCommand(trimRootPath(cmd.CommandPath()), key, 5*time.Minute, func(ctx context.Context) (any, []model.ProviderStatus, []string, bool, error) {
+				start := time.Now()
+				volatility, providerName, err := s.pairVolatility(ctx, chain.CAIP2, assetA.AssetID, assetB.AssetID, endTime.Sub(startTime))
+				warnings := []string{}
+				partial := false
+				statuses := []model.ProviderStatus{}
+				if providerName != "" {
+					statuses = append(statuses, model.ProviderStatus{Name: providerName, Status: statusFromErr(err), LatencyMS: time.Since(start).Milliseconds()})
+				}
+				if err != nil {
+					partial = true
+					warnings = append(warnings, fmt.Sprintf("pair volatility unavailable, only price move scenarios returned: %v", err))
+				}
+				return model.ILSimulation{
+					ChainID:     chain.CAIP2,
+					AssetAID:    assetA.AssetID,
+					SymbolA:     assetA.Symbol,
+					AssetBID:    assetB.AssetID,
+					SymbolB:     assetB.Symbol,
+					LPAnalytics: *yieldutil.LPAnalytics(volatility, windowDays, moves, feeAPR, rewardAPR),
+					Provider:    providerName,
+					FetchedAt:   s.runner.now().UTC().Format(time.RFC3339),
+				}, statuses, warnings, partial, nil
+			})
+		},
+	}
+	cmd.Flags().StringVar(&chainArg, "chain", "", "Chain identifier")
+	cmd.Flags().StringVar(&assetAArg, "asset-a", "", "First pool asset symbol/address/CAIP-19")
+	cmd.Flags().StringVar(&assetBArg, "asset-b", "", "Second pool asset symbol/address/CAIP-19")
+	cmd.Flags().StringVar(&movesArg, "moves", "-50,-25,-10,10,25,50,100", "Comma-separated price moves of asset A against asset B in percent")
+	cmd.Flags().StringVar(&windowArg, "window", "30d", "Price history lookback for pair volatility (for example 30d,90d)")
+	cmd.Flags().Float64Var(&feeAPR, "fee-apr", 0, "Pool fee APR percent")
+	cmd.Flags().Float64Var(&rewardAPR, "reward-apr", 0, "Pool reward APR percent")
+	_ = cmd.MarkFlagRequired("chain")
+	_ = cmd.MarkFlagRequired("asset-a")
+	_ = cmd.MarkFlagRequired("asset-b")
+	response := schema.SchemaFromType(model.ILSimulation{})
+	_ = schema.SetCommandMetadata(cmd, schema.CommandMetadata{Response: &response})
+	return cmd
+}
+
+func parseILMoves(raw string) ([]float64, error) {
+	moves := []float64{}
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		move, err := strconv.ParseFloat(strings.TrimPrefix(part, "+"), 64)
+		if err != nil {
+			return nil, clierr.Wrap(clierr.CodeUsage, fmt.Sprintf("invalid --moves value %q", part), err)
+		}
+		if move < -100 {
+			return nil, clierr.New(clierr.CodeUsage, "--moves values must be at least -100")
+		}
+		moves = append(moves, move)
+	}
+	if len(moves) == 0 {
+		return nil, clierr.New(clierr.CodeUsage, "--moves requires at least one price move")
+	}
+	return moves, nil
+}
@@ -0,0 +1,121 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/ggonzalez94/defi-cli/internal/model"
+	"github.com/ggonzalez94/defi-cli/internal/providers"
+)
+
+const yieldStabilityWindowDays = 30
+
+// attachYieldStability walks items in ranked order and attaches a 30-day
+// apy_total volatility summary from the owning provider's history. With
+// minStability > 0, opportunities scoring below it are dropped; those without
+// history are kept only when includeIncomplete is set. It stops once limit
+// items are kept so history is not fetched for rows that would be cut.
+func (s *runtimeState) attachYieldStability(ctx context.Context, items []model.YieldOpportunity, minStability float64, limit int, includeIncomplete bool) ([]model.YieldOpportunity, []string, bool) {
+	endTime := s.runner.now().UTC()
+	startTime := endTime.Add(-yieldStabilityWindowDays * 24 * time.Hour)
+	out := make([]model.YieldOpportunity, 0, len(items))
+	warnings := []string{}
+	partial := false
+	unsupported := map[string]struct{}{}
+
+	for _, item := range items {
+		if limit > 0 && len(out) >= limit {
+			break
+		}
+		historyProvider, ok := s.yieldProviders[item.Provider].(providers.YieldHistoryProvider)
+		if !ok {
+			if _, seen := unsupported[item.Provider]; !seen {
+				unsupported[item.Provider] = struct{}{}
+				warnings = append(warnings, fmt.Sprintf("provider %s does not support yield history; apy_volatility omitted", item.Provider))
+			}
+		} else {
+			series, err := historyProvider.YieldHistory(ctx, providers.YieldHistoryRequest{
+				Opportunity: item,
+				StartTime:   startTime,
+				EndTime:     endTime,
+				Interval:    providers.YieldHistoryIntervalDay,
+				Metrics:     []providers.YieldHistoryMetric{providers.YieldHistoryMetricAPYTotal},
+			})
+			if err != nil {
+				partial = true
+				warnings = append(warnings, fmt.Sprintf("provider %s failed history for opportunity %s: %v", item.Provider, item.OpportunityID, err))
+			}
+			for _, entry := range series {
+				if strings.EqualFold(entry.Metric, string(providers.YieldHistoryMetricAPYTotal)) {
+					item.APYVolatility = computeAPYVolatility(entry.Points)
+					break
+				}
+			}
+		}
+
+		if minStability > 0 {
+			if item.APYVolatility == nil {
+				if !includeIncomplete {
+					continue
+				}
+			} else if item.APYVolatility.StabilityScore < minStability {
+				continue
+			}
+		}
+		out = append(out, item)
+	}
+	return out, warnings, partial
+}
+
+// computeAPYVolatility summarizes an apy_total series. The stability score is
+// 1 minus the larger of the coefficient of variation and the worst
+// peak-to-trough drawdown, clamped to [0, 1]: a pool whose APY halved inside
+// the window scores at most 0.5. Fewer than two points yields nil.
+func computeAPYVolatility(points []model.YieldHistoryPoint) *model.YieldAPYVolatility {
+	if len(points) < 2 {
+		return nil
+	}
+	sorted := append([]model.YieldHistoryPoint(nil), points...)
+	sortYieldHistorySeries([]model.YieldHistorySeries{{Points: sorted}})
+
+	var sum float64
+	for _, point := range sorted {
+		sum += point.Value
+	}
+	mean := sum / float64(len(sorted))
+	var sqDiff float64
+	for _, point := range sorted {
+		sqDiff += (point.Value - mean) * (point.Value - mean)
+	}
+	stddev := math.Sqrt(sqDiff / float64(len(sorted)))
+
+	var peak, maxDrawdown float64
+	for _, point := range sorted {
+		if point.Value > peak {
+			peak = point.Value
+		}
+		if peak > 0 {
+			maxDrawdown = math.Max(maxDrawdown, (peak-point.Value)/peak)
+		}
+	}
+
+	instability := maxDrawdown
+	if mean > 0 {
+		instability = math.Max(instability, stddev/mean)
+	} else if stddev > 0 {
+		instability = 1
+	}
+	score := math.Max(0, 1-math.Min(1, instability))
+
+	return &model.YieldAPYVolatility{
+		WindowDays:     yieldStabilityWindowDays,
+		Points:         len(sorted),
+		MeanAPY:        mean,
+		StdDevAPY:      stddev,
+		MaxDrawdownPct: maxDrawdown * 100,
+		StabilityScore: score,
+	}
+}
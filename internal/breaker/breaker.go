@@ -0,0 +1,246 @@
+// Package breaker short-circuits calls to a provider capability after
+// consecutive failures. It keeps one circuit per provider and capability so
+// a downed endpoint is skipped for a cool-down window instead of being hit by
+// every retry, and persists the table so separate invocations share it.
+package breaker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+)
+
+// ErrOpen marks errors returned for calls skipped by an open circuit.
+var ErrOpen = errors.New("circuit open")
+
+// Circuit states reported by Circuit.State.
+const (
+	StateClosed   = "closed"
+	StateOpen     = "open"
+	StateHalfOpen = "half_open"
+)
+
+// Circuit is the recorded health of one provider capability.
+type Circuit struct {
+	Provider   string    `json:"provider"`
+	Capability string    `json:"capability"`
+	Failures   int       `json:"failures,omitempty"`
+	LastError  string    `json:"last_error,omitempty"`
+	CheckedAt  time.Time `json:"checked_at"`
+	OpenUntil  time.Time `json:"open_until"`
+}
+
+// State reports whether c is closed, open, or half open (its cool-down
+// elapsed and the next call decides) with the failure threshold in force.
+func (c Circuit) State(t time.Time) string {
+	mu.Lock()
+	threshold := failures
+	mu.Unlock()
+	return c.state(t, threshold)
+}
+
+func (c Circuit) state(t time.Time, threshold int) string {
+	switch {
+	case threshold <= 0 || c.Failures < threshold:
+		return StateClosed
+	case c.OpenUntil.After(t):
+		return StateOpen
+	default:
+		return StateHalfOpen
+	}
+}
+
+var (
+	mu       sync.Mutex
+	circuits = map[string]Circuit{}
+	dirty    bool
+	failures = 5
+	cooldown = 30 * time.Second
+	now      = time.Now
+)
+
+// Configure sets how many consecutive failures open a circuit and how long it
+// stays open. n <= 0 disables short-circuiting; failures are still recorded.
+func Configure(n int, d time.Duration) {
+	mu.Lock()
+	defer mu.Unlock()
+	failures = n
+	if d > 0 {
+		cooldown = d
+	}
+}
+
+type capabilityKey struct{}
+
+// WithCapability tags ctx with the capability (e.g. "lend.markets") that
+// provider calls made under it serve. Calls without one are not tracked.
+func WithCapability(ctx context.Context, capability string) context.Context {
+	return context.WithValue(ctx, capabilityKey{}, capability)
+}
+
+// CapabilityFrom returns the capability set by WithCapability.
+func CapabilityFrom(ctx context.Context) string {
+	capability, _ := ctx.Value(capabilityKey{}).(string)
+	return capability
+}
+
+// Allow returns an unavailable error wrapping ErrOpen when the circuit of
+// provider and capability is open.
+func Allow(provider, capability string) error {
+	mu.Lock()
+	defer mu.Unlock()
+	c, ok := circuits[key(provider, capability)]
+	if !ok || c.state(now(), failures) != StateOpen {
+		return nil
+	}
+	msg := fmt.Sprintf("provider %s skipped for %s: circuit open after %d consecutive failures until %s (last error: %s)",
+		provider, capability, c.Failures, c.OpenUntil.UTC().Format(time.RFC3339), c.LastError)
+	return clierr.Wrap(clierr.CodeUnavailable, msg, ErrOpen)
+}
+
+// Record stores the outcome of one call. Only unavailable and rate-limited
+// errors count as failures; any other outcome means the provider answered and
+// closes the circuit.
+func Record(provider, capability string, err error) {
+	if errors.Is(err, ErrOpen) {
+		return
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	k := key(provider, capability)
+	c := circuits[k]
+	c.Provider, c.Capability = provider, capability
+	c.CheckedAt = now().UTC()
+	if counts(err) {
+		c.Failures++
+		c.LastError = err.Error()
+		if failures > 0 && c.Failures >= failures {
+			c.OpenUntil = c.CheckedAt.Add(cooldown)
+		}
+	} else {
+		c.Failures = 0
+		c.LastError = ""
+		c.OpenUntil = time.Time{}
+	}
+	circuits[k] = c
+	dirty = true
+}
+
+func counts(err error) bool {
+	cErr, ok := clierr.As(err)
+	if !ok {
+		return false
+	}
+	return cErr.Code == clierr.CodeUnavailable || cErr.Code == clierr.CodeRateLimited
+}
+
+// Get returns the circuit of provider and capability.
+func Get(provider, capability string) (Circuit, bool) {
+	mu.Lock()
+	defer mu.Unlock()
+	c, ok := circuits[key(provider, capability)]
+	return c, ok
+}
+
+// Circuits returns every recorded circuit sorted by provider and capability.
+func Circuits() []Circuit {
+	mu.Lock()
+	out := make([]Circuit, 0, len(circuits))
+	for _, c := range circuits {
+		out = append(out, c)
+	}
+	mu.Unlock()
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Provider != out[j].Provider {
+			return out[i].Provider < out[j].Provider
+		}
+		return out[i].Capability < out[j].Capability
+	})
+	return out
+}
+
+// Reset forgets the circuits of provider, or all circuits when provider is
+// empty, and returns how many were removed.
+func Reset(provider string) int {
+	mu.Lock()
+	defer mu.Unlock()
+	removed := 0
+	for k, c := range circuits {
+		if provider == "" || c.Provider == provider {
+			delete(circuits, k)
+			removed++
+		}
+	}
+	if removed > 0 {
+		dirty = true
+	}
+	return removed
+}
+
+func key(provider, capability string) string {
+	return provider + "|" + capability
+}
+
+// Load replaces the in-memory table with the one saved at path by an earlier
+// invocation. A missing file leaves an empty table.
+func Load(path string) error {
+	buf, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	var saved []Circuit
+	if len(buf) > 0 {
+		if err := json.Unmarshal(buf, &saved); err != nil {
+			return fmt.Errorf("decode provider health: %w", err)
+		}
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	circuits = make(map[string]Circuit, len(saved))
+	for _, c := range saved {
+		circuits[key(c.Provider, c.Capability)] = c
+	}
+	dirty = false
+	return nil
+}
+
+// Save writes the table to path when it changed since the last load or save.
+func Save(path string) error {
+	mu.Lock()
+	if !dirty {
+		mu.Unlock()
+		return nil
+	}
+	dirty = false
+	mu.Unlock()
+
+	buf, err := json.MarshalIndent(Circuits(), "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".provider-health-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(buf); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,129 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+)
+
+func resetCircuits(t *testing.T) {
+	t.Helper()
+	mu.Lock()
+	circuits = map[string]Circuit{}
+	dirty = false
+	mu.Unlock()
+	t.Cleanup(func() {
+		mu.Lock()
+		circuits = map[string]Circuit{}
+		dirty = false
+		failures, cooldown = 5, 30*time.Second
+		now = time.Now
+		mu.Unlock()
+	})
+}
+
+func TestCircuitOpensAfterConsecutiveFailuresAndRecovers(t *testing.T) {
+	resetCircuits(t)
+	Configure(3, time.Minute)
+	clock := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	now = func() time.Time { return clock }
+
+	down := clierr.New(clierr.CodeUnavailable, "provider unavailable (status 503)")
+	Record("aave", "lend.markets", down)
+	Record("aave", "lend.markets", down)
+	// A non-availability error means the provider answered and resets the count.
+	Record("aave", "lend.markets", clierr.New(clierr.CodeUnsupported, "provider returned unexpected status 404"))
+	for i := 0; i < 3; i++ {
+		if err := Allow("aave", "lend.markets"); err != nil {
+			t.Fatalf("circuit opened early after %d failures: %v", i, err)
+		}
+		Record("aave", "lend.markets", down)
+	}
+
+	err := Allow("aave", "lend.markets")
+	if !errors.Is(err, ErrOpen) {
+		t.Fatalf("expected open circuit, got %v", err)
+	}
+	if cErr, ok := clierr.As(err); !ok || cErr.Code != clierr.CodeUnavailable {
+		t.Fatalf("expected unavailable error, got %v", err)
+	}
+	if err := Allow("aave", "lend.rates"); err != nil {
+		t.Fatalf("expected other capabilities to stay closed, got %v", err)
+	}
+	Record("aave", "lend.markets", err)
+	if c, _ := Get("aave", "lend.markets"); c.Failures != 3 || c.State(clock) != StateOpen {
+		t.Fatalf("short-circuited calls must not count as failures: %+v", c)
+	}
+
+	clock = clock.Add(2 * time.Minute)
+	if err := Allow("aave", "lend.markets"); err != nil {
+		t.Fatalf("expected half-open circuit to allow a call, got %v", err)
+	}
+	if c, _ := Get("aave", "lend.markets"); c.State(clock) != StateHalfOpen {
+		t.Fatalf("expected half_open, got %s", c.State(clock))
+	}
+	Record("aave", "lend.markets", down)
+	if err := Allow("aave", "lend.markets"); !errors.Is(err, ErrOpen) {
+		t.Fatalf("expected a failed half-open call to reopen the circuit, got %v", err)
+	}
+
+	clock = clock.Add(2 * time.Minute)
+	Record("aave", "lend.markets", nil)
+	if c, _ := Get("aave", "lend.markets"); c.Failures != 0 || c.State(clock) != StateClosed {
+		t.Fatalf("expected success to close the circuit, got %+v", c)
+	}
+}
+
+func TestDisabledBreakerOnlyRecords(t *testing.T) {
+	resetCircuits(t)
+	Configure(0, 0)
+	for i := 0; i < 10; i++ {
+		Record("lifi", "bridge.quote", clierr.New(clierr.CodeRateLimited, "provider rate limited request"))
+	}
+	if err := Allow("lifi", "bridge.quote"); err != nil {
+		t.Fatalf("expected disabled breaker to allow calls, got %v", err)
+	}
+	if c, _ := Get("lifi", "bridge.quote"); c.Failures != 10 {
+		t.Fatalf("expected failures to be recorded, got %+v", c)
+	}
+}
+
+func TestSaveLoadAndReset(t *testing.T) {
+	resetCircuits(t)
+	path := filepath.Join(t.TempDir(), "provider-health.json")
+	Record("aave", "lend.markets", clierr.New(clierr.CodeUnavailable, "provider timeout"))
+	Record("morpho", "lend.markets", nil)
+	if err := Save(path); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	Reset("")
+	if err := Load(path); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	got := Circuits()
+	if len(got) != 2 || got[0].Provider != "aave" || got[0].Failures != 1 || got[1].Provider != "morpho" {
+		t.Fatalf("unexpected circuits after load: %+v", got)
+	}
+
+	if removed := Reset("aave"); removed != 1 || len(Circuits()) != 1 {
+		t.Fatalf("expected Reset to remove only aave, removed=%d left=%+v", removed, Circuits())
+	}
+	if err := Load(filepath.Join(t.TempDir(), "missing.json")); err != nil || len(Circuits()) != 0 {
+		t.Fatalf("expected a missing file to leave an empty table, err=%v circuits=%+v", err, Circuits())
+	}
+}
+
+func TestCapabilityContext(t *testing.T) {
+	ctx := WithCapability(context.Background(), "yield.opportunities")
+	if got := CapabilityFrom(ctx); got != "yield.opportunities" {
+		t.Fatalf("unexpected capability %q", got)
+	}
+	if got := CapabilityFrom(context.Background()); got != "" {
+		t.Fatalf("expected no capability, got %q", got)
+	}
+}
@@ -0,0 +1,236 @@
+// Package bridgesafety holds curated bridge risk ratings used to annotate
+// bridge quotes and bridge listings.
+package bridgesafety
+
+import (
+	"strings"
+
+	"github.com/ggonzalez94/defi-cli/internal/model"
+)
+
+// Trust models, from strongest to weakest security assumptions.
+const (
+	TrustNative       = "native"
+	TrustLightClient  = "light-client"
+	TrustOptimistic   = "optimistic"
+	TrustAttestation  = "attestation"
+	TrustValidatorSet = "validator-set"
+	TrustMultisig     = "multisig"
+)
+
+const (
+	datasetSource = "curated from L2BEAT bridge risk assessments and public incident reports"
+	datasetAsOf   = "2026-10-01"
+)
+
+type rating struct {
+	Aliases []string
+	Safety  model.BridgeSafety
+}
+
+// ratings is the bundled dataset. Aliases match bridge names as reported by
+// providers (LiFi tool names, Bungee routes, DefiLlama bridge names).
+// MaxRecommendedUSD is a conservative per-transfer ceiling, not a hard
+// limit; zero marks bridges that should not be used at all.
+var ratings = []rating{
+	{
+		Aliases: []string{"across"},
+		Safety: model.BridgeSafety{
+			Bridge:            "across",
+			TrustModel:        TrustOptimistic,
+			AvgFinalityS:      60,
+			MaxRecommendedUSD: 1_000_000,
+			Notes:             "relayers fill on destination; settlement verified by UMA optimistic oracle",
+		},
+	},
+	{
+		Aliases: []string{"cctp", "circle"},
+		Safety: model.BridgeSafety{
+			Bridge:            "cctp",
+			TrustModel:        TrustAttestation,
+			AvgFinalityS:      900,
+			MaxRecommendedUSD: 10_000_000,
+			Notes:             "native USDC burn and mint attested by Circle",
+		},
+	},
+	{
+		Aliases: []string{"stargate", "layerzero"},
+		Safety: model.BridgeSafety{
+			Bridge:            "stargate",
+			TrustModel:        TrustValidatorSet,
+			AvgFinalityS:      120,
+			MaxRecommendedUSD: 1_000_000,
+			Notes:             "LayerZero messaging verified by configured DVNs",
+		},
+	},
+	{
+		Aliases: []string{"hop"},
+		Safety: model.BridgeSafety{
+			Bridge:            "hop",
+			TrustModel:        TrustOptimistic,
+			AvgFinalityS:      300,
+			MaxRecommendedUSD: 250_000,
+			Notes:             "bonders front liquidity; settles through native rollup bridges",
+		},
+	},
+	{
+		Aliases: []string{"cbridge", "celer"},
+		Safety: model.BridgeSafety{
+			Bridge:     "cbridge",
+			TrustModel: TrustValidatorSet,
+			Exploits: []model.BridgeExploit{
+				{Date: "2022-08-17", LossUSD: 240_000, Summary: "DNS hijack of the cBridge frontend redirected approvals"},
+			},
+			AvgFinalityS:      600,
+			MaxRecommendedUSD: 250_000,
+			Notes:             "Celer State Guardian Network signs transfers",
+		},
+	},
+	{
+		Aliases: []string{"debridge", "dln"},
+		Safety: model.BridgeSafety{
+			Bridge:            "debridge",
+			TrustModel:        TrustValidatorSet,
+			AvgFinalityS:      60,
+			MaxRecommendedUSD: 500_000,
+			Notes:             "intent fills settled by deBridge validators",
+		},
+	},
+	{
+		Aliases: []string{"wormhole", "portal"},
+		Safety: model.BridgeSafety{
+			Bridge:     "wormhole",
+			TrustModel: TrustMultisig,
+			Exploits: []model.BridgeExploit{
+				{Date: "2022-02-02", LossUSD: 326_000_000, Summary: "signature verification bypass minted unbacked wETH on Solana"},
+			},
+			AvgFinalityS:      900,
+			MaxRecommendedUSD: 500_000,
+			Notes:             "13-of-19 guardian set",
+		},
+	},
+	{
+		Aliases: []string{"axelar", "squid"},
+		Safety: model.BridgeSafety{
+			Bridge:            "axelar",
+			TrustModel:        TrustValidatorSet,
+			AvgFinalityS:      600,
+			MaxRecommendedUSD: 500_000,
+			Notes:             "proof-of-stake validator set signs cross-chain messages",
+		},
+	},
+	{
+		Aliases: []string{"arbitrumbridge", "arbitrumcanonical"},
+		Safety: model.BridgeSafety{
+			Bridge:            "arbitrum-bridge",
+			TrustModel:        TrustNative,
+			AvgFinalityS:      600,
+			MaxRecommendedUSD: 50_000_000,
+			Notes:             "canonical rollup bridge; withdrawals to Ethereum take about 7 days",
+		},
+	},
+	{
+		Aliases: []string{"optimismbridge", "opstandardbridge", "basebridge"},
+		Safety: model.BridgeSafety{
+			Bridge:            "op-standard-bridge",
+			TrustModel:        TrustNative,
+			AvgFinalityS:      180,
+			MaxRecommendedUSD: 50_000_000,
+			Notes:             "canonical rollup bridge; withdrawals to Ethereum take about 7 days",
+		},
+	},
+	{
+		Aliases: []string{"multichain", "anyswap"},
+		Safety: model.BridgeSafety{
+			Bridge:     "multichain",
+			TrustModel: TrustMultisig,
+			Exploits: []model.BridgeExploit{
+				{Date: "2022-01-18", LossUSD: 3_000_000, Summary: "router approval vulnerability drained user allowances"},
+				{Date: "2023-07-06", LossUSD: 126_000_000, Summary: "MPC keys compromised; bridge ceased operation"},
+			},
+			MaxRecommendedUSD: 0,
+			Notes:             "defunct; do not route funds",
+		},
+	},
+	{
+		Aliases: []string{"nomad"},
+		Safety: model.BridgeSafety{
+			Bridge:     "nomad",
+			TrustModel: TrustOptimistic,
+			Exploits: []model.BridgeExploit{
+				{Date: "2022-08-01", LossUSD: 190_000_000, Summary: "faulty upgrade accepted unproven messages"},
+			},
+			MaxRecommendedUSD: 0,
+			Notes:             "defunct; do not route funds",
+		},
+	},
+	{
+		Aliases: []string{"ronin"},
+		Safety: model.BridgeSafety{
+			Bridge:     "ronin",
+			TrustModel: TrustMultisig,
+			Exploits: []model.BridgeExploit{
+				{Date: "2022-03-23", LossUSD: 624_000_000, Summary: "5 of 9 validator keys compromised"},
+				{Date: "2024-08-06", LossUSD: 12_000_000, Summary: "misconfigured upgrade bypassed vote threshold; mostly returned by whitehat"},
+			},
+			AvgFinalityS:      600,
+			MaxRecommendedUSD: 100_000,
+		},
+	},
+	{
+		Aliases: []string{"harmony", "horizon"},
+		Safety: model.BridgeSafety{
+			Bridge:     "harmony-horizon",
+			TrustModel: TrustMultisig,
+			Exploits: []model.BridgeExploit{
+				{Date: "2022-06-23", LossUSD: 100_000_000, Summary: "2-of-5 multisig keys compromised"},
+			},
+			MaxRecommendedUSD: 0,
+			Notes:             "defunct; do not route funds",
+		},
+	},
+}
+
+// Lookup returns the rating matching name. Names are lowercased and split
+// into alphanumeric words; an alias matches when the whole name or any word
+// starts with it, so "Stargate V2" and "bungee:auto:across" both resolve.
+// The longest matching alias wins.
+func Lookup(name string) (model.BridgeSafety, bool) {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return (r < 'a' || r > 'z') && (r < '0' || r > '9')
+	})
+	if len(words) == 0 {
+		return model.BridgeSafety{}, false
+	}
+	candidates := append([]string{strings.Join(words, "")}, words...)
+	best, bestLen := -1, 0
+	for i, item := range ratings {
+		for _, alias := range item.Aliases {
+			for _, candidate := range candidates {
+				if strings.HasPrefix(candidate, alias) && len(alias) > bestLen {
+					best, bestLen = i, len(alias)
+				}
+			}
+		}
+	}
+	if best < 0 {
+		return model.BridgeSafety{}, false
+	}
+	return withSource(ratings[best].Safety), true
+}
+
+// ForQuote rates a bridge quote by the bridge named in its route, falling
+// back to the quoting provider when the route does not name one.
+func ForQuote(provider, route string) (model.BridgeSafety, bool) {
+	if safety, ok := Lookup(route); ok {
+		return safety, true
+	}
+	return Lookup(provider)
+}
+
+func withSource(safety model.BridgeSafety) model.BridgeSafety {
+	safety.Exploits = append([]model.BridgeExploit{}, safety.Exploits...)
+	safety.Source = datasetSource
+	safety.AsOf = datasetAsOf
+	return safety
+}
@@ -0,0 +1,57 @@
+package bridgesafety
+
+import "testing"
+
+func TestLookupMatchesProviderBridgeNames(t *testing.T) {
+	cases := map[string]string{
+		"Stargate V2":        "stargate",
+		"bungee:auto:across": "across",
+		"Hop Protocol":       "hop",
+		"Portal by Wormhole": "wormhole",
+		"Circle CCTP":        "cctp",
+		"Arbitrum Bridge":    "arbitrum-bridge",
+	}
+	for name, want := range cases {
+		got, ok := Lookup(name)
+		if !ok {
+			t.Fatalf("expected rating for %q", name)
+		}
+		if got.Bridge != want {
+			t.Fatalf("Lookup(%q) = %s, want %s", name, got.Bridge, want)
+		}
+		if got.Source == "" || got.AsOf == "" || got.Exploits == nil {
+			t.Fatalf("expected source, as_of, and exploits on %q: %+v", name, got)
+		}
+	}
+}
+
+func TestLookupUnknownBridge(t *testing.T) {
+	for _, name := range []string{"", "base->ethereum", "shopbridge"} {
+		if got, ok := Lookup(name); ok {
+			t.Fatalf("expected no rating for %q, got %+v", name, got)
+		}
+	}
+}
+
+func TestForQuoteFallsBackToProvider(t *testing.T) {
+	got, ok := ForQuote("across", "base->ethereum")
+	if !ok || got.Bridge != "across" || got.TrustModel != TrustOptimistic {
+		t.Fatalf("expected across fallback, got %+v (ok=%v)", got, ok)
+	}
+	got, ok = ForQuote("lifi", "Stargate V2")
+	if !ok || got.Bridge != "stargate" {
+		t.Fatalf("expected route bridge to win, got %+v", got)
+	}
+	if _, ok := ForQuote("lifi", "Relay"); ok {
+		t.Fatal("expected unrated route to return no rating")
+	}
+}
+
+func TestLookupReturnsIndependentExploitSlices(t *testing.T) {
+	first, _ := Lookup("wormhole")
+	first.Exploits[0].Summary = "mutated"
+	second, _ := Lookup("wormhole")
+	if second.Exploits[0].Summary == "mutated" {
+		t.Fatal("expected Lookup to copy exploits")
+	}
+}
@@ -23,6 +23,7 @@ type Result struct {
 	Hit      bool
 	Value    []byte
 	Age      time.Duration
+	TTL      time.Duration
 	Stale    bool
 	TooStale bool
 }
@@ -156,6 +157,7 @@ func (s *Store) Get(key string, maxStale time.Duration) (Result, error) {
 		Hit:      true,
 		Value:    value,
 		Age:      age,
+		TTL:      ttl,
 		Stale:    stale,
 		TooStale: tooStale,
 	}, nil
@@ -0,0 +1,265 @@
+package cache
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Shared caches large upstream payloads that several providers or commands
+// read within one TTL, such as the DefiLlama yields /pools list. Bodies are
+// gzip-compressed and stored once per content hash, concurrent fetches of a
+// key share one request, and with revalidate set a stale entry is served
+// while a background refresh replaces it. A Store, when attached, persists
+// entries across invocations.
+type Shared struct {
+	store      *Store
+	maxStale   time.Duration
+	revalidate bool
+	skipReads  bool
+
+	mu      sync.Mutex
+	entries map[string]sharedEntry
+	blobs   map[string][]byte
+	flights map[string]*flight
+	pending sync.WaitGroup
+}
+
+type sharedEntry struct {
+	hash      string
+	fetchedAt time.Time
+	ttl       time.Duration
+}
+
+type flight struct {
+	done  chan struct{}
+	value []byte
+	err   error
+}
+
+// SharedStatus describes how Fetch answered, for logs and traces.
+type SharedStatus string
+
+const (
+	SharedHit   SharedStatus = "hit"
+	SharedStale SharedStatus = "stale"
+	SharedMiss  SharedStatus = "miss"
+	SharedJoin  SharedStatus = "join"
+)
+
+var activeShared atomic.Pointer[Shared]
+
+// NewShared returns a shared cache backed by store, which may be nil for a
+// memory-only cache. maxStale bounds how old an entry served during
+// revalidation may be; a negative value means no bound.
+func NewShared(store *Store, maxStale time.Duration, revalidate bool) *Shared {
+	return &Shared{
+		store:      store,
+		maxStale:   maxStale,
+		revalidate: revalidate,
+		entries:    map[string]sharedEntry{},
+		blobs:      map[string][]byte{},
+		flights:    map[string]*flight{},
+	}
+}
+
+// SkipReads makes Fetch ignore existing entries, for --force-fresh. Fresh
+// bodies are still stored and concurrent fetches still share one request.
+func (s *Shared) SkipReads() {
+	s.skipReads = true
+}
+
+// SetShared installs the process-wide shared cache. nil disables it.
+func SetShared(s *Shared) {
+	activeShared.Store(s)
+}
+
+// ActiveShared returns the process-wide shared cache, or nil.
+func ActiveShared() *Shared {
+	return activeShared.Load()
+}
+
+// Fetch returns the body for key, calling fetch at most once per key at a
+// time. Fresh entries are served from memory or the store; a stale entry is
+// served immediately and refreshed in the background when revalidation is on.
+func (s *Shared) Fetch(ctx context.Context, key string, ttl time.Duration, fetch func(context.Context) ([]byte, error)) ([]byte, SharedStatus, error) {
+	storeKey := sharedKey(key)
+	if s.skipReads {
+		return s.do(ctx, storeKey, ttl, fetch)
+	}
+	if entry, blob, ok := s.lookup(storeKey); ok {
+		age := time.Since(entry.fetchedAt)
+		if age <= entry.ttl {
+			value, err := gunzip(blob)
+			if err == nil {
+				return value, SharedHit, nil
+			}
+		} else if s.revalidate && (s.maxStale < 0 || age <= entry.ttl+s.maxStale) {
+			if value, err := gunzip(blob); err == nil {
+				s.refresh(storeKey, ttl, fetch)
+				return value, SharedStale, nil
+			}
+		}
+	}
+	return s.do(ctx, storeKey, ttl, fetch)
+}
+
+// Wait blocks until background refreshes finish or timeout elapses, so a
+// short-lived process does not exit mid-refresh.
+func (s *Shared) Wait(timeout time.Duration) {
+	done := make(chan struct{})
+	go func() {
+		s.pending.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(timeout):
+	}
+}
+
+func (s *Shared) do(ctx context.Context, key string, ttl time.Duration, fetch func(context.Context) ([]byte, error)) ([]byte, SharedStatus, error) {
+	s.mu.Lock()
+	if f, ok := s.flights[key]; ok {
+		s.mu.Unlock()
+		select {
+		case <-f.done:
+			return f.value, SharedJoin, f.err
+		case <-ctx.Done():
+			return nil, SharedJoin, ctx.Err()
+		}
+	}
+	f := &flight{done: make(chan struct{})}
+	s.flights[key] = f
+	s.mu.Unlock()
+
+	f.value, f.err = fetch(ctx)
+	if f.err == nil {
+		s.save(key, f.value, ttl)
+	}
+	s.mu.Lock()
+	delete(s.flights, key)
+	s.mu.Unlock()
+	close(f.done)
+	return f.value, SharedMiss, f.err
+}
+
+// refresh starts a background fetch for key unless one is already running.
+// It outlives the request that triggered it, so it does not inherit its
+// context.
+func (s *Shared) refresh(key string, ttl time.Duration, fetch func(context.Context) ([]byte, error)) {
+	s.mu.Lock()
+	_, running := s.flights[key]
+	s.mu.Unlock()
+	if running {
+		return
+	}
+	s.pending.Add(1)
+	go func() {
+		defer s.pending.Done()
+		_, _, _ = s.do(context.Background(), key, ttl, fetch)
+	}()
+}
+
+func (s *Shared) lookup(key string) (sharedEntry, []byte, bool) {
+	s.mu.Lock()
+	entry, ok := s.entries[key]
+	blob := s.blobs[entry.hash]
+	s.mu.Unlock()
+	if ok && blob != nil {
+		return entry, blob, true
+	}
+	if s.store == nil {
+		return sharedEntry{}, nil, false
+	}
+	pointer, err := s.store.Get(key, s.maxStale)
+	if err != nil || !pointer.Hit || pointer.TooStale {
+		return sharedEntry{}, nil, false
+	}
+	hash := string(pointer.Value)
+	stored, err := s.store.Get(blobKey(hash), -1)
+	if err != nil || !stored.Hit {
+		return sharedEntry{}, nil, false
+	}
+	entry = sharedEntry{hash: hash, fetchedAt: time.Now().Add(-pointer.Age), ttl: pointer.TTL}
+	s.remember(key, entry, stored.Value)
+	return entry, stored.Value, true
+}
+
+func (s *Shared) save(key string, value []byte, ttl time.Duration) {
+	sum := sha256.Sum256(value)
+	hash := hex.EncodeToString(sum[:])
+	s.mu.Lock()
+	blob, ok := s.blobs[hash]
+	s.mu.Unlock()
+	if !ok {
+		var err error
+		if blob, err = gzipBytes(value); err != nil {
+			return
+		}
+	}
+	s.remember(key, sharedEntry{hash: hash, fetchedAt: time.Now(), ttl: ttl}, blob)
+	if s.store != nil {
+		// Best effort: the entry is still shared in memory if the write fails.
+		if err := s.store.Set(blobKey(hash), blob, ttl); err == nil {
+			_ = s.store.Set(key, []byte(hash), ttl)
+		}
+	}
+}
+
+func (s *Shared) remember(key string, entry sharedEntry, blob []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if previous, ok := s.entries[key]; ok && previous.hash != entry.hash && !s.referenced(previous.hash, key) {
+		delete(s.blobs, previous.hash)
+	}
+	s.entries[key] = entry
+	s.blobs[entry.hash] = blob
+}
+
+// referenced reports whether another key still points at hash. Callers hold mu.
+func (s *Shared) referenced(hash, except string) bool {
+	for key, entry := range s.entries {
+		if key != except && entry.hash == hash {
+			return true
+		}
+	}
+	return false
+}
+
+func sharedKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return "shared:" + hex.EncodeToString(sum[:])
+}
+
+func blobKey(hash string) string {
+	return "blob:" + hash
+}
+
+func gzipBytes(value []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(value); err != nil {
+		return nil, fmt.Errorf("compress shared entry: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("compress shared entry: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func gunzip(blob []byte) ([]byte, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(blob))
+	if err != nil {
+		return nil, fmt.Errorf("decompress shared entry: %w", err)
+	}
+	defer zr.Close()
+	return io.ReadAll(zr)
+}
@@ -0,0 +1,143 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSharedFetchDedupesConcurrentCalls(t *testing.T) {
+	shared := NewShared(nil, time.Minute, false)
+	var calls atomic.Int32
+	release := make(chan struct{})
+	fetch := func(context.Context) ([]byte, error) {
+		calls.Add(1)
+		<-release
+		return []byte(`[1,2,3]`), nil
+	}
+
+	var wg sync.WaitGroup
+	results := make([][]byte, 5)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			value, _, err := shared.Fetch(context.Background(), "GET https://yields.llama.fi/pools", time.Minute, fetch)
+			if err != nil {
+				t.Errorf("Fetch failed: %v", err)
+			}
+			results[i] = value
+		}(i)
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("expected one upstream fetch, got %d", got)
+	}
+	for _, value := range results {
+		if string(value) != `[1,2,3]` {
+			t.Fatalf("unexpected shared value %q", value)
+		}
+	}
+	if _, status, _ := shared.Fetch(context.Background(), "GET https://yields.llama.fi/pools", time.Minute, fetch); status != SharedHit {
+		t.Fatalf("expected memory hit after fetch, got %s", status)
+	}
+}
+
+func TestSharedPersistsCompressedContentAddressedBlobs(t *testing.T) {
+	tmp := t.TempDir()
+	store, err := Open(filepath.Join(tmp, "cache.db"), filepath.Join(tmp, "cache.lock"), time.Minute)
+	if err != nil {
+		t.Fatalf("Open cache failed: %v", err)
+	}
+	defer store.Close()
+
+	payload := bytes.Repeat([]byte(`{"chain":"Ethereum","project":"lido","tvlUsd":1}`), 200)
+	fetch := func(context.Context) ([]byte, error) { return payload, nil }
+	first := NewShared(store, time.Minute, false)
+	if _, _, err := first.Fetch(context.Background(), "GET https://a.example/pools", time.Minute, fetch); err != nil {
+		t.Fatalf("Fetch failed: %v", err)
+	}
+	if _, _, err := first.Fetch(context.Background(), "GET https://b.example/pools", time.Minute, fetch); err != nil {
+		t.Fatalf("Fetch failed: %v", err)
+	}
+
+	var blobs, blobBytes int
+	rows, err := store.db.Query("SELECT key, length(value) FROM cache_entries WHERE key LIKE 'blob:%'")
+	if err != nil {
+		t.Fatalf("query blobs: %v", err)
+	}
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key, &blobBytes); err != nil {
+			t.Fatalf("scan: %v", err)
+		}
+		blobs++
+	}
+	_ = rows.Close()
+	if blobs != 1 {
+		t.Fatalf("expected identical payloads to share one blob, got %d", blobs)
+	}
+	if blobBytes >= len(payload)/4 {
+		t.Fatalf("expected compressed blob, got %d bytes for %d byte payload", blobBytes, len(payload))
+	}
+
+	// A new process-level cache reads the persisted entry without fetching.
+	second := NewShared(store, time.Minute, false)
+	value, status, err := second.Fetch(context.Background(), "GET https://a.example/pools", time.Minute, func(context.Context) ([]byte, error) {
+		return nil, errors.New("unexpected fetch")
+	})
+	if err != nil || status != SharedHit || !bytes.Equal(value, payload) {
+		t.Fatalf("expected persisted hit, got status=%s err=%v", status, err)
+	}
+}
+
+func TestSharedServesStaleWhileRevalidating(t *testing.T) {
+	shared := NewShared(nil, time.Minute, true)
+	version := atomic.Int32{}
+	fetch := func(context.Context) ([]byte, error) {
+		return []byte{byte('0' + version.Add(1))}, nil
+	}
+	if _, _, err := shared.Fetch(context.Background(), "k", 10*time.Millisecond, fetch); err != nil {
+		t.Fatalf("Fetch failed: %v", err)
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	// The entry keeps the TTL it was stored with; the refresh uses this one.
+	value, status, err := shared.Fetch(context.Background(), "k", time.Minute, fetch)
+	if err != nil || status != SharedStale || string(value) != "1" {
+		t.Fatalf("expected stale value 1, got %q status=%s err=%v", value, status, err)
+	}
+	shared.Wait(time.Second)
+	value, status, _ = shared.Fetch(context.Background(), "k", time.Minute, fetch)
+	if status != SharedHit || string(value) != "2" {
+		t.Fatalf("expected refreshed value 2, got %q status=%s", value, status)
+	}
+}
+
+func TestSharedSkipReadsRefetches(t *testing.T) {
+	shared := NewShared(nil, time.Minute, false)
+	var calls atomic.Int32
+	fetch := func(context.Context) ([]byte, error) {
+		calls.Add(1)
+		return []byte(`[1]`), nil
+	}
+	key := "GET https://yields.llama.fi/pools"
+	if _, _, err := shared.Fetch(context.Background(), key, time.Minute, fetch); err != nil {
+		t.Fatalf("Fetch failed: %v", err)
+	}
+	shared.SkipReads()
+	if _, status, err := shared.Fetch(context.Background(), key, time.Minute, fetch); err != nil || status != SharedMiss {
+		t.Fatalf("expected a miss with reads skipped, got %s %v", status, err)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Fatalf("expected two upstream fetches, got %d", got)
+	}
+}
@@ -0,0 +1,209 @@
+// Package chainlist syncs EVM chain metadata from the ethereum-lists chain
+// registry (the data behind chainlist.org) into a local file. Synced chains
+// extend the built-in chains in internal/id so new networks resolve by name
+// without a release.
+package chainlist
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ggonzalez94/defi-cli/internal/httpx"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+)
+
+// MaxAge is how long a synced registry is used before a lookup of an unknown
+// chain name refreshes it.
+const MaxAge = 7 * 24 * time.Hour
+
+// Registry is the local copy of the chain list.
+type Registry struct {
+	Source   string    `json:"source"`
+	SyncedAt time.Time `json:"synced_at"`
+	Chains   []Chain   `json:"chains"`
+}
+
+// Chain is one EVM chain as published by ethereum-lists.
+type Chain struct {
+	ChainID        int64    `json:"chainId"`
+	Name           string   `json:"name"`
+	ShortName      string   `json:"shortName,omitempty"`
+	NativeCurrency Currency `json:"nativeCurrency"`
+	RPC            []string `json:"rpc,omitempty"`
+}
+
+// Currency is a chain's native gas token.
+type Currency struct {
+	Name     string `json:"name"`
+	Symbol   string `json:"symbol"`
+	Decimals int    `json:"decimals"`
+}
+
+// upstreamChain is the subset of the ethereum-lists schema that is kept.
+type upstreamChain struct {
+	Chain
+	Status string `json:"status"`
+}
+
+// Fetch downloads the chain list at url. Deprecated chains are dropped, as
+// are RPC URLs that need an API key or are not plain HTTP(S).
+func Fetch(ctx context.Context, client *httpx.Client, url string) ([]Chain, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	var upstream []upstreamChain
+	if _, err := client.DoJSON(ctx, req, &upstream); err != nil {
+		return nil, err
+	}
+	chains := make([]Chain, 0, len(upstream))
+	for _, c := range upstream {
+		if c.ChainID <= 0 || strings.TrimSpace(c.Name) == "" || strings.EqualFold(c.Status, "deprecated") {
+			continue
+		}
+		chain := c.Chain
+		chain.Name = strings.TrimSpace(chain.Name)
+		chain.ShortName = strings.TrimSpace(chain.ShortName)
+		chain.RPC = publicRPCs(chain.RPC)
+		chains = append(chains, chain)
+	}
+	if len(chains) == 0 {
+		return nil, fmt.Errorf("%s is not a chain list: no chains found", url)
+	}
+	return chains, nil
+}
+
+// Load reads the registry at path. A missing file is an empty registry.
+func Load(path string) (Registry, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return Registry{}, nil
+		}
+		return Registry{}, fmt.Errorf("read chain registry: %w", err)
+	}
+	var reg Registry
+	if err := json.Unmarshal(buf, &reg); err != nil {
+		return Registry{}, fmt.Errorf("chain registry %s: %w", path, err)
+	}
+	return reg, nil
+}
+
+// Save writes the registry to path, sorted by chain ID.
+func Save(path string, reg Registry) error {
+	sort.SliceStable(reg.Chains, func(i, j int) bool {
+		return reg.Chains[i].ChainID < reg.Chains[j].ChainID
+	})
+	buf, err := json.MarshalIndent(reg, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create chain registry dir: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".chains-*")
+	if err != nil {
+		return fmt.Errorf("write chain registry: %w", err)
+	}
+	if _, err := tmp.Write(append(buf, '\n')); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("write chain registry: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("write chain registry: %w", err)
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Stale reports whether the registry was never synced or is older than
+// maxAge.
+func (r Registry) Stale(now time.Time, maxAge time.Duration) bool {
+	return r.SyncedAt.IsZero() || now.Sub(r.SyncedAt) > maxAge
+}
+
+// Entries converts the registry to id chain entries. The slug is derived
+// from the chain name; the short name and the lower-case name are aliases.
+func (r Registry) Entries() []id.ChainEntry {
+	entries := make([]id.ChainEntry, 0, len(r.Chains))
+	for _, c := range r.Chains {
+		slug := Slug(c.Name)
+		if slug == "" {
+			continue
+		}
+		entry := id.ChainEntry{Chain: id.Chain{
+			Name:       c.Name,
+			Slug:       slug,
+			CAIP2:      "eip155:" + strconv.FormatInt(c.ChainID, 10),
+			EVMChainID: c.ChainID,
+		}}
+		for _, alias := range []string{strings.ToLower(c.ShortName), strings.ToLower(c.Name)} {
+			if alias != "" && alias != slug && !isNumeric(alias) && !strings.Contains(alias, ":") {
+				entry.Aliases = append(entry.Aliases, alias)
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// RPCURLs returns the public RPC endpoints of every chain, keyed by chain ID.
+func (r Registry) RPCURLs() map[int64][]string {
+	out := map[int64][]string{}
+	for _, c := range r.Chains {
+		if len(c.RPC) > 0 {
+			out[c.ChainID] = c.RPC
+		}
+	}
+	return out
+}
+
+// Slug lower-cases name and joins its words with dashes.
+func Slug(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(strings.TrimSpace(name)) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+		dash = true
+	}
+	return b.String()
+}
+
+func publicRPCs(urls []string) []string {
+	var out []string
+	for _, raw := range urls {
+		raw = strings.TrimSpace(raw)
+		if raw == "" || strings.Contains(raw, "${") {
+			continue
+		}
+		parsed, err := url.Parse(raw)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			continue
+		}
+		out = append(out, raw)
+	}
+	return out
+}
+
+func isNumeric(s string) bool {
+	_, err := strconv.ParseInt(s, 10, 64)
+	return err == nil
+}
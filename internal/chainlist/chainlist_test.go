@@ -0,0 +1,79 @@
+package chainlist
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ggonzalez94/defi-cli/internal/httpx"
+)
+
+const upstreamFixture = `[
+  {"name": "Unichain", "shortName": "unichain", "chainId": 130, "nativeCurrency": {"name": "Ether", "symbol": "ETH", "decimals": 18},
+   "rpc": ["https://mainnet.unichain.org", "wss://unichain.example", "https://rpc.example/${API_KEY}"]},
+  {"name": "Old Chain", "shortName": "old", "chainId": 7, "status": "deprecated", "rpc": ["https://old.example"]},
+  {"name": "", "chainId": 8}
+]`
+
+func TestFetchFiltersChainsAndRPCs(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(upstreamFixture))
+	}))
+	defer srv.Close()
+
+	chains, err := Fetch(context.Background(), httpx.New(5*time.Second, 0), srv.URL)
+	if err != nil {
+		t.Fatalf("Fetch failed: %v", err)
+	}
+	if len(chains) != 1 || chains[0].ChainID != 130 || chains[0].NativeCurrency.Symbol != "ETH" {
+		t.Fatalf("unexpected chains %+v", chains)
+	}
+	if len(chains[0].RPC) != 1 || chains[0].RPC[0] != "https://mainnet.unichain.org" {
+		t.Fatalf("expected only the public https rpc, got %v", chains[0].RPC)
+	}
+}
+
+func TestEntriesAndSlug(t *testing.T) {
+	reg := Registry{Chains: []Chain{{ChainID: 42170, Name: "Arbitrum Nova", ShortName: "arb-nova"}}}
+	entries := reg.Entries()
+	if len(entries) != 1 {
+		t.Fatalf("expected one entry, got %+v", entries)
+	}
+	entry := entries[0]
+	if entry.Chain.Slug != "arbitrum-nova" || entry.Chain.CAIP2 != "eip155:42170" || entry.Chain.EVMChainID != 42170 {
+		t.Fatalf("unexpected chain %+v", entry.Chain)
+	}
+	if len(entry.Aliases) != 2 || entry.Aliases[0] != "arb-nova" || entry.Aliases[1] != "arbitrum nova" {
+		t.Fatalf("unexpected aliases %v", entry.Aliases)
+	}
+	if got := Slug("  OP Mainnet (Legacy) "); got != "op-mainnet-legacy" {
+		t.Fatalf("unexpected slug %q", got)
+	}
+}
+
+func TestSaveLoadAndStale(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "chains.json")
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	reg, err := Load(path)
+	if err != nil || !reg.Stale(now, MaxAge) {
+		t.Fatalf("expected a missing file to load as a stale empty registry, got %+v err=%v", reg, err)
+	}
+	reg = Registry{Source: "https://chains.example", SyncedAt: now, Chains: []Chain{{ChainID: 130, Name: "Unichain"}, {ChainID: 7, Name: "ThaiChain"}}}
+	if err := Save(path, reg); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(loaded.Chains) != 2 || loaded.Chains[0].ChainID != 7 || !loaded.SyncedAt.Equal(now) {
+		t.Fatalf("unexpected registry %+v", loaded)
+	}
+	if loaded.Stale(now.Add(time.Hour), MaxAge) || !loaded.Stale(now.Add(MaxAge+time.Hour), MaxAge) {
+		t.Fatal("unexpected staleness")
+	}
+}
@@ -0,0 +1,34 @@
+// Package clock is the process-wide wall clock. Fixture replay
+// (--replay-fixtures) freezes it so timestamps and time windows derived from
+// "now" are identical across runs.
+package clock
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+var frozen atomic.Pointer[time.Time]
+
+// Now returns the frozen time when one is installed and time.Now otherwise.
+func Now() time.Time {
+	if t := frozen.Load(); t != nil {
+		return *t
+	}
+	return time.Now()
+}
+
+// Freeze makes Now return t until Reset.
+func Freeze(t time.Time) {
+	frozen.Store(&t)
+}
+
+// Reset restores the wall clock.
+func Reset() {
+	frozen.Store(nil)
+}
+
+// Frozen reports whether Now returns a frozen time.
+func Frozen() bool {
+	return frozen.Load() != nil
+}
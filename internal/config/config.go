@@ -3,6 +3,7 @@ package config
 import (
 	"errors"
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -10,86 +11,298 @@ import (
 	"time"
 
 	"github.com/ggonzalez94/defi-cli/internal/fsutil"
+	"github.com/ggonzalez94/defi-cli/internal/i18n"
+	"github.com/ggonzalez94/defi-cli/internal/logx"
+	"github.com/jmespath/go-jmespath"
 	"gopkg.in/yaml.v3"
 )
 
 type GlobalFlags struct {
-	ConfigPath     string
-	JSON           bool
-	Plain          bool
-	Select         string
-	ResultsOnly    bool
-	EnableCommands string
-	Strict         bool
-	Timeout        string
-	Retries        int
-	MaxStale       string
-	NoStale        bool
-	NoCache        bool
-}
-
-type Settings struct {
-	OutputMode      string
-	SelectFields    []string
+	ConfigPath      string
+	Profile         string
+	JSON            bool
+	Plain           bool
+	Lang            string
+	Select          string
+	Query           string
 	ResultsOnly     bool
-	EnableCommands  []string
+	Compact         bool
+	CompactGzip     int
+	EnableCommands  string
 	Strict          bool
-	Timeout         time.Duration
+	Timeout         string
 	Retries         int
-	MaxStale        time.Duration
+	Concurrency     int
+	ProviderTimeout string
+	MaxStale        string
 	NoStale         bool
-	CacheEnabled    bool
-	CachePath       string
-	CacheLockPath   string
-	ActionStorePath string
-	ActionLockPath  string
-	DefiLlamaAPIKey string
-	UniswapAPIKey   string
-	OneInchAPIKey   string
-	JupiterAPIKey   string
-	BungeeAPIKey    string
-	BungeeAffiliate string
+	NoCache         bool
+	CacheTTL        string
+	ForceFresh      bool
+	Offline         bool
+	ResolvePolicy   string
+	PreferTokenList string
+	StrictAsset     bool
+	ResolveUnknown  bool
+	Verbose         bool
+	LogLevel        string
+	LogFile         string
+	Trace           bool
+	Seed            string
+	RecordFixtures  string
+	ReplayFixtures  string
+	NotifyURL       string
+}
+
+type Settings struct {
+	Profile          string
+	OutputMode       string
+	Lang             string
+	SelectFields     []string
+	Query            string
+	ResultsOnly      bool
+	Compact          bool
+	CompactGzipBytes int
+	EnableCommands   []string
+	Strict           bool
+	Timeout          time.Duration
+	Retries          int
+	Concurrency      int
+	ProviderTimeout  time.Duration
+	MaxConnsPerHost  int
+	ProviderHTTP     map[string]ProviderHTTP
+	BreakerFailures  int
+	BreakerCooldown  time.Duration
+	MaxStale         time.Duration
+	NoStale          bool
+	StaleRevalidate  bool
+	CacheEnabled     bool
+	CacheTTL         time.Duration
+	ForceFresh       bool
+	Offline          bool
+	CachePath        string
+	CacheLockPath    string
+	ActionStorePath  string
+	ActionLockPath   string
+	PolicyPath       string
+	QuotesPath       string
+	QuotesLockPath   string
+	ResolvePolicy    string
+	PreferTokenList  string
+	StrictAsset      bool
+	TokensPath       string
+	LabelsPath       string
+	ResolveUnknown   bool
+	TokenListURL     string
+	ChainsPath       string
+	ChainListURL     string
+	ChainsRefresh    bool
+	LogLevel         string
+	LogFile          string
+	Trace            bool
+	TraceParent      string
+	OTLPEndpoint     string
+	OTLPHeaders      map[string]string
+	MetricsListen    string
+	MetricsOTLPURL   string
+	MetricsHeaders   map[string]string
+	MetricsInterval  time.Duration
+	Seed             *int64
+	RecordFixtures   string
+	ReplayFixtures   string
+	AllowProtocols   []string
+	DenyProtocols    []string
+	DefiLlamaAPIKey  string
+	UniswapAPIKey    string
+	OneInchAPIKey    string
+	JupiterAPIKey    string
+	BungeeAPIKey     string
+	BungeeAffiliate  string
+	EtherscanAPIKey  string
+	EtherscanBaseURL string
+	TheGraphAPIKey   string
+	RPCURLs          map[string][]string
+	DefaultProviders map[string]string
+	Paymasters       []Paymaster
+	Webhooks         []Webhook
+	NotifyURL        string
+	NotifySecret     string
+}
+
+// Webhook is an endpoint declared under notify.webhooks that receives action
+// status transitions.
+type Webhook struct {
+	URL       string `yaml:"url"`
+	URLEnv    string `yaml:"url_env"`
+	Secret    string `yaml:"secret"`
+	SecretEnv string `yaml:"secret_env"`
+	// Events limits deliveries to these new statuses; empty means all.
+	Events []string `yaml:"events"`
+}
+
+// Paymaster is an ERC-4337 paymaster declared under aa.paymasters.
+type Paymaster struct {
+	Name        string   `yaml:"name"`
+	Provider    string   `yaml:"provider"`
+	Chains      []string `yaml:"chains"`
+	Mode        string   `yaml:"mode"`
+	Policy      string   `yaml:"policy"`
+	FeeTokens   []string `yaml:"fee_tokens"`
+	Address     string   `yaml:"address"`
+	EntryPoints []string `yaml:"entry_points"`
+	URL         string   `yaml:"url"`
+	URLEnv      string   `yaml:"url_env"`
 }
 
 type fileConfig struct {
-	Output  string `yaml:"output"`
-	Strict  *bool  `yaml:"strict"`
-	Timeout string `yaml:"timeout"`
-	Retries *int   `yaml:"retries"`
-	Cache   struct {
-		Enabled  *bool  `yaml:"enabled"`
-		MaxStale string `yaml:"max_stale"`
+	Profile         string          `yaml:"profile"`
+	Output          string          `yaml:"output"`
+	Lang            string          `yaml:"lang"`
+	Compact         *bool           `yaml:"compact"`
+	Strict          *bool           `yaml:"strict"`
+	Timeout         string          `yaml:"timeout"`
+	Retries         *int            `yaml:"retries"`
+	Concurrency     *int            `yaml:"concurrency"`
+	ProviderTimeout string          `yaml:"provider_timeout"`
+	Cache           cacheConfig     `yaml:"cache"`
+	Execution       executionConfig `yaml:"execution"`
+	Quotes          struct {
 		Path     string `yaml:"path"`
 		LockPath string `yaml:"lock_path"`
-	} `yaml:"cache"`
-	Execution struct {
-		ActionsPath     string `yaml:"actions_path"`
-		ActionsLockPath string `yaml:"actions_lock_path"`
-	} `yaml:"execution"`
-	Providers struct {
-		DefiLlama struct {
-			APIKey    string `yaml:"api_key"`
-			APIKeyEnv string `yaml:"api_key_env"`
-		} `yaml:"defillama"`
-		Uniswap struct {
-			APIKey    string `yaml:"api_key"`
-			APIKeyEnv string `yaml:"api_key_env"`
-		} `yaml:"uniswap"`
-		OneInch struct {
-			APIKey    string `yaml:"api_key"`
-			APIKeyEnv string `yaml:"api_key_env"`
-		} `yaml:"oneinch"`
-		Jupiter struct {
-			APIKey    string `yaml:"api_key"`
-			APIKeyEnv string `yaml:"api_key_env"`
-		} `yaml:"jupiter"`
-		Bungee struct {
-			APIKey       string `yaml:"api_key"`
-			APIKeyEnv    string `yaml:"api_key_env"`
-			Affiliate    string `yaml:"affiliate"`
-			AffiliateEnv string `yaml:"affiliate_env"`
-		} `yaml:"bungee"`
-	} `yaml:"providers"`
+	} `yaml:"quotes"`
+	Assets struct {
+		ResolvePolicy   string `yaml:"resolve_policy"`
+		PreferTokenList string `yaml:"prefer_token_list"`
+		StrictAsset     *bool  `yaml:"strict_asset"`
+		TokensPath      string `yaml:"tokens_path"`
+		ResolveUnknown  *bool  `yaml:"resolve_unknown"`
+		TokenListURL    string `yaml:"token_list_url"`
+	} `yaml:"assets"`
+	Labels struct {
+		Path string `yaml:"path"`
+	} `yaml:"labels"`
+	Chains struct {
+		RegistryPath string `yaml:"registry_path"`
+		ListURL      string `yaml:"list_url"`
+		AutoRefresh  *bool  `yaml:"auto_refresh"`
+	} `yaml:"chains"`
+	Log struct {
+		Level string `yaml:"level"`
+		File  string `yaml:"file"`
+	} `yaml:"log"`
+	HTTP struct {
+		MaxConnsPerHost int                     `yaml:"max_conns_per_host"`
+		Providers       map[string]ProviderHTTP `yaml:"providers"`
+		CircuitBreaker  struct {
+			Failures *int   `yaml:"failures"`
+			Cooldown string `yaml:"cooldown"`
+		} `yaml:"circuit_breaker"`
+	} `yaml:"http"`
+	Metrics struct {
+		Listen       string `yaml:"listen"`
+		OTLPEndpoint string `yaml:"otlp_endpoint"`
+		PushInterval string `yaml:"push_interval"`
+	} `yaml:"metrics"`
+	Policy struct {
+		AllowProtocols []string `yaml:"allow_protocols"`
+		DenyProtocols  []string `yaml:"deny_protocols"`
+	} `yaml:"policy"`
+	Providers        providersConfig       `yaml:"providers"`
+	RPC              map[string]rpcURLList `yaml:"rpc"`
+	DefaultProviders map[string]string     `yaml:"default_providers"`
+	AA               struct {
+		Paymasters []Paymaster `yaml:"paymasters"`
+	} `yaml:"aa"`
+	Notify struct {
+		Webhooks []Webhook `yaml:"webhooks"`
+	} `yaml:"notify"`
+	Profiles map[string]profileConfig `yaml:"profiles"`
+}
+
+// ProviderHTTP tunes HTTP requests to one provider (http.providers.<name>).
+type ProviderHTTP struct {
+	// RateLimit is the sustained request rate in requests per second; 0
+	// means unlimited.
+	RateLimit float64 `yaml:"rate_limit"`
+	// Burst is how many requests may go out at once before RateLimit
+	// applies.
+	Burst int `yaml:"burst"`
+	// Retries replaces the global retry count for this provider.
+	Retries *int `yaml:"retries"`
+}
+
+// profileConfig is a named overlay under profiles.<name>. Its values replace
+// the top-level ones when the profile is selected.
+type profileConfig struct {
+	Cache            cacheConfig           `yaml:"cache"`
+	Execution        executionConfig       `yaml:"execution"`
+	Providers        providersConfig       `yaml:"providers"`
+	RPC              map[string]rpcURLList `yaml:"rpc"`
+	DefaultProviders map[string]string     `yaml:"default_providers"`
+}
+
+// rpcURLList accepts either one URL or a list of URLs, primary first.
+type rpcURLList []string
+
+func (l *rpcURLList) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*l = rpcURLList{node.Value}
+		return nil
+	}
+	var list []string
+	if err := node.Decode(&list); err != nil {
+		return fmt.Errorf("rpc endpoints must be a URL or a list of URLs: %w", err)
+	}
+	*l = list
+	return nil
+}
+
+type cacheConfig struct {
+	Enabled              *bool  `yaml:"enabled"`
+	MaxStale             string `yaml:"max_stale"`
+	Path                 string `yaml:"path"`
+	LockPath             string `yaml:"lock_path"`
+	StaleWhileRevalidate *bool  `yaml:"stale_while_revalidate"`
+}
+
+type executionConfig struct {
+	ActionsPath     string `yaml:"actions_path"`
+	ActionsLockPath string `yaml:"actions_lock_path"`
+	PolicyPath      string `yaml:"policy_path"`
+}
+
+type providersConfig struct {
+	DefiLlama struct {
+		APIKey    string `yaml:"api_key"`
+		APIKeyEnv string `yaml:"api_key_env"`
+	} `yaml:"defillama"`
+	Uniswap struct {
+		APIKey    string `yaml:"api_key"`
+		APIKeyEnv string `yaml:"api_key_env"`
+	} `yaml:"uniswap"`
+	OneInch struct {
+		APIKey    string `yaml:"api_key"`
+		APIKeyEnv string `yaml:"api_key_env"`
+	} `yaml:"oneinch"`
+	Jupiter struct {
+		APIKey    string `yaml:"api_key"`
+		APIKeyEnv string `yaml:"api_key_env"`
+	} `yaml:"jupiter"`
+	Bungee struct {
+		APIKey       string `yaml:"api_key"`
+		APIKeyEnv    string `yaml:"api_key_env"`
+		Affiliate    string `yaml:"affiliate"`
+		AffiliateEnv string `yaml:"affiliate_env"`
+	} `yaml:"bungee"`
+	Etherscan struct {
+		APIKey    string `yaml:"api_key"`
+		APIKeyEnv string `yaml:"api_key_env"`
+		BaseURL   string `yaml:"base_url"`
+	} `yaml:"etherscan"`
+	TheGraph struct {
+		APIKey    string `yaml:"api_key"`
+		APIKeyEnv string `yaml:"api_key_env"`
+	} `yaml:"thegraph"`
 }
 
 func Load(flags GlobalFlags) (Settings, error) {
@@ -103,7 +316,7 @@ func Load(flags GlobalFlags) (Settings, error) {
 		return Settings{}, err
 	}
 
-	if err := applyFileConfig(cfgPath, &settings); err != nil {
+	if err := applyFileConfig(cfgPath, selectedProfile(flags), &settings); err != nil {
 		return Settings{}, err
 	}
 
@@ -122,6 +335,12 @@ func Load(flags GlobalFlags) (Settings, error) {
 	if settings.Retries < 0 {
 		settings.Retries = 0
 	}
+	if settings.Concurrency < 1 {
+		settings.Concurrency = 1
+	}
+	if settings.ProviderTimeout < 0 {
+		settings.ProviderTimeout = 0
+	}
 	if settings.MaxStale < 0 {
 		settings.MaxStale = 5 * time.Minute
 	}
@@ -135,16 +354,34 @@ func defaultSettings() (Settings, error) {
 		return Settings{}, err
 	}
 	cacheDir := filepath.Dir(cachePath)
+	policyPath, tokensPath, labelsPath := "", "", ""
+	if home, err := os.UserHomeDir(); err == nil {
+		policyPath = filepath.Join(home, ".defi", "policy.yaml")
+		tokensPath = filepath.Join(home, ".defi", "tokens.json")
+		labelsPath = filepath.Join(home, ".defi", "labels.json")
+	}
 	return Settings{
 		OutputMode:      "json",
+		Lang:            i18n.Default,
 		Timeout:         10 * time.Second,
 		Retries:         2,
+		Concurrency:     4,
 		MaxStale:        5 * time.Minute,
 		CacheEnabled:    true,
 		CachePath:       cachePath,
 		CacheLockPath:   lockPath,
 		ActionStorePath: filepath.Join(cacheDir, "actions.db"),
 		ActionLockPath:  filepath.Join(cacheDir, "actions.lock"),
+		QuotesPath:      filepath.Join(cacheDir, "quotes.db"),
+		QuotesLockPath:  filepath.Join(cacheDir, "quotes.lock"),
+		PolicyPath:      policyPath,
+		ResolvePolicy:   "error",
+		TokensPath:      tokensPath,
+		LabelsPath:      labelsPath,
+		ChainsRefresh:   true,
+		MetricsInterval: time.Minute,
+		BreakerFailures: 5,
+		BreakerCooldown: 30 * time.Second,
 	}, nil
 }
 
@@ -176,23 +413,27 @@ func defaultCachePaths() (string, string, error) {
 	return filepath.Join(dir, "cache.db"), filepath.Join(dir, "cache.lock"), nil
 }
 
-func applyFileConfig(path string, settings *Settings) error {
-	buf, err := os.ReadFile(path)
+func applyFileConfig(path, profile string, settings *Settings) error {
+	cfg, err := readFileConfig(path)
 	if err != nil {
 		if errors.Is(err, os.ErrNotExist) {
+			if profile != "" {
+				return fmt.Errorf("config profile %q requested but %s does not exist", profile, path)
+			}
 			return nil
 		}
-		return fmt.Errorf("read config: %w", err)
-	}
-
-	var cfg fileConfig
-	if err := yaml.Unmarshal(buf, &cfg); err != nil {
-		return fmt.Errorf("parse config yaml: %w", err)
+		return err
 	}
 
 	if cfg.Output != "" {
 		settings.OutputMode = strings.ToLower(cfg.Output)
 	}
+	if cfg.Lang != "" {
+		settings.Lang = i18n.Normalize(cfg.Lang)
+	}
+	if cfg.Compact != nil {
+		settings.Compact = *cfg.Compact
+	}
 	if cfg.Strict != nil {
 		settings.Strict = *cfg.Strict
 	}
@@ -206,72 +447,327 @@ func applyFileConfig(path string, settings *Settings) error {
 	if cfg.Retries != nil {
 		settings.Retries = *cfg.Retries
 	}
-	if cfg.Cache.Enabled != nil {
-		settings.CacheEnabled = *cfg.Cache.Enabled
+	if cfg.Concurrency != nil {
+		settings.Concurrency = *cfg.Concurrency
+	}
+	if cfg.ProviderTimeout != "" {
+		d, err := time.ParseDuration(cfg.ProviderTimeout)
+		if err != nil {
+			return fmt.Errorf("config provider_timeout: %w", err)
+		}
+		settings.ProviderTimeout = d
+	}
+	if err := applyCacheConfig(cfg.Cache, settings); err != nil {
+		return err
+	}
+	applyExecutionConfig(cfg.Execution, settings)
+	if cfg.Quotes.Path != "" {
+		settings.QuotesPath = cfg.Quotes.Path
+	}
+	if cfg.Quotes.LockPath != "" {
+		settings.QuotesLockPath = cfg.Quotes.LockPath
+	}
+	if cfg.Assets.ResolvePolicy != "" {
+		settings.ResolvePolicy = strings.ToLower(strings.TrimSpace(cfg.Assets.ResolvePolicy))
+	}
+	if cfg.Assets.PreferTokenList != "" {
+		settings.PreferTokenList = strings.TrimSpace(cfg.Assets.PreferTokenList)
+	}
+	if cfg.Assets.StrictAsset != nil {
+		settings.StrictAsset = *cfg.Assets.StrictAsset
+	}
+	if cfg.Assets.TokensPath != "" {
+		settings.TokensPath = cfg.Assets.TokensPath
+	}
+	if cfg.Assets.ResolveUnknown != nil {
+		settings.ResolveUnknown = *cfg.Assets.ResolveUnknown
+	}
+	if cfg.Assets.TokenListURL != "" {
+		settings.TokenListURL = strings.TrimSpace(cfg.Assets.TokenListURL)
+	}
+	if cfg.Labels.Path != "" {
+		settings.LabelsPath = cfg.Labels.Path
+	}
+	if cfg.Chains.RegistryPath != "" {
+		settings.ChainsPath = cfg.Chains.RegistryPath
+	}
+	if cfg.Chains.ListURL != "" {
+		settings.ChainListURL = strings.TrimSpace(cfg.Chains.ListURL)
+	}
+	if cfg.Chains.AutoRefresh != nil {
+		settings.ChainsRefresh = *cfg.Chains.AutoRefresh
+	}
+	if cfg.Log.Level != "" {
+		settings.LogLevel = cfg.Log.Level
+	}
+	if cfg.Log.File != "" {
+		settings.LogFile = cfg.Log.File
+	}
+	if cfg.HTTP.MaxConnsPerHost != 0 {
+		if cfg.HTTP.MaxConnsPerHost < 0 {
+			return fmt.Errorf("config http.max_conns_per_host: must be positive")
+		}
+		settings.MaxConnsPerHost = cfg.HTTP.MaxConnsPerHost
+	}
+	for name, policy := range cfg.HTTP.Providers {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if policy.RateLimit < 0 || policy.Burst < 0 || (policy.Retries != nil && *policy.Retries < 0) {
+			return fmt.Errorf("config http.providers.%s: rate_limit, burst, and retries must not be negative", name)
+		}
+		if settings.ProviderHTTP == nil {
+			settings.ProviderHTTP = map[string]ProviderHTTP{}
+		}
+		settings.ProviderHTTP[name] = policy
+	}
+	if breaker := cfg.HTTP.CircuitBreaker; breaker.Failures != nil {
+		if *breaker.Failures < 0 {
+			return fmt.Errorf("config http.circuit_breaker.failures: must not be negative")
+		}
+		settings.BreakerFailures = *breaker.Failures
+	}
+	if cooldown := cfg.HTTP.CircuitBreaker.Cooldown; cooldown != "" {
+		d, err := time.ParseDuration(cooldown)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("config http.circuit_breaker.cooldown: invalid duration %q", cooldown)
+		}
+		settings.BreakerCooldown = d
+	}
+	if cfg.Metrics.Listen != "" {
+		settings.MetricsListen = strings.TrimSpace(cfg.Metrics.Listen)
+	}
+	if cfg.Metrics.OTLPEndpoint != "" {
+		settings.MetricsOTLPURL = strings.TrimSpace(cfg.Metrics.OTLPEndpoint)
+	}
+	if cfg.Metrics.PushInterval != "" {
+		d, err := time.ParseDuration(cfg.Metrics.PushInterval)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("config metrics.push_interval: must be a positive duration")
+		}
+		settings.MetricsInterval = d
+	}
+	if len(cfg.Policy.AllowProtocols) > 0 {
+		settings.AllowProtocols = cleanList(cfg.Policy.AllowProtocols)
+	}
+	if len(cfg.Policy.DenyProtocols) > 0 {
+		settings.DenyProtocols = cleanList(cfg.Policy.DenyProtocols)
 	}
-	if cfg.Cache.MaxStale != "" {
-		d, err := time.ParseDuration(cfg.Cache.MaxStale)
+	applyProvidersConfig(cfg.Providers, settings)
+	applyRPCConfig(cfg.RPC, settings)
+	applyDefaultProviders(cfg.DefaultProviders, settings)
+	for _, pm := range cfg.AA.Paymasters {
+		pm.Name = strings.TrimSpace(pm.Name)
+		if pm.Name == "" {
+			return fmt.Errorf("config aa.paymasters: name is required")
+		}
+		pm.Chains = cleanList(pm.Chains)
+		if len(pm.Chains) == 0 {
+			return fmt.Errorf("config aa.paymasters %s: chains is required", pm.Name)
+		}
+		if pm.URLEnv != "" {
+			pm.URL = os.Getenv(pm.URLEnv)
+		}
+		settings.Paymasters = append(settings.Paymasters, pm)
+	}
+	for i, hook := range cfg.Notify.Webhooks {
+		if hook.URLEnv != "" {
+			hook.URL = os.Getenv(hook.URLEnv)
+		}
+		if hook.SecretEnv != "" {
+			hook.Secret = os.Getenv(hook.SecretEnv)
+		}
+		hook.URL = strings.TrimSpace(hook.URL)
+		if hook.URL == "" {
+			return fmt.Errorf("config notify.webhooks[%d]: url is required", i)
+		}
+		if parsed, err := url.Parse(hook.URL); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("config notify.webhooks[%d]: url must be an http(s) URL", i)
+		}
+		hook.Events = cleanList(hook.Events)
+		for j := range hook.Events {
+			hook.Events[j] = strings.ToLower(hook.Events[j])
+		}
+		settings.Webhooks = append(settings.Webhooks, hook)
+	}
+
+	if profile == "" {
+		profile = strings.TrimSpace(cfg.Profile)
+	}
+	if profile == "" {
+		return nil
+	}
+	overlay, ok := cfg.Profiles[profile]
+	if !ok {
+		return fmt.Errorf("config profile %q is not defined under profiles", profile)
+	}
+	settings.Profile = profile
+	if err := applyCacheConfig(overlay.Cache, settings); err != nil {
+		return fmt.Errorf("profile %s: %w", profile, err)
+	}
+	applyExecutionConfig(overlay.Execution, settings)
+	applyProvidersConfig(overlay.Providers, settings)
+	applyRPCConfig(overlay.RPC, settings)
+	applyDefaultProviders(overlay.DefaultProviders, settings)
+	return nil
+}
+
+// selectedProfile returns the profile requested by --profile or DEFI_PROFILE.
+// An empty result falls back to the profile key in the config file.
+func selectedProfile(flags GlobalFlags) string {
+	if v := strings.TrimSpace(flags.Profile); v != "" {
+		return v
+	}
+	return strings.TrimSpace(os.Getenv("DEFI_PROFILE"))
+}
+
+func applyCacheConfig(cfg cacheConfig, settings *Settings) error {
+	if cfg.Enabled != nil {
+		settings.CacheEnabled = *cfg.Enabled
+	}
+	if cfg.MaxStale != "" {
+		d, err := time.ParseDuration(cfg.MaxStale)
 		if err != nil {
 			return fmt.Errorf("config cache.max_stale: %w", err)
 		}
 		settings.MaxStale = d
 	}
-	if cfg.Cache.Path != "" {
-		settings.CachePath = cfg.Cache.Path
+	if cfg.Path != "" {
+		settings.CachePath = cfg.Path
+	}
+	if cfg.LockPath != "" {
+		settings.CacheLockPath = cfg.LockPath
 	}
-	if cfg.Cache.LockPath != "" {
-		settings.CacheLockPath = cfg.Cache.LockPath
+	if cfg.StaleWhileRevalidate != nil {
+		settings.StaleRevalidate = *cfg.StaleWhileRevalidate
 	}
-	if cfg.Execution.ActionsPath != "" {
-		settings.ActionStorePath = cfg.Execution.ActionsPath
+	return nil
+}
+
+func applyExecutionConfig(cfg executionConfig, settings *Settings) {
+	if cfg.ActionsPath != "" {
+		settings.ActionStorePath = cfg.ActionsPath
+	}
+	if cfg.ActionsLockPath != "" {
+		settings.ActionLockPath = cfg.ActionsLockPath
+	}
+	if cfg.PolicyPath != "" {
+		settings.PolicyPath = cfg.PolicyPath
+	}
+}
+
+func applyProvidersConfig(cfg providersConfig, settings *Settings) {
+	if cfg.Uniswap.APIKey != "" {
+		settings.UniswapAPIKey = cfg.Uniswap.APIKey
 	}
-	if cfg.Execution.ActionsLockPath != "" {
-		settings.ActionLockPath = cfg.Execution.ActionsLockPath
+	if cfg.DefiLlama.APIKey != "" {
+		settings.DefiLlamaAPIKey = cfg.DefiLlama.APIKey
 	}
-	if cfg.Providers.Uniswap.APIKey != "" {
-		settings.UniswapAPIKey = cfg.Providers.Uniswap.APIKey
+	if cfg.DefiLlama.APIKeyEnv != "" {
+		settings.DefiLlamaAPIKey = os.Getenv(cfg.DefiLlama.APIKeyEnv)
 	}
-	if cfg.Providers.DefiLlama.APIKey != "" {
-		settings.DefiLlamaAPIKey = cfg.Providers.DefiLlama.APIKey
+	if cfg.Uniswap.APIKeyEnv != "" {
+		settings.UniswapAPIKey = os.Getenv(cfg.Uniswap.APIKeyEnv)
 	}
-	if cfg.Providers.DefiLlama.APIKeyEnv != "" {
-		settings.DefiLlamaAPIKey = os.Getenv(cfg.Providers.DefiLlama.APIKeyEnv)
+	if cfg.OneInch.APIKey != "" {
+		settings.OneInchAPIKey = cfg.OneInch.APIKey
 	}
-	if cfg.Providers.Uniswap.APIKeyEnv != "" {
-		settings.UniswapAPIKey = os.Getenv(cfg.Providers.Uniswap.APIKeyEnv)
+	if cfg.OneInch.APIKeyEnv != "" {
+		settings.OneInchAPIKey = os.Getenv(cfg.OneInch.APIKeyEnv)
 	}
-	if cfg.Providers.OneInch.APIKey != "" {
-		settings.OneInchAPIKey = cfg.Providers.OneInch.APIKey
+	if cfg.Jupiter.APIKey != "" {
+		settings.JupiterAPIKey = cfg.Jupiter.APIKey
 	}
-	if cfg.Providers.OneInch.APIKeyEnv != "" {
-		settings.OneInchAPIKey = os.Getenv(cfg.Providers.OneInch.APIKeyEnv)
+	if cfg.Jupiter.APIKeyEnv != "" {
+		settings.JupiterAPIKey = os.Getenv(cfg.Jupiter.APIKeyEnv)
 	}
-	if cfg.Providers.Jupiter.APIKey != "" {
-		settings.JupiterAPIKey = cfg.Providers.Jupiter.APIKey
+	if cfg.Bungee.APIKey != "" {
+		settings.BungeeAPIKey = cfg.Bungee.APIKey
 	}
-	if cfg.Providers.Jupiter.APIKeyEnv != "" {
-		settings.JupiterAPIKey = os.Getenv(cfg.Providers.Jupiter.APIKeyEnv)
+	if cfg.Bungee.APIKeyEnv != "" {
+		settings.BungeeAPIKey = os.Getenv(cfg.Bungee.APIKeyEnv)
 	}
-	if cfg.Providers.Bungee.APIKey != "" {
-		settings.BungeeAPIKey = cfg.Providers.Bungee.APIKey
+	if cfg.Bungee.Affiliate != "" {
+		settings.BungeeAffiliate = cfg.Bungee.Affiliate
 	}
-	if cfg.Providers.Bungee.APIKeyEnv != "" {
-		settings.BungeeAPIKey = os.Getenv(cfg.Providers.Bungee.APIKeyEnv)
+	if cfg.Bungee.AffiliateEnv != "" {
+		settings.BungeeAffiliate = os.Getenv(cfg.Bungee.AffiliateEnv)
 	}
-	if cfg.Providers.Bungee.Affiliate != "" {
-		settings.BungeeAffiliate = cfg.Providers.Bungee.Affiliate
+	if cfg.Etherscan.APIKey != "" {
+		settings.EtherscanAPIKey = cfg.Etherscan.APIKey
 	}
-	if cfg.Providers.Bungee.AffiliateEnv != "" {
-		settings.BungeeAffiliate = os.Getenv(cfg.Providers.Bungee.AffiliateEnv)
+	if cfg.Etherscan.APIKeyEnv != "" {
+		settings.EtherscanAPIKey = os.Getenv(cfg.Etherscan.APIKeyEnv)
 	}
+	if cfg.Etherscan.BaseURL != "" {
+		settings.EtherscanBaseURL = strings.TrimSpace(cfg.Etherscan.BaseURL)
+	}
+	if cfg.TheGraph.APIKey != "" {
+		settings.TheGraphAPIKey = cfg.TheGraph.APIKey
+	}
+	if cfg.TheGraph.APIKeyEnv != "" {
+		settings.TheGraphAPIKey = os.Getenv(cfg.TheGraph.APIKeyEnv)
+	}
+}
 
-	return nil
+// applyRPCConfig merges chain -> RPC endpoint lists. Keys are chain
+// identifiers as written (slug, chain ID, or CAIP-2); the CLI parses them.
+// A later list for the same key replaces the earlier one.
+func applyRPCConfig(rpc map[string]rpcURLList, settings *Settings) {
+	for chain, urls := range rpc {
+		chain = strings.TrimSpace(chain)
+		urls := cleanList(urls)
+		if chain == "" || len(urls) == 0 {
+			continue
+		}
+		if settings.RPCURLs == nil {
+			settings.RPCURLs = map[string][]string{}
+		}
+		settings.RPCURLs[chain] = urls
+	}
+}
+
+// applyDefaultProviders merges command -> provider defaults. Keys are a
+// top-level command ("swap") or a full command path ("swap quote").
+func applyDefaultProviders(defaults map[string]string, settings *Settings) {
+	for command, provider := range defaults {
+		command = strings.ToLower(strings.Join(strings.Fields(command), " "))
+		provider = strings.TrimSpace(provider)
+		if command == "" || provider == "" {
+			continue
+		}
+		if settings.DefaultProviders == nil {
+			settings.DefaultProviders = map[string]string{}
+		}
+		settings.DefaultProviders[command] = provider
+	}
+}
+
+func readFileConfig(path string) (fileConfig, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fileConfig{}, err
+		}
+		return fileConfig{}, fmt.Errorf("read config: %w", err)
+	}
+	var cfg fileConfig
+	if err := yaml.Unmarshal(buf, &cfg); err != nil {
+		return fileConfig{}, fmt.Errorf("parse config yaml: %w", err)
+	}
+	return cfg, nil
 }
 
 func applyEnv(settings *Settings) {
 	if v := os.Getenv("DEFI_OUTPUT"); v != "" {
 		settings.OutputMode = strings.ToLower(v)
 	}
+	if v := os.Getenv("DEFI_LANG"); v != "" {
+		settings.Lang = i18n.Normalize(v)
+	}
+	if v := os.Getenv("DEFI_COMPACT"); v != "" {
+		if b, err := strconv.ParseBool(v); err == nil {
+			settings.Compact = b
+		}
+	}
 	if v := os.Getenv("DEFI_STRICT"); v != "" {
 		if b, err := strconv.ParseBool(v); err == nil {
 			settings.Strict = b
@@ -287,6 +783,31 @@ func applyEnv(settings *Settings) {
 			settings.Retries = n
 		}
 	}
+	if v := os.Getenv("DEFI_CONCURRENCY"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			settings.Concurrency = n
+		}
+	}
+	if v := os.Getenv("DEFI_MAX_CONNS_PER_HOST"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			settings.MaxConnsPerHost = n
+		}
+	}
+	if v := os.Getenv("DEFI_BREAKER_FAILURES"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			settings.BreakerFailures = n
+		}
+	}
+	if v := os.Getenv("DEFI_BREAKER_COOLDOWN"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			settings.BreakerCooldown = d
+		}
+	}
+	if v := os.Getenv("DEFI_PROVIDER_TIMEOUT"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			settings.ProviderTimeout = d
+		}
+	}
 	if v := os.Getenv("DEFI_MAX_STALE"); v != "" {
 		if d, err := time.ParseDuration(v); err == nil {
 			settings.MaxStale = d
@@ -297,6 +818,11 @@ func applyEnv(settings *Settings) {
 			settings.NoStale = b
 		}
 	}
+	if v := os.Getenv("DEFI_STALE_WHILE_REVALIDATE"); v != "" {
+		if b, err := strconv.ParseBool(v); err == nil {
+			settings.StaleRevalidate = b
+		}
+	}
 	if v := os.Getenv("DEFI_NO_CACHE"); v != "" {
 		if b, err := strconv.ParseBool(v); err == nil {
 			settings.CacheEnabled = !b
@@ -314,6 +840,92 @@ func applyEnv(settings *Settings) {
 	if v := os.Getenv("DEFI_ACTIONS_LOCK_PATH"); v != "" {
 		settings.ActionLockPath = v
 	}
+	if v := os.Getenv("DEFI_POLICY_PATH"); v != "" {
+		settings.PolicyPath = v
+	}
+	if v := os.Getenv("DEFI_QUOTES_PATH"); v != "" {
+		settings.QuotesPath = v
+	}
+	if v := os.Getenv("DEFI_QUOTES_LOCK_PATH"); v != "" {
+		settings.QuotesLockPath = v
+	}
+	if v := os.Getenv("DEFI_RESOLVE_POLICY"); v != "" {
+		settings.ResolvePolicy = strings.ToLower(strings.TrimSpace(v))
+	}
+	if v := os.Getenv("DEFI_PREFER_TOKEN_LIST"); v != "" {
+		settings.PreferTokenList = strings.TrimSpace(v)
+	}
+	if v := os.Getenv("DEFI_TOKENS_PATH"); v != "" {
+		settings.TokensPath = v
+	}
+	if v := os.Getenv("DEFI_LABELS_PATH"); v != "" {
+		settings.LabelsPath = v
+	}
+	if v := os.Getenv("DEFI_STRICT_ASSET"); v != "" {
+		if b, err := strconv.ParseBool(v); err == nil {
+			settings.StrictAsset = b
+		}
+	}
+	if v := os.Getenv("DEFI_RESOLVE_UNKNOWN"); v != "" {
+		if b, err := strconv.ParseBool(v); err == nil {
+			settings.ResolveUnknown = b
+		}
+	}
+	if v := os.Getenv("DEFI_TOKEN_LIST_URL"); v != "" {
+		settings.TokenListURL = strings.TrimSpace(v)
+	}
+	if v := os.Getenv("DEFI_CHAINS_PATH"); v != "" {
+		settings.ChainsPath = v
+	}
+	if v := os.Getenv("DEFI_CHAIN_LIST_URL"); v != "" {
+		settings.ChainListURL = strings.TrimSpace(v)
+	}
+	if v := os.Getenv("DEFI_LOG_LEVEL"); v != "" {
+		settings.LogLevel = v
+	}
+	if v := os.Getenv("DEFI_LOG_FILE"); v != "" {
+		settings.LogFile = v
+	}
+	if v := os.Getenv("DEFI_TRACE"); v != "" {
+		if b, err := strconv.ParseBool(v); err == nil {
+			settings.Trace = b
+		}
+	}
+	applyTraceEnv(settings)
+	if v := os.Getenv("DEFI_OFFLINE"); v != "" {
+		if b, err := strconv.ParseBool(v); err == nil {
+			settings.Offline = b
+		}
+	}
+	if v := os.Getenv("DEFI_SEED"); v != "" {
+		if n, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64); err == nil {
+			settings.Seed = &n
+		}
+	}
+	if v := os.Getenv("DEFI_NOTIFY_URL"); v != "" {
+		settings.NotifyURL = strings.TrimSpace(v)
+	}
+	if v := os.Getenv("DEFI_NOTIFY_SECRET"); v != "" {
+		settings.NotifySecret = v
+	}
+	if v := os.Getenv("DEFI_RECORD_FIXTURES"); v != "" {
+		settings.RecordFixtures = strings.TrimSpace(v)
+	}
+	if v := os.Getenv("DEFI_REPLAY_FIXTURES"); v != "" {
+		settings.ReplayFixtures = strings.TrimSpace(v)
+	}
+	applyMetricsEnv(settings)
+	if v := os.Getenv("DEFI_CHAINS_AUTO_REFRESH"); v != "" {
+		if b, err := strconv.ParseBool(v); err == nil {
+			settings.ChainsRefresh = b
+		}
+	}
+	if v := os.Getenv("DEFI_ALLOW_PROTOCOLS"); v != "" {
+		settings.AllowProtocols = cleanList(strings.Split(v, ","))
+	}
+	if v := os.Getenv("DEFI_DENY_PROTOCOLS"); v != "" {
+		settings.DenyProtocols = cleanList(strings.Split(v, ","))
+	}
 	if v := os.Getenv("DEFI_UNISWAP_API_KEY"); v != "" {
 		settings.UniswapAPIKey = v
 	}
@@ -332,6 +944,15 @@ func applyEnv(settings *Settings) {
 	if v := os.Getenv("DEFI_BUNGEE_AFFILIATE"); v != "" {
 		settings.BungeeAffiliate = v
 	}
+	if v := os.Getenv("DEFI_ETHERSCAN_API_KEY"); v != "" {
+		settings.EtherscanAPIKey = v
+	}
+	if v := os.Getenv("DEFI_ETHERSCAN_BASE_URL"); v != "" {
+		settings.EtherscanBaseURL = strings.TrimSpace(v)
+	}
+	if v := os.Getenv("DEFI_THEGRAPH_API_KEY"); v != "" {
+		settings.TheGraphAPIKey = v
+	}
 }
 
 func applyFlags(flags GlobalFlags, settings *Settings) error {
@@ -344,6 +965,9 @@ func applyFlags(flags GlobalFlags, settings *Settings) error {
 	if flags.Plain {
 		settings.OutputMode = "plain"
 	}
+	if strings.TrimSpace(flags.Lang) != "" {
+		settings.Lang = i18n.Normalize(flags.Lang)
+	}
 	if strings.TrimSpace(flags.Select) != "" {
 		parts := strings.Split(flags.Select, ",")
 		fields := make([]string, 0, len(parts))
@@ -355,7 +979,26 @@ func applyFlags(flags GlobalFlags, settings *Settings) error {
 		}
 		settings.SelectFields = fields
 	}
+	if query := strings.TrimSpace(flags.Query); query != "" {
+		if len(settings.SelectFields) > 0 {
+			return fmt.Errorf("--select and --query cannot be combined; use a --query projection such as [].{a: a, b: b}")
+		}
+		if _, err := jmespath.Compile(query); err != nil {
+			return fmt.Errorf("invalid --query expression: %w", err)
+		}
+		settings.Query = query
+	}
 	settings.ResultsOnly = flags.ResultsOnly
+	if flags.Compact {
+		settings.Compact = true
+	}
+	if flags.CompactGzip < 0 {
+		return fmt.Errorf("--compact-gzip must be >= 0")
+	}
+	if flags.CompactGzip > 0 {
+		settings.Compact = true
+		settings.CompactGzipBytes = flags.CompactGzip
+	}
 
 	if strings.TrimSpace(flags.EnableCommands) != "" {
 		parts := strings.Split(flags.EnableCommands, ",")
@@ -382,6 +1025,16 @@ func applyFlags(flags GlobalFlags, settings *Settings) error {
 	if flags.Retries >= 0 {
 		settings.Retries = flags.Retries
 	}
+	if flags.Concurrency > 0 {
+		settings.Concurrency = flags.Concurrency
+	}
+	if flags.ProviderTimeout != "" {
+		d, err := time.ParseDuration(flags.ProviderTimeout)
+		if err != nil {
+			return fmt.Errorf("parse --provider-timeout: %w", err)
+		}
+		settings.ProviderTimeout = d
+	}
 	if flags.MaxStale != "" {
 		d, err := time.ParseDuration(flags.MaxStale)
 		if err != nil {
@@ -395,10 +1048,197 @@ func applyFlags(flags GlobalFlags, settings *Settings) error {
 	if flags.NoCache {
 		settings.CacheEnabled = false
 	}
+	if flags.CacheTTL != "" {
+		d, err := time.ParseDuration(flags.CacheTTL)
+		if err != nil {
+			return fmt.Errorf("parse --cache-ttl: %w", err)
+		}
+		if d <= 0 {
+			return fmt.Errorf("--cache-ttl must be positive")
+		}
+		settings.CacheTTL = d
+	}
+	if flags.ForceFresh {
+		settings.ForceFresh = true
+	}
+	if flags.Offline {
+		settings.Offline = true
+	}
+	if v := strings.TrimSpace(flags.NotifyURL); v != "" {
+		settings.NotifyURL = v
+	}
+	if strings.TrimSpace(flags.ResolvePolicy) != "" {
+		settings.ResolvePolicy = strings.ToLower(strings.TrimSpace(flags.ResolvePolicy))
+	}
+	if strings.TrimSpace(flags.PreferTokenList) != "" {
+		settings.PreferTokenList = strings.TrimSpace(flags.PreferTokenList)
+	}
+	if flags.StrictAsset {
+		settings.StrictAsset = true
+	}
+	if flags.ResolveUnknown {
+		settings.ResolveUnknown = true
+	}
+	if flags.Verbose {
+		settings.LogLevel = logx.LevelDebug
+	}
+	if strings.TrimSpace(flags.LogLevel) != "" {
+		settings.LogLevel = flags.LogLevel
+	}
+	if strings.TrimSpace(flags.LogFile) != "" {
+		settings.LogFile = flags.LogFile
+	}
+	if err := normalizeLogSettings(settings); err != nil {
+		return err
+	}
+	if flags.Trace {
+		settings.Trace = true
+	}
+	if v := strings.TrimSpace(flags.Seed); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return fmt.Errorf("parse --seed: %w", err)
+		}
+		settings.Seed = &n
+	}
+	if v := strings.TrimSpace(flags.RecordFixtures); v != "" {
+		settings.RecordFixtures = v
+	}
+	if v := strings.TrimSpace(flags.ReplayFixtures); v != "" {
+		settings.ReplayFixtures = v
+	}
+	if err := applyFixtureSettings(settings); err != nil {
+		return err
+	}
+	if settings.Offline {
+		if settings.RecordFixtures != "" {
+			return fmt.Errorf("--offline and --record-fixtures are mutually exclusive")
+		}
+		if settings.ForceFresh {
+			return fmt.Errorf("--offline and --force-fresh are mutually exclusive")
+		}
+		// Offline runs never wait on the network: nothing is retried or
+		// refreshed in the background.
+		settings.Retries = 0
+		settings.StaleRevalidate = false
+		settings.ChainsRefresh = false
+	}
 
 	if settings.OutputMode != "json" && settings.OutputMode != "plain" {
 		return fmt.Errorf("output must be json or plain")
 	}
+	if !i18n.IsSupported(settings.Lang) {
+		return fmt.Errorf("lang must be one of %s", strings.Join(i18n.Supported(), ", "))
+	}
+
+	return nil
+}
+
+// applyFixtureSettings adjusts settings for fixture recording and replay.
+// Both bypass the cache so every provider request is recorded or served from
+// fixtures; replay also drops retries, since a missing fixture stays missing,
+// and defaults the seed to 0 so IDs are reproducible.
+func applyFixtureSettings(settings *Settings) error {
+	if settings.RecordFixtures == "" && settings.ReplayFixtures == "" {
+		return nil
+	}
+	if settings.RecordFixtures != "" && settings.ReplayFixtures != "" {
+		return fmt.Errorf("--record-fixtures and --replay-fixtures are mutually exclusive")
+	}
+	settings.CacheEnabled = false
+	if settings.ReplayFixtures != "" {
+		settings.Retries = 0
+		if settings.Seed == nil {
+			seed := int64(0)
+			settings.Seed = &seed
+		}
+	}
+	return nil
+}
+
+// applyTraceEnv reads the W3C and OpenTelemetry variables used by --trace:
+// TRACEPARENT joins the caller's trace, and the standard OTLP exporter
+// variables select the collector that receives it.
+func applyTraceEnv(settings *Settings) {
+	settings.TraceParent = strings.TrimSpace(os.Getenv("TRACEPARENT"))
+	if v := strings.TrimSpace(os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT")); v != "" {
+		settings.OTLPEndpoint = v
+	} else if v := strings.TrimSpace(os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT")); v != "" {
+		settings.OTLPEndpoint = strings.TrimRight(v, "/") + "/v1/traces"
+	}
+	headers := parseOTLPHeaders(os.Getenv("OTEL_EXPORTER_OTLP_HEADERS"))
+	for k, v := range parseOTLPHeaders(os.Getenv("OTEL_EXPORTER_OTLP_TRACES_HEADERS")) {
+		headers[k] = v
+	}
+	settings.OTLPHeaders = headers
+}
+
+// applyMetricsEnv reads the metrics listener and OTLP push settings used by
+// long-lived modes. DEFI_METRICS_OTLP_ENDPOINT wins over the standard OTLP
+// metrics exporter variable.
+func applyMetricsEnv(settings *Settings) {
+	if v := strings.TrimSpace(os.Getenv("DEFI_METRICS_LISTEN")); v != "" {
+		settings.MetricsListen = v
+	}
+	if v := strings.TrimSpace(os.Getenv("DEFI_METRICS_OTLP_ENDPOINT")); v != "" {
+		settings.MetricsOTLPURL = v
+	} else if v := strings.TrimSpace(os.Getenv("OTEL_EXPORTER_OTLP_METRICS_ENDPOINT")); v != "" {
+		settings.MetricsOTLPURL = v
+	}
+	if v := os.Getenv("DEFI_METRICS_PUSH_INTERVAL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			settings.MetricsInterval = d
+		}
+	}
+	headers := parseOTLPHeaders(os.Getenv("OTEL_EXPORTER_OTLP_HEADERS"))
+	for k, v := range parseOTLPHeaders(os.Getenv("OTEL_EXPORTER_OTLP_METRICS_HEADERS")) {
+		headers[k] = v
+	}
+	settings.MetricsHeaders = headers
+}
+
+// parseOTLPHeaders parses the OTLP exporter header format: comma-separated
+// key=value pairs with URL-encoded values.
+func parseOTLPHeaders(raw string) map[string]string {
+	headers := map[string]string{}
+	for _, pair := range strings.Split(raw, ",") {
+		key, value, ok := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			continue
+		}
+		if decoded, err := url.QueryUnescape(strings.TrimSpace(value)); err == nil {
+			value = decoded
+		}
+		headers[key] = strings.TrimSpace(value)
+	}
+	return headers
+}
 
+// normalizeLogSettings validates the log level and expands the log file path.
+// A log file without a level logs at info.
+func normalizeLogSettings(settings *Settings) error {
+	settings.LogLevel = strings.ToLower(strings.TrimSpace(settings.LogLevel))
+	if _, _, err := logx.ParseLevel(settings.LogLevel); err != nil {
+		return err
+	}
+	path, err := fsutil.NormalizePath(settings.LogFile)
+	if err != nil {
+		return fmt.Errorf("log file: %w", err)
+	}
+	settings.LogFile = path
+	if settings.LogFile != "" && settings.LogLevel == "" {
+		settings.LogLevel = logx.LevelInfo
+	}
 	return nil
 }
+
+func cleanList(values []string) []string {
+	out := make([]string, 0, len(values))
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
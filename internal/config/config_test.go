@@ -3,7 +3,9 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestLoadPrecedenceFlagsOverEnvOverFile(t *testing.T) {
@@ -34,6 +36,84 @@ func TestLoadMutuallyExclusiveOutputFlags(t *testing.T) {
 	}
 }
 
+func TestLoadQueryValidation(t *testing.T) {
+	settings, err := Load(GlobalFlags{Query: " [?apy > `5`].provider "})
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if settings.Query != "[?apy > `5`].provider" {
+		t.Fatalf("unexpected query %q", settings.Query)
+	}
+	if _, err := Load(GlobalFlags{Query: "[?apy >"}); err == nil {
+		t.Fatal("expected error for an invalid --query expression")
+	}
+	if _, err := Load(GlobalFlags{Query: "[].provider", Select: "provider"}); err == nil {
+		t.Fatal("expected error with --select and --query")
+	}
+}
+
+func TestLoadLogSettings(t *testing.T) {
+	settings, err := Load(GlobalFlags{})
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if settings.LogLevel != "" || settings.LogFile != "" {
+		t.Fatalf("expected logging off by default, got level=%q file=%q", settings.LogLevel, settings.LogFile)
+	}
+	settings, err = Load(GlobalFlags{Verbose: true})
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if settings.LogLevel != "debug" {
+		t.Fatalf("expected --verbose to log at debug, got %q", settings.LogLevel)
+	}
+	settings, err = Load(GlobalFlags{Verbose: true, LogLevel: "WARN"})
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if settings.LogLevel != "warn" {
+		t.Fatalf("expected --log-level to win over --verbose, got %q", settings.LogLevel)
+	}
+	t.Setenv("DEFI_LOG_FILE", "/tmp/defi.log")
+	settings, err = Load(GlobalFlags{})
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if settings.LogFile != "/tmp/defi.log" || settings.LogLevel != "info" {
+		t.Fatalf("expected log file to default to info, got level=%q file=%q", settings.LogLevel, settings.LogFile)
+	}
+	if _, err := Load(GlobalFlags{LogLevel: "trace"}); err == nil {
+		t.Fatal("expected error for an unknown --log-level")
+	}
+}
+
+func TestLoadTraceSettings(t *testing.T) {
+	t.Setenv("TRACEPARENT", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "http://localhost:4318/")
+	t.Setenv("OTEL_EXPORTER_OTLP_HEADERS", "authorization=Bearer%20abc, x-team = agents")
+	settings, err := Load(GlobalFlags{Trace: true})
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if !settings.Trace || settings.TraceParent != "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01" {
+		t.Fatalf("unexpected trace settings: %+v", settings)
+	}
+	if settings.OTLPEndpoint != "http://localhost:4318/v1/traces" {
+		t.Fatalf("unexpected OTLP endpoint %q", settings.OTLPEndpoint)
+	}
+	if settings.OTLPHeaders["authorization"] != "Bearer abc" || settings.OTLPHeaders["x-team"] != "agents" {
+		t.Fatalf("unexpected OTLP headers %#v", settings.OTLPHeaders)
+	}
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "https://collector.example.com/traces")
+	settings, err = Load(GlobalFlags{})
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if settings.Trace || settings.OTLPEndpoint != "https://collector.example.com/traces" {
+		t.Fatalf("expected trace off and the traces endpoint to win, got %+v", settings)
+	}
+}
+
 func TestLoadAllowsZeroMaxStale(t *testing.T) {
 	settings, err := Load(GlobalFlags{MaxStale: "0s"})
 	if err != nil {
@@ -44,6 +124,22 @@ func TestLoadAllowsZeroMaxStale(t *testing.T) {
 	}
 }
 
+func TestLoadCacheTTLAndForceFresh(t *testing.T) {
+	settings, err := Load(GlobalFlags{CacheTTL: "90s", ForceFresh: true})
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if settings.CacheTTL != 90*time.Second || !settings.ForceFresh {
+		t.Fatalf("expected cache ttl 90s with force fresh, got %s %v", settings.CacheTTL, settings.ForceFresh)
+	}
+	if _, err := Load(GlobalFlags{CacheTTL: "0s"}); err == nil {
+		t.Fatal("expected a zero --cache-ttl to fail")
+	}
+	if _, err := Load(GlobalFlags{ForceFresh: true, Offline: true}); err == nil {
+		t.Fatal("expected --force-fresh with --offline to fail")
+	}
+}
+
 func TestLoadDefiLlamaAPIKeyFromEnv(t *testing.T) {
 	t.Setenv("DEFI_DEFILLAMA_API_KEY", "key-123")
 	settings, err := Load(GlobalFlags{})
@@ -81,6 +177,17 @@ func TestLoadJupiterAPIKeyFromEnv(t *testing.T) {
 	}
 }
 
+func TestLoadTheGraphAPIKeyFromEnv(t *testing.T) {
+	t.Setenv("DEFI_THEGRAPH_API_KEY", "graph-key")
+	settings, err := Load(GlobalFlags{})
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if settings.TheGraphAPIKey != "graph-key" {
+		t.Fatalf("expected The Graph API key from env, got %q", settings.TheGraphAPIKey)
+	}
+}
+
 func TestLoadBungeeDedicatedSettingsFromEnv(t *testing.T) {
 	t.Setenv("DEFI_BUNGEE_API_KEY", "bungee-key")
 	t.Setenv("DEFI_BUNGEE_AFFILIATE", "affiliate-id")
@@ -119,3 +226,432 @@ providers:
 		t.Fatalf("expected Bungee affiliate from file, got %q", settings.BungeeAffiliate)
 	}
 }
+
+func TestLoadPaymastersFromFile(t *testing.T) {
+	tmp := t.TempDir()
+	configPath := filepath.Join(tmp, "config.yaml")
+	if err := os.WriteFile(configPath, []byte(`
+aa:
+  paymasters:
+    - name: team-sponsor
+      chains: [base, " "]
+      mode: sponsored
+      url_env: TEAM_PAYMASTER_URL
+`), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	t.Setenv("TEAM_PAYMASTER_URL", "https://paymaster.example/rpc")
+
+	settings, err := Load(GlobalFlags{ConfigPath: configPath})
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(settings.Paymasters) != 1 {
+		t.Fatalf("expected one paymaster, got %+v", settings.Paymasters)
+	}
+	pm := settings.Paymasters[0]
+	if pm.Name != "team-sponsor" || len(pm.Chains) != 1 || pm.URL != "https://paymaster.example/rpc" {
+		t.Fatalf("unexpected paymaster config: %+v", pm)
+	}
+}
+
+func TestLoadPaymastersRequiresChains(t *testing.T) {
+	tmp := t.TempDir()
+	configPath := filepath.Join(tmp, "config.yaml")
+	if err := os.WriteFile(configPath, []byte("aa:\n  paymasters:\n    - name: missing-chains\n"), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	if _, err := Load(GlobalFlags{ConfigPath: configPath}); err == nil {
+		t.Fatal("expected error for paymaster without chains")
+	}
+}
+
+func TestLoadNotifyWebhooks(t *testing.T) {
+	tmp := t.TempDir()
+	configPath := filepath.Join(tmp, "config.yaml")
+	if err := os.WriteFile(configPath, []byte(`
+notify:
+  webhooks:
+    - url: https://hooks.example/defi
+      secret_env: TEAM_WEBHOOK_SECRET
+      events: [Completed, " failed "]
+`), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	t.Setenv("TEAM_WEBHOOK_SECRET", "s3cret")
+	t.Setenv("DEFI_NOTIFY_URL", "https://env.example/hook")
+
+	settings, err := Load(GlobalFlags{ConfigPath: configPath, NotifyURL: "https://flag.example/hook"})
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(settings.Webhooks) != 1 {
+		t.Fatalf("expected one webhook, got %+v", settings.Webhooks)
+	}
+	hook := settings.Webhooks[0]
+	if hook.Secret != "s3cret" || strings.Join(hook.Events, ",") != "completed,failed" {
+		t.Fatalf("unexpected webhook config: %+v", hook)
+	}
+	if settings.NotifyURL != "https://flag.example/hook" {
+		t.Fatalf("expected --notify-url to override DEFI_NOTIFY_URL, got %q", settings.NotifyURL)
+	}
+
+	if err := os.WriteFile(configPath, []byte("notify:\n  webhooks:\n    - url: ftp://hooks.example\n"), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	if _, err := Load(GlobalFlags{ConfigPath: configPath}); err == nil {
+		t.Fatal("expected error for a non-http webhook url")
+	}
+}
+
+func TestLoadLangPrecedenceAndValidation(t *testing.T) {
+	t.Setenv("DEFI_LANG", "ja_JP.UTF-8")
+	settings, err := Load(GlobalFlags{})
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if settings.Lang != "ja" {
+		t.Fatalf("expected lang from env, got %q", settings.Lang)
+	}
+	settings, err = Load(GlobalFlags{Lang: "es-MX"})
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if settings.Lang != "es" {
+		t.Fatalf("expected flag to override env, got %q", settings.Lang)
+	}
+	if _, err := Load(GlobalFlags{Lang: "fr"}); err == nil {
+		t.Fatal("expected error for unsupported lang")
+	}
+}
+
+func TestLoadQuotesPathsFromEnv(t *testing.T) {
+	t.Setenv("DEFI_QUOTES_PATH", "/tmp/defi-quotes.db")
+	t.Setenv("DEFI_QUOTES_LOCK_PATH", "/tmp/defi-quotes.lock")
+	settings, err := Load(GlobalFlags{})
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if settings.QuotesPath != "/tmp/defi-quotes.db" {
+		t.Fatalf("expected quotes path from env, got %q", settings.QuotesPath)
+	}
+	if settings.QuotesLockPath != "/tmp/defi-quotes.lock" {
+		t.Fatalf("expected quotes lock path from env, got %q", settings.QuotesLockPath)
+	}
+}
+
+func TestLoadResolvePolicyPrecedence(t *testing.T) {
+	t.Setenv("DEFI_RESOLVE_POLICY", "first")
+	t.Setenv("DEFI_PREFER_TOKEN_LIST", "native")
+	t.Setenv("DEFI_STRICT_ASSET", "true")
+	settings, err := Load(GlobalFlags{})
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if settings.ResolvePolicy != "first" || settings.PreferTokenList != "native" || !settings.StrictAsset {
+		t.Fatalf("expected env resolve settings, got %q/%q/%v", settings.ResolvePolicy, settings.PreferTokenList, settings.StrictAsset)
+	}
+	settings, err = Load(GlobalFlags{ResolvePolicy: "Error"})
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if settings.ResolvePolicy != "error" {
+		t.Fatalf("expected flag to override env, got %q", settings.ResolvePolicy)
+	}
+}
+
+func TestLoadProtocolPolicyFromFileAndEnv(t *testing.T) {
+	tmp := t.TempDir()
+	configPath := filepath.Join(tmp, "config.yaml")
+	if err := os.WriteFile(configPath, []byte(`
+policy:
+  allow_protocols: [aave, morpho]
+  deny_protocols: [curve]
+`), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	t.Setenv("DEFI_DENY_PROTOCOLS", "curve, uniswap* ,")
+
+	settings, err := Load(GlobalFlags{ConfigPath: configPath})
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(settings.AllowProtocols) != 2 || settings.AllowProtocols[1] != "morpho" {
+		t.Fatalf("expected allow list from file, got %+v", settings.AllowProtocols)
+	}
+	if len(settings.DenyProtocols) != 2 || settings.DenyProtocols[1] != "uniswap*" {
+		t.Fatalf("expected env deny list to override file, got %+v", settings.DenyProtocols)
+	}
+}
+
+const profilesConfig = `# shared settings
+profile: dev
+providers:
+  etherscan:
+    api_key: base-key
+rpc:
+  base: https://base.example
+profiles:
+  dev:
+    cache:
+      path: /tmp/defi-dev/cache.db
+  prod:
+    providers:
+      etherscan:
+        api_key: prod-key
+    rpc:
+      1: [https://mainnet.example, https://mainnet-2.example]
+    default_providers:
+      Swap: uniswap
+    execution:
+      policy_path: /tmp/prod-policy.yaml
+`
+
+func TestLoadProfileOverlayAndPrecedence(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configPath, []byte(profilesConfig), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	settings, err := Load(GlobalFlags{ConfigPath: configPath})
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if settings.Profile != "dev" || settings.CachePath != "/tmp/defi-dev/cache.db" || settings.EtherscanAPIKey != "base-key" {
+		t.Fatalf("expected file default profile dev, got %+v", settings)
+	}
+
+	t.Setenv("DEFI_PROFILE", "prod")
+	settings, err = Load(GlobalFlags{ConfigPath: configPath})
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if settings.Profile != "prod" || settings.EtherscanAPIKey != "prod-key" || settings.PolicyPath != "/tmp/prod-policy.yaml" {
+		t.Fatalf("expected env profile prod, got %+v", settings)
+	}
+	if settings.RPCURLs["base"][0] != "https://base.example" || len(settings.RPCURLs["1"]) != 2 || settings.RPCURLs["1"][1] != "https://mainnet-2.example" {
+		t.Fatalf("expected merged rpc overrides, got %+v", settings.RPCURLs)
+	}
+	if settings.DefaultProviders["swap"] != "uniswap" {
+		t.Fatalf("expected normalized default providers, got %+v", settings.DefaultProviders)
+	}
+
+	settings, err = Load(GlobalFlags{ConfigPath: configPath, Profile: "dev"})
+	if err != nil || settings.Profile != "dev" {
+		t.Fatalf("expected --profile to override env, got %q err=%v", settings.Profile, err)
+	}
+	if _, err := Load(GlobalFlags{ConfigPath: configPath, Profile: "staging"}); err == nil {
+		t.Fatal("expected unknown profile error")
+	}
+}
+
+func TestListAndUseProfiles(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configPath, []byte(profilesConfig), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	flags := GlobalFlags{ConfigPath: configPath}
+
+	list, err := ListProfiles(flags)
+	if err != nil {
+		t.Fatalf("ListProfiles failed: %v", err)
+	}
+	if list.Active != "dev" || list.ActiveSource != "file" || len(list.Profiles) != 2 {
+		t.Fatalf("unexpected profile list %+v", list)
+	}
+	prod := list.Profiles[1]
+	if prod.Name != "prod" || len(prod.APIKeys) != 1 || prod.APIKeys[0] != "etherscan" || len(prod.RPCChains) != 1 {
+		t.Fatalf("unexpected prod summary %+v", prod)
+	}
+
+	if _, err := UseProfile(flags, "prod"); err != nil {
+		t.Fatalf("UseProfile failed: %v", err)
+	}
+	buf, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(buf), "profile: prod") || !strings.Contains(string(buf), "# shared settings") {
+		t.Fatalf("expected profile key rewritten with comments kept, got:\n%s", buf)
+	}
+	if settings, err := Load(flags); err != nil || settings.EtherscanAPIKey != "prod-key" {
+		t.Fatalf("expected prod profile after use, got %q err=%v", settings.EtherscanAPIKey, err)
+	}
+	if _, err := UseProfile(flags, "staging"); err == nil {
+		t.Fatal("expected unknown profile error")
+	}
+}
+
+func TestAddRPCURLEditsActiveProfile(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configPath, []byte(profilesConfig), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	isMainnet := func(key string) bool { return key == "1" || key == "ethereum" }
+
+	target, err := AddRPCURL(GlobalFlags{ConfigPath: configPath, Profile: "prod"}, "ethereum", isMainnet, "https://mainnet-3.example", false)
+	if err != nil {
+		t.Fatalf("AddRPCURL failed: %v", err)
+	}
+	if target.Profile != "prod" || target.Key != "1" || len(target.URLs) != 3 || target.URLs[2] != "https://mainnet-3.example" {
+		t.Fatalf("unexpected target %+v", target)
+	}
+	target, err = AddRPCURL(GlobalFlags{ConfigPath: configPath, Profile: "prod"}, "ethereum", isMainnet, "https://mainnet-3.example", true)
+	if err != nil || target.URLs[0] != "https://mainnet-3.example" || len(target.URLs) != 3 {
+		t.Fatalf("expected existing URL promoted to primary, got %+v err=%v", target, err)
+	}
+
+	// Without --profile the file default (dev) is edited.
+	isBase := func(key string) bool { return key == "base" }
+	if target, err = AddRPCURL(GlobalFlags{ConfigPath: configPath}, "base", isBase, "https://base-dev.example", false); err != nil || target.Profile != "dev" {
+		t.Fatalf("expected dev profile edit, got %+v err=%v", target, err)
+	}
+
+	settings, err := Load(GlobalFlags{ConfigPath: configPath, Profile: "prod"})
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if got := settings.RPCURLs["1"]; len(got) != 3 || got[0] != "https://mainnet-3.example" {
+		t.Fatalf("unexpected prod rpc urls %v", got)
+	}
+	settings, err = Load(GlobalFlags{ConfigPath: configPath})
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if got := settings.RPCURLs["base"]; len(got) != 1 || got[0] != "https://base-dev.example" {
+		t.Fatalf("unexpected dev rpc urls %v", got)
+	}
+	if _, err := AddRPCURL(GlobalFlags{ConfigPath: configPath, Profile: "staging"}, "base", isBase, "https://x.example", false); err == nil {
+		t.Fatal("expected unknown profile error")
+	}
+}
+
+func TestLoadMetricsSettings(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	settings, err := Load(GlobalFlags{})
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if settings.MetricsListen != "" || settings.MetricsOTLPURL != "" || settings.MetricsInterval != time.Minute {
+		t.Fatalf("unexpected metrics defaults: %+v", settings)
+	}
+	t.Setenv("DEFI_METRICS_LISTEN", "127.0.0.1:9464")
+	t.Setenv("OTEL_EXPORTER_OTLP_METRICS_ENDPOINT", "http://localhost:4318/v1/metrics")
+	t.Setenv("OTEL_EXPORTER_OTLP_METRICS_HEADERS", "x-team=agents")
+	t.Setenv("DEFI_METRICS_PUSH_INTERVAL", "15s")
+	settings, err = Load(GlobalFlags{})
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if settings.MetricsListen != "127.0.0.1:9464" || settings.MetricsOTLPURL != "http://localhost:4318/v1/metrics" || settings.MetricsInterval != 15*time.Second {
+		t.Fatalf("unexpected metrics settings: %+v", settings)
+	}
+	if settings.MetricsHeaders["x-team"] != "agents" {
+		t.Fatalf("unexpected metrics headers %#v", settings.MetricsHeaders)
+	}
+}
+
+func TestLoadConcurrencySettings(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	settings, err := Load(GlobalFlags{Retries: -1})
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if settings.Concurrency != 4 || settings.ProviderTimeout != 0 {
+		t.Fatalf("unexpected defaults: concurrency=%d provider_timeout=%s", settings.Concurrency, settings.ProviderTimeout)
+	}
+	t.Setenv("DEFI_CONCURRENCY", "0")
+	t.Setenv("DEFI_PROVIDER_TIMEOUT", "3s")
+	settings, err = Load(GlobalFlags{Retries: -1})
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if settings.Concurrency != 1 || settings.ProviderTimeout != 3*time.Second {
+		t.Fatalf("expected env values with concurrency clamped to 1, got concurrency=%d provider_timeout=%s", settings.Concurrency, settings.ProviderTimeout)
+	}
+	settings, err = Load(GlobalFlags{Retries: -1, Concurrency: 8, ProviderTimeout: "1500ms"})
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if settings.Concurrency != 8 || settings.ProviderTimeout != 1500*time.Millisecond {
+		t.Fatalf("expected flags to win, got concurrency=%d provider_timeout=%s", settings.Concurrency, settings.ProviderTimeout)
+	}
+	if _, err := Load(GlobalFlags{Retries: -1, ProviderTimeout: "soon"}); err == nil {
+		t.Fatal("expected invalid --provider-timeout to fail")
+	}
+}
+
+func TestLoadStaleWhileRevalidate(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", tmp)
+	configPath := filepath.Join(tmp, "config.yaml")
+	if err := os.WriteFile(configPath, []byte("cache:\n  stale_while_revalidate: true\n"), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	settings, err := Load(GlobalFlags{ConfigPath: configPath})
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if !settings.StaleRevalidate {
+		t.Fatal("expected stale_while_revalidate from config")
+	}
+	t.Setenv("DEFI_STALE_WHILE_REVALIDATE", "false")
+	settings, err = Load(GlobalFlags{ConfigPath: configPath})
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if settings.StaleRevalidate {
+		t.Fatal("expected env to disable stale_while_revalidate")
+	}
+}
+
+func TestLoadHTTPProviderPolicies(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", tmp)
+	configPath := filepath.Join(tmp, "config.yaml")
+	body := "http:\n  max_conns_per_host: 8\n  providers:\n    DefiLlama:\n      rate_limit: 2.5\n      burst: 5\n      retries: 4\n"
+	if err := os.WriteFile(configPath, []byte(body), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	settings, err := Load(GlobalFlags{ConfigPath: configPath})
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	policy := settings.ProviderHTTP["defillama"]
+	if settings.MaxConnsPerHost != 8 || policy.RateLimit != 2.5 || policy.Burst != 5 || policy.Retries == nil || *policy.Retries != 4 {
+		t.Fatalf("unexpected http settings: max=%d policy=%+v", settings.MaxConnsPerHost, policy)
+	}
+
+	if err := os.WriteFile(configPath, []byte("http:\n  providers:\n    aave:\n      rate_limit: -1\n"), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	if _, err := Load(GlobalFlags{ConfigPath: configPath}); err == nil || !strings.Contains(err.Error(), "http.providers.aave") {
+		t.Fatalf("expected negative rate limit to fail, got %v", err)
+	}
+}
+
+func TestLoadCircuitBreakerSettings(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", tmp)
+	configPath := filepath.Join(tmp, "config.yaml")
+	if err := os.WriteFile(configPath, []byte("http:\n  circuit_breaker:\n    failures: 3\n    cooldown: 2m\n"), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	settings, err := Load(GlobalFlags{ConfigPath: configPath})
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if settings.BreakerFailures != 3 || settings.BreakerCooldown != 2*time.Minute {
+		t.Fatalf("unexpected breaker settings: failures=%d cooldown=%s", settings.BreakerFailures, settings.BreakerCooldown)
+	}
+
+	t.Setenv("DEFI_BREAKER_FAILURES", "0")
+	t.Setenv("DEFI_BREAKER_COOLDOWN", "10s")
+	settings, err = Load(GlobalFlags{ConfigPath: configPath})
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if settings.BreakerFailures != 0 || settings.BreakerCooldown != 10*time.Second {
+		t.Fatalf("expected env to override breaker settings, got failures=%d cooldown=%s", settings.BreakerFailures, settings.BreakerCooldown)
+	}
+}
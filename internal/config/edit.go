@@ -0,0 +1,184 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// document is a config file parsed as a YAML node tree so edits keep the
+// rest of the file, including comments, intact.
+type document struct {
+	path string
+	mode fs.FileMode
+	node yaml.Node
+	root *yaml.Node
+}
+
+func loadDocument(path string) (*document, error) {
+	doc := &document{path: path, mode: 0o600}
+	buf, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("read config: %w", err)
+	}
+	if err == nil {
+		if info, statErr := os.Stat(path); statErr == nil {
+			doc.mode = info.Mode().Perm()
+		}
+		if err := yaml.Unmarshal(buf, &doc.node); err != nil {
+			return nil, fmt.Errorf("parse config yaml: %w", err)
+		}
+	}
+	if len(doc.node.Content) == 0 {
+		doc.node = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+	doc.root = doc.node.Content[0]
+	if doc.root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("config %s must be a YAML mapping", path)
+	}
+	return doc, nil
+}
+
+func (d *document) write() error {
+	var out bytes.Buffer
+	enc := yaml.NewEncoder(&out)
+	enc.SetIndent(2)
+	if err := enc.Encode(&d.node); err != nil {
+		return fmt.Errorf("encode config yaml: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(d.path), 0o755); err != nil {
+		return fmt.Errorf("create config dir: %w", err)
+	}
+	if err := os.WriteFile(d.path, out.Bytes(), d.mode); err != nil {
+		return fmt.Errorf("write config: %w", err)
+	}
+	return nil
+}
+
+// mappingValue returns the value node for key in mapping. With create set, a
+// missing key is added with an empty mapping value.
+func mappingValue(mapping *yaml.Node, key string, create bool) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	if !create {
+		return nil
+	}
+	value := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+	return value
+}
+
+// setScalar sets key to a string value, adding the key first or last in the
+// mapping when it is missing.
+func setScalar(mapping *yaml.Node, key, value string, first bool) {
+	if node := mappingValue(mapping, key, false); node != nil {
+		*node = yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value, LineComment: node.LineComment}
+		return
+	}
+	pair := []*yaml.Node{
+		{Kind: yaml.ScalarNode, Tag: "!!str", Value: key},
+		{Kind: yaml.ScalarNode, Tag: "!!str", Value: value},
+	}
+	if first {
+		mapping.Content = append(pair, mapping.Content...)
+		return
+	}
+	mapping.Content = append(mapping.Content, pair...)
+}
+
+// RPCTarget names where AddRPCURL wrote an endpoint.
+type RPCTarget struct {
+	ConfigPath string
+	// Profile is the profile whose rpc block was edited, or "" for the
+	// top-level rpc block.
+	Profile string
+	Key     string
+	URLs    []string
+}
+
+// AddRPCURL adds url to the rpc endpoints of a chain in the config file. It
+// edits the active profile when one is selected and the top-level rpc block
+// otherwise. sameChain identifies an existing key (slug, ID, or CAIP-2) for
+// the chain; chainKey is used when none exists. With primary set the URL is
+// moved to the front of the list.
+func AddRPCURL(flags GlobalFlags, chainKey string, sameChain func(string) bool, url string, primary bool) (RPCTarget, error) {
+	url = strings.TrimSpace(url)
+	path, err := resolveConfigPath(flags.ConfigPath)
+	if err != nil {
+		return RPCTarget{}, err
+	}
+	cfg, err := readFileConfig(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return RPCTarget{}, err
+	}
+	profile := selectedProfile(flags)
+	if profile == "" {
+		profile = strings.TrimSpace(cfg.Profile)
+	}
+	if _, ok := cfg.Profiles[profile]; profile != "" && !ok {
+		return RPCTarget{}, fmt.Errorf("config profile %q is not defined under profiles", profile)
+	}
+
+	doc, err := loadDocument(path)
+	if err != nil {
+		return RPCTarget{}, err
+	}
+	scope := doc.root
+	if profile != "" {
+		scope = mappingValue(mappingValue(doc.root, "profiles", true), profile, true)
+	}
+	rpcNode := mappingValue(scope, "rpc", true)
+	if rpcNode.Kind != yaml.MappingNode {
+		return RPCTarget{}, fmt.Errorf("config rpc must be a mapping of chain to URLs")
+	}
+
+	target := RPCTarget{ConfigPath: path, Profile: profile, Key: chainKey}
+	var entry *yaml.Node
+	for i := 0; i+1 < len(rpcNode.Content); i += 2 {
+		if sameChain(rpcNode.Content[i].Value) {
+			target.Key = rpcNode.Content[i].Value
+			entry = rpcNode.Content[i+1]
+			break
+		}
+	}
+	var urls []string
+	if entry != nil {
+		var existing rpcURLList
+		if err := entry.Decode(&existing); err != nil {
+			return RPCTarget{}, fmt.Errorf("config rpc %s: %w", target.Key, err)
+		}
+		urls = cleanList(existing)
+	} else {
+		entry = &yaml.Node{}
+		rpcNode.Content = append(rpcNode.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: chainKey}, entry)
+	}
+	urls = slices.DeleteFunc(urls, func(existing string) bool { return existing == url && primary })
+	switch {
+	case slices.Contains(urls, url):
+	case primary:
+		urls = append([]string{url}, urls...)
+	default:
+		urls = append(urls, url)
+	}
+
+	list := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+	for _, u := range urls {
+		list.Content = append(list.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: u})
+	}
+	*entry = *list
+	if err := doc.write(); err != nil {
+		return RPCTarget{}, err
+	}
+	target.URLs = urls
+	return target, nil
+}
@@ -0,0 +1,131 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// ProfileSummary describes a profile from the config file. It names the
+// providers with a configured key but never carries the key itself.
+type ProfileSummary struct {
+	Name             string
+	APIKeys          []string
+	RPCChains        []string
+	DefaultProviders map[string]string
+	CachePath        string
+	PolicyPath       string
+}
+
+// ProfileList is the set of profiles in the config file and the one that
+// Load would apply.
+type ProfileList struct {
+	ConfigPath string
+	Active     string
+	// ActiveSource is "flag", "env", or "file" for the active profile.
+	ActiveSource string
+	Profiles     []ProfileSummary
+}
+
+// ListProfiles reads the profiles declared in the config file selected by
+// flags. A missing config file yields an empty list.
+func ListProfiles(flags GlobalFlags) (ProfileList, error) {
+	path, err := resolveConfigPath(flags.ConfigPath)
+	if err != nil {
+		return ProfileList{}, err
+	}
+	list := ProfileList{ConfigPath: path, Profiles: []ProfileSummary{}}
+	cfg, err := readFileConfig(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return ProfileList{}, err
+	}
+
+	switch {
+	case strings.TrimSpace(flags.Profile) != "":
+		list.Active, list.ActiveSource = strings.TrimSpace(flags.Profile), "flag"
+	case strings.TrimSpace(os.Getenv("DEFI_PROFILE")) != "":
+		list.Active, list.ActiveSource = strings.TrimSpace(os.Getenv("DEFI_PROFILE")), "env"
+	case strings.TrimSpace(cfg.Profile) != "":
+		list.Active, list.ActiveSource = strings.TrimSpace(cfg.Profile), "file"
+	}
+
+	names := make([]string, 0, len(cfg.Profiles))
+	for name := range cfg.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		profile := cfg.Profiles[name]
+		var settings Settings
+		applyRPCConfig(profile.RPC, &settings)
+		applyDefaultProviders(profile.DefaultProviders, &settings)
+		summary := ProfileSummary{
+			Name:             name,
+			APIKeys:          configuredKeys(profile.Providers),
+			RPCChains:        sortedKeys(settings.RPCURLs),
+			DefaultProviders: settings.DefaultProviders,
+			CachePath:        profile.Cache.Path,
+			PolicyPath:       profile.Execution.PolicyPath,
+		}
+		list.Profiles = append(list.Profiles, summary)
+	}
+	return list, nil
+}
+
+// UseProfile makes name the default profile by writing the top-level profile
+// key of the config file. Other content and comments are kept.
+func UseProfile(flags GlobalFlags, name string) (string, error) {
+	name = strings.TrimSpace(name)
+	path, err := resolveConfigPath(flags.ConfigPath)
+	if err != nil {
+		return "", err
+	}
+	cfg, err := readFileConfig(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", fmt.Errorf("config file %s does not exist; define profiles there first", path)
+		}
+		return "", err
+	}
+	if _, ok := cfg.Profiles[name]; !ok {
+		return "", fmt.Errorf("config profile %q is not defined under profiles", name)
+	}
+
+	doc, err := loadDocument(path)
+	if err != nil {
+		return "", err
+	}
+	setScalar(doc.root, "profile", name, true)
+	if err := doc.write(); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+func configuredKeys(cfg providersConfig) []string {
+	keys := []string{}
+	add := func(name, key, env string) {
+		if strings.TrimSpace(key) != "" || strings.TrimSpace(env) != "" {
+			keys = append(keys, name)
+		}
+	}
+	add("defillama", cfg.DefiLlama.APIKey, cfg.DefiLlama.APIKeyEnv)
+	add("uniswap", cfg.Uniswap.APIKey, cfg.Uniswap.APIKeyEnv)
+	add("oneinch", cfg.OneInch.APIKey, cfg.OneInch.APIKeyEnv)
+	add("jupiter", cfg.Jupiter.APIKey, cfg.Jupiter.APIKeyEnv)
+	add("bungee", cfg.Bungee.APIKey, cfg.Bungee.APIKeyEnv)
+	add("etherscan", cfg.Etherscan.APIKey, cfg.Etherscan.APIKeyEnv)
+	add("thegraph", cfg.TheGraph.APIKey, cfg.TheGraph.APIKeyEnv)
+	return keys
+}
+
+func sortedKeys[V any](values map[string]V) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -19,11 +19,14 @@ const (
 	CodeStale         Code = 14
 	CodePartialStrict Code = 15
 	CodeBlocked       Code = 16
+	CodeHealthAlert   Code = 17
+	CodeOffline       Code = 18
 	CodeActionPlan    Code = 20
 	CodeActionSim     Code = 21
 	CodeActionPolicy  Code = 22
 	CodeActionTimeout Code = 23
 	CodeSigner        Code = 24
+	CodeInterrupted   Code = 25
 )
 
 // Error is a typed CLI error that carries a stable error code.
@@ -31,6 +34,8 @@ type Error struct {
 	Code    Code
 	Message string
 	Cause   error
+	// Details is optional structured context rendered as error.details.
+	Details any
 }
 
 func (e *Error) Error() string {
@@ -50,6 +55,12 @@ func Wrap(code Code, message string, cause error) *Error {
 	return &Error{Code: code, Message: message, Cause: cause}
 }
 
+// WithDetails attaches structured context to the error and returns it.
+func (e *Error) WithDetails(details any) *Error {
+	e.Details = details
+	return e
+}
+
 func As(err error) (*Error, bool) {
 	var target *Error
 	if errors.As(err, &target) {
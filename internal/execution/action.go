@@ -1,14 +1,15 @@
 package execution
 
 import (
-	"crypto/rand"
 	"encoding/hex"
 	"fmt"
+
+	"github.com/ggonzalez94/defi-cli/internal/randx"
 )
 
 func NewActionID() string {
 	b := make([]byte, 16)
-	if _, err := rand.Read(b); err != nil {
+	if _, err := randx.Read(b); err != nil {
 		return "action-unknown"
 	}
 	return fmt.Sprintf("act_%s", hex.EncodeToString(b))
@@ -10,6 +10,7 @@ import (
 	"github.com/ggonzalez94/defi-cli/internal/execution"
 	"github.com/ggonzalez94/defi-cli/internal/execution/planner"
 	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/model"
 	"github.com/ggonzalez94/defi-cli/internal/providers"
 )
 
@@ -286,6 +287,8 @@ type RewardsClaimRequest struct {
 	RPCURL              string
 	ControllerAddress   string
 	PoolAddressProvider string
+	// Rewards are the claimable Merkl rewards for provider=morpho.
+	Rewards []model.ClaimableReward
 }
 
 func (r *Registry) BuildRewardsClaimAction(ctx context.Context, req RewardsClaimRequest) (execution.Action, error) {
@@ -293,21 +296,32 @@ func (r *Registry) BuildRewardsClaimAction(ctx context.Context, req RewardsClaim
 	if providerName == "" {
 		return execution.Action{}, clierr.New(clierr.CodeUsage, "--provider is required")
 	}
-	if providerName != "aave" {
-		return execution.Action{}, clierr.New(clierr.CodeUnsupported, "rewards execution currently supports only provider=aave")
+	switch providerName {
+	case "aave":
+		return planner.BuildAaveRewardsClaimAction(ctx, planner.AaveRewardsClaimRequest{
+			Chain:                 req.Chain,
+			Sender:                req.Sender,
+			Recipient:             req.Recipient,
+			Assets:                req.Assets,
+			RewardToken:           req.RewardToken,
+			AmountBaseUnits:       req.AmountBaseUnits,
+			Simulate:              req.Simulate,
+			RPCURL:                req.RPCURL,
+			ControllerAddress:     req.ControllerAddress,
+			PoolAddressesProvider: req.PoolAddressProvider,
+		})
+	case "morpho":
+		return planner.BuildMerklRewardsClaimAction(planner.MerklRewardsClaimRequest{
+			Provider: providerName,
+			Chain:    req.Chain,
+			Sender:   req.Sender,
+			Rewards:  req.Rewards,
+			Simulate: req.Simulate,
+			RPCURL:   req.RPCURL,
+		})
+	default:
+		return execution.Action{}, clierr.New(clierr.CodeUnsupported, "rewards claim currently supports provider=aave|morpho")
 	}
-	return planner.BuildAaveRewardsClaimAction(ctx, planner.AaveRewardsClaimRequest{
-		Chain:                 req.Chain,
-		Sender:                req.Sender,
-		Recipient:             req.Recipient,
-		Assets:                req.Assets,
-		RewardToken:           req.RewardToken,
-		AmountBaseUnits:       req.AmountBaseUnits,
-		Simulate:              req.Simulate,
-		RPCURL:                req.RPCURL,
-		ControllerAddress:     req.ControllerAddress,
-		PoolAddressesProvider: req.PoolAddressProvider,
-	})
 }
 
 type RewardsCompoundRequest struct {
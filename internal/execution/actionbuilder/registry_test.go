@@ -69,7 +69,7 @@ func TestBuildYieldActionRejectsUnsupportedProvider(t *testing.T) {
 
 func TestBuildRewardsClaimActionRejectsUnsupportedProvider(t *testing.T) {
 	reg := New(nil, nil)
-	_, err := reg.BuildRewardsClaimAction(context.Background(), RewardsClaimRequest{Provider: "morpho"})
+	_, err := reg.BuildRewardsClaimAction(context.Background(), RewardsClaimRequest{Provider: "kamino"})
 	if err == nil {
 		t.Fatal("expected unsupported provider error")
 	}
@@ -0,0 +1,196 @@
+package actionbuilder
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/execution"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/providers"
+)
+
+// RouteRequest describes a bridge leg and an optional destination-chain swap
+// leg. Bridge.ToAsset is the asset the bridge delivers; when SwapProvider is
+// set it is swapped into ToAsset on the destination chain.
+type RouteRequest struct {
+	BridgeProvider    string
+	SwapProvider      string
+	Bridge            providers.BridgeQuoteRequest
+	ToAsset           id.Asset
+	Sender            string
+	Recipient         string
+	SlippageBps       int64
+	Simulate          bool
+	RPCURL            string
+	DestinationRPCURL string
+}
+
+// RouteSwapProviders lists swap providers that can run as a route leg. Their
+// actions are plain EVM transactions signed by the route sender; Tempo and
+// CoW Protocol need their own signing flows.
+var RouteSwapProviders = []string{"odos", "paraswap", "taikoswap"}
+
+// BuildRouteAction builds the bridge leg and, when a swap leg is requested,
+// a swap leg sized to the bridge's minimum output, and joins them into one
+// "route" action. The bridge delivers to the sender so the swap can spend it;
+// the swap pays the final recipient.
+func (r *Registry) BuildRouteAction(ctx context.Context, req RouteRequest) (execution.Action, error) {
+	swapProvider := providers.NormalizeSwapProvider(req.SwapProvider)
+	if swapProvider != "" && !slices.Contains(RouteSwapProviders, swapProvider) {
+		return execution.Action{}, clierr.New(clierr.CodeUnsupported, "route swap legs currently support provider="+strings.Join(RouteSwapProviders, "|"))
+	}
+	recipient := strings.TrimSpace(req.Recipient)
+	if recipient == "" {
+		recipient = req.Sender
+	}
+	bridgeRecipient := recipient
+	if swapProvider != "" {
+		bridgeRecipient = req.Sender
+	}
+	bridgeAction, bridgeProvider, err := r.BuildBridgeAction(ctx, req.BridgeProvider, req.Bridge, providers.BridgeExecutionOptions{
+		Sender:      req.Sender,
+		Recipient:   bridgeRecipient,
+		SlippageBps: req.SlippageBps,
+		Simulate:    req.Simulate,
+		RPCURL:      req.RPCURL,
+	})
+	if err != nil {
+		return execution.Action{}, err
+	}
+	bridgeProvider = strings.ToLower(firstNonEmpty(bridgeAction.Provider, bridgeProvider))
+
+	action := execution.NewAction(execution.NewActionID(), "route", bridgeAction.ChainID, bridgeAction.Constraints)
+	action.FromAddress = bridgeAction.FromAddress
+	action.ToAddress = bridgeAction.ToAddress
+	action.InputAmount = bridgeAction.InputAmount
+	action.ProviderData = bridgeAction.ProviderData
+	action.Metadata = map[string]any{}
+	for key, value := range bridgeAction.Metadata {
+		action.Metadata[key] = value
+	}
+	action.Metadata["bridge_provider"] = bridgeProvider
+	action.Metadata["bridge_to_asset_id"] = req.Bridge.ToAsset.AssetID
+	action.Metadata["bridge_recipient"] = bridgeRecipient
+	action.Metadata["route"] = bridgeProvider
+	steps := append([]execution.ActionStep(nil), bridgeAction.Steps...)
+	execution.TagRouteLeg(steps, execution.RouteLegBridge, bridgeProvider, bridgeAction.InputAmount)
+	action.Steps = steps
+	if swapProvider == "" {
+		return action, nil
+	}
+
+	action.ToAddress = recipient
+	action.Metadata["to_asset_id"] = req.ToAsset.AssetID
+	action.Metadata["swap_provider"] = swapProvider
+	action.Metadata["route"] = bridgeProvider + ">" + swapProvider
+	if rpcURL := strings.TrimSpace(req.DestinationRPCURL); rpcURL != "" {
+		action.Metadata["swap_rpc_url"] = rpcURL
+	}
+	minOut := ""
+	for _, i := range execution.RouteLegSteps(&action, execution.RouteLegBridge) {
+		if action.Steps[i].Type == execution.StepTypeBridge {
+			minOut = strings.TrimSpace(action.Steps[i].ExpectedOutputs["to_amount_min"])
+		}
+	}
+	if minOut == "" {
+		return execution.Action{}, clierr.New(clierr.CodeActionPlan, "bridge leg does not report a minimum output to size the swap leg")
+	}
+	if err := r.RefreshRouteSwapLeg(ctx, &action, minOut); err != nil {
+		return execution.Action{}, err
+	}
+	return action, nil
+}
+
+// RefreshRouteSwapLeg re-quotes the swap leg of a route action for
+// amountBaseUnits of the bridged asset and replaces its steps. It refuses to
+// touch a leg with a step that is already submitted or confirmed.
+func (r *Registry) RefreshRouteSwapLeg(ctx context.Context, action *execution.Action, amountBaseUnits string) error {
+	if action == nil || action.IntentType != "route" {
+		return clierr.New(clierr.CodeUsage, "action is not a route intent")
+	}
+	swapProvider, _ := action.Metadata["swap_provider"].(string)
+	if strings.TrimSpace(swapProvider) == "" {
+		return nil
+	}
+	if !slices.Contains(RouteSwapProviders, swapProvider) {
+		return clierr.New(clierr.CodeUnsupported, "route swap legs currently support provider="+strings.Join(RouteSwapProviders, "|"))
+	}
+	for _, i := range execution.RouteLegSteps(action, execution.RouteLegSwap) {
+		switch action.Steps[i].Status {
+		case execution.StepStatusSubmitted, execution.StepStatusConfirmed:
+			return clierr.New(clierr.CodeActionPlan, "route swap leg already submitted; it cannot be re-quoted")
+		}
+	}
+
+	toChainID, _ := action.Metadata["to_chain_id"].(string)
+	chain, err := id.ParseChain(toChainID)
+	if err != nil {
+		return clierr.Wrap(clierr.CodeActionPlan, "route action has invalid to_chain_id", err)
+	}
+	fromAssetID, _ := action.Metadata["bridge_to_asset_id"].(string)
+	fromAsset, err := id.ParseAsset(fromAssetID, chain)
+	if err != nil {
+		return clierr.Wrap(clierr.CodeActionPlan, "route action has invalid bridge_to_asset_id", err)
+	}
+	toAssetID, _ := action.Metadata["to_asset_id"].(string)
+	toAsset, err := id.ParseAsset(toAssetID, chain)
+	if err != nil {
+		return clierr.Wrap(clierr.CodeActionPlan, "route action has invalid to_asset_id", err)
+	}
+	decimals := fromAsset.Decimals
+	if decimals <= 0 {
+		decimals = 18
+	}
+	base, decimal, err := id.NormalizeAmount(amountBaseUnits, "", decimals)
+	if err != nil {
+		return clierr.Wrap(clierr.CodeActionPlan, "route swap leg amount", err)
+	}
+	rpcURL, _ := action.Metadata["swap_rpc_url"].(string)
+
+	swapAction, _, err := r.BuildSwapAction(ctx, swapProvider, "plan", providers.SwapQuoteRequest{
+		Chain:           chain,
+		FromAsset:       fromAsset,
+		ToAsset:         toAsset,
+		AmountBaseUnits: base,
+		AmountDecimal:   decimal,
+		RPCURL:          rpcURL,
+		TradeType:       providers.SwapTradeTypeExactInput,
+	}, providers.SwapExecutionOptions{
+		Sender:      action.FromAddress,
+		Recipient:   action.ToAddress,
+		SlippageBps: action.Constraints.SlippageBps,
+		Simulate:    action.Constraints.Simulate,
+		RPCURL:      rpcURL,
+	})
+	if err != nil {
+		return err
+	}
+	swapSteps := append([]execution.ActionStep(nil), swapAction.Steps...)
+	execution.TagRouteLeg(swapSteps, execution.RouteLegSwap, swapProvider, base)
+
+	kept := make([]execution.ActionStep, 0, len(action.Steps)+len(swapSteps))
+	for _, step := range action.Steps {
+		if step.ExpectedOutputs[execution.StepOutputRouteLeg] != execution.RouteLegSwap {
+			kept = append(kept, step)
+		}
+	}
+	action.Steps = append(kept, swapSteps...)
+	action.Metadata["swap_input_amount"] = base
+	for _, key := range []string{"quoted_amount", "amount_out_min"} {
+		if value, ok := swapAction.Metadata[key]; ok {
+			action.Metadata["swap_"+key] = value
+		}
+	}
+	return nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if strings.TrimSpace(value) != "" {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
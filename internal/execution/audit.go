@@ -0,0 +1,31 @@
+package execution
+
+// Audit event types.
+const (
+	AuditEventSignerRotated = "signer_rotated"
+)
+
+// AuditEvent is an append-only record of an operational change made through
+// the CLI, such as a signer key rotation.
+type AuditEvent struct {
+	Event     string            `json:"event"`
+	CreatedAt string            `json:"created_at"`
+	Details   map[string]string `json:"details,omitempty"`
+}
+
+// SignerRotation is the result of rotating the local signer key. Both keys
+// sign Message so the rotation can be proven to third parties later.
+type SignerRotation struct {
+	OldAddress       string   `json:"old_address"`
+	NewAddress       string   `json:"new_address"`
+	KeyFile          string   `json:"key_file"`
+	BackupFile       string   `json:"backup_file,omitempty"`
+	Message          string   `json:"message"`
+	OldSignature     string   `json:"old_signature"`
+	NewSignature     string   `json:"new_signature"`
+	TemplatesUpdated []string `json:"templates_updated"`
+	SchedulesUpdated []string `json:"schedules_updated"`
+	PolicyUpdated    []string `json:"policy_updated"`
+	DryRun           bool     `json:"dry_run"`
+	RotatedAt        string   `json:"rotated_at"`
+}
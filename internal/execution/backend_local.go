@@ -6,9 +6,9 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/ethclient"
 	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
 	"github.com/ggonzalez94/defi-cli/internal/execution/signer"
+	"github.com/ggonzalez94/defi-cli/internal/rpcx"
 )
 
 type localSubmitBackend struct {
@@ -37,7 +37,7 @@ func (b *localSubmitBackend) SubmitDynamicFeeTx(ctx context.Context, rpcURL stri
 	if err != nil {
 		return common.Hash{}, clierr.Wrap(clierr.CodeSigner, "sign transaction", err)
 	}
-	client, err := ethclient.DialContext(ctx, rpcURL)
+	client, err := rpcx.DialContext(ctx, rpcURL)
 	if err != nil {
 		return common.Hash{}, clierr.Wrap(clierr.CodeUnavailable, "connect rpc", err)
 	}
@@ -0,0 +1,267 @@
+package execution
+
+import (
+	"context"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/registry"
+	"github.com/ggonzalez94/defi-cli/internal/rpcx"
+)
+
+// BridgeDeliveryStatus reports how the destination-chain receipt compared
+// with the planned minimum output.
+type BridgeDeliveryStatus string
+
+const (
+	// BridgeDeliveryDelivered means the recipient received at least to_amount_min.
+	BridgeDeliveryDelivered BridgeDeliveryStatus = "delivered"
+	// BridgeDeliveryShortfall means the recipient received less than
+	// to_amount_min, i.e. more than the quoted fees and slippage were lost.
+	BridgeDeliveryShortfall BridgeDeliveryStatus = "shortfall"
+	// BridgeDeliveryUnverified means the received amount could not be read.
+	BridgeDeliveryUnverified BridgeDeliveryStatus = "unverified"
+)
+
+// BridgeDelivery is the amount a bridge recipient actually received on the
+// destination chain, read from the fill transaction.
+type BridgeDelivery struct {
+	Status               BridgeDeliveryStatus `json:"status"`
+	Method               string               `json:"method,omitempty"`
+	ChainID              string               `json:"chain_id"`
+	Recipient            string               `json:"recipient,omitempty"`
+	AssetID              string               `json:"asset_id,omitempty"`
+	DestinationTxHash    string               `json:"destination_tx_hash,omitempty"`
+	ReceivedBaseUnits    string               `json:"received_base_units,omitempty"`
+	ExpectedMinBaseUnits string               `json:"expected_min_base_units,omitempty"`
+	ShortfallBaseUnits   string               `json:"shortfall_base_units,omitempty"`
+	TimeToFillS          int64                `json:"time_to_fill_s,omitempty"`
+	Note                 string               `json:"note,omitempty"`
+	VerifiedAt           string               `json:"verified_at"`
+}
+
+const (
+	bridgeDeliveryMethodTransferLogs = "transfer_logs"
+	bridgeDeliveryMethodBalanceDelta = "balance_delta"
+)
+
+var erc20TransferTopic = crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+
+type bridgeDeliveryReader interface {
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+	BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error)
+}
+
+var dialBridgeDeliveryReader = func(ctx context.Context, rpcURL string) (bridgeDeliveryReader, func(), error) {
+	client, err := rpcx.DialContext(ctx, rpcURL)
+	if err != nil {
+		return nil, nil, err
+	}
+	return client, client.Close, nil
+}
+
+// VerifyBridgeDelivery reads what the recipient received in the destination
+// fill transaction and records it as action.BridgeDelivery, together with
+// the time from source confirmation to the fill. ERC-20 deliveries sum the
+// Transfer logs to the recipient; native deliveries use the recipient's
+// balance change across the fill block. A shortfall against to_amount_min is
+// recorded, not returned as an error, because the funds have already moved.
+// Non-EVM destinations are skipped. It reports whether the action changed.
+func VerifyBridgeDelivery(ctx context.Context, action *Action) (bool, error) {
+	if action == nil || (action.IntentType != "bridge" && action.IntentType != "route") {
+		return false, nil
+	}
+	if action.BridgeDelivery != nil && action.BridgeDelivery.Status != BridgeDeliveryUnverified {
+		return false, nil
+	}
+	source, receive := bridgeDeliverySteps(action)
+	if source == nil || source.Status != StepStatusConfirmed {
+		return false, nil
+	}
+	if receive != nil && receive.Status != StepStatusConfirmed {
+		return false, nil
+	}
+
+	toChainID, _ := action.Metadata["to_chain_id"].(string)
+	chainID, ok := evmChainReference(toChainID)
+	if !ok {
+		return false, nil
+	}
+	bridgeRecipient, _ := action.Metadata["bridge_recipient"].(string)
+	delivery := &BridgeDelivery{
+		Status:               BridgeDeliveryUnverified,
+		ChainID:              toChainID,
+		Recipient:            firstNonEmpty(strings.TrimSpace(source.ExpectedOutputs["settlement_recipient"]), bridgeRecipient, action.ToAddress),
+		ExpectedMinBaseUnits: strings.TrimSpace(source.ExpectedOutputs["to_amount_min"]),
+		VerifiedAt:           time.Now().UTC().Format(time.RFC3339),
+	}
+	// Route actions swap after the bridge, so the bridged asset and its
+	// recipient are recorded separately from the final asset and recipient.
+	delivery.AssetID, _ = action.Metadata["bridge_to_asset_id"].(string)
+	if delivery.AssetID == "" {
+		delivery.AssetID, _ = action.Metadata["to_asset_id"].(string)
+	}
+	action.BridgeDelivery = delivery
+
+	token, ok := evmAssetAddress(delivery.AssetID)
+	if !ok || !common.IsHexAddress(delivery.Recipient) {
+		delivery.Note = "action metadata does not identify the destination asset and recipient"
+		return true, nil
+	}
+	recipient := common.HexToAddress(delivery.Recipient)
+
+	destTxHash := strings.TrimSpace(source.ExpectedOutputs["destination_tx_hash"])
+	destRPCURL := ""
+	if receive != nil {
+		destTxHash, destRPCURL = receive.TxHash, receive.RPCURL
+	}
+	hash, ok := normalizeStepTxHash(destTxHash)
+	if !ok {
+		delivery.Note = "provider did not report the destination fill transaction"
+		return true, nil
+	}
+	delivery.DestinationTxHash = hash.Hex()
+	if strings.TrimSpace(destRPCURL) == "" {
+		resolved, err := registry.ResolveRPCURL("", chainID)
+		if err != nil {
+			delivery.Note = err.Error()
+			return true, err
+		}
+		destRPCURL = resolved
+	}
+
+	reader, closeReader, err := dialBridgeDeliveryReader(ctx, destRPCURL)
+	if err != nil {
+		delivery.Note = "connect destination rpc: " + err.Error()
+		return true, clierr.Wrap(clierr.CodeUnavailable, "connect destination rpc", err)
+	}
+	defer closeReader()
+	receipt, err := reader.TransactionReceipt(ctx, hash)
+	if err != nil {
+		delivery.Note = "read destination receipt: " + err.Error()
+		return true, clierr.Wrap(clierr.CodeUnavailable, "read destination receipt", err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful || receipt.BlockNumber == nil {
+		delivery.Note = "destination fill transaction did not succeed"
+		return true, nil
+	}
+
+	var received *big.Int
+	if isNativeAssetAddress(token) {
+		delivery.Method = bridgeDeliveryMethodBalanceDelta
+		received, err = nativeBalanceDelta(ctx, reader, recipient, receipt.BlockNumber)
+		if err != nil {
+			delivery.Note = "read recipient balance: " + err.Error()
+			return true, clierr.Wrap(clierr.CodeUnavailable, "read recipient balance", err)
+		}
+	} else {
+		delivery.Method = bridgeDeliveryMethodTransferLogs
+		received = sumTransfersTo(receipt.Logs, token, recipient)
+	}
+	delivery.ReceivedBaseUnits = received.String()
+
+	delivery.Status = BridgeDeliveryDelivered
+	if minOut, ok := new(big.Int).SetString(delivery.ExpectedMinBaseUnits, 10); ok && received.Cmp(minOut) < 0 {
+		delivery.Status = BridgeDeliveryShortfall
+		delivery.ShortfallBaseUnits = new(big.Int).Sub(minOut, received).String()
+	}
+
+	if fillHeader, err := reader.HeaderByNumber(ctx, receipt.BlockNumber); err == nil {
+		if sourceTime, ok := bridgeSourceConfirmedAt(ctx, source); ok && fillHeader.Time >= sourceTime {
+			delivery.TimeToFillS = int64(fillHeader.Time - sourceTime)
+		}
+	}
+	return true, nil
+}
+
+// bridgeDeliverySteps returns the source bridge step and, for bridges that
+// finish with a destination-chain transaction (CCTP mint), that step.
+func bridgeDeliverySteps(action *Action) (*ActionStep, *ActionStep) {
+	var source, receive *ActionStep
+	for i := range action.Steps {
+		switch action.Steps[i].Type {
+		case StepTypeBridge:
+			source = &action.Steps[i]
+		case StepTypeBridgeReceive:
+			receive = &action.Steps[i]
+		}
+	}
+	return source, receive
+}
+
+func bridgeSourceConfirmedAt(ctx context.Context, source *ActionStep) (uint64, bool) {
+	block, ok := new(big.Int).SetString(source.ExpectedOutputs["_confirmed_block_number"], 10)
+	if !ok || strings.TrimSpace(source.RPCURL) == "" {
+		return 0, false
+	}
+	reader, closeReader, err := dialBridgeDeliveryReader(ctx, source.RPCURL)
+	if err != nil {
+		return 0, false
+	}
+	defer closeReader()
+	header, err := reader.HeaderByNumber(ctx, block)
+	if err != nil {
+		return 0, false
+	}
+	return header.Time, true
+}
+
+func sumTransfersTo(logs []*types.Log, token, recipient common.Address) *big.Int {
+	total := new(big.Int)
+	for _, entry := range logs {
+		if entry == nil || entry.Address != token || len(entry.Topics) != 3 || entry.Topics[0] != erc20TransferTopic {
+			continue
+		}
+		if common.BytesToAddress(entry.Topics[2].Bytes()) != recipient {
+			continue
+		}
+		total.Add(total, new(big.Int).SetBytes(entry.Data))
+	}
+	return total
+}
+
+func nativeBalanceDelta(ctx context.Context, reader bridgeDeliveryReader, account common.Address, block *big.Int) (*big.Int, error) {
+	after, err := reader.BalanceAt(ctx, account, block)
+	if err != nil {
+		return nil, err
+	}
+	before, err := reader.BalanceAt(ctx, account, new(big.Int).Sub(block, big.NewInt(1)))
+	if err != nil {
+		return nil, err
+	}
+	delta := new(big.Int).Sub(after, before)
+	if delta.Sign() < 0 {
+		delta.SetInt64(0)
+	}
+	return delta, nil
+}
+
+func evmChainReference(caip2 string) (int64, bool) {
+	ref, ok := strings.CutPrefix(strings.TrimSpace(caip2), "eip155:")
+	if !ok {
+		return 0, false
+	}
+	chainID, err := strconv.ParseInt(ref, 10, 64)
+	return chainID, err == nil && chainID > 0
+}
+
+func evmAssetAddress(assetID string) (common.Address, bool) {
+	_, asset, ok := strings.Cut(strings.TrimSpace(assetID), "/erc20:")
+	if !ok || !common.IsHexAddress(asset) {
+		return common.Address{}, false
+	}
+	return common.HexToAddress(asset), true
+}
+
+// isNativeAssetAddress matches the sentinel addresses the registry uses for
+// native gas tokens.
+func isNativeAssetAddress(addr common.Address) bool {
+	return addr == (common.Address{}) || addr == common.HexToAddress("0xEeeeeEeeeEeEeeEeEeEeeEEEeeeeEeeeeeeeEEeE")
+}
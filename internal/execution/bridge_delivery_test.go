@@ -0,0 +1,150 @@
+package execution
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+type fakeDeliveryReader struct {
+	receipt  *types.Receipt
+	times    map[uint64]uint64
+	balances map[uint64]*big.Int
+}
+
+func (f *fakeDeliveryReader) TransactionReceipt(context.Context, common.Hash) (*types.Receipt, error) {
+	if f.receipt == nil {
+		return nil, errors.New("not found")
+	}
+	return f.receipt, nil
+}
+
+func (f *fakeDeliveryReader) HeaderByNumber(_ context.Context, number *big.Int) (*types.Header, error) {
+	return &types.Header{Number: number, Time: f.times[number.Uint64()]}, nil
+}
+
+func (f *fakeDeliveryReader) BalanceAt(_ context.Context, _ common.Address, block *big.Int) (*big.Int, error) {
+	return f.balances[block.Uint64()], nil
+}
+
+func stubDeliveryReaders(t *testing.T, readers map[string]*fakeDeliveryReader) {
+	t.Helper()
+	previous := dialBridgeDeliveryReader
+	dialBridgeDeliveryReader = func(_ context.Context, rpcURL string) (bridgeDeliveryReader, func(), error) {
+		reader, ok := readers[rpcURL]
+		if !ok {
+			return nil, nil, errors.New("unexpected rpc " + rpcURL)
+		}
+		return reader, func() {}, nil
+	}
+	t.Cleanup(func() { dialBridgeDeliveryReader = previous })
+}
+
+func transferLog(token, to common.Address, amount int64) *types.Log {
+	return &types.Log{
+		Address: token,
+		Topics:  []common.Hash{erc20TransferTopic, common.BytesToHash(common.HexToAddress("0x01").Bytes()), common.BytesToHash(to.Bytes())},
+		Data:    common.LeftPadBytes(big.NewInt(amount).Bytes(), 32),
+	}
+}
+
+func deliveryTestAction(toAssetID string) *Action {
+	action := NewAction("act_delivery", "bridge", "eip155:1", Constraints{})
+	action.Status = ActionStatusCompleted
+	action.ToAddress = "0x00000000000000000000000000000000000000AA"
+	action.Metadata = map[string]any{"to_chain_id": "eip155:8453", "to_asset_id": toAssetID}
+	action.Steps = []ActionStep{{
+		Type:   StepTypeBridge,
+		Status: StepStatusConfirmed,
+		RPCURL: "source",
+		TxHash: "0x" + common.Bytes2Hex(common.LeftPadBytes([]byte{1}, 32)),
+		ExpectedOutputs: map[string]string{
+			"to_amount_min":           "990000",
+			"destination_tx_hash":     "0x" + common.Bytes2Hex(common.LeftPadBytes([]byte{2}, 32)),
+			"_confirmed_block_number": "100",
+		},
+	}}
+	return &action
+}
+
+func TestVerifyBridgeDeliverySumsTransfersAndFlagsShortfall(t *testing.T) {
+	token := common.HexToAddress("0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913")
+	recipient := common.HexToAddress("0x00000000000000000000000000000000000000AA")
+	action := deliveryTestAction("eip155:8453/erc20:" + token.Hex())
+	stubDeliveryReaders(t, map[string]*fakeDeliveryReader{
+		"source": {times: map[uint64]uint64{100: 1_000}},
+		"dest": {
+			receipt: &types.Receipt{
+				Status:      types.ReceiptStatusSuccessful,
+				BlockNumber: big.NewInt(7),
+				Logs: []*types.Log{
+					transferLog(token, recipient, 500_000),
+					transferLog(token, recipient, 400_000),
+					transferLog(token, common.HexToAddress("0xBB"), 1_000_000),
+				},
+			},
+			times: map[uint64]uint64{7: 1_042},
+		},
+	})
+	// The CCTP-style receive step supplies the destination tx and RPC.
+	action.Steps = append(action.Steps, ActionStep{Type: StepTypeBridgeReceive, Status: StepStatusConfirmed, RPCURL: "dest", TxHash: action.Steps[0].ExpectedOutputs["destination_tx_hash"]})
+
+	changed, err := VerifyBridgeDelivery(context.Background(), action)
+	if err != nil || !changed {
+		t.Fatalf("expected delivery to be recorded, changed=%v err=%v", changed, err)
+	}
+	delivery := action.BridgeDelivery
+	if delivery.Method != bridgeDeliveryMethodTransferLogs || delivery.ReceivedBaseUnits != "900000" {
+		t.Fatalf("unexpected received amount: %+v", delivery)
+	}
+	if delivery.Status != BridgeDeliveryShortfall || delivery.ShortfallBaseUnits != "90000" {
+		t.Fatalf("expected shortfall of 90000, got %+v", delivery)
+	}
+	if delivery.TimeToFillS != 42 {
+		t.Fatalf("expected 42s time to fill, got %d", delivery.TimeToFillS)
+	}
+
+	// Recorded results are not re-read.
+	if changed, _ := VerifyBridgeDelivery(context.Background(), action); changed {
+		t.Fatal("expected recorded delivery to be left alone")
+	}
+}
+
+func TestVerifyBridgeDeliveryUsesBalanceDeltaForNativeAssets(t *testing.T) {
+	action := deliveryTestAction("eip155:8453/erc20:0xEeeeeEeeeEeEeeEeEeEeeEEEeeeeEeeeeeeeEEeE")
+	action.Steps = append(action.Steps, ActionStep{Type: StepTypeBridgeReceive, Status: StepStatusConfirmed, RPCURL: "dest", TxHash: action.Steps[0].ExpectedOutputs["destination_tx_hash"]})
+	stubDeliveryReaders(t, map[string]*fakeDeliveryReader{
+		"source": {times: map[uint64]uint64{100: 1_000}},
+		"dest": {
+			receipt:  &types.Receipt{Status: types.ReceiptStatusSuccessful, BlockNumber: big.NewInt(7)},
+			times:    map[uint64]uint64{7: 1_010},
+			balances: map[uint64]*big.Int{6: big.NewInt(10), 7: big.NewInt(1_000_010)},
+		},
+	})
+
+	if _, err := VerifyBridgeDelivery(context.Background(), action); err != nil {
+		t.Fatalf("VerifyBridgeDelivery failed: %v", err)
+	}
+	delivery := action.BridgeDelivery
+	if delivery.Method != bridgeDeliveryMethodBalanceDelta || delivery.ReceivedBaseUnits != "1000000" || delivery.Status != BridgeDeliveryDelivered {
+		t.Fatalf("unexpected native delivery: %+v", delivery)
+	}
+}
+
+func TestVerifyBridgeDeliverySkipsUnfinishedAndNonEVMBridges(t *testing.T) {
+	action := deliveryTestAction("eip155:8453/erc20:0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913")
+	action.Steps = append(action.Steps, ActionStep{Type: StepTypeBridgeReceive, Status: StepStatusPending})
+	if changed, err := VerifyBridgeDelivery(context.Background(), action); changed || err != nil || action.BridgeDelivery != nil {
+		t.Fatalf("expected pending mint to skip verification, changed=%v err=%v", changed, err)
+	}
+
+	action = deliveryTestAction("solana:5eykt4UsFv8P8NJdTREpY1vzqKqZKvdp/token:EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v")
+	action.Metadata["to_chain_id"] = "solana:5eykt4UsFv8P8NJdTREpY1vzqKqZKvdp"
+	if changed, err := VerifyBridgeDelivery(context.Background(), action); changed || err != nil || action.BridgeDelivery != nil {
+		t.Fatalf("expected non-EVM destination to be skipped, changed=%v err=%v", changed, err)
+	}
+}
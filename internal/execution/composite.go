@@ -0,0 +1,140 @@
+package execution
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/execution/signer"
+)
+
+// IntentComposite is the intent type of an action made of ordered
+// sub-actions, e.g. approve → swap → bridge → destination swap. Each
+// sub-action is a complete single-intent action persisted inside the
+// composite's payload; Store.Save routes a sub-action save into its parent, so
+// step executors checkpoint sub-actions exactly like standalone actions.
+const IntentComposite = "composite"
+
+// NewCompositeAction joins planned actions into one composite action. Each
+// sub-action depends on the one before it, so a sub-action only starts once
+// its predecessor completed. All sub-actions must share a sender and
+// execution backend because one signer runs the whole composite.
+func NewCompositeAction(actionID string, subActions []Action) (Action, error) {
+	if len(subActions) < 2 {
+		return Action{}, clierr.New(clierr.CodeUsage, "a composite action needs at least two sub-actions")
+	}
+	first := subActions[0]
+	seen := map[string]struct{}{}
+	for _, sub := range subActions {
+		if _, dup := seen[sub.ActionID]; dup {
+			return Action{}, clierr.New(clierr.CodeUsage, "duplicate sub-action "+sub.ActionID)
+		}
+		seen[sub.ActionID] = struct{}{}
+		if sub.IntentType == IntentComposite || len(sub.SubActions) > 0 {
+			return Action{}, clierr.New(clierr.CodeUsage, "composite actions cannot be nested: "+sub.ActionID)
+		}
+		if sub.Status != ActionStatusPlanned {
+			return Action{}, clierr.New(clierr.CodeUsage, fmt.Sprintf("sub-action %s is %s; only planned actions can be composed", sub.ActionID, sub.Status))
+		}
+		if len(sub.Steps) == 0 {
+			return Action{}, clierr.New(clierr.CodeUsage, "sub-action "+sub.ActionID+" has no executable steps")
+		}
+		if sub.ExecutionBackend != first.ExecutionBackend || !strings.EqualFold(sub.WalletID, first.WalletID) {
+			return Action{}, clierr.New(clierr.CodeUsage, "sub-actions must share one execution backend and wallet")
+		}
+		if sender := strings.TrimSpace(sub.FromAddress); sender != "" && strings.TrimSpace(first.FromAddress) != "" && !strings.EqualFold(sender, strings.TrimSpace(first.FromAddress)) {
+			return Action{}, clierr.New(clierr.CodeUsage, "sub-actions must share one sender address")
+		}
+	}
+
+	action := NewAction(actionID, IntentComposite, first.ChainID, first.Constraints)
+	action.FromAddress = first.FromAddress
+	action.WalletID = first.WalletID
+	action.WalletName = first.WalletName
+	action.ExecutionBackend = first.ExecutionBackend
+	action.SubActions = make([]Action, 0, len(subActions))
+	for i, sub := range subActions {
+		sub.ParentActionID = actionID
+		sub.DependsOn = nil
+		if i > 0 {
+			sub.DependsOn = []string{subActions[i-1].ActionID}
+		}
+		action.SubActions = append(action.SubActions, sub)
+	}
+	return action, nil
+}
+
+// ExecutionSteps returns the steps a submit would run: the action's own
+// steps, or every sub-action's steps in order for composite actions.
+func (a Action) ExecutionSteps() []ActionStep {
+	if len(a.SubActions) == 0 {
+		return a.Steps
+	}
+	var out []ActionStep
+	for _, sub := range a.SubActions {
+		out = append(out, sub.ExecutionSteps()...)
+	}
+	return out
+}
+
+// Broadcast reports whether any step of the action, including the steps of
+// its sub-actions, was sent to the network.
+func (a Action) Broadcast() bool {
+	for _, step := range a.ExecutionSteps() {
+		if step.broadcast() {
+			return true
+		}
+	}
+	return false
+}
+
+func (s ActionStep) broadcast() bool {
+	return s.TxHash != "" || s.Status == StepStatusSubmitted || s.Status == StepStatusConfirmed
+}
+
+// executeComposite runs sub-actions in order, skipping completed ones, so a
+// composite that failed or was interrupted resumes at the sub-action (and
+// step) where it stopped.
+func executeComposite(ctx context.Context, store *Store, action *Action, txSigner signer.Signer, evmBackend EVMSubmitBackend, opts ExecuteOptions) error {
+	persist := func() error {
+		action.Touch()
+		if store != nil {
+			if err := store.Save(*action); err != nil {
+				return clierr.Wrap(clierr.CodeInternal, "persist action state", err)
+			}
+		}
+		return nil
+	}
+
+	completed := map[string]bool{}
+	for i := range action.SubActions {
+		sub := &action.SubActions[i]
+		if sub.Status == ActionStatusCompleted {
+			completed[sub.ActionID] = true
+			continue
+		}
+		for _, dep := range sub.DependsOn {
+			if !completed[dep] {
+				return clierr.New(clierr.CodeActionPlan, fmt.Sprintf("sub-action %s depends on %s, which has not completed", sub.ActionID, dep))
+			}
+		}
+		action.Status = ActionStatusRunning
+		if err := persist(); err != nil {
+			return err
+		}
+		if err := ExecuteAction(ctx, store, sub, txSigner, evmBackend, opts); err != nil {
+			action.Status = ActionStatusFailed
+			if sub.Status == ActionStatusInterrupted {
+				action.Status = ActionStatusInterrupted
+			}
+			if persistErr := persist(); persistErr != nil {
+				return persistErr
+			}
+			return err
+		}
+		completed[sub.ActionID] = true
+	}
+	action.Status = ActionStatusCompleted
+	return persist()
+}
@@ -0,0 +1,158 @@
+package execution
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+)
+
+func compositeTestSubAction(actionID, target string) Action {
+	action := NewAction(actionID, "swap", "eip155:1", Constraints{Simulate: true})
+	action.Status = ActionStatusPlanned
+	action.FromAddress = "0x00000000000000000000000000000000000000aa"
+	action.Steps = append(action.Steps, ActionStep{
+		StepID:  "swap-1",
+		Type:    StepTypeSwap,
+		Status:  StepStatusPending,
+		ChainID: "eip155:1",
+		RPCURL:  "http://127.0.0.1:65535",
+		Target:  target,
+		Data:    "0x",
+		Value:   "0",
+	})
+	return action
+}
+
+func TestNewCompositeActionChainsDependencies(t *testing.T) {
+	first := compositeTestSubAction("act_first", "0x00000000000000000000000000000000000000bb")
+	second := compositeTestSubAction("act_second", "0x00000000000000000000000000000000000000cc")
+	action, err := NewCompositeAction("act_parent", []Action{first, second})
+	if err != nil {
+		t.Fatalf("NewCompositeAction failed: %v", err)
+	}
+	if action.IntentType != IntentComposite || len(action.SubActions) != 2 {
+		t.Fatalf("unexpected composite action: %+v", action)
+	}
+	if action.FromAddress != first.FromAddress {
+		t.Fatalf("expected sender from first sub-action, got %q", action.FromAddress)
+	}
+	if len(action.SubActions[0].DependsOn) != 0 {
+		t.Fatalf("expected first sub-action without dependencies, got %v", action.SubActions[0].DependsOn)
+	}
+	if got := action.SubActions[1].DependsOn; len(got) != 1 || got[0] != "act_first" {
+		t.Fatalf("expected second sub-action to depend on the first, got %v", got)
+	}
+	for _, sub := range action.SubActions {
+		if sub.ParentActionID != "act_parent" {
+			t.Fatalf("expected parent action id on sub-action, got %q", sub.ParentActionID)
+		}
+	}
+	if steps := action.ExecutionSteps(); len(steps) != 2 {
+		t.Fatalf("expected flattened steps from both sub-actions, got %d", len(steps))
+	}
+}
+
+func TestNewCompositeActionRejectsInvalidSubActions(t *testing.T) {
+	first := compositeTestSubAction("act_first", "0x00000000000000000000000000000000000000bb")
+	otherSender := compositeTestSubAction("act_other", "0x00000000000000000000000000000000000000cc")
+	otherSender.FromAddress = "0x00000000000000000000000000000000000000dd"
+	completed := compositeTestSubAction("act_done", "0x00000000000000000000000000000000000000cc")
+	completed.Status = ActionStatusCompleted
+
+	cases := map[string][]Action{
+		"single":           {first},
+		"duplicate":        {first, first},
+		"different sender": {first, otherSender},
+		"not planned":      {first, completed},
+	}
+	for name, subs := range cases {
+		if _, err := NewCompositeAction("act_parent", subs); err == nil {
+			t.Fatalf("%s: expected composite validation error", name)
+		}
+	}
+}
+
+func TestStoreSaveRoutesSubActionIntoParent(t *testing.T) {
+	dir := t.TempDir()
+	store, err := OpenStore(filepath.Join(dir, "actions.db"), filepath.Join(dir, "actions.lock"))
+	if err != nil {
+		t.Fatalf("OpenStore failed: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	first := compositeTestSubAction("act_first", "0x00000000000000000000000000000000000000bb")
+	second := compositeTestSubAction("act_second", "0x00000000000000000000000000000000000000cc")
+	for _, sub := range []Action{first, second} {
+		if err := store.Save(sub); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+	}
+	action, err := NewCompositeAction("act_parent", []Action{first, second})
+	if err != nil {
+		t.Fatalf("NewCompositeAction failed: %v", err)
+	}
+	if err := store.SaveComposite(action); err != nil {
+		t.Fatalf("SaveComposite failed: %v", err)
+	}
+	if _, err := store.Get("act_first"); err == nil {
+		t.Fatal("expected composed sub-action to no longer be stored standalone")
+	}
+
+	sub := action.SubActions[0]
+	sub.Status = ActionStatusCompleted
+	if err := store.Save(sub); err != nil {
+		t.Fatalf("Save sub-action failed: %v", err)
+	}
+	got, err := store.Get("act_parent")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if got.SubActions[0].Status != ActionStatusCompleted {
+		t.Fatalf("expected sub-action save to update the parent, got %s", got.SubActions[0].Status)
+	}
+}
+
+func TestExecuteCompositeResumesAfterCompletedSubAction(t *testing.T) {
+	first := compositeTestSubAction("act_first", "0x00000000000000000000000000000000000000bb")
+	second := compositeTestSubAction("act_second", "not-an-address")
+	action, err := NewCompositeAction("act_parent", []Action{first, second})
+	if err != nil {
+		t.Fatalf("NewCompositeAction failed: %v", err)
+	}
+	action.SubActions[0].Status = ActionStatusCompleted
+	action.SubActions[0].Steps[0].Status = StepStatusConfirmed
+
+	err = ExecuteAction(context.Background(), nil, &action, staticSigner{}, NewLocalSubmitBackend(staticSigner{}), DefaultExecuteOptions())
+	typed, ok := clierr.As(err)
+	if !ok || typed.Code != clierr.CodeUsage {
+		t.Fatalf("expected second sub-action target validation error, got %v", err)
+	}
+	if action.Status != ActionStatusFailed {
+		t.Fatalf("expected composite to be marked failed, got %s", action.Status)
+	}
+	if action.SubActions[0].Status != ActionStatusCompleted {
+		t.Fatalf("expected completed sub-action to be skipped, got %s", action.SubActions[0].Status)
+	}
+	if action.SubActions[1].Status != ActionStatusFailed {
+		t.Fatalf("expected second sub-action to fail, got %s", action.SubActions[1].Status)
+	}
+}
+
+func TestExecuteCompositeRequiresCompletedDependency(t *testing.T) {
+	first := compositeTestSubAction("act_first", "0x00000000000000000000000000000000000000bb")
+	second := compositeTestSubAction("act_second", "0x00000000000000000000000000000000000000cc")
+	action, err := NewCompositeAction("act_parent", []Action{first, second})
+	if err != nil {
+		t.Fatalf("NewCompositeAction failed: %v", err)
+	}
+	action.SubActions[1].DependsOn = []string{"act_missing"}
+	action.SubActions[0].Status = ActionStatusCompleted
+
+	err = ExecuteAction(context.Background(), nil, &action, staticSigner{}, NewLocalSubmitBackend(staticSigner{}), DefaultExecuteOptions())
+	typed, ok := clierr.As(err)
+	if !ok || typed.Code != clierr.CodeActionPlan {
+		t.Fatalf("expected unmet dependency error, got %v", err)
+	}
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/ethereum/go-ethereum/rpc"
 	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
 	"github.com/ggonzalez94/defi-cli/internal/registry"
+	"github.com/ggonzalez94/defi-cli/internal/rpcx"
 )
 
 type EstimateBlockTag string
@@ -102,7 +103,8 @@ func EstimateActionGas(ctx context.Context, action Action, opts EstimateOptions)
 	if strings.TrimSpace(action.ActionID) == "" {
 		return ActionGasEstimate{}, clierr.New(clierr.CodeUsage, "missing action id")
 	}
-	if len(action.Steps) == 0 {
+	steps := action.ExecutionSteps()
+	if len(steps) == 0 {
 		return ActionGasEstimate{}, clierr.New(clierr.CodeUsage, "action has no executable steps")
 	}
 	if opts.GasMultiplier <= 1 {
@@ -122,11 +124,19 @@ func EstimateActionGas(ctx context.Context, action Action, opts EstimateOptions)
 	}
 
 	stepFilter := buildStepFilter(opts.StepIDs)
-	selected := make([]ActionStep, 0, len(action.Steps))
-	for _, step := range action.Steps {
+	selected := make([]ActionStep, 0, len(steps))
+	for _, step := range steps {
 		if !matchesStepFilter(stepFilter, step.StepID) {
 			continue
 		}
+		// Order steps are signed off-chain; solvers pay settlement gas.
+		if step.Type == StepTypeOrder {
+			continue
+		}
+		// Mint calldata is only known once the source burn is attested.
+		if step.Type == StepTypeBridgeReceive && strings.TrimSpace(step.Data) == "" {
+			continue
+		}
 		selected = append(selected, step)
 	}
 	if len(selected) == 0 {
@@ -159,7 +169,7 @@ func EstimateActionGas(ctx context.Context, action Action, opts EstimateOptions)
 		client := rpcClients[strings.TrimSpace(step.RPCURL)]
 		if client == nil {
 			var err error
-			client, err = ethclient.DialContext(ctx, strings.TrimSpace(step.RPCURL))
+			client, err = rpcx.DialContext(ctx, strings.TrimSpace(step.RPCURL))
 			if err != nil {
 				return ActionGasEstimate{}, clierr.Wrap(clierr.CodeUnavailable, "connect rpc", err)
 			}
@@ -12,6 +12,8 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
 	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/logx"
+	"github.com/ggonzalez94/defi-cli/internal/rpcx"
 )
 
 // EVMStepExecutor executes action steps as EIP-1559 transactions on
@@ -57,7 +59,7 @@ func (e *EVMStepExecutor) getClient(ctx context.Context, rpcURL string) (*ethcli
 	if client := e.rpcClients[rpcURL]; client != nil {
 		return client, nil
 	}
-	client, err := ethclient.DialContext(ctx, rpcURL)
+	client, err := rpcx.DialContext(ctx, rpcURL)
 	if err != nil {
 		return nil, clierr.Wrap(clierr.CodeUnavailable, "connect rpc", err)
 	}
@@ -79,9 +81,14 @@ func (e *EVMStepExecutor) ExecuteStep(ctx context.Context, store *Store, action
 		return err
 	}
 
-	chainID, err := client.ChainID(ctx)
+	sender := e.EffectiveSender()
+	prefetch := usablePrefetch(action, step, sender, opts)
+	if prefetch != nil {
+		logx.L().Info("using prefetched chain state", "action_id", action.ActionID, "step_id", step.StepID, "fetched_at", prefetch.FetchedAt)
+	}
+	chainID, err := resolveStepChainID(ctx, client, prefetch)
 	if err != nil {
-		return clierr.Wrap(clierr.CodeUnavailable, "read chain id", err)
+		return err
 	}
 	if step.ChainID != "" {
 		expected := fmt.Sprintf("eip155:%d", chainID.Int64())
@@ -105,7 +112,6 @@ func (e *EVMStepExecutor) ExecuteStep(ctx context.Context, store *Store, action
 	if !ok {
 		return clierr.New(clierr.CodeUsage, "invalid step value")
 	}
-	sender := e.EffectiveSender()
 	if sender == (common.Address{}) {
 		return clierr.New(clierr.CodeSigner, "missing EVM submission backend sender")
 	}
@@ -142,6 +148,7 @@ func (e *EVMStepExecutor) ExecuteStep(ctx context.Context, store *Store, action
 		}
 		step.Status = StepStatusSimulated
 		step.Error = ""
+		logx.L().Info("step simulated", "action_id", action.ActionID, "step_id", step.StepID)
 		if err := safePersist(persist); err != nil {
 			return err
 		}
@@ -156,49 +163,69 @@ func (e *EVMStepExecutor) ExecuteStep(ctx context.Context, store *Store, action
 		return clierr.New(clierr.CodeActionSim, "estimate gas returned zero")
 	}
 
-	tipCap, err := resolveTipCap(ctx, client, opts.MaxPriorityFeeGwei)
+	tipCap, baseFee, err := resolveStepFees(ctx, client, prefetch, opts)
 	if err != nil {
 		return err
 	}
-	header, err := client.HeaderByNumber(ctx, nil)
-	if err != nil {
-		return clierr.Wrap(clierr.CodeUnavailable, "fetch latest header", err)
-	}
-	baseFee := header.BaseFee
-	if baseFee == nil {
-		baseFee = big.NewInt(1_000_000_000)
-	}
 	feeCap, err := resolveFeeCap(baseFee, tipCap, opts.MaxFeeGwei)
 	if err != nil {
 		return err
 	}
+	if opts.Simulate {
+		if err := checkNativeBalance(ctx, client, sender, value, gasLimit, feeCap); err != nil {
+			return err
+		}
+	}
 	unlockNonce := acquireSignerNonceLock(chainID, sender)
 	defer unlockNonce()
-	nonce, err := client.PendingNonceAt(ctx, sender)
-	if err != nil {
-		return clierr.Wrap(clierr.CodeUnavailable, "fetch nonce", err)
-	}
-
-	tx := types.NewTx(&types.DynamicFeeTx{
-		ChainID:   chainID,
-		Nonce:     nonce,
-		GasTipCap: tipCap,
-		GasFeeCap: feeCap,
-		Gas:       gasLimit,
-		To:        &target,
-		Value:     value,
-		Data:      data,
-	})
-	txHash, err := e.backend.SubmitDynamicFeeTx(ctx, rpcURL, chainID, tx)
+	nonce, prefetchedNonce := uint64(0), false
+	if prefetch != nil {
+		nonce, prefetchedNonce = prefetch.firstBroadcastNonce(action)
+	}
+	if !prefetchedNonce {
+		if nonce, err = client.PendingNonceAt(ctx, sender); err != nil {
+			return clierr.Wrap(clierr.CodeUnavailable, "fetch nonce", err)
+		}
+	}
+
+	buildTx := func(nonce uint64) *types.Transaction {
+		return types.NewTx(&types.DynamicFeeTx{
+			ChainID:   chainID,
+			Nonce:     nonce,
+			GasTipCap: tipCap,
+			GasFeeCap: feeCap,
+			Gas:       gasLimit,
+			To:        &target,
+			Value:     value,
+			Data:      data,
+		})
+	}
+	tx := buildTx(nonce)
+	txHash, err := e.submitStepTx(ctx, rpcURL, chainID, tx, step, opts)
+	if err != nil && prefetchedNonce && isStaleNonceError(err) {
+		// The prefetched nonce was used since planning; retry once with the
+		// chain's pending nonce.
+		logx.L().Info("prefetched nonce is stale, re-reading", "action_id", action.ActionID, "step_id", step.StepID, "nonce", nonce)
+		if nonce, err = client.PendingNonceAt(ctx, sender); err != nil {
+			return clierr.Wrap(clierr.CodeUnavailable, "fetch nonce", err)
+		}
+		tx = buildTx(nonce)
+		txHash, err = e.submitStepTx(ctx, rpcURL, chainID, tx, step, opts)
+	}
 	if err != nil {
 		return err
 	}
 	step.Status = StepStatusSubmitted
 	step.TxHash = txHash.Hex()
 	step.Error = ""
+	logx.L().Info("transaction broadcast", "action_id", action.ActionID, "step_id", step.StepID, "tx_hash", step.TxHash)
 	if err := safePersist(persist); err != nil {
 		return err
 	}
+	txHash, err = e.awaitPrivateInclusion(ctx, rpcURL, chainID, tx, step, txHash, opts, persist)
+	if err != nil {
+		return err
+	}
 	confirmedBlock, err := waitForStepConfirmation(ctx, client, step, msg, txHash, opts, persist)
 	if err != nil {
 		return err
@@ -207,6 +234,71 @@ func (e *EVMStepExecutor) ExecuteStep(ctx context.Context, store *Store, action
 	return nil
 }
 
+// resolveStepChainID returns the chain ID a prefetch verified for the step's
+// RPC URL, or reads it.
+func resolveStepChainID(ctx context.Context, client *ethclient.Client, prefetch *Prefetch) (*big.Int, error) {
+	if prefetch != nil {
+		if chainID, ok := prefetch.chainIDBig(); ok {
+			return chainID, nil
+		}
+	}
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return nil, clierr.Wrap(clierr.CodeUnavailable, "read chain id", err)
+	}
+	return chainID, nil
+}
+
+// resolveStepFees returns the priority fee and base fee for a step, taking
+// each from the prefetch when it has one and no override applies.
+func resolveStepFees(ctx context.Context, client *ethclient.Client, prefetch *Prefetch, opts ExecuteOptions) (*big.Int, *big.Int, error) {
+	var tipCap, baseFee *big.Int
+	if prefetch != nil {
+		if strings.TrimSpace(opts.MaxPriorityFeeGwei) == "" {
+			tipCap, _ = parseWei(prefetch.TipCapWei)
+		}
+		baseFee, _ = parseWei(prefetch.BaseFeeWei)
+	}
+	if tipCap == nil {
+		var err error
+		if tipCap, err = resolveTipCap(ctx, client, opts.MaxPriorityFeeGwei); err != nil {
+			return nil, nil, err
+		}
+	}
+	if baseFee == nil {
+		header, err := client.HeaderByNumber(ctx, nil)
+		if err != nil {
+			return nil, nil, clierr.Wrap(clierr.CodeUnavailable, "fetch latest header", err)
+		}
+		baseFee = header.BaseFee
+	}
+	if baseFee == nil {
+		baseFee = big.NewInt(1_000_000_000)
+	}
+	return tipCap, baseFee, nil
+}
+
+// nativeBalanceReader reads an account's gas token balance.
+type nativeBalanceReader interface {
+	BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error)
+}
+
+// checkNativeBalance fails preflight when sender cannot pay the step's value
+// plus its worst-case gas cost (gas limit at the fee cap). Native swap and
+// bridge inputs are sent as value, so this also covers their amount.
+func checkNativeBalance(ctx context.Context, reader nativeBalanceReader, sender common.Address, value *big.Int, gasLimit uint64, feeCap *big.Int) error {
+	gasCost := new(big.Int).Mul(new(big.Int).SetUint64(gasLimit), feeCap)
+	need := new(big.Int).Add(value, gasCost)
+	balance, err := reader.BalanceAt(ctx, sender, nil)
+	if err != nil {
+		return clierr.Wrap(clierr.CodeUnavailable, "read native balance", err)
+	}
+	if balance.Cmp(need) < 0 {
+		return clierr.New(clierr.CodeActionSim, fmt.Sprintf("insufficient native balance for %s: have %s wei, need %s wei (value %s + max gas %s)", sender.Hex(), balance, need, value, gasCost))
+	}
+	return nil
+}
+
 // storeConfirmedBlock records the confirmed block number in the step's
 // ExpectedOutputs so the caller can use it for cross-step ordering.
 func storeConfirmedBlock(step *ActionStep, block *big.Int) {
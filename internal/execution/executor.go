@@ -21,7 +21,9 @@ import (
 	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
 	"github.com/ggonzalez94/defi-cli/internal/execution/signer"
 	"github.com/ggonzalez94/defi-cli/internal/httpx"
+	"github.com/ggonzalez94/defi-cli/internal/logx"
 	"github.com/ggonzalez94/defi-cli/internal/registry"
+	"github.com/ggonzalez94/defi-cli/internal/rpcx"
 )
 
 type ExecuteOptions struct {
@@ -34,6 +36,21 @@ type ExecuteOptions struct {
 	AllowMaxApproval   bool
 	UnsafeProviderTx   bool
 	FeeToken           string // optional; Tempo-only, defaults to chain's primary USDC
+	// Interrupt, when closed, stops execution at the next safe point: before a
+	// step starts, or while polling a step that was already broadcast.
+	Interrupt <-chan struct{}
+	// PrepareStep, when set, runs before each unconfirmed step. It may rewrite
+	// action.Steps from index onward, e.g. to re-quote a route leg whose input
+	// is only known once the previous leg has settled.
+	PrepareStep func(ctx context.Context, action *Action, index int) error
+	// Guardrails, when set, are checked before any step runs; violations
+	// fail with action_policy_error.
+	Guardrails *Guardrails
+	// PrivateTx, when set, routes transactions on its chain through a
+	// private relay instead of the public mempool.
+	PrivateTx *PrivateTxOptions
+	// Fresh ignores state prefetched at plan time and reads the chain.
+	Fresh bool
 }
 
 var (
@@ -69,6 +86,9 @@ func ExecuteAction(ctx context.Context, store *Store, action *Action, txSigner s
 	if action == nil {
 		return clierr.New(clierr.CodeInternal, "missing action")
 	}
+	if len(action.SubActions) > 0 {
+		return executeComposite(ctx, store, action, txSigner, evmBackend, opts)
+	}
 	if len(action.Steps) == 0 {
 		return clierr.New(clierr.CodeUsage, "action has no executable steps")
 	}
@@ -81,6 +101,9 @@ func ExecuteAction(ctx context.Context, store *Store, action *Action, txSigner s
 	if opts.GasMultiplier <= 1 {
 		return clierr.New(clierr.CodeUsage, "gas multiplier must be > 1")
 	}
+	if err := checkGuardrails(store, action, opts); err != nil {
+		return err
+	}
 	persist := func() error {
 		action.Touch()
 		if store != nil {
@@ -114,6 +137,7 @@ func ExecuteAction(ctx context.Context, store *Store, action *Action, txSigner s
 	if err := persist(); err != nil {
 		return err
 	}
+	logx.L().Info("action running", "action_id", action.ActionID, "steps", len(action.Steps), "simulate", opts.Simulate)
 
 	rpcClients := make(map[string]*ethclient.Client)
 	defer func() {
@@ -125,11 +149,35 @@ func ExecuteAction(ctx context.Context, store *Store, action *Action, txSigner s
 	}()
 	requiredHeadByRPC := make(map[string]*big.Int)
 
-	for i := range action.Steps {
+	for i := 0; i < len(action.Steps); i++ {
 		step := &action.Steps[i]
 		if step.Status == StepStatusConfirmed {
 			continue
 		}
+		if interruptRequested(opts) {
+			return interruptAction(action, step, persist)
+		}
+		if opts.PrepareStep != nil {
+			if err := opts.PrepareStep(ctx, action, i); err != nil {
+				step = &action.Steps[i]
+				markStepFailed(action, step, err.Error())
+				if persistErr := persist(); persistErr != nil {
+					return persistErr
+				}
+				return err
+			}
+			if i >= len(action.Steps) {
+				break
+			}
+			step = &action.Steps[i]
+		}
+		if err := checkStepGuardrails(store, action, i, opts); err != nil {
+			markStepFailed(action, step, err.Error())
+			if persistErr := persist(); persistErr != nil {
+				return persistErr
+			}
+			return err
+		}
 		stepRPCURL := strings.TrimSpace(step.RPCURL)
 		step.RPCURL = stepRPCURL
 		if stepRPCURL == "" {
@@ -162,7 +210,7 @@ func ExecuteAction(ctx context.Context, store *Store, action *Action, txSigner s
 		client := rpcClients[stepRPCURL]
 		if client == nil {
 			var err error
-			client, err = ethclient.DialContext(ctx, stepRPCURL)
+			client, err = rpcx.DialContext(ctx, stepRPCURL)
 			if err != nil {
 				markStepFailed(action, step, err.Error())
 				if err := persist(); err != nil {
@@ -174,10 +222,13 @@ func ExecuteAction(ctx context.Context, store *Store, action *Action, txSigner s
 		}
 
 		if minRequiredHead := requiredHeadByRPC[stepRPCURL]; minRequiredHead != nil {
-			waitCtx, cancel := context.WithTimeout(ctx, opts.StepTimeout)
+			waitCtx, cancel := stepWaitContext(ctx, opts)
 			err := waitForRPCHeadAtLeast(waitCtx, client, minRequiredHead, opts.PollInterval)
 			cancel()
 			if err != nil {
+				if interruptRequested(opts) {
+					return interruptAction(action, step, persist)
+				}
 				markStepFailed(action, step, err.Error())
 				if err := persist(); err != nil {
 					return err
@@ -186,7 +237,29 @@ func ExecuteAction(ctx context.Context, store *Store, action *Action, txSigner s
 			}
 		}
 
-		if err := executor.ExecuteStep(ctx, store, action, step, opts); err != nil {
+		logx.L().Info("step started", "action_id", action.ActionID, "step_id", step.StepID, "type", step.Type, "status", step.Status, "rpc_url", logx.SanitizeURL(stepRPCURL))
+
+		// Order steps are signed off-chain and settled by solvers, so they
+		// bypass the transaction executor.
+		var stepErr error
+		if step.Type == StepTypeOrder {
+			stepErr = executeOrderStep(ctx, action, step, txSigner, opts, persist)
+		} else {
+			if step.Type == StepTypeBridgeReceive {
+				stepErr = prepareBridgeReceiveStep(action, i)
+			}
+			if stepErr == nil {
+				stepErr = executor.ExecuteStep(ctx, store, action, step, opts)
+			}
+		}
+		spendErr := recordStepSpend(store, action, i, time.Now())
+		if err := stepErr; err != nil {
+			if spendErr != nil {
+				logx.L().Warn("record spend failed", "action_id", action.ActionID, "step_id", step.StepID, "error", spendErr)
+			}
+			if typed, ok := clierr.As(err); ok && typed.Code == clierr.CodeActionTimeout && interruptRequested(opts) {
+				return interruptAction(action, step, persist)
+			}
 			if step.Status != StepStatusFailed {
 				markStepFailed(action, step, err.Error())
 			}
@@ -204,14 +277,20 @@ func ExecuteAction(ctx context.Context, store *Store, action *Action, txSigner s
 				}
 			}
 		}
+		logx.L().Info("step finished", "action_id", action.ActionID, "step_id", step.StepID, "status", step.Status, "tx_hash", step.TxHash)
 		if err := persist(); err != nil {
 			return err
 		}
+		if spendErr != nil {
+			return clierr.Wrap(clierr.CodeInternal, "record spend", spendErr)
+		}
 	}
+	action.UpdateRealized()
 	action.Status = ActionStatusCompleted
 	if err := persist(); err != nil {
 		return err
 	}
+	logx.L().Info("action completed", "action_id", action.ActionID)
 	return nil
 }
 
@@ -233,48 +312,84 @@ func validatePersistedActionSender(action *Action, effectiveSender common.Addres
 	return nil
 }
 
-func waitForStepConfirmation(ctx context.Context, client *ethclient.Client, step *ActionStep, msg ethereum.CallMsg, txHash common.Hash, opts ExecuteOptions, persist func() error) (*big.Int, error) {
+// stepWaitContext bounds a polling wait by opts.StepTimeout and ends it early
+// when opts.Interrupt is closed. Broadcasts never run under this context, so
+// an interrupt cannot drop a transaction between signing and persisting it.
+func stepWaitContext(ctx context.Context, opts ExecuteOptions) (context.Context, context.CancelFunc) {
 	waitCtx, cancel := context.WithTimeout(ctx, opts.StepTimeout)
-	defer cancel()
-	ticker := time.NewTicker(opts.PollInterval)
-	defer ticker.Stop()
-	for {
-		receipt, err := client.TransactionReceipt(waitCtx, txHash)
-		if err == nil && receipt != nil {
-			if receipt.Status == types.ReceiptStatusSuccessful {
-				if err := ensurePostConfirmationStateVisible(waitCtx, client, step, msg, opts.PollInterval); err != nil {
-					return nil, err
-				}
-				if err := verifyBridgeSettlement(ctx, step, txHash.Hex(), opts); err != nil {
-					return nil, err
-				}
-				step.Status = StepStatusConfirmed
-				step.Error = ""
-				if err := safePersist(persist); err != nil {
-					return nil, err
-				}
-				if receipt.BlockNumber == nil {
-					return nil, nil
-				}
-				return new(big.Int).Set(receipt.BlockNumber), nil
+	if opts.Interrupt != nil {
+		go func() {
+			select {
+			case <-opts.Interrupt:
+				cancel()
+			case <-waitCtx.Done():
 			}
-			if reason := decodeReceiptRevertReason(waitCtx, client, msg, receipt.BlockNumber); reason != "" {
-				return nil, clierr.New(clierr.CodeUnavailable, "transaction reverted on-chain: "+reason)
-			}
-			return nil, clierr.New(clierr.CodeUnavailable, "transaction reverted on-chain")
-		}
-		if waitCtx.Err() != nil {
-			return nil, clierr.Wrap(clierr.CodeActionTimeout, "timed out waiting for receipt", waitCtx.Err())
-		}
-		if err != nil && !errors.Is(err, ethereum.NotFound) {
-			// Ignore transient RPC polling failures until timeout.
-		}
-		select {
-		case <-waitCtx.Done():
-			return nil, clierr.Wrap(clierr.CodeActionTimeout, "timed out waiting for receipt", waitCtx.Err())
-		case <-ticker.C:
+		}()
+	}
+	return waitCtx, cancel
+}
+
+func interruptRequested(opts ExecuteOptions) bool {
+	if opts.Interrupt == nil {
+		return false
+	}
+	select {
+	case <-opts.Interrupt:
+		return true
+	default:
+		return false
+	}
+}
+
+// interruptAction checkpoints an action stopped by a shutdown signal. The
+// current step keeps its status and tx hash so a later submit resumes it.
+func interruptAction(action *Action, step *ActionStep, persist func() error) error {
+	logx.L().Warn("action interrupted", "action_id", action.ActionID, "step_id", step.StepID, "step_status", step.Status)
+	action.Status = ActionStatusInterrupted
+	if err := persist(); err != nil {
+		return err
+	}
+	details := map[string]string{
+		"action_id":   action.ActionID,
+		"step_id":     step.StepID,
+		"step_status": string(step.Status),
+	}
+	if step.TxHash != "" {
+		details["tx_hash"] = step.TxHash
+	}
+	return clierr.New(clierr.CodeInterrupted, "execution interrupted; submit the action again to resume").WithDetails(details)
+}
+
+func waitForStepConfirmation(ctx context.Context, client *ethclient.Client, step *ActionStep, msg ethereum.CallMsg, txHash common.Hash, opts ExecuteOptions, persist func() error) (*big.Int, error) {
+	waitCtx, cancel := stepWaitContext(ctx, opts)
+	defer cancel()
+	// Transient RPC polling failures are retried by the shared poller until timeout.
+	receipt, err := awaitReceipt(waitCtx, strings.TrimSpace(step.RPCURL), txHash, opts.PollInterval)
+	if err != nil {
+		return nil, clierr.Wrap(clierr.CodeActionTimeout, "timed out waiting for receipt", err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		if reason := decodeReceiptRevertReason(waitCtx, client, msg, receipt.BlockNumber); reason != "" {
+			return nil, clierr.New(clierr.CodeUnavailable, "transaction reverted on-chain: "+reason)
 		}
+		return nil, clierr.New(clierr.CodeUnavailable, "transaction reverted on-chain")
 	}
+	recordStepReceipt(step, receipt)
+	if err := ensurePostConfirmationStateVisible(waitCtx, client, step, msg, opts.PollInterval); err != nil {
+		return nil, err
+	}
+	if err := verifyBridgeSettlement(ctx, step, txHash.Hex(), opts); err != nil {
+		return nil, err
+	}
+	step.Status = StepStatusConfirmed
+	step.Error = ""
+	if err := safePersist(persist); err != nil {
+		return nil, err
+	}
+	if receipt.BlockNumber == nil {
+		return nil, nil
+	}
+	return new(big.Int).Set(receipt.BlockNumber), nil
 }
 
 func safePersist(persist func() error) error {
@@ -521,6 +636,18 @@ func verifyBridgeSettlement(ctx context.Context, step *ActionStep, sourceTxHash
 			statusEndpoint = registry.AcrossSettlementURL
 		}
 		return waitForAcrossSettlement(ctx, step, sourceTxHash, statusEndpoint, opts)
+	case "wormhole":
+		statusEndpoint := strings.TrimSpace(step.ExpectedOutputs["settlement_status_endpoint"])
+		if statusEndpoint == "" {
+			statusEndpoint = registry.WormholeSettlementURL
+		}
+		return waitForWormholeSettlement(ctx, step, sourceTxHash, statusEndpoint, opts)
+	case "cctp":
+		statusEndpoint := strings.TrimSpace(step.ExpectedOutputs["settlement_status_endpoint"])
+		if statusEndpoint == "" {
+			statusEndpoint = registry.CCTPSettlementURL
+		}
+		return waitForCCTPAttestation(ctx, step, sourceTxHash, statusEndpoint, opts)
 	default:
 		return clierr.New(clierr.CodeUnsupported, fmt.Sprintf("unsupported bridge settlement provider %q", provider))
 	}
@@ -540,7 +667,7 @@ type liFiStatusResponse struct {
 }
 
 func waitForLiFiSettlement(ctx context.Context, step *ActionStep, sourceTxHash, statusEndpoint string, opts ExecuteOptions) error {
-	waitCtx, cancel := context.WithTimeout(ctx, opts.StepTimeout)
+	waitCtx, cancel := stepWaitContext(ctx, opts)
 	defer cancel()
 	ticker := time.NewTicker(opts.PollInterval)
 	defer ticker.Stop()
@@ -596,7 +723,7 @@ type acrossStatusResponse struct {
 }
 
 func waitForAcrossSettlement(ctx context.Context, step *ActionStep, sourceTxHash, statusEndpoint string, opts ExecuteOptions) error {
-	waitCtx, cancel := context.WithTimeout(ctx, opts.StepTimeout)
+	waitCtx, cancel := stepWaitContext(ctx, opts)
 	defer cancel()
 	ticker := time.NewTicker(opts.PollInterval)
 	defer ticker.Stop()
@@ -717,6 +844,250 @@ func queryAcrossStatus(ctx context.Context, sourceTxHash, statusEndpoint string,
 	return out, nil
 }
 
+// Wormhole settlement stages. Portal transfers are redeemed on the
+// destination separately, so submit completes once guardians sign the VAA.
+const (
+	wormholeStatusPending   = "pending_finality"
+	wormholeStatusVAASigned = "vaa_signed"
+	wormholeStatusRedeemed  = "redeemed"
+	wormholeTargetCompleted = "completed"
+)
+
+type wormholeOperationsResponse struct {
+	Operations []wormholeOperation `json:"operations"`
+}
+
+type wormholeOperation struct {
+	ID  string `json:"id"`
+	VAA *struct {
+		Raw string `json:"raw"`
+	} `json:"vaa"`
+	TargetChain *struct {
+		Status      string `json:"status"`
+		Transaction struct {
+			TxHash string `json:"txHash"`
+		} `json:"transaction"`
+	} `json:"targetChain"`
+}
+
+func waitForWormholeSettlement(ctx context.Context, step *ActionStep, sourceTxHash, statusEndpoint string, opts ExecuteOptions) error {
+	waitCtx, cancel := stepWaitContext(ctx, opts)
+	defer cancel()
+	ticker := time.NewTicker(opts.PollInterval)
+	defer ticker.Stop()
+
+	setStepOutput(step, "settlement_explorer_url", registry.WormholeScanTxURL+strings.TrimSpace(sourceTxHash))
+	for {
+		op, err := queryWormholeOperation(waitCtx, sourceTxHash, statusEndpoint)
+		if err == nil && applyWormholeOperation(step, op) != wormholeStatusPending {
+			return nil
+		}
+		if waitCtx.Err() != nil {
+			return clierr.Wrap(clierr.CodeActionTimeout, "timed out waiting for wormhole guardians to sign the VAA", waitCtx.Err())
+		}
+		select {
+		case <-waitCtx.Done():
+			return clierr.Wrap(clierr.CodeActionTimeout, "timed out waiting for wormhole guardians to sign the VAA", waitCtx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// applyWormholeOperation records the operation's stage on step and returns it.
+func applyWormholeOperation(step *ActionStep, op *wormholeOperation) string {
+	status := wormholeStatusPending
+	if op != nil && op.VAA != nil && strings.TrimSpace(op.VAA.Raw) != "" {
+		status = wormholeStatusVAASigned
+		setStepOutput(step, "wormhole_vaa_id", strings.TrimSpace(op.ID))
+		if target := op.TargetChain; target != nil && strings.TrimSpace(target.Transaction.TxHash) != "" {
+			setStepOutput(step, "destination_tx_hash", strings.TrimSpace(target.Transaction.TxHash))
+			if strings.EqualFold(strings.TrimSpace(target.Status), wormholeTargetCompleted) {
+				status = wormholeStatusRedeemed
+			}
+		}
+	}
+	setStepOutput(step, "settlement_status", status)
+	return status
+}
+
+func queryWormholeOperation(ctx context.Context, sourceTxHash, statusEndpoint string) (*wormholeOperation, error) {
+	endpoint := strings.TrimSpace(statusEndpoint)
+	if endpoint == "" {
+		endpoint = registry.WormholeSettlementURL
+	}
+	parsed, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	query := parsed.Query()
+	query.Set("txHash", strings.TrimSpace(sourceTxHash))
+	parsed.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, parsed.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	var out wormholeOperationsResponse
+	if _, err := settlementHTTPClient.DoJSON(ctx, req, &out); err != nil {
+		return nil, clierr.Wrap(clierr.CodeUnavailable, "query wormhole operation", err)
+	}
+	if len(out.Operations) == 0 {
+		return nil, nil
+	}
+	return &out.Operations[0], nil
+}
+
+// RefreshBridgeSettlement re-queries settlement once for submitted bridge
+// steps that can progress after submit returns. Only Wormhole has such a
+// stage today: the VAA is redeemed on the destination chain later. It
+// reports whether any step output changed.
+func RefreshBridgeSettlement(ctx context.Context, action *Action) (bool, error) {
+	if action == nil {
+		return false, nil
+	}
+	changed := false
+	for i := range action.Steps {
+		step := &action.Steps[i]
+		if step.Type != StepTypeBridge || strings.TrimSpace(step.TxHash) == "" || step.ExpectedOutputs == nil {
+			continue
+		}
+		if !strings.EqualFold(strings.TrimSpace(step.ExpectedOutputs["settlement_provider"]), "wormhole") {
+			continue
+		}
+		before := step.ExpectedOutputs["settlement_status"]
+		if before == wormholeStatusRedeemed {
+			continue
+		}
+		endpoint := strings.TrimSpace(step.ExpectedOutputs["settlement_status_endpoint"])
+		if !registry.IsAllowedBridgeSettlementURL("wormhole", endpoint) {
+			return changed, clierr.New(clierr.CodeActionPlan, "bridge step settlement endpoint is not allowed")
+		}
+		op, err := queryWormholeOperation(ctx, step.TxHash, endpoint)
+		if err != nil {
+			return changed, err
+		}
+		if applyWormholeOperation(step, op) != before {
+			changed = true
+		}
+	}
+	return changed, nil
+}
+
+// cctpStatusComplete is the Iris message status once Circle has attested the
+// burn; until then the attestation field reads "PENDING".
+const cctpStatusComplete = "complete"
+
+type cctpMessagesResponse struct {
+	Messages []cctpMessage `json:"messages"`
+}
+
+type cctpMessage struct {
+	Message     string `json:"message"`
+	Attestation string `json:"attestation"`
+	EventNonce  string `json:"eventNonce"`
+	Status      string `json:"status"`
+}
+
+func waitForCCTPAttestation(ctx context.Context, step *ActionStep, sourceTxHash, statusEndpoint string, opts ExecuteOptions) error {
+	waitCtx, cancel := stepWaitContext(ctx, opts)
+	defer cancel()
+	ticker := time.NewTicker(opts.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		// Iris answers 404 until it indexes the burn, so errors keep polling.
+		msg, err := queryCCTPMessage(waitCtx, sourceTxHash, statusEndpoint, step.ExpectedOutputs["settlement_source_domain"])
+		if err == nil && msg != nil {
+			if status := strings.ToLower(strings.TrimSpace(msg.Status)); status != "" {
+				setStepOutput(step, "settlement_status", status)
+			}
+			if strings.EqualFold(strings.TrimSpace(msg.Status), cctpStatusComplete) && isHexPayload(msg.Message) && isHexPayload(msg.Attestation) {
+				setStepOutput(step, "cctp_message", strings.TrimSpace(msg.Message))
+				setStepOutput(step, "cctp_attestation", strings.TrimSpace(msg.Attestation))
+				setStepOutput(step, "cctp_event_nonce", strings.TrimSpace(msg.EventNonce))
+				return nil
+			}
+		}
+		if waitCtx.Err() != nil {
+			return clierr.Wrap(clierr.CodeActionTimeout, "timed out waiting for circle attestation", waitCtx.Err())
+		}
+		select {
+		case <-waitCtx.Done():
+			return clierr.Wrap(clierr.CodeActionTimeout, "timed out waiting for circle attestation", waitCtx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+func queryCCTPMessage(ctx context.Context, sourceTxHash, statusEndpoint, sourceDomain string) (*cctpMessage, error) {
+	endpoint := strings.TrimSpace(statusEndpoint)
+	if endpoint == "" {
+		endpoint = registry.CCTPSettlementURL
+	}
+	domain := strings.TrimSpace(sourceDomain)
+	if domain == "" {
+		return nil, clierr.New(clierr.CodeActionPlan, "cctp bridge step is missing settlement_source_domain")
+	}
+	parsed, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	parsed.Path = strings.TrimRight(parsed.Path, "/") + "/" + url.PathEscape(domain)
+	query := parsed.Query()
+	query.Set("transactionHash", strings.TrimSpace(sourceTxHash))
+	parsed.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, parsed.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	var out cctpMessagesResponse
+	if _, err := settlementHTTPClient.DoJSON(ctx, req, &out); err != nil {
+		return nil, clierr.Wrap(clierr.CodeUnavailable, "query cctp attestation", err)
+	}
+	if len(out.Messages) == 0 {
+		return nil, nil
+	}
+	return &out.Messages[0], nil
+}
+
+func isHexPayload(value string) bool {
+	decoded, err := decodeHex(strings.TrimSpace(value))
+	return err == nil && len(decoded) > 0
+}
+
+// prepareBridgeReceiveStep fills the destination mint calldata from the
+// attested message recorded by the nearest preceding bridge step.
+func prepareBridgeReceiveStep(action *Action, index int) error {
+	step := &action.Steps[index]
+	for i := index - 1; i >= 0; i-- {
+		source := action.Steps[i]
+		if source.Type != StepTypeBridge {
+			continue
+		}
+		message := strings.TrimSpace(source.ExpectedOutputs["cctp_message"])
+		attestation := strings.TrimSpace(source.ExpectedOutputs["cctp_attestation"])
+		if source.Status != StepStatusConfirmed || message == "" || attestation == "" {
+			return clierr.New(clierr.CodeActionPlan, "bridge receive step has no attested source message; submit the action again once the burn is attested")
+		}
+		messageBytes, err := decodeHex(message)
+		if err != nil {
+			return clierr.Wrap(clierr.CodeActionPlan, "decode attested message", err)
+		}
+		attestationBytes, err := decodeHex(attestation)
+		if err != nil {
+			return clierr.Wrap(clierr.CodeActionPlan, "decode attestation", err)
+		}
+		data, err := policyMessageTransmitterABI.Pack("receiveMessage", messageBytes, attestationBytes)
+		if err != nil {
+			return clierr.Wrap(clierr.CodeInternal, "pack receiveMessage calldata", err)
+		}
+		step.Data = "0x" + hex.EncodeToString(data)
+		return nil
+	}
+	return clierr.New(clierr.CodeActionPlan, "bridge receive step has no preceding bridge step")
+}
+
 func setStepOutput(step *ActionStep, key, value string) {
 	if step == nil || strings.TrimSpace(key) == "" {
 		return
@@ -790,6 +1161,7 @@ func parseGwei(v string) (*big.Int, error) {
 }
 
 func markStepFailed(action *Action, step *ActionStep, msg string) {
+	logx.L().Warn("step failed", "action_id", action.ActionID, "step_id", step.StepID, "previous_status", step.Status, "error", msg)
 	step.Status = StepStatusFailed
 	step.Error = msg
 	action.Status = ActionStatusFailed
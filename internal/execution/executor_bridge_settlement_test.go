@@ -10,6 +10,7 @@ import (
 	"time"
 
 	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/registry"
 )
 
 func TestVerifyBridgeSettlementNoopForNonBridgeStep(t *testing.T) {
@@ -167,3 +168,149 @@ func TestVerifyBridgeSettlementAcrossRefunded(t *testing.T) {
 		t.Fatalf("expected refunded error, got %v", err)
 	}
 }
+
+func TestVerifyBridgeSettlementWormholeVAASigned(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if got := r.URL.Query().Get("txHash"); got != "0xabc" {
+			t.Fatalf("expected txHash query param, got %q", got)
+		}
+		if calls == 1 {
+			_, _ = fmt.Fprint(w, `{"operations":[]}`)
+			return
+		}
+		_, _ = fmt.Fprint(w, `{"operations":[{"id":"2/000/42","vaa":{"raw":"AQAAAA=="}}]}`)
+	}))
+	defer srv.Close()
+
+	step := &ActionStep{
+		Type: StepTypeBridge,
+		ExpectedOutputs: map[string]string{
+			"settlement_provider":        "wormhole",
+			"settlement_status_endpoint": srv.URL,
+		},
+	}
+	err := verifyBridgeSettlement(context.Background(), step, "0xabc", ExecuteOptions{
+		PollInterval: 5 * time.Millisecond,
+		StepTimeout:  200 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("expected VAA to satisfy settlement, got err=%v", err)
+	}
+	if step.ExpectedOutputs["settlement_status"] != "vaa_signed" || step.ExpectedOutputs["wormhole_vaa_id"] != "2/000/42" {
+		t.Fatalf("unexpected settlement outputs: %#v", step.ExpectedOutputs)
+	}
+	if !strings.HasSuffix(step.ExpectedOutputs["settlement_explorer_url"], "0xabc") {
+		t.Fatalf("expected explorer url, got %q", step.ExpectedOutputs["settlement_explorer_url"])
+	}
+}
+
+func TestRefreshBridgeSettlementWormholeRedeemed(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"operations":[{"id":"2/000/42","vaa":{"raw":"AQAAAA=="},"targetChain":{"status":"completed","transaction":{"txHash":"5sig"}}}]}`)
+	}))
+	defer srv.Close()
+
+	action := &Action{Steps: []ActionStep{
+		{Type: StepTypeApproval, TxHash: "0x01"},
+		{
+			Type:   StepTypeBridge,
+			TxHash: "0xabc",
+			ExpectedOutputs: map[string]string{
+				"settlement_provider":        "wormhole",
+				"settlement_status_endpoint": srv.URL,
+				"settlement_status":          "vaa_signed",
+			},
+		},
+	}}
+	changed, err := RefreshBridgeSettlement(context.Background(), action)
+	if err != nil || !changed {
+		t.Fatalf("expected refreshed settlement, changed=%v err=%v", changed, err)
+	}
+	outputs := action.Steps[1].ExpectedOutputs
+	if outputs["settlement_status"] != "redeemed" || outputs["destination_tx_hash"] != "5sig" {
+		t.Fatalf("unexpected settlement outputs: %#v", outputs)
+	}
+
+	changed, err = RefreshBridgeSettlement(context.Background(), action)
+	if err != nil || changed {
+		t.Fatalf("expected redeemed steps to be skipped, changed=%v err=%v", changed, err)
+	}
+}
+
+func TestVerifyBridgeSettlementCCTPWaitsForAttestation(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.URL.Path != "/3" || r.URL.Query().Get("transactionHash") != "0xabc" {
+			t.Fatalf("unexpected attestation query %s", r.URL.String())
+		}
+		switch calls {
+		case 1:
+			http.Error(w, `{"error":"Message hash not found"}`, http.StatusNotFound)
+		case 2:
+			_, _ = fmt.Fprint(w, `{"messages":[{"message":"0x","attestation":"PENDING","status":"pending_confirmations"}]}`)
+		default:
+			_, _ = fmt.Fprint(w, `{"messages":[{"message":"0x0102","attestation":"0x0304","eventNonce":"0x09","status":"complete"}]}`)
+		}
+	}))
+	defer srv.Close()
+
+	step := &ActionStep{
+		Type: StepTypeBridge,
+		ExpectedOutputs: map[string]string{
+			"settlement_provider":        "cctp",
+			"settlement_status_endpoint": srv.URL,
+			"settlement_source_domain":   "3",
+		},
+	}
+	err := verifyBridgeSettlement(context.Background(), step, "0xabc", ExecuteOptions{
+		PollInterval: 5 * time.Millisecond,
+		StepTimeout:  500 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("expected attestation to satisfy settlement, got err=%v", err)
+	}
+	if step.ExpectedOutputs["settlement_status"] != "complete" || step.ExpectedOutputs["cctp_message"] != "0x0102" || step.ExpectedOutputs["cctp_attestation"] != "0x0304" {
+		t.Fatalf("unexpected settlement outputs: %#v", step.ExpectedOutputs)
+	}
+}
+
+func TestPrepareBridgeReceiveStepPacksAttestedMessage(t *testing.T) {
+	action := &Action{Steps: []ActionStep{
+		{
+			Type:   StepTypeBridge,
+			Status: StepStatusConfirmed,
+			ExpectedOutputs: map[string]string{
+				"cctp_message":     "0x0102",
+				"cctp_attestation": "0x0304",
+			},
+		},
+		{Type: StepTypeBridgeReceive, Target: registry.CCTPMessageTransmitterV2},
+	}}
+	if err := prepareBridgeReceiveStep(action, 1); err != nil {
+		t.Fatalf("prepareBridgeReceiveStep failed: %v", err)
+	}
+	data, err := decodeHex(action.Steps[1].Data)
+	if err != nil {
+		t.Fatalf("decode mint calldata: %v", err)
+	}
+	if err := validateBridgeReceivePolicy(&action.Steps[1], 8453, data); err != nil {
+		t.Fatalf("expected mint step to pass policy, got %v", err)
+	}
+	args, err := policyMessageTransmitterABI.Methods["receiveMessage"].Inputs.Unpack(data[4:])
+	if err != nil || fmt.Sprintf("%x/%x", args[0], args[1]) != "0102/0304" {
+		t.Fatalf("unexpected receiveMessage args %v err=%v", args, err)
+	}
+
+	action.Steps[1].Target = "0x00000000000000000000000000000000000000bb"
+	if err := validateBridgeReceivePolicy(&action.Steps[1], 8453, data); err == nil {
+		t.Fatal("expected non-transmitter target to be rejected")
+	}
+
+	action.Steps[0].Status = StepStatusSubmitted
+	if err := prepareBridgeReceiveStep(action, 1); err == nil {
+		t.Fatal("expected unattested source step to block the mint")
+	}
+}
@@ -68,6 +68,29 @@ func TestWrapEVMExecutionErrorIncludesDecodedRevert(t *testing.T) {
 	}
 }
 
+type staticBalanceReader struct{ balance *big.Int }
+
+func (r staticBalanceReader) BalanceAt(context.Context, common.Address, *big.Int) (*big.Int, error) {
+	return r.balance, nil
+}
+
+func TestCheckNativeBalanceRequiresValuePlusGasHeadroom(t *testing.T) {
+	sender := common.HexToAddress("0x00000000000000000000000000000000000000AA")
+	value := big.NewInt(1_000_000)
+	feeCap := big.NewInt(10)
+	// value + 21000 gas at 10 wei = 1_210_000 wei.
+	if err := checkNativeBalance(context.Background(), staticBalanceReader{big.NewInt(1_210_000)}, sender, value, 21_000, feeCap); err != nil {
+		t.Fatalf("expected an exact balance to pass, got %v", err)
+	}
+	err := checkNativeBalance(context.Background(), staticBalanceReader{big.NewInt(1_100_000)}, sender, value, 21_000, feeCap)
+	if err == nil {
+		t.Fatal("expected a balance that covers only the value to fail")
+	}
+	if typed, ok := clierr.As(err); !ok || typed.Code != clierr.CodeActionSim {
+		t.Fatalf("expected an action simulation error, got %v", err)
+	}
+}
+
 func TestNormalizeStepTxHash(t *testing.T) {
 	validHash := "0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
 	if _, ok := normalizeStepTxHash(validHash); !ok {
@@ -103,6 +126,60 @@ func TestExecuteActionRejectsInvalidStepTargetBeforeRPCDial(t *testing.T) {
 	}
 }
 
+func TestExecuteActionRunsPreparedStep(t *testing.T) {
+	action := NewAction("act_test", "route", "eip155:1", Constraints{Simulate: true})
+	action.Steps = append(action.Steps, ActionStep{
+		StepID:  "step-1",
+		Type:    StepTypeSwap,
+		Status:  StepStatusPending,
+		ChainID: "eip155:1",
+		RPCURL:  "http://127.0.0.1:65535",
+		Target:  "0x00000000000000000000000000000000000000bb",
+		Data:    "0x",
+		Value:   "0",
+	})
+	opts := DefaultExecuteOptions()
+	prepared := -1
+	opts.PrepareStep = func(_ context.Context, action *Action, index int) error {
+		prepared = index
+		action.Steps[index].Target = "not-an-address"
+		return nil
+	}
+	err := ExecuteAction(context.Background(), nil, &action, staticSigner{}, NewLocalSubmitBackend(staticSigner{}), opts)
+	typed, ok := clierr.As(err)
+	if !ok || typed.Code != clierr.CodeUsage {
+		t.Fatalf("expected the rewritten step to be validated, got %v", err)
+	}
+	if prepared != 0 {
+		t.Fatalf("expected PrepareStep to run for step 0, got %d", prepared)
+	}
+}
+
+func TestExecuteActionFailsStepWhenPrepareFails(t *testing.T) {
+	action := NewAction("act_test", "route", "eip155:1", Constraints{Simulate: true})
+	action.Steps = append(action.Steps, ActionStep{
+		StepID:  "step-1",
+		Type:    StepTypeSwap,
+		Status:  StepStatusPending,
+		ChainID: "eip155:1",
+		RPCURL:  "http://127.0.0.1:65535",
+		Target:  "0x00000000000000000000000000000000000000bb",
+		Data:    "0x",
+		Value:   "0",
+	})
+	opts := DefaultExecuteOptions()
+	opts.PrepareStep = func(context.Context, *Action, int) error {
+		return clierr.New(clierr.CodeActionPlan, "re-quote failed")
+	}
+	err := ExecuteAction(context.Background(), nil, &action, staticSigner{}, NewLocalSubmitBackend(staticSigner{}), opts)
+	if err == nil || !strings.Contains(err.Error(), "re-quote failed") {
+		t.Fatalf("expected prepare error, got %v", err)
+	}
+	if action.Steps[0].Status != StepStatusFailed {
+		t.Fatalf("expected step to be marked failed, got %s", action.Steps[0].Status)
+	}
+}
+
 func TestExecuteActionReturnsErrorWhenPersistFails(t *testing.T) {
 	storePath := filepath.Join(t.TempDir(), "actions.db")
 	lockPath := filepath.Join(t.TempDir(), "actions.lock")
@@ -139,6 +216,43 @@ func TestExecuteActionReturnsErrorWhenPersistFails(t *testing.T) {
 	}
 }
 
+func TestExecuteActionCheckpointsBeforeStepWhenInterrupted(t *testing.T) {
+	store, err := OpenStore(filepath.Join(t.TempDir(), "actions.db"), filepath.Join(t.TempDir(), "actions.lock"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	defer store.Close()
+
+	action := NewAction("act_test", "swap", "eip155:1", Constraints{Simulate: true})
+	action.Steps = append(action.Steps, ActionStep{
+		StepID:  "step-1",
+		Type:    StepTypeSwap,
+		Status:  StepStatusPending,
+		ChainID: "eip155:1",
+		RPCURL:  "http://127.0.0.1:65535",
+		Target:  "0x00000000000000000000000000000000000000bb",
+		Data:    "0x",
+		Value:   "0",
+	})
+	interrupt := make(chan struct{})
+	close(interrupt)
+	opts := DefaultExecuteOptions()
+	opts.Interrupt = interrupt
+
+	err = ExecuteAction(context.Background(), store, &action, staticSigner{}, NewLocalSubmitBackend(staticSigner{}), opts)
+	typed, ok := clierr.As(err)
+	if !ok || typed.Code != clierr.CodeInterrupted {
+		t.Fatalf("expected interrupted error, got %v", err)
+	}
+	saved, err := store.Get("act_test")
+	if err != nil {
+		t.Fatalf("load action: %v", err)
+	}
+	if saved.Status != ActionStatusInterrupted || saved.Steps[0].Status != StepStatusPending {
+		t.Fatalf("expected resumable checkpoint, got action=%s step=%s", saved.Status, saved.Steps[0].Status)
+	}
+}
+
 func TestExecuteActionRejectsMismatchedPersistedSender(t *testing.T) {
 	action := NewAction("act_test", "swap", "eip155:1", Constraints{Simulate: true})
 	action.FromAddress = "0x00000000000000000000000000000000000000bb"
@@ -0,0 +1,396 @@
+package execution
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+)
+
+// NativeToken identifies a chain's native coin in guardrail token entries
+// and spend records.
+const NativeToken = "native"
+
+// Guardrails are operator-configured hard limits the executor checks before
+// any step of an action is signed. Empty lists allow everything.
+type Guardrails struct {
+	RequireSimulation bool
+	// AllowedChains holds CAIP-2 chain ids steps may execute on.
+	AllowedChains []string
+	// AllowedSpenders holds addresses ERC-20 approvals may be granted to.
+	AllowedSpenders []string
+	// Tokens lists the tokens actions may spend or approve, with optional
+	// per-transaction and rolling 24h limits.
+	Tokens []TokenGuardrail
+}
+
+// TokenGuardrail allows one token on one chain. Limits are in base units;
+// nil leaves a limit unset.
+type TokenGuardrail struct {
+	ChainID   string
+	Token     string // lowercase hex address or NativeToken
+	Symbol    string
+	MaxPerTx  *big.Int
+	MaxPerDay *big.Int
+}
+
+// GuardrailViolation is returned in error.details when guardrails block an
+// action.
+type GuardrailViolation struct {
+	Rule    string `json:"rule"`
+	ChainID string `json:"chain_id,omitempty"`
+	Token   string `json:"token,omitempty"`
+	Spender string `json:"spender,omitempty"`
+	Amount  string `json:"amount,omitempty"`
+	Limit   string `json:"limit,omitempty"`
+}
+
+// spendingIntents move the action's input amount out of the sender's wallet.
+var spendingIntents = map[string]bool{
+	"swap":          true,
+	"bridge":        true,
+	"route":         true,
+	"transfer":      true,
+	"lend_supply":   true,
+	"lend_repay":    true,
+	"yield_deposit": true,
+	"limit_order":   true,
+}
+
+var nativeTokenAddress = common.HexToAddress("0xEeeeeEeeeEeEeeEeEeEeeEEEeeeeEeeeeeeeEEeE")
+
+// checkGuardrails validates the unconfirmed steps of action and its spend
+// against opts.Guardrails. Rolling daily spend is read from store.
+func checkGuardrails(store *Store, action *Action, opts ExecuteOptions) error {
+	g := opts.Guardrails
+	if g == nil || action == nil {
+		return nil
+	}
+	if g.RequireSimulation && !opts.Simulate {
+		return guardrailViolation(GuardrailViolation{Rule: "require_simulation"}, "execution policy requires simulation; remove --simulate=false")
+	}
+	for i := range action.Steps {
+		step := &action.Steps[i]
+		if step.Status == StepStatusConfirmed {
+			continue
+		}
+		if err := g.checkStep(action, step); err != nil {
+			return err
+		}
+	}
+
+	// A spend already broadcast is in the spend log; resuming must not
+	// count it twice.
+	if index := spendStepIndex(action); index >= 0 && action.Steps[index].broadcast() {
+		return nil
+	}
+	chainID, token, amount, ok := actionSpend(action)
+	if !ok {
+		return nil
+	}
+	return g.checkSpend(store, chainID, token, amount)
+}
+
+// CheckActionSpend validates the input spend of action against the token
+// allowlist and the per-transaction and rolling daily limits. Commands that
+// spend without running the executor, such as limit orders, call it before
+// committing to the spend.
+func (g *Guardrails) CheckActionSpend(store *Store, action *Action) error {
+	if g == nil {
+		return nil
+	}
+	chainID, token, amount, ok := actionSpend(action)
+	if !ok {
+		return nil
+	}
+	return g.checkSpend(store, chainID, token, amount)
+}
+
+// RecordActionSpend books the input spend of action for rolling daily limits.
+// Actions that spend nothing are ignored.
+func RecordActionSpend(store *Store, action *Action, at time.Time) error {
+	chainID, token, amount, ok := actionSpend(action)
+	if !ok || store == nil {
+		return nil
+	}
+	return store.RecordSpend(action.ActionID, chainID, token, amount, at)
+}
+
+func (g *Guardrails) checkSpend(store *Store, chainID, token string, amount *big.Int) error {
+	limit, listed := g.token(chainID, token)
+	if len(g.Tokens) > 0 && !listed {
+		return guardrailViolation(GuardrailViolation{Rule: "allowed_tokens", ChainID: chainID, Token: token},
+			fmt.Sprintf("execution policy does not allow spending token %s on %s", token, chainID))
+	}
+	if !listed {
+		return nil
+	}
+	if limit.MaxPerTx != nil && amount.Cmp(limit.MaxPerTx) > 0 {
+		return guardrailViolation(GuardrailViolation{Rule: "max_per_tx", ChainID: chainID, Token: token, Amount: amount.String(), Limit: limit.MaxPerTx.String()},
+			fmt.Sprintf("amount %s exceeds the execution policy limit of %s per transaction for %s", amount, limit.MaxPerTx, limit.label()))
+	}
+	if limit.MaxPerDay != nil {
+		spent := new(big.Int)
+		if store != nil {
+			var err error
+			spent, err = store.SpentSince(chainID, token, time.Now().Add(-24*time.Hour))
+			if err != nil {
+				return clierr.Wrap(clierr.CodeInternal, "read spend log", err)
+			}
+		}
+		if total := new(big.Int).Add(spent, amount); total.Cmp(limit.MaxPerDay) > 0 {
+			return guardrailViolation(GuardrailViolation{Rule: "max_per_day", ChainID: chainID, Token: token, Amount: total.String(), Limit: limit.MaxPerDay.String()},
+				fmt.Sprintf("amount %s would bring 24h spend of %s to %s, above the execution policy limit of %s", amount, limit.label(), total, limit.MaxPerDay))
+		}
+	}
+	return nil
+}
+
+// checkStepGuardrails validates the step at index against opts.Guardrails
+// right before it runs. PrepareStep may rewrite a step, and a TWAP slice or
+// route leg may be re-quoted, after the action-wide check in
+// checkGuardrails, so the spend limits are checked again against the amount
+// the step actually moves and the spend booked since.
+func checkStepGuardrails(store *Store, action *Action, index int, opts ExecuteOptions) error {
+	g := opts.Guardrails
+	if g == nil || action == nil || index < 0 || index >= len(action.Steps) {
+		return nil
+	}
+	step := &action.Steps[index]
+	if err := g.checkStep(action, step); err != nil {
+		return err
+	}
+	if step.broadcast() {
+		return nil
+	}
+	chainID, token, amount, ok := stepSpend(action, index)
+	if !ok {
+		return nil
+	}
+	return g.checkSpend(store, chainID, token, amount)
+}
+
+// recordStepSpend books the spend of the step at index once it has been
+// broadcast, so actions that stop part way still count toward daily limits.
+// Spend is keyed by action, so a resumed step is booked once.
+func recordStepSpend(store *Store, action *Action, index int, at time.Time) error {
+	if store == nil || action == nil || index < 0 || index >= len(action.Steps) || !action.Steps[index].broadcast() {
+		return nil
+	}
+	chainID, token, amount, ok := stepSpend(action, index)
+	if !ok {
+		return nil
+	}
+	return store.RecordSpend(action.ActionID, chainID, token, amount, at)
+}
+
+func (g *Guardrails) checkStep(action *Action, step *ActionStep) error {
+	chainID := strings.TrimSpace(step.ChainID)
+	if chainID == "" {
+		chainID = strings.TrimSpace(action.ChainID)
+	}
+	if len(g.AllowedChains) > 0 && !containsFold(g.AllowedChains, chainID) {
+		return guardrailViolation(GuardrailViolation{Rule: "allowed_chains", ChainID: chainID},
+			fmt.Sprintf("execution policy does not allow chain %s", chainID))
+	}
+	for _, call := range guardrailCalls(step) {
+		if err := g.checkCall(chainID, step.Type, call); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (g *Guardrails) checkCall(chainID string, stepType StepType, call StepCall) error {
+	data := common.FromHex(strings.TrimSpace(call.Data))
+	if value, ok := new(big.Int).SetString(strings.TrimSpace(call.Value), 10); ok && value.Sign() > 0 && len(g.Tokens) > 0 {
+		if _, listed := g.token(chainID, NativeToken); !listed {
+			return guardrailViolation(GuardrailViolation{Rule: "allowed_tokens", ChainID: chainID, Token: NativeToken},
+				fmt.Sprintf("execution policy does not allow spending the native token on %s", chainID))
+		}
+	}
+	if stepType != StepTypeApproval && stepType != StepTypeTransfer {
+		return nil
+	}
+	token := strings.ToLower(strings.TrimSpace(call.Target))
+	if len(g.Tokens) > 0 {
+		if _, listed := g.token(chainID, token); !listed {
+			return guardrailViolation(GuardrailViolation{Rule: "allowed_tokens", ChainID: chainID, Token: token},
+				fmt.Sprintf("execution policy does not allow token %s on %s", token, chainID))
+		}
+	}
+	if stepType != StepTypeApproval || len(g.AllowedSpenders) == 0 {
+		return nil
+	}
+	if len(data) < 4 || !bytes.Equal(data[:4], policyApproveSelector) {
+		return nil
+	}
+	args, err := policyERC20ABI.Methods["approve"].Inputs.Unpack(data[4:])
+	if err != nil || len(args) != 2 {
+		return nil
+	}
+	spender, ok := toAddress(args[0])
+	if !ok {
+		return nil
+	}
+	if !containsFold(g.AllowedSpenders, spender.Hex()) {
+		return guardrailViolation(GuardrailViolation{Rule: "allowed_spenders", ChainID: chainID, Token: token, Spender: spender.Hex()},
+			fmt.Sprintf("execution policy does not allow approving spender %s", spender.Hex()))
+	}
+	return nil
+}
+
+func (g *Guardrails) token(chainID, token string) (TokenGuardrail, bool) {
+	for _, entry := range g.Tokens {
+		if strings.EqualFold(entry.ChainID, chainID) && strings.EqualFold(entry.Token, token) {
+			return entry, true
+		}
+	}
+	return TokenGuardrail{}, false
+}
+
+func (t TokenGuardrail) label() string {
+	if t.Symbol != "" {
+		return t.Symbol
+	}
+	return t.Token
+}
+
+// actionSpend returns the token and base-unit amount a spending intent takes
+// from the sender: the action input amount of the asset recorded in its
+// metadata.
+func actionSpend(action *Action) (chainID, token string, amount *big.Int, ok bool) {
+	if action == nil || !spendingIntents[action.IntentType] {
+		return "", "", nil, false
+	}
+	amount, ok = parsePositiveBaseUnits(action.InputAmount)
+	if !ok {
+		return "", "", nil, false
+	}
+	for _, key := range []string{"token_in", "from_asset_id", "asset_id"} {
+		raw, _ := action.Metadata[key].(string)
+		if token = spendToken(raw); token != "" {
+			return strings.TrimSpace(action.ChainID), token, amount, true
+		}
+	}
+	return "", "", nil, false
+}
+
+// spendStepIndex returns the index of the step that moves the action input
+// out of the sender's wallet: the first step that is not an approval.
+func spendStepIndex(action *Action) int {
+	for i, step := range action.Steps {
+		if step.Type != StepTypeApproval {
+			return i
+		}
+	}
+	return -1
+}
+
+// stepSpend returns the spend of the step at index when it is the action's
+// spending step. Native value and ERC-20 transfer calldata are read from the
+// step itself; other steps spend the action input amount, which re-quoting
+// keeps in line with the step.
+func stepSpend(action *Action, index int) (chainID, token string, amount *big.Int, ok bool) {
+	if index < 0 || index != spendStepIndex(action) {
+		return "", "", nil, false
+	}
+	chainID, token, amount, ok = actionSpend(action)
+	if !ok {
+		return "", "", nil, false
+	}
+	step := &action.Steps[index]
+	if stepChainID := strings.TrimSpace(step.ChainID); stepChainID != "" {
+		chainID = stepChainID
+	}
+	switch {
+	case token == NativeToken:
+		if value := stepValue(step); value.Sign() > 0 {
+			amount = value
+		}
+	case step.Type == StepTypeTransfer:
+		if transferred, found := stepTransferAmount(step, token); found {
+			amount = transferred
+		}
+	}
+	return chainID, token, amount, true
+}
+
+// stepValue sums the native value sent by the calls of step.
+func stepValue(step *ActionStep) *big.Int {
+	total := new(big.Int)
+	for _, call := range guardrailCalls(step) {
+		if value, ok := new(big.Int).SetString(strings.TrimSpace(call.Value), 10); ok && value.Sign() > 0 {
+			total.Add(total, value)
+		}
+	}
+	return total
+}
+
+// stepTransferAmount sums the ERC-20 transfers of token made by step.
+func stepTransferAmount(step *ActionStep, token string) (*big.Int, bool) {
+	total, found := new(big.Int), false
+	for _, call := range guardrailCalls(step) {
+		if !strings.EqualFold(strings.TrimSpace(call.Target), token) {
+			continue
+		}
+		data := common.FromHex(strings.TrimSpace(call.Data))
+		if len(data) < 4 || !bytes.Equal(data[:4], policyTransferSelector) {
+			continue
+		}
+		args, err := policyERC20ABI.Methods["transfer"].Inputs.Unpack(data[4:])
+		if err != nil || len(args) != 2 {
+			continue
+		}
+		if amount, ok := toBigInt(args[1]); ok {
+			total.Add(total, amount)
+			found = true
+		}
+	}
+	return total, found
+}
+
+// spendToken normalizes an address or CAIP-19 asset id to a lowercase token
+// address, or NativeToken for native coins.
+func spendToken(raw string) string {
+	raw = strings.TrimSpace(raw)
+	if _, asset, found := strings.Cut(raw, "/"); found {
+		namespace, reference, _ := strings.Cut(asset, ":")
+		if namespace == "slip44" {
+			return NativeToken
+		}
+		raw = reference
+	}
+	if !common.IsHexAddress(raw) {
+		return ""
+	}
+	address := common.HexToAddress(raw)
+	if address == (common.Address{}) || address == nativeTokenAddress {
+		return NativeToken
+	}
+	return strings.ToLower(address.Hex())
+}
+
+func guardrailCalls(step *ActionStep) []StepCall {
+	if len(step.Calls) > 0 {
+		return step.Calls
+	}
+	return []StepCall{{Target: step.Target, Data: step.Data, Value: step.Value}}
+}
+
+func guardrailViolation(violation GuardrailViolation, message string) error {
+	return clierr.New(clierr.CodeActionPolicy, message).WithDetails(violation)
+}
+
+func containsFold(values []string, target string) bool {
+	for _, value := range values {
+		if strings.EqualFold(strings.TrimSpace(value), strings.TrimSpace(target)) {
+			return true
+		}
+	}
+	return false
+}
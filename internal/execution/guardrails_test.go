@@ -0,0 +1,251 @@
+package execution
+
+import (
+	"context"
+	"math/big"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+)
+
+const guardrailTestUSDC = "0x833589fcd6edb6e08f4c7c32d4f71b54bda02913"
+
+func guardrailTestAction(t *testing.T, spender common.Address) Action {
+	t.Helper()
+	approve, err := policyERC20ABI.Pack("approve", spender, big.NewInt(100_000_000))
+	if err != nil {
+		t.Fatalf("pack approve: %v", err)
+	}
+	action := NewAction("act_guardrails", "swap", "eip155:8453", Constraints{Simulate: true})
+	action.InputAmount = "100000000"
+	action.Metadata = map[string]any{"token_in": common.HexToAddress(guardrailTestUSDC).Hex()}
+	action.Steps = []ActionStep{
+		{
+			StepID:  "approve",
+			Type:    StepTypeApproval,
+			Status:  StepStatusPending,
+			ChainID: "eip155:8453",
+			Target:  common.HexToAddress(guardrailTestUSDC).Hex(),
+			Data:    "0x" + common.Bytes2Hex(approve),
+			Value:   "0",
+		},
+		{
+			StepID:  "swap",
+			Type:    StepTypeSwap,
+			Status:  StepStatusPending,
+			ChainID: "eip155:8453",
+			Target:  spender.Hex(),
+			Data:    "0x",
+			Value:   "0",
+		},
+	}
+	return action
+}
+
+func requireGuardrailRule(t *testing.T, err error, rule string) {
+	t.Helper()
+	typed, ok := clierr.As(err)
+	if !ok || typed.Code != clierr.CodeActionPolicy {
+		t.Fatalf("expected action policy error for %s, got %v", rule, err)
+	}
+	violation, ok := typed.Details.(GuardrailViolation)
+	if !ok || violation.Rule != rule {
+		t.Fatalf("expected %s violation, got %#v", rule, typed.Details)
+	}
+}
+
+func TestCheckGuardrailsAllowsListedSpend(t *testing.T) {
+	spender := common.HexToAddress("0x00000000000000000000000000000000000000bb")
+	action := guardrailTestAction(t, spender)
+	opts := DefaultExecuteOptions()
+	opts.Guardrails = &Guardrails{
+		RequireSimulation: true,
+		AllowedChains:     []string{"eip155:8453"},
+		AllowedSpenders:   []string{spender.Hex()},
+		Tokens:            []TokenGuardrail{{ChainID: "eip155:8453", Token: guardrailTestUSDC, MaxPerTx: big.NewInt(100_000_000)}},
+	}
+	if err := checkGuardrails(nil, &action, opts); err != nil {
+		t.Fatalf("expected action within guardrails, got %v", err)
+	}
+}
+
+func TestCheckGuardrailsRejectsViolations(t *testing.T) {
+	spender := common.HexToAddress("0x00000000000000000000000000000000000000bb")
+	usdc := TokenGuardrail{ChainID: "eip155:8453", Token: guardrailTestUSDC}
+
+	cases := []struct {
+		rule       string
+		guardrails Guardrails
+		simulate   bool
+	}{
+		{"require_simulation", Guardrails{RequireSimulation: true}, false},
+		{"allowed_chains", Guardrails{AllowedChains: []string{"eip155:1"}}, true},
+		{"allowed_spenders", Guardrails{AllowedSpenders: []string{"0x00000000000000000000000000000000000000cc"}}, true},
+		{"allowed_tokens", Guardrails{Tokens: []TokenGuardrail{{ChainID: "eip155:8453", Token: NativeToken}}}, true},
+		{"max_per_tx", Guardrails{Tokens: []TokenGuardrail{{ChainID: usdc.ChainID, Token: usdc.Token, MaxPerTx: big.NewInt(50_000_000)}}}, true},
+	}
+	for _, tc := range cases {
+		action := guardrailTestAction(t, spender)
+		opts := DefaultExecuteOptions()
+		opts.Simulate = tc.simulate
+		guardrails := tc.guardrails
+		opts.Guardrails = &guardrails
+		requireGuardrailRule(t, checkGuardrails(nil, &action, opts), tc.rule)
+	}
+}
+
+func TestExecuteActionRechecksGuardrailsAfterPrepareStep(t *testing.T) {
+	allowed := common.HexToAddress("0x00000000000000000000000000000000000000bb")
+	disallowed := common.HexToAddress("0x00000000000000000000000000000000000000cc")
+	action := guardrailTestAction(t, allowed)
+	for i := range action.Steps {
+		action.Steps[i].RPCURL = "http://127.0.0.1:65535"
+	}
+	opts := DefaultExecuteOptions()
+	opts.Guardrails = &Guardrails{AllowedSpenders: []string{allowed.Hex()}}
+	opts.PrepareStep = func(_ context.Context, action *Action, index int) error {
+		approve, err := policyERC20ABI.Pack("approve", disallowed, big.NewInt(100_000_000))
+		if err != nil {
+			return err
+		}
+		action.Steps[index].Data = "0x" + common.Bytes2Hex(approve)
+		return nil
+	}
+	err := ExecuteAction(context.Background(), nil, &action, staticSigner{}, NewLocalSubmitBackend(staticSigner{}), opts)
+	requireGuardrailRule(t, err, "allowed_spenders")
+	if action.Steps[0].Status != StepStatusFailed {
+		t.Fatalf("expected rewritten step to be marked failed, got %s", action.Steps[0].Status)
+	}
+}
+
+func TestExecuteActionRechecksSpendAfterPrepareStep(t *testing.T) {
+	spender := common.HexToAddress("0x00000000000000000000000000000000000000bb")
+	action := guardrailTestAction(t, spender)
+	action.Steps = action.Steps[1:]
+	action.Steps[0].RPCURL = "http://127.0.0.1:65535"
+	opts := DefaultExecuteOptions()
+	opts.Guardrails = &Guardrails{Tokens: []TokenGuardrail{{ChainID: "eip155:8453", Token: guardrailTestUSDC, MaxPerTx: big.NewInt(100_000_000)}}}
+	opts.PrepareStep = func(_ context.Context, action *Action, _ int) error {
+		action.InputAmount = "150000000"
+		return nil
+	}
+	err := ExecuteAction(context.Background(), nil, &action, staticSigner{}, NewLocalSubmitBackend(staticSigner{}), opts)
+	requireGuardrailRule(t, err, "max_per_tx")
+	if action.Steps[0].Status != StepStatusFailed {
+		t.Fatalf("expected re-quoted step to be marked failed, got %s", action.Steps[0].Status)
+	}
+}
+
+func TestRecordStepSpendBooksBroadcastSpendOnce(t *testing.T) {
+	dir := t.TempDir()
+	store, err := OpenStore(filepath.Join(dir, "actions.db"), filepath.Join(dir, "actions.lock"))
+	if err != nil {
+		t.Fatalf("OpenStore failed: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	spender := common.HexToAddress("0x00000000000000000000000000000000000000bb")
+	action := guardrailTestAction(t, spender)
+	spent := func() string {
+		t.Helper()
+		total, err := store.SpentSince("eip155:8453", guardrailTestUSDC, time.Now().Add(-time.Hour))
+		if err != nil {
+			t.Fatalf("SpentSince failed: %v", err)
+		}
+		return total.String()
+	}
+
+	action.Steps[0].Status = StepStatusConfirmed
+	action.Steps[0].TxHash = "0xapprove"
+	for i := range action.Steps {
+		if err := recordStepSpend(store, &action, i, time.Now()); err != nil {
+			t.Fatalf("recordStepSpend failed: %v", err)
+		}
+	}
+	if got := spent(); got != "0" {
+		t.Fatalf("expected approvals and unsent steps to book nothing, got %s", got)
+	}
+
+	// A swap that was broadcast but then failed still spent its input.
+	action.Steps[1].Status = StepStatusFailed
+	action.Steps[1].TxHash = "0xswap"
+	for range 2 {
+		if err := recordStepSpend(store, &action, 1, time.Now()); err != nil {
+			t.Fatalf("recordStepSpend failed: %v", err)
+		}
+	}
+	if got := spent(); got != "100000000" {
+		t.Fatalf("expected the broadcast swap to be booked once, got %s", got)
+	}
+
+	opts := DefaultExecuteOptions()
+	opts.Guardrails = &Guardrails{Tokens: []TokenGuardrail{{ChainID: "eip155:8453", Token: guardrailTestUSDC, MaxPerDay: big.NewInt(150_000_000)}}}
+	if err := checkGuardrails(store, &action, opts); err != nil {
+		t.Fatalf("expected a resumed action not to count its booked spend twice, got %v", err)
+	}
+}
+
+func TestStepSpendReadsNativeValue(t *testing.T) {
+	action := NewAction("act_native", "bridge", "eip155:8453", Constraints{})
+	action.InputAmount = "1000"
+	action.Metadata = map[string]any{"from_asset_id": "eip155:8453/slip44:60"}
+	action.Steps = []ActionStep{{StepID: "bridge", Type: StepTypeBridge, Target: "0x00000000000000000000000000000000000000bb", Value: "1500"}}
+	chainID, token, amount, ok := stepSpend(&action, 0)
+	if !ok || chainID != "eip155:8453" || token != NativeToken || amount.String() != "1500" {
+		t.Fatalf("expected the step value as native spend, got %s %s %v ok=%v", chainID, token, amount, ok)
+	}
+}
+
+func TestCheckGuardrailsEnforcesRollingDailySpend(t *testing.T) {
+	dir := t.TempDir()
+	store, err := OpenStore(filepath.Join(dir, "actions.db"), filepath.Join(dir, "actions.lock"))
+	if err != nil {
+		t.Fatalf("OpenStore failed: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	spender := common.HexToAddress("0x00000000000000000000000000000000000000bb")
+	opts := DefaultExecuteOptions()
+	opts.Guardrails = &Guardrails{Tokens: []TokenGuardrail{{ChainID: "eip155:8453", Token: guardrailTestUSDC, MaxPerDay: big.NewInt(250_000_000)}}}
+
+	if err := store.RecordSpend("act_old", "eip155:8453", guardrailTestUSDC, big.NewInt(200_000_000), time.Now().Add(-25*time.Hour)); err != nil {
+		t.Fatalf("RecordSpend failed: %v", err)
+	}
+	action := guardrailTestAction(t, spender)
+	if err := checkGuardrails(store, &action, opts); err != nil {
+		t.Fatalf("expected spend older than 24h to be ignored, got %v", err)
+	}
+
+	if err := store.RecordSpend("act_recent", "eip155:8453", guardrailTestUSDC, big.NewInt(200_000_000), time.Now().Add(-time.Hour)); err != nil {
+		t.Fatalf("RecordSpend failed: %v", err)
+	}
+	requireGuardrailRule(t, checkGuardrails(store, &action, opts), "max_per_day")
+}
+
+func TestActionSpendResolvesInputToken(t *testing.T) {
+	cases := map[string]string{
+		"0xEeeeeEeeeEeEeeEeEeEeeEEEeeeeEeeeeeeeEEeE":                    NativeToken,
+		"eip155:8453/slip44:60":                                         NativeToken,
+		"eip155:8453/erc20:0x" + strings.ToUpper(guardrailTestUSDC[2:]): guardrailTestUSDC,
+	}
+	for raw, want := range cases {
+		action := NewAction("act_spend", "bridge", "eip155:8453", Constraints{})
+		action.InputAmount = "1"
+		action.Metadata = map[string]any{"from_asset_id": raw}
+		_, token, _, ok := actionSpend(&action)
+		if !ok || token != want {
+			t.Fatalf("actionSpend(%q) = %q, %v; want %q", raw, token, ok, want)
+		}
+	}
+
+	borrow := NewAction("act_borrow", "lend_borrow", "eip155:8453", Constraints{})
+	borrow.InputAmount = "1"
+	borrow.Metadata = map[string]any{"asset_id": "eip155:8453/erc20:" + guardrailTestUSDC}
+	if _, _, _, ok := actionSpend(&borrow); ok {
+		t.Fatal("did not expect borrows to count as spend")
+	}
+}
@@ -0,0 +1,181 @@
+package execution
+
+import (
+	"bytes"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/registry"
+)
+
+// IntentLimitOrder is the intent type of a resting limit order placed with
+// an off-chain order book.
+const IntentLimitOrder = "limit_order"
+
+// Limit order protocols.
+const (
+	LimitOrderProtocolOneInch = "1inch-lop-v4"
+	LimitOrderProtocolJupiter = "jupiter-trigger"
+)
+
+// Limit order statuses. Filled, cancelled, and expired are final.
+const (
+	LimitOrderStatusPendingSignature = "pending_signature"
+	LimitOrderStatusOpen             = "open"
+	LimitOrderStatusPartiallyFilled  = "partially_filled"
+	LimitOrderStatusFilled           = "filled"
+	LimitOrderStatusCancelled        = "cancelled"
+	LimitOrderStatusExpired          = "expired"
+)
+
+// LimitOrder is a resting order that sells MakingAmount of MakerAsset for
+// at least TakingAmount of TakerAsset. EVM orders are signed by the CLI and
+// posted to the order book; Solana orders are created by an unsigned
+// transaction the maker signs with their own wallet.
+type LimitOrder struct {
+	Protocol            string `json:"protocol"`
+	OrderID             string `json:"order_id,omitempty"`
+	Maker               string `json:"maker"`
+	Receiver            string `json:"receiver,omitempty"`
+	MakerAsset          string `json:"maker_asset"`
+	TakerAsset          string `json:"taker_asset"`
+	MakingAmount        string `json:"making_amount"`
+	TakingAmount        string `json:"taking_amount"`
+	FilledMakingAmount  string `json:"filled_making_amount,omitempty"`
+	ExpiresAt           string `json:"expires_at,omitempty"`
+	Status              string `json:"status"`
+	StatusReason        string `json:"status_reason,omitempty"`
+	Salt                string `json:"salt,omitempty"`
+	MakerTraits         string `json:"maker_traits,omitempty"`
+	Signature           string `json:"signature,omitempty"`
+	UnsignedTransaction string `json:"unsigned_transaction,omitempty"`
+	RequestID           string `json:"request_id,omitempty"`
+	CheckedAt           string `json:"checked_at,omitempty"`
+}
+
+// Final reports whether the order can no longer fill.
+func (o LimitOrder) Final() bool {
+	switch o.Status {
+	case LimitOrderStatusFilled, LimitOrderStatusCancelled, LimitOrderStatusExpired:
+		return true
+	}
+	return false
+}
+
+// Expired reports whether the order's expiry has passed at now.
+func (o LimitOrder) Expired(now time.Time) bool {
+	expiresAt, err := time.Parse(time.RFC3339, strings.TrimSpace(o.ExpiresAt))
+	return err == nil && !now.Before(expiresAt)
+}
+
+// 1inch maker traits flags and bit offsets (Limit Order Protocol v4).
+const (
+	oneInchNoPartialFillsBit    = 255
+	oneInchAllowMultipleFillBit = 254
+	oneInchExpirationOffset     = 80
+	oneInchNonceOffset          = 120
+)
+
+// OneInchMakerTraits packs the maker traits of a public 1inch order with no
+// extension. Partially fillable orders allow multiple fills; all-or-nothing
+// orders use nonce for the bit invalidator.
+func OneInchMakerTraits(expiresAt time.Time, nonce uint64, allowPartialFill bool) *big.Int {
+	traits := new(big.Int)
+	if allowPartialFill {
+		traits.SetBit(traits, oneInchAllowMultipleFillBit, 1)
+	} else {
+		traits.SetBit(traits, oneInchNoPartialFillsBit, 1)
+	}
+	mask40 := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 40), big.NewInt(1))
+	expiration := new(big.Int).And(big.NewInt(expiresAt.Unix()), mask40)
+	traits.Or(traits, new(big.Int).Lsh(expiration, oneInchExpirationOffset))
+	nonceBits := new(big.Int).And(new(big.Int).SetUint64(nonce), mask40)
+	traits.Or(traits, new(big.Int).Lsh(nonceBits, oneInchNonceOffset))
+	return traits
+}
+
+// OneInchLimitOrderDigest returns the EIP-712 hash signed for a 1inch Limit
+// Order Protocol v4 order. The hash is also the order's id in the order book.
+func OneInchLimitOrderDigest(chainID int64, router common.Address, order LimitOrder) ([]byte, error) {
+	receiver := order.Receiver
+	if strings.TrimSpace(receiver) == "" {
+		receiver = common.Address{}.Hex()
+	}
+	typed := apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": {
+				{Name: "name", Type: "string"},
+				{Name: "version", Type: "string"},
+				{Name: "chainId", Type: "uint256"},
+				{Name: "verifyingContract", Type: "address"},
+			},
+			"Order": {
+				{Name: "salt", Type: "uint256"},
+				{Name: "maker", Type: "address"},
+				{Name: "receiver", Type: "address"},
+				{Name: "makerAsset", Type: "address"},
+				{Name: "takerAsset", Type: "address"},
+				{Name: "makingAmount", Type: "uint256"},
+				{Name: "takingAmount", Type: "uint256"},
+				{Name: "makerTraits", Type: "uint256"},
+			},
+		},
+		PrimaryType: "Order",
+		Domain: apitypes.TypedDataDomain{
+			Name:              "1inch Aggregation Router",
+			Version:           "6",
+			ChainId:           math.NewHexOrDecimal256(chainID),
+			VerifyingContract: router.Hex(),
+		},
+		Message: apitypes.TypedDataMessage{
+			"salt":         order.Salt,
+			"maker":        order.Maker,
+			"receiver":     receiver,
+			"makerAsset":   order.MakerAsset,
+			"takerAsset":   order.TakerAsset,
+			"makingAmount": order.MakingAmount,
+			"takingAmount": order.TakingAmount,
+			"makerTraits":  order.MakerTraits,
+		},
+	}
+	hash, _, err := apitypes.TypedDataAndHash(typed)
+	if err != nil {
+		return nil, clierr.Wrap(clierr.CodeActionPlan, "hash limit order typed data", err)
+	}
+	return hash, nil
+}
+
+// validateCancelOrderPolicy pins a cancel step to the canonical 1inch router
+// and to the order hash and maker traits of the action's limit order.
+func validateCancelOrderPolicy(action *Action, step *ActionStep, chainID int64, data []byte) error {
+	if action == nil || action.LimitOrder == nil {
+		return clierr.New(clierr.CodeActionPlan, "cancel step has no limit order")
+	}
+	router, ok := registry.OneInchLimitOrderRouter(chainID)
+	if !ok || !strings.EqualFold(common.HexToAddress(step.Target).Hex(), common.HexToAddress(router).Hex()) {
+		return clierr.New(clierr.CodeActionPlan, "cancel step target does not match the canonical 1inch router")
+	}
+	method := policyOneInchRouterABI.Methods["cancelOrder"]
+	if len(data) < 4 || !bytes.Equal(data[:4], method.ID) {
+		return clierr.New(clierr.CodeActionPlan, "cancel step must call cancelOrder(makerTraits,orderHash)")
+	}
+	args, err := method.Inputs.Unpack(data[4:])
+	if err != nil || len(args) != 2 {
+		return clierr.New(clierr.CodeActionPlan, "cancel step calldata is invalid")
+	}
+	traits, ok := toBigInt(args[0])
+	expectedTraits, okTraits := new(big.Int).SetString(action.LimitOrder.MakerTraits, 10)
+	if !ok || !okTraits || traits.Cmp(expectedTraits) != 0 {
+		return clierr.New(clierr.CodeActionPlan, "cancel step maker traits do not match the order")
+	}
+	orderHash, ok := args[1].([32]byte)
+	if !ok || !strings.EqualFold(common.Hash(orderHash).Hex(), action.LimitOrder.OrderID) {
+		return clierr.New(clierr.CodeActionPlan, "cancel step order hash does not match the order")
+	}
+	return nil
+}
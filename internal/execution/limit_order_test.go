@@ -0,0 +1,83 @@
+package execution
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestOneInchMakerTraitsPacksFlagsExpiryAndNonce(t *testing.T) {
+	expiresAt := time.Unix(1_800_000_000, 0)
+	traits := OneInchMakerTraits(expiresAt, 42, true)
+	if traits.Bit(oneInchAllowMultipleFillBit) != 1 || traits.Bit(oneInchNoPartialFillsBit) != 0 {
+		t.Fatalf("expected a partially fillable order to allow multiple fills, got %x", traits)
+	}
+	mask40 := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 40), big.NewInt(1))
+	expiry := new(big.Int).And(new(big.Int).Rsh(traits, oneInchExpirationOffset), mask40)
+	if expiry.Int64() != expiresAt.Unix() {
+		t.Fatalf("expected expiry %d, got %s", expiresAt.Unix(), expiry)
+	}
+	nonce := new(big.Int).And(new(big.Int).Rsh(traits, oneInchNonceOffset), mask40)
+	if nonce.Int64() != 42 {
+		t.Fatalf("expected nonce 42, got %s", nonce)
+	}
+
+	allOrNothing := OneInchMakerTraits(expiresAt, 42, false)
+	if allOrNothing.Bit(oneInchNoPartialFillsBit) != 1 || allOrNothing.Bit(oneInchAllowMultipleFillBit) != 0 {
+		t.Fatalf("expected an all-or-nothing order to set only the no-partial-fills flag, got %x", allOrNothing)
+	}
+}
+
+func TestOneInchLimitOrderDigestBindsChainAndOrder(t *testing.T) {
+	router := common.HexToAddress("0x111111125421cA6dc452d289314280a0f8842A65")
+	order := LimitOrder{
+		Maker:        "0x00000000000000000000000000000000000000aa",
+		MakerAsset:   "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48",
+		TakerAsset:   "0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2",
+		MakingAmount: "1000000000",
+		TakingAmount: "300000000000000000",
+		Salt:         "12345",
+		MakerTraits:  OneInchMakerTraits(time.Unix(1_800_000_000, 0), 1, true).String(),
+	}
+	digest, err := OneInchLimitOrderDigest(1, router, order)
+	if err != nil {
+		t.Fatalf("digest failed: %v", err)
+	}
+	if len(digest) != 32 {
+		t.Fatalf("expected a 32-byte digest, got %d bytes", len(digest))
+	}
+	other, _ := OneInchLimitOrderDigest(8453, router, order)
+	if common.BytesToHash(other) == common.BytesToHash(digest) {
+		t.Fatal("expected the digest to depend on the chain id")
+	}
+	order.TakingAmount = "300000000000000001"
+	changed, _ := OneInchLimitOrderDigest(1, router, order)
+	if common.BytesToHash(changed) == common.BytesToHash(digest) {
+		t.Fatal("expected the digest to depend on the taking amount")
+	}
+}
+
+func TestValidateCancelOrderPolicyPinsRouterAndOrder(t *testing.T) {
+	orderHash := common.HexToHash("0x01")
+	traits := OneInchMakerTraits(time.Unix(1_800_000_000, 0), 7, true)
+	action := &Action{LimitOrder: &LimitOrder{OrderID: orderHash.Hex(), MakerTraits: traits.String()}}
+	data, err := policyOneInchRouterABI.Pack("cancelOrder", traits, orderHash)
+	if err != nil {
+		t.Fatalf("pack cancelOrder calldata: %v", err)
+	}
+	step := &ActionStep{Type: StepTypeCancelOrder, Target: "0x111111125421cA6dc452d289314280a0f8842A65"}
+	if err := validateStepPolicy(action, step, 1, data, ExecuteOptions{}); err != nil {
+		t.Fatalf("expected cancel of the saved order to pass, got %v", err)
+	}
+
+	wrongRouter := &ActionStep{Type: StepTypeCancelOrder, Target: "0x00000000000000000000000000000000000000cd"}
+	if err := validateStepPolicy(action, wrongRouter, 1, data, ExecuteOptions{}); err == nil {
+		t.Fatal("expected a non-canonical router to fail")
+	}
+	otherOrder, _ := policyOneInchRouterABI.Pack("cancelOrder", traits, common.HexToHash("0x02"))
+	if err := validateStepPolicy(action, step, 1, otherOrder, ExecuteOptions{}); err == nil {
+		t.Fatal("expected a different order hash to fail")
+	}
+}
@@ -0,0 +1,318 @@
+package execution
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/execution/signer"
+	"github.com/ggonzalez94/defi-cli/internal/httpx"
+	"github.com/ggonzalez94/defi-cli/internal/registry"
+)
+
+// Order statuses reported by the CoW Protocol order book.
+const (
+	OrderStatusPresignaturePending = "presignaturePending"
+	OrderStatusOpen                = "open"
+	OrderStatusFulfilled           = "fulfilled"
+	OrderStatusCancelled           = "cancelled"
+	OrderStatusExpired             = "expired"
+)
+
+// OrderDetails is an off-chain intent signed by the sender and settled by
+// solvers. No transaction is sent by the CLI; the step tracks the order until
+// a solver settles it on-chain.
+type OrderDetails struct {
+	OrderBookURL       string `json:"order_book_url"`
+	SellToken          string `json:"sell_token"`
+	BuyToken           string `json:"buy_token"`
+	Receiver           string `json:"receiver"`
+	SellAmount         string `json:"sell_amount"`
+	BuyAmount          string `json:"buy_amount"`
+	ValidTo            uint32 `json:"valid_to"`
+	AppData            string `json:"app_data"`
+	AppDataHash        string `json:"app_data_hash"`
+	FeeAmount          string `json:"fee_amount"`
+	Kind               string `json:"kind"`
+	PartiallyFillable  bool   `json:"partially_fillable"`
+	SellTokenBalance   string `json:"sell_token_balance"`
+	BuyTokenBalance    string `json:"buy_token_balance"`
+	QuoteID            int64  `json:"quote_id,omitempty"`
+	UID                string `json:"uid,omitempty"`
+	Status             string `json:"status,omitempty"`
+	ExecutedSellAmount string `json:"executed_sell_amount,omitempty"`
+	ExecutedBuyAmount  string `json:"executed_buy_amount,omitempty"`
+}
+
+var orderHTTPClient = httpx.New(10*time.Second, 2)
+
+// CowSwapOrderDigest returns the EIP-712 hash signed for a GPv2 order.
+func CowSwapOrderDigest(chainID int64, settlement common.Address, order OrderDetails) ([]byte, error) {
+	typed := apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": {
+				{Name: "name", Type: "string"},
+				{Name: "version", Type: "string"},
+				{Name: "chainId", Type: "uint256"},
+				{Name: "verifyingContract", Type: "address"},
+			},
+			"Order": {
+				{Name: "sellToken", Type: "address"},
+				{Name: "buyToken", Type: "address"},
+				{Name: "receiver", Type: "address"},
+				{Name: "sellAmount", Type: "uint256"},
+				{Name: "buyAmount", Type: "uint256"},
+				{Name: "validTo", Type: "uint32"},
+				{Name: "appData", Type: "bytes32"},
+				{Name: "feeAmount", Type: "uint256"},
+				{Name: "kind", Type: "string"},
+				{Name: "partiallyFillable", Type: "bool"},
+				{Name: "sellTokenBalance", Type: "string"},
+				{Name: "buyTokenBalance", Type: "string"},
+			},
+		},
+		PrimaryType: "Order",
+		Domain: apitypes.TypedDataDomain{
+			Name:              "Gnosis Protocol",
+			Version:           "v2",
+			ChainId:           math.NewHexOrDecimal256(chainID),
+			VerifyingContract: settlement.Hex(),
+		},
+		Message: apitypes.TypedDataMessage{
+			"sellToken":         order.SellToken,
+			"buyToken":          order.BuyToken,
+			"receiver":          order.Receiver,
+			"sellAmount":        order.SellAmount,
+			"buyAmount":         order.BuyAmount,
+			"validTo":           strconv.FormatUint(uint64(order.ValidTo), 10),
+			"appData":           order.AppDataHash,
+			"feeAmount":         order.FeeAmount,
+			"kind":              order.Kind,
+			"partiallyFillable": order.PartiallyFillable,
+			"sellTokenBalance":  order.SellTokenBalance,
+			"buyTokenBalance":   order.BuyTokenBalance,
+		},
+	}
+	hash, _, err := apitypes.TypedDataAndHash(typed)
+	if err != nil {
+		return nil, clierr.Wrap(clierr.CodeActionPlan, "hash order typed data", err)
+	}
+	return hash, nil
+}
+
+// executeOrderStep signs and submits an order step, then tracks it until it
+// is settled. A step that already carries an order UID resumes tracking
+// without re-signing, so timed-out orders can be picked up by resubmitting.
+func executeOrderStep(ctx context.Context, action *Action, step *ActionStep, txSigner signer.Signer, opts ExecuteOptions, persist func() error) error {
+	order := step.Order
+	if order == nil {
+		return clierr.New(clierr.CodeActionPlan, "order step is missing order details")
+	}
+	chainID, err := ParseEVMChainID(step.ChainID)
+	if err != nil {
+		return clierr.Wrap(clierr.CodeActionPlan, "parse order step chain", err)
+	}
+	if strings.TrimSpace(order.UID) == "" {
+		if err := submitOrder(ctx, action, step, chainID, txSigner, opts); err != nil {
+			return err
+		}
+		step.Status = StepStatusSubmitted
+		if err := safePersist(persist); err != nil {
+			return err
+		}
+	}
+	return waitForOrderSettlement(ctx, step, opts, persist)
+}
+
+func submitOrder(ctx context.Context, action *Action, step *ActionStep, chainID int64, txSigner signer.Signer, opts ExecuteOptions) error {
+	order := step.Order
+	if normalizeExecutionBackend(action.ExecutionBackend) != ExecutionBackendLegacyLocal {
+		return clierr.New(clierr.CodeUnsupported, "wallet-backed execution cannot sign off-chain orders; use a local signer")
+	}
+	hashSigner, ok := txSigner.(signer.HashSigner)
+	if !ok {
+		return clierr.New(clierr.CodeUnsupported, "signer cannot sign off-chain orders")
+	}
+	sender := hashSigner.Address()
+	if err := validateOrderPolicy(action, step, chainID, sender, opts); err != nil {
+		return err
+	}
+	if int64(order.ValidTo) <= time.Now().Unix() {
+		return clierr.New(clierr.CodeActionPlan, "order has expired; re-run plan to fetch a fresh quote")
+	}
+
+	digest, err := CowSwapOrderDigest(chainID, common.HexToAddress(step.Target), *order)
+	if err != nil {
+		return err
+	}
+	signature, err := hashSigner.SignHash(digest)
+	if err != nil {
+		return clierr.Wrap(clierr.CodeSigner, "sign order", err)
+	}
+	signature[64] += 27
+
+	body := map[string]any{
+		"sellToken":         order.SellToken,
+		"buyToken":          order.BuyToken,
+		"receiver":          order.Receiver,
+		"sellAmount":        order.SellAmount,
+		"buyAmount":         order.BuyAmount,
+		"validTo":           order.ValidTo,
+		"appData":           order.AppData,
+		"appDataHash":       order.AppDataHash,
+		"feeAmount":         order.FeeAmount,
+		"kind":              order.Kind,
+		"partiallyFillable": order.PartiallyFillable,
+		"sellTokenBalance":  order.SellTokenBalance,
+		"buyTokenBalance":   order.BuyTokenBalance,
+		"signingScheme":     "eip712",
+		"signature":         hexutil.Encode(signature),
+		"from":              sender.Hex(),
+	}
+	if order.QuoteID != 0 {
+		body["quoteId"] = order.QuoteID
+	}
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return clierr.Wrap(clierr.CodeInternal, "encode order", err)
+	}
+	var uid string
+	endpoint := strings.TrimRight(order.OrderBookURL, "/") + "/api/v1/orders"
+	if _, err := httpx.DoBodyJSON(ctx, orderHTTPClient, http.MethodPost, endpoint, payload, nil, &uid); err != nil {
+		return clierr.Wrap(clierr.CodeUnavailable, "submit order", err)
+	}
+	if strings.TrimSpace(uid) == "" {
+		return clierr.New(clierr.CodeUnavailable, "order book returned empty order uid")
+	}
+	order.UID = strings.TrimSpace(uid)
+	order.Status = OrderStatusOpen
+	return nil
+}
+
+// validateOrderPolicy pins the order to the canonical settlement contract and
+// order book, and to the planned sender and recipient.
+func validateOrderPolicy(action *Action, step *ActionStep, chainID int64, sender common.Address, opts ExecuteOptions) error {
+	order := step.Order
+	if persisted := strings.TrimSpace(action.FromAddress); persisted != "" && !strings.EqualFold(common.HexToAddress(persisted).Hex(), sender.Hex()) {
+		return clierr.New(clierr.CodeSigner, "signer does not match planned action sender")
+	}
+	for _, addr := range []string{order.SellToken, order.BuyToken, order.Receiver} {
+		if !common.IsHexAddress(addr) {
+			return clierr.New(clierr.CodeActionPlan, "order has invalid token or receiver address")
+		}
+	}
+	if _, ok := parsePositiveBaseUnits(order.SellAmount); !ok {
+		return clierr.New(clierr.CodeActionPlan, "order has invalid sell amount")
+	}
+	if _, ok := parsePositiveBaseUnits(order.BuyAmount); !ok {
+		return clierr.New(clierr.CodeActionPlan, "order has invalid buy amount")
+	}
+	if strings.TrimSpace(order.AppData) != "" && !strings.EqualFold(crypto.Keccak256Hash([]byte(order.AppData)).Hex(), order.AppDataHash) {
+		return clierr.New(clierr.CodeActionPlan, "order app data hash does not match app data")
+	}
+	if opts.UnsafeProviderTx {
+		return nil
+	}
+	settlement, _, ok := registry.CowSwapContracts(chainID)
+	if !ok {
+		return clierr.New(clierr.CodeActionPlan, "order step has unsupported chain")
+	}
+	if !strings.EqualFold(common.HexToAddress(step.Target).Hex(), common.HexToAddress(settlement).Hex()) {
+		return clierr.New(clierr.CodeActionPlan, "order step target does not match canonical settlement contract")
+	}
+	if !registry.IsAllowedCowSwapOrderBookURL(chainID, order.OrderBookURL) {
+		return clierr.New(clierr.CodeActionPlan, "order book endpoint is not allowed; use --unsafe-provider-tx to override")
+	}
+	expectedReceiver := sender.Hex()
+	if common.IsHexAddress(action.ToAddress) {
+		expectedReceiver = common.HexToAddress(action.ToAddress).Hex()
+	}
+	if !strings.EqualFold(common.HexToAddress(order.Receiver).Hex(), expectedReceiver) {
+		return clierr.New(clierr.CodeActionPlan, "order receiver does not match planned recipient; use --unsafe-provider-tx to override")
+	}
+	return nil
+}
+
+type orderStatusResponse struct {
+	Status             string `json:"status"`
+	ExecutedSellAmount string `json:"executedSellAmount"`
+	ExecutedBuyAmount  string `json:"executedBuyAmount"`
+}
+
+type orderTradeResponse struct {
+	TxHash string `json:"txHash"`
+}
+
+func waitForOrderSettlement(ctx context.Context, step *ActionStep, opts ExecuteOptions, persist func() error) error {
+	order := step.Order
+	waitCtx, cancel := stepWaitContext(ctx, opts)
+	defer cancel()
+	ticker := time.NewTicker(opts.PollInterval)
+	defer ticker.Stop()
+
+	base := strings.TrimRight(order.OrderBookURL, "/")
+	for {
+		var resp orderStatusResponse
+		req, err := http.NewRequestWithContext(waitCtx, http.MethodGet, base+"/api/v1/orders/"+url.PathEscape(order.UID), nil)
+		if err != nil {
+			return clierr.Wrap(clierr.CodeInternal, "build order status request", err)
+		}
+		if _, err := orderHTTPClient.DoJSON(waitCtx, req, &resp); err == nil {
+			order.ExecutedSellAmount = resp.ExecutedSellAmount
+			order.ExecutedBuyAmount = resp.ExecutedBuyAmount
+			if status := strings.TrimSpace(resp.Status); status != "" && status != order.Status {
+				order.Status = status
+				if err := safePersist(persist); err != nil {
+					return err
+				}
+			}
+			switch order.Status {
+			case OrderStatusFulfilled:
+				if txHash, err := queryOrderTradeTx(waitCtx, base, order.UID); err == nil {
+					step.TxHash = txHash
+				}
+				step.Status = StepStatusConfirmed
+				step.Error = ""
+				return safePersist(persist)
+			case OrderStatusCancelled, OrderStatusExpired:
+				return clierr.New(clierr.CodeUnavailable, fmt.Sprintf("order %s was %s before settlement", order.UID, order.Status))
+			}
+		}
+		if waitCtx.Err() != nil {
+			return clierr.Wrap(clierr.CodeActionTimeout, "timed out waiting for order settlement; resubmit to resume tracking", waitCtx.Err())
+		}
+		select {
+		case <-waitCtx.Done():
+			return clierr.Wrap(clierr.CodeActionTimeout, "timed out waiting for order settlement; resubmit to resume tracking", waitCtx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+func queryOrderTradeTx(ctx context.Context, base, uid string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, base+"/api/v1/trades?orderUid="+url.QueryEscape(uid), nil)
+	if err != nil {
+		return "", err
+	}
+	var trades []orderTradeResponse
+	if _, err := orderHTTPClient.DoJSON(ctx, req, &trades); err != nil {
+		return "", err
+	}
+	for _, trade := range trades {
+		if strings.TrimSpace(trade.TxHash) != "" {
+			return strings.TrimSpace(trade.TxHash), nil
+		}
+	}
+	return "", fmt.Errorf("no settlement trade for order %s", uid)
+}
@@ -0,0 +1,183 @@
+package execution
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/execution/signer"
+)
+
+const testOrderPrivateKey = "59c6995e998f97a5a0044966f0945389dc9e86dae88c7a8412f4603b6b78690d"
+
+type fakeOrderBook struct {
+	t         *testing.T
+	submitted map[string]any
+	statuses  []string
+}
+
+func (b *fakeOrderBook) handler(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/api/v1/orders":
+		if err := json.NewDecoder(r.Body).Decode(&b.submitted); err != nil {
+			b.t.Fatalf("decode order: %v", err)
+		}
+		_, _ = w.Write([]byte(`"0xuid"`))
+	case r.Method == http.MethodGet && r.URL.Path == "/api/v1/orders/0xuid":
+		status := b.statuses[0]
+		if len(b.statuses) > 1 {
+			b.statuses = b.statuses[1:]
+		}
+		_, _ = w.Write([]byte(`{"status":"` + status + `","executedSellAmount":"1000000","executedBuyAmount":"401"}`))
+	case r.Method == http.MethodGet && r.URL.Path == "/api/v1/trades":
+		_, _ = w.Write([]byte(`[{"txHash":"0xsettled"}]`))
+	default:
+		b.t.Fatalf("unexpected request %s %s", r.Method, r.URL.String())
+	}
+}
+
+func newTestOrderAction(t *testing.T, orderBookURL string) (*Action, *signer.LocalSigner) {
+	t.Helper()
+	localSigner, err := signer.NewLocalSigner(signer.LocalSignerConfig{PrivateKeyHex: testOrderPrivateKey})
+	if err != nil {
+		t.Fatalf("new local signer: %v", err)
+	}
+	action := NewAction("act_order", "swap", "eip155:1", Constraints{})
+	action.Provider = "cowswap"
+	action.FromAddress = localSigner.Address().Hex()
+	action.Steps = []ActionStep{{
+		StepID:  "cowswap-order",
+		Type:    StepTypeOrder,
+		Status:  StepStatusPending,
+		ChainID: "eip155:1",
+		Target:  "0x9008D19f58AAbD9eD0D60971565AA8510560ab41",
+		Order: &OrderDetails{
+			OrderBookURL:     orderBookURL,
+			SellToken:        "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48",
+			BuyToken:         "0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2",
+			Receiver:         localSigner.Address().Hex(),
+			SellAmount:       "1000000",
+			BuyAmount:        "400",
+			ValidTo:          uint32(time.Now().Add(time.Hour).Unix()),
+			AppData:          "{}",
+			AppDataHash:      crypto.Keccak256Hash([]byte("{}")).Hex(),
+			FeeAmount:        "0",
+			Kind:             "sell",
+			SellTokenBalance: "erc20",
+			BuyTokenBalance:  "erc20",
+		},
+	}}
+	return &action, localSigner
+}
+
+func TestExecuteOrderStepSubmitsSignedOrderAndTracksSettlement(t *testing.T) {
+	book := &fakeOrderBook{t: t, statuses: []string{OrderStatusOpen, OrderStatusFulfilled}}
+	server := httptest.NewServer(http.HandlerFunc(book.handler))
+	defer server.Close()
+	action, localSigner := newTestOrderAction(t, server.URL)
+	step := &action.Steps[0]
+
+	persists := 0
+	opts := ExecuteOptions{PollInterval: time.Millisecond, StepTimeout: time.Second}
+	if err := executeOrderStep(context.Background(), action, step, localSigner, opts, func() error { persists++; return nil }); err != nil {
+		t.Fatalf("executeOrderStep failed: %v", err)
+	}
+	if step.Status != StepStatusConfirmed || step.TxHash != "0xsettled" || step.Order.UID != "0xuid" {
+		t.Fatalf("unexpected step state: %+v order=%+v", step, step.Order)
+	}
+	if step.Order.Status != OrderStatusFulfilled || step.Order.ExecutedBuyAmount != "401" {
+		t.Fatalf("unexpected order tracking state: %+v", step.Order)
+	}
+	if persists < 2 {
+		t.Fatalf("expected uid and status changes to be persisted, got %d persists", persists)
+	}
+
+	if book.submitted["signingScheme"] != "eip712" || book.submitted["from"] != localSigner.Address().Hex() {
+		t.Fatalf("unexpected submitted order: %+v", book.submitted)
+	}
+	signature, err := hexutil.Decode(book.submitted["signature"].(string))
+	if err != nil || len(signature) != 65 || signature[64] < 27 {
+		t.Fatalf("unexpected signature encoding: %x (%v)", signature, err)
+	}
+	digest, err := CowSwapOrderDigest(1, common.HexToAddress(step.Target), *step.Order)
+	if err != nil {
+		t.Fatalf("digest: %v", err)
+	}
+	signature[64] -= 27
+	pub, err := crypto.SigToPub(digest, signature)
+	if err != nil || crypto.PubkeyToAddress(*pub) != localSigner.Address() {
+		t.Fatalf("signature does not recover to signer: %v", err)
+	}
+}
+
+func TestExecuteOrderStepResumesTrackingWithoutResigning(t *testing.T) {
+	book := &fakeOrderBook{t: t, statuses: []string{OrderStatusExpired}}
+	server := httptest.NewServer(http.HandlerFunc(book.handler))
+	defer server.Close()
+	action, _ := newTestOrderAction(t, server.URL)
+	step := &action.Steps[0]
+	step.Status = StepStatusSubmitted
+	step.Order.UID = "0xuid"
+
+	// No signer is needed to resume tracking an already submitted order.
+	err := executeOrderStep(context.Background(), action, step, nil, ExecuteOptions{PollInterval: time.Millisecond, StepTimeout: time.Second}, nil)
+	if err == nil || !strings.Contains(err.Error(), "expired") {
+		t.Fatalf("expected expired order error, got %v", err)
+	}
+	if book.submitted != nil {
+		t.Fatal("expected no order resubmission")
+	}
+}
+
+func TestExecuteActionInterruptKeepsSubmittedOrderResumable(t *testing.T) {
+	interrupt := make(chan struct{})
+	book := &fakeOrderBook{t: t, statuses: []string{OrderStatusOpen}}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		book.handler(w, r)
+		if r.Method == http.MethodGet {
+			select {
+			case <-interrupt:
+			default:
+				close(interrupt)
+			}
+		}
+	}))
+	defer server.Close()
+	action, localSigner := newTestOrderAction(t, server.URL)
+	action.Steps[0].RPCURL = "http://127.0.0.1:65535"
+
+	opts := ExecuteOptions{PollInterval: time.Millisecond, StepTimeout: time.Minute, GasMultiplier: 1.2, Interrupt: interrupt}
+	err := ExecuteAction(context.Background(), nil, action, localSigner, NewLocalSubmitBackend(localSigner), opts)
+	assertClierrCode(t, err, clierr.CodeInterrupted)
+	step := action.Steps[0]
+	if action.Status != ActionStatusInterrupted || step.Status != StepStatusSubmitted || step.Order.UID != "0xuid" {
+		t.Fatalf("expected submitted order checkpoint, got action=%s step=%s uid=%q", action.Status, step.Status, step.Order.UID)
+	}
+}
+
+func TestExecuteOrderStepRejectsWalletBackendAndUnknownOrderBook(t *testing.T) {
+	action, localSigner := newTestOrderAction(t, "http://127.0.0.1:1")
+	action.ExecutionBackend = ExecutionBackendOWS
+	err := executeOrderStep(context.Background(), action, &action.Steps[0], localSigner, ExecuteOptions{}, nil)
+	assertClierrCode(t, err, clierr.CodeUnsupported)
+
+	action, localSigner = newTestOrderAction(t, "https://orders.example.com")
+	err = executeOrderStep(context.Background(), action, &action.Steps[0], localSigner, ExecuteOptions{}, nil)
+	assertClierrCode(t, err, clierr.CodeActionPlan)
+}
+
+func assertClierrCode(t *testing.T, err error, code clierr.Code) {
+	t.Helper()
+	typed, ok := clierr.As(err)
+	if !ok || typed.Code != code {
+		t.Fatalf("expected error code %v, got %v", code, err)
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/ggonzalez94/defi-cli/internal/execution"
 	"github.com/ggonzalez94/defi-cli/internal/id"
 	"github.com/ggonzalez94/defi-cli/internal/registry"
+	"github.com/ggonzalez94/defi-cli/internal/rpcx"
 )
 
 type AaveLendVerb string
@@ -75,7 +76,7 @@ func BuildAaveLendAction(ctx context.Context, req AaveLendRequest) (execution.Ac
 		return execution.Action{}, err
 	}
 
-	client, err := ethclient.DialContext(ctx, rpcURL)
+	client, err := rpcx.DialContext(ctx, rpcURL)
 	if err != nil {
 		return execution.Action{}, clierr.Wrap(clierr.CodeUnavailable, "connect rpc", err)
 	}
@@ -219,7 +220,7 @@ func BuildAaveRewardsClaimAction(ctx context.Context, req AaveRewardsClaimReques
 	if err != nil {
 		return execution.Action{}, clierr.Wrap(clierr.CodeUsage, "resolve rpc url", err)
 	}
-	client, err := ethclient.DialContext(ctx, rpcURL)
+	client, err := rpcx.DialContext(ctx, rpcURL)
 	if err != nil {
 		return execution.Action{}, clierr.Wrap(clierr.CodeUnavailable, "connect rpc", err)
 	}
@@ -299,7 +300,7 @@ func BuildAaveRewardsCompoundAction(ctx context.Context, req AaveRewardsCompound
 	if err != nil {
 		return execution.Action{}, clierr.Wrap(clierr.CodeUsage, "resolve rpc url", err)
 	}
-	client, err := ethclient.DialContext(ctx, rpcURL)
+	client, err := rpcx.DialContext(ctx, rpcURL)
 	if err != nil {
 		return execution.Action{}, clierr.Wrap(clierr.CodeUnavailable, "connect rpc", err)
 	}
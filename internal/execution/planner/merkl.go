@@ -0,0 +1,110 @@
+package planner
+
+import (
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/execution"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/model"
+	"github.com/ggonzalez94/defi-cli/internal/registry"
+)
+
+// MerklRewardsClaimRequest claims Merkl-distributed rewards (Morpho
+// incentives) for Sender. Rewards come from the provider's claimable rewards
+// and carry the cumulative amounts and proofs of the current Merkle root.
+type MerklRewardsClaimRequest struct {
+	Provider string
+	Chain    id.Chain
+	Sender   string
+	Rewards  []model.ClaimableReward
+	Simulate bool
+	RPCURL   string
+}
+
+var merklDistributorABI = mustPlannerABI(registry.MerklDistributorABI)
+
+// BuildMerklRewardsClaimAction builds one Distributor.claim step for every
+// reward. Merkl pays the user itself, so there is no separate recipient.
+func BuildMerklRewardsClaimAction(req MerklRewardsClaimRequest) (execution.Action, error) {
+	sender := strings.TrimSpace(req.Sender)
+	if !common.IsHexAddress(sender) {
+		return execution.Action{}, clierr.New(clierr.CodeUsage, "rewards claim requires sender address")
+	}
+	if len(req.Rewards) == 0 {
+		return execution.Action{}, clierr.New(clierr.CodeUsage, "no claimable rewards to claim")
+	}
+	distributor, ok := registry.MerklDistributor(req.Chain.EVMChainID)
+	if !ok {
+		return execution.Action{}, clierr.New(clierr.CodeUnsupported, "merkl rewards are not supported on this chain")
+	}
+	rpcURL, err := registry.ResolveRPCURL(req.RPCURL, req.Chain.EVMChainID)
+	if err != nil {
+		return execution.Action{}, clierr.Wrap(clierr.CodeUsage, "resolve rpc url", err)
+	}
+
+	user := common.HexToAddress(sender)
+	users := make([]common.Address, 0, len(req.Rewards))
+	tokens := make([]common.Address, 0, len(req.Rewards))
+	amounts := make([]*big.Int, 0, len(req.Rewards))
+	proofs := make([][][32]byte, 0, len(req.Rewards))
+	rewardTokens := make([]string, 0, len(req.Rewards))
+	for _, reward := range req.Rewards {
+		if !strings.EqualFold(reward.Account, sender) {
+			return execution.Action{}, clierr.New(clierr.CodeUsage, "merkl rewards can only be claimed for the sender")
+		}
+		token, err := id.ParseAsset(reward.RewardAssetID, req.Chain)
+		if err != nil || !common.IsHexAddress(token.Address) {
+			return execution.Action{}, clierr.New(clierr.CodeUsage, "invalid reward asset "+reward.RewardAssetID)
+		}
+		amount, ok := new(big.Int).SetString(reward.CumulativeAmount, 10)
+		if !ok || amount.Sign() <= 0 {
+			return execution.Action{}, clierr.New(clierr.CodeUsage, "reward has no cumulative amount to claim")
+		}
+		proof := make([][32]byte, 0, len(reward.Proofs))
+		for _, raw := range reward.Proofs {
+			decoded := common.FromHex(raw)
+			if len(decoded) != 32 {
+				return execution.Action{}, clierr.New(clierr.CodeUsage, "invalid merkl proof "+raw)
+			}
+			proof = append(proof, [32]byte(decoded))
+		}
+		users = append(users, user)
+		tokens = append(tokens, common.HexToAddress(token.Address))
+		amounts = append(amounts, amount)
+		proofs = append(proofs, proof)
+		rewardTokens = append(rewardTokens, common.HexToAddress(token.Address).Hex())
+	}
+	data, err := merklDistributorABI.Pack("claim", users, tokens, amounts, proofs)
+	if err != nil {
+		return execution.Action{}, clierr.Wrap(clierr.CodeInternal, "pack merkl claim calldata", err)
+	}
+
+	provider := strings.TrimSpace(req.Provider)
+	if provider == "" {
+		provider = "morpho"
+	}
+	action := execution.NewAction(execution.NewActionID(), "claim_rewards", req.Chain.CAIP2, execution.Constraints{Simulate: req.Simulate})
+	action.Provider = provider
+	action.FromAddress = user.Hex()
+	action.ToAddress = user.Hex()
+	action.Metadata = map[string]any{
+		"protocol":      provider,
+		"distributor":   common.HexToAddress(distributor).Hex(),
+		"reward_tokens": rewardTokens,
+	}
+	action.Steps = append(action.Steps, execution.ActionStep{
+		StepID:      "merkl-claim-rewards",
+		Type:        execution.StepTypeClaim,
+		Status:      execution.StepStatusPending,
+		ChainID:     req.Chain.CAIP2,
+		RPCURL:      rpcURL,
+		Description: "Claim rewards from Merkl distributor",
+		Target:      common.HexToAddress(distributor).Hex(),
+		Data:        "0x" + common.Bytes2Hex(data),
+		Value:       "0",
+	})
+	return action, nil
+}
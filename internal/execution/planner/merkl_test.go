@@ -0,0 +1,62 @@
+package planner
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/model"
+	"github.com/ggonzalez94/defi-cli/internal/registry"
+)
+
+func TestBuildMerklRewardsClaimAction(t *testing.T) {
+	chain, err := id.ParseChain("base")
+	if err != nil {
+		t.Fatalf("parse chain: %v", err)
+	}
+	reward := model.ClaimableReward{
+		Provider:         "morpho",
+		ChainID:          chain.CAIP2,
+		Account:          "0x00000000000000000000000000000000000000aa",
+		RewardAssetID:    "eip155:8453/erc20:0xbaa5cc21fd487b8fcc2f632f3f4e8d37262a0842",
+		CumulativeAmount: "3000000000000000000",
+		Proofs:           []string{"0x1111111111111111111111111111111111111111111111111111111111111111"},
+	}
+	action, err := BuildMerklRewardsClaimAction(MerklRewardsClaimRequest{
+		Provider: "morpho",
+		Chain:    chain,
+		Sender:   "0x00000000000000000000000000000000000000AA",
+		Rewards:  []model.ClaimableReward{reward},
+		Simulate: true,
+		RPCURL:   "http://127.0.0.1:8545",
+	})
+	if err != nil {
+		t.Fatalf("BuildMerklRewardsClaimAction failed: %v", err)
+	}
+	if action.IntentType != "claim_rewards" || len(action.Steps) != 1 {
+		t.Fatalf("unexpected action: %+v", action)
+	}
+	distributor, _ := registry.MerklDistributor(chain.EVMChainID)
+	step := action.Steps[0]
+	if !strings.EqualFold(step.Target, distributor) {
+		t.Fatalf("expected distributor target %s, got %s", distributor, step.Target)
+	}
+	args, err := merklDistributorABI.Methods["claim"].Inputs.Unpack(common.FromHex(step.Data)[4:])
+	if err != nil {
+		t.Fatalf("unpack claim calldata: %v", err)
+	}
+	if len(args) != 4 {
+		t.Fatalf("expected 4 claim arguments, got %d", len(args))
+	}
+
+	_, err = BuildMerklRewardsClaimAction(MerklRewardsClaimRequest{
+		Chain:   chain,
+		Sender:  "0x00000000000000000000000000000000000000BB",
+		Rewards: []model.ClaimableReward{reward},
+		RPCURL:  "http://127.0.0.1:8545",
+	})
+	if err == nil || !strings.Contains(err.Error(), "only be claimed for the sender") {
+		t.Fatalf("expected sender mismatch error, got %v", err)
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/ggonzalez94/defi-cli/internal/execution"
 	"github.com/ggonzalez94/defi-cli/internal/id"
 	"github.com/ggonzalez94/defi-cli/internal/registry"
+	"github.com/ggonzalez94/defi-cli/internal/rpcx"
 )
 
 var plannerMC3Addr = common.HexToAddress("0xcA11bde05977b3631167028862bE2a173976CA11")
@@ -68,7 +69,7 @@ func BuildMoonwellLendAction(ctx context.Context, req MoonwellLendRequest) (exec
 		return execution.Action{}, clierr.Wrap(clierr.CodeUsage, "resolve rpc url", err)
 	}
 
-	client, err := ethclient.DialContext(ctx, rpcURL)
+	client, err := rpcx.DialContext(ctx, rpcURL)
 	if err != nil {
 		return execution.Action{}, clierr.Wrap(clierr.CodeUnavailable, "connect rpc", err)
 	}
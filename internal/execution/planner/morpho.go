@@ -11,12 +11,12 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/ethclient"
 	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
 	"github.com/ggonzalez94/defi-cli/internal/execution"
 	"github.com/ggonzalez94/defi-cli/internal/httpx"
 	"github.com/ggonzalez94/defi-cli/internal/id"
 	"github.com/ggonzalez94/defi-cli/internal/registry"
+	"github.com/ggonzalez94/defi-cli/internal/rpcx"
 )
 
 const defaultMorphoGraphQLEndpoint = registry.MorphoGraphQLEndpoint
@@ -148,7 +148,7 @@ func BuildMorphoLendAction(ctx context.Context, req MorphoLendRequest) (executio
 		LLTV:            lltv,
 	}
 
-	client, err := ethclient.DialContext(ctx, rpcURL)
+	client, err := rpcx.DialContext(ctx, rpcURL)
 	if err != nil {
 		return execution.Action{}, clierr.Wrap(clierr.CodeUnavailable, "connect rpc", err)
 	}
@@ -9,12 +9,12 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/ethclient"
 	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
 	"github.com/ggonzalez94/defi-cli/internal/execution"
 	"github.com/ggonzalez94/defi-cli/internal/httpx"
 	"github.com/ggonzalez94/defi-cli/internal/id"
 	"github.com/ggonzalez94/defi-cli/internal/registry"
+	"github.com/ggonzalez94/defi-cli/internal/rpcx"
 )
 
 const morphoVaultByAddressQuery = `query VaultByAddress($address:String!,$chainId:Int!){
@@ -142,7 +142,7 @@ func BuildMorphoVaultYieldAction(ctx context.Context, req MorphoVaultYieldReques
 		return execution.Action{}, clierr.New(clierr.CodeUsage, "selected morpho vault asset does not match --asset")
 	}
 
-	client, err := ethclient.DialContext(ctx, rpcURL)
+	client, err := rpcx.DialContext(ctx, rpcURL)
 	if err != nil {
 		return execution.Action{}, clierr.Wrap(clierr.CodeUnavailable, "connect rpc", err)
 	}
@@ -17,11 +17,15 @@ var (
 	policyUniswapV3RouterABI = mustPolicyABI(registry.UniswapV3RouterABI)
 	policyTempoDEXABI        = mustPolicyABI(registry.TempoStablecoinDEXABI)
 
+	policyMessageTransmitterABI = mustPolicyABI(registry.CCTPMessageTransmitterV2ABI)
+	policyOneInchRouterABI      = mustPolicyABI(registry.OneInchLimitOrderRouterABI)
+
 	policyApproveSelector     = policyERC20ABI.Methods["approve"].ID
 	policyTransferSelector    = policyERC20ABI.Methods["transfer"].ID
 	policyUniswapV3SwapMethod = policyUniswapV3RouterABI.Methods["exactInputSingle"].ID
 	policyTempoSwapExactIn    = policyTempoDEXABI.Methods["swapExactAmountIn"].ID
 	policyTempoSwapExactOut   = policyTempoDEXABI.Methods["swapExactAmountOut"].ID
+	policyReceiveMessage      = policyMessageTransmitterABI.Methods["receiveMessage"].ID
 )
 
 func validateStepPolicy(action *Action, step *ActionStep, chainID int64, data []byte, opts ExecuteOptions) error {
@@ -37,19 +41,23 @@ func validateStepPolicy(action *Action, step *ActionStep, chainID int64, data []
 
 	switch step.Type {
 	case StepTypeApproval:
-		return validateApprovalPolicy(action, data, opts)
+		return validateApprovalPolicy(action, step, data, opts)
 	case StepTypeTransfer:
 		return validateTransferPolicy(action, step, data)
 	case StepTypeSwap:
 		return validateSwapPolicy(action, step, chainID, data, opts)
 	case StepTypeBridge:
 		return validateBridgePolicy(action, step, chainID, opts)
+	case StepTypeBridgeReceive:
+		return validateBridgeReceivePolicy(step, chainID, data)
+	case StepTypeCancelOrder:
+		return validateCancelOrderPolicy(action, step, chainID, data)
 	default:
 		return nil
 	}
 }
 
-func validateApprovalPolicy(action *Action, data []byte, opts ExecuteOptions) error {
+func validateApprovalPolicy(action *Action, step *ActionStep, data []byte, opts ExecuteOptions) error {
 	if len(data) < 4 || !bytes.Equal(data[:4], policyApproveSelector) {
 		return clierr.New(clierr.CodeActionPlan, "approval step must use ERC20 approve(spender,amount)")
 	}
@@ -71,7 +79,7 @@ func validateApprovalPolicy(action *Action, data []byte, opts ExecuteOptions) er
 	if action == nil {
 		return clierr.New(clierr.CodeActionPlan, "cannot validate approval bounds without action context")
 	}
-	requested, ok := parsePositiveBaseUnits(action.InputAmount)
+	requested, ok := parsePositiveBaseUnits(stepInputAmount(action, step))
 	if !ok {
 		return clierr.New(clierr.CodeActionPlan, "cannot validate approval bounds for non-numeric input amount; use --allow-max-approval to override")
 	}
@@ -136,7 +144,7 @@ func validateSwapPolicy(action *Action, step *ActionStep, chainID int64, data []
 	if action == nil {
 		return nil
 	}
-	switch strings.ToLower(strings.TrimSpace(action.Provider)) {
+	switch strings.ToLower(stepProvider(action, step)) {
 	case "taikoswap":
 		if len(data) < 4 || !bytes.Equal(data[:4], policyUniswapV3SwapMethod) {
 			return clierr.New(clierr.CodeActionPlan, "taikoswap swap step must call exactInputSingle")
@@ -170,6 +178,28 @@ func validateSwapPolicy(action *Action, step *ActionStep, chainID int64, data []
 		if !strings.EqualFold(common.HexToAddress(step.Target).Hex(), expectedDEX) {
 			return clierr.New(clierr.CodeActionPlan, "tempo swap step target does not match canonical stablecoin dex")
 		}
+	case "paraswap":
+		if opts.UnsafeProviderTx {
+			return nil
+		}
+		augustus, ok := registry.ParaSwapAugustus(chainID)
+		if !ok {
+			return clierr.New(clierr.CodeActionPlan, "paraswap swap step has unsupported chain")
+		}
+		if !strings.EqualFold(common.HexToAddress(step.Target).Hex(), common.HexToAddress(augustus).Hex()) {
+			return clierr.New(clierr.CodeActionPlan, "paraswap swap step target is not the Augustus router; use --unsafe-provider-tx to override")
+		}
+	case "odos":
+		if opts.UnsafeProviderTx {
+			return nil
+		}
+		router, ok := registry.OdosRouter(chainID)
+		if !ok {
+			return clierr.New(clierr.CodeActionPlan, "odos swap step has unsupported chain")
+		}
+		if !strings.EqualFold(common.HexToAddress(step.Target).Hex(), common.HexToAddress(router).Hex()) {
+			return clierr.New(clierr.CodeActionPlan, "odos swap step target is not the Odos router; use --unsafe-provider-tx to override")
+		}
 	}
 	return nil
 }
@@ -274,7 +304,7 @@ func validateBridgePolicy(action *Action, step *ActionStep, chainID int64, opts
 	if provider == "" && action != nil {
 		provider = strings.ToLower(strings.TrimSpace(action.Provider))
 	}
-	if provider != "lifi" && provider != "across" {
+	if provider != "lifi" && provider != "across" && provider != "wormhole" && provider != "cctp" {
 		return clierr.New(clierr.CodeActionPlan, "bridge step has unknown settlement provider; use --unsafe-provider-tx to override")
 	}
 	if action != nil && strings.TrimSpace(action.Provider) != "" && !strings.EqualFold(strings.TrimSpace(action.Provider), provider) {
@@ -294,6 +324,19 @@ func validateBridgePolicy(action *Action, step *ActionStep, chainID int64, opts
 	return nil
 }
 
+// validateBridgeReceivePolicy pins destination mints to the CCTP message
+// transmitter. The calldata is built locally from Circle's attestation, so
+// --unsafe-provider-tx does not apply.
+func validateBridgeReceivePolicy(step *ActionStep, chainID int64, data []byte) error {
+	if len(data) < 4 || !bytes.Equal(data[:4], policyReceiveMessage) {
+		return clierr.New(clierr.CodeActionPlan, "bridge receive step must call receiveMessage(message,attestation)")
+	}
+	if _, ok := registry.CCTP(chainID); !ok || !strings.EqualFold(common.HexToAddress(step.Target).Hex(), common.HexToAddress(registry.CCTPMessageTransmitterV2).Hex()) {
+		return clierr.New(clierr.CodeActionPlan, "bridge receive step target is not the CCTP message transmitter")
+	}
+	return nil
+}
+
 func parsePositiveBaseUnits(value string) (*big.Int, bool) {
 	v := strings.TrimSpace(value)
 	if v == "" {
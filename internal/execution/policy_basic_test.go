@@ -51,6 +51,24 @@ func TestValidateApprovalPolicyAllowsOverride(t *testing.T) {
 	}
 }
 
+func TestValidateApprovalPolicyUsesRouteLegInput(t *testing.T) {
+	data, err := policyERC20ABI.Pack("approve", common.HexToAddress("0x00000000000000000000000000000000000000ab"), big.NewInt(150))
+	if err != nil {
+		t.Fatalf("pack approval calldata: %v", err)
+	}
+	action := &Action{IntentType: "route", InputAmount: "100"}
+	steps := []ActionStep{{Type: StepTypeApproval, Target: "0x00000000000000000000000000000000000000cd"}}
+	TagRouteLeg(steps, RouteLegSwap, "odos", "150")
+
+	if err := validateStepPolicy(action, &steps[0], 1, data, ExecuteOptions{}); err != nil {
+		t.Fatalf("expected approval bounded by the swap leg input to pass, got err=%v", err)
+	}
+	TagRouteLeg(steps, RouteLegSwap, "odos", "149")
+	if err := validateStepPolicy(action, &steps[0], 1, data, ExecuteOptions{}); err == nil {
+		t.Fatal("expected approval above the swap leg input to fail")
+	}
+}
+
 func TestValidateTransferPolicyMatchesAction(t *testing.T) {
 	data, err := policyERC20ABI.Pack("transfer", common.HexToAddress("0x00000000000000000000000000000000000000ab"), big.NewInt(100))
 	if err != nil {
@@ -166,6 +184,33 @@ func TestValidateSwapPolicyTempoDEX(t *testing.T) {
 	}
 }
 
+func TestValidateSwapPolicyParaSwapRouter(t *testing.T) {
+	action := &Action{Provider: "paraswap"}
+	step := &ActionStep{
+		Type:   StepTypeSwap,
+		Target: "0x00000000000000000000000000000000000000cd",
+	}
+	if err := validateStepPolicy(action, step, 1, []byte{0x01}, ExecuteOptions{}); err == nil {
+		t.Fatal("expected paraswap router mismatch to fail")
+	}
+	if err := validateStepPolicy(action, step, 1, []byte{0x01}, ExecuteOptions{UnsafeProviderTx: true}); err != nil {
+		t.Fatalf("expected --unsafe-provider-tx to bypass router check, got %v", err)
+	}
+	step.Target = "0x6A000F20005980200259B80c5102003040001068"
+	if err := validateStepPolicy(action, step, 8453, []byte{0x01}, ExecuteOptions{}); err != nil {
+		t.Fatalf("expected canonical augustus router to pass, got %v", err)
+	}
+}
+
+func TestValidateSwapPolicyUsesRouteLegProvider(t *testing.T) {
+	action := &Action{IntentType: "route", Provider: ""}
+	steps := []ActionStep{{Type: StepTypeSwap, Target: "0x00000000000000000000000000000000000000cd"}}
+	TagRouteLeg(steps, RouteLegSwap, "paraswap", "100")
+	if err := validateStepPolicy(action, &steps[0], 1, []byte{0x01}, ExecuteOptions{}); err == nil {
+		t.Fatal("expected paraswap router check to apply to the route swap leg")
+	}
+}
+
 func TestValidateTempoSwapBatchedCallsPass(t *testing.T) {
 	// Build a valid approve + swap batched step.
 	dexAddr := "0xdec0000000000000000000000000000000000000"
@@ -529,3 +574,21 @@ func TestValidateBridgePolicySkipsTargetCheckOnUncoveredChain(t *testing.T) {
 		t.Fatalf("expected uncovered chain to skip target check, got err=%v", err)
 	}
 }
+
+func TestValidateSwapPolicyOdosRouter(t *testing.T) {
+	action := &Action{Provider: "odos"}
+	step := &ActionStep{
+		Type:   StepTypeSwap,
+		Target: "0x00000000000000000000000000000000000000cd",
+	}
+	if err := validateStepPolicy(action, step, 1, []byte{0x01}, ExecuteOptions{}); err == nil {
+		t.Fatal("expected odos router mismatch to fail")
+	}
+	step.Target = "0xCf5540fFFCdC3d510B18bFcA6d2b9987b0772559"
+	if err := validateStepPolicy(action, step, 8453, []byte{0x01}, ExecuteOptions{}); err != nil {
+		t.Fatalf("expected canonical odos router to pass, got %v", err)
+	}
+	if err := validateStepPolicy(action, step, 100, []byte{0x01}, ExecuteOptions{}); err == nil {
+		t.Fatal("expected unsupported odos chain to fail")
+	}
+}
@@ -0,0 +1,47 @@
+package execution
+
+// PostStateStatus reports which source confirmed the post-execution state.
+type PostStateStatus string
+
+const (
+	// PostStateProviderConfirmed means the provider API already reflects the execution.
+	PostStateProviderConfirmed PostStateStatus = "provider_confirmed"
+	// PostStateOnchainVerified means the provider API lagged or has no position
+	// data, and the change was verified from on-chain balances instead.
+	PostStateOnchainVerified PostStateStatus = "onchain_verified"
+	// PostStateUnverified means no source showed a change yet.
+	PostStateUnverified PostStateStatus = "unverified"
+)
+
+// PostState is the account state re-read right after an action completes.
+// Positions are only included once the provider reflects the execution, so
+// callers never receive provider data known to be stale.
+type PostState struct {
+	Status    PostStateStatus     `json:"status"`
+	Attempts  int                 `json:"attempts"`
+	Balances  []PostStateBalance  `json:"balances,omitempty"`
+	Positions []PostStatePosition `json:"positions,omitempty"`
+	FetchedAt string              `json:"fetched_at"`
+}
+
+// PostStateBalance is an on-chain wallet balance read before and after execution.
+type PostStateBalance struct {
+	AssetID         string `json:"asset_id"`
+	Symbol          string `json:"symbol,omitempty"`
+	Decimals        int    `json:"decimals"`
+	BeforeBaseUnits string `json:"before_base_units,omitempty"`
+	AfterBaseUnits  string `json:"after_base_units"`
+	AfterDecimal    string `json:"after_decimal"`
+}
+
+// PostStatePosition is a lending position reported by the provider after execution.
+type PostStatePosition struct {
+	Provider         string  `json:"provider"`
+	Protocol         string  `json:"protocol"`
+	PositionType     string  `json:"position_type"`
+	AssetID          string  `json:"asset_id"`
+	ProviderNativeID string  `json:"provider_native_id,omitempty"`
+	AmountBaseUnits  string  `json:"amount_base_units"`
+	AmountDecimal    string  `json:"amount_decimal"`
+	AmountUSD        float64 `json:"amount_usd"`
+}
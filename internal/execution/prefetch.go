@@ -0,0 +1,170 @@
+package execution
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ggonzalez94/defi-cli/internal/clock"
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/rpcx"
+)
+
+// PrefetchMaxAge bounds how old prefetched state may be before submit reads
+// the chain again.
+const PrefetchMaxAge = 2 * time.Minute
+
+// Prefetch is source-chain state read while planning so submit can skip the
+// reads. Submit uses it for steps on the same chain and RPC URL while it is
+// younger than PrefetchMaxAge, unless --fresh is set. Nonce is only recorded
+// when the sender had no pending transactions, so it can never be ahead of
+// the chain; a stale nonce is re-read when the broadcast is rejected.
+// Allowances are not prefetched: approval steps always run.
+type Prefetch struct {
+	FetchedAt  string  `json:"fetched_at"`
+	ChainID    string  `json:"chain_id"`
+	RPCURL     string  `json:"rpc_url"`
+	Sender     string  `json:"sender"`
+	Nonce      *uint64 `json:"nonce,omitempty"`
+	BaseFeeWei string  `json:"base_fee_wei,omitempty"`
+	TipCapWei  string  `json:"tip_cap_wei,omitempty"`
+}
+
+// PrefetchAction reads the state submit needs for the action's source-chain
+// steps concurrently and stores it on the action. Actions without an EVM
+// sender or source-chain steps are left unchanged. Chain ID and the latest
+// header are required; the other reads are dropped individually on failure.
+func PrefetchAction(ctx context.Context, action *Action) error {
+	if action == nil || !common.IsHexAddress(action.FromAddress) {
+		return nil
+	}
+	rpcURL := ""
+	for _, step := range action.Steps {
+		if strings.EqualFold(step.ChainID, action.ChainID) && strings.TrimSpace(step.RPCURL) != "" {
+			rpcURL = strings.TrimSpace(step.RPCURL)
+			break
+		}
+	}
+	if rpcURL == "" {
+		return nil
+	}
+	client, err := rpcx.DialContext(ctx, rpcURL)
+	if err != nil {
+		return clierr.Wrap(clierr.CodeUnavailable, "connect rpc", err)
+	}
+	defer client.Close()
+
+	sender := common.HexToAddress(action.FromAddress)
+	out := &Prefetch{ChainID: action.ChainID, RPCURL: rpcURL, Sender: sender.Hex()}
+
+	var (
+		wg                    sync.WaitGroup
+		chainErr, headerErr   error
+		pending, latest       uint64
+		pendingErr, latestErr error
+	)
+	run := func(fn func()) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			fn()
+		}()
+	}
+	run(func() {
+		chainID, err := client.ChainID(ctx)
+		if err == nil && !strings.EqualFold(fmt.Sprintf("eip155:%d", chainID.Int64()), action.ChainID) {
+			err = fmt.Errorf("rpc serves eip155:%d, action is on %s", chainID.Int64(), action.ChainID)
+		}
+		chainErr = err
+	})
+	run(func() {
+		header, err := client.HeaderByNumber(ctx, nil)
+		if err == nil && header.BaseFee != nil {
+			out.BaseFeeWei = header.BaseFee.String()
+		}
+		headerErr = err
+	})
+	run(func() {
+		if tip, err := client.SuggestGasTipCap(ctx); err == nil {
+			out.TipCapWei = tip.String()
+		}
+	})
+	run(func() { pending, pendingErr = client.PendingNonceAt(ctx, sender) })
+	run(func() { latest, latestErr = client.NonceAt(ctx, sender, nil) })
+	wg.Wait()
+
+	if chainErr != nil {
+		return clierr.Wrap(clierr.CodeUnavailable, "read chain id", chainErr)
+	}
+	if headerErr != nil {
+		return clierr.Wrap(clierr.CodeUnavailable, "fetch latest header", headerErr)
+	}
+	if pendingErr == nil && latestErr == nil && pending == latest {
+		out.Nonce = &pending
+	}
+	out.FetchedAt = clock.Now().UTC().Format(time.RFC3339)
+	action.Prefetch = out
+	return nil
+}
+
+// usablePrefetch returns the action's prefetched state when it applies to
+// step: same chain, RPC URL and sender, not older than PrefetchMaxAge, and
+// --fresh not set.
+func usablePrefetch(action *Action, step *ActionStep, sender common.Address, opts ExecuteOptions) *Prefetch {
+	if opts.Fresh || action == nil || action.Prefetch == nil || step == nil {
+		return nil
+	}
+	prefetch := action.Prefetch
+	if !strings.EqualFold(prefetch.ChainID, step.ChainID) || prefetch.RPCURL != strings.TrimSpace(step.RPCURL) {
+		return nil
+	}
+	if !common.IsHexAddress(prefetch.Sender) || common.HexToAddress(prefetch.Sender) != sender {
+		return nil
+	}
+	fetchedAt, err := time.Parse(time.RFC3339, prefetch.FetchedAt)
+	if err != nil || clock.Now().Sub(fetchedAt) > PrefetchMaxAge {
+		return nil
+	}
+	return prefetch
+}
+
+// chainIDBig parses the EIP-155 chain ID the prefetch was verified against.
+func (p *Prefetch) chainIDBig() (*big.Int, bool) {
+	id, ok := new(big.Int).SetString(strings.TrimPrefix(strings.ToLower(p.ChainID), "eip155:"), 10)
+	return id, ok && id.Sign() > 0
+}
+
+// firstBroadcastNonce returns the prefetched nonce while no step of the
+// action has been broadcast yet; later steps read the chain.
+func (p *Prefetch) firstBroadcastNonce(action *Action) (uint64, bool) {
+	if p.Nonce == nil {
+		return 0, false
+	}
+	for _, step := range action.Steps {
+		if strings.TrimSpace(step.TxHash) != "" {
+			return 0, false
+		}
+	}
+	return *p.Nonce, true
+}
+
+func parseWei(value string) (*big.Int, bool) {
+	if strings.TrimSpace(value) == "" {
+		return nil, false
+	}
+	return new(big.Int).SetString(strings.TrimSpace(value), 10)
+}
+
+// isStaleNonceError reports whether a broadcast was rejected because its
+// nonce was already used.
+func isStaleNonceError(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "nonce too low") || strings.Contains(msg, "already known") || strings.Contains(msg, "replacement transaction underpriced")
+}
@@ -0,0 +1,184 @@
+package execution
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ggonzalez94/defi-cli/internal/clock"
+)
+
+const (
+	prefetchTestSender  = "0x00000000000000000000000000000000000000aa"
+	prefetchTestToken   = "0x00000000000000000000000000000000000000c0"
+	prefetchTestSpender = "0x00000000000000000000000000000000000000d0"
+)
+
+// newPrefetchRPCServer answers the reads PrefetchAction makes. pendingNonce
+// differs from the mined nonce of 5 when the sender has transactions in flight.
+func newPrefetchRPCServer(t *testing.T, pendingNonce uint64) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req estimateRPCRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		switch req.Method {
+		case "eth_chainId":
+			writeEstimateRPCResult(t, w, req.ID, "0x1")
+		case "eth_getBlockByNumber":
+			writeEstimateRPCResult(t, w, req.ID, map[string]any{
+				"parentHash":       common.Hash{}.Hex(),
+				"sha3Uncles":       common.Hash{}.Hex(),
+				"miner":            common.Address{}.Hex(),
+				"stateRoot":        common.Hash{}.Hex(),
+				"transactionsRoot": common.Hash{}.Hex(),
+				"receiptsRoot":     common.Hash{}.Hex(),
+				"logsBloom":        fmt.Sprintf("0x%0512x", 0),
+				"difficulty":       "0x0",
+				"number":           "0x10",
+				"gasLimit":         "0x1c9c380",
+				"gasUsed":          "0x0",
+				"timestamp":        "0x0",
+				"extraData":        "0x",
+				"baseFeePerGas":    "0x3b9aca00",
+			})
+		case "eth_maxPriorityFeePerGas":
+			writeEstimateRPCResult(t, w, req.ID, "0x77359400")
+		case "eth_getTransactionCount":
+			var tag string
+			_ = json.Unmarshal(req.Params[1], &tag)
+			if tag == "pending" {
+				writeEstimateRPCResult(t, w, req.ID, fmt.Sprintf("0x%x", pendingNonce))
+				return
+			}
+			writeEstimateRPCResult(t, w, req.ID, "0x5")
+		case "eth_call":
+			var call struct {
+				Input string `json:"input"`
+				Data  string `json:"data"`
+			}
+			_ = json.Unmarshal(req.Params[0], &call)
+			input := call.Input + call.Data
+			if strings.Contains(input, "dd62ed3e") || strings.Contains(input, "313ce567") {
+				t.Errorf("unexpected token read during prefetch: %s", input)
+			}
+			writeEstimateRPCError(w, req.ID, -32000, "execution reverted")
+		default:
+			writeEstimateRPCError(w, req.ID, -32601, "method not supported in test: "+req.Method)
+		}
+	}))
+}
+
+func prefetchTestAction(rpcURL string) *Action {
+	approve, err := policyERC20ABI.Pack("approve", common.HexToAddress(prefetchTestSpender), big.NewInt(500))
+	if err != nil {
+		panic(err)
+	}
+	return &Action{
+		ActionID:    "act_prefetch",
+		ChainID:     "eip155:1",
+		FromAddress: prefetchTestSender,
+		Steps: []ActionStep{
+			{StepID: "approve", Type: StepTypeApproval, ChainID: "eip155:1", RPCURL: rpcURL, Target: prefetchTestToken, Data: "0x" + common.Bytes2Hex(approve), Value: "0"},
+			{StepID: "swap", Type: StepTypeSwap, ChainID: "eip155:1", RPCURL: rpcURL, Target: prefetchTestSpender, Data: "0x", Value: "0"},
+		},
+	}
+}
+
+func TestPrefetchActionEmbedsSubmitState(t *testing.T) {
+	server := newPrefetchRPCServer(t, 5)
+	defer server.Close()
+	action := prefetchTestAction(server.URL)
+
+	if err := PrefetchAction(context.Background(), action); err != nil {
+		t.Fatalf("PrefetchAction failed: %v", err)
+	}
+	got := action.Prefetch
+	if got == nil {
+		t.Fatal("expected prefetched state on the action")
+	}
+	if got.ChainID != "eip155:1" || got.RPCURL != server.URL || got.Sender != common.HexToAddress(prefetchTestSender).Hex() {
+		t.Fatalf("unexpected prefetch scope: %+v", got)
+	}
+	if got.Nonce == nil || *got.Nonce != 5 {
+		t.Fatalf("expected nonce 5, got %v", got.Nonce)
+	}
+	if got.BaseFeeWei != "1000000000" || got.TipCapWei != "2000000000" {
+		t.Fatalf("unexpected fees: base=%s tip=%s", got.BaseFeeWei, got.TipCapWei)
+	}
+}
+
+func TestPrefetchActionSkipsNonceWithPendingTransactions(t *testing.T) {
+	server := newPrefetchRPCServer(t, 7)
+	defer server.Close()
+	action := prefetchTestAction(server.URL)
+
+	if err := PrefetchAction(context.Background(), action); err != nil {
+		t.Fatalf("PrefetchAction failed: %v", err)
+	}
+	if action.Prefetch.Nonce != nil {
+		t.Fatalf("expected no nonce while transactions are pending, got %d", *action.Prefetch.Nonce)
+	}
+}
+
+func TestUsablePrefetchHonorsScopeAgeAndFresh(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	clock.Freeze(now)
+	defer clock.Reset()
+
+	nonce := uint64(5)
+	action := prefetchTestAction("https://rpc.example")
+	action.Prefetch = &Prefetch{
+		FetchedAt:  now.Add(-time.Minute).Format(time.RFC3339),
+		ChainID:    "eip155:1",
+		RPCURL:     "https://rpc.example",
+		Sender:     common.HexToAddress(prefetchTestSender).Hex(),
+		Nonce:      &nonce,
+		BaseFeeWei: "1000000000",
+		TipCapWei:  "2000000000",
+	}
+	sender := common.HexToAddress(prefetchTestSender)
+	step := &action.Steps[0]
+
+	prefetch := usablePrefetch(action, step, sender, ExecuteOptions{})
+	if prefetch == nil {
+		t.Fatal("expected the prefetch to be usable")
+	}
+	if got, ok := prefetch.firstBroadcastNonce(action); !ok || got != 5 {
+		t.Fatalf("expected prefetched nonce 5, got %d %v", got, ok)
+	}
+	// Fees come from the prefetch without touching the RPC client.
+	tip, base, err := resolveStepFees(context.Background(), nil, prefetch, ExecuteOptions{})
+	if err != nil || tip.String() != "2000000000" || base.String() != "1000000000" {
+		t.Fatalf("unexpected fees tip=%v base=%v err=%v", tip, base, err)
+	}
+
+	if usablePrefetch(action, step, sender, ExecuteOptions{Fresh: true}) != nil {
+		t.Fatal("expected --fresh to ignore the prefetch")
+	}
+	if usablePrefetch(action, step, common.HexToAddress("0x00000000000000000000000000000000000000bb"), ExecuteOptions{}) != nil {
+		t.Fatal("expected another sender to ignore the prefetch")
+	}
+	other := *step
+	other.RPCURL = "https://other.example"
+	if usablePrefetch(action, &other, sender, ExecuteOptions{}) != nil {
+		t.Fatal("expected another rpc url to ignore the prefetch")
+	}
+	action.Steps[0].TxHash = "0x01"
+	if _, ok := prefetch.firstBroadcastNonce(action); ok {
+		t.Fatal("expected the prefetched nonce only before the first broadcast")
+	}
+	clock.Freeze(now.Add(PrefetchMaxAge))
+	if usablePrefetch(action, step, sender, ExecuteOptions{}) != nil {
+		t.Fatal("expected an expired prefetch to be ignored")
+	}
+}
@@ -0,0 +1,367 @@
+package execution
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ggonzalez94/defi-cli/internal/labels"
+	"github.com/ggonzalez94/defi-cli/internal/registry"
+)
+
+// ActionPreview is a human-readable decoding of every call an action would
+// sign. Calls that match none of the known ABIs are listed with their selector
+// and counted in UndecodedCalls so callers can refuse to blind-sign them.
+type ActionPreview struct {
+	ActionID       string        `json:"action_id"`
+	IntentType     string        `json:"intent_type"`
+	Provider       string        `json:"provider,omitempty"`
+	ChainID        string        `json:"chain_id"`
+	FromAddress    string        `json:"from_address,omitempty"`
+	Steps          []StepPreview `json:"steps"`
+	UndecodedCalls int           `json:"undecoded_calls"`
+}
+
+// StepPreview decodes the calls of one action step. ActionID is set for steps
+// of composite sub-actions.
+type StepPreview struct {
+	ActionID    string        `json:"action_id,omitempty"`
+	StepID      string        `json:"step_id"`
+	Type        StepType      `json:"type"`
+	Status      StepStatus    `json:"status"`
+	ChainID     string        `json:"chain_id"`
+	Description string        `json:"description,omitempty"`
+	Calls       []CallPreview `json:"calls"`
+}
+
+// CallPreview describes one call. The summary fields are lifted from the
+// decoded arguments; amounts are in base units. The *Label fields name
+// addresses found in the address directory (see internal/labels).
+type CallPreview struct {
+	Target         string         `json:"target"`
+	TargetLabel    string         `json:"target_label,omitempty"`
+	Value          string         `json:"value,omitempty"`
+	Decoded        bool           `json:"decoded"`
+	Selector       string         `json:"selector,omitempty"`
+	Contract       string         `json:"contract,omitempty"`
+	Method         string         `json:"method,omitempty"`
+	Token          string         `json:"token,omitempty"`
+	Spender        string         `json:"spender,omitempty"`
+	SpenderLabel   string         `json:"spender_label,omitempty"`
+	Amount         string         `json:"amount,omitempty"`
+	Recipient      string         `json:"recipient,omitempty"`
+	RecipientLabel string         `json:"recipient_label,omitempty"`
+	MinOut         string         `json:"min_out,omitempty"`
+	Deadline       string         `json:"deadline,omitempty"`
+	Args           map[string]any `json:"args,omitempty"`
+	Summary        string         `json:"summary"`
+}
+
+type previewContract struct {
+	name string
+	abi  abi.ABI
+}
+
+// previewContracts are tried in order when decoding calldata.
+var previewContracts = []previewContract{
+	{name: "erc20", abi: policyERC20ABI},
+	{name: "erc4626_vault", abi: mustPolicyABI(registry.ERC4626VaultABI)},
+	{name: "uniswap_v3_router", abi: policyUniswapV3RouterABI},
+	{name: "tempo_stablecoin_dex", abi: policyTempoDEXABI},
+	{name: "aave_pool", abi: mustPolicyABI(registry.AavePoolABI)},
+	{name: "aave_rewards", abi: mustPolicyABI(registry.AaveRewardsABI)},
+	{name: "merkl_distributor", abi: mustPolicyABI(registry.MerklDistributorABI)},
+	{name: "morpho_blue", abi: mustPolicyABI(registry.MorphoBlueABI)},
+	{name: "moonwell_mtoken", abi: mustPolicyABI(registry.MoonwellMTokenABI)},
+	{name: "moonwell_comptroller", abi: mustPolicyABI(registry.MoonwellComptrollerABI)},
+	{name: "across_spoke_pool", abi: mustPolicyABI(registry.AcrossSpokePoolABI)},
+	{name: "cctp_token_messenger", abi: mustPolicyABI(registry.CCTPTokenMessengerV2ABI)},
+	{name: "cctp_message_transmitter", abi: policyMessageTransmitterABI},
+	{name: "wormhole_token_bridge", abi: mustPolicyABI(registry.WormholeTokenBridgeABI)},
+}
+
+// Argument names lifted into the CallPreview summary fields, in priority order.
+var (
+	previewTokenArgs     = []string{"asset", "tokenIn", "inputToken", "burnToken", "token", "loanToken"}
+	previewSpenderArgs   = []string{"spender"}
+	previewAmountArgs    = []string{"amount", "amountIn", "inputAmount", "assets", "mintAmount", "redeemAmount", "borrowAmount", "repayAmount"}
+	previewRecipientArgs = []string{"recipient", "to", "receiver", "mintRecipient", "onBehalfOf", "onBehalf"}
+	previewMinOutArgs    = []string{"amountOutMinimum", "minAmountOut", "outputAmount"}
+	previewDeadlineArgs  = []string{"deadline", "fillDeadline"}
+)
+
+// PreviewAction decodes the calldata of every step of action, including the
+// steps of composite sub-actions.
+func PreviewAction(action Action) ActionPreview {
+	out := ActionPreview{
+		ActionID:    action.ActionID,
+		IntentType:  action.IntentType,
+		Provider:    action.Provider,
+		ChainID:     action.ChainID,
+		FromAddress: action.FromAddress,
+		Steps:       []StepPreview{},
+	}
+	appendSteps := func(item Action, subActionID string) {
+		for _, step := range item.Steps {
+			preview := previewStep(item, step)
+			preview.ActionID = subActionID
+			for _, call := range preview.Calls {
+				if !call.Decoded {
+					out.UndecodedCalls++
+				}
+			}
+			out.Steps = append(out.Steps, preview)
+		}
+	}
+	if len(action.SubActions) == 0 {
+		appendSteps(action, "")
+		return out
+	}
+	for _, sub := range action.SubActions {
+		appendSteps(sub, sub.ActionID)
+	}
+	return out
+}
+
+func previewStep(action Action, step ActionStep) StepPreview {
+	chainID := strings.TrimSpace(step.ChainID)
+	if chainID == "" {
+		chainID = strings.TrimSpace(action.ChainID)
+	}
+	out := StepPreview{
+		StepID:      step.StepID,
+		Type:        step.Type,
+		Status:      step.Status,
+		ChainID:     chainID,
+		Description: step.Description,
+	}
+	if step.Order != nil {
+		out.Calls = []CallPreview{previewOrder(*step.Order)}
+		return out
+	}
+	for _, call := range guardrailCalls(&step) {
+		preview := PreviewCall(call.Target, call.Data, call.Value)
+		preview.applyLabels(chainID)
+		out.Calls = append(out.Calls, preview)
+	}
+	return out
+}
+
+// applyLabels names the target, spender, and recipient of c on chainID.
+func (c *CallPreview) applyLabels(chainID string) {
+	c.TargetLabel = labels.Label(chainID, c.Target)
+	c.SpenderLabel = labels.Label(chainID, c.Spender)
+	c.RecipientLabel = labels.Label(chainID, c.Recipient)
+}
+
+// PreviewCall decodes one call against the known ABIs.
+func PreviewCall(target, data, value string) CallPreview {
+	out := CallPreview{Target: strings.TrimSpace(target)}
+	if common.IsHexAddress(out.Target) {
+		out.Target = common.HexToAddress(out.Target).Hex()
+	}
+	if v, ok := new(big.Int).SetString(strings.TrimSpace(value), 10); ok && v.Sign() > 0 {
+		out.Value = v.String()
+	}
+	raw := common.FromHex(strings.TrimSpace(data))
+	if len(raw) == 0 {
+		out.Decoded = true
+		out.Method = "native_transfer"
+		out.Recipient = out.Target
+		out.Amount = out.Value
+		out.Summary = fmt.Sprintf("send %s wei to %s", valueOrZero(out.Value), out.Target)
+		return out
+	}
+	if len(raw) < 4 {
+		out.Summary = fmt.Sprintf("call %s with malformed calldata", out.Target)
+		return out
+	}
+	out.Selector = hexutil.Encode(raw[:4])
+	for _, contract := range previewContracts {
+		method, err := contract.abi.MethodById(raw[:4])
+		if err != nil {
+			continue
+		}
+		values, err := method.Inputs.Unpack(raw[4:])
+		if err != nil || len(values) != len(method.Inputs) {
+			continue
+		}
+		out.Decoded = true
+		out.Contract = contract.name
+		out.Method = method.Name
+		out.Args = make(map[string]any, len(values))
+		for i, input := range method.Inputs {
+			out.Args[input.Name] = previewValue(values[i])
+		}
+		out.fillSummaryFields()
+		out.Summary = out.describe()
+		return out
+	}
+	out.Summary = fmt.Sprintf("unrecognized call %s to %s; calldata not decoded", out.Selector, out.Target)
+	return out
+}
+
+func previewOrder(order OrderDetails) CallPreview {
+	out := CallPreview{
+		Target:    order.OrderBookURL,
+		Decoded:   true,
+		Contract:  "cowswap_order",
+		Method:    "order",
+		Token:     order.SellToken,
+		Amount:    order.SellAmount,
+		Recipient: order.Receiver,
+		MinOut:    order.BuyAmount,
+		Deadline:  previewDeadline(strconv.FormatUint(uint64(order.ValidTo), 10)),
+	}
+	out.Summary = fmt.Sprintf("sign off-chain order selling %s of %s for at least %s of %s to %s", order.SellAmount, order.SellToken, order.BuyAmount, order.BuyToken, order.Receiver)
+	return out
+}
+
+func (c *CallPreview) fillSummaryFields() {
+	flat := map[string]any{}
+	flattenPreviewArgs(c.Args, flat)
+	c.Token = firstPreviewArg(flat, previewTokenArgs)
+	if c.Contract == "erc20" {
+		c.Token = c.Target
+	}
+	c.Spender = firstPreviewArg(flat, previewSpenderArgs)
+	c.Amount = firstPreviewArg(flat, previewAmountArgs)
+	c.Recipient = previewAddress(firstPreviewArg(flat, previewRecipientArgs))
+	c.MinOut = firstPreviewArg(flat, previewMinOutArgs)
+	c.Deadline = previewDeadline(firstPreviewArg(flat, previewDeadlineArgs))
+}
+
+func (c CallPreview) describe() string {
+	switch {
+	case c.Method == "approve":
+		return fmt.Sprintf("approve %s to spend %s of %s", c.Spender, c.Amount, c.Token)
+	case c.Method == "transfer" && c.Contract == "erc20":
+		return fmt.Sprintf("transfer %s of %s to %s", c.Amount, c.Token, c.Recipient)
+	}
+	parts := []string{fmt.Sprintf("%s.%s", c.Contract, c.Method)}
+	if c.Amount != "" {
+		parts = append(parts, "amount "+c.Amount)
+	}
+	if c.Token != "" {
+		parts = append(parts, "token "+c.Token)
+	}
+	if c.MinOut != "" {
+		parts = append(parts, "min out "+c.MinOut)
+	}
+	if c.Recipient != "" {
+		parts = append(parts, "recipient "+c.Recipient)
+	}
+	if c.Deadline != "" {
+		parts = append(parts, "deadline "+c.Deadline)
+	}
+	if c.Value != "" {
+		parts = append(parts, "value "+c.Value+" wei")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// previewValue converts decoded ABI values to JSON-friendly forms: addresses
+// as checksummed hex, integers as decimal strings, bytes as hex, and tuples as
+// maps keyed by component name.
+func previewValue(v any) any {
+	switch value := v.(type) {
+	case common.Address:
+		return value.Hex()
+	case *big.Int:
+		return value.String()
+	case []byte:
+		return hexutil.Encode(value)
+	case [32]byte:
+		return hexutil.Encode(value[:])
+	case bool, string:
+		return value
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(rv.Uint(), 10)
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10)
+	case reflect.Slice, reflect.Array:
+		out := make([]any, rv.Len())
+		for i := range out {
+			out[i] = previewValue(rv.Index(i).Interface())
+		}
+		return out
+	case reflect.Struct:
+		out := map[string]any{}
+		for i := 0; i < rv.NumField(); i++ {
+			field := rv.Type().Field(i)
+			name := strings.Split(field.Tag.Get("json"), ",")[0]
+			if name == "" {
+				name = field.Name
+			}
+			out[name] = previewValue(rv.Field(i).Interface())
+		}
+		return out
+	}
+	return fmt.Sprint(v)
+}
+
+// flattenPreviewArgs merges tuple components into out; top-level arguments
+// win over nested ones with the same name.
+func flattenPreviewArgs(args map[string]any, out map[string]any) {
+	var nested []map[string]any
+	for name, value := range args {
+		if tuple, ok := value.(map[string]any); ok {
+			nested = append(nested, tuple)
+			continue
+		}
+		out[name] = value
+	}
+	for _, tuple := range nested {
+		inner := map[string]any{}
+		flattenPreviewArgs(tuple, inner)
+		for name, value := range inner {
+			if _, ok := out[name]; !ok {
+				out[name] = value
+			}
+		}
+	}
+}
+
+func firstPreviewArg(args map[string]any, names []string) string {
+	for _, name := range names {
+		if value, ok := args[name].(string); ok && value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// previewAddress renders bytes32-encoded recipients (CCTP, Wormhole) as EVM
+// addresses when their upper 12 bytes are zero.
+func previewAddress(value string) string {
+	raw := common.FromHex(value)
+	if len(raw) == 32 && new(big.Int).SetBytes(raw[:12]).Sign() == 0 {
+		return common.BytesToAddress(raw[12:]).Hex()
+	}
+	return value
+}
+
+// previewDeadline renders a unix-seconds deadline as RFC3339.
+func previewDeadline(value string) string {
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || seconds <= 0 {
+		return ""
+	}
+	return time.Unix(seconds, 0).UTC().Format(time.RFC3339)
+}
+
+func valueOrZero(value string) string {
+	if value == "" {
+		return "0"
+	}
+	return value
+}
@@ -0,0 +1,111 @@
+package execution
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ggonzalez94/defi-cli/internal/registry"
+)
+
+func TestPreviewCallDecodesApproval(t *testing.T) {
+	spender := common.HexToAddress("0x00000000000000000000000000000000000000b0")
+	data, err := policyERC20ABI.Pack("approve", spender, big.NewInt(5_000_000))
+	if err != nil {
+		t.Fatalf("pack approve: %v", err)
+	}
+	got := PreviewCall(guardrailTestUSDC, "0x"+common.Bytes2Hex(data), "0")
+	if !got.Decoded || got.Contract != "erc20" || got.Method != "approve" {
+		t.Fatalf("unexpected decode: %+v", got)
+	}
+	if got.Spender != spender.Hex() || got.Amount != "5000000" || got.Token != common.HexToAddress(guardrailTestUSDC).Hex() {
+		t.Fatalf("unexpected summary fields: %+v", got)
+	}
+	if got.Value != "" {
+		t.Fatalf("expected zero value to be omitted, got %q", got.Value)
+	}
+}
+
+func TestPreviewCallFlattensUniswapTuple(t *testing.T) {
+	type params struct {
+		TokenIn           common.Address
+		TokenOut          common.Address
+		Fee               *big.Int
+		Recipient         common.Address
+		AmountIn          *big.Int
+		AmountOutMinimum  *big.Int
+		SqrtPriceLimitX96 *big.Int
+	}
+	recipient := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	data, err := policyUniswapV3RouterABI.Pack("exactInputSingle", params{
+		TokenIn:           common.HexToAddress(guardrailTestUSDC),
+		TokenOut:          common.HexToAddress("0x4200000000000000000000000000000000000006"),
+		Fee:               big.NewInt(500),
+		Recipient:         recipient,
+		AmountIn:          big.NewInt(1_000_000),
+		AmountOutMinimum:  big.NewInt(990),
+		SqrtPriceLimitX96: big.NewInt(0),
+	})
+	if err != nil {
+		t.Fatalf("pack exactInputSingle: %v", err)
+	}
+	got := PreviewCall("0x00000000000000000000000000000000000000c0", "0x"+common.Bytes2Hex(data), "")
+	if !got.Decoded || got.Contract != "uniswap_v3_router" {
+		t.Fatalf("unexpected decode: %+v", got)
+	}
+	if got.Amount != "1000000" || got.MinOut != "990" || got.Recipient != recipient.Hex() {
+		t.Fatalf("unexpected summary fields: %+v", got)
+	}
+	if _, ok := got.Args["params"].(map[string]any); !ok {
+		t.Fatalf("expected tuple args as map, got %#v", got.Args["params"])
+	}
+}
+
+func TestPreviewCallDecodesAcrossDeadline(t *testing.T) {
+	spokePool := mustPolicyABI(registry.AcrossSpokePoolABI)
+	recipient := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	data, err := spokePool.Pack("depositV3",
+		recipient, recipient,
+		common.HexToAddress(guardrailTestUSDC), common.HexToAddress("0xaf88d065e77c8cc2239327c5edb3a432268e5831"),
+		big.NewInt(1_000_000), big.NewInt(998_000), big.NewInt(42161),
+		common.Address{}, uint32(1_700_000_000), uint32(1_700_003_600), uint32(0), []byte{},
+	)
+	if err != nil {
+		t.Fatalf("pack depositV3: %v", err)
+	}
+	got := PreviewCall("0x00000000000000000000000000000000000000d0", "0x"+common.Bytes2Hex(data), "")
+	if got.Contract != "across_spoke_pool" || got.MinOut != "998000" || got.Amount != "1000000" {
+		t.Fatalf("unexpected decode: %+v", got)
+	}
+	if got.Deadline != "2023-11-14T23:13:20Z" {
+		t.Fatalf("unexpected deadline %q", got.Deadline)
+	}
+}
+
+func TestPreviewActionCountsUndecodedCalls(t *testing.T) {
+	action := guardrailTestAction(t, common.HexToAddress("0x00000000000000000000000000000000000000b0"))
+	action.Steps[1].Data = "0xdeadbeef00"
+	preview := PreviewAction(action)
+	if len(preview.Steps) != 2 {
+		t.Fatalf("expected 2 steps, got %d", len(preview.Steps))
+	}
+	if preview.UndecodedCalls != 1 {
+		t.Fatalf("expected one undecoded call, got %d", preview.UndecodedCalls)
+	}
+	call := preview.Steps[1].Calls[0]
+	if call.Decoded || call.Selector != "0xdeadbeef" {
+		t.Fatalf("unexpected undecoded call: %+v", call)
+	}
+}
+
+func TestPreviewActionLabelsKnownAddresses(t *testing.T) {
+	permit2 := common.HexToAddress("0x000000000022D473030F116dDEE9F6B43aC78BA3")
+	preview := PreviewAction(guardrailTestAction(t, permit2))
+	approve := preview.Steps[0].Calls[0]
+	if approve.SpenderLabel != "Uniswap Permit2" || approve.TargetLabel != "" {
+		t.Fatalf("expected the Permit2 spender to be labeled, got %+v", approve)
+	}
+	if send := preview.Steps[1].Calls[0]; send.TargetLabel != "Uniswap Permit2" || send.RecipientLabel != "Uniswap Permit2" {
+		t.Fatalf("expected the native transfer target to be labeled, got %+v", send)
+	}
+}
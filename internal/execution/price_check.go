@@ -0,0 +1,17 @@
+package execution
+
+// PriceCheck records the independent price check run before a swap is
+// submitted. PriceImpactPct is how far the provider's quoted output falls
+// below the output implied by the independent prices; a quote better than the
+// reference has a negative impact.
+type PriceCheck struct {
+	Source            string  `json:"source"`
+	InputPriceUSD     float64 `json:"input_price_usd"`
+	OutputPriceUSD    float64 `json:"output_price_usd"`
+	QuotedOut         string  `json:"quoted_out"`
+	ExpectedOut       string  `json:"expected_out"`
+	PriceImpactPct    float64 `json:"price_impact_pct" unit:"percent"`
+	MaxPriceImpactPct float64 `json:"max_price_impact_pct" unit:"percent"`
+	Passed            bool    `json:"passed"`
+	CheckedAt         string  `json:"checked_at"`
+}
@@ -0,0 +1,135 @@
+package execution
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+)
+
+// Private transaction routing states recorded on ActionStep.PrivateTx.
+const (
+	PrivateTxPending        = "pending"
+	PrivateTxIncluded       = "included"
+	PrivateTxFailed         = "failed"
+	PrivateTxFallbackPublic = "fallback_public"
+)
+
+// PrivateTxOptions routes step transactions through a private relay instead
+// of the public mempool. Only steps on ChainID use the relay; steps on other
+// chains, such as a bridge's destination claim, are broadcast normally.
+type PrivateTxOptions struct {
+	Relay   string // relay name recorded on the step, e.g. "flashbots"
+	ChainID string // CAIP-2 chain id the relay accepts
+	RPCURL  string
+	// StatusURL, when set, is a status API prefix polled with the tx hash
+	// until the relay reports inclusion or drops the transaction.
+	StatusURL string
+	// Fallback rebroadcasts through the step's public RPC when the relay
+	// rejects or drops the transaction.
+	Fallback bool
+}
+
+// PrivateTxStatus records how a step's transaction was routed.
+type PrivateTxStatus struct {
+	Relay          string `json:"relay"`
+	Status         string `json:"status"`
+	FallbackReason string `json:"fallback_reason,omitempty"`
+}
+
+func (o *PrivateTxOptions) appliesTo(step *ActionStep) bool {
+	return o != nil && strings.TrimSpace(o.RPCURL) != "" && strings.EqualFold(strings.TrimSpace(step.ChainID), strings.TrimSpace(o.ChainID))
+}
+
+// submitStepTx broadcasts tx through the private relay when one applies to
+// step, falling back to the public RPC only when opts allow it.
+func (e *EVMStepExecutor) submitStepTx(ctx context.Context, rpcURL string, chainID *big.Int, tx *types.Transaction, step *ActionStep, opts ExecuteOptions) (common.Hash, error) {
+	relay := opts.PrivateTx
+	if !relay.appliesTo(step) {
+		return e.backend.SubmitDynamicFeeTx(ctx, rpcURL, chainID, tx)
+	}
+	step.PrivateTx = &PrivateTxStatus{Relay: relay.Relay, Status: PrivateTxPending}
+	txHash, err := e.backend.SubmitDynamicFeeTx(ctx, strings.TrimSpace(relay.RPCURL), chainID, tx)
+	if err == nil {
+		return txHash, nil
+	}
+	if typed, ok := clierr.As(err); (ok && typed.Code == clierr.CodeSigner) || !relay.Fallback {
+		step.PrivateTx.Status = PrivateTxFailed
+		return common.Hash{}, clierr.Wrap(clierr.CodeUnavailable, "broadcast through private relay "+relay.Relay+" (pass --private-fallback to use the public mempool)", err)
+	}
+	step.PrivateTx.Status = PrivateTxFallbackPublic
+	step.PrivateTx.FallbackReason = err.Error()
+	return e.backend.SubmitDynamicFeeTx(ctx, rpcURL, chainID, tx)
+}
+
+// awaitPrivateInclusion polls the relay status API until the relay reports
+// the transaction included. A dropped transaction is rebroadcast publicly
+// when fallback is enabled; the returned hash is the one to wait on. Relays
+// without a status API return immediately and the receipt wait covers
+// inclusion.
+func (e *EVMStepExecutor) awaitPrivateInclusion(ctx context.Context, rpcURL string, chainID *big.Int, tx *types.Transaction, step *ActionStep, txHash common.Hash, opts ExecuteOptions, persist func() error) (common.Hash, error) {
+	relay := opts.PrivateTx
+	if step.PrivateTx == nil || step.PrivateTx.Status != PrivateTxPending || strings.TrimSpace(relay.StatusURL) == "" {
+		return txHash, nil
+	}
+	waitCtx, cancel := stepWaitContext(ctx, opts)
+	defer cancel()
+	interval := opts.PollInterval
+	if interval <= 0 {
+		interval = 2 * time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		// Status API failures are transient; keep polling until timeout.
+		status, err := queryPrivateTxStatus(waitCtx, relay.StatusURL, txHash)
+		if err == nil {
+			switch status {
+			case "INCLUDED":
+				step.PrivateTx.Status = PrivateTxIncluded
+				return txHash, safePersist(persist)
+			case "FAILED", "CANCELLED":
+				if !relay.Fallback {
+					step.PrivateTx.Status = PrivateTxFailed
+					step.Status = StepStatusFailed
+					_ = safePersist(persist)
+					return common.Hash{}, clierr.New(clierr.CodeUnavailable, "private relay "+relay.Relay+" dropped the transaction ("+strings.ToLower(status)+"); re-run with --private-fallback to use the public mempool")
+				}
+				step.PrivateTx.Status = PrivateTxFallbackPublic
+				step.PrivateTx.FallbackReason = "relay status " + strings.ToLower(status)
+				publicHash, err := e.backend.SubmitDynamicFeeTx(ctx, rpcURL, chainID, tx)
+				if err != nil {
+					return common.Hash{}, err
+				}
+				step.TxHash = publicHash.Hex()
+				return publicHash, safePersist(persist)
+			}
+		}
+		select {
+		case <-waitCtx.Done():
+			return common.Hash{}, clierr.Wrap(clierr.CodeActionTimeout, "timed out waiting for private relay inclusion", waitCtx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+type privateTxStatusResponse struct {
+	Status string `json:"status"`
+}
+
+func queryPrivateTxStatus(ctx context.Context, statusURL string, txHash common.Hash) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSpace(statusURL)+txHash.Hex(), nil)
+	if err != nil {
+		return "", err
+	}
+	var out privateTxStatusResponse
+	if _, err := settlementHTTPClient.DoJSON(ctx, req, &out); err != nil {
+		return "", clierr.Wrap(clierr.CodeUnavailable, "query private relay status", err)
+	}
+	return strings.ToUpper(strings.TrimSpace(out.Status)), nil
+}
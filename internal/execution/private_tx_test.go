@@ -0,0 +1,161 @@
+package execution
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+)
+
+// recordingSubmitBackend records the RPC URL of each broadcast and rejects
+// URLs listed in fail.
+type recordingSubmitBackend struct {
+	fail  map[string]bool
+	calls []string
+}
+
+func (b *recordingSubmitBackend) EffectiveSender() common.Address {
+	return common.HexToAddress("0x00000000000000000000000000000000000000aa")
+}
+
+func (b *recordingSubmitBackend) SubmitDynamicFeeTx(_ context.Context, rpcURL string, _ *big.Int, tx *types.Transaction) (common.Hash, error) {
+	b.calls = append(b.calls, rpcURL)
+	if b.fail[rpcURL] {
+		return common.Hash{}, clierr.New(clierr.CodeUnavailable, "broadcast transaction: rejected")
+	}
+	return tx.Hash(), nil
+}
+
+func privateTxTestTx() *types.Transaction {
+	to := common.HexToAddress("0x00000000000000000000000000000000000000c0")
+	return types.NewTx(&types.DynamicFeeTx{ChainID: big.NewInt(1), Nonce: 7, Gas: 21_000, To: &to, Value: big.NewInt(1)})
+}
+
+func TestSubmitStepTxRoutesThroughPrivateRelay(t *testing.T) {
+	backend := &recordingSubmitBackend{}
+	exec := NewEVMStepExecutor(backend)
+	step := &ActionStep{ChainID: "eip155:1"}
+	opts := ExecuteOptions{PrivateTx: &PrivateTxOptions{Relay: "flashbots", ChainID: "eip155:1", RPCURL: "https://relay.example"}}
+
+	if _, err := exec.submitStepTx(context.Background(), "https://public.example", big.NewInt(1), privateTxTestTx(), step, opts); err != nil {
+		t.Fatalf("submitStepTx failed: %v", err)
+	}
+	if len(backend.calls) != 1 || backend.calls[0] != "https://relay.example" {
+		t.Fatalf("expected a single relay broadcast, got %v", backend.calls)
+	}
+	if step.PrivateTx == nil || step.PrivateTx.Relay != "flashbots" || step.PrivateTx.Status != PrivateTxPending {
+		t.Fatalf("unexpected private tx status: %+v", step.PrivateTx)
+	}
+
+	// Steps on other chains are broadcast publicly without a relay record.
+	other := &ActionStep{ChainID: "eip155:8453"}
+	if _, err := exec.submitStepTx(context.Background(), "https://public.example", big.NewInt(8453), privateTxTestTx(), other, opts); err != nil {
+		t.Fatalf("submitStepTx failed: %v", err)
+	}
+	if backend.calls[1] != "https://public.example" || other.PrivateTx != nil {
+		t.Fatalf("expected public broadcast for other chain, got %v %+v", backend.calls, other.PrivateTx)
+	}
+}
+
+func TestSubmitStepTxFallsBackOnlyWhenAllowed(t *testing.T) {
+	relay := &PrivateTxOptions{Relay: "mevblocker", ChainID: "eip155:1", RPCURL: "https://relay.example"}
+	backend := &recordingSubmitBackend{fail: map[string]bool{"https://relay.example": true}}
+	exec := NewEVMStepExecutor(backend)
+
+	step := &ActionStep{ChainID: "eip155:1"}
+	_, err := exec.submitStepTx(context.Background(), "https://public.example", big.NewInt(1), privateTxTestTx(), step, ExecuteOptions{PrivateTx: relay})
+	if err == nil || !strings.Contains(err.Error(), "--private-fallback") {
+		t.Fatalf("expected relay failure without fallback, got %v", err)
+	}
+	if len(backend.calls) != 1 || step.PrivateTx.Status != PrivateTxFailed {
+		t.Fatalf("expected no public broadcast, got %v %+v", backend.calls, step.PrivateTx)
+	}
+
+	relay.Fallback = true
+	step = &ActionStep{ChainID: "eip155:1"}
+	if _, err := exec.submitStepTx(context.Background(), "https://public.example", big.NewInt(1), privateTxTestTx(), step, ExecuteOptions{PrivateTx: relay}); err != nil {
+		t.Fatalf("submitStepTx with fallback failed: %v", err)
+	}
+	if got := backend.calls[len(backend.calls)-1]; got != "https://public.example" {
+		t.Fatalf("expected public fallback broadcast, got %v", backend.calls)
+	}
+	if step.PrivateTx.Status != PrivateTxFallbackPublic || step.PrivateTx.FallbackReason == "" {
+		t.Fatalf("unexpected fallback status: %+v", step.PrivateTx)
+	}
+}
+
+func TestAwaitPrivateInclusionPollsRelayStatus(t *testing.T) {
+	statuses := []string{"PENDING", "INCLUDED"}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, "/tx/0x") {
+			t.Errorf("unexpected status path %q", r.URL.Path)
+		}
+		status := statuses[0]
+		if len(statuses) > 1 {
+			statuses = statuses[1:]
+		}
+		_, _ = w.Write([]byte(`{"status":"` + status + `"}`))
+	}))
+	defer server.Close()
+
+	backend := &recordingSubmitBackend{}
+	exec := NewEVMStepExecutor(backend)
+	tx := privateTxTestTx()
+	step := &ActionStep{ChainID: "eip155:1", PrivateTx: &PrivateTxStatus{Relay: "flashbots", Status: PrivateTxPending}}
+	opts := ExecuteOptions{
+		PollInterval: 5 * time.Millisecond,
+		StepTimeout:  time.Second,
+		PrivateTx:    &PrivateTxOptions{Relay: "flashbots", ChainID: "eip155:1", RPCURL: "https://relay.example", StatusURL: server.URL + "/tx/"},
+	}
+	hash, err := exec.awaitPrivateInclusion(context.Background(), "https://public.example", big.NewInt(1), tx, step, tx.Hash(), opts, nil)
+	if err != nil {
+		t.Fatalf("awaitPrivateInclusion failed: %v", err)
+	}
+	if hash != tx.Hash() || step.PrivateTx.Status != PrivateTxIncluded || len(backend.calls) != 0 {
+		t.Fatalf("unexpected inclusion result: hash=%s status=%+v calls=%v", hash.Hex(), step.PrivateTx, backend.calls)
+	}
+}
+
+func TestAwaitPrivateInclusionHandlesDroppedTransaction(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"status":"FAILED"}`))
+	}))
+	defer server.Close()
+
+	tx := privateTxTestTx()
+	relay := &PrivateTxOptions{Relay: "flashbots", ChainID: "eip155:1", RPCURL: "https://relay.example", StatusURL: server.URL + "/tx/"}
+	opts := ExecuteOptions{PollInterval: 5 * time.Millisecond, StepTimeout: time.Second, PrivateTx: relay}
+
+	backend := &recordingSubmitBackend{}
+	exec := NewEVMStepExecutor(backend)
+	step := &ActionStep{ChainID: "eip155:1", PrivateTx: &PrivateTxStatus{Relay: "flashbots", Status: PrivateTxPending}}
+	_, err := exec.awaitPrivateInclusion(context.Background(), "https://public.example", big.NewInt(1), tx, step, tx.Hash(), opts, nil)
+	var typed *clierr.Error
+	if !errors.As(err, &typed) || typed.Code != clierr.CodeUnavailable {
+		t.Fatalf("expected unavailable error for dropped tx, got %v", err)
+	}
+	if step.PrivateTx.Status != PrivateTxFailed || len(backend.calls) != 0 {
+		t.Fatalf("expected no public rebroadcast, got %+v %v", step.PrivateTx, backend.calls)
+	}
+
+	relay.Fallback = true
+	step = &ActionStep{ChainID: "eip155:1", PrivateTx: &PrivateTxStatus{Relay: "flashbots", Status: PrivateTxPending}}
+	hash, err := exec.awaitPrivateInclusion(context.Background(), "https://public.example", big.NewInt(1), tx, step, tx.Hash(), opts, nil)
+	if err != nil {
+		t.Fatalf("awaitPrivateInclusion with fallback failed: %v", err)
+	}
+	if len(backend.calls) != 1 || backend.calls[0] != "https://public.example" {
+		t.Fatalf("expected public rebroadcast, got %v", backend.calls)
+	}
+	if step.PrivateTx.Status != PrivateTxFallbackPublic || step.TxHash != hash.Hex() {
+		t.Fatalf("unexpected fallback state: %+v tx=%s", step.PrivateTx, step.TxHash)
+	}
+}
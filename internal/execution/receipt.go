@@ -0,0 +1,298 @@
+package execution
+
+import (
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/registry"
+)
+
+// StepReceipt summarizes the receipt of a confirmed step: the gas it paid
+// and the known events it emitted.
+type StepReceipt struct {
+	BlockNumber       string         `json:"block_number,omitempty"`
+	GasUsed           uint64         `json:"gas_used"`
+	EffectiveGasPrice string         `json:"effective_gas_price,omitempty"`
+	GasCostWei        string         `json:"gas_cost_wei,omitempty"`
+	Events            []ReceiptEvent `json:"events,omitempty"`
+	UndecodedLogs     int            `json:"undecoded_logs,omitempty"`
+}
+
+// ReceiptEvent is a decoded receipt log. Args use the same rendering as
+// call previews.
+type ReceiptEvent struct {
+	Contract string         `json:"contract"`
+	Standard string         `json:"standard"`
+	Name     string         `json:"name"`
+	Args     map[string]any `json:"args"`
+}
+
+// Realized is what a completed swap or bridge actually moved, read from its
+// step receipts and, for bridges, the verified destination delivery.
+// RealizedSlippageBps is positive when the output fell short of the
+// reference amount.
+type Realized struct {
+	InputBaseUnits      string `json:"input_base_units,omitempty"`
+	OutputBaseUnits     string `json:"output_base_units,omitempty"`
+	OutputSource        string `json:"output_source,omitempty"`
+	ReferenceOut        string `json:"reference_out_base_units,omitempty"`
+	SlippageReference   string `json:"slippage_reference,omitempty"`
+	EffectivePrice      string `json:"effective_price,omitempty"`
+	RealizedSlippageBps *int64 `json:"realized_slippage_bps,omitempty" unit:"bps"`
+	GasUsed             uint64 `json:"gas_used"`
+	GasCostWei          string `json:"gas_cost_wei"`
+}
+
+type receiptEventSource struct {
+	standard string
+	abi      abi.ABI
+}
+
+var (
+	receiptEventSources = []receiptEventSource{
+		{standard: "erc20", abi: mustPolicyABI(registry.ERC20EventsABI)},
+		{standard: "weth", abi: mustPolicyABI(registry.WETHEventsABI)},
+		{standard: "uniswap_v3_pool", abi: mustPolicyABI(registry.UniswapV3PoolEventsABI)},
+		{standard: "uniswap_v2_pair", abi: mustPolicyABI(registry.UniswapV2PairEventsABI)},
+		{standard: "across_spoke_pool", abi: mustPolicyABI(registry.AcrossSpokePoolEventsABI)},
+		{standard: "cctp_token_messenger", abi: mustPolicyABI(registry.CCTPTokenMessengerV2EventsABI)},
+	}
+	receiptEventsByTopic = indexReceiptEvents()
+)
+
+type receiptEventDef struct {
+	standard string
+	event    abi.Event
+}
+
+func indexReceiptEvents() map[common.Hash]receiptEventDef {
+	out := map[common.Hash]receiptEventDef{}
+	for _, source := range receiptEventSources {
+		for _, event := range source.abi.Events {
+			out[event.ID] = receiptEventDef{standard: source.standard, event: event}
+		}
+	}
+	return out
+}
+
+// recordStepReceipt attaches the gas paid and decoded events of receipt to
+// step.
+func recordStepReceipt(step *ActionStep, receipt *types.Receipt) {
+	if step == nil || receipt == nil {
+		return
+	}
+	summary := &StepReceipt{GasUsed: receipt.GasUsed}
+	if receipt.BlockNumber != nil {
+		summary.BlockNumber = receipt.BlockNumber.String()
+	}
+	if receipt.EffectiveGasPrice != nil {
+		summary.EffectiveGasPrice = receipt.EffectiveGasPrice.String()
+		summary.GasCostWei = new(big.Int).Mul(receipt.EffectiveGasPrice, new(big.Int).SetUint64(receipt.GasUsed)).String()
+	}
+	for _, entry := range receipt.Logs {
+		event, ok := decodeReceiptLog(entry)
+		if !ok {
+			summary.UndecodedLogs++
+			continue
+		}
+		summary.Events = append(summary.Events, event)
+	}
+	step.Receipt = summary
+}
+
+func decodeReceiptLog(entry *types.Log) (ReceiptEvent, bool) {
+	if entry == nil || len(entry.Topics) == 0 {
+		return ReceiptEvent{}, false
+	}
+	def, ok := receiptEventsByTopic[entry.Topics[0]]
+	if !ok {
+		return ReceiptEvent{}, false
+	}
+	var indexed abi.Arguments
+	for _, input := range def.event.Inputs {
+		if input.Indexed {
+			indexed = append(indexed, input)
+		}
+	}
+	// ERC-721 Transfer shares the ERC-20 topic but indexes the token id.
+	if len(indexed) != len(entry.Topics)-1 {
+		return ReceiptEvent{}, false
+	}
+	raw := map[string]any{}
+	if err := def.event.Inputs.UnpackIntoMap(raw, entry.Data); err != nil {
+		return ReceiptEvent{}, false
+	}
+	if err := abi.ParseTopicsIntoMap(raw, indexed, entry.Topics[1:]); err != nil {
+		return ReceiptEvent{}, false
+	}
+	args := make(map[string]any, len(raw))
+	for name, value := range raw {
+		rendered := previewValue(value)
+		if _, isWord := value.([32]byte); isWord {
+			rendered = previewAddress(rendered.(string))
+		}
+		args[name] = rendered
+	}
+	return ReceiptEvent{
+		Contract: entry.Address.Hex(),
+		Standard: def.standard,
+		Name:     def.event.RawName,
+		Args:     args,
+	}, true
+}
+
+// UpdateRealized recomputes action.Realized from the step receipts. Swaps
+// measure the output token transferred to the recipient; bridges use the
+// verified destination delivery. Other intents are left untouched.
+func (a *Action) UpdateRealized() {
+	if a == nil || (a.IntentType != "swap" && a.IntentType != "bridge") {
+		return
+	}
+	realized := &Realized{}
+	gasCost := new(big.Int)
+	sender := strings.TrimSpace(a.FromAddress)
+	recipient := firstNonEmpty(strings.TrimSpace(a.ToAddress), sender)
+	tokenIn := realizedToken(a.Metadata, "token_in", "from_asset_id")
+	tokenOut := realizedToken(a.Metadata, "token_out", "to_asset_id")
+	input, output := new(big.Int), new(big.Int)
+	hasReceipt := false
+	for i := range a.Steps {
+		step := a.Steps[i]
+		if step.Receipt == nil {
+			continue
+		}
+		hasReceipt = true
+		realized.GasUsed += step.Receipt.GasUsed
+		if cost, ok := new(big.Int).SetString(step.Receipt.GasCostWei, 10); ok {
+			gasCost.Add(gasCost, cost)
+		}
+		if step.Type == StepTypeApproval {
+			continue
+		}
+		for _, event := range step.Receipt.Events {
+			if event.Standard != "erc20" || event.Name != "Transfer" {
+				continue
+			}
+			value, ok := new(big.Int).SetString(stringArg(event.Args, "value"), 10)
+			if !ok {
+				continue
+			}
+			if tokenIn != "" && strings.EqualFold(event.Contract, tokenIn) && strings.EqualFold(stringArg(event.Args, "from"), sender) {
+				input.Add(input, value)
+			}
+			if a.IntentType == "swap" && tokenOut != "" && strings.EqualFold(event.Contract, tokenOut) && strings.EqualFold(stringArg(event.Args, "to"), recipient) {
+				output.Add(output, value)
+			}
+		}
+	}
+	if !hasReceipt {
+		return
+	}
+	realized.GasCostWei = gasCost.String()
+	if input.Sign() > 0 {
+		realized.InputBaseUnits = input.String()
+	} else if amount, ok := parsePositiveBaseUnits(a.InputAmount); ok {
+		realized.InputBaseUnits = amount.String()
+	}
+	if output.Sign() > 0 {
+		realized.OutputBaseUnits = output.String()
+		realized.OutputSource = "receipt_logs"
+	}
+	if a.IntentType == "bridge" && a.BridgeDelivery != nil && strings.TrimSpace(a.BridgeDelivery.ReceivedBaseUnits) != "" {
+		realized.OutputBaseUnits = strings.TrimSpace(a.BridgeDelivery.ReceivedBaseUnits)
+		realized.OutputSource = "bridge_delivery"
+	}
+
+	realized.ReferenceOut, realized.SlippageReference = realizedReference(a)
+	out, okOut := parsePositiveBaseUnits(realized.OutputBaseUnits)
+	if reference, ok := parsePositiveBaseUnits(realized.ReferenceOut); ok && okOut {
+		shortfall := new(big.Int).Sub(reference, out)
+		bps := new(big.Int).Quo(new(big.Int).Mul(shortfall, big.NewInt(10_000)), reference).Int64()
+		realized.RealizedSlippageBps = &bps
+	}
+	if in, ok := parsePositiveBaseUnits(realized.InputBaseUnits); ok && okOut {
+		realized.EffectivePrice = effectivePrice(a, in, out)
+	}
+	a.Realized = realized
+}
+
+// realizedReference returns the amount the realized output is measured
+// against: the quoted output when the plan recorded one, else the planned
+// minimum.
+func realizedReference(a *Action) (string, string) {
+	for _, key := range []string{"quoted_amount", "quoted_amount_out"} {
+		if quoted, _ := a.Metadata[key].(string); strings.TrimSpace(quoted) != "" {
+			return strings.TrimSpace(quoted), "quoted"
+		}
+	}
+	if minOut, _ := a.Metadata["amount_out_min"].(string); strings.TrimSpace(minOut) != "" {
+		return strings.TrimSpace(minOut), "min_out"
+	}
+	for _, step := range a.Steps {
+		if minOut := strings.TrimSpace(step.ExpectedOutputs["to_amount_min"]); minOut != "" {
+			return minOut, "min_out"
+		}
+	}
+	return "", ""
+}
+
+// effectivePrice is output per unit of input in decimal units, or "" when
+// either asset's decimals are unknown.
+func effectivePrice(a *Action, in, out *big.Int) string {
+	inDecimals, okIn := realizedDecimals(a.ChainID, a.Metadata, "token_in", "from_asset_id")
+	outChain, _ := a.Metadata["to_chain_id"].(string)
+	outDecimals, okOut := realizedDecimals(firstNonEmpty(outChain, a.ChainID), a.Metadata, "token_out", "to_asset_id")
+	if !okIn || !okOut {
+		return ""
+	}
+	price := new(big.Rat).SetFrac(
+		new(big.Int).Mul(out, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(inDecimals)), nil)),
+		new(big.Int).Mul(in, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(outDecimals)), nil)),
+	)
+	text := strings.TrimRight(price.FloatString(12), "0")
+	return strings.TrimSuffix(text, ".")
+}
+
+func realizedDecimals(chainID string, metadata map[string]any, keys ...string) (int, bool) {
+	chain, err := id.ParseChain(chainID)
+	if err != nil {
+		return 0, false
+	}
+	for _, key := range keys {
+		raw, _ := metadata[key].(string)
+		if strings.TrimSpace(raw) == "" {
+			continue
+		}
+		asset, err := id.ParseAsset(raw, chain)
+		if err != nil || asset.Decimals <= 0 {
+			return 0, false
+		}
+		return asset.Decimals, true
+	}
+	return 0, false
+}
+
+// realizedToken returns the ERC-20 address of the first metadata key that
+// names one, or "" for native or unknown assets.
+func realizedToken(metadata map[string]any, keys ...string) string {
+	for _, key := range keys {
+		raw, _ := metadata[key].(string)
+		token := spendToken(raw)
+		if token == NativeToken {
+			return ""
+		}
+		if token != "" {
+			return token
+		}
+	}
+	return ""
+}
+
+func stringArg(args map[string]any, name string) string {
+	value, _ := args[name].(string)
+	return value
+}
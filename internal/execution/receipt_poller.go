@@ -0,0 +1,164 @@
+package execution
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ggonzalez94/defi-cli/internal/rpcx"
+)
+
+const (
+	// maxReceiptBatchSize caps eth_getTransactionReceipt calls per JSON-RPC batch.
+	maxReceiptBatchSize = 100
+	receiptBatchTimeout = 15 * time.Second
+)
+
+type receiptPollerKey struct {
+	rpcURL   string
+	interval time.Duration
+}
+
+// receiptPoller serves every receipt wait on one RPC endpoint from a single
+// ticker, fetching all pending hashes in one JSON-RPC batch per tick. It
+// exits once no waiters remain.
+type receiptPoller struct {
+	key     receiptPollerKey
+	waiters map[common.Hash][]chan *types.Receipt
+}
+
+var (
+	receiptPollersMu sync.Mutex
+	receiptPollers   = map[receiptPollerKey]*receiptPoller{}
+)
+
+// awaitReceipt blocks until txHash has a receipt on rpcURL or ctx ends.
+// Concurrent waits on the same endpoint and interval share one poller, so
+// steps and actions in flight together cost one batch call per tick.
+func awaitReceipt(ctx context.Context, rpcURL string, txHash common.Hash, interval time.Duration) (*types.Receipt, error) {
+	if interval <= 0 {
+		interval = 2 * time.Second
+	}
+	key := receiptPollerKey{rpcURL: rpcURL, interval: interval}
+	ch := make(chan *types.Receipt, 1)
+
+	receiptPollersMu.Lock()
+	poller, ok := receiptPollers[key]
+	if !ok {
+		poller = &receiptPoller{key: key, waiters: map[common.Hash][]chan *types.Receipt{}}
+		receiptPollers[key] = poller
+		go poller.run()
+	}
+	poller.waiters[txHash] = append(poller.waiters[txHash], ch)
+	receiptPollersMu.Unlock()
+
+	select {
+	case receipt := <-ch:
+		return receipt, nil
+	case <-ctx.Done():
+		receiptPollersMu.Lock()
+		poller.remove(txHash, ch)
+		receiptPollersMu.Unlock()
+		// The receipt may have been delivered while the context ended.
+		select {
+		case receipt := <-ch:
+			return receipt, nil
+		default:
+		}
+		return nil, ctx.Err()
+	}
+}
+
+func (p *receiptPoller) run() {
+	var client *rpc.Client
+	defer func() {
+		if client != nil {
+			client.Close()
+		}
+	}()
+	ticker := time.NewTicker(p.key.interval)
+	defer ticker.Stop()
+	for {
+		hashes := p.pending()
+		if len(hashes) == 0 {
+			return
+		}
+		if client == nil {
+			// Dial failures are retried on the next tick, like transient
+			// polling errors; waiters give up via their own contexts.
+			client, _ = rpcx.DialRPC(context.Background(), p.key.rpcURL)
+		}
+		if client != nil {
+			p.poll(client, hashes)
+		}
+		<-ticker.C
+	}
+}
+
+// pending returns the hashes still awaited. When none remain the poller is
+// unregistered under the same lock, so later waits start a fresh poller.
+func (p *receiptPoller) pending() []common.Hash {
+	receiptPollersMu.Lock()
+	defer receiptPollersMu.Unlock()
+	if len(p.waiters) == 0 {
+		delete(receiptPollers, p.key)
+		return nil
+	}
+	hashes := make([]common.Hash, 0, len(p.waiters))
+	for hash := range p.waiters {
+		hashes = append(hashes, hash)
+	}
+	return hashes
+}
+
+func (p *receiptPoller) poll(client *rpc.Client, hashes []common.Hash) {
+	for start := 0; start < len(hashes); start += maxReceiptBatchSize {
+		end := min(start+maxReceiptBatchSize, len(hashes))
+		chunk := hashes[start:end]
+		receipts := make([]*types.Receipt, len(chunk))
+		batch := make([]rpc.BatchElem, len(chunk))
+		for i, hash := range chunk {
+			batch[i] = rpc.BatchElem{
+				Method: "eth_getTransactionReceipt",
+				Args:   []any{hash},
+				Result: &receipts[i],
+			}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), receiptBatchTimeout)
+		err := client.BatchCallContext(ctx, batch)
+		cancel()
+		if err != nil {
+			// Transient transport failure; retry the whole set next tick.
+			return
+		}
+		receiptPollersMu.Lock()
+		for i, hash := range chunk {
+			if batch[i].Error != nil || receipts[i] == nil {
+				continue
+			}
+			for _, ch := range p.waiters[hash] {
+				ch <- receipts[i]
+			}
+			delete(p.waiters, hash)
+		}
+		receiptPollersMu.Unlock()
+	}
+}
+
+func (p *receiptPoller) remove(txHash common.Hash, ch chan *types.Receipt) {
+	waiters := p.waiters[txHash]
+	for i, candidate := range waiters {
+		if candidate == ch {
+			waiters = append(waiters[:i], waiters[i+1:]...)
+			break
+		}
+	}
+	if len(waiters) == 0 {
+		delete(p.waiters, txHash)
+		return
+	}
+	p.waiters[txHash] = waiters
+}
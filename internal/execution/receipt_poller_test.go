@@ -0,0 +1,110 @@
+package execution
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// newReceiptRPCServer answers eth_getTransactionReceipt batches, returning a
+// receipt once a hash has been polled minedAfter times.
+func newReceiptRPCServer(t *testing.T, minedAfter int) (*httptest.Server, *atomic.Int32, *sync.Map) {
+	t.Helper()
+	var requests atomic.Int32
+	var seen sync.Map
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		var batch []struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+			Params []string        `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+			t.Errorf("expected a JSON-RPC batch: %v", err)
+			return
+		}
+		out := make([]string, 0, len(batch))
+		for _, req := range batch {
+			if req.Method != "eth_getTransactionReceipt" || len(req.Params) != 1 {
+				t.Errorf("unexpected request %s %v", req.Method, req.Params)
+			}
+			count, _ := seen.LoadOrStore(req.Params[0], new(atomic.Int32))
+			if int(count.(*atomic.Int32).Add(1)) < minedAfter {
+				out = append(out, fmt.Sprintf(`{"jsonrpc":"2.0","id":%s,"result":null}`, req.ID))
+				continue
+			}
+			out = append(out, fmt.Sprintf(`{"jsonrpc":"2.0","id":%s,"result":{"status":"0x1","cumulativeGasUsed":"0x5208","gasUsed":"0x5208","logsBloom":"0x%0512x","logs":[],"transactionHash":"%s","blockNumber":"0x10","type":"0x2"}}`, req.ID, 0, req.Params[0]))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("[" + strings.Join(out, ",") + "]"))
+	}))
+	return server, &requests, &seen
+}
+
+func TestAwaitReceiptBatchesConcurrentWaits(t *testing.T) {
+	server, requests, seen := newReceiptRPCServer(t, 2)
+	defer server.Close()
+
+	hashes := []common.Hash{common.HexToHash("0x01"), common.HexToHash("0x02"), common.HexToHash("0x03")}
+	receipts := make([]*types.Receipt, len(hashes))
+	errs := make([]error, len(hashes))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var wg sync.WaitGroup
+	for i, hash := range hashes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			receipts[i], errs[i] = awaitReceipt(ctx, server.URL, hash, 50*time.Millisecond)
+		}()
+	}
+	wg.Wait()
+
+	for i := range hashes {
+		if errs[i] != nil || receipts[i] == nil || receipts[i].BlockNumber.Int64() != 16 {
+			t.Fatalf("wait %d: receipt=%+v err=%v", i, receipts[i], errs[i])
+		}
+	}
+	polls := 0
+	seen.Range(func(_, v any) bool {
+		polls += int(v.(*atomic.Int32).Load())
+		return true
+	})
+	if got := int(requests.Load()); got >= polls {
+		t.Fatalf("expected batched polling, got %d HTTP requests for %d receipt lookups", got, polls)
+	}
+}
+
+func TestAwaitReceiptHonorsContextAndReleasesPoller(t *testing.T) {
+	server, _, _ := newReceiptRPCServer(t, 1_000)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	if _, err := awaitReceipt(ctx, server.URL, common.HexToHash("0x04"), 10*time.Millisecond); err == nil {
+		t.Fatal("expected context error")
+	}
+	deadline := time.Now().Add(time.Second)
+	for {
+		receiptPollersMu.Lock()
+		_, running := receiptPollers[receiptPollerKey{rpcURL: server.URL, interval: 10 * time.Millisecond}]
+		receiptPollersMu.Unlock()
+		if !running {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected idle poller to shut down")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
@@ -0,0 +1,124 @@
+package execution
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	receiptTestUSDC = "0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913"
+	receiptTestWETH = "0x4200000000000000000000000000000000000006"
+)
+
+func erc20TransferLog(token, from, to common.Address, value *big.Int) *types.Log {
+	return &types.Log{
+		Address: token,
+		Topics:  []common.Hash{erc20TransferTopic, common.BytesToHash(from.Bytes()), common.BytesToHash(to.Bytes())},
+		Data:    common.LeftPadBytes(value.Bytes(), 32),
+	}
+}
+
+func TestRecordStepReceiptDecodesKnownEvents(t *testing.T) {
+	sender := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	pool := common.HexToAddress("0x00000000000000000000000000000000000000c0")
+	swapTopic := crypto.Keccak256Hash([]byte("Swap(address,address,int256,int256,uint160,uint128,int24)"))
+	swapData := make([]byte, 0, 5*32)
+	swapData = append(swapData, common.LeftPadBytes(big.NewInt(1_000_000).Bytes(), 32)...)
+	swapData = append(swapData, common.LeftPadBytes(new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(400)).Bytes(), 32)...)
+	swapData = append(swapData, make([]byte, 3*32)...)
+	receipt := &types.Receipt{
+		GasUsed:           150_000,
+		EffectiveGasPrice: big.NewInt(2_000_000_000),
+		BlockNumber:       big.NewInt(42),
+		Logs: []*types.Log{
+			erc20TransferLog(common.HexToAddress(receiptTestUSDC), sender, pool, big.NewInt(1_000_000)),
+			{Address: pool, Topics: []common.Hash{swapTopic, common.BytesToHash(sender.Bytes()), common.BytesToHash(sender.Bytes())}, Data: swapData},
+			// ERC-721 transfers share the topic but index the token id.
+			{Address: pool, Topics: []common.Hash{erc20TransferTopic, {}, {}, {}}},
+		},
+	}
+	step := &ActionStep{}
+	recordStepReceipt(step, receipt)
+	got := step.Receipt
+	if got == nil || got.GasCostWei != "300000000000000" || got.BlockNumber != "42" {
+		t.Fatalf("unexpected receipt summary: %+v", got)
+	}
+	if len(got.Events) != 2 || got.UndecodedLogs != 1 {
+		t.Fatalf("expected two decoded events and one undecoded log, got %+v", got)
+	}
+	transfer := got.Events[0]
+	if transfer.Name != "Transfer" || transfer.Standard != "erc20" || transfer.Args["value"] != "1000000" || transfer.Args["from"] != sender.Hex() {
+		t.Fatalf("unexpected transfer event: %+v", transfer)
+	}
+	swap := got.Events[1]
+	if swap.Standard != "uniswap_v3_pool" || swap.Args["amount1"] != "-400" {
+		t.Fatalf("unexpected swap event: %+v", swap)
+	}
+}
+
+func TestUpdateRealizedMeasuresSwapOutput(t *testing.T) {
+	sender := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	router := common.HexToAddress("0x00000000000000000000000000000000000000c0")
+	action := NewAction("act_realized", "swap", "eip155:8453", Constraints{})
+	action.FromAddress = sender.Hex()
+	action.InputAmount = "2000000"
+	action.Metadata = map[string]any{
+		"token_in":      receiptTestUSDC,
+		"token_out":     receiptTestWETH,
+		"quoted_amount": "1000000000000000",
+	}
+	action.Steps = []ActionStep{{StepID: "swap", Type: StepTypeSwap, Status: StepStatusConfirmed}}
+	recordStepReceipt(&action.Steps[0], &types.Receipt{
+		GasUsed:           100_000,
+		EffectiveGasPrice: big.NewInt(1_000_000),
+		Logs: []*types.Log{
+			erc20TransferLog(common.HexToAddress(receiptTestUSDC), sender, router, big.NewInt(2_000_000)),
+			erc20TransferLog(common.HexToAddress(receiptTestWETH), router, sender, big.NewInt(990_000_000_000_000)),
+		},
+	})
+
+	action.UpdateRealized()
+	got := action.Realized
+	if got == nil {
+		t.Fatal("expected realized amounts")
+	}
+	if got.InputBaseUnits != "2000000" || got.OutputBaseUnits != "990000000000000" || got.OutputSource != "receipt_logs" {
+		t.Fatalf("unexpected realized amounts: %+v", got)
+	}
+	if got.RealizedSlippageBps == nil || *got.RealizedSlippageBps != 100 || got.SlippageReference != "quoted" {
+		t.Fatalf("expected 100 bps slippage against the quote, got %+v", got)
+	}
+	if got.EffectivePrice != "0.000495" {
+		t.Fatalf("unexpected effective price %q", got.EffectivePrice)
+	}
+	if got.GasUsed != 100_000 || got.GasCostWei != "100000000000" {
+		t.Fatalf("unexpected gas totals: %+v", got)
+	}
+}
+
+func TestUpdateRealizedUsesBridgeDelivery(t *testing.T) {
+	action := NewAction("act_bridge", "bridge", "eip155:1", Constraints{})
+	action.InputAmount = "1000000"
+	action.Metadata = map[string]any{"from_asset_id": "eip155:1/erc20:0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"}
+	action.Steps = []ActionStep{{
+		StepID:          "bridge-transfer",
+		Type:            StepTypeBridge,
+		Status:          StepStatusConfirmed,
+		ExpectedOutputs: map[string]string{"to_amount_min": "995000"},
+		Receipt:         &StepReceipt{GasUsed: 80_000, GasCostWei: "8000"},
+	}}
+	action.BridgeDelivery = &BridgeDelivery{Status: BridgeDeliveryDelivered, ReceivedBaseUnits: "997000"}
+
+	action.UpdateRealized()
+	got := action.Realized
+	if got == nil || got.OutputBaseUnits != "997000" || got.OutputSource != "bridge_delivery" || got.InputBaseUnits != "1000000" {
+		t.Fatalf("unexpected realized bridge amounts: %+v", got)
+	}
+	if got.RealizedSlippageBps == nil || *got.RealizedSlippageBps != -20 || got.SlippageReference != "min_out" {
+		t.Fatalf("expected -20 bps against the planned minimum, got %+v", got)
+	}
+}
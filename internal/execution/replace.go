@@ -0,0 +1,252 @@
+package execution
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+)
+
+// Replacement kinds for a stuck transaction. A speedup resends the same call
+// with higher fees; a cancel sends a zero-value self-transfer at the same
+// nonce so the original call never lands.
+const (
+	ReplacementSpeedup = "speedup"
+	ReplacementCancel  = "cancel"
+)
+
+// Replacement and tracked transaction states.
+const (
+	TxStatePending = "pending"
+	TxStateMined   = "mined"
+	TxStateDropped = "dropped"
+)
+
+// MinReplacementBumpPct is the fee increase nodes require before they accept
+// a replacement at the same nonce.
+const MinReplacementBumpPct = 10.0
+
+// TxReplacement records a speedup or cancel sent for a step's transaction.
+type TxReplacement struct {
+	Kind           string `json:"kind"`
+	OriginalTxHash string `json:"original_tx_hash"`
+	TxHash         string `json:"tx_hash"`
+	Status         string `json:"status"`
+	CreatedAt      string `json:"created_at"`
+}
+
+// TxReplacementResult is the output of tx speedup and tx cancel.
+type TxReplacementResult struct {
+	Kind                 string `json:"kind"`
+	ChainID              string `json:"chain_id"`
+	From                 string `json:"from"`
+	Nonce                uint64 `json:"nonce"`
+	OriginalTxHash       string `json:"original_tx_hash"`
+	TxHash               string `json:"tx_hash"`
+	MaxFeePerGas         string `json:"max_fee_per_gas"`
+	MaxPriorityFeePerGas string `json:"max_priority_fee_per_gas"`
+	// Status is pending until one transaction at the nonce is mined, then
+	// mined when the replacement won or dropped when the original did.
+	Status      string `json:"status"`
+	MinedTxHash string `json:"mined_tx_hash,omitempty"`
+	ActionID    string `json:"action_id,omitempty"`
+	StepID      string `json:"step_id,omitempty"`
+	SubmittedAt string `json:"submitted_at"`
+}
+
+// TrackedTx is an action step transaction reported by tx list.
+type TrackedTx struct {
+	ActionID             string         `json:"action_id"`
+	StepID               string         `json:"step_id"`
+	TxHash               string         `json:"tx_hash"`
+	Nonce                *uint64        `json:"nonce,omitempty"`
+	Status               string         `json:"status"`
+	MaxFeePerGas         string         `json:"max_fee_per_gas,omitempty"`
+	MaxPriorityFeePerGas string         `json:"max_priority_fee_per_gas,omitempty"`
+	Replacement          *TxReplacement `json:"replacement,omitempty"`
+}
+
+// TxList is the output of tx list. PendingNonce above LatestNonce means the
+// address has transactions waiting in the mempool.
+type TxList struct {
+	Address      string      `json:"address"`
+	ChainID      string      `json:"chain_id"`
+	LatestNonce  uint64      `json:"latest_nonce"`
+	PendingNonce uint64      `json:"pending_nonce"`
+	Transactions []TrackedTx `json:"transactions"`
+	FetchedAt    string      `json:"fetched_at"`
+}
+
+// ReplaceOptions controls replacement fees. Fee overrides act as floors: the
+// replacement always pays at least BumpPct more than the original.
+type ReplaceOptions struct {
+	BumpPct            float64
+	MaxFeeGwei         string
+	MaxPriorityFeeGwei string
+}
+
+// BuildReplacementTx returns an unsigned dynamic-fee transaction reusing the
+// nonce of original, sent by from, with bumped fees.
+func BuildReplacementTx(ctx context.Context, client *ethclient.Client, original *types.Transaction, from common.Address, kind string, opts ReplaceOptions) (*types.Transaction, error) {
+	if opts.BumpPct < MinReplacementBumpPct {
+		return nil, clierr.New(clierr.CodeUsage, fmt.Sprintf("--fee-bump-pct must be >= %.0f", MinReplacementBumpPct))
+	}
+	tipFloor, err := resolveTipCap(ctx, client, opts.MaxPriorityFeeGwei)
+	if err != nil {
+		return nil, err
+	}
+	header, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, clierr.Wrap(clierr.CodeUnavailable, "fetch latest header", err)
+	}
+	baseFee := header.BaseFee
+	if baseFee == nil {
+		baseFee = big.NewInt(1_000_000_000)
+	}
+	tipCap := maxBig(bumpFee(original.GasTipCap(), opts.BumpPct), tipFloor)
+	feeFloor, err := resolveFeeCap(baseFee, tipCap, opts.MaxFeeGwei)
+	if err != nil {
+		return nil, err
+	}
+	feeCap := maxBig(bumpFee(original.GasFeeCap(), opts.BumpPct), feeFloor)
+
+	inner := &types.DynamicFeeTx{
+		ChainID:   original.ChainId(),
+		Nonce:     original.Nonce(),
+		GasTipCap: tipCap,
+		GasFeeCap: feeCap,
+	}
+	switch kind {
+	case ReplacementSpeedup:
+		inner.Gas = original.Gas()
+		inner.To = original.To()
+		inner.Value = original.Value()
+		inner.Data = original.Data()
+		inner.AccessList = original.AccessList()
+	case ReplacementCancel:
+		self := from
+		inner.Gas = 21_000
+		inner.To = &self
+		inner.Value = big.NewInt(0)
+	default:
+		return nil, clierr.New(clierr.CodeInternal, "unknown replacement kind "+kind)
+	}
+	return types.NewTx(inner), nil
+}
+
+// bumpFee raises fee by pct percent, rounding up so the node-side minimum
+// bump is always met.
+func bumpFee(fee *big.Int, pct float64) *big.Int {
+	if fee == nil {
+		return big.NewInt(0)
+	}
+	basisPoints := int64(math.Ceil(pct * 100))
+	out := new(big.Int).Mul(fee, big.NewInt(10_000+basisPoints))
+	out.Add(out, big.NewInt(9_999))
+	return out.Div(out, big.NewInt(10_000))
+}
+
+func maxBig(a, b *big.Int) *big.Int {
+	if a.Cmp(b) >= 0 {
+		return a
+	}
+	return b
+}
+
+// StepIndexForTxHash returns the step whose transaction, or one of its
+// replacements, has hash txHash.
+func (a *Action) StepIndexForTxHash(txHash string) (int, bool) {
+	for i := range a.Steps {
+		for _, candidate := range a.Steps[i].CandidateTxHashes() {
+			if strings.EqualFold(candidate, strings.TrimSpace(txHash)) {
+				return i, true
+			}
+		}
+	}
+	return -1, false
+}
+
+// CandidateTxHashes lists every transaction that may still land at the
+// step's nonce: its current hash plus originals and replacements that have
+// not been resolved.
+func (s ActionStep) CandidateTxHashes() []string {
+	var out []string
+	add := func(hash string) {
+		hash = strings.TrimSpace(hash)
+		if hash == "" {
+			return
+		}
+		for _, existing := range out {
+			if strings.EqualFold(existing, hash) {
+				return
+			}
+		}
+		out = append(out, hash)
+	}
+	add(s.TxHash)
+	for _, r := range s.Replacements {
+		if r.Status == TxStatePending {
+			add(r.OriginalTxHash)
+			add(r.TxHash)
+		}
+	}
+	return out
+}
+
+// RecordReplacement attaches a broadcast replacement to step index. A speedup
+// carries the step's call, so it becomes the step's current hash; a cancel
+// does not until it is mined.
+func (a *Action) RecordReplacement(index int, r TxReplacement) {
+	step := &a.Steps[index]
+	r.Status = TxStatePending
+	step.Replacements = append(step.Replacements, r)
+	if r.Kind == ReplacementSpeedup {
+		step.TxHash = r.TxHash
+	}
+	a.Touch()
+}
+
+// ResolveReplacements settles pending replacements of step index once
+// minedHash is known to hold the step's nonce. A mined cancel fails the step
+// with no transaction so resume rebuilds it; otherwise the mined hash becomes
+// the step's transaction and resume confirms it.
+func (a *Action) ResolveReplacements(index int, minedHash string) {
+	step := &a.Steps[index]
+	cancelled := false
+	for i := range step.Replacements {
+		r := &step.Replacements[i]
+		if r.Status != TxStatePending {
+			continue
+		}
+		if strings.EqualFold(r.TxHash, minedHash) {
+			r.Status = TxStateMined
+			cancelled = r.Kind == ReplacementCancel
+		} else {
+			r.Status = TxStateDropped
+		}
+	}
+	if cancelled {
+		step.TxHash = ""
+		markStepFailed(a, step, "transaction cancelled by "+minedHash)
+	} else {
+		step.TxHash = minedHash
+	}
+	a.Touch()
+}
+
+// PendingReplacement returns the latest unresolved replacement of step.
+func (s ActionStep) PendingReplacement() *TxReplacement {
+	for i := len(s.Replacements) - 1; i >= 0; i-- {
+		if s.Replacements[i].Status == TxStatePending {
+			r := s.Replacements[i]
+			return &r
+		}
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package execution
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestBumpFeeRoundsUp(t *testing.T) {
+	if got := bumpFee(big.NewInt(1_000_000_000), 10); got.String() != "1100000000" {
+		t.Fatalf("unexpected 10%% bump: %s", got)
+	}
+	// 7 * 1.125 = 7.875 must round up so the node-side minimum is met.
+	if got := bumpFee(big.NewInt(7), 12.5); got.String() != "8" {
+		t.Fatalf("expected bump to round up, got %s", got)
+	}
+}
+
+func replacementTestAction() Action {
+	action := NewAction("act_replace", "swap", "eip155:1", Constraints{})
+	action.Status = ActionStatusFailed
+	action.Steps = []ActionStep{{StepID: "swap", Status: StepStatusSubmitted, ChainID: "eip155:1", TxHash: "0x01"}}
+	return action
+}
+
+func TestSpeedupReplacementTracksStepHash(t *testing.T) {
+	action := replacementTestAction()
+	action.RecordReplacement(0, TxReplacement{Kind: ReplacementSpeedup, OriginalTxHash: "0x01", TxHash: "0x02"})
+	if action.Steps[0].TxHash != "0x02" {
+		t.Fatalf("expected speedup to become the step hash, got %s", action.Steps[0].TxHash)
+	}
+	if index, ok := action.StepIndexForTxHash("0x01"); !ok || index != 0 {
+		t.Fatalf("expected original hash to resolve to the step")
+	}
+	if got := action.Steps[0].CandidateTxHashes(); len(got) != 2 {
+		t.Fatalf("expected original and replacement as candidates, got %v", got)
+	}
+
+	// The original won the nonce: the step keeps the original hash.
+	action.ResolveReplacements(0, "0x01")
+	step := action.Steps[0]
+	if step.TxHash != "0x01" || step.Status != StepStatusSubmitted || step.Replacements[0].Status != TxStateDropped {
+		t.Fatalf("unexpected step after original mined: %+v", step)
+	}
+	if step.PendingReplacement() != nil {
+		t.Fatalf("expected no pending replacement")
+	}
+}
+
+func TestMinedCancelFailsStep(t *testing.T) {
+	action := replacementTestAction()
+	action.RecordReplacement(0, TxReplacement{Kind: ReplacementCancel, OriginalTxHash: "0x01", TxHash: "0x03"})
+	if action.Steps[0].TxHash != "0x01" {
+		t.Fatalf("expected cancel to keep the original step hash, got %s", action.Steps[0].TxHash)
+	}
+	action.ResolveReplacements(0, "0x03")
+	step := action.Steps[0]
+	if step.Status != StepStatusFailed || step.TxHash != "" || action.Status != ActionStatusFailed {
+		t.Fatalf("expected cancelled step to fail without a tx hash, got %+v", step)
+	}
+	if step.Replacements[0].Status != TxStateMined {
+		t.Fatalf("expected cancel replacement to be mined, got %+v", step.Replacements[0])
+	}
+}
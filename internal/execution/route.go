@@ -0,0 +1,70 @@
+package execution
+
+import "strings"
+
+// Route actions chain a bridge leg and a destination-chain swap leg built by
+// different providers. Each step records its leg, the provider that built it,
+// and the leg's input amount in ExpectedOutputs, so policy checks validate the
+// step against its own provider instead of the action-level fields.
+const (
+	StepOutputRouteLeg       = "route_leg"
+	StepOutputLegProvider    = "leg_provider"
+	StepOutputLegInputAmount = "leg_input_amount"
+)
+
+const (
+	RouteLegBridge = "bridge"
+	RouteLegSwap   = "swap"
+)
+
+// RouteLegSteps returns the indexes of the steps tagged with leg, in order.
+func RouteLegSteps(action *Action, leg string) []int {
+	if action == nil {
+		return nil
+	}
+	var out []int
+	for i := range action.Steps {
+		if action.Steps[i].ExpectedOutputs[StepOutputRouteLeg] == leg {
+			out = append(out, i)
+		}
+	}
+	return out
+}
+
+// TagRouteLeg marks every step as part of leg, built by provider, spending
+// inputAmount base units.
+func TagRouteLeg(steps []ActionStep, leg, provider, inputAmount string) {
+	for i := range steps {
+		setStepOutput(&steps[i], StepOutputRouteLeg, leg)
+		setStepOutput(&steps[i], StepOutputLegProvider, provider)
+		setStepOutput(&steps[i], StepOutputLegInputAmount, inputAmount)
+	}
+}
+
+// stepProvider is the provider whose payload step carries: its leg provider
+// on route actions, otherwise the action provider.
+func stepProvider(action *Action, step *ActionStep) string {
+	if step != nil {
+		if provider := strings.TrimSpace(step.ExpectedOutputs[StepOutputLegProvider]); provider != "" {
+			return provider
+		}
+	}
+	if action == nil {
+		return ""
+	}
+	return strings.TrimSpace(action.Provider)
+}
+
+// stepInputAmount bounds approvals for step: its leg input amount on route
+// actions, otherwise the action input amount.
+func stepInputAmount(action *Action, step *ActionStep) string {
+	if step != nil {
+		if amount := strings.TrimSpace(step.ExpectedOutputs[StepOutputLegInputAmount]); amount != "" {
+			return amount
+		}
+	}
+	if action == nil {
+		return ""
+	}
+	return action.InputAmount
+}
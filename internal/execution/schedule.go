@@ -0,0 +1,220 @@
+package execution
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MinScheduleInterval is the shortest interval a schedule may repeat at.
+const MinScheduleInterval = time.Minute
+
+// Schedule is a recurring plan invocation. Each run plans a fresh action from
+// Template and submits it; Spent and Runs are checked against the caps before
+// anything is signed.
+type Schedule struct {
+	Name            string   `json:"name"`
+	Template        Template `json:"template"`
+	Every           string   `json:"every"`
+	IntervalSeconds int64    `json:"interval_seconds"`
+	NextRunAt       string   `json:"next_run_at"`
+	MaxPerRun       string   `json:"max_per_run,omitempty"`
+	MaxTotal        string   `json:"max_total,omitempty"`
+	MaxRuns         int      `json:"max_runs,omitempty"`
+	Spent           string   `json:"spent"`
+	Runs            int      `json:"runs"`
+	LastRunAt       string   `json:"last_run_at,omitempty"`
+	LastActionID    string   `json:"last_action_id,omitempty"`
+	LastError       string   `json:"last_error,omitempty"`
+	CreatedAt       string   `json:"created_at"`
+}
+
+// ScheduleRun reports what `schedule run` did with one due schedule.
+type ScheduleRun struct {
+	Name      string `json:"name"`
+	Status    string `json:"status"`
+	ActionID  string `json:"action_id,omitempty"`
+	Amount    string `json:"amount,omitempty"`
+	Spent     string `json:"spent"`
+	NextRunAt string `json:"next_run_at"`
+	Error     string `json:"error,omitempty"`
+}
+
+// Schedule run statuses.
+const (
+	ScheduleRunCompleted = "completed"
+	ScheduleRunFailed    = "failed"
+	ScheduleRunCapped    = "cap_reached"
+)
+
+// ScheduleCaps bounds how much a schedule may spend, in base units of the
+// planned action's input asset. Empty or zero values leave a cap unset.
+type ScheduleCaps struct {
+	MaxPerRun string
+	MaxTotal  string
+	MaxRuns   int
+}
+
+// ParseScheduleInterval parses a repeat interval. Besides Go durations
+// ("30m", "12h") it accepts whole days and weeks ("1d", "2w").
+func ParseScheduleInterval(every string) (time.Duration, error) {
+	every = strings.ToLower(strings.TrimSpace(every))
+	var interval time.Duration
+	switch {
+	case strings.HasSuffix(every, "d"), strings.HasSuffix(every, "w"):
+		unit := 24 * time.Hour
+		if strings.HasSuffix(every, "w") {
+			unit *= 7
+		}
+		count, err := strconv.Atoi(every[:len(every)-1])
+		if err != nil || count <= 0 {
+			return 0, fmt.Errorf("invalid interval %q", every)
+		}
+		interval = time.Duration(count) * unit
+	default:
+		parsed, err := time.ParseDuration(every)
+		if err != nil {
+			return 0, fmt.Errorf("invalid interval %q", every)
+		}
+		interval = parsed
+	}
+	if interval < MinScheduleInterval {
+		return 0, fmt.Errorf("interval must be at least %s", MinScheduleInterval)
+	}
+	return interval, nil
+}
+
+// NewSchedule builds a schedule that re-plans action's recorded invocation
+// every interval, starting at start. now stamps CreatedAt. Only swap and
+// lend plans can recur.
+func NewSchedule(name string, action Action, every string, start, now time.Time, caps ScheduleCaps) (Schedule, error) {
+	interval, err := ParseScheduleInterval(every)
+	if err != nil {
+		return Schedule{}, err
+	}
+	tmpl, err := NewTemplate(name, action)
+	if err != nil {
+		return Schedule{}, err
+	}
+	if tmpl.Command != "swap plan" && !(strings.HasPrefix(tmpl.Command, "lend ") && strings.HasSuffix(tmpl.Command, " plan")) {
+		return Schedule{}, fmt.Errorf("schedules support swap and lend plans, not %q", tmpl.Command)
+	}
+	for label, value := range map[string]string{"max per run": caps.MaxPerRun, "max total": caps.MaxTotal} {
+		if _, err := parseScheduleAmount(value); err != nil {
+			return Schedule{}, fmt.Errorf("%s: %w", label, err)
+		}
+	}
+	if caps.MaxRuns < 0 {
+		return Schedule{}, fmt.Errorf("max runs must be non-negative")
+	}
+	return Schedule{
+		Name:            name,
+		Template:        tmpl,
+		Every:           strings.TrimSpace(every),
+		IntervalSeconds: int64(interval / time.Second),
+		NextRunAt:       start.UTC().Format(time.RFC3339),
+		MaxPerRun:       strings.TrimSpace(caps.MaxPerRun),
+		MaxTotal:        strings.TrimSpace(caps.MaxTotal),
+		MaxRuns:         caps.MaxRuns,
+		Spent:           "0",
+		CreatedAt:       now.UTC().Format(time.RFC3339),
+	}, nil
+}
+
+// Due reports whether the schedule should run at now.
+func (s Schedule) Due(now time.Time) bool {
+	next, err := time.Parse(time.RFC3339, s.NextRunAt)
+	return err != nil || !next.After(now)
+}
+
+// Exhausted reports whether the schedule used up its run or total spend cap.
+func (s Schedule) Exhausted() bool {
+	if s.MaxRuns > 0 && s.Runs >= s.MaxRuns {
+		return true
+	}
+	limit, _ := parseScheduleAmount(s.MaxTotal)
+	spent, _ := parseScheduleAmount(s.Spent)
+	return limit.Sign() > 0 && spent.Cmp(limit) >= 0
+}
+
+// CheckSpend reports whether a run spending amount base units stays within
+// the schedule's caps.
+func (s Schedule) CheckSpend(amount string) error {
+	if s.MaxRuns > 0 && s.Runs >= s.MaxRuns {
+		return fmt.Errorf("schedule %s reached max runs (%d)", s.Name, s.MaxRuns)
+	}
+	value, err := parseScheduleAmount(amount)
+	if err != nil {
+		return fmt.Errorf("schedule %s: planned amount: %w", s.Name, err)
+	}
+	if limit, _ := parseScheduleAmount(s.MaxPerRun); limit.Sign() > 0 && value.Cmp(limit) > 0 {
+		return fmt.Errorf("schedule %s: amount %s exceeds max per run %s", s.Name, value, limit)
+	}
+	if limit, _ := parseScheduleAmount(s.MaxTotal); limit.Sign() > 0 {
+		spent, _ := parseScheduleAmount(s.Spent)
+		if total := new(big.Int).Add(spent, value); total.Cmp(limit) > 0 {
+			return fmt.Errorf("schedule %s: amount %s would bring spend to %s, above max total %s", s.Name, value, total, limit)
+		}
+	}
+	return nil
+}
+
+// RecordRun books a completed run and moves NextRunAt past now.
+func (s *Schedule) RecordRun(now time.Time, actionID, amount string) {
+	spent, _ := parseScheduleAmount(s.Spent)
+	value, _ := parseScheduleAmount(amount)
+	s.Spent = new(big.Int).Add(spent, value).String()
+	s.Runs++
+	s.LastActionID = actionID
+	s.LastError = ""
+	s.advance(now)
+}
+
+// RecordFailure notes a failed run and moves NextRunAt past now so a broken
+// schedule is retried at its next slot instead of on every invocation. When
+// the run already broadcast a step, spent is its input amount and is booked
+// against the caps like a completed run; otherwise spent is empty.
+func (s *Schedule) RecordFailure(now time.Time, actionID, spent string, err error) {
+	if spent != "" {
+		total, _ := parseScheduleAmount(s.Spent)
+		value, _ := parseScheduleAmount(spent)
+		s.Spent = new(big.Int).Add(total, value).String()
+		s.Runs++
+	}
+	if actionID != "" {
+		s.LastActionID = actionID
+	}
+	s.LastError = err.Error()
+	s.advance(now)
+}
+
+// advance skips missed slots rather than running them back to back.
+func (s *Schedule) advance(now time.Time) {
+	s.LastRunAt = now.UTC().Format(time.RFC3339)
+	interval := time.Duration(s.IntervalSeconds) * time.Second
+	if interval < MinScheduleInterval {
+		interval = MinScheduleInterval
+	}
+	next, err := time.Parse(time.RFC3339, s.NextRunAt)
+	if err != nil {
+		next = now
+	}
+	for !next.After(now) {
+		next = next.Add(interval)
+	}
+	s.NextRunAt = next.UTC().Format(time.RFC3339)
+}
+
+func parseScheduleAmount(value string) (*big.Int, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return new(big.Int), nil
+	}
+	out, ok := new(big.Int).SetString(value, 10)
+	if !ok || out.Sign() < 0 {
+		return nil, fmt.Errorf("amount must be a non-negative integer in base units, got %q", value)
+	}
+	return out, nil
+}
@@ -0,0 +1,190 @@
+package execution
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func scheduleSourceAction() Action {
+	action := NewAction(NewActionID(), "swap", "eip155:8453", Constraints{Simulate: true})
+	action.Metadata = map[string]any{
+		MetadataPlanCommand: "swap plan",
+		MetadataPlanFlags: map[string]any{
+			"provider":   "odos",
+			"chain":      "base",
+			"from-asset": "USDC",
+			"to-asset":   "WETH",
+			"amount":     "100000000",
+			"wallet":     "dca",
+		},
+	}
+	return action
+}
+
+func TestParseScheduleInterval(t *testing.T) {
+	cases := map[string]time.Duration{
+		"1d":  24 * time.Hour,
+		"2w":  14 * 24 * time.Hour,
+		"12h": 12 * time.Hour,
+		"90m": 90 * time.Minute,
+	}
+	for input, want := range cases {
+		got, err := ParseScheduleInterval(input)
+		if err != nil || got != want {
+			t.Fatalf("ParseScheduleInterval(%q) = %s, %v; want %s", input, got, err, want)
+		}
+	}
+	for _, input := range []string{"", "0d", "-1d", "daily", "30s"} {
+		if _, err := ParseScheduleInterval(input); err == nil {
+			t.Fatalf("expected ParseScheduleInterval(%q) to fail", input)
+		}
+	}
+}
+
+func TestNewScheduleRejectsUnsupportedCommands(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	if _, err := NewSchedule("dca", scheduleSourceAction(), "1d", start, start, ScheduleCaps{}); err != nil {
+		t.Fatalf("NewSchedule failed for swap plan: %v", err)
+	}
+	if _, err := NewSchedule("payout", templateSourceAction(), "1d", start, start, ScheduleCaps{}); err == nil {
+		t.Fatal("expected transfer plan schedules to be rejected")
+	}
+	if _, err := NewSchedule("dca", scheduleSourceAction(), "1d", start, start, ScheduleCaps{MaxTotal: "1.5"}); err == nil {
+		t.Fatal("expected non-integer cap to be rejected")
+	}
+}
+
+func TestScheduleSpendCapsAndAdvance(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	schedule, err := NewSchedule("dca", scheduleSourceAction(), "1d", start, start, ScheduleCaps{MaxPerRun: "150", MaxTotal: "250"})
+	if err != nil {
+		t.Fatalf("NewSchedule failed: %v", err)
+	}
+	if schedule.Due(start.Add(-time.Minute)) || !schedule.Due(start) {
+		t.Fatalf("unexpected due state for next run %s", schedule.NextRunAt)
+	}
+	if err := schedule.CheckSpend("200"); err == nil {
+		t.Fatal("expected per-run cap to reject 200")
+	}
+	if err := schedule.CheckSpend("100"); err != nil {
+		t.Fatalf("expected 100 within caps, got %v", err)
+	}
+
+	// A run three days late books once and skips the missed slots.
+	late := start.Add(72*time.Hour + time.Hour)
+	schedule.RecordRun(late, "act_1", "100")
+	schedule.RecordRun(late, "act_2", "100")
+	if schedule.Spent != "200" || schedule.Runs != 2 {
+		t.Fatalf("unexpected spend tracking: spent=%s runs=%d", schedule.Spent, schedule.Runs)
+	}
+	if want := start.Add(96 * time.Hour).Format(time.RFC3339); schedule.NextRunAt != want {
+		t.Fatalf("expected next run %s, got %s", want, schedule.NextRunAt)
+	}
+	if err := schedule.CheckSpend("100"); err == nil {
+		t.Fatal("expected total cap to reject a run past 250")
+	}
+	if schedule.Exhausted() {
+		t.Fatal("did not expect schedule to be exhausted below its total cap")
+	}
+	schedule.RecordRun(late, "act_3", "50")
+	if !schedule.Exhausted() {
+		t.Fatal("expected schedule to be exhausted at its total cap")
+	}
+}
+
+func TestStoreScheduleLifecycle(t *testing.T) {
+	dir := t.TempDir()
+	store, err := OpenStore(filepath.Join(dir, "actions.db"), filepath.Join(dir, "actions.lock"))
+	if err != nil {
+		t.Fatalf("OpenStore failed: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	schedule, err := NewSchedule("dca", scheduleSourceAction(), "1d", time.Now(), time.Now(), ScheduleCaps{MaxRuns: 3})
+	if err != nil {
+		t.Fatalf("NewSchedule failed: %v", err)
+	}
+	if err := store.CreateSchedule(schedule); err != nil {
+		t.Fatalf("CreateSchedule failed: %v", err)
+	}
+	if err := store.CreateSchedule(schedule); !errors.Is(err, ErrScheduleExists) {
+		t.Fatalf("expected ErrScheduleExists, got %v", err)
+	}
+	schedule.RecordRun(time.Now(), "act_1", "100000000")
+	if err := store.UpdateSchedule(schedule); err != nil {
+		t.Fatalf("UpdateSchedule failed: %v", err)
+	}
+	got, err := store.GetSchedule("dca")
+	if err != nil {
+		t.Fatalf("GetSchedule failed: %v", err)
+	}
+	if got.Runs != 1 || got.Spent != "100000000" || got.Template.Command != "swap plan" {
+		t.Fatalf("unexpected stored schedule: %+v", got)
+	}
+	items, err := store.ListSchedules()
+	if err != nil || len(items) != 1 {
+		t.Fatalf("ListSchedules returned %d items, err=%v", len(items), err)
+	}
+	if err := store.DeleteSchedule("dca"); err != nil {
+		t.Fatalf("DeleteSchedule failed: %v", err)
+	}
+	if _, err := store.GetSchedule("dca"); !errors.Is(err, ErrScheduleNotFound) {
+		t.Fatalf("expected ErrScheduleNotFound, got %v", err)
+	}
+}
+
+func TestStoreClaimScheduleTakesEachSlotOnce(t *testing.T) {
+	dir := t.TempDir()
+	store, err := OpenStore(filepath.Join(dir, "actions.db"), filepath.Join(dir, "actions.lock"))
+	if err != nil {
+		t.Fatalf("OpenStore failed: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	schedule, err := NewSchedule("dca", scheduleSourceAction(), "1h", start, start.Add(-time.Hour), ScheduleCaps{MaxTotal: "250"})
+	if err != nil {
+		t.Fatalf("NewSchedule failed: %v", err)
+	}
+	if schedule.CreatedAt != "2025-12-31T23:00:00Z" {
+		t.Fatalf("expected CreatedAt from the given clock, got %s", schedule.CreatedAt)
+	}
+	if err := store.CreateSchedule(schedule); err != nil {
+		t.Fatalf("CreateSchedule failed: %v", err)
+	}
+
+	now := start.Add(time.Minute)
+	claimed, ok, err := store.ClaimSchedule(schedule, now)
+	if err != nil || !ok {
+		t.Fatalf("expected the first claim to succeed, ok=%v err=%v", ok, err)
+	}
+	if claimed.NextRunAt != "2026-01-01T01:00:00Z" {
+		t.Fatalf("expected the claim to advance the next run, got %s", claimed.NextRunAt)
+	}
+	// An overlapping invocation that loaded the schedule before the claim
+	// must not run the same slot.
+	if _, ok, err := store.ClaimSchedule(schedule, now); err != nil || ok {
+		t.Fatalf("expected a second claim of the same slot to fail, ok=%v err=%v", ok, err)
+	}
+
+	// Outcomes of overlapping runs accumulate instead of overwriting.
+	for _, amount := range []string{"100", "100"} {
+		if _, err := store.ModifySchedule("dca", func(current *Schedule) { current.RecordRun(now, "act", amount) }); err != nil {
+			t.Fatalf("ModifySchedule failed: %v", err)
+		}
+	}
+	broadcastFailure := errors.New("receipt timeout")
+	got, err := store.ModifySchedule("dca", func(current *Schedule) { current.RecordFailure(now, "act_3", "50", broadcastFailure) })
+	if err != nil {
+		t.Fatalf("ModifySchedule failed: %v", err)
+	}
+	if got.Spent != "250" || got.Runs != 3 || got.LastError != "receipt timeout" || !got.Exhausted() {
+		t.Fatalf("expected broadcast failures to book spend, got %+v", got)
+	}
+	got, err = store.ModifySchedule("dca", func(current *Schedule) { current.RecordFailure(now, "act_4", "", broadcastFailure) })
+	if err != nil || got.Spent != "250" || got.Runs != 3 {
+		t.Fatalf("expected a failure before broadcast to book nothing, got %+v err=%v", got, err)
+	}
+}
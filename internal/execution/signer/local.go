@@ -2,6 +2,7 @@ package signer
 
 import (
 	"crypto/ecdsa"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"math/big"
@@ -14,6 +15,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ggonzalez94/defi-cli/internal/fsutil"
+	"github.com/ggonzalez94/defi-cli/internal/logx"
 )
 
 const (
@@ -58,6 +60,13 @@ func (s *LocalSigner) SignTx(chainID *big.Int, tx *types.Transaction) (*types.Tr
 	return types.SignTx(tx, signer, s.privateKey)
 }
 
+func (s *LocalSigner) SignHash(hash []byte) ([]byte, error) {
+	if s == nil || s.privateKey == nil {
+		return nil, errors.New("local signer is not initialized")
+	}
+	return crypto.Sign(hash, s.privateKey)
+}
+
 func NewLocalSignerFromEnv(source string) (*LocalSigner, error) {
 	return NewLocalSignerFromInputs(source, "")
 }
@@ -149,6 +158,9 @@ func NewLocalSigner(cfg LocalSignerConfig) (*LocalSigner, error) {
 		return nil, fmt.Errorf("invalid ECDSA public key")
 	}
 	addr := crypto.PubkeyToAddress(*pub)
+	// Never let the key reach logs or the action store, even when an error
+	// message or provider payload echoes it.
+	logx.RegisterSecret(hex.EncodeToString(crypto.FromECDSA(pk)))
 	return &LocalSigner{privateKey: pk, address: addr}, nil
 }
 
@@ -0,0 +1,150 @@
+package signer
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ggonzalez94/defi-cli/internal/fsutil"
+)
+
+// RotationMessage is the statement both the outgoing and incoming keys sign
+// during a rotation, binding the two addresses together.
+func RotationMessage(oldAddress, newAddress common.Address, issuedAt time.Time) string {
+	return fmt.Sprintf(
+		"defi-cli signer rotation\nold: %s\nnew: %s\nissued_at: %s",
+		oldAddress.Hex(),
+		newAddress.Hex(),
+		issuedAt.UTC().Format(time.RFC3339),
+	)
+}
+
+// SignMessage signs message as an EIP-191 personal message. The returned
+// signature uses V in {27, 28} as expected by personal_sign verifiers.
+func (s *LocalSigner) SignMessage(message []byte) ([]byte, error) {
+	signature, err := s.SignHash(accounts.TextHash(message))
+	if err != nil {
+		return nil, err
+	}
+	signature[crypto.RecoveryIDOffset] += 27
+	return signature, nil
+}
+
+// VerifyMessage reports an error unless signature is an EIP-191 signature of
+// message by address.
+func VerifyMessage(address common.Address, message []byte, signature []byte) error {
+	if len(signature) != crypto.SignatureLength {
+		return fmt.Errorf("signature must be %d bytes", crypto.SignatureLength)
+	}
+	sig := append([]byte{}, signature...)
+	if sig[crypto.RecoveryIDOffset] >= 27 {
+		sig[crypto.RecoveryIDOffset] -= 27
+	}
+	pub, err := crypto.SigToPub(accounts.TextHash(message), sig)
+	if err != nil {
+		return fmt.Errorf("recover signer: %w", err)
+	}
+	if recovered := crypto.PubkeyToAddress(*pub); recovered != address {
+		return fmt.Errorf("signature recovers to %s, expected %s", recovered.Hex(), address.Hex())
+	}
+	return nil
+}
+
+// EncodeSignature formats a signature as 0x-prefixed hex.
+func EncodeSignature(signature []byte) string {
+	return hexutil.Encode(signature)
+}
+
+// ConfiguredPrivateKeyFile returns the key file the local signer loads when
+// no --private-key is passed: DEFI_PRIVATE_KEY_FILE when set, otherwise the
+// default config path. It fails when DEFI_PRIVATE_KEY is set, because the env
+// key would keep taking precedence over any rotated file.
+func ConfiguredPrivateKeyFile() (string, error) {
+	if strings.TrimSpace(os.Getenv(EnvPrivateKey)) != "" {
+		return "", fmt.Errorf("%s is set and takes precedence over key files; unset it before rotating", EnvPrivateKey)
+	}
+	if path := strings.TrimSpace(os.Getenv(EnvPrivateKeyFile)); path != "" {
+		return fsutil.NormalizePath(path)
+	}
+	path := defaultPrivateKeyPath()
+	if path == "" {
+		return "", errors.New("cannot resolve default private key path")
+	}
+	return path, nil
+}
+
+// InstallPrivateKeyFile writes key to path with owner-only permissions. An
+// existing file is kept as path.rotated-<unix> so the outgoing key can be
+// recovered; the backup path is returned ("" when there was no file).
+func InstallPrivateKeyFile(path string, key *ecdsa.PrivateKey, now time.Time) (string, error) {
+	if key == nil {
+		return "", errors.New("missing private key")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return "", fmt.Errorf("create key directory: %w", err)
+	}
+	backup := ""
+	if info, err := os.Stat(path); err == nil {
+		if info.IsDir() {
+			return "", fmt.Errorf("key path %s is a directory", path)
+		}
+		backup = fmt.Sprintf("%s.rotated-%d", path, now.UTC().Unix())
+		if err := copyFile(path, backup); err != nil {
+			return "", fmt.Errorf("back up current key: %w", err)
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("stat key file: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".key-rotate-*")
+	if err != nil {
+		return "", fmt.Errorf("create temp key file: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if err := tmp.Chmod(0o600); err != nil {
+		_ = tmp.Close()
+		return "", fmt.Errorf("restrict temp key file: %w", err)
+	}
+	if _, err := tmp.WriteString(hexutil.Encode(crypto.FromECDSA(key)) + "\n"); err != nil {
+		_ = tmp.Close()
+		return "", fmt.Errorf("write temp key file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("close temp key file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", fmt.Errorf("install key file: %w", err)
+	}
+	return backup, nil
+}
+
+func copyFile(src, dst string) error {
+	buf, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(dst, buf, 0o600)
+}
+
+// RestorePrivateKeyFile undoes InstallPrivateKeyFile: it moves backup back to
+// path, or removes path when there was no previous key.
+func RestorePrivateKeyFile(path, backup string) error {
+	if backup == "" {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("remove installed key: %w", err)
+		}
+		return nil
+	}
+	if err := os.Rename(backup, path); err != nil {
+		return fmt.Errorf("restore previous key: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package signer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestSignMessageVerifiesAgainstSignerOnly(t *testing.T) {
+	s, err := NewLocalSigner(LocalSignerConfig{PrivateKeyHex: testPrivateKey})
+	if err != nil {
+		t.Fatalf("NewLocalSigner failed: %v", err)
+	}
+	message := []byte(RotationMessage(s.Address(), common.HexToAddress("0x00000000000000000000000000000000000000bb"), time.Unix(0, 0)))
+	signature, err := s.SignMessage(message)
+	if err != nil {
+		t.Fatalf("SignMessage failed: %v", err)
+	}
+	if v := signature[64]; v != 27 && v != 28 {
+		t.Fatalf("expected personal_sign V in {27,28}, got %d", v)
+	}
+	if err := VerifyMessage(s.Address(), message, signature); err != nil {
+		t.Fatalf("VerifyMessage failed: %v", err)
+	}
+	if err := VerifyMessage(common.HexToAddress("0x00000000000000000000000000000000000000bb"), message, signature); err == nil {
+		t.Fatal("expected verification against another address to fail")
+	}
+	if err := VerifyMessage(s.Address(), []byte("tampered"), signature); err == nil {
+		t.Fatal("expected verification of a different message to fail")
+	}
+}
+
+func TestInstallPrivateKeyFileBacksUpExistingKey(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "defi", "key.hex")
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte("old-key"), 0o600); err != nil {
+		t.Fatalf("write key: %v", err)
+	}
+	next, err := NewLocalSigner(LocalSignerConfig{PrivateKeyHex: testPrivateKey})
+	if err != nil {
+		t.Fatalf("NewLocalSigner failed: %v", err)
+	}
+
+	backup, err := InstallPrivateKeyFile(path, next.PrivateKey(), time.Unix(1700000000, 0))
+	if err != nil {
+		t.Fatalf("InstallPrivateKeyFile failed: %v", err)
+	}
+	if backup != path+".rotated-1700000000" {
+		t.Fatalf("unexpected backup path: %s", backup)
+	}
+	if buf, _ := os.ReadFile(backup); string(buf) != "old-key" {
+		t.Fatalf("expected backup to hold the old key, got %q", buf)
+	}
+	installed, err := NewLocalSigner(LocalSignerConfig{PrivateKeyFile: path})
+	if err != nil {
+		t.Fatalf("load installed key: %v", err)
+	}
+	if installed.Address() != next.Address() {
+		t.Fatalf("installed key address %s, want %s", installed.Address().Hex(), next.Address().Hex())
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("stat key: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Fatalf("expected 0600 key permissions, got %o", perm)
+	}
+
+	if err := RestorePrivateKeyFile(path, backup); err != nil {
+		t.Fatalf("RestorePrivateKeyFile failed: %v", err)
+	}
+	if buf, _ := os.ReadFile(path); string(buf) != "old-key" {
+		t.Fatalf("expected restore to put the old key back, got %q", buf)
+	}
+	if _, err := os.Stat(backup); !os.IsNotExist(err) {
+		t.Fatalf("expected backup to be moved back, got %v", err)
+	}
+}
+
+func TestConfiguredPrivateKeyFilePrecedence(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv(EnvPrivateKey, "")
+	t.Setenv(EnvPrivateKeyFile, "")
+	path, err := ConfiguredPrivateKeyFile()
+	if err != nil || path != filepath.Join(dir, "defi", "key.hex") {
+		t.Fatalf("expected default key path, got %q (%v)", path, err)
+	}
+
+	override := filepath.Join(dir, "custom.hex")
+	t.Setenv(EnvPrivateKeyFile, override)
+	if path, err := ConfiguredPrivateKeyFile(); err != nil || path != override {
+		t.Fatalf("expected %s override, got %q (%v)", EnvPrivateKeyFile, path, err)
+	}
+
+	t.Setenv(EnvPrivateKey, testPrivateKey)
+	if _, err := ConfiguredPrivateKeyFile(); err == nil || !strings.Contains(err.Error(), EnvPrivateKey) {
+		t.Fatalf("expected %s precedence error, got %v", EnvPrivateKey, err)
+	}
+}
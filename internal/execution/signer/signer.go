@@ -11,3 +11,10 @@ type Signer interface {
 	Address() common.Address
 	SignTx(chainID *big.Int, tx *types.Transaction) (*types.Transaction, error)
 }
+
+// HashSigner signs 32-byte digests such as EIP-712 typed-data hashes used by
+// off-chain orders. Signatures are 65 bytes [R || S || V] with V in {0, 1}.
+type HashSigner interface {
+	Address() common.Address
+	SignHash(hash []byte) ([]byte, error)
+}
@@ -2,10 +2,11 @@ package execution
 
 import (
 	"context"
+	"crypto/cipher"
 	"database/sql"
-	"encoding/json"
 	"errors"
 	"fmt"
+	"math/big"
 	"os"
 	"path/filepath"
 	"strings"
@@ -18,6 +19,11 @@ import (
 type Store struct {
 	db   *sql.DB
 	lock *flock.Flock
+	// aead seals payloads once the store is encrypted; see store_crypto.go.
+	aead      cipher.AEAD
+	keySource string
+	// onTransition is called after a save changes an action's status.
+	onTransition func(previous ActionStatus, action Action)
 }
 
 func OpenStore(path, lockPath string) (*Store, error) {
@@ -45,6 +51,34 @@ func OpenStore(path, lockPath string) (*Store, error) {
 			payload BLOB NOT NULL
 		);`,
 		"CREATE INDEX IF NOT EXISTS idx_actions_status_updated ON actions(status, updated_at DESC);",
+		`CREATE TABLE IF NOT EXISTS templates (
+			name TEXT PRIMARY KEY,
+			created_at INTEGER NOT NULL,
+			payload BLOB NOT NULL
+		);`,
+		`CREATE TABLE IF NOT EXISTS schedules (
+			name TEXT PRIMARY KEY,
+			created_at INTEGER NOT NULL,
+			payload BLOB NOT NULL
+		);`,
+		`CREATE TABLE IF NOT EXISTS spend_log (
+			action_id TEXT PRIMARY KEY,
+			chain_id TEXT NOT NULL,
+			token TEXT NOT NULL,
+			amount TEXT NOT NULL,
+			created_at INTEGER NOT NULL
+		);`,
+		"CREATE INDEX IF NOT EXISTS idx_spend_log_token_created ON spend_log(chain_id, token, created_at);",
+		`CREATE TABLE IF NOT EXISTS audit_log (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			event TEXT NOT NULL,
+			created_at INTEGER NOT NULL,
+			payload BLOB NOT NULL
+		);`,
+		`CREATE TABLE IF NOT EXISTS store_meta (
+			name TEXT PRIMARY KEY,
+			value BLOB NOT NULL
+		);`,
 	}
 	for _, q := range queries {
 		if _, err := db.Exec(q); err != nil {
@@ -52,7 +86,12 @@ func OpenStore(path, lockPath string) (*Store, error) {
 			return nil, fmt.Errorf("init action schema: %w", err)
 		}
 	}
-	return &Store{db: db, lock: flock.New(lockPath)}, nil
+	store := &Store{db: db, lock: flock.New(lockPath)}
+	if err := store.loadCipher(); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return store, nil
 }
 
 func (s *Store) Close() error {
@@ -62,20 +101,132 @@ func (s *Store) Close() error {
 	return s.db.Close()
 }
 
+// OnTransition registers fn to run after Save or SaveComposite changes an
+// action's status, including the first save of a new action (previous is
+// empty). fn runs after the store lock is released.
+func (s *Store) OnTransition(fn func(previous ActionStatus, action Action)) {
+	s.onTransition = fn
+}
+
+// Save upserts an action. A sub-action of a composite action (ParentActionID
+// set) is written into its parent's payload instead of its own row.
 func (s *Store) Save(action Action) error {
 	if stringsTrim(action.ActionID) == "" {
 		return fmt.Errorf("save action: missing action id")
 	}
+	previous, saved, err := s.saveLocked(action)
+	if err != nil {
+		return err
+	}
+	s.notifyTransition(previous, saved)
+	return nil
+}
+
+// saveLocked saves action under the store lock and returns the saved row's
+// previous status and the action written to it (the parent of a sub-action).
+func (s *Store) saveLocked(action Action) (ActionStatus, Action, error) {
 	locked, err := s.lock.TryLockContext(context.Background(), 5*time.Second)
 	if err != nil {
-		return fmt.Errorf("lock action store: %w", err)
+		return "", Action{}, fmt.Errorf("lock action store: %w", err)
 	}
 	if !locked {
-		return fmt.Errorf("lock action store: timeout acquiring lock")
+		return "", Action{}, fmt.Errorf("lock action store: timeout acquiring lock")
+	}
+	defer func() { _ = s.lock.Unlock() }()
+
+	if parentID := stringsTrim(action.ParentActionID); parentID != "" {
+		parent, err := s.Get(parentID)
+		if err != nil {
+			return "", Action{}, fmt.Errorf("save sub-action: %w", err)
+		}
+		replaced := false
+		for i := range parent.SubActions {
+			if parent.SubActions[i].ActionID == action.ActionID {
+				parent.SubActions[i] = action
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			return "", Action{}, fmt.Errorf("save sub-action: %s is not part of %s", action.ActionID, parentID)
+		}
+		parent.UpdatedAt = action.UpdatedAt
+		action = parent
+	}
+	previous := s.status(action.ActionID)
+	if err := s.save(s.db, action); err != nil {
+		return "", Action{}, err
+	}
+	return previous, action, nil
+}
+
+// status returns the persisted status of an action, or "" when it is not
+// stored yet.
+func (s *Store) status(actionID string) ActionStatus {
+	var status string
+	if err := s.db.QueryRow("SELECT status FROM actions WHERE action_id = ?", actionID).Scan(&status); err != nil {
+		return ""
+	}
+	return ActionStatus(status)
+}
+
+func (s *Store) notifyTransition(previous ActionStatus, action Action) {
+	if s.onTransition != nil && previous != action.Status {
+		s.onTransition(previous, action)
+	}
+}
+
+// SaveComposite persists a composite action and removes the standalone rows
+// of its sub-actions in one transaction, so a composed action can no longer
+// be submitted on its own.
+func (s *Store) SaveComposite(action Action) error {
+	if stringsTrim(action.ActionID) == "" {
+		return fmt.Errorf("save action: missing action id")
+	}
+	previous, err := s.saveCompositeLocked(action)
+	if err != nil {
+		return err
+	}
+	s.notifyTransition(previous, action)
+	return nil
+}
+
+func (s *Store) saveCompositeLocked(action Action) (ActionStatus, error) {
+	locked, err := s.lock.TryLockContext(context.Background(), 5*time.Second)
+	if err != nil {
+		return "", fmt.Errorf("lock action store: %w", err)
+	}
+	if !locked {
+		return "", fmt.Errorf("lock action store: timeout acquiring lock")
 	}
 	defer func() { _ = s.lock.Unlock() }()
 
-	payload, err := json.Marshal(action)
+	previous := s.status(action.ActionID)
+	tx, err := s.db.Begin()
+	if err != nil {
+		return "", fmt.Errorf("begin composite save: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	if err := s.save(tx, action); err != nil {
+		return "", err
+	}
+	for _, sub := range action.SubActions {
+		if _, err := tx.Exec("DELETE FROM actions WHERE action_id = ?", sub.ActionID); err != nil {
+			return "", fmt.Errorf("remove composed action: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return "", fmt.Errorf("commit composite save: %w", err)
+	}
+	return previous, nil
+}
+
+type sqlExecer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+func (s *Store) save(db sqlExecer, action Action) error {
+	payload, err := s.encode(action)
 	if err != nil {
 		return fmt.Errorf("marshal action: %w", err)
 	}
@@ -88,7 +239,7 @@ func (s *Store) Save(action Action) error {
 		updatedUnix = time.Now().UTC().Unix()
 	}
 
-	_, err = s.db.Exec(`
+	_, err = db.Exec(`
 		INSERT INTO actions (action_id, intent_type, status, chain_id, created_at, updated_at, payload)
 		VALUES (?, ?, ?, ?, ?, ?, ?)
 		ON CONFLICT(action_id) DO UPDATE SET
@@ -114,13 +265,590 @@ func (s *Store) Get(actionID string) (Action, error) {
 		return Action{}, fmt.Errorf("read action: %w", err)
 	}
 	var action Action
-	if err := json.Unmarshal(payload, &action); err != nil {
+	if err := s.decode(payload, &action); err != nil {
 		return Action{}, fmt.Errorf("decode action payload: %w", err)
 	}
 	return action, nil
 }
 
 func (s *Store) List(status string, limit int) ([]Action, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	return s.Query(ActionFilter{Status: status, Limit: limit})
+}
+
+// ActionFilter selects actions for Query and Scan. Empty fields match every
+// action. IntentType matches the intent or its family, so "lend" also
+// matches "lend_supply". Address matches the sender or recipient. Limit 0
+// returns every match.
+type ActionFilter struct {
+	Status     string
+	IntentType string
+	ChainID    string
+	Address    string
+	Since      time.Time
+	Limit      int
+}
+
+// Query returns the actions matching filter, most recently updated first.
+func (s *Store) Query(filter ActionFilter) ([]Action, error) {
+	actions := make([]Action, 0)
+	err := s.Scan(filter, func(action Action) error {
+		actions = append(actions, action)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return actions, nil
+}
+
+// Scan calls fn for each action matching filter, most recently updated
+// first, without loading them all at once. An error from fn stops the scan.
+func (s *Store) Scan(filter ActionFilter, fn func(Action) error) error {
+	query := "SELECT payload FROM actions"
+	var where []string
+	var args []any
+	if status := stringsTrim(filter.Status); status != "" {
+		where = append(where, "status = ?")
+		args = append(args, status)
+	}
+	if intent := stringsTrim(filter.IntentType); intent != "" {
+		where = append(where, "(intent_type = ? OR intent_type LIKE ? ESCAPE '\\')")
+		args = append(args, intent, escapeLike(intent)+"\\_%")
+	}
+	if chainID := stringsTrim(filter.ChainID); chainID != "" {
+		where = append(where, "chain_id = ?")
+		args = append(args, chainID)
+	}
+	if !filter.Since.IsZero() {
+		where = append(where, "updated_at >= ?")
+		args = append(args, filter.Since.UTC().Unix())
+	}
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY updated_at DESC, action_id ASC"
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return fmt.Errorf("list actions: %w", err)
+	}
+	defer rows.Close()
+
+	// The sender and recipient only live in the payload, so the address
+	// filter and the limit it affects are applied while decoding.
+	address := stringsTrim(filter.Address)
+	matched := 0
+	for rows.Next() {
+		var payload []byte
+		if err := rows.Scan(&payload); err != nil {
+			return fmt.Errorf("scan action row: %w", err)
+		}
+		var action Action
+		if err := s.decode(payload, &action); err != nil {
+			return fmt.Errorf("decode action row: %w", err)
+		}
+		if address != "" && !strings.EqualFold(stringsTrim(action.FromAddress), address) && !strings.EqualFold(stringsTrim(action.ToAddress), address) {
+			continue
+		}
+		if err := fn(action); err != nil {
+			return err
+		}
+		matched++
+		if filter.Limit > 0 && matched >= filter.Limit {
+			break
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterate action rows: %w", err)
+	}
+	return nil
+}
+
+// Prune deletes actions last updated before cutoff and returns their IDs.
+// Running actions are kept, since a submit may still be broadcasting them.
+// With dryRun set, nothing is deleted.
+func (s *Store) Prune(cutoff time.Time, dryRun bool) ([]string, error) {
+	locked, err := s.lock.TryLockContext(context.Background(), 5*time.Second)
+	if err != nil {
+		return nil, fmt.Errorf("lock action store: %w", err)
+	}
+	if !locked {
+		return nil, fmt.Errorf("lock action store: timeout acquiring lock")
+	}
+	defer func() { _ = s.lock.Unlock() }()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("begin prune: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	rows, err := tx.Query("SELECT action_id FROM actions WHERE updated_at < ? AND status != ? ORDER BY updated_at ASC, action_id ASC", cutoff.UTC().Unix(), string(ActionStatusRunning))
+	if err != nil {
+		return nil, fmt.Errorf("select actions to prune: %w", err)
+	}
+	ids := make([]string, 0)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			_ = rows.Close()
+			return nil, fmt.Errorf("scan action row: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate action rows: %w", err)
+	}
+	if dryRun || len(ids) == 0 {
+		return ids, nil
+	}
+	if _, err := tx.Exec("DELETE FROM actions WHERE updated_at < ? AND status != ?", cutoff.UTC().Unix(), string(ActionStatusRunning)); err != nil {
+		return nil, fmt.Errorf("prune actions: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit prune: %w", err)
+	}
+	return ids, nil
+}
+
+func escapeLike(value string) string {
+	return strings.NewReplacer("\\", "\\\\", "%", "\\%", "_", "\\_").Replace(value)
+}
+
+// ErrTemplateExists is returned by CreateTemplate when the name is taken.
+var ErrTemplateExists = errors.New("template already exists")
+
+// ErrTemplateNotFound is returned when a named template does not exist.
+var ErrTemplateNotFound = errors.New("template not found")
+
+func (s *Store) CreateTemplate(tmpl Template) error {
+	if err := ValidateTemplateName(tmpl.Name); err != nil {
+		return err
+	}
+	locked, err := s.lock.TryLockContext(context.Background(), 5*time.Second)
+	if err != nil {
+		return fmt.Errorf("lock action store: %w", err)
+	}
+	if !locked {
+		return fmt.Errorf("lock action store: timeout acquiring lock")
+	}
+	defer func() { _ = s.lock.Unlock() }()
+
+	payload, err := s.encode(tmpl)
+	if err != nil {
+		return fmt.Errorf("marshal template: %w", err)
+	}
+	createdUnix, ok := parseRFC3339Unix(tmpl.CreatedAt)
+	if !ok {
+		createdUnix = time.Now().UTC().Unix()
+	}
+	result, err := s.db.Exec("INSERT OR IGNORE INTO templates (name, created_at, payload) VALUES (?, ?, ?)", tmpl.Name, createdUnix, payload)
+	if err != nil {
+		return fmt.Errorf("save template: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("%w: %s", ErrTemplateExists, tmpl.Name)
+	}
+	return nil
+}
+
+func (s *Store) GetTemplate(name string) (Template, error) {
+	var payload []byte
+	err := s.db.QueryRow("SELECT payload FROM templates WHERE name = ?", name).Scan(&payload)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Template{}, fmt.Errorf("%w: %s", ErrTemplateNotFound, name)
+		}
+		return Template{}, fmt.Errorf("read template: %w", err)
+	}
+	var tmpl Template
+	if err := s.decode(payload, &tmpl); err != nil {
+		return Template{}, fmt.Errorf("decode template payload: %w", err)
+	}
+	return tmpl, nil
+}
+
+func (s *Store) ListTemplates() ([]Template, error) {
+	rows, err := s.db.Query("SELECT payload FROM templates ORDER BY name ASC")
+	if err != nil {
+		return nil, fmt.Errorf("list templates: %w", err)
+	}
+	defer rows.Close()
+
+	templates := make([]Template, 0)
+	for rows.Next() {
+		var payload []byte
+		if err := rows.Scan(&payload); err != nil {
+			return nil, fmt.Errorf("scan template row: %w", err)
+		}
+		var tmpl Template
+		if err := s.decode(payload, &tmpl); err != nil {
+			return nil, fmt.Errorf("decode template row: %w", err)
+		}
+		templates = append(templates, tmpl)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate template rows: %w", err)
+	}
+	return templates, nil
+}
+
+// UpdateTemplate replaces the stored payload of an existing template.
+func (s *Store) UpdateTemplate(tmpl Template) error {
+	locked, err := s.lock.TryLockContext(context.Background(), 5*time.Second)
+	if err != nil {
+		return fmt.Errorf("lock action store: %w", err)
+	}
+	if !locked {
+		return fmt.Errorf("lock action store: timeout acquiring lock")
+	}
+	defer func() { _ = s.lock.Unlock() }()
+
+	payload, err := s.encode(tmpl)
+	if err != nil {
+		return fmt.Errorf("marshal template: %w", err)
+	}
+	result, err := s.db.Exec("UPDATE templates SET payload = ? WHERE name = ?", payload, tmpl.Name)
+	if err != nil {
+		return fmt.Errorf("update template: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("%w: %s", ErrTemplateNotFound, tmpl.Name)
+	}
+	return nil
+}
+
+func (s *Store) DeleteTemplate(name string) error {
+	locked, err := s.lock.TryLockContext(context.Background(), 5*time.Second)
+	if err != nil {
+		return fmt.Errorf("lock action store: %w", err)
+	}
+	if !locked {
+		return fmt.Errorf("lock action store: timeout acquiring lock")
+	}
+	defer func() { _ = s.lock.Unlock() }()
+
+	result, err := s.db.Exec("DELETE FROM templates WHERE name = ?", name)
+	if err != nil {
+		return fmt.Errorf("delete template: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("%w: %s", ErrTemplateNotFound, name)
+	}
+	return nil
+}
+
+// ErrScheduleExists is returned by CreateSchedule when the name is taken.
+var ErrScheduleExists = errors.New("schedule already exists")
+
+// ErrScheduleNotFound is returned when a named schedule does not exist.
+var ErrScheduleNotFound = errors.New("schedule not found")
+
+func (s *Store) CreateSchedule(schedule Schedule) error {
+	if err := ValidateTemplateName(schedule.Name); err != nil {
+		return err
+	}
+	locked, err := s.lock.TryLockContext(context.Background(), 5*time.Second)
+	if err != nil {
+		return fmt.Errorf("lock action store: %w", err)
+	}
+	if !locked {
+		return fmt.Errorf("lock action store: timeout acquiring lock")
+	}
+	defer func() { _ = s.lock.Unlock() }()
+
+	payload, err := s.encode(schedule)
+	if err != nil {
+		return fmt.Errorf("marshal schedule: %w", err)
+	}
+	createdUnix, ok := parseRFC3339Unix(schedule.CreatedAt)
+	if !ok {
+		createdUnix = time.Now().UTC().Unix()
+	}
+	result, err := s.db.Exec("INSERT OR IGNORE INTO schedules (name, created_at, payload) VALUES (?, ?, ?)", schedule.Name, createdUnix, payload)
+	if err != nil {
+		return fmt.Errorf("save schedule: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("%w: %s", ErrScheduleExists, schedule.Name)
+	}
+	return nil
+}
+
+func (s *Store) GetSchedule(name string) (Schedule, error) {
+	var payload []byte
+	err := s.db.QueryRow("SELECT payload FROM schedules WHERE name = ?", name).Scan(&payload)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Schedule{}, fmt.Errorf("%w: %s", ErrScheduleNotFound, name)
+		}
+		return Schedule{}, fmt.Errorf("read schedule: %w", err)
+	}
+	var schedule Schedule
+	if err := s.decode(payload, &schedule); err != nil {
+		return Schedule{}, fmt.Errorf("decode schedule payload: %w", err)
+	}
+	return schedule, nil
+}
+
+func (s *Store) ListSchedules() ([]Schedule, error) {
+	rows, err := s.db.Query("SELECT payload FROM schedules ORDER BY name ASC")
+	if err != nil {
+		return nil, fmt.Errorf("list schedules: %w", err)
+	}
+	defer rows.Close()
+
+	schedules := make([]Schedule, 0)
+	for rows.Next() {
+		var payload []byte
+		if err := rows.Scan(&payload); err != nil {
+			return nil, fmt.Errorf("scan schedule row: %w", err)
+		}
+		var schedule Schedule
+		if err := s.decode(payload, &schedule); err != nil {
+			return nil, fmt.Errorf("decode schedule row: %w", err)
+		}
+		schedules = append(schedules, schedule)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate schedule rows: %w", err)
+	}
+	return schedules, nil
+}
+
+// UpdateSchedule replaces the stored payload of an existing schedule.
+func (s *Store) UpdateSchedule(schedule Schedule) error {
+	locked, err := s.lock.TryLockContext(context.Background(), 5*time.Second)
+	if err != nil {
+		return fmt.Errorf("lock action store: %w", err)
+	}
+	if !locked {
+		return fmt.Errorf("lock action store: timeout acquiring lock")
+	}
+	defer func() { _ = s.lock.Unlock() }()
+
+	payload, err := s.encode(schedule)
+	if err != nil {
+		return fmt.Errorf("marshal schedule: %w", err)
+	}
+	result, err := s.db.Exec("UPDATE schedules SET payload = ? WHERE name = ?", payload, schedule.Name)
+	if err != nil {
+		return fmt.Errorf("update schedule: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("%w: %s", ErrScheduleNotFound, schedule.Name)
+	}
+	return nil
+}
+
+// ClaimSchedule takes the due slot of expected before it runs. Under the
+// store lock it checks that the stored schedule still has expected's
+// NextRunAt and Runs and is due at now, then moves NextRunAt past now. It
+// reports false when another invocation already claimed or ran the slot.
+func (s *Store) ClaimSchedule(expected Schedule, now time.Time) (Schedule, bool, error) {
+	claimed := false
+	schedule, err := s.ModifySchedule(expected.Name, func(current *Schedule) {
+		if current.NextRunAt != expected.NextRunAt || current.Runs != expected.Runs || !current.Due(now) {
+			return
+		}
+		current.advance(now)
+		claimed = true
+	})
+	if err != nil {
+		return Schedule{}, false, err
+	}
+	return schedule, claimed, nil
+}
+
+// ModifySchedule applies fn to the stored schedule and saves the result under
+// the store lock, so concurrent runs book their outcomes on the latest Spent
+// and Runs instead of overwriting each other. A nil fn only reads.
+func (s *Store) ModifySchedule(name string, fn func(*Schedule)) (Schedule, error) {
+	locked, err := s.lock.TryLockContext(context.Background(), 5*time.Second)
+	if err != nil {
+		return Schedule{}, fmt.Errorf("lock action store: %w", err)
+	}
+	if !locked {
+		return Schedule{}, fmt.Errorf("lock action store: timeout acquiring lock")
+	}
+	defer func() { _ = s.lock.Unlock() }()
+
+	schedule, err := s.GetSchedule(name)
+	if err != nil {
+		return Schedule{}, err
+	}
+	if fn == nil {
+		return schedule, nil
+	}
+	fn(&schedule)
+	payload, err := s.encode(schedule)
+	if err != nil {
+		return Schedule{}, fmt.Errorf("marshal schedule: %w", err)
+	}
+	if _, err := s.db.Exec("UPDATE schedules SET payload = ? WHERE name = ?", payload, schedule.Name); err != nil {
+		return Schedule{}, fmt.Errorf("update schedule: %w", err)
+	}
+	return schedule, nil
+}
+
+func (s *Store) DeleteSchedule(name string) error {
+	locked, err := s.lock.TryLockContext(context.Background(), 5*time.Second)
+	if err != nil {
+		return fmt.Errorf("lock action store: %w", err)
+	}
+	if !locked {
+		return fmt.Errorf("lock action store: timeout acquiring lock")
+	}
+	defer func() { _ = s.lock.Unlock() }()
+
+	result, err := s.db.Exec("DELETE FROM schedules WHERE name = ?", name)
+	if err != nil {
+		return fmt.Errorf("delete schedule: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("%w: %s", ErrScheduleNotFound, name)
+	}
+	return nil
+}
+
+// RecordSpend books the input spend of an action for rolling
+// execution policy limits. Recording the same action twice is a no-op.
+func (s *Store) RecordSpend(actionID, chainID, token string, amount *big.Int, at time.Time) error {
+	locked, err := s.lock.TryLockContext(context.Background(), 5*time.Second)
+	if err != nil {
+		return fmt.Errorf("lock action store: %w", err)
+	}
+	if !locked {
+		return fmt.Errorf("lock action store: timeout acquiring lock")
+	}
+	defer func() { _ = s.lock.Unlock() }()
+
+	if _, err := s.db.Exec("INSERT OR IGNORE INTO spend_log (action_id, chain_id, token, amount, created_at) VALUES (?, ?, ?, ?, ?)",
+		actionID, strings.ToLower(chainID), strings.ToLower(token), amount.String(), at.UTC().Unix()); err != nil {
+		return fmt.Errorf("record spend: %w", err)
+	}
+	return nil
+}
+
+// SpentSince sums the recorded spend of token on chainID since the given time.
+func (s *Store) SpentSince(chainID, token string, since time.Time) (*big.Int, error) {
+	rows, err := s.db.Query("SELECT amount FROM spend_log WHERE chain_id = ? AND token = ? AND created_at >= ?",
+		strings.ToLower(chainID), strings.ToLower(token), since.UTC().Unix())
+	if err != nil {
+		return nil, fmt.Errorf("read spend log: %w", err)
+	}
+	defer rows.Close()
+
+	total := new(big.Int)
+	for rows.Next() {
+		var raw string
+		if err := rows.Scan(&raw); err != nil {
+			return nil, fmt.Errorf("scan spend row: %w", err)
+		}
+		amount, ok := new(big.Int).SetString(raw, 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid spend amount %q", raw)
+		}
+		total.Add(total, amount)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate spend rows: %w", err)
+	}
+	return total, nil
+}
+
+// AppendAuditEvent records event in the append-only audit log.
+func (s *Store) AppendAuditEvent(event AuditEvent) error {
+	if stringsTrim(event.Event) == "" {
+		return fmt.Errorf("append audit event: missing event type")
+	}
+	locked, err := s.lock.TryLockContext(context.Background(), 5*time.Second)
+	if err != nil {
+		return fmt.Errorf("lock action store: %w", err)
+	}
+	if !locked {
+		return fmt.Errorf("lock action store: timeout acquiring lock")
+	}
+	defer func() { _ = s.lock.Unlock() }()
+
+	return s.appendAuditEvent(s.db, event)
+}
+
+func (s *Store) appendAuditEvent(db sqlExecer, event AuditEvent) error {
+	payload, err := s.encode(event)
+	if err != nil {
+		return fmt.Errorf("marshal audit event: %w", err)
+	}
+	createdUnix, ok := parseRFC3339Unix(event.CreatedAt)
+	if !ok {
+		createdUnix = time.Now().UTC().Unix()
+	}
+	if _, err := db.Exec("INSERT INTO audit_log (event, created_at, payload) VALUES (?, ?, ?)", event.Event, createdUnix, payload); err != nil {
+		return fmt.Errorf("append audit event: %w", err)
+	}
+	return nil
+}
+
+// ApplySignerRotation rewrites templates and schedules to a rotated sender
+// and appends the rotation's audit event in one transaction. install runs
+// after the rows are staged and before the commit, so the key is installed
+// last; when install fails nothing is written. install may add details, such
+// as the key backup path, to event before it is recorded.
+func (s *Store) ApplySignerRotation(templates []Template, schedules []Schedule, event AuditEvent, install func(event *AuditEvent) error) error {
+	if stringsTrim(event.Event) == "" {
+		return fmt.Errorf("append audit event: missing event type")
+	}
+	locked, err := s.lock.TryLockContext(context.Background(), 5*time.Second)
+	if err != nil {
+		return fmt.Errorf("lock action store: %w", err)
+	}
+	if !locked {
+		return fmt.Errorf("lock action store: timeout acquiring lock")
+	}
+	defer func() { _ = s.lock.Unlock() }()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin signer rotation: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	for _, tmpl := range templates {
+		payload, err := s.encode(tmpl)
+		if err != nil {
+			return fmt.Errorf("marshal template: %w", err)
+		}
+		if _, err := tx.Exec("UPDATE templates SET payload = ? WHERE name = ?", payload, tmpl.Name); err != nil {
+			return fmt.Errorf("update template: %w", err)
+		}
+	}
+	for _, schedule := range schedules {
+		payload, err := s.encode(schedule)
+		if err != nil {
+			return fmt.Errorf("marshal schedule: %w", err)
+		}
+		if _, err := tx.Exec("UPDATE schedules SET payload = ? WHERE name = ?", payload, schedule.Name); err != nil {
+			return fmt.Errorf("update schedule: %w", err)
+		}
+	}
+	if install != nil {
+		if err := install(&event); err != nil {
+			return err
+		}
+	}
+	if err := s.appendAuditEvent(tx, event); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit signer rotation: %w", err)
+	}
+	return nil
+}
+
+// ListAuditEvents returns the newest audit events first, optionally filtered
+// by event type.
+func (s *Store) ListAuditEvents(event string, limit int) ([]AuditEvent, error) {
 	if limit <= 0 {
 		limit = 20
 	}
@@ -128,32 +856,32 @@ func (s *Store) List(status string, limit int) ([]Action, error) {
 		rows *sql.Rows
 		err  error
 	)
-	if stringsTrim(status) == "" {
-		rows, err = s.db.Query("SELECT payload FROM actions ORDER BY updated_at DESC LIMIT ?", limit)
+	if stringsTrim(event) == "" {
+		rows, err = s.db.Query("SELECT payload FROM audit_log ORDER BY id DESC LIMIT ?", limit)
 	} else {
-		rows, err = s.db.Query("SELECT payload FROM actions WHERE status = ? ORDER BY updated_at DESC LIMIT ?", status, limit)
+		rows, err = s.db.Query("SELECT payload FROM audit_log WHERE event = ? ORDER BY id DESC LIMIT ?", event, limit)
 	}
 	if err != nil {
-		return nil, fmt.Errorf("list actions: %w", err)
+		return nil, fmt.Errorf("list audit events: %w", err)
 	}
 	defer rows.Close()
 
-	actions := make([]Action, 0)
+	events := make([]AuditEvent, 0)
 	for rows.Next() {
 		var payload []byte
 		if err := rows.Scan(&payload); err != nil {
-			return nil, fmt.Errorf("scan action row: %w", err)
+			return nil, fmt.Errorf("scan audit row: %w", err)
 		}
-		var action Action
-		if err := json.Unmarshal(payload, &action); err != nil {
-			return nil, fmt.Errorf("decode action row: %w", err)
+		var item AuditEvent
+		if err := s.decode(payload, &item); err != nil {
+			return nil, fmt.Errorf("decode audit row: %w", err)
 		}
-		actions = append(actions, action)
+		events = append(events, item)
 	}
 	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("iterate action rows: %w", err)
+		return nil, fmt.Errorf("iterate audit rows: %w", err)
 	}
-	return actions, nil
+	return events, nil
 }
 
 func stringsTrim(v string) string {
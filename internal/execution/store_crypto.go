@@ -0,0 +1,374 @@
+package execution
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ggonzalez94/defi-cli/internal/keyring"
+	"github.com/ggonzalez94/defi-cli/internal/logx"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	EnvActionsPassphrase     = "DEFI_ACTIONS_PASSPHRASE"
+	EnvActionsPassphraseFile = "DEFI_ACTIONS_PASSPHRASE_FILE"
+
+	StoreKeySourceKeyring    = "keyring"
+	StoreKeySourcePassphrase = "passphrase"
+
+	// KeyringService and KeyringAccount name the OS keyring entry holding
+	// the action store key.
+	KeyringService = "defi-cli"
+	KeyringAccount = "action-store"
+)
+
+// ErrStoreKeyUnavailable is returned when an encrypted store is opened
+// without its key, or with the wrong one.
+var ErrStoreKeyUnavailable = errors.New("action store key unavailable")
+
+// sealedPrefix marks an encrypted payload: prefix, 12-byte nonce, AES-256-GCM
+// ciphertext. Plaintext payloads are JSON objects, so the two never collide.
+var sealedPrefix = []byte("DEFIENC1")
+
+// keyCheckPlaintext is sealed into store_meta so a wrong key fails at open
+// instead of on the first payload read.
+const keyCheckPlaintext = "defi-cli action store"
+
+// scrypt parameters for passphrase-derived keys.
+const (
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+)
+
+// StoreEncryptResult reports an Encrypt migration.
+type StoreEncryptResult struct {
+	KeySource   string `json:"key_source"`
+	Actions     int    `json:"actions"`
+	Templates   int    `json:"templates"`
+	Schedules   int    `json:"schedules"`
+	AuditEvents int    `json:"audit_events"`
+}
+
+// KeySource returns how the store is encrypted (keyring or passphrase), or ""
+// for a plaintext store.
+func (s *Store) KeySource() string {
+	return s.keySource
+}
+
+// loadCipher reads the store's encryption metadata and resolves its key.
+func (s *Store) loadCipher() error {
+	source, err := s.meta("encryption")
+	if err != nil || source == "" {
+		return err
+	}
+	salt, err := s.meta("salt")
+	if err != nil {
+		return err
+	}
+	key, err := resolveStoreKey(source, []byte(salt), false)
+	if err != nil {
+		return err
+	}
+	aead, err := newStoreAEAD(key)
+	if err != nil {
+		return err
+	}
+	check, err := s.meta("key_check")
+	if err != nil {
+		return err
+	}
+	if plain, err := openSealed(aead, []byte(check)); err != nil || string(plain) != keyCheckPlaintext {
+		return fmt.Errorf("%w: the %s key does not decrypt this store", ErrStoreKeyUnavailable, source)
+	}
+	s.aead = aead
+	s.keySource = source
+	return nil
+}
+
+// Encrypt seals every payload in the store with a key from source and
+// records it so later opens decrypt transparently. It migrates a plaintext
+// store, re-seals rows still in plaintext, and switches an encrypted store to
+// a new key. Index columns (intent, status, chain, timestamps) and the spend
+// log stay in plaintext so queries and guardrails keep working.
+func (s *Store) Encrypt(source string) (StoreEncryptResult, error) {
+	source = strings.ToLower(strings.TrimSpace(source))
+	if source != StoreKeySourceKeyring && source != StoreKeySourcePassphrase {
+		return StoreEncryptResult{}, fmt.Errorf("unsupported key source %q (keyring|passphrase)", source)
+	}
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return StoreEncryptResult{}, fmt.Errorf("generate salt: %w", err)
+	}
+	key, err := resolveStoreKey(source, salt, true)
+	if err != nil {
+		return StoreEncryptResult{}, err
+	}
+	aead, err := newStoreAEAD(key)
+	if err != nil {
+		return StoreEncryptResult{}, err
+	}
+	check, err := seal(aead, []byte(keyCheckPlaintext))
+	if err != nil {
+		return StoreEncryptResult{}, err
+	}
+
+	locked, err := s.lock.TryLockContext(context.Background(), 5*time.Second)
+	if err != nil {
+		return StoreEncryptResult{}, fmt.Errorf("lock action store: %w", err)
+	}
+	if !locked {
+		return StoreEncryptResult{}, fmt.Errorf("lock action store: timeout acquiring lock")
+	}
+	defer func() { _ = s.lock.Unlock() }()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return StoreEncryptResult{}, fmt.Errorf("begin encrypt: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	// Overwrite the pages freed by resealing instead of leaving the
+	// plaintext payloads behind in the file.
+	if _, err := tx.Exec("PRAGMA secure_delete=ON"); err != nil {
+		return StoreEncryptResult{}, fmt.Errorf("enable secure delete: %w", err)
+	}
+
+	result := StoreEncryptResult{KeySource: source}
+	tables := []struct {
+		table, key string
+		count      *int
+	}{
+		{"actions", "action_id", &result.Actions},
+		{"templates", "name", &result.Templates},
+		{"schedules", "name", &result.Schedules},
+		{"audit_log", "id", &result.AuditEvents},
+	}
+	for _, t := range tables {
+		n, err := s.resealTable(tx, aead, t.table, t.key)
+		if err != nil {
+			return StoreEncryptResult{}, err
+		}
+		*t.count = n
+	}
+	for name, value := range map[string][]byte{"encryption": []byte(source), "salt": salt, "key_check": check} {
+		if _, err := tx.Exec("INSERT INTO store_meta (name, value) VALUES (?, ?) ON CONFLICT(name) DO UPDATE SET value=excluded.value", name, value); err != nil {
+			return StoreEncryptResult{}, fmt.Errorf("write store metadata: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return StoreEncryptResult{}, fmt.Errorf("commit encrypt: %w", err)
+	}
+	s.aead = aead
+	s.keySource = source
+	// The WAL and free pages still hold the plaintext rows; fold the WAL
+	// into the database and rebuild the file without them.
+	if _, err := s.db.Exec("PRAGMA wal_checkpoint(TRUNCATE)"); err != nil {
+		return result, fmt.Errorf("checkpoint action store: %w", err)
+	}
+	if _, err := s.db.Exec("VACUUM"); err != nil {
+		return result, fmt.Errorf("vacuum action store: %w", err)
+	}
+	return result, nil
+}
+
+// resealTable rewrites every payload of table with aead, redacting key
+// material on the way, and returns the number of rows rewritten.
+func (s *Store) resealTable(tx *sql.Tx, aead cipher.AEAD, table, key string) (int, error) {
+	rows, err := tx.Query(fmt.Sprintf("SELECT %s, payload FROM %s", key, table))
+	if err != nil {
+		return 0, fmt.Errorf("read %s: %w", table, err)
+	}
+	type row struct {
+		key     any
+		payload []byte
+	}
+	var pending []row
+	for rows.Next() {
+		var r row
+		if err := rows.Scan(&r.key, &r.payload); err != nil {
+			_ = rows.Close()
+			return 0, fmt.Errorf("scan %s row: %w", table, err)
+		}
+		pending = append(pending, r)
+	}
+	if err := rows.Close(); err != nil {
+		return 0, fmt.Errorf("iterate %s rows: %w", table, err)
+	}
+	for _, r := range pending {
+		plain, err := s.openPayload(r.payload)
+		if err != nil {
+			return 0, fmt.Errorf("decrypt %s row %v: %w", table, r.key, err)
+		}
+		sealed, err := seal(aead, redactPayload(plain))
+		if err != nil {
+			return 0, err
+		}
+		if _, err := tx.Exec(fmt.Sprintf("UPDATE %s SET payload = ? WHERE %s = ?", table, key), sealed, r.key); err != nil {
+			return 0, fmt.Errorf("rewrite %s row %v: %w", table, r.key, err)
+		}
+	}
+	return len(pending), nil
+}
+
+// encode marshals v for a payload column, redacting key material and sealing
+// it when the store is encrypted.
+func (s *Store) encode(v any) ([]byte, error) {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	payload = redactPayload(payload)
+	if s.aead == nil {
+		return payload, nil
+	}
+	return seal(s.aead, payload)
+}
+
+// decode reads a payload column written by encode, or a plaintext payload
+// written before the store was encrypted.
+func (s *Store) decode(payload []byte, v any) error {
+	plain, err := s.openPayload(payload)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(plain, v)
+}
+
+func (s *Store) openPayload(payload []byte) ([]byte, error) {
+	if !bytes.HasPrefix(payload, sealedPrefix) {
+		return payload, nil
+	}
+	if s.aead == nil {
+		return nil, fmt.Errorf("%w: payload is encrypted", ErrStoreKeyUnavailable)
+	}
+	return openSealed(s.aead, payload)
+}
+
+func (s *Store) meta(name string) (string, error) {
+	var value []byte
+	err := s.db.QueryRow("SELECT value FROM store_meta WHERE name = ?", name).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("read store metadata: %w", err)
+	}
+	return string(value), nil
+}
+
+// redactPayload replaces registered secrets and the values of key-material
+// fields in an encoded JSON payload.
+func redactPayload(payload []byte) []byte {
+	payload = []byte(logx.RedactSecrets(string(payload)))
+	if !logx.MayContainPrivateKeyField(payload) {
+		return payload
+	}
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.UseNumber()
+	var generic any
+	if err := decoder.Decode(&generic); err != nil {
+		return payload
+	}
+	redacted, err := json.Marshal(logx.RedactFields(generic))
+	if err != nil {
+		return payload
+	}
+	return redacted
+}
+
+// resolveStoreKey returns the 32-byte key for source. create generates and
+// stores a keyring key when none exists yet.
+func resolveStoreKey(source string, salt []byte, create bool) ([]byte, error) {
+	switch source {
+	case StoreKeySourcePassphrase:
+		passphrase, err := storePassphrase()
+		if err != nil {
+			return nil, err
+		}
+		logx.RegisterSecret(passphrase)
+		key, err := scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, 32)
+		if err != nil {
+			return nil, fmt.Errorf("derive store key: %w", err)
+		}
+		return key, nil
+	case StoreKeySourceKeyring:
+		secret, err := keyring.Get(KeyringService, KeyringAccount)
+		if errors.Is(err, keyring.ErrNotFound) && create {
+			key := make([]byte, 32)
+			if _, err := rand.Read(key); err != nil {
+				return nil, fmt.Errorf("generate store key: %w", err)
+			}
+			if err := keyring.Set(KeyringService, KeyringAccount, hex.EncodeToString(key)); err != nil {
+				return nil, fmt.Errorf("save store key to keyring: %w", err)
+			}
+			return key, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: read keyring entry %s/%s: %v", ErrStoreKeyUnavailable, KeyringService, KeyringAccount, err)
+		}
+		key, err := hex.DecodeString(strings.TrimSpace(secret))
+		if err != nil || len(key) != 32 {
+			return nil, fmt.Errorf("%w: keyring entry %s/%s is not a 32-byte hex key", ErrStoreKeyUnavailable, KeyringService, KeyringAccount)
+		}
+		return key, nil
+	default:
+		return nil, fmt.Errorf("unsupported store encryption %q", source)
+	}
+}
+
+func storePassphrase() (string, error) {
+	if passphrase := os.Getenv(EnvActionsPassphrase); strings.TrimSpace(passphrase) != "" {
+		return passphrase, nil
+	}
+	if path := strings.TrimSpace(os.Getenv(EnvActionsPassphraseFile)); path != "" {
+		buf, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("read %s: %w", EnvActionsPassphraseFile, err)
+		}
+		if passphrase := strings.TrimSpace(string(buf)); passphrase != "" {
+			return passphrase, nil
+		}
+	}
+	return "", fmt.Errorf("%w: set %s or %s", ErrStoreKeyUnavailable, EnvActionsPassphrase, EnvActionsPassphraseFile)
+}
+
+func newStoreAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("init store cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+func seal(aead cipher.AEAD, plain []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("generate nonce: %w", err)
+	}
+	out := append([]byte{}, sealedPrefix...)
+	out = append(out, nonce...)
+	return aead.Seal(out, nonce, plain, nil), nil
+}
+
+func openSealed(aead cipher.AEAD, payload []byte) ([]byte, error) {
+	body := bytes.TrimPrefix(payload, sealedPrefix)
+	if len(body) < aead.NonceSize() {
+		return nil, fmt.Errorf("sealed payload too short")
+	}
+	plain, err := aead.Open(nil, body[:aead.NonceSize()], body[aead.NonceSize():], nil)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt payload: %w", err)
+	}
+	return plain, nil
+}
@@ -1,8 +1,15 @@
 package execution
 
 import (
+	"errors"
+	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/ggonzalez94/defi-cli/internal/keyring"
 )
 
 func TestStoreSaveGetList(t *testing.T) {
@@ -95,3 +102,171 @@ func TestStoreSaveGetPreservesExecutionBackend(t *testing.T) {
 		t.Fatalf("wallet name mismatch: %s vs %s", got.WalletName, action.WalletName)
 	}
 }
+
+func TestStoreQueryFiltersAndPrune(t *testing.T) {
+	dir := t.TempDir()
+	store, err := OpenStore(filepath.Join(dir, "actions.db"), filepath.Join(dir, "actions.lock"))
+	if err != nil {
+		t.Fatalf("OpenStore failed: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	save := func(id, intent, chain, from string, age time.Duration, status ActionStatus) {
+		action := NewAction(id, intent, chain, Constraints{Simulate: true})
+		action.FromAddress = from
+		action.Status = status
+		action.CreatedAt = now.Add(-age).Format(time.RFC3339)
+		action.UpdatedAt = action.CreatedAt
+		if err := store.Save(action); err != nil {
+			t.Fatalf("Save %s failed: %v", id, err)
+		}
+	}
+	alice := "0x00000000000000000000000000000000000000aa"
+	bob := "0x00000000000000000000000000000000000000bb"
+	save("act_swap_new", "swap", "eip155:8453", alice, time.Hour, ActionStatusCompleted)
+	save("act_lend_new", "lend_supply", "eip155:1", bob, 2*time.Hour, ActionStatusPlanned)
+	save("act_lending_old", "lending_custom", "eip155:1", alice, 40*24*time.Hour, ActionStatusFailed)
+	save("act_bridge_old", "bridge", "eip155:1", alice, 45*24*time.Hour, ActionStatusRunning)
+
+	ids := func(filter ActionFilter) []string {
+		t.Helper()
+		items, err := store.Query(filter)
+		if err != nil {
+			t.Fatalf("Query failed: %v", err)
+		}
+		out := make([]string, len(items))
+		for i, item := range items {
+			out[i] = item.ActionID
+		}
+		return out
+	}
+	if got := ids(ActionFilter{IntentType: "lend"}); !slices.Equal(got, []string{"act_lend_new"}) {
+		t.Fatalf("expected the lend family only, got %v", got)
+	}
+	if got := ids(ActionFilter{ChainID: "eip155:1", Address: "0x" + strings.ToUpper(alice[2:])}); !slices.Equal(got, []string{"act_lending_old", "act_bridge_old"}) {
+		t.Fatalf("unexpected chain and address matches: %v", got)
+	}
+	if got := ids(ActionFilter{Since: now.Add(-3 * time.Hour)}); !slices.Equal(got, []string{"act_swap_new", "act_lend_new"}) {
+		t.Fatalf("unexpected since matches: %v", got)
+	}
+	if got := ids(ActionFilter{Address: alice, Limit: 1}); !slices.Equal(got, []string{"act_swap_new"}) {
+		t.Fatalf("expected the limit to count address matches, got %v", got)
+	}
+
+	cutoff := now.Add(-30 * 24 * time.Hour)
+	pruned, err := store.Prune(cutoff, true)
+	if err != nil || !slices.Equal(pruned, []string{"act_lending_old"}) {
+		t.Fatalf("unexpected dry-run prune: %v %v", pruned, err)
+	}
+	if got := ids(ActionFilter{}); len(got) != 4 {
+		t.Fatalf("expected a dry run to keep every action, got %v", got)
+	}
+	if pruned, err = store.Prune(cutoff, false); err != nil || !slices.Equal(pruned, []string{"act_lending_old"}) {
+		t.Fatalf("unexpected prune: %v %v", pruned, err)
+	}
+	if got := ids(ActionFilter{}); !slices.Equal(got, []string{"act_swap_new", "act_lend_new", "act_bridge_old"}) {
+		t.Fatalf("expected the old running action to survive pruning, got %v", got)
+	}
+}
+
+func TestStoreEncryptMigratesPlaintextStore(t *testing.T) {
+	dir := t.TempDir()
+	dbPath, lockPath := filepath.Join(dir, "actions.db"), filepath.Join(dir, "actions.lock")
+	store, err := OpenStore(dbPath, lockPath)
+	if err != nil {
+		t.Fatalf("OpenStore failed: %v", err)
+	}
+	action := NewAction(NewActionID(), "swap", "eip155:1", Constraints{})
+	action.FromAddress = "0x00000000000000000000000000000000000000aa"
+	action.Metadata = map[string]any{"plan_flags": map[string]any{"private-key": "0xdeadbeef", "amount": "1"}}
+	if err := store.Save(action); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if err := store.AppendAuditEvent(AuditEvent{Event: "signer_rotated", Details: map[string]string{"old_address": action.FromAddress}}); err != nil {
+		t.Fatalf("AppendAuditEvent failed: %v", err)
+	}
+
+	t.Setenv(EnvActionsPassphrase, "correct horse battery staple")
+	result, err := store.Encrypt(StoreKeySourcePassphrase)
+	if err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+	if result.Actions != 1 || result.AuditEvents != 1 || result.KeySource != StoreKeySourcePassphrase {
+		t.Fatalf("unexpected encrypt result: %+v", result)
+	}
+	var raw []byte
+	if err := store.db.QueryRow("SELECT payload FROM actions WHERE action_id = ?", action.ActionID).Scan(&raw); err != nil {
+		t.Fatalf("read raw payload: %v", err)
+	}
+	if strings.Contains(string(raw), "00000000000000000000000000000000000000aa") {
+		t.Fatal("expected the persisted payload to be encrypted")
+	}
+	for _, path := range []string{dbPath, dbPath + "-wal"} {
+		file, err := os.ReadFile(path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			t.Fatalf("read %s: %v", path, err)
+		}
+		if strings.Contains(string(file), "00000000000000000000000000000000000000aa") {
+			t.Fatalf("expected no plaintext payload left in %s", filepath.Base(path))
+		}
+	}
+	_ = store.Close()
+
+	reopened, err := OpenStore(dbPath, lockPath)
+	if err != nil {
+		t.Fatalf("reopen failed: %v", err)
+	}
+	t.Cleanup(func() { _ = reopened.Close() })
+	got, err := reopened.Get(action.ActionID)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	flags := got.Metadata["plan_flags"].(map[string]any)
+	if got.FromAddress != action.FromAddress || flags["private-key"] != "REDACTED" || flags["amount"] != "1" {
+		t.Fatalf("unexpected decrypted action: %+v", got)
+	}
+	events, err := reopened.ListAuditEvents("", 10)
+	if err != nil || len(events) != 1 || events[0].Details["old_address"] != action.FromAddress {
+		t.Fatalf("unexpected audit events %+v err=%v", events, err)
+	}
+
+	t.Setenv(EnvActionsPassphrase, "wrong passphrase")
+	if _, err := OpenStore(dbPath, lockPath); !errors.Is(err, ErrStoreKeyUnavailable) {
+		t.Fatalf("expected a wrong passphrase to be rejected, got %v", err)
+	}
+	t.Setenv(EnvActionsPassphrase, "")
+	if _, err := OpenStore(dbPath, lockPath); !errors.Is(err, ErrStoreKeyUnavailable) {
+		t.Fatalf("expected a missing passphrase to be rejected, got %v", err)
+	}
+}
+
+func TestStoreEncryptWithKeyring(t *testing.T) {
+	defer keyring.SetBackend(&keyring.MemoryBackend{})()
+	dir := t.TempDir()
+	dbPath, lockPath := filepath.Join(dir, "actions.db"), filepath.Join(dir, "actions.lock")
+	store, err := OpenStore(dbPath, lockPath)
+	if err != nil {
+		t.Fatalf("OpenStore failed: %v", err)
+	}
+	if _, err := store.Encrypt(StoreKeySourceKeyring); err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+	action := NewAction(NewActionID(), "bridge", "eip155:1", Constraints{})
+	if err := store.Save(action); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	_ = store.Close()
+
+	reopened, err := OpenStore(dbPath, lockPath)
+	if err != nil {
+		t.Fatalf("reopen failed: %v", err)
+	}
+	defer reopened.Close()
+	if reopened.KeySource() != StoreKeySourceKeyring {
+		t.Fatalf("unexpected key source %q", reopened.KeySource())
+	}
+	if _, err := reopened.Get(action.ActionID); err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+}
@@ -0,0 +1,204 @@
+package execution
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Action metadata keys recording the plan invocation an action came from.
+const (
+	MetadataPlanCommand = "plan_command"
+	MetadataPlanFlags   = "plan_flags"
+)
+
+// TemplateVariableFlags lists the plan flags a template exposes as variables.
+// Every other flag recorded on the source action is fixed.
+var TemplateVariableFlags = []string{
+	"amount",
+	"amount-decimal",
+	"amount-out",
+	"amount-out-decimal",
+	"amount-usd",
+	"asset",
+	"chain",
+	"from",
+	"from-asset",
+	"to",
+	"to-asset",
+}
+
+// templateAmountPairs maps amount flags to their mutually exclusive
+// counterparts so a template planned with --amount can run with
+// --set amount-decimal=... and vice versa.
+var templateAmountPairs = map[string][]string{
+	"amount":             {"amount-decimal", "amount-usd"},
+	"amount-decimal":     {"amount", "amount-usd"},
+	"amount-usd":         {"amount", "amount-decimal"},
+	"amount-out":         {"amount-out-decimal"},
+	"amount-out-decimal": {"amount-out"},
+}
+
+// templateAmountUSDCommands are the command groups whose plan accepts
+// --amount-usd.
+var templateAmountUSDCommands = []string{"bridge ", "lend ", "swap "}
+
+var templateNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+// Template is a named plan invocation with fixed structure. Only Variables
+// may be overridden when the template runs.
+type Template struct {
+	Name           string            `json:"name"`
+	Command        string            `json:"command"`
+	IntentType     string            `json:"intent_type"`
+	Provider       string            `json:"provider,omitempty"`
+	SourceActionID string            `json:"source_action_id"`
+	Fixed          map[string]string `json:"fixed"`
+	Variables      map[string]string `json:"variables"`
+	CreatedAt      string            `json:"created_at"`
+}
+
+// ValidateTemplateName reports whether name is a valid template name.
+func ValidateTemplateName(name string) error {
+	if !templateNamePattern.MatchString(name) {
+		return fmt.Errorf("template name must match %s", templateNamePattern.String())
+	}
+	return nil
+}
+
+// NewTemplate builds a template from the plan invocation recorded on action.
+func NewTemplate(name string, action Action) (Template, error) {
+	if err := ValidateTemplateName(name); err != nil {
+		return Template{}, err
+	}
+	command, _ := action.Metadata[MetadataPlanCommand].(string)
+	command = strings.TrimSpace(command)
+	if command == "" {
+		return Template{}, fmt.Errorf("action %s has no recorded plan command; re-plan it to create a template", action.ActionID)
+	}
+	rawFlags, _ := action.Metadata[MetadataPlanFlags].(map[string]any)
+	flags := make(map[string]string, len(rawFlags))
+	for key, value := range rawFlags {
+		str, ok := value.(string)
+		if !ok {
+			return Template{}, fmt.Errorf("action %s has invalid plan flag %q", action.ActionID, key)
+		}
+		flags[key] = str
+	}
+
+	tmpl := Template{
+		Name:           name,
+		Command:        command,
+		IntentType:     action.IntentType,
+		Provider:       action.Provider,
+		SourceActionID: action.ActionID,
+		Fixed:          map[string]string{},
+		Variables:      map[string]string{},
+		CreatedAt:      time.Now().UTC().Format(time.RFC3339),
+	}
+	for key, value := range flags {
+		if isTemplateVariable(key) {
+			tmpl.Variables[key] = value
+		} else {
+			tmpl.Fixed[key] = value
+		}
+	}
+	return tmpl, nil
+}
+
+// VariableNames returns the names accepted by Args, including the
+// alternate unit of any amount variable.
+func (t Template) VariableNames() []string {
+	names := make([]string, 0, len(t.Variables))
+	seen := map[string]struct{}{}
+	for key := range t.Variables {
+		for _, name := range append([]string{key}, t.amountAlternatives(key)...) {
+			if _, ok := seen[name]; ok {
+				continue
+			}
+			seen[name] = struct{}{}
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Args merges overrides into the template defaults and returns the full flag
+// set for the plan command. Overriding a fixed flag or an unknown name fails.
+func (t Template) Args(overrides map[string]string) (map[string]string, error) {
+	allowed := map[string]struct{}{}
+	for _, name := range t.VariableNames() {
+		allowed[name] = struct{}{}
+	}
+	out := make(map[string]string, len(t.Fixed)+len(t.Variables))
+	for key, value := range t.Fixed {
+		out[key] = value
+	}
+	for key, value := range t.Variables {
+		out[key] = value
+	}
+	keys := make([]string, 0, len(overrides))
+	for key := range overrides {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if _, ok := allowed[key]; !ok {
+			if _, fixed := t.Fixed[key]; fixed {
+				return nil, fmt.Errorf("template %s: %s is fixed and cannot be set", t.Name, key)
+			}
+			return nil, fmt.Errorf("template %s: unknown variable %s (allowed: %s)", t.Name, key, strings.Join(t.VariableNames(), ","))
+		}
+		for _, pair := range t.amountAlternatives(key) {
+			if _, ok := overrides[pair]; ok {
+				return nil, fmt.Errorf("template %s: set only one of %s and %s", t.Name, key, pair)
+			}
+			delete(out, pair)
+		}
+		out[key] = overrides[key]
+	}
+	return out, nil
+}
+
+// amountAlternatives returns the other units key can be set in. USD
+// notionals are offered only for plan commands that accept --amount-usd.
+func (t Template) amountAlternatives(key string) []string {
+	usd := false
+	for _, prefix := range templateAmountUSDCommands {
+		if strings.HasPrefix(t.Command, prefix) {
+			usd = true
+			break
+		}
+	}
+	out := []string{}
+	for _, name := range templateAmountPairs[key] {
+		if name == "amount-usd" && !usd {
+			continue
+		}
+		out = append(out, name)
+	}
+	return out
+}
+
+// ReplaceSender points a template pinned to oldAddress via --from-address at
+// newAddress. It reports whether the template changed.
+func (t *Template) ReplaceSender(oldAddress, newAddress string) bool {
+	current, ok := t.Fixed["from-address"]
+	if !ok || !strings.EqualFold(strings.TrimSpace(current), strings.TrimSpace(oldAddress)) {
+		return false
+	}
+	t.Fixed["from-address"] = newAddress
+	return true
+}
+
+func isTemplateVariable(flag string) bool {
+	for _, name := range TemplateVariableFlags {
+		if name == flag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,139 @@
+package execution
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func templateSourceAction() Action {
+	action := NewAction(NewActionID(), "transfer", "eip155:167000", Constraints{Simulate: true})
+	action.Metadata = map[string]any{
+		MetadataPlanCommand: "transfer plan",
+		MetadataPlanFlags: map[string]any{
+			"chain":     "taiko",
+			"asset":     "USDC",
+			"amount":    "1000000",
+			"recipient": "0x00000000000000000000000000000000000000bb",
+		},
+	}
+	return action
+}
+
+func TestNewTemplateSplitsFixedAndVariables(t *testing.T) {
+	tmpl, err := NewTemplate("payout", templateSourceAction())
+	if err != nil {
+		t.Fatalf("NewTemplate failed: %v", err)
+	}
+	if tmpl.Command != "transfer plan" || tmpl.IntentType != "transfer" {
+		t.Fatalf("unexpected template header: %+v", tmpl)
+	}
+	if len(tmpl.Fixed) != 1 || tmpl.Fixed["recipient"] == "" {
+		t.Fatalf("expected recipient as the only fixed flag, got %+v", tmpl.Fixed)
+	}
+	if len(tmpl.Variables) != 3 {
+		t.Fatalf("expected chain/asset/amount variables, got %+v", tmpl.Variables)
+	}
+
+	if _, err := NewTemplate("Bad Name", templateSourceAction()); err == nil {
+		t.Fatal("expected invalid name error")
+	}
+	if _, err := NewTemplate("legacy", NewAction(NewActionID(), "transfer", "eip155:1", Constraints{})); err == nil {
+		t.Fatal("expected error for action without recorded plan command")
+	}
+}
+
+func TestTemplateArgsOnlyOverridesVariables(t *testing.T) {
+	tmpl, err := NewTemplate("payout", templateSourceAction())
+	if err != nil {
+		t.Fatalf("NewTemplate failed: %v", err)
+	}
+
+	args, err := tmpl.Args(map[string]string{"amount-decimal": "500"})
+	if err != nil {
+		t.Fatalf("Args failed: %v", err)
+	}
+	if args["amount-decimal"] != "500" {
+		t.Fatalf("expected amount-decimal override, got %+v", args)
+	}
+	if _, ok := args["amount"]; ok {
+		t.Fatalf("expected base-unit amount to be replaced, got %+v", args)
+	}
+	if args["recipient"] != "0x00000000000000000000000000000000000000bb" {
+		t.Fatalf("expected fixed recipient, got %+v", args)
+	}
+
+	if _, err := tmpl.Args(map[string]string{"recipient": "0x00000000000000000000000000000000000000cc"}); err == nil {
+		t.Fatal("expected fixed flag override to fail")
+	}
+	if _, err := tmpl.Args(map[string]string{"slippage-bps": "500"}); err == nil {
+		t.Fatal("expected unknown variable to fail")
+	}
+	if _, err := tmpl.Args(map[string]string{"amount": "1", "amount-decimal": "1"}); err == nil {
+		t.Fatal("expected conflicting amount overrides to fail")
+	}
+}
+
+func TestTemplateArgsAmountUSDOnlyForSupportingCommands(t *testing.T) {
+	tmpl, err := NewTemplate("payout", templateSourceAction())
+	if err != nil {
+		t.Fatalf("NewTemplate failed: %v", err)
+	}
+	if _, err := tmpl.Args(map[string]string{"amount-usd": "100"}); err == nil {
+		t.Fatal("expected amount-usd to be rejected for transfer plan")
+	}
+
+	action := templateSourceAction()
+	action.Metadata[MetadataPlanCommand] = "swap plan"
+	tmpl, err = NewTemplate("dca", action)
+	if err != nil {
+		t.Fatalf("NewTemplate failed: %v", err)
+	}
+	args, err := tmpl.Args(map[string]string{"amount-usd": "100"})
+	if err != nil {
+		t.Fatalf("Args failed: %v", err)
+	}
+	if args["amount-usd"] != "100" {
+		t.Fatalf("expected amount-usd override, got %+v", args)
+	}
+	if _, ok := args["amount"]; ok {
+		t.Fatalf("expected base-unit amount to be replaced, got %+v", args)
+	}
+}
+
+func TestStoreTemplateLifecycle(t *testing.T) {
+	dir := t.TempDir()
+	store, err := OpenStore(filepath.Join(dir, "actions.db"), filepath.Join(dir, "actions.lock"))
+	if err != nil {
+		t.Fatalf("OpenStore failed: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	tmpl, err := NewTemplate("payout", templateSourceAction())
+	if err != nil {
+		t.Fatalf("NewTemplate failed: %v", err)
+	}
+	if err := store.CreateTemplate(tmpl); err != nil {
+		t.Fatalf("CreateTemplate failed: %v", err)
+	}
+	if err := store.CreateTemplate(tmpl); !errors.Is(err, ErrTemplateExists) {
+		t.Fatalf("expected ErrTemplateExists, got %v", err)
+	}
+	got, err := store.GetTemplate("payout")
+	if err != nil {
+		t.Fatalf("GetTemplate failed: %v", err)
+	}
+	if got.Variables["amount"] != "1000000" {
+		t.Fatalf("unexpected stored template: %+v", got)
+	}
+	items, err := store.ListTemplates()
+	if err != nil || len(items) != 1 {
+		t.Fatalf("ListTemplates returned %d items, err=%v", len(items), err)
+	}
+	if err := store.DeleteTemplate("payout"); err != nil {
+		t.Fatalf("DeleteTemplate failed: %v", err)
+	}
+	if _, err := store.GetTemplate("payout"); !errors.Is(err, ErrTemplateNotFound) {
+		t.Fatalf("expected ErrTemplateNotFound, got %v", err)
+	}
+}
@@ -7,7 +7,6 @@ import (
 	"math/big"
 	"strings"
 	"sync"
-	"time"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
@@ -15,7 +14,9 @@ import (
 	"github.com/ethereum/go-ethereum/ethclient"
 	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
 	"github.com/ggonzalez94/defi-cli/internal/execution/signer"
+	"github.com/ggonzalez94/defi-cli/internal/logx"
 	"github.com/ggonzalez94/defi-cli/internal/registry"
+	"github.com/ggonzalez94/defi-cli/internal/rpcx"
 	temposigner "github.com/tempoxyz/tempo-go/pkg/signer"
 	"github.com/tempoxyz/tempo-go/pkg/transaction"
 
@@ -88,7 +89,7 @@ func (e *TempoStepExecutor) getClient(ctx context.Context, rpcURL string) (*ethc
 	if client := e.rpcClients[rpcURL]; client != nil {
 		return client, nil
 	}
-	client, err := ethclient.DialContext(ctx, rpcURL)
+	client, err := rpcx.DialContext(ctx, rpcURL)
 	if err != nil {
 		return nil, clierr.Wrap(clierr.CodeUnavailable, "connect tempo rpc", err)
 	}
@@ -129,7 +130,7 @@ func (e *TempoStepExecutor) ExecuteStep(ctx context.Context, store *Store, actio
 		if err := safePersist(persist); err != nil {
 			return err
 		}
-		confirmedBlock, err := waitForTempoReceipt(ctx, ethClient, step, txHash, opts, persist)
+		confirmedBlock, err := waitForTempoReceipt(ctx, step, txHash, opts, persist)
 		if err != nil {
 			return err
 		}
@@ -268,13 +269,14 @@ func (e *TempoStepExecutor) ExecuteStep(ctx context.Context, store *Store, actio
 	step.Status = StepStatusSubmitted
 	step.TxHash = txHashHex
 	step.Error = ""
+	logx.L().Info("transaction broadcast", "action_id", action.ActionID, "step_id", step.StepID, "tx_hash", step.TxHash)
 	if err := safePersist(persist); err != nil {
 		return err
 	}
 
 	// Poll for receipt.
 	txHash := common.HexToHash(txHashHex)
-	confirmedBlock, err := waitForTempoReceipt(ctx, ethClient, step, txHash, opts, persist)
+	confirmedBlock, err := waitForTempoReceipt(ctx, step, txHash, opts, persist)
 	if err != nil {
 		return err
 	}
@@ -282,44 +284,28 @@ func (e *TempoStepExecutor) ExecuteStep(ctx context.Context, store *Store, actio
 	return nil
 }
 
-// waitForTempoReceipt polls eth_getTransactionReceipt until the transaction
-// is confirmed or the step timeout elapses.
-func waitForTempoReceipt(ctx context.Context, client *ethclient.Client, step *ActionStep, txHash common.Hash, opts ExecuteOptions, persist func() error) (*big.Int, error) {
-	waitCtx, cancel := context.WithTimeout(ctx, opts.StepTimeout)
+// waitForTempoReceipt waits for the transaction receipt through the shared
+// receipt poller until the transaction is confirmed or the step timeout elapses.
+func waitForTempoReceipt(ctx context.Context, step *ActionStep, txHash common.Hash, opts ExecuteOptions, persist func() error) (*big.Int, error) {
+	waitCtx, cancel := stepWaitContext(ctx, opts)
 	defer cancel()
 
-	pollInterval := opts.PollInterval
-	if pollInterval <= 0 {
-		pollInterval = 2 * time.Second
-	}
-	ticker := time.NewTicker(pollInterval)
-	defer ticker.Stop()
-
-	for {
-		receipt, err := client.TransactionReceipt(waitCtx, txHash)
-		if err == nil && receipt != nil {
-			if receipt.Status == types.ReceiptStatusSuccessful {
-				step.Status = StepStatusConfirmed
-				step.Error = ""
-				if err := safePersist(persist); err != nil {
-					return nil, err
-				}
-				if receipt.BlockNumber == nil {
-					return nil, nil
-				}
-				return new(big.Int).Set(receipt.BlockNumber), nil
-			}
-			return nil, clierr.New(clierr.CodeUnavailable, "tempo transaction reverted on-chain")
-		}
-		if waitCtx.Err() != nil {
-			return nil, clierr.Wrap(clierr.CodeActionTimeout, "timed out waiting for tempo receipt", waitCtx.Err())
-		}
-		select {
-		case <-waitCtx.Done():
-			return nil, clierr.Wrap(clierr.CodeActionTimeout, "timed out waiting for tempo receipt", waitCtx.Err())
-		case <-ticker.C:
-		}
+	receipt, err := awaitReceipt(waitCtx, strings.TrimSpace(step.RPCURL), txHash, opts.PollInterval)
+	if err != nil {
+		return nil, clierr.Wrap(clierr.CodeActionTimeout, "timed out waiting for tempo receipt", err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return nil, clierr.New(clierr.CodeUnavailable, "tempo transaction reverted on-chain")
+	}
+	step.Status = StepStatusConfirmed
+	step.Error = ""
+	if err := safePersist(persist); err != nil {
+		return nil, err
+	}
+	if receipt.BlockNumber == nil {
+		return nil, nil
 	}
+	return new(big.Int).Set(receipt.BlockNumber), nil
 }
 
 // EstimateStep returns a gas/fee estimate for a Tempo step.
@@ -11,10 +11,11 @@ type StepType string
 type ExecutionBackend string
 
 const (
-	ActionStatusPlanned   ActionStatus = "planned"
-	ActionStatusRunning   ActionStatus = "running"
-	ActionStatusCompleted ActionStatus = "completed"
-	ActionStatusFailed    ActionStatus = "failed"
+	ActionStatusPlanned     ActionStatus = "planned"
+	ActionStatusRunning     ActionStatus = "running"
+	ActionStatusCompleted   ActionStatus = "completed"
+	ActionStatusFailed      ActionStatus = "failed"
+	ActionStatusInterrupted ActionStatus = "interrupted"
 )
 
 const (
@@ -32,6 +33,13 @@ const (
 	StepTypeBridge   StepType = "bridge_send"
 	StepTypeLend     StepType = "lend_call"
 	StepTypeClaim    StepType = "claim"
+	StepTypeOrder    StepType = "order"
+	// StepTypeCancelOrder cancels a resting limit order on-chain.
+	StepTypeCancelOrder StepType = "cancel_order"
+	// StepTypeBridgeReceive completes a bridge on the destination chain. Its
+	// calldata depends on the source step's settlement and is filled in at
+	// submit time.
+	StepTypeBridgeReceive StepType = "bridge_receive"
 )
 
 const (
@@ -66,6 +74,10 @@ type ActionStep struct {
 	Calls           []StepCall        `json:"calls,omitempty"`
 	ExpectedOutputs map[string]string `json:"expected_outputs,omitempty"`
 	TxHash          string            `json:"tx_hash,omitempty"`
+	PrivateTx       *PrivateTxStatus  `json:"private_tx,omitempty"`
+	Replacements    []TxReplacement   `json:"replacements,omitempty"`
+	Receipt         *StepReceipt      `json:"receipt,omitempty"`
+	Order           *OrderDetails     `json:"order,omitempty"`
 	Error           string            `json:"error,omitempty"`
 }
 
@@ -87,6 +99,19 @@ type Action struct {
 	Steps             []ActionStep           `json:"steps"`
 	Metadata          map[string]any         `json:"metadata,omitempty"`
 	ProviderData      map[string]interface{} `json:"provider_data,omitempty"`
+	PostState         *PostState             `json:"post_state,omitempty"`
+	PriceCheck        *PriceCheck            `json:"price_check,omitempty"`
+	Realized          *Realized              `json:"realized,omitempty"`
+	BridgeDelivery    *BridgeDelivery        `json:"bridge_delivery,omitempty"`
+	LimitOrder        *LimitOrder            `json:"limit_order,omitempty"`
+	// Prefetch is chain state read at plan time for submit to reuse.
+	Prefetch          *Prefetch              `json:"prefetch,omitempty"`
+	// Preview is attached to plan output by --preview and is not persisted.
+	Preview           *ActionPreview         `json:"preview,omitempty"`
+	// ParentActionID is set on sub-actions of a composite action.
+	ParentActionID    string                 `json:"parent_action_id,omitempty"`
+	DependsOn         []string               `json:"depends_on,omitempty"`
+	SubActions        []Action               `json:"sub_actions,omitempty"`
 }
 
 func NewAction(actionID, intentType, chainID string, constraints Constraints) Action {
@@ -0,0 +1,381 @@
+// Package fixtures records provider HTTP exchanges to a directory
+// (--record-fixtures) and serves them back (--replay-fixtures) so a command
+// runs without network access or API keys. Requests are matched on method,
+// sanitized URL, and body; JSON-RPC request IDs are ignored when matching and
+// rewritten into replayed responses. Headers are never stored, and URLs are
+// stored sanitized, so fixture directories do not capture credentials.
+package fixtures
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/logx"
+)
+
+// Mode selects whether a Store captures or serves exchanges.
+type Mode string
+
+const (
+	ModeRecord Mode = "record"
+	ModeReplay Mode = "replay"
+)
+
+// ManifestFile holds the recording metadata inside a fixture directory.
+const ManifestFile = "manifest.json"
+
+const manifestVersion = 1
+
+// Manifest describes a fixture directory. Replay freezes the clock at
+// RecordedAt. Credentials names the providers that had an API key configured
+// while recording (never the keys), since a key can change the endpoint a
+// provider calls.
+type Manifest struct {
+	Version     int       `json:"version"`
+	RecordedAt  time.Time `json:"recorded_at"`
+	Credentials []string  `json:"credentials,omitempty"`
+}
+
+// Fixture is one recorded exchange.
+type Fixture struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is the recorded request. Its URL is sanitized.
+type Request struct {
+	Method string          `json:"method"`
+	URL    string          `json:"url"`
+	JSON   json.RawMessage `json:"json,omitempty"`
+	Text   string          `json:"text,omitempty"`
+}
+
+// Response is the recorded response; JSON bodies are stored as JSON and any
+// other body as text.
+type Response struct {
+	Status      int             `json:"status"`
+	ContentType string          `json:"content_type,omitempty"`
+	JSON        json.RawMessage `json:"json,omitempty"`
+	Text        string          `json:"text,omitempty"`
+}
+
+// Store is an open fixture directory.
+type Store struct {
+	dir      string
+	mode     Mode
+	manifest Manifest
+}
+
+// Open prepares dir for mode. Recording creates the directory and keeps the
+// manifest of an earlier recording so several commands can share one
+// directory; replay requires the manifest.
+func Open(dir string, mode Mode) (*Store, error) {
+	dir = strings.TrimSpace(dir)
+	if dir == "" {
+		return nil, clierr.New(clierr.CodeUsage, "fixture directory is required")
+	}
+	store := &Store{dir: dir, mode: mode}
+	manifest, err := readManifest(dir)
+	switch {
+	case err == nil:
+		store.manifest = manifest
+	case errors.Is(err, os.ErrNotExist) && mode == ModeRecord:
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, clierr.Wrap(clierr.CodeUsage, "create fixture directory", err)
+		}
+		store.manifest = Manifest{Version: manifestVersion, RecordedAt: time.Now().UTC().Truncate(time.Second)}
+		if err := writeJSON(filepath.Join(dir, ManifestFile), store.manifest); err != nil {
+			return nil, clierr.Wrap(clierr.CodeUsage, "write fixture manifest", err)
+		}
+	case errors.Is(err, os.ErrNotExist):
+		return nil, clierr.New(clierr.CodeUsage, fmt.Sprintf("%s has no %s; record fixtures with --record-fixtures first", dir, ManifestFile))
+	default:
+		return nil, clierr.Wrap(clierr.CodeUsage, "read fixture manifest", err)
+	}
+	return store, nil
+}
+
+// Mode reports whether the store records or replays.
+func (s *Store) Mode() Mode {
+	return s.mode
+}
+
+// RecordedAt is when the directory was first recorded.
+func (s *Store) RecordedAt() time.Time {
+	return s.manifest.RecordedAt
+}
+
+// Credentials returns the providers recorded with an API key.
+func (s *Store) Credentials() []string {
+	return slices.Clone(s.manifest.Credentials)
+}
+
+// AddCredentials notes providers recorded with an API key in the manifest.
+func (s *Store) AddCredentials(names []string) error {
+	merged := append(slices.Clone(s.manifest.Credentials), names...)
+	slices.Sort(merged)
+	merged = slices.Compact(merged)
+	if slices.Equal(merged, s.manifest.Credentials) {
+		return nil
+	}
+	s.manifest.Credentials = merged
+	return writeJSON(filepath.Join(s.dir, ManifestFile), s.manifest)
+}
+
+var active atomic.Pointer[Store]
+
+// Set installs the process-wide store. nil turns recording and replay off.
+func Set(s *Store) {
+	active.Store(s)
+}
+
+// Active returns the process-wide store, or nil.
+func Active() *Store {
+	return active.Load()
+}
+
+// Wrap routes base through the active store. It returns base unchanged when
+// no store is installed.
+func Wrap(base http.RoundTripper) http.RoundTripper {
+	store := Active()
+	if store == nil {
+		return base
+	}
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &transport{store: store, base: base}
+}
+
+type transport struct {
+	store *Store
+	base  http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+	key, rpcIDs := requestKey(req.Method, req.URL.String(), body)
+	path := filepath.Join(t.store.dir, key+".json")
+	if t.store.mode == ModeReplay {
+		return t.replay(req, path, rpcIDs)
+	}
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	fixture := Fixture{
+		Request:  Request{Method: req.Method, URL: logx.SanitizeURL(req.URL.String())},
+		Response: Response{Status: resp.StatusCode, ContentType: resp.Header.Get("Content-Type")},
+	}
+	fixture.Request.JSON, fixture.Request.Text = splitBody(body)
+	fixture.Response.JSON, fixture.Response.Text = splitBody(respBody)
+	if err := writeJSON(path, fixture); err != nil {
+		logx.L().Warn("fixture write failed", "path", path, "error", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	return resp, nil
+}
+
+func (t *transport) replay(req *http.Request, path string, rpcIDs []json.RawMessage) (*http.Response, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("no recorded fixture for %s %s in %s", req.Method, logx.SanitizeURL(req.URL.String()), t.store.dir)
+	}
+	var fixture Fixture
+	if err := json.Unmarshal(raw, &fixture); err != nil {
+		return nil, fmt.Errorf("decode fixture %s: %w", path, err)
+	}
+	body := []byte(fixture.Response.Text)
+	if len(fixture.Response.JSON) > 0 {
+		body = fixture.Response.JSON
+		if len(rpcIDs) > 0 {
+			body = rewriteRPCIDs(fixture.Request.JSON, body, rpcIDs)
+		}
+	}
+	header := http.Header{}
+	if fixture.Response.ContentType != "" {
+		header.Set("Content-Type", fixture.Response.ContentType)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", fixture.Response.Status, http.StatusText(fixture.Response.Status)),
+		StatusCode:    fixture.Response.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	_ = req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+// requestKey names the fixture of a request. JSON-RPC IDs are replaced by
+// their position so the same calls match however the client numbered them;
+// the original IDs are returned for rewriting the response.
+func requestKey(method, rawURL string, body []byte) (string, []json.RawMessage) {
+	normalized, ids := normalizeRPC(body)
+	sum := sha256.Sum256([]byte(strings.ToUpper(method) + "\n" + logx.SanitizeURL(rawURL) + "\n" + string(normalized)))
+	return hex.EncodeToString(sum[:16]), ids
+}
+
+type rpcMessage map[string]json.RawMessage
+
+func normalizeRPC(body []byte) ([]byte, []json.RawMessage) {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) == 0 {
+		return body, nil
+	}
+	var batch []rpcMessage
+	if trimmed[0] == '[' {
+		if err := json.Unmarshal(trimmed, &batch); err != nil {
+			return body, nil
+		}
+	} else {
+		var single rpcMessage
+		if err := json.Unmarshal(trimmed, &single); err != nil {
+			return body, nil
+		}
+		batch = []rpcMessage{single}
+	}
+	ids := make([]json.RawMessage, len(batch))
+	for i, msg := range batch {
+		if _, ok := msg["jsonrpc"]; !ok {
+			return body, nil
+		}
+		ids[i] = msg["id"]
+		msg["id"] = json.RawMessage(fmt.Sprint(i))
+	}
+	var normalized []byte
+	var err error
+	if trimmed[0] == '[' {
+		normalized, err = json.Marshal(batch)
+	} else {
+		normalized, err = json.Marshal(batch[0])
+	}
+	if err != nil {
+		return body, nil
+	}
+	return normalized, ids
+}
+
+// rewriteRPCIDs maps the IDs of a recorded JSON-RPC response back to the IDs
+// of the live request, pairing calls by position.
+func rewriteRPCIDs(recordedRequest, response json.RawMessage, live []json.RawMessage) json.RawMessage {
+	_, recorded := normalizeRPC(recordedRequest)
+	if len(recorded) != len(live) {
+		return response
+	}
+	mapping := make(map[string]json.RawMessage, len(recorded))
+	for i, id := range recorded {
+		mapping[string(id)] = live[i]
+	}
+	rewrite := func(msg rpcMessage) {
+		if id, ok := mapping[string(msg["id"])]; ok {
+			msg["id"] = id
+		}
+	}
+	trimmed := bytes.TrimSpace(response)
+	var out []byte
+	var err error
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		var batch []rpcMessage
+		if json.Unmarshal(trimmed, &batch) != nil {
+			return response
+		}
+		for _, msg := range batch {
+			rewrite(msg)
+		}
+		out, err = json.Marshal(batch)
+	} else {
+		var single rpcMessage
+		if json.Unmarshal(trimmed, &single) != nil {
+			return response
+		}
+		rewrite(single)
+		out, err = json.Marshal(single)
+	}
+	if err != nil {
+		return response
+	}
+	return out
+}
+
+func splitBody(body []byte) (json.RawMessage, string) {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil, ""
+	}
+	if json.Valid(body) {
+		var compact bytes.Buffer
+		if err := json.Compact(&compact, body); err == nil {
+			return compact.Bytes(), ""
+		}
+	}
+	return nil, string(body)
+}
+
+func readManifest(dir string) (Manifest, error) {
+	raw, err := os.ReadFile(filepath.Join(dir, ManifestFile))
+	if err != nil {
+		return Manifest{}, err
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(raw, &manifest); err != nil {
+		return Manifest{}, err
+	}
+	return manifest, nil
+}
+
+func writeJSON(path string, v any) error {
+	raw, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".fixture-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(append(raw, '\n')); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
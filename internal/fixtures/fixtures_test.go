@@ -0,0 +1,72 @@
+package fixtures
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReplayMatchesJSONRPCWhateverTheRequestIDs(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var batch []map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&batch)
+		out := make([]map[string]any, 0, len(batch))
+		for i := len(batch) - 1; i >= 0; i-- {
+			out = append(out, map[string]any{"jsonrpc": "2.0", "id": batch[i]["id"], "result": batch[i]["method"]})
+		}
+		_ = json.NewEncoder(w).Encode(out)
+	}))
+	defer srv.Close()
+	dir := t.TempDir()
+	t.Cleanup(func() { Set(nil) })
+
+	call := func(mode Mode, body string) []map[string]any {
+		store, err := Open(dir, mode)
+		if err != nil {
+			t.Fatalf("open %s: %v", mode, err)
+		}
+		Set(store)
+		client := &http.Client{Transport: Wrap(http.DefaultTransport)}
+		resp, err := client.Post(srv.URL+"/rpc?apikey=secret", "application/json", bytes.NewBufferString(body))
+		if err != nil {
+			t.Fatalf("%s: %v", mode, err)
+		}
+		defer resp.Body.Close()
+		raw, _ := io.ReadAll(resp.Body)
+		var out []map[string]any
+		if err := json.Unmarshal(raw, &out); err != nil {
+			t.Fatalf("decode %s: %v", raw, err)
+		}
+		return out
+	}
+
+	call(ModeRecord, `[{"jsonrpc":"2.0","id":1,"method":"eth_chainId"},{"jsonrpc":"2.0","id":2,"method":"eth_blockNumber"}]`)
+	srv.Close()
+	entries, _ := os.ReadDir(dir)
+	for _, entry := range entries {
+		raw, _ := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if bytes.Contains(raw, []byte("secret")) {
+			t.Fatalf("fixture %s stored a credential: %s", entry.Name(), raw)
+		}
+	}
+	replayed := call(ModeReplay, `[{"jsonrpc":"2.0","id":7,"method":"eth_chainId"},{"jsonrpc":"2.0","id":8,"method":"eth_blockNumber"}]`)
+	got := map[string]any{}
+	for _, msg := range replayed {
+		got[msg["result"].(string)] = msg["id"]
+	}
+	if got["eth_chainId"] != float64(7) || got["eth_blockNumber"] != float64(8) {
+		t.Fatalf("expected replayed ids to follow the live request, got %v", replayed)
+	}
+
+	store, _ := Open(dir, ModeReplay)
+	Set(store)
+	client := &http.Client{Transport: Wrap(http.DefaultTransport)}
+	if _, err := client.Get(srv.URL + "/unrecorded"); err == nil {
+		t.Fatal("expected an unrecorded request to fail during replay")
+	}
+}
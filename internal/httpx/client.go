@@ -4,20 +4,29 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"math/rand"
+	"log/slog"
 	"net"
 	"net/http"
 	"time"
 
+	"github.com/ggonzalez94/defi-cli/internal/breaker"
+	"github.com/ggonzalez94/defi-cli/internal/cache"
 	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/logx"
+	"github.com/ggonzalez94/defi-cli/internal/randx"
+	"github.com/ggonzalez94/defi-cli/internal/ratebudget"
+	"github.com/ggonzalez94/defi-cli/internal/tracex"
 )
 
 type Client struct {
 	httpClient *http.Client
 	retries    int
 	userAgent  string
+	provider   string
+	limiter    *limiter
 }
 
 func New(timeout time.Duration, retries int) *Client {
@@ -25,13 +34,54 @@ func New(timeout time.Duration, retries int) *Client {
 		retries = 0
 	}
 	return &Client{
-		httpClient: &http.Client{Timeout: timeout},
+		httpClient: &http.Client{Timeout: timeout, Transport: Transport(transport.Load())},
 		retries:    retries,
 		userAgent:  "defi-cli/1.0",
 	}
 }
 
+// DoJSON sends req with retries and decodes a JSON response into out. Calls
+// from a provider client (ForProvider) under a capability context
+// (breaker.WithCapability) go through that provider's circuit breaker, except
+// in offline mode, where requests never reach the provider.
 func (c *Client) DoJSON(ctx context.Context, req *http.Request, out any) (http.Header, error) {
+	capability := breaker.CapabilityFrom(ctx)
+	if c.provider == "" || capability == "" || Offline() {
+		return c.doJSON(ctx, req, out)
+	}
+	if err := breaker.Allow(c.provider, capability); err != nil {
+		logx.L().Warn("provider circuit open; skipping request", "provider", c.provider, "capability", capability)
+		return nil, err
+	}
+	header, err := c.doJSON(ctx, req, out)
+	if !errors.Is(err, ratebudget.ErrExhausted) {
+		breaker.Record(c.provider, capability, err)
+	}
+	return header, err
+}
+
+// waitBudget delays a request while the provider's advertised rate-limit
+// budget is low or spent (see ratebudget.Reserve), and fails it when the
+// budget resets too late to wait for.
+func (c *Client) waitBudget(ctx context.Context, log *slog.Logger, attempt int) error {
+	delay, err := ratebudget.Reserve(c.provider)
+	if err != nil {
+		log.Warn("provider rate limit budget exhausted; skipping request", "attempt", attempt+1)
+		return err
+	}
+	if delay <= 0 {
+		return nil
+	}
+	log.Info("pacing request for provider rate limit budget", "attempt", attempt+1, "wait_ms", delay.Milliseconds())
+	select {
+	case <-ctx.Done():
+		return clierr.Wrap(clierr.CodeUnavailable, "request cancelled", ctx.Err())
+	case <-time.After(delay):
+		return nil
+	}
+}
+
+func (c *Client) doJSON(ctx context.Context, req *http.Request, out any) (http.Header, error) {
 	if req.Header.Get("Accept") == "" {
 		req.Header.Set("Accept", "application/json")
 	}
@@ -39,17 +89,39 @@ func (c *Client) DoJSON(ctx context.Context, req *http.Request, out any) (http.H
 		req.Header.Set("User-Agent", c.userAgent)
 	}
 
+	log := logx.L().With("method", req.Method, "url", logx.SanitizeURL(req.URL.String()))
+	if c.provider != "" {
+		log = log.With("provider", c.provider)
+	}
 	var lastErr error
 	for attempt := 0; attempt <= c.retries; attempt++ {
 		if attempt > 0 {
+			wait := backoff(attempt)
+			log.Info("retrying http request", "attempt", attempt+1, "backoff_ms", wait.Milliseconds(), "reason", lastErr)
 			select {
 			case <-ctx.Done():
 				return nil, clierr.Wrap(clierr.CodeUnavailable, "request cancelled", ctx.Err())
-			case <-time.After(backoff(attempt)):
+			case <-time.After(wait):
 			}
 		}
 
-		cloneReq := req.Clone(ctx)
+		if c.limiter != nil {
+			waited, err := c.limiter.wait(ctx)
+			if err != nil {
+				return nil, clierr.Wrap(clierr.CodeUnavailable, "request cancelled", err)
+			}
+			if waited > time.Millisecond {
+				log.Debug("rate limited locally", "attempt", attempt+1, "wait_ms", waited.Milliseconds())
+			}
+		}
+		if c.provider != "" && !Offline() {
+			if err := c.waitBudget(ctx, log, attempt); err != nil {
+				return nil, err
+			}
+		}
+
+		traceCtx, traced := tracex.StartRequest(ctx, req, attempt+1)
+		cloneReq := req.Clone(traceCtx)
 		if req.Body != nil && req.GetBody != nil {
 			body, err := req.GetBody()
 			if err != nil {
@@ -58,8 +130,12 @@ func (c *Client) DoJSON(ctx context.Context, req *http.Request, out any) (http.H
 			cloneReq.Body = body
 		}
 
+		log.Debug("http request", "attempt", attempt+1)
+		started := time.Now()
 		resp, err := c.httpClient.Do(cloneReq)
 		if err != nil {
+			traced(0, 0, err)
+			log.Warn("http request failed", "attempt", attempt+1, "duration_ms", time.Since(started).Milliseconds(), "error", err)
 			lastErr = mapNetError(err)
 			if attempt < c.retries {
 				continue
@@ -69,9 +145,14 @@ func (c *Client) DoJSON(ctx context.Context, req *http.Request, out any) (http.H
 
 		buf, readErr := io.ReadAll(resp.Body)
 		_ = resp.Body.Close()
+		if c.provider != "" {
+			ratebudget.Observe(c.provider, resp.StatusCode, resp.Header)
+		}
+		traced(resp.StatusCode, len(buf), readErr)
 		if readErr != nil {
 			return resp.Header, clierr.Wrap(clierr.CodeUnavailable, "read provider response", readErr)
 		}
+		log.Debug("http response", "attempt", attempt+1, "status", resp.StatusCode, "bytes", len(buf), "duration_ms", time.Since(started).Milliseconds())
 
 		if resp.StatusCode == http.StatusTooManyRequests {
 			lastErr = clierr.New(clierr.CodeRateLimited, "provider rate limited request")
@@ -115,6 +196,35 @@ func (c *Client) DoJSON(ctx context.Context, req *http.Request, out any) (http.H
 	return nil, clierr.New(clierr.CodeUnavailable, "request failed")
 }
 
+// DoSharedJSON is DoJSON for large GET payloads that several commands or
+// providers read, such as DefiLlama's /pools. The raw body goes through the
+// process-wide shared cache (cache.ActiveShared) for ttl, so concurrent and
+// repeated reads share one request. Without a shared cache it is DoJSON.
+func (c *Client) DoSharedJSON(ctx context.Context, req *http.Request, ttl time.Duration, out any) error {
+	shared := cache.ActiveShared()
+	if shared == nil {
+		_, err := c.DoJSON(ctx, req, out)
+		return err
+	}
+	started := time.Now()
+	body, status, err := shared.Fetch(ctx, req.Method+" "+req.URL.String(), ttl, func(ctx context.Context) ([]byte, error) {
+		var raw json.RawMessage
+		if _, err := c.DoJSON(ctx, req.Clone(ctx), &raw); err != nil {
+			return nil, err
+		}
+		return raw, nil
+	})
+	tracex.RecordCache("shared", string(status), time.Since(started))
+	logx.L().Debug("shared cache", "url", logx.SanitizeURL(req.URL.String()), "status", string(status), "bytes", len(body))
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return clierr.Wrap(clierr.CodeUnavailable, "decode provider JSON", err)
+	}
+	return nil
+}
+
 func DoBodyJSON(ctx context.Context, c *Client, method, url string, body []byte, headers map[string]string, out any) (http.Header, error) {
 	var reader io.Reader
 	if body != nil {
@@ -151,6 +261,6 @@ func backoff(attempt int) time.Duration {
 	if d > 2*time.Second {
 		d = 2 * time.Second
 	}
-	jitter := time.Duration(rand.Intn(75)) * time.Millisecond
+	jitter := time.Duration(randx.Intn(75)) * time.Millisecond
 	return d + jitter
 }
@@ -1,12 +1,21 @@
 package httpx
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"sync/atomic"
 	"testing"
 	"time"
+
+	"github.com/ggonzalez94/defi-cli/internal/breaker"
+	"github.com/ggonzalez94/defi-cli/internal/cache"
+	"github.com/ggonzalez94/defi-cli/internal/logx"
+	"github.com/ggonzalez94/defi-cli/internal/ratebudget"
 )
 
 func TestDoJSONRetriesServerError(t *testing.T) {
@@ -35,3 +44,144 @@ func TestDoJSONRetriesServerError(t *testing.T) {
 		t.Fatalf("unexpected response: %#v", out)
 	}
 }
+
+func TestDoJSONLogsSanitizedRequestsAndRetries(t *testing.T) {
+	var count int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&count, 1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer srv.Close()
+
+	var logs bytes.Buffer
+	logx.Set(logx.New(&logs, slog.LevelDebug, true))
+	t.Cleanup(func() { logx.Set(nil) })
+
+	client := New(2*time.Second, 1)
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, srv.URL+"/quote?api_key=secret-value", nil)
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	var out map[string]any
+	if _, err := client.DoJSON(context.Background(), req, &out); err != nil {
+		t.Fatalf("DoJSON failed: %v", err)
+	}
+	got := logs.String()
+	if strings.Contains(got, "secret-value") {
+		t.Fatalf("expected api key to be redacted, got %s", got)
+	}
+	for _, want := range []string{`"msg":"http response"`, `"status":502`, `"msg":"retrying http request"`, `"status":200`, "api_key=REDACTED"} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("expected %s in logs, got %s", want, got)
+		}
+	}
+}
+
+func TestDoSharedJSONReusesSharedPayload(t *testing.T) {
+	var count int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&count, 1)
+		_, _ = w.Write([]byte(`{"data":[{"project":"lido"}]}`))
+	}))
+	defer srv.Close()
+
+	previous := cache.ActiveShared()
+	cache.SetShared(cache.NewShared(nil, time.Minute, false))
+	t.Cleanup(func() { cache.SetShared(previous) })
+
+	client := New(2*time.Second, 0)
+	for i := 0; i < 3; i++ {
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, srv.URL+"/pools", nil)
+		if err != nil {
+			t.Fatalf("new request: %v", err)
+		}
+		var out struct {
+			Data []struct {
+				Project string `json:"project"`
+			} `json:"data"`
+		}
+		if err := client.DoSharedJSON(context.Background(), req, time.Minute, &out); err != nil {
+			t.Fatalf("DoSharedJSON failed: %v", err)
+		}
+		if len(out.Data) != 1 || out.Data[0].Project != "lido" {
+			t.Fatalf("unexpected response: %+v", out)
+		}
+	}
+	if got := atomic.LoadInt32(&count); got != 1 {
+		t.Fatalf("expected one upstream request, got %d", got)
+	}
+}
+
+func TestDoJSONShortCircuitsOpenProviderCircuit(t *testing.T) {
+	var count int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&count, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	breaker.Configure(2, time.Minute)
+	t.Cleanup(func() {
+		breaker.Reset("test-breaker")
+		breaker.Configure(5, 30*time.Second)
+	})
+	client := New(time.Second, 0).ForProvider("test-breaker", Policy{})
+	ctx := breaker.WithCapability(context.Background(), "lend.markets")
+	for i := 0; i < 3; i++ {
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+		_, err := client.DoJSON(ctx, req, nil)
+		if err == nil {
+			t.Fatal("expected error")
+		}
+		if open := errors.Is(err, breaker.ErrOpen); open != (i == 2) {
+			t.Fatalf("call %d: unexpected circuit state in %v", i+1, err)
+		}
+	}
+	if atomic.LoadInt32(&count) != 2 {
+		t.Fatalf("expected the open circuit to skip the third request, got %d requests", count)
+	}
+
+	// Calls without a capability are not tracked.
+	req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, srv.URL, nil)
+	if _, err := client.DoJSON(context.Background(), req, nil); errors.Is(err, breaker.ErrOpen) {
+		t.Fatalf("expected untracked call to reach the provider, got %v", err)
+	}
+}
+
+func TestDoJSONSkipsProviderWithExhaustedBudget(t *testing.T) {
+	var count int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&count, 1)
+		w.Header().Set("X-RateLimit-Limit", "100")
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", "60")
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer srv.Close()
+	t.Cleanup(func() {
+		ratebudget.Reset("test-budget")
+		breaker.Reset("test-budget")
+	})
+
+	client := New(time.Second, 0).ForProvider("test-budget", Policy{})
+	ctx := breaker.WithCapability(context.Background(), "swap.quote")
+	var out map[string]any
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	if _, err := client.DoJSON(ctx, req, &out); err != nil {
+		t.Fatalf("expected the first request to succeed, got %v", err)
+	}
+	req, _ = http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	_, err := client.DoJSON(ctx, req, &out)
+	if !errors.Is(err, ratebudget.ErrExhausted) {
+		t.Fatalf("expected the exhausted budget to skip the request, got %v", err)
+	}
+	if atomic.LoadInt32(&count) != 1 {
+		t.Fatalf("expected one request to reach the provider, got %d", count)
+	}
+	if c, _ := breaker.Get("test-budget", "swap.quote"); c.Failures != 0 {
+		t.Fatalf("skipped requests must not count as circuit failures: %+v", c)
+	}
+}
@@ -0,0 +1,50 @@
+package httpx
+
+import (
+	"errors"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/ggonzalez94/defi-cli/internal/fixtures"
+)
+
+// ErrOffline is the error of every request made while offline mode is on.
+var ErrOffline = errors.New("network access disabled by --offline")
+
+var offline atomic.Bool
+
+// SetOffline turns offline mode on or off. While on, clients built with New
+// or Transport fail every request at once with ErrOffline instead of reaching
+// the network.
+func SetOffline(on bool) {
+	offline.Store(on)
+}
+
+// Offline reports whether offline mode is on.
+func Offline() bool {
+	return offline.Load()
+}
+
+// Transport returns the round tripper for requests sent through base: refused
+// in offline mode, and recorded or replayed when fixtures are active. Like
+// those modes, it is fixed when the client is built. Callers that build their
+// own http.Client (such as RPC clients) use it so both modes cover every
+// provider request.
+func Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if Offline() {
+		base = offlineTransport{}
+	}
+	return fixtures.Wrap(base)
+}
+
+type offlineTransport struct{}
+
+func (offlineTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		_ = req.Body.Close()
+	}
+	return nil, ErrOffline
+}
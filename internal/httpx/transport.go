@@ -0,0 +1,129 @@
+package httpx
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultMaxConnsPerHost bounds concurrent connections to one provider host
+// unless http.max_conns_per_host is configured.
+const DefaultMaxConnsPerHost = 16
+
+// transport is shared by every Client so connections are kept alive and
+// reused across providers, commands, and nested mcp runs, instead of each
+// client dialing its own.
+var transport atomic.Pointer[http.Transport]
+
+func init() {
+	transport.Store(newTransport(DefaultMaxConnsPerHost))
+}
+
+func newTransport(maxConnsPerHost int) *http.Transport {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.ForceAttemptHTTP2 = true
+	t.MaxIdleConns = 128
+	t.MaxIdleConnsPerHost = maxConnsPerHost
+	t.MaxConnsPerHost = maxConnsPerHost
+	t.IdleConnTimeout = 90 * time.Second
+	return t
+}
+
+// SetMaxConnsPerHost replaces the shared transport when the per-host
+// connection limit changes. Clients created earlier keep the transport they
+// were built with. n <= 0 restores the default.
+func SetMaxConnsPerHost(n int) {
+	if n <= 0 {
+		n = DefaultMaxConnsPerHost
+	}
+	if current := transport.Load(); current.MaxConnsPerHost == n {
+		return
+	}
+	previous := transport.Swap(newTransport(n))
+	previous.CloseIdleConnections()
+}
+
+// Policy tunes requests to one provider. The zero value keeps the client's
+// retries and does not rate limit.
+type Policy struct {
+	// RateLimit is the sustained request rate in requests per second.
+	RateLimit float64
+	// Burst is how many requests may be sent at once before RateLimit
+	// applies. It defaults to the rate rounded up.
+	Burst int
+	// Retries replaces the client's retry count when not nil.
+	Retries *int
+}
+
+// ForProvider returns a copy of c that labels its logs with provider, applies
+// policy's retry count, and shares one rate limiter per provider name across
+// the process, so parallel commands and mcp tool calls draw from the same
+// budget.
+func (c *Client) ForProvider(provider string, policy Policy) *Client {
+	out := *c
+	out.provider = provider
+	if policy.Retries != nil && *policy.Retries >= 0 {
+		out.retries = *policy.Retries
+	}
+	out.limiter = limiterFor(provider, policy.RateLimit, policy.Burst)
+	return &out
+}
+
+var (
+	limitersMu sync.Mutex
+	limiters   = map[string]*limiter{}
+)
+
+// limiterFor returns the process-wide limiter of provider, replacing it when
+// the configured rate changes. A non-positive rate disables limiting.
+func limiterFor(provider string, rate float64, burst int) *limiter {
+	if rate <= 0 {
+		return nil
+	}
+	if burst <= 0 {
+		burst = int(math.Ceil(rate))
+	}
+	limitersMu.Lock()
+	defer limitersMu.Unlock()
+	if l, ok := limiters[provider]; ok && l.rate == rate && l.burst == float64(burst) {
+		return l
+	}
+	l := &limiter{rate: rate, burst: float64(burst), tokens: float64(burst), last: time.Now()}
+	limiters[provider] = l
+	return l
+}
+
+// limiter is a token bucket refilled at rate tokens per second.
+type limiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// wait blocks until a token is available and returns how long it waited.
+func (l *limiter) wait(ctx context.Context) (time.Duration, error) {
+	started := time.Now()
+	for {
+		l.mu.Lock()
+		now := time.Now()
+		l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+		l.last = now
+		if l.tokens >= 1 {
+			l.tokens--
+			l.mu.Unlock()
+			return time.Since(started), nil
+		}
+		delay := time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+		l.mu.Unlock()
+		select {
+		case <-ctx.Done():
+			return time.Since(started), ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
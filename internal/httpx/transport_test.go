@@ -0,0 +1,78 @@
+package httpx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClientsShareTunedTransport(t *testing.T) {
+	a, b := New(time.Second, 0), New(time.Second, 0)
+	if a.httpClient.Transport == nil || a.httpClient.Transport != b.httpClient.Transport {
+		t.Fatal("expected clients to share one transport")
+	}
+	tr := a.httpClient.Transport.(*http.Transport)
+	if !tr.ForceAttemptHTTP2 || tr.MaxIdleConnsPerHost != DefaultMaxConnsPerHost || tr.MaxConnsPerHost != DefaultMaxConnsPerHost {
+		t.Fatalf("unexpected transport tuning: http2=%v idle=%d max=%d", tr.ForceAttemptHTTP2, tr.MaxIdleConnsPerHost, tr.MaxConnsPerHost)
+	}
+
+	SetMaxConnsPerHost(4)
+	t.Cleanup(func() { SetMaxConnsPerHost(0) })
+	c := New(time.Second, 0)
+	if c.httpClient.Transport.(*http.Transport).MaxConnsPerHost != 4 {
+		t.Fatal("expected new clients to use the reconfigured transport")
+	}
+	if a.httpClient.Transport.(*http.Transport).MaxConnsPerHost != DefaultMaxConnsPerHost {
+		t.Fatal("expected existing clients to keep their transport")
+	}
+}
+
+func TestForProviderAppliesRetriesAndRateLimit(t *testing.T) {
+	var count int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&count, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer srv.Close()
+
+	zero := 0
+	noRetry := New(2*time.Second, 3).ForProvider("test-no-retry", Policy{Retries: &zero})
+	req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, srv.URL, nil)
+	if _, err := noRetry.DoJSON(context.Background(), req, nil); err == nil {
+		t.Fatal("expected provider retries of 0 to surface the first 503")
+	}
+
+	limited := New(2*time.Second, 0).ForProvider("test-limited", Policy{RateLimit: 20, Burst: 1})
+	if again := New(time.Second, 0).ForProvider("test-limited", Policy{RateLimit: 20, Burst: 1}); again.limiter != limited.limiter {
+		t.Fatal("expected one limiter per provider across clients")
+	}
+	started := time.Now()
+	for i := 0; i < 3; i++ {
+		req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, srv.URL, nil)
+		if _, err := limited.DoJSON(context.Background(), req, nil); err != nil {
+			t.Fatalf("DoJSON failed: %v", err)
+		}
+	}
+	// Burst 1 at 20 req/s spaces the 2nd and 3rd requests by ~50ms each.
+	if elapsed := time.Since(started); elapsed < 90*time.Millisecond {
+		t.Fatalf("expected rate limiting to space requests, took %s", elapsed)
+	}
+}
+
+func TestLimiterWaitHonoursContext(t *testing.T) {
+	l := limiterFor("test-cancel", 0.5, 1)
+	if _, err := l.wait(context.Background()); err != nil {
+		t.Fatalf("first token: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := l.wait(ctx); err == nil {
+		t.Fatal("expected wait to stop when the context ends")
+	}
+}
@@ -0,0 +1,161 @@
+// Package i18n holds the message catalog for human-facing plain output.
+// JSON output never passes through it, so field names and enum values stay
+// stable for machine consumers.
+package i18n
+
+import (
+	"sort"
+	"strings"
+)
+
+// Default is the language used when none is requested. It is the identity
+// translation: message keys are already English.
+const Default = "en"
+
+// catalog maps language -> message key -> translation. Keys are either
+// envelope/value words ("success", "true") or snake_case field names as
+// they appear in JSON output.
+var catalog = map[string]map[string]string{
+	Default: {
+		"empty": "[]",
+	},
+	"es": {
+		"success":          "éxito",
+		"data":             "datos",
+		"warnings":         "avisos",
+		"meta":             "meta",
+		"error":            "error",
+		"true":             "sí",
+		"false":            "no",
+		"null":             "nulo",
+		"empty":            "(vacío)",
+		"code":             "código",
+		"message":          "mensaje",
+		"provider":         "proveedor",
+		"protocol":         "protocolo",
+		"chain_id":         "cadena",
+		"asset_id":         "activo",
+		"symbol":           "símbolo",
+		"amount":           "cantidad",
+		"amount_usd":       "cantidad_usd",
+		"status":           "estado",
+		"name":             "nombre",
+		"supply_apy":       "apy_depósito",
+		"borrow_apy":       "apy_préstamo",
+		"apy_total":        "apy_total",
+		"tvl_usd":          "tvl_usd",
+		"input_amount":     "cantidad_entrada",
+		"estimated_out":    "salida_estimada",
+		"price_impact_pct": "impacto_precio_pct",
+		"action_id":        "acción",
+		"fetched_at":       "obtenido",
+	},
+	"zh": {
+		"success":          "成功",
+		"data":             "数据",
+		"warnings":         "警告",
+		"meta":             "元数据",
+		"error":            "错误",
+		"true":             "是",
+		"false":            "否",
+		"null":             "空",
+		"empty":            "（无）",
+		"code":             "代码",
+		"message":          "消息",
+		"provider":         "提供方",
+		"protocol":         "协议",
+		"chain_id":         "链",
+		"asset_id":         "资产",
+		"symbol":           "符号",
+		"amount":           "数量",
+		"amount_usd":       "金额_usd",
+		"status":           "状态",
+		"name":             "名称",
+		"supply_apy":       "存款年化",
+		"borrow_apy":       "借款年化",
+		"apy_total":        "总年化",
+		"tvl_usd":          "锁仓_usd",
+		"input_amount":     "输入数量",
+		"estimated_out":    "预计输出",
+		"price_impact_pct": "价格影响_pct",
+		"action_id":        "操作",
+		"fetched_at":       "获取时间",
+	},
+	"ja": {
+		"success":          "成功",
+		"data":             "データ",
+		"warnings":         "警告",
+		"meta":             "メタ",
+		"error":            "エラー",
+		"true":             "はい",
+		"false":            "いいえ",
+		"null":             "なし",
+		"empty":            "（空）",
+		"code":             "コード",
+		"message":          "メッセージ",
+		"provider":         "プロバイダー",
+		"protocol":         "プロトコル",
+		"chain_id":         "チェーン",
+		"asset_id":         "資産",
+		"symbol":           "シンボル",
+		"amount":           "数量",
+		"amount_usd":       "金額_usd",
+		"status":           "状態",
+		"name":             "名前",
+		"supply_apy":       "預入apy",
+		"borrow_apy":       "借入apy",
+		"apy_total":        "合計apy",
+		"tvl_usd":          "tvl_usd",
+		"input_amount":     "入力数量",
+		"estimated_out":    "推定出力",
+		"price_impact_pct": "価格影響_pct",
+		"action_id":        "アクション",
+		"fetched_at":       "取得日時",
+	},
+}
+
+// Supported lists accepted language codes, Default first.
+func Supported() []string {
+	langs := make([]string, 0, len(catalog))
+	for lang := range catalog {
+		if lang != Default {
+			langs = append(langs, lang)
+		}
+	}
+	sort.Strings(langs)
+	return append([]string{Default}, langs...)
+}
+
+// Normalize lowercases lang and strips region/encoding suffixes, so
+// "ja_JP.UTF-8" and "es-MX" resolve to their base language.
+func Normalize(lang string) string {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if i := strings.IndexAny(lang, "-_."); i >= 0 {
+		lang = lang[:i]
+	}
+	return lang
+}
+
+// IsSupported reports whether lang (after Normalize) has a catalog. An empty
+// lang means Default.
+func IsSupported(lang string) bool {
+	lang = Normalize(lang)
+	if lang == "" {
+		return true
+	}
+	_, ok := catalog[lang]
+	return ok
+}
+
+// T translates key into lang, falling back to key itself when the language
+// or message is missing. An empty lang means Default.
+func T(lang, key string) string {
+	lang = Normalize(lang)
+	if lang == "" {
+		lang = Default
+	}
+	if msg, ok := catalog[lang][key]; ok {
+		return msg
+	}
+	return key
+}
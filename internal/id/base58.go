@@ -0,0 +1,84 @@
+package id
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+)
+
+// base58Alphabet is the Bitcoin alphabet used by Solana addresses.
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// solanaAddressLen is the decoded length of a Solana public key.
+const solanaAddressLen = 32
+
+var bigRadix58 = big.NewInt(58)
+
+// EncodeBase58 encodes input with the Bitcoin alphabet, keeping leading zero
+// bytes as leading '1's.
+func EncodeBase58(input []byte) string {
+	n := new(big.Int).SetBytes(input)
+	mod := new(big.Int)
+	out := make([]byte, 0, len(input)*138/100+1)
+	for n.Sign() > 0 {
+		n.DivMod(n, bigRadix58, mod)
+		out = append(out, base58Alphabet[mod.Int64()])
+	}
+	for _, b := range input {
+		if b != 0 {
+			break
+		}
+		out = append(out, base58Alphabet[0])
+	}
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return string(out)
+}
+
+// DecodeBase58 decodes a Bitcoin-alphabet base58 string.
+func DecodeBase58(input string) ([]byte, error) {
+	if input == "" {
+		return nil, errors.New("empty base58 string")
+	}
+	n := new(big.Int)
+	for _, r := range input {
+		idx := strings.IndexRune(base58Alphabet, r)
+		if idx < 0 {
+			return nil, errors.New("invalid base58 character")
+		}
+		n.Mul(n, bigRadix58)
+		n.Add(n, big.NewInt(int64(idx)))
+	}
+	decoded := n.Bytes()
+	zeros := 0
+	for zeros < len(input) && input[zeros] == base58Alphabet[0] {
+		zeros++
+	}
+	return append(make([]byte, zeros), decoded...), nil
+}
+
+// IsSolanaAddress reports whether s is a base58 string that decodes to a
+// 32-byte Solana public key. Wallets, mints, and program IDs all qualify.
+func IsSolanaAddress(s string) bool {
+	s = strings.TrimSpace(s)
+	// A 32-byte key encodes to 32-44 characters; checking first avoids
+	// big-int work on obviously wrong input.
+	if len(s) < 32 || len(s) > 44 {
+		return false
+	}
+	decoded, err := DecodeBase58(s)
+	return err == nil && len(decoded) == solanaAddressLen
+}
+
+func isBase58(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if !strings.ContainsRune(base58Alphabet, r) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,92 @@
+package id
+
+import "strings"
+
+// equivalenceGroups lists symbols that represent the same underlying asset:
+// the canonical token first, followed by wrapped and bridged variants. Routing
+// and matching treat members of a group as interchangeable unless
+// ResolveOptions.StrictAsset is set.
+var equivalenceGroups = [][]string{
+	{"USDC", "USDC.e", "USDbC"},
+	{"USDT", "USDT0"},
+	{"EURC", "EURC.e"},
+	{"ETH", "WETH"},
+}
+
+// EquivalentSymbols returns the symbols equivalent to symbol, including symbol
+// itself in canonical-first order, or nil when symbol has no equivalents.
+func EquivalentSymbols(symbol string) []string {
+	symbol = strings.TrimSpace(symbol)
+	for _, group := range equivalenceGroups {
+		for _, member := range group {
+			if strings.EqualFold(member, symbol) {
+				return append([]string(nil), group...)
+			}
+		}
+	}
+	return nil
+}
+
+// SymbolsEquivalent reports whether a and b name the same asset, either
+// directly or through an equivalence group.
+func SymbolsEquivalent(a, b string) bool {
+	if strings.EqualFold(strings.TrimSpace(a), strings.TrimSpace(b)) {
+		return true
+	}
+	for _, member := range EquivalentSymbols(a) {
+		if strings.EqualFold(member, strings.TrimSpace(b)) {
+			return true
+		}
+	}
+	return false
+}
+
+// EquivalentAssets returns the registry tokens on chain that are equivalent to
+// asset, excluding asset itself. It returns nil when opts.StrictAsset is set.
+func EquivalentAssets(asset Asset, chain Chain, opts ResolveOptions) []Asset {
+	if opts.StrictAsset {
+		return nil
+	}
+	symbols := EquivalentSymbols(asset.Symbol)
+	if len(symbols) == 0 {
+		return nil
+	}
+	out := []Asset{}
+	for _, symbol := range symbols {
+		for _, t := range findTokensBySymbol(chain.CAIP2, symbol) {
+			if strings.EqualFold(t.Address, asset.Address) {
+				continue
+			}
+			out = append(out, Asset{
+				ChainID:  chain.CAIP2,
+				AssetID:  canonicalAssetID(chain.CAIP2, t.Address),
+				Address:  t.Address,
+				Symbol:   t.Symbol,
+				Decimals: t.Decimals,
+			})
+		}
+	}
+	return out
+}
+
+// ParseEquivalentAsset resolves symbol on chain like ParseAssetWithOptions and,
+// when the symbol itself is not registered there, falls back to the first
+// equivalent symbol that is. It is used to infer a destination asset for
+// cross-chain routes, where the same asset often trades under a bridged
+// ticker (USDC on Ethereum, USDC.e on Tempo).
+func ParseEquivalentAsset(symbol string, chain Chain, opts ResolveOptions) (Asset, error) {
+	asset, err := ParseAssetWithOptions(symbol, chain, opts)
+	if err == nil || opts.StrictAsset {
+		return asset, err
+	}
+	if len(findTokensBySymbol(chain.CAIP2, symbol)) > 0 {
+		return asset, err
+	}
+	for _, candidate := range EquivalentSymbols(symbol) {
+		if strings.EqualFold(candidate, symbol) || len(findTokensBySymbol(chain.CAIP2, candidate)) == 0 {
+			continue
+		}
+		return ParseAssetWithOptions(candidate, chain, opts)
+	}
+	return asset, err
+}
@@ -11,17 +11,32 @@ import (
 )
 
 var (
-	eip155ChainPattern     = regexp.MustCompile(`^eip155:[0-9]+$`)
-	evmAddressPattern      = regexp.MustCompile(`^0x[0-9a-fA-F]{40}$`)
-	solanaTokenMintPattern = regexp.MustCompile(`^[1-9A-HJ-NP-Za-km-z]{32,44}$`)
+	eip155ChainPattern = regexp.MustCompile(`^eip155:[0-9]+$`)
+	evmAddressPattern  = regexp.MustCompile(`^0x[0-9a-fA-F]{40}$`)
+)
+
+// CAIP-2 chain namespaces.
+const (
+	NamespaceEIP155 = "eip155"
+	NamespaceSolana = "solana"
 )
 
 const (
 	solanaMainnetRef = "5eykt4UsFv8P8NJdTREpY1vzqKqZKvdp"
 )
 
+// SolanaMainnetCAIP2 is the CAIP-2 ID of Solana mainnet, the only Solana
+// cluster defi-cli supports.
+const (
+	SolanaMainnetCAIP2 = NamespaceSolana + ":" + solanaMainnetRef
+)
+
+// CAIP-19 asset namespaces. SPL mints are written token:<mint> in output;
+// spl:<mint> is accepted as input.
 const (
-	solanaMainnetCAIP2 = "solana:" + solanaMainnetRef
+	assetNamespaceERC20 = "erc20"
+	assetNamespaceToken = "token"
+	assetNamespaceSPL   = "spl"
 )
 
 type Chain struct {
@@ -36,11 +51,11 @@ func (c Chain) Namespace() string {
 }
 
 func (c Chain) IsEVM() bool {
-	return c.Namespace() == "eip155"
+	return c.Namespace() == NamespaceEIP155
 }
 
 func (c Chain) IsSolana() bool {
-	return c.Namespace() == "solana"
+	return c.Namespace() == NamespaceSolana
 }
 
 type Asset struct {
@@ -55,6 +70,10 @@ type Token struct {
 	Symbol   string
 	Address  string
 	Decimals int
+	// Lists names the token lists the token belongs to (BuiltinTokenList
+	// for the bootstrap registry); used by --prefer-token-list when a
+	// symbol is ambiguous.
+	Lists []string
 }
 
 var chainBySlug = map[string]Chain{
@@ -108,11 +127,12 @@ var chainBySlug = map[string]Chain{
 	"taiko hoodi":   {Name: "Taiko Hoodi", Slug: "taiko-hoodi", CAIP2: "eip155:167013", EVMChainID: 167013},
 	"taiko-hoodi":   {Name: "Taiko Hoodi", Slug: "taiko-hoodi", CAIP2: "eip155:167013", EVMChainID: 167013},
 	"hoodi":         {Name: "Taiko Hoodi", Slug: "taiko-hoodi", CAIP2: "eip155:167013", EVMChainID: 167013},
-	"solana":        {Name: "Solana", Slug: "solana", CAIP2: solanaMainnetCAIP2},
+	"solana":        {Name: "Solana", Slug: "solana", CAIP2: SolanaMainnetCAIP2},
 	"solana-mainnet": {
-		Name: "Solana", Slug: "solana", CAIP2: solanaMainnetCAIP2,
+		Name: "Solana", Slug: "solana", CAIP2: SolanaMainnetCAIP2,
 	},
-	"mainnet-beta": {Name: "Solana", Slug: "solana", CAIP2: solanaMainnetCAIP2},
+	"mainnet-beta":   {Name: "Solana", Slug: "solana", CAIP2: SolanaMainnetCAIP2},
+	"solana:mainnet": {Name: "Solana", Slug: "solana", CAIP2: SolanaMainnetCAIP2},
 }
 
 var chainByID = map[int64]Chain{
@@ -158,6 +178,7 @@ var tokenRegistry = map[string][]Token{
 		{Symbol: "CRV", Address: "0xd533a949740bb3306d119cc777fa900ba034cd52", Decimals: 18},
 		{Symbol: "CRVUSD", Address: "0xf939e0a03fb07f59a73314e73794be0e57ac1b4e", Decimals: 18},
 		{Symbol: "DAI", Address: "0x6b175474e89094c44da98b954eedeac495271d0f", Decimals: 18},
+		{Symbol: "EETH", Address: "0x35fa164735182de50811e8e2e824cfb9b6118ac2", Decimals: 18},
 		{Symbol: "ENA", Address: "0x57e114b691db790c35207b2e685d4a43181e6061", Decimals: 18},
 		{Symbol: "ETHFI", Address: "0xfe0c30065b384f05761f15d0cc899d4f9f9cc0eb", Decimals: 18},
 		{Symbol: "EURC", Address: "0x1abaea1f7c830bd89acc67ec4af516284b1bc33c", Decimals: 6},
@@ -169,7 +190,9 @@ var tokenRegistry = map[string][]Token{
 		{Symbol: "PAXG", Address: "0x45804880de22913dafe09f4980848ece6ecbaf78", Decimals: 18},
 		{Symbol: "PENDLE", Address: "0x808507121b80c02388fad14726482e061b8da827", Decimals: 18},
 		{Symbol: "PEPE", Address: "0x6982508145454ce325ddbe47a25d4ec3d2311933", Decimals: 18},
+		{Symbol: "RETH", Address: "0xae78736cd615f374d3085123a210448e74fc6393", Decimals: 18},
 		{Symbol: "SHIB", Address: "0x95ad61b0a150d79219dcf64e1e6cc01f0b64c4ce", Decimals: 18},
+		{Symbol: "STETH", Address: "0xae7ab96520de3a18e5e111b5eaab095312d7fe84", Decimals: 18},
 		{Symbol: "TAIKO", Address: "0x10dea67478c5f8c5e2d90e5e9b26dbe60c54d800", Decimals: 18},
 		{Symbol: "TUSD", Address: "0x0000000000085d4780b73119b644ae5ecd22b376", Decimals: 18},
 		{Symbol: "UNI", Address: "0x1f9840a85d5af5bf1d1762f925bdaddc4201f984", Decimals: 18},
@@ -327,6 +350,7 @@ var tokenRegistry = map[string][]Token{
 		{Symbol: "SNX", Address: "0x22e6966b799c4d5b13be962e1d117b56327fda66", Decimals: 18},
 		{Symbol: "UNI", Address: "0xc3de830ea07524a0761646a6a4e4be0e114a3c83", Decimals: 18},
 		{Symbol: "USDC", Address: "0x833589fcd6edb6e08f4c7c32d4f71b54bda02913", Decimals: 6},
+		{Symbol: "USDbC", Address: "0xd9aaec86b65d86f6a7b5b1b0c42ffa531710b6ca", Decimals: 6},
 		{Symbol: "USDE", Address: "0x5d3a1ff2b6bab83b63cd9ad0787074081a52ef34", Decimals: 18},
 		{Symbol: "USDS", Address: "0x820c137fa70c8691f0e44dc420a5e53c168921dc", Decimals: 18},
 		{Symbol: "USDT", Address: "0xfde4c96c8593536e31f229ea8f37b2ada2699bb2", Decimals: 6},
@@ -355,6 +379,7 @@ var tokenRegistry = map[string][]Token{
 		{Symbol: "TUSD", Address: "0x4d15a3a2286d883af0aa1b3f21367843fac63e07", Decimals: 18},
 		{Symbol: "UNI", Address: "0xfa7f8980b0f1e64a2062791cc3b0871572f1f7f0", Decimals: 18},
 		{Symbol: "USDC", Address: "0xaf88d065e77c8cc2239327c5edb3a432268e5831", Decimals: 6},
+		{Symbol: "USDC.e", Address: "0xff970a61a04b1ca14834a43f5de4533ebddb5cc8", Decimals: 6},
 		{Symbol: "USDE", Address: "0x5d3a1ff2b6bab83b63cd9ad0787074081a52ef34", Decimals: 18},
 		{Symbol: "USDS", Address: "0x6491c05a82219b8d1479057361ff1654749b876b", Decimals: 18},
 		{Symbol: "USDT", Address: "0xfd086bc7cd5c481dcc9c85ebe478a1c0b69fcbb9", Decimals: 6},
@@ -475,7 +500,7 @@ var tokenRegistry = map[string][]Token{
 		{Symbol: "WCBTC", Address: "0x3100000000000000000000000000000000000006", Decimals: 18},
 		{Symbol: "USDC", Address: "0xE045e6c36cF77FAA2CfB54466D71A3aEF7bBE839", Decimals: 6},
 	},
-	solanaMainnetCAIP2: {
+	SolanaMainnetCAIP2: {
 		{Symbol: "USDC", Address: "EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v", Decimals: 6},
 		{Symbol: "USDT", Address: "Es9vMFrzaCERmJfrF4H2FYD4KCoNkY11McCe8BenwNYB", Decimals: 6},
 		{Symbol: "SOL", Address: "So11111111111111111111111111111111111111112", Decimals: 9},
@@ -497,6 +522,9 @@ func ParseChain(input string) (Chain, error) {
 	if chain, ok := chainBySlug[norm]; ok {
 		return chain, nil
 	}
+	if chain, ok := lookupSyncedSlug(norm); ok {
+		return chain, nil
+	}
 
 	if eip155ChainPattern.MatchString(norm) {
 		parts := strings.Split(norm, ":")
@@ -504,17 +532,20 @@ func ParseChain(input string) (Chain, error) {
 		if known, ok := chainByID[id]; ok {
 			return known, nil
 		}
+		if synced, ok := lookupSyncedID(id); ok {
+			return synced, nil
+		}
 		return Chain{Name: fmt.Sprintf("EVM-%d", id), Slug: fmt.Sprintf("evm-%d", id), CAIP2: norm, EVMChainID: id}, nil
 	}
 
-	if namespace, reference, ok := parseCAIP2(raw); ok && namespace == "solana" {
+	if namespace, reference, ok := parseCAIP2(raw); ok && namespace == NamespaceSolana {
 		if reference == solanaMainnetRef {
-			if known, ok := chainByCAIP2[solanaMainnetCAIP2]; ok {
+			if known, ok := chainByCAIP2[SolanaMainnetCAIP2]; ok {
 				return known, nil
 			}
 			return chainBySlug["solana"], nil
 		}
-		if solanaTokenMintPattern.MatchString(reference) {
+		if isBase58(reference) {
 			return Chain{}, clierr.New(clierr.CodeUnsupported, "solana non-mainnet references are not supported; only solana mainnet is supported")
 		}
 		return Chain{}, clierr.New(clierr.CodeUsage, fmt.Sprintf("unsupported chain input: %s", input))
@@ -528,13 +559,29 @@ func ParseChain(input string) (Chain, error) {
 		if chain, ok := chainByID[id]; ok {
 			return chain, nil
 		}
+		if synced, ok := lookupSyncedID(id); ok {
+			return synced, nil
+		}
 		return Chain{Name: fmt.Sprintf("EVM-%d", id), Slug: fmt.Sprintf("evm-%d", id), CAIP2: fmt.Sprintf("eip155:%d", id), EVMChainID: id}, nil
 	}
 
-	return Chain{}, clierr.New(clierr.CodeUsage, fmt.Sprintf("unsupported chain input: %s", input))
+	// The name may belong to a chain added since the chain list was synced.
+	if refreshSyncedChains() {
+		if chain, ok := lookupSyncedSlug(norm); ok {
+			return chain, nil
+		}
+	}
+
+	return Chain{}, clierr.New(clierr.CodeUsage, fmt.Sprintf("unsupported chain input: %s; use the chain ID or run `chains sync` to add chains from chainlist", input))
 }
 
 func ParseAsset(input string, chain Chain) (Asset, error) {
+	return ParseAssetWithOptions(input, chain, DefaultResolveOptions())
+}
+
+// ParseAssetWithOptions is ParseAsset with an explicit policy for symbols
+// that match more than one registry token on the chain.
+func ParseAssetWithOptions(input string, chain Chain, opts ResolveOptions) (Asset, error) {
 	raw := strings.TrimSpace(input)
 	if raw == "" {
 		return Asset{}, clierr.New(clierr.CodeUsage, "asset is required")
@@ -551,46 +598,58 @@ func ParseAsset(input string, chain Chain) (Asset, error) {
 		assetNamespace := strings.ToLower(strings.TrimSpace(assetParts[0]))
 		address := strings.TrimSpace(assetParts[1])
 		if chain.IsEVM() {
-			if assetNamespace != "erc20" || !evmAddressPattern.MatchString(address) {
+			if assetNamespace != assetNamespaceERC20 || !evmAddressPattern.MatchString(address) {
 				return Asset{}, clierr.New(clierr.CodeUsage, fmt.Sprintf("invalid CAIP-19 asset format: %s", input))
 			}
 		} else if chain.IsSolana() {
-			if assetNamespace != "token" || !solanaTokenMintPattern.MatchString(address) {
+			if (assetNamespace != assetNamespaceToken && assetNamespace != assetNamespaceSPL) || !IsSolanaAddress(address) {
 				return Asset{}, clierr.New(clierr.CodeUsage, fmt.Sprintf("invalid CAIP-19 asset format: %s", input))
 			}
 		} else {
 			return Asset{}, clierr.New(clierr.CodeUnsupported, fmt.Sprintf("unsupported chain namespace: %s", chain.Namespace()))
 		}
 		addr := canonicalizeAddress(chain.CAIP2, address)
-		token, _ := findTokenByAddress(chain.CAIP2, addr)
+		token := lookupAddress(chain, addr, opts)
 		return Asset{ChainID: chain.CAIP2, AssetID: canonicalAssetID(chain.CAIP2, addr), Address: addr, Symbol: token.Symbol, Decimals: token.Decimals}, nil
 	}
 
 	if chain.IsEVM() && evmAddressPattern.MatchString(raw) {
 		addr := canonicalizeAddress(chain.CAIP2, raw)
-		token, _ := findTokenByAddress(chain.CAIP2, addr)
+		token := lookupAddress(chain, addr, opts)
 		return Asset{ChainID: chain.CAIP2, AssetID: canonicalAssetID(chain.CAIP2, addr), Address: addr, Symbol: token.Symbol, Decimals: token.Decimals}, nil
 	}
 
-	if chain.IsSolana() && solanaTokenMintPattern.MatchString(raw) {
+	if chain.IsSolana() && IsSolanaAddress(raw) {
 		addr := canonicalizeAddress(chain.CAIP2, raw)
-		token, _ := findTokenByAddress(chain.CAIP2, addr)
+		token := lookupAddress(chain, addr, opts)
 		return Asset{ChainID: chain.CAIP2, AssetID: canonicalAssetID(chain.CAIP2, addr), Address: addr, Symbol: token.Symbol, Decimals: token.Decimals}, nil
 	}
 
 	matches := findTokensBySymbol(chain.CAIP2, raw)
+	if len(matches) == 0 && chain.IsEVM() && strings.EqualFold(raw, NativeSymbol(chain)) {
+		return NativeAsset(chain), nil
+	}
+	if len(matches) == 0 && opts.ResolveUnknown && unknownResolver != nil {
+		token, err := unknownResolver.ResolveSymbol(chain, raw)
+		if err != nil {
+			if _, ok := clierr.As(err); ok {
+				return Asset{}, err
+			}
+			return Asset{}, clierr.Wrap(clierr.CodeUsage, fmt.Sprintf("symbol %s not found in registry for chain %s", input, chain.CAIP2), err)
+		}
+		matches = []Token{token}
+	}
 	if len(matches) == 0 {
-		return Asset{}, clierr.New(clierr.CodeUsage, fmt.Sprintf("symbol %s not found in registry for chain %s", input, chain.CAIP2))
+		return Asset{}, clierr.New(clierr.CodeUsage, fmt.Sprintf("symbol %s not found in registry for chain %s; use the token address, add it with `assets add`, or pass --resolve-unknown", input, chain.CAIP2))
 	}
+	t := matches[0]
 	if len(matches) > 1 {
-		addresses := make([]string, 0, len(matches))
-		for _, m := range matches {
-			addresses = append(addresses, m.Address)
+		resolved, err := resolveAmbiguousSymbol(input, chain, matches, opts)
+		if err != nil {
+			return Asset{}, err
 		}
-		sort.Strings(addresses)
-		return Asset{}, clierr.New(clierr.CodeUsage, fmt.Sprintf("symbol %s is ambiguous on chain %s, use address or CAIP-19 (%s)", input, chain.CAIP2, strings.Join(addresses, ", ")))
+		t = resolved
 	}
-	t := matches[0]
 	addr := canonicalizeAddress(chain.CAIP2, t.Address)
 	return Asset{
 		ChainID:  chain.CAIP2,
@@ -638,11 +697,14 @@ func caip2MatchesChain(input string, chain Chain) bool {
 	}
 	if chain.IsSolana() {
 		inputNamespace, inputReference, ok := parseCAIP2(input)
-		if !ok || inputNamespace != "solana" {
+		if !ok || inputNamespace != NamespaceSolana {
 			return false
 		}
+		if strings.EqualFold(inputReference, "mainnet") {
+			return chain.CAIP2 == SolanaMainnetCAIP2
+		}
 		chainNS, chainReference, ok := parseCAIP2(chain.CAIP2)
-		if !ok || chainNS != "solana" {
+		if !ok || chainNS != NamespaceSolana {
 			return false
 		}
 		return inputReference == chainReference
@@ -660,7 +722,7 @@ func buildChainByCAIP2() map[string]Chain {
 
 func canonicalizeAddress(chainID, address string) string {
 	addr := strings.TrimSpace(address)
-	if chainNamespace(chainID) == "eip155" {
+	if chainNamespace(chainID) == NamespaceEIP155 {
 		return strings.ToLower(addr)
 	}
 	return addr
@@ -669,10 +731,10 @@ func canonicalizeAddress(chainID, address string) string {
 func canonicalAssetID(chainID, address string) string {
 	addr := canonicalizeAddress(chainID, address)
 	switch chainNamespace(chainID) {
-	case "eip155":
-		return fmt.Sprintf("%s/erc20:%s", chainID, addr)
-	case "solana":
-		return fmt.Sprintf("%s/token:%s", chainID, addr)
+	case NamespaceEIP155:
+		return fmt.Sprintf("%s/%s:%s", chainID, assetNamespaceERC20, addr)
+	case NamespaceSolana:
+		return fmt.Sprintf("%s/%s:%s", chainID, assetNamespaceToken, addr)
 	default:
 		return fmt.Sprintf("%s/asset:%s", chainID, addr)
 	}
@@ -680,7 +742,7 @@ func canonicalAssetID(chainID, address string) string {
 
 func findTokenByAddress(chainID, address string) (Token, bool) {
 	target := canonicalizeAddress(chainID, address)
-	for _, t := range tokenRegistry[chainID] {
+	for _, t := range registryTokens(chainID) {
 		candidate := canonicalizeAddress(chainID, t.Address)
 		if candidate == target {
 			return Token{Symbol: strings.ToUpper(t.Symbol), Address: candidate, Decimals: t.Decimals}, true
@@ -689,11 +751,36 @@ func findTokenByAddress(chainID, address string) (Token, bool) {
 	return Token{}, false
 }
 
+// lookupAddress returns registry metadata for addr, falling back to the
+// unknown-token resolver when opts allow it. Lookup failures leave the
+// symbol and decimals empty, as for any unregistered address.
+func lookupAddress(chain Chain, addr string, opts ResolveOptions) Token {
+	if token, ok := findTokenByAddress(chain.CAIP2, addr); ok {
+		return token
+	}
+	if token, ok := nativeToken(chain, addr); ok {
+		return token
+	}
+	if !opts.ResolveUnknown || unknownResolver == nil {
+		return Token{}
+	}
+	token, err := unknownResolver.ResolveAddress(chain, addr)
+	if err != nil {
+		return Token{}
+	}
+	return Token{Symbol: strings.ToUpper(token.Symbol), Address: addr, Decimals: token.Decimals}
+}
+
 func findTokensBySymbol(chainID, symbol string) []Token {
 	matches := []Token{}
-	for _, t := range tokenRegistry[chainID] {
+	for _, t := range registryTokens(chainID) {
 		if strings.EqualFold(t.Symbol, symbol) {
-			matches = append(matches, Token{Symbol: strings.ToUpper(t.Symbol), Address: canonicalizeAddress(chainID, t.Address), Decimals: t.Decimals})
+			matches = append(matches, Token{
+				Symbol:   strings.ToUpper(t.Symbol),
+				Address:  canonicalizeAddress(chainID, t.Address),
+				Decimals: t.Decimals,
+				Lists:    tokenLists(t),
+			})
 		}
 	}
 	return matches
@@ -711,6 +798,21 @@ func LookupByAddress(chainID, address string) (Token, bool) {
 	return findTokenByAddress(chainID, canonicalizeAddress(chainID, address))
 }
 
+// ChainTokens returns the registered tokens for chainID with canonical
+// addresses, in registry order, followed by user tokens.
+func ChainTokens(chainID string) []Token {
+	tokens := make([]Token, 0, len(registryTokens(chainID)))
+	for _, t := range registryTokens(chainID) {
+		tokens = append(tokens, Token{
+			Symbol:   strings.ToUpper(t.Symbol),
+			Address:  canonicalizeAddress(chainID, t.Address),
+			Decimals: t.Decimals,
+			Lists:    tokenLists(t),
+		})
+	}
+	return tokens
+}
+
 // ListChains returns all unique supported chains sorted by CAIP-2 identifier.
 // Each chain includes its canonical aliases (excluding the primary slug).
 func ListChains() []ChainEntry {
@@ -1,6 +1,7 @@
 package id
 
 import (
+	"errors"
 	"strings"
 	"testing"
 
@@ -141,6 +142,74 @@ func TestParseAssetSolanaSymbolAndMint(t *testing.T) {
 	}
 }
 
+func TestParseChainSolanaMainnetAlias(t *testing.T) {
+	chain, err := ParseChain("solana:mainnet")
+	if err != nil {
+		t.Fatalf("ParseChain(solana:mainnet) failed: %v", err)
+	}
+	if chain.CAIP2 != SolanaMainnetCAIP2 {
+		t.Fatalf("unexpected solana CAIP2: %s", chain.CAIP2)
+	}
+}
+
+func TestParseAssetSolanaSPLNamespace(t *testing.T) {
+	chain, _ := ParseChain("solana")
+	for _, input := range []string{
+		"solana:5eykt4UsFv8P8NJdTREpY1vzqKqZKvdp/spl:EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v",
+		"solana:mainnet/spl:EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v",
+		"solana:mainnet/token:EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v",
+	} {
+		asset, err := ParseAsset(input, chain)
+		if err != nil {
+			t.Fatalf("ParseAsset(%s) failed: %v", input, err)
+		}
+		if asset.AssetID != "solana:5eykt4UsFv8P8NJdTREpY1vzqKqZKvdp/token:EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v" || asset.Symbol != "USDC" {
+			t.Fatalf("unexpected asset for %s: %+v", input, asset)
+		}
+	}
+}
+
+func TestParseAssetSolanaRejectsInvalidMint(t *testing.T) {
+	chain, _ := ParseChain("solana")
+	// Valid base58 alphabet and length, but decodes to more than 32 bytes.
+	for _, input := range []string{
+		"zzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzz",
+		"solana:mainnet/spl:zzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzz",
+		"solana:mainnet/erc20:EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v",
+	} {
+		if _, err := ParseAsset(input, chain); err == nil {
+			t.Fatalf("expected %s to be rejected", input)
+		}
+	}
+}
+
+func TestBase58RoundTrip(t *testing.T) {
+	for _, addr := range []string{
+		"EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v",
+		"So11111111111111111111111111111111111111112",
+		"11111111111111111111111111111111",
+	} {
+		decoded, err := DecodeBase58(addr)
+		if err != nil {
+			t.Fatalf("DecodeBase58(%s) failed: %v", addr, err)
+		}
+		if len(decoded) != 32 {
+			t.Fatalf("expected 32 bytes for %s, got %d", addr, len(decoded))
+		}
+		if got := EncodeBase58(decoded); got != addr {
+			t.Fatalf("round trip of %s returned %s", addr, got)
+		}
+		if !IsSolanaAddress(addr) {
+			t.Fatalf("expected %s to be a solana address", addr)
+		}
+	}
+	for _, bad := range []string{"", "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48", "EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1l", "abc"} {
+		if IsSolanaAddress(bad) {
+			t.Fatalf("expected %q to be rejected", bad)
+		}
+	}
+}
+
 func TestParseAssetCAIP19MixedCaseEVM(t *testing.T) {
 	chain, _ := ParseChain("ethereum")
 	asset, err := ParseAsset("EIP155:1/ERC20:0xA0B86991C6218B36C1D19D4A2E9EB0CE3606EB48", chain)
@@ -571,3 +640,274 @@ func TestParseAssetFibrousChainBootstrapAddresses(t *testing.T) {
 		}
 	}
 }
+
+func TestParseAssetAmbiguousSymbolPolicies(t *testing.T) {
+	chain, err := ParseChain("eip155:999999")
+	if err != nil {
+		t.Fatalf("ParseChain failed: %v", err)
+	}
+	tokenRegistry[chain.CAIP2] = []Token{
+		{Symbol: "USDX", Address: "0x00000000000000000000000000000000000000b2", Decimals: 6, Lists: []string{"bridged"}},
+		{Symbol: "USDX", Address: "0x00000000000000000000000000000000000000a1", Decimals: 6},
+	}
+	t.Cleanup(func() { delete(tokenRegistry, chain.CAIP2) })
+
+	_, err = ParseAssetWithOptions("USDX", chain, ResolveOptions{Policy: ResolvePolicyError})
+	cErr, ok := clierr.As(err)
+	if !ok || cErr.Code != clierr.CodeUsage {
+		t.Fatalf("expected usage error for ambiguous symbol, got %v", err)
+	}
+	details, ok := cErr.Details.(map[string]any)
+	if !ok {
+		t.Fatalf("expected candidate details, got %#v", cErr.Details)
+	}
+	candidates, ok := details["candidates"].([]AssetCandidate)
+	if !ok || len(candidates) != 2 || candidates[0].Address != "0x00000000000000000000000000000000000000a1" {
+		t.Fatalf("unexpected candidates: %#v", details["candidates"])
+	}
+
+	first, err := ParseAssetWithOptions("usdx", chain, ResolveOptions{Policy: ResolvePolicyFirst})
+	if err != nil || first.Address != "0x00000000000000000000000000000000000000b2" {
+		t.Fatalf("expected registry-order pick, got %+v err=%v", first, err)
+	}
+	builtin, err := ParseAssetWithOptions("USDX", chain, ResolveOptions{Policy: ResolvePolicyError, PreferTokenList: BuiltinTokenList})
+	if err != nil || builtin.Address != "0x00000000000000000000000000000000000000a1" {
+		t.Fatalf("expected the bootstrap token to be on the builtin list, got %+v err=%v", builtin, err)
+	}
+	listed, err := ParseAssetWithOptions("USDX", chain, ResolveOptions{Policy: ResolvePolicyError, PreferTokenList: "Bridged"})
+	if err != nil || listed.Address != "0x00000000000000000000000000000000000000b2" {
+		t.Fatalf("expected preferred list pick, got %+v err=%v", listed, err)
+	}
+	if _, err := ParseAssetWithOptions("USDX", chain, ResolveOptions{Policy: ResolvePolicyError, PreferTokenList: "unknown"}); err == nil {
+		t.Fatal("expected ambiguity error when preferred list has no candidates")
+	}
+}
+
+func TestParseResolvePolicy(t *testing.T) {
+	if policy, err := ParseResolvePolicy(""); err != nil || policy != ResolvePolicyError {
+		t.Fatalf("expected default error policy, got %q err=%v", policy, err)
+	}
+	if policy, err := ParseResolvePolicy("First"); err != nil || policy != ResolvePolicyFirst {
+		t.Fatalf("expected first, got %q err=%v", policy, err)
+	}
+	if _, err := ParseResolvePolicy("highest-liquidity"); err == nil {
+		t.Fatal("expected the removed highest-liquidity policy to be rejected")
+	}
+	if _, err := ParseResolvePolicy("random"); err == nil {
+		t.Fatal("expected invalid policy error")
+	}
+}
+
+func TestEquivalentAssets(t *testing.T) {
+	base, _ := ParseChain("base")
+	usdc, err := ParseAssetWithOptions("USDC", base, ResolveOptions{})
+	if err != nil {
+		t.Fatalf("parse USDC: %v", err)
+	}
+	equivalents := EquivalentAssets(usdc, base, ResolveOptions{})
+	if len(equivalents) != 1 || equivalents[0].Address != "0xd9aaec86b65d86f6a7b5b1b0c42ffa531710b6ca" {
+		t.Fatalf("expected USDbC equivalent, got %+v", equivalents)
+	}
+	if got := EquivalentAssets(usdc, base, ResolveOptions{StrictAsset: true}); got != nil {
+		t.Fatalf("expected no equivalents in strict mode, got %+v", got)
+	}
+	if !SymbolsEquivalent("weth", "ETH") || SymbolsEquivalent("USDC", "USDT") {
+		t.Fatal("unexpected symbol equivalence")
+	}
+}
+
+func TestParseEquivalentAssetFallsBackToBridgedSymbol(t *testing.T) {
+	tempo, _ := ParseChain("tempo")
+	asset, err := ParseEquivalentAsset("USDC", tempo, ResolveOptions{})
+	if err != nil {
+		t.Fatalf("expected USDC to resolve to a bridged variant on tempo: %v", err)
+	}
+	if asset.Symbol != "USDC.E" {
+		t.Fatalf("expected USDC.e, got %+v", asset)
+	}
+	if _, err := ParseEquivalentAsset("USDC", tempo, ResolveOptions{StrictAsset: true}); err == nil {
+		t.Fatal("expected strict mode to require the exact symbol")
+	}
+
+	optimism, _ := ParseChain("optimism")
+	exact, err := ParseEquivalentAsset("USDC.e", optimism, ResolveOptions{})
+	if err != nil || exact.Address != "0x7f5c764cbc14f9669b88837ca1490cca17c31607" {
+		t.Fatalf("expected exact symbol to win when registered, got %+v err=%v", exact, err)
+	}
+}
+
+func TestUserTokensExtendRegistry(t *testing.T) {
+	base, err := ParseChain("base")
+	if err != nil {
+		t.Fatalf("ParseChain failed: %v", err)
+	}
+	t.Cleanup(func() { SetUserTokens(nil) })
+	SetUserTokens(map[string][]Token{base.CAIP2: {
+		{Symbol: "DEGEN", Address: "0x4ED4E862860beD51a9570b96d89aF5E1B0Efefed", Decimals: 18, Lists: []string{"user"}},
+		// Built-in USDC keeps its registry metadata.
+		{Symbol: "FAKE", Address: "0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913", Decimals: 2},
+	}})
+
+	asset, err := ParseAsset("degen", base)
+	if err != nil {
+		t.Fatalf("expected user token to resolve, got %v", err)
+	}
+	if asset.Address != "0x4ed4e862860bed51a9570b96d89af5e1b0efefed" || asset.Decimals != 18 || asset.Symbol != "DEGEN" {
+		t.Fatalf("unexpected user asset %+v", asset)
+	}
+	if !IsUserToken(base.CAIP2, asset.Address) || IsBuiltinToken(base.CAIP2, asset.Address) {
+		t.Fatal("expected DEGEN to be a user token")
+	}
+	if _, err := ParseAsset("FAKE", base); err == nil {
+		t.Fatal("expected user entry shadowing a built-in address to be dropped")
+	}
+	if usdc, err := ParseAsset("0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913", base); err != nil || usdc.Symbol != "USDC" {
+		t.Fatalf("expected built-in metadata for USDC, got %+v err=%v", usdc, err)
+	}
+
+	SetUserTokens(nil)
+	if _, err := ParseAsset("DEGEN", base); err == nil {
+		t.Fatal("expected DEGEN to be unknown after clearing user tokens")
+	}
+}
+
+type stubUnknownResolver struct {
+	calls int
+}
+
+func (r *stubUnknownResolver) ResolveAddress(chain Chain, address string) (Token, error) {
+	r.calls++
+	return Token{Symbol: "mystery", Address: address, Decimals: 9}, nil
+}
+
+func (r *stubUnknownResolver) ResolveSymbol(chain Chain, symbol string) (Token, error) {
+	r.calls++
+	if symbol != "MYS" {
+		return Token{}, errors.New("not in token list")
+	}
+	return Token{Symbol: "MYS", Address: "0x00000000000000000000000000000000000000C1", Decimals: 9}, nil
+}
+
+func TestParseAssetResolveUnknown(t *testing.T) {
+	base, err := ParseChain("base")
+	if err != nil {
+		t.Fatalf("ParseChain failed: %v", err)
+	}
+	resolver := &stubUnknownResolver{}
+	SetUnknownResolver(resolver)
+	t.Cleanup(func() { SetUnknownResolver(nil) })
+	address := "0x00000000000000000000000000000000000000c1"
+
+	if asset, err := ParseAssetWithOptions(address, base, ResolveOptions{}); err != nil || asset.Symbol != "" || resolver.calls != 0 {
+		t.Fatalf("expected no lookup without ResolveUnknown, got %+v calls=%d err=%v", asset, resolver.calls, err)
+	}
+	if _, err := ParseAssetWithOptions("MYS", base, ResolveOptions{}); err == nil {
+		t.Fatal("expected unknown symbol error without ResolveUnknown")
+	}
+
+	opts := ResolveOptions{ResolveUnknown: true}
+	asset, err := ParseAssetWithOptions(address, base, opts)
+	if err != nil || asset.Symbol != "MYSTERY" || asset.Decimals != 9 {
+		t.Fatalf("unexpected address lookup %+v err=%v", asset, err)
+	}
+	asset, err = ParseAssetWithOptions("mys", base, ResolveOptions{ResolveUnknown: true})
+	if err == nil {
+		t.Fatalf("expected resolver error to surface, got %+v", asset)
+	}
+	asset, err = ParseAssetWithOptions("MYS", base, opts)
+	if err != nil || asset.Address != address || asset.AssetID != "eip155:8453/erc20:"+address {
+		t.Fatalf("unexpected symbol lookup %+v err=%v", asset, err)
+	}
+	if usdc, err := ParseAssetWithOptions("USDC", base, opts); err != nil || usdc.Decimals != 6 || resolver.calls != 3 {
+		t.Fatalf("expected registry symbols to skip the resolver, got %+v calls=%d err=%v", usdc, resolver.calls, err)
+	}
+}
+
+func TestParseChainSyncedChains(t *testing.T) {
+	t.Cleanup(func() {
+		SetSyncedChains(nil)
+		SetChainRefresher(nil)
+	})
+	SetSyncedChains([]ChainEntry{
+		{Chain: Chain{Name: "Unichain", Slug: "unichain", CAIP2: "eip155:130", EVMChainID: 130}, Aliases: []string{"uni"}},
+		// Built-in chain IDs and slugs are never overridden.
+		{Chain: Chain{Name: "Base Mainnet", Slug: "base-mainnet", CAIP2: "eip155:8453", EVMChainID: 8453}},
+		{Chain: Chain{Name: "Other", Slug: "ethereum", CAIP2: "eip155:777", EVMChainID: 777}},
+	})
+
+	for _, input := range []string{"unichain", "UNI", "130", "eip155:130"} {
+		chain, err := ParseChain(input)
+		if err != nil {
+			t.Fatalf("ParseChain(%s) failed: %v", input, err)
+		}
+		if chain.Slug != "unichain" || chain.EVMChainID != 130 {
+			t.Fatalf("unexpected chain for %s: %+v", input, chain)
+		}
+	}
+	if chain, _ := ParseChain("8453"); chain.Slug != "base" {
+		t.Fatalf("expected built-in base to win, got %+v", chain)
+	}
+	if chain, _ := ParseChain("ethereum"); chain.EVMChainID != 1 {
+		t.Fatalf("expected built-in ethereum slug to win, got %+v", chain)
+	}
+	if !IsSyncedChain(130) || IsSyncedChain(8453) {
+		t.Fatal("unexpected synced chain membership")
+	}
+
+	calls := 0
+	SetChainRefresher(func() bool {
+		calls++
+		SetSyncedChains([]ChainEntry{{Chain: Chain{Name: "Ink Sepolia", Slug: "ink-sepolia", CAIP2: "eip155:763373", EVMChainID: 763373}}})
+		return true
+	})
+	chain, err := ParseChain("ink-sepolia")
+	if err != nil || chain.EVMChainID != 763373 || calls != 1 {
+		t.Fatalf("expected refresh to resolve the new chain, got %+v err=%v calls=%d", chain, err, calls)
+	}
+	if _, err := ParseChain("base"); err != nil || calls != 1 {
+		t.Fatalf("expected known chains not to refresh, calls=%d err=%v", calls, err)
+	}
+}
+
+func TestParseAssetResolvesNativeSymbols(t *testing.T) {
+	cases := []struct {
+		chain  string
+		symbol string
+	}{
+		{"ethereum", "ETH"},
+		{"base", "eth"},
+		{"polygon", "POL"},
+		{"avalanche", "AVAX"},
+	}
+	for _, tc := range cases {
+		chain, err := ParseChain(tc.chain)
+		if err != nil {
+			t.Fatalf("ParseChain(%s) failed: %v", tc.chain, err)
+		}
+		asset, err := ParseAsset(tc.symbol, chain)
+		if err != nil {
+			t.Fatalf("ParseAsset(%s on %s) failed: %v", tc.symbol, tc.chain, err)
+		}
+		if !asset.IsNative() || asset.Address != NativeTokenAddress || asset.Decimals != 18 {
+			t.Fatalf("expected native %s on %s, got %+v", tc.symbol, tc.chain, asset)
+		}
+		byAddress, err := ParseAsset(NativeTokenAddress, chain)
+		if err != nil || byAddress.AssetID != asset.AssetID || byAddress.Symbol != asset.Symbol {
+			t.Fatalf("expected the placeholder address to resolve like the symbol, got %+v err=%v", byAddress, err)
+		}
+	}
+
+	polygon, _ := ParseChain("polygon")
+	if _, err := ParseAsset("ETH", polygon); err == nil {
+		t.Fatal("expected ETH not to resolve as native on Polygon")
+	}
+	ethereum, _ := ParseChain("ethereum")
+	weth, _ := ParseAsset("WETH", ethereum)
+	if weth.IsNative() {
+		t.Fatal("expected WETH to be an ERC-20")
+	}
+	wrapped, ok := WrappedNativeToken(ethereum)
+	if !ok || wrapped.Address != weth.Address {
+		t.Fatalf("expected WETH as wrapped native on Ethereum, got %+v", wrapped)
+	}
+}
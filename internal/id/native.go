@@ -0,0 +1,77 @@
+package id
+
+import "strings"
+
+// NativeTokenAddress is the placeholder address the registry and most EVM
+// aggregators use for a chain's gas token. Providers that expect another
+// sentinel (the zero address) translate it themselves.
+const NativeTokenAddress = "0xeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee"
+
+// NativeSymbol returns the conventional gas token symbol for an EVM chain,
+// defaulting to ETH.
+func NativeSymbol(chain Chain) string {
+	switch chain.EVMChainID {
+	case 56:
+		return "BNB"
+	case 100:
+		return "XDAI"
+	case 137:
+		return "POL"
+	case 143:
+		return "MON"
+	case 146:
+		return "S"
+	case 252:
+		return "frxETH"
+	case 999:
+		return "HYPE"
+	case 4114:
+		return "cBTC"
+	case 5000:
+		return "MNT"
+	case 42220:
+		return "CELO"
+	case 43114:
+		return "AVAX"
+	case 80094:
+		return "BERA"
+	default:
+		return "ETH"
+	}
+}
+
+// IsNative reports whether a is an EVM chain's gas token rather than an
+// ERC-20. Native inputs are paid as transaction value and need no approval.
+func (a Asset) IsNative() bool {
+	return chainNamespace(a.ChainID) == NamespaceEIP155 && strings.EqualFold(strings.TrimSpace(a.Address), NativeTokenAddress)
+}
+
+// NativeAsset returns the gas token of an EVM chain at NativeTokenAddress.
+func NativeAsset(chain Chain) Asset {
+	return Asset{
+		ChainID:  chain.CAIP2,
+		AssetID:  canonicalAssetID(chain.CAIP2, NativeTokenAddress),
+		Address:  NativeTokenAddress,
+		Symbol:   strings.ToUpper(NativeSymbol(chain)),
+		Decimals: 18,
+	}
+}
+
+// WrappedNativeToken returns the registry's wrapped gas token for an EVM
+// chain (WETH for ETH, WPOL for POL), for venues that only trade ERC-20s.
+func WrappedNativeToken(chain Chain) (Token, bool) {
+	if !chain.IsEVM() {
+		return Token{}, false
+	}
+	return KnownToken(chain.CAIP2, "W"+NativeSymbol(chain))
+}
+
+// nativeToken returns registry-style metadata for the gas token when addr
+// is the native placeholder on an EVM chain.
+func nativeToken(chain Chain, addr string) (Token, bool) {
+	if !chain.IsEVM() || !strings.EqualFold(strings.TrimSpace(addr), NativeTokenAddress) {
+		return Token{}, false
+	}
+	asset := NativeAsset(chain)
+	return Token{Symbol: asset.Symbol, Address: asset.Address, Decimals: asset.Decimals}, true
+}
@@ -0,0 +1,150 @@
+package id
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+)
+
+// ResolvePolicy selects how ParseAsset handles a symbol that matches more
+// than one registry token on a chain.
+type ResolvePolicy string
+
+const (
+	ResolvePolicyError ResolvePolicy = "error"
+	ResolvePolicyFirst ResolvePolicy = "first"
+)
+
+// BuiltinTokenList names the bootstrap registry in Token.Lists, so
+// --prefer-token-list builtin picks the curated token over user imports.
+const BuiltinTokenList = "builtin"
+
+type ResolveOptions struct {
+	Policy ResolvePolicy
+	// PreferTokenList narrows ambiguous candidates to members of the named
+	// list before Policy is applied.
+	PreferTokenList string
+	// StrictAsset disables wrapped/bridged equivalence (USDC vs USDC.e,
+	// WETH vs ETH) so only the exact requested token matches.
+	StrictAsset bool
+	// ResolveUnknown looks up tokens missing from the registry through the
+	// resolver set with SetUnknownResolver.
+	ResolveUnknown bool
+}
+
+// UnknownResolver looks up tokens that are not in the registry. It is only
+// consulted when ResolveOptions.ResolveUnknown is set.
+type UnknownResolver interface {
+	// ResolveAddress returns the metadata of the token at address.
+	ResolveAddress(chain Chain, address string) (Token, error)
+	// ResolveSymbol returns the single token on chain with symbol.
+	ResolveSymbol(chain Chain, symbol string) (Token, error)
+}
+
+// AssetCandidate describes one registry match for an ambiguous symbol. It is
+// returned in error.details so callers can retry with an address or CAIP-19.
+type AssetCandidate struct {
+	AssetID  string   `json:"asset_id"`
+	Address  string   `json:"address"`
+	Symbol   string   `json:"symbol"`
+	Decimals int      `json:"decimals"`
+	Lists    []string `json:"lists,omitempty"`
+}
+
+var (
+	defaultResolveOptions = ResolveOptions{Policy: ResolvePolicyError}
+	unknownResolver       UnknownResolver
+)
+
+// SetUnknownResolver sets the resolver used for tokens missing from the
+// registry. A nil resolver disables lookups.
+func SetUnknownResolver(r UnknownResolver) {
+	unknownResolver = r
+}
+
+// SetDefaultResolveOptions sets the options used by ParseAsset. The CLI calls
+// it once per invocation after loading configuration.
+func SetDefaultResolveOptions(opts ResolveOptions) {
+	if opts.Policy == "" {
+		opts.Policy = ResolvePolicyError
+	}
+	defaultResolveOptions = opts
+}
+
+func DefaultResolveOptions() ResolveOptions {
+	return defaultResolveOptions
+}
+
+func ParseResolvePolicy(input string) (ResolvePolicy, error) {
+	switch ResolvePolicy(strings.ToLower(strings.TrimSpace(input))) {
+	case "", ResolvePolicyError:
+		return ResolvePolicyError, nil
+	case ResolvePolicyFirst:
+		return ResolvePolicyFirst, nil
+	default:
+		return "", clierr.New(clierr.CodeUsage, "--resolve-policy must be one of: error,first")
+	}
+}
+
+func resolveAmbiguousSymbol(input string, chain Chain, matches []Token, opts ResolveOptions) (Token, error) {
+	candidates := matches
+	if list := strings.TrimSpace(opts.PreferTokenList); list != "" {
+		preferred := make([]Token, 0, len(matches))
+		for _, t := range matches {
+			if tokenInList(t, list) {
+				preferred = append(preferred, t)
+			}
+		}
+		if len(preferred) > 0 {
+			candidates = preferred
+		}
+	}
+	if len(candidates) == 1 {
+		return candidates[0], nil
+	}
+
+	if opts.Policy == ResolvePolicyFirst {
+		return candidates[0], nil
+	}
+
+	addresses := make([]string, 0, len(candidates))
+	details := make([]AssetCandidate, 0, len(candidates))
+	for _, t := range candidates {
+		addresses = append(addresses, t.Address)
+		details = append(details, AssetCandidate{
+			AssetID:  canonicalAssetID(chain.CAIP2, t.Address),
+			Address:  t.Address,
+			Symbol:   t.Symbol,
+			Decimals: t.Decimals,
+			Lists:    t.Lists,
+		})
+	}
+	sort.Strings(addresses)
+	sort.Slice(details, func(i, j int) bool { return details[i].AssetID < details[j].AssetID })
+	message := fmt.Sprintf("symbol %s is ambiguous on chain %s, use address or CAIP-19 (%s)", input, chain.CAIP2, strings.Join(addresses, ", "))
+	return Token{}, clierr.New(clierr.CodeUsage, message).WithDetails(map[string]any{
+		"symbol":     input,
+		"chain_id":   chain.CAIP2,
+		"candidates": details,
+	})
+}
+
+func tokenInList(t Token, list string) bool {
+	for _, name := range t.Lists {
+		if strings.EqualFold(name, list) {
+			return true
+		}
+	}
+	return false
+}
+
+// tokenLists returns the lists t belongs to. Bootstrap registry tokens carry
+// none and belong to BuiltinTokenList.
+func tokenLists(t Token) []string {
+	if len(t.Lists) == 0 {
+		return []string{BuiltinTokenList}
+	}
+	return t.Lists
+}
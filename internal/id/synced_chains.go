@@ -0,0 +1,118 @@
+package id
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Synced chains come from the local chain list (`chains sync`) and extend the
+// built-in chains. Built-in IDs, slugs, and aliases always win.
+var (
+	syncedMu     sync.RWMutex
+	syncedBySlug = map[string]Chain{}
+	syncedByID   = map[int64]ChainEntry{}
+
+	// chainRefresher refreshes the synced chains when a chain name is not
+	// recognized. It reports whether the synced chains were replaced.
+	chainRefresher func() bool
+)
+
+// SetSyncedChains replaces the synced chain registry. Only EVM chains are
+// kept; entries whose chain ID is built in are dropped, and a slug or alias
+// already taken (built in or by a lower chain ID) is skipped.
+func SetSyncedChains(entries []ChainEntry) {
+	sorted := append([]ChainEntry(nil), entries...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Chain.EVMChainID < sorted[j].Chain.EVMChainID
+	})
+	bySlug := map[string]Chain{}
+	byID := map[int64]ChainEntry{}
+	for _, entry := range sorted {
+		chain := entry.Chain
+		if !chain.IsEVM() || chain.EVMChainID <= 0 {
+			continue
+		}
+		if _, ok := chainByID[chain.EVMChainID]; ok {
+			continue
+		}
+		if _, ok := byID[chain.EVMChainID]; ok {
+			continue
+		}
+		kept := ChainEntry{Chain: chain}
+		for i, name := range append([]string{chain.Slug}, entry.Aliases...) {
+			name = strings.ToLower(strings.TrimSpace(name))
+			if name == "" {
+				continue
+			}
+			if _, ok := chainBySlug[name]; ok {
+				continue
+			}
+			if _, ok := bySlug[name]; ok {
+				continue
+			}
+			bySlug[name] = chain
+			if i > 0 {
+				kept.Aliases = append(kept.Aliases, name)
+			}
+		}
+		byID[chain.EVMChainID] = kept
+	}
+	syncedMu.Lock()
+	syncedBySlug = bySlug
+	syncedByID = byID
+	syncedMu.Unlock()
+}
+
+// SetChainRefresher installs the hook ParseChain calls before rejecting an
+// unknown chain name. nil disables refreshing.
+func SetChainRefresher(refresh func() bool) {
+	syncedMu.Lock()
+	chainRefresher = refresh
+	syncedMu.Unlock()
+}
+
+// IsSyncedChain reports whether chainID is known only from the synced chain
+// list.
+func IsSyncedChain(chainID int64) bool {
+	syncedMu.RLock()
+	defer syncedMu.RUnlock()
+	_, ok := syncedByID[chainID]
+	return ok
+}
+
+// SyncedChains returns the synced chains sorted by CAIP-2 identifier.
+func SyncedChains() []ChainEntry {
+	syncedMu.RLock()
+	entries := make([]ChainEntry, 0, len(syncedByID))
+	for _, entry := range syncedByID {
+		entries = append(entries, entry)
+	}
+	syncedMu.RUnlock()
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Chain.CAIP2 < entries[j].Chain.CAIP2
+	})
+	return entries
+}
+
+func lookupSyncedSlug(name string) (Chain, bool) {
+	syncedMu.RLock()
+	defer syncedMu.RUnlock()
+	chain, ok := syncedBySlug[name]
+	return chain, ok
+}
+
+func lookupSyncedID(chainID int64) (Chain, bool) {
+	syncedMu.RLock()
+	defer syncedMu.RUnlock()
+	entry, ok := syncedByID[chainID]
+	return entry.Chain, ok
+}
+
+// refreshSyncedChains runs the chain refresher, if any, outside the lock.
+func refreshSyncedChains() bool {
+	syncedMu.RLock()
+	refresh := chainRefresher
+	syncedMu.RUnlock()
+	return refresh != nil && refresh()
+}
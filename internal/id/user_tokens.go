@@ -0,0 +1,85 @@
+package id
+
+import (
+	"sort"
+	"strings"
+)
+
+// userTokenRegistry holds tokens added by the user (token list file) on top
+// of the bootstrap registry, keyed by CAIP-2 chain ID.
+var userTokenRegistry = map[string][]Token{}
+
+// SetUserTokens replaces the user token registry. Tokens whose address is
+// already in the bootstrap registry for the chain are dropped so built-in
+// metadata always wins; duplicate addresses keep the first entry.
+func SetUserTokens(tokens map[string][]Token) {
+	next := make(map[string][]Token, len(tokens))
+	for chainID, list := range tokens {
+		seen := map[string]bool{}
+		for _, t := range tokenRegistry[chainID] {
+			seen[canonicalizeAddress(chainID, t.Address)] = true
+		}
+		for _, t := range list {
+			addr := canonicalizeAddress(chainID, t.Address)
+			if addr == "" || strings.TrimSpace(t.Symbol) == "" || seen[addr] {
+				continue
+			}
+			seen[addr] = true
+			t.Address = addr
+			next[chainID] = append(next[chainID], t)
+		}
+	}
+	userTokenRegistry = next
+}
+
+// IsBuiltinToken reports whether address is in the bootstrap registry for
+// chainID.
+func IsBuiltinToken(chainID, address string) bool {
+	target := canonicalizeAddress(chainID, address)
+	for _, t := range tokenRegistry[chainID] {
+		if canonicalizeAddress(chainID, t.Address) == target {
+			return true
+		}
+	}
+	return false
+}
+
+// IsUserToken reports whether address was added through the user token
+// registry for chainID.
+func IsUserToken(chainID, address string) bool {
+	target := canonicalizeAddress(chainID, address)
+	for _, t := range userTokenRegistry[chainID] {
+		if t.Address == target {
+			return true
+		}
+	}
+	return false
+}
+
+// TokenChainIDs returns the CAIP-2 IDs of every chain with registered
+// tokens, sorted.
+func TokenChainIDs() []string {
+	ids := make([]string, 0, len(tokenRegistry)+len(userTokenRegistry))
+	for chainID := range tokenRegistry {
+		ids = append(ids, chainID)
+	}
+	for chainID := range userTokenRegistry {
+		if _, ok := tokenRegistry[chainID]; !ok {
+			ids = append(ids, chainID)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// registryTokens returns the bootstrap tokens of chainID followed by the
+// user tokens.
+func registryTokens(chainID string) []Token {
+	user := userTokenRegistry[chainID]
+	if len(user) == 0 {
+		return tokenRegistry[chainID]
+	}
+	tokens := make([]Token, 0, len(tokenRegistry[chainID])+len(user))
+	tokens = append(tokens, tokenRegistry[chainID]...)
+	return append(tokens, user...)
+}
@@ -0,0 +1,207 @@
+// Package keyring stores small secrets in the operating system keyring by
+// calling the platform tool: security(1) on macOS and secret-tool(1)
+// (libsecret) on Linux. Other platforms report ErrUnsupported.
+package keyring
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNotFound is returned by Get when no secret is stored under the name.
+var ErrNotFound = errors.New("secret not found in keyring")
+
+// ErrUnsupported is returned on platforms without a supported keyring tool.
+var ErrUnsupported = errors.New("no supported OS keyring on this platform (needs macOS security or Linux secret-tool)")
+
+// Backend reads and writes keyring secrets.
+type Backend interface {
+	Get(service, account string) (string, error)
+	Set(service, account, secret string) error
+}
+
+var (
+	mu      sync.RWMutex
+	backend Backend = osBackend{goos: runtime.GOOS, run: run}
+)
+
+// SetBackend replaces the keyring backend and returns a function restoring
+// the previous one. Tests use it to avoid touching the real keyring.
+func SetBackend(b Backend) func() {
+	mu.Lock()
+	defer mu.Unlock()
+	previous := backend
+	backend = b
+	return func() {
+		mu.Lock()
+		defer mu.Unlock()
+		backend = previous
+	}
+}
+
+// Get returns the secret stored for service and account.
+func Get(service, account string) (string, error) {
+	mu.RLock()
+	defer mu.RUnlock()
+	return backend.Get(service, account)
+}
+
+// Set stores secret for service and account, replacing any existing one.
+func Set(service, account, secret string) error {
+	mu.RLock()
+	defer mu.RUnlock()
+	return backend.Set(service, account, secret)
+}
+
+// MemoryBackend keeps secrets in memory.
+type MemoryBackend struct {
+	mu      sync.Mutex
+	secrets map[string]string
+}
+
+func (m *MemoryBackend) Get(service, account string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	secret, ok := m.secrets[service+"\x00"+account]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return secret, nil
+}
+
+func (m *MemoryBackend) Set(service, account, secret string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.secrets == nil {
+		m.secrets = map[string]string{}
+	}
+	m.secrets[service+"\x00"+account] = secret
+	return nil
+}
+
+const toolTimeout = 30 * time.Second
+
+// Exit codes the keyring tools use when no item matches. security(1) exits
+// with errSecItemNotFound's code; secret-tool(1) exits 1 without output.
+// Other failures, such as a locked keychain, must not look like a missing
+// secret, or callers would create a new one.
+const (
+	securityNotFoundExit   = 44
+	secretToolNotFoundExit = 1
+)
+
+// runner runs a keyring tool with stdin and returns its stdout. Failed runs
+// return a *toolError.
+type runner func(args []string, stdin string) (string, error)
+
+type osBackend struct {
+	goos string
+	run  runner
+}
+
+func (b osBackend) Get(service, account string) (string, error) {
+	var args []string
+	switch b.goos {
+	case "darwin":
+		args = []string{"security", "find-generic-password", "-s", service, "-a", account, "-w"}
+	case "linux":
+		args = []string{"secret-tool", "lookup", "service", service, "account", account}
+	default:
+		return "", ErrUnsupported
+	}
+	out, err := b.run(args, "")
+	if err != nil {
+		if b.notFound(err) {
+			return "", ErrNotFound
+		}
+		return "", err
+	}
+	secret := strings.TrimSpace(out)
+	if secret == "" {
+		return "", ErrNotFound
+	}
+	return secret, nil
+}
+
+func (b osBackend) Set(service, account, secret string) error {
+	switch b.goos {
+	case "darwin":
+		// security -i reads commands from stdin, which keeps the secret out
+		// of the process arguments. -X takes the secret hex encoded, so it
+		// needs no quoting; -U updates an existing item.
+		command := fmt.Sprintf("add-generic-password -U -s %s -a %s -X %s\n", securityQuote(service), securityQuote(account), hex.EncodeToString([]byte(secret)))
+		_, err := b.run([]string{"security", "-i"}, command)
+		return err
+	case "linux":
+		_, err := b.run([]string{"secret-tool", "store", "--label", service + " " + account, "service", service, "account", account}, secret)
+		return err
+	default:
+		return ErrUnsupported
+	}
+}
+
+func (b osBackend) notFound(err error) bool {
+	var toolErr *toolError
+	if !errors.As(err, &toolErr) {
+		return false
+	}
+	switch b.goos {
+	case "darwin":
+		return toolErr.exitCode == securityNotFoundExit
+	case "linux":
+		return toolErr.exitCode == secretToolNotFoundExit && toolErr.stderr == ""
+	}
+	return false
+}
+
+// securityQuote quotes value for the security -i command parser.
+func securityQuote(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
+
+// toolError is a keyring tool that ran and failed.
+type toolError struct {
+	tool     string
+	exitCode int
+	stderr   string
+	err      error
+}
+
+func (e *toolError) Error() string {
+	if e.stderr != "" {
+		return fmt.Sprintf("%s: %v: %s", e.tool, e.err, e.stderr)
+	}
+	return fmt.Sprintf("%s: %v", e.tool, e.err)
+}
+
+func (e *toolError) Unwrap() error { return e.err }
+
+func run(args []string, stdin string) (string, error) {
+	if _, err := exec.LookPath(args[0]); err != nil {
+		return "", fmt.Errorf("%w: %s not found", ErrUnsupported, args[0])
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), toolTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Stdin = strings.NewReader(stdin)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		toolErr := &toolError{tool: args[0], exitCode: -1, stderr: strings.TrimSpace(stderr.String()), err: err}
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && ctx.Err() == nil {
+			toolErr.exitCode = exitErr.ExitCode()
+		}
+		return "", toolErr
+	}
+	return stdout.String(), nil
+}
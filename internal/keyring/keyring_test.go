@@ -0,0 +1,142 @@
+package keyring
+
+import (
+	"encoding/hex"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// fakeTool answers keyring tool runs with a canned result and records the
+// last invocation.
+type fakeTool struct {
+	out   string
+	err   error
+	args  []string
+	stdin string
+}
+
+func (f *fakeTool) run(args []string, stdin string) (string, error) {
+	f.args = slices.Clone(args)
+	f.stdin = stdin
+	return f.out, f.err
+}
+
+func TestOSBackendGet(t *testing.T) {
+	tests := []struct {
+		name     string
+		goos     string
+		out      string
+		err      error
+		want     string
+		notFound bool
+		wantErr  bool
+	}{
+		{name: "darwin found", goos: "darwin", out: "s3cret\n", want: "s3cret"},
+		{name: "darwin missing item", goos: "darwin", err: &toolError{tool: "security", exitCode: securityNotFoundExit, stderr: "security: SecKeychainSearchCopyNext: The specified item could not be found in the keychain."}, notFound: true},
+		{name: "darwin locked keychain", goos: "darwin", err: &toolError{tool: "security", exitCode: 36, stderr: "security: SecKeychainItemCopyContent: User interaction is not allowed."}, wantErr: true},
+		{name: "linux found", goos: "linux", out: "s3cret", want: "s3cret"},
+		{name: "linux missing item", goos: "linux", err: &toolError{tool: "secret-tool", exitCode: secretToolNotFoundExit}, notFound: true},
+		{name: "linux service unavailable", goos: "linux", err: &toolError{tool: "secret-tool", exitCode: 1, stderr: "Cannot autolaunch D-Bus without X11 $DISPLAY"}, wantErr: true},
+		{name: "linux timeout", goos: "linux", err: &toolError{tool: "secret-tool", exitCode: -1}, wantErr: true},
+		{name: "tool missing", goos: "linux", err: ErrUnsupported, wantErr: true},
+		{name: "empty secret", goos: "linux", out: "\n", notFound: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tool := &fakeTool{out: tc.out, err: tc.err}
+			got, err := osBackend{goos: tc.goos, run: tool.run}.Get("defi", "actions")
+			switch {
+			case tc.notFound:
+				if !errors.Is(err, ErrNotFound) {
+					t.Fatalf("expected ErrNotFound, got %q err=%v", got, err)
+				}
+			case tc.wantErr:
+				if err == nil || errors.Is(err, ErrNotFound) {
+					t.Fatalf("expected a keyring error other than ErrNotFound, got %v", err)
+				}
+			default:
+				if err != nil || got != tc.want {
+					t.Fatalf("expected %q, got %q err=%v", tc.want, got, err)
+				}
+			}
+		})
+	}
+}
+
+func TestOSBackendGetArgs(t *testing.T) {
+	tool := &fakeTool{out: "s3cret"}
+	if _, err := (osBackend{goos: "darwin", run: tool.run}).Get("defi", "actions"); err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if got := strings.Join(tool.args, " "); got != "security find-generic-password -s defi -a actions -w" {
+		t.Fatalf("unexpected darwin args %q", got)
+	}
+	if _, err := (osBackend{goos: "linux", run: tool.run}).Get("defi", "actions"); err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if got := strings.Join(tool.args, " "); got != "secret-tool lookup service defi account actions" {
+		t.Fatalf("unexpected linux args %q", got)
+	}
+}
+
+func TestOSBackendSetKeepsSecretOutOfArgs(t *testing.T) {
+	const secret = "s3cret with \"quotes\""
+
+	tool := &fakeTool{}
+	if err := (osBackend{goos: "darwin", run: tool.run}).Set("defi", `act"ions`, secret); err != nil {
+		t.Fatalf("darwin Set failed: %v", err)
+	}
+	if got := strings.Join(tool.args, " "); got != "security -i" {
+		t.Fatalf("unexpected darwin args %q", got)
+	}
+	want := `add-generic-password -U -s "defi" -a "act\"ions" -X ` + hex.EncodeToString([]byte(secret)) + "\n"
+	if tool.stdin != want {
+		t.Fatalf("unexpected darwin stdin %q, want %q", tool.stdin, want)
+	}
+
+	tool = &fakeTool{}
+	if err := (osBackend{goos: "linux", run: tool.run}).Set("defi", "actions", secret); err != nil {
+		t.Fatalf("linux Set failed: %v", err)
+	}
+	if tool.stdin != secret {
+		t.Fatalf("expected the secret on stdin, got %q", tool.stdin)
+	}
+	for _, arg := range tool.args {
+		if strings.Contains(arg, "s3cret") {
+			t.Fatalf("expected the secret to stay out of args, got %v", tool.args)
+		}
+	}
+
+	failing := &fakeTool{err: &toolError{tool: "security", exitCode: 51}}
+	if err := (osBackend{goos: "darwin", run: failing.run}).Set("defi", "actions", secret); err == nil {
+		t.Fatal("expected a failed tool run to fail Set")
+	}
+}
+
+func TestOSBackendUnsupported(t *testing.T) {
+	tool := &fakeTool{}
+	b := osBackend{goos: "windows", run: tool.run}
+	if _, err := b.Get("defi", "actions"); !errors.Is(err, ErrUnsupported) {
+		t.Fatalf("expected ErrUnsupported from Get, got %v", err)
+	}
+	if err := b.Set("defi", "actions", "s3cret"); !errors.Is(err, ErrUnsupported) {
+		t.Fatalf("expected ErrUnsupported from Set, got %v", err)
+	}
+	if tool.args != nil {
+		t.Fatalf("expected no tool run, got %v", tool.args)
+	}
+}
+
+func TestRunReportsExitCode(t *testing.T) {
+	_, err := run([]string{"sh", "-c", "echo locked >&2; exit 36"}, "")
+	var toolErr *toolError
+	if !errors.As(err, &toolErr) || toolErr.exitCode != 36 || toolErr.stderr != "locked" {
+		t.Fatalf("expected exit code and stderr in the tool error, got %#v", err)
+	}
+	out, err := run([]string{"sh", "-c", "cat"}, "from stdin")
+	if err != nil || out != "from stdin" {
+		t.Fatalf("expected stdin to reach the tool, got %q err=%v", out, err)
+	}
+}
@@ -0,0 +1,193 @@
+// Package labels maps contract addresses to human-readable labels. The
+// bundled directory comes from the contracts in internal/registry; users
+// extend or override it with a JSON file of their own labels (multisigs,
+// treasuries, counterparties).
+package labels
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ggonzalez94/defi-cli/internal/model"
+	"github.com/ggonzalez94/defi-cli/internal/registry"
+)
+
+// Label sources.
+const (
+	SourceBuiltin = "builtin"
+	SourceUser    = "user"
+)
+
+// Entry is one label of the user labels file. ChainID 0 applies the label on
+// every EVM chain.
+type Entry struct {
+	ChainID  int64  `json:"chainId"`
+	Address  string `json:"address"`
+	Label    string `json:"label"`
+	Category string `json:"category,omitempty"`
+	Protocol string `json:"protocol,omitempty"`
+}
+
+// File is the user labels document.
+type File struct {
+	Labels []Entry `json:"labels"`
+}
+
+var (
+	builtinOnce  sync.Once
+	builtinIndex map[string]model.AddressLabel
+	userIndex    = map[string]model.AddressLabel{}
+)
+
+// Load reads the user labels file at path. A missing file has no labels.
+func Load(path string) ([]Entry, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read labels file: %w", err)
+	}
+	var file File
+	if err := json.Unmarshal(buf, &file); err != nil {
+		return nil, fmt.Errorf("labels file %s: %w", path, err)
+	}
+	for i, entry := range file.Labels {
+		if !common.IsHexAddress(strings.TrimSpace(entry.Address)) {
+			return nil, fmt.Errorf("labels file %s: entry %d has invalid address %q", path, i, entry.Address)
+		}
+		if strings.TrimSpace(entry.Label) == "" {
+			return nil, fmt.Errorf("labels file %s: entry %d has no label", path, i)
+		}
+		if entry.ChainID < 0 {
+			return nil, fmt.Errorf("labels file %s: entry %d has invalid chainId %d", path, i, entry.ChainID)
+		}
+	}
+	return file.Labels, nil
+}
+
+// SetUser replaces the user labels. User labels take precedence over the
+// bundled directory; a later entry for the same chain and address wins.
+func SetUser(entries []Entry) {
+	next := make(map[string]model.AddressLabel, len(entries))
+	for _, entry := range entries {
+		label := newLabel(entry.ChainID, entry.Address, entry.Label, entry.Category, entry.Protocol, SourceUser)
+		next[indexKey(entry.ChainID, label.Address)] = label
+	}
+	userIndex = next
+}
+
+// Lookup returns the label of address on a CAIP-2 chain. Chain-specific
+// labels win over labels that apply to every chain, and user labels win over
+// bundled ones.
+func Lookup(chainID, address string) (model.AddressLabel, bool) {
+	evmID, ok := evmChainID(chainID)
+	if !ok || !common.IsHexAddress(strings.TrimSpace(address)) {
+		return model.AddressLabel{}, false
+	}
+	addr := common.HexToAddress(strings.TrimSpace(address)).Hex()
+	builtin := builtinLabels()
+	for _, key := range []string{indexKey(evmID, addr), indexKey(0, addr)} {
+		if label, ok := userIndex[key]; ok {
+			return label, true
+		}
+		if label, ok := builtin[key]; ok {
+			return label, true
+		}
+	}
+	return model.AddressLabel{}, false
+}
+
+// LookupAll returns every label of address across chains, sorted by chain.
+// A user label hides the bundled label for the same chain.
+func LookupAll(address string) []model.AddressLabel {
+	if !common.IsHexAddress(strings.TrimSpace(address)) {
+		return nil
+	}
+	addr := common.HexToAddress(strings.TrimSpace(address)).Hex()
+	byKey := map[string]model.AddressLabel{}
+	for key, label := range builtinLabels() {
+		if label.Address == addr {
+			byKey[key] = label
+		}
+	}
+	for key, label := range userIndex {
+		if label.Address == addr {
+			byKey[key] = label
+		}
+	}
+	out := make([]model.AddressLabel, 0, len(byKey))
+	for _, label := range byKey {
+		out = append(out, label)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		left, _ := evmChainID(out[i].ChainID)
+		right, _ := evmChainID(out[j].ChainID)
+		if left != right {
+			return left < right
+		}
+		return out[i].Source < out[j].Source
+	})
+	return out
+}
+
+// Label returns the label text of address on chainID, or "" when the
+// address is not labeled.
+func Label(chainID, address string) string {
+	label, _ := Lookup(chainID, address)
+	return label.Label
+}
+
+func builtinLabels() map[string]model.AddressLabel {
+	builtinOnce.Do(func() {
+		entries := registry.AddressLabels()
+		builtinIndex = make(map[string]model.AddressLabel, len(entries))
+		for _, entry := range entries {
+			label := newLabel(entry.ChainID, entry.Address, entry.Label, entry.Category, entry.Protocol, SourceBuiltin)
+			builtinIndex[indexKey(entry.ChainID, label.Address)] = label
+		}
+	})
+	return builtinIndex
+}
+
+func newLabel(chainID int64, address, label, category, protocol, source string) model.AddressLabel {
+	out := model.AddressLabel{
+		Address:  common.HexToAddress(strings.TrimSpace(address)).Hex(),
+		Label:    strings.TrimSpace(label),
+		Category: strings.ToLower(strings.TrimSpace(category)),
+		Protocol: strings.ToLower(strings.TrimSpace(protocol)),
+		Source:   source,
+	}
+	if chainID != 0 {
+		out.ChainID = "eip155:" + strconv.FormatInt(chainID, 10)
+	}
+	return out
+}
+
+func indexKey(chainID int64, address string) string {
+	return strconv.FormatInt(chainID, 10) + "|" + address
+}
+
+// evmChainID parses a CAIP-2 EVM chain; "" is the every-chain sentinel 0.
+func evmChainID(chainID string) (int64, bool) {
+	chainID = strings.TrimSpace(chainID)
+	if chainID == "" {
+		return 0, true
+	}
+	raw, ok := strings.CutPrefix(chainID, "eip155:")
+	if !ok {
+		return 0, false
+	}
+	value, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || value <= 0 {
+		return 0, false
+	}
+	return value, true
+}
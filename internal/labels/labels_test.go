@@ -0,0 +1,67 @@
+package labels
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ggonzalez94/defi-cli/internal/registry"
+)
+
+func TestLookupBundledLabels(t *testing.T) {
+	SetUser(nil)
+	label, ok := Lookup("eip155:8453", "0x16613524e02ad97edfef371bc883f2f5d6c480a5")
+	if !ok || label.Label != "Aerodrome Voter" || label.Protocol != "aerodrome" || label.ChainID != "eip155:8453" || label.Source != SourceBuiltin {
+		t.Fatalf("unexpected Aerodrome voter label %+v", label)
+	}
+	if _, ok := Lookup("eip155:1", "0x16613524e02ad97edfef371bc883f2f5d6c480a5"); ok {
+		t.Fatalf("expected chain-specific label to miss on another chain")
+	}
+	// Multicall3 is deployed at one address everywhere.
+	if got := Label("eip155:42161", registry.Multicall3Address); got != "Multicall3" {
+		t.Fatalf("expected every-chain Multicall3 label, got %q", got)
+	}
+	if _, ok := Lookup("solana", registry.Multicall3Address); ok {
+		t.Fatalf("expected non-EVM chains to have no labels")
+	}
+	all := LookupAll("0x1231DEB6f5749EF6cE6943a275A1D3E7486F4EaE")
+	if len(all) < 5 || all[0].ChainID != "eip155:1" || all[0].Protocol != "lifi" {
+		t.Fatalf("expected LI.FI diamond on many chains sorted by chain, got %+v", all)
+	}
+}
+
+func TestUserLabelsOverrideBundled(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "labels.json")
+	doc := `{"labels":[
+		{"chainId":8453,"address":"0x16613524e02ad97edfef371bc883f2f5d6c480a5","label":"Voter (mine)","category":"rewards"},
+		{"chainId":0,"address":"0x00000000000000000000000000000000000000aa","label":"Treasury Safe","category":"Multisig"}
+	]}`
+	if err := os.WriteFile(path, []byte(doc), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	SetUser(entries)
+	t.Cleanup(func() { SetUser(nil) })
+
+	if got, _ := Lookup("eip155:8453", "0x16613524e02ad97edfef371bc883f2f5d6c480a5"); got.Label != "Voter (mine)" || got.Source != SourceUser {
+		t.Fatalf("expected user label to win, got %+v", got)
+	}
+	safe, ok := Lookup("eip155:10", "0x00000000000000000000000000000000000000AA")
+	if !ok || safe.Category != "multisig" || safe.ChainID != "" {
+		t.Fatalf("expected every-chain user label, got %+v", safe)
+	}
+
+	missing, err := Load(filepath.Join(t.TempDir(), "missing.json"))
+	if err != nil || missing != nil {
+		t.Fatalf("expected a missing file to have no labels, got %+v err=%v", missing, err)
+	}
+	if err := os.WriteFile(path, []byte(`{"labels":[{"chainId":1,"address":"nope","label":"x"}]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Fatalf("expected invalid address to be rejected")
+	}
+}
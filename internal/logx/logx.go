@@ -0,0 +1,147 @@
+// Package logx is the CLI's diagnostic log. It records provider HTTP requests,
+// retries, cache decisions, and execution step transitions to stderr or a
+// file. stdout stays reserved for the envelope, and logging is off unless a
+// level is set.
+package logx
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"strings"
+	"sync/atomic"
+)
+
+// Levels accepted by --log-level. LevelOff disables logging.
+const (
+	LevelOff   = "off"
+	LevelError = "error"
+	LevelWarn  = "warn"
+	LevelInfo  = "info"
+	LevelDebug = "debug"
+)
+
+const redacted = "REDACTED"
+
+var (
+	discard = slog.New(slog.DiscardHandler)
+	current atomic.Pointer[slog.Logger]
+)
+
+// Levels returns the accepted --log-level values.
+func Levels() []string {
+	return []string{LevelOff, LevelError, LevelWarn, LevelInfo, LevelDebug}
+}
+
+// ParseLevel maps a --log-level value to a slog level. ok is false for "off"
+// and the empty string.
+func ParseLevel(raw string) (level slog.Level, ok bool, err error) {
+	switch strings.ToLower(strings.TrimSpace(raw)) {
+	case "", LevelOff:
+		return 0, false, nil
+	case LevelError:
+		return slog.LevelError, true, nil
+	case LevelWarn, "warning":
+		return slog.LevelWarn, true, nil
+	case LevelInfo:
+		return slog.LevelInfo, true, nil
+	case LevelDebug:
+		return slog.LevelDebug, true, nil
+	default:
+		return 0, false, fmt.Errorf("log level must be one of %s", strings.Join(Levels(), ", "))
+	}
+}
+
+// New returns a logger writing to w at level. Text is used for terminals and
+// JSON lines for log files.
+func New(w io.Writer, level slog.Level, jsonLines bool) *slog.Logger {
+	opts := &slog.HandlerOptions{Level: level, ReplaceAttr: redactAttr}
+	if jsonLines {
+		return slog.New(slog.NewJSONHandler(w, opts))
+	}
+	return slog.New(slog.NewTextHandler(w, opts))
+}
+
+// Set installs the process-wide logger. nil disables logging.
+func Set(logger *slog.Logger) {
+	current.Store(logger)
+}
+
+// L returns the process-wide logger, which discards everything until Set is
+// called.
+func L() *slog.Logger {
+	if logger := current.Load(); logger != nil {
+		return logger
+	}
+	return discard
+}
+
+// Enabled reports whether level is logged, so callers can skip building
+// expensive attributes.
+func Enabled(level slog.Level) bool {
+	return L().Enabled(context.Background(), level)
+}
+
+// SanitizeURL strips credentials from raw before it is logged: userinfo,
+// query values whose name suggests a secret, and path segments that look
+// like API keys (RPC providers often embed the key in the path).
+func SanitizeURL(raw string) string {
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return redacted
+	}
+	if parsed.User != nil {
+		parsed.User = url.User(redacted)
+	}
+	if parsed.RawQuery != "" {
+		query := parsed.Query()
+		for name := range query {
+			if isSecretName(name) {
+				query.Set(name, redacted)
+			}
+		}
+		parsed.RawQuery = query.Encode()
+	}
+	segments := strings.Split(parsed.Path, "/")
+	for i, segment := range segments {
+		if looksLikeKey(segment) {
+			segments[i] = redacted
+		}
+	}
+	parsed.Path = strings.Join(segments, "/")
+	parsed.RawPath = ""
+	return parsed.String()
+}
+
+func isSecretName(name string) bool {
+	name = strings.ToLower(name)
+	for _, marker := range []string{"key", "token", "secret", "auth", "password", "signature", "sig"} {
+		if strings.Contains(name, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// looksLikeKey matches long opaque path segments. 0x-prefixed hex is kept so
+// addresses and hashes stay readable.
+func looksLikeKey(segment string) bool {
+	if len(segment) < 24 || strings.HasPrefix(segment, "0x") {
+		return false
+	}
+	letters, digits := false, false
+	for _, r := range segment {
+		switch {
+		case r >= '0' && r <= '9':
+			digits = true
+		case (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z'):
+			letters = true
+		case r == '-' || r == '_':
+		default:
+			return false
+		}
+	}
+	return letters && digits
+}
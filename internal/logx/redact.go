@@ -0,0 +1,131 @@
+package logx
+
+import (
+	"log/slog"
+	"strings"
+	"sync"
+)
+
+// Secrets are redacted by value and by field name. Values are registered
+// when they are loaded (a local signer's private key, the action store
+// passphrase) and replaced wherever they appear in log attributes or
+// persisted payloads. Field names cover key material the CLI never loads
+// itself, such as a private key echoed in provider metadata.
+
+var (
+	secretsMu sync.RWMutex
+	secrets   []string
+)
+
+// minSecretLen keeps short values from redacting unrelated text.
+const minSecretLen = 16
+
+// RegisterSecret records a value to redact. Hex values are matched with and
+// without a 0x prefix, case-insensitively.
+func RegisterSecret(value string) {
+	value = strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(value), "0x"), "0X")
+	if len(value) < minSecretLen {
+		return
+	}
+	secretsMu.Lock()
+	defer secretsMu.Unlock()
+	for _, known := range secrets {
+		if known == value {
+			return
+		}
+	}
+	secrets = append(secrets, value)
+}
+
+// ResetSecrets forgets every registered value.
+func ResetSecrets() {
+	secretsMu.Lock()
+	defer secretsMu.Unlock()
+	secrets = nil
+}
+
+// RedactSecrets replaces registered secret values in s.
+func RedactSecrets(s string) string {
+	secretsMu.RLock()
+	defer secretsMu.RUnlock()
+	for _, secret := range secrets {
+		for _, variant := range []string{secret, strings.ToLower(secret), strings.ToUpper(secret)} {
+			if strings.Contains(s, variant) {
+				s = strings.ReplaceAll(s, variant, redacted)
+			}
+		}
+	}
+	return s
+}
+
+var privateKeyFields = []string{"privatekey", "secretkey", "mnemonic", "seedphrase", "passphrase", "password"}
+
+// IsPrivateKeyField reports whether a field or flag name holds key material,
+// ignoring case, dashes, and underscores ("private_key", "privateKey",
+// "--private-key").
+func IsPrivateKeyField(name string) bool {
+	normalized := strings.NewReplacer("-", "", "_", "", " ", "").Replace(strings.ToLower(name))
+	for _, field := range privateKeyFields {
+		if strings.Contains(normalized, field) {
+			return true
+		}
+	}
+	return false
+}
+
+// MayContainPrivateKeyField is a cheap pre-check for RedactFields on encoded
+// JSON: it reports whether any key-material field name appears in raw.
+func MayContainPrivateKeyField(raw []byte) bool {
+	normalized := strings.NewReplacer("-", "", "_", "").Replace(strings.ToLower(string(raw)))
+	for _, field := range privateKeyFields {
+		if strings.Contains(normalized, field) {
+			return true
+		}
+	}
+	return false
+}
+
+// RedactFields returns v with the values of key-material fields replaced, for
+// decoded JSON (maps and slices of any). Other values are returned as is.
+func RedactFields(v any) any {
+	switch typed := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(typed))
+		for key, value := range typed {
+			if IsPrivateKeyField(key) {
+				out[key] = redacted
+				continue
+			}
+			out[key] = RedactFields(value)
+		}
+		return out
+	case []any:
+		out := make([]any, len(typed))
+		for i, value := range typed {
+			out[i] = RedactFields(value)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// redactAttr is the ReplaceAttr hook of loggers built by New.
+func redactAttr(_ []string, attr slog.Attr) slog.Attr {
+	if IsPrivateKeyField(attr.Key) {
+		return slog.String(attr.Key, redacted)
+	}
+	switch attr.Value.Kind() {
+	case slog.KindString:
+		if value := attr.Value.String(); RedactSecrets(value) != value {
+			return slog.String(attr.Key, RedactSecrets(value))
+		}
+	case slog.KindAny:
+		if err, ok := attr.Value.Any().(error); ok {
+			if value := err.Error(); RedactSecrets(value) != value {
+				return slog.String(attr.Key, RedactSecrets(value))
+			}
+		}
+	}
+	return attr
+}
@@ -0,0 +1,407 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ggonzalez94/defi-cli/internal/schema"
+)
+
+// ProtocolVersion is the MCP revision advertised when the client does not
+// request one explicitly.
+const ProtocolVersion = "2025-06-18"
+
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+)
+
+// argsProperty is the tool parameter carrying positional arguments for
+// commands whose usage declares them ("templates run <name>").
+const argsProperty = "args"
+
+// reservedFlags are global flags the server controls itself; they are never
+// exposed as tool parameters.
+var reservedFlags = map[string]struct{}{
+	"json":            {},
+	"plain":           {},
+	"results-only":    {},
+	"config":          {},
+	"enable-commands": {},
+	"verbose":         {},
+	"log-level":       {},
+	"log-file":        {},
+	"help":            {},
+}
+
+// Executor runs one CLI invocation and returns its stdout and exit code.
+type Executor func(ctx context.Context, args []string) ([]byte, int)
+
+type Tool struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	InputSchema map[string]any  `json:"inputSchema"`
+	Annotations ToolAnnotations `json:"annotations"`
+
+	path       []string
+	flags      map[string]schema.FlagSchema
+	positional bool
+}
+
+type ToolAnnotations struct {
+	Title        string `json:"title,omitempty"`
+	ReadOnlyHint bool   `json:"readOnlyHint"`
+}
+
+type Server struct {
+	name    string
+	version string
+	tools   []Tool
+	byName  map[string]Tool
+	exec    Executor
+}
+
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type textContent struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type callResult struct {
+	Content []textContent `json:"content"`
+	IsError bool          `json:"isError"`
+}
+
+// NewServer builds a server exposing every runnable leaf command in doc as
+// a tool. allow filters command paths (nil allows all).
+func NewServer(name, version string, doc schema.CommandSchema, allow func(path string) bool, exec Executor) *Server {
+	s := &Server{name: name, version: version, byName: map[string]Tool{}, exec: exec}
+	rootPath := strings.TrimSpace(doc.Path)
+	var walk func(cmd schema.CommandSchema)
+	walk = func(cmd schema.CommandSchema) {
+		if len(cmd.Subcommands) > 0 {
+			for _, sub := range cmd.Subcommands {
+				walk(sub)
+			}
+			return
+		}
+		path := strings.TrimSpace(strings.TrimPrefix(cmd.Path, rootPath))
+		if path == "" || path == "mcp" || path == "tui" {
+			return
+		}
+		if allow != nil && !allow(path) {
+			return
+		}
+		tool := buildTool(path, cmd)
+		s.tools = append(s.tools, tool)
+		s.byName[tool.Name] = tool
+	}
+	walk(doc)
+	sort.Slice(s.tools, func(i, j int) bool { return s.tools[i].Name < s.tools[j].Name })
+	return s
+}
+
+// Tools returns the tool list in name order.
+func (s *Server) Tools() []Tool {
+	return s.tools
+}
+
+// ToolName maps a command path ("yield opportunities") to its tool name
+// ("yield_opportunities").
+func ToolName(path string) string {
+	return strings.Join(strings.Fields(path), "_")
+}
+
+func buildTool(path string, cmd schema.CommandSchema) Tool {
+	properties := map[string]any{}
+	required := []string{}
+	flags := map[string]schema.FlagSchema{}
+	for _, flag := range cmd.Flags {
+		if _, skip := reservedFlags[flag.Name]; skip {
+			continue
+		}
+		flags[flag.Name] = flag
+		properties[flag.Name] = flagProperty(flag)
+		if flag.Required {
+			required = append(required, flag.Name)
+		}
+	}
+	// Positional arguments are declared in the usage line after the
+	// command name; a flag named args keeps precedence.
+	positional := false
+	if usage := strings.Fields(cmd.Use); len(usage) > 1 {
+		if _, taken := flags[argsProperty]; !taken {
+			positional = true
+			properties[argsProperty] = map[string]any{
+				"description": "Positional arguments: " + strings.Join(usage[1:], " "),
+				"type":        "array",
+				"items":       map[string]any{"type": "string"},
+			}
+			if strings.Contains(cmd.Use, "<") {
+				required = append(required, argsProperty)
+			}
+		}
+	}
+	sort.Strings(required)
+	input := map[string]any{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if len(required) > 0 {
+		input["required"] = required
+	}
+	description := cmd.Short
+	if cmd.Mutation {
+		description = strings.TrimSpace(description + " (mutation)")
+	}
+	return Tool{
+		Name:        ToolName(path),
+		Description: description,
+		InputSchema: input,
+		Annotations: ToolAnnotations{Title: path, ReadOnlyHint: !cmd.Mutation},
+		path:        strings.Fields(path),
+		flags:       flags,
+		positional:  positional,
+	}
+}
+
+func flagProperty(flag schema.FlagSchema) map[string]any {
+	prop := map[string]any{"description": flag.Usage}
+	switch flag.Type {
+	case "bool":
+		prop["type"] = "boolean"
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64":
+		prop["type"] = "integer"
+	case "float32", "float64":
+		prop["type"] = "number"
+	case "stringSlice", "stringArray", "intSlice", "uintSlice":
+		prop["type"] = "array"
+		prop["items"] = map[string]any{"type": "string"}
+	default:
+		prop["type"] = "string"
+	}
+	if len(flag.Enum) > 0 {
+		prop["enum"] = flag.Enum
+	}
+	if flag.Format != "" {
+		prop["format"] = flag.Format
+	}
+	if flag.Default != nil {
+		prop["default"] = flag.Default
+	}
+	return prop
+}
+
+// Serve reads newline-delimited JSON-RPC messages from in and writes
+// responses to out until in is exhausted or ctx is cancelled.
+func (s *Server) Serve(ctx context.Context, in io.Reader, out io.Writer) error {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	encoder := json.NewEncoder(out)
+	for scanner.Scan() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		resp, ok := s.handle(ctx, []byte(line))
+		if !ok {
+			continue
+		}
+		if err := encoder.Encode(resp); err != nil {
+			return fmt.Errorf("write mcp response: %w", err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("read mcp request: %w", err)
+	}
+	return nil
+}
+
+// handle processes one message. The boolean is false for notifications,
+// which never receive a response.
+func (s *Server) handle(ctx context.Context, raw []byte) (response, bool) {
+	var req request
+	if err := json.Unmarshal(raw, &req); err != nil {
+		return errorResponse(json.RawMessage("null"), codeParseError, "parse error: "+err.Error()), true
+	}
+	isNotification := len(req.ID) == 0
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		if isNotification {
+			return response{}, false
+		}
+		return errorResponse(req.ID, codeInvalidRequest, "invalid request"), true
+	}
+
+	var (
+		result any
+		rpcErr *rpcError
+	)
+	switch req.Method {
+	case "initialize":
+		result = s.initialize(req.Params)
+	case "ping":
+		result = map[string]any{}
+	case "tools/list":
+		result = map[string]any{"tools": s.tools}
+	case "tools/call":
+		result, rpcErr = s.callTool(ctx, req.Params)
+	default:
+		if strings.HasPrefix(req.Method, "notifications/") {
+			return response{}, false
+		}
+		rpcErr = &rpcError{Code: codeMethodNotFound, Message: "method not found: " + req.Method}
+	}
+	if isNotification {
+		return response{}, false
+	}
+	if rpcErr != nil {
+		return errorResponse(req.ID, rpcErr.Code, rpcErr.Message), true
+	}
+	return response{JSONRPC: "2.0", ID: req.ID, Result: result}, true
+}
+
+func (s *Server) initialize(params json.RawMessage) map[string]any {
+	var req struct {
+		ProtocolVersion string `json:"protocolVersion"`
+	}
+	_ = json.Unmarshal(params, &req)
+	version := strings.TrimSpace(req.ProtocolVersion)
+	if version == "" {
+		version = ProtocolVersion
+	}
+	return map[string]any{
+		"protocolVersion": version,
+		"capabilities":    map[string]any{"tools": map[string]any{"listChanged": false}},
+		"serverInfo":      map[string]any{"name": s.name, "version": s.version},
+	}
+}
+
+func (s *Server) callTool(ctx context.Context, params json.RawMessage) (any, *rpcError) {
+	var req struct {
+		Name      string         `json:"name"`
+		Arguments map[string]any `json:"arguments"`
+	}
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, &rpcError{Code: codeInvalidParams, Message: "invalid tools/call params: " + err.Error()}
+	}
+	tool, ok := s.byName[req.Name]
+	if !ok {
+		return nil, &rpcError{Code: codeInvalidParams, Message: "unknown tool: " + req.Name}
+	}
+	args, err := tool.commandArgs(req.Arguments)
+	if err != nil {
+		return nil, &rpcError{Code: codeInvalidParams, Message: err.Error()}
+	}
+	stdout, exitCode := s.exec(ctx, args)
+	return callResult{
+		Content: []textContent{{Type: "text", Text: strings.TrimSpace(string(stdout))}},
+		IsError: exitCode != 0,
+	}, nil
+}
+
+// commandArgs converts tool arguments into CLI args for the tool's command.
+func (t Tool) commandArgs(arguments map[string]any) ([]string, error) {
+	args := append([]string{}, t.path...)
+	names := make([]string, 0, len(arguments))
+	for name := range arguments {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var positional []string
+	for _, name := range names {
+		if name == argsProperty && t.positional {
+			values, ok := arguments[name].([]any)
+			if !ok {
+				return nil, fmt.Errorf("argument %q must be an array of strings", name)
+			}
+			for _, value := range values {
+				text, ok := value.(string)
+				if !ok {
+					return nil, fmt.Errorf("argument %q must be an array of strings", name)
+				}
+				positional = append(positional, text)
+			}
+			continue
+		}
+		flag, ok := t.flags[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown argument %q for tool %s", name, t.Name)
+		}
+		value, err := flagValue(flag, arguments[name])
+		if err != nil {
+			return nil, fmt.Errorf("argument %q: %w", name, err)
+		}
+		args = append(args, "--"+name+"="+value)
+	}
+	// "--" keeps positional values that start with a dash from being
+	// parsed as flags.
+	if len(positional) > 0 {
+		args = append(args, "--")
+		args = append(args, positional...)
+	}
+	return args, nil
+}
+
+func flagValue(flag schema.FlagSchema, raw any) (string, error) {
+	switch v := raw.(type) {
+	case nil:
+		return "", fmt.Errorf("null is not allowed")
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case json.Number:
+		return v.String(), nil
+	case []any:
+		parts := make([]string, 0, len(v))
+		for _, item := range v {
+			part, err := flagValue(flag, item)
+			if err != nil {
+				return "", err
+			}
+			parts = append(parts, part)
+		}
+		return strings.Join(parts, ","), nil
+	default:
+		return "", fmt.Errorf("unsupported value type %T", raw)
+	}
+}
+
+func errorResponse(id json.RawMessage, code int, message string) response {
+	if len(id) == 0 {
+		id = json.RawMessage("null")
+	}
+	return response{JSONRPC: "2.0", ID: id, Error: &rpcError{Code: code, Message: message}}
+}
@@ -0,0 +1,195 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/ggonzalez94/defi-cli/internal/schema"
+)
+
+func testDoc() schema.CommandSchema {
+	return schema.CommandSchema{
+		Path: "defi",
+		Subcommands: []schema.CommandSchema{
+			{
+				Path: "defi yield",
+				Subcommands: []schema.CommandSchema{
+					{
+						Path:  "defi yield opportunities",
+						Short: "Rank yield opportunities",
+						Flags: []schema.FlagSchema{
+							{Name: "chain", Type: "string", Usage: "Chain identifier", Required: true},
+							{Name: "limit", Type: "int", Usage: "Maximum results", Default: 20},
+							{Name: "providers", Type: "stringSlice", Usage: "Providers"},
+							{Name: "results-only", Type: "bool", Usage: "Output only data payload", Scope: "inherited"},
+						},
+					},
+					{
+						Path:  "defi yield explain",
+						Use:   "explain <opportunity-id>",
+						Short: "Explain one opportunity",
+					},
+					{
+						Path:     "defi yield submit",
+						Short:    "Execute a yield action",
+						Mutation: true,
+						Flags: []schema.FlagSchema{
+							{Name: "action-id", Type: "string", Usage: "Action identifier", Required: true},
+						},
+					},
+				},
+			},
+			{Path: "defi mcp", Short: "Serve commands as MCP tools over stdio"},
+		},
+	}
+}
+
+func serve(t *testing.T, server *Server, lines ...string) []map[string]any {
+	t.Helper()
+	var out bytes.Buffer
+	if err := server.Serve(context.Background(), strings.NewReader(strings.Join(lines, "\n")+"\n"), &out); err != nil {
+		t.Fatalf("Serve failed: %v", err)
+	}
+	responses := []map[string]any{}
+	decoder := json.NewDecoder(&out)
+	for decoder.More() {
+		var resp map[string]any
+		if err := decoder.Decode(&resp); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		responses = append(responses, resp)
+	}
+	return responses
+}
+
+func TestServerListsLeafCommandsAsTools(t *testing.T) {
+	server := NewServer("defi", "0.0.0", testDoc(), nil, nil)
+	responses := serve(t, server,
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26"}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
+	)
+	if len(responses) != 2 {
+		t.Fatalf("expected 2 responses (notification gets none), got %d", len(responses))
+	}
+	initResult := responses[0]["result"].(map[string]any)
+	if initResult["protocolVersion"] != "2025-03-26" {
+		t.Fatalf("expected requested protocol version echoed, got %v", initResult["protocolVersion"])
+	}
+
+	tools := responses[1]["result"].(map[string]any)["tools"].([]any)
+	if len(tools) != 3 {
+		t.Fatalf("expected 3 tools (mcp excluded), got %d", len(tools))
+	}
+	explain := tools[0].(map[string]any)["inputSchema"].(map[string]any)
+	if explain["properties"].(map[string]any)["args"].(map[string]any)["type"] != "array" {
+		t.Fatalf("expected an args array for a command with positional arguments, got %+v", explain)
+	}
+	if required := explain["required"].([]any); len(required) != 1 || required[0] != "args" {
+		t.Fatalf("expected args to be required, got %+v", explain["required"])
+	}
+	opps := tools[1].(map[string]any)
+	if opps["name"] != "yield_opportunities" {
+		t.Fatalf("unexpected first tool name: %v", opps["name"])
+	}
+	input := opps["inputSchema"].(map[string]any)
+	props := input["properties"].(map[string]any)
+	if _, ok := props["args"]; ok {
+		t.Fatal("expected no args parameter for a command without positional arguments")
+	}
+	if _, ok := props["results-only"]; ok {
+		t.Fatal("expected reserved output flags to be hidden from tool parameters")
+	}
+	if props["limit"].(map[string]any)["type"] != "integer" {
+		t.Fatalf("expected integer limit, got %+v", props["limit"])
+	}
+	if props["providers"].(map[string]any)["type"] != "array" {
+		t.Fatalf("expected array providers, got %+v", props["providers"])
+	}
+	if required := input["required"].([]any); len(required) != 1 || required[0] != "chain" {
+		t.Fatalf("unexpected required list: %+v", required)
+	}
+	if opps["annotations"].(map[string]any)["readOnlyHint"] != true {
+		t.Fatal("expected read command to be marked read-only")
+	}
+	submit := tools[2].(map[string]any)
+	if submit["annotations"].(map[string]any)["readOnlyHint"] != false {
+		t.Fatal("expected mutation command to not be read-only")
+	}
+}
+
+func TestServerCallToolBuildsArgs(t *testing.T) {
+	var gotArgs []string
+	exec := func(_ context.Context, args []string) ([]byte, int) {
+		gotArgs = args
+		return []byte(`{"success":true}` + "\n"), 0
+	}
+	server := NewServer("defi", "0.0.0", testDoc(), nil, exec)
+	responses := serve(t, server,
+		`{"jsonrpc":"2.0","id":"a","method":"tools/call","params":{"name":"yield_opportunities","arguments":{"chain":"1","limit":5,"providers":["aave","morpho"]}}}`,
+	)
+	want := []string{"yield", "opportunities", "--chain=1", "--limit=5", "--providers=aave,morpho"}
+	if strings.Join(gotArgs, " ") != strings.Join(want, " ") {
+		t.Fatalf("unexpected args: %v", gotArgs)
+	}
+	result := responses[0]["result"].(map[string]any)
+	if result["isError"] != false {
+		t.Fatalf("expected success result, got %+v", result)
+	}
+	content := result["content"].([]any)[0].(map[string]any)
+	if content["text"] != `{"success":true}` {
+		t.Fatalf("unexpected content: %+v", content)
+	}
+
+	serve(t, server,
+		`{"jsonrpc":"2.0","id":"b","method":"tools/call","params":{"name":"yield_explain","arguments":{"args":["-opp"]}}}`,
+	)
+	if want := "yield explain -- -opp"; strings.Join(gotArgs, " ") != want {
+		t.Fatalf("expected positional args after --, got %v", gotArgs)
+	}
+	responses = serve(t, server,
+		`{"jsonrpc":"2.0","id":"c","method":"tools/call","params":{"name":"yield_explain","arguments":{"args":[1]}}}`,
+		`{"jsonrpc":"2.0","id":"d","method":"tools/call","params":{"name":"yield_opportunities","arguments":{"args":["x"]}}}`,
+	)
+	for i, resp := range responses {
+		if rpcErr, ok := resp["error"].(map[string]any); !ok || rpcErr["code"] != float64(codeInvalidParams) {
+			t.Fatalf("response %d: expected invalid params, got %+v", i, resp)
+		}
+	}
+}
+
+func TestServerCallToolErrors(t *testing.T) {
+	exec := func(_ context.Context, args []string) ([]byte, int) {
+		return []byte(`{"success":false}`), 2
+	}
+	allow := func(path string) bool { return path == "yield opportunities" }
+	server := NewServer("defi", "0.0.0", testDoc(), allow, exec)
+	responses := serve(t, server,
+		`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"yield_submit","arguments":{"action-id":"x"}}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"yield_opportunities","arguments":{"bogus":1}}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"yield_opportunities","arguments":{"chain":"1"}}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"resources/list"}`,
+		`not json`,
+	)
+	if len(responses) != 5 {
+		t.Fatalf("expected 5 responses, got %d", len(responses))
+	}
+	for i, code := range []float64{codeInvalidParams, codeInvalidParams} {
+		rpcErr, ok := responses[i]["error"].(map[string]any)
+		if !ok || rpcErr["code"] != code {
+			t.Fatalf("response %d: expected error code %v, got %+v", i, code, responses[i])
+		}
+	}
+	if responses[2]["result"].(map[string]any)["isError"] != true {
+		t.Fatalf("expected non-zero exit to be reported as tool error, got %+v", responses[2])
+	}
+	if responses[3]["error"].(map[string]any)["code"] != float64(codeMethodNotFound) {
+		t.Fatalf("expected method not found, got %+v", responses[3])
+	}
+	if responses[4]["error"].(map[string]any)["code"] != float64(codeParseError) {
+		t.Fatalf("expected parse error, got %+v", responses[4])
+	}
+}
@@ -0,0 +1,243 @@
+// Package metrics keeps in-process counters, histograms, and gauges for the
+// long-lived server modes and exposes them in the Prometheus text format or
+// pushes them to an OTLP/HTTP collector.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultBuckets are histogram bounds in seconds suited to provider calls and
+// CLI invocations.
+var DefaultBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+type kind int
+
+const (
+	kindCounter kind = iota
+	kindHistogram
+	kindGauge
+)
+
+// Registry holds every metric of one server process.
+type Registry struct {
+	mu      sync.Mutex
+	started time.Time
+	metrics []*metric
+}
+
+type metric struct {
+	kind    kind
+	name    string
+	help    string
+	labels  []string
+	buckets []float64
+	series  map[string]*series
+	gauge   func() float64
+}
+
+type series struct {
+	labels  []string
+	value   float64 // counter value, or histogram sum
+	count   uint64
+	buckets []uint64
+}
+
+// Counter is a monotonically increasing value per label set.
+type Counter struct {
+	r *Registry
+	m *metric
+}
+
+// Histogram counts observations into fixed buckets per label set.
+type Histogram struct {
+	r *Registry
+	m *metric
+}
+
+// NewRegistry returns an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{started: time.Now()}
+}
+
+// Counter registers a counter with the given label names.
+func (r *Registry) Counter(name, help string, labels ...string) *Counter {
+	return &Counter{r: r, m: r.register(&metric{kind: kindCounter, name: name, help: help, labels: labels})}
+}
+
+// Histogram registers a histogram with the given upper bounds and label names.
+func (r *Registry) Histogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	sorted := append([]float64(nil), buckets...)
+	sort.Float64s(sorted)
+	return &Histogram{r: r, m: r.register(&metric{kind: kindHistogram, name: name, help: help, labels: labels, buckets: sorted})}
+}
+
+// GaugeFunc registers an unlabelled gauge whose value is read at scrape time.
+func (r *Registry) GaugeFunc(name, help string, value func() float64) {
+	r.register(&metric{kind: kindGauge, name: name, help: help, gauge: value})
+}
+
+func (r *Registry) register(m *metric) *metric {
+	m.series = map[string]*series{}
+	r.mu.Lock()
+	r.metrics = append(r.metrics, m)
+	r.mu.Unlock()
+	return m
+}
+
+// Add increases the counter for labelValues by v.
+func (c *Counter) Add(v float64, labelValues ...string) {
+	c.r.mu.Lock()
+	c.m.get(labelValues).value += v
+	c.r.mu.Unlock()
+}
+
+// Inc increases the counter for labelValues by one.
+func (c *Counter) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Value returns the counter for labelValues.
+func (c *Counter) Value(labelValues ...string) float64 {
+	c.r.mu.Lock()
+	defer c.r.mu.Unlock()
+	if s, ok := c.m.series[seriesKey(labelValues)]; ok {
+		return s.value
+	}
+	return 0
+}
+
+// Observe records v for labelValues.
+func (h *Histogram) Observe(v float64, labelValues ...string) {
+	h.r.mu.Lock()
+	s := h.m.get(labelValues)
+	s.value += v
+	s.count++
+	for i, bound := range h.m.buckets {
+		if v <= bound {
+			s.buckets[i]++
+		}
+	}
+	h.r.mu.Unlock()
+}
+
+func (m *metric) get(labelValues []string) *series {
+	values := make([]string, len(m.labels))
+	copy(values, labelValues)
+	key := seriesKey(values)
+	s, ok := m.series[key]
+	if !ok {
+		s = &series{labels: values, buckets: make([]uint64, len(m.buckets))}
+		m.series[key] = s
+	}
+	return s
+}
+
+func seriesKey(values []string) string {
+	return strings.Join(values, "\xff")
+}
+
+// Handler serves the registry in the Prometheus text exposition format.
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		_ = r.WritePrometheus(w)
+	})
+}
+
+// WritePrometheus writes every metric in the Prometheus text format, series
+// sorted by label values.
+func (r *Registry) WritePrometheus(w io.Writer) error {
+	var b strings.Builder
+	for _, m := range r.snapshot() {
+		fmt.Fprintf(&b, "# HELP %s %s\n", m.name, m.help)
+		switch m.kind {
+		case kindCounter:
+			fmt.Fprintf(&b, "# TYPE %s counter\n", m.name)
+			for _, s := range m.sorted() {
+				fmt.Fprintf(&b, "%s%s %s\n", m.name, formatLabels(m.labels, s.labels, "", ""), formatFloat(s.value))
+			}
+		case kindHistogram:
+			fmt.Fprintf(&b, "# TYPE %s histogram\n", m.name)
+			for _, s := range m.sorted() {
+				for i, bound := range m.buckets {
+					fmt.Fprintf(&b, "%s_bucket%s %d\n", m.name, formatLabels(m.labels, s.labels, "le", formatFloat(bound)), s.buckets[i])
+				}
+				fmt.Fprintf(&b, "%s_bucket%s %d\n", m.name, formatLabels(m.labels, s.labels, "le", "+Inf"), s.count)
+				fmt.Fprintf(&b, "%s_sum%s %s\n", m.name, formatLabels(m.labels, s.labels, "", ""), formatFloat(s.value))
+				fmt.Fprintf(&b, "%s_count%s %d\n", m.name, formatLabels(m.labels, s.labels, "", ""), s.count)
+			}
+		case kindGauge:
+			fmt.Fprintf(&b, "# TYPE %s gauge\n", m.name)
+			fmt.Fprintf(&b, "%s %s\n", m.name, formatFloat(m.gauge()))
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// snapshot copies the registry so it can be rendered without holding the
+// lock while gauge callbacks run.
+func (r *Registry) snapshot() []*metric {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]*metric, 0, len(r.metrics))
+	for _, m := range r.metrics {
+		cp := *m
+		cp.series = make(map[string]*series, len(m.series))
+		for k, s := range m.series {
+			sc := *s
+			sc.buckets = append([]uint64(nil), s.buckets...)
+			cp.series[k] = &sc
+		}
+		out = append(out, &cp)
+	}
+	return out
+}
+
+func (m *metric) sorted() []*series {
+	keys := make([]string, 0, len(m.series))
+	for k := range m.series {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	out := make([]*series, 0, len(keys))
+	for _, k := range keys {
+		out = append(out, m.series[k])
+	}
+	return out
+}
+
+func formatLabels(names, values []string, extraName, extraValue string) string {
+	parts := make([]string, 0, len(names)+1)
+	for i, name := range names {
+		parts = append(parts, name+"="+strconv.Quote(values[i]))
+	}
+	if extraName != "" {
+		parts = append(parts, extraName+"="+strconv.Quote(extraValue))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
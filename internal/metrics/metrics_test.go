@@ -0,0 +1,88 @@
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWritePrometheus(t *testing.T) {
+	r := NewRegistry()
+	calls := r.Counter("test_calls_total", "Calls.", "provider")
+	latency := r.Histogram("test_latency_seconds", "Latency.", []float64{1, 0.1}, "provider")
+	r.GaugeFunc("test_ratio", "Ratio.", func() float64 { return 0.25 })
+	calls.Inc("aave")
+	calls.Add(2, `we"ird`)
+	latency.Observe(0.05, "aave")
+	latency.Observe(0.5, "aave")
+	latency.Observe(3, "aave")
+
+	srv := httptest.NewServer(r.Handler())
+	defer srv.Close()
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("scrape: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/plain; version=0.0.4") {
+		t.Fatalf("unexpected content type %q", resp.Header.Get("Content-Type"))
+	}
+	want := `# HELP test_calls_total Calls.
+# TYPE test_calls_total counter
+test_calls_total{provider="aave"} 1
+test_calls_total{provider="we\"ird"} 2
+# HELP test_latency_seconds Latency.
+# TYPE test_latency_seconds histogram
+test_latency_seconds_bucket{provider="aave",le="0.1"} 1
+test_latency_seconds_bucket{provider="aave",le="1"} 2
+test_latency_seconds_bucket{provider="aave",le="+Inf"} 3
+test_latency_seconds_sum{provider="aave"} 3.55
+test_latency_seconds_count{provider="aave"} 3
+# HELP test_ratio Ratio.
+# TYPE test_ratio gauge
+test_ratio 0.25
+`
+	if string(body) != want {
+		t.Fatalf("unexpected exposition:\n%s", body)
+	}
+}
+
+func TestPushOTLPSendsPerBucketCounts(t *testing.T) {
+	r := NewRegistry()
+	r.Counter("test_idle_total", "Never incremented.")
+	latency := r.Histogram("test_latency_seconds", "Latency.", []float64{0.1, 1})
+	latency.Observe(0.05)
+	latency.Observe(0.5)
+	latency.Observe(3)
+
+	var got otlpRequest
+	var auth string
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		auth = req.Header.Get("Authorization")
+		_ = json.NewDecoder(req.Body).Decode(&got)
+	}))
+	defer collector.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := r.PushOTLP(ctx, collector.Client(), collector.URL, map[string]string{"Authorization": "Bearer abc"}); err != nil {
+		t.Fatalf("push: %v", err)
+	}
+	if auth != "Bearer abc" {
+		t.Fatalf("expected headers forwarded, got %q", auth)
+	}
+	metrics := got.ResourceMetrics[0].ScopeMetrics[0].Metrics
+	if len(metrics) != 1 || metrics[0].Histogram == nil {
+		t.Fatalf("expected only the observed histogram, got %+v", metrics)
+	}
+	point := metrics[0].Histogram.DataPoints[0]
+	if point.Count != "3" || strings.Join(point.BucketCounts, ",") != "1,1,1" {
+		t.Fatalf("unexpected histogram point %+v", point)
+	}
+}
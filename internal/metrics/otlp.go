@@ -0,0 +1,191 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ggonzalez94/defi-cli/internal/version"
+)
+
+// otlpCumulative is AGGREGATION_TEMPORALITY_CUMULATIVE.
+const otlpCumulative = 2
+
+type otlpRequest struct {
+	ResourceMetrics []otlpResourceMetrics `json:"resourceMetrics"`
+}
+
+type otlpResourceMetrics struct {
+	Resource     otlpResource       `json:"resource"`
+	ScopeMetrics []otlpScopeMetrics `json:"scopeMetrics"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes"`
+}
+
+type otlpScopeMetrics struct {
+	Scope   otlpScope    `json:"scope"`
+	Metrics []otlpMetric `json:"metrics"`
+}
+
+type otlpScope struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+type otlpMetric struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	Sum         *otlpSum       `json:"sum,omitempty"`
+	Histogram   *otlpHistogram `json:"histogram,omitempty"`
+	Gauge       *otlpGauge     `json:"gauge,omitempty"`
+}
+
+type otlpSum struct {
+	DataPoints             []otlpNumberPoint `json:"dataPoints"`
+	AggregationTemporality int               `json:"aggregationTemporality"`
+	IsMonotonic            bool              `json:"isMonotonic"`
+}
+
+type otlpGauge struct {
+	DataPoints []otlpNumberPoint `json:"dataPoints"`
+}
+
+type otlpHistogram struct {
+	DataPoints             []otlpHistogramPoint `json:"dataPoints"`
+	AggregationTemporality int                  `json:"aggregationTemporality"`
+}
+
+type otlpNumberPoint struct {
+	Attributes        []otlpAttribute `json:"attributes,omitempty"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano,omitempty"`
+	TimeUnixNano      string          `json:"timeUnixNano"`
+	AsDouble          float64         `json:"asDouble"`
+}
+
+type otlpHistogramPoint struct {
+	Attributes        []otlpAttribute `json:"attributes,omitempty"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	TimeUnixNano      string          `json:"timeUnixNano"`
+	Count             string          `json:"count"`
+	Sum               float64         `json:"sum"`
+	BucketCounts      []string        `json:"bucketCounts"`
+	ExplicitBounds    []float64       `json:"explicitBounds"`
+}
+
+type otlpAttribute struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+// PushOTLP posts the current values to an OTLP/HTTP metrics endpoint as JSON.
+// Counters and histograms are sent cumulatively from registry creation.
+func (r *Registry) PushOTLP(ctx context.Context, client *http.Client, endpoint string, headers map[string]string) error {
+	body, err := json.Marshal(r.otlpPayload(time.Now()))
+	if err != nil {
+		return fmt.Errorf("encode otlp metrics: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build otlp request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("export metrics: %w", err)
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("export metrics: collector returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (r *Registry) otlpPayload(now time.Time) otlpRequest {
+	start, ts := unixNano(r.started), unixNano(now)
+	var out []otlpMetric
+	for _, m := range r.snapshot() {
+		metric := otlpMetric{Name: m.name, Description: m.help}
+		switch m.kind {
+		case kindCounter:
+			sum := &otlpSum{AggregationTemporality: otlpCumulative, IsMonotonic: true}
+			for _, s := range m.sorted() {
+				sum.DataPoints = append(sum.DataPoints, otlpNumberPoint{
+					Attributes:        attributes(m.labels, s.labels),
+					StartTimeUnixNano: start,
+					TimeUnixNano:      ts,
+					AsDouble:          s.value,
+				})
+			}
+			if len(sum.DataPoints) == 0 {
+				continue
+			}
+			metric.Sum = sum
+		case kindHistogram:
+			hist := &otlpHistogram{AggregationTemporality: otlpCumulative}
+			for _, s := range m.sorted() {
+				// OTLP bucket counts are per bucket, not cumulative like
+				// Prometheus, with a trailing overflow bucket.
+				counts := make([]string, 0, len(s.buckets)+1)
+				var below uint64
+				for _, c := range s.buckets {
+					counts = append(counts, strconv.FormatUint(c-below, 10))
+					below = c
+				}
+				counts = append(counts, strconv.FormatUint(s.count-below, 10))
+				hist.DataPoints = append(hist.DataPoints, otlpHistogramPoint{
+					Attributes:        attributes(m.labels, s.labels),
+					StartTimeUnixNano: start,
+					TimeUnixNano:      ts,
+					Count:             strconv.FormatUint(s.count, 10),
+					Sum:               s.value,
+					BucketCounts:      counts,
+					ExplicitBounds:    m.buckets,
+				})
+			}
+			if len(hist.DataPoints) == 0 {
+				continue
+			}
+			metric.Histogram = hist
+		case kindGauge:
+			metric.Gauge = &otlpGauge{DataPoints: []otlpNumberPoint{{TimeUnixNano: ts, AsDouble: m.gauge()}}}
+		}
+		out = append(out, metric)
+	}
+	return otlpRequest{ResourceMetrics: []otlpResourceMetrics{{
+		Resource: otlpResource{Attributes: []otlpAttribute{
+			{Key: "service.name", Value: otlpValue{StringValue: version.CLIName}},
+			{Key: "service.version", Value: otlpValue{StringValue: version.CLIVersion}},
+		}},
+		ScopeMetrics: []otlpScopeMetrics{{
+			Scope:   otlpScope{Name: "github.com/ggonzalez94/defi-cli", Version: version.CLIVersion},
+			Metrics: out,
+		}},
+	}}}
+}
+
+func attributes(names, values []string) []otlpAttribute {
+	out := make([]otlpAttribute, 0, len(names))
+	for i, name := range names {
+		out = append(out, otlpAttribute{Key: name, Value: otlpValue{StringValue: values[i]}})
+	}
+	return out
+}
+
+func unixNano(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
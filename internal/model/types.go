@@ -9,6 +9,7 @@ const (
 	NativeIDKindMarketID             = "market_id"
 	NativeIDKindVaultAddress         = "vault_address"
 	NativeIDKindPoolID               = "pool_id"
+	NativeIDKindObligationAddress    = "obligation_address"
 )
 
 type Envelope struct {
@@ -24,6 +25,7 @@ type ErrorBody struct {
 	Code    int    `json:"code"`
 	Type    string `json:"type"`
 	Message string `json:"message"`
+	Details any    `json:"details,omitempty"`
 }
 
 type EnvelopeMeta struct {
@@ -33,6 +35,78 @@ type EnvelopeMeta struct {
 	Providers []ProviderStatus `json:"providers,omitempty"`
 	Cache     CacheStatus      `json:"cache"`
 	Partial   bool             `json:"partial"`
+
+	// ResolvedNames lists ENS/SNS names substituted into address flags.
+	ResolvedNames []ResolvedName `json:"resolved_names,omitempty"`
+
+	// NextCursor continues a paged listing (--cursor); it is absent on the
+	// last page.
+	NextCursor string `json:"next_cursor,omitempty"`
+
+	// Trace is the request trace, present only with --trace.
+	Trace *Trace `json:"trace,omitempty"`
+}
+
+// Trace breaks one invocation down into provider HTTP requests and cache
+// operations. IDs follow W3C Trace Context, so the trace joins the caller's
+// when TRACEPARENT is set.
+type Trace struct {
+	TraceID      string         `json:"trace_id"`
+	SpanID       string         `json:"span_id"`
+	ParentSpanID string         `json:"parent_span_id,omitempty"`
+	StartedAt    time.Time      `json:"started_at"`
+	DurationMS   float64        `json:"duration_ms"`
+	Requests     []TraceRequest `json:"requests,omitempty"`
+	Cache        []TraceCache   `json:"cache,omitempty"`
+	Exported     bool           `json:"exported,omitempty"`
+}
+
+// TraceRequest is one HTTP attempt. Phase timings are zero when the phase
+// did not happen, e.g. DNS and TLS on a reused connection.
+type TraceRequest struct {
+	SpanID     string    `json:"span_id"`
+	Method     string    `json:"method"`
+	URL        string    `json:"url"`
+	Host       string    `json:"host"`
+	Attempt    int       `json:"attempt"`
+	Status     int       `json:"status,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	Bytes      int       `json:"bytes,omitempty"`
+	ReusedConn bool      `json:"reused_conn"`
+	StartedAt  time.Time `json:"started_at"`
+	DNSMS      float64   `json:"dns_ms"`
+	ConnectMS  float64   `json:"connect_ms"`
+	TLSMS      float64   `json:"tls_ms"`
+	TTFBMS     float64   `json:"ttfb_ms"`
+	TotalMS    float64   `json:"total_ms"`
+}
+
+// TraceCache is one cache read or write.
+type TraceCache struct {
+	Op         string  `json:"op"`
+	Status     string  `json:"status"`
+	DurationMS float64 `json:"duration_ms"`
+}
+
+// ResolvedName maps a human-readable account name to the address it resolved
+// to. Flag is set when the name was given to an address flag.
+type ResolvedName struct {
+	Name    string `json:"name"`
+	Address string `json:"address"`
+	Service string `json:"service"`
+	Flag    string `json:"flag,omitempty"`
+}
+
+// AddressLabel names a known address. An empty ChainID means the label
+// applies on every EVM chain. Source is "builtin" for the bundled directory
+// and "user" for the user labels file.
+type AddressLabel struct {
+	ChainID  string `json:"chain_id,omitempty"`
+	Address  string `json:"address"`
+	Label    string `json:"label"`
+	Category string `json:"category,omitempty"`
+	Protocol string `json:"protocol,omitempty"`
+	Source   string `json:"source"`
 }
 
 type ProviderStatus struct {
@@ -45,6 +119,8 @@ type CacheStatus struct {
 	Status string `json:"status"`
 	AgeMS  int64  `json:"age_ms"`
 	Stale  bool   `json:"stale"`
+	// TTLMS is the effective TTL of the command, after --cache-ttl.
+	TTLMS int64 `json:"ttl_ms,omitempty"`
 }
 
 type ProviderInfo struct {
@@ -54,6 +130,17 @@ type ProviderInfo struct {
 	Capabilities   []string                 `json:"capabilities"`
 	KeyEnvVarName  string                   `json:"key_env_var,omitempty"`
 	CapabilityAuth []ProviderCapabilityAuth `json:"capability_auth,omitempty"`
+	// Support narrows capabilities to chains and features. Capabilities
+	// without an entry are not restricted by chain.
+	Support []CapabilitySupport `json:"support,omitempty"`
+}
+
+// CapabilitySupport lists the chains (CAIP-2, "eip155:*" for every EVM
+// chain) and features, such as exact-output, of one provider capability.
+type CapabilitySupport struct {
+	Capability string   `json:"capability"`
+	Chains     []string `json:"chains"`
+	Features   []string `json:"features,omitempty"`
 }
 
 type ProviderCapabilityAuth struct {
@@ -62,6 +149,44 @@ type ProviderCapabilityAuth struct {
 	Description string `json:"description,omitempty"`
 }
 
+// ProviderHealth is the circuit breaker state of one provider capability, as
+// listed by `providers health`.
+type ProviderHealth struct {
+	Provider   string `json:"provider"`
+	Capability string `json:"capability"`
+	State      string `json:"state"`
+	Failures   int    `json:"failures"`
+	LastError  string `json:"last_error,omitempty"`
+	CheckedAt  string `json:"checked_at"`
+	OpenUntil  string `json:"open_until,omitempty"`
+	// RateLimit is the provider-wide budget from its rate-limit headers,
+	// repeated on each of its capabilities.
+	RateLimit *RateLimitBudget `json:"rate_limit,omitempty"`
+}
+
+// RateLimitBudget is the last rate-limit state a provider advertised. State
+// is ok, low (requests are paced until the reset), or exhausted (requests
+// fail fast until the reset).
+type RateLimitBudget struct {
+	State      string `json:"state"`
+	Limit      int    `json:"limit,omitempty"`
+	Remaining  int    `json:"remaining"`
+	ResetAt    string `json:"reset_at,omitempty"`
+	ObservedAt string `json:"observed_at"`
+}
+
+// ProviderKeyCheck is the result of one providers verify call. Status is
+// valid, invalid, rate_limited, missing (no key configured), or unverified
+// (the check itself failed, for example offline or unreachable).
+type ProviderKeyCheck struct {
+	Provider  string `json:"provider"`
+	KeyEnvVar string `json:"key_env_var"`
+	Status    string `json:"status"`
+	Message   string `json:"message,omitempty"`
+	LatencyMS int64  `json:"latency_ms"`
+	CheckedAt string `json:"checked_at"`
+}
+
 type SupportedChain struct {
 	Name       string   `json:"name"`
 	Slug       string   `json:"slug"`
@@ -69,6 +194,19 @@ type SupportedChain struct {
 	Namespace  string   `json:"namespace"`
 	EVMChainID int64    `json:"evm_chain_id,omitempty"`
 	Aliases    []string `json:"aliases,omitempty"`
+	// Synced marks chains learned from the synced chain list.
+	Synced bool `json:"synced,omitempty"`
+}
+
+// ChainSyncResult summarizes a `chains sync` run.
+type ChainSyncResult struct {
+	Source   string `json:"source"`
+	Path     string `json:"path"`
+	SyncedAt string `json:"synced_at"`
+	Chains   int    `json:"chains"`
+	// Added counts chains that are not built in and now resolve by name.
+	Added   int `json:"added"`
+	WithRPC int `json:"with_rpc"`
 }
 
 type GasPrice struct {
@@ -84,10 +222,13 @@ type GasPrice struct {
 }
 
 type ChainTVL struct {
-	Rank    int     `json:"rank"`
-	Chain   string  `json:"chain"`
-	ChainID string  `json:"chain_id"`
-	TVLUSD  float64 `json:"tvl_usd"`
+	Rank            int      `json:"rank"`
+	Chain           string   `json:"chain"`
+	ChainID         string   `json:"chain_id"`
+	TVLUSD          float64  `json:"tvl_usd"`
+	TVLChangePct    *float64 `json:"tvl_change_pct,omitempty"`
+	TVLChangeWindow string   `json:"tvl_change_window,omitempty"`
+	DropAlert       bool     `json:"drop_alert,omitempty"`
 }
 
 type ChainAssetTVL struct {
@@ -100,11 +241,64 @@ type ChainAssetTVL struct {
 }
 
 type ProtocolTVL struct {
-	Rank     int     `json:"rank"`
-	Protocol string  `json:"protocol"`
-	Category string  `json:"category"`
-	TVLUSD   float64 `json:"tvl_usd"`
-	Chains   int     `json:"chains"`
+	Rank            int      `json:"rank"`
+	Protocol        string   `json:"protocol"`
+	Category        string   `json:"category"`
+	TVLUSD          float64  `json:"tvl_usd"`
+	Chains          int      `json:"chains"`
+	TVLChangePct    *float64 `json:"tvl_change_pct,omitempty"`
+	TVLChangeWindow string   `json:"tvl_change_window,omitempty"`
+	DropAlert       bool     `json:"drop_alert,omitempty"`
+}
+
+// ProtocolSecurity is audit and incident metadata for a protocol, merged
+// across the DefiLlama listings it covers (e.g. Aave V2 and Aave V3).
+type ProtocolSecurity struct {
+	Protocol       string             `json:"protocol"`
+	Listings       []string           `json:"listings"`
+	Category       string             `json:"category,omitempty"`
+	TVLUSD         float64            `json:"tvl_usd"`
+	Audits         int                `json:"audits"`
+	AuditLinks     []string           `json:"audit_links"`
+	ListedAt       string             `json:"listed_at,omitempty"`
+	ListingAgeDays int                `json:"listing_age_days"`
+	Exploits       []ProtocolIncident `json:"exploits"`
+	Provider       string             `json:"provider"`
+	SourceURL      string             `json:"source_url,omitempty"`
+	FetchedAt      string             `json:"fetched_at"`
+}
+
+// ProtocolIncident is a dated event from a protocol's DefiLlama hallmarks.
+type ProtocolIncident struct {
+	Date        string `json:"date"`
+	Description string `json:"description"`
+}
+
+// GovernanceProposal is a Snapshot or on-chain governor proposal. Votes and
+// quorum are in the voting token's units; quorum_progress_pct is the counted
+// votes (all votes on Snapshot, votes for on-chain) as a percent of quorum.
+type GovernanceProposal struct {
+	Protocol          string             `json:"protocol"`
+	Source            string             `json:"source"`
+	ProposalID        string             `json:"proposal_id"`
+	Title             string             `json:"title"`
+	Status            string             `json:"status"`
+	ChainID           string             `json:"chain_id,omitempty"`
+	Space             string             `json:"space,omitempty"`
+	StartsAt          string             `json:"starts_at,omitempty"`
+	EndsAt            string             `json:"ends_at,omitempty"`
+	Quorum            float64            `json:"quorum"`
+	VotesTotal        float64            `json:"votes_total"`
+	QuorumProgressPct *float64           `json:"quorum_progress_pct,omitempty"`
+	Choices           []GovernanceChoice `json:"choices"`
+	URL               string             `json:"url"`
+	Provider          string             `json:"provider"`
+	FetchedAt         string             `json:"fetched_at"`
+}
+
+type GovernanceChoice struct {
+	Choice string  `json:"choice"`
+	Votes  float64 `json:"votes"`
 }
 
 type ProtocolCategory struct {
@@ -163,6 +357,37 @@ type Stablecoin struct {
 	DayChangeUSD   float64 `json:"day_change_usd"`
 	WeekChangeUSD  float64 `json:"week_change_usd"`
 	MonthChangeUSD float64 `json:"month_change_usd"`
+	// PegDeviationPct is (price - 1) * 100 for USD-pegged coins; omitted for
+	// other peg types and when no price is reported.
+	PegDeviationPct *float64 `json:"peg_deviation_pct,omitempty"`
+	WeekChangePct   float64  `json:"week_change_pct"`
+	MonthChangePct  float64  `json:"month_change_pct"`
+}
+
+type StablecoinDetails struct {
+	ID              string                   `json:"id"`
+	Name            string                   `json:"name"`
+	Symbol          string                   `json:"symbol"`
+	PegType         string                   `json:"peg_type"`
+	PegMechanism    string                   `json:"peg_mechanism"`
+	Price           float64                  `json:"price"`
+	PegDeviationPct *float64                 `json:"peg_deviation_pct,omitempty"`
+	CirculatingUSD  float64                  `json:"circulating_usd"`
+	DayChangeUSD    float64                  `json:"day_change_usd"`
+	WeekChangeUSD   float64                  `json:"week_change_usd"`
+	WeekChangePct   float64                  `json:"week_change_pct"`
+	MonthChangeUSD  float64                  `json:"month_change_usd"`
+	MonthChangePct  float64                  `json:"month_change_pct"`
+	Chains          []StablecoinChainBalance `json:"chains"`
+}
+
+type StablecoinChainBalance struct {
+	Chain          string  `json:"chain"`
+	ChainID        string  `json:"chain_id,omitempty"`
+	CirculatingUSD float64 `json:"circulating_usd"`
+	SharePct       float64 `json:"share_pct"`
+	WeekChangeUSD  float64 `json:"week_change_usd"`
+	MonthChangeUSD float64 `json:"month_change_usd"`
 }
 
 type StablecoinChain struct {
@@ -173,6 +398,32 @@ type StablecoinChain struct {
 	DominantPegType   string  `json:"dominant_peg_type"`
 }
 
+// DepegReport is the peg health of a USD stablecoin. DeviationPct is the
+// current price's deviation from $1 in percentage points, negative below the
+// peg, and MaxDeviationPct the largest deviation over the window. DepthDownUSD
+// is how much of the stablecoin can be sold into DEX pools against
+// ReferenceAssetID before its price falls DepthBps, and DepthUpUSD how much
+// can be bought before it rises DepthBps. Status is pegged, watch (the
+// window crossed ThresholdBps but the price is back), or depegged.
+type DepegReport struct {
+	Symbol           string  `json:"symbol"`
+	ChainID          string  `json:"chain_id"`
+	AssetID          string  `json:"asset_id"`
+	PriceUSD         float64 `json:"price_usd"`
+	DeviationPct     float64 `json:"deviation_pct" unit:"percent"`
+	WindowDays       int     `json:"window_days"`
+	MaxDeviationPct  float64 `json:"max_deviation_pct" unit:"percent"`
+	MaxDeviationAt   string  `json:"max_deviation_at,omitempty"`
+	ReferenceAssetID string  `json:"reference_asset_id"`
+	DepthBps         int     `json:"depth_bps"`
+	DepthDownUSD     float64 `json:"depth_down_usd"`
+	DepthUpUSD       float64 `json:"depth_up_usd"`
+	DepthPools       int     `json:"depth_pools"`
+	ThresholdBps     int     `json:"threshold_bps"`
+	Status           string  `json:"status"`
+	FetchedAt        string  `json:"fetched_at"`
+}
+
 type AssetResolution struct {
 	Input       string `json:"input"`
 	ChainID     string `json:"chain_id"`
@@ -182,6 +433,47 @@ type AssetResolution struct {
 	Decimals    int    `json:"decimals"`
 	ResolvedBy  string `json:"resolved_by"`
 	Unambiguous bool   `json:"unambiguous"`
+	// Equivalents lists wrapped or bridged variants of the asset on the same
+	// chain (for example USDbC for USDC on Base). Empty with --strict-asset.
+	Equivalents []AssetEquivalent `json:"equivalents,omitempty"`
+}
+
+type AssetEquivalent struct {
+	Symbol   string `json:"symbol"`
+	AssetID  string `json:"asset_id"`
+	Address  string `json:"address"`
+	Decimals int    `json:"decimals"`
+}
+
+// TokenSafetyReport is the assets inspect result: on-chain token metadata,
+// proxy and ownership state, explorer verification, and heuristic risk flags.
+type TokenSafetyReport struct {
+	ChainID     string     `json:"chain_id"`
+	AssetID     string     `json:"asset_id"`
+	Address     string     `json:"address"`
+	Name        string     `json:"name,omitempty"`
+	Symbol      string     `json:"symbol"`
+	Decimals    int        `json:"decimals"`
+	TotalSupply AmountInfo `json:"total_supply"`
+	// Owner is the owner() result; empty when the token has no owner().
+	Owner          string `json:"owner,omitempty"`
+	OwnerRenounced bool   `json:"owner_renounced"`
+	IsProxy        bool   `json:"is_proxy"`
+	Implementation string `json:"implementation,omitempty"`
+	// Verification is verified, unverified, or unknown when the explorer
+	// could not be queried.
+	Verification string            `json:"verification"`
+	ContractName string            `json:"contract_name,omitempty"`
+	Flags        []TokenSafetyFlag `json:"flags"`
+	// RiskLevel is the highest flag severity, or low without flags.
+	RiskLevel string `json:"risk_level"`
+	FetchedAt string `json:"fetched_at"`
+}
+
+type TokenSafetyFlag struct {
+	Code     string `json:"code"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
 }
 
 type LendMarket struct {
@@ -199,6 +491,10 @@ type LendMarket struct {
 	FetchedAt            string  `json:"fetched_at"`
 }
 
+// LendRate is a lending market's current supply and borrow rate. SupplyAPY
+// and BorrowAPY are as the provider reports them, on RateBasis (apr|apy) with
+// the market's Compounding frequency; the *_effective fields are the same
+// rates as compounded APYs, comparable across providers.
 type LendRate struct {
 	Protocol             string  `json:"protocol"`
 	Provider             string  `json:"provider"`
@@ -208,11 +504,63 @@ type LendRate struct {
 	ProviderNativeIDKind string  `json:"provider_native_id_kind,omitempty"`
 	SupplyAPY            float64 `json:"supply_apy"`
 	BorrowAPY            float64 `json:"borrow_apy"`
+	RateBasis            string  `json:"rate_basis"`
+	Compounding          string  `json:"compounding"`
+	SupplyAPYEffective   float64 `json:"supply_apy_effective"`
+	BorrowAPYEffective   float64 `json:"borrow_apy_effective"`
 	Utilization          float64 `json:"utilization"`
+	Source               string  `json:"source,omitempty"`
 	SourceURL            string  `json:"source_url,omitempty"`
 	FetchedAt            string  `json:"fetched_at"`
 }
 
+// BorrowQuote is one venue for borrowing an asset against a collateral.
+// MaxLTVPct is how much of the collateral value can be borrowed and
+// LiquidationLTVPct where the position becomes liquidatable; both are zero
+// when the provider does not report them. The USD fields and FitsLiquidity
+// are set when the borrowed asset has a USD price.
+type BorrowQuote struct {
+	Rank                  int        `json:"rank"`
+	Protocol              string     `json:"protocol"`
+	Provider              string     `json:"provider"`
+	ChainID               string     `json:"chain_id"`
+	AssetID               string     `json:"asset_id"`
+	CollateralAssetID     string     `json:"collateral_asset_id,omitempty"`
+	ProviderNativeID      string     `json:"provider_native_id,omitempty"`
+	ProviderNativeIDKind  string     `json:"provider_native_id_kind,omitempty"`
+	Amount                AmountInfo `json:"amount"`
+	AmountUSD             float64    `json:"amount_usd,omitempty"`
+	BorrowAPY             float64    `json:"borrow_apy"`
+	AnnualCostUSD         float64    `json:"annual_cost_usd,omitempty"`
+	AvailableLiquidityUSD float64    `json:"available_liquidity_usd"`
+	FitsLiquidity         *bool      `json:"fits_liquidity,omitempty"`
+	MaxLTVPct             float64    `json:"max_ltv_pct,omitempty"`
+	LiquidationLTVPct     float64    `json:"liquidation_ltv_pct,omitempty"`
+	MinCollateralUSD      float64    `json:"min_collateral_usd,omitempty"`
+	SourceURL             string     `json:"source_url,omitempty"`
+	FetchedAt             string     `json:"fetched_at"`
+}
+
+// ClaimableReward is an incentive token an account can claim now. For Aave,
+// Sources are the aToken and debt token addresses to claim from. For rewards
+// distributed through a Merkl distributor, CumulativeAmount and Proofs are
+// the claim arguments; Claimable is what the claim would transfer.
+type ClaimableReward struct {
+	Provider         string     `json:"provider"`
+	ChainID          string     `json:"chain_id"`
+	Account          string     `json:"account"`
+	RewardAssetID    string     `json:"reward_asset_id"`
+	Symbol           string     `json:"symbol,omitempty"`
+	Claimable        AmountInfo `json:"claimable"`
+	ClaimableUSD     float64    `json:"claimable_usd,omitempty"`
+	Distributor      string     `json:"distributor"`
+	Sources          []string   `json:"sources,omitempty"`
+	CumulativeAmount string     `json:"cumulative_amount,omitempty"`
+	Proofs           []string   `json:"proofs,omitempty"`
+	SourceURL        string     `json:"source_url,omitempty"`
+	FetchedAt        string     `json:"fetched_at"`
+}
+
 type LendPosition struct {
 	Protocol             string     `json:"protocol"`
 	Provider             string     `json:"provider"`
@@ -229,10 +577,45 @@ type LendPosition struct {
 	FetchedAt            string     `json:"fetched_at"`
 }
 
+// LendHealth is the liquidation risk of an account in one lending market.
+// HealthFactor and the price moves are omitted while the account has no debt.
+type LendHealth struct {
+	Protocol                string                 `json:"protocol"`
+	Provider                string                 `json:"provider"`
+	ChainID                 string                 `json:"chain_id"`
+	AccountAddress          string                 `json:"account_address"`
+	MarketAddress           string                 `json:"market_address,omitempty"`
+	HealthFactor            *float64               `json:"health_factor,omitempty"`
+	CollateralUSD           float64                `json:"collateral_usd"`
+	DebtUSD                 float64                `json:"debt_usd"`
+	LTVPct                  float64                `json:"ltv_pct"`
+	MaxLTVPct               float64                `json:"max_ltv_pct"`
+	LiquidationThresholdPct float64                `json:"liquidation_threshold_pct"`
+	LiquidationPriceMovePct *float64               `json:"liquidation_price_move_pct,omitempty"`
+	Collateral              []LendHealthCollateral `json:"collateral"`
+	SourceURL               string                 `json:"source_url,omitempty"`
+	FetchedAt               string                 `json:"fetched_at"`
+}
+
+// LendHealthCollateral is one collateral asset of a LendHealth.
+// LiquidationPriceMovePct is the drop of this asset alone that liquidates the
+// account; it is omitted when losing all of it would not.
+type LendHealthCollateral struct {
+	AssetID                 string   `json:"asset_id"`
+	Symbol                  string   `json:"symbol,omitempty"`
+	AmountUSD               float64  `json:"amount_usd"`
+	MaxLTVPct               float64  `json:"max_ltv_pct"`
+	LiquidationThresholdPct float64  `json:"liquidation_threshold_pct"`
+	LiquidationPriceMovePct *float64 `json:"liquidation_price_move_pct,omitempty"`
+}
+
 type AmountInfo struct {
 	AmountBaseUnits string `json:"amount_base_units"`
 	AmountDecimal   string `json:"amount_decimal"`
 	Decimals        int    `json:"decimals"`
+	// AmountUSD is the USD notional the amount was converted from with
+	// --amount-usd.
+	AmountUSD float64 `json:"amount_usd,omitempty"`
 }
 
 type FeeAmount struct {
@@ -284,6 +667,7 @@ type BridgeSummary struct {
 	URL              string        `json:"url,omitempty"`
 	Chains           []string      `json:"chains,omitempty"`
 	Volumes          BridgeVolumes `json:"volumes"`
+	Safety           *BridgeSafety `json:"safety,omitempty"`
 	LastUpdatedUNIX  int64         `json:"last_updated_unix"`
 	FetchedAt        string        `json:"fetched_at"`
 }
@@ -321,23 +705,176 @@ type BridgeQuote struct {
 	FeeBreakdown               *BridgeFeeBreakdown `json:"fee_breakdown,omitempty"`
 	EstimatedTimeS             int64               `json:"estimated_time_s"`
 	Route                      string              `json:"route"`
+	Safety                     *BridgeSafety       `json:"safety,omitempty"`
 	SourceURL                  string              `json:"source_url,omitempty"`
 	FetchedAt                  string              `json:"fetched_at"`
+	// ExpiresAt is when the quote stops being valid. Firmness is "firm" when
+	// the provider commits to the price until then, "indicative" otherwise.
+	ExpiresAt string `json:"expires_at,omitempty"`
+	Firmness  string `json:"firmness,omitempty"`
 }
 
-type SwapQuote struct {
-	Provider        string     `json:"provider"`
-	ChainID         string     `json:"chain_id"`
+// BridgeQuoteComparison holds quotes for the same transfer from several
+// providers, ordered by estimated output (best first).
+type BridgeQuoteComparison struct {
+	Quotes             []BridgeQuote `json:"quotes"`
+	BestOutputProvider string        `json:"best_output_provider"`
+	CheapestProvider   string        `json:"cheapest_provider"`
+	FastestProvider    string        `json:"fastest_provider"`
+}
+
+// RouteQuote is a candidate cross-chain route made of a bridge leg and an
+// optional destination-chain swap leg. NetOutUSD is the estimated output
+// value minus swap gas; bridge fees are already reflected in the bridge
+// leg's output. USD fields are zero when no output price was available.
+type RouteQuote struct {
+	Route           string     `json:"route"`
+	FromChainID     string     `json:"from_chain_id"`
+	ToChainID       string     `json:"to_chain_id"`
 	FromAssetID     string     `json:"from_asset_id"`
 	ToAssetID       string     `json:"to_asset_id"`
-	TradeType       string     `json:"trade_type"`
 	InputAmount     AmountInfo `json:"input_amount"`
 	EstimatedOut    AmountInfo `json:"estimated_out"`
-	EstimatedGasUSD float64    `json:"estimated_gas_usd"`
-	PriceImpactPct  float64    `json:"price_impact_pct"`
-	Route           string     `json:"route"`
-	SourceURL       string     `json:"source_url,omitempty"`
-	FetchedAt       string     `json:"fetched_at"`
+	BridgeFeeUSD    float64    `json:"bridge_fee_usd"`
+	GasUSD          float64    `json:"gas_usd"`
+	OutputPriceUSD  float64    `json:"output_price_usd,omitempty"`
+	EstimatedOutUSD float64    `json:"estimated_out_usd,omitempty"`
+	NetOutUSD       float64    `json:"net_out_usd,omitempty"`
+	EstimatedTimeS  int64      `json:"estimated_time_s"`
+	Legs            []RouteLeg `json:"legs"`
+}
+
+// RouteLeg is one provider hop of a RouteQuote, in execution order.
+type RouteLeg struct {
+	Type           string     `json:"type"`
+	Provider       string     `json:"provider"`
+	FromChainID    string     `json:"from_chain_id"`
+	ToChainID      string     `json:"to_chain_id"`
+	FromAssetID    string     `json:"from_asset_id"`
+	ToAssetID      string     `json:"to_asset_id"`
+	InputAmount    AmountInfo `json:"input_amount"`
+	EstimatedOut   AmountInfo `json:"estimated_out"`
+	FeeUSD         float64    `json:"fee_usd,omitempty"`
+	GasUSD         float64    `json:"gas_usd,omitempty"`
+	EstimatedTimeS int64      `json:"estimated_time_s,omitempty"`
+}
+
+// RouteComparison ranks candidate routes best first: by net USD output when
+// the output asset is priced, otherwise by estimated output.
+type RouteComparison struct {
+	Routes    []RouteQuote `json:"routes"`
+	BestRoute string       `json:"best_route"`
+	RankedBy  string       `json:"ranked_by"`
+}
+
+// BridgeSafety is curated risk metadata for a bridge protocol.
+type BridgeSafety struct {
+	Bridge            string          `json:"bridge"`
+	TrustModel        string          `json:"trust_model"`
+	Exploits          []BridgeExploit `json:"exploits"`
+	AvgFinalityS      int64           `json:"avg_finality_s"`
+	MaxRecommendedUSD float64         `json:"max_recommended_usd"`
+	Notes             string          `json:"notes,omitempty"`
+	Source            string          `json:"source"`
+	AsOf              string          `json:"as_of"`
+}
+
+type BridgeExploit struct {
+	Date    string  `json:"date"`
+	LossUSD float64 `json:"loss_usd"`
+	Summary string  `json:"summary"`
+}
+
+// Paymaster is an ERC-4337 paymaster usable for gas sponsorship on a chain.
+type Paymaster struct {
+	Name               string           `json:"name"`
+	Provider           string           `json:"provider"`
+	ChainID            string           `json:"chain_id"`
+	Source             string           `json:"source"`
+	Mode               string           `json:"mode"`
+	Policy             string           `json:"policy,omitempty"`
+	FeeTokens          []PaymasterToken `json:"fee_tokens"`
+	Address            string           `json:"address,omitempty"`
+	EntryPoints        []string         `json:"entry_points"`
+	RequiresAPIKey     bool             `json:"requires_api_key"`
+	EndpointConfigured bool             `json:"endpoint_configured"`
+	Notes              string           `json:"notes,omitempty"`
+	AsOf               string           `json:"as_of,omitempty"`
+}
+
+type PaymasterToken struct {
+	Symbol  string `json:"symbol"`
+	AssetID string `json:"asset_id,omitempty"`
+}
+
+// SwapQuote is a single-chain swap quote, or a cross-chain swap quote when
+// ToChainID is set. Cross-chain quotes report bridge and swap fees in
+// EstimatedFeeUSD and the delivery estimate in EstimatedTimeS.
+type SwapQuote struct {
+	Provider        string         `json:"provider"`
+	ChainID         string         `json:"chain_id"`
+	ToChainID       string         `json:"to_chain_id,omitempty"`
+	FromAssetID     string         `json:"from_asset_id"`
+	ToAssetID       string         `json:"to_asset_id"`
+	TradeType       string         `json:"trade_type"`
+	InputAmount     AmountInfo     `json:"input_amount"`
+	InputLegs       []SwapInputLeg `json:"input_legs,omitempty"`
+	EstimatedOut    AmountInfo     `json:"estimated_out"`
+	EstimatedGasUSD float64        `json:"estimated_gas_usd"`
+	EstimatedFeeUSD float64        `json:"estimated_fee_usd,omitempty"`
+	EstimatedTimeS  int64          `json:"estimated_time_s,omitempty"`
+	PriceImpactPct  float64        `json:"price_impact_pct"`
+	Route           string         `json:"route"`
+	RouteHops       []RouteHop     `json:"route_hops,omitempty"`
+	SourceURL       string         `json:"source_url,omitempty"`
+	FetchedAt       string         `json:"fetched_at"`
+	// Emulated is set when an exact-output input amount was solved by
+	// searching exact-input quotes because the provider has no native
+	// exact-output quote.
+	Emulated bool `json:"emulated,omitempty"`
+	// MinOut is the worst-case output within SlippagePct. MaxIn is the
+	// worst-case input of exact-output quotes.
+	MinOut      *AmountInfo `json:"min_out,omitempty"`
+	MaxIn       *AmountInfo `json:"max_in,omitempty"`
+	SlippagePct float64     `json:"slippage_pct,omitempty"`
+	// ExpiresAt is when the quote stops being valid. Firmness is "firm" when
+	// the provider commits to the price until then, "indicative" otherwise.
+	ExpiresAt string `json:"expires_at,omitempty"`
+	Firmness  string `json:"firmness,omitempty"`
+}
+
+// SwapInputLeg is one input of a multi-input swap. FromAssetID and
+// InputAmount on the quote mirror the first leg.
+type SwapInputLeg struct {
+	AssetID string     `json:"asset_id"`
+	Amount  AmountInfo `json:"amount"`
+}
+
+// RouteHop is one leg of an aggregator route. Split routes share a Path
+// index; SharePct is the portion of the total input routed through the hop.
+type RouteHop struct {
+	Path      int     `json:"path"`
+	Hop       int     `json:"hop"`
+	FromToken string  `json:"from_token"`
+	ToToken   string  `json:"to_token"`
+	Exchange  string  `json:"exchange"`
+	SharePct  float64 `json:"share_pct"`
+}
+
+type ArchivedQuote struct {
+	Kind         string     `json:"kind"`
+	Provider     string     `json:"provider"`
+	ChainID      string     `json:"chain_id"`
+	ToChainID    string     `json:"to_chain_id"`
+	FromAssetID  string     `json:"from_asset_id"`
+	ToAssetID    string     `json:"to_asset_id"`
+	TradeType    string     `json:"trade_type,omitempty"`
+	InputAmount  AmountInfo `json:"input_amount"`
+	EstimatedOut AmountInfo `json:"estimated_out"`
+	Price        float64    `json:"price"`
+	FeeUSD       float64    `json:"fee_usd"`
+	Route        string     `json:"route"`
+	ObservedAt   string     `json:"observed_at"`
 }
 
 type YieldBackingAsset struct {
@@ -346,27 +883,132 @@ type YieldBackingAsset struct {
 	SharePct float64 `json:"share_pct"`
 }
 
+// YieldOpportunity is one place to earn yield on an asset. APYTotal is as the
+// provider reports it, on RateBasis (apr|apy) with Compounding frequency;
+// APYEffective is the compounded APY, comparable across providers.
+// RiskReasons flags risks risk_score does not weigh, such as a depegged
+// stablecoin. EquivalentOf holds the requested asset_id on rows returned for
+// a wrapped or bridged equivalent of it.
 type YieldOpportunity struct {
 	OpportunityID        string              `json:"opportunity_id"`
 	Provider             string              `json:"provider"`
 	Protocol             string              `json:"protocol"`
 	ChainID              string              `json:"chain_id"`
 	AssetID              string              `json:"asset_id"`
+	EquivalentOf         string              `json:"equivalent_of,omitempty"`
 	ProviderNativeID     string              `json:"provider_native_id,omitempty"`
 	ProviderNativeIDKind string              `json:"provider_native_id_kind,omitempty"`
 	Type                 string              `json:"type"`
 	APYBase              float64             `json:"apy_base"`
 	APYReward            float64             `json:"apy_reward"`
 	APYTotal             float64             `json:"apy_total"`
+	RateBasis            string              `json:"rate_basis"`
+	Compounding          string              `json:"compounding"`
+	APYEffective         float64             `json:"apy_effective"`
 	TVLUSD               float64             `json:"tvl_usd"`
 	LiquidityUSD         float64             `json:"liquidity_usd"`
 	LockupDays           float64             `json:"lockup_days"`
 	WithdrawalTerms      string              `json:"withdrawal_terms"`
+	ExitLiquidity        *YieldExitLiquidity `json:"exit_liquidity,omitempty"`
+	APYVolatility        *YieldAPYVolatility `json:"apy_volatility,omitempty"`
+	RiskScore            float64             `json:"risk_score"`
+	RiskLevel            string              `json:"risk_level"`
+	RiskFactors          *YieldRiskFactors   `json:"risk_factors,omitempty"`
+	RiskReasons          []string            `json:"risk_reasons,omitempty"`
+	Security             *YieldSecurity      `json:"security,omitempty"`
+	LPAnalytics          *YieldLPAnalytics   `json:"lp_analytics,omitempty"`
+	Gauge                *YieldGauge         `json:"gauge,omitempty"`
 	BackingAssets        []YieldBackingAsset `json:"backing_assets"`
 	SourceURL            string              `json:"source_url,omitempty"`
 	FetchedAt            string              `json:"fetched_at"`
 }
 
+type YieldExitLiquidity struct {
+	ImmediateUSD float64 `json:"immediate_usd"`
+	QueuedUSD    float64 `json:"queued_usd"`
+	ImmediatePct float64 `json:"immediate_pct"`
+	QueueDays    float64 `json:"queue_days"`
+}
+
+// YieldAPYVolatility summarizes apy_total history over a trailing window.
+// StabilityScore is 0-1; higher means the APY held steady.
+type YieldAPYVolatility struct {
+	WindowDays     int     `json:"window_days"`
+	Points         int     `json:"points"`
+	MeanAPY        float64 `json:"mean_apy"`
+	StdDevAPY      float64 `json:"stddev_apy"`
+	MaxDrawdownPct float64 `json:"max_drawdown_pct"`
+	StabilityScore float64 `json:"stability_score"`
+}
+
+// YieldRiskFactors are the inputs of risk_score. Each *_score is 0-100;
+// higher means riskier.
+type YieldRiskFactors struct {
+	AuditAgeYears    float64 `json:"audit_age_years"`
+	ProtocolScore    float64 `json:"protocol_score"`
+	TVLScore         float64 `json:"tvl_score"`
+	UtilizationPct   float64 `json:"utilization_pct"`
+	UtilizationScore float64 `json:"utilization_score"`
+	AssetClass       string  `json:"asset_class"`
+	AssetScore       float64 `json:"asset_score"`
+	Exploits         int     `json:"exploits"`
+}
+
+// YieldSecurity summarizes the ProtocolSecurity of an opportunity's protocol.
+type YieldSecurity struct {
+	Audits         int    `json:"audits"`
+	ListingAgeDays int    `json:"listing_age_days"`
+	Exploits       int    `json:"exploits"`
+	LastExploitAt  string `json:"last_exploit_at,omitempty"`
+}
+
+// YieldLPAnalytics describes the impermanent loss exposure of a liquidity
+// pool position. ExpectedILPct is the loss after a one standard deviation
+// annual move of the pair; NetAPYAfterIL adds it to fee and reward APR.
+type YieldLPAnalytics struct {
+	PairVolatilityPct    float64           `json:"pair_volatility_pct"`
+	VolatilityWindowDays int               `json:"volatility_window_days"`
+	ExpectedILPct        float64           `json:"expected_il_pct"`
+	ILScenarios          []YieldILScenario `json:"il_scenarios"`
+	FeeAPR               float64           `json:"fee_apr"`
+	RewardAPR            float64           `json:"reward_apr"`
+	NetAPYAfterIL        float64           `json:"net_apy_after_il"`
+}
+
+// YieldGauge is the ve(3,3) gauge of a DEX liquidity pool. Gauge emissions
+// are the opportunity's apy_reward; Bribes are the rewards deposited for
+// voters on the gauge in the current weekly epoch.
+type YieldGauge struct {
+	Address      string            `json:"address"`
+	Bribes       []YieldGaugeBribe `json:"bribes"`
+	EpochStart   string            `json:"epoch_start"`
+	EpochEnd     string            `json:"epoch_end"`
+	EpochEndsInS int64             `json:"epoch_ends_in_s"`
+}
+
+type YieldGaugeBribe struct {
+	AssetID string     `json:"asset_id"`
+	Symbol  string     `json:"symbol,omitempty"`
+	Amount  AmountInfo `json:"amount"`
+}
+
+type YieldILScenario struct {
+	PriceMovePct float64 `json:"price_move_pct"`
+	ILPct        float64 `json:"il_pct"`
+}
+
+// ILSimulation is the impermanent loss profile of a two-asset pool.
+type ILSimulation struct {
+	ChainID     string           `json:"chain_id"`
+	AssetAID    string           `json:"asset_a_id"`
+	SymbolA     string           `json:"symbol_a,omitempty"`
+	AssetBID    string           `json:"asset_b_id"`
+	SymbolB     string           `json:"symbol_b,omitempty"`
+	LPAnalytics YieldLPAnalytics `json:"lp_analytics"`
+	Provider    string           `json:"provider,omitempty"`
+	FetchedAt   string           `json:"fetched_at"`
+}
+
 type YieldPosition struct {
 	Protocol             string      `json:"protocol"`
 	Provider             string      `json:"provider"`
@@ -395,6 +1037,92 @@ type WalletBalance struct {
 	FetchedAt      string     `json:"fetched_at"`
 }
 
+// AssetBalance is one non-zero holding in a multi-chain balance snapshot.
+// PriceUSD and ValueUSD are omitted when no price source covers the asset.
+type AssetBalance struct {
+	ChainID        string     `json:"chain_id"`
+	AccountAddress string     `json:"account_address"`
+	AssetType      string     `json:"asset_type"`
+	AssetID        string     `json:"asset_id"`
+	Symbol         string     `json:"symbol"`
+	Balance        AmountInfo `json:"balance"`
+	PriceUSD       *float64   `json:"price_usd,omitempty"`
+	ValueUSD       *float64   `json:"value_usd,omitempty"`
+}
+
+// WalletBalances is the output of the balances command. Balances are sorted
+// by USD value, largest first, with unpriced holdings last.
+type WalletBalances struct {
+	Addresses    []string       `json:"addresses"`
+	Chains       []string       `json:"chains"`
+	Balances     []AssetBalance `json:"balances"`
+	TotalUSD     float64        `json:"total_usd"`
+	MinUSD       float64        `json:"min_usd,omitempty"`
+	DustFiltered int            `json:"dust_filtered"`
+	FetchedAt    string         `json:"fetched_at"`
+}
+
+// ActivityEntry is one transaction touching an address, normalized from a
+// block explorer. Kind is transfer, swap, approval, contract_call, or
+// contract_creation; Direction is in, out, or self from the address's view.
+type ActivityEntry struct {
+	ChainID     string             `json:"chain_id"`
+	TxHash      string             `json:"tx_hash"`
+	BlockNumber int64              `json:"block_number"`
+	Timestamp   string             `json:"timestamp"`
+	Kind        string             `json:"kind"`
+	Direction   string             `json:"direction"`
+	From        string             `json:"from"`
+	To          string             `json:"to,omitempty"`
+	ToLabel     string             `json:"to_label,omitempty"`
+	Method      string             `json:"method,omitempty"`
+	MethodID    string             `json:"method_id,omitempty"`
+	Protocol    string             `json:"protocol,omitempty"`
+	Value       *AmountInfo        `json:"value,omitempty"`
+	Transfers   []ActivityTransfer `json:"transfers,omitempty"`
+	GasFeeWei   string             `json:"gas_fee_wei,omitempty"`
+	Failed      bool               `json:"failed"`
+}
+
+// ActivityTransfer is a token transfer inside an activity entry.
+type ActivityTransfer struct {
+	AssetID   string     `json:"asset_id"`
+	Symbol    string     `json:"symbol"`
+	From      string     `json:"from"`
+	To        string     `json:"to"`
+	Direction string     `json:"direction"`
+	Amount    AmountInfo `json:"amount"`
+}
+
+// ActivityFeed is the output of the activity command, newest first.
+type ActivityFeed struct {
+	Address   string          `json:"address"`
+	ChainID   string          `json:"chain_id"`
+	Provider  string          `json:"provider"`
+	Entries   []ActivityEntry `json:"entries"`
+	FetchedAt string          `json:"fetched_at"`
+}
+
+type MarketHistoryPoint struct {
+	Timestamp string  `json:"timestamp"`
+	Value     float64 `json:"value"`
+}
+
+type MarketHistorySeries struct {
+	Metric    string               `json:"metric"`
+	Interval  string               `json:"interval"`
+	ChainID   string               `json:"chain_id,omitempty"`
+	Protocol  string               `json:"protocol,omitempty"`
+	AssetID   string               `json:"asset_id,omitempty"`
+	Symbol    string               `json:"symbol,omitempty"`
+	StartTime string               `json:"start_time"`
+	EndTime   string               `json:"end_time"`
+	Points    []MarketHistoryPoint `json:"points"`
+	Provider  string               `json:"provider"`
+	SourceURL string               `json:"source_url,omitempty"`
+	FetchedAt string               `json:"fetched_at"`
+}
+
 type YieldHistoryPoint struct {
 	Timestamp string  `json:"timestamp"`
 	Value     float64 `json:"value"`
@@ -416,3 +1144,366 @@ type YieldHistorySeries struct {
 	SourceURL            string              `json:"source_url,omitempty"`
 	FetchedAt            string              `json:"fetched_at"`
 }
+
+// YieldBacktest compares holding one opportunity with rotating to the best
+// APY every RotateEvery over a historical window. Returns are percent of
+// AmountUSD; rotations pay RotationGasUSD each.
+type YieldBacktest struct {
+	ChainID        string                  `json:"chain_id"`
+	AssetID        string                  `json:"asset_id"`
+	StartTime      string                  `json:"start_time"`
+	EndTime        string                  `json:"end_time"`
+	RotateEvery    string                  `json:"rotate_every"`
+	AmountUSD      float64                 `json:"amount_usd"`
+	RotationGasUSD float64                 `json:"rotation_gas_usd"`
+	Candidates     int                     `json:"candidates"`
+	Strategies     []YieldBacktestStrategy `json:"strategies"`
+	// RotationEdgePct is the rotate strategy's cumulative return minus the
+	// hold strategy's, in percentage points.
+	RotationEdgePct float64 `json:"rotation_edge_pct"`
+	FetchedAt       string  `json:"fetched_at"`
+}
+
+// YieldBacktestStrategy is the outcome of one backtested strategy.
+type YieldBacktestStrategy struct {
+	Strategy            string                 `json:"strategy"`
+	FinalValueUSD       float64                `json:"final_value_usd"`
+	CumulativeReturnPct float64                `json:"cumulative_return_pct"`
+	AnnualizedReturnPct float64                `json:"annualized_return_pct"`
+	Rotations           int                    `json:"rotations"`
+	GasDragUSD          float64                `json:"gas_drag_usd"`
+	GasDragPct          float64                `json:"gas_drag_pct"`
+	Holdings            []YieldBacktestHolding `json:"holdings"`
+}
+
+// YieldBacktestHolding is a span during which a strategy held one
+// opportunity. APYAtEntry is the apy_total that selected it.
+type YieldBacktestHolding struct {
+	OpportunityID string  `json:"opportunity_id"`
+	Provider      string  `json:"provider"`
+	Protocol      string  `json:"protocol"`
+	From          string  `json:"from"`
+	To            string  `json:"to"`
+	APYAtEntry    float64 `json:"apy_at_entry"`
+	ReturnPct     float64 `json:"return_pct"`
+}
+
+// YieldLoop is a lend-borrow loop on one venue: supply the collateral, borrow
+// the borrow asset against it, convert, and supply again. CollateralYieldPct
+// is the collateral supply APY plus its staking APY; SpreadPct is that minus
+// the borrow APY, so a loop is profitable when it is positive. TargetLTVPct is
+// the max LTV capped BufferPct below liquidation, and MaxLeverage the
+// leverage of looping it forever. All rates are percentage points.
+type YieldLoop struct {
+	Rank                  int             `json:"rank"`
+	Protocol              string          `json:"protocol"`
+	Provider              string          `json:"provider"`
+	ChainID               string          `json:"chain_id"`
+	CollateralAssetID     string          `json:"collateral_asset_id"`
+	BorrowAssetID         string          `json:"borrow_asset_id"`
+	ProviderNativeID      string          `json:"provider_native_id,omitempty"`
+	ProviderNativeIDKind  string          `json:"provider_native_id_kind,omitempty"`
+	CollateralSupplyAPY   float64         `json:"collateral_supply_apy" unit:"percent"`
+	CollateralStakingAPY  float64         `json:"collateral_staking_apy" unit:"percent"`
+	CollateralYieldPct    float64         `json:"collateral_yield_pct" unit:"percent"`
+	BorrowAPY             float64         `json:"borrow_apy" unit:"percent"`
+	SpreadPct             float64         `json:"spread_pct" unit:"percent"`
+	Profitable            bool            `json:"profitable"`
+	MaxLTVPct             float64         `json:"max_ltv_pct" unit:"percent"`
+	LiquidationLTVPct     float64         `json:"liquidation_ltv_pct" unit:"percent"`
+	BufferPct             float64         `json:"buffer_pct" unit:"percent"`
+	TargetLTVPct          float64         `json:"target_ltv_pct" unit:"percent"`
+	MaxLeverage           float64         `json:"max_leverage"`
+	MaxNetAPY             float64         `json:"max_net_apy" unit:"percent"`
+	Steps                 []YieldLoopStep `json:"steps"`
+	AvailableLiquidityUSD float64         `json:"available_liquidity_usd"`
+	SourceURL             string          `json:"source_url,omitempty"`
+	FetchedAt             string          `json:"fetched_at"`
+}
+
+// YieldLoopStep is a loop after Loops borrow-and-resupply rounds at the
+// target LTV. Leverage is total collateral over the initial deposit and
+// LTVPct the resulting debt over collateral.
+type YieldLoopStep struct {
+	Loops    int     `json:"loops"`
+	Leverage float64 `json:"leverage"`
+	LTVPct   float64 `json:"ltv_pct" unit:"percent"`
+	NetAPY   float64 `json:"net_apy" unit:"percent"`
+}
+
+// StakeRate is the current staking yield of a liquid staking token. APR is
+// percentage points.
+type StakeRate struct {
+	Provider             string  `json:"provider"`
+	Protocol             string  `json:"protocol"`
+	ChainID              string  `json:"chain_id"`
+	AssetID              string  `json:"asset_id"`
+	Symbol               string  `json:"symbol"`
+	UnderlyingAssetID    string  `json:"underlying_asset_id"`
+	APR                  float64 `json:"apr" unit:"percent"`
+	TVLUSD               float64 `json:"tvl_usd"`
+	WithdrawalTerms      string  `json:"withdrawal_terms"`
+	ProviderNativeID     string  `json:"provider_native_id,omitempty"`
+	ProviderNativeIDKind string  `json:"provider_native_id_kind,omitempty"`
+	SourceURL            string  `json:"source_url,omitempty"`
+	FetchedAt            string  `json:"fetched_at"`
+}
+
+// PerpMarket is a perpetual futures market snapshot. FundingRate is the
+// current rate per funding interval as a fraction; FundingAPR annualizes it in
+// percentage points (positive means longs pay shorts).
+type PerpMarket struct {
+	Provider             string  `json:"provider"`
+	Market               string  `json:"market"`
+	BaseSymbol           string  `json:"base_symbol"`
+	QuoteSymbol          string  `json:"quote_symbol"`
+	MarkPrice            float64 `json:"mark_price"`
+	IndexPrice           float64 `json:"index_price"`
+	FundingRate          float64 `json:"funding_rate"`
+	FundingIntervalHours float64 `json:"funding_interval_hours"`
+	FundingAPR           float64 `json:"funding_apr" unit:"percent"`
+	OpenInterest         float64 `json:"open_interest"`
+	OpenInterestUSD      float64 `json:"open_interest_usd"`
+	Volume24hUSD         float64 `json:"volume_24h_usd"`
+	MaxLeverage          float64 `json:"max_leverage"`
+	SourceURL            string  `json:"source_url,omitempty"`
+	FetchedAt            string  `json:"fetched_at"`
+}
+
+// PerpFundingRate is the funding view of a PerpMarket.
+type PerpFundingRate struct {
+	Provider             string  `json:"provider"`
+	Market               string  `json:"market"`
+	BaseSymbol           string  `json:"base_symbol"`
+	FundingRate          float64 `json:"funding_rate"`
+	FundingIntervalHours float64 `json:"funding_interval_hours"`
+	FundingAPR           float64 `json:"funding_apr" unit:"percent"`
+	MarkPrice            float64 `json:"mark_price"`
+	IndexPrice           float64 `json:"index_price"`
+	OpenInterestUSD      float64 `json:"open_interest_usd"`
+	FetchedAt            string  `json:"fetched_at"`
+}
+
+// OptionContract is a listed option with its venue mark price and greeks.
+// MarkIV is the mark implied volatility in percentage points; prices are in
+// QuoteSymbol units.
+type OptionContract struct {
+	Provider     string  `json:"provider"`
+	Instrument   string  `json:"instrument"`
+	Underlying   string  `json:"underlying"`
+	QuoteSymbol  string  `json:"quote_symbol"`
+	OptionType   string  `json:"option_type"`
+	Strike       float64 `json:"strike"`
+	Expiry       string  `json:"expiry"`
+	DaysToExpiry float64 `json:"days_to_expiry"`
+	MarkPrice    float64 `json:"mark_price"`
+	IndexPrice   float64 `json:"index_price"`
+	ForwardPrice float64 `json:"forward_price"`
+	MarkIV       float64 `json:"mark_iv" unit:"percent"`
+	Delta        float64 `json:"delta"`
+	Gamma        float64 `json:"gamma"`
+	Theta        float64 `json:"theta"`
+	Vega         float64 `json:"vega"`
+	SourceURL    string  `json:"source_url,omitempty"`
+	FetchedAt    string  `json:"fetched_at"`
+}
+
+// OptionIVSurface is the implied volatility surface for one underlying on one
+// venue, as a smile per expiry.
+type OptionIVSurface struct {
+	Provider   string          `json:"provider"`
+	Underlying string          `json:"underlying"`
+	IndexPrice float64         `json:"index_price"`
+	Expiries   []OptionIVSmile `json:"expiries"`
+	FetchedAt  string          `json:"fetched_at"`
+}
+
+// OptionIVSmile is the strike-by-strike IV for one expiry. ATMIV is taken at
+// the strike closest to the forward price.
+type OptionIVSmile struct {
+	Expiry       string          `json:"expiry"`
+	DaysToExpiry float64         `json:"days_to_expiry"`
+	ForwardPrice float64         `json:"forward_price"`
+	ATMStrike    float64         `json:"atm_strike"`
+	ATMIV        float64         `json:"atm_iv" unit:"percent"`
+	Points       []OptionIVPoint `json:"points"`
+}
+
+// OptionIVPoint is the call and put mark IV at one strike. Moneyness is
+// strike divided by the forward price; a missing side is omitted.
+type OptionIVPoint struct {
+	Strike    float64 `json:"strike"`
+	Moneyness float64 `json:"moneyness"`
+	CallIV    float64 `json:"call_iv,omitempty" unit:"percent"`
+	PutIV     float64 `json:"put_iv,omitempty" unit:"percent"`
+}
+
+// DexPool is a liquidity pool snapshot. Price is Token1 per Token0 at the
+// current tick. FeeTierPct is the swap fee in percentage points (0.05 for the
+// 500 tier) and FeeAPR annualizes the last 24h of fees over TVL. Liquidity is
+// the in-range liquidity L at the current tick in raw units, when reported.
+type DexPool struct {
+	Dex          string       `json:"dex"`
+	Version      string       `json:"version"`
+	ChainID      string       `json:"chain_id"`
+	PoolID       string       `json:"pool_id"`
+	Token0       DexPoolToken `json:"token0"`
+	Token1       DexPoolToken `json:"token1"`
+	FeeTier      int64        `json:"fee_tier"`
+	FeeTierPct   float64      `json:"fee_tier_pct" unit:"percent"`
+	TickSpacing  int64        `json:"tick_spacing,omitempty"`
+	Hooks        string       `json:"hooks,omitempty"`
+	Tick         int64        `json:"tick"`
+	Liquidity    string       `json:"liquidity,omitempty"`
+	Price        float64      `json:"price"`
+	TVLUSD       float64      `json:"tvl_usd"`
+	Volume24hUSD float64      `json:"volume_24h_usd"`
+	Fees24hUSD   float64      `json:"fees_24h_usd"`
+	FeeAPR       float64      `json:"fee_apr" unit:"percent"`
+	SourceURL    string       `json:"source_url,omitempty"`
+	FetchedAt    string       `json:"fetched_at"`
+}
+
+// DexPoolToken is one side of a DexPool.
+type DexPoolToken struct {
+	AssetID  string `json:"asset_id"`
+	Symbol   string `json:"symbol"`
+	Decimals int    `json:"decimals"`
+}
+
+// APYConsistencyReport compares APYs for the same chain/asset across lending
+// providers. All APY values are percentage points.
+type APYConsistencyReport struct {
+	ChainID       string           `json:"chain_id"`
+	AssetID       string           `json:"asset_id"`
+	Threshold     float64          `json:"threshold"`
+	Consistent    bool             `json:"consistent"`
+	Samples       []APYSample      `json:"samples"`
+	Discrepancies []APYDiscrepancy `json:"discrepancies"`
+	FetchedAt     string           `json:"fetched_at"`
+}
+
+type APYSample struct {
+	Provider        string  `json:"provider"`
+	Markets         int     `json:"markets"`
+	MedianSupplyAPY float64 `json:"median_supply_apy"`
+	MedianBorrowAPY float64 `json:"median_borrow_apy"`
+}
+
+type APYDiscrepancy struct {
+	Metric    string  `json:"metric"`
+	ProviderA string  `json:"provider_a"`
+	ProviderB string  `json:"provider_b"`
+	ValueA    float64 `json:"value_a" unit:"percent"`
+	ValueB    float64 `json:"value_b" unit:"percent"`
+	Ratio     float64 `json:"ratio"`
+}
+
+// QuoteVerification re-prices a swap quote or planned swap against
+// independent references. Deviations are percent; positive means the
+// provider quoted more output than the reference.
+type QuoteVerification struct {
+	Source          string           `json:"source"`
+	ActionID        string           `json:"action_id,omitempty"`
+	Provider        string           `json:"provider"`
+	ChainID         string           `json:"chain_id"`
+	FromAssetID     string           `json:"from_asset_id"`
+	ToAssetID       string           `json:"to_asset_id"`
+	InputAmount     AmountInfo       `json:"input_amount"`
+	QuotedOut       AmountInfo       `json:"quoted_out"`
+	MaxDeviationPct float64          `json:"max_deviation_pct" unit:"percent"`
+	Verified        bool             `json:"verified"`
+	References      []QuoteReference `json:"references"`
+	FetchedAt       string           `json:"fetched_at"`
+}
+
+type QuoteReference struct {
+	Source          string     `json:"source"`
+	EstimatedOut    AmountInfo `json:"estimated_out"`
+	DeviationPct    float64    `json:"deviation_pct" unit:"percent"`
+	WithinThreshold bool       `json:"within_threshold"`
+	Detail          string     `json:"detail,omitempty"`
+}
+
+// TokenPrice is a current USD price for one token from an aggregated price
+// source.
+type TokenPrice struct {
+	ChainID    string  `json:"chain_id"`
+	Address    string  `json:"address"`
+	Symbol     string  `json:"symbol"`
+	Decimals   int     `json:"decimals"`
+	PriceUSD   float64 `json:"price_usd"`
+	Confidence float64 `json:"confidence,omitempty"`
+	Timestamp  string  `json:"timestamp"`
+}
+
+// ConfigProfile summarizes one named profile from the config file. Only the
+// names of providers with keys are listed, never the keys.
+type ConfigProfile struct {
+	Name             string            `json:"name"`
+	Active           bool              `json:"active"`
+	APIKeys          []string          `json:"api_keys"`
+	RPCChains        []string          `json:"rpc_chains"`
+	DefaultProviders map[string]string `json:"default_providers,omitempty"`
+	CachePath        string            `json:"cache_path,omitempty"`
+	PolicyPath       string            `json:"policy_path,omitempty"`
+}
+
+type ConfigProfiles struct {
+	ConfigPath   string          `json:"config_path"`
+	Active       string          `json:"active,omitempty"`
+	ActiveSource string          `json:"active_source,omitempty"`
+	Profiles     []ConfigProfile `json:"profiles"`
+}
+
+// RPCEndpoint is one endpoint in a chain's RPC pool. URLs are redacted:
+// credentials in the path or query are replaced with ***.
+type RPCEndpoint struct {
+	ChainID     string `json:"chain_id"`
+	Chain       string `json:"chain"`
+	URL         string `json:"url"`
+	Source      string `json:"source"`
+	Rank        int    `json:"rank"`
+	Primary     bool   `json:"primary"`
+	LatencyMS   int64  `json:"latency_ms,omitempty"`
+	Failures    int    `json:"failures,omitempty"`
+	LastError   string `json:"last_error,omitempty"`
+	CheckedAt   string `json:"checked_at,omitempty"`
+	CoolingDown bool   `json:"cooling_down"`
+}
+
+// RPCProbe is the result of checking one endpoint with eth_chainId and
+// eth_blockNumber.
+type RPCProbe struct {
+	ChainID     string `json:"chain_id"`
+	Chain       string `json:"chain"`
+	URL         string `json:"url"`
+	Source      string `json:"source"`
+	OK          bool   `json:"ok"`
+	LatencyMS   int64  `json:"latency_ms"`
+	BlockNumber uint64 `json:"block_number,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+// RegistryToken is one token known to the asset registry. Source is builtin
+// for the bootstrap registry and user for the user token list.
+type RegistryToken struct {
+	ChainID  string `json:"chain_id"`
+	Symbol   string `json:"symbol"`
+	Name     string `json:"name,omitempty"`
+	AssetID  string `json:"asset_id"`
+	Address  string `json:"address"`
+	Decimals int    `json:"decimals"`
+	Source   string `json:"source"`
+	List     string `json:"list,omitempty"`
+}
+
+// TokenListChange reports an edit of the user token list.
+type TokenListChange struct {
+	Path    string          `json:"path"`
+	Added   int             `json:"added"`
+	Updated int             `json:"updated"`
+	Removed int             `json:"removed"`
+	Skipped int             `json:"skipped"`
+	Tokens  []RegistryToken `json:"tokens"`
+}
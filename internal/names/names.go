@@ -0,0 +1,153 @@
+// Package names resolves human-readable account names to addresses: ENS
+// names through the on-chain registry on Ethereum mainnet and .sol names
+// through the Solana Name Service.
+package names
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/httpx"
+	"github.com/ggonzalez94/defi-cli/internal/registry"
+)
+
+// Naming services.
+const (
+	ServiceENS = "ens"
+	ServiceSNS = "sns"
+)
+
+var (
+	registryABI = mustABI(registry.ENSRegistryABI)
+	resolverABI = mustABI(registry.ENSResolverABI)
+)
+
+// Service reports which naming service handles value, or "" when value is
+// not a name. Names are dotted labels; anything ending in .sol goes to SNS and
+// every other dotted name (.eth and DNS names imported into ENS) to ENS.
+func Service(value string) string {
+	name := Normalize(value)
+	if name == "" || !strings.Contains(name, ".") {
+		return ""
+	}
+	for _, label := range strings.Split(name, ".") {
+		if label == "" || strings.ContainsAny(label, " /:?#%@") {
+			return ""
+		}
+	}
+	if strings.HasSuffix(name, ".sol") {
+		return ServiceSNS
+	}
+	return ServiceENS
+}
+
+// IsName reports whether value looks like an ENS or SNS name.
+func IsName(value string) bool {
+	return Service(value) != ""
+}
+
+// Normalize trims and lowercases a name. Full ENSIP-15 normalization (emoji,
+// confusables) is out of scope; names outside ASCII should be passed already
+// normalized.
+func Normalize(value string) string {
+	return strings.ToLower(strings.TrimSpace(value))
+}
+
+// Namehash computes the ENS node for name (EIP-137).
+func Namehash(name string) common.Hash {
+	var node common.Hash
+	name = Normalize(name)
+	if name == "" {
+		return node
+	}
+	labels := strings.Split(name, ".")
+	for i := len(labels) - 1; i >= 0; i-- {
+		labelHash := crypto.Keccak256([]byte(labels[i]))
+		node = common.BytesToHash(crypto.Keccak256(node.Bytes(), labelHash))
+	}
+	return node
+}
+
+// ResolveENS looks up the resolver for name in the mainnet ENS registry and
+// returns its addr record. caller must be connected to Ethereum mainnet.
+// Wildcard (ENSIP-10) and offchain (CCIP-read) resolvers are not followed.
+func ResolveENS(ctx context.Context, caller ethereum.ContractCaller, name string) (common.Address, error) {
+	name = Normalize(name)
+	node := Namehash(name)
+	resolver, err := callAddress(ctx, caller, common.HexToAddress(registry.ENSRegistryAddress), registryABI, "resolver", node)
+	if err != nil {
+		return common.Address{}, clierr.Wrap(clierr.CodeUnavailable, fmt.Sprintf("look up ENS resolver for %s", name), err)
+	}
+	if resolver == (common.Address{}) {
+		return common.Address{}, clierr.New(clierr.CodeUsage, fmt.Sprintf("ENS name %s is not registered or has no resolver", name))
+	}
+	addr, err := callAddress(ctx, caller, resolver, resolverABI, "addr", node)
+	if err != nil {
+		return common.Address{}, clierr.Wrap(clierr.CodeUnavailable, fmt.Sprintf("read ENS address record for %s", name), err)
+	}
+	if addr == (common.Address{}) {
+		return common.Address{}, clierr.New(clierr.CodeUsage, fmt.Sprintf("ENS name %s has no address record", name))
+	}
+	return addr, nil
+}
+
+func callAddress(ctx context.Context, caller ethereum.ContractCaller, target common.Address, contract abi.ABI, method string, node common.Hash) (common.Address, error) {
+	data, err := contract.Pack(method, node)
+	if err != nil {
+		return common.Address{}, err
+	}
+	out, err := caller.CallContract(ctx, ethereum.CallMsg{To: &target, Data: data}, nil)
+	if err != nil {
+		return common.Address{}, err
+	}
+	values, err := contract.Unpack(method, out)
+	if err != nil || len(values) != 1 {
+		return common.Address{}, fmt.Errorf("decode %s result: %v", method, err)
+	}
+	addr, ok := values[0].(common.Address)
+	if !ok {
+		return common.Address{}, fmt.Errorf("decode %s result: unexpected type %T", method, values[0])
+	}
+	return addr, nil
+}
+
+// ResolveSNS resolves a .sol name to the owner's Solana address through the
+// SNS proxy at baseURL (registry.SNSProxyBaseURL when empty).
+func ResolveSNS(ctx context.Context, client *httpx.Client, baseURL, name string) (string, error) {
+	name = Normalize(name)
+	if strings.TrimSpace(baseURL) == "" {
+		baseURL = registry.SNSProxyBaseURL
+	}
+	endpoint := strings.TrimRight(baseURL, "/") + "/resolve/" + url.PathEscape(strings.TrimSuffix(name, ".sol"))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return "", clierr.Wrap(clierr.CodeInternal, "build SNS request", err)
+	}
+	var resp struct {
+		S      string `json:"s"`
+		Result string `json:"result"`
+	}
+	if _, err := client.DoJSON(ctx, req, &resp); err != nil {
+		return "", err
+	}
+	if resp.S != "ok" || strings.TrimSpace(resp.Result) == "" {
+		return "", clierr.New(clierr.CodeUsage, fmt.Sprintf("SNS name %s could not be resolved", name))
+	}
+	return strings.TrimSpace(resp.Result), nil
+}
+
+func mustABI(raw string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(raw))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
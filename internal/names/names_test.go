@@ -0,0 +1,96 @@
+package names
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/httpx"
+	"github.com/ggonzalez94/defi-cli/internal/registry"
+)
+
+type fakeENS struct {
+	resolvers map[common.Hash]common.Address
+	addrs     map[common.Hash]common.Address
+}
+
+func (f fakeENS) CallContract(_ context.Context, msg ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	node := common.BytesToHash(msg.Data[4:36])
+	if *msg.To == common.HexToAddress(registry.ENSRegistryAddress) {
+		return registryABI.Methods["resolver"].Outputs.Pack(f.resolvers[node])
+	}
+	return resolverABI.Methods["addr"].Outputs.Pack(f.addrs[node])
+}
+
+func TestServiceAndNamehash(t *testing.T) {
+	cases := map[string]string{
+		"vitalik.eth":   ServiceENS,
+		" Vitalik.ETH ": ServiceENS,
+		"nick.xyz":      ServiceENS,
+		"bonfida.sol":   ServiceSNS,
+		"0x000000000000000000000000000000000000dEaD": "",
+		"vitalik":       "",
+		"vitalik..eth":  "",
+		"https://x.eth": "",
+	}
+	for input, want := range cases {
+		if got := Service(input); got != want {
+			t.Fatalf("Service(%q) = %q, want %q", input, got, want)
+		}
+	}
+
+	if got := Namehash("eth").Hex(); got != "0x93cdeb708b7545dc668eb9280176169d1c33cfd8ed6f04690a0bcc88a93fc4ae" {
+		t.Fatalf("unexpected namehash(eth): %s", got)
+	}
+	if got := Namehash("foo.eth").Hex(); got != "0xde9b09fd7c5f901e23a3f19fecc54828e9c848539801e86591bd9801b019f84f" {
+		t.Fatalf("unexpected namehash(foo.eth): %s", got)
+	}
+}
+
+func TestResolveENS(t *testing.T) {
+	resolver := common.HexToAddress("0x231b0Ee14048e9dCcD1d247744d114a4EB5E8E63")
+	owner := common.HexToAddress("0xd8dA6BF26964aF9D7eEd9e03E53415D37aA96045")
+	caller := fakeENS{
+		resolvers: map[common.Hash]common.Address{Namehash("vitalik.eth"): resolver, Namehash("empty.eth"): resolver},
+		addrs:     map[common.Hash]common.Address{Namehash("vitalik.eth"): owner},
+	}
+
+	got, err := ResolveENS(context.Background(), caller, "Vitalik.eth")
+	if err != nil || got != owner {
+		t.Fatalf("expected %s, got %s err=%v", owner.Hex(), got.Hex(), err)
+	}
+	for _, name := range []string{"unregistered.eth", "empty.eth"} {
+		_, err := ResolveENS(context.Background(), caller, name)
+		if cErr, ok := clierr.As(err); !ok || cErr.Code != clierr.CodeUsage {
+			t.Fatalf("expected usage error for %s, got %v", name, err)
+		}
+	}
+}
+
+func TestResolveSNS(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/resolve/bonfida":
+			_, _ = w.Write([]byte(`{"s":"ok","result":"HKKp49qGWXd639QsuH7JiLijfVW5UtCVY4s1n2HANwEA"}`))
+		default:
+			_, _ = w.Write([]byte(`{"s":"error","result":"Invalid domain input"}`))
+		}
+	}))
+	defer srv.Close()
+
+	client := httpx.New(5*time.Second, 0)
+	got, err := ResolveSNS(context.Background(), client, srv.URL, "bonfida.sol")
+	if err != nil || got != "HKKp49qGWXd639QsuH7JiLijfVW5UtCVY4s1n2HANwEA" {
+		t.Fatalf("unexpected resolution %q err=%v", got, err)
+	}
+	_, err = ResolveSNS(context.Background(), client, srv.URL, "missing.sol")
+	if cErr, ok := clierr.As(err); !ok || cErr.Code != clierr.CodeUsage {
+		t.Fatalf("expected usage error, got %v", err)
+	}
+}
@@ -0,0 +1,78 @@
+// Package notify delivers action status transitions to webhooks.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Delivery headers. The signature is present only when the webhook has a
+// secret.
+const (
+	HeaderEvent          = "X-Defi-Event"
+	HeaderPreviousStatus = "X-Defi-Previous-Status"
+	HeaderTimestamp      = "X-Defi-Timestamp"
+	HeaderSignature      = "X-Defi-Signature"
+)
+
+// Timeout bounds one delivery. It is kept short because deliveries run
+// inline with the save that triggered them.
+const Timeout = 3 * time.Second
+
+// Delivery is one transition posted to one webhook. Body is the JSON
+// envelope; Event names the transition, for example action.completed.
+type Delivery struct {
+	URL            string
+	Secret         string
+	Event          string
+	PreviousStatus string
+	Body           []byte
+	At             time.Time
+}
+
+// Sign returns the X-Defi-Signature value for d sent at timestamp: "sha256="
+// and the hex HMAC-SHA256 of "<timestamp>.<event>.<previous status>.<body>"
+// keyed by d.Secret, so every header a receiver acts on is covered. Receivers
+// recompute it and should reject old timestamps to stop replays.
+func Sign(d Delivery, timestamp int64) string {
+	mac := hmac.New(sha256.New, []byte(d.Secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "." + d.Event + "." + d.PreviousStatus + "."))
+	mac.Write(d.Body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Post sends d and fails on transport errors and non-2xx responses.
+func Post(ctx context.Context, client *http.Client, d Delivery) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.URL, bytes.NewReader(d.Body))
+	if err != nil {
+		return fmt.Errorf("build webhook request: %w", err)
+	}
+	timestamp := d.At.Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, d.Event)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	if d.PreviousStatus != "" {
+		req.Header.Set(HeaderPreviousStatus, d.PreviousStatus)
+	}
+	if d.Secret != "" {
+		req.Header.Set(HeaderSignature, Sign(d, timestamp))
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("post webhook: %w", err)
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("post webhook: endpoint returned status %d", resp.StatusCode)
+	}
+	return nil
+}
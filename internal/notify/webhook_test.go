@@ -0,0 +1,113 @@
+package notify
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSign(t *testing.T) {
+	d := Delivery{Secret: "hook-secret", Event: "action.completed", PreviousStatus: "running", Body: []byte(`{"success":true}`)}
+	got := Sign(d, 1700000000)
+	if !regexp.MustCompile(`^sha256=[0-9a-f]{64}$`).MatchString(got) {
+		t.Fatalf("unexpected signature format %q", got)
+	}
+	mac := hmac.New(sha256.New, []byte("hook-secret"))
+	mac.Write([]byte(`1700000000.action.completed.running.{"success":true}`))
+	if want := "sha256=" + hex.EncodeToString(mac.Sum(nil)); got != want {
+		t.Fatalf("unexpected signature %q, want %q", got, want)
+	}
+
+	tampered := d
+	tampered.PreviousStatus = "planned"
+	if Sign(tampered, 1700000000) == got {
+		t.Fatal("expected the previous status to be covered by the signature")
+	}
+	if Sign(d, 1700000001) == got {
+		t.Fatal("expected the timestamp to be covered by the signature")
+	}
+}
+
+func TestPostSendsSignedDelivery(t *testing.T) {
+	var header http.Header
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	d := Delivery{
+		URL:            server.URL,
+		Secret:         "hook-secret",
+		Event:          "action.failed",
+		PreviousStatus: "running",
+		Body:           []byte(`{"success":true}`),
+		At:             time.Unix(1700000000, 0),
+	}
+	if err := Post(context.Background(), server.Client(), d); err != nil {
+		t.Fatalf("Post failed: %v", err)
+	}
+	if string(body) != string(d.Body) || header.Get("Content-Type") != "application/json" {
+		t.Fatalf("unexpected request body=%s header=%v", body, header)
+	}
+	if header.Get(HeaderEvent) != "action.failed" || header.Get(HeaderPreviousStatus) != "running" || header.Get(HeaderTimestamp) != "1700000000" {
+		t.Fatalf("unexpected delivery headers: %v", header)
+	}
+	if header.Get(HeaderSignature) != Sign(d, 1700000000) {
+		t.Fatalf("unexpected signature %q", header.Get(HeaderSignature))
+	}
+
+	d.Secret = ""
+	d.PreviousStatus = ""
+	if err := Post(context.Background(), server.Client(), d); err != nil {
+		t.Fatalf("Post failed: %v", err)
+	}
+	if header.Get(HeaderSignature) != "" || header.Get(HeaderPreviousStatus) != "" {
+		t.Fatalf("expected no signature or previous status headers, got %v", header)
+	}
+}
+
+func TestPostRejectsNon2xx(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "nope", http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	err := Post(context.Background(), server.Client(), Delivery{URL: server.URL, Event: "action.planned", Body: []byte(`{}`), At: time.Now()})
+	if err == nil || !strings.Contains(err.Error(), "status 502") {
+		t.Fatalf("expected non-2xx error, got %v", err)
+	}
+}
+
+func TestPostTimesOut(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	started := time.Now()
+	err := Post(ctx, server.Client(), Delivery{URL: server.URL, Event: "action.planned", Body: []byte(`{}`), At: time.Now()})
+	if err == nil {
+		t.Fatal("expected a slow endpoint to time out")
+	}
+	if elapsed := time.Since(started); elapsed > 2*time.Second {
+		t.Fatalf("expected Post to return at the deadline, took %s", elapsed)
+	}
+}
@@ -0,0 +1,156 @@
+package out
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+)
+
+// FieldAbbreviations maps JSON field names to the short keys emitted by
+// --compact. Fields not listed keep their original name. The map is part of
+// the output contract; entries may be added but never reassigned.
+var FieldAbbreviations = map[string]string{
+	"account_address":         "acct",
+	"amount_usd":              "amt_usd",
+	"apy_base":                "apy_b",
+	"apy_effective":           "apy_e",
+	"apy_reward":              "apy_r",
+	"apy_total":               "apy",
+	"asset_id":                "aid",
+	"backing_assets":          "backing",
+	"borrow_apy":              "b_apy",
+	"borrow_apy_effective":    "b_apy_e",
+	"chain_id":                "cid",
+	"change_1d_pct":           "chg_1d",
+	"change_1m_pct":           "chg_1m",
+	"change_7d_pct":           "chg_7d",
+	"circulating_usd":         "circ_usd",
+	"decimals":                "dec",
+	"estimated_out":           "out",
+	"exit_liquidity":          "exit",
+	"fetched_at":              "at",
+	"from_asset_id":           "from_aid",
+	"input_amount":            "in",
+	"latency_ms":              "ms",
+	"liquidity_usd":           "liq_usd",
+	"opportunity_id":          "oid",
+	"peg_deviation_pct":       "peg_dev",
+	"position_type":           "pos",
+	"provider":                "prov",
+	"provider_native_id":      "pnid",
+	"provider_native_id_kind": "pnid_kind",
+	"providers":               "provs",
+	"request_id":              "rid",
+	"source_url":              "src",
+	"supply_apy":              "s_apy",
+	"supply_apy_effective":    "s_apy_e",
+	"symbol":                  "sym",
+	"timestamp":               "ts",
+	"to_asset_id":             "to_aid",
+	"to_chain_id":             "to_cid",
+	"trade_type":              "tt",
+	"tvl_usd":                 "tvl",
+}
+
+// EnumAbbreviations maps enum values to short forms, keyed by the original
+// field name the value appears under.
+var EnumAbbreviations = map[string]map[string]string{
+	"status": {
+		"auth_error":   "auth",
+		"completed":    "done",
+		"confirmed":    "conf",
+		"rate_limited": "rl",
+		"submitted":    "sub",
+		"unavailable":  "unavail",
+	},
+	"position_type": {
+		"borrow":     "b",
+		"collateral": "c",
+		"supply":     "s",
+	},
+	"trade_type": {
+		"exact-input":  "ei",
+		"exact-output": "eo",
+	},
+}
+
+// compactEnvelopeKeys are kept even when their value is zero so that
+// success/failure and the envelope shape stay unambiguous.
+var compactEnvelopeKeys = map[string]bool{"success": true, "version": true}
+
+// compactValue strips null, zero, and empty values from a normalized JSON
+// value, abbreviates known field names and enum values, and gzips arrays
+// whose JSON encoding exceeds gzipMinBytes (disabled when <= 0).
+func compactValue(v any, gzipMinBytes int) any {
+	out, _ := compactNode(v, "", gzipMinBytes, true)
+	return out
+}
+
+func compactNode(v any, field string, gzipMinBytes int, top bool) (any, bool) {
+	switch t := v.(type) {
+	case nil:
+		return nil, false
+	case string:
+		if t == "" {
+			return nil, false
+		}
+		if short, ok := EnumAbbreviations[field][t]; ok {
+			return short, true
+		}
+		return t, true
+	case float64:
+		return t, t != 0
+	case bool:
+		return t, true
+	case map[string]any:
+		out := make(map[string]any, len(t))
+		for key, item := range t {
+			value, keep := compactNode(item, key, gzipMinBytes, false)
+			if !keep && !(top && compactEnvelopeKeys[key]) {
+				continue
+			}
+			if !keep {
+				value = item
+			}
+			if short, ok := FieldAbbreviations[key]; ok {
+				key = short
+			}
+			out[key] = value
+		}
+		return out, len(out) > 0
+	case []any:
+		out := make([]any, 0, len(t))
+		for _, item := range t {
+			// Elements keep their position; only the outermost large array
+			// is gzipped.
+			value, _ := compactNode(item, "", 0, false)
+			out = append(out, value)
+		}
+		if gzipMinBytes > 0 {
+			if packed, ok := gzipArray(out, gzipMinBytes); ok {
+				return packed, true
+			}
+		}
+		return out, top || len(out) > 0
+	default:
+		return t, true
+	}
+}
+
+// gzipArray replaces a large array with {"gz": base64(gzip(json)), "n": len}.
+func gzipArray(items []any, minBytes int) (map[string]any, bool) {
+	raw, err := json.Marshal(items)
+	if err != nil || len(raw) < minBytes {
+		return nil, false
+	}
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(raw); err != nil {
+		return nil, false
+	}
+	if err := zw.Close(); err != nil {
+		return nil, false
+	}
+	return map[string]any{"gz": base64.StdEncoding.EncodeToString(buf.Bytes()), "n": len(items)}, true
+}
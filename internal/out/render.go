@@ -6,10 +6,14 @@ import (
 	"io"
 	"reflect"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/ggonzalez94/defi-cli/internal/config"
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/i18n"
 	"github.com/ggonzalez94/defi-cli/internal/model"
+	"github.com/jmespath/go-jmespath"
 )
 
 func Render(w io.Writer, env model.Envelope, settings config.Settings) error {
@@ -17,6 +21,17 @@ func Render(w io.Writer, env model.Envelope, settings config.Settings) error {
 	if len(settings.SelectFields) > 0 {
 		data = project(data, settings.SelectFields)
 	}
+	if settings.Query != "" {
+		queried, err := query(data, settings.Query)
+		if err != nil {
+			return err
+		}
+		data = queried
+	}
+
+	if settings.Compact {
+		return renderCompact(w, env, data, settings)
+	}
 
 	if settings.ResultsOnly {
 		if settings.OutputMode == "json" {
@@ -24,7 +39,7 @@ func Render(w io.Writer, env model.Envelope, settings config.Settings) error {
 			enc.SetIndent("", "  ")
 			return enc.Encode(data)
 		}
-		return renderPlain(w, data)
+		return renderPlain(w, data, settings.Lang)
 	}
 
 	if settings.OutputMode == "json" {
@@ -34,6 +49,7 @@ func Render(w io.Writer, env model.Envelope, settings config.Settings) error {
 		return enc.Encode(env)
 	}
 
+	// Envelope keys are localized by toLine like any other top-level key.
 	plain := map[string]any{
 		"success":  env.Success,
 		"data":     data,
@@ -43,13 +59,30 @@ func Render(w io.Writer, env model.Envelope, settings config.Settings) error {
 	if env.Error != nil {
 		plain["error"] = env.Error
 	}
-	return renderPlain(w, plain)
+	return renderPlain(w, plain, settings.Lang)
 }
 
-func renderPlain(w io.Writer, data any) error {
+// renderCompact writes the minimal-output form used by --compact: no
+// indentation, zero values stripped, and abbreviated keys and enum values.
+func renderCompact(w io.Writer, env model.Envelope, data any, settings config.Settings) error {
+	var payload any = data
+	if !settings.ResultsOnly {
+		env.Data = data
+		payload = env
+	}
+	compacted := compactValue(normalizeValue(payload), settings.CompactGzipBytes)
+	if settings.OutputMode == "json" {
+		return json.NewEncoder(w).Encode(compacted)
+	}
+	return renderPlain(w, compacted, settings.Lang)
+}
+
+// renderPlain writes one key=value line per item. Top-level keys and boolean
+// values are translated into lang; nested values and JSON keys are not.
+func renderPlain(w io.Writer, data any, lang string) error {
 	v := reflect.ValueOf(data)
 	if !v.IsValid() {
-		_, err := fmt.Fprintln(w, "null")
+		_, err := fmt.Fprintln(w, i18n.T(lang, "null"))
 		return err
 	}
 
@@ -57,7 +90,7 @@ func renderPlain(w io.Writer, data any) error {
 	case reflect.Slice, reflect.Array:
 		for i := 0; i < v.Len(); i++ {
 			item := normalizeValue(v.Index(i).Interface())
-			line, err := toLine(item)
+			line, err := toLine(item, lang)
 			if err != nil {
 				return err
 			}
@@ -66,12 +99,12 @@ func renderPlain(w io.Writer, data any) error {
 			}
 		}
 		if v.Len() == 0 {
-			_, err := fmt.Fprintln(w, "[]")
+			_, err := fmt.Fprintln(w, i18n.T(lang, "empty"))
 			return err
 		}
 		return nil
 	default:
-		line, err := toLine(normalizeValue(data))
+		line, err := toLine(normalizeValue(data), lang)
 		if err != nil {
 			return err
 		}
@@ -110,6 +143,15 @@ func projectMap(m map[string]any, fields []string) map[string]any {
 	return out
 }
 
+// query evaluates a JMESPath expression against the JSON form of data.
+func query(data any, expr string) (any, error) {
+	result, err := jmespath.Search(expr, normalizeValue(data))
+	if err != nil {
+		return nil, clierr.Wrap(clierr.CodeUsage, "evaluate --query", err)
+	}
+	return result, nil
+}
+
 func normalizeValue(v any) any {
 	buf, err := json.Marshal(v)
 	if err != nil {
@@ -122,7 +164,7 @@ func normalizeValue(v any) any {
 	return out
 }
 
-func toLine(v any) (string, error) {
+func toLine(v any, lang string) (string, error) {
 	switch t := v.(type) {
 	case map[string]any:
 		keys := make([]string, 0, len(t))
@@ -132,7 +174,11 @@ func toLine(v any) (string, error) {
 		sort.Strings(keys)
 		parts := make([]string, 0, len(keys))
 		for _, k := range keys {
-			parts = append(parts, fmt.Sprintf("%s=%v", k, t[k]))
+			value := t[k]
+			if b, ok := value.(bool); ok {
+				value = i18n.T(lang, strconv.FormatBool(b))
+			}
+			parts = append(parts, fmt.Sprintf("%s=%v", i18n.T(lang, k), value))
 		}
 		return strings.Join(parts, " "), nil
 	default:
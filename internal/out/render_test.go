@@ -2,6 +2,8 @@ package out
 
 import (
 	"bytes"
+	"compress/gzip"
+	"encoding/base64"
 	"encoding/json"
 	"strings"
 	"testing"
@@ -35,6 +37,47 @@ func TestRenderJSONSelectResultsOnly(t *testing.T) {
 	}
 }
 
+func TestRenderJSONQueryFiltersAndProjects(t *testing.T) {
+	env := model.Envelope{
+		Version: "v1",
+		Success: true,
+		Data: []map[string]any{
+			{"provider": "aave", "apy": 4.2, "asset": map[string]any{"symbol": "USDC"}},
+			{"provider": "morpho", "apy": 6.1, "asset": map[string]any{"symbol": "USDC"}},
+		},
+		Meta: model.EnvelopeMeta{Timestamp: time.Now()},
+	}
+	settings := config.Settings{OutputMode: "json", Query: "[?apy > `5`].{provider: provider, symbol: asset.symbol}"}
+	var buf bytes.Buffer
+	if err := Render(&buf, env, settings); err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	var out struct {
+		Success bool             `json:"success"`
+		Data    []map[string]any `json:"data"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("json decode failed: %v", err)
+	}
+	if !out.Success || len(out.Data) != 1 || out.Data[0]["provider"] != "morpho" || out.Data[0]["symbol"] != "USDC" {
+		t.Fatalf("unexpected output: %s", buf.String())
+	}
+
+	settings = config.Settings{OutputMode: "json", ResultsOnly: true, Query: "max_by(@, &apy).provider"}
+	buf.Reset()
+	if err := Render(&buf, env, settings); err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if strings.TrimSpace(buf.String()) != `"morpho"` {
+		t.Fatalf("unexpected scalar output: %s", buf.String())
+	}
+
+	settings.Query = "abs(@)"
+	if err := Render(&buf, env, settings); err == nil {
+		t.Fatal("expected a type error from abs over an array")
+	}
+}
+
 func TestRenderPlain(t *testing.T) {
 	env := model.Envelope{
 		Version: "v1",
@@ -51,3 +94,110 @@ func TestRenderPlain(t *testing.T) {
 		t.Fatalf("unexpected plain output: %s", buf.String())
 	}
 }
+
+func TestRenderPlainLocalizesLabelsOnly(t *testing.T) {
+	env := model.Envelope{
+		Version: "v1",
+		Success: true,
+		Data:    []map[string]any{{"provider": "aave", "paused": false}},
+		Meta:    model.EnvelopeMeta{Timestamp: time.Now()},
+	}
+	var buf bytes.Buffer
+	if err := Render(&buf, env, config.Settings{OutputMode: "plain", ResultsOnly: true, Lang: "es"}); err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if got := strings.TrimSpace(buf.String()); got != "paused=no proveedor=aave" {
+		t.Fatalf("unexpected localized plain output: %q", got)
+	}
+
+	buf.Reset()
+	if err := Render(&buf, env, config.Settings{OutputMode: "json", ResultsOnly: true, Lang: "ja"}); err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), `"provider": "aave"`) || !strings.Contains(buf.String(), `"paused": false`) {
+		t.Fatalf("json output must not be localized: %s", buf.String())
+	}
+
+	buf.Reset()
+	env.Data = []map[string]any{}
+	if err := Render(&buf, env, config.Settings{OutputMode: "plain", ResultsOnly: true, Lang: "zh"}); err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if strings.TrimSpace(buf.String()) != "（无）" {
+		t.Fatalf("unexpected empty output: %q", buf.String())
+	}
+}
+
+func TestRenderCompactStripsZeroValuesAndAbbreviates(t *testing.T) {
+	env := model.Envelope{
+		Version: "v1",
+		Success: true,
+		Data: []map[string]any{
+			{"opportunity_id": "x", "apy_total": 4.2, "tvl_usd": 0, "position_type": "supply", "note": "", "extra": nil},
+		},
+		Meta: model.EnvelopeMeta{Timestamp: time.Now()},
+	}
+	var buf bytes.Buffer
+	if err := Render(&buf, env, config.Settings{OutputMode: "json", Compact: true, ResultsOnly: true}); err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if strings.Count(buf.String(), "\n") != 1 {
+		t.Fatalf("expected single-line output, got %q", buf.String())
+	}
+	var out []map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("json decode failed: %v", err)
+	}
+	want := map[string]any{"oid": "x", "apy": 4.2, "pos": "s"}
+	if len(out) != 1 || len(out[0]) != len(want) {
+		t.Fatalf("unexpected compact output: %s", buf.String())
+	}
+	for k, v := range want {
+		if out[0][k] != v {
+			t.Fatalf("expected %s=%v, got %s", k, v, buf.String())
+		}
+	}
+}
+
+func TestRenderCompactKeepsEnvelopeShapeAndGzipsArrays(t *testing.T) {
+	rows := make([]map[string]any, 0, 50)
+	for i := 0; i < 50; i++ {
+		rows = append(rows, map[string]any{"symbol": "USDC", "rank": i + 1})
+	}
+	env := model.Envelope{
+		Version: "v1",
+		Success: false,
+		Data:    rows,
+		Meta:    model.EnvelopeMeta{Timestamp: time.Now()},
+	}
+	var buf bytes.Buffer
+	if err := Render(&buf, env, config.Settings{OutputMode: "json", Compact: true, CompactGzipBytes: 256}); err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	var out map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("json decode failed: %v", err)
+	}
+	if out["success"] != false || out["version"] != "v1" {
+		t.Fatalf("expected envelope keys to be kept, got %s", buf.String())
+	}
+	packed, ok := out["data"].(map[string]any)
+	if !ok || packed["n"] != float64(50) {
+		t.Fatalf("expected gzipped data array, got %s", buf.String())
+	}
+	raw, err := base64.StdEncoding.DecodeString(packed["gz"].(string))
+	if err != nil {
+		t.Fatalf("decode base64: %v", err)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatalf("gzip reader: %v", err)
+	}
+	var decoded []map[string]any
+	if err := json.NewDecoder(zr).Decode(&decoded); err != nil {
+		t.Fatalf("decode gzipped array: %v", err)
+	}
+	if len(decoded) != 50 || decoded[0]["sym"] != "USDC" {
+		t.Fatalf("unexpected decoded rows: %+v", decoded[:1])
+	}
+}
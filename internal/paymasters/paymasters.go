@@ -0,0 +1,204 @@
+// Package paymasters holds the ERC-4337 paymaster catalog used by
+// `aa paymasters`: curated public services plus paymasters declared in
+// config.
+package paymasters
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ggonzalez94/defi-cli/internal/config"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/model"
+)
+
+// Sponsorship modes.
+const (
+	// ModeSponsored paymasters cover gas for the user under a policy owned
+	// by the dapp or account operator.
+	ModeSponsored = "sponsored"
+	// ModeERC20 paymasters front gas and charge the user in an ERC-20 token.
+	ModeERC20 = "erc20"
+)
+
+// Catalog sources.
+const (
+	SourceKnown      = "known"
+	SourceConfigured = "configured"
+)
+
+// Canonical EntryPoint deployments.
+const (
+	EntryPointV06 = "0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789"
+	EntryPointV07 = "0x0000000071727De22E5E9d8BAf0edAc6f37da032"
+	EntryPointV08 = "0x4337084D9E255Ff0702461CF8895CE9E3b5Ff108"
+)
+
+// datasetAsOf is when the known catalog was last checked against provider
+// documentation.
+const datasetAsOf = "2026-10-01"
+
+type entry struct {
+	Name           string
+	Provider       string
+	Mode           string
+	Policy         string
+	FeeTokens      []string
+	EntryPoints    []string
+	Chains         []int64
+	RequiresAPIKey bool
+	Notes          string
+}
+
+// known is the bundled catalog. Paymaster contract addresses are omitted for
+// hosted services because the provider endpoint returns them per request.
+var known = []entry{
+	{
+		Name:        "circle-paymaster",
+		Provider:    "circle",
+		Mode:        ModeERC20,
+		Policy:      "permissionless; user authorizes the USDC fee with an EIP-2612 permit",
+		FeeTokens:   []string{"USDC"},
+		EntryPoints: []string{EntryPointV07, EntryPointV08},
+		Chains:      []int64{1, 10, 137, 8453, 42161, 43114},
+		Notes:       "no API key; fee includes a small surcharge over gas cost",
+	},
+	{
+		Name:           "pimlico-verifying",
+		Provider:       "pimlico",
+		Mode:           ModeSponsored,
+		Policy:         "sponsorship policies set in the Pimlico dashboard (spend caps, allowlisted senders)",
+		FeeTokens:      []string{},
+		EntryPoints:    []string{EntryPointV06, EntryPointV07},
+		Chains:         []int64{1, 10, 56, 100, 137, 8453, 42161, 43114, 59144},
+		RequiresAPIKey: true,
+	},
+	{
+		Name:           "pimlico-erc20",
+		Provider:       "pimlico",
+		Mode:           ModeERC20,
+		Policy:         "permissionless; user approves the paymaster for the fee token",
+		FeeTokens:      []string{"USDC", "USDT"},
+		EntryPoints:    []string{EntryPointV06, EntryPointV07},
+		Chains:         []int64{1, 10, 137, 8453, 42161},
+		RequiresAPIKey: true,
+	},
+	{
+		Name:           "coinbase-cdp",
+		Provider:       "coinbase",
+		Mode:           ModeSponsored,
+		Policy:         "allowlisted contracts with per-user and global spend limits set in the CDP portal",
+		FeeTokens:      []string{},
+		EntryPoints:    []string{EntryPointV06, EntryPointV07},
+		Chains:         []int64{8453},
+		RequiresAPIKey: true,
+	},
+	{
+		Name:           "alchemy-gas-manager",
+		Provider:       "alchemy",
+		Mode:           ModeSponsored,
+		Policy:         "Gas Manager policies with spend limits, sender allowlists, and expiry",
+		FeeTokens:      []string{},
+		EntryPoints:    []string{EntryPointV06, EntryPointV07},
+		Chains:         []int64{1, 10, 137, 8453, 42161},
+		RequiresAPIKey: true,
+	},
+}
+
+// ForChain lists configured paymasters followed by known ones that support
+// chain. Fee tokens resolve to asset IDs when the chain registry knows them.
+func ForChain(chain id.Chain, configured []config.Paymaster) ([]model.Paymaster, error) {
+	out := make([]model.Paymaster, 0, len(configured)+len(known))
+	for _, cfg := range configured {
+		supported, err := configuredOnChain(cfg, chain)
+		if err != nil {
+			return nil, err
+		}
+		if !supported {
+			continue
+		}
+		out = append(out, model.Paymaster{
+			Name:               cfg.Name,
+			Provider:           firstNonEmpty(cfg.Provider, cfg.Name),
+			ChainID:            chain.CAIP2,
+			Source:             SourceConfigured,
+			Mode:               strings.ToLower(strings.TrimSpace(cfg.Mode)),
+			Policy:             cfg.Policy,
+			FeeTokens:          feeTokens(cfg.FeeTokens, chain),
+			Address:            cfg.Address,
+			EntryPoints:        nonNil(cfg.EntryPoints),
+			EndpointConfigured: strings.TrimSpace(cfg.URL) != "",
+		})
+	}
+
+	for _, item := range known {
+		if !supportsChain(item.Chains, chain.EVMChainID) {
+			continue
+		}
+		out = append(out, model.Paymaster{
+			Name:           item.Name,
+			Provider:       item.Provider,
+			ChainID:        chain.CAIP2,
+			Source:         SourceKnown,
+			Mode:           item.Mode,
+			Policy:         item.Policy,
+			FeeTokens:      feeTokens(item.FeeTokens, chain),
+			EntryPoints:    append([]string{}, item.EntryPoints...),
+			RequiresAPIKey: item.RequiresAPIKey,
+			Notes:          item.Notes,
+			AsOf:           datasetAsOf,
+		})
+	}
+	return out, nil
+}
+
+func configuredOnChain(cfg config.Paymaster, chain id.Chain) (bool, error) {
+	for _, raw := range cfg.Chains {
+		parsed, err := id.ParseChain(raw)
+		if err != nil {
+			return false, fmt.Errorf("paymaster %s: %w", cfg.Name, err)
+		}
+		if parsed.CAIP2 == chain.CAIP2 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func feeTokens(symbols []string, chain id.Chain) []model.PaymasterToken {
+	out := make([]model.PaymasterToken, 0, len(symbols))
+	for _, symbol := range symbols {
+		token := model.PaymasterToken{Symbol: symbol}
+		if asset, err := id.ParseAsset(symbol, chain); err == nil {
+			token.Symbol = asset.Symbol
+			token.AssetID = asset.AssetID
+		}
+		out = append(out, token)
+	}
+	return out
+}
+
+func supportsChain(chains []int64, chainID int64) bool {
+	for _, candidate := range chains {
+		if candidate == chainID {
+			return true
+		}
+	}
+	return false
+}
+
+func nonNil(items []string) []string {
+	if items == nil {
+		return []string{}
+	}
+	return items
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,67 @@
+package paymasters
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ggonzalez94/defi-cli/internal/config"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+)
+
+func TestForChainListsKnownPaymastersWithResolvedFeeTokens(t *testing.T) {
+	chain, err := id.ParseChain("base")
+	if err != nil {
+		t.Fatalf("parse chain: %v", err)
+	}
+	items, err := ForChain(chain, nil)
+	if err != nil {
+		t.Fatalf("ForChain failed: %v", err)
+	}
+	byName := map[string]int{}
+	for i, item := range items {
+		byName[item.Name] = i
+		if item.Source != SourceKnown || item.ChainID != "eip155:8453" || item.AsOf == "" || len(item.EntryPoints) == 0 {
+			t.Fatalf("unexpected known paymaster: %+v", item)
+		}
+	}
+	if _, ok := byName["coinbase-cdp"]; !ok {
+		t.Fatalf("expected coinbase-cdp on base, got %+v", items)
+	}
+	circle := items[byName["circle-paymaster"]]
+	if circle.Mode != ModeERC20 || len(circle.FeeTokens) != 1 || !strings.HasPrefix(circle.FeeTokens[0].AssetID, "eip155:8453/erc20:0x") {
+		t.Fatalf("expected resolved USDC fee token, got %+v", circle)
+	}
+}
+
+func TestForChainIncludesConfiguredPaymastersFirst(t *testing.T) {
+	chain, err := id.ParseChain("ethereum")
+	if err != nil {
+		t.Fatalf("parse chain: %v", err)
+	}
+	items, err := ForChain(chain, []config.Paymaster{
+		{Name: "team-sponsor", Chains: []string{"base"}, Mode: "sponsored", URL: "https://example.com/rpc"},
+		{Name: "team-usdc", Chains: []string{"1", "base"}, Mode: "ERC20", FeeTokens: []string{"USDC", "NOTATOKEN"}, URL: "https://example.com/rpc"},
+	})
+	if err != nil {
+		t.Fatalf("ForChain failed: %v", err)
+	}
+	first := items[0]
+	if first.Name != "team-usdc" || first.Source != SourceConfigured || first.Mode != ModeERC20 || !first.EndpointConfigured {
+		t.Fatalf("expected configured paymaster first, got %+v", first)
+	}
+	if first.FeeTokens[0].AssetID == "" || first.FeeTokens[1].AssetID != "" || first.FeeTokens[1].Symbol != "NOTATOKEN" {
+		t.Fatalf("unexpected fee tokens: %+v", first.FeeTokens)
+	}
+	for _, item := range items {
+		if item.Name == "team-sponsor" || item.Name == "coinbase-cdp" {
+			t.Fatalf("did not expect base-only paymaster %s on ethereum", item.Name)
+		}
+	}
+}
+
+func TestForChainRejectsInvalidConfiguredChain(t *testing.T) {
+	chain, _ := id.ParseChain("base")
+	if _, err := ForChain(chain, []config.Paymaster{{Name: "bad", Chains: []string{"not-a-chain"}}}); err == nil {
+		t.Fatal("expected invalid chain error")
+	}
+}
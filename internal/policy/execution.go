@@ -0,0 +1,155 @@
+package policy
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ExecutionFile is the execution policy file (default ~/.defi/policy.yaml).
+// It holds hard limits every submit enforces, independent of command flags.
+type ExecutionFile struct {
+	RequireSimulation bool             `yaml:"require_simulation"`
+	AllowedChains     []string         `yaml:"allowed_chains"`
+	AllowedSpenders   []string         `yaml:"allowed_spenders"`
+	Tokens            []ExecutionToken `yaml:"tokens"`
+}
+
+// ExecutionToken allows one asset on one chain. When any token is listed,
+// only listed tokens may be spent or approved. Limits are decimal amounts of
+// the asset; empty leaves a limit unset.
+type ExecutionToken struct {
+	Chain     string `yaml:"chain"`
+	Asset     string `yaml:"asset"`
+	MaxPerTx  string `yaml:"max_per_tx"`
+	MaxPerDay string `yaml:"max_per_day"`
+}
+
+// LoadExecutionFile reads the execution policy at path. It reports false
+// when the file does not exist. Unknown keys are rejected so a typo cannot
+// silently disable a limit.
+func LoadExecutionFile(path string) (ExecutionFile, bool, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return ExecutionFile{}, false, nil
+		}
+		return ExecutionFile{}, false, fmt.Errorf("read execution policy: %w", err)
+	}
+	var file ExecutionFile
+	dec := yaml.NewDecoder(bytes.NewReader(buf))
+	dec.KnownFields(true)
+	if err := dec.Decode(&file); err != nil && !errors.Is(err, io.EOF) {
+		return ExecutionFile{}, false, fmt.Errorf("parse execution policy %s: %w", path, err)
+	}
+	return file, true, nil
+}
+
+// ReplaceExecutionAddress rewrites every value in the execution policy at
+// path that names oldAddress, either as a bare address or as the reference of
+// a CAIP-19 asset id, to newAddress. It returns the rewritten file and the
+// fields that changed (for example "allowed_spenders[0]"); content is nil
+// when the file does not exist or nothing matches. Comments are kept.
+func ReplaceExecutionAddress(path, oldAddress, newAddress string) ([]byte, []string, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil, nil
+		}
+		return nil, nil, fmt.Errorf("read execution policy: %w", err)
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(buf, &doc); err != nil {
+		return nil, nil, fmt.Errorf("parse execution policy %s: %w", path, err)
+	}
+	var fields []string
+	replaceAddress(&doc, "", strings.TrimSpace(oldAddress), strings.TrimSpace(newAddress), &fields)
+	if len(fields) == 0 {
+		return nil, nil, nil
+	}
+	var out bytes.Buffer
+	enc := yaml.NewEncoder(&out)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, nil, fmt.Errorf("encode execution policy: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, nil, fmt.Errorf("encode execution policy: %w", err)
+	}
+	return out.Bytes(), fields, nil
+}
+
+// WriteExecutionFile atomically replaces the execution policy at path with
+// content, keeping the existing file mode.
+func WriteExecutionFile(path string, content []byte) error {
+	mode := os.FileMode(0o600)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".policy-*")
+	if err != nil {
+		return fmt.Errorf("create temp execution policy: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if err := tmp.Chmod(mode); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("chmod temp execution policy: %w", err)
+	}
+	if _, err := tmp.Write(content); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("write temp execution policy: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("close temp execution policy: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("replace execution policy: %w", err)
+	}
+	return nil
+}
+
+func replaceAddress(node *yaml.Node, field, oldAddress, newAddress string, fields *[]string) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, child := range node.Content {
+			replaceAddress(child, field, oldAddress, newAddress, fields)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i].Value
+			if field != "" {
+				key = field + "." + key
+			}
+			replaceAddress(node.Content[i+1], key, oldAddress, newAddress, fields)
+		}
+	case yaml.SequenceNode:
+		for i, child := range node.Content {
+			replaceAddress(child, fmt.Sprintf("%s[%d]", field, i), oldAddress, newAddress, fields)
+		}
+	case yaml.ScalarNode:
+		value := strings.TrimSpace(node.Value)
+		if strings.EqualFold(value, oldAddress) {
+			node.Value = newAddress
+			*fields = append(*fields, field)
+			return
+		}
+		if prefix, reference, ok := cutLast(value, ":"); ok && strings.EqualFold(reference, oldAddress) {
+			node.Value = prefix + ":" + newAddress
+			*fields = append(*fields, field)
+		}
+	}
+}
+
+func cutLast(value, sep string) (string, string, bool) {
+	idx := strings.LastIndex(value, sep)
+	if idx < 0 {
+		return value, "", false
+	}
+	return value[:idx], value[idx+len(sep):], true
+}
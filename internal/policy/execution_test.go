@@ -0,0 +1,74 @@
+package policy
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadExecutionFile(t *testing.T) {
+	dir := t.TempDir()
+	if _, ok, err := LoadExecutionFile(filepath.Join(dir, "missing.yaml")); ok || err != nil {
+		t.Fatalf("expected missing file to load as absent, got ok=%v err=%v", ok, err)
+	}
+
+	path := filepath.Join(dir, "policy.yaml")
+	content := "require_simulation: true\nallowed_chains: [base]\ntokens:\n  - chain: base\n    asset: USDC\n    max_per_tx: \"1000\"\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write policy: %v", err)
+	}
+	file, ok, err := LoadExecutionFile(path)
+	if err != nil || !ok {
+		t.Fatalf("LoadExecutionFile failed: ok=%v err=%v", ok, err)
+	}
+	if !file.RequireSimulation || len(file.AllowedChains) != 1 || len(file.Tokens) != 1 || file.Tokens[0].MaxPerTx != "1000" {
+		t.Fatalf("unexpected policy: %+v", file)
+	}
+
+	if err := os.WriteFile(path, []byte("max_per_txn: 5\n"), 0o600); err != nil {
+		t.Fatalf("write policy: %v", err)
+	}
+	if _, _, err := LoadExecutionFile(path); err == nil {
+		t.Fatal("expected unknown policy keys to be rejected")
+	}
+}
+
+func TestReplaceExecutionAddress(t *testing.T) {
+	const oldAddress = "0x00000000000000000000000000000000000000Aa"
+	const newAddress = "0x00000000000000000000000000000000000000Bb"
+	dir := t.TempDir()
+	if content, fields, err := ReplaceExecutionAddress(filepath.Join(dir, "missing.yaml"), oldAddress, newAddress); content != nil || fields != nil || err != nil {
+		t.Fatalf("expected missing file to be left alone, got content=%q fields=%v err=%v", content, fields, err)
+	}
+
+	path := filepath.Join(dir, "policy.yaml")
+	original := "# spenders we trust\nallowed_spenders:\n  - 0x00000000000000000000000000000000000000aa\ntokens:\n  - chain: base\n    asset: eip155:8453/erc20:0x00000000000000000000000000000000000000AA\n"
+	if err := os.WriteFile(path, []byte(original), 0o600); err != nil {
+		t.Fatalf("write policy: %v", err)
+	}
+	content, fields, err := ReplaceExecutionAddress(path, oldAddress, newAddress)
+	if err != nil {
+		t.Fatalf("ReplaceExecutionAddress failed: %v", err)
+	}
+	if len(fields) != 2 || fields[0] != "allowed_spenders[0]" || fields[1] != "tokens[0].asset" {
+		t.Fatalf("unexpected changed fields: %v", fields)
+	}
+	if err := WriteExecutionFile(path, content); err != nil {
+		t.Fatalf("WriteExecutionFile failed: %v", err)
+	}
+	file, _, err := LoadExecutionFile(path)
+	if err != nil {
+		t.Fatalf("reload policy: %v", err)
+	}
+	if file.AllowedSpenders[0] != newAddress || file.Tokens[0].Asset != "eip155:8453/erc20:"+newAddress {
+		t.Fatalf("expected addresses to be rewritten, got %+v", file)
+	}
+	if buf, _ := os.ReadFile(path); !strings.Contains(string(buf), "# spenders we trust") {
+		t.Fatalf("expected comments to be kept, got %s", buf)
+	}
+
+	if content, fields, err := ReplaceExecutionAddress(path, oldAddress, newAddress); content != nil || fields != nil || err != nil {
+		t.Fatalf("expected no changes on a second pass, got fields=%v err=%v", fields, err)
+	}
+}
@@ -0,0 +1,128 @@
+package policy
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+)
+
+// ProtocolRules restricts which liquidity sources (swap venues, bridges,
+// lending and yield protocols) commands may use. Rules are case-insensitive
+// glob patterns (for example "uniswap*") matched against provider and
+// protocol names. Deny rules win over allow rules.
+type ProtocolRules struct {
+	Allow []string
+	Deny  []string
+}
+
+// ProtocolViolation is returned in error.details when a protocol is blocked.
+type ProtocolViolation struct {
+	Protocol string `json:"protocol"`
+	// List is "deny" when Rule matched, or "allow" when an allow list is set
+	// and no rule matched.
+	List string `json:"list"`
+	Rule string `json:"rule,omitempty"`
+}
+
+func (r ProtocolRules) Empty() bool {
+	return len(r.Allow) == 0 && len(r.Deny) == 0
+}
+
+// References returns the allow and deny rules that name value exactly, such
+// as an address, formatted as "allow_protocols: <rule>".
+func (r ProtocolRules) References(value string) []string {
+	value = strings.TrimSpace(value)
+	var out []string
+	for _, rule := range r.Allow {
+		if strings.EqualFold(strings.TrimSpace(rule), value) {
+			out = append(out, "allow_protocols: "+rule)
+		}
+	}
+	for _, rule := range r.Deny {
+		if strings.EqualFold(strings.TrimSpace(rule), value) {
+			out = append(out, "deny_protocols: "+rule)
+		}
+	}
+	return out
+}
+
+// Check validates the names that identify one liquidity source, such as a
+// provider and the protocol it reports. The source is blocked when any name
+// matches a deny rule, or when an allow list is set and no name matches it.
+func (r ProtocolRules) Check(names ...string) error {
+	names = nonEmpty(names)
+	if len(names) == 0 || r.Empty() {
+		return nil
+	}
+	for _, name := range names {
+		if rule, ok := matchRule(r.Deny, name); ok {
+			return protocolBlocked(ProtocolViolation{Protocol: name, List: "deny", Rule: rule},
+				fmt.Sprintf("protocol %s blocked by deny rule %q", name, rule))
+		}
+	}
+	if len(r.Allow) == 0 {
+		return nil
+	}
+	for _, name := range names {
+		if _, ok := matchRule(r.Allow, name); ok {
+			return nil
+		}
+	}
+	return protocolBlocked(ProtocolViolation{Protocol: names[0], List: "allow"},
+		fmt.Sprintf("protocol %s is not in the protocol allow list", names[0]))
+}
+
+// CheckRoute applies deny rules to each hop of a provider-reported route
+// label (for example "Raydium > Orca"). Allow rules are not applied because
+// aggregators report venue labels that rarely match configured names. A
+// denied hop is an action policy error: the provider itself was allowed.
+func (r ProtocolRules) CheckRoute(route string) error {
+	if len(r.Deny) == 0 {
+		return nil
+	}
+	hops := strings.FieldsFunc(route, func(c rune) bool { return c == '>' || c == ',' || c == '|' })
+	for _, hop := range hops {
+		hop = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(hop), "-"))
+		if hop == "" {
+			continue
+		}
+		if rule, ok := matchRule(r.Deny, hop); ok {
+			return clierr.New(clierr.CodeActionPolicy, fmt.Sprintf("route through %s blocked by deny rule %q", hop, rule)).
+				WithDetails(ProtocolViolation{Protocol: hop, List: "deny", Rule: rule})
+		}
+	}
+	return nil
+}
+
+func protocolBlocked(violation ProtocolViolation, message string) error {
+	return clierr.New(clierr.CodeBlocked, message).WithDetails(violation)
+}
+
+func matchRule(rules []string, name string) (string, bool) {
+	target := normalize(name)
+	for _, rule := range rules {
+		pattern := normalize(rule)
+		if pattern == "" {
+			continue
+		}
+		if pattern == target {
+			return rule, true
+		}
+		if ok, err := path.Match(pattern, target); err == nil && ok {
+			return rule, true
+		}
+	}
+	return "", false
+}
+
+func nonEmpty(values []string) []string {
+	out := make([]string, 0, len(values))
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			out = append(out, strings.TrimSpace(v))
+		}
+	}
+	return out
+}
@@ -0,0 +1,59 @@
+package policy
+
+import (
+	"errors"
+	"testing"
+
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+)
+
+func TestProtocolRulesCheck(t *testing.T) {
+	rules := ProtocolRules{Allow: []string{"aave", "morpho"}, Deny: []string{"uniswap*"}}
+	if err := rules.Check("aave"); err != nil {
+		t.Fatalf("expected aave to be allowed: %v", err)
+	}
+	if err := rules.Check("defillama", "Morpho"); err != nil {
+		t.Fatalf("expected allow match on protocol name: %v", err)
+	}
+
+	err := rules.Check("uniswap-v3")
+	var cErr *clierr.Error
+	if !errors.As(err, &cErr) || cErr.Code != clierr.CodeBlocked {
+		t.Fatalf("expected blocked error, got %v", err)
+	}
+	violation, ok := cErr.Details.(ProtocolViolation)
+	if !ok || violation.List != "deny" || violation.Rule != "uniswap*" || violation.Protocol != "uniswap-v3" {
+		t.Fatalf("unexpected violation details: %+v", cErr.Details)
+	}
+
+	err = rules.Check("kamino")
+	if !errors.As(err, &cErr) || cErr.Details.(ProtocolViolation).List != "allow" {
+		t.Fatalf("expected allow-list violation, got %v", err)
+	}
+	if err := (ProtocolRules{}).Check("anything"); err != nil {
+		t.Fatalf("expected empty rules to allow everything: %v", err)
+	}
+}
+
+func TestProtocolRulesCheckRoute(t *testing.T) {
+	rules := ProtocolRules{Allow: []string{"jupiter"}, Deny: []string{"orca"}}
+	if err := rules.CheckRoute("Raydium > Meteora"); err != nil {
+		t.Fatalf("expected route to pass: %v", err)
+	}
+	err := rules.CheckRoute("Raydium > Orca")
+	var cErr *clierr.Error
+	if !errors.As(err, &cErr) || cErr.Code != clierr.CodeActionPolicy || cErr.Details.(ProtocolViolation).Protocol != "Orca" {
+		t.Fatalf("expected route through Orca to be blocked by action policy, got %v", err)
+	}
+}
+
+func TestProtocolRulesReferences(t *testing.T) {
+	rules := ProtocolRules{Allow: []string{"aave", "0xAbC"}, Deny: []string{"0xabc*", "0xabc"}}
+	refs := rules.References("0xABC")
+	if len(refs) != 2 || refs[0] != "allow_protocols: 0xAbC" || refs[1] != "deny_protocols: 0xabc" {
+		t.Fatalf("unexpected references: %v", refs)
+	}
+	if refs := rules.References("morpho"); len(refs) != 0 {
+		t.Fatalf("expected no references, got %v", refs)
+	}
+}
@@ -0,0 +1,219 @@
+// Package pricecheck re-prices swaps against sources that do not depend on
+// the quoting provider, so agents can check a quote before executing it.
+package pricecheck
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/registry"
+)
+
+// Reference sources.
+const (
+	SourceUniswapV3 = "uniswap-v3"
+	SourceChainlink = "chainlink"
+	SourceDefiLlama = "defillama"
+)
+
+// maxFeedAge rejects Chainlink answers older than the longest heartbeat used
+// by the registered feeds.
+const maxFeedAge = 24 * time.Hour
+
+var (
+	feeTiers = []uint32{100, 500, 3000, 10000}
+
+	quoterABI     = mustABI(registry.UniswapV3QuoterV2ABI)
+	aggregatorABI = mustABI(registry.ChainlinkAggregatorV3ABI)
+)
+
+// Reference is an independently derived output amount for a swap.
+type Reference struct {
+	Source    string
+	AmountOut *big.Int
+	Detail    string
+}
+
+type quoteExactInputSingleParams struct {
+	TokenIn           common.Address `abi:"tokenIn"`
+	TokenOut          common.Address `abi:"tokenOut"`
+	AmountIn          *big.Int       `abi:"amountIn"`
+	Fee               *big.Int       `abi:"fee"`
+	SqrtPriceLimitX96 *big.Int       `abi:"sqrtPriceLimitX96"`
+}
+
+// UniswapV3 quotes the best single-pool Uniswap V3 output across fee tiers.
+// Aggregators that split or multi-hop can legitimately beat it.
+func UniswapV3(ctx context.Context, caller ethereum.ContractCaller, chainID int64, in, out id.Asset, amountIn *big.Int) (Reference, error) {
+	quoterRaw, ok := registry.UniswapV3ReferenceQuoter(chainID)
+	if !ok {
+		return Reference{}, clierr.New(clierr.CodeUnsupported, fmt.Sprintf("no Uniswap V3 quoter registered for chain %d", chainID))
+	}
+	if !common.IsHexAddress(in.Address) || !common.IsHexAddress(out.Address) || isNative(in) || isNative(out) {
+		return Reference{}, clierr.New(clierr.CodeUnsupported, "uniswap-v3 reference requires ERC-20 tokens on both sides")
+	}
+	quoter := common.HexToAddress(quoterRaw)
+	var best *big.Int
+	var bestFee uint32
+	for _, fee := range feeTiers {
+		callData, err := quoterABI.Pack("quoteExactInputSingle", quoteExactInputSingleParams{
+			TokenIn:           common.HexToAddress(in.Address),
+			TokenOut:          common.HexToAddress(out.Address),
+			AmountIn:          amountIn,
+			Fee:               big.NewInt(int64(fee)),
+			SqrtPriceLimitX96: big.NewInt(0),
+		})
+		if err != nil {
+			return Reference{}, clierr.Wrap(clierr.CodeInternal, "pack quoter calldata", err)
+		}
+		raw, err := caller.CallContract(ctx, ethereum.CallMsg{To: &quoter, Data: callData}, nil)
+		if err != nil {
+			continue
+		}
+		decoded, err := quoterABI.Unpack("quoteExactInputSingle", raw)
+		if err != nil || len(decoded) == 0 {
+			continue
+		}
+		amountOut, ok := decoded[0].(*big.Int)
+		if !ok || amountOut == nil || amountOut.Sign() <= 0 {
+			continue
+		}
+		if best == nil || amountOut.Cmp(best) > 0 {
+			best, bestFee = amountOut, fee
+		}
+	}
+	if best == nil {
+		return Reference{}, clierr.New(clierr.CodeUnavailable, "no Uniswap V3 pool quoted the pair")
+	}
+	return Reference{Source: SourceUniswapV3, AmountOut: best, Detail: fmt.Sprintf("best single pool, fee tier %d", bestFee)}, nil
+}
+
+// Chainlink converts amountIn to the output token through both tokens' USD
+// feeds. It ignores pool depth, so it anchors price rather than fill size.
+func Chainlink(ctx context.Context, caller ethereum.ContractCaller, chainID int64, in, out id.Asset, amountIn *big.Int, now time.Time) (Reference, error) {
+	priceIn, decIn, err := feedPrice(ctx, caller, chainID, in.Symbol, now)
+	if err != nil {
+		return Reference{}, err
+	}
+	priceOut, decOut, err := feedPrice(ctx, caller, chainID, out.Symbol, now)
+	if err != nil {
+		return Reference{}, err
+	}
+	// out = amountIn * priceIn / priceOut, rescaled for token and feed decimals.
+	num := new(big.Int).Mul(amountIn, priceIn)
+	num.Mul(num, pow10(out.Decimals+decOut))
+	den := new(big.Int).Mul(priceOut, pow10(in.Decimals+decIn))
+	amountOut := num.Quo(num, den)
+	if amountOut.Sign() <= 0 {
+		return Reference{}, clierr.New(clierr.CodeUnavailable, "chainlink reference rounded to zero")
+	}
+	detail := fmt.Sprintf("%s/USD %s, %s/USD %s", strings.ToUpper(in.Symbol), formatPrice(priceIn, decIn), strings.ToUpper(out.Symbol), formatPrice(priceOut, decOut))
+	return Reference{Source: SourceChainlink, AmountOut: amountOut, Detail: detail}, nil
+}
+
+// USDPrices converts amountIn to the output token through aggregated USD
+// prices, such as the DefiLlama coins API. Like Chainlink it anchors price, not
+// fill size.
+func USDPrices(source string, in, out id.Asset, amountIn *big.Int, priceIn, priceOut float64) (Reference, error) {
+	if priceIn <= 0 || priceOut <= 0 {
+		return Reference{}, clierr.New(clierr.CodeUnavailable, "usd prices must be positive")
+	}
+	// out = amountIn * priceIn / priceOut, rescaled for token decimals.
+	value := new(big.Float).SetInt(amountIn)
+	value.Mul(value, big.NewFloat(priceIn))
+	value.Quo(value, big.NewFloat(priceOut))
+	value.Mul(value, new(big.Float).SetInt(pow10(out.Decimals)))
+	value.Quo(value, new(big.Float).SetInt(pow10(in.Decimals)))
+	amountOut, _ := value.Int(nil)
+	if amountOut.Sign() <= 0 {
+		return Reference{}, clierr.New(clierr.CodeUnavailable, source+" reference rounded to zero")
+	}
+	detail := fmt.Sprintf("%s/USD %.6g, %s/USD %.6g", strings.ToUpper(in.Symbol), priceIn, strings.ToUpper(out.Symbol), priceOut)
+	return Reference{Source: source, AmountOut: amountOut, Detail: detail}, nil
+}
+
+// DeviationPct is how far quoted sits above (positive) or below (negative)
+// reference, in percent.
+func DeviationPct(quoted, reference *big.Int) float64 {
+	if reference == nil || reference.Sign() == 0 {
+		return 0
+	}
+	diff := new(big.Float).SetInt(new(big.Int).Sub(quoted, reference))
+	ratio, _ := new(big.Float).Quo(diff, new(big.Float).SetInt(reference)).Float64()
+	return ratio * 100
+}
+
+func feedPrice(ctx context.Context, caller ethereum.ContractCaller, chainID int64, symbol string, now time.Time) (*big.Int, int, error) {
+	feedRaw, ok := registry.ChainlinkUSDFeed(chainID, symbol)
+	if !ok {
+		return nil, 0, clierr.New(clierr.CodeUnsupported, fmt.Sprintf("no Chainlink %s/USD feed registered for chain %d", strings.ToUpper(symbol), chainID))
+	}
+	feed := common.HexToAddress(feedRaw)
+	decimalsData, err := aggregatorABI.Pack("decimals")
+	if err != nil {
+		return nil, 0, clierr.Wrap(clierr.CodeInternal, "pack decimals call", err)
+	}
+	raw, err := caller.CallContract(ctx, ethereum.CallMsg{To: &feed, Data: decimalsData}, nil)
+	if err != nil {
+		return nil, 0, clierr.Wrap(clierr.CodeUnavailable, "read chainlink decimals", err)
+	}
+	decoded, err := aggregatorABI.Unpack("decimals", raw)
+	if err != nil || len(decoded) == 0 {
+		return nil, 0, clierr.Wrap(clierr.CodeUnavailable, "decode chainlink decimals", err)
+	}
+	decimals, ok := decoded[0].(uint8)
+	if !ok {
+		return nil, 0, clierr.New(clierr.CodeUnavailable, "invalid chainlink decimals")
+	}
+
+	roundData, err := aggregatorABI.Pack("latestRoundData")
+	if err != nil {
+		return nil, 0, clierr.Wrap(clierr.CodeInternal, "pack latestRoundData call", err)
+	}
+	raw, err = caller.CallContract(ctx, ethereum.CallMsg{To: &feed, Data: roundData}, nil)
+	if err != nil {
+		return nil, 0, clierr.Wrap(clierr.CodeUnavailable, "read chainlink round", err)
+	}
+	decoded, err = aggregatorABI.Unpack("latestRoundData", raw)
+	if err != nil || len(decoded) < 4 {
+		return nil, 0, clierr.Wrap(clierr.CodeUnavailable, "decode chainlink round", err)
+	}
+	answer, ok := decoded[1].(*big.Int)
+	if !ok || answer == nil || answer.Sign() <= 0 {
+		return nil, 0, clierr.New(clierr.CodeUnavailable, "chainlink feed returned a non-positive answer")
+	}
+	updatedAt, ok := decoded[3].(*big.Int)
+	if !ok || updatedAt == nil || now.Sub(time.Unix(updatedAt.Int64(), 0)) > maxFeedAge {
+		return nil, 0, clierr.New(clierr.CodeUnavailable, fmt.Sprintf("chainlink %s/USD feed is stale", strings.ToUpper(symbol)))
+	}
+	return answer, int(decimals), nil
+}
+
+func formatPrice(answer *big.Int, decimals int) string {
+	value, _ := new(big.Float).Quo(new(big.Float).SetInt(answer), new(big.Float).SetInt(pow10(decimals))).Float64()
+	return fmt.Sprintf("%.6g", value)
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+func isNative(asset id.Asset) bool {
+	return strings.EqualFold(strings.TrimSpace(asset.Address), "0xEeeeeEeeeEeEeeEeEeEeeEEEeeeeEeeeeeeeEEeE")
+}
+
+func mustABI(raw string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(raw))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
@@ -0,0 +1,142 @@
+package pricecheck
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/registry"
+)
+
+type fakeCaller struct {
+	quotes  map[uint32]*big.Int
+	answers map[common.Address]*big.Int
+	updated int64
+}
+
+func (f fakeCaller) CallContract(_ context.Context, msg ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	method, err := quoterABI.MethodById(msg.Data[:4])
+	if err == nil && method.Name == "quoteExactInputSingle" {
+		args, err := method.Inputs.Unpack(msg.Data[4:])
+		if err != nil {
+			return nil, err
+		}
+		fee := args[0].(struct {
+			TokenIn           common.Address `json:"tokenIn"`
+			TokenOut          common.Address `json:"tokenOut"`
+			AmountIn          *big.Int       `json:"amountIn"`
+			Fee               *big.Int       `json:"fee"`
+			SqrtPriceLimitX96 *big.Int       `json:"sqrtPriceLimitX96"`
+		}).Fee
+		out, ok := f.quotes[uint32(fee.Uint64())]
+		if !ok {
+			return nil, errors.New("execution reverted")
+		}
+		return method.Outputs.Pack(out, big.NewInt(0), uint32(0), big.NewInt(0))
+	}
+	method, err = aggregatorABI.MethodById(msg.Data[:4])
+	if err != nil {
+		return nil, err
+	}
+	switch method.Name {
+	case "decimals":
+		return method.Outputs.Pack(uint8(8))
+	default:
+		answer, ok := f.answers[*msg.To]
+		if !ok {
+			return nil, errors.New("no feed")
+		}
+		return method.Outputs.Pack(big.NewInt(1), answer, big.NewInt(0), big.NewInt(f.updated), big.NewInt(1))
+	}
+}
+
+func mainnetAssets(t *testing.T) (id.Asset, id.Asset) {
+	t.Helper()
+	chain, err := id.ParseChain("ethereum")
+	if err != nil {
+		t.Fatalf("parse chain: %v", err)
+	}
+	weth, err := id.ParseAsset("WETH", chain)
+	if err != nil {
+		t.Fatalf("parse WETH: %v", err)
+	}
+	usdc, err := id.ParseAsset("USDC", chain)
+	if err != nil {
+		t.Fatalf("parse USDC: %v", err)
+	}
+	return weth, usdc
+}
+
+func TestUniswapV3PicksBestFeeTier(t *testing.T) {
+	weth, usdc := mainnetAssets(t)
+	caller := fakeCaller{quotes: map[uint32]*big.Int{500: big.NewInt(2_990_000_000), 3000: big.NewInt(2_980_000_000)}}
+	ref, err := UniswapV3(context.Background(), caller, 1, weth, usdc, big.NewInt(1e18))
+	if err != nil {
+		t.Fatalf("UniswapV3 failed: %v", err)
+	}
+	if ref.AmountOut.Int64() != 2_990_000_000 || !strings.Contains(ref.Detail, "fee tier 500") {
+		t.Fatalf("unexpected reference: %+v", ref)
+	}
+}
+
+func TestUniswapV3FailsWhenNoPoolQuotes(t *testing.T) {
+	weth, usdc := mainnetAssets(t)
+	if _, err := UniswapV3(context.Background(), fakeCaller{}, 1, weth, usdc, big.NewInt(1e18)); err == nil {
+		t.Fatal("expected error when every fee tier reverts")
+	}
+}
+
+func TestChainlinkConvertsThroughUSD(t *testing.T) {
+	weth, usdc := mainnetAssets(t)
+	now := time.Unix(1_800_000_000, 0)
+	ethFeed, _ := registry.ChainlinkUSDFeed(1, "WETH")
+	usdcFeed, _ := registry.ChainlinkUSDFeed(1, "USDC")
+	caller := fakeCaller{
+		answers: map[common.Address]*big.Int{
+			common.HexToAddress(ethFeed):  big.NewInt(3_000_00000000),
+			common.HexToAddress(usdcFeed): big.NewInt(1_00000000),
+		},
+		updated: now.Add(-time.Hour).Unix(),
+	}
+	ref, err := Chainlink(context.Background(), caller, 1, weth, usdc, big.NewInt(1e18), now)
+	if err != nil {
+		t.Fatalf("Chainlink failed: %v", err)
+	}
+	if ref.AmountOut.Int64() != 3_000_000_000 {
+		t.Fatalf("expected 3000 USDC, got %s", ref.AmountOut)
+	}
+
+	caller.updated = now.Add(-48 * time.Hour).Unix()
+	if _, err := Chainlink(context.Background(), caller, 1, weth, usdc, big.NewInt(1e18), now); err == nil || !strings.Contains(err.Error(), "stale") {
+		t.Fatalf("expected stale feed error, got %v", err)
+	}
+}
+
+func TestDeviationPct(t *testing.T) {
+	if got := DeviationPct(big.NewInt(98), big.NewInt(100)); got > -1.99 || got < -2.01 {
+		t.Fatalf("expected -2%%, got %v", got)
+	}
+	if got := DeviationPct(big.NewInt(1), big.NewInt(0)); got != 0 {
+		t.Fatalf("expected 0 for empty reference, got %v", got)
+	}
+}
+
+func TestUSDPricesRescalesDecimals(t *testing.T) {
+	weth, usdc := mainnetAssets(t)
+	ref, err := USDPrices(SourceDefiLlama, weth, usdc, big.NewInt(2e18), 3000, 1)
+	if err != nil {
+		t.Fatalf("USDPrices failed: %v", err)
+	}
+	if ref.AmountOut.Cmp(big.NewInt(6_000_000_000)) != 0 {
+		t.Fatalf("expected 6000 USDC, got %s", ref.AmountOut)
+	}
+	if _, err := USDPrices(SourceDefiLlama, weth, usdc, big.NewInt(1), 0, 1); err == nil {
+		t.Fatal("expected error for a missing input price")
+	}
+}
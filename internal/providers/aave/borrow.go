@@ -0,0 +1,66 @@
+package aave
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/model"
+	"github.com/ggonzalez94/defi-cli/internal/providers"
+	"github.com/ggonzalez94/defi-cli/internal/providers/yieldutil"
+)
+
+// BorrowQuotes returns one venue per Aave market that lends req.Asset and,
+// when req.Collateral is set, accepts it as collateral. LTV caps come from
+// the collateral reserve outside e-mode.
+func (c *Client) BorrowQuotes(ctx context.Context, req providers.BorrowQuoteRequest) ([]model.BorrowQuote, error) {
+	markets, err := c.fetchMarkets(ctx, req.Chain)
+	if err != nil {
+		return nil, err
+	}
+	withCollateral := strings.TrimSpace(req.Collateral.AssetID) != ""
+	fetchedAt := c.now().UTC().Format(time.RFC3339)
+	out := []model.BorrowQuote{}
+	for _, market := range markets {
+		var collateral *aaveReserve
+		if withCollateral {
+			for i := range market.Reserves {
+				if matchesReserveAsset(market.Reserves[i], req.Collateral) && parseFloat(market.Reserves[i].SupplyInfo.MaxLTV.Value) > 0 {
+					collateral = &market.Reserves[i]
+					break
+				}
+			}
+			if collateral == nil {
+				continue
+			}
+		}
+		for _, reserve := range market.Reserves {
+			if reserve.BorrowInfo == nil || !matchesReserveAsset(reserve, req.Asset) {
+				continue
+			}
+			quote := model.BorrowQuote{
+				Protocol:              "aave",
+				Provider:              "aave",
+				ChainID:               req.Chain.CAIP2,
+				AssetID:               canonicalAssetID(req.Asset, reserve.UnderlyingToken.Address),
+				ProviderNativeID:      providerNativeID("aave", req.Chain.CAIP2, market.Address, reserve.UnderlyingToken.Address),
+				ProviderNativeIDKind:  model.NativeIDKindCompositeMarketAsset,
+				BorrowAPY:             yieldutil.PercentFromRatio(parseFloat(reserve.BorrowInfo.APY.Value)),
+				AvailableLiquidityUSD: parseFloat(reserve.BorrowInfo.AvailableLiquidity.USD),
+				SourceURL:             "https://app.aave.com",
+				FetchedAt:             fetchedAt,
+			}
+			if collateral != nil {
+				quote.CollateralAssetID = canonicalAssetIDForChain(req.Chain.CAIP2, collateral.UnderlyingToken.Address)
+				quote.MaxLTVPct = yieldutil.PercentFromRatio(parseFloat(collateral.SupplyInfo.MaxLTV.Value))
+				quote.LiquidationLTVPct = yieldutil.PercentFromRatio(parseFloat(collateral.SupplyInfo.LiquidationThreshold.Value))
+			}
+			out = append(out, quote)
+		}
+	}
+	if len(out) == 0 {
+		return nil, clierr.New(clierr.CodeUnsupported, "no aave borrow market for requested chain/asset/collateral")
+	}
+	return out, nil
+}
@@ -13,6 +13,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/ggonzalez94/defi-cli/internal/clock"
 	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
 	"github.com/ggonzalez94/defi-cli/internal/httpx"
 	"github.com/ggonzalez94/defi-cli/internal/id"
@@ -27,10 +28,25 @@ type Client struct {
 	http     *httpx.Client
 	endpoint string
 	now      func() time.Time
+
+	// Set by NewSubgraph: market reads go to The Graph instead of the API.
+	backend          providers.DataBackend
+	subgraphKey      string
+	subgraphEndpoint func(chainID int64) (string, bool)
 }
 
 func New(httpClient *httpx.Client) *Client {
-	return &Client{http: httpClient, endpoint: defaultEndpoint, now: time.Now}
+	return &Client{http: httpClient, endpoint: defaultEndpoint, now: clock.Now, backend: providers.DataBackendAPI}
+}
+
+// NewSubgraph returns a client that reads markets and rates from the Aave v3
+// subgraphs on The Graph network, authenticated with apiKey.
+func NewSubgraph(httpClient *httpx.Client, apiKey string) *Client {
+	c := New(httpClient)
+	c.backend = providers.DataBackendSubgraph
+	c.subgraphKey = strings.TrimSpace(apiKey)
+	c.subgraphEndpoint = defaultSubgraphEndpoint
+	return c
 }
 
 func (c *Client) Info() model.ProviderInfo {
@@ -42,6 +58,8 @@ func (c *Client) Info() model.ProviderInfo {
 			"lend.markets",
 			"lend.rates",
 			"lend.positions",
+			"lend.health",
+			"lend.borrow_quote",
 			"yield.opportunities",
 			"yield.positions",
 			"yield.history",
@@ -49,6 +67,7 @@ func (c *Client) Info() model.ProviderInfo {
 			"lend.execute",
 			"yield.plan",
 			"yield.execute",
+			"rewards.claimable",
 			"rewards.plan",
 			"rewards.execute",
 		},
@@ -63,8 +82,9 @@ const marketsQuery = `query Markets($request: MarketsRequest!) {
     reserves {
       underlyingToken { address symbol decimals }
       aToken { address }
+      vToken { address }
       size { usd }
-      supplyInfo { apy { value } total { value } }
+      supplyInfo { apy { value } total { value } maxLTV { value } liquidationThreshold { value } }
       borrowInfo { apy { value } total { usd } utilizationRate { value } availableLiquidity { usd } }
     }
   }
@@ -163,6 +183,9 @@ type aaveReserve struct {
 	AToken struct {
 		Address string `json:"address"`
 	} `json:"aToken"`
+	VToken struct {
+		Address string `json:"address"`
+	} `json:"vToken"`
 	Size struct {
 		USD string `json:"usd"`
 	} `json:"size"`
@@ -173,21 +196,29 @@ type aaveReserve struct {
 		Total struct {
 			Value string `json:"value"`
 		} `json:"total"`
-	} `json:"supplyInfo"`
-	BorrowInfo *struct {
-		APY struct {
+		MaxLTV struct {
 			Value string `json:"value"`
-		} `json:"apy"`
-		Total struct {
-			USD string `json:"usd"`
-		} `json:"total"`
-		UtilizationRate struct {
+		} `json:"maxLTV"`
+		LiquidationThreshold struct {
 			Value string `json:"value"`
-		} `json:"utilizationRate"`
-		AvailableLiquidity struct {
-			USD string `json:"usd"`
-		} `json:"availableLiquidity"`
-	} `json:"borrowInfo"`
+		} `json:"liquidationThreshold"`
+	} `json:"supplyInfo"`
+	BorrowInfo *aaveBorrowInfo `json:"borrowInfo"`
+}
+
+type aaveBorrowInfo struct {
+	APY struct {
+		Value string `json:"value"`
+	} `json:"apy"`
+	Total struct {
+		USD string `json:"usd"`
+	} `json:"total"`
+	UtilizationRate struct {
+		Value string `json:"value"`
+	} `json:"utilizationRate"`
+	AvailableLiquidity struct {
+		USD string `json:"usd"`
+	} `json:"availableLiquidity"`
 }
 
 type aaveUserSupply struct {
@@ -251,10 +282,10 @@ func (c *Client) LendMarkets(ctx context.Context, provider string, chain id.Chai
 			if !matchesReserveAsset(r, asset) {
 				continue
 			}
-			supplyAPY := parseFloat(r.SupplyInfo.APY.Value) * 100
+			supplyAPY := yieldutil.PercentFromRatio(parseFloat(r.SupplyInfo.APY.Value))
 			borrowAPY := 0.0
 			if r.BorrowInfo != nil {
-				borrowAPY = parseFloat(r.BorrowInfo.APY.Value) * 100
+				borrowAPY = yieldutil.PercentFromRatio(parseFloat(r.BorrowInfo.APY.Value))
 			}
 			tvlUSD := parseFloat(r.Size.USD)
 			if tvlUSD <= 0 {
@@ -305,11 +336,11 @@ func (c *Client) LendRates(ctx context.Context, provider string, chain id.Chain,
 			if !matchesReserveAsset(r, asset) {
 				continue
 			}
-			supplyAPY := parseFloat(r.SupplyInfo.APY.Value) * 100
+			supplyAPY := yieldutil.PercentFromRatio(parseFloat(r.SupplyInfo.APY.Value))
 			borrowAPY := 0.0
 			utilization := 0.0
 			if r.BorrowInfo != nil {
-				borrowAPY = parseFloat(r.BorrowInfo.APY.Value) * 100
+				borrowAPY = yieldutil.PercentFromRatio(parseFloat(r.BorrowInfo.APY.Value))
 				utilization = parseFloat(r.BorrowInfo.UtilizationRate.Value)
 			}
 			out = append(out, model.LendRate{
@@ -321,6 +352,8 @@ func (c *Client) LendRates(ctx context.Context, provider string, chain id.Chain,
 				ProviderNativeIDKind: model.NativeIDKindCompositeMarketAsset,
 				SupplyAPY:            supplyAPY,
 				BorrowAPY:            borrowAPY,
+				RateBasis:            yieldutil.RateBasisAPY,
+				Compounding:          yieldutil.CompoundingPerSecond,
 				Utilization:          utilization,
 				SourceURL:            "https://app.aave.com",
 				FetchedAt:            c.now().UTC().Format(time.RFC3339),
@@ -348,50 +381,10 @@ func (c *Client) LendPositions(ctx context.Context, req providers.LendPositionsR
 	if account == "" {
 		return nil, clierr.New(clierr.CodeUsage, "aave positions requires a valid EVM account address")
 	}
-
-	marketAddresses, err := c.fetchMarketAddresses(ctx, req.Chain)
-	if err != nil {
-		return nil, err
-	}
-	markets := make([]map[string]any, 0, len(marketAddresses))
-	for _, address := range marketAddresses {
-		markets = append(markets, map[string]any{
-			"address": address,
-			"chainId": req.Chain.EVMChainID,
-		})
-	}
-
-	body, err := json.Marshal(map[string]any{
-		"query": positionsQuery,
-		"variables": map[string]any{
-			"suppliesRequest": map[string]any{
-				"markets":         markets,
-				"user":            account,
-				"collateralsOnly": false,
-				"orderBy": map[string]any{
-					"balance": "DESC",
-				},
-			},
-			"borrowsRequest": map[string]any{
-				"markets": markets,
-				"user":    account,
-				"orderBy": map[string]any{
-					"debt": "DESC",
-				},
-			},
-		},
-	})
+	resp, err := c.fetchPositions(ctx, req.Chain, account)
 	if err != nil {
-		return nil, clierr.Wrap(clierr.CodeInternal, "marshal aave positions query", err)
-	}
-
-	var resp positionsResponse
-	if _, err := httpx.DoBodyJSON(ctx, c.http, http.MethodPost, c.endpoint, body, nil, &resp); err != nil {
 		return nil, err
 	}
-	if len(resp.Errors) > 0 {
-		return nil, clierr.New(clierr.CodeUnavailable, fmt.Sprintf("aave graphql error: %s", resp.Errors[0].Message))
-	}
 
 	filterType := req.PositionType
 	if filterType == "" {
@@ -426,7 +419,7 @@ func (c *Client) LendPositions(ctx context.Context, req providers.LendPositionsR
 			ProviderNativeIDKind: model.NativeIDKindCompositeMarketAsset,
 			Amount:               amount,
 			AmountUSD:            parseFloat(supply.Balance.USD),
-			APY:                  parseFloat(supply.APY.Value) * 100,
+			APY:                  yieldutil.PercentFromRatio(parseFloat(supply.APY.Value)),
 			SourceURL:            "https://app.aave.com",
 			FetchedAt:            c.now().UTC().Format(time.RFC3339),
 		})
@@ -456,7 +449,7 @@ func (c *Client) LendPositions(ctx context.Context, req providers.LendPositionsR
 			ProviderNativeIDKind: model.NativeIDKindCompositeMarketAsset,
 			Amount:               amount,
 			AmountUSD:            parseFloat(borrow.Debt.USD),
-			APY:                  parseFloat(borrow.APY.Value) * 100,
+			APY:                  yieldutil.PercentFromRatio(parseFloat(borrow.APY.Value)),
 			SourceURL:            "https://app.aave.com",
 			FetchedAt:            c.now().UTC().Format(time.RFC3339),
 		})
@@ -481,7 +474,7 @@ func (c *Client) YieldOpportunities(ctx context.Context, req providers.YieldRequ
 			if !matchesReserveAsset(r, req.Asset) {
 				continue
 			}
-			apy := parseFloat(r.SupplyInfo.APY.Value) * 100
+			apy := yieldutil.PercentFromRatio(parseFloat(r.SupplyInfo.APY.Value))
 			tvl := parseFloat(r.Size.USD)
 			if (apy == 0 || tvl == 0) && !req.IncludeIncomplete {
 				continue
@@ -514,10 +507,13 @@ func (c *Client) YieldOpportunities(ctx context.Context, req providers.YieldRequ
 				APYBase:              apy,
 				APYReward:            0,
 				APYTotal:             apy,
+				RateBasis:            yieldutil.RateBasisAPY,
+				Compounding:          yieldutil.CompoundingPerSecond,
 				TVLUSD:               tvl,
 				LiquidityUSD:         liquidityUSD,
 				LockupDays:           0,
 				WithdrawalTerms:      "variable",
+				ExitLiquidity:        yieldutil.ExitLiquidity(tvl, liquidityUSD, 0),
 				BackingAssets: []model.YieldBackingAsset{{
 					AssetID:  assetID,
 					Symbol:   strings.TrimSpace(r.UnderlyingToken.Symbol),
@@ -532,6 +528,8 @@ func (c *Client) YieldOpportunities(ctx context.Context, req providers.YieldRequ
 	if len(out) == 0 {
 		return nil, clierr.New(clierr.CodeUnavailable, "no aave yield opportunities for requested chain/asset")
 	}
+	yieldutil.ApplyEffectiveAPY(out)
+	yieldutil.ApplyRisk(out)
 	yieldutil.Sort(out, req.SortBy)
 	if req.Limit <= 0 || req.Limit > len(out) {
 		req.Limit = len(out)
@@ -655,7 +653,7 @@ func (c *Client) YieldHistory(ctx context.Context, req providers.YieldHistoryReq
 		}
 		points = append(points, model.YieldHistoryPoint{
 			Timestamp: ts.UTC().Format(time.RFC3339),
-			Value:     parseFloat(sample.AvgRate.Value) * 100,
+			Value:     yieldutil.PercentFromRatio(parseFloat(sample.AvgRate.Value)),
 		})
 	}
 	if req.Interval == providers.YieldHistoryIntervalDay {
@@ -688,10 +686,62 @@ func (c *Client) YieldHistory(ctx context.Context, req providers.YieldHistoryReq
 	return series, nil
 }
 
+// fetchPositions returns the account's supplies and borrows across every
+// Aave market of the chain.
+func (c *Client) fetchPositions(ctx context.Context, chain id.Chain, account string) (positionsResponse, error) {
+	marketAddresses, err := c.fetchMarketAddresses(ctx, chain)
+	if err != nil {
+		return positionsResponse{}, err
+	}
+	markets := make([]map[string]any, 0, len(marketAddresses))
+	for _, address := range marketAddresses {
+		markets = append(markets, map[string]any{
+			"address": address,
+			"chainId": chain.EVMChainID,
+		})
+	}
+
+	body, err := json.Marshal(map[string]any{
+		"query": positionsQuery,
+		"variables": map[string]any{
+			"suppliesRequest": map[string]any{
+				"markets":         markets,
+				"user":            account,
+				"collateralsOnly": false,
+				"orderBy": map[string]any{
+					"balance": "DESC",
+				},
+			},
+			"borrowsRequest": map[string]any{
+				"markets": markets,
+				"user":    account,
+				"orderBy": map[string]any{
+					"debt": "DESC",
+				},
+			},
+		},
+	})
+	if err != nil {
+		return positionsResponse{}, clierr.Wrap(clierr.CodeInternal, "marshal aave positions query", err)
+	}
+
+	var resp positionsResponse
+	if _, err := httpx.DoBodyJSON(ctx, c.http, http.MethodPost, c.endpoint, body, nil, &resp); err != nil {
+		return positionsResponse{}, err
+	}
+	if len(resp.Errors) > 0 {
+		return positionsResponse{}, clierr.New(clierr.CodeUnavailable, fmt.Sprintf("aave graphql error: %s", resp.Errors[0].Message))
+	}
+	return resp, nil
+}
+
 func (c *Client) fetchMarkets(ctx context.Context, chain id.Chain) ([]aaveMarket, error) {
 	if !chain.IsEVM() {
 		return nil, clierr.New(clierr.CodeUnsupported, "aave supports only EVM chains")
 	}
+	if c.backend == providers.DataBackendSubgraph {
+		return c.fetchSubgraphMarkets(ctx, chain)
+	}
 	body, err := json.Marshal(map[string]any{
 		"query": marketsQuery,
 		"variables": map[string]any{
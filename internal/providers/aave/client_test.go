@@ -325,3 +325,98 @@ func TestYieldHistoryRejectsUnsupportedMetric(t *testing.T) {
 		t.Fatal("expected unsupported metric error")
 	}
 }
+
+func TestLendHealth(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case strings.Contains(string(body), "MarketAddresses"):
+			_, _ = w.Write([]byte(`{"data": {"markets": [{"address": "0x1111111111111111111111111111111111111111"}]}}`))
+		case strings.Contains(string(body), "Positions"):
+			_, _ = w.Write([]byte(`{
+				"data": {
+					"userSupplies": [
+						{
+							"market": {"address": "0x1111111111111111111111111111111111111111"},
+							"currency": {"address": "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2", "symbol": "WETH", "decimals": 18},
+							"balance": {"amount": {"raw": "4000000000000000000", "decimals": 18, "value": "4"}, "usd": "10000"},
+							"isCollateral": true
+						},
+						{
+							"market": {"address": "0x1111111111111111111111111111111111111111"},
+							"currency": {"address": "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48", "symbol": "USDC", "decimals": 6},
+							"balance": {"amount": {"raw": "2000000000", "decimals": 6, "value": "2000"}, "usd": "2000"},
+							"isCollateral": true
+						},
+						{
+							"market": {"address": "0x1111111111111111111111111111111111111111"},
+							"currency": {"address": "0xdac17f958d2ee523a2206206994597c13d831ec7", "symbol": "USDT", "decimals": 6},
+							"balance": {"amount": {"raw": "5000000000", "decimals": 6, "value": "5000"}, "usd": "5000"},
+							"isCollateral": false
+						}
+					],
+					"userBorrows": [
+						{
+							"market": {"address": "0x1111111111111111111111111111111111111111"},
+							"currency": {"address": "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48", "symbol": "USDC", "decimals": 6},
+							"debt": {"amount": {"raw": "6000000000", "decimals": 6, "value": "6000"}, "usd": "6000"}
+						}
+					]
+				}
+			}`))
+		case strings.Contains(string(body), "query Markets("):
+			_, _ = w.Write([]byte(`{
+				"data": {
+					"markets": [{
+						"address": "0x1111111111111111111111111111111111111111",
+						"chain": {"chainId": 1, "name": "Ethereum"},
+						"reserves": [
+							{"underlyingToken": {"address": "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2", "symbol": "WETH", "decimals": 18}, "supplyInfo": {"maxLTV": {"value": "0.80"}, "liquidationThreshold": {"value": "0.83"}}},
+							{"underlyingToken": {"address": "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48", "symbol": "USDC", "decimals": 6}, "supplyInfo": {"maxLTV": {"value": "0.75"}, "liquidationThreshold": {"value": "0.78"}}}
+						]
+					}]
+				}
+			}`))
+		default:
+			_, _ = w.Write([]byte(`{"errors":[{"message":"unexpected query"}]}`))
+		}
+	}))
+	defer srv.Close()
+
+	client := New(httpx.New(2*time.Second, 0))
+	client.endpoint = srv.URL
+	chain, _ := id.ParseChain("ethereum")
+
+	rows, err := client.LendHealth(context.Background(), providers.LendHealthRequest{
+		Chain:   chain,
+		Account: "0x000000000000000000000000000000000000dEaD",
+	})
+	if err != nil {
+		t.Fatalf("LendHealth failed: %v", err)
+	}
+	if len(rows) != 1 {
+		t.Fatalf("expected one market row, got %+v", rows)
+	}
+	row := rows[0]
+	if row.HealthFactor == nil || *row.HealthFactor != 1.6433 {
+		t.Fatalf("expected health factor 1.6433, got %v", row.HealthFactor)
+	}
+	if row.CollateralUSD != 12000 || row.DebtUSD != 6000 || row.LTVPct != 50 || row.MaxLTVPct != 79.17 || row.LiquidationThresholdPct != 82.17 {
+		t.Fatalf("unexpected market totals: %+v", row)
+	}
+	if row.LiquidationPriceMovePct == nil || *row.LiquidationPriceMovePct != -39.15 {
+		t.Fatalf("expected basket liquidation move -39.15, got %v", row.LiquidationPriceMovePct)
+	}
+	if len(row.Collateral) != 2 {
+		t.Fatalf("expected non-collateral supply to be excluded, got %+v", row.Collateral)
+	}
+	weth, usdc := row.Collateral[0], row.Collateral[1]
+	if weth.LiquidationThresholdPct != 83 || weth.LiquidationPriceMovePct == nil || *weth.LiquidationPriceMovePct != -46.51 {
+		t.Fatalf("unexpected WETH collateral: %+v", weth)
+	}
+	if usdc.LiquidationPriceMovePct != nil {
+		t.Fatalf("expected USDC alone not to trigger liquidation, got %v", *usdc.LiquidationPriceMovePct)
+	}
+}
@@ -0,0 +1,144 @@
+package aave
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/model"
+	"github.com/ggonzalez94/defi-cli/internal/providers"
+	"github.com/ggonzalez94/defi-cli/internal/providers/yieldutil"
+)
+
+// LendHealth returns one row per Aave market in which the account has
+// collateral or debt, riskiest first. The health factor is computed from the
+// USD value of collateral weighted by each reserve's base liquidation
+// threshold, so accounts in e-mode, which raises thresholds, read lower than
+// the Aave UI.
+func (c *Client) LendHealth(ctx context.Context, req providers.LendHealthRequest) ([]model.LendHealth, error) {
+	if !req.Chain.IsEVM() {
+		return nil, clierr.New(clierr.CodeUnsupported, "aave supports only EVM chains")
+	}
+	account := normalizeEVMAddress(req.Account)
+	if account == "" {
+		return nil, clierr.New(clierr.CodeUsage, "aave health requires a valid EVM account address")
+	}
+	positions, err := c.fetchPositions(ctx, req.Chain, account)
+	if err != nil {
+		return nil, err
+	}
+	markets, err := c.fetchMarkets(ctx, req.Chain)
+	if err != nil {
+		return nil, err
+	}
+	reserves := map[string]aaveReserve{}
+	for _, market := range markets {
+		for _, reserve := range market.Reserves {
+			reserves[normalizeEVMAddress(market.Address)+"|"+normalizeEVMAddress(reserve.UnderlyingToken.Address)] = reserve
+		}
+	}
+
+	fetchedAt := c.now().UTC().Format(time.RFC3339)
+	byMarket := map[string]*model.LendHealth{}
+	order := []string{}
+	marketHealth := func(address string) *model.LendHealth {
+		address = normalizeEVMAddress(address)
+		if row, ok := byMarket[address]; ok {
+			return row
+		}
+		row := &model.LendHealth{
+			Protocol:       "aave",
+			Provider:       "aave",
+			ChainID:        req.Chain.CAIP2,
+			AccountAddress: account,
+			MarketAddress:  address,
+			Collateral:     []model.LendHealthCollateral{},
+			SourceURL:      "https://app.aave.com",
+			FetchedAt:      fetchedAt,
+		}
+		byMarket[address] = row
+		order = append(order, address)
+		return row
+	}
+
+	for _, supply := range positions.Data.UserSupplies {
+		amountUSD := parseFloat(supply.Balance.USD)
+		if !supply.IsCollateral || amountUSD <= 0 {
+			continue
+		}
+		reserve := reserves[normalizeEVMAddress(supply.Market.Address)+"|"+normalizeEVMAddress(supply.Currency.Address)]
+		row := marketHealth(supply.Market.Address)
+		row.CollateralUSD += amountUSD
+		row.Collateral = append(row.Collateral, model.LendHealthCollateral{
+			AssetID:                 canonicalAssetIDForChain(req.Chain.CAIP2, supply.Currency.Address),
+			Symbol:                  supply.Currency.Symbol,
+			AmountUSD:               amountUSD,
+			MaxLTVPct:               yieldutil.PercentFromRatio(parseFloat(reserve.SupplyInfo.MaxLTV.Value)),
+			LiquidationThresholdPct: yieldutil.PercentFromRatio(parseFloat(reserve.SupplyInfo.LiquidationThreshold.Value)),
+		})
+	}
+	for _, borrow := range positions.Data.UserBorrows {
+		if amountUSD := parseFloat(borrow.Debt.USD); amountUSD > 0 {
+			marketHealth(borrow.Market.Address).DebtUSD += amountUSD
+		}
+	}
+
+	out := make([]model.LendHealth, 0, len(order))
+	for _, address := range order {
+		row := byMarket[address]
+		applyHealth(row)
+		out = append(out, *row)
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if (out[i].HealthFactor == nil) != (out[j].HealthFactor == nil) {
+			return out[i].HealthFactor != nil
+		}
+		return out[i].HealthFactor != nil && *out[i].HealthFactor < *out[j].HealthFactor
+	})
+	return out, nil
+}
+
+// applyHealth fills the market totals of row from its collateral and debt.
+// Collateral is liquidatable once its threshold-weighted value falls to the
+// debt, so the whole basket may drop by 1-1/HF and a single asset by the
+// threshold-weighted surplus over its own weighted value.
+func applyHealth(row *model.LendHealth) {
+	var maxBorrowUSD, liquidationUSD float64
+	for _, collateral := range row.Collateral {
+		maxBorrowUSD += collateral.AmountUSD * collateral.MaxLTVPct / 100
+		liquidationUSD += collateral.AmountUSD * collateral.LiquidationThresholdPct / 100
+	}
+	if row.CollateralUSD > 0 {
+		row.LTVPct = round2(row.DebtUSD / row.CollateralUSD * 100)
+		row.MaxLTVPct = round2(maxBorrowUSD / row.CollateralUSD * 100)
+		row.LiquidationThresholdPct = round2(liquidationUSD / row.CollateralUSD * 100)
+	}
+	if row.DebtUSD <= 0 {
+		return
+	}
+	healthFactor := math.Round(liquidationUSD/row.DebtUSD*10000) / 10000
+	row.HealthFactor = &healthFactor
+	if liquidationUSD <= 0 {
+		return
+	}
+	basketMove := round2((row.DebtUSD/liquidationUSD - 1) * 100)
+	row.LiquidationPriceMovePct = &basketMove
+	for i := range row.Collateral {
+		weighted := row.Collateral[i].AmountUSD * row.Collateral[i].LiquidationThresholdPct / 100
+		if weighted <= 0 {
+			continue
+		}
+		drop := (liquidationUSD - row.DebtUSD) / weighted
+		if drop > 1 {
+			continue
+		}
+		move := round2(-math.Max(drop, 0) * 100)
+		row.Collateral[i].LiquidationPriceMovePct = &move
+	}
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
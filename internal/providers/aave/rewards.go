@@ -0,0 +1,179 @@
+package aave
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/model"
+	"github.com/ggonzalez94/defi-cli/internal/providers"
+	"github.com/ggonzalez94/defi-cli/internal/registry"
+	"github.com/ggonzalez94/defi-cli/internal/rpcx"
+)
+
+var (
+	poolAddressProviderABI = mustABI(registry.AavePoolAddressProviderABI)
+	rewardsControllerABI   = mustABI(registry.AaveRewardsABI)
+	erc20MetadataABI       = mustABI(registry.ERC20MetadataABI)
+)
+
+// ClaimableRewards reads the account's unclaimed incentives from the Aave
+// RewardsController across every aToken and variable debt token of the
+// chain's markets. Those tokens are returned as Sources, the --assets of a
+// rewards claim.
+func (c *Client) ClaimableRewards(ctx context.Context, req providers.RewardsClaimableRequest) ([]model.ClaimableReward, error) {
+	account := normalizeEVMAddress(req.Account)
+	if account == "" {
+		return nil, clierr.New(clierr.CodeUsage, "aave rewards requires a valid EVM account address")
+	}
+	markets, err := c.fetchMarkets(ctx, req.Chain)
+	if err != nil {
+		return nil, err
+	}
+	sources := rewardSources(markets)
+	if len(sources) == 0 {
+		return nil, clierr.New(clierr.CodeUnavailable, "aave markets returned no reward-bearing tokens")
+	}
+	providerAddr, ok := registry.AavePoolAddressProvider(req.Chain.EVMChainID)
+	if !ok {
+		return nil, clierr.New(clierr.CodeUnsupported, "aave incentives controller is unavailable for this chain")
+	}
+
+	rpcURL, err := registry.ResolveRPCURL(req.RPCURL, req.Chain.EVMChainID)
+	if err != nil {
+		return nil, clierr.Wrap(clierr.CodeUnsupported, "resolve rpc url", err)
+	}
+	client, err := rpcx.DialContext(ctx, rpcURL)
+	if err != nil {
+		return nil, clierr.Wrap(clierr.CodeUnavailable, "connect rpc", err)
+	}
+	defer client.Close()
+
+	controller, err := incentivesController(ctx, client, common.HexToAddress(providerAddr))
+	if err != nil {
+		return nil, err
+	}
+	assets := make([]common.Address, 0, len(sources))
+	for _, source := range sources {
+		assets = append(assets, common.HexToAddress(source))
+	}
+	decoded, err := callView(ctx, client, rewardsControllerABI, controller, "getAllUserRewards", assets, common.HexToAddress(account))
+	if err != nil {
+		return nil, err
+	}
+	if len(decoded) != 2 {
+		return nil, clierr.New(clierr.CodeUnavailable, "invalid aave getAllUserRewards response")
+	}
+	rewardTokens, _ := decoded[0].([]common.Address)
+	amounts, _ := decoded[1].([]*big.Int)
+	if len(rewardTokens) != len(amounts) {
+		return nil, clierr.New(clierr.CodeUnavailable, "invalid aave getAllUserRewards response")
+	}
+
+	fetchedAt := c.now().UTC().Format(time.RFC3339)
+	out := make([]model.ClaimableReward, 0, len(rewardTokens))
+	for i, token := range rewardTokens {
+		if amounts[i] == nil || amounts[i].Sign() <= 0 {
+			continue
+		}
+		symbol, decimals := tokenMetadata(ctx, client, req.Chain, token)
+		out = append(out, model.ClaimableReward{
+			Provider:      "aave",
+			ChainID:       req.Chain.CAIP2,
+			Account:       account,
+			RewardAssetID: canonicalAssetIDForChain(req.Chain.CAIP2, token.Hex()),
+			Symbol:        symbol,
+			Claimable:     amountInfoFromRaw(amounts[i].String(), decimals),
+			Distributor:   controller.Hex(),
+			Sources:       sources,
+			SourceURL:     "https://app.aave.com",
+			FetchedAt:     fetchedAt,
+		})
+	}
+	return out, nil
+}
+
+func rewardSources(markets []aaveMarket) []string {
+	seen := map[string]struct{}{}
+	out := []string{}
+	for _, market := range markets {
+		for _, reserve := range market.Reserves {
+			for _, address := range []string{reserve.AToken.Address, reserve.VToken.Address} {
+				address = normalizeEVMAddress(address)
+				if address == "" {
+					continue
+				}
+				if _, ok := seen[address]; ok {
+					continue
+				}
+				seen[address] = struct{}{}
+				out = append(out, address)
+			}
+		}
+	}
+	return out
+}
+
+func incentivesController(ctx context.Context, client *ethclient.Client, provider common.Address) (common.Address, error) {
+	decoded, err := callView(ctx, client, poolAddressProviderABI, provider, "getAddress", crypto.Keccak256Hash([]byte("INCENTIVES_CONTROLLER")))
+	if err != nil {
+		return common.Address{}, err
+	}
+	controller, ok := decoded[0].(common.Address)
+	if !ok || controller == (common.Address{}) {
+		return common.Address{}, clierr.New(clierr.CodeUnavailable, "aave incentives controller is not configured for this chain")
+	}
+	return controller, nil
+}
+
+// tokenMetadata returns the symbol and decimals of token from the asset
+// registry, falling back to the token contract.
+func tokenMetadata(ctx context.Context, client *ethclient.Client, chain id.Chain, token common.Address) (string, int) {
+	if asset, err := id.ParseAsset(token.Hex(), chain); err == nil && asset.Symbol != "" && asset.Decimals > 0 {
+		return asset.Symbol, asset.Decimals
+	}
+	symbol, decimals := "", 18
+	if decoded, err := callView(ctx, client, erc20MetadataABI, token, "symbol"); err == nil {
+		symbol, _ = decoded[0].(string)
+	}
+	if decoded, err := callView(ctx, client, erc20MetadataABI, token, "decimals"); err == nil {
+		if value, ok := decoded[0].(uint8); ok {
+			decimals = int(value)
+		}
+	}
+	return symbol, decimals
+}
+
+func callView(ctx context.Context, client *ethclient.Client, contract abi.ABI, target common.Address, method string, args ...any) ([]any, error) {
+	data, err := contract.Pack(method, args...)
+	if err != nil {
+		return nil, clierr.Wrap(clierr.CodeInternal, fmt.Sprintf("pack %s calldata", method), err)
+	}
+	out, err := client.CallContract(ctx, ethereum.CallMsg{To: &target, Data: data}, nil)
+	if err != nil {
+		return nil, clierr.Wrap(clierr.CodeUnavailable, fmt.Sprintf("call %s", method), err)
+	}
+	decoded, err := contract.Unpack(method, out)
+	if err != nil || len(decoded) == 0 {
+		return nil, clierr.Wrap(clierr.CodeUnavailable, fmt.Sprintf("decode %s", method), err)
+	}
+	return decoded, nil
+}
+
+func mustABI(raw string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(raw))
+	if err != nil {
+		panic(fmt.Sprintf("invalid ABI: %v", err))
+	}
+	return parsed
+}
@@ -0,0 +1,186 @@
+package aave
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/httpx"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/registry"
+)
+
+const subgraphReservesQuery = `{
+  reserves(first: 500, where: {isActive: true}) {
+    underlyingAsset
+    symbol
+    decimals
+    liquidityRate
+    variableBorrowRate
+    totalATokenSupply
+    totalCurrentVariableDebt
+    availableLiquidity
+    baseLTVasCollateral
+    reserveLiquidationThreshold
+    borrowingEnabled
+    aToken { id }
+    vToken { id }
+    price { priceInEth }
+    pool { pool }
+  }
+}`
+
+// Aave v3 subgraph prices are in the pool's USD base currency (8 decimals);
+// rates are APRs in ray.
+const (
+	subgraphPriceDecimals = 8
+	secondsPerYear        = 365 * 24 * 3600
+)
+
+type subgraphReservesResponse struct {
+	Data struct {
+		Reserves []subgraphReserve `json:"reserves"`
+	} `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+type subgraphReserve struct {
+	UnderlyingAsset             string `json:"underlyingAsset"`
+	Symbol                      string `json:"symbol"`
+	Decimals                    int    `json:"decimals"`
+	LiquidityRate               string `json:"liquidityRate"`
+	VariableBorrowRate          string `json:"variableBorrowRate"`
+	TotalATokenSupply           string `json:"totalATokenSupply"`
+	TotalCurrentVariableDebt    string `json:"totalCurrentVariableDebt"`
+	AvailableLiquidity          string `json:"availableLiquidity"`
+	BaseLTVasCollateral         string `json:"baseLTVasCollateral"`
+	ReserveLiquidationThreshold string `json:"reserveLiquidationThreshold"`
+	BorrowingEnabled            bool   `json:"borrowingEnabled"`
+	AToken                      struct {
+		ID string `json:"id"`
+	} `json:"aToken"`
+	VToken struct {
+		ID string `json:"id"`
+	} `json:"vToken"`
+	Price struct {
+		PriceInEth string `json:"priceInEth"`
+	} `json:"price"`
+	Pool struct {
+		Pool string `json:"pool"`
+	} `json:"pool"`
+}
+
+func defaultSubgraphEndpoint(chainID int64) (string, bool) {
+	subgraphID, ok := registry.AaveV3SubgraphID(chainID)
+	if !ok {
+		return "", false
+	}
+	return registry.TheGraphGatewayURL + subgraphID, true
+}
+
+// fetchSubgraphMarkets reads the chain's reserves from its Aave v3 subgraph
+// and groups them by pool in the API's market shape.
+func (c *Client) fetchSubgraphMarkets(ctx context.Context, chain id.Chain) ([]aaveMarket, error) {
+	if c.subgraphKey == "" {
+		return nil, clierr.New(clierr.CodeAuth, "missing required API key for The Graph (DEFI_THEGRAPH_API_KEY)")
+	}
+	endpoint, ok := c.subgraphEndpoint(chain.EVMChainID)
+	if !ok {
+		return nil, clierr.New(clierr.CodeUnsupported, "no aave subgraph for requested chain")
+	}
+	body, err := json.Marshal(map[string]any{"query": subgraphReservesQuery})
+	if err != nil {
+		return nil, clierr.Wrap(clierr.CodeInternal, "marshal aave subgraph query", err)
+	}
+	var resp subgraphReservesResponse
+	headers := map[string]string{"Authorization": "Bearer " + c.subgraphKey}
+	if _, err := httpx.DoBodyJSON(ctx, c.http, http.MethodPost, endpoint, body, headers, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Errors) > 0 {
+		return nil, clierr.New(clierr.CodeUnavailable, fmt.Sprintf("aave subgraph error: %s", resp.Errors[0].Message))
+	}
+
+	byPool := map[string]int{}
+	markets := make([]aaveMarket, 0, 1)
+	for _, sr := range resp.Data.Reserves {
+		pool := normalizeEVMAddress(sr.Pool.Pool)
+		if pool == "" || normalizeEVMAddress(sr.UnderlyingAsset) == "" {
+			continue
+		}
+		i, ok := byPool[pool]
+		if !ok {
+			i = len(markets)
+			byPool[pool] = i
+			market := aaveMarket{Address: pool}
+			market.Chain.ChainID = chain.EVMChainID
+			markets = append(markets, market)
+		}
+		markets[i].Reserves = append(markets[i].Reserves, sr.toReserve())
+	}
+	if len(markets) == 0 {
+		return nil, clierr.New(clierr.CodeUnsupported, "aave has no market for requested chain")
+	}
+	return markets, nil
+}
+
+// toReserve converts raw subgraph units to the API's reserve fields: APYs
+// and ratios as decimal strings, sizes in USD.
+func (sr subgraphReserve) toReserve() aaveReserve {
+	var r aaveReserve
+	r.UnderlyingToken.Address = sr.UnderlyingAsset
+	r.UnderlyingToken.Symbol = sr.Symbol
+	r.UnderlyingToken.Decimals = sr.Decimals
+	r.AToken.Address = sr.AToken.ID
+	r.VToken.Address = sr.VToken.ID
+
+	price := baseUnitsToFloat(sr.Price.PriceInEth, subgraphPriceDecimals)
+	supplied := baseUnitsToFloat(sr.TotalATokenSupply, sr.Decimals)
+	borrowed := baseUnitsToFloat(sr.TotalCurrentVariableDebt, sr.Decimals)
+	available := baseUnitsToFloat(sr.AvailableLiquidity, sr.Decimals)
+
+	r.Size.USD = formatFloat(supplied * price)
+	r.SupplyInfo.APY.Value = formatFloat(rayAPRToAPY(sr.LiquidityRate))
+	r.SupplyInfo.Total.Value = formatFloat(supplied)
+	r.SupplyInfo.MaxLTV.Value = formatFloat(parseFloat(sr.BaseLTVasCollateral) / 10_000)
+	r.SupplyInfo.LiquidationThreshold.Value = formatFloat(parseFloat(sr.ReserveLiquidationThreshold) / 10_000)
+	if sr.BorrowingEnabled {
+		borrow := &aaveBorrowInfo{}
+		borrow.APY.Value = formatFloat(rayAPRToAPY(sr.VariableBorrowRate))
+		borrow.Total.USD = formatFloat(borrowed * price)
+		borrow.AvailableLiquidity.USD = formatFloat(available * price)
+		if supplied > 0 {
+			borrow.UtilizationRate.Value = formatFloat(borrowed / supplied)
+		}
+		r.BorrowInfo = borrow
+	}
+	return r
+}
+
+// rayAPRToAPY compounds a ray-scaled APR per second, as the Aave API does.
+func rayAPRToAPY(raw string) float64 {
+	apr := baseUnitsToFloat(raw, 27)
+	return math.Pow(1+apr/secondsPerYear, secondsPerYear) - 1
+}
+
+func baseUnitsToFloat(raw string, decimals int) float64 {
+	v, ok := new(big.Float).SetString(strings.TrimSpace(raw))
+	if !ok {
+		return 0
+	}
+	scale := new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil))
+	out, _ := v.Quo(v, scale).Float64()
+	return out
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
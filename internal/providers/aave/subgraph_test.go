@@ -0,0 +1,86 @@
+package aave
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/httpx"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+)
+
+func TestLendMarketsFromSubgraph(t *testing.T) {
+	var gotAuth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"data": {
+				"reserves": [
+					{
+						"underlyingAsset": "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48",
+						"symbol": "USDC",
+						"decimals": 6,
+						"liquidityRate": "30000000000000000000000000",
+						"variableBorrowRate": "50000000000000000000000000",
+						"totalATokenSupply": "2000000000000",
+						"totalCurrentVariableDebt": "1000000000000",
+						"availableLiquidity": "1000000000000",
+						"baseLTVasCollateral": "7500",
+						"reserveLiquidationThreshold": "7800",
+						"borrowingEnabled": true,
+						"aToken": {"id": "0x98c23e9d8f34fefb1b7bd6a91b7ff122f4e16f5c"},
+						"vToken": {"id": "0x72e95b8931767c79ba4eee721354d6e99a61d004"},
+						"price": {"priceInEth": "100000000"},
+						"pool": {"pool": "0x87870bca3f3fd6335c3f4ce8392d69350b4fa4e2"}
+					}
+				]
+			}
+		}`))
+	}))
+	defer srv.Close()
+
+	client := NewSubgraph(httpx.New(2*time.Second, 0), "graph-key")
+	client.subgraphEndpoint = func(int64) (string, bool) { return srv.URL, true }
+	chain, _ := id.ParseChain("ethereum")
+	asset, _ := id.ParseAsset("USDC", chain)
+
+	markets, err := client.LendMarkets(context.Background(), "aave", chain, asset)
+	if err != nil {
+		t.Fatalf("LendMarkets failed: %v", err)
+	}
+	if gotAuth != "Bearer graph-key" {
+		t.Fatalf("expected bearer api key, got %q", gotAuth)
+	}
+	if len(markets) != 1 || markets[0].TVLUSD != 2_000_000 {
+		t.Fatalf("expected one $2M market, got %+v", markets)
+	}
+	if want := (math.Exp(0.03) - 1) * 100; math.Abs(markets[0].SupplyAPY-want) > 0.001 {
+		t.Fatalf("expected supply APY ~%.4f, got %.4f", want, markets[0].SupplyAPY)
+	}
+	if markets[0].ProviderNativeID != "aave:eip155:1:0x87870bca3f3fd6335c3f4ce8392d69350b4fa4e2:0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48" {
+		t.Fatalf("expected api-compatible provider native id, got %s", markets[0].ProviderNativeID)
+	}
+
+	rates, err := client.LendRates(context.Background(), "aave", chain, asset)
+	if err != nil {
+		t.Fatalf("LendRates failed: %v", err)
+	}
+	if len(rates) != 1 || rates[0].Utilization != 0.5 || rates[0].BorrowAPY <= rates[0].SupplyAPY {
+		t.Fatalf("unexpected subgraph rates %+v", rates)
+	}
+}
+
+func TestSubgraphRequiresAPIKey(t *testing.T) {
+	client := NewSubgraph(httpx.New(2*time.Second, 0), " ")
+	chain, _ := id.ParseChain("ethereum")
+	asset, _ := id.ParseAsset("USDC", chain)
+	_, err := client.LendMarkets(context.Background(), "aave", chain, asset)
+	if cErr, ok := clierr.As(err); !ok || cErr.Code != clierr.CodeAuth {
+		t.Fatalf("expected auth error without a graph api key, got %v", err)
+	}
+}
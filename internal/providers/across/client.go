@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ggonzalez94/defi-cli/internal/clock"
 	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
 	"github.com/ggonzalez94/defi-cli/internal/execution"
 	"github.com/ggonzalez94/defi-cli/internal/httpx"
@@ -29,7 +30,7 @@ type Client struct {
 }
 
 func New(httpClient *httpx.Client) *Client {
-	return &Client{http: httpClient, baseURL: defaultBase, now: time.Now}
+	return &Client{http: httpClient, baseURL: defaultBase, now: clock.Now}
 }
 
 func (c *Client) Info() model.ProviderInfo {
@@ -42,6 +43,7 @@ func (c *Client) Info() model.ProviderInfo {
 			"bridge.plan",
 			"bridge.execute",
 		},
+		Support: providers.ChainSupport([]string{providers.AnyEVMChain}, "bridge.quote", "bridge.plan", "bridge.execute"),
 	}
 }
 
@@ -55,7 +57,11 @@ func (c *Client) QuoteBridge(ctx context.Context, req providers.BridgeQuoteReque
 	vals := url.Values{}
 	vals.Set("originChainId", chainFrom)
 	vals.Set("destinationChainId", chainTo)
-	vals.Set("token", req.FromAsset.Address)
+	token, err := quoteToken(req.FromChain, req.FromAsset)
+	if err != nil {
+		return model.BridgeQuote{}, err
+	}
+	vals.Set("token", token)
 	vals.Set("amount", req.AmountBaseUnits)
 
 	limitsURL := c.baseURL + "/limits?" + vals.Encode()
@@ -190,8 +196,8 @@ func (c *Client) BuildBridgeAction(ctx context.Context, req providers.BridgeQuot
 
 	vals := url.Values{}
 	vals.Set("amount", req.AmountBaseUnits)
-	vals.Set("inputToken", req.FromAsset.Address)
-	vals.Set("outputToken", req.ToAsset.Address)
+	vals.Set("inputToken", swapToken(req.FromAsset))
+	vals.Set("outputToken", swapToken(req.ToAsset))
 	vals.Set("originChainId", strconv.FormatInt(req.FromChain.EVMChainID, 10))
 	vals.Set("destinationChainId", strconv.FormatInt(req.ToChain.EVMChainID, 10))
 	vals.Set("depositor", sender)
@@ -283,6 +289,30 @@ func (c *Client) BuildBridgeAction(ctx context.Context, req providers.BridgeQuot
 	return action, nil
 }
 
+// nativeToken is the address the Across swap API uses for the gas token.
+const nativeToken = "0x0000000000000000000000000000000000000000"
+
+// quoteToken returns the origin token for /limits and /suggested-fees, which
+// quote native deposits against the chain's wrapped gas token.
+func quoteToken(chain id.Chain, asset id.Asset) (string, error) {
+	if !asset.IsNative() {
+		return asset.Address, nil
+	}
+	wrapped, ok := id.WrappedNativeToken(chain)
+	if !ok {
+		return "", clierr.New(clierr.CodeUnsupported, fmt.Sprintf("across native quotes need a registered wrapped %s on %s", id.NativeSymbol(chain), chain.Slug))
+	}
+	return wrapped.Address, nil
+}
+
+// swapToken maps the registry's native placeholder to the swap API sentinel.
+func swapToken(asset id.Asset) string {
+	if asset.IsNative() {
+		return nativeToken
+	}
+	return asset.Address
+}
+
 func checkAmountWithinLimits(amount string, limits map[string]any) bool {
 	min := pickNumberString(limits, "minDeposit", "minLimit")
 	max := pickNumberString(limits, "maxDeposit", "maxLimit")
@@ -275,6 +275,25 @@ func TestBuildBridgeActionRejectsInvalidSwapTarget(t *testing.T) {
 	}
 }
 
+func TestNativeTokenMapping(t *testing.T) {
+	chain, _ := id.ParseChain("ethereum")
+	eth, err := id.ParseAsset("ETH", chain)
+	if err != nil {
+		t.Fatalf("ParseAsset(ETH) failed: %v", err)
+	}
+	weth, _ := id.ParseAsset("WETH", chain)
+	token, err := quoteToken(chain, eth)
+	if err != nil || token != weth.Address {
+		t.Fatalf("expected native quotes against WETH, got %q err=%v", token, err)
+	}
+	if got := swapToken(eth); got != nativeToken {
+		t.Fatalf("expected the swap API native sentinel, got %s", got)
+	}
+	if got := swapToken(weth); got != weth.Address {
+		t.Fatalf("expected ERC-20 addresses to pass through, got %s", got)
+	}
+}
+
 func TestApproximateStableUSDExcludesEURS(t *testing.T) {
 	if isLikelyStableSymbol("EURS") {
 		t.Fatal("EURS should not be treated as USD-pegged")
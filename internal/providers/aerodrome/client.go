@@ -0,0 +1,551 @@
+// Package aerodrome reports ve(3,3) DEX liquidity pool yields for Aerodrome
+// on Base and Velodrome v2 on Optimism. Aerodrome is a Velodrome v2 fork, so
+// one client serves both: pool APRs and TVL come from the DefiLlama yields
+// API and gauge bribes are read from the protocol's Voter over RPC.
+package aerodrome
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ggonzalez94/defi-cli/internal/clock"
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/httpx"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/model"
+	"github.com/ggonzalez94/defi-cli/internal/providers"
+	"github.com/ggonzalez94/defi-cli/internal/providers/multicall"
+	"github.com/ggonzalez94/defi-cli/internal/providers/yieldutil"
+	"github.com/ggonzalez94/defi-cli/internal/registry"
+	"github.com/ggonzalez94/defi-cli/internal/rpcx"
+)
+
+const (
+	defaultBase     = "https://yields.llama.fi"
+	withdrawalTerms = "instant; unstake from the gauge and remove liquidity"
+
+	// poolsTTL matches the staking provider so both reuse one /pools payload.
+	poolsTTL = 60 * time.Second
+
+	// epochDuration is the ve(3,3) voting epoch. Epochs flip every Thursday
+	// 00:00 UTC, which is a whole number of weeks after the unix epoch.
+	epochDuration = 7 * 24 * time.Hour
+
+	// maxBribeTokens caps the reward tokens read per bribe contract.
+	maxBribeTokens = 16
+)
+
+// dex describes one ve(3,3) deployment.
+type dex struct {
+	name       string
+	chainID    int64
+	llamaChain string
+	projects   []string
+	sourceURL  string
+}
+
+var (
+	aerodromeDEX = dex{
+		name:       "aerodrome",
+		chainID:    8453,
+		llamaChain: "Base",
+		projects:   []string{"aerodrome-v1", "aerodrome-slipstream"},
+		sourceURL:  "https://aerodrome.finance/liquidity",
+	}
+	velodromeDEX = dex{
+		name:       "velodrome",
+		chainID:    10,
+		llamaChain: "Optimism",
+		projects:   []string{"velodrome-v2", "velodrome-slipstream"},
+		sourceURL:  "https://velodrome.finance/liquidity",
+	}
+)
+
+var (
+	zero = common.Address{}
+)
+
+type Client struct {
+	dex         dex
+	http        *httpx.Client
+	baseURL     string
+	now         func() time.Time
+	rpcOverride string
+}
+
+// New returns the Aerodrome (Base) provider.
+func New(httpClient *httpx.Client) *Client {
+	return newClient(httpClient, aerodromeDEX)
+}
+
+// NewVelodrome returns the Velodrome v2 (Optimism) provider.
+func NewVelodrome(httpClient *httpx.Client) *Client {
+	return newClient(httpClient, velodromeDEX)
+}
+
+func newClient(httpClient *httpx.Client, d dex) *Client {
+	return &Client{dex: d, http: httpClient, baseURL: defaultBase, now: clock.Now}
+}
+
+// SetRPCOverride sets the RPC URL used for gauge bribe reads. Pass "" to
+// revert to the default.
+func (c *Client) SetRPCOverride(url string) { c.rpcOverride = url }
+
+func (c *Client) Info() model.ProviderInfo {
+	return model.ProviderInfo{
+		Name:        c.dex.name,
+		Type:        "yield",
+		RequiresKey: false,
+		Capabilities: []string{
+			"yield.opportunities",
+		},
+		Support: providers.ChainSupport(providers.EVMChains([]int64{c.dex.chainID}), "yield.opportunities"),
+	}
+}
+
+type poolsResponse struct {
+	Status string `json:"status"`
+	Data   []pool `json:"data"`
+}
+
+type pool struct {
+	Chain            string   `json:"chain"`
+	Project          string   `json:"project"`
+	Symbol           string   `json:"symbol"`
+	Pool             string   `json:"pool"`
+	TVLUSD           float64  `json:"tvlUsd"`
+	APYBase          *float64 `json:"apyBase"`
+	APYReward        *float64 `json:"apyReward"`
+	APY              *float64 `json:"apy"`
+	Stablecoin       bool     `json:"stablecoin"`
+	UnderlyingTokens []string `json:"underlyingTokens"`
+}
+
+// YieldOpportunities lists the DEX's liquidity pools that hold the requested
+// asset as lp_stable or lp_volatile opportunities. apy_base is the trading
+// fee APR and apy_reward the gauge emissions APR; gauge carries the current
+// epoch's bribes and timing.
+func (c *Client) YieldOpportunities(ctx context.Context, req providers.YieldRequest) ([]model.YieldOpportunity, error) {
+	if !req.Chain.IsEVM() || req.Chain.EVMChainID != c.dex.chainID {
+		return nil, clierr.New(clierr.CodeUnsupported, fmt.Sprintf("%s opportunities are available only on chain %d", c.dex.name, c.dex.chainID))
+	}
+	pools, err := c.fetchPools(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	chainID := req.Chain.CAIP2
+	fetchedAt := c.now().UTC().Format(time.RFC3339)
+	out := make([]model.YieldOpportunity, 0)
+	for _, item := range pools {
+		symbols := strings.Split(item.Symbol, "-")
+		if len(item.UnderlyingTokens) < 2 || !matchesAsset(item, symbols, req.Chain, req.Asset) {
+			continue
+		}
+		apyBase := nonNegative(ptrValue(item.APYBase))
+		apyReward := nonNegative(ptrValue(item.APYReward))
+		apyTotal := yieldutil.PositiveFirst(ptrValue(item.APY), apyBase+apyReward)
+		tvl := nonNegative(item.TVLUSD)
+		if (apyTotal == 0 || tvl == 0) && !req.IncludeIncomplete {
+			continue
+		}
+		if apyTotal < req.MinAPY || tvl < req.MinTVLUSD {
+			continue
+		}
+
+		kind := yieldutil.TypeLPVolatile
+		if item.Stablecoin {
+			kind = yieldutil.TypeLPStable
+		}
+		backing := make([]model.YieldBackingAsset, 0, 2)
+		for i, token := range item.UnderlyingTokens[:2] {
+			symbol := ""
+			if i < len(symbols) {
+				symbol = strings.TrimSpace(symbols[i])
+			}
+			backing = append(backing, model.YieldBackingAsset{AssetID: assetID(chainID, token), Symbol: symbol, SharePct: 50})
+		}
+		opportunityAsset := req.Asset.AssetID
+		if opportunityAsset == "" {
+			opportunityAsset = backing[0].AssetID
+		}
+		poolID := strings.TrimSpace(item.Pool)
+		out = append(out, model.YieldOpportunity{
+			OpportunityID:        hashOpportunity(strings.Join([]string{c.dex.name, chainID, strings.ToLower(poolID)}, "|")),
+			Provider:             c.dex.name,
+			Protocol:             c.dex.name,
+			ChainID:              chainID,
+			AssetID:              opportunityAsset,
+			ProviderNativeID:     poolID,
+			ProviderNativeIDKind: model.NativeIDKindPoolID,
+			Type:                 kind,
+			APYBase:              apyBase,
+			APYReward:            apyReward,
+			APYTotal:             apyTotal,
+			RateBasis:            yieldutil.RateBasisAPR,
+			Compounding:          yieldutil.CompoundingNone,
+			TVLUSD:               tvl,
+			LiquidityUSD:         tvl,
+			LockupDays:           0,
+			WithdrawalTerms:      withdrawalTerms,
+			ExitLiquidity:        yieldutil.ExitLiquidity(tvl, tvl, 0),
+			BackingAssets:        backing,
+			SourceURL:            c.dex.sourceURL,
+			FetchedAt:            fetchedAt,
+		})
+	}
+
+	if len(out) == 0 {
+		return nil, clierr.New(clierr.CodeUnavailable, fmt.Sprintf("no %s pools for requested asset", c.dex.name))
+	}
+	yieldutil.ApplyEffectiveAPY(out)
+	yieldutil.ApplyRisk(out)
+	yieldutil.Sort(out, req.SortBy)
+	if req.Limit > 0 && req.Limit < len(out) {
+		out = out[:req.Limit]
+	}
+	if err := c.attachGauges(ctx, req.Chain, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// fetchPools returns the DEX's pools on its chain from the DefiLlama yields
+// API.
+func (c *Client) fetchPools(ctx context.Context) ([]pool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(c.baseURL, "/")+"/pools", nil)
+	if err != nil {
+		return nil, clierr.Wrap(clierr.CodeInternal, "build "+c.dex.name+" pools request", err)
+	}
+	var resp poolsResponse
+	if err := c.http.DoSharedJSON(ctx, req, poolsTTL, &resp); err != nil {
+		return nil, err
+	}
+	out := make([]pool, 0)
+	for _, item := range resp.Data {
+		if !strings.EqualFold(strings.TrimSpace(item.Chain), c.dex.llamaChain) {
+			continue
+		}
+		for _, project := range c.dex.projects {
+			if strings.EqualFold(strings.TrimSpace(item.Project), project) {
+				out = append(out, item)
+				break
+			}
+		}
+	}
+	if len(out) == 0 {
+		return nil, clierr.New(clierr.CodeUnavailable, "no "+c.dex.name+" pools returned by yields API")
+	}
+	return out, nil
+}
+
+// attachGauges sets the epoch timing on every item and reads the bribes of
+// each pool's gauge for the current epoch.
+func (c *Client) attachGauges(ctx context.Context, chain id.Chain, items []model.YieldOpportunity) error {
+	now := c.now().UTC()
+	epochStart, epochEnd := epochBounds(now)
+	pools := make([]common.Address, 0, len(items))
+	for _, item := range items {
+		if addr, ok := poolAddress(item.ProviderNativeID); ok {
+			pools = append(pools, addr)
+		}
+	}
+	gauges, err := c.readBribes(ctx, chain, pools, epochStart)
+	if err != nil {
+		return err
+	}
+	for i := range items {
+		addr, _ := poolAddress(items[i].ProviderNativeID)
+		gauge, ok := gauges[addr]
+		if !ok {
+			continue
+		}
+		gauge.EpochStart = epochStart.Format(time.RFC3339)
+		gauge.EpochEnd = epochEnd.Format(time.RFC3339)
+		gauge.EpochEndsInS = int64(epochEnd.Sub(now) / time.Second)
+		items[i].Gauge = &gauge
+	}
+	return nil
+}
+
+// readBribes looks up the gauge of each pool through the Voter and the
+// current epoch's deposits in the gauge's bribe reward contract. Pools
+// without a gauge are left out.
+func (c *Client) readBribes(ctx context.Context, chain id.Chain, pools []common.Address, epochStart time.Time) (map[common.Address]model.YieldGauge, error) {
+	out := map[common.Address]model.YieldGauge{}
+	voterAddr, ok := registry.VE33Voter(chain.EVMChainID)
+	if !ok || len(pools) == 0 {
+		return out, nil
+	}
+	rpcURL, err := registry.ResolveRPCURL(c.rpcOverride, chain.EVMChainID)
+	if err != nil {
+		return nil, clierr.Wrap(clierr.CodeUnsupported, "resolve rpc url", err)
+	}
+	client, err := rpcx.DialContext(ctx, rpcURL)
+	if err != nil {
+		return nil, clierr.Wrap(clierr.CodeUnavailable, "connect rpc", err)
+	}
+	defer client.Close()
+	voter := common.HexToAddress(voterAddr)
+
+	gaugeCalls := make([]multicall.Call, len(pools))
+	for i, p := range pools {
+		gaugeCalls[i] = multicall.Call{Target: voter, CallData: mustPack(voterABI, "gauges", p)}
+	}
+	gaugeResults, err := multicall.Aggregate3(ctx, client, gaugeCalls)
+	if err != nil {
+		return nil, clierr.Wrap(clierr.CodeUnavailable, "read "+c.dex.name+" gauges", err)
+	}
+	type poolGauge struct {
+		pool, gauge, bribe common.Address
+		tokens             []common.Address
+	}
+	found := make([]*poolGauge, 0, len(pools))
+	for i, r := range gaugeResults {
+		if gauge := decodeAddress(r); gauge != zero {
+			found = append(found, &poolGauge{pool: pools[i], gauge: gauge})
+		}
+	}
+	if len(found) == 0 {
+		return out, nil
+	}
+
+	bribeCalls := make([]multicall.Call, len(found))
+	for i, g := range found {
+		bribeCalls[i] = multicall.Call{Target: voter, CallData: mustPack(voterABI, "gaugeToBribe", g.gauge)}
+	}
+	bribeResults, err := multicall.Aggregate3(ctx, client, bribeCalls)
+	if err != nil {
+		return nil, clierr.Wrap(clierr.CodeUnavailable, "read "+c.dex.name+" bribe contracts", err)
+	}
+	lengthCalls := make([]multicall.Call, len(found))
+	for i, g := range found {
+		g.bribe = decodeAddress(bribeResults[i])
+		lengthCalls[i] = multicall.Call{Target: g.bribe, CallData: mustPack(rewardABI, "rewardsListLength")}
+	}
+	lengthResults, err := multicall.Aggregate3(ctx, client, lengthCalls)
+	if err != nil {
+		return nil, clierr.Wrap(clierr.CodeUnavailable, "read "+c.dex.name+" bribe tokens", err)
+	}
+
+	type tokenSlot struct{ gauge, index int }
+	var tokenCalls []multicall.Call
+	var slots []tokenSlot
+	for i, g := range found {
+		if g.bribe == zero {
+			continue
+		}
+		n := decodeUint256(lengthResults[i]).Int64()
+		if n > maxBribeTokens {
+			n = maxBribeTokens
+		}
+		for j := int64(0); j < n; j++ {
+			tokenCalls = append(tokenCalls, multicall.Call{Target: g.bribe, CallData: mustPack(rewardABI, "rewards", big.NewInt(j))})
+			slots = append(slots, tokenSlot{gauge: i})
+		}
+	}
+	if len(tokenCalls) > 0 {
+		tokenResults, err := multicall.Aggregate3(ctx, client, tokenCalls)
+		if err != nil {
+			return nil, clierr.Wrap(clierr.CodeUnavailable, "read "+c.dex.name+" bribe tokens", err)
+		}
+		for i, r := range tokenResults {
+			if token := decodeAddress(r); token != zero {
+				found[slots[i].gauge].tokens = append(found[slots[i].gauge].tokens, token)
+			}
+		}
+	}
+
+	// One round reads every deposit plus metadata for tokens outside the
+	// registry.
+	epoch := big.NewInt(epochStart.Unix())
+	var amountCalls []multicall.Call
+	var amountSlots []tokenSlot
+	for i, g := range found {
+		for j, token := range g.tokens {
+			amountCalls = append(amountCalls, multicall.Call{Target: g.bribe, CallData: mustPack(rewardABI, "tokenRewardsPerEpoch", token, epoch)})
+			amountSlots = append(amountSlots, tokenSlot{gauge: i, index: j})
+		}
+	}
+	metaIndex := map[common.Address]int{}
+	for _, g := range found {
+		for _, token := range g.tokens {
+			if _, known := id.LookupByAddress(chain.CAIP2, token.Hex()); known {
+				continue
+			}
+			if _, queued := metaIndex[token]; queued {
+				continue
+			}
+			metaIndex[token] = len(amountCalls)
+			amountCalls = append(amountCalls,
+				multicall.Call{Target: token, CallData: mustPack(erc20MetadataABI, "decimals")},
+				multicall.Call{Target: token, CallData: mustPack(erc20MetadataABI, "symbol")},
+			)
+		}
+	}
+	var amountResults []multicall.Result
+	if len(amountCalls) > 0 {
+		amountResults, err = multicall.Aggregate3(ctx, client, amountCalls)
+		if err != nil {
+			return nil, clierr.Wrap(clierr.CodeUnavailable, "read "+c.dex.name+" bribe amounts", err)
+		}
+	}
+
+	bribes := make([][]model.YieldGaugeBribe, len(found))
+	for i, slot := range amountSlots {
+		amount := decodeUint256(amountResults[i])
+		if amount.Sign() == 0 {
+			continue
+		}
+		token := found[slot.gauge].tokens[slot.index]
+		symbol, decimals := tokenMetadata(chain, token, metaIndex, amountResults)
+		base := amount.String()
+		bribes[slot.gauge] = append(bribes[slot.gauge], model.YieldGaugeBribe{
+			AssetID: assetID(chain.CAIP2, token.Hex()),
+			Symbol:  symbol,
+			Amount: model.AmountInfo{
+				AmountBaseUnits: base,
+				AmountDecimal:   id.FormatDecimalCompat(base, decimals),
+				Decimals:        decimals,
+			},
+		})
+	}
+	for i, g := range found {
+		gauge := model.YieldGauge{Address: strings.ToLower(g.gauge.Hex()), Bribes: bribes[i]}
+		if gauge.Bribes == nil {
+			gauge.Bribes = []model.YieldGaugeBribe{}
+		}
+		out[g.pool] = gauge
+	}
+	return out, nil
+}
+
+// tokenMetadata returns the symbol and decimals of token from the registry,
+// or from the metadata calls queued at metaIndex.
+func tokenMetadata(chain id.Chain, token common.Address, metaIndex map[common.Address]int, results []multicall.Result) (string, int) {
+	if known, ok := id.LookupByAddress(chain.CAIP2, token.Hex()); ok {
+		return known.Symbol, known.Decimals
+	}
+	symbol, decimals := "", 18
+	i, ok := metaIndex[token]
+	if !ok {
+		return symbol, decimals
+	}
+	if r := results[i]; r.Success && len(r.ReturnData) >= 32 {
+		decimals = int(decodeUint256(r).Int64())
+	}
+	if r := results[i+1]; r.Success {
+		if decoded, err := erc20MetadataABI.Unpack("symbol", r.ReturnData); err == nil && len(decoded) > 0 {
+			symbol, _ = decoded[0].(string)
+		}
+	}
+	return symbol, decimals
+}
+
+// epochBounds returns the start and end of the voting epoch containing now.
+func epochBounds(now time.Time) (time.Time, time.Time) {
+	week := int64(epochDuration / time.Second)
+	start := time.Unix(now.Unix()-now.Unix()%week, 0).UTC()
+	return start, start.Add(epochDuration)
+}
+
+// matchesAsset reports whether the pool holds asset. Native gas tokens match
+// pools of the wrapped token.
+func matchesAsset(item pool, symbols []string, chain id.Chain, asset id.Asset) bool {
+	address := strings.TrimSpace(asset.Address)
+	if asset.IsNative() {
+		if wrapped, ok := id.WrappedNativeToken(chain); ok {
+			address = wrapped.Address
+		}
+	}
+	if address != "" {
+		for _, token := range item.UnderlyingTokens {
+			if strings.EqualFold(strings.TrimSpace(token), address) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, symbol := range symbols {
+		if id.SymbolsEquivalent(strings.TrimSpace(symbol), asset.Symbol) {
+			return true
+		}
+	}
+	return false
+}
+
+// poolAddress extracts the pool contract from a yields API pool ID, which is
+// the address optionally suffixed with "-<chain>".
+func poolAddress(poolID string) (common.Address, bool) {
+	candidate, _, _ := strings.Cut(strings.TrimSpace(poolID), "-")
+	if !common.IsHexAddress(candidate) {
+		return common.Address{}, false
+	}
+	return common.HexToAddress(candidate), true
+}
+
+func assetID(chainID, address string) string {
+	return fmt.Sprintf("%s/erc20:%s", chainID, strings.ToLower(strings.TrimSpace(address)))
+}
+
+func ptrValue(v *float64) float64 {
+	if v == nil {
+		return 0
+	}
+	return *v
+}
+
+func nonNegative(v float64) float64 {
+	if math.IsNaN(v) || math.IsInf(v, 0) || v < 0 {
+		return 0
+	}
+	return v
+}
+
+func hashOpportunity(seed string) string {
+	sum := sha1.Sum([]byte(seed))
+	return hex.EncodeToString(sum[:])
+}
+
+func decodeAddress(r multicall.Result) common.Address {
+	if !r.Success || len(r.ReturnData) < 32 {
+		return zero
+	}
+	return common.BytesToAddress(r.ReturnData[:32])
+}
+
+func decodeUint256(r multicall.Result) *big.Int {
+	if !r.Success || len(r.ReturnData) < 32 {
+		return new(big.Int)
+	}
+	return new(big.Int).SetBytes(r.ReturnData[:32])
+}
+
+func mustPack(contract abi.ABI, method string, args ...any) []byte {
+	data, err := contract.Pack(method, args...)
+	if err != nil {
+		panic(fmt.Sprintf("pack %s: %v", method, err))
+	}
+	return data
+}
+
+var voterABI = mustABI(registry.VE33VoterABI)
+var rewardABI = mustABI(registry.VE33RewardABI)
+var erc20MetadataABI = mustABI(registry.ERC20MetadataABI)
+
+func mustABI(raw string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(raw))
+	if err != nil {
+		panic(fmt.Sprintf("invalid ABI: %v", err))
+	}
+	return parsed
+}
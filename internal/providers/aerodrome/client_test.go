@@ -0,0 +1,226 @@
+package aerodrome
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/httpx"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/providers"
+	"github.com/ggonzalez94/defi-cli/internal/providers/multicall"
+	"github.com/ggonzalez94/defi-cli/internal/providers/yieldutil"
+)
+
+var (
+	testPool   = common.HexToAddress("0xcDAC0d6c6C59727a65F871236188350531885C43")
+	testGauge  = common.HexToAddress("0x519BBD1Dd8C6A94C46080E24f316c14Ee758C025")
+	testBribe  = common.HexToAddress("0x685b5173e002B2eC55A8cd02C74d7B1f6B4E0e2B")
+	testUSDC   = common.HexToAddress("0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913")
+	testBRETT  = common.HexToAddress("0x532f27101965dd16442E59d40670FaF5eBB142E4")
+	testNow    = time.Date(2026, time.October, 17, 6, 0, 0, 0, time.UTC) // Saturday
+	testEpoch  = time.Date(2026, time.October, 15, 0, 0, 0, 0, time.UTC) // Thursday
+	baseChain  = id.Chain{CAIP2: "eip155:8453", EVMChainID: 8453}
+	weth       = "0x4200000000000000000000000000000000000006"
+	poolsReply = `{"status":"success","data":[
+		{"chain":"Base","project":"aerodrome-v1","symbol":"WETH-USDC","pool":"0xcdac0d6c6c59727a65f871236188350531885c43-base","tvlUsd":20000000,"apyBase":12.5,"apyReward":30,"apy":42.5,"stablecoin":false,"underlyingTokens":["0x4200000000000000000000000000000000000006","0x833589fcd6edb6e08f4c7c32d4f71b54bda02913"]},
+		{"chain":"Base","project":"aerodrome-slipstream","symbol":"USDC-USDBC","pool":"0x98c7a2338336d2d354663246f64676009c7bda97-base","tvlUsd":5000000,"apyBase":1,"apyReward":4,"apy":5,"stablecoin":true,"underlyingTokens":["0x833589fcd6edb6e08f4c7c32d4f71b54bda02913","0xd9aaec86b65d86f6a7b5b1b0c42ffa531710b6ca"]},
+		{"chain":"Base","project":"uniswap-v3","symbol":"WETH-USDC","pool":"x","tvlUsd":90000000,"apy":20,"underlyingTokens":["0x4200000000000000000000000000000000000006","0x833589fcd6edb6e08f4c7c32d4f71b54bda02913"]},
+		{"chain":"Optimism","project":"velodrome-v2","symbol":"WETH-USDC","pool":"y","tvlUsd":1000000,"apy":20,"underlyingTokens":["0x4200000000000000000000000000000000000006","0x0b2c639c533813f4aa9d7837caf62653d097ff85"]}
+	]}`
+)
+
+func word(v *big.Int) []byte { return common.LeftPadBytes(v.Bytes(), 32) }
+
+func dispatch(to common.Address, data []byte) ([]byte, bool) {
+	var method string
+	for _, contract := range []abi.ABI{voterABI, rewardABI, erc20MetadataABI} {
+		if m, err := contract.MethodById(data[:4]); err == nil {
+			method = m.Name
+		}
+	}
+	switch method {
+	case "gauges":
+		if common.BytesToAddress(data[4:36]) == testPool {
+			return common.LeftPadBytes(testGauge.Bytes(), 32), true
+		}
+		return make([]byte, 32), true
+	case "gaugeToBribe":
+		return common.LeftPadBytes(testBribe.Bytes(), 32), true
+	case "rewardsListLength":
+		return word(big.NewInt(2)), true
+	case "rewards":
+		if new(big.Int).SetBytes(data[4:36]).Int64() == 0 {
+			return common.LeftPadBytes(testUSDC.Bytes(), 32), true
+		}
+		return common.LeftPadBytes(testBRETT.Bytes(), 32), true
+	case "tokenRewardsPerEpoch":
+		if new(big.Int).SetBytes(data[36:68]).Int64() != testEpoch.Unix() {
+			return word(big.NewInt(0)), true
+		}
+		if common.BytesToAddress(data[4:36]) == testUSDC {
+			return word(big.NewInt(2_500_000_000)), true
+		}
+		return word(new(big.Int).Mul(big.NewInt(15), new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil))), true
+	case "decimals":
+		return word(big.NewInt(18)), true
+	case "symbol":
+		out, _ := erc20MetadataABI.Methods["symbol"].Outputs.Pack("BRETT")
+		return out, true
+	}
+	return nil, false
+}
+
+func newTestRPCServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	aggregate3 := hex.EncodeToString(multicall.ABI.Methods["aggregate3"].ID)
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+			ID     any               `json:"id"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		reply := func(result string) {
+			_ = json.NewEncoder(w).Encode(map[string]any{"jsonrpc": "2.0", "id": req.ID, "result": result})
+		}
+		var call struct {
+			Data  string `json:"data"`
+			Input string `json:"input"`
+		}
+		if req.Method != "eth_call" || len(req.Params) == 0 {
+			reply("0x")
+			return
+		}
+		_ = json.Unmarshal(req.Params[0], &call)
+		raw := call.Data
+		if raw == "" {
+			raw = call.Input
+		}
+		data, _ := hex.DecodeString(strings.TrimPrefix(raw, "0x"))
+		if len(data) < 4 || hex.EncodeToString(data[:4]) != aggregate3 {
+			reply("0x")
+			return
+		}
+		decoded, err := multicall.ABI.Methods["aggregate3"].Inputs.Unpack(data[4:])
+		if err != nil {
+			t.Errorf("unpack aggregate3: %v", err)
+			reply("0x")
+			return
+		}
+		calls := decoded[0].([]struct {
+			Target       common.Address `json:"target"`
+			AllowFailure bool           `json:"allowFailure"`
+			CallData     []byte         `json:"callData"`
+		})
+		type result struct {
+			Success    bool
+			ReturnData []byte
+		}
+		results := make([]result, len(calls))
+		for i, c := range calls {
+			out, ok := dispatch(c.Target, c.CallData)
+			results[i] = result{Success: ok, ReturnData: out}
+		}
+		encoded, err := multicall.ABI.Methods["aggregate3"].Outputs.Pack(results)
+		if err != nil {
+			t.Errorf("pack aggregate3: %v", err)
+			reply("0x")
+			return
+		}
+		reply("0x" + hex.EncodeToString(encoded))
+	}))
+}
+
+func newTestClient(t *testing.T) *Client {
+	t.Helper()
+	llama := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/pools" {
+			http.Error(w, "unexpected path", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, poolsReply)
+	}))
+	t.Cleanup(llama.Close)
+	rpc := newTestRPCServer(t)
+	t.Cleanup(rpc.Close)
+
+	client := New(httpx.New(2*time.Second, 0))
+	client.baseURL = llama.URL
+	client.SetRPCOverride(rpc.URL)
+	client.now = func() time.Time { return testNow }
+	return client
+}
+
+func TestYieldOpportunitiesReportsGaugeBribesAndEpoch(t *testing.T) {
+	client := newTestClient(t)
+	usdc, _ := id.ParseAsset("USDC", baseChain)
+	items, err := client.YieldOpportunities(context.Background(), providers.YieldRequest{Chain: baseChain, Asset: usdc, Limit: 10})
+	if err != nil {
+		t.Fatalf("YieldOpportunities failed: %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("expected both aerodrome pools holding USDC, got %+v", items)
+	}
+	volatile := items[0]
+	if volatile.Provider != "aerodrome" || volatile.Type != yieldutil.TypeLPVolatile || volatile.APYBase != 12.5 || volatile.APYReward != 30 || volatile.APYTotal != 42.5 {
+		t.Fatalf("unexpected volatile pool %+v", volatile)
+	}
+	if len(volatile.BackingAssets) != 2 || volatile.BackingAssets[0].AssetID != "eip155:8453/erc20:"+weth || volatile.BackingAssets[0].Symbol != "WETH" {
+		t.Fatalf("unexpected backing assets %+v", volatile.BackingAssets)
+	}
+	gauge := volatile.Gauge
+	if gauge == nil || gauge.Address != strings.ToLower(testGauge.Hex()) {
+		t.Fatalf("expected gauge %s, got %+v", testGauge.Hex(), gauge)
+	}
+	if gauge.EpochStart != "2026-10-15T00:00:00Z" || gauge.EpochEnd != "2026-10-22T00:00:00Z" || gauge.EpochEndsInS != int64((4*24+18)*3600) {
+		t.Fatalf("unexpected epoch timing %+v", gauge)
+	}
+	if len(gauge.Bribes) != 2 {
+		t.Fatalf("expected two bribes, got %+v", gauge.Bribes)
+	}
+	if gauge.Bribes[0].Symbol != "USDC" || gauge.Bribes[0].Amount.AmountDecimal != "2500" {
+		t.Fatalf("unexpected USDC bribe %+v", gauge.Bribes[0])
+	}
+	if gauge.Bribes[1].Symbol != "BRETT" || gauge.Bribes[1].Amount.AmountDecimal != "15" || gauge.Bribes[1].Amount.Decimals != 18 {
+		t.Fatalf("unexpected unregistered-token bribe %+v", gauge.Bribes[1])
+	}
+
+	stable := items[1]
+	if stable.Type != yieldutil.TypeLPStable || stable.Gauge != nil {
+		t.Fatalf("expected an lp_stable pool without a gauge, got %+v", stable)
+	}
+}
+
+func TestYieldOpportunitiesMatchesNativeAsWrapped(t *testing.T) {
+	client := newTestClient(t)
+	items, err := client.YieldOpportunities(context.Background(), providers.YieldRequest{Chain: baseChain, Asset: id.NativeAsset(baseChain)})
+	if err != nil {
+		t.Fatalf("YieldOpportunities failed: %v", err)
+	}
+	if len(items) != 1 || items[0].ProviderNativeID != "0xcdac0d6c6c59727a65f871236188350531885c43-base" {
+		t.Fatalf("expected the WETH pool, got %+v", items)
+	}
+}
+
+func TestYieldOpportunitiesRejectsOtherChains(t *testing.T) {
+	client := NewVelodrome(httpx.New(time.Second, 0))
+	_, err := client.YieldOpportunities(context.Background(), providers.YieldRequest{Chain: baseChain})
+	if cErr, ok := clierr.As(err); !ok || cErr.Code != clierr.CodeUnsupported {
+		t.Fatalf("expected unsupported chain error, got %v", err)
+	}
+}
@@ -0,0 +1,139 @@
+package aevo
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ggonzalez94/defi-cli/internal/clock"
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/httpx"
+	"github.com/ggonzalez94/defi-cli/internal/model"
+)
+
+const (
+	defaultBase = "https://api.aevo.xyz"
+	sourceURL   = "https://app.aevo.xyz/option"
+)
+
+type Client struct {
+	http    *httpx.Client
+	baseURL string
+	now     func() time.Time
+}
+
+func New(httpClient *httpx.Client) *Client {
+	return &Client{http: httpClient, baseURL: defaultBase, now: clock.Now}
+}
+
+func (c *Client) Info() model.ProviderInfo {
+	return model.ProviderInfo{
+		Name:        "aevo",
+		Type:        "options",
+		RequiresKey: false,
+		Capabilities: []string{
+			"options.chains",
+			"options.iv",
+		},
+	}
+}
+
+type instrument struct {
+	InstrumentName  string `json:"instrument_name"`
+	InstrumentType  string `json:"instrument_type"`
+	UnderlyingAsset string `json:"underlying_asset"`
+	QuoteAsset      string `json:"quote_asset"`
+	MarkPrice       string `json:"mark_price"`
+	ForwardPrice    string `json:"forward_price"`
+	IndexPrice      string `json:"index_price"`
+	IsActive        bool   `json:"is_active"`
+	OptionType      string `json:"option_type"`
+	Expiry          string `json:"expiry"`
+	Strike          string `json:"strike"`
+	Greeks          greeks `json:"greeks"`
+}
+
+type greeks struct {
+	Delta string `json:"delta"`
+	Gamma string `json:"gamma"`
+	Theta string `json:"theta"`
+	Vega  string `json:"vega"`
+	IV    string `json:"iv"`
+}
+
+// OptionChain returns the active Aevo option instruments for an underlying.
+// Aevo reports expiry in unix nanoseconds and mark IV as a fraction; IV is
+// converted to percentage points.
+func (c *Client) OptionChain(ctx context.Context, underlying string) ([]model.OptionContract, error) {
+	underlying = strings.ToUpper(strings.TrimSpace(underlying))
+	if underlying == "" {
+		return nil, clierr.New(clierr.CodeUsage, "options underlying is required")
+	}
+	query := url.Values{}
+	query.Set("asset", underlying)
+	query.Set("instrument_type", "OPTION")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(c.baseURL, "/")+"/markets?"+query.Encode(), nil)
+	if err != nil {
+		return nil, clierr.Wrap(clierr.CodeInternal, "build aevo markets request", err)
+	}
+	var resp []instrument
+	if _, err := c.http.DoJSON(ctx, req, &resp); err != nil {
+		return nil, err
+	}
+
+	now := c.now().UTC()
+	fetchedAt := now.Format(time.RFC3339)
+	out := make([]model.OptionContract, 0, len(resp))
+	for _, item := range resp {
+		if !item.IsActive || !strings.EqualFold(item.InstrumentType, "OPTION") {
+			continue
+		}
+		optionType := strings.ToLower(strings.TrimSpace(item.OptionType))
+		if optionType != "call" && optionType != "put" {
+			continue
+		}
+		expiryNS, err := strconv.ParseInt(strings.TrimSpace(item.Expiry), 10, 64)
+		if err != nil || expiryNS <= 0 {
+			continue
+		}
+		expiry := time.Unix(0, expiryNS).UTC()
+		if !expiry.After(now) {
+			continue
+		}
+		out = append(out, model.OptionContract{
+			Provider:     "aevo",
+			Instrument:   item.InstrumentName,
+			Underlying:   strings.ToUpper(item.UnderlyingAsset),
+			QuoteSymbol:  strings.ToUpper(item.QuoteAsset),
+			OptionType:   optionType,
+			Strike:       parseFloat(item.Strike),
+			Expiry:       expiry.Format(time.RFC3339),
+			DaysToExpiry: expiry.Sub(now).Hours() / 24,
+			MarkPrice:    parseFloat(item.MarkPrice),
+			IndexPrice:   parseFloat(item.IndexPrice),
+			ForwardPrice: parseFloat(item.ForwardPrice),
+			MarkIV:       parseFloat(item.Greeks.IV) * 100,
+			Delta:        parseFloat(item.Greeks.Delta),
+			Gamma:        parseFloat(item.Greeks.Gamma),
+			Theta:        parseFloat(item.Greeks.Theta),
+			Vega:         parseFloat(item.Greeks.Vega),
+			SourceURL:    sourceURL + "/" + strings.ToLower(underlying),
+			FetchedAt:    fetchedAt,
+		})
+	}
+	if len(out) == 0 {
+		return nil, clierr.New(clierr.CodeUnavailable, "no active option instruments returned by aevo for "+underlying)
+	}
+	return out, nil
+}
+
+func parseFloat(raw string) float64 {
+	v, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+	if err != nil {
+		return 0
+	}
+	return v
+}
@@ -0,0 +1,66 @@
+package aevo
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ggonzalez94/defi-cli/internal/httpx"
+)
+
+// Expiries: 1767254400000000000 = 2026-01-01T08:00:00Z, 1764547200000000000 = 2025-12-01T00:00:00Z.
+const marketsFixture = `[
+	{"instrument_name":"ETH-01JAN26-3000-C","instrument_type":"OPTION","underlying_asset":"ETH","quote_asset":"USDC","mark_price":"120.5","forward_price":"3010","index_price":"3000","is_active":true,"option_type":"call","expiry":"1767254400000000000","strike":"3000","greeks":{"delta":"0.52","gamma":"0.0011","theta":"-4.2","vega":"3.1","iv":"0.55"}},
+	{"instrument_name":"ETH-01JAN26-3000-P","instrument_type":"OPTION","underlying_asset":"ETH","quote_asset":"USDC","mark_price":"110","forward_price":"3010","index_price":"3000","is_active":true,"option_type":"put","expiry":"1767254400000000000","strike":"3000","greeks":{"delta":"-0.48","gamma":"0.0011","theta":"-4.0","vega":"3.1","iv":"0.57"}},
+	{"instrument_name":"ETH-01DEC25-3000-C","instrument_type":"OPTION","underlying_asset":"ETH","quote_asset":"USDC","mark_price":"0","forward_price":"3000","index_price":"3000","is_active":true,"option_type":"call","expiry":"1764547200000000000","strike":"3000","greeks":{"iv":"0.5"}},
+	{"instrument_name":"ETH-01JAN26-4000-C","instrument_type":"OPTION","underlying_asset":"ETH","quote_asset":"USDC","mark_price":"5","forward_price":"3010","index_price":"3000","is_active":false,"option_type":"call","expiry":"1767254400000000000","strike":"4000","greeks":{"iv":"0.7"}}
+]`
+
+func TestOptionChainNormalizesActiveInstruments(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/markets" || r.URL.Query().Get("asset") != "ETH" || r.URL.Query().Get("instrument_type") != "OPTION" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(marketsFixture))
+	}))
+	defer srv.Close()
+
+	c := New(httpx.New(2*time.Second, 0))
+	c.baseURL = srv.URL
+	c.now = func() time.Time { return time.Date(2025, 12, 31, 8, 0, 0, 0, time.UTC) }
+
+	contracts, err := c.OptionChain(context.Background(), "eth")
+	if err != nil {
+		t.Fatalf("OptionChain failed: %v", err)
+	}
+	if len(contracts) != 2 {
+		t.Fatalf("expected expired and inactive instruments to be skipped, got %+v", contracts)
+	}
+	call := contracts[0]
+	if call.Instrument != "ETH-01JAN26-3000-C" || call.OptionType != "call" || call.Strike != 3000 || call.Underlying != "ETH" || call.QuoteSymbol != "USDC" {
+		t.Fatalf("unexpected call contract: %+v", call)
+	}
+	if call.Expiry != "2026-01-01T08:00:00Z" || math.Abs(call.DaysToExpiry-1) > 1e-9 {
+		t.Fatalf("unexpected expiry: %+v", call)
+	}
+	if math.Abs(call.MarkIV-55) > 1e-9 || call.Delta != 0.52 || call.ForwardPrice != 3010 {
+		t.Fatalf("unexpected iv/greeks: %+v", call)
+	}
+}
+
+func TestOptionChainErrorsWhenNothingActive(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer srv.Close()
+
+	c := New(httpx.New(2*time.Second, 0))
+	c.baseURL = srv.URL
+	if _, err := c.OptionChain(context.Background(), "DOGE"); err == nil {
+		t.Fatal("expected error for empty option chain")
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/ggonzalez94/defi-cli/internal/clock"
 	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
 	"github.com/ggonzalez94/defi-cli/internal/httpx"
 	"github.com/ggonzalez94/defi-cli/internal/id"
@@ -47,7 +48,7 @@ func NewBridge(httpClient *httpx.Client, apiKey, affiliate string) *Client {
 		apiKey:           apiKey,
 		affiliate:        affiliate,
 		mode:             modeBridge,
-		now:              time.Now,
+		now:              clock.Now,
 	}
 }
 
@@ -59,7 +60,7 @@ func NewSwap(httpClient *httpx.Client, apiKey, affiliate string) *Client {
 		apiKey:           apiKey,
 		affiliate:        affiliate,
 		mode:             modeSwap,
-		now:              time.Now,
+		now:              clock.Now,
 	}
 }
 
@@ -72,6 +73,9 @@ func (c *Client) Info() model.ProviderInfo {
 			Capabilities: []string{
 				"swap.quote",
 			},
+			Support: []model.CapabilitySupport{
+				{Capability: "swap.quote", Chains: []string{providers.AnyEVMChain}, Features: []string{providers.FeatureExactInput}},
+			},
 			CapabilityAuth: []model.ProviderCapabilityAuth{
 				{
 					Capability:  "swap.quote",
@@ -93,6 +97,7 @@ func (c *Client) Info() model.ProviderInfo {
 		Capabilities: []string{
 			"bridge.quote",
 		},
+		Support: providers.ChainSupport([]string{providers.AnyEVMChain}, "bridge.quote"),
 		CapabilityAuth: []model.ProviderCapabilityAuth{
 			{
 				Capability:  "bridge.quote",
@@ -123,9 +128,10 @@ type quoteResult struct {
 }
 
 type quoteOutput struct {
-	Amount   string `json:"amount"`
-	Decimals int    `json:"decimals"`
-	Token    struct {
+	Amount       string `json:"amount"`
+	MinAmountOut string `json:"minAmountOut"`
+	Decimals     int    `json:"decimals"`
+	Token        struct {
 		Decimals int `json:"decimals"`
 	} `json:"token"`
 }
@@ -163,7 +169,7 @@ type quoteBridgeRoute struct {
 }
 
 func (c *Client) QuoteBridge(ctx context.Context, req providers.BridgeQuoteRequest) (model.BridgeQuote, error) {
-	resp, err := c.quote(ctx, req.FromChain, req.ToChain, req.FromAsset.Address, req.ToAsset.Address, req.AmountBaseUnits)
+	resp, err := c.quote(ctx, req.FromChain, req.ToChain, req.FromAsset.Address, req.ToAsset.Address, req.AmountBaseUnits, 0)
 	if err != nil {
 		return model.BridgeQuote{}, err
 	}
@@ -213,7 +219,8 @@ func (c *Client) QuoteSwap(ctx context.Context, req providers.SwapQuoteRequest)
 		return model.SwapQuote{}, clierr.New(clierr.CodeUnsupported, "bungee supports only --type exact-input")
 	}
 
-	resp, err := c.quote(ctx, req.Chain, req.Chain, req.FromAsset.Address, req.ToAsset.Address, req.AmountBaseUnits)
+	slippagePct := providers.SwapSlippagePct(req)
+	resp, err := c.quote(ctx, req.Chain, req.Chain, req.FromAsset.Address, req.ToAsset.Address, req.AmountBaseUnits, slippagePct)
 	if err != nil {
 		return model.SwapQuote{}, err
 	}
@@ -222,7 +229,7 @@ func (c *Client) QuoteSwap(ctx context.Context, req providers.SwapQuoteRequest)
 		return model.SwapQuote{}, err
 	}
 
-	return model.SwapQuote{
+	quote := model.SwapQuote{
 		Provider:    "bungee",
 		ChainID:     req.Chain.CAIP2,
 		FromAssetID: req.FromAsset.AssetID,
@@ -243,10 +250,19 @@ func (c *Client) QuoteSwap(ctx context.Context, req providers.SwapQuoteRequest)
 		Route:           route,
 		SourceURL:       "https://www.bungee.exchange",
 		FetchedAt:       c.now().UTC().Format(time.RFC3339),
-	}, nil
+	}
+	if auto := resp.Result.AutoRoute; auto != nil {
+		if minOut := strings.TrimSpace(auto.Output.MinAmountOut); minOut != "" {
+			quote.MinOut = &model.AmountInfo{AmountBaseUnits: minOut, AmountDecimal: id.FormatDecimalCompat(minOut, outDecimals), Decimals: outDecimals}
+		}
+	}
+	providers.ApplySwapSlippage(&quote, slippagePct)
+	return quote, nil
 }
 
-func (c *Client) quote(ctx context.Context, fromChain, toChain id.Chain, fromToken, toToken, amountBase string) (quoteResponse, error) {
+// quote requests a Bungee quote. slippagePct is sent as the swap slippage
+// tolerance when positive.
+func (c *Client) quote(ctx context.Context, fromChain, toChain id.Chain, fromToken, toToken, amountBase string, slippagePct float64) (quoteResponse, error) {
 	vals := url.Values{}
 	vals.Set("originChainId", strconv.FormatInt(fromChain.EVMChainID, 10))
 	vals.Set("destinationChainId", strconv.FormatInt(toChain.EVMChainID, 10))
@@ -255,6 +271,9 @@ func (c *Client) quote(ctx context.Context, fromChain, toChain id.Chain, fromTok
 	vals.Set("inputAmount", amountBase)
 	vals.Set("userAddress", defaultAddressForChain(fromChain))
 	vals.Set("receiverAddress", defaultAddressForChain(toChain))
+	if slippagePct > 0 {
+		vals.Set("slippage", strconv.FormatFloat(slippagePct, 'f', -1, 64))
+	}
 
 	base := c.baseURL
 	apiKey, affiliate, useDedicated := c.dedicatedAuth()
@@ -409,3 +428,29 @@ func bungeeError(v any) string {
 	}
 	return "bungee quote failed"
 }
+
+// VerifyKey checks the dedicated backend credentials with a 1 USDC to WETH
+// quote on Base. The key is only sent together with an affiliate, so a key
+// without one is reported as a usage error.
+func (c *Client) VerifyKey(ctx context.Context) error {
+	if strings.TrimSpace(c.apiKey) == "" {
+		return providers.ErrNoAPIKey
+	}
+	if _, _, ok := c.dedicatedAuth(); !ok {
+		return clierr.New(clierr.CodeUsage, "bungee API key is unused without DEFI_BUNGEE_AFFILIATE")
+	}
+	chain, err := id.ParseChain("base")
+	if err != nil {
+		return err
+	}
+	usdc, err := id.ParseAsset("USDC", chain)
+	if err != nil {
+		return err
+	}
+	weth, err := id.ParseAsset("WETH", chain)
+	if err != nil {
+		return err
+	}
+	_, err = c.quote(ctx, chain, chain, usdc.Address, weth.Address, "1000000", 0)
+	return err
+}
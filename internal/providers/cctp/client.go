@@ -0,0 +1,358 @@
+package cctp
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ggonzalez94/defi-cli/internal/clock"
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/execution"
+	"github.com/ggonzalez94/defi-cli/internal/httpx"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/model"
+	"github.com/ggonzalez94/defi-cli/internal/providers"
+	"github.com/ggonzalez94/defi-cli/internal/registry"
+	"github.com/ggonzalez94/defi-cli/internal/rpcx"
+)
+
+var (
+	erc20ABI          = mustABI(registry.ERC20MinimalABI)
+	tokenMessengerABI = mustABI(registry.CCTPTokenMessengerV2ABI)
+)
+
+// Client quotes and plans Circle CCTP v2 transfers, which burn native USDC on
+// the source chain and mint it on the destination once Circle attests the
+// burn. Fast transfers are attested at soft finality for a small fee;
+// standard transfers are free but wait for hard finality.
+type Client struct {
+	http    *httpx.Client
+	baseURL string
+	now     func() time.Time
+	rpcURL  func(override string, chainID int64) (string, error)
+}
+
+func New(httpClient *httpx.Client) *Client {
+	return &Client{http: httpClient, baseURL: registry.CCTPIrisBaseURL, now: clock.Now, rpcURL: registry.ResolveRPCURL}
+}
+
+func (c *Client) Info() model.ProviderInfo {
+	return model.ProviderInfo{
+		Name:        "cctp",
+		Type:        "bridge",
+		RequiresKey: false,
+		Capabilities: []string{
+			"bridge.quote",
+			"bridge.plan",
+			"bridge.execute",
+		},
+		Support: providers.ChainSupport(providers.EVMChains(registry.CCTPChainIDs()), "bridge.quote", "bridge.plan", "bridge.execute"),
+	}
+}
+
+// feeTier is one entry of the Iris burn fee response. MinimumFee is in basis
+// points and may be fractional.
+type feeTier struct {
+	FinalityThreshold uint32  `json:"finalityThreshold"`
+	MinimumFee        float64 `json:"minimumFee"`
+}
+
+// route is a resolved CCTP transfer.
+type route struct {
+	src, dst          registry.CCTPChain
+	toAsset           id.Asset
+	amount            *big.Int
+	maxFee            *big.Int
+	finalityThreshold uint32
+	estimatedTimeS    int64
+}
+
+func (r route) speed() string {
+	if r.finalityThreshold == registry.CCTPFinalityFast {
+		return "fast"
+	}
+	return "standard"
+}
+
+func (c *Client) QuoteBridge(ctx context.Context, req providers.BridgeQuoteRequest) (model.BridgeQuote, error) {
+	r, err := c.resolveRoute(ctx, req)
+	if err != nil {
+		return model.BridgeQuote{}, err
+	}
+	out := new(big.Int).Sub(r.amount, r.maxFee)
+	feeDecimal := id.FormatDecimalCompat(r.maxFee.String(), req.FromAsset.Decimals)
+	// USDC is redeemable 1:1, so the token fee is the USD fee.
+	feeUSD, _ := strconv.ParseFloat(feeDecimal, 64)
+	return model.BridgeQuote{
+		Provider:    "cctp",
+		FromChainID: req.FromChain.CAIP2,
+		ToChainID:   req.ToChain.CAIP2,
+		FromAssetID: req.FromAsset.AssetID,
+		ToAssetID:   r.toAsset.AssetID,
+		InputAmount: model.AmountInfo{
+			AmountBaseUnits: r.amount.String(),
+			AmountDecimal:   id.FormatDecimalCompat(r.amount.String(), req.FromAsset.Decimals),
+			Decimals:        req.FromAsset.Decimals,
+		},
+		EstimatedOut: model.AmountInfo{
+			AmountBaseUnits: out.String(),
+			AmountDecimal:   id.FormatDecimalCompat(out.String(), r.toAsset.Decimals),
+			Decimals:        r.toAsset.Decimals,
+		},
+		EstimatedFeeUSD: feeUSD,
+		FeeBreakdown: &model.BridgeFeeBreakdown{
+			TotalFeeBaseUnits: r.maxFee.String(),
+			TotalFeeDecimal:   feeDecimal,
+			TotalFeeUSD:       feeUSD,
+		},
+		EstimatedTimeS: r.estimatedTimeS,
+		Route:          "CCTP v2 " + r.speed(),
+		SourceURL:      "https://developers.circle.com/cctp",
+		FetchedAt:      c.now().UTC().Format(time.RFC3339),
+	}, nil
+}
+
+func (c *Client) resolveRoute(ctx context.Context, req providers.BridgeQuoteRequest) (route, error) {
+	if !req.FromChain.IsEVM() || !req.ToChain.IsEVM() {
+		return route{}, clierr.New(clierr.CodeUnsupported, "cctp bridge quotes support only EVM chains")
+	}
+	if req.FromChain.EVMChainID == req.ToChain.EVMChainID {
+		return route{}, clierr.New(clierr.CodeUsage, "cctp source and destination chains must differ")
+	}
+	src, ok := registry.CCTP(req.FromChain.EVMChainID)
+	if !ok {
+		return route{}, clierr.New(clierr.CodeUnsupported, "cctp does not support source chain "+req.FromChain.Slug)
+	}
+	dst, ok := registry.CCTP(req.ToChain.EVMChainID)
+	if !ok {
+		return route{}, clierr.New(clierr.CodeUnsupported, "cctp does not support destination chain "+req.ToChain.Slug)
+	}
+	if !strings.EqualFold(strings.TrimSpace(req.FromAsset.Address), src.USDC) {
+		return route{}, clierr.New(clierr.CodeUnsupported, "cctp only bridges native USDC")
+	}
+	amount, ok := new(big.Int).SetString(strings.TrimSpace(req.AmountBaseUnits), 10)
+	if !ok || amount.Sign() <= 0 {
+		return route{}, clierr.New(clierr.CodeUsage, "amount must be a positive integer in base units")
+	}
+	toAsset, err := id.ParseAsset(dst.USDC, req.ToChain)
+	if err != nil {
+		return route{}, err
+	}
+	if toAsset.Decimals <= 0 {
+		toAsset.Decimals = req.FromAsset.Decimals
+	}
+
+	tiers, err := c.fees(ctx, src.Domain, dst.Domain)
+	if err != nil {
+		return route{}, err
+	}
+	r := route{
+		src:               src,
+		dst:               dst,
+		toAsset:           toAsset,
+		amount:            amount,
+		maxFee:            big.NewInt(0),
+		finalityThreshold: registry.CCTPFinalityStandard,
+		estimatedTimeS:    src.StandardFinalityS,
+	}
+	// Prefer fast transfers where the route offers them and they actually
+	// beat standard finality.
+	for _, tier := range tiers {
+		if tier.FinalityThreshold == registry.CCTPFinalityFast && src.StandardFinalityS > registry.CCTPFastTransferS {
+			r.finalityThreshold = registry.CCTPFinalityFast
+			r.estimatedTimeS = registry.CCTPFastTransferS
+			r.maxFee = feeForBps(amount, tier.MinimumFee)
+			break
+		}
+		if tier.FinalityThreshold == registry.CCTPFinalityStandard {
+			r.maxFee = feeForBps(amount, tier.MinimumFee)
+		}
+	}
+	if r.maxFee.Cmp(amount) >= 0 {
+		return route{}, clierr.New(clierr.CodeUsage, "amount does not cover the cctp transfer fee")
+	}
+	return r, nil
+}
+
+func (c *Client) fees(ctx context.Context, srcDomain, dstDomain uint32) ([]feeTier, error) {
+	endpoint := fmt.Sprintf("%s/v2/burn/USDC/fees/%d/%d", strings.TrimRight(c.baseURL, "/"), srcDomain, dstDomain)
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, clierr.Wrap(clierr.CodeInternal, "build cctp fee request", err)
+	}
+	var tiers []feeTier
+	if _, err := c.http.DoJSON(ctx, httpReq, &tiers); err != nil {
+		return nil, err
+	}
+	return tiers, nil
+}
+
+// feeForBps rounds the fee up so the burn's maxFee never undercuts what the
+// attester requires.
+func feeForBps(amount *big.Int, bps float64) *big.Int {
+	if bps <= 0 {
+		return big.NewInt(0)
+	}
+	// Hundredths of a basis point keep fractional fees such as 1.3 bps exact.
+	scaled := big.NewInt(int64(math.Round(bps * 100)))
+	fee := new(big.Int).Mul(amount, scaled)
+	denom := big.NewInt(1_000_000)
+	fee.Add(fee, new(big.Int).Sub(denom, big.NewInt(1)))
+	return fee.Quo(fee, denom)
+}
+
+func (c *Client) BuildBridgeAction(ctx context.Context, req providers.BridgeQuoteRequest, opts providers.BridgeExecutionOptions) (execution.Action, error) {
+	sender := strings.TrimSpace(opts.Sender)
+	if sender == "" {
+		return execution.Action{}, clierr.New(clierr.CodeUsage, "bridge execution requires sender address")
+	}
+	if !common.IsHexAddress(sender) {
+		return execution.Action{}, clierr.New(clierr.CodeUsage, "bridge execution sender must be a valid EVM address")
+	}
+	recipient := strings.TrimSpace(opts.Recipient)
+	if recipient == "" {
+		recipient = sender
+	}
+	if !common.IsHexAddress(recipient) {
+		return execution.Action{}, clierr.New(clierr.CodeUsage, "bridge execution recipient must be a valid EVM address")
+	}
+
+	r, err := c.resolveRoute(ctx, req)
+	if err != nil {
+		return execution.Action{}, err
+	}
+	rpcURL, err := c.rpcURL(opts.RPCURL, req.FromChain.EVMChainID)
+	if err != nil {
+		return execution.Action{}, clierr.Wrap(clierr.CodeUsage, "resolve rpc url", err)
+	}
+	destRPCURL, err := c.rpcURL("", req.ToChain.EVMChainID)
+	if err != nil {
+		return execution.Action{}, clierr.Wrap(clierr.CodeUsage, "resolve destination rpc url", err)
+	}
+	client, err := rpcx.DialContext(ctx, rpcURL)
+	if err != nil {
+		return execution.Action{}, clierr.Wrap(clierr.CodeUnavailable, "connect rpc", err)
+	}
+	defer client.Close()
+
+	senderAddr := common.HexToAddress(sender)
+	recipientAddr := common.HexToAddress(recipient)
+	usdc := common.HexToAddress(r.src.USDC)
+	messenger := common.HexToAddress(registry.CCTPTokenMessengerV2)
+	allowance, err := readAllowance(ctx, client, usdc, senderAddr, messenger)
+	if err != nil {
+		return execution.Action{}, err
+	}
+
+	// An empty destinationCaller lets the sender's own mint step relay the
+	// message.
+	burnData, err := tokenMessengerABI.Pack("depositForBurn", r.amount, r.dst.Domain, common.BytesToHash(recipientAddr.Bytes()), usdc, [32]byte{}, r.maxFee, r.finalityThreshold)
+	if err != nil {
+		return execution.Action{}, clierr.Wrap(clierr.CodeInternal, "pack depositForBurn calldata", err)
+	}
+
+	minOut := new(big.Int).Sub(r.amount, r.maxFee)
+	action := execution.NewAction(execution.NewActionID(), "bridge", req.FromChain.CAIP2, execution.Constraints{
+		Simulate: opts.Simulate,
+	})
+	action.Provider = "cctp"
+	action.FromAddress = senderAddr.Hex()
+	action.ToAddress = recipientAddr.Hex()
+	action.InputAmount = r.amount.String()
+	action.Metadata = map[string]any{
+		"to_chain_id":        req.ToChain.CAIP2,
+		"from_asset_id":      req.FromAsset.AssetID,
+		"to_asset_id":        r.toAsset.AssetID,
+		"route":              "cctp-v2-" + r.speed(),
+		"transfer_speed":     r.speed(),
+		"max_fee_base_units": r.maxFee.String(),
+	}
+
+	if allowance.Cmp(r.amount) < 0 {
+		approveData, err := erc20ABI.Pack("approve", messenger, r.amount)
+		if err != nil {
+			return execution.Action{}, clierr.Wrap(clierr.CodeInternal, "pack approve calldata", err)
+		}
+		action.Steps = append(action.Steps, execution.ActionStep{
+			StepID:      "approve-cctp-token-messenger",
+			Type:        execution.StepTypeApproval,
+			Status:      execution.StepStatusPending,
+			ChainID:     req.FromChain.CAIP2,
+			RPCURL:      rpcURL,
+			Description: "Approve CCTP token messenger for USDC",
+			Target:      usdc.Hex(),
+			Data:        "0x" + common.Bytes2Hex(approveData),
+			Value:       "0",
+		})
+	}
+	action.Steps = append(action.Steps, execution.ActionStep{
+		StepID:      "bridge-burn",
+		Type:        execution.StepTypeBridge,
+		Status:      execution.StepStatusPending,
+		ChainID:     req.FromChain.CAIP2,
+		RPCURL:      rpcURL,
+		Description: fmt.Sprintf("Burn USDC via CCTP v2 (%s transfer)", r.speed()),
+		Target:      messenger.Hex(),
+		Data:        "0x" + common.Bytes2Hex(burnData),
+		Value:       "0",
+		ExpectedOutputs: map[string]string{
+			"to_amount_min":              minOut.String(),
+			"settlement_provider":        "cctp",
+			"settlement_status_endpoint": registry.CCTPSettlementURL,
+			"settlement_source_domain":   strconv.FormatUint(uint64(r.src.Domain), 10),
+		},
+	})
+	// Calldata is filled in at submit time from the attested message.
+	action.Steps = append(action.Steps, execution.ActionStep{
+		StepID:      "bridge-mint",
+		Type:        execution.StepTypeBridgeReceive,
+		Status:      execution.StepStatusPending,
+		ChainID:     req.ToChain.CAIP2,
+		RPCURL:      destRPCURL,
+		Description: "Mint USDC on destination with Circle attestation",
+		Target:      common.HexToAddress(registry.CCTPMessageTransmitterV2).Hex(),
+		Value:       "0",
+		ExpectedOutputs: map[string]string{
+			"to_amount_min": minOut.String(),
+			"recipient":     recipientAddr.Hex(),
+		},
+	})
+	return action, nil
+}
+
+func readAllowance(ctx context.Context, client *ethclient.Client, token, owner, spender common.Address) (*big.Int, error) {
+	data, err := erc20ABI.Pack("allowance", owner, spender)
+	if err != nil {
+		return nil, clierr.Wrap(clierr.CodeInternal, "pack allowance call", err)
+	}
+	out, err := client.CallContract(ctx, ethereum.CallMsg{To: &token, Data: data}, nil)
+	if err != nil {
+		return nil, clierr.Wrap(clierr.CodeUnavailable, "read allowance", err)
+	}
+	values, err := erc20ABI.Unpack("allowance", out)
+	if err != nil || len(values) == 0 {
+		return nil, clierr.Wrap(clierr.CodeUnavailable, "decode allowance", err)
+	}
+	allowance, ok := values[0].(*big.Int)
+	if !ok || allowance == nil {
+		return nil, clierr.New(clierr.CodeUnavailable, "invalid allowance response")
+	}
+	return allowance, nil
+}
+
+func mustABI(raw string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(raw))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
@@ -0,0 +1,158 @@
+package cctp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ggonzalez94/defi-cli/internal/execution"
+	"github.com/ggonzalez94/defi-cli/internal/httpx"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/providers"
+	"github.com/ggonzalez94/defi-cli/internal/registry"
+)
+
+func newFeeServer(t *testing.T, body string) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, "/v2/burn/USDC/fees/") {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}))
+}
+
+// newZeroRPCServer answers every eth_call with a zero allowance.
+func newZeroRPCServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		w.Header().Set("Content-Type", "application/json")
+		if req.Method != "eth_call" {
+			_, _ = fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"error":{"code":-32601,"message":"unsupported"}}`, req.ID)
+			return
+		}
+		_, _ = fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":"0x%064d"}`, req.ID, 0)
+	}))
+}
+
+func usdcRequest(t *testing.T, from, to, amount string) providers.BridgeQuoteRequest {
+	t.Helper()
+	fromChain, err := id.ParseChain(from)
+	if err != nil {
+		t.Fatalf("parse chain: %v", err)
+	}
+	toChain, err := id.ParseChain(to)
+	if err != nil {
+		t.Fatalf("parse chain: %v", err)
+	}
+	fromAsset, err := id.ParseAsset("USDC", fromChain)
+	if err != nil {
+		t.Fatalf("parse asset: %v", err)
+	}
+	toAsset, _ := id.ParseAsset("USDC", toChain)
+	return providers.BridgeQuoteRequest{FromChain: fromChain, ToChain: toChain, FromAsset: fromAsset, ToAsset: toAsset, AmountBaseUnits: amount}
+}
+
+func newTestClient(feeURL string) *Client {
+	c := New(httpx.New(2*time.Second, 0))
+	c.baseURL = feeURL
+	c.now = func() time.Time { return time.Unix(1_800_000_000, 0) }
+	return c
+}
+
+func TestQuoteBridgeUsesFastTransferFee(t *testing.T) {
+	fees := newFeeServer(t, `[{"finalityThreshold":1000,"minimumFee":1.3},{"finalityThreshold":2000,"minimumFee":0}]`)
+	defer fees.Close()
+
+	quote, err := newTestClient(fees.URL).QuoteBridge(context.Background(), usdcRequest(t, "ethereum", "base", "1000000000"))
+	if err != nil {
+		t.Fatalf("QuoteBridge failed: %v", err)
+	}
+	// 1.3 bps of 1000 USDC is 0.13 USDC.
+	if quote.EstimatedOut.AmountBaseUnits != "999870000" || quote.FeeBreakdown.TotalFeeBaseUnits != "130000" {
+		t.Fatalf("unexpected amounts: out=%s fee=%s", quote.EstimatedOut.AmountBaseUnits, quote.FeeBreakdown.TotalFeeBaseUnits)
+	}
+	if quote.EstimatedFeeUSD != 0.13 || quote.EstimatedTimeS != registry.CCTPFastTransferS || quote.Route != "CCTP v2 fast" {
+		t.Fatalf("unexpected fee/eta/route: %v %d %s", quote.EstimatedFeeUSD, quote.EstimatedTimeS, quote.Route)
+	}
+	if !strings.HasSuffix(strings.ToLower(quote.ToAssetID), "0x833589fcd6edb6e08f4c7c32d4f71b54bda02913") {
+		t.Fatalf("expected Base native USDC, got %s", quote.ToAssetID)
+	}
+}
+
+func TestQuoteBridgeFallsBackToStandardOnFastFinalityChains(t *testing.T) {
+	fees := newFeeServer(t, `[{"finalityThreshold":1000,"minimumFee":1},{"finalityThreshold":2000,"minimumFee":0}]`)
+	defer fees.Close()
+
+	quote, err := newTestClient(fees.URL).QuoteBridge(context.Background(), usdcRequest(t, "avalanche", "base", "1000000"))
+	if err != nil {
+		t.Fatalf("QuoteBridge failed: %v", err)
+	}
+	if quote.Route != "CCTP v2 standard" || quote.EstimatedOut.AmountBaseUnits != "1000000" {
+		t.Fatalf("expected free standard transfer, got route=%s out=%s", quote.Route, quote.EstimatedOut.AmountBaseUnits)
+	}
+}
+
+func TestQuoteBridgeRejectsNonUSDC(t *testing.T) {
+	req := usdcRequest(t, "ethereum", "base", "1000000")
+	req.FromAsset, _ = id.ParseAsset("WETH", req.FromChain)
+	if _, err := newTestClient("http://127.0.0.1:0").QuoteBridge(context.Background(), req); err == nil || !strings.Contains(err.Error(), "native USDC") {
+		t.Fatalf("expected native USDC error, got %v", err)
+	}
+}
+
+func TestBuildBridgeActionAddsBurnAndMintSteps(t *testing.T) {
+	fees := newFeeServer(t, `[{"finalityThreshold":1000,"minimumFee":1},{"finalityThreshold":2000,"minimumFee":0}]`)
+	defer fees.Close()
+	rpc := newZeroRPCServer()
+	defer rpc.Close()
+
+	c := newTestClient(fees.URL)
+	c.rpcURL = func(string, int64) (string, error) { return rpc.URL, nil }
+	action, err := c.BuildBridgeAction(context.Background(), usdcRequest(t, "arbitrum", "base", "5000000"), providers.BridgeExecutionOptions{
+		Sender:   "0x00000000000000000000000000000000000000AA",
+		Simulate: true,
+	})
+	if err != nil {
+		t.Fatalf("BuildBridgeAction failed: %v", err)
+	}
+	if action.Provider != "cctp" || len(action.Steps) != 3 {
+		t.Fatalf("expected approval, burn, and mint steps, got provider=%s steps=%d", action.Provider, len(action.Steps))
+	}
+	burn, mint := action.Steps[1], action.Steps[2]
+	if burn.Type != execution.StepTypeBridge || !registry.IsAllowedBridgeExecutionTarget("cctp", 42161, burn.Target) {
+		t.Fatalf("unexpected burn step: %+v", burn)
+	}
+	if burn.ExpectedOutputs["settlement_provider"] != "cctp" || burn.ExpectedOutputs["settlement_source_domain"] != "3" || burn.ExpectedOutputs["to_amount_min"] != "4999500" {
+		t.Fatalf("unexpected burn outputs: %#v", burn.ExpectedOutputs)
+	}
+	args, err := tokenMessengerABI.Methods["depositForBurn"].Inputs.Unpack(common.FromHex(burn.Data)[4:])
+	if err != nil {
+		t.Fatalf("decode depositForBurn: %v", err)
+	}
+	if args[1].(uint32) != 6 || args[6].(uint32) != registry.CCTPFinalityFast {
+		t.Fatalf("unexpected destination domain or finality: %v %v", args[1], args[6])
+	}
+	mintRecipient := args[2].([32]byte)
+	if common.BytesToAddress(mintRecipient[:]) != common.HexToAddress("0x00000000000000000000000000000000000000AA") {
+		t.Fatalf("expected mint recipient to default to sender, got %x", mintRecipient)
+	}
+	if mint.Type != execution.StepTypeBridgeReceive || mint.ChainID != "eip155:8453" || mint.Data != "" {
+		t.Fatalf("unexpected mint step: %+v", mint)
+	}
+	if !strings.EqualFold(mint.Target, registry.CCTPMessageTransmitterV2) {
+		t.Fatalf("unexpected mint target %s", mint.Target)
+	}
+}
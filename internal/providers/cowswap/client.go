@@ -0,0 +1,360 @@
+package cowswap
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ggonzalez94/defi-cli/internal/clock"
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/execution"
+	"github.com/ggonzalez94/defi-cli/internal/httpx"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/model"
+	"github.com/ggonzalez94/defi-cli/internal/providers"
+	"github.com/ggonzalez94/defi-cli/internal/registry"
+	"github.com/ggonzalez94/defi-cli/internal/rpcx"
+)
+
+const (
+	// appData is the order metadata document; its keccak hash is signed.
+	appData = `{"appCode":"defi-cli","metadata":{},"version":"1.3.0"}`
+	// orderValidity bounds how long solvers may settle a planned order.
+	orderValidity = 30 * time.Minute
+)
+
+var (
+	erc20ABI    = mustABI(registry.ERC20MinimalABI)
+	appDataHash = crypto.Keccak256Hash([]byte(appData)).Hex()
+)
+
+type Client struct {
+	http    *httpx.Client
+	baseURL string
+	now     func() time.Time
+}
+
+func New(httpClient *httpx.Client) *Client {
+	return &Client{http: httpClient, now: clock.Now}
+}
+
+func (c *Client) Info() model.ProviderInfo {
+	return model.ProviderInfo{
+		Name:        "cowswap",
+		Type:        "swap",
+		RequiresKey: false,
+		Capabilities: []string{
+			"swap.quote",
+			"swap.plan",
+			"swap.execute",
+		},
+		Support: []model.CapabilitySupport{
+			{Capability: "swap.quote", Chains: providers.EVMChains(registry.CowSwapChainIDs()), Features: []string{providers.FeatureExactInput}},
+			{Capability: "swap.plan", Chains: providers.EVMChains(registry.CowSwapChainIDs()), Features: []string{providers.FeatureExactInput}},
+			{Capability: "swap.execute", Chains: providers.EVMChains(registry.CowSwapChainIDs())},
+		},
+	}
+}
+
+type quoteRequest struct {
+	SellToken           string `json:"sellToken"`
+	BuyToken            string `json:"buyToken"`
+	From                string `json:"from"`
+	Receiver            string `json:"receiver,omitempty"`
+	Kind                string `json:"kind"`
+	SellAmountBeforeFee string `json:"sellAmountBeforeFee"`
+	ValidFor            int64  `json:"validFor"`
+	AppData             string `json:"appData"`
+	AppDataHash         string `json:"appDataHash"`
+	SigningScheme       string `json:"signingScheme"`
+	PriceQuality        string `json:"priceQuality"`
+}
+
+type quoteResponse struct {
+	Quote struct {
+		SellAmount string `json:"sellAmount"`
+		BuyAmount  string `json:"buyAmount"`
+		FeeAmount  string `json:"feeAmount"`
+		ValidTo    uint32 `json:"validTo"`
+	} `json:"quote"`
+	ID       int64 `json:"id"`
+	Verified bool  `json:"verified"`
+	// Expiration is when the API stops accepting orders built from the quote.
+	Expiration string `json:"expiration"`
+}
+
+func (c *Client) QuoteSwap(ctx context.Context, req providers.SwapQuoteRequest) (model.SwapQuote, error) {
+	from := strings.TrimSpace(req.Swapper)
+	if from == "" {
+		from = common.Address{}.Hex()
+	}
+	quote, base, err := c.quote(ctx, req, from, "")
+	if err != nil {
+		return model.SwapQuote{}, err
+	}
+	return model.SwapQuote{
+		Provider:    "cowswap",
+		ChainID:     req.Chain.CAIP2,
+		FromAssetID: req.FromAsset.AssetID,
+		ToAssetID:   req.ToAsset.AssetID,
+		TradeType:   string(providers.SwapTradeTypeExactInput),
+		InputAmount: model.AmountInfo{AmountBaseUnits: req.AmountBaseUnits, AmountDecimal: req.AmountDecimal, Decimals: req.FromAsset.Decimals},
+		EstimatedOut: model.AmountInfo{
+			AmountBaseUnits: quote.Quote.BuyAmount,
+			AmountDecimal:   id.FormatDecimalCompat(quote.Quote.BuyAmount, req.ToAsset.Decimals),
+			Decimals:        req.ToAsset.Decimals,
+		},
+		// Solvers pay settlement gas; network costs are deducted from the sell amount.
+		EstimatedGasUSD: 0,
+		PriceImpactPct:  0,
+		Route:           "cow-protocol-batch-auction",
+		SourceURL:       base,
+		FetchedAt:       c.now().UTC().Format(time.RFC3339),
+		// Orders settle at the quoted buy amount or better, so the quote is
+		// firm until the API stops accepting it.
+		ExpiresAt: quoteExpiresAt(quote),
+		Firmness:  providers.QuoteFirmnessFirm,
+	}, nil
+}
+
+// quoteExpiresAt returns the quote expiration in RFC 3339, falling back to
+// the order validTo.
+func quoteExpiresAt(quote quoteResponse) string {
+	if expiration, err := time.Parse(time.RFC3339, quote.Expiration); err == nil {
+		return expiration.UTC().Format(time.RFC3339)
+	}
+	if quote.Quote.ValidTo == 0 {
+		return ""
+	}
+	return time.Unix(int64(quote.Quote.ValidTo), 0).UTC().Format(time.RFC3339)
+}
+
+func (c *Client) BuildSwapAction(ctx context.Context, req providers.SwapQuoteRequest, opts providers.SwapExecutionOptions) (execution.Action, error) {
+	sender := strings.TrimSpace(opts.Sender)
+	if sender == "" {
+		return execution.Action{}, clierr.New(clierr.CodeUsage, "swap execution requires sender address")
+	}
+	if !common.IsHexAddress(sender) {
+		return execution.Action{}, clierr.New(clierr.CodeUsage, "swap execution sender must be a valid EVM address")
+	}
+	recipient := strings.TrimSpace(opts.Recipient)
+	if recipient == "" {
+		recipient = sender
+	}
+	if !common.IsHexAddress(recipient) {
+		return execution.Action{}, clierr.New(clierr.CodeUsage, "swap execution recipient must be a valid EVM address")
+	}
+	senderAddr := common.HexToAddress(sender)
+	recipientAddr := common.HexToAddress(recipient)
+	settlement, vaultRelayer, ok := registry.CowSwapContracts(req.Chain.EVMChainID)
+	if !ok {
+		return execution.Action{}, clierr.New(clierr.CodeUnsupported, "cowswap does not support chain "+req.Chain.Slug)
+	}
+	rpcURL, err := registry.ResolveRPCURL(opts.RPCURL, req.Chain.EVMChainID)
+	if err != nil {
+		return execution.Action{}, clierr.Wrap(clierr.CodeUsage, "resolve rpc url", err)
+	}
+	slippage := opts.SlippageBps
+	if slippage <= 0 {
+		slippage = 50
+	}
+	if slippage >= 10_000 {
+		return execution.Action{}, clierr.New(clierr.CodeUsage, "slippage bps must be less than 10000")
+	}
+
+	quote, base, err := c.quote(ctx, req, senderAddr.Hex(), recipientAddr.Hex())
+	if err != nil {
+		return execution.Action{}, err
+	}
+	sellAmount, ok := new(big.Int).SetString(quote.Quote.SellAmount, 10)
+	if !ok {
+		return execution.Action{}, clierr.New(clierr.CodeUnavailable, "cowswap quote has invalid sell amount")
+	}
+	feeAmount, ok := new(big.Int).SetString(firstNonEmpty(quote.Quote.FeeAmount, "0"), 10)
+	if !ok {
+		return execution.Action{}, clierr.New(clierr.CodeUnavailable, "cowswap quote has invalid fee amount")
+	}
+	quotedOut, ok := new(big.Int).SetString(quote.Quote.BuyAmount, 10)
+	if !ok || quotedOut.Sign() <= 0 {
+		return execution.Action{}, clierr.New(clierr.CodeUnavailable, "cowswap quote has invalid buy amount")
+	}
+	// Orders are signed with a zero fee; the quoted network cost stays in the
+	// sell amount and is taken by the solver from the surplus.
+	totalSell := new(big.Int).Add(sellAmount, feeAmount)
+	amountOutMin := new(big.Int).Mul(quotedOut, big.NewInt(10_000-slippage))
+	amountOutMin.Div(amountOutMin, big.NewInt(10_000))
+	validTo := quote.Quote.ValidTo
+	if validTo == 0 {
+		validTo = uint32(c.now().Add(orderValidity).Unix())
+	}
+
+	sellToken := common.HexToAddress(req.FromAsset.Address)
+	buyToken := common.HexToAddress(req.ToAsset.Address)
+	action := execution.NewAction(execution.NewActionID(), "swap", req.Chain.CAIP2, execution.Constraints{SlippageBps: slippage, Simulate: opts.Simulate})
+	action.Provider = "cowswap"
+	action.FromAddress = senderAddr.Hex()
+	action.ToAddress = recipientAddr.Hex()
+	action.InputAmount = totalSell.String()
+	action.Metadata = map[string]any{
+		"token_in":       sellToken.Hex(),
+		"token_out":      buyToken.Hex(),
+		"quote_id":       quote.ID,
+		"quoted_amount":  quotedOut.String(),
+		"amount_out_min": amountOutMin.String(),
+		"valid_to":       validTo,
+	}
+
+	allowance, err := readAllowance(ctx, rpcURL, sellToken, senderAddr, common.HexToAddress(vaultRelayer))
+	if err != nil {
+		return execution.Action{}, err
+	}
+	if allowance.Cmp(totalSell) < 0 {
+		approveData, err := erc20ABI.Pack("approve", common.HexToAddress(vaultRelayer), totalSell)
+		if err != nil {
+			return execution.Action{}, clierr.Wrap(clierr.CodeInternal, "pack approve calldata", err)
+		}
+		action.Steps = append(action.Steps, execution.ActionStep{
+			StepID:      "approve-vault-relayer",
+			Type:        execution.StepTypeApproval,
+			Status:      execution.StepStatusPending,
+			ChainID:     req.Chain.CAIP2,
+			RPCURL:      rpcURL,
+			Description: "Approve token spending for CoW Protocol vault relayer",
+			Target:      sellToken.Hex(),
+			Data:        "0x" + common.Bytes2Hex(approveData),
+			Value:       "0",
+		})
+	}
+
+	action.Steps = append(action.Steps, execution.ActionStep{
+		StepID:      "cowswap-order",
+		Type:        execution.StepTypeOrder,
+		Status:      execution.StepStatusPending,
+		ChainID:     req.Chain.CAIP2,
+		RPCURL:      rpcURL,
+		Description: "Sign and submit CoW Protocol order; solvers settle on-chain",
+		Target:      common.HexToAddress(settlement).Hex(),
+		Data:        "0x",
+		Value:       "0",
+		ExpectedOutputs: map[string]string{
+			"amount_out_min": amountOutMin.String(),
+		},
+		Order: &execution.OrderDetails{
+			OrderBookURL:      base,
+			SellToken:         sellToken.Hex(),
+			BuyToken:          buyToken.Hex(),
+			Receiver:          recipientAddr.Hex(),
+			SellAmount:        totalSell.String(),
+			BuyAmount:         amountOutMin.String(),
+			ValidTo:           validTo,
+			AppData:           appData,
+			AppDataHash:       appDataHash,
+			FeeAmount:         "0",
+			Kind:              "sell",
+			PartiallyFillable: false,
+			SellTokenBalance:  "erc20",
+			BuyTokenBalance:   "erc20",
+			QuoteID:           quote.ID,
+		},
+	})
+	return action, nil
+}
+
+func (c *Client) quote(ctx context.Context, req providers.SwapQuoteRequest, from, receiver string) (quoteResponse, string, error) {
+	if req.TradeType != "" && req.TradeType != providers.SwapTradeTypeExactInput {
+		return quoteResponse{}, "", clierr.New(clierr.CodeUnsupported, "cowswap supports only --type exact-input")
+	}
+	base, err := c.orderBookURL(req.Chain)
+	if err != nil {
+		return quoteResponse{}, "", err
+	}
+	for _, asset := range []id.Asset{req.FromAsset, req.ToAsset} {
+		if !common.IsHexAddress(asset.Address) || strings.EqualFold(asset.Address, "0xEeeeeEeeeEeEeeEeEeEeeEEEeeeeEeeeeeeeEEeE") {
+			return quoteResponse{}, "", clierr.New(clierr.CodeUnsupported, "cowswap supports only ERC20 tokens; wrap native assets first")
+		}
+	}
+	body, err := json.Marshal(quoteRequest{
+		SellToken:           common.HexToAddress(req.FromAsset.Address).Hex(),
+		BuyToken:            common.HexToAddress(req.ToAsset.Address).Hex(),
+		From:                from,
+		Receiver:            receiver,
+		Kind:                "sell",
+		SellAmountBeforeFee: req.AmountBaseUnits,
+		ValidFor:            int64(orderValidity / time.Second),
+		AppData:             appData,
+		AppDataHash:         appDataHash,
+		SigningScheme:       "eip712",
+		PriceQuality:        "optimal",
+	})
+	if err != nil {
+		return quoteResponse{}, "", clierr.Wrap(clierr.CodeInternal, "encode cowswap quote request", err)
+	}
+	var out quoteResponse
+	if _, err := httpx.DoBodyJSON(ctx, c.http, http.MethodPost, base+"/api/v1/quote", body, nil, &out); err != nil {
+		return quoteResponse{}, "", err
+	}
+	if strings.TrimSpace(out.Quote.BuyAmount) == "" || strings.TrimSpace(out.Quote.SellAmount) == "" {
+		return quoteResponse{}, "", clierr.New(clierr.CodeUnavailable, "cowswap quote missing amounts")
+	}
+	return out, base, nil
+}
+
+func (c *Client) orderBookURL(chain id.Chain) (string, error) {
+	if _, ok := registry.CowSwapOrderBookURL(chain.EVMChainID); !ok {
+		return "", clierr.New(clierr.CodeUnsupported, "cowswap does not support chain "+chain.Slug)
+	}
+	if c.baseURL != "" {
+		return strings.TrimRight(c.baseURL, "/"), nil
+	}
+	base, _ := registry.CowSwapOrderBookURL(chain.EVMChainID)
+	return base, nil
+}
+
+func readAllowance(ctx context.Context, rpcURL string, token, owner, spender common.Address) (*big.Int, error) {
+	client, err := rpcx.DialContext(ctx, rpcURL)
+	if err != nil {
+		return nil, clierr.Wrap(clierr.CodeUnavailable, "connect rpc", err)
+	}
+	defer client.Close()
+	data, err := erc20ABI.Pack("allowance", owner, spender)
+	if err != nil {
+		return nil, clierr.Wrap(clierr.CodeInternal, "pack allowance call", err)
+	}
+	out, err := client.CallContract(ctx, ethereum.CallMsg{From: owner, To: &token, Data: data}, nil)
+	if err != nil {
+		return nil, clierr.Wrap(clierr.CodeUnavailable, "read allowance", err)
+	}
+	values, err := erc20ABI.Unpack("allowance", out)
+	if err != nil || len(values) == 0 {
+		return nil, clierr.Wrap(clierr.CodeUnavailable, "decode allowance", err)
+	}
+	allowance, ok := values[0].(*big.Int)
+	if !ok {
+		return nil, clierr.New(clierr.CodeUnavailable, "invalid allowance response")
+	}
+	return allowance, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return strings.TrimSpace(v)
+		}
+	}
+	return ""
+}
+
+func mustABI(raw string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(raw))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
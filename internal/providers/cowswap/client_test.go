@@ -0,0 +1,161 @@
+package cowswap
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ggonzalez94/defi-cli/internal/execution"
+	"github.com/ggonzalez94/defi-cli/internal/httpx"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/providers"
+)
+
+func newQuoteServer(t *testing.T, seen *quoteRequest) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v1/quote" {
+			t.Fatalf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(seen); err != nil {
+			t.Fatalf("decode quote request: %v", err)
+		}
+		_, _ = w.Write([]byte(`{"quote":{"sellAmount":"990000","buyAmount":"400000000000000","feeAmount":"10000","validTo":1900000000},"id":42,"verified":true}`))
+	}))
+}
+
+func newAllowanceRPCServer(allowanceHex string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		w.Header().Set("Content-Type", "application/json")
+		if req.Method != "eth_call" {
+			_, _ = fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"error":{"code":-32601,"message":"unsupported"}}`, req.ID)
+			return
+		}
+		_, _ = fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":"0x%064s"}`, req.ID, allowanceHex)
+	}))
+}
+
+func testAssets(t *testing.T) (id.Chain, id.Asset, id.Asset) {
+	t.Helper()
+	chain, err := id.ParseChain("ethereum")
+	if err != nil {
+		t.Fatalf("parse chain: %v", err)
+	}
+	usdc, _ := id.ParseAsset("USDC", chain)
+	weth, _ := id.ParseAsset("WETH", chain)
+	return chain, usdc, weth
+}
+
+func TestQuoteSwapUsesSellQuote(t *testing.T) {
+	var seen quoteRequest
+	server := newQuoteServer(t, &seen)
+	defer server.Close()
+
+	c := New(httpx.New(2*time.Second, 0))
+	c.baseURL = server.URL
+	chain, usdc, weth := testAssets(t)
+	quote, err := c.QuoteSwap(context.Background(), providers.SwapQuoteRequest{
+		Chain: chain, FromAsset: usdc, ToAsset: weth, AmountBaseUnits: "1000000", AmountDecimal: "1",
+	})
+	if err != nil {
+		t.Fatalf("QuoteSwap failed: %v", err)
+	}
+	if seen.Kind != "sell" || seen.SellAmountBeforeFee != "1000000" || seen.From != "0x0000000000000000000000000000000000000000" {
+		t.Fatalf("unexpected quote request: %+v", seen)
+	}
+	if quote.Provider != "cowswap" || quote.EstimatedOut.AmountBaseUnits != "400000000000000" {
+		t.Fatalf("unexpected quote: %+v", quote)
+	}
+	if quote.Firmness != providers.QuoteFirmnessFirm || quote.ExpiresAt != "2030-03-17T17:46:40Z" {
+		t.Fatalf("expected a firm quote expiring at validTo, got %q %q", quote.Firmness, quote.ExpiresAt)
+	}
+}
+
+func TestQuoteSwapRejectsExactOutputAndUnsupportedChain(t *testing.T) {
+	c := New(httpx.New(2*time.Second, 0))
+	chain, usdc, weth := testAssets(t)
+	_, err := c.QuoteSwap(context.Background(), providers.SwapQuoteRequest{
+		Chain: chain, FromAsset: usdc, ToAsset: weth, AmountBaseUnits: "1", TradeType: providers.SwapTradeTypeExactOutput,
+	})
+	if err == nil || !strings.Contains(err.Error(), "exact-input") {
+		t.Fatalf("expected exact-input error, got %v", err)
+	}
+	taiko, _ := id.ParseChain("taiko")
+	taikoUSDC, _ := id.ParseAsset("USDC", taiko)
+	taikoWETH, _ := id.ParseAsset("WETH", taiko)
+	if _, err := c.QuoteSwap(context.Background(), providers.SwapQuoteRequest{Chain: taiko, FromAsset: taikoUSDC, ToAsset: taikoWETH, AmountBaseUnits: "1"}); err == nil {
+		t.Fatal("expected unsupported chain error")
+	}
+}
+
+func TestBuildSwapActionAddsApprovalAndOrderStep(t *testing.T) {
+	var seen quoteRequest
+	server := newQuoteServer(t, &seen)
+	defer server.Close()
+	rpc := newAllowanceRPCServer("0")
+	defer rpc.Close()
+
+	c := New(httpx.New(2*time.Second, 0))
+	c.baseURL = server.URL
+	chain, usdc, weth := testAssets(t)
+	action, err := c.BuildSwapAction(context.Background(), providers.SwapQuoteRequest{
+		Chain: chain, FromAsset: usdc, ToAsset: weth, AmountBaseUnits: "1000000", AmountDecimal: "1",
+	}, providers.SwapExecutionOptions{
+		Sender:      "0x00000000000000000000000000000000000000AA",
+		SlippageBps: 100,
+		RPCURL:      rpc.URL,
+	})
+	if err != nil {
+		t.Fatalf("BuildSwapAction failed: %v", err)
+	}
+	if len(action.Steps) != 2 || action.Steps[0].Type != execution.StepTypeApproval || action.Steps[1].Type != execution.StepTypeOrder {
+		t.Fatalf("expected approval + order steps, got %+v", action.Steps)
+	}
+	order := action.Steps[1].Order
+	if order == nil {
+		t.Fatal("expected order details")
+	}
+	if order.SellAmount != "1000000" || order.FeeAmount != "0" || order.BuyAmount != "396000000000000" {
+		t.Fatalf("unexpected order amounts: %+v", order)
+	}
+	if order.QuoteID != 42 || order.ValidTo != 1900000000 || order.AppDataHash != appDataHash {
+		t.Fatalf("unexpected order fields: %+v", order)
+	}
+	if !strings.EqualFold(order.Receiver, "0x00000000000000000000000000000000000000AA") || seen.From != order.Receiver {
+		t.Fatalf("expected sender as receiver and quote from, got %+v / %+v", order, seen)
+	}
+	if action.InputAmount != "1000000" {
+		t.Fatalf("unexpected input amount: %s", action.InputAmount)
+	}
+}
+
+func TestBuildSwapActionSkipsApprovalWithAllowance(t *testing.T) {
+	var seen quoteRequest
+	server := newQuoteServer(t, &seen)
+	defer server.Close()
+	rpc := newAllowanceRPCServer("ffffffff")
+	defer rpc.Close()
+
+	c := New(httpx.New(2*time.Second, 0))
+	c.baseURL = server.URL
+	chain, usdc, weth := testAssets(t)
+	action, err := c.BuildSwapAction(context.Background(), providers.SwapQuoteRequest{
+		Chain: chain, FromAsset: usdc, ToAsset: weth, AmountBaseUnits: "1000000", AmountDecimal: "1",
+	}, providers.SwapExecutionOptions{Sender: "0x00000000000000000000000000000000000000AA", RPCURL: rpc.URL})
+	if err != nil {
+		t.Fatalf("BuildSwapAction failed: %v", err)
+	}
+	if len(action.Steps) != 1 || action.Steps[0].Type != execution.StepTypeOrder {
+		t.Fatalf("expected a single order step, got %+v", action.Steps)
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/ggonzalez94/defi-cli/internal/clock"
 	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
 	"github.com/ggonzalez94/defi-cli/internal/httpx"
 	"github.com/ggonzalez94/defi-cli/internal/id"
@@ -23,6 +24,11 @@ const (
 	defaultAPIBase           = "https://api.llama.fi"
 	defaultBridgeAPIURL      = "https://pro-api.llama.fi"
 	defaultStablecoinsAPIURL = "https://stablecoins.llama.fi"
+	defaultCoinsAPIURL       = "https://coins.llama.fi"
+
+	// protocolsTTL is how long the shared /protocols payload is reused by
+	// protocols top and protocols categories.
+	protocolsTTL = 5 * time.Minute
 )
 
 type Client struct {
@@ -30,6 +36,7 @@ type Client struct {
 	apiBase           string
 	bridgeBaseURL     string
 	stablecoinsAPIURL string
+	coinsAPIURL       string
 	apiKey            string
 	now               func() time.Time
 }
@@ -40,8 +47,9 @@ func New(httpClient *httpx.Client, apiKey string) *Client {
 		apiBase:           defaultAPIBase,
 		bridgeBaseURL:     defaultBridgeAPIURL,
 		stablecoinsAPIURL: defaultStablecoinsAPIURL,
+		coinsAPIURL:       defaultCoinsAPIURL,
 		apiKey:            strings.TrimSpace(apiKey),
-		now:               time.Now,
+		now:               clock.Now,
 	}
 }
 
@@ -57,9 +65,12 @@ func (c *Client) Info() model.ProviderInfo {
 			"protocols.categories",
 			"protocols.fees",
 			"protocols.revenue",
+			"protocols.volume",
 			"dexes.volume",
 			"stablecoins.top",
 			"stablecoins.chains",
+			"history.tvl",
+			"history.price",
 			"bridge.list",
 			"bridge.details",
 		},
@@ -185,11 +196,19 @@ func (c *Client) ChainsAssets(ctx context.Context, chain id.Chain, asset id.Asse
 }
 
 type protocolResp struct {
-	Name      string             `json:"name"`
-	Category  string             `json:"category"`
-	TVL       float64            `json:"tvl"`
-	Chains    []string           `json:"chains"`
-	ChainTvls map[string]float64 `json:"chainTvls"`
+	Name           string              `json:"name"`
+	Slug           string              `json:"slug"`
+	ParentProtocol string              `json:"parentProtocol"`
+	Category       string              `json:"category"`
+	TVL            float64             `json:"tvl"`
+	Change1d       *float64            `json:"change_1d"`
+	Change7d       *float64            `json:"change_7d"`
+	Chains         []string            `json:"chains"`
+	ChainTvls      map[string]float64  `json:"chainTvls"`
+	Audits         json.RawMessage     `json:"audits"`
+	AuditLinks     []string            `json:"audit_links"`
+	ListedAt       int64               `json:"listedAt"`
+	Hallmarks      [][]json.RawMessage `json:"hallmarks"`
 }
 
 func (c *Client) ProtocolsTop(ctx context.Context, category string, chain string, limit int) ([]model.ProtocolTVL, error) {
@@ -199,7 +218,7 @@ func (c *Client) ProtocolsTop(ctx context.Context, category string, chain string
 		return nil, clierr.Wrap(clierr.CodeInternal, "build protocols request", err)
 	}
 	var resp []protocolResp
-	if _, err := c.http.DoJSON(ctx, req, &resp); err != nil {
+	if err := c.http.DoSharedJSON(ctx, req, protocolsTTL, &resp); err != nil {
 		return nil, err
 	}
 
@@ -275,7 +294,7 @@ func (c *Client) ProtocolsCategories(ctx context.Context) ([]model.ProtocolCateg
 		return nil, clierr.Wrap(clierr.CodeInternal, "build protocols request", err)
 	}
 	var resp []protocolResp
-	if _, err := c.http.DoJSON(ctx, req, &resp); err != nil {
+	if err := c.http.DoSharedJSON(ctx, req, protocolsTTL, &resp); err != nil {
 		return nil, err
 	}
 
@@ -322,6 +341,7 @@ func (c *Client) ProtocolsCategories(ctx context.Context) ([]model.ProtocolCateg
 
 type feesProtocolResp struct {
 	Name      string   `json:"name"`
+	Slug      string   `json:"slug"`
 	Category  string   `json:"category"`
 	Total24h  *float64 `json:"total24h"`
 	Total7d   *float64 `json:"total7d"`
@@ -336,7 +356,7 @@ type feesOverviewResp struct {
 	Protocols []feesProtocolResp `json:"protocols"`
 }
 
-func (c *Client) ProtocolsFees(ctx context.Context, category string, chain string, limit int) ([]model.ProtocolFees, error) {
+func (c *Client) ProtocolsFees(ctx context.Context, metricsReq providers.ProtocolMetricsRequest) ([]model.ProtocolFees, error) {
 	endpoint := c.apiBase + "/overview/fees?excludeTotalDataChart=true&excludeTotalDataChartBreakdown=true"
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
 	if err != nil {
@@ -347,7 +367,8 @@ func (c *Client) ProtocolsFees(ctx context.Context, category string, chain strin
 		return nil, err
 	}
 
-	filtered := filterFeesProtocols(resp.Protocols, category, chain)
+	filtered := filterProtocolMetrics(resp.Protocols, metricsReq)
+	limit := metricsReq.Limit
 
 	out := make([]model.ProtocolFees, 0, capLimit(limit, len(filtered)))
 	for i := 0; i < capLimit(limit, len(filtered)); i++ {
@@ -403,11 +424,29 @@ func (c *Client) ProtocolsRevenue(ctx context.Context, category string, chain st
 // filterFeesProtocols filters protocols by positive 24h value, optional category,
 // and optional chain presence, then sorts descending by 24h total.
 func filterFeesProtocols(protocols []feesProtocolResp, category, chain string) []feesProtocolResp {
-	normCategory := strings.ToLower(strings.TrimSpace(category))
-	normChain := strings.ToLower(strings.TrimSpace(chain))
+	return filterProtocolMetrics(protocols, providers.ProtocolMetricsRequest{Category: category, Chain: chain})
+}
+
+// filterProtocolMetrics is filterFeesProtocols with an optional protocol
+// filter and a selectable window: protocols need a positive total for the
+// window and are sorted descending by it.
+func filterProtocolMetrics(protocols []feesProtocolResp, req providers.ProtocolMetricsRequest) []feesProtocolResp {
+	normCategory := strings.ToLower(strings.TrimSpace(req.Category))
+	normChain := strings.ToLower(strings.TrimSpace(req.Chain))
+	normProtocol := strings.ToLower(strings.TrimSpace(req.Protocol))
+	total := func(p feesProtocolResp) *float64 {
+		switch req.Window {
+		case providers.MetricsWindow7d:
+			return p.Total7d
+		case providers.MetricsWindow30d:
+			return p.Total30d
+		default:
+			return p.Total24h
+		}
+	}
 	filtered := make([]feesProtocolResp, 0, len(protocols))
 	for _, p := range protocols {
-		if p.Total24h == nil || *p.Total24h <= 0 {
+		if v := total(p); v == nil || *v <= 0 {
 			continue
 		}
 		if normCategory != "" && strings.ToLower(p.Category) != normCategory {
@@ -416,10 +455,13 @@ func filterFeesProtocols(protocols []feesProtocolResp, category, chain string) [
 		if normChain != "" && !containsChain(p.Chains, normChain) {
 			continue
 		}
+		if normProtocol != "" && strings.ToLower(p.Name) != normProtocol && strings.ToLower(p.Slug) != normProtocol {
+			continue
+		}
 		filtered = append(filtered, p)
 	}
 	sort.Slice(filtered, func(i, j int) bool {
-		return valOrZero(filtered[i].Total24h) > valOrZero(filtered[j].Total24h)
+		return valOrZero(total(filtered[i])) > valOrZero(total(filtered[j]))
 	})
 	return filtered
 }
@@ -433,6 +475,10 @@ func capLimit(limit, total int) int {
 }
 
 func (c *Client) DexesVolume(ctx context.Context, chain string, limit int) ([]model.DexVolume, error) {
+	return c.ProtocolsVolume(ctx, providers.ProtocolMetricsRequest{Chain: chain, Limit: limit})
+}
+
+func (c *Client) ProtocolsVolume(ctx context.Context, metricsReq providers.ProtocolMetricsRequest) ([]model.DexVolume, error) {
 	endpoint := c.apiBase + "/overview/dexs?excludeTotalDataChart=true&excludeTotalDataChartBreakdown=true"
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
 	if err != nil {
@@ -443,7 +489,8 @@ func (c *Client) DexesVolume(ctx context.Context, chain string, limit int) ([]mo
 		return nil, err
 	}
 
-	filtered := filterFeesProtocols(resp.Protocols, "", chain)
+	filtered := filterProtocolMetrics(resp.Protocols, metricsReq)
+	limit := metricsReq.Limit
 
 	out := make([]model.DexVolume, 0, capLimit(limit, len(filtered)))
 	for i := 0; i < capLimit(limit, len(filtered)); i++ {
@@ -473,6 +520,8 @@ func containsChain(chains []string, target string) bool {
 }
 
 type stablecoinResp struct {
+	ID               string                           `json:"id"`
+	ChainCirculating map[string]stablecoinChainAmount `json:"chainCirculating"`
 	Name           string           `json:"name"`
 	Symbol         string           `json:"symbol"`
 	PegType        string           `json:"pegType"`
@@ -497,6 +546,12 @@ func (p peggedAmount) total() float64 {
 	return sum
 }
 
+type stablecoinChainAmount struct {
+	Current       peggedAmount `json:"current"`
+	CircPrevWeek  peggedAmount `json:"circulatingPrevWeek"`
+	CircPrevMonth peggedAmount `json:"circulatingPrevMonth"`
+}
+
 type stablecoinsEnvelope struct {
 	PeggedAssets []stablecoinResp `json:"peggedAssets"`
 }
@@ -536,22 +591,132 @@ func (c *Client) StablecoinsTop(ctx context.Context, pegType string, limit int)
 			price = *item.Price
 		}
 		out = append(out, model.Stablecoin{
-			Rank:           i + 1,
-			Name:           item.Name,
-			Symbol:         item.Symbol,
-			PegType:        item.PegType,
-			PegMechanism:   item.PegMechanism,
-			CirculatingUSD: item.Circulating.total(),
-			Price:          price,
-			Chains:         len(item.Chains),
-			DayChangeUSD:   item.Circulating.total() - item.CircPrevDay.total(),
-			WeekChangeUSD:  item.Circulating.total() - item.CircPrevWeek.total(),
-			MonthChangeUSD: item.Circulating.total() - item.CircPrevMonth.total(),
+			Rank:            i + 1,
+			Name:            item.Name,
+			Symbol:          item.Symbol,
+			PegType:         item.PegType,
+			PegMechanism:    item.PegMechanism,
+			CirculatingUSD:  item.Circulating.total(),
+			Price:           price,
+			Chains:          len(item.Chains),
+			DayChangeUSD:    item.Circulating.total() - item.CircPrevDay.total(),
+			WeekChangeUSD:   item.Circulating.total() - item.CircPrevWeek.total(),
+			MonthChangeUSD:  item.Circulating.total() - item.CircPrevMonth.total(),
+			PegDeviationPct: pegDeviationPct(item.PegType, item.Price),
+			WeekChangePct:   changePct(item.Circulating.total(), item.CircPrevWeek.total()),
+			MonthChangePct:  changePct(item.Circulating.total(), item.CircPrevMonth.total()),
 		})
 	}
 	return out, nil
 }
 
+// StablecoinDetails returns supply, peg, and per-chain distribution for one
+// stablecoin matched by DefiLlama id, symbol, or name.
+func (c *Client) StablecoinDetails(ctx context.Context, stablecoin string) (model.StablecoinDetails, error) {
+	ref := strings.TrimSpace(stablecoin)
+	if ref == "" {
+		return model.StablecoinDetails{}, clierr.New(clierr.CodeUsage, "stablecoin is required")
+	}
+	endpoint := c.stablecoinsAPIURL + "/stablecoins?includePrices=true"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return model.StablecoinDetails{}, clierr.Wrap(clierr.CodeInternal, "build stablecoins request", err)
+	}
+	var resp stablecoinsEnvelope
+	if _, err := c.http.DoJSON(ctx, req, &resp); err != nil {
+		return model.StablecoinDetails{}, err
+	}
+
+	// Symbols are not unique (bridged variants share tickers), so prefer an
+	// exact id match and otherwise the largest coin by circulating supply.
+	var match *stablecoinResp
+	for i := range resp.PeggedAssets {
+		item := &resp.PeggedAssets[i]
+		if item.ID == ref {
+			match = item
+			break
+		}
+		if !strings.EqualFold(item.Symbol, ref) && !strings.EqualFold(item.Name, ref) {
+			continue
+		}
+		if match == nil || item.Circulating.total() > match.Circulating.total() {
+			match = item
+		}
+	}
+	if match == nil {
+		return model.StablecoinDetails{}, clierr.New(clierr.CodeUsage, fmt.Sprintf("stablecoin not found: %s", ref))
+	}
+
+	total := match.Circulating.total()
+	chains := make([]model.StablecoinChainBalance, 0, len(match.ChainCirculating))
+	for name, amount := range match.ChainCirculating {
+		current := amount.Current.total()
+		if current <= 0 {
+			continue
+		}
+		share := 0.0
+		if total > 0 {
+			share = current / total * 100
+		}
+		chainID := ""
+		if chain, err := id.ParseChain(name); err == nil {
+			chainID = chain.CAIP2
+		}
+		chains = append(chains, model.StablecoinChainBalance{
+			Chain:          name,
+			ChainID:        chainID,
+			CirculatingUSD: current,
+			SharePct:       share,
+			WeekChangeUSD:  current - amount.CircPrevWeek.total(),
+			MonthChangeUSD: current - amount.CircPrevMonth.total(),
+		})
+	}
+	sort.Slice(chains, func(i, j int) bool {
+		if chains[i].CirculatingUSD != chains[j].CirculatingUSD {
+			return chains[i].CirculatingUSD > chains[j].CirculatingUSD
+		}
+		return chains[i].Chain < chains[j].Chain
+	})
+
+	price := 0.0
+	if match.Price != nil {
+		price = *match.Price
+	}
+	return model.StablecoinDetails{
+		ID:              match.ID,
+		Name:            match.Name,
+		Symbol:          match.Symbol,
+		PegType:         match.PegType,
+		PegMechanism:    match.PegMechanism,
+		Price:           price,
+		PegDeviationPct: pegDeviationPct(match.PegType, match.Price),
+		CirculatingUSD:  total,
+		DayChangeUSD:    total - match.CircPrevDay.total(),
+		WeekChangeUSD:   total - match.CircPrevWeek.total(),
+		WeekChangePct:   changePct(total, match.CircPrevWeek.total()),
+		MonthChangeUSD:  total - match.CircPrevMonth.total(),
+		MonthChangePct:  changePct(total, match.CircPrevMonth.total()),
+		Chains:          chains,
+	}, nil
+}
+
+// pegDeviationPct is only meaningful for USD pegs because DefiLlama reports
+// prices in USD.
+func pegDeviationPct(pegType string, price *float64) *float64 {
+	if price == nil || !strings.EqualFold(pegType, "peggedUSD") {
+		return nil
+	}
+	v := (*price - 1) * 100
+	return &v
+}
+
+func changePct(current, previous float64) float64 {
+	if previous <= 0 {
+		return 0
+	}
+	return (current - previous) / previous * 100
+}
+
 type stablecoinChainResp struct {
 	GeckoID            string                  `json:"gecko_id"`
 	TotalCirculatingUSD map[string]float64     `json:"totalCirculatingUSD"`
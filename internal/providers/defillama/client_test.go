@@ -2,6 +2,7 @@ package defillama
 
 import (
 	"context"
+	"math"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -453,6 +454,63 @@ func TestStablecoinsTopSortsAndLimits(t *testing.T) {
 	}
 }
 
+func TestStablecoinDetailsBuildsChainDistribution(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/stablecoins", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{
+			"peggedAssets":[
+				{"id":"2","name":"USD Coin","symbol":"USDC","pegType":"peggedUSD","pegMechanism":"fiat-backed",
+				 "circulating":{"peggedUSD":1000},"circulatingPrevDay":{"peggedUSD":990},
+				 "circulatingPrevWeek":{"peggedUSD":900},"circulatingPrevMonth":{"peggedUSD":800},
+				 "chainCirculating":{
+					"Ethereum":{"current":{"peggedUSD":750},"circulatingPrevWeek":{"peggedUSD":700},"circulatingPrevMonth":{"peggedUSD":600}},
+					"Base":{"current":{"peggedUSD":250},"circulatingPrevWeek":{"peggedUSD":200},"circulatingPrevMonth":{"peggedUSD":200}},
+					"Dead":{"current":{"peggedUSD":0}}
+				 },
+				 "chains":["Ethereum","Base"],"price":0.995},
+				{"id":"99","name":"Bridged USDC","symbol":"USDC","pegType":"peggedUSD","pegMechanism":"fiat-backed",
+				 "circulating":{"peggedUSD":10},"chains":["Ethereum"],"price":1.0}
+			]
+		}`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	c := New(httpx.New(2*time.Second, 0), "")
+	c.stablecoinsAPIURL = srv.URL
+
+	details, err := c.StablecoinDetails(context.Background(), "usdc")
+	if err != nil {
+		t.Fatalf("StablecoinDetails failed: %v", err)
+	}
+	if details.ID != "2" || details.CirculatingUSD != 1000 {
+		t.Fatalf("expected largest USDC to win symbol match, got %+v", details)
+	}
+	if details.PegDeviationPct == nil || math.Abs(*details.PegDeviationPct-(-0.5)) > 1e-9 {
+		t.Fatalf("unexpected peg deviation: %v", details.PegDeviationPct)
+	}
+	if details.WeekChangeUSD != 100 || math.Abs(details.MonthChangePct-25) > 1e-9 {
+		t.Fatalf("unexpected supply change: %+v", details)
+	}
+	if len(details.Chains) != 2 {
+		t.Fatalf("expected zero-supply chain skipped, got %+v", details.Chains)
+	}
+	if details.Chains[0].Chain != "Ethereum" || details.Chains[0].ChainID != "eip155:1" || details.Chains[0].SharePct != 75 {
+		t.Fatalf("unexpected first chain: %+v", details.Chains[0])
+	}
+	if details.Chains[1].WeekChangeUSD != 50 {
+		t.Fatalf("unexpected base week change: %+v", details.Chains[1])
+	}
+
+	byID, err := c.StablecoinDetails(context.Background(), "99")
+	if err != nil || byID.Name != "Bridged USDC" {
+		t.Fatalf("expected id lookup to select bridged variant, got %+v err=%v", byID, err)
+	}
+	if _, err := c.StablecoinDetails(context.Background(), "NOPE"); err == nil {
+		t.Fatal("expected error for unknown stablecoin")
+	}
+}
+
 func TestStablecoinsTopFiltersByPegType(t *testing.T) {
 	mux := http.NewServeMux()
 	mux.HandleFunc("/stablecoins", func(w http.ResponseWriter, r *http.Request) {
@@ -666,7 +724,7 @@ func TestProtocolsFeesSortsAndLimits(t *testing.T) {
 	c := New(httpx.New(2*time.Second, 0), "")
 	c.apiBase = srv.URL
 
-	items, err := c.ProtocolsFees(context.Background(), "", "", 2)
+	items, err := c.ProtocolsFees(context.Background(), providers.ProtocolMetricsRequest{Limit: 2})
 	if err != nil {
 		t.Fatalf("ProtocolsFees failed: %v", err)
 	}
@@ -701,7 +759,7 @@ func TestProtocolsFeesFiltersByCategory(t *testing.T) {
 	c := New(httpx.New(2*time.Second, 0), "")
 	c.apiBase = srv.URL
 
-	items, err := c.ProtocolsFees(context.Background(), "Dexs", "", 0)
+	items, err := c.ProtocolsFees(context.Background(), providers.ProtocolMetricsRequest{Category: "Dexs"})
 	if err != nil {
 		t.Fatalf("ProtocolsFees with category filter failed: %v", err)
 	}
@@ -733,7 +791,7 @@ func TestProtocolsFeesFiltersByChain(t *testing.T) {
 	c := New(httpx.New(2*time.Second, 0), "")
 	c.apiBase = srv.URL
 
-	items, err := c.ProtocolsFees(context.Background(), "", "Ethereum", 0)
+	items, err := c.ProtocolsFees(context.Background(), providers.ProtocolMetricsRequest{Chain: "Ethereum"})
 	if err != nil {
 		t.Fatalf("ProtocolsFees with chain filter failed: %v", err)
 	}
@@ -766,7 +824,7 @@ func TestProtocolsFeesFiltersByCategoryAndChain(t *testing.T) {
 	c := New(httpx.New(2*time.Second, 0), "")
 	c.apiBase = srv.URL
 
-	items, err := c.ProtocolsFees(context.Background(), "Dexs", "Ethereum", 0)
+	items, err := c.ProtocolsFees(context.Background(), providers.ProtocolMetricsRequest{Category: "Dexs", Chain: "Ethereum"})
 	if err != nil {
 		t.Fatalf("ProtocolsFees with category+chain filter failed: %v", err)
 	}
@@ -799,7 +857,7 @@ func TestProtocolsFeesSkipsNullAndZero(t *testing.T) {
 	c := New(httpx.New(2*time.Second, 0), "")
 	c.apiBase = srv.URL
 
-	items, err := c.ProtocolsFees(context.Background(), "", "", 0)
+	items, err := c.ProtocolsFees(context.Background(), providers.ProtocolMetricsRequest{})
 	if err != nil {
 		t.Fatalf("ProtocolsFees failed: %v", err)
 	}
@@ -1015,6 +1073,48 @@ func TestDexesVolumeSortsAndLimits(t *testing.T) {
 	}
 }
 
+func TestProtocolsVolumeWindowAndProtocolFilter(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/overview/dexs", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{
+			"protocols":[
+				{"name":"Uniswap V3","slug":"uniswap-v3","total24h":5000000,"total7d":30000000,"total30d":90000000,"chains":["Ethereum","Base"]},
+				{"name":"Curve DEX","slug":"curve-dex","total24h":6000000,"total7d":20000000,"total30d":120000000,"chains":["Ethereum"]},
+				{"name":"Weekly Only","slug":"weekly-only","total24h":0,"total7d":40000000,"chains":["Ethereum"]}
+			]
+		}`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	c := New(httpx.New(2*time.Second, 0), "")
+	c.apiBase = srv.URL
+
+	weekly, err := c.ProtocolsVolume(context.Background(), providers.ProtocolMetricsRequest{Window: providers.MetricsWindow7d})
+	if err != nil {
+		t.Fatalf("ProtocolsVolume failed: %v", err)
+	}
+	if len(weekly) != 3 || weekly[0].Protocol != "Weekly Only" || weekly[1].Protocol != "Uniswap V3" {
+		t.Fatalf("expected 7d ranking, got %+v", weekly)
+	}
+
+	monthly, err := c.ProtocolsVolume(context.Background(), providers.ProtocolMetricsRequest{Window: providers.MetricsWindow30d, Limit: 1})
+	if err != nil {
+		t.Fatalf("ProtocolsVolume failed: %v", err)
+	}
+	if len(monthly) != 1 || monthly[0].Protocol != "Curve DEX" {
+		t.Fatalf("expected Curve DEX to lead 30d ranking, got %+v", monthly)
+	}
+
+	bySlug, err := c.ProtocolsVolume(context.Background(), providers.ProtocolMetricsRequest{Protocol: "UNISWAP-V3"})
+	if err != nil {
+		t.Fatalf("ProtocolsVolume failed: %v", err)
+	}
+	if len(bySlug) != 1 || bySlug[0].Protocol != "Uniswap V3" || bySlug[0].Rank != 1 {
+		t.Fatalf("expected slug filter to match Uniswap V3, got %+v", bySlug)
+	}
+}
+
 func TestDexesVolumeFiltersByChain(t *testing.T) {
 	mux := http.NewServeMux()
 	mux.HandleFunc("/overview/dexs", func(w http.ResponseWriter, r *http.Request) {
@@ -1190,3 +1290,238 @@ func TestBridgeDetailsBySlugIncludesBreakdown(t *testing.T) {
 		t.Fatalf("expected CAIP chain id for Base, got %+v", got.ChainBreakdown[0])
 	}
 }
+
+func TestTVLHistoryChainFiltersRange(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v2/historicalChainTvl/Base", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[{"date":1767225600,"tvl":100},{"date":1767398400,"tvl":300},{"date":1767312000,"tvl":200}]`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	c := New(httpx.New(2*time.Second, 0), "")
+	c.apiBase = srv.URL
+	chain, _ := id.ParseChain("base")
+	series, err := c.TVLHistory(context.Background(), providers.TVLHistoryRequest{
+		Chain:     chain,
+		StartTime: time.Unix(1767312000, 0).UTC(),
+		EndTime:   time.Unix(1767398400, 0).UTC(),
+	})
+	if err != nil {
+		t.Fatalf("TVLHistory failed: %v", err)
+	}
+	if series.Metric != "tvl_usd" || series.ChainID != "eip155:8453" {
+		t.Fatalf("unexpected series metadata: %+v", series)
+	}
+	if len(series.Points) != 2 || series.Points[0].Value != 200 || series.Points[1].Value != 300 {
+		t.Fatalf("unexpected points: %+v", series.Points)
+	}
+}
+
+func TestTVLHistoryProtocolChainBreakdown(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/protocol/aave-v3", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{
+			"name":"Aave V3",
+			"tvl":[{"date":1767225600,"totalLiquidityUSD":1000}],
+			"chainTvls":{
+				"Ethereum-borrowed":{"tvl":[{"date":1767225600,"totalLiquidityUSD":9}]},
+				"Ethereum":{"tvl":[{"date":1767225600,"totalLiquidityUSD":700}]}
+			}
+		}`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	c := New(httpx.New(2*time.Second, 0), "")
+	c.apiBase = srv.URL
+	chain, _ := id.ParseChain("ethereum")
+	req := providers.TVLHistoryRequest{
+		Chain:     chain,
+		Protocol:  "aave-v3",
+		StartTime: time.Unix(1767225600, 0).UTC(),
+		EndTime:   time.Unix(1767312000, 0).UTC(),
+	}
+	series, err := c.TVLHistory(context.Background(), req)
+	if err != nil {
+		t.Fatalf("TVLHistory failed: %v", err)
+	}
+	if len(series.Points) != 1 || series.Points[0].Value != 700 || series.Protocol != "aave-v3" {
+		t.Fatalf("unexpected protocol chain series: %+v", series)
+	}
+
+	req.Chain, _ = id.ParseChain("base")
+	if _, err := c.TVLHistory(context.Background(), req); err == nil {
+		t.Fatal("expected error for chain without protocol TVL")
+	}
+}
+
+func TestChainsTVLChangeComparesWindowStart(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v2/historicalChainTvl/Ethereum", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[{"date":1767225600,"tvl":200},{"date":1767744000,"tvl":100},{"date":1767830400,"tvl":80}]`))
+	})
+	mux.HandleFunc("/v2/historicalChainTvl/Base", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[{"date":1767830400,"tvl":50}]`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	c := New(httpx.New(2*time.Second, 0), "")
+	c.apiBase = srv.URL
+	changes, err := c.ChainsTVLChange(context.Background(), []string{"Ethereum", "Base"}, providers.MetricsWindow24h)
+	if err != nil {
+		t.Fatalf("ChainsTVLChange failed: %v", err)
+	}
+	if len(changes) != 1 || changes["Ethereum"] != -20 {
+		t.Fatalf("unexpected 24h changes: %+v", changes)
+	}
+	changes, err = c.ChainsTVLChange(context.Background(), []string{"Ethereum"}, providers.MetricsWindow7d)
+	if err != nil {
+		t.Fatalf("ChainsTVLChange 7d failed: %v", err)
+	}
+	if changes["Ethereum"] != -60 {
+		t.Fatalf("unexpected 7d change: %+v", changes)
+	}
+}
+
+func TestProtocolsTVLChangeUsesChainHistory(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/protocols", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[{"name":"Aave V3","slug":"aave-v3","tvl":1000,"change_1d":-1.5,"change_7d":4}]`))
+	})
+	mux.HandleFunc("/protocol/aave-v3", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{
+			"name":"Aave V3",
+			"chainTvls":{
+				"Ethereum-borrowed":{"tvl":[{"date":1767744000,"totalLiquidityUSD":9},{"date":1767830400,"totalLiquidityUSD":1}]},
+				"Ethereum":{"tvl":[{"date":1767744000,"totalLiquidityUSD":800},{"date":1767830400,"totalLiquidityUSD":600}]}
+			}
+		}`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	c := New(httpx.New(2*time.Second, 0), "")
+	c.apiBase = srv.URL
+	changes, err := c.ProtocolsTVLChange(context.Background(), "", []string{"Aave V3"}, providers.MetricsWindow7d)
+	if err != nil {
+		t.Fatalf("ProtocolsTVLChange failed: %v", err)
+	}
+	if changes["Aave V3"] != 4 {
+		t.Fatalf("expected /protocols change_7d, got %+v", changes)
+	}
+	changes, err = c.ProtocolsTVLChange(context.Background(), "ethereum", []string{"Aave V3", "Unknown"}, providers.MetricsWindow24h)
+	if err != nil {
+		t.Fatalf("ProtocolsTVLChange on chain failed: %v", err)
+	}
+	if len(changes) != 1 || changes["Aave V3"] != -25 {
+		t.Fatalf("expected Ethereum history change, got %+v", changes)
+	}
+}
+
+func TestProtocolSecurityMergesParentListings(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[
+			{"name":"Aave V2","slug":"aave-v2","parentProtocol":"parent#aave","category":"Lending","tvl":100,"audits":"2","audit_links":["https://audit/a"],"listedAt":1600000000},
+			{"name":"Aave V3","slug":"aave-v3","parentProtocol":"parent#aave","category":"Lending","tvl":900,"audits":"3","audit_links":["https://audit/a","https://audit/b"],"listedAt":1650000000,
+			 "hallmarks":[[1672531200,"Launch on Base"],[[1680307200,1680393600],"Oracle exploit on Fantom"]]},
+			{"name":"Ether.fi Stake","slug":"ether.fi-stake","parentProtocol":"parent#ether.fi","category":"Liquid Restaking","tvl":50,"audits":"0"}
+		]`))
+	}))
+	defer srv.Close()
+
+	c := New(httpx.New(2*time.Second, 0), "")
+	c.apiBase = srv.URL
+	c.now = func() time.Time { return time.Unix(1600000000, 0).Add(1000 * 24 * time.Hour) }
+	security, err := c.ProtocolSecurity(context.Background(), "Aave")
+	if err != nil {
+		t.Fatalf("ProtocolSecurity failed: %v", err)
+	}
+	if len(security.Listings) != 2 || security.Listings[0] != "Aave V3" || security.TVLUSD != 1000 {
+		t.Fatalf("expected both Aave listings, largest first: %+v", security)
+	}
+	if security.Audits != 3 || len(security.AuditLinks) != 2 || security.ListingAgeDays != 1000 {
+		t.Fatalf("unexpected audit metadata: %+v", security)
+	}
+	if len(security.Exploits) != 1 || security.Exploits[0].Date != "2023-04-01" {
+		t.Fatalf("expected only the exploit hallmark: %+v", security.Exploits)
+	}
+
+	etherfi, err := c.ProtocolSecurity(context.Background(), "etherfi")
+	if err != nil || etherfi.Listings[0] != "Ether.fi Stake" || etherfi.Audits != 0 {
+		t.Fatalf("expected punctuation-insensitive parent match, got %+v err=%v", etherfi, err)
+	}
+	if _, err := c.ProtocolSecurity(context.Background(), "nope"); err == nil {
+		t.Fatal("expected unknown protocol to fail")
+	}
+}
+
+func TestPriceHistoryUsesCoinsChart(t *testing.T) {
+	var gotPath, gotPeriod string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/chart/", func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotPeriod = r.URL.Query().Get("period")
+		_, _ = w.Write([]byte(`{"coins":{"base:0x833589fcd6edb6e08f4c7c32d4f71b54bda02913":{"symbol":"USDC","prices":[{"timestamp":1767229200,"price":0.999},{"timestamp":1767225600,"price":1.001}]}}}`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	c := New(httpx.New(2*time.Second, 0), "")
+	c.coinsAPIURL = srv.URL
+	chain, _ := id.ParseChain("base")
+	asset, err := id.ParseAsset("USDC", chain)
+	if err != nil {
+		t.Fatalf("ParseAsset failed: %v", err)
+	}
+	series, err := c.PriceHistory(context.Background(), providers.PriceHistoryRequest{
+		Chain:     chain,
+		Asset:     asset,
+		StartTime: time.Unix(1767225600, 0).UTC(),
+		EndTime:   time.Unix(1767229200, 0).UTC(),
+		Interval:  providers.YieldHistoryIntervalHour,
+	})
+	if err != nil {
+		t.Fatalf("PriceHistory failed: %v", err)
+	}
+	if gotPath != "/chart/base:0x833589fcd6edb6e08f4c7c32d4f71b54bda02913" || gotPeriod != "1h" {
+		t.Fatalf("unexpected request path=%s period=%s", gotPath, gotPeriod)
+	}
+	if series.Metric != "price_usd" || series.Symbol != "USDC" || len(series.Points) != 2 || series.Points[0].Value != 1.001 {
+		t.Fatalf("unexpected price series: %+v", series)
+	}
+}
+
+func TestTokenPricesUsesCurrentCoinsPrices(t *testing.T) {
+	var gotPath, gotWidth string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/prices/current/", func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotWidth = r.URL.Query().Get("searchWidth")
+		_, _ = w.Write([]byte(`{"coins":{"base:0x833589fcd6edb6e08f4c7c32d4f71b54bda02913":{"symbol":"USDC","decimals":6,"price":0.999,"timestamp":1767229200,"confidence":0.99}}}`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	c := New(httpx.New(2*time.Second, 0), "")
+	c.coinsAPIURL = srv.URL
+	chain, _ := id.ParseChain("base")
+	prices, err := c.TokenPrices(context.Background(), chain, []string{
+		"0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913",
+		"0x0000000000000000000000000000000000000000",
+	})
+	if err != nil {
+		t.Fatalf("TokenPrices failed: %v", err)
+	}
+	if gotPath != "/prices/current/base:0x833589fcd6edb6e08f4c7c32d4f71b54bda02913,base:0x0000000000000000000000000000000000000000" || gotWidth != "1h" {
+		t.Fatalf("unexpected request path=%s searchWidth=%s", gotPath, gotWidth)
+	}
+	price, ok := prices["0x833589fcd6edb6e08f4c7c32d4f71b54bda02913"]
+	if !ok || price.Decimals != 6 || price.PriceUSD != 0.999 || price.Symbol != "USDC" {
+		t.Fatalf("unexpected prices: %+v", prices)
+	}
+	if _, ok := prices["0x0000000000000000000000000000000000000000"]; ok {
+		t.Fatal("expected tokens missing from the response to be left out")
+	}
+}
@@ -0,0 +1,290 @@
+package defillama
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/model"
+	"github.com/ggonzalez94/defi-cli/internal/providers"
+)
+
+// coinsChainPrefixes maps chain slugs to the DefiLlama coins API prefix where
+// the two differ.
+var coinsChainPrefixes = map[string]string{
+	"avalanche":   "avax",
+	"gnosis":      "xdai",
+	"zksync":      "era",
+	"world-chain": "wc",
+	"hyperevm":    "hyperliquid",
+}
+
+type tvlHistoryPoint struct {
+	Date              float64 `json:"date"`
+	TVL               float64 `json:"tvl"`
+	TotalLiquidityUSD float64 `json:"totalLiquidityUSD"`
+}
+
+func (p tvlHistoryPoint) value() float64 {
+	if p.TotalLiquidityUSD != 0 {
+		return p.TotalLiquidityUSD
+	}
+	return p.TVL
+}
+
+type protocolHistoryResp struct {
+	Name      string            `json:"name"`
+	TVL       []tvlHistoryPoint `json:"tvl"`
+	ChainTvls map[string]struct {
+		TVL []tvlHistoryPoint `json:"tvl"`
+	} `json:"chainTvls"`
+}
+
+type coinsChartResp struct {
+	Coins map[string]struct {
+		Symbol string `json:"symbol"`
+		Prices []struct {
+			Timestamp int64   `json:"timestamp"`
+			Price     float64 `json:"price"`
+		} `json:"prices"`
+	} `json:"coins"`
+}
+
+func (c *Client) TVLHistory(ctx context.Context, req providers.TVLHistoryRequest) (model.MarketHistorySeries, error) {
+	protocol := strings.ToLower(strings.TrimSpace(req.Protocol))
+	chainSet := strings.TrimSpace(req.Chain.CAIP2) != ""
+
+	var (
+		endpoint string
+		raw      []tvlHistoryPoint
+	)
+	if protocol == "" {
+		endpoint = c.apiBase + "/v2/historicalChainTvl"
+		if chainSet {
+			endpoint += "/" + url.PathEscape(req.Chain.Name)
+		}
+		httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+		if err != nil {
+			return model.MarketHistorySeries{}, clierr.Wrap(clierr.CodeInternal, "build tvl history request", err)
+		}
+		if _, err := c.http.DoJSON(ctx, httpReq, &raw); err != nil {
+			return model.MarketHistorySeries{}, err
+		}
+	} else {
+		endpoint = c.apiBase + "/protocol/" + url.PathEscape(protocol)
+		httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+		if err != nil {
+			return model.MarketHistorySeries{}, clierr.Wrap(clierr.CodeInternal, "build protocol tvl history request", err)
+		}
+		var resp protocolHistoryResp
+		if _, err := c.http.DoJSON(ctx, httpReq, &resp); err != nil {
+			return model.MarketHistorySeries{}, err
+		}
+		raw = resp.TVL
+		if chainSet {
+			found := false
+			for key, item := range resp.ChainTvls {
+				if matchesChain(key, req.Chain) {
+					raw = item.TVL
+					found = true
+					break
+				}
+			}
+			if !found {
+				return model.MarketHistorySeries{}, clierr.New(clierr.CodeUnavailable, fmt.Sprintf("protocol %s has no TVL history on %s", protocol, req.Chain.Name))
+			}
+		}
+	}
+
+	points := make([]model.MarketHistoryPoint, 0, len(raw))
+	for _, item := range raw {
+		ts := time.Unix(int64(item.Date), 0).UTC()
+		if ts.Before(req.StartTime) || ts.After(req.EndTime) {
+			continue
+		}
+		points = append(points, model.MarketHistoryPoint{Timestamp: ts.Format(time.RFC3339), Value: item.value()})
+	}
+	if len(points) == 0 {
+		return model.MarketHistorySeries{}, clierr.New(clierr.CodeUnavailable, "no tvl history points in requested range")
+	}
+	sortMarketHistoryPoints(points)
+
+	series := model.MarketHistorySeries{
+		Metric:    "tvl_usd",
+		Interval:  string(providers.YieldHistoryIntervalDay),
+		Protocol:  protocol,
+		StartTime: req.StartTime.UTC().Format(time.RFC3339),
+		EndTime:   req.EndTime.UTC().Format(time.RFC3339),
+		Points:    points,
+		Provider:  "defillama",
+		SourceURL: endpoint,
+		FetchedAt: c.now().UTC().Format(time.RFC3339),
+	}
+	if chainSet {
+		series.ChainID = req.Chain.CAIP2
+	}
+	return series, nil
+}
+
+func (c *Client) PriceHistory(ctx context.Context, req providers.PriceHistoryRequest) (model.MarketHistorySeries, error) {
+	coin, err := coinsKey(req.Chain, req.Asset)
+	if err != nil {
+		return model.MarketHistorySeries{}, err
+	}
+	period := time.Hour
+	periodArg := "1h"
+	if req.Interval != providers.YieldHistoryIntervalHour {
+		period = 24 * time.Hour
+		periodArg = "1d"
+	}
+	span := int(math.Ceil(req.EndTime.Sub(req.StartTime).Hours()/period.Hours())) + 1
+	if span < 1 {
+		span = 1
+	}
+
+	query := url.Values{}
+	query.Set("start", fmt.Sprintf("%d", req.StartTime.UTC().Unix()))
+	query.Set("span", fmt.Sprintf("%d", span))
+	query.Set("period", periodArg)
+	endpoint := c.coinsAPIURL + "/chart/" + url.PathEscape(coin) + "?" + query.Encode()
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return model.MarketHistorySeries{}, clierr.Wrap(clierr.CodeInternal, "build price history request", err)
+	}
+	var resp coinsChartResp
+	if _, err := c.http.DoJSON(ctx, httpReq, &resp); err != nil {
+		return model.MarketHistorySeries{}, err
+	}
+
+	entry, ok := resp.Coins[coin]
+	if !ok {
+		for key, item := range resp.Coins {
+			if strings.EqualFold(key, coin) {
+				entry, ok = item, true
+				break
+			}
+		}
+	}
+	if !ok {
+		return model.MarketHistorySeries{}, clierr.New(clierr.CodeUnavailable, fmt.Sprintf("no price history for %s", coin))
+	}
+
+	points := make([]model.MarketHistoryPoint, 0, len(entry.Prices))
+	for _, item := range entry.Prices {
+		ts := time.Unix(item.Timestamp, 0).UTC()
+		if ts.Before(req.StartTime) || ts.After(req.EndTime) {
+			continue
+		}
+		points = append(points, model.MarketHistoryPoint{Timestamp: ts.Format(time.RFC3339), Value: item.Price})
+	}
+	if len(points) == 0 {
+		return model.MarketHistorySeries{}, clierr.New(clierr.CodeUnavailable, "no price history points in requested range")
+	}
+	sortMarketHistoryPoints(points)
+
+	symbol := req.Asset.Symbol
+	if symbol == "" {
+		symbol = entry.Symbol
+	}
+	return model.MarketHistorySeries{
+		Metric:    "price_usd",
+		Interval:  string(req.Interval),
+		ChainID:   req.Chain.CAIP2,
+		AssetID:   req.Asset.AssetID,
+		Symbol:    symbol,
+		StartTime: req.StartTime.UTC().Format(time.RFC3339),
+		EndTime:   req.EndTime.UTC().Format(time.RFC3339),
+		Points:    points,
+		Provider:  "defillama",
+		SourceURL: endpoint,
+		FetchedAt: c.now().UTC().Format(time.RFC3339),
+	}, nil
+}
+
+type coinsCurrentResp struct {
+	Coins map[string]struct {
+		Symbol     string  `json:"symbol"`
+		Decimals   int     `json:"decimals"`
+		Price      float64 `json:"price"`
+		Timestamp  int64   `json:"timestamp"`
+		Confidence float64 `json:"confidence"`
+	} `json:"coins"`
+}
+
+// TokenPrices returns current coins API prices for addresses on chain. Only
+// prices observed within the past hour are returned; missing tokens are left
+// out of the map.
+func (c *Client) TokenPrices(ctx context.Context, chain id.Chain, addresses []string) (map[string]model.TokenPrice, error) {
+	keys := make([]string, 0, len(addresses))
+	byKey := make(map[string]string, len(addresses))
+	for _, address := range addresses {
+		key, err := coinsKey(chain, id.Asset{Address: address})
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, url.PathEscape(key))
+		byKey[strings.ToLower(key)] = strings.ToLower(strings.TrimSpace(address))
+	}
+	query := url.Values{}
+	query.Set("searchWidth", "1h")
+	endpoint := c.coinsAPIURL + "/prices/current/" + strings.Join(keys, ",") + "?" + query.Encode()
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, clierr.Wrap(clierr.CodeInternal, "build token price request", err)
+	}
+	var resp coinsCurrentResp
+	if _, err := c.http.DoJSON(ctx, httpReq, &resp); err != nil {
+		return nil, err
+	}
+
+	out := make(map[string]model.TokenPrice, len(resp.Coins))
+	for key, item := range resp.Coins {
+		address, ok := byKey[strings.ToLower(key)]
+		if !ok || item.Price <= 0 {
+			continue
+		}
+		out[address] = model.TokenPrice{
+			ChainID:    chain.CAIP2,
+			Address:    address,
+			Symbol:     item.Symbol,
+			Decimals:   item.Decimals,
+			PriceUSD:   item.Price,
+			Confidence: item.Confidence,
+			Timestamp:  time.Unix(item.Timestamp, 0).UTC().Format(time.RFC3339),
+		}
+	}
+	return out, nil
+}
+
+// coinsKey builds the "<chain>:<address>" identifier used by the DefiLlama
+// coins API.
+func coinsKey(chain id.Chain, asset id.Asset) (string, error) {
+	address := strings.TrimSpace(asset.Address)
+	if address == "" {
+		return "", clierr.New(clierr.CodeUsage, "price history requires an asset with a resolvable token address")
+	}
+	prefix := chain.Slug
+	if mapped, ok := coinsChainPrefixes[prefix]; ok {
+		prefix = mapped
+	}
+	if prefix == "" {
+		return "", clierr.New(clierr.CodeUnsupported, fmt.Sprintf("price history is not supported on %s", chain.CAIP2))
+	}
+	if chain.IsEVM() {
+		address = strings.ToLower(address)
+	}
+	return prefix + ":" + address, nil
+}
+
+func sortMarketHistoryPoints(points []model.MarketHistoryPoint) {
+	sort.Slice(points, func(i, j int) bool {
+		return points[i].Timestamp < points[j].Timestamp
+	})
+}
@@ -0,0 +1,152 @@
+package defillama
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/model"
+)
+
+// exploitKeywords mark a hallmark as a security incident rather than a
+// launch, migration, or market event.
+var exploitKeywords = []string{"hack", "exploit", "attack", "drain", "stolen", "compromise"}
+
+// ProtocolSecurity returns audit and exploit metadata for protocol from the
+// /protocols listing. A protocol matches listings by slug, name, or parent
+// protocol, ignoring case and punctuation, so "aave" covers every Aave
+// version and "etherfi" matches "ether.fi".
+func (c *Client) ProtocolSecurity(ctx context.Context, protocol string) (model.ProtocolSecurity, error) {
+	query := securityKey(protocol)
+	if query == "" {
+		return model.ProtocolSecurity{}, clierr.New(clierr.CodeUsage, "protocol is required")
+	}
+	endpoint := c.apiBase + "/protocols"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return model.ProtocolSecurity{}, clierr.Wrap(clierr.CodeInternal, "build protocols request", err)
+	}
+	var resp []protocolResp
+	if err := c.http.DoSharedJSON(ctx, req, protocolsTTL, &resp); err != nil {
+		return model.ProtocolSecurity{}, err
+	}
+
+	matched := []protocolResp{}
+	for _, p := range resp {
+		if securityKey(p.Slug) == query || securityKey(p.Name) == query || securityKey(strings.TrimPrefix(p.ParentProtocol, "parent#")) == query {
+			matched = append(matched, p)
+		}
+	}
+	if len(matched) == 0 {
+		return model.ProtocolSecurity{}, clierr.New(clierr.CodeUsage, fmt.Sprintf("protocol not found: %s", protocol))
+	}
+	sort.SliceStable(matched, func(i, j int) bool { return matched[i].TVL > matched[j].TVL })
+
+	now := c.now().UTC()
+	out := model.ProtocolSecurity{
+		Protocol:   strings.TrimSpace(protocol),
+		Listings:   make([]string, 0, len(matched)),
+		Category:   matched[0].Category,
+		AuditLinks: []string{},
+		Exploits:   []model.ProtocolIncident{},
+		Provider:   "defillama",
+		SourceURL:  "https://defillama.com/protocol/" + matched[0].Slug,
+		FetchedAt:  now.Format(time.RFC3339),
+	}
+	seenLinks := map[string]bool{}
+	seenIncidents := map[string]bool{}
+	var listedAt int64
+	for _, p := range matched {
+		out.Listings = append(out.Listings, p.Name)
+		out.TVLUSD += p.TVL
+		out.Audits = max(out.Audits, parseAuditCount(p.Audits))
+		for _, link := range p.AuditLinks {
+			if link = strings.TrimSpace(link); link != "" && !seenLinks[link] {
+				seenLinks[link] = true
+				out.AuditLinks = append(out.AuditLinks, link)
+			}
+		}
+		if p.ListedAt > 0 && (listedAt == 0 || p.ListedAt < listedAt) {
+			listedAt = p.ListedAt
+		}
+		for _, incident := range exploitHallmarks(p.Hallmarks) {
+			if key := incident.Date + "|" + incident.Description; !seenIncidents[key] {
+				seenIncidents[key] = true
+				out.Exploits = append(out.Exploits, incident)
+			}
+		}
+	}
+	if listedAt > 0 {
+		listed := time.Unix(listedAt, 0).UTC()
+		out.ListedAt = listed.Format(time.RFC3339)
+		out.ListingAgeDays = int(now.Sub(listed).Hours() / 24)
+	}
+	sort.SliceStable(out.Exploits, func(i, j int) bool { return out.Exploits[i].Date < out.Exploits[j].Date })
+	return out, nil
+}
+
+// securityKey lowercases s and drops everything but letters and digits.
+func securityKey(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// parseAuditCount reads the audits field, which DefiLlama sends as a quoted
+// number.
+func parseAuditCount(raw json.RawMessage) int {
+	value := strings.Trim(strings.TrimSpace(string(raw)), `"`)
+	count, err := strconv.Atoi(value)
+	if err != nil || count < 0 {
+		return 0
+	}
+	return count
+}
+
+// exploitHallmarks returns the hallmarks that describe security incidents.
+// A hallmark is [timestamp, text], or [[start, end], text] for a period.
+func exploitHallmarks(hallmarks [][]json.RawMessage) []model.ProtocolIncident {
+	out := []model.ProtocolIncident{}
+	for _, hallmark := range hallmarks {
+		if len(hallmark) < 2 {
+			continue
+		}
+		var text string
+		if err := json.Unmarshal(hallmark[1], &text); err != nil || !isExploitText(text) {
+			continue
+		}
+		var ts float64
+		if err := json.Unmarshal(hallmark[0], &ts); err != nil {
+			var span []float64
+			if err := json.Unmarshal(hallmark[0], &span); err != nil || len(span) == 0 {
+				continue
+			}
+			ts = span[0]
+		}
+		out = append(out, model.ProtocolIncident{
+			Date:        time.Unix(int64(ts), 0).UTC().Format("2006-01-02"),
+			Description: strings.TrimSpace(text),
+		})
+	}
+	return out
+}
+
+func isExploitText(text string) bool {
+	text = strings.ToLower(text)
+	for _, keyword := range exploitKeywords {
+		if strings.Contains(text, keyword) {
+			return true
+		}
+	}
+	return false
+}
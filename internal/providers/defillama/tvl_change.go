@@ -0,0 +1,170 @@
+package defillama
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/providers"
+)
+
+// tvlHistoryWorkers bounds concurrent history requests for one ranking.
+const tvlHistoryWorkers = 4
+
+// ChainsTVLChange returns the TVL change of each chain over window from the
+// chain's daily TVL history.
+func (c *Client) ChainsTVLChange(ctx context.Context, chains []string, window providers.MetricsWindow) (map[string]float64, error) {
+	return c.fetchTVLChanges(ctx, chains, window, func(ctx context.Context, chain string) ([]tvlHistoryPoint, error) {
+		endpoint := c.apiBase + "/v2/historicalChainTvl/" + url.PathEscape(chain)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+		if err != nil {
+			return nil, clierr.Wrap(clierr.CodeInternal, "build chain tvl history request", err)
+		}
+		var resp []tvlHistoryPoint
+		if _, err := c.http.DoJSON(ctx, req, &resp); err != nil {
+			return nil, err
+		}
+		return resp, nil
+	})
+}
+
+// ProtocolsTVLChange returns the TVL change of each protocol over window.
+// Without a chain it uses the change DefiLlama reports in /protocols; with a
+// chain it reads the protocol's TVL history on that chain.
+func (c *Client) ProtocolsTVLChange(ctx context.Context, chain string, protocols []string, window providers.MetricsWindow) (map[string]float64, error) {
+	endpoint := c.apiBase + "/protocols"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, clierr.Wrap(clierr.CodeInternal, "build protocols request", err)
+	}
+	var resp []protocolResp
+	if err := c.http.DoSharedJSON(ctx, req, protocolsTTL, &resp); err != nil {
+		return nil, err
+	}
+	byName := make(map[string]protocolResp, len(resp))
+	for _, p := range resp {
+		byName[p.Name] = p
+	}
+
+	normChain := strings.ToLower(strings.TrimSpace(chain))
+	if normChain == "" {
+		out := map[string]float64{}
+		for _, name := range protocols {
+			p, ok := byName[name]
+			if !ok {
+				continue
+			}
+			change := p.Change1d
+			if window == providers.MetricsWindow7d {
+				change = p.Change7d
+			}
+			if change != nil {
+				out[name] = *change
+			}
+		}
+		return out, nil
+	}
+
+	return c.fetchTVLChanges(ctx, protocols, window, func(ctx context.Context, name string) ([]tvlHistoryPoint, error) {
+		p, ok := byName[name]
+		if !ok || strings.TrimSpace(p.Slug) == "" {
+			return nil, nil
+		}
+		endpoint := c.apiBase + "/protocol/" + url.PathEscape(p.Slug)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+		if err != nil {
+			return nil, clierr.Wrap(clierr.CodeInternal, "build protocol tvl history request", err)
+		}
+		var history protocolHistoryResp
+		if _, err := c.http.DoJSON(ctx, req, &history); err != nil {
+			return nil, err
+		}
+		for key, item := range history.ChainTvls {
+			if strings.ToLower(strings.TrimSpace(key)) == normChain {
+				return item.TVL, nil
+			}
+		}
+		return nil, nil
+	})
+}
+
+// fetchTVLChanges loads the history of each name with bounded concurrency and
+// returns the change over window for those with enough history. It fails only
+// when every request fails.
+func (c *Client) fetchTVLChanges(ctx context.Context, names []string, window providers.MetricsWindow, fetch func(context.Context, string) ([]tvlHistoryPoint, error)) (map[string]float64, error) {
+	type result struct {
+		points []tvlHistoryPoint
+		err    error
+	}
+	results := make([]result, len(names))
+	sem := make(chan struct{}, tvlHistoryWorkers)
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func(index int, name string) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				results[index] = result{err: ctx.Err()}
+				return
+			}
+			defer func() { <-sem }()
+			points, err := fetch(ctx, name)
+			results[index] = result{points: points, err: err}
+		}(i, name)
+	}
+	wg.Wait()
+
+	span := 24 * time.Hour
+	if window == providers.MetricsWindow7d {
+		span = 7 * 24 * time.Hour
+	}
+	out := map[string]float64{}
+	var firstErr error
+	failed := 0
+	for i, res := range results {
+		if res.err != nil {
+			failed++
+			if firstErr == nil {
+				firstErr = res.err
+			}
+			continue
+		}
+		if change, ok := tvlChangePct(res.points, span); ok {
+			out[names[i]] = change
+		}
+	}
+	if len(names) > 0 && failed == len(names) {
+		return nil, firstErr
+	}
+	return out, nil
+}
+
+// tvlChangePct compares the latest point with the last point at least span
+// older, rounded to hundredths of a percent.
+func tvlChangePct(points []tvlHistoryPoint, span time.Duration) (float64, bool) {
+	if len(points) < 2 {
+		return 0, false
+	}
+	sorted := append([]tvlHistoryPoint(nil), points...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Date < sorted[j].Date })
+	latest := sorted[len(sorted)-1]
+	cutoff := latest.Date - span.Seconds()
+	for i := len(sorted) - 2; i >= 0; i-- {
+		if sorted[i].Date > cutoff {
+			continue
+		}
+		if base := sorted[i].value(); base > 0 {
+			return math.Round((latest.value()/base-1)*10000) / 100, true
+		}
+		return 0, false
+	}
+	return 0, false
+}
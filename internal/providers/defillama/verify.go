@@ -0,0 +1,26 @@
+package defillama
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/providers"
+)
+
+// VerifyKey checks the pro API key against the usage endpoint, which does
+// not consume credits.
+func (c *Client) VerifyKey(ctx context.Context) error {
+	if c.apiKey == "" {
+		return providers.ErrNoAPIKey
+	}
+	endpoint := strings.TrimSuffix(c.bridgeBaseURL, "/") + "/usage/" + c.apiKey
+	hReq, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return clierr.Wrap(clierr.CodeInternal, "build defillama usage request", err)
+	}
+	var resp any
+	_, err = c.http.DoJSON(ctx, hReq, &resp)
+	return err
+}
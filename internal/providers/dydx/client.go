@@ -0,0 +1,118 @@
+package dydx
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ggonzalez94/defi-cli/internal/clock"
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/httpx"
+	"github.com/ggonzalez94/defi-cli/internal/model"
+)
+
+const (
+	defaultBase = "https://indexer.dydx.trade/v4"
+	sourceURL   = "https://dydx.trade/trade"
+	// dYdX v4 settles funding every hour; nextFundingRate is the hourly rate.
+	fundingIntervalHours = 1
+)
+
+type Client struct {
+	http    *httpx.Client
+	baseURL string
+	now     func() time.Time
+}
+
+func New(httpClient *httpx.Client) *Client {
+	return &Client{http: httpClient, baseURL: defaultBase, now: clock.Now}
+}
+
+func (c *Client) Info() model.ProviderInfo {
+	return model.ProviderInfo{
+		Name:        "dydx",
+		Type:        "perps",
+		RequiresKey: false,
+		Capabilities: []string{
+			"perps.markets",
+			"perps.rates",
+		},
+	}
+}
+
+type marketsResponse struct {
+	Markets map[string]market `json:"markets"`
+}
+
+type market struct {
+	Ticker                string `json:"ticker"`
+	Status                string `json:"status"`
+	OraclePrice           string `json:"oraclePrice"`
+	NextFundingRate       string `json:"nextFundingRate"`
+	OpenInterest          string `json:"openInterest"`
+	Volume24H             string `json:"volume24H"`
+	InitialMarginFraction string `json:"initialMarginFraction"`
+}
+
+// PerpMarkets returns active dYdX v4 perpetuals from the public indexer.
+// dYdX marks positions to the oracle price, so mark and index price match.
+func (c *Client) PerpMarkets(ctx context.Context) ([]model.PerpMarket, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(c.baseURL, "/")+"/perpetualMarkets", nil)
+	if err != nil {
+		return nil, clierr.Wrap(clierr.CodeInternal, "build dydx markets request", err)
+	}
+	var resp marketsResponse
+	if _, err := c.http.DoJSON(ctx, req, &resp); err != nil {
+		return nil, err
+	}
+
+	fetchedAt := c.now().UTC().Format(time.RFC3339)
+	out := make([]model.PerpMarket, 0, len(resp.Markets))
+	for _, item := range resp.Markets {
+		if !strings.EqualFold(strings.TrimSpace(item.Status), "ACTIVE") {
+			continue
+		}
+		base, quote, ok := strings.Cut(strings.TrimSpace(item.Ticker), "-")
+		if !ok || base == "" {
+			continue
+		}
+		price := parseFloat(item.OraclePrice)
+		funding := parseFloat(item.NextFundingRate)
+		openInterest := parseFloat(item.OpenInterest)
+		var maxLeverage float64
+		if imf := parseFloat(item.InitialMarginFraction); imf > 0 {
+			maxLeverage = 1 / imf
+		}
+		out = append(out, model.PerpMarket{
+			Provider:             "dydx",
+			Market:               item.Ticker,
+			BaseSymbol:           base,
+			QuoteSymbol:          quote,
+			MarkPrice:            price,
+			IndexPrice:           price,
+			FundingRate:          funding,
+			FundingIntervalHours: fundingIntervalHours,
+			FundingAPR:           funding * 24 / fundingIntervalHours * 365 * 100,
+			OpenInterest:         openInterest,
+			OpenInterestUSD:      openInterest * price,
+			Volume24hUSD:         parseFloat(item.Volume24H),
+			MaxLeverage:          maxLeverage,
+			SourceURL:            sourceURL + "/" + item.Ticker,
+			FetchedAt:            fetchedAt,
+		})
+	}
+	if len(out) == 0 {
+		return nil, clierr.New(clierr.CodeUnavailable, "no active perpetual markets returned by dydx")
+	}
+	return out, nil
+}
+
+func parseFloat(raw string) float64 {
+	v, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+	if err != nil {
+		return 0
+	}
+	return v
+}
@@ -0,0 +1,54 @@
+package dydx
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ggonzalez94/defi-cli/internal/httpx"
+)
+
+const perpetualMarketsFixture = `{"markets":{
+	"BTC-USD":{"ticker":"BTC-USD","status":"ACTIVE","oraclePrice":"60000","nextFundingRate":"0.0000125","openInterest":"250","volume24H":"900000000","initialMarginFraction":"0.05"},
+	"ETH-USD":{"ticker":"ETH-USD","status":"ACTIVE","oraclePrice":"3000","nextFundingRate":"0","openInterest":"1000","volume24H":"300000000","initialMarginFraction":"0.05"},
+	"LUNA-USD":{"ticker":"LUNA-USD","status":"FINAL_SETTLEMENT","oraclePrice":"0.1","nextFundingRate":"0","openInterest":"0","volume24H":"0","initialMarginFraction":"1"}
+}}`
+
+func TestPerpMarketsReturnsActiveMarkets(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/perpetualMarkets" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(perpetualMarketsFixture))
+	}))
+	defer srv.Close()
+
+	c := New(httpx.New(2*time.Second, 0))
+	c.baseURL = srv.URL
+	c.now = func() time.Time { return time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC) }
+
+	markets, err := c.PerpMarkets(context.Background())
+	if err != nil {
+		t.Fatalf("PerpMarkets failed: %v", err)
+	}
+	if len(markets) != 2 {
+		t.Fatalf("expected settled market to be skipped, got %+v", markets)
+	}
+	for _, market := range markets {
+		if market.Market != "BTC-USD" {
+			continue
+		}
+		if market.BaseSymbol != "BTC" || market.QuoteSymbol != "USD" || market.MarkPrice != market.IndexPrice {
+			t.Fatalf("unexpected BTC market: %+v", market)
+		}
+		if math.Abs(market.FundingAPR-10.95) > 1e-9 || market.OpenInterestUSD != 15_000_000 || market.MaxLeverage != 20 {
+			t.Fatalf("unexpected BTC funding/open interest/leverage: %+v", market)
+		}
+		return
+	}
+	t.Fatalf("BTC-USD missing from %+v", markets)
+}
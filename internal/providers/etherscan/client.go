@@ -0,0 +1,395 @@
+package etherscan
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/execution"
+	"github.com/ggonzalez94/defi-cli/internal/httpx"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/labels"
+	"github.com/ggonzalez94/defi-cli/internal/model"
+	"github.com/ggonzalez94/defi-cli/internal/providers"
+	"github.com/ggonzalez94/defi-cli/internal/registry"
+)
+
+// Client reads account activity from an Etherscan-compatible explorer API.
+// The default is Etherscan V2, which serves every chain from one endpoint;
+// other explorers that accept the same module/action queries work through
+// the base URL override.
+type Client struct {
+	http    *httpx.Client
+	baseURL string
+	apiKey  string
+}
+
+func New(httpClient *httpx.Client, apiKey, baseURL string) *Client {
+	baseURL = strings.TrimRight(strings.TrimSpace(baseURL), "/")
+	if baseURL == "" {
+		baseURL = registry.EtherscanV2BaseURL
+	}
+	return &Client{http: httpClient, baseURL: baseURL, apiKey: strings.TrimSpace(apiKey)}
+}
+
+func (c *Client) Info() model.ProviderInfo {
+	return model.ProviderInfo{
+		Name:          "etherscan",
+		Type:          "activity",
+		RequiresKey:   true,
+		KeyEnvVarName: "DEFI_ETHERSCAN_API_KEY",
+		Capabilities: []string{
+			"activity",
+			"contract.source",
+		},
+		CapabilityAuth: []model.ProviderCapabilityAuth{
+			{
+				Capability: "activity",
+				KeyEnvVar:  "DEFI_ETHERSCAN_API_KEY",
+			},
+			{
+				Capability: "contract.source",
+				KeyEnvVar:  "DEFI_ETHERSCAN_API_KEY",
+			},
+		},
+	}
+}
+
+type apiResponse struct {
+	Status  string          `json:"status"`
+	Message string          `json:"message"`
+	Result  json.RawMessage `json:"result"`
+}
+
+type normalTx struct {
+	BlockNumber     string `json:"blockNumber"`
+	TimeStamp       string `json:"timeStamp"`
+	Hash            string `json:"hash"`
+	From            string `json:"from"`
+	To              string `json:"to"`
+	Value           string `json:"value"`
+	GasUsed         string `json:"gasUsed"`
+	GasPrice        string `json:"gasPrice"`
+	IsError         string `json:"isError"`
+	Input           string `json:"input"`
+	MethodID        string `json:"methodId"`
+	FunctionName    string `json:"functionName"`
+	ContractAddress string `json:"contractAddress"`
+}
+
+type tokenTx struct {
+	BlockNumber     string `json:"blockNumber"`
+	TimeStamp       string `json:"timeStamp"`
+	Hash            string `json:"hash"`
+	From            string `json:"from"`
+	To              string `json:"to"`
+	Value           string `json:"value"`
+	ContractAddress string `json:"contractAddress"`
+	TokenSymbol     string `json:"tokenSymbol"`
+	TokenDecimal    string `json:"tokenDecimal"`
+}
+
+// Activity merges the address's latest transactions and ERC-20 transfers by
+// transaction hash. Transfers received in transactions sent by others become
+// entries of their own.
+func (c *Client) Activity(ctx context.Context, req providers.ActivityRequest) ([]model.ActivityEntry, error) {
+	if !req.Chain.IsEVM() {
+		return nil, clierr.New(clierr.CodeUnsupported, "etherscan activity supports only EVM chains")
+	}
+	if c.apiKey == "" {
+		return nil, clierr.New(clierr.CodeAuth, "missing required API key for etherscan (DEFI_ETHERSCAN_API_KEY)")
+	}
+	address := strings.ToLower(strings.TrimSpace(req.Address))
+	var txs []normalTx
+	if err := c.accountQuery(ctx, req.Chain, "txlist", address, req.Limit, &txs); err != nil {
+		return nil, err
+	}
+	var transfers []tokenTx
+	if err := c.accountQuery(ctx, req.Chain, "tokentx", address, req.Limit, &transfers); err != nil {
+		return nil, err
+	}
+
+	entries := map[string]*model.ActivityEntry{}
+	order := []string{}
+	entryFor := func(hash string) (*model.ActivityEntry, bool) {
+		key := strings.ToLower(hash)
+		if entry, ok := entries[key]; ok {
+			return entry, true
+		}
+		entry := &model.ActivityEntry{ChainID: req.Chain.CAIP2, TxHash: hash}
+		entries[key] = entry
+		order = append(order, key)
+		return entry, false
+	}
+	calls := map[string]normalTx{}
+	for _, tx := range txs {
+		entry, _ := entryFor(tx.Hash)
+		fillFromTx(entry, tx, address)
+		calls[strings.ToLower(tx.Hash)] = tx
+	}
+	for _, transfer := range transfers {
+		entry, existed := entryFor(transfer.Hash)
+		if !existed {
+			entry.BlockNumber, _ = strconv.ParseInt(transfer.BlockNumber, 10, 64)
+			entry.Timestamp = unixTimestamp(transfer.TimeStamp)
+			entry.From = strings.ToLower(transfer.From)
+		}
+		entry.Transfers = append(entry.Transfers, normalizeTransfer(req.Chain, transfer, address))
+	}
+
+	out := make([]model.ActivityEntry, 0, len(order))
+	for _, key := range order {
+		entry := entries[key]
+		tx, hasTx := calls[key]
+		classify(entry, tx, hasTx, address)
+		out = append(out, *entry)
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		return out[i].BlockNumber > out[j].BlockNumber
+	})
+	if req.Limit > 0 && len(out) > req.Limit {
+		out = out[:req.Limit]
+	}
+	return out, nil
+}
+
+type sourceCode struct {
+	SourceCode      string `json:"SourceCode"`
+	ABI             string `json:"ABI"`
+	ContractName    string `json:"ContractName"`
+	CompilerVersion string `json:"CompilerVersion"`
+	Proxy           string `json:"Proxy"`
+	Implementation  string `json:"Implementation"`
+}
+
+// ContractSource reports whether address has verified source on the
+// explorer, with its ABI and any proxy implementation the explorer detected.
+func (c *Client) ContractSource(ctx context.Context, chain id.Chain, address string) (providers.ContractSource, error) {
+	if !chain.IsEVM() {
+		return providers.ContractSource{}, clierr.New(clierr.CodeUnsupported, "etherscan contract source supports only EVM chains")
+	}
+	if c.apiKey == "" {
+		return providers.ContractSource{}, clierr.New(clierr.CodeAuth, "missing required API key for etherscan (DEFI_ETHERSCAN_API_KEY)")
+	}
+	address = strings.ToLower(strings.TrimSpace(address))
+	vals := url.Values{}
+	vals.Set("module", "contract")
+	vals.Set("action", "getsourcecode")
+	vals.Set("address", address)
+	var results []sourceCode
+	if err := c.query(ctx, chain, "getsourcecode", vals, &results); err != nil {
+		return providers.ContractSource{}, err
+	}
+	if len(results) == 0 {
+		return providers.ContractSource{}, clierr.New(clierr.CodeUnavailable, "etherscan getsourcecode returned no result")
+	}
+	result := results[0]
+	out := providers.ContractSource{
+		Address:         address,
+		Verified:        strings.TrimSpace(result.SourceCode) != "",
+		ContractName:    strings.TrimSpace(result.ContractName),
+		CompilerVersion: strings.TrimSpace(result.CompilerVersion),
+	}
+	if out.Verified {
+		out.ABI = result.ABI
+	}
+	if result.Proxy == "1" && common.IsHexAddress(result.Implementation) {
+		out.Implementation = strings.ToLower(result.Implementation)
+	}
+	return out, nil
+}
+
+func (c *Client) accountQuery(ctx context.Context, chain id.Chain, action, address string, limit int, out any) error {
+	vals := url.Values{}
+	vals.Set("module", "account")
+	vals.Set("action", action)
+	vals.Set("address", address)
+	vals.Set("page", "1")
+	vals.Set("offset", strconv.Itoa(limit))
+	vals.Set("sort", "desc")
+	return c.query(ctx, chain, action, vals, out)
+}
+
+func (c *Client) query(ctx context.Context, chain id.Chain, action string, vals url.Values, out any) error {
+	vals.Set("chainid", strconv.FormatInt(chain.EVMChainID, 10))
+	vals.Set("apikey", c.apiKey)
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"?"+vals.Encode(), nil)
+	if err != nil {
+		return clierr.Wrap(clierr.CodeInternal, "build etherscan request", err)
+	}
+	var resp apiResponse
+	if _, err := c.http.DoJSON(ctx, httpReq, &resp); err != nil {
+		return err
+	}
+	if resp.Status != "1" {
+		if strings.HasPrefix(strings.ToLower(resp.Message), "no transactions found") {
+			return nil
+		}
+		var detail string
+		_ = json.Unmarshal(resp.Result, &detail)
+		return apiError(action, resp.Message, detail)
+	}
+	if err := json.Unmarshal(resp.Result, out); err != nil {
+		return clierr.Wrap(clierr.CodeUnavailable, "decode etherscan "+action, err)
+	}
+	return nil
+}
+
+// apiError maps an explorer error payload, which arrives with HTTP 200, to
+// a CLI error code.
+func apiError(action, message, detail string) error {
+	text := strings.TrimSpace(firstNonEmpty(detail, message))
+	lower := strings.ToLower(text)
+	switch {
+	case strings.Contains(lower, "api key"):
+		return clierr.New(clierr.CodeAuth, "etherscan rejected the API key: "+text)
+	case strings.Contains(lower, "rate limit"):
+		return clierr.New(clierr.CodeRateLimited, "etherscan rate limit: "+text)
+	case strings.Contains(lower, "chain"):
+		return clierr.New(clierr.CodeUnsupported, "etherscan: "+text)
+	default:
+		return clierr.New(clierr.CodeUnavailable, fmt.Sprintf("etherscan %s failed: %s", action, text))
+	}
+}
+
+func fillFromTx(entry *model.ActivityEntry, tx normalTx, address string) {
+	entry.BlockNumber, _ = strconv.ParseInt(tx.BlockNumber, 10, 64)
+	entry.Timestamp = unixTimestamp(tx.TimeStamp)
+	entry.From = strings.ToLower(tx.From)
+	entry.To = strings.ToLower(tx.To)
+	entry.ToLabel = labels.Label(entry.ChainID, entry.To)
+	entry.Failed = tx.IsError == "1"
+	if value, ok := new(big.Int).SetString(tx.Value, 10); ok && value.Sign() > 0 {
+		baseUnits := value.String()
+		entry.Value = &model.AmountInfo{AmountBaseUnits: baseUnits, AmountDecimal: id.FormatDecimalCompat(baseUnits, 18), Decimals: 18}
+	}
+	if entry.From == address {
+		gasUsed, okUsed := new(big.Int).SetString(tx.GasUsed, 10)
+		gasPrice, okPrice := new(big.Int).SetString(tx.GasPrice, 10)
+		if okUsed && okPrice {
+			entry.GasFeeWei = new(big.Int).Mul(gasUsed, gasPrice).String()
+		}
+	}
+	input := strings.TrimSpace(tx.Input)
+	if input == "" || input == "0x" {
+		return
+	}
+	entry.MethodID = strings.ToLower(firstNonEmpty(tx.MethodID, prefix(input, 10)))
+	if name := strings.TrimSpace(tx.FunctionName); name != "" {
+		entry.Method = strings.TrimSpace(strings.SplitN(name, "(", 2)[0])
+	}
+	preview := execution.PreviewCall(tx.To, input, tx.Value)
+	if preview.Decoded {
+		entry.Method = firstNonEmpty(entry.Method, preview.Method)
+		if preview.Contract != "erc20" {
+			entry.Protocol = preview.Contract
+		}
+	}
+	if entry.Method == "" {
+		entry.Method = entry.MethodID
+	}
+}
+
+func normalizeTransfer(chain id.Chain, transfer tokenTx, address string) model.ActivityTransfer {
+	decimals, _ := strconv.Atoi(transfer.TokenDecimal)
+	baseUnits := strings.TrimSpace(transfer.Value)
+	token := strings.ToLower(transfer.ContractAddress)
+	symbol := transfer.TokenSymbol
+	if known, ok := id.LookupByAddress(chain.CAIP2, token); ok {
+		symbol = known.Symbol
+	}
+	return model.ActivityTransfer{
+		AssetID:   chain.CAIP2 + "/erc20:" + token,
+		Symbol:    symbol,
+		From:      strings.ToLower(transfer.From),
+		To:        strings.ToLower(transfer.To),
+		Direction: direction(strings.ToLower(transfer.From), strings.ToLower(transfer.To), address),
+		Amount: model.AmountInfo{
+			AmountBaseUnits: baseUnits,
+			AmountDecimal:   id.FormatDecimalCompat(baseUnits, decimals),
+			Decimals:        decimals,
+		},
+	}
+}
+
+// classify sets Kind and Direction. A transaction that sent value or tokens
+// out and received tokens back is a swap.
+func classify(entry *model.ActivityEntry, tx normalTx, hasTx bool, address string) {
+	var tokensIn, tokensOut bool
+	for _, transfer := range entry.Transfers {
+		switch transfer.Direction {
+		case "in":
+			tokensIn = true
+		case "out":
+			tokensOut = true
+		}
+	}
+	if !hasTx {
+		entry.Kind = "transfer"
+		entry.Direction = "in"
+		if tokensOut && !tokensIn {
+			entry.Direction = "out"
+		}
+		return
+	}
+	entry.Direction = direction(entry.From, entry.To, address)
+	sentValue := entry.Value != nil && entry.From == address
+	switch {
+	case entry.To == "" && strings.TrimSpace(tx.ContractAddress) != "":
+		entry.Kind = "contract_creation"
+	case tokensIn && (tokensOut || sentValue):
+		entry.Kind = "swap"
+	case entry.Method == "approve" || entry.Method == "increaseAllowance":
+		entry.Kind = "approval"
+	case entry.MethodID == "":
+		entry.Kind = "transfer"
+	case (entry.Method == "transfer" || entry.Method == "transferFrom") && len(entry.Transfers) <= 1:
+		entry.Kind = "transfer"
+	default:
+		entry.Kind = "contract_call"
+	}
+}
+
+func direction(from, to, address string) string {
+	switch {
+	case from == address && to == address:
+		return "self"
+	case from == address:
+		return "out"
+	default:
+		return "in"
+	}
+}
+
+func unixTimestamp(raw string) string {
+	seconds, err := strconv.ParseInt(strings.TrimSpace(raw), 10, 64)
+	if err != nil {
+		return ""
+	}
+	return time.Unix(seconds, 0).UTC().Format(time.RFC3339)
+}
+
+func prefix(value string, n int) string {
+	if len(value) < n {
+		return value
+	}
+	return value[:n]
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if strings.TrimSpace(value) != "" {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,139 @@
+package etherscan
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/httpx"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/providers"
+)
+
+const (
+	testAccount = "0x00000000000000000000000000000000000000aa"
+	testRouter  = "0x00000000000000000000000000000000000000c0"
+	testUSDC    = "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"
+	testWETH    = "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2"
+)
+
+func TestActivityMergesTransactionsAndTransfers(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("chainid") != "1" || q.Get("apikey") != "test-key" || q.Get("offset") != "10" {
+			t.Fatalf("unexpected query %s", r.URL.RawQuery)
+		}
+		switch q.Get("action") {
+		case "txlist":
+			_, _ = w.Write([]byte(`{"status":"1","message":"OK","result":[
+				{"blockNumber":"102","timeStamp":"1700000200","hash":"0xswap","from":"` + testAccount + `","to":"` + testRouter + `","value":"0","gasUsed":"100000","gasPrice":"2000000000","isError":"0","input":"0x12345678","methodId":"0x12345678","functionName":"swapExactTokensForTokens(uint256 amountIn, uint256 amountOutMin, address[] path, address to, uint256 deadline)","contractAddress":""},
+				{"blockNumber":"101","timeStamp":"1700000100","hash":"0xapprove","from":"` + testAccount + `","to":"` + testUSDC + `","value":"0","gasUsed":"46000","gasPrice":"2000000000","isError":"0","input":"0x095ea7b3000000000000000000000000` + testRouter[2:] + `0000000000000000000000000000000000000000000000000000000000000001","methodId":"0x095ea7b3","functionName":"","contractAddress":""},
+				{"blockNumber":"100","timeStamp":"1700000000","hash":"0xsend","from":"` + testAccount + `","to":"` + testRouter + `","value":"1000000000000000000","gasUsed":"21000","gasPrice":"2000000000","isError":"0","input":"0x","methodId":"0x","functionName":"","contractAddress":""}
+			]}`))
+		case "tokentx":
+			_, _ = w.Write([]byte(`{"status":"1","message":"OK","result":[
+				{"blockNumber":"103","timeStamp":"1700000300","hash":"0xairdrop","from":"` + testRouter + `","to":"` + testAccount + `","value":"5000000","contractAddress":"` + testUSDC + `","tokenSymbol":"USDC","tokenDecimal":"6"},
+				{"blockNumber":"102","timeStamp":"1700000200","hash":"0xswap","from":"` + testAccount + `","to":"` + testRouter + `","value":"2000000","contractAddress":"` + testUSDC + `","tokenSymbol":"USDC","tokenDecimal":"6"},
+				{"blockNumber":"102","timeStamp":"1700000200","hash":"0xswap","from":"` + testRouter + `","to":"` + testAccount + `","value":"1000000000000000","contractAddress":"` + testWETH + `","tokenSymbol":"WETH","tokenDecimal":"18"}
+			]}`))
+		default:
+			t.Fatalf("unexpected action %s", q.Get("action"))
+		}
+	}))
+	defer srv.Close()
+
+	chain, _ := id.ParseChain("ethereum")
+	c := New(httpx.New(5*time.Second, 0), "test-key", srv.URL)
+	entries, err := c.Activity(context.Background(), providers.ActivityRequest{Chain: chain, Address: testAccount, Limit: 10})
+	if err != nil {
+		t.Fatalf("Activity failed: %v", err)
+	}
+	if len(entries) != 4 {
+		t.Fatalf("expected 4 entries, got %+v", entries)
+	}
+	want := []struct{ hash, kind, direction, method string }{
+		{"0xairdrop", "transfer", "in", ""},
+		{"0xswap", "swap", "out", "swapExactTokensForTokens"},
+		{"0xapprove", "approval", "out", "approve"},
+		{"0xsend", "transfer", "out", ""},
+	}
+	for i, w := range want {
+		got := entries[i]
+		if got.TxHash != w.hash || got.Kind != w.kind || got.Direction != w.direction || got.Method != w.method {
+			t.Fatalf("entry %d: expected %+v, got %+v", i, w, got)
+		}
+	}
+	if swap := entries[1]; len(swap.Transfers) != 2 || swap.GasFeeWei != "200000000000000" || swap.Timestamp != "2023-11-14T22:16:40Z" {
+		t.Fatalf("unexpected swap entry: %+v", swap)
+	}
+	if entries[3].Value == nil || entries[3].Value.AmountDecimal != "1" {
+		t.Fatalf("expected native value on the send, got %+v", entries[3].Value)
+	}
+}
+
+func TestActivityEmptyResultAndErrors(t *testing.T) {
+	invalidKey := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if invalidKey {
+			_, _ = w.Write([]byte(`{"status":"0","message":"NOTOK","result":"Invalid API Key (#err2)|chainid"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"status":"0","message":"No transactions found","result":[]}`))
+	}))
+	defer srv.Close()
+
+	chain, _ := id.ParseChain("base")
+	c := New(httpx.New(5*time.Second, 0), "test-key", srv.URL)
+	entries, err := c.Activity(context.Background(), providers.ActivityRequest{Chain: chain, Address: testAccount, Limit: 5})
+	if err != nil || len(entries) != 0 {
+		t.Fatalf("expected no entries, got %+v err=%v", entries, err)
+	}
+
+	invalidKey = true
+	_, err = c.Activity(context.Background(), providers.ActivityRequest{Chain: chain, Address: testAccount, Limit: 5})
+	if cErr, ok := clierr.As(err); !ok || cErr.Code != clierr.CodeAuth {
+		t.Fatalf("expected auth error, got %v", err)
+	}
+
+	_, err = New(httpx.New(time.Second, 0), "", srv.URL).Activity(context.Background(), providers.ActivityRequest{Chain: chain, Address: testAccount, Limit: 5})
+	if cErr, ok := clierr.As(err); !ok || cErr.Code != clierr.CodeAuth {
+		t.Fatalf("expected missing key error, got %v", err)
+	}
+}
+
+func TestContractSourceReportsVerificationAndProxy(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("module") != "contract" || q.Get("action") != "getsourcecode" || q.Get("chainid") != "1" {
+			t.Fatalf("unexpected query %s", r.URL.RawQuery)
+		}
+		switch q.Get("address") {
+		case testUSDC:
+			_, _ = w.Write([]byte(`{"status":"1","message":"OK","result":[{"SourceCode":"pragma solidity ^0.4.24;","ABI":"[]","ContractName":"FiatTokenProxy","CompilerVersion":"v0.4.24+commit.e67f0147","Proxy":"1","Implementation":"0x43506849D7C04F9138D1A2050bbF3A0c054402dd"}]}`))
+		default:
+			_, _ = w.Write([]byte(`{"status":"1","message":"OK","result":[{"SourceCode":"","ABI":"Contract source code not verified","ContractName":"","CompilerVersion":"","Proxy":"0","Implementation":""}]}`))
+		}
+	}))
+	defer srv.Close()
+
+	chain, _ := id.ParseChain("ethereum")
+	c := New(httpx.New(5*time.Second, 0), "test-key", srv.URL)
+	source, err := c.ContractSource(context.Background(), chain, testUSDC)
+	if err != nil {
+		t.Fatalf("ContractSource failed: %v", err)
+	}
+	if !source.Verified || source.ContractName != "FiatTokenProxy" || source.ABI != "[]" || source.Implementation != "0x43506849d7c04f9138d1a2050bbf3a0c054402dd" {
+		t.Fatalf("unexpected verified source %+v", source)
+	}
+
+	source, err = c.ContractSource(context.Background(), chain, testWETH)
+	if err != nil {
+		t.Fatalf("ContractSource failed: %v", err)
+	}
+	if source.Verified || source.ABI != "" {
+		t.Fatalf("expected an unverified contract without ABI, got %+v", source)
+	}
+}
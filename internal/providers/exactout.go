@@ -0,0 +1,91 @@
+package providers
+
+import (
+	"context"
+	"math/big"
+
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+)
+
+// ExactInputQuoter returns the output an exact-input quote gives for amountIn.
+type ExactInputQuoter func(ctx context.Context, amountIn *big.Int) (*big.Int, error)
+
+const (
+	// exactOutputMaxQuotes bounds the upstream quotes one emulated
+	// exact-output quote may spend.
+	exactOutputMaxQuotes = 12
+	// exactOutputToleranceBps is the input precision at which the search
+	// stops: the returned input is at most this much above the minimum.
+	exactOutputToleranceBps = 5
+)
+
+// SolveExactOutput emulates an exact-output quote with exact-input quotes
+// for providers whose API lacks one. It returns the smallest input found
+// whose quoted output covers amountOut, and that output. seedIn is any
+// positive input amount (one whole unit of the input token works) used to
+// estimate the price before bisecting.
+func SolveExactOutput(ctx context.Context, quote ExactInputQuoter, amountOut, seedIn *big.Int) (*big.Int, *big.Int, error) {
+	if amountOut == nil || amountOut.Sign() <= 0 {
+		return nil, nil, clierr.New(clierr.CodeUsage, "exact-output amount must be positive")
+	}
+	if seedIn == nil || seedIn.Sign() <= 0 {
+		seedIn = big.NewInt(1)
+	}
+	quotes := 0
+	try := func(amountIn *big.Int) (*big.Int, error) {
+		quotes++
+		return quote(ctx, amountIn)
+	}
+
+	seedOut, err := try(seedIn)
+	if err != nil {
+		return nil, nil, err
+	}
+	if seedOut == nil || seedOut.Sign() <= 0 {
+		return nil, nil, clierr.New(clierr.CodeUnavailable, "exact-output emulation: probe quote returned no output")
+	}
+	// Linear estimate of the input, padded by 1% for price impact.
+	estimate := new(big.Int).Mul(amountOut, seedIn)
+	estimate.Quo(estimate, seedOut)
+	estimate.Mul(estimate, big.NewInt(101))
+	estimate.Quo(estimate, big.NewInt(100))
+	if estimate.Sign() <= 0 {
+		estimate.SetInt64(1)
+	}
+
+	lo := new(big.Int)
+	hi := estimate
+	hiOut, err := try(hi)
+	if err != nil {
+		return nil, nil, err
+	}
+	for hiOut.Cmp(amountOut) < 0 {
+		if quotes >= exactOutputMaxQuotes {
+			return nil, nil, clierr.New(clierr.CodeUnavailable, "exact-output emulation: no input covers the requested output")
+		}
+		lo.Set(hi)
+		hi = new(big.Int).Mul(hi, big.NewInt(2))
+		if hiOut, err = try(hi); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	for quotes < exactOutputMaxQuotes {
+		gap := new(big.Int).Sub(hi, lo)
+		if gap.Cmp(big.NewInt(1)) <= 0 || new(big.Int).Mul(gap, big.NewInt(10_000)).Cmp(new(big.Int).Mul(hi, big.NewInt(exactOutputToleranceBps))) <= 0 {
+			break
+		}
+		mid := new(big.Int).Add(lo, hi)
+		mid.Rsh(mid, 1)
+		out, err := try(mid)
+		if err != nil {
+			return nil, nil, err
+		}
+		if out.Cmp(amountOut) >= 0 {
+			hi, hiOut = mid, out
+		} else {
+			lo = mid
+		}
+	}
+	return hi, hiOut, nil
+}
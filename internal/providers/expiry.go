@@ -0,0 +1,44 @@
+package providers
+
+import (
+	"time"
+
+	"github.com/ggonzalez94/defi-cli/internal/model"
+)
+
+// Quote firmness values. A firm quote is a price the provider commits to
+// until it expires, such as a signed RFQ or CoW order quote; indicative
+// quotes are a snapshot of liquidity that may move at any time.
+const (
+	QuoteFirmnessIndicative = "indicative"
+	QuoteFirmnessFirm       = "firm"
+)
+
+// DefaultQuoteValidity is how long an indicative quote is treated as current
+// after it was fetched. swap submit re-quotes plans older than this.
+const DefaultQuoteValidity = 30 * time.Second
+
+// ApplySwapQuoteExpiry marks quote indicative and sets its expiry from
+// FetchedAt, unless the provider already reported them.
+func ApplySwapQuoteExpiry(quote *model.SwapQuote) {
+	quote.ExpiresAt, quote.Firmness = quoteExpiry(quote.FetchedAt, quote.ExpiresAt, quote.Firmness)
+}
+
+// ApplyBridgeQuoteExpiry is ApplySwapQuoteExpiry for bridge quotes.
+func ApplyBridgeQuoteExpiry(quote *model.BridgeQuote) {
+	quote.ExpiresAt, quote.Firmness = quoteExpiry(quote.FetchedAt, quote.ExpiresAt, quote.Firmness)
+}
+
+func quoteExpiry(fetchedAt, expiresAt, firmness string) (string, string) {
+	if firmness == "" {
+		firmness = QuoteFirmnessIndicative
+	}
+	if expiresAt != "" {
+		return expiresAt, firmness
+	}
+	fetched, err := time.Parse(time.RFC3339, fetchedAt)
+	if err != nil {
+		return "", firmness
+	}
+	return fetched.Add(DefaultQuoteValidity).UTC().Format(time.RFC3339), firmness
+}
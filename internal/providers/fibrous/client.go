@@ -3,12 +3,15 @@ package fibrous
 import (
 	"context"
 	"fmt"
+	"maps"
 	"net/http"
 	"net/url"
+	"slices"
 	"sort"
 	"strings"
 	"time"
 
+	"github.com/ggonzalez94/defi-cli/internal/clock"
 	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
 	"github.com/ggonzalez94/defi-cli/internal/httpx"
 	"github.com/ggonzalez94/defi-cli/internal/id"
@@ -35,7 +38,7 @@ func New(httpClient *httpx.Client) *Client {
 	return &Client{
 		http:    httpClient,
 		baseURL: defaultBase,
-		now:     time.Now,
+		now:     clock.Now,
 	}
 }
 
@@ -45,6 +48,9 @@ func (c *Client) Info() model.ProviderInfo {
 		Type:         "swap",
 		RequiresKey:  false,
 		Capabilities: []string{"swap.quote"},
+		Support: []model.CapabilitySupport{
+			{Capability: "swap.quote", Chains: providers.EVMChains(slices.Sorted(maps.Keys(chainSlugs))), Features: []string{providers.FeatureExactInput}},
+		},
 	}
 }
 
@@ -101,7 +107,7 @@ func (c *Client) QuoteSwap(ctx context.Context, req providers.SwapQuoteRequest)
 		estimatedGasUSD = *resp.EstimatedGasUsedInUsd
 	}
 
-	return model.SwapQuote{
+	quote := model.SwapQuote{
 		Provider:    "fibrous",
 		ChainID:     req.Chain.CAIP2,
 		FromAssetID: req.FromAsset.AssetID,
@@ -122,5 +128,8 @@ func (c *Client) QuoteSwap(ctx context.Context, req providers.SwapQuoteRequest)
 		Route:           "fibrous",
 		SourceURL:       "https://fibrous.finance",
 		FetchedAt:       c.now().UTC().Format(time.RFC3339),
-	}, nil
+	}
+	// The route endpoint takes no slippage; min_out is derived from it.
+	providers.ApplySwapSlippage(&quote, providers.SwapSlippagePct(req))
+	return quote, nil
 }
@@ -0,0 +1,234 @@
+package governance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ggonzalez94/defi-cli/internal/clock"
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/httpx"
+	"github.com/ggonzalez94/defi-cli/internal/model"
+	"github.com/ggonzalez94/defi-cli/internal/providers"
+)
+
+const (
+	defaultSnapshotURL = "https://hub.snapshot.org/graphql"
+
+	SourceSnapshot = "snapshot"
+	SourceOnchain  = "onchain"
+
+	StatusActive  = "active"
+	StatusPending = "pending"
+	StatusClosed  = "closed"
+)
+
+// protocolSource is where a protocol votes: its Snapshot space and, when
+// binding votes happen on-chain through GovernorBravo, the governor's chain.
+type protocolSource struct {
+	Space           string
+	GovernorChainID int64
+	TallySlug       string
+}
+
+var protocolSources = map[string]protocolSource{
+	"aave":    {Space: "aavedao.eth"},
+	"uniswap": {Space: "uniswapgovernance.eth", GovernorChainID: 1, TallySlug: "uniswap"},
+	"morpho":  {Space: "morpho.eth"},
+}
+
+// Protocols returns the protocols with governance sources, sorted.
+func Protocols() []string {
+	out := make([]string, 0, len(protocolSources))
+	for name := range protocolSources {
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// HasGovernor reports whether protocol votes on-chain through a governor.
+func HasGovernor(protocol string) bool {
+	return protocolSources[protocol].GovernorChainID != 0
+}
+
+type Client struct {
+	http        *httpx.Client
+	snapshotURL string
+	rpcOverride string
+	now         func() time.Time
+}
+
+func New(httpClient *httpx.Client) *Client {
+	return &Client{http: httpClient, snapshotURL: defaultSnapshotURL, now: clock.Now}
+}
+
+// SetRPCOverride sets the RPC URL used for on-chain governor reads.
+func (c *Client) SetRPCOverride(url string) { c.rpcOverride = url }
+
+func (c *Client) Info() model.ProviderInfo {
+	return model.ProviderInfo{
+		Name:         "governance",
+		Type:         "governance",
+		RequiresKey:  false,
+		Capabilities: []string{"governance.list"},
+	}
+}
+
+// GovernanceProposals returns the newest proposals of req.Protocol from
+// Snapshot and its on-chain governor, newest first.
+func (c *Client) GovernanceProposals(ctx context.Context, req providers.GovernanceRequest) ([]model.GovernanceProposal, error) {
+	protocol := strings.ToLower(strings.TrimSpace(req.Protocol))
+	source, ok := protocolSources[protocol]
+	if !ok {
+		return nil, clierr.New(clierr.CodeUnsupported, fmt.Sprintf("no governance source for protocol %s; supported: %s", req.Protocol, strings.Join(Protocols(), ",")))
+	}
+	if req.Source == SourceOnchain && source.GovernorChainID == 0 {
+		return nil, clierr.New(clierr.CodeUnsupported, fmt.Sprintf("%s has no on-chain governor source; use --source snapshot", protocol))
+	}
+	limit := req.Limit
+	if limit <= 0 {
+		limit = 10
+	}
+
+	out := []model.GovernanceProposal{}
+	if req.Source == "" || req.Source == SourceSnapshot {
+		items, err := c.snapshotProposals(ctx, protocol, source.Space, req.Status, limit)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, items...)
+	}
+	if (req.Source == "" || req.Source == SourceOnchain) && source.GovernorChainID != 0 {
+		items, err := c.governorProposals(ctx, protocol, source, req.Status, limit)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, items...)
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].StartsAt > out[j].StartsAt })
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+const snapshotProposalsQuery = `query Proposals($first: Int!, $where: ProposalWhere) {
+  proposals(first: $first, where: $where, orderBy: "created", orderDirection: desc) {
+    id
+    title
+    state
+    start
+    end
+    quorum
+    choices
+    scores
+    scores_total
+  }
+}`
+
+type snapshotResponse struct {
+	Data struct {
+		Proposals []snapshotProposal `json:"proposals"`
+	} `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+type snapshotProposal struct {
+	ID          string    `json:"id"`
+	Title       string    `json:"title"`
+	State       string    `json:"state"`
+	Start       int64     `json:"start"`
+	End         int64     `json:"end"`
+	Quorum      float64   `json:"quorum"`
+	Choices     []string  `json:"choices"`
+	Scores      []float64 `json:"scores"`
+	ScoresTotal float64   `json:"scores_total"`
+}
+
+func (c *Client) snapshotProposals(ctx context.Context, protocol, space, status string, limit int) ([]model.GovernanceProposal, error) {
+	where := map[string]any{"space": space}
+	if status != "" {
+		where["state"] = status
+	}
+	body, err := json.Marshal(map[string]any{
+		"query":     snapshotProposalsQuery,
+		"variables": map[string]any{"first": limit, "where": where},
+	})
+	if err != nil {
+		return nil, clierr.Wrap(clierr.CodeInternal, "marshal snapshot proposals query", err)
+	}
+	var resp snapshotResponse
+	if _, err := httpx.DoBodyJSON(ctx, c.http, http.MethodPost, c.snapshotURL, body, nil, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Errors) > 0 {
+		return nil, clierr.New(clierr.CodeUnavailable, fmt.Sprintf("snapshot graphql error: %s", resp.Errors[0].Message))
+	}
+
+	fetchedAt := c.now().UTC().Format(time.RFC3339)
+	out := make([]model.GovernanceProposal, 0, len(resp.Data.Proposals))
+	for _, item := range resp.Data.Proposals {
+		choices := make([]model.GovernanceChoice, 0, len(item.Choices))
+		for i, choice := range item.Choices {
+			var votes float64
+			if i < len(item.Scores) {
+				votes = item.Scores[i]
+			}
+			choices = append(choices, model.GovernanceChoice{Choice: choice, Votes: votes})
+		}
+		out = append(out, model.GovernanceProposal{
+			Protocol:          protocol,
+			Source:            SourceSnapshot,
+			ProposalID:        item.ID,
+			Title:             strings.TrimSpace(item.Title),
+			Status:            item.State,
+			Space:             space,
+			StartsAt:          unixTime(item.Start),
+			EndsAt:            unixTime(item.End),
+			Quorum:            item.Quorum,
+			VotesTotal:        item.ScoresTotal,
+			QuorumProgressPct: quorumProgress(item.ScoresTotal, item.Quorum),
+			Choices:           choices,
+			URL:               fmt.Sprintf("https://snapshot.org/#/%s/proposal/%s", space, item.ID),
+			Provider:          "governance",
+			FetchedAt:         fetchedAt,
+		})
+	}
+	return out, nil
+}
+
+// MatchesStatus reports whether a proposal status passes a status filter.
+// On-chain outcomes such as executed or defeated count as closed.
+func MatchesStatus(status, filter string) bool {
+	switch filter {
+	case "":
+		return true
+	case StatusClosed:
+		return status != StatusActive && status != StatusPending
+	default:
+		return status == filter
+	}
+}
+
+func quorumProgress(votes, quorum float64) *float64 {
+	if quorum <= 0 {
+		return nil
+	}
+	pct := math.Round(votes/quorum*10000) / 100
+	return &pct
+}
+
+func unixTime(ts int64) string {
+	if ts <= 0 {
+		return ""
+	}
+	return time.Unix(ts, 0).UTC().Format(time.RFC3339)
+}
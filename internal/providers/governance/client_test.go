@@ -0,0 +1,141 @@
+package governance
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ggonzalez94/defi-cli/internal/httpx"
+	"github.com/ggonzalez94/defi-cli/internal/providers"
+)
+
+func TestSnapshotProposals(t *testing.T) {
+	var gotBody string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		gotBody = string(body)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data":{"proposals":[
+			{"id":"0xabc","title":" Raise USDC reserve factor ","state":"active","start":1767225600,"end":1767830400,"quorum":320000,"choices":["YAE","NAY"],"scores":[200000,40000],"scores_total":240000}
+		]}}`))
+	}))
+	defer srv.Close()
+
+	client := New(httpx.New(2*time.Second, 0))
+	client.snapshotURL = srv.URL
+	items, err := client.GovernanceProposals(context.Background(), providers.GovernanceRequest{Protocol: "aave", Status: StatusActive, Limit: 5})
+	if err != nil {
+		t.Fatalf("GovernanceProposals failed: %v", err)
+	}
+	if !strings.Contains(gotBody, `"space":"aavedao.eth"`) || !strings.Contains(gotBody, `"state":"active"`) {
+		t.Fatalf("unexpected snapshot query: %s", gotBody)
+	}
+	if len(items) != 1 {
+		t.Fatalf("expected one proposal, got %+v", items)
+	}
+	item := items[0]
+	if item.Source != SourceSnapshot || item.Title != "Raise USDC reserve factor" || item.EndsAt != "2026-01-08T00:00:00Z" {
+		t.Fatalf("unexpected proposal: %+v", item)
+	}
+	if item.QuorumProgressPct == nil || *item.QuorumProgressPct != 75 || len(item.Choices) != 2 || item.Choices[1].Votes != 40000 {
+		t.Fatalf("unexpected votes: %+v", item)
+	}
+	if item.URL != "https://snapshot.org/#/aavedao.eth/proposal/0xabc" {
+		t.Fatalf("unexpected url: %s", item.URL)
+	}
+
+	if _, err := client.GovernanceProposals(context.Background(), providers.GovernanceRequest{Protocol: "aave", Source: SourceOnchain}); err == nil {
+		t.Fatal("expected aave on-chain source to be unsupported")
+	}
+	if _, err := client.GovernanceProposals(context.Background(), providers.GovernanceRequest{Protocol: "curve"}); err == nil {
+		t.Fatal("expected unknown protocol to fail")
+	}
+}
+
+func TestGovernorProposalsReadsBravo(t *testing.T) {
+	units := func(n int64) *big.Int { return new(big.Int).Mul(big.NewInt(n), big.NewInt(1e18)) }
+	pack := func(method string, values ...any) string {
+		out, err := governorABI.Methods[method].Outputs.Pack(values...)
+		if err != nil {
+			t.Fatalf("pack %s: %v", method, err)
+		}
+		return "0x" + hex.EncodeToString(out)
+	}
+	states := map[int64]uint8{3: 1, 2: 7, 1: 3}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+			Params []json.RawMessage
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		result := "0x"
+		switch req.Method {
+		case "eth_blockNumber":
+			result = "0x3e8" // 1000
+		case "eth_call":
+			var call struct {
+				Data  string `json:"data"`
+				Input string `json:"input"`
+			}
+			_ = json.Unmarshal(req.Params[0], &call)
+			data := call.Input
+			if data == "" {
+				data = call.Data
+			}
+			raw, _ := hex.DecodeString(strings.TrimPrefix(data, "0x"))
+			method, _ := governorABI.MethodById(raw[:4])
+			args, _ := method.Inputs.Unpack(raw[4:])
+			switch method.Name {
+			case "proposalCount":
+				result = pack("proposalCount", big.NewInt(3))
+			case "quorumVotes":
+				result = pack("quorumVotes", units(40_000_000))
+			case "state":
+				result = pack("state", states[args[0].(*big.Int).Int64()])
+			case "proposals":
+				id := args[0].(*big.Int)
+				result = pack("proposals", id, common.Address{}, big.NewInt(0), big.NewInt(900), big.NewInt(1100), units(30_000_000), units(1_000_000), units(0), false, id.Int64() == 2)
+			}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"jsonrpc": "2.0", "id": req.ID, "result": result})
+	}))
+	defer srv.Close()
+
+	client := New(httpx.New(2*time.Second, 0))
+	client.SetRPCOverride(srv.URL)
+	client.now = func() time.Time { return time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC) }
+	items, err := client.GovernanceProposals(context.Background(), providers.GovernanceRequest{Protocol: "uniswap", Source: SourceOnchain, Status: StatusActive})
+	if err != nil {
+		t.Fatalf("GovernanceProposals failed: %v", err)
+	}
+	if len(items) != 1 {
+		t.Fatalf("expected only the active proposal, got %+v", items)
+	}
+	item := items[0]
+	if item.ProposalID != "3" || item.Status != "active" || item.ChainID != "eip155:1" {
+		t.Fatalf("unexpected proposal: %+v", item)
+	}
+	if item.EndsAt != "2026-01-01T00:20:00Z" || item.StartsAt != "2025-12-31T23:40:00Z" {
+		t.Fatalf("expected block-estimated times, got %s - %s", item.StartsAt, item.EndsAt)
+	}
+	if item.Quorum != 40_000_000 || item.VotesTotal != 31_000_000 || item.QuorumProgressPct == nil || *item.QuorumProgressPct != 75 {
+		t.Fatalf("unexpected quorum progress: %+v", item)
+	}
+
+	closed, err := client.GovernanceProposals(context.Background(), providers.GovernanceRequest{Protocol: "uniswap", Source: SourceOnchain, Status: StatusClosed})
+	if err != nil {
+		t.Fatalf("GovernanceProposals closed failed: %v", err)
+	}
+	if len(closed) != 2 || closed[0].Status != "executed" || closed[1].Status != "defeated" {
+		t.Fatalf("expected executed and defeated proposals, got %+v", closed)
+	}
+}
@@ -0,0 +1,157 @@
+package governance
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/model"
+	"github.com/ggonzalez94/defi-cli/internal/registry"
+	"github.com/ggonzalez94/defi-cli/internal/rpcx"
+)
+
+// ethereumBlockTime estimates when a future block lands.
+const ethereumBlockTime = 12 * time.Second
+
+var governorABI = mustABI(registry.GovernorBravoABI)
+
+// governorStates names GovernorBravo.state values.
+var governorStates = []string{"pending", "active", "canceled", "defeated", "succeeded", "queued", "expired", "executed"}
+
+// governorProposals reads the newest proposals from the protocol's
+// GovernorBravo. Titles live only in the creation event, so each proposal is
+// titled by its id. Block-based start and end times are estimated from the
+// current block at 12s per block.
+func (c *Client) governorProposals(ctx context.Context, protocol string, source protocolSource, status string, limit int) ([]model.GovernanceProposal, error) {
+	governorAddr, ok := registry.UniswapGovernor(source.GovernorChainID)
+	if !ok {
+		return nil, clierr.New(clierr.CodeUnsupported, fmt.Sprintf("%s has no on-chain governor", protocol))
+	}
+	rpcURL, err := registry.ResolveRPCURL(c.rpcOverride, source.GovernorChainID)
+	if err != nil {
+		return nil, clierr.Wrap(clierr.CodeUnsupported, "resolve rpc url", err)
+	}
+	client, err := rpcx.DialContext(ctx, rpcURL)
+	if err != nil {
+		return nil, clierr.Wrap(clierr.CodeUnavailable, "connect rpc", err)
+	}
+	defer client.Close()
+	governor := common.HexToAddress(governorAddr)
+
+	countOut, err := callGovernor(ctx, client, governor, "proposalCount")
+	if err != nil {
+		return nil, err
+	}
+	quorumOut, err := callGovernor(ctx, client, governor, "quorumVotes")
+	if err != nil {
+		return nil, err
+	}
+	currentBlock, err := client.BlockNumber(ctx)
+	if err != nil {
+		return nil, clierr.Wrap(clierr.CodeUnavailable, "read block number", err)
+	}
+	now := c.now().UTC()
+	quorum := tokenUnits(quorumOut[0].(*big.Int))
+	count := countOut[0].(*big.Int).Int64()
+
+	out := []model.GovernanceProposal{}
+	// Proposals are numbered in creation order; scan back from the newest.
+	// Active and pending proposals are always among the newest few.
+	scanned := 0
+	for id := count; id > 0 && len(out) < limit && scanned < limit*3; id-- {
+		scanned++
+		proposalID := big.NewInt(id)
+		stateOut, err := callGovernor(ctx, client, governor, "state", proposalID)
+		if err != nil {
+			return nil, err
+		}
+		state := governorState(stateOut[0].(uint8))
+		if !MatchesStatus(state, status) {
+			continue
+		}
+		proposalOut, err := callGovernor(ctx, client, governor, "proposals", proposalID)
+		if err != nil {
+			return nil, err
+		}
+		startBlock := proposalOut[3].(*big.Int)
+		endBlock := proposalOut[4].(*big.Int)
+		forVotes := tokenUnits(proposalOut[5].(*big.Int))
+		againstVotes := tokenUnits(proposalOut[6].(*big.Int))
+		abstainVotes := tokenUnits(proposalOut[7].(*big.Int))
+		out = append(out, model.GovernanceProposal{
+			Protocol:          protocol,
+			Source:            SourceOnchain,
+			ProposalID:        proposalID.String(),
+			Title:             fmt.Sprintf("%s proposal %d", strings.ToUpper(protocol[:1])+protocol[1:], id),
+			Status:            state,
+			ChainID:           fmt.Sprintf("eip155:%d", source.GovernorChainID),
+			StartsAt:          blockTime(now, currentBlock, startBlock),
+			EndsAt:            blockTime(now, currentBlock, endBlock),
+			Quorum:            quorum,
+			VotesTotal:        forVotes + againstVotes + abstainVotes,
+			QuorumProgressPct: quorumProgress(forVotes, quorum),
+			Choices: []model.GovernanceChoice{
+				{Choice: "for", Votes: forVotes},
+				{Choice: "against", Votes: againstVotes},
+				{Choice: "abstain", Votes: abstainVotes},
+			},
+			URL:       fmt.Sprintf("https://www.tally.xyz/gov/%s/proposal/%d", source.TallySlug, id),
+			Provider:  "governance",
+			FetchedAt: now.Format(time.RFC3339),
+		})
+	}
+	return out, nil
+}
+
+func callGovernor(ctx context.Context, client *ethclient.Client, governor common.Address, method string, args ...any) ([]any, error) {
+	data, err := governorABI.Pack(method, args...)
+	if err != nil {
+		return nil, clierr.Wrap(clierr.CodeInternal, "pack "+method, err)
+	}
+	out, err := client.CallContract(ctx, ethereum.CallMsg{To: &governor, Data: data}, nil)
+	if err != nil {
+		return nil, clierr.Wrap(clierr.CodeUnavailable, "call "+method, err)
+	}
+	decoded, err := governorABI.Unpack(method, out)
+	if err != nil || len(decoded) == 0 {
+		return nil, clierr.Wrap(clierr.CodeUnavailable, "decode "+method, err)
+	}
+	return decoded, nil
+}
+
+func governorState(state uint8) string {
+	if int(state) < len(governorStates) {
+		return governorStates[state]
+	}
+	return fmt.Sprintf("unknown_%d", state)
+}
+
+// blockTime estimates the time of block from the current block.
+func blockTime(now time.Time, current uint64, block *big.Int) string {
+	if block == nil || block.Sign() <= 0 {
+		return ""
+	}
+	delta := block.Int64() - int64(current)
+	return now.Add(time.Duration(delta) * ethereumBlockTime).Format(time.RFC3339)
+}
+
+// tokenUnits converts an 18-decimal vote amount to whole tokens.
+func tokenUnits(value *big.Int) float64 {
+	out, _ := new(big.Float).Quo(new(big.Float).SetInt(value), big.NewFloat(1e18)).Float64()
+	return out
+}
+
+func mustABI(raw string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(raw))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
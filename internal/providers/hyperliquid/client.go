@@ -0,0 +1,130 @@
+package hyperliquid
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ggonzalez94/defi-cli/internal/clock"
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/httpx"
+	"github.com/ggonzalez94/defi-cli/internal/model"
+)
+
+const (
+	defaultBase = "https://api.hyperliquid.xyz"
+	sourceURL   = "https://app.hyperliquid.xyz/trade"
+	// Hyperliquid settles funding every hour; the API reports the hourly rate.
+	fundingIntervalHours = 1
+)
+
+type Client struct {
+	http    *httpx.Client
+	baseURL string
+	now     func() time.Time
+}
+
+func New(httpClient *httpx.Client) *Client {
+	return &Client{http: httpClient, baseURL: defaultBase, now: clock.Now}
+}
+
+func (c *Client) Info() model.ProviderInfo {
+	return model.ProviderInfo{
+		Name:        "hyperliquid",
+		Type:        "perps+yield",
+		RequiresKey: false,
+		Capabilities: []string{
+			"perps.markets",
+			"perps.rates",
+			"yield.opportunities",
+			"history.price",
+		},
+	}
+}
+
+type universeEntry struct {
+	Name        string `json:"name"`
+	MaxLeverage int    `json:"maxLeverage"`
+	IsDelisted  bool   `json:"isDelisted"`
+}
+
+type meta struct {
+	Universe []universeEntry `json:"universe"`
+}
+
+type assetContext struct {
+	Funding      string `json:"funding"`
+	OpenInterest string `json:"openInterest"`
+	OraclePx     string `json:"oraclePx"`
+	MarkPx       string `json:"markPx"`
+	DayNtlVlm    string `json:"dayNtlVlm"`
+}
+
+// PerpMarkets returns every listed Hyperliquid perpetual. The info endpoint
+// answers metaAndAssetCtxs with a two-element array: the universe and the
+// per-asset contexts in the same order.
+func (c *Client) PerpMarkets(ctx context.Context) ([]model.PerpMarket, error) {
+	var raw []json.RawMessage
+	body := []byte(`{"type":"metaAndAssetCtxs"}`)
+	if _, err := httpx.DoBodyJSON(ctx, c.http, http.MethodPost, strings.TrimRight(c.baseURL, "/")+"/info", body, nil, &raw); err != nil {
+		return nil, err
+	}
+	if len(raw) != 2 {
+		return nil, clierr.New(clierr.CodeUnavailable, "unexpected hyperliquid metaAndAssetCtxs response")
+	}
+	var universe meta
+	if err := json.Unmarshal(raw[0], &universe); err != nil {
+		return nil, clierr.Wrap(clierr.CodeUnavailable, "decode hyperliquid universe", err)
+	}
+	var contexts []assetContext
+	if err := json.Unmarshal(raw[1], &contexts); err != nil {
+		return nil, clierr.Wrap(clierr.CodeUnavailable, "decode hyperliquid asset contexts", err)
+	}
+	if len(contexts) != len(universe.Universe) {
+		return nil, clierr.New(clierr.CodeUnavailable, "hyperliquid universe and asset contexts differ in length")
+	}
+
+	fetchedAt := c.now().UTC().Format(time.RFC3339)
+	out := make([]model.PerpMarket, 0, len(contexts))
+	for i, asset := range universe.Universe {
+		if asset.IsDelisted || strings.TrimSpace(asset.Name) == "" {
+			continue
+		}
+		item := contexts[i]
+		funding := parseFloat(item.Funding)
+		mark := parseFloat(item.MarkPx)
+		openInterest := parseFloat(item.OpenInterest)
+		out = append(out, model.PerpMarket{
+			Provider:             "hyperliquid",
+			Market:               asset.Name + "-USD",
+			BaseSymbol:           asset.Name,
+			QuoteSymbol:          "USD",
+			MarkPrice:            mark,
+			IndexPrice:           parseFloat(item.OraclePx),
+			FundingRate:          funding,
+			FundingIntervalHours: fundingIntervalHours,
+			FundingAPR:           funding * 24 / fundingIntervalHours * 365 * 100,
+			OpenInterest:         openInterest,
+			OpenInterestUSD:      openInterest * mark,
+			Volume24hUSD:         parseFloat(item.DayNtlVlm),
+			MaxLeverage:          float64(asset.MaxLeverage),
+			SourceURL:            sourceURL + "/" + asset.Name,
+			FetchedAt:            fetchedAt,
+		})
+	}
+	if len(out) == 0 {
+		return nil, clierr.New(clierr.CodeUnavailable, "no perpetual markets returned by hyperliquid")
+	}
+	return out, nil
+}
+
+func parseFloat(raw string) float64 {
+	v, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+	if err != nil {
+		return 0
+	}
+	return v
+}
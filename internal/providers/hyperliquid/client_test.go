@@ -0,0 +1,193 @@
+package hyperliquid
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/httpx"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/providers"
+)
+
+const metaAndAssetCtxsFixture = `[
+	{"universe":[
+		{"name":"BTC","szDecimals":5,"maxLeverage":40},
+		{"name":"OLD","szDecimals":0,"maxLeverage":3,"isDelisted":true},
+		{"name":"ETH","szDecimals":4,"maxLeverage":25}
+	]},
+	[
+		{"funding":"0.0000125","openInterest":"100.5","oraclePx":"60010.0","markPx":"60000.0","dayNtlVlm":"1500000000.0"},
+		{"funding":"0.0","openInterest":"0.0","oraclePx":"1.0","markPx":"1.0","dayNtlVlm":"0.0"},
+		{"funding":"-0.00002","openInterest":"2000.0","oraclePx":"3001.0","markPx":"3000.0","dayNtlVlm":"800000000.0"}
+	]
+]`
+
+func TestPerpMarketsJoinsUniverseWithAssetContexts(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Type string `json:"type"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if r.Method != http.MethodPost || r.URL.Path != "/info" || body.Type != "metaAndAssetCtxs" {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(metaAndAssetCtxsFixture))
+	}))
+	defer srv.Close()
+
+	c := New(httpx.New(2*time.Second, 0))
+	c.baseURL = srv.URL
+	c.now = func() time.Time { return time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC) }
+
+	markets, err := c.PerpMarkets(context.Background())
+	if err != nil {
+		t.Fatalf("PerpMarkets failed: %v", err)
+	}
+	if len(markets) != 2 {
+		t.Fatalf("expected delisted market to be skipped, got %+v", markets)
+	}
+	btc, eth := markets[0], markets[1]
+	if btc.Market != "BTC-USD" || btc.MarkPrice != 60000 || btc.IndexPrice != 60010 || btc.MaxLeverage != 40 {
+		t.Fatalf("unexpected BTC market: %+v", btc)
+	}
+	// 0.00125% per hour annualizes to 10.95%.
+	if math.Abs(btc.FundingAPR-10.95) > 1e-9 || btc.OpenInterestUSD != 100.5*60000 {
+		t.Fatalf("unexpected BTC funding/open interest: %+v", btc)
+	}
+	if eth.BaseSymbol != "ETH" || eth.FundingRate >= 0 || eth.FundingAPR >= 0 {
+		t.Fatalf("expected negative ETH funding, got %+v", eth)
+	}
+}
+
+var hyperEVM = id.Chain{Name: "HyperEVM", CAIP2: "eip155:999", EVMChainID: 999}
+
+// newInfoServer answers each info request type with a canned body and records
+// the request payloads it saw.
+func newInfoServer(t *testing.T, replies map[string]string, seen map[string]json.RawMessage) *Client {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var raw json.RawMessage
+		_ = json.NewDecoder(r.Body).Decode(&raw)
+		var body struct {
+			Type string `json:"type"`
+		}
+		_ = json.Unmarshal(raw, &body)
+		reply, ok := replies[body.Type]
+		if !ok {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+		if seen != nil {
+			seen[body.Type] = raw
+		}
+		_, _ = w.Write([]byte(reply))
+	}))
+	t.Cleanup(srv.Close)
+	c := New(httpx.New(2*time.Second, 0))
+	c.baseURL = srv.URL
+	c.now = func() time.Time { return time.Date(2026, 1, 3, 12, 0, 0, 0, time.UTC) }
+	return c
+}
+
+func TestYieldOpportunitiesReportsHLPVault(t *testing.T) {
+	c := newInfoServer(t, map[string]string{
+		"vaultDetails": `{"name":"Hyperliquidity Provider (HLP)","apr":0.0725,"isClosed":false,"portfolio":[
+			["allTime",{"accountValueHistory":[[1700000000000,"1.0"]]}],
+			["day",{"accountValueHistory":[[1767350000000,"380000000.0"],[1767440000000,"400000000.5"]]}]
+		]}`,
+	}, nil)
+	usdc, err := id.ParseAsset("USDC", hyperEVM)
+	if err != nil {
+		t.Fatalf("parse USDC: %v", err)
+	}
+	items, err := c.YieldOpportunities(context.Background(), providers.YieldRequest{Chain: hyperEVM, Asset: usdc})
+	if err != nil {
+		t.Fatalf("YieldOpportunities failed: %v", err)
+	}
+	if len(items) != 1 {
+		t.Fatalf("expected the HLP vault only, got %+v", items)
+	}
+	hlp := items[0]
+	if hlp.Provider != "hyperliquid" || hlp.Type != "vault" || hlp.ProviderNativeID != hlpVaultAddress || hlp.AssetID != usdc.AssetID {
+		t.Fatalf("unexpected HLP opportunity %+v", hlp)
+	}
+	if math.Abs(hlp.APYTotal-7.25) > 1e-9 || hlp.TVLUSD != 400000000.5 || hlp.LockupDays != 4 {
+		t.Fatalf("unexpected HLP APY/TVL/lockup %+v", hlp)
+	}
+	if hlp.ExitLiquidity == nil || hlp.ExitLiquidity.ImmediateUSD != 0 || hlp.ExitLiquidity.QueueDays != 4 {
+		t.Fatalf("expected the lockup to queue every exit, got %+v", hlp.ExitLiquidity)
+	}
+
+	weth := id.Asset{ChainID: hyperEVM.CAIP2, Symbol: "WETH", Address: "0x1111111111111111111111111111111111111111"}
+	_, err = c.YieldOpportunities(context.Background(), providers.YieldRequest{Chain: hyperEVM, Asset: weth})
+	if cErr, ok := clierr.As(err); !ok || cErr.Code != clierr.CodeUnsupported {
+		t.Fatalf("expected unsupported asset error, got %v", err)
+	}
+}
+
+const spotMetaFixture = `{
+	"tokens":[
+		{"name":"USDC","index":0,"evmContract":null},
+		{"name":"PURR","index":1,"evmContract":{"address":"0x9b498c3c8a0b8cd8ba1d9851d40d186f1872b44e"}},
+		{"name":"HYPE","index":150,"evmContract":{"address":"0x2222222222222222222222222222222222222222"}}
+	],
+	"universe":[
+		{"name":"PURR/USDC","tokens":[1,0],"index":0},
+		{"name":"@107","tokens":[150,0],"index":107}
+	]
+}`
+
+func TestPriceHistoryUsesSpotCandlesAndMid(t *testing.T) {
+	seen := map[string]json.RawMessage{}
+	c := newInfoServer(t, map[string]string{
+		"spotMeta": spotMetaFixture,
+		"candleSnapshot": `[
+			{"t":1767312000000,"c":"24.10","s":"@107","i":"1d"},
+			{"t":1767225600000,"c":"23.50","s":"@107","i":"1d"}
+		]`,
+		"allMids": `{"@107":"24.55","PURR/USDC":"0.2","BTC":"90000"}`,
+	}, seen)
+	end := c.now()
+	series, err := c.PriceHistory(context.Background(), providers.PriceHistoryRequest{
+		Chain:     hyperEVM,
+		Asset:     id.NativeAsset(hyperEVM),
+		StartTime: end.Add(-72 * time.Hour),
+		EndTime:   end,
+		Interval:  providers.YieldHistoryIntervalDay,
+	})
+	if err != nil {
+		t.Fatalf("PriceHistory failed: %v", err)
+	}
+	var candleReq struct {
+		Req struct {
+			Coin     string `json:"coin"`
+			Interval string `json:"interval"`
+		} `json:"req"`
+	}
+	_ = json.Unmarshal(seen["candleSnapshot"], &candleReq)
+	if candleReq.Req.Coin != "@107" || candleReq.Req.Interval != "1d" {
+		t.Fatalf("expected native HYPE to query the @107 daily candles, got %+v", candleReq.Req)
+	}
+	if series.Provider != "hyperliquid" || series.Metric != "price_usd" || len(series.Points) != 3 {
+		t.Fatalf("unexpected series %+v", series)
+	}
+	if series.Points[0].Value != 23.5 || series.Points[1].Value != 24.1 {
+		t.Fatalf("expected candle closes in time order, got %+v", series.Points)
+	}
+	if last := series.Points[2]; last.Value != 24.55 || last.Timestamp != "2026-01-03T12:00:00Z" {
+		t.Fatalf("expected the current mid as the final point, got %+v", last)
+	}
+
+	usdc := id.Asset{ChainID: hyperEVM.CAIP2, Symbol: "USDC", Address: hyperEVMUSDC}
+	_, err = c.PriceHistory(context.Background(), providers.PriceHistoryRequest{Chain: hyperEVM, Asset: usdc, StartTime: end.Add(-time.Hour), EndTime: end})
+	if cErr, ok := clierr.As(err); !ok || cErr.Code != clierr.CodeUnsupported {
+		t.Fatalf("expected unsupported error for an unlisted token, got %v", err)
+	}
+}
@@ -0,0 +1,153 @@
+package hyperliquid
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/model"
+	"github.com/ggonzalez94/defi-cli/internal/providers"
+)
+
+// whypeAddress is wrapped HYPE on HyperEVM; it trades as HYPE on the spot
+// order book just like the native gas token.
+const whypeAddress = "0x5555555555555555555555555555555555555555"
+
+type spotToken struct {
+	Name        string `json:"name"`
+	Index       int    `json:"index"`
+	EVMContract *struct {
+		Address string `json:"address"`
+	} `json:"evmContract"`
+}
+
+type spotPair struct {
+	Name   string `json:"name"`
+	Tokens []int  `json:"tokens"`
+}
+
+type spotMeta struct {
+	Tokens   []spotToken `json:"tokens"`
+	Universe []spotPair  `json:"universe"`
+}
+
+type candle struct {
+	OpenTime int64  `json:"t"`
+	Close    string `json:"c"`
+}
+
+// PriceHistory returns USDC-quoted spot prices for a HyperEVM asset from the
+// Hyperliquid order book: one candle close per interval, followed by the
+// current order-book mid when the requested range reaches the present.
+func (c *Client) PriceHistory(ctx context.Context, req providers.PriceHistoryRequest) (model.MarketHistorySeries, error) {
+	if req.Chain.CAIP2 != hyperEVMCAIP2 {
+		return model.MarketHistorySeries{}, clierr.New(clierr.CodeUnsupported, "hyperliquid spot prices are available only for HyperEVM assets")
+	}
+	var meta spotMeta
+	if err := c.info(ctx, map[string]any{"type": "spotMeta"}, &meta); err != nil {
+		return model.MarketHistorySeries{}, err
+	}
+	token, coin, err := resolveSpotCoin(meta, req.Asset)
+	if err != nil {
+		return model.MarketHistorySeries{}, err
+	}
+
+	period := time.Hour
+	periodArg := "1h"
+	if req.Interval != providers.YieldHistoryIntervalHour {
+		period = 24 * time.Hour
+		periodArg = "1d"
+	}
+	var candles []candle
+	if err := c.info(ctx, map[string]any{
+		"type": "candleSnapshot",
+		"req": map[string]any{
+			"coin":      coin,
+			"interval":  periodArg,
+			"startTime": req.StartTime.UTC().UnixMilli(),
+			"endTime":   req.EndTime.UTC().UnixMilli(),
+		},
+	}, &candles); err != nil {
+		return model.MarketHistorySeries{}, err
+	}
+	points := make([]model.MarketHistoryPoint, 0, len(candles)+1)
+	for _, item := range candles {
+		ts := time.UnixMilli(item.OpenTime).UTC()
+		if ts.Before(req.StartTime) || ts.After(req.EndTime) {
+			continue
+		}
+		points = append(points, model.MarketHistoryPoint{Timestamp: ts.Format(time.RFC3339), Value: parseFloat(item.Close)})
+	}
+	sort.Slice(points, func(i, j int) bool { return points[i].Timestamp < points[j].Timestamp })
+
+	now := c.now().UTC()
+	if !req.EndTime.Before(now.Add(-period)) {
+		var mids map[string]string
+		if err := c.info(ctx, map[string]any{"type": "allMids"}, &mids); err != nil {
+			return model.MarketHistorySeries{}, err
+		}
+		if mid := parseFloat(mids[coin]); mid > 0 {
+			points = append(points, model.MarketHistoryPoint{Timestamp: now.Format(time.RFC3339), Value: mid})
+		}
+	}
+	if len(points) == 0 {
+		return model.MarketHistorySeries{}, clierr.New(clierr.CodeUnavailable, "no price history points in requested range")
+	}
+
+	symbol := req.Asset.Symbol
+	if symbol == "" {
+		symbol = token.Name
+	}
+	return model.MarketHistorySeries{
+		Metric:    "price_usd",
+		Interval:  string(req.Interval),
+		ChainID:   req.Chain.CAIP2,
+		AssetID:   req.Asset.AssetID,
+		Symbol:    symbol,
+		StartTime: req.StartTime.UTC().Format(time.RFC3339),
+		EndTime:   req.EndTime.UTC().Format(time.RFC3339),
+		Points:    points,
+		Provider:  "hyperliquid",
+		SourceURL: sourceURL + "/" + token.Name + "/USDC",
+		FetchedAt: now.Format(time.RFC3339),
+	}, nil
+}
+
+// resolveSpotCoin finds the spot token linked to a HyperEVM asset and the
+// coin name of its USDC pair ("PURR/USDC" for canonical pairs, "@<index>"
+// otherwise). Native HYPE and WHYPE both resolve to HYPE.
+func resolveSpotCoin(meta spotMeta, asset id.Asset) (spotToken, string, error) {
+	address := strings.ToLower(strings.TrimSpace(asset.Address))
+	if address == whypeAddress || asset.IsNative() {
+		address, asset.Symbol = "", "HYPE"
+	}
+	var token *spotToken
+	for i := range meta.Tokens {
+		item := &meta.Tokens[i]
+		if address != "" && item.EVMContract != nil && strings.EqualFold(item.EVMContract.Address, address) {
+			token = item
+			break
+		}
+		if address == "" && strings.EqualFold(item.Name, strings.TrimSpace(asset.Symbol)) {
+			token = item
+			break
+		}
+	}
+	if token == nil {
+		return spotToken{}, "", clierr.New(clierr.CodeUnsupported, fmt.Sprintf("asset %s is not listed on the hyperliquid spot order book", asset.AssetID))
+	}
+	// Token index 0 is USDC, the quote asset of every spot pair.
+	if token.Index == 0 {
+		return spotToken{}, "", clierr.New(clierr.CodeUnsupported, "USDC is the hyperliquid spot quote asset and has no spot price")
+	}
+	for _, pair := range meta.Universe {
+		if len(pair.Tokens) == 2 && pair.Tokens[0] == token.Index && pair.Tokens[1] == 0 {
+			return *token, pair.Name, nil
+		}
+	}
+	return spotToken{}, "", clierr.New(clierr.CodeUnsupported, fmt.Sprintf("hyperliquid has no %s/USDC spot pair", token.Name))
+}
@@ -0,0 +1,151 @@
+package hyperliquid
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/httpx"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/model"
+	"github.com/ggonzalez94/defi-cli/internal/providers"
+	"github.com/ggonzalez94/defi-cli/internal/providers/yieldutil"
+)
+
+const (
+	hyperEVMCAIP2 = "eip155:999"
+	// hyperEVMUSDC is the HyperEVM deployment of Hyperliquid's USDC, the
+	// only asset HLP accepts.
+	hyperEVMUSDC    = "0xb88339cb7199b77e23db6e890353e22632ba630f"
+	hlpVaultAddress = "0xdfc24b077bc1425ad1dea75bcb6f8158e10df303"
+	vaultsURL       = "https://app.hyperliquid.xyz/vaults/"
+	// HLP deposits are locked for four days before they can be withdrawn.
+	hlpLockupDays      = 4
+	hlpWithdrawalTerms = "4-day lockup after each deposit; deposits and withdrawals settle in USDC on HyperCore"
+)
+
+type vaultDetails struct {
+	Name      string              `json:"name"`
+	APR       float64             `json:"apr"`
+	IsClosed  bool                `json:"isClosed"`
+	Portfolio [][]json.RawMessage `json:"portfolio"`
+}
+
+type portfolioPeriod struct {
+	AccountValueHistory [][]json.RawMessage `json:"accountValueHistory"`
+}
+
+// YieldOpportunities returns the Hyperliquidity Provider (HLP) vault for USDC
+// on HyperEVM. The vault APR is the trailing return Hyperliquid reports for
+// HLP; TVL is the vault's latest account value.
+func (c *Client) YieldOpportunities(ctx context.Context, req providers.YieldRequest) ([]model.YieldOpportunity, error) {
+	if req.Chain.CAIP2 != hyperEVMCAIP2 {
+		return nil, clierr.New(clierr.CodeUnsupported, "hyperliquid vault opportunities are available only on HyperEVM")
+	}
+	if !isUSDC(req.Asset) {
+		return nil, clierr.New(clierr.CodeUnsupported, "hyperliquid HLP accepts USDC only")
+	}
+
+	var details vaultDetails
+	if err := c.info(ctx, map[string]any{"type": "vaultDetails", "vaultAddress": hlpVaultAddress}, &details); err != nil {
+		return nil, err
+	}
+	if details.IsClosed {
+		return nil, clierr.New(clierr.CodeUnavailable, "hyperliquid HLP vault is closed")
+	}
+	apy := yieldutil.PercentFromRatio(details.APR)
+	tvl := latestAccountValue(details.Portfolio)
+	if (apy <= 0 || tvl == 0) && !req.IncludeIncomplete {
+		return nil, clierr.New(clierr.CodeUnavailable, "hyperliquid HLP vault returned no APR or TVL")
+	}
+	if apy < req.MinAPY || tvl < req.MinTVLUSD {
+		return nil, clierr.New(clierr.CodeUnavailable, "hyperliquid HLP vault is below the requested APY/TVL thresholds")
+	}
+
+	usdcID := "eip155:999/erc20:" + hyperEVMUSDC
+	out := []model.YieldOpportunity{{
+		OpportunityID:        hashOpportunity(strings.Join([]string{"hyperliquid", hyperEVMCAIP2, hlpVaultAddress}, "|")),
+		Provider:             "hyperliquid",
+		Protocol:             "hyperliquid",
+		ChainID:              hyperEVMCAIP2,
+		AssetID:              usdcID,
+		ProviderNativeID:     hlpVaultAddress,
+		ProviderNativeIDKind: model.NativeIDKindVaultAddress,
+		Type:                 "vault",
+		APYBase:              apy,
+		APYTotal:             apy,
+		RateBasis:            yieldutil.RateBasisAPR,
+		Compounding:          yieldutil.CompoundingContinuous,
+		TVLUSD:               tvl,
+		LockupDays:           hlpLockupDays,
+		WithdrawalTerms:      hlpWithdrawalTerms,
+		ExitLiquidity:        yieldutil.ExitLiquidity(tvl, tvl, hlpLockupDays),
+		BackingAssets: []model.YieldBackingAsset{{
+			AssetID:  usdcID,
+			Symbol:   "USDC",
+			SharePct: 100,
+		}},
+		SourceURL: vaultsURL + hlpVaultAddress,
+		FetchedAt: c.now().UTC().Format(time.RFC3339),
+	}}
+	yieldutil.ApplyEffectiveAPY(out)
+	yieldutil.ApplyRisk(out)
+	return out, nil
+}
+
+func isUSDC(asset id.Asset) bool {
+	address := strings.ToLower(strings.TrimSpace(asset.Address))
+	if address != "" {
+		return address == hyperEVMUSDC
+	}
+	return id.SymbolsEquivalent(asset.Symbol, "USDC")
+}
+
+// latestAccountValue reads the most recent account value from the "day"
+// portfolio window, which the API encodes as [period, {...}] pairs of
+// [timestamp_ms, "value"] points.
+func latestAccountValue(portfolio [][]json.RawMessage) float64 {
+	for _, entry := range portfolio {
+		if len(entry) != 2 {
+			continue
+		}
+		var period string
+		if err := json.Unmarshal(entry[0], &period); err != nil || period != "day" {
+			continue
+		}
+		var data portfolioPeriod
+		if err := json.Unmarshal(entry[1], &data); err != nil || len(data.AccountValueHistory) == 0 {
+			return 0
+		}
+		last := data.AccountValueHistory[len(data.AccountValueHistory)-1]
+		if len(last) != 2 {
+			return 0
+		}
+		var value string
+		if err := json.Unmarshal(last[1], &value); err != nil {
+			return 0
+		}
+		return parseFloat(value)
+	}
+	return 0
+}
+
+// info posts one request body to the Hyperliquid info endpoint.
+func (c *Client) info(ctx context.Context, request any, out any) error {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return clierr.Wrap(clierr.CodeInternal, "encode hyperliquid info request", err)
+	}
+	_, err = httpx.DoBodyJSON(ctx, c.http, http.MethodPost, strings.TrimRight(c.baseURL, "/")+"/info", body, nil, out)
+	return err
+}
+
+func hashOpportunity(seed string) string {
+	sum := sha1.Sum([]byte(seed))
+	return hex.EncodeToString(sum[:])
+}
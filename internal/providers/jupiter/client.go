@@ -9,6 +9,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/ggonzalez94/defi-cli/internal/clock"
 	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
 	"github.com/ggonzalez94/defi-cli/internal/httpx"
 	"github.com/ggonzalez94/defi-cli/internal/id"
@@ -17,9 +18,8 @@ import (
 )
 
 const (
-	defaultLiteBase    = "https://lite-api.jup.ag/swap/v1"
-	defaultProBase     = "https://api.jup.ag/swap/v1"
-	solanaMainnetCAIP2 = "solana:5eykt4UsFv8P8NJdTREpY1vzqKqZKvdp"
+	defaultLiteBase = "https://lite-api.jup.ag/swap/v1"
+	defaultProBase  = "https://api.jup.ag/swap/v1"
 )
 
 type Client struct {
@@ -39,7 +39,7 @@ func New(httpClient *httpx.Client, apiKey string) *Client {
 		http:    httpClient,
 		baseURL: baseURL,
 		apiKey:  apiKey,
-		now:     time.Now,
+		now:     clock.Now,
 	}
 }
 
@@ -51,6 +51,7 @@ func (c *Client) Info() model.ProviderInfo {
 		KeyEnvVarName: "DEFI_JUPITER_API_KEY",
 		Capabilities: []string{
 			"swap.quote",
+			"swap.limit_order",
 		},
 		CapabilityAuth: []model.ProviderCapabilityAuth{
 			{
@@ -58,14 +59,27 @@ func (c *Client) Info() model.ProviderInfo {
 				KeyEnvVar:   "DEFI_JUPITER_API_KEY",
 				Description: "Optional API key for higher Jupiter API limits",
 			},
+			{
+				Capability:  "swap.limit_order",
+				KeyEnvVar:   "DEFI_JUPITER_API_KEY",
+				Description: "Optional API key for higher Jupiter API limits",
+			},
+		},
+		Support: []model.CapabilitySupport{
+			{Capability: "swap.quote", Chains: []string{id.SolanaMainnetCAIP2}, Features: []string{providers.FeatureExactInput, providers.FeatureExactOutput}},
+			{Capability: "swap.limit_order", Chains: []string{id.SolanaMainnetCAIP2}},
 		},
 	}
 }
 
 type quoteResponse struct {
-	OutAmount      string `json:"outAmount"`
-	PriceImpactPct string `json:"priceImpactPct"`
-	RoutePlan      []struct {
+	InAmount  string `json:"inAmount"`
+	OutAmount string `json:"outAmount"`
+	// OtherAmountThreshold is the min output of ExactIn quotes and the max
+	// input of ExactOut quotes at slippageBps.
+	OtherAmountThreshold string `json:"otherAmountThreshold"`
+	PriceImpactPct       string `json:"priceImpactPct"`
+	RoutePlan            []struct {
 		SwapInfo struct {
 			Label string `json:"label"`
 		} `json:"swapInfo"`
@@ -77,14 +91,16 @@ func (c *Client) QuoteSwap(ctx context.Context, req providers.SwapQuoteRequest)
 	if tradeType == "" {
 		tradeType = providers.SwapTradeTypeExactInput
 	}
-	if tradeType != providers.SwapTradeTypeExactInput {
-		return model.SwapQuote{}, clierr.New(clierr.CodeUnsupported, "jupiter supports only --type exact-input")
+	switch tradeType {
+	case providers.SwapTradeTypeExactInput, providers.SwapTradeTypeExactOutput:
+	default:
+		return model.SwapQuote{}, clierr.New(clierr.CodeUnsupported, "jupiter swap type must be exact-input or exact-output")
 	}
 
 	if !req.Chain.IsSolana() {
 		return model.SwapQuote{}, clierr.New(clierr.CodeUnsupported, "jupiter swap quotes support only Solana chains")
 	}
-	if req.Chain.CAIP2 != solanaMainnetCAIP2 {
+	if req.Chain.CAIP2 != id.SolanaMainnetCAIP2 {
 		return model.SwapQuote{}, clierr.New(clierr.CodeUnsupported, "jupiter swap quotes support only Solana mainnet")
 	}
 
@@ -92,7 +108,12 @@ func (c *Client) QuoteSwap(ctx context.Context, req providers.SwapQuoteRequest)
 	vals.Set("inputMint", req.FromAsset.Address)
 	vals.Set("outputMint", req.ToAsset.Address)
 	vals.Set("amount", req.AmountBaseUnits)
-	vals.Set("slippageBps", "50")
+	slippagePct := providers.SwapSlippagePct(req)
+	vals.Set("slippageBps", strconv.FormatInt(providers.SlippageBps(slippagePct), 10))
+	if tradeType == providers.SwapTradeTypeExactOutput {
+		// ExactOut quotes take the output amount; fewer AMMs support it.
+		vals.Set("swapMode", "ExactOut")
+	}
 
 	endpoint := fmt.Sprintf("%s/quote?%s", strings.TrimRight(c.baseURL, "/"), vals.Encode())
 	hReq, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
@@ -110,16 +131,25 @@ func (c *Client) QuoteSwap(ctx context.Context, req providers.SwapQuoteRequest)
 	if strings.TrimSpace(resp.OutAmount) == "" {
 		return model.SwapQuote{}, clierr.New(clierr.CodeUnavailable, "jupiter quote missing output amount")
 	}
+	inputBase := req.AmountBaseUnits
+	inputDecimal := req.AmountDecimal
+	if tradeType == providers.SwapTradeTypeExactOutput {
+		inputBase = strings.TrimSpace(resp.InAmount)
+		if inputBase == "" {
+			return model.SwapQuote{}, clierr.New(clierr.CodeUnavailable, "jupiter exact-output quote missing input amount")
+		}
+		inputDecimal = id.FormatDecimalCompat(inputBase, req.FromAsset.Decimals)
+	}
 
-	return model.SwapQuote{
+	quote := model.SwapQuote{
 		Provider:    "jupiter",
 		ChainID:     req.Chain.CAIP2,
 		FromAssetID: req.FromAsset.AssetID,
 		ToAssetID:   req.ToAsset.AssetID,
 		TradeType:   string(tradeType),
 		InputAmount: model.AmountInfo{
-			AmountBaseUnits: req.AmountBaseUnits,
-			AmountDecimal:   req.AmountDecimal,
+			AmountBaseUnits: inputBase,
+			AmountDecimal:   inputDecimal,
 			Decimals:        req.FromAsset.Decimals,
 		},
 		EstimatedOut: model.AmountInfo{
@@ -132,7 +162,16 @@ func (c *Client) QuoteSwap(ctx context.Context, req providers.SwapQuoteRequest)
 		Route:           routeFromPlan(resp.RoutePlan),
 		SourceURL:       "https://jup.ag",
 		FetchedAt:       c.now().UTC().Format(time.RFC3339),
-	}, nil
+	}
+	if threshold := strings.TrimSpace(resp.OtherAmountThreshold); threshold != "" {
+		if tradeType == providers.SwapTradeTypeExactOutput {
+			quote.MaxIn = &model.AmountInfo{AmountBaseUnits: threshold, AmountDecimal: id.FormatDecimalCompat(threshold, req.FromAsset.Decimals), Decimals: req.FromAsset.Decimals}
+		} else {
+			quote.MinOut = &model.AmountInfo{AmountBaseUnits: threshold, AmountDecimal: id.FormatDecimalCompat(threshold, req.ToAsset.Decimals), Decimals: req.ToAsset.Decimals}
+		}
+	}
+	providers.ApplySwapSlippage(&quote, slippagePct)
+	return quote, nil
 }
 
 func parsePriceImpactPct(v string) float64 {
@@ -170,3 +209,19 @@ func routeFromPlan(plan []struct {
 	}
 	return strings.Join(parts, " > ")
 }
+
+// VerifyKey checks the API key against the program label endpoint, the
+// cheapest authenticated call on the pro API.
+func (c *Client) VerifyKey(ctx context.Context) error {
+	if c.apiKey == "" {
+		return providers.ErrNoAPIKey
+	}
+	hReq, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/program-id-to-label", nil)
+	if err != nil {
+		return clierr.Wrap(clierr.CodeInternal, "build jupiter label request", err)
+	}
+	hReq.Header.Set("x-api-key", c.apiKey)
+	var resp map[string]string
+	_, err = c.http.DoJSON(ctx, hReq, &resp)
+	return err
+}
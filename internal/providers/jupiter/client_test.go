@@ -49,8 +49,12 @@ func TestQuoteSwapParsesJupiterResponse(t *testing.T) {
 		if got := r.Header.Get("x-api-key"); got != "test-key" {
 			t.Fatalf("expected x-api-key header, got %q", got)
 		}
+		if got := r.URL.Query().Get("slippageBps"); got != "100" {
+			t.Fatalf("expected slippageBps=100, got %q", got)
+		}
 		_, _ = w.Write([]byte(`{
 			"outAmount":"1995000",
+			"otherAmountThreshold":"1975050",
 			"priceImpactPct":"0.13",
 			"routePlan":[
 				{"swapInfo":{"label":"Meteora"}},
@@ -65,6 +69,7 @@ func TestQuoteSwapParsesJupiterResponse(t *testing.T) {
 	assetIn, _ := id.ParseAsset("USDC", chain)
 	assetOut, _ := id.ParseAsset("USDT", chain)
 
+	slippage := 1.0
 	c := New(httpx.New(2*time.Second, 0), "test-key")
 	c.baseURL = srv.URL
 	got, err := c.QuoteSwap(context.Background(), providers.SwapQuoteRequest{
@@ -73,6 +78,7 @@ func TestQuoteSwapParsesJupiterResponse(t *testing.T) {
 		ToAsset:         assetOut,
 		AmountBaseUnits: "2000000",
 		AmountDecimal:   "2",
+		SlippagePct:     &slippage,
 	})
 	if err != nil {
 		t.Fatalf("QuoteSwap failed: %v", err)
@@ -92,15 +98,32 @@ func TestQuoteSwapParsesJupiterResponse(t *testing.T) {
 	if got.Route != "Meteora > Orca" {
 		t.Fatalf("unexpected route: %s", got.Route)
 	}
+	if got.SlippagePct != 1 || got.MinOut == nil || got.MinOut.AmountBaseUnits != "1975050" {
+		t.Fatalf("expected min_out from otherAmountThreshold, got %+v", got)
+	}
 }
 
-func TestQuoteSwapRejectsExactOutput(t *testing.T) {
+func TestQuoteSwapExactOutputUsesSwapMode(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/quote", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("swapMode"); got != "ExactOut" {
+			t.Fatalf("expected swapMode=ExactOut, got %q", got)
+		}
+		if got := r.URL.Query().Get("amount"); got != "1000000" {
+			t.Fatalf("expected output amount, got %q", got)
+		}
+		_, _ = w.Write([]byte(`{"inAmount":"1002500","outAmount":"1000000","priceImpactPct":"0.01","routePlan":[{"swapInfo":{"label":"Whirlpool"}}]}`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
 	chain, _ := id.ParseChain("solana")
 	assetIn, _ := id.ParseAsset("USDC", chain)
 	assetOut, _ := id.ParseAsset("USDT", chain)
 
 	c := New(httpx.New(2*time.Second, 0), "")
-	_, err := c.QuoteSwap(context.Background(), providers.SwapQuoteRequest{
+	c.baseURL = srv.URL
+	got, err := c.QuoteSwap(context.Background(), providers.SwapQuoteRequest{
 		Chain:           chain,
 		FromAsset:       assetIn,
 		ToAsset:         assetOut,
@@ -108,7 +131,16 @@ func TestQuoteSwapRejectsExactOutput(t *testing.T) {
 		AmountDecimal:   "1",
 		TradeType:       providers.SwapTradeTypeExactOutput,
 	})
-	if err == nil {
-		t.Fatal("expected unsupported exact-output error")
+	if err != nil {
+		t.Fatalf("QuoteSwap failed: %v", err)
+	}
+	if got.TradeType != "exact-output" || got.Emulated {
+		t.Fatalf("expected native exact-output quote, got %+v", got)
+	}
+	if got.InputAmount.AmountBaseUnits != "1002500" || got.InputAmount.AmountDecimal != "1.0025" {
+		t.Fatalf("unexpected input amount: %+v", got.InputAmount)
+	}
+	if got.EstimatedOut.AmountBaseUnits != "1000000" {
+		t.Fatalf("unexpected amount out: %+v", got.EstimatedOut)
 	}
 }
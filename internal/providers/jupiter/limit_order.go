@@ -0,0 +1,201 @@
+package jupiter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/execution"
+	"github.com/ggonzalez94/defi-cli/internal/httpx"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/providers"
+)
+
+type triggerOrderParams struct {
+	MakingAmount string `json:"makingAmount"`
+	TakingAmount string `json:"takingAmount"`
+	ExpiredAt    string `json:"expiredAt,omitempty"`
+}
+
+type createTriggerOrderRequest struct {
+	InputMint        string             `json:"inputMint"`
+	OutputMint       string             `json:"outputMint"`
+	Maker            string             `json:"maker"`
+	Payer            string             `json:"payer"`
+	Params           triggerOrderParams `json:"params"`
+	ComputeUnitPrice string             `json:"computeUnitPrice"`
+}
+
+type cancelTriggerOrderRequest struct {
+	Maker            string `json:"maker"`
+	Order            string `json:"order"`
+	ComputeUnitPrice string `json:"computeUnitPrice"`
+}
+
+type triggerTransactionResponse struct {
+	Order       string `json:"order"`
+	Transaction string `json:"transaction"`
+	RequestID   string `json:"requestId"`
+}
+
+type triggerOrdersResponse struct {
+	Orders []struct {
+		OrderKey                 string `json:"orderKey"`
+		RawMakingAmount          string `json:"rawMakingAmount"`
+		RawRemainingMakingAmount string `json:"rawRemainingMakingAmount"`
+		Status                   string `json:"status"`
+	} `json:"orders"`
+}
+
+func (c *Client) CreateLimitOrder(ctx context.Context, req providers.LimitOrderRequest, _ providers.LimitOrderSigner) (execution.LimitOrder, error) {
+	if !req.Chain.IsSolana() {
+		return execution.LimitOrder{}, clierr.New(clierr.CodeUnsupported, "jupiter limit orders support only Solana")
+	}
+	if !id.IsSolanaAddress(req.Maker) {
+		return execution.LimitOrder{}, clierr.New(clierr.CodeUsage, "limit order maker must be a Solana address")
+	}
+	if receiver := strings.TrimSpace(req.Receiver); receiver != "" && receiver != req.Maker {
+		return execution.LimitOrder{}, clierr.New(clierr.CodeUnsupported, "jupiter limit orders always pay out to the maker")
+	}
+	params := triggerOrderParams{MakingAmount: req.MakingAmount, TakingAmount: req.TakingAmount}
+	expiresAt := ""
+	if !req.ExpiresAt.IsZero() {
+		params.ExpiredAt = strconv.FormatInt(req.ExpiresAt.Unix(), 10)
+		expiresAt = req.ExpiresAt.UTC().Format(time.RFC3339)
+	}
+	body, err := json.Marshal(createTriggerOrderRequest{
+		InputMint:        req.FromAsset.Address,
+		OutputMint:       req.ToAsset.Address,
+		Maker:            req.Maker,
+		Payer:            req.Maker,
+		Params:           params,
+		ComputeUnitPrice: "auto",
+	})
+	if err != nil {
+		return execution.LimitOrder{}, clierr.Wrap(clierr.CodeInternal, "encode jupiter trigger order", err)
+	}
+	var resp triggerTransactionResponse
+	if _, err := httpx.DoBodyJSON(ctx, c.http, http.MethodPost, c.triggerBase()+"/createOrder", body, c.triggerHeaders(), &resp); err != nil {
+		return execution.LimitOrder{}, err
+	}
+	if resp.Order == "" || resp.Transaction == "" {
+		return execution.LimitOrder{}, clierr.New(clierr.CodeUnavailable, "jupiter trigger order response missing order or transaction")
+	}
+	return execution.LimitOrder{
+		Protocol:            execution.LimitOrderProtocolJupiter,
+		OrderID:             resp.Order,
+		Maker:               req.Maker,
+		MakerAsset:          req.FromAsset.Address,
+		TakerAsset:          req.ToAsset.Address,
+		MakingAmount:        req.MakingAmount,
+		TakingAmount:        req.TakingAmount,
+		ExpiresAt:           expiresAt,
+		Status:              execution.LimitOrderStatusPendingSignature,
+		StatusReason:        "sign and send unsigned_transaction with the maker wallet to open the order",
+		UnsignedTransaction: resp.Transaction,
+		RequestID:           resp.RequestID,
+		CheckedAt:           c.now().UTC().Format(time.RFC3339),
+	}, nil
+}
+
+func (c *Client) RefreshLimitOrder(ctx context.Context, _ id.Chain, order *execution.LimitOrder) error {
+	if order == nil || strings.TrimSpace(order.OrderID) == "" {
+		return clierr.New(clierr.CodeUsage, "limit order has no order key")
+	}
+	now := c.now().UTC()
+	for _, status := range []string{"active", "history"} {
+		vals := url.Values{}
+		vals.Set("user", order.Maker)
+		vals.Set("orderStatus", status)
+		var resp triggerOrdersResponse
+		if _, err := httpx.DoBodyJSON(ctx, c.http, http.MethodGet, c.triggerBase()+"/getTriggerOrders?"+vals.Encode(), nil, c.triggerHeaders(), &resp); err != nil {
+			return err
+		}
+		for _, item := range resp.Orders {
+			if item.OrderKey != order.OrderID {
+				continue
+			}
+			order.CheckedAt = now.Format(time.RFC3339)
+			order.UnsignedTransaction = ""
+			order.StatusReason = ""
+			making, okMaking := new(big.Int).SetString(firstNonEmpty(item.RawMakingAmount, order.MakingAmount), 10)
+			remaining, okRemaining := new(big.Int).SetString(item.RawRemainingMakingAmount, 10)
+			if okMaking && okRemaining {
+				order.FilledMakingAmount = new(big.Int).Sub(making, remaining).String()
+			}
+			switch strings.ToLower(item.Status) {
+			case "completed":
+				order.Status = execution.LimitOrderStatusFilled
+			case "cancelled":
+				order.Status = execution.LimitOrderStatusCancelled
+			default:
+				order.Status = execution.LimitOrderStatusOpen
+				if okRemaining && remaining.Sign() > 0 && order.FilledMakingAmount != "0" {
+					order.Status = execution.LimitOrderStatusPartiallyFilled
+				}
+				if order.Expired(now) {
+					order.Status = execution.LimitOrderStatusExpired
+				}
+			}
+			return nil
+		}
+	}
+	// The order account only exists once the maker sends the create
+	// transaction, so an unknown order is still awaiting its signature.
+	order.CheckedAt = now.Format(time.RFC3339)
+	if order.Status == execution.LimitOrderStatusPendingSignature && order.Expired(now) {
+		order.Status = execution.LimitOrderStatusExpired
+	}
+	return nil
+}
+
+func (c *Client) CancelLimitOrder(ctx context.Context, _ id.Chain, order *execution.LimitOrder) ([]execution.ActionStep, error) {
+	if order == nil || strings.TrimSpace(order.OrderID) == "" {
+		return nil, clierr.New(clierr.CodeUsage, "limit order has no order key")
+	}
+	body, err := json.Marshal(cancelTriggerOrderRequest{Maker: order.Maker, Order: order.OrderID, ComputeUnitPrice: "auto"})
+	if err != nil {
+		return nil, clierr.Wrap(clierr.CodeInternal, "encode jupiter cancel order", err)
+	}
+	var resp triggerTransactionResponse
+	if _, err := httpx.DoBodyJSON(ctx, c.http, http.MethodPost, c.triggerBase()+"/cancelOrder", body, c.triggerHeaders(), &resp); err != nil {
+		return nil, err
+	}
+	if resp.Transaction == "" {
+		return nil, clierr.New(clierr.CodeUnavailable, "jupiter cancel order response missing transaction")
+	}
+	order.UnsignedTransaction = resp.Transaction
+	order.RequestID = resp.RequestID
+	order.StatusReason = "sign and send unsigned_transaction with the maker wallet to cancel the order"
+	return nil, nil
+}
+
+// triggerBase maps the configured swap API base to the Trigger API on the
+// same host, so the pro host is used whenever an API key is set.
+func (c *Client) triggerBase() string {
+	base := strings.TrimSuffix(strings.TrimRight(c.baseURL, "/"), "/swap/v1")
+	return fmt.Sprintf("%s/trigger/v1", base)
+}
+
+func (c *Client) triggerHeaders() map[string]string {
+	if c.apiKey == "" {
+		return nil
+	}
+	return map[string]string{"x-api-key": c.apiKey}
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if strings.TrimSpace(value) != "" {
+			return value
+		}
+	}
+	return ""
+}
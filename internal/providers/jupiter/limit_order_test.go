@@ -0,0 +1,80 @@
+package jupiter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ggonzalez94/defi-cli/internal/execution"
+	"github.com/ggonzalez94/defi-cli/internal/httpx"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/providers"
+)
+
+const testMaker = "7xKXtg2CW87d97TXJSDpbD5jBkheTqA83TZRuJosgAsU"
+
+func TestCreateLimitOrderReturnsUnsignedTransaction(t *testing.T) {
+	var posted createTriggerOrderRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/trigger/v1/createOrder" {
+			t.Fatalf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&posted); err != nil {
+			t.Fatalf("decode body: %v", err)
+		}
+		_, _ = w.Write([]byte(`{"order":"OrderKey111","transaction":"AQAB","requestId":"req-1"}`))
+	}))
+	defer srv.Close()
+
+	chain, _ := id.ParseChain("solana")
+	usdc, _ := id.ParseAsset("USDC", chain)
+	sol, _ := id.ParseAsset("SOL", chain)
+	c := New(httpx.New(2*time.Second, 0), "test-key")
+	c.baseURL = srv.URL
+	order, err := c.CreateLimitOrder(context.Background(), providers.LimitOrderRequest{
+		Chain:        chain,
+		FromAsset:    usdc,
+		ToAsset:      sol,
+		MakingAmount: "100000000",
+		TakingAmount: "500000000",
+		Maker:        testMaker,
+		ExpiresAt:    time.Unix(1_800_000_000, 0),
+	}, nil)
+	if err != nil {
+		t.Fatalf("CreateLimitOrder failed: %v", err)
+	}
+	if order.Status != execution.LimitOrderStatusPendingSignature || order.OrderID != "OrderKey111" || order.UnsignedTransaction != "AQAB" {
+		t.Fatalf("unexpected order %+v", order)
+	}
+	if posted.Maker != testMaker || posted.Params.MakingAmount != "100000000" || posted.Params.ExpiredAt != "1800000000" {
+		t.Fatalf("unexpected posted order %+v", posted)
+	}
+}
+
+func TestRefreshLimitOrderFindsOrderInHistory(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/trigger/v1/getTriggerOrders" || r.URL.Query().Get("user") != testMaker {
+			t.Fatalf("unexpected request %s", r.URL.String())
+		}
+		if r.URL.Query().Get("orderStatus") == "active" {
+			_, _ = w.Write([]byte(`{"orders":[]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"orders":[{"orderKey":"OrderKey111","rawMakingAmount":"100000000","rawRemainingMakingAmount":"0","status":"Completed"}]}`))
+	}))
+	defer srv.Close()
+
+	chain, _ := id.ParseChain("solana")
+	c := New(httpx.New(2*time.Second, 0), "")
+	c.baseURL = srv.URL
+	order := &execution.LimitOrder{OrderID: "OrderKey111", Maker: testMaker, MakingAmount: "100000000", Status: execution.LimitOrderStatusPendingSignature, UnsignedTransaction: "AQAB"}
+	if err := c.RefreshLimitOrder(context.Background(), chain, order); err != nil {
+		t.Fatalf("RefreshLimitOrder failed: %v", err)
+	}
+	if order.Status != execution.LimitOrderStatusFilled || order.FilledMakingAmount != "100000000" || order.UnsignedTransaction != "" {
+		t.Fatalf("expected a filled order, got %+v", order)
+	}
+}
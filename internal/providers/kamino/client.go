@@ -14,6 +14,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/ggonzalez94/defi-cli/internal/clock"
 	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
 	"github.com/ggonzalez94/defi-cli/internal/httpx"
 	"github.com/ggonzalez94/defi-cli/internal/id"
@@ -24,7 +25,6 @@ import (
 
 const (
 	defaultBase        = "https://api.kamino.finance"
-	solanaMainnetCAIP2 = "solana:5eykt4UsFv8P8NJdTREpY1vzqKqZKvdp"
 	marketFetchWorkers = 6
 )
 
@@ -35,7 +35,7 @@ type Client struct {
 }
 
 func New(httpClient *httpx.Client) *Client {
-	return &Client{http: httpClient, baseURL: defaultBase, now: time.Now}
+	return &Client{http: httpClient, baseURL: defaultBase, now: clock.Now}
 }
 
 func (c *Client) Info() model.ProviderInfo {
@@ -46,9 +46,11 @@ func (c *Client) Info() model.ProviderInfo {
 		Capabilities: []string{
 			"lend.markets",
 			"lend.rates",
+			"lend.positions",
 			"yield.opportunities",
 			"yield.history",
 		},
+		Support: providers.ChainSupport([]string{id.SolanaMainnetCAIP2}, "lend.markets", "lend.rates", "lend.positions", "yield.opportunities", "yield.history"),
 	}
 }
 
@@ -65,6 +67,7 @@ type reserveMetric struct {
 	LiquidityTokenMint string `json:"liquidityTokenMint"`
 	BorrowAPY          string `json:"borrowApy"`
 	SupplyAPY          string `json:"supplyApy"`
+	TotalSupply        string `json:"totalSupply"`
 	TotalSupplyUSD     string `json:"totalSupplyUsd"`
 	TotalBorrowUSD     string `json:"totalBorrowUsd"`
 }
@@ -169,6 +172,8 @@ func (c *Client) LendRates(ctx context.Context, provider string, chain id.Chain,
 			ProviderNativeIDKind: model.NativeIDKindPoolID,
 			SupplyAPY:            ratioToPercent(item.Reserve.SupplyAPY),
 			BorrowAPY:            ratioToPercent(item.Reserve.BorrowAPY),
+			RateBasis:            yieldutil.RateBasisAPY,
+			Compounding:          yieldutil.CompoundingContinuous,
 			Utilization:          math.Min(math.Max(utilization, 0), 1),
 			SourceURL:            marketURL(item.Market.LendingMarket),
 			FetchedAt:            fetchedAt,
@@ -235,10 +240,13 @@ func (c *Client) YieldOpportunities(ctx context.Context, req providers.YieldRequ
 			APYBase:              apy,
 			APYReward:            0,
 			APYTotal:             apy,
+			RateBasis:            yieldutil.RateBasisAPY,
+			Compounding:          yieldutil.CompoundingContinuous,
 			TVLUSD:               tvl,
 			LiquidityUSD:         liquidityUSD,
 			LockupDays:           0,
 			WithdrawalTerms:      "variable",
+			ExitLiquidity:        yieldutil.ExitLiquidity(tvl, liquidityUSD, 0),
 			BackingAssets: []model.YieldBackingAsset{{
 				AssetID:  assetID,
 				Symbol:   strings.TrimSpace(item.Reserve.LiquidityToken),
@@ -252,6 +260,8 @@ func (c *Client) YieldOpportunities(ctx context.Context, req providers.YieldRequ
 	if len(out) == 0 {
 		return nil, clierr.New(clierr.CodeUnavailable, "no kamino yield opportunities for requested chain/asset")
 	}
+	yieldutil.ApplyEffectiveAPY(out)
+	yieldutil.ApplyRisk(out)
 	yieldutil.Sort(out, req.SortBy)
 	if req.Limit <= 0 || req.Limit > len(out) {
 		req.Limit = len(out)
@@ -271,7 +281,7 @@ func (c *Client) YieldHistory(ctx context.Context, req providers.YieldHistoryReq
 	if err != nil {
 		return nil, clierr.Wrap(clierr.CodeUsage, "parse kamino opportunity chain", err)
 	}
-	if !chain.IsSolana() || chain.CAIP2 != solanaMainnetCAIP2 {
+	if !chain.IsSolana() || chain.CAIP2 != id.SolanaMainnetCAIP2 {
 		return nil, clierr.New(clierr.CodeUnsupported, "kamino history supports only Solana mainnet")
 	}
 
@@ -326,7 +336,7 @@ func (c *Client) YieldHistory(ctx context.Context, req providers.YieldHistoryReq
 			}
 			points = append(points, model.YieldHistoryPoint{
 				Timestamp: ts.UTC().Format(time.RFC3339),
-				Value:     value * 100,
+				Value:     yieldutil.PercentFromRatio(value),
 			})
 		}
 		sortHistoryPoints(points)
@@ -395,7 +405,7 @@ func (c *Client) fetchReserves(ctx context.Context, chain id.Chain) ([]reserveWi
 	if !chain.IsSolana() {
 		return nil, clierr.New(clierr.CodeUnsupported, "kamino supports only Solana chains")
 	}
-	if chain.CAIP2 != solanaMainnetCAIP2 {
+	if chain.CAIP2 != id.SolanaMainnetCAIP2 {
 		return nil, clierr.New(clierr.CodeUnsupported, "kamino supports only Solana mainnet")
 	}
 
@@ -625,8 +635,7 @@ func marketURL(pubkey string) string {
 }
 
 func ratioToPercent(v string) float64 {
-	ratio := parseNonNegative(v)
-	return ratio * 100
+	return yieldutil.PercentFromRatio(parseNonNegative(v))
 }
 
 func parseNonNegative(v string) float64 {
@@ -373,3 +373,96 @@ func TestYieldHistoryResolvesMarketFromReserve(t *testing.T) {
 		t.Fatalf("unexpected series: %+v", series)
 	}
 }
+
+func TestLendPositionsFromObligations(t *testing.T) {
+	const account = "6dM4QgP1VnRfx6TVV1t5hBf3ytA5Qn2ATqNnSboP8qz5"
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v2/kamino-market", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[
+			{"lendingMarket":"market-primary","name":"Main Market","isPrimary":true,"isCurated":false},
+			{"lendingMarket":"market-jup","name":"JUP Market","isPrimary":false,"isCurated":false}
+		]`))
+	})
+	mux.HandleFunc("/kamino-market/market-primary/reserves/metrics", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[
+			{"reserve":"reserve-usdc","liquidityToken":"USDC","liquidityTokenMint":"EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v","borrowApy":"0.05","supplyApy":"0.03","totalSupply":"1000000","totalSupplyUsd":"1000000","totalBorrowUsd":"500000"},
+			{"reserve":"reserve-sol","liquidityToken":"SOL","liquidityTokenMint":"So11111111111111111111111111111111111111112","borrowApy":"0.06","supplyApy":"0.02","totalSupply":"1000","totalSupplyUsd":"150000","totalBorrowUsd":"1000"}
+		]`))
+	})
+	mux.HandleFunc("/kamino-market/market-jup/reserves/metrics", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[]`))
+	})
+	mux.HandleFunc("/kamino-market/market-primary/users/"+account+"/obligations", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("env"); got != "mainnet-beta" {
+			t.Fatalf("expected env=mainnet-beta, got %q", got)
+		}
+		// 300 USD of SOL collateral, 100 USDC debt (both 2^60-scaled).
+		_, _ = w.Write([]byte(`[{
+			"obligationAddress":"obligation-1",
+			"state":{
+				"deposits":[
+					{"depositReserve":"reserve-sol","depositedAmount":"1900000000","marketValueSf":"345876451382054092800"},
+					{"depositReserve":"11111111111111111111111111111111","depositedAmount":"0","marketValueSf":"0"}
+				],
+				"borrows":[
+					{"borrowReserve":"reserve-usdc","borrowedAmountSf":"115292150460684697600000000","marketValueSf":"115292150460684697600"}
+				]
+			}
+		}]`))
+	})
+	mux.HandleFunc("/kamino-market/market-jup/users/"+account+"/obligations", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("markets without reserves should not be queried")
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	chain, _ := id.ParseChain("solana")
+	c := New(httpx.New(2*time.Second, 0))
+	c.baseURL = srv.URL
+	c.now = func() time.Time { return time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC) }
+
+	positions, err := c.LendPositions(context.Background(), providers.LendPositionsRequest{
+		Chain:        chain,
+		Account:      account,
+		PositionType: providers.LendPositionTypeAll,
+	})
+	if err != nil {
+		t.Fatalf("LendPositions failed: %v", err)
+	}
+	if len(positions) != 2 {
+		t.Fatalf("expected collateral and borrow rows, got %+v", positions)
+	}
+	collateral, borrow := positions[0], positions[1]
+	if collateral.PositionType != "collateral" || collateral.AmountUSD != 300 || collateral.Amount.AmountDecimal != "2" || collateral.Amount.Decimals != 9 {
+		t.Fatalf("unexpected collateral row: %+v", collateral)
+	}
+	if collateral.APY != 2 || collateral.ProviderNativeID != "obligation-1" || collateral.ProviderNativeIDKind != model.NativeIDKindObligationAddress {
+		t.Fatalf("unexpected collateral metadata: %+v", collateral)
+	}
+	if borrow.PositionType != "borrow" || borrow.Amount.AmountBaseUnits != "100000000" || borrow.Amount.AmountDecimal != "100" || borrow.APY != 5 {
+		t.Fatalf("unexpected borrow row: %+v", borrow)
+	}
+	if borrow.AssetID != "solana:5eykt4UsFv8P8NJdTREpY1vzqKqZKvdp/token:EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v" {
+		t.Fatalf("unexpected borrow asset id: %s", borrow.AssetID)
+	}
+
+	borrows, err := c.LendPositions(context.Background(), providers.LendPositionsRequest{
+		Chain:        chain,
+		Account:      account,
+		PositionType: providers.LendPositionTypeBorrow,
+	})
+	if err != nil {
+		t.Fatalf("LendPositions borrow filter failed: %v", err)
+	}
+	if len(borrows) != 1 || borrows[0].PositionType != "borrow" {
+		t.Fatalf("expected only borrow rows, got %+v", borrows)
+	}
+}
+
+func TestLendPositionsRejectsInvalidAccount(t *testing.T) {
+	chain, _ := id.ParseChain("solana")
+	c := New(httpx.New(2*time.Second, 0))
+	if _, err := c.LendPositions(context.Background(), providers.LendPositionsRequest{Chain: chain, Account: "0xabc"}); err == nil {
+		t.Fatal("expected invalid account error")
+	}
+}
@@ -0,0 +1,288 @@
+package kamino
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/model"
+	"github.com/ggonzalez94/defi-cli/internal/providers"
+)
+
+// scaledFractionOne is the 2^60 fixed-point scale used by Kamino "Sf" fields.
+var scaledFractionOne = new(big.Float).SetInt(new(big.Int).Lsh(big.NewInt(1), 60))
+
+type obligationResponse struct {
+	ObligationAddress string `json:"obligationAddress"`
+	State             struct {
+		Deposits []struct {
+			DepositReserve  string `json:"depositReserve"`
+			DepositedAmount string `json:"depositedAmount"`
+			MarketValueSf   string `json:"marketValueSf"`
+		} `json:"deposits"`
+		Borrows []struct {
+			BorrowReserve    string `json:"borrowReserve"`
+			BorrowedAmountSf string `json:"borrowedAmountSf"`
+			MarketValueSf    string `json:"marketValueSf"`
+		} `json:"borrows"`
+	} `json:"state"`
+}
+
+// LendPositions returns deposits and borrows held in the account's Kamino
+// obligations across all lending markets. Obligation deposits always back
+// borrows, so they are reported as collateral rows.
+func (c *Client) LendPositions(ctx context.Context, req providers.LendPositionsRequest) ([]model.LendPosition, error) {
+	account := strings.TrimSpace(req.Account)
+	if !id.IsSolanaAddress(account) {
+		return nil, clierr.New(clierr.CodeUsage, "kamino positions requires a valid Solana account address")
+	}
+	reserves, err := c.fetchReserves(ctx, req.Chain)
+	if err != nil {
+		return nil, err
+	}
+
+	reserveByKey := make(map[string]reserveWithMarket, len(reserves))
+	marketSet := map[string]struct{}{}
+	markets := make([]string, 0)
+	for _, item := range reserves {
+		reserveByKey[strings.TrimSpace(item.Reserve.Reserve)] = item
+		market := strings.TrimSpace(item.Market.LendingMarket)
+		if _, ok := marketSet[market]; ok {
+			continue
+		}
+		marketSet[market] = struct{}{}
+		markets = append(markets, market)
+	}
+
+	obligations, err := c.fetchObligations(ctx, markets, account)
+	if err != nil {
+		return nil, err
+	}
+
+	filterType := req.PositionType
+	if filterType == "" {
+		filterType = providers.LendPositionTypeAll
+	}
+	fetchedAt := c.now().UTC().Format(time.RFC3339)
+	out := make([]model.LendPosition, 0)
+	for _, obligation := range obligations {
+		obligationAddress := strings.TrimSpace(obligation.ObligationAddress)
+		if matchesPositionType(filterType, providers.LendPositionTypeCollateral) {
+			for _, deposit := range obligation.State.Deposits {
+				reserve, ok := reserveByKey[strings.TrimSpace(deposit.DepositReserve)]
+				if !ok || !matchesPositionAsset(reserve.Reserve, req.Asset) {
+					continue
+				}
+				amountUSD := scaledFraction(deposit.MarketValueSf)
+				if amountUSD <= 0 && parseNonNegative(deposit.DepositedAmount) == 0 {
+					continue
+				}
+				out = append(out, c.lendPosition(req.Chain, account, obligationAddress, reserve, providers.LendPositionTypeCollateral, amountFromUSD(reserve.Reserve, amountUSD), amountUSD, fetchedAt))
+			}
+		}
+		if matchesPositionType(filterType, providers.LendPositionTypeBorrow) {
+			for _, borrow := range obligation.State.Borrows {
+				reserve, ok := reserveByKey[strings.TrimSpace(borrow.BorrowReserve)]
+				if !ok || !matchesPositionAsset(reserve.Reserve, req.Asset) {
+					continue
+				}
+				amountUSD := scaledFraction(borrow.MarketValueSf)
+				amount, ok := amountFromScaledBaseUnits(reserve.Reserve, borrow.BorrowedAmountSf)
+				if !ok {
+					amount = amountFromUSD(reserve.Reserve, amountUSD)
+				}
+				if amountUSD <= 0 && amount.AmountBaseUnits == "0" {
+					continue
+				}
+				out = append(out, c.lendPosition(req.Chain, account, obligationAddress, reserve, providers.LendPositionTypeBorrow, amount, amountUSD, fetchedAt))
+			}
+		}
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].AmountUSD != out[j].AmountUSD {
+			return out[i].AmountUSD > out[j].AmountUSD
+		}
+		if out[i].PositionType != out[j].PositionType {
+			return out[i].PositionType < out[j].PositionType
+		}
+		if out[i].AssetID != out[j].AssetID {
+			return out[i].AssetID < out[j].AssetID
+		}
+		return out[i].ProviderNativeID < out[j].ProviderNativeID
+	})
+	if req.Limit > 0 && len(out) > req.Limit {
+		out = out[:req.Limit]
+	}
+	return out, nil
+}
+
+func (c *Client) lendPosition(chain id.Chain, account, obligation string, reserve reserveWithMarket, positionType providers.LendPositionType, amount model.AmountInfo, amountUSD float64, fetchedAt string) model.LendPosition {
+	apy := ratioToPercent(reserve.Reserve.SupplyAPY)
+	if positionType == providers.LendPositionTypeBorrow {
+		apy = ratioToPercent(reserve.Reserve.BorrowAPY)
+	}
+	return model.LendPosition{
+		Protocol:             "kamino",
+		Provider:             "kamino",
+		ChainID:              chain.CAIP2,
+		AccountAddress:       account,
+		PositionType:         string(positionType),
+		AssetID:              reserveAssetID(chain.CAIP2, "", reserve.Reserve.LiquidityTokenMint),
+		ProviderNativeID:     obligation,
+		ProviderNativeIDKind: model.NativeIDKindObligationAddress,
+		Amount:               amount,
+		AmountUSD:            amountUSD,
+		APY:                  apy,
+		SourceURL:            marketURL(reserve.Market.LendingMarket),
+		FetchedAt:            fetchedAt,
+	}
+}
+
+// fetchObligations loads the account's obligations in every market. Markets
+// where the account has no obligation return an empty list.
+func (c *Client) fetchObligations(ctx context.Context, markets []string, account string) ([]obligationResponse, error) {
+	results := make([][]obligationResponse, len(markets))
+	errs := make([]error, len(markets))
+
+	workerLimit := marketFetchWorkers
+	if workerLimit > len(markets) {
+		workerLimit = len(markets)
+	}
+	if workerLimit <= 0 {
+		workerLimit = 1
+	}
+	sem := make(chan struct{}, workerLimit)
+	var wg sync.WaitGroup
+	for i, market := range markets {
+		wg.Add(1)
+		go func(index int, market string) {
+			defer wg.Done()
+
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				errs[index] = ctx.Err()
+				return
+			}
+			defer func() { <-sem }()
+
+			results[index], errs[index] = c.fetchMarketObligations(ctx, market, account)
+		}(i, market)
+	}
+	wg.Wait()
+
+	out := make([]obligationResponse, 0)
+	for i, err := range errs {
+		if err != nil {
+			return nil, clierr.Wrap(clierr.CodeUnavailable, "kamino obligation fetch incomplete", err)
+		}
+		out = append(out, results[i]...)
+	}
+	return out, nil
+}
+
+func (c *Client) fetchMarketObligations(ctx context.Context, market, account string) ([]obligationResponse, error) {
+	endpoint := fmt.Sprintf(
+		"%s/kamino-market/%s/users/%s/obligations?env=mainnet-beta",
+		strings.TrimRight(c.baseURL, "/"),
+		strings.TrimSpace(market),
+		strings.TrimSpace(account),
+	)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, clierr.Wrap(clierr.CodeInternal, "build kamino obligations request", err)
+	}
+	var obligations []obligationResponse
+	if _, err := c.http.DoJSON(ctx, req, &obligations); err != nil {
+		return nil, err
+	}
+	return obligations, nil
+}
+
+func matchesPositionType(filter, position providers.LendPositionType) bool {
+	if filter == "" || filter == providers.LendPositionTypeAll {
+		return true
+	}
+	return filter == position
+}
+
+func matchesPositionAsset(reserve reserveMetric, asset id.Asset) bool {
+	if strings.TrimSpace(asset.Address) == "" && strings.TrimSpace(asset.Symbol) == "" {
+		return true
+	}
+	return matchesReserveAsset(reserve, asset)
+}
+
+// reserveDecimals returns the liquidity token decimals from the bootstrap
+// registry; Kamino reserve metrics do not report them.
+func reserveDecimals(reserve reserveMetric) (int, bool) {
+	token, ok := id.LookupByAddress(id.SolanaMainnetCAIP2, strings.TrimSpace(reserve.LiquidityTokenMint))
+	if !ok {
+		return 0, false
+	}
+	return token.Decimals, true
+}
+
+// amountFromScaledBaseUnits converts a 2^60-scaled base-unit amount (used for
+// borrows) into AmountInfo.
+func amountFromScaledBaseUnits(reserve reserveMetric, scaled string) (model.AmountInfo, bool) {
+	decimals, ok := reserveDecimals(reserve)
+	if !ok {
+		return model.AmountInfo{}, false
+	}
+	value, ok := new(big.Float).SetString(strings.TrimSpace(scaled))
+	if !ok || value.Sign() < 0 {
+		return model.AmountInfo{}, false
+	}
+	base, _ := new(big.Float).Quo(value, scaledFractionOne).Int(nil)
+	return model.AmountInfo{
+		AmountBaseUnits: base.String(),
+		AmountDecimal:   id.FormatDecimalCompat(base.String(), decimals),
+		Decimals:        decimals,
+	}, true
+}
+
+// amountFromUSD derives a token amount from a USD value and the reserve's
+// implied price (total supply USD / total supply). Deposits are held as
+// collateral tokens, so this is the only exchange-rate-free conversion the
+// API allows.
+func amountFromUSD(reserve reserveMetric, amountUSD float64) model.AmountInfo {
+	supply := parseNonNegative(reserve.TotalSupply)
+	supplyUSD := parseNonNegative(reserve.TotalSupplyUSD)
+	if supply <= 0 || supplyUSD <= 0 || amountUSD <= 0 {
+		return model.AmountInfo{AmountBaseUnits: "0", AmountDecimal: "0"}
+	}
+	amount := amountUSD * supply / supplyUSD
+	decimals, ok := reserveDecimals(reserve)
+	if !ok {
+		return model.AmountInfo{AmountDecimal: fmt.Sprintf("%g", amount)}
+	}
+	base, _ := new(big.Float).Mul(big.NewFloat(amount), new(big.Float).SetFloat64(math.Pow10(decimals))).Int(nil)
+	return model.AmountInfo{
+		AmountBaseUnits: base.String(),
+		AmountDecimal:   id.FormatDecimalCompat(base.String(), decimals),
+		Decimals:        decimals,
+	}
+}
+
+// scaledFraction converts a Kamino 2^60 fixed-point value into a float.
+func scaledFraction(v string) float64 {
+	value, ok := new(big.Float).SetString(strings.TrimSpace(v))
+	if !ok || value.Sign() <= 0 {
+		return 0
+	}
+	out, _ := new(big.Float).Quo(value, scaledFractionOne).Float64()
+	if math.IsNaN(out) || math.IsInf(out, 0) {
+		return 0
+	}
+	return out
+}
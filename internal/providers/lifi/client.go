@@ -13,7 +13,7 @@ import (
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ggonzalez94/defi-cli/internal/clock"
 	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
 	"github.com/ggonzalez94/defi-cli/internal/execution"
 	"github.com/ggonzalez94/defi-cli/internal/httpx"
@@ -21,6 +21,7 @@ import (
 	"github.com/ggonzalez94/defi-cli/internal/model"
 	"github.com/ggonzalez94/defi-cli/internal/providers"
 	"github.com/ggonzalez94/defi-cli/internal/registry"
+	"github.com/ggonzalez94/defi-cli/internal/rpcx"
 )
 
 type Client struct {
@@ -30,7 +31,7 @@ type Client struct {
 }
 
 func New(httpClient *httpx.Client) *Client {
-	return &Client{http: httpClient, baseURL: registry.LiFiBaseURL, now: time.Now}
+	return &Client{http: httpClient, baseURL: registry.LiFiBaseURL, now: clock.Now}
 }
 
 func (c *Client) Info() model.ProviderInfo {
@@ -43,6 +44,7 @@ func (c *Client) Info() model.ProviderInfo {
 			"bridge.plan",
 			"bridge.execute",
 		},
+		Support: providers.ChainSupport([]string{providers.AnyEVMChain}, "bridge.quote", "bridge.plan", "bridge.execute"),
 	}
 }
 
@@ -277,7 +279,7 @@ func (c *Client) BuildBridgeAction(ctx context.Context, req providers.BridgeQuot
 		if !common.IsHexAddress(resp.Estimate.ApprovalAddress) {
 			return execution.Action{}, clierr.New(clierr.CodeActionPlan, "lifi quote returned invalid approval address")
 		}
-		client, err := ethclient.DialContext(ctx, rpcURL)
+		client, err := rpcx.DialContext(ctx, rpcURL)
 		if err != nil {
 			return execution.Action{}, clierr.Wrap(clierr.CodeUnavailable, "connect source chain rpc for allowance check", err)
 		}
@@ -373,7 +375,7 @@ func shouldAddApproval(tokenAddr, spender string) bool {
 	if !common.IsHexAddress(tokenAddr) || !common.IsHexAddress(spender) {
 		return false
 	}
-	return !strings.EqualFold(strings.TrimSpace(tokenAddr), "0x0000000000000000000000000000000000000000")
+	return !isNativeTokenAddress(strings.TrimSpace(tokenAddr))
 }
 
 func destinationNativeEstimate(steps []quoteStep, destinationChainID int64) *model.AmountInfo {
@@ -229,6 +229,42 @@ func TestBuildBridgeActionSkipsApprovalWhenSpenderMissing(t *testing.T) {
 	}
 }
 
+func TestBuildBridgeActionSkipsApprovalForNativeInput(t *testing.T) {
+	quoteServer := newLiFiQuoteServer(t, "0x1231DeB6f5749EF6Ce6943a275A1D3E7486F4EaE")
+	defer quoteServer.Close()
+
+	c := New(httpx.New(2*time.Second, 0))
+	c.baseURL = quoteServer.URL
+
+	fromChain, _ := id.ParseChain("ethereum")
+	toChain, _ := id.ParseChain("base")
+	fromAsset, err := id.ParseAsset("ETH", fromChain)
+	if err != nil {
+		t.Fatalf("ParseAsset(ETH) failed: %v", err)
+	}
+	toAsset, _ := id.ParseAsset("USDC", toChain)
+
+	// The RPC endpoint is unreachable, so any allowance read would fail.
+	action, err := c.BuildBridgeAction(context.Background(), providers.BridgeQuoteRequest{
+		FromChain:       fromChain,
+		ToChain:         toChain,
+		FromAsset:       fromAsset,
+		ToAsset:         toAsset,
+		AmountBaseUnits: "1000000000000000000",
+		AmountDecimal:   "1",
+	}, providers.BridgeExecutionOptions{
+		Sender:   "0x00000000000000000000000000000000000000AA",
+		Simulate: true,
+		RPCURL:   "http://127.0.0.1:1",
+	})
+	if err != nil {
+		t.Fatalf("BuildBridgeAction failed: %v", err)
+	}
+	if len(action.Steps) != 1 || action.Steps[0].Type != "bridge_send" {
+		t.Fatalf("expected a bridge-only action for native input, got %+v", action.Steps)
+	}
+}
+
 func TestBuildBridgeActionAllowsNonCanonicalTransactionTargetAtPlanTime(t *testing.T) {
 	quoteServer := newLiFiQuoteServerWithTxTo(t, "", "0x1111111111111111111111111111111111111111")
 	defer quoteServer.Close()
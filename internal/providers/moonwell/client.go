@@ -15,39 +15,26 @@ import (
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ggonzalez94/defi-cli/internal/clock"
 	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
 	"github.com/ggonzalez94/defi-cli/internal/id"
 	"github.com/ggonzalez94/defi-cli/internal/model"
 	"github.com/ggonzalez94/defi-cli/internal/providers"
+	"github.com/ggonzalez94/defi-cli/internal/providers/multicall"
 	"github.com/ggonzalez94/defi-cli/internal/providers/yieldutil"
 	"github.com/ggonzalez94/defi-cli/internal/registry"
+	"github.com/ggonzalez94/defi-cli/internal/rpcx"
 )
 
 const secondsPerYear = 365.25 * 24 * 3600
 
-// Multicall3 is deployed at a standard address on all major EVM chains.
-var multicall3Addr = common.HexToAddress("0xcA11bde05977b3631167028862bE2a173976CA11")
-
-// multicall3Call matches Multicall3.Call3 struct.
-type multicall3Call struct {
-	Target       common.Address
-	AllowFailure bool
-	CallData     []byte
-}
-
-// multicall3Result matches Multicall3.Result struct.
-type multicall3Result struct {
-	Success    bool
-	ReturnData []byte
-}
-
 type Client struct {
 	now         func() time.Time
 	rpcOverride string // used in tests to point at a mock RPC server
 }
 
 func New() *Client {
-	return &Client{now: time.Now}
+	return &Client{now: clock.Now}
 }
 
 // SetRPCOverride sets the RPC URL used for on-chain reads (markets,
@@ -70,6 +57,7 @@ func (c *Client) Info() model.ProviderInfo {
 			"yield.plan",
 			"yield.execute",
 		},
+		Support: providers.ChainSupport(providers.EVMChains(registry.MoonwellChainIDs()), "lend.markets", "lend.rates", "lend.positions", "yield.opportunities", "yield.positions", "lend.plan", "lend.execute", "yield.plan", "yield.execute"),
 	}
 }
 
@@ -163,6 +151,8 @@ func (c *Client) LendRates(ctx context.Context, provider string, chain id.Chain,
 			ProviderNativeIDKind: model.NativeIDKindCompositeMarketAsset,
 			SupplyAPY:            m.SupplyAPY,
 			BorrowAPY:            m.BorrowAPY,
+			RateBasis:            yieldutil.RateBasisAPR,
+			Compounding:          yieldutil.CompoundingPerSecond,
 			Utilization:          m.Utilization,
 			SourceURL:            "https://moonwell.fi",
 			FetchedAt:            c.now().UTC().Format(time.RFC3339),
@@ -202,7 +192,7 @@ func (c *Client) LendPositions(ctx context.Context, req providers.LendPositionsR
 		return nil, clierr.New(clierr.CodeUnsupported, "moonwell is not supported on this chain")
 	}
 
-	client, err := ethclient.DialContext(ctx, rpcURL)
+	client, err := rpcx.DialContext(ctx, rpcURL)
 	if err != nil {
 		return nil, clierr.Wrap(clierr.CodeUnavailable, "connect rpc", err)
 	}
@@ -229,7 +219,7 @@ func (c *Client) LendPositions(ctx context.Context, req providers.LendPositionsR
 	// Per mToken: getAccountSnapshot, underlying, supplyRate, borrowRate, getUnderlyingPrice
 	// Per underlying: symbol, decimals (phase 2 after we know underlying addresses)
 	const posCallsPerMarket = 5 // snapshot, underlying, supplyRate, borrowRate, price
-	snapshotCalls := make([]multicall3Call, 0, len(allMarkets)*posCallsPerMarket)
+	snapshotCalls := make([]multicall.Call, 0, len(allMarkets)*posCallsPerMarket)
 	underlyingCD, _ := mTokenABI.Pack("underlying")
 	supplyRateCD, _ := mTokenABI.Pack("supplyRatePerTimestamp")
 	borrowRateCD, _ := mTokenABI.Pack("borrowRatePerTimestamp")
@@ -238,15 +228,15 @@ func (c *Client) LendPositions(ctx context.Context, req providers.LendPositionsR
 		snapshotCD, _ := mTokenABI.Pack("getAccountSnapshot", accountAddr)
 		priceCD, _ := oracleABI.Pack("getUnderlyingPrice", mt)
 		snapshotCalls = append(snapshotCalls,
-			multicall3Call{Target: mt, AllowFailure: true, CallData: snapshotCD},
-			multicall3Call{Target: mt, AllowFailure: true, CallData: underlyingCD},
-			multicall3Call{Target: mt, AllowFailure: true, CallData: supplyRateCD},
-			multicall3Call{Target: mt, AllowFailure: true, CallData: borrowRateCD},
-			multicall3Call{Target: oracleAddr, AllowFailure: true, CallData: priceCD},
+			multicall.Call{Target: mt, CallData: snapshotCD},
+			multicall.Call{Target: mt, CallData: underlyingCD},
+			multicall.Call{Target: mt, CallData: supplyRateCD},
+			multicall.Call{Target: mt, CallData: borrowRateCD},
+			multicall.Call{Target: oracleAddr, CallData: priceCD},
 		)
 	}
 
-	phase1Results, err := execMulticall3(ctx, client, snapshotCalls)
+	phase1Results, err := multicall.Aggregate3(ctx, client, snapshotCalls)
 	if err != nil {
 		return nil, clierr.Wrap(clierr.CodeUnavailable, "multicall positions", err)
 	}
@@ -319,15 +309,15 @@ func (c *Client) LendPositions(ctx context.Context, req providers.LendPositionsR
 	// Phase 2: get symbol + decimals for each underlying.
 	symbolCD, _ := erc20ABI.Pack("symbol")
 	decimalsCD, _ := erc20ABI.Pack("decimals")
-	phase2Calls := make([]multicall3Call, 0, len(posMarkets)*2)
+	phase2Calls := make([]multicall.Call, 0, len(posMarkets)*2)
 	for _, pm := range posMarkets {
 		phase2Calls = append(phase2Calls,
-			multicall3Call{Target: pm.underlying, AllowFailure: true, CallData: symbolCD},
-			multicall3Call{Target: pm.underlying, AllowFailure: true, CallData: decimalsCD},
+			multicall.Call{Target: pm.underlying, CallData: symbolCD},
+			multicall.Call{Target: pm.underlying, CallData: decimalsCD},
 		)
 	}
 
-	phase2Results, err := execMulticall3(ctx, client, phase2Calls)
+	phase2Results, err := multicall.Aggregate3(ctx, client, phase2Calls)
 	if err != nil {
 		return nil, clierr.Wrap(clierr.CodeUnavailable, "multicall position metadata", err)
 	}
@@ -466,10 +456,13 @@ func (c *Client) YieldOpportunities(ctx context.Context, req providers.YieldRequ
 			APYBase:              m.SupplyAPY,
 			APYReward:            0,
 			APYTotal:             m.SupplyAPY,
+			RateBasis:            yieldutil.RateBasisAPR,
+			Compounding:          yieldutil.CompoundingPerSecond,
 			TVLUSD:               m.TVLUSD,
 			LiquidityUSD:         m.LiquidityUSD,
 			LockupDays:           0,
 			WithdrawalTerms:      "variable",
+			ExitLiquidity:        yieldutil.ExitLiquidity(m.TVLUSD, m.LiquidityUSD, 0),
 			BackingAssets: []model.YieldBackingAsset{{
 				AssetID:  assetID,
 				Symbol:   m.UnderlyingSymbol,
@@ -483,6 +476,8 @@ func (c *Client) YieldOpportunities(ctx context.Context, req providers.YieldRequ
 	if len(out) == 0 {
 		return nil, clierr.New(clierr.CodeUnavailable, "no moonwell yield opportunities for requested chain/asset")
 	}
+	yieldutil.ApplyEffectiveAPY(out)
+	yieldutil.ApplyRisk(out)
 	yieldutil.Sort(out, req.SortBy)
 	if req.Limit <= 0 || req.Limit > len(out) {
 		req.Limit = len(out)
@@ -564,7 +559,7 @@ func (c *Client) fetchMarkets(ctx context.Context, chain id.Chain, rpcOverride s
 		return nil, "", clierr.New(clierr.CodeUnsupported, "moonwell is not supported on this chain")
 	}
 
-	client, err := ethclient.DialContext(ctx, rpcURL)
+	client, err := rpcx.DialContext(ctx, rpcURL)
 	if err != nil {
 		return nil, "", clierr.Wrap(clierr.CodeUnavailable, "connect rpc", err)
 	}
@@ -586,7 +581,7 @@ func (c *Client) fetchMarkets(ctx context.Context, chain id.Chain, rpcOverride s
 	}
 
 	// 2. Phase 1 multicall: per-mToken data (underlying, rates, supply, exchange, borrows, cash, price).
-	phase1Calls := make([]multicall3Call, 0, len(mTokens)*callsPerMarketPhase1)
+	phase1Calls := make([]multicall.Call, 0, len(mTokens)*callsPerMarketPhase1)
 	underlyingCD, _ := mTokenABI.Pack("underlying")
 	supplyRateCD, _ := mTokenABI.Pack("supplyRatePerTimestamp")
 	borrowRateCD, _ := mTokenABI.Pack("borrowRatePerTimestamp")
@@ -598,18 +593,18 @@ func (c *Client) fetchMarkets(ctx context.Context, chain id.Chain, rpcOverride s
 	for _, mt := range mTokens {
 		priceCD, _ := oracleABI.Pack("getUnderlyingPrice", mt)
 		phase1Calls = append(phase1Calls,
-			multicall3Call{Target: mt, AllowFailure: true, CallData: underlyingCD},
-			multicall3Call{Target: mt, AllowFailure: true, CallData: supplyRateCD},
-			multicall3Call{Target: mt, AllowFailure: true, CallData: borrowRateCD},
-			multicall3Call{Target: mt, AllowFailure: true, CallData: totalSupplyCD},
-			multicall3Call{Target: mt, AllowFailure: true, CallData: exchangeRateCD},
-			multicall3Call{Target: mt, AllowFailure: true, CallData: totalBorrowsCD},
-			multicall3Call{Target: mt, AllowFailure: true, CallData: getCashCD},
-			multicall3Call{Target: oracleAddr, AllowFailure: true, CallData: priceCD},
+			multicall.Call{Target: mt, CallData: underlyingCD},
+			multicall.Call{Target: mt, CallData: supplyRateCD},
+			multicall.Call{Target: mt, CallData: borrowRateCD},
+			multicall.Call{Target: mt, CallData: totalSupplyCD},
+			multicall.Call{Target: mt, CallData: exchangeRateCD},
+			multicall.Call{Target: mt, CallData: totalBorrowsCD},
+			multicall.Call{Target: mt, CallData: getCashCD},
+			multicall.Call{Target: oracleAddr, CallData: priceCD},
 		)
 	}
 
-	phase1Results, err := execMulticall3(ctx, client, phase1Calls)
+	phase1Results, err := multicall.Aggregate3(ctx, client, phase1Calls)
 	if err != nil {
 		return nil, "", clierr.Wrap(clierr.CodeUnavailable, "multicall market data", err)
 	}
@@ -674,15 +669,15 @@ func (c *Client) fetchMarkets(ctx context.Context, chain id.Chain, rpcOverride s
 	symbolCD, _ := erc20ABI.Pack("symbol")
 	decimalsCD, _ := erc20ABI.Pack("decimals")
 
-	phase2Calls := make([]multicall3Call, 0, len(p1Parsed)*callsPerMarketPhase2)
+	phase2Calls := make([]multicall.Call, 0, len(p1Parsed)*callsPerMarketPhase2)
 	for _, p := range p1Parsed {
 		phase2Calls = append(phase2Calls,
-			multicall3Call{Target: p.underlying, AllowFailure: true, CallData: symbolCD},
-			multicall3Call{Target: p.underlying, AllowFailure: true, CallData: decimalsCD},
+			multicall.Call{Target: p.underlying, CallData: symbolCD},
+			multicall.Call{Target: p.underlying, CallData: decimalsCD},
 		)
 	}
 
-	phase2Results, err := execMulticall3(ctx, client, phase2Calls)
+	phase2Results, err := multicall.Aggregate3(ctx, client, phase2Calls)
 	if err != nil {
 		return nil, "", clierr.Wrap(clierr.CodeUnavailable, "multicall token metadata", err)
 	}
@@ -746,7 +741,7 @@ func (c *Client) fetchMarkets(ctx context.Context, chain id.Chain, rpcOverride s
 }
 
 // decodeUint256Result decodes a single uint256 from a multicall result.
-func decodeUint256Result(r multicall3Result, a abi.ABI, method string) *big.Int {
+func decodeUint256Result(r multicall.Result, a abi.ABI, method string) *big.Int {
 	if !r.Success || len(r.ReturnData) < 32 {
 		return new(big.Int)
 	}
@@ -774,63 +769,6 @@ func mantissaToUSD(priceMantissa *big.Int, underlyingDecimals int) float64 {
 	return result
 }
 
-// execMulticall3 batches multiple contract calls into a single Multicall3.aggregate3 RPC call.
-func execMulticall3(ctx context.Context, client *ethclient.Client, calls []multicall3Call) ([]multicall3Result, error) {
-	if len(calls) == 0 {
-		return nil, nil
-	}
-
-	// Build the aggregate3 input as a tuple array.
-	type call3Tuple struct {
-		Target       common.Address `abi:"target"`
-		AllowFailure bool           `abi:"allowFailure"`
-		CallData     []byte         `abi:"callData"`
-	}
-	tuples := make([]call3Tuple, len(calls))
-	for i, c := range calls {
-		tuples[i] = call3Tuple{Target: c.Target, AllowFailure: c.AllowFailure, CallData: c.CallData}
-	}
-
-	data, err := mc3ABI.Pack("aggregate3", tuples)
-	if err != nil {
-		return nil, fmt.Errorf("pack aggregate3: %w", err)
-	}
-
-	mc3 := multicall3Addr
-	out, err := client.CallContract(ctx, ethereum.CallMsg{To: &mc3, Data: data}, nil)
-	if err != nil {
-		return nil, fmt.Errorf("call aggregate3: %w", err)
-	}
-
-	decoded, err := mc3ABI.Unpack("aggregate3", out)
-	if err != nil {
-		return nil, fmt.Errorf("decode aggregate3: %w", err)
-	}
-	if len(decoded) == 0 {
-		return nil, fmt.Errorf("empty aggregate3 response")
-	}
-
-	// decoded[0] is []struct{Success bool; ReturnData []byte}
-	type resultTuple struct {
-		Success    bool   `abi:"success"`
-		ReturnData []byte `abi:"returnData"`
-	}
-
-	// The ABI decoder returns a slice of anonymous structs.
-	rawResults, ok := decoded[0].([]struct {
-		Success    bool   `json:"success"`
-		ReturnData []byte `json:"returnData"`
-	})
-	if !ok {
-		return nil, fmt.Errorf("unexpected aggregate3 result type: %T", decoded[0])
-	}
-
-	results := make([]multicall3Result, len(rawResults))
-	for i, r := range rawResults {
-		results[i] = multicall3Result{Success: r.Success, ReturnData: r.ReturnData}
-	}
-	return results, nil
-}
 
 // ── RPC call helpers ────────────────────────────────────────────────────
 
@@ -904,16 +842,13 @@ func rateToAPY(ratePerTimestamp *big.Int) float64 {
 	if ratePerTimestamp == nil || ratePerTimestamp.Sign() == 0 {
 		return 0
 	}
-	// APY ≈ ratePerSecond * secondsPerYear / 1e18 * 100  (linear approximation)
+	// APY ≈ ratePerSecond * secondsPerYear / 1e18 * 100  (linear approximation,
+	// so the result is an APR; rates are marked rate_basis apr)
 	rateFloat := new(big.Float).SetInt(ratePerTimestamp)
 	rateFloat.Mul(rateFloat, big.NewFloat(secondsPerYear))
 	rateFloat.Quo(rateFloat, big.NewFloat(1e18))
-	rateFloat.Mul(rateFloat, big.NewFloat(100))
-	result, _ := rateFloat.Float64()
-	if math.IsNaN(result) || math.IsInf(result, 0) {
-		return 0
-	}
-	return result
+	ratio, _ := rateFloat.Float64()
+	return yieldutil.PercentFromRatio(ratio)
 }
 
 func bigIntToFloat(v *big.Int, decimals int) float64 {
@@ -1034,7 +969,6 @@ var comptrollerABI = mustABI(registry.MoonwellComptrollerABI)
 var mTokenABI = mustABI(registry.MoonwellMTokenABI)
 var oracleABI = mustABI(registry.MoonwellOracleABI)
 var erc20ABI = mustABI(registry.MoonwellERC20MinimalABI)
-var mc3ABI = mustABI(registry.Multicall3ABI)
 
 func mustABI(raw string) abi.ABI {
 	parsed, err := abi.JSON(strings.NewReader(raw))
@@ -16,6 +16,7 @@ import (
 	"github.com/ggonzalez94/defi-cli/internal/id"
 	"github.com/ggonzalez94/defi-cli/internal/model"
 	"github.com/ggonzalez94/defi-cli/internal/providers"
+	"github.com/ggonzalez94/defi-cli/internal/providers/multicall"
 	"github.com/ggonzalez94/defi-cli/internal/registry"
 )
 
@@ -164,7 +165,7 @@ func newTestRPCServer(t *testing.T) *httptest.Server {
 	}
 	oABI, _ := abi.JSON(strings.NewReader(registry.MoonwellOracleABI))
 	oSel := selectorHex(oABI, "getUnderlyingPrice")
-	mc3Sel := selectorHex(mc3ABI, "aggregate3")
+	mc3Sel := selectorHex(multicall.ABI, "aggregate3")
 
 	supplyRate := big.NewInt(951293759)
 	borrowRate := big.NewInt(1585489599)
@@ -206,9 +207,9 @@ func newTestRPCServer(t *testing.T) *httptest.Server {
 		to := strings.ToLower(toHex)
 
 		// Handle Multicall3.aggregate3 — decode Call3[], dispatch each, re-encode Result[].
-		if to == strings.ToLower(multicall3Addr.Hex()) && selector == mc3Sel {
+		if to == strings.ToLower(registry.Multicall3Address) && selector == mc3Sel {
 			rawData, _ := hex.DecodeString(dataHex)
-			decoded, err := mc3ABI.Methods["aggregate3"].Inputs.Unpack(rawData[4:])
+			decoded, err := multicall.ABI.Methods["aggregate3"].Inputs.Unpack(rawData[4:])
 			if err != nil {
 				t.Logf("aggregate3 unpack error: %v", err)
 				json.NewEncoder(w).Encode(jsonRPCResponse{JSONRPC: "2.0", ID: req.ID, Result: "0x"})
@@ -234,7 +235,7 @@ func newTestRPCServer(t *testing.T) *httptest.Server {
 			}
 
 			// Encode as aggregate3 output: tuple[](bool success, bytes returnData)
-			encoded, err := mc3ABI.Methods["aggregate3"].Outputs.Pack(results)
+			encoded, err := multicall.ABI.Methods["aggregate3"].Outputs.Pack(results)
 			if err != nil {
 				t.Logf("aggregate3 pack error: %v", err)
 				json.NewEncoder(w).Encode(jsonRPCResponse{JSONRPC: "2.0", ID: req.ID, Result: "0x"})
@@ -0,0 +1,70 @@
+package morpho
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"time"
+
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/model"
+	"github.com/ggonzalez94/defi-cli/internal/providers"
+	"github.com/ggonzalez94/defi-cli/internal/providers/yieldutil"
+)
+
+// BorrowQuotes returns one venue per Morpho Blue market that lends req.Asset
+// against req.Collateral, or against any collateral when it is unset. A
+// market borrows up to its LLTV, so the max and liquidation LTV are equal.
+func (c *Client) BorrowQuotes(ctx context.Context, req providers.BorrowQuoteRequest) ([]model.BorrowQuote, error) {
+	markets, err := c.fetchMarkets(ctx, req.Chain, req.Asset)
+	if err != nil {
+		return nil, err
+	}
+	fetchedAt := c.now().UTC().Format(time.RFC3339)
+	out := []model.BorrowQuote{}
+	for _, m := range markets {
+		if m.CollateralAsset == nil || normalizeEVMAddress(m.CollateralAsset.Address) == "" {
+			continue
+		}
+		if strings.TrimSpace(req.Collateral.AssetID) != "" && !matchesCollateral(m.CollateralAsset.Address, m.CollateralAsset.Symbol, req.Collateral.Address, req.Collateral.Symbol) {
+			continue
+		}
+		lltv := lltvPercent(m.LLTV)
+		out = append(out, model.BorrowQuote{
+			Protocol:              "morpho",
+			Provider:              "morpho",
+			ChainID:               req.Chain.CAIP2,
+			AssetID:               canonicalAssetID(req.Asset, m.LoanAsset.Address),
+			CollateralAssetID:     canonicalAssetIDForChain(req.Chain.CAIP2, m.CollateralAsset.Address),
+			ProviderNativeID:      strings.TrimSpace(m.UniqueKey),
+			ProviderNativeIDKind:  model.NativeIDKindMarketID,
+			BorrowAPY:             yieldutil.PercentFromRatio(m.State.BorrowAPY),
+			AvailableLiquidityUSD: yieldutil.PositiveFirst(m.State.LiquidityAssetsUSD, m.State.TotalLiquidityUSD),
+			MaxLTVPct:             lltv,
+			LiquidationLTVPct:     lltv,
+			SourceURL:             "https://app.morpho.org",
+			FetchedAt:             fetchedAt,
+		})
+	}
+	if len(out) == 0 {
+		return nil, clierr.New(clierr.CodeUnsupported, "no morpho borrow market for requested chain/asset/collateral")
+	}
+	return out, nil
+}
+
+func matchesCollateral(address, symbol, wantAddress, wantSymbol string) bool {
+	if strings.TrimSpace(wantAddress) != "" {
+		return strings.EqualFold(strings.TrimSpace(address), strings.TrimSpace(wantAddress))
+	}
+	return strings.EqualFold(strings.TrimSpace(symbol), strings.TrimSpace(wantSymbol))
+}
+
+// lltvPercent converts a WAD-scaled LLTV to percent.
+func lltvPercent(lltv bigintString) float64 {
+	value, ok := new(big.Float).SetString(lltv.normalized())
+	if !ok {
+		return 0
+	}
+	ratio, _ := new(big.Float).Quo(value, big.NewFloat(1e18)).Float64()
+	return yieldutil.PercentFromRatio(ratio)
+}
@@ -12,6 +12,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/ggonzalez94/defi-cli/internal/clock"
 	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
 	"github.com/ggonzalez94/defi-cli/internal/httpx"
 	"github.com/ggonzalez94/defi-cli/internal/id"
@@ -24,13 +25,14 @@ import (
 const defaultEndpoint = registry.MorphoGraphQLEndpoint
 
 type Client struct {
-	http     *httpx.Client
-	endpoint string
-	now      func() time.Time
+	http          *httpx.Client
+	endpoint      string
+	merklEndpoint string
+	now           func() time.Time
 }
 
 func New(httpClient *httpx.Client) *Client {
-	return &Client{http: httpClient, endpoint: defaultEndpoint, now: time.Now}
+	return &Client{http: httpClient, endpoint: defaultEndpoint, merklEndpoint: registry.MerklAPIBaseURL, now: clock.Now}
 }
 
 func (c *Client) Info() model.ProviderInfo {
@@ -42,6 +44,7 @@ func (c *Client) Info() model.ProviderInfo {
 			"lend.markets",
 			"lend.rates",
 			"lend.positions",
+			"lend.borrow_quote",
 			"yield.opportunities",
 			"yield.positions",
 			"yield.history",
@@ -49,6 +52,9 @@ func (c *Client) Info() model.ProviderInfo {
 			"lend.execute",
 			"yield.plan",
 			"yield.execute",
+			"rewards.claimable",
+			"rewards.plan",
+			"rewards.execute",
 		},
 	}
 }
@@ -59,6 +65,7 @@ const marketsQuery = `query Markets($first:Int,$where:MarketFilters,$orderBy:Mar
       id
       uniqueKey
       irmAddress
+      lltv
       loanAsset{ address symbol decimals chain{ id network } }
       collateralAsset{ address symbol }
       state{ supplyApy borrowApy utilization supplyAssetsUsd liquidityAssetsUsd totalLiquidityUsd }
@@ -281,9 +288,10 @@ type morphoFloatDataPoint struct {
 }
 
 type morphoMarket struct {
-	ID         string `json:"id"`
-	UniqueKey  string `json:"uniqueKey"`
-	IRMAddress string `json:"irmAddress"`
+	ID         string       `json:"id"`
+	UniqueKey  string       `json:"uniqueKey"`
+	IRMAddress string       `json:"irmAddress"`
+	LLTV       bigintString `json:"lltv"`
 	LoanAsset  struct {
 		Address  string `json:"address"`
 		Symbol   string `json:"symbol"`
@@ -461,8 +469,8 @@ func (c *Client) LendMarkets(ctx context.Context, provider string, chain id.Chai
 		if tvl <= 0 {
 			continue
 		}
-		supplyAPY := m.State.SupplyAPY * 100
-		borrowAPY := m.State.BorrowAPY * 100
+		supplyAPY := yieldutil.PercentFromRatio(m.State.SupplyAPY)
+		borrowAPY := yieldutil.PercentFromRatio(m.State.BorrowAPY)
 		out = append(out, model.LendMarket{
 			Protocol:             "morpho",
 			Provider:             "morpho",
@@ -509,8 +517,10 @@ func (c *Client) LendRates(ctx context.Context, provider string, chain id.Chain,
 			AssetID:              canonicalAssetID(asset, m.LoanAsset.Address),
 			ProviderNativeID:     strings.TrimSpace(m.UniqueKey),
 			ProviderNativeIDKind: model.NativeIDKindMarketID,
-			SupplyAPY:            m.State.SupplyAPY * 100,
-			BorrowAPY:            m.State.BorrowAPY * 100,
+			SupplyAPY:            yieldutil.PercentFromRatio(m.State.SupplyAPY),
+			BorrowAPY:            yieldutil.PercentFromRatio(m.State.BorrowAPY),
+			RateBasis:            yieldutil.RateBasisAPY,
+			Compounding:          yieldutil.CompoundingContinuous,
 			Utilization:          m.State.Utilization,
 			SourceURL:            "https://app.morpho.org",
 			FetchedAt:            c.now().UTC().Format(time.RFC3339),
@@ -587,7 +597,7 @@ func (c *Client) LendPositions(ctx context.Context, req providers.LendPositionsR
 				if base != "0" {
 					supplyAPY := 0.0
 					if item.Market.State != nil {
-						supplyAPY = item.Market.State.SupplyAPY * 100
+						supplyAPY = yieldutil.PercentFromRatio(item.Market.State.SupplyAPY)
 					}
 					out = append(out, model.LendPosition{
 						Protocol:             "morpho",
@@ -613,7 +623,7 @@ func (c *Client) LendPositions(ctx context.Context, req providers.LendPositionsR
 				if base != "0" {
 					borrowAPY := 0.0
 					if item.Market.State != nil {
-						borrowAPY = item.Market.State.BorrowAPY * 100
+						borrowAPY = yieldutil.PercentFromRatio(item.Market.State.BorrowAPY)
 					}
 					out = append(out, model.LendPosition{
 						Protocol:             "morpho",
@@ -734,7 +744,7 @@ func (c *Client) YieldPositions(ctx context.Context, req providers.YieldPosition
 		}
 		apyTotal := 0.0
 		if item.Vault.State != nil {
-			apyTotal = item.Vault.State.NetAPY * 100
+			apyTotal = yieldutil.PercentFromRatio(item.Vault.State.NetAPY)
 		}
 		out = append(out, model.YieldPosition{
 			Protocol:             "morpho",
@@ -797,10 +807,13 @@ func (c *Client) YieldOpportunities(ctx context.Context, req providers.YieldRequ
 			APYBase:              apy,
 			APYReward:            0,
 			APYTotal:             apy,
+			RateBasis:            yieldutil.RateBasisAPY,
+			Compounding:          yieldutil.CompoundingContinuous,
 			TVLUSD:               tvl,
 			LiquidityUSD:         liq,
 			LockupDays:           0,
 			WithdrawalTerms:      "variable",
+			ExitLiquidity:        yieldutil.ExitLiquidity(tvl, liq, 0),
 			BackingAssets:        backingAssets,
 			SourceURL:            sourceURLForVault(vaultAddress),
 			FetchedAt:            c.now().UTC().Format(time.RFC3339),
@@ -810,6 +823,8 @@ func (c *Client) YieldOpportunities(ctx context.Context, req providers.YieldRequ
 	if len(out) == 0 {
 		return nil, clierr.New(clierr.CodeUnavailable, "no morpho yield opportunities for requested chain/asset")
 	}
+	yieldutil.ApplyEffectiveAPY(out)
+	yieldutil.ApplyRisk(out)
 	yieldutil.Sort(out, req.SortBy)
 	if req.Limit <= 0 || req.Limit > len(out) {
 		req.Limit = len(out)
@@ -938,7 +953,7 @@ func (c *Client) fetchYieldVaultCandidates(ctx context.Context, chain id.Chain,
 		netAPY := 0.0
 		tvl := 0.0
 		if vault.State != nil {
-			netAPY = vault.State.NetAPY * 100
+			netAPY = yieldutil.PercentFromRatio(vault.State.NetAPY)
 			tvl = vault.State.TotalAssetsUSD
 		}
 		liquidity := 0.0
@@ -969,7 +984,7 @@ func (c *Client) fetchYieldVaultCandidates(ctx context.Context, chain id.Chain,
 			Address:        vault.Address,
 			AssetAddress:   assetAddress,
 			AssetSymbol:    assetSymbol,
-			NetAPYPercent:  vault.NetAPY * 100,
+			NetAPYPercent:  yieldutil.PercentFromRatio(vault.NetAPY),
 			TotalAssetsUSD: vault.TotalAssets,
 			LiquidityUSD:   vault.LiquidityUSD,
 			BackingShares:  collateralSharesFromVaultV2(vault, assetAddress, assetSymbol),
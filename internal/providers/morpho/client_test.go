@@ -612,3 +612,123 @@ func TestYieldHistoryFallsBackToVaultV2(t *testing.T) {
 		t.Fatalf("expected v2 apy value 4, got %+v", series[0].Points[0])
 	}
 }
+
+func TestClaimableRewardsFromMerkl(t *testing.T) {
+	const account = "0x00000000000000000000000000000000000000aa"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/users/"+account+"/rewards" || r.URL.Query().Get("chainId") != "8453" {
+			t.Errorf("unexpected request %s", r.URL.String())
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{
+			"chain": {"id": 8453},
+			"rewards": [
+				{
+					"amount": "3000000000000000000",
+					"claimed": "1000000000000000000",
+					"proofs": ["0x1111111111111111111111111111111111111111111111111111111111111111"],
+					"token": {"address": "0xBAa5CC21fd487B8Fcc2F632f3F4E8D37262a0842", "symbol": "MORPHO", "decimals": 18, "price": 1.5}
+				},
+				{
+					"amount": "500",
+					"claimed": "500",
+					"proofs": ["0x2222222222222222222222222222222222222222222222222222222222222222"],
+					"token": {"address": "0x833589fcd6edb6e08f4c7c32d4f71b54bda02913", "symbol": "USDC", "decimals": 6, "price": 1}
+				}
+			]
+		}]`))
+	}))
+	defer srv.Close()
+
+	client := New(httpx.New(2*time.Second, 0))
+	client.merklEndpoint = srv.URL
+	chain, _ := id.ParseChain("base")
+
+	rewards, err := client.ClaimableRewards(context.Background(), providers.RewardsClaimableRequest{Chain: chain, Account: account})
+	if err != nil {
+		t.Fatalf("ClaimableRewards failed: %v", err)
+	}
+	if len(rewards) != 1 {
+		t.Fatalf("expected fully claimed reward to be skipped, got %+v", rewards)
+	}
+	reward := rewards[0]
+	if reward.Symbol != "MORPHO" || reward.Claimable.AmountBaseUnits != "2000000000000000000" || reward.Claimable.AmountDecimal != "2" {
+		t.Fatalf("unexpected claimable amount: %+v", reward)
+	}
+	if reward.CumulativeAmount != "3000000000000000000" || len(reward.Proofs) != 1 {
+		t.Fatalf("expected cumulative amount and proofs for the claim, got %+v", reward)
+	}
+	if reward.ClaimableUSD != 3 {
+		t.Fatalf("expected claimable usd 3, got %v", reward.ClaimableUSD)
+	}
+	if reward.RewardAssetID != "eip155:8453/erc20:0xbaa5cc21fd487b8fcc2f632f3f4e8d37262a0842" {
+		t.Fatalf("unexpected reward asset id: %s", reward.RewardAssetID)
+	}
+}
+
+func TestBorrowQuotesFiltersCollateral(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"data": {
+				"markets": {
+					"items": [
+						{
+							"uniqueKey": "weth-usdc",
+							"lltv": "860000000000000000",
+							"loanAsset": {"address": "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48", "symbol": "USDC", "decimals": 6},
+							"collateralAsset": {"address": "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2", "symbol": "WETH"},
+							"state": {"borrowApy": 0.045, "liquidityAssetsUsd": 2500000}
+						},
+						{
+							"uniqueKey": "wbtc-usdc",
+							"lltv": "915000000000000000",
+							"loanAsset": {"address": "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48", "symbol": "USDC", "decimals": 6},
+							"collateralAsset": {"address": "0x2260fac5e5542a773aa44fbcfedf7c193bc2c599", "symbol": "WBTC"},
+							"state": {"borrowApy": 0.04, "liquidityAssetsUsd": 900000}
+						},
+						{
+							"uniqueKey": "idle-usdc",
+							"lltv": "0",
+							"loanAsset": {"address": "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48", "symbol": "USDC", "decimals": 6},
+							"state": {"borrowApy": 0, "liquidityAssetsUsd": 100}
+						}
+					]
+				}
+			}
+		}`))
+	}))
+	defer srv.Close()
+
+	client := New(httpx.New(2*time.Second, 0))
+	client.endpoint = srv.URL
+	chain, _ := id.ParseChain("ethereum")
+	asset, _ := id.ParseAsset("USDC", chain)
+	collateral, _ := id.ParseAsset("WETH", chain)
+
+	quotes, err := client.BorrowQuotes(context.Background(), providers.BorrowQuoteRequest{Chain: chain, Asset: asset, Collateral: collateral})
+	if err != nil {
+		t.Fatalf("BorrowQuotes failed: %v", err)
+	}
+	if len(quotes) != 1 {
+		t.Fatalf("expected only the WETH market, got %+v", quotes)
+	}
+	quote := quotes[0]
+	if quote.ProviderNativeID != "weth-usdc" || quote.BorrowAPY != 4.5 || quote.AvailableLiquidityUSD != 2500000 {
+		t.Fatalf("unexpected quote: %+v", quote)
+	}
+	if quote.MaxLTVPct != 86 || quote.LiquidationLTVPct != 86 {
+		t.Fatalf("expected LLTV of 86%%, got %+v", quote)
+	}
+	if quote.CollateralAssetID != "eip155:1/erc20:0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2" {
+		t.Fatalf("unexpected collateral asset id: %s", quote.CollateralAssetID)
+	}
+
+	all, err := client.BorrowQuotes(context.Background(), providers.BorrowQuoteRequest{Chain: chain, Asset: asset})
+	if err != nil {
+		t.Fatalf("BorrowQuotes without collateral failed: %v", err)
+	}
+	if len(all) != 2 {
+		t.Fatalf("expected markets with collateral only, got %+v", all)
+	}
+}
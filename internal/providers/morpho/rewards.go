@@ -0,0 +1,118 @@
+package morpho
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/model"
+	"github.com/ggonzalez94/defi-cli/internal/providers"
+	"github.com/ggonzalez94/defi-cli/internal/registry"
+)
+
+type merklChainRewards struct {
+	Chain struct {
+		ID int64 `json:"id"`
+	} `json:"chain"`
+	Rewards []merklReward `json:"rewards"`
+}
+
+type merklReward struct {
+	Amount  string   `json:"amount"`
+	Claimed string   `json:"claimed"`
+	Proofs  []string `json:"proofs"`
+	Token   struct {
+		Address  string  `json:"address"`
+		Symbol   string  `json:"symbol"`
+		Decimals int     `json:"decimals"`
+		Price    float64 `json:"price"`
+	} `json:"token"`
+}
+
+// ClaimableRewards lists the account's unclaimed Merkl rewards on the chain.
+// Morpho distributes incentives through Merkl, so this covers every Merkl
+// campaign of the account, not only Morpho vaults and markets.
+func (c *Client) ClaimableRewards(ctx context.Context, req providers.RewardsClaimableRequest) ([]model.ClaimableReward, error) {
+	if !req.Chain.IsEVM() {
+		return nil, clierr.New(clierr.CodeUnsupported, "morpho rewards support only EVM chains")
+	}
+	account := normalizeEVMAddress(req.Account)
+	if account == "" {
+		return nil, clierr.New(clierr.CodeUsage, "morpho rewards requires a valid EVM account address")
+	}
+	distributor, ok := registry.MerklDistributor(req.Chain.EVMChainID)
+	if !ok {
+		return nil, clierr.New(clierr.CodeUnsupported, "merkl rewards are not supported on this chain")
+	}
+
+	endpoint := fmt.Sprintf("%s/users/%s/rewards?chainId=%s", strings.TrimRight(c.merklEndpoint, "/"), url.PathEscape(account), strconv.FormatInt(req.Chain.EVMChainID, 10))
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, clierr.Wrap(clierr.CodeInternal, "build merkl rewards request", err)
+	}
+	var resp []merklChainRewards
+	if _, err := c.http.DoJSON(ctx, httpReq, &resp); err != nil {
+		return nil, err
+	}
+
+	fetchedAt := c.now().UTC().Format(time.RFC3339)
+	out := []model.ClaimableReward{}
+	for _, chainRewards := range resp {
+		if chainRewards.Chain.ID != 0 && chainRewards.Chain.ID != req.Chain.EVMChainID {
+			continue
+		}
+		for _, reward := range chainRewards.Rewards {
+			claimable, ok := unclaimed(reward.Amount, reward.Claimed)
+			token := normalizeEVMAddress(reward.Token.Address)
+			if !ok || token == "" || len(reward.Proofs) == 0 {
+				continue
+			}
+			decimals := reward.Token.Decimals
+			row := model.ClaimableReward{
+				Provider:      "morpho",
+				ChainID:       req.Chain.CAIP2,
+				Account:       account,
+				RewardAssetID: canonicalAssetIDForChain(req.Chain.CAIP2, token),
+				Symbol:        reward.Token.Symbol,
+				Claimable: model.AmountInfo{
+					AmountBaseUnits: claimable.String(),
+					AmountDecimal:   id.FormatDecimalCompat(claimable.String(), decimals),
+					Decimals:        decimals,
+				},
+				Distributor:      distributor,
+				CumulativeAmount: reward.Amount,
+				Proofs:           reward.Proofs,
+				SourceURL:        "https://app.merkl.xyz/users/" + account,
+				FetchedAt:        fetchedAt,
+			}
+			if reward.Token.Price > 0 {
+				amount, _ := strconv.ParseFloat(row.Claimable.AmountDecimal, 64)
+				row.ClaimableUSD = amount * reward.Token.Price
+			}
+			out = append(out, row)
+		}
+	}
+	return out, nil
+}
+
+// unclaimed returns amount minus claimed when positive. Merkl amounts are
+// cumulative, so the claim transfers only the difference.
+func unclaimed(amount, claimed string) (*big.Int, bool) {
+	total, ok := new(big.Int).SetString(strings.TrimSpace(amount), 10)
+	if !ok {
+		return nil, false
+	}
+	done, ok := new(big.Int).SetString(strings.TrimSpace(claimed), 10)
+	if !ok {
+		done = new(big.Int)
+	}
+	diff := new(big.Int).Sub(total, done)
+	return diff, diff.Sign() > 0
+}
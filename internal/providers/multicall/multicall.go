@@ -0,0 +1,97 @@
+// Package multicall batches read-only contract calls into one
+// Multicall3.aggregate3 eth_call. Multicall3 is deployed at the same address
+// on all major EVM chains.
+package multicall
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ggonzalez94/defi-cli/internal/registry"
+)
+
+// ABI is the parsed Multicall3 ABI. Provider tests use it to decode and
+// answer aggregate3 requests in mock RPC servers.
+var ABI = mustABI(registry.Multicall3ABI)
+
+var address = common.HexToAddress(registry.Multicall3Address)
+
+// Call matches the target and calldata of Multicall3.Call3. Every call is
+// sent with allowFailure set, so one reverting call does not fail the batch.
+type Call struct {
+	Target   common.Address
+	CallData []byte
+}
+
+// Result matches Multicall3.Result. Callers check Success before decoding.
+type Result struct {
+	Success    bool
+	ReturnData []byte
+}
+
+// Caller is the part of an RPC client Aggregate3 needs; *ethclient.Client
+// satisfies it.
+type Caller interface {
+	CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error)
+}
+
+// Aggregate3 runs calls in one eth_call and returns one result per call, in
+// order. It returns nil for an empty batch without calling the node.
+func Aggregate3(ctx context.Context, client Caller, calls []Call) ([]Result, error) {
+	if len(calls) == 0 {
+		return nil, nil
+	}
+	type call3Tuple struct {
+		Target       common.Address `abi:"target"`
+		AllowFailure bool           `abi:"allowFailure"`
+		CallData     []byte         `abi:"callData"`
+	}
+	tuples := make([]call3Tuple, len(calls))
+	for i, call := range calls {
+		tuples[i] = call3Tuple{Target: call.Target, AllowFailure: true, CallData: call.CallData}
+	}
+	data, err := ABI.Pack("aggregate3", tuples)
+	if err != nil {
+		return nil, fmt.Errorf("pack aggregate3: %w", err)
+	}
+	target := address
+	out, err := client.CallContract(ctx, ethereum.CallMsg{To: &target, Data: data}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("call aggregate3: %w", err)
+	}
+	decoded, err := ABI.Unpack("aggregate3", out)
+	if err != nil {
+		return nil, fmt.Errorf("decode aggregate3: %w", err)
+	}
+	if len(decoded) == 0 {
+		return nil, fmt.Errorf("empty aggregate3 response")
+	}
+	raw, ok := decoded[0].([]struct {
+		Success    bool   `json:"success"`
+		ReturnData []byte `json:"returnData"`
+	})
+	if !ok {
+		return nil, fmt.Errorf("unexpected aggregate3 result type: %T", decoded[0])
+	}
+	if len(raw) != len(calls) {
+		return nil, fmt.Errorf("aggregate3 returned %d results for %d calls", len(raw), len(calls))
+	}
+	results := make([]Result, len(raw))
+	for i, r := range raw {
+		results[i] = Result{Success: r.Success, ReturnData: r.ReturnData}
+	}
+	return results, nil
+}
+
+func mustABI(raw string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(raw))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
@@ -0,0 +1,95 @@
+package multicall
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ggonzalez94/defi-cli/internal/registry"
+)
+
+type resultTuple struct {
+	Success    bool   `abi:"success"`
+	ReturnData []byte `abi:"returnData"`
+}
+
+// fakeCaller answers aggregate3 with results, recording every request.
+type fakeCaller struct {
+	results []resultTuple
+	err     error
+	msgs    []ethereum.CallMsg
+}
+
+func (f *fakeCaller) CallContract(_ context.Context, msg ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	f.msgs = append(f.msgs, msg)
+	if f.err != nil {
+		return nil, f.err
+	}
+	return ABI.Methods["aggregate3"].Outputs.Pack(f.results)
+}
+
+func TestAggregate3(t *testing.T) {
+	first := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	second := common.HexToAddress("0x00000000000000000000000000000000000000bb")
+	caller := &fakeCaller{results: []resultTuple{{Success: true, ReturnData: []byte{1}}, {Success: false}}}
+
+	results, err := Aggregate3(context.Background(), caller, []Call{
+		{Target: first, CallData: []byte{0xaa}},
+		{Target: second, CallData: []byte{0xbb}},
+	})
+	if err != nil {
+		t.Fatalf("Aggregate3 failed: %v", err)
+	}
+	if len(results) != 2 || !results[0].Success || !bytes.Equal(results[0].ReturnData, []byte{1}) || results[1].Success {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+
+	if len(caller.msgs) != 1 || caller.msgs[0].To == nil || !strings.EqualFold(caller.msgs[0].To.Hex(), registry.Multicall3Address) {
+		t.Fatalf("expected one call to Multicall3, got %+v", caller.msgs)
+	}
+	decoded, err := ABI.Methods["aggregate3"].Inputs.Unpack(caller.msgs[0].Data[4:])
+	if err != nil {
+		t.Fatalf("decode request: %v", err)
+	}
+	calls := decoded[0].([]struct {
+		Target       common.Address `json:"target"`
+		AllowFailure bool           `json:"allowFailure"`
+		CallData     []byte         `json:"callData"`
+	})
+	if len(calls) != 2 || calls[0].Target != first || calls[1].Target != second {
+		t.Fatalf("expected calls in order, got %+v", calls)
+	}
+	for _, call := range calls {
+		if !call.AllowFailure {
+			t.Fatalf("expected every call to allow failure, got %+v", calls)
+		}
+	}
+}
+
+func TestAggregate3EmptyBatch(t *testing.T) {
+	caller := &fakeCaller{}
+	results, err := Aggregate3(context.Background(), caller, nil)
+	if err != nil || results != nil {
+		t.Fatalf("expected nil results for an empty batch, got %+v err=%v", results, err)
+	}
+	if len(caller.msgs) != 0 {
+		t.Fatalf("expected no RPC call for an empty batch, got %d", len(caller.msgs))
+	}
+}
+
+func TestAggregate3Errors(t *testing.T) {
+	calls := []Call{{Target: common.HexToAddress("0x00000000000000000000000000000000000000aa")}}
+
+	if _, err := Aggregate3(context.Background(), &fakeCaller{err: errors.New("boom")}, calls); err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Fatalf("expected RPC error, got %v", err)
+	}
+	short := &fakeCaller{results: []resultTuple{}}
+	if _, err := Aggregate3(context.Background(), short, calls); err == nil {
+		t.Fatal("expected error when aggregate3 returns fewer results than calls")
+	}
+}
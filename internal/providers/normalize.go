@@ -13,6 +13,10 @@ func NormalizeLendingProvider(input string) string {
 		return "kamino"
 	case "moonwell", "moonwell-v2":
 		return "moonwell"
+	case "spark", "sparklend", "spark-lend":
+		return "spark"
+	case "compound", "compound-v3", "comet":
+		return "compound"
 	default:
 		return strings.ToLower(strings.TrimSpace(input))
 	}
@@ -23,6 +27,10 @@ func NormalizeSwapProvider(input string) string {
 	switch strings.ToLower(strings.TrimSpace(input)) {
 	case "tempo", "tempo-dex", "tempodex":
 		return "tempo"
+	case "cowswap", "cow", "cow-protocol":
+		return "cowswap"
+	case "paraswap", "velora":
+		return "paraswap"
 	default:
 		return strings.ToLower(strings.TrimSpace(input))
 	}
@@ -0,0 +1,421 @@
+package odos
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ggonzalez94/defi-cli/internal/clock"
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/execution"
+	"github.com/ggonzalez94/defi-cli/internal/httpx"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/model"
+	"github.com/ggonzalez94/defi-cli/internal/providers"
+	"github.com/ggonzalez94/defi-cli/internal/registry"
+	"github.com/ggonzalez94/defi-cli/internal/rpcx"
+)
+
+const (
+	defaultBase = "https://api.odos.xyz"
+	// nativeToken is the address Odos uses for the chain's gas token.
+	nativeToken = "0x0000000000000000000000000000000000000000"
+	// evmNativePlaceholder is how the asset registry marks gas tokens.
+	evmNativePlaceholder = "0xEeeeeEeeeEeEeeEeEeEeeEEEeeeeEeeeeeeeEEeE"
+)
+
+var erc20ABI = mustABI(registry.ERC20MinimalABI)
+
+type Client struct {
+	http    *httpx.Client
+	baseURL string
+	now     func() time.Time
+}
+
+func New(httpClient *httpx.Client) *Client {
+	return &Client{http: httpClient, baseURL: defaultBase, now: clock.Now}
+}
+
+func (c *Client) Info() model.ProviderInfo {
+	return model.ProviderInfo{
+		Name:        "odos",
+		Type:        "swap",
+		RequiresKey: false,
+		Capabilities: []string{
+			"swap.quote",
+			"swap.quote.multi_input",
+			"swap.plan",
+			"swap.execute",
+		},
+		Support: []model.CapabilitySupport{
+			{Capability: "swap.quote", Chains: providers.EVMChains(registry.OdosChainIDs()), Features: []string{providers.FeatureExactInput}},
+			{Capability: "swap.quote.multi_input", Chains: providers.EVMChains(registry.OdosChainIDs()), Features: []string{providers.FeatureExactInput}},
+			{Capability: "swap.plan", Chains: providers.EVMChains(registry.OdosChainIDs()), Features: []string{providers.FeatureExactInput}},
+			{Capability: "swap.execute", Chains: providers.EVMChains(registry.OdosChainIDs())},
+		},
+	}
+}
+
+type quoteToken struct {
+	TokenAddress string  `json:"tokenAddress"`
+	Amount       string  `json:"amount,omitempty"`
+	Proportion   float64 `json:"proportion,omitempty"`
+}
+
+type quoteRequest struct {
+	ChainID              int64        `json:"chainId"`
+	InputTokens          []quoteToken `json:"inputTokens"`
+	OutputTokens         []quoteToken `json:"outputTokens"`
+	UserAddr             string       `json:"userAddr,omitempty"`
+	SlippageLimitPercent float64      `json:"slippageLimitPercent"`
+	Compact              bool         `json:"compact"`
+}
+
+type quoteResponse struct {
+	PathID           string    `json:"pathId"`
+	InTokens         []string  `json:"inTokens"`
+	OutTokens        []string  `json:"outTokens"`
+	InAmounts        []string  `json:"inAmounts"`
+	OutAmounts       []string  `json:"outAmounts"`
+	InValues         []float64 `json:"inValues"`
+	OutValues        []float64 `json:"outValues"`
+	GasEstimateValue float64   `json:"gasEstimateValue"`
+}
+
+type assembleRequest struct {
+	UserAddr string `json:"userAddr"`
+	PathID   string `json:"pathId"`
+	Simulate bool   `json:"simulate"`
+	Receiver string `json:"receiver,omitempty"`
+}
+
+type assembleResponse struct {
+	Transaction struct {
+		To    string `json:"to"`
+		Data  string `json:"data"`
+		Value string `json:"value"`
+	} `json:"transaction"`
+}
+
+func (c *Client) QuoteSwap(ctx context.Context, req providers.SwapQuoteRequest) (model.SwapQuote, error) {
+	inputs, err := swapInputs(req)
+	if err != nil {
+		return model.SwapQuote{}, err
+	}
+	quote, err := c.quote(ctx, req, inputs, req.Swapper, 0.5)
+	if err != nil {
+		return model.SwapQuote{}, err
+	}
+	out := model.SwapQuote{
+		Provider:    "odos",
+		ChainID:     req.Chain.CAIP2,
+		FromAssetID: inputs[0].Asset.AssetID,
+		ToAssetID:   req.ToAsset.AssetID,
+		TradeType:   string(providers.SwapTradeTypeExactInput),
+		InputAmount: model.AmountInfo{
+			AmountBaseUnits: inputs[0].AmountBaseUnits,
+			AmountDecimal:   inputs[0].AmountDecimal,
+			Decimals:        inputs[0].Asset.Decimals,
+		},
+		EstimatedOut: model.AmountInfo{
+			AmountBaseUnits: quote.OutAmounts[0],
+			AmountDecimal:   id.FormatDecimalCompat(quote.OutAmounts[0], req.ToAsset.Decimals),
+			Decimals:        req.ToAsset.Decimals,
+		},
+		EstimatedGasUSD: quote.GasEstimateValue,
+		PriceImpactPct:  priceImpactPct(quote.InValues, quote.OutValues),
+		Route:           "odos",
+		SourceURL:       "https://app.odos.xyz",
+		FetchedAt:       c.now().UTC().Format(time.RFC3339),
+	}
+	if len(req.Inputs) > 0 {
+		for _, in := range inputs {
+			out.InputLegs = append(out.InputLegs, model.SwapInputLeg{
+				AssetID: in.Asset.AssetID,
+				Amount: model.AmountInfo{
+					AmountBaseUnits: in.AmountBaseUnits,
+					AmountDecimal:   in.AmountDecimal,
+					Decimals:        in.Asset.Decimals,
+				},
+			})
+		}
+	}
+	return out, nil
+}
+
+func (c *Client) BuildSwapAction(ctx context.Context, req providers.SwapQuoteRequest, opts providers.SwapExecutionOptions) (execution.Action, error) {
+	sender := strings.TrimSpace(opts.Sender)
+	if sender == "" {
+		return execution.Action{}, clierr.New(clierr.CodeUsage, "swap execution requires sender address")
+	}
+	if !common.IsHexAddress(sender) {
+		return execution.Action{}, clierr.New(clierr.CodeUsage, "swap execution sender must be a valid EVM address")
+	}
+	recipient := strings.TrimSpace(opts.Recipient)
+	if recipient == "" {
+		recipient = sender
+	}
+	if !common.IsHexAddress(recipient) {
+		return execution.Action{}, clierr.New(clierr.CodeUsage, "swap execution recipient must be a valid EVM address")
+	}
+	senderAddr := common.HexToAddress(sender)
+	recipientAddr := common.HexToAddress(recipient)
+	router, ok := registry.OdosRouter(req.Chain.EVMChainID)
+	if !ok {
+		return execution.Action{}, clierr.New(clierr.CodeUnsupported, "odos execution does not support chain "+req.Chain.Slug)
+	}
+	routerAddr := common.HexToAddress(router)
+	rpcURL, err := registry.ResolveRPCURL(opts.RPCURL, req.Chain.EVMChainID)
+	if err != nil {
+		return execution.Action{}, clierr.Wrap(clierr.CodeUsage, "resolve rpc url", err)
+	}
+	slippage := opts.SlippageBps
+	if slippage <= 0 {
+		slippage = 50
+	}
+	if slippage >= 10_000 {
+		return execution.Action{}, clierr.New(clierr.CodeUsage, "slippage bps must be less than 10000")
+	}
+	inputs, err := swapInputs(req)
+	if err != nil {
+		return execution.Action{}, err
+	}
+
+	quote, err := c.quote(ctx, req, inputs, senderAddr.Hex(), float64(slippage)/100)
+	if err != nil {
+		return execution.Action{}, err
+	}
+	quotedOut, ok := new(big.Int).SetString(quote.OutAmounts[0], 10)
+	if !ok || quotedOut.Sign() <= 0 {
+		return execution.Action{}, clierr.New(clierr.CodeUnavailable, "odos quote has invalid output amount")
+	}
+	amountOutMin := new(big.Int).Mul(quotedOut, big.NewInt(10_000-slippage))
+	amountOutMin.Div(amountOutMin, big.NewInt(10_000))
+
+	body, err := json.Marshal(assembleRequest{UserAddr: senderAddr.Hex(), PathID: quote.PathID, Receiver: recipientAddr.Hex()})
+	if err != nil {
+		return execution.Action{}, clierr.Wrap(clierr.CodeInternal, "encode odos assemble request", err)
+	}
+	var assembled assembleResponse
+	if _, err := httpx.DoBodyJSON(ctx, c.http, http.MethodPost, strings.TrimRight(c.baseURL, "/")+"/sor/assemble", body, nil, &assembled); err != nil {
+		return execution.Action{}, err
+	}
+	tx := assembled.Transaction
+	if !common.IsHexAddress(tx.To) || common.HexToAddress(tx.To) != routerAddr {
+		return execution.Action{}, clierr.New(clierr.CodeActionPlan, "odos transaction targets an unexpected contract")
+	}
+	if strings.TrimSpace(tx.Data) == "" {
+		return execution.Action{}, clierr.New(clierr.CodeUnavailable, "odos transaction missing calldata")
+	}
+	nativeIn := big.NewInt(0)
+	for _, in := range inputs {
+		if isNative(in.Asset) {
+			amount, _ := new(big.Int).SetString(in.AmountBaseUnits, 10)
+			nativeIn.Add(nativeIn, amount)
+		}
+	}
+	value := strings.TrimSpace(tx.Value)
+	if value == "" {
+		value = "0"
+	}
+	if value != nativeIn.String() {
+		return execution.Action{}, clierr.New(clierr.CodeActionPlan, "odos transaction value does not match native input amount")
+	}
+
+	tokensIn := make([]string, 0, len(inputs))
+	for _, in := range inputs {
+		tokensIn = append(tokensIn, tokenAddress(in.Asset))
+	}
+	action := execution.NewAction(execution.NewActionID(), "swap", req.Chain.CAIP2, execution.Constraints{SlippageBps: slippage, Simulate: opts.Simulate})
+	action.Provider = "odos"
+	action.FromAddress = senderAddr.Hex()
+	action.ToAddress = recipientAddr.Hex()
+	action.InputAmount = inputs[0].AmountBaseUnits
+	action.Metadata = map[string]any{
+		"token_in":       tokensIn[0],
+		"token_out":      tokenAddress(req.ToAsset),
+		"router":         routerAddr.Hex(),
+		"path_id":        quote.PathID,
+		"quoted_amount":  quotedOut.String(),
+		"amount_out_min": amountOutMin.String(),
+	}
+	if len(inputs) > 1 {
+		action.Metadata["tokens_in"] = tokensIn
+	}
+
+	for i, in := range inputs {
+		if isNative(in.Asset) {
+			continue
+		}
+		amount, _ := new(big.Int).SetString(in.AmountBaseUnits, 10)
+		token := common.HexToAddress(tokensIn[i])
+		allowance, err := readAllowance(ctx, rpcURL, token, senderAddr, routerAddr)
+		if err != nil {
+			return execution.Action{}, err
+		}
+		if allowance.Cmp(amount) >= 0 {
+			continue
+		}
+		approveData, err := erc20ABI.Pack("approve", routerAddr, amount)
+		if err != nil {
+			return execution.Action{}, clierr.Wrap(clierr.CodeInternal, "pack approve calldata", err)
+		}
+		stepID := "approve-odos-router"
+		if len(inputs) > 1 {
+			stepID += "-" + strconv.Itoa(i+1)
+		}
+		action.Steps = append(action.Steps, execution.ActionStep{
+			StepID:      stepID,
+			Type:        execution.StepTypeApproval,
+			Status:      execution.StepStatusPending,
+			ChainID:     req.Chain.CAIP2,
+			RPCURL:      rpcURL,
+			Description: "Approve token spending for Odos router",
+			Target:      token.Hex(),
+			Data:        "0x" + common.Bytes2Hex(approveData),
+			Value:       "0",
+		})
+	}
+
+	action.Steps = append(action.Steps, execution.ActionStep{
+		StepID:      "odos-swap",
+		Type:        execution.StepTypeSwap,
+		Status:      execution.StepStatusPending,
+		ChainID:     req.Chain.CAIP2,
+		RPCURL:      rpcURL,
+		Description: "Swap via Odos router",
+		Target:      routerAddr.Hex(),
+		Data:        tx.Data,
+		Value:       value,
+		ExpectedOutputs: map[string]string{
+			"amount_out_min": amountOutMin.String(),
+		},
+	})
+	return action, nil
+}
+
+func (c *Client) quote(ctx context.Context, req providers.SwapQuoteRequest, inputs []providers.SwapInput, userAddress string, slippagePct float64) (quoteResponse, error) {
+	if req.TradeType != "" && req.TradeType != providers.SwapTradeTypeExactInput {
+		return quoteResponse{}, clierr.New(clierr.CodeUnsupported, "odos supports only --type exact-input")
+	}
+	if !req.Chain.IsEVM() {
+		return quoteResponse{}, clierr.New(clierr.CodeUnsupported, "odos supports only EVM chains")
+	}
+	body := quoteRequest{
+		ChainID:              req.Chain.EVMChainID,
+		OutputTokens:         []quoteToken{{TokenAddress: tokenAddress(req.ToAsset), Proportion: 1}},
+		SlippageLimitPercent: slippagePct,
+		Compact:              true,
+	}
+	for _, in := range inputs {
+		body.InputTokens = append(body.InputTokens, quoteToken{TokenAddress: tokenAddress(in.Asset), Amount: in.AmountBaseUnits})
+	}
+	if addr := strings.TrimSpace(userAddress); addr != "" && common.IsHexAddress(addr) {
+		body.UserAddr = common.HexToAddress(addr).Hex()
+	}
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return quoteResponse{}, clierr.Wrap(clierr.CodeInternal, "encode odos quote request", err)
+	}
+	var resp quoteResponse
+	if _, err := httpx.DoBodyJSON(ctx, c.http, http.MethodPost, strings.TrimRight(c.baseURL, "/")+"/sor/quote/v2", payload, nil, &resp); err != nil {
+		return quoteResponse{}, err
+	}
+	if strings.TrimSpace(resp.PathID) == "" || len(resp.OutAmounts) == 0 || strings.TrimSpace(resp.OutAmounts[0]) == "" {
+		return quoteResponse{}, clierr.New(clierr.CodeUnavailable, "odos quote missing path or output amount")
+	}
+	return resp, nil
+}
+
+// swapInputs returns the request's input legs, treating a single-asset
+// request as one leg.
+func swapInputs(req providers.SwapQuoteRequest) ([]providers.SwapInput, error) {
+	inputs := req.Inputs
+	if len(inputs) == 0 {
+		inputs = []providers.SwapInput{{Asset: req.FromAsset, AmountBaseUnits: req.AmountBaseUnits, AmountDecimal: req.AmountDecimal}}
+	}
+	seen := make(map[string]struct{}, len(inputs))
+	for _, in := range inputs {
+		amount, ok := new(big.Int).SetString(in.AmountBaseUnits, 10)
+		if !ok || amount.Sign() <= 0 {
+			return nil, clierr.New(clierr.CodeUsage, "swap amount must be a positive integer in base units")
+		}
+		key := strings.ToLower(tokenAddress(in.Asset))
+		if _, dup := seen[key]; dup {
+			return nil, clierr.New(clierr.CodeUsage, "multi-input swap lists the same input asset more than once")
+		}
+		seen[key] = struct{}{}
+		if strings.EqualFold(key, tokenAddress(req.ToAsset)) {
+			return nil, clierr.New(clierr.CodeUsage, "swap input and output assets must differ")
+		}
+	}
+	return inputs, nil
+}
+
+// priceImpactPct compares the USD value in against the value out across all
+// legs; Odos reports both per token.
+func priceImpactPct(inValues, outValues []float64) float64 {
+	var in, out float64
+	for _, v := range inValues {
+		in += v
+	}
+	for _, v := range outValues {
+		out += v
+	}
+	if in <= 0 || out <= 0 || out >= in {
+		return 0
+	}
+	return (in - out) / in * 100
+}
+
+func tokenAddress(asset id.Asset) string {
+	if isNative(asset) {
+		return nativeToken
+	}
+	return common.HexToAddress(asset.Address).Hex()
+}
+
+func isNative(asset id.Asset) bool {
+	addr := strings.TrimSpace(asset.Address)
+	return strings.EqualFold(addr, evmNativePlaceholder) || strings.EqualFold(addr, nativeToken)
+}
+
+func readAllowance(ctx context.Context, rpcURL string, token, owner, spender common.Address) (*big.Int, error) {
+	client, err := rpcx.DialContext(ctx, rpcURL)
+	if err != nil {
+		return nil, clierr.Wrap(clierr.CodeUnavailable, "connect rpc", err)
+	}
+	defer client.Close()
+	data, err := erc20ABI.Pack("allowance", owner, spender)
+	if err != nil {
+		return nil, clierr.Wrap(clierr.CodeInternal, "pack allowance call", err)
+	}
+	out, err := client.CallContract(ctx, ethereum.CallMsg{From: owner, To: &token, Data: data}, nil)
+	if err != nil {
+		return nil, clierr.Wrap(clierr.CodeUnavailable, "read allowance", err)
+	}
+	values, err := erc20ABI.Unpack("allowance", out)
+	if err != nil || len(values) == 0 {
+		return nil, clierr.Wrap(clierr.CodeUnavailable, "decode allowance", err)
+	}
+	allowance, ok := values[0].(*big.Int)
+	if !ok {
+		return nil, clierr.New(clierr.CodeUnavailable, "invalid allowance response")
+	}
+	return allowance, nil
+}
+
+func mustABI(raw string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(raw))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
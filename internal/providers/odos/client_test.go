@@ -0,0 +1,169 @@
+package odos
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ggonzalez94/defi-cli/internal/execution"
+	"github.com/ggonzalez94/defi-cli/internal/httpx"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/providers"
+)
+
+const testQuote = `{"pathId":"path-1","inTokens":["0xa0b8","0x6b17"],"outTokens":["0xc02a"],
+	"inAmounts":["500000000","500000000000000000000"],"outAmounts":["400000000000000000"],
+	"inValues":[500,500],"outValues":[990],"gasEstimateValue":2.5}`
+
+type fakeAPI struct {
+	t         *testing.T
+	quotes    []quoteRequest
+	assembled assembleRequest
+	txReply   string
+}
+
+func (f *fakeAPI) handler(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/sor/quote/v2":
+		var req quoteRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			f.t.Fatalf("decode quote request: %v", err)
+		}
+		f.quotes = append(f.quotes, req)
+		_, _ = w.Write([]byte(testQuote))
+	case "/sor/assemble":
+		if err := json.NewDecoder(r.Body).Decode(&f.assembled); err != nil {
+			f.t.Fatalf("decode assemble request: %v", err)
+		}
+		_, _ = w.Write([]byte(f.txReply))
+	default:
+		f.t.Fatalf("unexpected request %s %s", r.Method, r.URL.String())
+	}
+}
+
+func newAllowanceRPCServer(allowanceHex string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		w.Header().Set("Content-Type", "application/json")
+		if req.Method != "eth_call" {
+			_, _ = fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"error":{"code":-32601,"message":"unsupported"}}`, req.ID)
+			return
+		}
+		_, _ = fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":"0x%064s"}`, req.ID, allowanceHex)
+	}))
+}
+
+func multiInputRequest(t *testing.T) providers.SwapQuoteRequest {
+	t.Helper()
+	chain, err := id.ParseChain("ethereum")
+	if err != nil {
+		t.Fatalf("parse chain: %v", err)
+	}
+	usdc, _ := id.ParseAsset("USDC", chain)
+	dai, _ := id.ParseAsset("DAI", chain)
+	weth, _ := id.ParseAsset("WETH", chain)
+	inputs := []providers.SwapInput{
+		{Asset: usdc, AmountBaseUnits: "500000000", AmountDecimal: "500"},
+		{Asset: dai, AmountBaseUnits: "500000000000000000000", AmountDecimal: "500"},
+	}
+	return providers.SwapQuoteRequest{
+		Chain: chain, FromAsset: usdc, ToAsset: weth,
+		AmountBaseUnits: inputs[0].AmountBaseUnits, AmountDecimal: inputs[0].AmountDecimal,
+		Inputs: inputs,
+	}
+}
+
+func TestQuoteSwapSendsEveryInputLeg(t *testing.T) {
+	api := &fakeAPI{t: t}
+	server := httptest.NewServer(http.HandlerFunc(api.handler))
+	defer server.Close()
+
+	c := New(httpx.New(2*time.Second, 0))
+	c.baseURL = server.URL
+	quote, err := c.QuoteSwap(context.Background(), multiInputRequest(t))
+	if err != nil {
+		t.Fatalf("QuoteSwap failed: %v", err)
+	}
+	if len(api.quotes) != 1 || len(api.quotes[0].InputTokens) != 2 || api.quotes[0].ChainID != 1 {
+		t.Fatalf("unexpected quote request: %+v", api.quotes)
+	}
+	if api.quotes[0].InputTokens[1].Amount != "500000000000000000000" || api.quotes[0].OutputTokens[0].Proportion != 1 {
+		t.Fatalf("unexpected quote tokens: %+v", api.quotes[0])
+	}
+	if quote.Provider != "odos" || quote.EstimatedOut.AmountBaseUnits != "400000000000000000" || quote.EstimatedGasUSD != 2.5 {
+		t.Fatalf("unexpected quote: %+v", quote)
+	}
+	if len(quote.InputLegs) != 2 || quote.InputLegs[1].Amount.AmountDecimal != "500" || quote.FromAssetID != quote.InputLegs[0].AssetID {
+		t.Fatalf("unexpected input legs: %+v", quote.InputLegs)
+	}
+	if quote.PriceImpactPct < 0.99 || quote.PriceImpactPct > 1.01 {
+		t.Fatalf("unexpected price impact: %v", quote.PriceImpactPct)
+	}
+}
+
+func TestQuoteSwapRejectsDuplicateInputs(t *testing.T) {
+	req := multiInputRequest(t)
+	req.Inputs[1] = req.Inputs[0]
+	_, err := New(httpx.New(2*time.Second, 0)).QuoteSwap(context.Background(), req)
+	if err == nil || !strings.Contains(err.Error(), "more than once") {
+		t.Fatalf("expected duplicate input error, got %v", err)
+	}
+}
+
+func TestBuildSwapActionApprovesEachInputLeg(t *testing.T) {
+	api := &fakeAPI{t: t, txReply: `{"transaction":{"to":"0xcf5540fffcdc3d510b18bfca6d2b9987b0772559","data":"0x83bd37f9","value":"0"}}`}
+	server := httptest.NewServer(http.HandlerFunc(api.handler))
+	defer server.Close()
+	rpc := newAllowanceRPCServer("0")
+	defer rpc.Close()
+
+	c := New(httpx.New(2*time.Second, 0))
+	c.baseURL = server.URL
+	action, err := c.BuildSwapAction(context.Background(), multiInputRequest(t), providers.SwapExecutionOptions{
+		Sender:      "0x00000000000000000000000000000000000000AA",
+		SlippageBps: 100,
+		RPCURL:      rpc.URL,
+	})
+	if err != nil {
+		t.Fatalf("BuildSwapAction failed: %v", err)
+	}
+	if len(action.Steps) != 3 || action.Steps[0].StepID != "approve-odos-router-1" || action.Steps[1].StepID != "approve-odos-router-2" {
+		t.Fatalf("expected two approvals and a swap, got %+v", action.Steps)
+	}
+	swap := action.Steps[2]
+	if swap.Type != execution.StepTypeSwap || swap.Target != "0xCf5540fFFCdC3d510B18bFcA6d2b9987b0772559" || swap.Value != "0" {
+		t.Fatalf("unexpected swap step: %+v", swap)
+	}
+	if swap.ExpectedOutputs["amount_out_min"] != "396000000000000000" {
+		t.Fatalf("unexpected min output: %+v", swap.ExpectedOutputs)
+	}
+	if api.assembled.PathID != "path-1" || api.quotes[0].SlippageLimitPercent != 1 {
+		t.Fatalf("unexpected assemble/quote requests: %+v %+v", api.assembled, api.quotes)
+	}
+}
+
+func TestBuildSwapActionRejectsUnexpectedRouter(t *testing.T) {
+	api := &fakeAPI{t: t, txReply: `{"transaction":{"to":"0x00000000000000000000000000000000000000CC","data":"0x01","value":"0"}}`}
+	server := httptest.NewServer(http.HandlerFunc(api.handler))
+	defer server.Close()
+	rpc := newAllowanceRPCServer("ffffffffffffffffffffffffffffffff")
+	defer rpc.Close()
+
+	c := New(httpx.New(2*time.Second, 0))
+	c.baseURL = server.URL
+	_, err := c.BuildSwapAction(context.Background(), multiInputRequest(t), providers.SwapExecutionOptions{
+		Sender: "0x00000000000000000000000000000000000000AA", RPCURL: rpc.URL,
+	})
+	if err == nil || !strings.Contains(err.Error(), "unexpected contract") {
+		t.Fatalf("expected router mismatch error, got %v", err)
+	}
+}
@@ -0,0 +1,338 @@
+// Package onchainlend reads lending rates straight from protocol contracts
+// over RPC. It backs lend rates when a protocol's hosted API is down, and
+// serves Compound v3, which has no API provider.
+package onchainlend
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ggonzalez94/defi-cli/internal/clock"
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/model"
+	"github.com/ggonzalez94/defi-cli/internal/providers/multicall"
+	"github.com/ggonzalez94/defi-cli/internal/providers/yieldutil"
+	"github.com/ggonzalez94/defi-cli/internal/registry"
+	"github.com/ggonzalez94/defi-cli/internal/rpcx"
+)
+
+// Source tags rates read by this provider.
+const Source = "onchain"
+
+const secondsPerYear = 365 * 24 * 3600
+
+// Aave getReserveData returns a static tuple; these are its word offsets.
+const (
+	reserveWordLiquidityRate      = 2
+	reserveWordVariableBorrowRate = 4
+	reserveWordAToken             = 8
+	reserveWordVariableDebtToken  = 10
+	reserveWords                  = 15
+)
+
+var (
+	ray  = new(big.Float).SetFloat64(1e27)
+	wad  = new(big.Float).SetFloat64(1e18)
+	zero = common.Address{}
+)
+
+type Client struct {
+	now         func() time.Time
+	rpcOverride string
+}
+
+func New() *Client {
+	return &Client{now: clock.Now}
+}
+
+// SetRPCOverride sets the RPC URL used for contract reads. Pass "" to revert
+// to the chain default.
+func (c *Client) SetRPCOverride(url string) { c.rpcOverride = url }
+
+func (c *Client) Info() model.ProviderInfo {
+	return model.ProviderInfo{
+		Name:         Source,
+		Type:         "lending",
+		RequiresKey:  false,
+		Capabilities: []string{"lend.rates"},
+	}
+}
+
+// Supports reports whether rates for provider on chain can be read on-chain.
+func (c *Client) Supports(provider string, chain id.Chain) bool {
+	if !chain.IsEVM() {
+		return false
+	}
+	switch provider {
+	case "aave":
+		_, ok := registry.AavePoolAddressProvider(chain.EVMChainID)
+		return ok
+	case "compound":
+		return len(registry.CometMarkets(chain.EVMChainID)) > 0
+	default:
+		return false
+	}
+}
+
+func (c *Client) LendRates(ctx context.Context, provider string, chain id.Chain, asset id.Asset) ([]model.LendRate, error) {
+	if !c.Supports(provider, chain) {
+		return nil, clierr.New(clierr.CodeUnsupported, fmt.Sprintf("on-chain rates are not available for %s on this chain", provider))
+	}
+	underlying, err := underlyingAddress(chain, asset)
+	if err != nil {
+		return nil, err
+	}
+	rpcURL, err := registry.ResolveRPCURL(c.rpcOverride, chain.EVMChainID)
+	if err != nil {
+		return nil, clierr.Wrap(clierr.CodeUnsupported, "resolve rpc url", err)
+	}
+	client, err := rpcx.DialContext(ctx, rpcURL)
+	if err != nil {
+		return nil, clierr.Wrap(clierr.CodeUnavailable, "connect rpc", err)
+	}
+	defer client.Close()
+
+	var out []model.LendRate
+	if provider == "aave" {
+		out, err = c.aaveRates(ctx, client, chain, underlying)
+	} else {
+		out, err = c.cometRates(ctx, client, chain, underlying)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(out) == 0 {
+		return nil, clierr.New(clierr.CodeUnsupported, fmt.Sprintf("no %s market found on-chain for requested asset", provider))
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].SupplyAPY != out[j].SupplyAPY {
+			return out[i].SupplyAPY > out[j].SupplyAPY
+		}
+		return out[i].ProviderNativeID < out[j].ProviderNativeID
+	})
+	return out, nil
+}
+
+// aaveRates reads the reserve of underlying from the chain's Aave v3 pool.
+func (c *Client) aaveRates(ctx context.Context, client *ethclient.Client, chain id.Chain, underlying common.Address) ([]model.LendRate, error) {
+	providerAddr, _ := registry.AavePoolAddressProvider(chain.EVMChainID)
+	pool, err := callAddress(ctx, client, common.HexToAddress(providerAddr), aavePoolAddressProviderABI, "getPool")
+	if err != nil {
+		return nil, err
+	}
+	reserveCD, _ := aavePoolABI.Pack("getReserveData", underlying)
+	results, err := multicall.Aggregate3(ctx, client, []multicall.Call{{Target: pool, CallData: reserveCD}})
+	if err != nil {
+		return nil, clierr.Wrap(clierr.CodeUnavailable, "multicall aave reserve data", err)
+	}
+	reserve := results[0]
+	if !reserve.Success || len(reserve.ReturnData) < reserveWords*32 {
+		return nil, nil
+	}
+	aToken := common.BytesToAddress(word(reserve.ReturnData, reserveWordAToken))
+	if aToken == zero {
+		return nil, nil
+	}
+	debtToken := common.BytesToAddress(word(reserve.ReturnData, reserveWordVariableDebtToken))
+
+	supplyCD, _ := erc20SupplyABI.Pack("totalSupply")
+	supplies, err := multicall.Aggregate3(ctx, client, []multicall.Call{
+		{Target: aToken, CallData: supplyCD},
+		{Target: debtToken, CallData: supplyCD},
+	})
+	if err != nil {
+		return nil, clierr.Wrap(clierr.CodeUnavailable, "multicall aave reserve supply", err)
+	}
+
+	liquidityRate := new(big.Int).SetBytes(word(reserve.ReturnData, reserveWordLiquidityRate))
+	borrowRate := new(big.Int).SetBytes(word(reserve.ReturnData, reserveWordVariableBorrowRate))
+	return []model.LendRate{{
+		Protocol:             "aave",
+		Provider:             "aave",
+		ChainID:              chain.CAIP2,
+		AssetID:              canonicalAssetID(chain.CAIP2, underlying),
+		ProviderNativeID:     providerNativeID("aave", chain.CAIP2, pool, underlying),
+		ProviderNativeIDKind: model.NativeIDKindCompositeMarketAsset,
+		SupplyAPY:            yieldutil.PercentFromRatio(compoundAPR(scaled(liquidityRate, ray))),
+		BorrowAPY:            yieldutil.PercentFromRatio(compoundAPR(scaled(borrowRate, ray))),
+		RateBasis:            yieldutil.RateBasisAPY,
+		Compounding:          yieldutil.CompoundingPerSecond,
+		Utilization:          ratio(decodeUint256(supplies[1]), decodeUint256(supplies[0])),
+		Source:               Source,
+		SourceURL:            "https://app.aave.com",
+		FetchedAt:            c.now().UTC().Format(time.RFC3339),
+	}}, nil
+}
+
+// cometRates reads every known Compound v3 market on the chain whose base
+// token is underlying.
+func (c *Client) cometRates(ctx context.Context, client *ethclient.Client, chain id.Chain, underlying common.Address) ([]model.LendRate, error) {
+	markets := registry.CometMarkets(chain.EVMChainID)
+	baseCD, _ := cometABI.Pack("baseToken")
+	utilCD, _ := cometABI.Pack("getUtilization")
+	phase1 := make([]multicall.Call, 0, len(markets)*2)
+	for _, market := range markets {
+		addr := common.HexToAddress(market)
+		phase1 = append(phase1, multicall.Call{Target: addr, CallData: baseCD}, multicall.Call{Target: addr, CallData: utilCD})
+	}
+	results, err := multicall.Aggregate3(ctx, client, phase1)
+	if err != nil {
+		return nil, clierr.Wrap(clierr.CodeUnavailable, "multicall comet markets", err)
+	}
+
+	type cometMarket struct {
+		address     common.Address
+		utilization *big.Int
+	}
+	matched := make([]cometMarket, 0, len(markets))
+	for i, market := range markets {
+		base := results[i*2]
+		if !base.Success || len(base.ReturnData) < 32 || common.BytesToAddress(word(base.ReturnData, 0)) != underlying {
+			continue
+		}
+		matched = append(matched, cometMarket{address: common.HexToAddress(market), utilization: decodeUint256(results[i*2+1])})
+	}
+	if len(matched) == 0 {
+		return nil, nil
+	}
+
+	phase2 := make([]multicall.Call, 0, len(matched)*2)
+	for _, m := range matched {
+		supplyCD, _ := cometABI.Pack("getSupplyRate", m.utilization)
+		borrowCD, _ := cometABI.Pack("getBorrowRate", m.utilization)
+		phase2 = append(phase2, multicall.Call{Target: m.address, CallData: supplyCD}, multicall.Call{Target: m.address, CallData: borrowCD})
+	}
+	rates, err := multicall.Aggregate3(ctx, client, phase2)
+	if err != nil {
+		return nil, clierr.Wrap(clierr.CodeUnavailable, "multicall comet rates", err)
+	}
+
+	fetchedAt := c.now().UTC().Format(time.RFC3339)
+	out := make([]model.LendRate, 0, len(matched))
+	for i, m := range matched {
+		utilization, _ := scaled(m.utilization, wad).Float64()
+		out = append(out, model.LendRate{
+			Protocol:             "compound",
+			Provider:             "compound",
+			ChainID:              chain.CAIP2,
+			AssetID:              canonicalAssetID(chain.CAIP2, underlying),
+			ProviderNativeID:     providerNativeID("compound", chain.CAIP2, m.address, underlying),
+			ProviderNativeIDKind: model.NativeIDKindCompositeMarketAsset,
+			SupplyAPY:            yieldutil.PercentFromRatio(compoundPerSecond(scaled(decodeUint256(rates[i*2]), wad))),
+			BorrowAPY:            yieldutil.PercentFromRatio(compoundPerSecond(scaled(decodeUint256(rates[i*2+1]), wad))),
+			RateBasis:            yieldutil.RateBasisAPY,
+			Compounding:          yieldutil.CompoundingPerSecond,
+			Utilization:          utilization,
+			Source:               Source,
+			SourceURL:            "https://app.compound.finance",
+			FetchedAt:            fetchedAt,
+		})
+	}
+	return out, nil
+}
+
+// underlyingAddress returns the ERC-20 the markets list for asset. Pools hold
+// the wrapped token for native gas token inputs.
+func underlyingAddress(chain id.Chain, asset id.Asset) (common.Address, error) {
+	addr := strings.TrimSpace(asset.Address)
+	if asset.IsNative() {
+		wrapped, ok := id.WrappedNativeToken(chain)
+		if !ok {
+			return common.Address{}, clierr.New(clierr.CodeUnsupported, "no wrapped native token known for this chain")
+		}
+		addr = wrapped.Address
+	}
+	if !common.IsHexAddress(addr) {
+		return common.Address{}, clierr.New(clierr.CodeUsage, "on-chain rates require an asset with a known contract address")
+	}
+	return common.HexToAddress(addr), nil
+}
+
+// compoundAPR converts an annual rate accrued per second to an APY ratio.
+func compoundAPR(apr *big.Float) float64 {
+	v, _ := apr.Float64()
+	return math.Pow(1+v/secondsPerYear, secondsPerYear) - 1
+}
+
+// compoundPerSecond converts a per-second rate to an APY ratio.
+func compoundPerSecond(rate *big.Float) float64 {
+	v, _ := rate.Float64()
+	return math.Pow(1+v, secondsPerYear) - 1
+}
+
+func scaled(v *big.Int, scale *big.Float) *big.Float {
+	if v == nil {
+		return new(big.Float)
+	}
+	return new(big.Float).Quo(new(big.Float).SetInt(v), scale)
+}
+
+func ratio(num, den *big.Int) float64 {
+	if den == nil || den.Sign() == 0 || num == nil {
+		return 0
+	}
+	out, _ := new(big.Float).Quo(new(big.Float).SetInt(num), new(big.Float).SetInt(den)).Float64()
+	return out
+}
+
+func word(data []byte, i int) []byte {
+	return data[i*32 : (i+1)*32]
+}
+
+func decodeUint256(r multicall.Result) *big.Int {
+	if !r.Success || len(r.ReturnData) < 32 {
+		return new(big.Int)
+	}
+	return new(big.Int).SetBytes(word(r.ReturnData, 0))
+}
+
+func canonicalAssetID(chainID string, addr common.Address) string {
+	return fmt.Sprintf("%s/erc20:%s", chainID, strings.ToLower(addr.Hex()))
+}
+
+func providerNativeID(provider, chainID string, market, underlying common.Address) string {
+	return fmt.Sprintf("%s:%s:%s:%s", provider, chainID, strings.ToLower(market.Hex()), strings.ToLower(underlying.Hex()))
+}
+
+func callAddress(ctx context.Context, client *ethclient.Client, target common.Address, contract abi.ABI, method string) (common.Address, error) {
+	data, err := contract.Pack(method)
+	if err != nil {
+		return common.Address{}, clierr.Wrap(clierr.CodeInternal, "pack "+method, err)
+	}
+	out, err := client.CallContract(ctx, ethereum.CallMsg{To: &target, Data: data}, nil)
+	if err != nil {
+		return common.Address{}, clierr.Wrap(clierr.CodeUnavailable, "call "+method, err)
+	}
+	decoded, err := contract.Unpack(method, out)
+	if err != nil || len(decoded) == 0 {
+		return common.Address{}, clierr.Wrap(clierr.CodeUnavailable, "decode "+method, err)
+	}
+	addr, ok := decoded[0].(common.Address)
+	if !ok || addr == zero {
+		return common.Address{}, clierr.New(clierr.CodeUnavailable, "invalid "+method+" response")
+	}
+	return addr, nil
+}
+
+var aavePoolAddressProviderABI = mustABI(registry.AavePoolAddressProviderABI)
+var aavePoolABI = mustABI(registry.AavePoolABI)
+var cometABI = mustABI(registry.CometABI)
+var erc20SupplyABI = mustABI(registry.ERC20SupplyABI)
+
+func mustABI(raw string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(raw))
+	if err != nil {
+		panic(fmt.Sprintf("invalid ABI: %v", err))
+	}
+	return parsed
+}
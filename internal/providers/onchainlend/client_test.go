@@ -0,0 +1,231 @@
+package onchainlend
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"math"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/providers/multicall"
+	"github.com/ggonzalez94/defi-cli/internal/registry"
+)
+
+var (
+	testPool      = common.HexToAddress("0xA238Dd80C259a72e81d7e4664a9801593F98d1c5")
+	testAToken    = common.HexToAddress("0x4e65fE4DbA92790696d040ac24Aa414708F5c0AB")
+	testDebtToken = common.HexToAddress("0x59dca05b6c26dbd64b5381374aAaC5CD05644C28")
+	testUSDC      = common.HexToAddress("0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913")
+	testWETH      = common.HexToAddress("0x4200000000000000000000000000000000000006")
+)
+
+func uint256Word(v *big.Int) []byte {
+	return common.LeftPadBytes(v.Bytes(), 32)
+}
+
+func exp10(n int64) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(n), nil)
+}
+
+// reserveData encodes an Aave getReserveData tuple with a 5% supply APR, an
+// 8% variable borrow APR and the test aToken/debt token.
+func reserveData() []byte {
+	words := make([][]byte, reserveWords)
+	for i := range words {
+		words[i] = make([]byte, 32)
+	}
+	words[reserveWordLiquidityRate] = uint256Word(new(big.Int).Mul(big.NewInt(5), exp10(25)))
+	words[reserveWordVariableBorrowRate] = uint256Word(new(big.Int).Mul(big.NewInt(8), exp10(25)))
+	words[reserveWordAToken] = common.LeftPadBytes(testAToken.Bytes(), 32)
+	words[reserveWordVariableDebtToken] = common.LeftPadBytes(testDebtToken.Bytes(), 32)
+	var out []byte
+	for _, w := range words {
+		out = append(out, w...)
+	}
+	return out
+}
+
+func dispatch(to common.Address, data []byte) ([]byte, bool) {
+	selector := hex.EncodeToString(data[:4])
+	is := func(contract, name string) bool {
+		return selector == hex.EncodeToString(mustABI(contract).Methods[name].ID)
+	}
+	switch {
+	case to == testPool && is(registry.AavePoolABI, "getReserveData"):
+		if common.BytesToAddress(data[4:36]) != testUSDC {
+			return make([]byte, reserveWords*32), true
+		}
+		return reserveData(), true
+	case to == testAToken && is(registry.ERC20SupplyABI, "totalSupply"):
+		return uint256Word(new(big.Int).Mul(big.NewInt(1_000_000), exp10(6))), true
+	case to == testDebtToken && is(registry.ERC20SupplyABI, "totalSupply"):
+		return uint256Word(new(big.Int).Mul(big.NewInt(800_000), exp10(6))), true
+	case is(registry.CometABI, "baseToken"):
+		if strings.EqualFold(to.Hex(), "0xb125E6687d4313864e53df431d5425969c15Eb2F") {
+			return common.LeftPadBytes(testUSDC.Bytes(), 32), true
+		}
+		return common.LeftPadBytes(testWETH.Bytes(), 32), true
+	case is(registry.CometABI, "getUtilization"):
+		return uint256Word(new(big.Int).Mul(big.NewInt(9), exp10(17))), true
+	case is(registry.CometABI, "getSupplyRate"):
+		return uint256Word(big.NewInt(1_500_000_000)), true
+	case is(registry.CometABI, "getBorrowRate"):
+		return uint256Word(big.NewInt(2_000_000_000)), true
+	}
+	return nil, false
+}
+
+func newTestRPCServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	getPool := hex.EncodeToString(aavePoolAddressProviderABI.Methods["getPool"].ID)
+	aggregate3 := hex.EncodeToString(multicall.ABI.Methods["aggregate3"].ID)
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+			ID     any               `json:"id"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		reply := func(result string) {
+			_ = json.NewEncoder(w).Encode(map[string]any{"jsonrpc": "2.0", "id": req.ID, "result": result})
+		}
+		if req.Method != "eth_call" {
+			reply("0x")
+			return
+		}
+		var call struct {
+			To    string `json:"to"`
+			Data  string `json:"data"`
+			Input string `json:"input"`
+		}
+		_ = json.Unmarshal(req.Params[0], &call)
+		raw := call.Data
+		if raw == "" {
+			raw = call.Input
+		}
+		data, _ := hex.DecodeString(strings.TrimPrefix(raw, "0x"))
+		switch hex.EncodeToString(data[:4]) {
+		case getPool:
+			reply("0x" + hex.EncodeToString(common.LeftPadBytes(testPool.Bytes(), 32)))
+		case aggregate3:
+			decoded, err := multicall.ABI.Methods["aggregate3"].Inputs.Unpack(data[4:])
+			if err != nil {
+				t.Errorf("unpack aggregate3: %v", err)
+				reply("0x")
+				return
+			}
+			calls := decoded[0].([]struct {
+				Target       common.Address `json:"target"`
+				AllowFailure bool           `json:"allowFailure"`
+				CallData     []byte         `json:"callData"`
+			})
+			type result struct {
+				Success    bool
+				ReturnData []byte
+			}
+			results := make([]result, len(calls))
+			for i, c := range calls {
+				out, ok := dispatch(c.Target, c.CallData)
+				results[i] = result{Success: ok, ReturnData: out}
+			}
+			encoded, err := multicall.ABI.Methods["aggregate3"].Outputs.Pack(results)
+			if err != nil {
+				t.Errorf("pack aggregate3: %v", err)
+				reply("0x")
+				return
+			}
+			reply("0x" + hex.EncodeToString(encoded))
+		default:
+			reply("0x")
+		}
+	}))
+}
+
+func newTestClient(t *testing.T) *Client {
+	t.Helper()
+	srv := newTestRPCServer(t)
+	t.Cleanup(srv.Close)
+	client := New()
+	client.SetRPCOverride(srv.URL)
+	client.now = func() time.Time { return time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC) }
+	return client
+}
+
+var base = id.Chain{CAIP2: "eip155:8453", EVMChainID: 8453}
+
+func TestLendRatesReadsAaveReserve(t *testing.T) {
+	client := newTestClient(t)
+	rates, err := client.LendRates(context.Background(), "aave", base, id.Asset{ChainID: base.CAIP2, Address: testUSDC.Hex(), Symbol: "USDC"})
+	if err != nil {
+		t.Fatalf("LendRates failed: %v", err)
+	}
+	if len(rates) != 1 {
+		t.Fatalf("expected one rate, got %+v", rates)
+	}
+	got := rates[0]
+	if got.Provider != "aave" || got.Source != Source || got.AssetID != "eip155:8453/erc20:0x833589fcd6edb6e08f4c7c32d4f71b54bda02913" {
+		t.Fatalf("unexpected rate metadata %+v", got)
+	}
+	if !strings.Contains(got.ProviderNativeID, strings.ToLower(testPool.Hex())) {
+		t.Fatalf("expected pool address in provider native id, got %s", got.ProviderNativeID)
+	}
+	if want := (math.Exp(0.05) - 1) * 100; math.Abs(got.SupplyAPY-want) > 0.001 {
+		t.Fatalf("expected supply APY ~%.4f, got %.4f", want, got.SupplyAPY)
+	}
+	if want := (math.Exp(0.08) - 1) * 100; math.Abs(got.BorrowAPY-want) > 0.001 {
+		t.Fatalf("expected borrow APY ~%.4f, got %.4f", want, got.BorrowAPY)
+	}
+	if math.Abs(got.Utilization-0.8) > 1e-9 {
+		t.Fatalf("expected utilization 0.8, got %f", got.Utilization)
+	}
+}
+
+func TestLendRatesAaveUnlistedAssetIsUnsupported(t *testing.T) {
+	client := newTestClient(t)
+	_, err := client.LendRates(context.Background(), "aave", base, id.Asset{ChainID: base.CAIP2, Address: testWETH.Hex()})
+	if cErr, ok := clierr.As(err); !ok || cErr.Code != clierr.CodeUnsupported {
+		t.Fatalf("expected unsupported error, got %v", err)
+	}
+}
+
+func TestLendRatesReadsCometMarketsForBaseToken(t *testing.T) {
+	client := newTestClient(t)
+	rates, err := client.LendRates(context.Background(), "compound", base, id.NativeAsset(base))
+	if err != nil {
+		t.Fatalf("LendRates failed: %v", err)
+	}
+	if len(rates) != 1 {
+		t.Fatalf("expected only the WETH market, got %+v", rates)
+	}
+	got := rates[0]
+	if got.Provider != "compound" || got.Source != Source || !strings.Contains(got.ProviderNativeID, "0x46e6b214b524310239732d51387075e0e70970bf") {
+		t.Fatalf("unexpected rate metadata %+v", got)
+	}
+	if want := (math.Exp(1.5e-9*secondsPerYear) - 1) * 100; math.Abs(got.SupplyAPY-want) > 0.001 {
+		t.Fatalf("expected supply APY ~%.4f, got %.4f", want, got.SupplyAPY)
+	}
+	if got.BorrowAPY <= got.SupplyAPY || math.Abs(got.Utilization-0.9) > 1e-9 {
+		t.Fatalf("unexpected borrow APY or utilization %+v", got)
+	}
+}
+
+func TestSupports(t *testing.T) {
+	client := New()
+	if !client.Supports("aave", base) || !client.Supports("compound", base) {
+		t.Fatal("expected aave and compound on Base")
+	}
+	if client.Supports("morpho", base) || client.Supports("compound", id.Chain{CAIP2: "eip155:167000", EVMChainID: 167000}) {
+		t.Fatal("expected morpho and compound on Taiko to be unsupported")
+	}
+}
@@ -3,16 +3,19 @@ package oneinch
 import (
 	"context"
 	"fmt"
+	"math/big"
 	"net/http"
 	"net/url"
 	"strconv"
 	"time"
 
+	"github.com/ggonzalez94/defi-cli/internal/clock"
 	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
 	"github.com/ggonzalez94/defi-cli/internal/httpx"
 	"github.com/ggonzalez94/defi-cli/internal/id"
 	"github.com/ggonzalez94/defi-cli/internal/model"
 	"github.com/ggonzalez94/defi-cli/internal/providers"
+	"github.com/ggonzalez94/defi-cli/internal/registry"
 )
 
 const defaultBase = "https://api.1inch.dev"
@@ -25,7 +28,7 @@ type Client struct {
 }
 
 func New(httpClient *httpx.Client, apiKey string) *Client {
-	return &Client{http: httpClient, baseURL: defaultBase, apiKey: apiKey, now: time.Now}
+	return &Client{http: httpClient, baseURL: defaultBase, apiKey: apiKey, now: clock.Now}
 }
 
 func (c *Client) Info() model.ProviderInfo {
@@ -36,12 +39,21 @@ func (c *Client) Info() model.ProviderInfo {
 		KeyEnvVarName: "DEFI_1INCH_API_KEY",
 		Capabilities: []string{
 			"swap.quote",
+			"swap.limit_order",
 		},
 		CapabilityAuth: []model.ProviderCapabilityAuth{
 			{
 				Capability: "swap.quote",
 				KeyEnvVar:  "DEFI_1INCH_API_KEY",
 			},
+			{
+				Capability: "swap.limit_order",
+				KeyEnvVar:  "DEFI_1INCH_API_KEY",
+			},
+		},
+		Support: []model.CapabilitySupport{
+			{Capability: "swap.quote", Chains: []string{providers.AnyEVMChain}, Features: []string{providers.FeatureExactInput, providers.FeatureExactOutput}},
+			{Capability: "swap.limit_order", Chains: providers.EVMChains(registry.OneInchLimitOrderChainIDs())},
 		},
 	}
 }
@@ -56,8 +68,10 @@ func (c *Client) QuoteSwap(ctx context.Context, req providers.SwapQuoteRequest)
 	if tradeType == "" {
 		tradeType = providers.SwapTradeTypeExactInput
 	}
-	if tradeType != providers.SwapTradeTypeExactInput {
-		return model.SwapQuote{}, clierr.New(clierr.CodeUnsupported, "1inch supports only --type exact-input")
+	switch tradeType {
+	case providers.SwapTradeTypeExactInput, providers.SwapTradeTypeExactOutput:
+	default:
+		return model.SwapQuote{}, clierr.New(clierr.CodeUnsupported, "1inch swap type must be exact-input or exact-output")
 	}
 
 	if !req.Chain.IsEVM() {
@@ -66,42 +80,62 @@ func (c *Client) QuoteSwap(ctx context.Context, req providers.SwapQuoteRequest)
 	if c.apiKey == "" {
 		return model.SwapQuote{}, clierr.New(clierr.CodeAuth, "missing required API key for 1inch (DEFI_1INCH_API_KEY)")
 	}
-	chainID := strconv.FormatInt(req.Chain.EVMChainID, 10)
-	vals := url.Values{}
-	vals.Set("src", req.FromAsset.Address)
-	vals.Set("dst", req.ToAsset.Address)
-	vals.Set("amount", req.AmountBaseUnits)
-	vals.Set("includeGas", "true")
-
-	url := fmt.Sprintf("%s/swap/v6.0/%s/quote?%s", c.baseURL, chainID, vals.Encode())
-	hReq, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-	if err != nil {
-		return model.SwapQuote{}, clierr.Wrap(clierr.CodeInternal, "build 1inch quote request", err)
-	}
-	hReq.Header.Set("Authorization", "Bearer "+c.apiKey)
 
-	var resp quoteResponse
-	if _, err := c.http.DoJSON(ctx, hReq, &resp); err != nil {
-		return model.SwapQuote{}, err
-	}
-	if resp.DstAmount == "" {
-		return model.SwapQuote{}, clierr.New(clierr.CodeUnavailable, "1inch quote missing destination amount")
+	inputBase := req.AmountBaseUnits
+	inputDecimal := req.AmountDecimal
+	inputDecimals := req.FromAsset.Decimals
+	var dstAmount string
+	emulated := false
+	if tradeType == providers.SwapTradeTypeExactOutput {
+		// 1inch only quotes exact inputs, so the input is solved by search.
+		target, ok := new(big.Int).SetString(req.AmountBaseUnits, 10)
+		if !ok {
+			return model.SwapQuote{}, clierr.New(clierr.CodeUsage, "invalid exact-output amount")
+		}
+		if inputDecimals <= 0 {
+			inputDecimals = 18
+		}
+		seed := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(inputDecimals)), nil)
+		amountIn, amountOut, err := providers.SolveExactOutput(ctx, func(ctx context.Context, amountIn *big.Int) (*big.Int, error) {
+			dst, err := c.quoteDstAmount(ctx, req, amountIn.String())
+			if err != nil {
+				return nil, err
+			}
+			out, ok := new(big.Int).SetString(dst, 10)
+			if !ok {
+				return nil, clierr.New(clierr.CodeUnavailable, "1inch quote returned an invalid destination amount")
+			}
+			return out, nil
+		}, target, seed)
+		if err != nil {
+			return model.SwapQuote{}, err
+		}
+		inputBase = amountIn.String()
+		inputDecimal = id.FormatDecimalCompat(inputBase, inputDecimals)
+		dstAmount = amountOut.String()
+		emulated = true
+	} else {
+		var err error
+		dstAmount, err = c.quoteDstAmount(ctx, req, req.AmountBaseUnits)
+		if err != nil {
+			return model.SwapQuote{}, err
+		}
 	}
 
-	return model.SwapQuote{
+	quote := model.SwapQuote{
 		Provider:    "1inch",
 		ChainID:     req.Chain.CAIP2,
 		FromAssetID: req.FromAsset.AssetID,
 		ToAssetID:   req.ToAsset.AssetID,
 		TradeType:   string(tradeType),
 		InputAmount: model.AmountInfo{
-			AmountBaseUnits: req.AmountBaseUnits,
-			AmountDecimal:   req.AmountDecimal,
-			Decimals:        req.FromAsset.Decimals,
+			AmountBaseUnits: inputBase,
+			AmountDecimal:   inputDecimal,
+			Decimals:        inputDecimals,
 		},
 		EstimatedOut: model.AmountInfo{
-			AmountBaseUnits: resp.DstAmount,
-			AmountDecimal:   id.FormatDecimalCompat(resp.DstAmount, req.ToAsset.Decimals),
+			AmountBaseUnits: dstAmount,
+			AmountDecimal:   id.FormatDecimalCompat(dstAmount, req.ToAsset.Decimals),
 			Decimals:        req.ToAsset.Decimals,
 		},
 		EstimatedGasUSD: 0,
@@ -109,5 +143,54 @@ func (c *Client) QuoteSwap(ctx context.Context, req providers.SwapQuoteRequest)
 		Route:           "1inch",
 		SourceURL:       "https://app.1inch.io",
 		FetchedAt:       c.now().UTC().Format(time.RFC3339),
-	}, nil
+		Emulated:        emulated,
+	}
+	// The quote endpoint takes no slippage; min_out is derived from it.
+	providers.ApplySwapSlippage(&quote, providers.SwapSlippagePct(req))
+	return quote, nil
+}
+
+// quoteDstAmount returns the destination amount 1inch quotes for an exact
+// input of amount base units.
+func (c *Client) quoteDstAmount(ctx context.Context, req providers.SwapQuoteRequest, amount string) (string, error) {
+	chainID := strconv.FormatInt(req.Chain.EVMChainID, 10)
+	vals := url.Values{}
+	vals.Set("src", req.FromAsset.Address)
+	vals.Set("dst", req.ToAsset.Address)
+	vals.Set("amount", amount)
+	vals.Set("includeGas", "true")
+
+	url := fmt.Sprintf("%s/swap/v6.0/%s/quote?%s", c.baseURL, chainID, vals.Encode())
+	hReq, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", clierr.Wrap(clierr.CodeInternal, "build 1inch quote request", err)
+	}
+	hReq.Header.Set("Authorization", "Bearer "+c.apiKey)
+
+	var resp quoteResponse
+	if _, err := c.http.DoJSON(ctx, hReq, &resp); err != nil {
+		return "", err
+	}
+	if resp.DstAmount == "" {
+		return "", clierr.New(clierr.CodeUnavailable, "1inch quote missing destination amount")
+	}
+	return resp.DstAmount, nil
+}
+
+// VerifyKey checks the API key against the approve spender endpoint, which
+// is authenticated but does no routing.
+func (c *Client) VerifyKey(ctx context.Context) error {
+	if c.apiKey == "" {
+		return providers.ErrNoAPIKey
+	}
+	hReq, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/swap/v6.0/1/approve/spender", nil)
+	if err != nil {
+		return clierr.Wrap(clierr.CodeInternal, "build 1inch spender request", err)
+	}
+	hReq.Header.Set("Authorization", "Bearer "+c.apiKey)
+	var resp struct {
+		Address string `json:"address"`
+	}
+	_, err = c.http.DoJSON(ctx, hReq, &resp)
+	return err
 }
@@ -2,9 +2,15 @@ package oneinch
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
 	"github.com/ggonzalez94/defi-cli/internal/httpx"
 	"github.com/ggonzalez94/defi-cli/internal/id"
 	"github.com/ggonzalez94/defi-cli/internal/providers"
@@ -36,20 +42,80 @@ func TestQuoteSwapRejectsNonEVMChain(t *testing.T) {
 	}
 }
 
-func TestQuoteSwapRejectsExactOutput(t *testing.T) {
+func TestQuoteSwapEmulatesExactOutput(t *testing.T) {
+	// 1 USDC (6 decimals) buys 0.999 DAI (18 decimals), with no price impact.
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		amount, ok := new(big.Int).SetString(r.URL.Query().Get("amount"), 10)
+		if !ok {
+			t.Fatalf("invalid amount in %s", r.URL.RawQuery)
+		}
+		out := new(big.Int).Mul(amount, big.NewInt(999_000_000_000))
+		_, _ = fmt.Fprintf(w, `{"dstAmount":"%s"}`, out.String())
+	}))
+	defer srv.Close()
+
 	chain, _ := id.ParseChain("ethereum")
 	assetIn, _ := id.ParseAsset("USDC", chain)
 	assetOut, _ := id.ParseAsset("DAI", chain)
-	c := New(httpx.New(1*time.Second, 0), "test-key")
-	_, err := c.QuoteSwap(context.Background(), providers.SwapQuoteRequest{
+	c := New(httpx.New(2*time.Second, 0), "test-key")
+	c.baseURL = srv.URL
+	got, err := c.QuoteSwap(context.Background(), providers.SwapQuoteRequest{
 		Chain:           chain,
 		FromAsset:       assetIn,
 		ToAsset:         assetOut,
-		AmountBaseUnits: "1000000000000000000",
-		AmountDecimal:   "1",
+		AmountBaseUnits: "1000000000000000000000",
+		AmountDecimal:   "1000",
 		TradeType:       providers.SwapTradeTypeExactOutput,
 	})
-	if err == nil {
-		t.Fatal("expected unsupported exact-output error")
+	if err != nil {
+		t.Fatalf("QuoteSwap failed: %v", err)
+	}
+	if !got.Emulated || got.TradeType != "exact-output" {
+		t.Fatalf("expected emulated exact-output quote, got %+v", got)
+	}
+	in, _ := new(big.Int).SetString(got.InputAmount.AmountBaseUnits, 10)
+	out, _ := new(big.Int).SetString(got.EstimatedOut.AmountBaseUnits, 10)
+	target, _ := new(big.Int).SetString("1000000000000000000000", 10)
+	if out.Cmp(target) < 0 {
+		t.Fatalf("expected output to cover the target, got %s", out)
+	}
+	// The exact answer is 1001.001002 USDC; the search stops within 5 bps.
+	if in.Cmp(big.NewInt(1001001002)) < 0 || in.Cmp(big.NewInt(1001600000)) > 0 {
+		t.Fatalf("unexpected solved input: %s", in)
+	}
+	if got.MaxIn == nil || got.MinOut == nil || got.MinOut.AmountBaseUnits != got.EstimatedOut.AmountBaseUnits {
+		t.Fatalf("expected max_in and min_out on exact-output quote, got %+v", got)
+	}
+	if calls > 12 {
+		t.Fatalf("expected at most 12 upstream quotes, got %d", calls)
+	}
+}
+
+func TestVerifyKey(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/swap/v6.0/1/approve/spender" {
+			t.Fatalf("unexpected path %s", r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Bearer good" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"address":"0x111111125421ca6dc452d289314280a0f8842a65"}`))
+	}))
+	defer srv.Close()
+
+	if err := New(httpx.New(time.Second, 0), "").VerifyKey(context.Background()); !errors.Is(err, providers.ErrNoAPIKey) {
+		t.Fatalf("expected ErrNoAPIKey, got %v", err)
+	}
+	c := New(httpx.New(time.Second, 0), "good")
+	c.baseURL = srv.URL
+	if err := c.VerifyKey(context.Background()); err != nil {
+		t.Fatalf("expected a valid key, got %v", err)
+	}
+	c.apiKey = "bad"
+	if typed, ok := clierr.As(c.VerifyKey(context.Background())); !ok || typed.Code != clierr.CodeAuth {
+		t.Fatal("expected a rejected key to be an auth error")
 	}
 }
@@ -0,0 +1,256 @@
+package oneinch
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/execution"
+	"github.com/ggonzalez94/defi-cli/internal/httpx"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/providers"
+	"github.com/ggonzalez94/defi-cli/internal/registry"
+)
+
+var routerABI = mustABI(registry.OneInchLimitOrderRouterABI)
+
+type orderData struct {
+	MakerAsset   string `json:"makerAsset"`
+	TakerAsset   string `json:"takerAsset"`
+	Maker        string `json:"maker"`
+	Receiver     string `json:"receiver"`
+	MakingAmount string `json:"makingAmount"`
+	TakingAmount string `json:"takingAmount"`
+	Salt         string `json:"salt"`
+	Extension    string `json:"extension"`
+	MakerTraits  string `json:"makerTraits"`
+}
+
+type createOrderRequest struct {
+	OrderHash string    `json:"orderHash"`
+	Signature string    `json:"signature"`
+	Data      orderData `json:"data"`
+}
+
+type orderStatusResponse struct {
+	OrderHash            string `json:"orderHash"`
+	RemainingMakerAmount string `json:"remainingMakerAmount"`
+	OrderInvalidReason   any    `json:"orderInvalidReason"`
+}
+
+func (c *Client) CreateLimitOrder(ctx context.Context, req providers.LimitOrderRequest, sign providers.LimitOrderSigner) (execution.LimitOrder, error) {
+	if !req.Chain.IsEVM() {
+		return execution.LimitOrder{}, clierr.New(clierr.CodeUnsupported, "1inch limit orders support only EVM chains")
+	}
+	if c.apiKey == "" {
+		return execution.LimitOrder{}, clierr.New(clierr.CodeAuth, "missing required API key for 1inch (DEFI_1INCH_API_KEY)")
+	}
+	if sign == nil {
+		return execution.LimitOrder{}, clierr.New(clierr.CodeSigner, "1inch limit orders require a local signer")
+	}
+	routerRaw, ok := registry.OneInchLimitOrderRouter(req.Chain.EVMChainID)
+	if !ok {
+		return execution.LimitOrder{}, clierr.New(clierr.CodeUnsupported, fmt.Sprintf("1inch limit orders are not supported on chain %d", req.Chain.EVMChainID))
+	}
+	if !common.IsHexAddress(req.Maker) {
+		return execution.LimitOrder{}, clierr.New(clierr.CodeUsage, "limit order maker must be an EVM address")
+	}
+	receiver := common.Address{}
+	if strings.TrimSpace(req.Receiver) != "" {
+		if !common.IsHexAddress(req.Receiver) {
+			return execution.LimitOrder{}, clierr.New(clierr.CodeUsage, "limit order receiver must be an EVM address")
+		}
+		receiver = common.HexToAddress(req.Receiver)
+	}
+
+	salt, err := randomUint(96)
+	if err != nil {
+		return execution.LimitOrder{}, err
+	}
+	nonce, err := randomUint(40)
+	if err != nil {
+		return execution.LimitOrder{}, err
+	}
+	order := execution.LimitOrder{
+		Protocol:     execution.LimitOrderProtocolOneInch,
+		Maker:        common.HexToAddress(req.Maker).Hex(),
+		Receiver:     receiver.Hex(),
+		MakerAsset:   common.HexToAddress(req.FromAsset.Address).Hex(),
+		TakerAsset:   common.HexToAddress(req.ToAsset.Address).Hex(),
+		MakingAmount: req.MakingAmount,
+		TakingAmount: req.TakingAmount,
+		ExpiresAt:    req.ExpiresAt.UTC().Format(time.RFC3339),
+		Salt:         salt.String(),
+		MakerTraits:  execution.OneInchMakerTraits(req.ExpiresAt, nonce.Uint64(), req.AllowPartialFill).String(),
+	}
+	digest, err := execution.OneInchLimitOrderDigest(req.Chain.EVMChainID, common.HexToAddress(routerRaw), order)
+	if err != nil {
+		return execution.LimitOrder{}, err
+	}
+	sig, err := sign(digest)
+	if err != nil {
+		return execution.LimitOrder{}, clierr.Wrap(clierr.CodeSigner, "sign 1inch limit order", err)
+	}
+	if len(sig) != 65 {
+		return execution.LimitOrder{}, clierr.New(clierr.CodeSigner, "limit order signature must be 65 bytes")
+	}
+	sig = append([]byte(nil), sig...)
+	if sig[64] < 27 {
+		sig[64] += 27
+	}
+	order.OrderID = common.BytesToHash(digest).Hex()
+	order.Signature = "0x" + common.Bytes2Hex(sig)
+
+	body, err := json.Marshal(createOrderRequest{
+		OrderHash: order.OrderID,
+		Signature: order.Signature,
+		Data: orderData{
+			MakerAsset:   order.MakerAsset,
+			TakerAsset:   order.TakerAsset,
+			Maker:        order.Maker,
+			Receiver:     order.Receiver,
+			MakingAmount: order.MakingAmount,
+			TakingAmount: order.TakingAmount,
+			Salt:         order.Salt,
+			Extension:    "0x",
+			MakerTraits:  order.MakerTraits,
+		},
+	})
+	if err != nil {
+		return execution.LimitOrder{}, clierr.Wrap(clierr.CodeInternal, "encode 1inch limit order", err)
+	}
+	url := fmt.Sprintf("%s/orderbook/v4.0/%d", c.baseURL, req.Chain.EVMChainID)
+	if _, err := httpx.DoBodyJSON(ctx, c.http, http.MethodPost, url, body, c.authHeaders(), nil); err != nil {
+		return execution.LimitOrder{}, err
+	}
+	order.Status = execution.LimitOrderStatusOpen
+	order.CheckedAt = c.now().UTC().Format(time.RFC3339)
+	return order, nil
+}
+
+func (c *Client) RefreshLimitOrder(ctx context.Context, chain id.Chain, order *execution.LimitOrder) error {
+	if order == nil || strings.TrimSpace(order.OrderID) == "" {
+		return clierr.New(clierr.CodeUsage, "limit order has no order hash")
+	}
+	if c.apiKey == "" {
+		return clierr.New(clierr.CodeAuth, "missing required API key for 1inch (DEFI_1INCH_API_KEY)")
+	}
+	url := fmt.Sprintf("%s/orderbook/v4.0/%d/order/%s", c.baseURL, chain.EVMChainID, order.OrderID)
+	var resp orderStatusResponse
+	if _, err := httpx.DoBodyJSON(ctx, c.http, http.MethodGet, url, nil, c.authHeaders(), &resp); err != nil {
+		return err
+	}
+	now := c.now().UTC()
+	order.CheckedAt = now.Format(time.RFC3339)
+
+	making, okMaking := new(big.Int).SetString(order.MakingAmount, 10)
+	remaining, okRemaining := new(big.Int).SetString(strings.TrimSpace(resp.RemainingMakerAmount), 10)
+	if okMaking && okRemaining {
+		filled := new(big.Int).Sub(making, remaining)
+		if filled.Sign() < 0 {
+			filled.SetInt64(0)
+		}
+		order.FilledMakingAmount = filled.String()
+		switch {
+		case remaining.Sign() == 0:
+			order.Status = execution.LimitOrderStatusFilled
+			order.StatusReason = ""
+			return nil
+		case filled.Sign() > 0:
+			order.Status = execution.LimitOrderStatusPartiallyFilled
+		default:
+			order.Status = execution.LimitOrderStatusOpen
+		}
+	}
+	if reason := invalidReason(resp.OrderInvalidReason); reason != "" {
+		order.StatusReason = reason
+		if strings.Contains(strings.ToLower(reason), "cancel") {
+			order.Status = execution.LimitOrderStatusCancelled
+			return nil
+		}
+	}
+	if order.Expired(now) {
+		order.Status = execution.LimitOrderStatusExpired
+	}
+	return nil
+}
+
+func (c *Client) CancelLimitOrder(_ context.Context, chain id.Chain, order *execution.LimitOrder) ([]execution.ActionStep, error) {
+	if order == nil || strings.TrimSpace(order.OrderID) == "" {
+		return nil, clierr.New(clierr.CodeUsage, "limit order has no order hash")
+	}
+	routerRaw, ok := registry.OneInchLimitOrderRouter(chain.EVMChainID)
+	if !ok {
+		return nil, clierr.New(clierr.CodeUnsupported, fmt.Sprintf("1inch limit orders are not supported on chain %d", chain.EVMChainID))
+	}
+	traits, ok := new(big.Int).SetString(order.MakerTraits, 10)
+	if !ok {
+		return nil, clierr.New(clierr.CodeUsage, "limit order has invalid maker traits")
+	}
+	rpcURL, err := registry.ResolveRPCURL("", chain.EVMChainID)
+	if err != nil {
+		return nil, err
+	}
+	data, err := routerABI.Pack("cancelOrder", traits, common.HexToHash(order.OrderID))
+	if err != nil {
+		return nil, clierr.Wrap(clierr.CodeInternal, "pack cancelOrder calldata", err)
+	}
+	return []execution.ActionStep{{
+		StepID:      "cancel-limit-order",
+		Type:        execution.StepTypeCancelOrder,
+		Status:      execution.StepStatusPending,
+		ChainID:     chain.CAIP2,
+		RPCURL:      rpcURL,
+		Description: "Cancel 1inch limit order on-chain",
+		Target:      common.HexToAddress(routerRaw).Hex(),
+		Data:        "0x" + common.Bytes2Hex(data),
+		Value:       "0",
+	}}, nil
+}
+
+func (c *Client) authHeaders() map[string]string {
+	return map[string]string{"Authorization": "Bearer " + c.apiKey}
+}
+
+// invalidReason flattens the order book's invalid reason, which is either a
+// string or a list of strings.
+func invalidReason(raw any) string {
+	switch v := raw.(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case []any:
+		parts := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok && strings.TrimSpace(s) != "" {
+				parts = append(parts, strings.TrimSpace(s))
+			}
+		}
+		return strings.Join(parts, "; ")
+	}
+	return ""
+}
+
+func randomUint(bits uint) (*big.Int, error) {
+	limit := new(big.Int).Lsh(big.NewInt(1), bits)
+	n, err := rand.Int(rand.Reader, limit)
+	if err != nil {
+		return nil, clierr.Wrap(clierr.CodeInternal, "generate random order salt", err)
+	}
+	return n, nil
+}
+
+func mustABI(raw string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(raw))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
@@ -0,0 +1,118 @@
+package oneinch
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ggonzalez94/defi-cli/internal/execution"
+	"github.com/ggonzalez94/defi-cli/internal/httpx"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/providers"
+)
+
+func TestCreateLimitOrderSignsAndPostsOrder(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	maker := crypto.PubkeyToAddress(key.PublicKey)
+	var posted createOrderRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/orderbook/v4.0/1" {
+			t.Fatalf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Bearer test-key" {
+			t.Fatalf("missing auth header")
+		}
+		if err := json.NewDecoder(r.Body).Decode(&posted); err != nil {
+			t.Fatalf("decode body: %v", err)
+		}
+		_, _ = w.Write([]byte(`{"success":true}`))
+	}))
+	defer srv.Close()
+
+	chain, _ := id.ParseChain("ethereum")
+	usdc, _ := id.ParseAsset("USDC", chain)
+	weth, _ := id.ParseAsset("WETH", chain)
+	c := New(httpx.New(2*time.Second, 0), "test-key")
+	c.baseURL = srv.URL
+	order, err := c.CreateLimitOrder(context.Background(), providers.LimitOrderRequest{
+		Chain:            chain,
+		FromAsset:        usdc,
+		ToAsset:          weth,
+		MakingAmount:     "1000000000",
+		TakingAmount:     "300000000000000000",
+		Maker:            maker.Hex(),
+		ExpiresAt:        time.Now().Add(time.Hour),
+		AllowPartialFill: true,
+	}, func(digest []byte) ([]byte, error) { return crypto.Sign(digest, key) })
+	if err != nil {
+		t.Fatalf("CreateLimitOrder failed: %v", err)
+	}
+	if order.Status != execution.LimitOrderStatusOpen || order.OrderID != posted.OrderHash {
+		t.Fatalf("unexpected order %+v (posted %s)", order, posted.OrderHash)
+	}
+	if posted.Data.Maker != maker.Hex() || posted.Data.MakerTraits != order.MakerTraits || posted.Data.Extension != "0x" {
+		t.Fatalf("unexpected posted order data %+v", posted.Data)
+	}
+
+	// The posted signature must recover to the maker over the order hash.
+	sig := append([]byte(nil), mustDecodeHex(t, posted.Signature)...)
+	sig[64] -= 27
+	pub, err := crypto.SigToPub(mustDecodeHex(t, posted.OrderHash), sig)
+	if err != nil || crypto.PubkeyToAddress(*pub) != maker {
+		t.Fatalf("signature does not recover to the maker: %v", err)
+	}
+}
+
+func TestRefreshLimitOrderTracksFillsAndCancellation(t *testing.T) {
+	remaining := "400"
+	reason := `null`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/orderbook/v4.0/1/order/0xabc") {
+			t.Fatalf("unexpected path %s", r.URL.Path)
+		}
+		_, _ = w.Write([]byte(`{"orderHash":"0xabc","remainingMakerAmount":"` + remaining + `","orderInvalidReason":` + reason + `}`))
+	}))
+	defer srv.Close()
+
+	chain, _ := id.ParseChain("ethereum")
+	c := New(httpx.New(2*time.Second, 0), "test-key")
+	c.baseURL = srv.URL
+	order := &execution.LimitOrder{OrderID: "0xabc", MakingAmount: "1000", Status: execution.LimitOrderStatusOpen}
+	if err := c.RefreshLimitOrder(context.Background(), chain, order); err != nil {
+		t.Fatalf("RefreshLimitOrder failed: %v", err)
+	}
+	if order.Status != execution.LimitOrderStatusPartiallyFilled || order.FilledMakingAmount != "600" {
+		t.Fatalf("expected a partial fill of 600, got %+v", order)
+	}
+
+	reason = `["order cancelled"]`
+	if err := c.RefreshLimitOrder(context.Background(), chain, order); err != nil {
+		t.Fatalf("RefreshLimitOrder failed: %v", err)
+	}
+	if order.Status != execution.LimitOrderStatusCancelled || !order.Final() {
+		t.Fatalf("expected a cancelled order, got %+v", order)
+	}
+
+	remaining, reason = "0", `null`
+	if err := c.RefreshLimitOrder(context.Background(), chain, order); err != nil {
+		t.Fatalf("RefreshLimitOrder failed: %v", err)
+	}
+	if order.Status != execution.LimitOrderStatusFilled || order.FilledMakingAmount != "1000" {
+		t.Fatalf("expected a filled order, got %+v", order)
+	}
+}
+
+func mustDecodeHex(t *testing.T, value string) []byte {
+	t.Helper()
+	out, err := hexutil.Decode(value)
+	if err != nil {
+		t.Fatalf("decode %s: %v", value, err)
+	}
+	return out
+}
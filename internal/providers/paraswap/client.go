@@ -0,0 +1,442 @@
+package paraswap
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ggonzalez94/defi-cli/internal/clock"
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/execution"
+	"github.com/ggonzalez94/defi-cli/internal/httpx"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/model"
+	"github.com/ggonzalez94/defi-cli/internal/providers"
+	"github.com/ggonzalez94/defi-cli/internal/registry"
+	"github.com/ggonzalez94/defi-cli/internal/rpcx"
+)
+
+const (
+	defaultBase = "https://api.paraswap.io"
+	apiVersion  = "6.2"
+	// nativeToken is the placeholder ParaSwap uses for the chain's gas token.
+	nativeToken = "0xEeeeeEeeeEeEeeEeEeEeeEEEeeeeEeeeeeeeEEeE"
+)
+
+var erc20ABI = mustABI(registry.ERC20MinimalABI)
+
+type Client struct {
+	http    *httpx.Client
+	baseURL string
+	now     func() time.Time
+}
+
+func New(httpClient *httpx.Client) *Client {
+	return &Client{http: httpClient, baseURL: defaultBase, now: clock.Now}
+}
+
+func (c *Client) Info() model.ProviderInfo {
+	return model.ProviderInfo{
+		Name:        "paraswap",
+		Type:        "swap",
+		RequiresKey: false,
+		Capabilities: []string{
+			"swap.quote",
+			"swap.plan",
+			"swap.execute",
+		},
+		Support: []model.CapabilitySupport{
+			{Capability: "swap.quote", Chains: providers.EVMChains(registry.ParaSwapChainIDs()), Features: []string{providers.FeatureExactInput}},
+			{Capability: "swap.plan", Chains: providers.EVMChains(registry.ParaSwapChainIDs()), Features: []string{providers.FeatureExactInput}},
+			{Capability: "swap.execute", Chains: providers.EVMChains(registry.ParaSwapChainIDs())},
+		},
+	}
+}
+
+type priceResponse struct {
+	PriceRoute json.RawMessage `json:"priceRoute"`
+	Error      string          `json:"error"`
+}
+
+type priceRoute struct {
+	SrcAmount       string      `json:"srcAmount"`
+	DestAmount      string      `json:"destAmount"`
+	GasCostUSD      string      `json:"gasCostUSD"`
+	SrcUSD          string      `json:"srcUSD"`
+	DestUSD         string      `json:"destUSD"`
+	ContractAddress string      `json:"contractAddress"`
+	BestRoute       []routePath `json:"bestRoute"`
+}
+
+type routePath struct {
+	Percent float64     `json:"percent"`
+	Swaps   []routeSwap `json:"swaps"`
+}
+
+type routeSwap struct {
+	SrcToken      string          `json:"srcToken"`
+	DestToken     string          `json:"destToken"`
+	SwapExchanges []routeExchange `json:"swapExchanges"`
+}
+
+type routeExchange struct {
+	Exchange string  `json:"exchange"`
+	Percent  float64 `json:"percent"`
+}
+
+type transactionRequest struct {
+	SrcToken     string          `json:"srcToken"`
+	SrcDecimals  int             `json:"srcDecimals"`
+	DestToken    string          `json:"destToken"`
+	DestDecimals int             `json:"destDecimals"`
+	SrcAmount    string          `json:"srcAmount"`
+	Slippage     int64           `json:"slippage"`
+	PriceRoute   json.RawMessage `json:"priceRoute"`
+	UserAddress  string          `json:"userAddress"`
+	Receiver     string          `json:"receiver,omitempty"`
+}
+
+type transactionResponse struct {
+	To    string `json:"to"`
+	Data  string `json:"data"`
+	Value string `json:"value"`
+	Error string `json:"error"`
+}
+
+func (c *Client) QuoteSwap(ctx context.Context, req providers.SwapQuoteRequest) (model.SwapQuote, error) {
+	route, _, err := c.price(ctx, req, req.Swapper)
+	if err != nil {
+		return model.SwapQuote{}, err
+	}
+	hops := routeHops(route.BestRoute)
+	return model.SwapQuote{
+		Provider:    "paraswap",
+		ChainID:     req.Chain.CAIP2,
+		FromAssetID: req.FromAsset.AssetID,
+		ToAssetID:   req.ToAsset.AssetID,
+		TradeType:   string(providers.SwapTradeTypeExactInput),
+		InputAmount: model.AmountInfo{
+			AmountBaseUnits: req.AmountBaseUnits,
+			AmountDecimal:   req.AmountDecimal,
+			Decimals:        req.FromAsset.Decimals,
+		},
+		EstimatedOut: model.AmountInfo{
+			AmountBaseUnits: route.DestAmount,
+			AmountDecimal:   id.FormatDecimalCompat(route.DestAmount, req.ToAsset.Decimals),
+			Decimals:        req.ToAsset.Decimals,
+		},
+		EstimatedGasUSD: parseFloat(route.GasCostUSD),
+		PriceImpactPct:  priceImpactPct(route.SrcUSD, route.DestUSD),
+		Route:           routeSummary(hops),
+		RouteHops:       hops,
+		SourceURL:       "https://app.velora.xyz",
+		FetchedAt:       c.now().UTC().Format(time.RFC3339),
+	}, nil
+}
+
+func (c *Client) BuildSwapAction(ctx context.Context, req providers.SwapQuoteRequest, opts providers.SwapExecutionOptions) (execution.Action, error) {
+	sender := strings.TrimSpace(opts.Sender)
+	if sender == "" {
+		return execution.Action{}, clierr.New(clierr.CodeUsage, "swap execution requires sender address")
+	}
+	if !common.IsHexAddress(sender) {
+		return execution.Action{}, clierr.New(clierr.CodeUsage, "swap execution sender must be a valid EVM address")
+	}
+	recipient := strings.TrimSpace(opts.Recipient)
+	if recipient == "" {
+		recipient = sender
+	}
+	if !common.IsHexAddress(recipient) {
+		return execution.Action{}, clierr.New(clierr.CodeUsage, "swap execution recipient must be a valid EVM address")
+	}
+	senderAddr := common.HexToAddress(sender)
+	recipientAddr := common.HexToAddress(recipient)
+	augustus, ok := registry.ParaSwapAugustus(req.Chain.EVMChainID)
+	if !ok {
+		return execution.Action{}, clierr.New(clierr.CodeUnsupported, "paraswap execution does not support chain "+req.Chain.Slug)
+	}
+	augustusAddr := common.HexToAddress(augustus)
+	rpcURL, err := registry.ResolveRPCURL(opts.RPCURL, req.Chain.EVMChainID)
+	if err != nil {
+		return execution.Action{}, clierr.Wrap(clierr.CodeUsage, "resolve rpc url", err)
+	}
+	slippage := opts.SlippageBps
+	if slippage <= 0 {
+		slippage = 50
+	}
+	if slippage >= 10_000 {
+		return execution.Action{}, clierr.New(clierr.CodeUsage, "slippage bps must be less than 10000")
+	}
+	amountIn, ok := new(big.Int).SetString(req.AmountBaseUnits, 10)
+	if !ok || amountIn.Sign() <= 0 {
+		return execution.Action{}, clierr.New(clierr.CodeUsage, "swap amount must be a positive integer in base units")
+	}
+
+	route, rawRoute, err := c.price(ctx, req, senderAddr.Hex())
+	if err != nil {
+		return execution.Action{}, err
+	}
+	if !strings.EqualFold(strings.TrimSpace(route.ContractAddress), augustusAddr.Hex()) {
+		return execution.Action{}, clierr.New(clierr.CodeActionPlan, "paraswap price route targets an unexpected contract")
+	}
+	quotedOut, ok := new(big.Int).SetString(route.DestAmount, 10)
+	if !ok || quotedOut.Sign() <= 0 {
+		return execution.Action{}, clierr.New(clierr.CodeUnavailable, "paraswap price route has invalid destination amount")
+	}
+	amountOutMin := new(big.Int).Mul(quotedOut, big.NewInt(10_000-slippage))
+	amountOutMin.Div(amountOutMin, big.NewInt(10_000))
+
+	srcToken, destToken := tokenAddress(req.FromAsset), tokenAddress(req.ToAsset)
+	body, err := json.Marshal(transactionRequest{
+		SrcToken:     srcToken,
+		SrcDecimals:  req.FromAsset.Decimals,
+		DestToken:    destToken,
+		DestDecimals: req.ToAsset.Decimals,
+		SrcAmount:    amountIn.String(),
+		Slippage:     slippage,
+		PriceRoute:   rawRoute,
+		UserAddress:  senderAddr.Hex(),
+		Receiver:     recipientAddr.Hex(),
+	})
+	if err != nil {
+		return execution.Action{}, clierr.Wrap(clierr.CodeInternal, "encode paraswap transaction request", err)
+	}
+	endpoint := fmt.Sprintf("%s/transactions/%d?ignoreChecks=true", strings.TrimRight(c.baseURL, "/"), req.Chain.EVMChainID)
+	var tx transactionResponse
+	if _, err := httpx.DoBodyJSON(ctx, c.http, http.MethodPost, endpoint, body, nil, &tx); err != nil {
+		return execution.Action{}, err
+	}
+	if strings.TrimSpace(tx.Error) != "" {
+		return execution.Action{}, clierr.New(clierr.CodeUnavailable, "paraswap transaction build failed: "+tx.Error)
+	}
+	if !common.IsHexAddress(tx.To) || common.HexToAddress(tx.To) != augustusAddr {
+		return execution.Action{}, clierr.New(clierr.CodeActionPlan, "paraswap transaction targets an unexpected contract")
+	}
+	if strings.TrimSpace(tx.Data) == "" {
+		return execution.Action{}, clierr.New(clierr.CodeUnavailable, "paraswap transaction missing calldata")
+	}
+	value := strings.TrimSpace(tx.Value)
+	if value == "" {
+		value = "0"
+	}
+	native := isNative(req.FromAsset)
+	if native && value != amountIn.String() {
+		return execution.Action{}, clierr.New(clierr.CodeActionPlan, "paraswap transaction value does not match native input amount")
+	}
+	if !native && value != "0" {
+		return execution.Action{}, clierr.New(clierr.CodeActionPlan, "paraswap transaction sends native value for a token input")
+	}
+
+	action := execution.NewAction(execution.NewActionID(), "swap", req.Chain.CAIP2, execution.Constraints{SlippageBps: slippage, Simulate: opts.Simulate})
+	action.Provider = "paraswap"
+	action.FromAddress = senderAddr.Hex()
+	action.ToAddress = recipientAddr.Hex()
+	action.InputAmount = amountIn.String()
+	action.Metadata = map[string]any{
+		"token_in":       srcToken,
+		"token_out":      destToken,
+		"router":         augustusAddr.Hex(),
+		"route":          routeSummary(routeHops(route.BestRoute)),
+		"quoted_amount":  quotedOut.String(),
+		"amount_out_min": amountOutMin.String(),
+	}
+
+	if !native {
+		token := common.HexToAddress(srcToken)
+		allowance, err := readAllowance(ctx, rpcURL, token, senderAddr, augustusAddr)
+		if err != nil {
+			return execution.Action{}, err
+		}
+		if allowance.Cmp(amountIn) < 0 {
+			approveData, err := erc20ABI.Pack("approve", augustusAddr, amountIn)
+			if err != nil {
+				return execution.Action{}, clierr.Wrap(clierr.CodeInternal, "pack approve calldata", err)
+			}
+			action.Steps = append(action.Steps, execution.ActionStep{
+				StepID:      "approve-augustus",
+				Type:        execution.StepTypeApproval,
+				Status:      execution.StepStatusPending,
+				ChainID:     req.Chain.CAIP2,
+				RPCURL:      rpcURL,
+				Description: "Approve token spending for ParaSwap Augustus router",
+				Target:      token.Hex(),
+				Data:        "0x" + common.Bytes2Hex(approveData),
+				Value:       "0",
+			})
+		}
+	}
+
+	action.Steps = append(action.Steps, execution.ActionStep{
+		StepID:      "paraswap-swap",
+		Type:        execution.StepTypeSwap,
+		Status:      execution.StepStatusPending,
+		ChainID:     req.Chain.CAIP2,
+		RPCURL:      rpcURL,
+		Description: "Swap via ParaSwap Augustus router",
+		Target:      augustusAddr.Hex(),
+		Data:        tx.Data,
+		Value:       value,
+		ExpectedOutputs: map[string]string{
+			"amount_out_min": amountOutMin.String(),
+		},
+	})
+	return action, nil
+}
+
+// price fetches the best route for an exact-input swap. The raw route is
+// returned alongside the decoded one because the transactions endpoint
+// requires it unchanged.
+func (c *Client) price(ctx context.Context, req providers.SwapQuoteRequest, userAddress string) (priceRoute, json.RawMessage, error) {
+	if req.TradeType != "" && req.TradeType != providers.SwapTradeTypeExactInput {
+		return priceRoute{}, nil, clierr.New(clierr.CodeUnsupported, "paraswap supports only --type exact-input")
+	}
+	if !req.Chain.IsEVM() {
+		return priceRoute{}, nil, clierr.New(clierr.CodeUnsupported, "paraswap supports only EVM chains")
+	}
+	vals := url.Values{}
+	vals.Set("srcToken", tokenAddress(req.FromAsset))
+	vals.Set("srcDecimals", strconv.Itoa(req.FromAsset.Decimals))
+	vals.Set("destToken", tokenAddress(req.ToAsset))
+	vals.Set("destDecimals", strconv.Itoa(req.ToAsset.Decimals))
+	vals.Set("amount", req.AmountBaseUnits)
+	vals.Set("side", "SELL")
+	vals.Set("network", strconv.FormatInt(req.Chain.EVMChainID, 10))
+	vals.Set("version", apiVersion)
+	if addr := strings.TrimSpace(userAddress); addr != "" && common.IsHexAddress(addr) {
+		vals.Set("userAddress", common.HexToAddress(addr).Hex())
+	}
+
+	hReq, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(c.baseURL, "/")+"/prices?"+vals.Encode(), nil)
+	if err != nil {
+		return priceRoute{}, nil, clierr.Wrap(clierr.CodeInternal, "build paraswap price request", err)
+	}
+	var resp priceResponse
+	if _, err := c.http.DoJSON(ctx, hReq, &resp); err != nil {
+		return priceRoute{}, nil, err
+	}
+	if strings.TrimSpace(resp.Error) != "" {
+		return priceRoute{}, nil, clierr.New(clierr.CodeUnavailable, "paraswap price route failed: "+resp.Error)
+	}
+	if len(resp.PriceRoute) == 0 {
+		return priceRoute{}, nil, clierr.New(clierr.CodeUnavailable, "paraswap response missing price route")
+	}
+	var route priceRoute
+	if err := json.Unmarshal(resp.PriceRoute, &route); err != nil {
+		return priceRoute{}, nil, clierr.Wrap(clierr.CodeUnavailable, "decode paraswap price route", err)
+	}
+	if strings.TrimSpace(route.DestAmount) == "" {
+		return priceRoute{}, nil, clierr.New(clierr.CodeUnavailable, "paraswap price route missing destination amount")
+	}
+	return route, resp.PriceRoute, nil
+}
+
+// routeHops flattens ParaSwap's split routes into one entry per exchange leg.
+func routeHops(paths []routePath) []model.RouteHop {
+	var hops []model.RouteHop
+	for p, path := range paths {
+		for h, swap := range path.Swaps {
+			for _, ex := range swap.SwapExchanges {
+				hops = append(hops, model.RouteHop{
+					Path:      p,
+					Hop:       h,
+					FromToken: swap.SrcToken,
+					ToToken:   swap.DestToken,
+					Exchange:  ex.Exchange,
+					SharePct:  path.Percent * ex.Percent / 100,
+				})
+			}
+		}
+	}
+	return hops
+}
+
+// routeSummary lists the distinct exchanges in route order, matching the
+// "a > b" form other aggregators use so protocol deny rules apply per hop.
+func routeSummary(hops []model.RouteHop) string {
+	seen := make(map[string]struct{}, len(hops))
+	parts := make([]string, 0, len(hops))
+	for _, hop := range hops {
+		name := strings.TrimSpace(hop.Exchange)
+		if name == "" {
+			continue
+		}
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		seen[name] = struct{}{}
+		parts = append(parts, name)
+	}
+	if len(parts) == 0 {
+		return "paraswap"
+	}
+	return strings.Join(parts, " > ")
+}
+
+func priceImpactPct(srcUSD, destUSD string) float64 {
+	src, dest := parseFloat(srcUSD), parseFloat(destUSD)
+	if src <= 0 || dest <= 0 || dest >= src {
+		return 0
+	}
+	return (src - dest) / src * 100
+}
+
+func tokenAddress(asset id.Asset) string {
+	if isNative(asset) {
+		return nativeToken
+	}
+	return common.HexToAddress(asset.Address).Hex()
+}
+
+func isNative(asset id.Asset) bool {
+	return strings.EqualFold(strings.TrimSpace(asset.Address), nativeToken)
+}
+
+func readAllowance(ctx context.Context, rpcURL string, token, owner, spender common.Address) (*big.Int, error) {
+	client, err := rpcx.DialContext(ctx, rpcURL)
+	if err != nil {
+		return nil, clierr.Wrap(clierr.CodeUnavailable, "connect rpc", err)
+	}
+	defer client.Close()
+	data, err := erc20ABI.Pack("allowance", owner, spender)
+	if err != nil {
+		return nil, clierr.Wrap(clierr.CodeInternal, "pack allowance call", err)
+	}
+	out, err := client.CallContract(ctx, ethereum.CallMsg{From: owner, To: &token, Data: data}, nil)
+	if err != nil {
+		return nil, clierr.Wrap(clierr.CodeUnavailable, "read allowance", err)
+	}
+	values, err := erc20ABI.Unpack("allowance", out)
+	if err != nil || len(values) == 0 {
+		return nil, clierr.Wrap(clierr.CodeUnavailable, "decode allowance", err)
+	}
+	allowance, ok := values[0].(*big.Int)
+	if !ok {
+		return nil, clierr.New(clierr.CodeUnavailable, "invalid allowance response")
+	}
+	return allowance, nil
+}
+
+func parseFloat(v string) float64 {
+	f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+	if err != nil {
+		return 0
+	}
+	return f
+}
+
+func mustABI(raw string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(raw))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
@@ -0,0 +1,183 @@
+package paraswap
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ggonzalez94/defi-cli/internal/execution"
+	"github.com/ggonzalez94/defi-cli/internal/httpx"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/providers"
+)
+
+const testPriceRoute = `{"priceRoute":{
+	"srcAmount":"1000000","destAmount":"400000000000000","gasCostUSD":"1.25","srcUSD":"1.00","destUSD":"0.99",
+	"contractAddress":"0x6A000F20005980200259B80c5102003040001068",
+	"bestRoute":[
+		{"percent":60,"swaps":[{"srcToken":"0xa0b8","destToken":"0xc02a","swapExchanges":[{"exchange":"UniswapV3","percent":100}]}]},
+		{"percent":40,"swaps":[
+			{"srcToken":"0xa0b8","destToken":"0xdac1","swapExchanges":[{"exchange":"CurveV1","percent":50},{"exchange":"UniswapV3","percent":50}]},
+			{"srcToken":"0xdac1","destToken":"0xc02a","swapExchanges":[{"exchange":"BalancerV2","percent":100}]}
+		]}
+	]}}`
+
+type fakeAPI struct {
+	t       *testing.T
+	prices  []string
+	txBody  map[string]any
+	txReply string
+}
+
+func (f *fakeAPI) handler(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/prices":
+		f.prices = append(f.prices, r.URL.RawQuery)
+		_, _ = w.Write([]byte(testPriceRoute))
+	case r.Method == http.MethodPost && r.URL.Path == "/transactions/1":
+		if err := json.NewDecoder(r.Body).Decode(&f.txBody); err != nil {
+			f.t.Fatalf("decode transaction request: %v", err)
+		}
+		_, _ = w.Write([]byte(f.txReply))
+	default:
+		f.t.Fatalf("unexpected request %s %s", r.Method, r.URL.String())
+	}
+}
+
+func newAllowanceRPCServer(allowanceHex string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		w.Header().Set("Content-Type", "application/json")
+		if req.Method != "eth_call" {
+			_, _ = fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"error":{"code":-32601,"message":"unsupported"}}`, req.ID)
+			return
+		}
+		_, _ = fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":"0x%064s"}`, req.ID, allowanceHex)
+	}))
+}
+
+func testAssets(t *testing.T) (id.Chain, id.Asset, id.Asset) {
+	t.Helper()
+	chain, err := id.ParseChain("ethereum")
+	if err != nil {
+		t.Fatalf("parse chain: %v", err)
+	}
+	usdc, _ := id.ParseAsset("USDC", chain)
+	weth, _ := id.ParseAsset("WETH", chain)
+	return chain, usdc, weth
+}
+
+func TestQuoteSwapNormalizesSplitRoute(t *testing.T) {
+	api := &fakeAPI{t: t}
+	server := httptest.NewServer(http.HandlerFunc(api.handler))
+	defer server.Close()
+
+	c := New(httpx.New(2*time.Second, 0))
+	c.baseURL = server.URL
+	chain, usdc, weth := testAssets(t)
+	quote, err := c.QuoteSwap(context.Background(), providers.SwapQuoteRequest{
+		Chain: chain, FromAsset: usdc, ToAsset: weth, AmountBaseUnits: "1000000", AmountDecimal: "1",
+	})
+	if err != nil {
+		t.Fatalf("QuoteSwap failed: %v", err)
+	}
+	if len(api.prices) != 1 || !strings.Contains(api.prices[0], "side=SELL") || !strings.Contains(api.prices[0], "network=1") {
+		t.Fatalf("unexpected price query: %v", api.prices)
+	}
+	if quote.Provider != "paraswap" || quote.EstimatedOut.AmountBaseUnits != "400000000000000" || quote.EstimatedGasUSD != 1.25 {
+		t.Fatalf("unexpected quote: %+v", quote)
+	}
+	if quote.Route != "UniswapV3 > CurveV1 > BalancerV2" {
+		t.Fatalf("unexpected route summary: %q", quote.Route)
+	}
+	if len(quote.RouteHops) != 4 {
+		t.Fatalf("expected 4 route hops, got %+v", quote.RouteHops)
+	}
+	curve := quote.RouteHops[1]
+	if curve.Path != 1 || curve.Hop != 0 || curve.Exchange != "CurveV1" || curve.SharePct != 20 {
+		t.Fatalf("unexpected split hop: %+v", curve)
+	}
+	if last := quote.RouteHops[3]; last.Path != 1 || last.Hop != 1 || last.SharePct != 40 {
+		t.Fatalf("unexpected second-leg hop: %+v", last)
+	}
+	if quote.PriceImpactPct < 0.99 || quote.PriceImpactPct > 1.01 {
+		t.Fatalf("unexpected price impact: %v", quote.PriceImpactPct)
+	}
+}
+
+func TestQuoteSwapRejectsExactOutput(t *testing.T) {
+	c := New(httpx.New(2*time.Second, 0))
+	chain, usdc, weth := testAssets(t)
+	_, err := c.QuoteSwap(context.Background(), providers.SwapQuoteRequest{
+		Chain: chain, FromAsset: usdc, ToAsset: weth, AmountBaseUnits: "1", TradeType: providers.SwapTradeTypeExactOutput,
+	})
+	if err == nil || !strings.Contains(err.Error(), "exact-input") {
+		t.Fatalf("expected exact-input error, got %v", err)
+	}
+}
+
+func TestBuildSwapActionAddsApprovalAndRouterCall(t *testing.T) {
+	api := &fakeAPI{t: t, txReply: `{"to":"0x6a000f20005980200259b80c5102003040001068","data":"0xe3ead59e","value":"0"}`}
+	server := httptest.NewServer(http.HandlerFunc(api.handler))
+	defer server.Close()
+	rpc := newAllowanceRPCServer("0")
+	defer rpc.Close()
+
+	c := New(httpx.New(2*time.Second, 0))
+	c.baseURL = server.URL
+	chain, usdc, weth := testAssets(t)
+	action, err := c.BuildSwapAction(context.Background(), providers.SwapQuoteRequest{
+		Chain: chain, FromAsset: usdc, ToAsset: weth, AmountBaseUnits: "1000000", AmountDecimal: "1",
+	}, providers.SwapExecutionOptions{
+		Sender:      "0x00000000000000000000000000000000000000AA",
+		Recipient:   "0x00000000000000000000000000000000000000BB",
+		SlippageBps: 100,
+		RPCURL:      rpc.URL,
+	})
+	if err != nil {
+		t.Fatalf("BuildSwapAction failed: %v", err)
+	}
+	if len(action.Steps) != 2 || action.Steps[0].Type != execution.StepTypeApproval || action.Steps[1].Type != execution.StepTypeSwap {
+		t.Fatalf("expected approval + swap steps, got %+v", action.Steps)
+	}
+	swap := action.Steps[1]
+	if swap.Target != "0x6A000F20005980200259B80c5102003040001068" || swap.Data != "0xe3ead59e" || swap.Value != "0" {
+		t.Fatalf("unexpected swap step: %+v", swap)
+	}
+	if swap.ExpectedOutputs["amount_out_min"] != "396000000000000" {
+		t.Fatalf("unexpected min output: %+v", swap.ExpectedOutputs)
+	}
+	if api.txBody["slippage"] != float64(100) || !strings.EqualFold(fmt.Sprint(api.txBody["receiver"]), "0x00000000000000000000000000000000000000BB") || api.txBody["priceRoute"] == nil {
+		t.Fatalf("unexpected transaction request: %+v", api.txBody)
+	}
+	if action.Provider != "paraswap" || action.InputAmount != "1000000" || !strings.EqualFold(action.ToAddress, "0x00000000000000000000000000000000000000BB") {
+		t.Fatalf("unexpected action: %+v", action)
+	}
+}
+
+func TestBuildSwapActionRejectsUnexpectedRouter(t *testing.T) {
+	api := &fakeAPI{t: t, txReply: `{"to":"0x00000000000000000000000000000000000000CC","data":"0x01","value":"0"}`}
+	server := httptest.NewServer(http.HandlerFunc(api.handler))
+	defer server.Close()
+	rpc := newAllowanceRPCServer("ffffffff")
+	defer rpc.Close()
+
+	c := New(httpx.New(2*time.Second, 0))
+	c.baseURL = server.URL
+	chain, usdc, weth := testAssets(t)
+	_, err := c.BuildSwapAction(context.Background(), providers.SwapQuoteRequest{
+		Chain: chain, FromAsset: usdc, ToAsset: weth, AmountBaseUnits: "1000000",
+	}, providers.SwapExecutionOptions{Sender: "0x00000000000000000000000000000000000000AA", RPCURL: rpc.URL})
+	if err == nil || !strings.Contains(err.Error(), "unexpected contract") {
+		t.Fatalf("expected router mismatch error, got %v", err)
+	}
+}
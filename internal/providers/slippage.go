@@ -0,0 +1,69 @@
+package providers
+
+import (
+	"math"
+	"math/big"
+
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/model"
+)
+
+// DefaultSwapSlippagePct is the slippage tolerance of a swap quote request
+// without SlippagePct. It matches the 50 bps default of swap plans.
+const DefaultSwapSlippagePct = 0.5
+
+// SwapSlippagePct returns the slippage tolerance of req in percent.
+func SwapSlippagePct(req SwapQuoteRequest) float64 {
+	if req.SlippagePct != nil {
+		return *req.SlippagePct
+	}
+	return DefaultSwapSlippagePct
+}
+
+// SlippageBps converts a percent slippage tolerance to basis points.
+func SlippageBps(slippagePct float64) int64 {
+	return int64(math.Round(slippagePct * 100))
+}
+
+// ApplySwapSlippage sets the slippage tolerance of quote and its worst-case
+// amounts: min_out for exact-input quotes, and min_out (the requested
+// output) plus max_in for exact-output quotes. A MinOut or MaxIn the
+// provider already reported is kept.
+func ApplySwapSlippage(quote *model.SwapQuote, slippagePct float64) {
+	quote.SlippagePct = slippagePct
+	// Slippage in millionths keeps fractional basis points exact.
+	scaled := int64(math.Round(slippagePct * 10_000))
+	if quote.TradeType == string(SwapTradeTypeExactOutput) {
+		if quote.MinOut == nil {
+			out := quote.EstimatedOut
+			quote.MinOut = &out
+		}
+		if quote.MaxIn == nil {
+			quote.MaxIn = scaleAmount(quote.InputAmount, 1_000_000+scaled, true)
+		}
+		return
+	}
+	if quote.MinOut == nil {
+		quote.MinOut = scaleAmount(quote.EstimatedOut, 1_000_000-scaled, false)
+	}
+}
+
+// scaleAmount returns amount times factor/1e6, rounded up with ceil and
+// down otherwise, or nil when amount has no base units.
+func scaleAmount(amount model.AmountInfo, factor int64, ceil bool) *model.AmountInfo {
+	base, ok := new(big.Int).SetString(amount.AmountBaseUnits, 10)
+	if !ok || factor < 0 {
+		return nil
+	}
+	scaled := new(big.Int).Mul(base, big.NewInt(factor))
+	divisor := big.NewInt(1_000_000)
+	if ceil {
+		scaled.Add(scaled, new(big.Int).Sub(divisor, big.NewInt(1)))
+	}
+	scaled.Quo(scaled, divisor)
+	return &model.AmountInfo{
+		AmountBaseUnits: scaled.String(),
+		AmountDecimal:   id.FormatDecimalCompat(scaled.String(), amount.Decimals),
+		Decimals:        amount.Decimals,
+	}
+}
@@ -0,0 +1,517 @@
+// Package spark reads SparkLend reserves and the Sky savings rate (sDAI and
+// sUSDS) straight from Ethereum contracts. SparkLend is an Aave v3 fork, so
+// reserves decode the same way as Aave's.
+package spark
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ggonzalez94/defi-cli/internal/clock"
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/model"
+	"github.com/ggonzalez94/defi-cli/internal/providers"
+	"github.com/ggonzalez94/defi-cli/internal/providers/multicall"
+	"github.com/ggonzalez94/defi-cli/internal/providers/yieldutil"
+	"github.com/ggonzalez94/defi-cli/internal/registry"
+	"github.com/ggonzalez94/defi-cli/internal/rpcx"
+)
+
+const (
+	providerName   = "spark"
+	sourceURL      = "https://app.spark.fi"
+	secondsPerYear = 365 * 24 * 3600
+)
+
+// getReserveData returns a static tuple; these are its word offsets.
+const (
+	reserveWordConfiguration      = 0
+	reserveWordLiquidityRate      = 2
+	reserveWordVariableBorrowRate = 4
+	reserveWordAToken             = 8
+	reserveWordVariableDebtToken  = 10
+	reserveWords                  = 15
+)
+
+// rateSourcePot marks a savings vault that accrues the Maker Pot's dsr(),
+// see registry.SavingsVault.
+const rateSourcePot = "pot"
+
+var (
+	ray    = new(big.Float).SetFloat64(1e27)
+	rayInt = new(big.Int).Exp(big.NewInt(10), big.NewInt(27), nil)
+	zero   = common.Address{}
+)
+
+type Client struct {
+	now         func() time.Time
+	rpcOverride string
+}
+
+func New() *Client {
+	return &Client{now: clock.Now}
+}
+
+// SetRPCOverride sets the RPC URL used for contract reads. Pass "" to revert
+// to the chain default.
+func (c *Client) SetRPCOverride(url string) { c.rpcOverride = url }
+
+func (c *Client) Info() model.ProviderInfo {
+	return model.ProviderInfo{
+		Name:        providerName,
+		Type:        "lending+yield",
+		RequiresKey: false,
+		Capabilities: []string{
+			"lend.markets",
+			"lend.rates",
+			"yield.opportunities",
+		},
+		Support: providers.ChainSupport(providers.EVMChains(registry.SparkChainIDs()), "lend.markets", "lend.rates", "yield.opportunities"),
+	}
+}
+
+// reserve is one SparkLend reserve with amounts converted to USD.
+type reserve struct {
+	Pool        common.Address
+	Underlying  common.Address
+	SupplyAPY   float64 // percentage points
+	BorrowAPY   float64
+	SuppliedUSD float64
+	BorrowedUSD float64
+	Utilization float64
+}
+
+// savings is one Sky savings vault and the rate it accrues.
+type savings struct {
+	Vault  registry.SavingsVault
+	Asset  common.Address
+	APY    float64 // percentage points
+	TVLUSD float64
+}
+
+// ── LendingProvider ─────────────────────────────────────────────────────
+
+func (c *Client) LendMarkets(ctx context.Context, provider string, chain id.Chain, asset id.Asset) ([]model.LendMarket, error) {
+	r, ok, err := c.fetchReserve(ctx, chain, asset)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, clierr.New(clierr.CodeUnsupported, "no spark lending market for requested chain/asset")
+	}
+	assetID := canonicalAssetID(chain.CAIP2, r.Underlying)
+	return []model.LendMarket{{
+		Protocol:             providerName,
+		Provider:             providerName,
+		ChainID:              chain.CAIP2,
+		AssetID:              assetID,
+		ProviderNativeID:     providerNativeID(chain.CAIP2, r.Pool, r.Underlying),
+		ProviderNativeIDKind: model.NativeIDKindCompositeMarketAsset,
+		SupplyAPY:            r.SupplyAPY,
+		BorrowAPY:            r.BorrowAPY,
+		TVLUSD:               r.SuppliedUSD,
+		LiquidityUSD:         r.liquidityUSD(),
+		SourceURL:            sourceURL,
+		FetchedAt:            c.now().UTC().Format(time.RFC3339),
+	}}, nil
+}
+
+func (c *Client) LendRates(ctx context.Context, provider string, chain id.Chain, asset id.Asset) ([]model.LendRate, error) {
+	r, ok, err := c.fetchReserve(ctx, chain, asset)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, clierr.New(clierr.CodeUnsupported, "no spark lending rates for requested chain/asset")
+	}
+	return []model.LendRate{{
+		Protocol:             providerName,
+		Provider:             providerName,
+		ChainID:              chain.CAIP2,
+		AssetID:              canonicalAssetID(chain.CAIP2, r.Underlying),
+		ProviderNativeID:     providerNativeID(chain.CAIP2, r.Pool, r.Underlying),
+		ProviderNativeIDKind: model.NativeIDKindCompositeMarketAsset,
+		SupplyAPY:            r.SupplyAPY,
+		BorrowAPY:            r.BorrowAPY,
+		RateBasis:            yieldutil.RateBasisAPY,
+		Compounding:          yieldutil.CompoundingPerSecond,
+		Utilization:          r.Utilization,
+		SourceURL:            sourceURL,
+		FetchedAt:            c.now().UTC().Format(time.RFC3339),
+	}}, nil
+}
+
+// ── YieldProvider ───────────────────────────────────────────────────────
+
+// YieldOpportunities returns the SparkLend supply market for the asset and,
+// for DAI and USDS, the matching savings vault. Savings deposits are never
+// lent against collateral, so they carry no liquidation risk and can be
+// redeemed in full at any time.
+func (c *Client) YieldOpportunities(ctx context.Context, req providers.YieldRequest) ([]model.YieldOpportunity, error) {
+	underlying, client, err := c.connect(ctx, req.Chain, req.Asset)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	fetchedAt := c.now().UTC().Format(time.RFC3339)
+	candidates := make([]model.YieldOpportunity, 0, 3)
+	r, ok, err := c.readReserve(ctx, client, req.Chain, underlying)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		assetID := canonicalAssetID(req.Chain.CAIP2, r.Underlying)
+		nativeID := providerNativeID(req.Chain.CAIP2, r.Pool, r.Underlying)
+		candidates = append(candidates, model.YieldOpportunity{
+			OpportunityID:        hashOpportunity(req.Chain.CAIP2, nativeID, assetID),
+			Provider:             providerName,
+			Protocol:             providerName,
+			ChainID:              req.Chain.CAIP2,
+			AssetID:              assetID,
+			ProviderNativeID:     nativeID,
+			ProviderNativeIDKind: model.NativeIDKindCompositeMarketAsset,
+			Type:                 "lend",
+			APYBase:              r.SupplyAPY,
+			APYTotal:             r.SupplyAPY,
+			RateBasis:            yieldutil.RateBasisAPY,
+			Compounding:          yieldutil.CompoundingPerSecond,
+			TVLUSD:               r.SuppliedUSD,
+			LiquidityUSD:         r.liquidityUSD(),
+			WithdrawalTerms:      "variable",
+			ExitLiquidity:        yieldutil.ExitLiquidity(r.SuppliedUSD, r.liquidityUSD(), 0),
+			BackingAssets: []model.YieldBackingAsset{{
+				AssetID:  assetID,
+				Symbol:   req.Asset.Symbol,
+				SharePct: 100,
+			}},
+			SourceURL: sourceURL,
+			FetchedAt: fetchedAt,
+		})
+	}
+
+	vaults, err := c.readSavings(ctx, client, req.Chain, underlying)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range vaults {
+		assetID := canonicalAssetID(req.Chain.CAIP2, v.Asset)
+		nativeID := providerNativeID(req.Chain.CAIP2, common.HexToAddress(v.Vault.Address), v.Asset)
+		candidates = append(candidates, model.YieldOpportunity{
+			OpportunityID:        hashOpportunity(req.Chain.CAIP2, nativeID, assetID),
+			Provider:             providerName,
+			Protocol:             providerName,
+			ChainID:              req.Chain.CAIP2,
+			AssetID:              assetID,
+			ProviderNativeID:     nativeID,
+			ProviderNativeIDKind: model.NativeIDKindCompositeMarketAsset,
+			Type:                 "savings",
+			APYBase:              v.APY,
+			APYTotal:             v.APY,
+			RateBasis:            yieldutil.RateBasisAPY,
+			Compounding:          yieldutil.CompoundingPerSecond,
+			TVLUSD:               v.TVLUSD,
+			LiquidityUSD:         v.TVLUSD,
+			WithdrawalTerms:      "instant",
+			ExitLiquidity:        yieldutil.ExitLiquidity(v.TVLUSD, v.TVLUSD, 0),
+			BackingAssets: []model.YieldBackingAsset{{
+				AssetID:  assetID,
+				Symbol:   req.Asset.Symbol,
+				SharePct: 100,
+			}},
+			SourceURL: sourceURL + "/savings",
+			FetchedAt: fetchedAt,
+		})
+	}
+
+	out := make([]model.YieldOpportunity, 0, len(candidates))
+	for _, item := range candidates {
+		if (item.APYTotal == 0 || item.TVLUSD == 0) && !req.IncludeIncomplete {
+			continue
+		}
+		if item.APYTotal < req.MinAPY || item.TVLUSD < req.MinTVLUSD {
+			continue
+		}
+		out = append(out, item)
+	}
+	if len(out) == 0 {
+		return nil, clierr.New(clierr.CodeUnavailable, "no spark yield opportunities for requested chain/asset")
+	}
+	yieldutil.ApplyEffectiveAPY(out)
+	yieldutil.ApplyRisk(out)
+	yieldutil.Sort(out, req.SortBy)
+	if req.Limit <= 0 || req.Limit > len(out) {
+		req.Limit = len(out)
+	}
+	return out[:req.Limit], nil
+}
+
+// ── On-chain reads ──────────────────────────────────────────────────────
+
+// fetchReserve reads the SparkLend reserve of asset. ok is false when Spark
+// does not list the asset.
+func (c *Client) fetchReserve(ctx context.Context, chain id.Chain, asset id.Asset) (reserve, bool, error) {
+	underlying, client, err := c.connect(ctx, chain, asset)
+	if err != nil {
+		return reserve{}, false, err
+	}
+	defer client.Close()
+	return c.readReserve(ctx, client, chain, underlying)
+}
+
+func (c *Client) connect(ctx context.Context, chain id.Chain, asset id.Asset) (common.Address, *ethclient.Client, error) {
+	if _, ok := registry.SparkPoolAddressProvider(chain.EVMChainID); !chain.IsEVM() || !ok {
+		return common.Address{}, nil, clierr.New(clierr.CodeUnsupported, "spark supports only Ethereum mainnet")
+	}
+	underlying, err := underlyingAddress(chain, asset)
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+	rpcURL, err := registry.ResolveRPCURL(c.rpcOverride, chain.EVMChainID)
+	if err != nil {
+		return common.Address{}, nil, clierr.Wrap(clierr.CodeUnsupported, "resolve rpc url", err)
+	}
+	client, err := rpcx.DialContext(ctx, rpcURL)
+	if err != nil {
+		return common.Address{}, nil, clierr.Wrap(clierr.CodeUnavailable, "connect rpc", err)
+	}
+	return underlying, client, nil
+}
+
+func (c *Client) readReserve(ctx context.Context, client *ethclient.Client, chain id.Chain, underlying common.Address) (reserve, bool, error) {
+	providerAddr, _ := registry.SparkPoolAddressProvider(chain.EVMChainID)
+	provider := common.HexToAddress(providerAddr)
+	poolCD, _ := poolAddressProviderABI.Pack("getPool")
+	oracleCD, _ := poolAddressProviderABI.Pack("getPriceOracle")
+	addrs, err := multicall.Aggregate3(ctx, client, []multicall.Call{
+		{Target: provider, CallData: poolCD},
+		{Target: provider, CallData: oracleCD},
+	})
+	if err != nil {
+		return reserve{}, false, clierr.Wrap(clierr.CodeUnavailable, "multicall spark pool addresses", err)
+	}
+	pool, oracle := decodeAddress(addrs[0]), decodeAddress(addrs[1])
+	if pool == zero || oracle == zero {
+		return reserve{}, false, clierr.New(clierr.CodeUnavailable, "invalid spark pool addresses provider response")
+	}
+
+	reserveCD, _ := poolABI.Pack("getReserveData", underlying)
+	priceCD, _ := oracleABI.Pack("getAssetPrice", underlying)
+	results, err := multicall.Aggregate3(ctx, client, []multicall.Call{
+		{Target: pool, CallData: reserveCD},
+		{Target: oracle, CallData: priceCD},
+	})
+	if err != nil {
+		return reserve{}, false, clierr.Wrap(clierr.CodeUnavailable, "multicall spark reserve data", err)
+	}
+	data := results[0]
+	if !data.Success || len(data.ReturnData) < reserveWords*32 {
+		return reserve{}, false, nil
+	}
+	aToken := common.BytesToAddress(word(data.ReturnData, reserveWordAToken))
+	if aToken == zero {
+		return reserve{}, false, nil
+	}
+	debtToken := common.BytesToAddress(word(data.ReturnData, reserveWordVariableDebtToken))
+
+	supplyCD, _ := erc20SupplyABI.Pack("totalSupply")
+	supplies, err := multicall.Aggregate3(ctx, client, []multicall.Call{
+		{Target: aToken, CallData: supplyCD},
+		{Target: debtToken, CallData: supplyCD},
+	})
+	if err != nil {
+		return reserve{}, false, clierr.Wrap(clierr.CodeUnavailable, "multicall spark reserve supply", err)
+	}
+
+	configuration := new(big.Int).SetBytes(word(data.ReturnData, reserveWordConfiguration))
+	decimals := reserveDecimals(configuration)
+	// The oracle quotes USD with 8 decimals.
+	price := amount(decodeUint256(results[1]), 8)
+	supplied, borrowed := decodeUint256(supplies[0]), decodeUint256(supplies[1])
+	return reserve{
+		Pool:        pool,
+		Underlying:  underlying,
+		SupplyAPY:   yieldutil.PercentFromRatio(compoundAPR(new(big.Int).SetBytes(word(data.ReturnData, reserveWordLiquidityRate)))),
+		BorrowAPY:   yieldutil.PercentFromRatio(compoundAPR(new(big.Int).SetBytes(word(data.ReturnData, reserveWordVariableBorrowRate)))),
+		SuppliedUSD: amount(supplied, decimals) * price,
+		BorrowedUSD: amount(borrowed, decimals) * price,
+		Utilization: ratio(borrowed, supplied),
+	}, true, nil
+}
+
+// readSavings reads the savings vaults whose asset is underlying. Vault
+// assets are USD stablecoins and are valued at $1.
+func (c *Client) readSavings(ctx context.Context, client *ethclient.Client, chain id.Chain, underlying common.Address) ([]savings, error) {
+	vaults := registry.SparkSavingsVaults(chain.EVMChainID)
+	if len(vaults) == 0 {
+		return nil, nil
+	}
+	assetCD, _ := savingsABI.Pack("asset")
+	totalCD, _ := savingsABI.Pack("totalAssets")
+	calls := make([]multicall.Call, 0, len(vaults)*3)
+	for _, v := range vaults {
+		rateCD, _ := savingsABI.Pack(v.RateSource)
+		addr := common.HexToAddress(v.Address)
+		calls = append(calls,
+			multicall.Call{Target: addr, CallData: assetCD},
+			multicall.Call{Target: addr, CallData: totalCD},
+			multicall.Call{Target: addr, CallData: rateCD},
+		)
+	}
+	results, err := multicall.Aggregate3(ctx, client, calls)
+	if err != nil {
+		return nil, clierr.Wrap(clierr.CodeUnavailable, "multicall spark savings vaults", err)
+	}
+
+	out := make([]savings, 0, len(vaults))
+	var potCalls []multicall.Call
+	var potIndex []int
+	for i, v := range vaults {
+		asset := decodeAddress(results[i*3])
+		if asset != underlying {
+			continue
+		}
+		out = append(out, savings{
+			Vault:  v,
+			Asset:  asset,
+			TVLUSD: amount(decodeUint256(results[i*3+1]), 18),
+		})
+		rate := results[i*3+2]
+		if v.RateSource == rateSourcePot {
+			pot := decodeAddress(rate)
+			if pot == zero {
+				continue
+			}
+			dsrCD, _ := potABI.Pack("dsr")
+			potCalls = append(potCalls, multicall.Call{Target: pot, CallData: dsrCD})
+			potIndex = append(potIndex, len(out)-1)
+			continue
+		}
+		out[len(out)-1].APY = yieldutil.PercentFromRatio(compoundPerSecond(decodeUint256(rate)))
+	}
+	if len(potCalls) == 0 {
+		return out, nil
+	}
+	dsrs, err := multicall.Aggregate3(ctx, client, potCalls)
+	if err != nil {
+		return nil, clierr.Wrap(clierr.CodeUnavailable, "multicall maker pot dsr", err)
+	}
+	for i, idx := range potIndex {
+		out[idx].APY = yieldutil.PercentFromRatio(compoundPerSecond(decodeUint256(dsrs[i])))
+	}
+	return out, nil
+}
+
+func (r reserve) liquidityUSD() float64 {
+	return math.Max(r.SuppliedUSD-r.BorrowedUSD, 0)
+}
+
+// reserveDecimals reads bits 48-55 of a reserve configuration bitmap.
+func reserveDecimals(configuration *big.Int) int {
+	return int(new(big.Int).And(new(big.Int).Rsh(configuration, 48), big.NewInt(0xff)).Int64())
+}
+
+// underlyingAddress returns the ERC-20 the pool lists for asset. The pool
+// holds WETH for ETH inputs.
+func underlyingAddress(chain id.Chain, asset id.Asset) (common.Address, error) {
+	addr := strings.TrimSpace(asset.Address)
+	if asset.IsNative() {
+		wrapped, ok := id.WrappedNativeToken(chain)
+		if !ok {
+			return common.Address{}, clierr.New(clierr.CodeUnsupported, "no wrapped native token known for this chain")
+		}
+		addr = wrapped.Address
+	}
+	if !common.IsHexAddress(addr) {
+		return common.Address{}, clierr.New(clierr.CodeUsage, "spark requires an asset with a known contract address")
+	}
+	return common.HexToAddress(addr), nil
+}
+
+// compoundAPR converts a ray-scaled annual rate accrued per second to an APY
+// ratio.
+func compoundAPR(rate *big.Int) float64 {
+	v, _ := new(big.Float).Quo(new(big.Float).SetInt(rate), ray).Float64()
+	return math.Pow(1+v/secondsPerYear, secondsPerYear) - 1
+}
+
+// compoundPerSecond converts a ray-scaled per-second multiplier (1e27 means
+// no growth) to an APY ratio.
+func compoundPerSecond(multiplier *big.Int) float64 {
+	if multiplier.Sign() == 0 {
+		return 0
+	}
+	growth := new(big.Int).Sub(multiplier, rayInt)
+	perSecond, _ := new(big.Float).Quo(new(big.Float).SetInt(growth), ray).Float64()
+	return math.Expm1(secondsPerYear * math.Log1p(perSecond))
+}
+
+func amount(v *big.Int, decimals int) float64 {
+	out, _ := new(big.Float).Quo(new(big.Float).SetInt(v), new(big.Float).SetFloat64(math.Pow10(decimals))).Float64()
+	return out
+}
+
+func ratio(num, den *big.Int) float64 {
+	if den.Sign() == 0 {
+		return 0
+	}
+	out, _ := new(big.Float).Quo(new(big.Float).SetInt(num), new(big.Float).SetInt(den)).Float64()
+	return out
+}
+
+func word(data []byte, i int) []byte {
+	return data[i*32 : (i+1)*32]
+}
+
+func decodeUint256(r multicall.Result) *big.Int {
+	if !r.Success || len(r.ReturnData) < 32 {
+		return new(big.Int)
+	}
+	return new(big.Int).SetBytes(word(r.ReturnData, 0))
+}
+
+func decodeAddress(r multicall.Result) common.Address {
+	if !r.Success || len(r.ReturnData) < 32 {
+		return zero
+	}
+	return common.BytesToAddress(word(r.ReturnData, 0))
+}
+
+func canonicalAssetID(chainID string, addr common.Address) string {
+	return fmt.Sprintf("%s/erc20:%s", chainID, strings.ToLower(addr.Hex()))
+}
+
+func providerNativeID(chainID string, market, underlying common.Address) string {
+	return fmt.Sprintf("%s:%s:%s:%s", providerName, chainID, strings.ToLower(market.Hex()), strings.ToLower(underlying.Hex()))
+}
+
+func hashOpportunity(chainID, marketID, assetID string) string {
+	seed := strings.Join([]string{providerName, chainID, marketID, assetID}, "|")
+	h := sha1.Sum([]byte(seed))
+	return hex.EncodeToString(h[:])
+}
+
+var poolAddressProviderABI = mustABI(registry.AavePoolAddressProviderABI)
+var poolABI = mustABI(registry.AavePoolABI)
+var oracleABI = mustABI(registry.AaveOracleABI)
+var erc20SupplyABI = mustABI(registry.ERC20SupplyABI)
+var savingsABI = mustABI(registry.SkySavingsABI)
+var potABI = mustABI(registry.MakerPotABI)
+
+func mustABI(raw string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(raw))
+	if err != nil {
+		panic(fmt.Sprintf("invalid ABI: %v", err))
+	}
+	return parsed
+}
@@ -0,0 +1,289 @@
+package spark
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"math"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/providers"
+	"github.com/ggonzalez94/defi-cli/internal/providers/multicall"
+	"github.com/ggonzalez94/defi-cli/internal/registry"
+)
+
+var (
+	testProvider  = common.HexToAddress("0x02C3eA4e34C0cBd694D2adFa2c690EECbC1793eE")
+	testPool      = common.HexToAddress("0xC13e21B648A5Ee794902342038FF3aDAB66BE987")
+	testOracle    = common.HexToAddress("0x8105f69D9C41644c6A0803fDA7D03Aa70996cFD9")
+	testAToken    = common.HexToAddress("0x4DEDf26112B3Ec8eC46e7E31EA5e123490B05B8B")
+	testDebtToken = common.HexToAddress("0xf705d2B7e92B3F38e6ae7afaDAA2fEE110fE5914")
+	testPot       = common.HexToAddress("0x197E90f9FAD81970bA7976f33CbD77088E5D7cf7")
+	testSDAI      = common.HexToAddress("0x83F20F44975D03b1b09e64809B757c47f942BEeA")
+	testSUSDS     = common.HexToAddress("0xa3931d71877C0E7a3148CB7Eb4463524FEc27fbD")
+	testDAI       = common.HexToAddress("0x6B175474E89094C44Da98b954EedeAC495271d0F")
+	testUSDS      = common.HexToAddress("0xdC035D45d973E3EC169d2276DDab16f1e407384F")
+)
+
+var mainnet = id.Chain{CAIP2: "eip155:1", EVMChainID: 1}
+
+func uint256Word(v *big.Int) []byte {
+	return common.LeftPadBytes(v.Bytes(), 32)
+}
+
+func addressWord(addr common.Address) []byte {
+	return common.LeftPadBytes(addr.Bytes(), 32)
+}
+
+func exp10(n int64) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(n), nil)
+}
+
+// perSecondMultiplier returns the ray-scaled per-second multiplier that
+// compounds to apy over a year.
+func perSecondMultiplier(apy float64) *big.Int {
+	growth := new(big.Float).Mul(big.NewFloat(math.Log1p(apy)/secondsPerYear), ray)
+	out, _ := growth.Int(nil)
+	return out.Add(out, rayInt)
+}
+
+// reserveData encodes a DAI getReserveData tuple with 18 decimals, a 5%
+// supply APR, an 8% variable borrow APR and the test aToken/debt token.
+func reserveData() []byte {
+	words := make([][]byte, reserveWords)
+	for i := range words {
+		words[i] = make([]byte, 32)
+	}
+	words[reserveWordConfiguration] = uint256Word(new(big.Int).Lsh(big.NewInt(18), 48))
+	words[reserveWordLiquidityRate] = uint256Word(new(big.Int).Mul(big.NewInt(5), exp10(25)))
+	words[reserveWordVariableBorrowRate] = uint256Word(new(big.Int).Mul(big.NewInt(8), exp10(25)))
+	words[reserveWordAToken] = addressWord(testAToken)
+	words[reserveWordVariableDebtToken] = addressWord(testDebtToken)
+	var out []byte
+	for _, w := range words {
+		out = append(out, w...)
+	}
+	return out
+}
+
+func dispatch(to common.Address, data []byte) ([]byte, bool) {
+	selector := hex.EncodeToString(data[:4])
+	is := func(contract, name string) bool {
+		return selector == hex.EncodeToString(mustABI(contract).Methods[name].ID)
+	}
+	switch {
+	case to == testProvider && is(registry.AavePoolAddressProviderABI, "getPool"):
+		return addressWord(testPool), true
+	case to == testProvider && is(registry.AavePoolAddressProviderABI, "getPriceOracle"):
+		return addressWord(testOracle), true
+	case to == testPool && is(registry.AavePoolABI, "getReserveData"):
+		if common.BytesToAddress(data[4:36]) != testDAI {
+			return make([]byte, reserveWords*32), true
+		}
+		return reserveData(), true
+	case to == testOracle && is(registry.AaveOracleABI, "getAssetPrice"):
+		return uint256Word(exp10(8)), true
+	case to == testAToken && is(registry.ERC20SupplyABI, "totalSupply"):
+		return uint256Word(new(big.Int).Mul(big.NewInt(1_000_000), exp10(18))), true
+	case to == testDebtToken && is(registry.ERC20SupplyABI, "totalSupply"):
+		return uint256Word(new(big.Int).Mul(big.NewInt(800_000), exp10(18))), true
+	case to == testSDAI && is(registry.SkySavingsABI, "asset"):
+		return addressWord(testDAI), true
+	case to == testSDAI && is(registry.SkySavingsABI, "totalAssets"):
+		return uint256Word(new(big.Int).Mul(big.NewInt(2_000_000), exp10(18))), true
+	case to == testSDAI && is(registry.SkySavingsABI, "pot"):
+		return addressWord(testPot), true
+	case to == testPot && is(registry.MakerPotABI, "dsr"):
+		return uint256Word(perSecondMultiplier(0.05)), true
+	case to == testSUSDS && is(registry.SkySavingsABI, "asset"):
+		return addressWord(testUSDS), true
+	case to == testSUSDS && is(registry.SkySavingsABI, "totalAssets"):
+		return uint256Word(new(big.Int).Mul(big.NewInt(3_000_000), exp10(18))), true
+	case to == testSUSDS && is(registry.SkySavingsABI, "ssr"):
+		return uint256Word(perSecondMultiplier(0.065)), true
+	}
+	return nil, false
+}
+
+func newTestRPCServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	aggregate3 := hex.EncodeToString(multicall.ABI.Methods["aggregate3"].ID)
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+			ID     any               `json:"id"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		reply := func(result string) {
+			_ = json.NewEncoder(w).Encode(map[string]any{"jsonrpc": "2.0", "id": req.ID, "result": result})
+		}
+		if req.Method != "eth_call" {
+			reply("0x")
+			return
+		}
+		var call struct {
+			Data  string `json:"data"`
+			Input string `json:"input"`
+		}
+		_ = json.Unmarshal(req.Params[0], &call)
+		raw := call.Data
+		if raw == "" {
+			raw = call.Input
+		}
+		data, _ := hex.DecodeString(strings.TrimPrefix(raw, "0x"))
+		if len(data) < 4 || hex.EncodeToString(data[:4]) != aggregate3 {
+			reply("0x")
+			return
+		}
+		decoded, err := multicall.ABI.Methods["aggregate3"].Inputs.Unpack(data[4:])
+		if err != nil {
+			t.Errorf("unpack aggregate3: %v", err)
+			reply("0x")
+			return
+		}
+		calls := decoded[0].([]struct {
+			Target       common.Address `json:"target"`
+			AllowFailure bool           `json:"allowFailure"`
+			CallData     []byte         `json:"callData"`
+		})
+		type result struct {
+			Success    bool
+			ReturnData []byte
+		}
+		results := make([]result, len(calls))
+		for i, c := range calls {
+			out, ok := dispatch(c.Target, c.CallData)
+			results[i] = result{Success: ok, ReturnData: out}
+		}
+		encoded, err := multicall.ABI.Methods["aggregate3"].Outputs.Pack(results)
+		if err != nil {
+			t.Errorf("pack aggregate3: %v", err)
+			reply("0x")
+			return
+		}
+		reply("0x" + hex.EncodeToString(encoded))
+	}))
+}
+
+func newTestClient(t *testing.T) *Client {
+	t.Helper()
+	srv := newTestRPCServer(t)
+	t.Cleanup(srv.Close)
+	client := New()
+	client.SetRPCOverride(srv.URL)
+	client.now = func() time.Time { return time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC) }
+	return client
+}
+
+func TestLendMarketsAndRatesReadSparkReserve(t *testing.T) {
+	client := newTestClient(t)
+	dai := id.Asset{ChainID: mainnet.CAIP2, Address: testDAI.Hex(), Symbol: "DAI"}
+
+	markets, err := client.LendMarkets(context.Background(), "spark", mainnet, dai)
+	if err != nil {
+		t.Fatalf("LendMarkets failed: %v", err)
+	}
+	if len(markets) != 1 {
+		t.Fatalf("expected one market, got %+v", markets)
+	}
+	market := markets[0]
+	if market.Provider != "spark" || market.AssetID != "eip155:1/erc20:0x6b175474e89094c44da98b954eedeac495271d0f" {
+		t.Fatalf("unexpected market metadata %+v", market)
+	}
+	if !strings.Contains(market.ProviderNativeID, strings.ToLower(testPool.Hex())) {
+		t.Fatalf("expected pool address in provider native id, got %s", market.ProviderNativeID)
+	}
+	if math.Abs(market.TVLUSD-1_000_000) > 1e-6 || math.Abs(market.LiquidityUSD-200_000) > 1e-6 {
+		t.Fatalf("expected $1M supplied and $200k available, got %+v", market)
+	}
+
+	rates, err := client.LendRates(context.Background(), "spark", mainnet, dai)
+	if err != nil {
+		t.Fatalf("LendRates failed: %v", err)
+	}
+	if len(rates) != 1 {
+		t.Fatalf("expected one rate, got %+v", rates)
+	}
+	got := rates[0]
+	if want := (math.Exp(0.05) - 1) * 100; math.Abs(got.SupplyAPY-want) > 0.001 {
+		t.Fatalf("expected supply APY ~%.4f, got %.4f", want, got.SupplyAPY)
+	}
+	if want := (math.Exp(0.08) - 1) * 100; math.Abs(got.BorrowAPY-want) > 0.001 {
+		t.Fatalf("expected borrow APY ~%.4f, got %.4f", want, got.BorrowAPY)
+	}
+	if math.Abs(got.Utilization-0.8) > 1e-9 {
+		t.Fatalf("expected utilization 0.8, got %f", got.Utilization)
+	}
+
+	unlisted := id.Asset{ChainID: mainnet.CAIP2, Address: testUSDS.Hex()}
+	if _, err := client.LendRates(context.Background(), "spark", mainnet, unlisted); err == nil {
+		t.Fatal("expected error for an unlisted reserve")
+	} else if cErr, ok := clierr.As(err); !ok || cErr.Code != clierr.CodeUnsupported {
+		t.Fatalf("expected unsupported error for an unlisted reserve, got %v", err)
+	}
+	if _, err := client.LendMarkets(context.Background(), "spark", mainnet, unlisted); err == nil {
+		t.Fatal("expected error for an unlisted market")
+	} else if cErr, ok := clierr.As(err); !ok || cErr.Code != clierr.CodeUnsupported {
+		t.Fatalf("expected unsupported error for an unlisted market, got %v", err)
+	}
+}
+
+func TestYieldOpportunitiesIncludeSavingsRate(t *testing.T) {
+	client := newTestClient(t)
+	dai := id.Asset{ChainID: mainnet.CAIP2, Address: testDAI.Hex(), Symbol: "DAI"}
+
+	opps, err := client.YieldOpportunities(context.Background(), providers.YieldRequest{Chain: mainnet, Asset: dai, Limit: 10, SortBy: "apy_total"})
+	if err != nil {
+		t.Fatalf("YieldOpportunities failed: %v", err)
+	}
+	if len(opps) != 2 {
+		t.Fatalf("expected the lend market and sDAI, got %+v", opps)
+	}
+	byType := map[string]int{}
+	for i, opp := range opps {
+		byType[opp.Type] = i
+	}
+	savings := opps[byType["savings"]]
+	if !strings.Contains(savings.ProviderNativeID, strings.ToLower(testSDAI.Hex())) || savings.WithdrawalTerms != "instant" {
+		t.Fatalf("unexpected savings metadata %+v", savings)
+	}
+	if math.Abs(savings.APYTotal-5) > 1e-4 || math.Abs(savings.TVLUSD-2_000_000) > 1e-6 {
+		t.Fatalf("expected a 5%% DSR on $2M, got %+v", savings)
+	}
+	if savings.ExitLiquidity == nil || savings.ExitLiquidity.ImmediatePct != 100 {
+		t.Fatalf("expected savings to be fully redeemable, got %+v", savings.ExitLiquidity)
+	}
+	if lend := opps[byType["lend"]]; lend.WithdrawalTerms != "variable" || math.Abs(lend.LiquidityUSD-200_000) > 1e-6 {
+		t.Fatalf("unexpected lend opportunity %+v", lend)
+	}
+
+	usds := id.Asset{ChainID: mainnet.CAIP2, Address: testUSDS.Hex(), Symbol: "USDS"}
+	opps, err = client.YieldOpportunities(context.Background(), providers.YieldRequest{Chain: mainnet, Asset: usds, Limit: 10})
+	if err != nil {
+		t.Fatalf("YieldOpportunities failed: %v", err)
+	}
+	if len(opps) != 1 || opps[0].Type != "savings" || math.Abs(opps[0].APYTotal-6.5) > 1e-4 {
+		t.Fatalf("expected only sUSDS at the 6.5%% SSR, got %+v", opps)
+	}
+}
+
+func TestUnsupportedChain(t *testing.T) {
+	client := New()
+	base := id.Chain{CAIP2: "eip155:8453", EVMChainID: 8453}
+	_, err := client.LendRates(context.Background(), "spark", base, id.Asset{ChainID: base.CAIP2, Address: testDAI.Hex()})
+	if cErr, ok := clierr.As(err); !ok || cErr.Code != clierr.CodeUnsupported {
+		t.Fatalf("expected unsupported error, got %v", err)
+	}
+}
@@ -0,0 +1,290 @@
+package staking
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ggonzalez94/defi-cli/internal/clock"
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/httpx"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/model"
+	"github.com/ggonzalez94/defi-cli/internal/providers"
+	"github.com/ggonzalez94/defi-cli/internal/providers/yieldutil"
+)
+
+const (
+	defaultBase     = "https://yields.llama.fi"
+	ethereumCAIP2   = "eip155:1"
+	wethAddress     = "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2"
+	withdrawalTerms = "withdrawal queue; instant exit only via secondary market"
+
+	// poolsTTL is how long the shared /pools payload (several MB) is reused
+	// across stake rates, yield opportunities, and their equivalent-asset
+	// lookups.
+	poolsTTL = 60 * time.Second
+)
+
+// liquidStakingToken describes one supported LST and the DefiLlama yields
+// project that tracks it.
+type liquidStakingToken struct {
+	Protocol  string
+	Project   string
+	Symbol    string
+	Address   string
+	SourceURL string
+}
+
+var liquidStakingTokens = []liquidStakingToken{
+	{Protocol: "lido", Project: "lido", Symbol: "stETH", Address: "0xae7ab96520de3a18e5e111b5eaab095312d7fe84", SourceURL: "https://stake.lido.fi"},
+	{Protocol: "etherfi", Project: "ether.fi-stake", Symbol: "eETH", Address: "0x35fa164735182de50811e8e2e824cfb9b6118ac2", SourceURL: "https://app.ether.fi"},
+	{Protocol: "rocketpool", Project: "rocket-pool", Symbol: "rETH", Address: "0xae78736cd615f374d3085123a210448e74fc6393", SourceURL: "https://stake.rocketpool.net"},
+}
+
+type Client struct {
+	http    *httpx.Client
+	baseURL string
+	now     func() time.Time
+}
+
+func New(httpClient *httpx.Client) *Client {
+	return &Client{http: httpClient, baseURL: defaultBase, now: clock.Now}
+}
+
+func (c *Client) Info() model.ProviderInfo {
+	return model.ProviderInfo{
+		Name:        "staking",
+		Type:        "yield",
+		RequiresKey: false,
+		Capabilities: []string{
+			"yield.opportunities",
+			"stake.rates",
+		},
+	}
+}
+
+type poolsResponse struct {
+	Status string `json:"status"`
+	Data   []pool `json:"data"`
+}
+
+type pool struct {
+	Chain     string   `json:"chain"`
+	Project   string   `json:"project"`
+	Symbol    string   `json:"symbol"`
+	Pool      string   `json:"pool"`
+	TVLUSD    float64  `json:"tvlUsd"`
+	APYBase   *float64 `json:"apyBase"`
+	APYReward *float64 `json:"apyReward"`
+	APY       *float64 `json:"apy"`
+}
+
+type stakingPool struct {
+	Token liquidStakingToken
+	Pool  pool
+}
+
+// StakeRates returns the current staking APR and TVL of every supported
+// liquid staking token on Ethereum mainnet.
+func (c *Client) StakeRates(ctx context.Context, chain id.Chain) ([]model.StakeRate, error) {
+	if chain.CAIP2 != ethereumCAIP2 {
+		return nil, clierr.New(clierr.CodeUnsupported, "liquid staking rates are available only on Ethereum mainnet")
+	}
+	pools, err := c.fetchStakingPools(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	fetchedAt := c.now().UTC().Format(time.RFC3339)
+	out := make([]model.StakeRate, 0, len(pools))
+	for _, item := range pools {
+		out = append(out, model.StakeRate{
+			Provider:             "staking",
+			Protocol:             item.Token.Protocol,
+			ChainID:              ethereumCAIP2,
+			AssetID:              assetID(item.Token.Address),
+			Symbol:               item.Token.Symbol,
+			UnderlyingAssetID:    assetID(wethAddress),
+			APR:                  baseAPY(item.Pool),
+			TVLUSD:               nonNegative(item.Pool.TVLUSD),
+			WithdrawalTerms:      withdrawalTerms,
+			ProviderNativeID:     strings.TrimSpace(item.Pool.Pool),
+			ProviderNativeIDKind: model.NativeIDKindPoolID,
+			SourceURL:            item.Token.SourceURL,
+			FetchedAt:            fetchedAt,
+		})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].APR != out[j].APR {
+			return out[i].APR > out[j].APR
+		}
+		return out[i].Protocol < out[j].Protocol
+	})
+	return out, nil
+}
+
+// YieldOpportunities reports liquid staking as `type: staking` opportunities
+// for ETH/WETH, or for a specific LST when the asset is the token itself.
+func (c *Client) YieldOpportunities(ctx context.Context, req providers.YieldRequest) ([]model.YieldOpportunity, error) {
+	if req.Chain.CAIP2 != ethereumCAIP2 {
+		return nil, clierr.New(clierr.CodeUnsupported, "liquid staking opportunities are available only on Ethereum mainnet")
+	}
+	pools, err := c.fetchStakingPools(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	fetchedAt := c.now().UTC().Format(time.RFC3339)
+	out := make([]model.YieldOpportunity, 0, len(pools))
+	for _, item := range pools {
+		opportunityAsset, ok := matchAsset(item.Token, req.Asset)
+		if !ok {
+			continue
+		}
+		apyBase := baseAPY(item.Pool)
+		apyReward := 0.0
+		if item.Pool.APYReward != nil {
+			apyReward = nonNegative(*item.Pool.APYReward)
+		}
+		apyTotal := apyBase + apyReward
+		if item.Pool.APY != nil {
+			apyTotal = yieldutil.PositiveFirst(*item.Pool.APY, apyTotal)
+		}
+		tvl := nonNegative(item.Pool.TVLUSD)
+		if (apyTotal == 0 || tvl == 0) && !req.IncludeIncomplete {
+			continue
+		}
+		if apyTotal < req.MinAPY || tvl < req.MinTVLUSD {
+			continue
+		}
+
+		tokenID := assetID(item.Token.Address)
+		out = append(out, model.YieldOpportunity{
+			OpportunityID:        hashOpportunity(strings.Join([]string{"staking", ethereumCAIP2, item.Token.Protocol, tokenID}, "|")),
+			Provider:             "staking",
+			Protocol:             item.Token.Protocol,
+			ChainID:              ethereumCAIP2,
+			AssetID:              opportunityAsset,
+			ProviderNativeID:     strings.TrimSpace(item.Pool.Pool),
+			ProviderNativeIDKind: model.NativeIDKindPoolID,
+			Type:                 "staking",
+			APYBase:              apyBase,
+			APYReward:            apyReward,
+			APYTotal:             apyTotal,
+			RateBasis:            yieldutil.RateBasisAPR,
+			Compounding:          yieldutil.CompoundingDaily,
+			TVLUSD:               tvl,
+			LiquidityUSD:         0,
+			LockupDays:           0,
+			WithdrawalTerms:      withdrawalTerms,
+			BackingAssets: []model.YieldBackingAsset{{
+				AssetID:  tokenID,
+				Symbol:   item.Token.Symbol,
+				SharePct: 100,
+			}},
+			SourceURL: item.Token.SourceURL,
+			FetchedAt: fetchedAt,
+		})
+	}
+
+	if len(out) == 0 {
+		return nil, clierr.New(clierr.CodeUnavailable, "no liquid staking opportunities for requested chain/asset")
+	}
+	yieldutil.ApplyEffectiveAPY(out)
+	yieldutil.ApplyRisk(out)
+	yieldutil.Sort(out, req.SortBy)
+	if req.Limit <= 0 || req.Limit > len(out) {
+		req.Limit = len(out)
+	}
+	return out[:req.Limit], nil
+}
+
+// fetchStakingPools returns the largest Ethereum pool of each supported
+// protocol from the DefiLlama yields API.
+func (c *Client) fetchStakingPools(ctx context.Context) ([]stakingPool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(c.baseURL, "/")+"/pools", nil)
+	if err != nil {
+		return nil, clierr.Wrap(clierr.CodeInternal, "build staking pools request", err)
+	}
+	var resp poolsResponse
+	if err := c.http.DoSharedJSON(ctx, req, poolsTTL, &resp); err != nil {
+		return nil, err
+	}
+
+	best := map[string]pool{}
+	for _, item := range resp.Data {
+		if !strings.EqualFold(strings.TrimSpace(item.Chain), "Ethereum") {
+			continue
+		}
+		project := strings.ToLower(strings.TrimSpace(item.Project))
+		if existing, ok := best[project]; ok && existing.TVLUSD >= item.TVLUSD {
+			continue
+		}
+		best[project] = item
+	}
+
+	out := make([]stakingPool, 0, len(liquidStakingTokens))
+	for _, token := range liquidStakingTokens {
+		item, ok := best[token.Project]
+		if !ok {
+			continue
+		}
+		out = append(out, stakingPool{Token: token, Pool: item})
+	}
+	if len(out) == 0 {
+		return nil, clierr.New(clierr.CodeUnavailable, "no liquid staking pools returned by yields API")
+	}
+	return out, nil
+}
+
+// matchAsset reports whether the requested asset can be staked into token
+// and returns the asset ID the opportunity is quoted in.
+func matchAsset(token liquidStakingToken, asset id.Asset) (string, bool) {
+	address := strings.ToLower(strings.TrimSpace(asset.Address))
+	symbol := strings.TrimSpace(asset.Symbol)
+	switch {
+	case address != "" && address == token.Address:
+		return assetID(token.Address), true
+	case address == "" && strings.EqualFold(symbol, token.Symbol):
+		return assetID(token.Address), true
+	case id.SymbolsEquivalent(symbol, "ETH") || address == wethAddress:
+		if strings.TrimSpace(asset.AssetID) != "" {
+			return asset.AssetID, true
+		}
+		return assetID(wethAddress), true
+	default:
+		return "", false
+	}
+}
+
+func baseAPY(item pool) float64 {
+	if item.APYBase != nil {
+		return nonNegative(*item.APYBase)
+	}
+	if item.APY != nil {
+		return nonNegative(*item.APY)
+	}
+	return 0
+}
+
+func assetID(address string) string {
+	return ethereumCAIP2 + "/erc20:" + address
+}
+
+func nonNegative(v float64) float64 {
+	if math.IsNaN(v) || math.IsInf(v, 0) || v < 0 {
+		return 0
+	}
+	return v
+}
+
+func hashOpportunity(seed string) string {
+	sum := sha1.Sum([]byte(seed))
+	return hex.EncodeToString(sum[:])
+}
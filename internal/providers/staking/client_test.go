@@ -0,0 +1,112 @@
+package staking
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ggonzalez94/defi-cli/internal/httpx"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/providers"
+)
+
+const poolsFixture = `{
+	"status": "success",
+	"data": [
+		{"chain":"Ethereum","project":"lido","symbol":"STETH","pool":"pool-lido","tvlUsd":30000000000,"apyBase":2.9,"apyReward":null,"apy":2.9},
+		{"chain":"Ethereum","project":"ether.fi-stake","symbol":"WEETH","pool":"pool-etherfi","tvlUsd":6000000000,"apyBase":2.7,"apyReward":0.4,"apy":3.1},
+		{"chain":"Ethereum","project":"rocket-pool","symbol":"RETH","pool":"pool-rocket","tvlUsd":2000000000,"apyBase":2.6,"apyReward":null,"apy":2.6},
+		{"chain":"Ethereum","project":"rocket-pool","symbol":"RPL","pool":"pool-rocket-small","tvlUsd":1000,"apyBase":9.0,"apyReward":null,"apy":9.0},
+		{"chain":"Arbitrum","project":"lido","symbol":"WSTETH","pool":"pool-lido-arb","tvlUsd":50000000000,"apyBase":1.0,"apy":1.0},
+		{"chain":"Ethereum","project":"aave-v3","symbol":"USDC","pool":"pool-aave","tvlUsd":100,"apyBase":5,"apy":5}
+	]
+}`
+
+func newTestClient(t *testing.T) *Client {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/pools" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(poolsFixture))
+	}))
+	t.Cleanup(srv.Close)
+
+	c := New(httpx.New(2*time.Second, 0))
+	c.baseURL = srv.URL
+	c.now = func() time.Time { return time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC) }
+	return c
+}
+
+func TestStakeRatesReturnsSupportedTokens(t *testing.T) {
+	c := newTestClient(t)
+	chain, _ := id.ParseChain("ethereum")
+
+	rates, err := c.StakeRates(context.Background(), chain)
+	if err != nil {
+		t.Fatalf("StakeRates failed: %v", err)
+	}
+	if len(rates) != 3 {
+		t.Fatalf("expected 3 staking rates, got %d: %+v", len(rates), rates)
+	}
+	if rates[0].Protocol != "lido" || rates[0].APR != 2.9 || rates[0].Symbol != "stETH" {
+		t.Fatalf("unexpected top rate: %+v", rates[0])
+	}
+	for _, rate := range rates {
+		if rate.Protocol == "rocketpool" && rate.ProviderNativeID != "pool-rocket" {
+			t.Fatalf("expected largest rocket pool pool, got %s", rate.ProviderNativeID)
+		}
+		if rate.UnderlyingAssetID != "eip155:1/erc20:"+wethAddress {
+			t.Fatalf("unexpected underlying asset: %s", rate.UnderlyingAssetID)
+		}
+	}
+}
+
+func TestStakeRatesRejectsNonMainnet(t *testing.T) {
+	c := New(httpx.New(2*time.Second, 0))
+	chain, _ := id.ParseChain("base")
+	if _, err := c.StakeRates(context.Background(), chain); err == nil {
+		t.Fatal("expected unsupported chain error")
+	}
+}
+
+func TestYieldOpportunitiesForWETHAndLST(t *testing.T) {
+	c := newTestClient(t)
+	chain, _ := id.ParseChain("ethereum")
+	weth, _ := id.ParseAsset("WETH", chain)
+
+	items, err := c.YieldOpportunities(context.Background(), providers.YieldRequest{Chain: chain, Asset: weth, SortBy: "apy_total"})
+	if err != nil {
+		t.Fatalf("YieldOpportunities failed: %v", err)
+	}
+	if len(items) != 3 {
+		t.Fatalf("expected 3 opportunities, got %d", len(items))
+	}
+	top := items[0]
+	if top.Protocol != "etherfi" || top.Type != "staking" || top.APYTotal != 3.1 || top.APYReward != 0.4 {
+		t.Fatalf("unexpected top opportunity: %+v", top)
+	}
+	if top.AssetID != weth.AssetID {
+		t.Fatalf("expected WETH asset id, got %s", top.AssetID)
+	}
+
+	steth, err := id.ParseAsset("stETH", chain)
+	if err != nil {
+		t.Fatalf("parse stETH: %v", err)
+	}
+	items, err = c.YieldOpportunities(context.Background(), providers.YieldRequest{Chain: chain, Asset: steth})
+	if err != nil {
+		t.Fatalf("YieldOpportunities(stETH) failed: %v", err)
+	}
+	if len(items) != 1 || items[0].Protocol != "lido" {
+		t.Fatalf("expected only lido opportunity, got %+v", items)
+	}
+
+	usdc, _ := id.ParseAsset("USDC", chain)
+	if _, err := c.YieldOpportunities(context.Background(), providers.YieldRequest{Chain: chain, Asset: usdc}); err == nil {
+		t.Fatal("expected no opportunities for USDC")
+	}
+}
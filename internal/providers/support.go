@@ -0,0 +1,45 @@
+package providers
+
+import (
+	"slices"
+	"strconv"
+
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/model"
+)
+
+// AnyEVMChain in CapabilitySupport.Chains matches every EVM chain.
+const AnyEVMChain = id.NamespaceEIP155 + ":*"
+
+// Swap features listed in CapabilitySupport.Features.
+const (
+	FeatureExactInput  = string(SwapTradeTypeExactInput)
+	FeatureExactOutput = string(SwapTradeTypeExactOutput)
+)
+
+// EVMChains converts EVM chain IDs to CAIP-2 identifiers.
+func EVMChains(chainIDs []int64) []string {
+	chains := make([]string, 0, len(chainIDs))
+	for _, chainID := range chainIDs {
+		chains = append(chains, id.NamespaceEIP155+":"+strconv.FormatInt(chainID, 10))
+	}
+	return chains
+}
+
+// SupportsChain reports whether support covers chain.
+func SupportsChain(support model.CapabilitySupport, chain id.Chain) bool {
+	if slices.Contains(support.Chains, chain.CAIP2) {
+		return true
+	}
+	return chain.IsEVM() && slices.Contains(support.Chains, AnyEVMChain)
+}
+
+// ChainSupport returns one CapabilitySupport per capability, all limited to
+// chains.
+func ChainSupport(chains []string, capabilities ...string) []model.CapabilitySupport {
+	support := make([]model.CapabilitySupport, 0, len(capabilities))
+	for _, capability := range capabilities {
+		support = append(support, model.CapabilitySupport{Capability: capability, Chains: chains})
+	}
+	return support
+}
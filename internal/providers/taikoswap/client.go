@@ -11,12 +11,14 @@ import (
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ggonzalez94/defi-cli/internal/clock"
 	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
 	"github.com/ggonzalez94/defi-cli/internal/execution"
 	"github.com/ggonzalez94/defi-cli/internal/id"
 	"github.com/ggonzalez94/defi-cli/internal/model"
 	"github.com/ggonzalez94/defi-cli/internal/providers"
 	"github.com/ggonzalez94/defi-cli/internal/registry"
+	"github.com/ggonzalez94/defi-cli/internal/rpcx"
 )
 
 var (
@@ -32,7 +34,7 @@ type Client struct {
 }
 
 func New() *Client {
-	return &Client{now: time.Now}
+	return &Client{now: clock.Now}
 }
 
 func (c *Client) Info() model.ProviderInfo {
@@ -45,6 +47,11 @@ func (c *Client) Info() model.ProviderInfo {
 			"swap.plan",
 			"swap.execute",
 		},
+		Support: []model.CapabilitySupport{
+			{Capability: "swap.quote", Chains: providers.EVMChains(registry.UniswapV3ChainIDs()), Features: []string{providers.FeatureExactInput}},
+			{Capability: "swap.plan", Chains: providers.EVMChains(registry.UniswapV3ChainIDs()), Features: []string{providers.FeatureExactInput}},
+			{Capability: "swap.execute", Chains: providers.EVMChains(registry.UniswapV3ChainIDs())},
+		},
 	}
 }
 
@@ -71,7 +78,7 @@ func (c *Client) QuoteSwap(ctx context.Context, req providers.SwapQuoteRequest)
 	if err != nil {
 		return model.SwapQuote{}, err
 	}
-	client, err := ethclient.DialContext(ctx, rpcURL)
+	client, err := rpcx.DialContext(ctx, rpcURL)
 	if err != nil {
 		return model.SwapQuote{}, clierr.Wrap(clierr.CodeUnavailable, "connect taiko rpc", err)
 	}
@@ -80,8 +87,14 @@ func (c *Client) QuoteSwap(ctx context.Context, req providers.SwapQuoteRequest)
 	if !ok {
 		return model.SwapQuote{}, clierr.New(clierr.CodeUsage, "invalid amount base units")
 	}
-	from := common.HexToAddress(req.FromAsset.Address)
-	to := common.HexToAddress(req.ToAsset.Address)
+	from, err := poolToken(req.Chain, req.FromAsset)
+	if err != nil {
+		return model.SwapQuote{}, err
+	}
+	to, err := poolToken(req.Chain, req.ToAsset)
+	if err != nil {
+		return model.SwapQuote{}, err
+	}
 	quoteOut, bestFee, _, err := quoteBestFee(ctx, client, quoter, from, to, amountIn)
 	if err != nil {
 		return model.SwapQuote{}, err
@@ -117,7 +130,7 @@ func (c *Client) BuildSwapAction(ctx context.Context, req providers.SwapQuoteReq
 	if err != nil {
 		return execution.Action{}, err
 	}
-	client, err := ethclient.DialContext(ctx, rpcURL)
+	client, err := rpcx.DialContext(ctx, rpcURL)
 	if err != nil {
 		return execution.Action{}, clierr.Wrap(clierr.CodeUnavailable, "connect taiko rpc", err)
 	}
@@ -127,7 +140,14 @@ func (c *Client) BuildSwapAction(ctx context.Context, req providers.SwapQuoteReq
 	if !ok {
 		return execution.Action{}, clierr.New(clierr.CodeUsage, "invalid amount base units")
 	}
-	fromToken := common.HexToAddress(req.FromAsset.Address)
+	if req.ToAsset.IsNative() {
+		return execution.Action{}, clierr.New(clierr.CodeUnsupported, "taikoswap execution cannot unwrap to native ETH; swap to WETH instead")
+	}
+	nativeIn := req.FromAsset.IsNative()
+	fromToken, err := poolToken(req.Chain, req.FromAsset)
+	if err != nil {
+		return execution.Action{}, err
+	}
 	toToken := common.HexToAddress(req.ToAsset.Address)
 	recipient := strings.TrimSpace(opts.Recipient)
 	if recipient == "" {
@@ -166,39 +186,46 @@ func (c *Client) BuildSwapAction(ctx context.Context, req providers.SwapQuoteReq
 		"amount_out_min": amountOutMin.String(),
 	}
 
-	allowanceData, err := erc20ABI.Pack("allowance", senderAddr, router)
-	if err != nil {
-		return execution.Action{}, clierr.Wrap(clierr.CodeInternal, "pack allowance call", err)
-	}
-	allowanceOut, err := client.CallContract(ctx, ethereum.CallMsg{From: senderAddr, To: &fromToken, Data: allowanceData}, nil)
-	if err != nil {
-		return execution.Action{}, clierr.Wrap(clierr.CodeUnavailable, "read allowance", err)
-	}
-	values, err := erc20ABI.Unpack("allowance", allowanceOut)
-	if err != nil || len(values) == 0 {
-		return execution.Action{}, clierr.Wrap(clierr.CodeUnavailable, "decode allowance", err)
-	}
-	allowance, ok := values[0].(*big.Int)
-	if !ok {
-		return execution.Action{}, clierr.New(clierr.CodeUnavailable, "invalid allowance response")
-	}
-
-	if allowance.Cmp(amountIn) < 0 {
-		approveData, err := erc20ABI.Pack("approve", router, amountIn)
+	// Native ETH is sent as value and wrapped by the router, so it needs no
+	// approval.
+	swapValue := "0"
+	if nativeIn {
+		swapValue = amountIn.String()
+	} else {
+		allowanceData, err := erc20ABI.Pack("allowance", senderAddr, router)
 		if err != nil {
-			return execution.Action{}, clierr.Wrap(clierr.CodeInternal, "pack approve calldata", err)
+			return execution.Action{}, clierr.Wrap(clierr.CodeInternal, "pack allowance call", err)
+		}
+		allowanceOut, err := client.CallContract(ctx, ethereum.CallMsg{From: senderAddr, To: &fromToken, Data: allowanceData}, nil)
+		if err != nil {
+			return execution.Action{}, clierr.Wrap(clierr.CodeUnavailable, "read allowance", err)
+		}
+		values, err := erc20ABI.Unpack("allowance", allowanceOut)
+		if err != nil || len(values) == 0 {
+			return execution.Action{}, clierr.Wrap(clierr.CodeUnavailable, "decode allowance", err)
+		}
+		allowance, ok := values[0].(*big.Int)
+		if !ok {
+			return execution.Action{}, clierr.New(clierr.CodeUnavailable, "invalid allowance response")
+		}
+
+		if allowance.Cmp(amountIn) < 0 {
+			approveData, err := erc20ABI.Pack("approve", router, amountIn)
+			if err != nil {
+				return execution.Action{}, clierr.Wrap(clierr.CodeInternal, "pack approve calldata", err)
+			}
+			action.Steps = append(action.Steps, execution.ActionStep{
+				StepID:      "approve-token-in",
+				Type:        execution.StepTypeApproval,
+				Status:      execution.StepStatusPending,
+				ChainID:     req.Chain.CAIP2,
+				RPCURL:      rpcURL,
+				Description: "Approve token spending for swap router",
+				Target:      fromToken.Hex(),
+				Data:        "0x" + common.Bytes2Hex(approveData),
+				Value:       "0",
+			})
 		}
-		action.Steps = append(action.Steps, execution.ActionStep{
-			StepID:      "approve-token-in",
-			Type:        execution.StepTypeApproval,
-			Status:      execution.StepStatusPending,
-			ChainID:     req.Chain.CAIP2,
-			RPCURL:      rpcURL,
-			Description: "Approve token spending for swap router",
-			Target:      fromToken.Hex(),
-			Data:        "0x" + common.Bytes2Hex(approveData),
-			Value:       "0",
-		})
 	}
 
 	swapData, err := routerABI.Pack("exactInputSingle", exactInputSingleParams{
@@ -222,7 +249,7 @@ func (c *Client) BuildSwapAction(ctx context.Context, req providers.SwapQuoteReq
 		Description: "Swap exact input via TaikoSwap router",
 		Target:      router.Hex(),
 		Data:        "0x" + common.Bytes2Hex(swapData),
-		Value:       "0",
+		Value:       swapValue,
 		ExpectedOutputs: map[string]string{
 			"amount_out_min": amountOutMin.String(),
 		},
@@ -230,6 +257,19 @@ func (c *Client) BuildSwapAction(ctx context.Context, req providers.SwapQuoteReq
 	return action, nil
 }
 
+// poolToken returns the pool token for asset. Native ETH trades through the
+// chain's WETH pools.
+func poolToken(chain id.Chain, asset id.Asset) (common.Address, error) {
+	if !asset.IsNative() {
+		return common.HexToAddress(asset.Address), nil
+	}
+	wrapped, ok := id.WrappedNativeToken(chain)
+	if !ok {
+		return common.Address{}, clierr.New(clierr.CodeUnsupported, fmt.Sprintf("taikoswap has no wrapped native token on %s", chain.Slug))
+	}
+	return common.HexToAddress(wrapped.Address), nil
+}
+
 func (c *Client) chainConfig(chain id.Chain, rpcOverride string) (rpc string, quoter common.Address, router common.Address, err error) {
 	quoterRaw, routerRaw, ok := registry.UniswapV3Contracts(chain.EVMChainID)
 	if !ok {
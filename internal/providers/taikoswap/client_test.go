@@ -82,6 +82,47 @@ func TestBuildSwapActionAddsApprovalWhenNeeded(t *testing.T) {
 	}
 }
 
+func TestBuildSwapActionSendsNativeInputAsValue(t *testing.T) {
+	server := newMockRPCServer(t, false)
+	defer server.Close()
+
+	c := New()
+	chain, _ := id.ParseChain("taiko")
+	fromAsset, err := id.ParseAsset("ETH", chain)
+	if err != nil {
+		t.Fatalf("ParseAsset(ETH) failed: %v", err)
+	}
+	toAsset, _ := id.ParseAsset("USDC", chain)
+	weth, _ := id.ParseAsset("WETH", chain)
+	action, err := c.BuildSwapAction(context.Background(), providers.SwapQuoteRequest{
+		Chain: chain, FromAsset: fromAsset, ToAsset: toAsset, AmountBaseUnits: "1000000000000000000", AmountDecimal: "1",
+	}, providers.SwapExecutionOptions{
+		Sender:      "0x00000000000000000000000000000000000000AA",
+		SlippageBps: 100,
+		Simulate:    true,
+		RPCURL:      server.URL,
+	})
+	if err != nil {
+		t.Fatalf("BuildSwapAction failed: %v", err)
+	}
+	if len(action.Steps) != 1 || action.Steps[0].Type != "swap" {
+		t.Fatalf("expected a swap-only action for native input, got %+v", action.Steps)
+	}
+	if action.Steps[0].Value != "1000000000000000000" {
+		t.Fatalf("expected the input amount as value, got %s", action.Steps[0].Value)
+	}
+	if !strings.Contains(action.Steps[0].Data, strings.TrimPrefix(weth.Address, "0x")) {
+		t.Fatal("expected the swap to route through WETH")
+	}
+
+	_, err = c.BuildSwapAction(context.Background(), providers.SwapQuoteRequest{
+		Chain: chain, FromAsset: toAsset, ToAsset: fromAsset, AmountBaseUnits: "1000000", AmountDecimal: "1",
+	}, providers.SwapExecutionOptions{Sender: "0x00000000000000000000000000000000000000AA", RPCURL: server.URL})
+	if err == nil {
+		t.Fatal("expected native output to be rejected")
+	}
+}
+
 func TestBuildSwapActionRequiresSender(t *testing.T) {
 	c := New()
 	chain, _ := id.ParseChain("taiko")
@@ -11,12 +11,14 @@ import (
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ggonzalez94/defi-cli/internal/clock"
 	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
 	"github.com/ggonzalez94/defi-cli/internal/execution"
 	"github.com/ggonzalez94/defi-cli/internal/id"
 	"github.com/ggonzalez94/defi-cli/internal/model"
 	"github.com/ggonzalez94/defi-cli/internal/providers"
 	"github.com/ggonzalez94/defi-cli/internal/registry"
+	"github.com/ggonzalez94/defi-cli/internal/rpcx"
 )
 
 var (
@@ -30,7 +32,7 @@ type Client struct {
 }
 
 func New() *Client {
-	return &Client{now: time.Now}
+	return &Client{now: clock.Now}
 }
 
 func (c *Client) Info() model.ProviderInfo {
@@ -43,6 +45,11 @@ func (c *Client) Info() model.ProviderInfo {
 			"swap.plan",
 			"swap.execute",
 		},
+		Support: []model.CapabilitySupport{
+			{Capability: "swap.quote", Chains: providers.EVMChains(registry.TempoChainIDs()), Features: []string{providers.FeatureExactInput, providers.FeatureExactOutput}},
+			{Capability: "swap.plan", Chains: providers.EVMChains(registry.TempoChainIDs()), Features: []string{providers.FeatureExactInput, providers.FeatureExactOutput}},
+			{Capability: "swap.execute", Chains: providers.EVMChains(registry.TempoChainIDs())},
+		},
 	}
 }
 
@@ -51,7 +58,7 @@ func (c *Client) QuoteSwap(ctx context.Context, req providers.SwapQuoteRequest)
 	if err != nil {
 		return model.SwapQuote{}, err
 	}
-	client, err := ethclient.DialContext(ctx, rpcURL)
+	client, err := rpcx.DialContext(ctx, rpcURL)
 	if err != nil {
 		return model.SwapQuote{}, clierr.Wrap(clierr.CodeUnavailable, "connect tempo rpc", err)
 	}
@@ -148,7 +155,7 @@ func (c *Client) BuildSwapAction(ctx context.Context, req providers.SwapQuoteReq
 	if err != nil {
 		return execution.Action{}, err
 	}
-	client, err := ethclient.DialContext(ctx, rpcURL)
+	client, err := rpcx.DialContext(ctx, rpcURL)
 	if err != nil {
 		return execution.Action{}, clierr.Wrap(clierr.CodeUnavailable, "connect tempo rpc", err)
 	}
@@ -2,6 +2,7 @@ package providers
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/ggonzalez94/defi-cli/internal/execution"
@@ -13,6 +14,18 @@ type Provider interface {
 	Info() model.ProviderInfo
 }
 
+// KeyVerifier is a keyed provider that can check its API key with a cheap
+// authenticated call. VerifyKey returns ErrNoAPIKey when no key is
+// configured, a CodeAuth error when the key is rejected, and CodeRateLimited
+// when the key is throttled.
+type KeyVerifier interface {
+	Provider
+	VerifyKey(ctx context.Context) error
+}
+
+// ErrNoAPIKey is returned by VerifyKey when the provider has no key to check.
+var ErrNoAPIKey = errors.New("no API key configured")
+
 type MarketDataProvider interface {
 	Provider
 	ChainsTop(ctx context.Context, limit int) ([]model.ChainTVL, error)
@@ -21,17 +34,76 @@ type MarketDataProvider interface {
 	ProtocolsCategories(ctx context.Context) ([]model.ProtocolCategory, error)
 	StablecoinsTop(ctx context.Context, pegType string, limit int) ([]model.Stablecoin, error)
 	StablecoinChains(ctx context.Context, limit int) ([]model.StablecoinChain, error)
-	ProtocolsFees(ctx context.Context, category string, chain string, limit int) ([]model.ProtocolFees, error)
+	StablecoinDetails(ctx context.Context, stablecoin string) (model.StablecoinDetails, error)
+	ProtocolsFees(ctx context.Context, req ProtocolMetricsRequest) ([]model.ProtocolFees, error)
+	ProtocolsVolume(ctx context.Context, req ProtocolMetricsRequest) ([]model.DexVolume, error)
 	ProtocolsRevenue(ctx context.Context, category string, chain string, limit int) ([]model.ProtocolRevenue, error)
 	DexesVolume(ctx context.Context, chain string, limit int) ([]model.DexVolume, error)
 }
 
+// MetricsWindow selects which rolling total ranks protocol volume and fees.
+type MetricsWindow string
+
+const (
+	MetricsWindow24h MetricsWindow = "24h"
+	MetricsWindow7d  MetricsWindow = "7d"
+	MetricsWindow30d MetricsWindow = "30d"
+)
+
+type ProtocolMetricsRequest struct {
+	Category string
+	Chain    string // DefiLlama chain name; matches protocols active on the chain
+	Protocol string // protocol name or slug
+	Window   MetricsWindow
+	Limit    int
+}
+
+// TVLChangeProvider reports the percent change in TVL over a window, keyed by
+// the chain or protocol names returned from MarketDataProvider. Names without
+// enough history are left out of the map.
+type TVLChangeProvider interface {
+	Provider
+	ChainsTVLChange(ctx context.Context, chains []string, window MetricsWindow) (map[string]float64, error)
+	ProtocolsTVLChange(ctx context.Context, chain string, protocols []string, window MetricsWindow) (map[string]float64, error)
+}
+
+// ProtocolSecurityProvider reports audit and exploit metadata for a protocol
+// name, slug, or parent protocol.
+type ProtocolSecurityProvider interface {
+	Provider
+	ProtocolSecurity(ctx context.Context, protocol string) (model.ProtocolSecurity, error)
+}
+
+// GovernanceRequest selects proposals of one protocol. Source is "snapshot",
+// "onchain", or "" for both; Status is "active", "pending", "closed", or ""
+// for any.
+type GovernanceRequest struct {
+	Protocol string
+	Source   string
+	Status   string
+	Limit    int
+}
+
+type GovernanceProvider interface {
+	Provider
+	GovernanceProposals(ctx context.Context, req GovernanceRequest) ([]model.GovernanceProposal, error)
+}
+
 type LendingProvider interface {
 	Provider
 	LendMarkets(ctx context.Context, provider string, chain id.Chain, asset id.Asset) ([]model.LendMarket, error)
 	LendRates(ctx context.Context, provider string, chain id.Chain, asset id.Asset) ([]model.LendRate, error)
 }
 
+// DataBackend selects where a provider reads market data: its hosted API or
+// The Graph network subgraphs.
+type DataBackend string
+
+const (
+	DataBackendAPI      DataBackend = "api"
+	DataBackendSubgraph DataBackend = "subgraph"
+)
+
 type LendPositionType string
 
 const (
@@ -55,11 +127,71 @@ type LendingPositionsProvider interface {
 	LendPositions(ctx context.Context, req LendPositionsRequest) ([]model.LendPosition, error)
 }
 
+// BorrowQuoteRequest selects borrow venues for Asset. A zero Collateral
+// matches any collateral.
+type BorrowQuoteRequest struct {
+	Chain      id.Chain
+	Asset      id.Asset
+	Collateral id.Asset
+}
+
+// LendingBorrowQuoteProvider reports borrow venues with their LTV caps.
+// Lending providers without it are compared from LendMarkets alone.
+type LendingBorrowQuoteProvider interface {
+	Provider
+	BorrowQuotes(ctx context.Context, req BorrowQuoteRequest) ([]model.BorrowQuote, error)
+}
+
+type LendHealthRequest struct {
+	Chain   id.Chain
+	Account string
+}
+
+type LendingHealthProvider interface {
+	Provider
+	LendHealth(ctx context.Context, req LendHealthRequest) ([]model.LendHealth, error)
+}
+
 type YieldProvider interface {
 	Provider
 	YieldOpportunities(ctx context.Context, req YieldRequest) ([]model.YieldOpportunity, error)
 }
 
+// StakingProvider reports liquid staking token rates.
+type StakingProvider interface {
+	Provider
+	StakeRates(ctx context.Context, chain id.Chain) ([]model.StakeRate, error)
+}
+
+// PerpsProvider reports perpetual futures markets and funding rates.
+type PerpsProvider interface {
+	Provider
+	PerpMarkets(ctx context.Context) ([]model.PerpMarket, error)
+}
+
+// PoolsProvider reports DEX liquidity pool analytics.
+type PoolsProvider interface {
+	Provider
+	ListPools(ctx context.Context, req PoolsRequest) ([]model.DexPool, error)
+	PoolDetails(ctx context.Context, chain id.Chain, poolID string) (model.DexPool, error)
+}
+
+// PoolsRequest selects pools holding both tokens. An empty Version queries
+// every protocol version the provider has data for on the chain.
+type PoolsRequest struct {
+	Chain   id.Chain
+	TokenA  id.Asset
+	TokenB  id.Asset
+	Version string
+	Limit   int
+}
+
+// OptionsProvider reports listed options for an underlying asset symbol.
+type OptionsProvider interface {
+	Provider
+	OptionChain(ctx context.Context, underlying string) ([]model.OptionContract, error)
+}
+
 type YieldPositionsRequest struct {
 	Chain   id.Chain
 	Account string
@@ -100,6 +232,89 @@ type YieldHistoryProvider interface {
 	YieldHistory(ctx context.Context, req YieldHistoryRequest) ([]model.YieldHistorySeries, error)
 }
 
+type TVLHistoryRequest struct {
+	Chain     id.Chain // zero value means all chains
+	Protocol  string   // protocol slug; empty means chain-level TVL
+	StartTime time.Time
+	EndTime   time.Time
+}
+
+type PriceHistoryRequest struct {
+	Chain     id.Chain
+	Asset     id.Asset
+	StartTime time.Time
+	EndTime   time.Time
+	Interval  YieldHistoryInterval
+}
+
+// PriceHistoryProvider serves history price for chains whose assets are
+// priced by a venue rather than the market data provider.
+type PriceHistoryProvider interface {
+	Provider
+	PriceHistory(ctx context.Context, req PriceHistoryRequest) (model.MarketHistorySeries, error)
+}
+
+type MarketHistoryProvider interface {
+	Provider
+	TVLHistory(ctx context.Context, req TVLHistoryRequest) (model.MarketHistorySeries, error)
+	PriceHistory(ctx context.Context, req PriceHistoryRequest) (model.MarketHistorySeries, error)
+}
+
+// RewardsClaimableRequest selects the incentives an account has accrued but
+// not claimed on one chain.
+type RewardsClaimableRequest struct {
+	Chain   id.Chain
+	Account string
+	RPCURL  string
+}
+
+// RewardsProvider reports claimable protocol incentives. Each row carries
+// what a claim needs: the source assets for Aave, the cumulative amount and
+// Merkle proofs for Merkl-distributed rewards.
+type RewardsProvider interface {
+	Provider
+	ClaimableRewards(ctx context.Context, req RewardsClaimableRequest) ([]model.ClaimableReward, error)
+}
+
+// TokenPriceProvider returns current USD prices keyed by lowercase token
+// address. Native coins are requested with the zero address.
+type TokenPriceProvider interface {
+	Provider
+	TokenPrices(ctx context.Context, chain id.Chain, addresses []string) (map[string]model.TokenPrice, error)
+}
+
+// ActivityRequest selects recent activity for an address on one chain.
+type ActivityRequest struct {
+	Chain   id.Chain
+	Address string
+	Limit   int
+}
+
+// ActivityProvider lists recent transactions and token transfers for an
+// address, newest first.
+type ActivityProvider interface {
+	Provider
+	Activity(ctx context.Context, req ActivityRequest) ([]model.ActivityEntry, error)
+}
+
+// ContractSource is an explorer's verification record for a contract. ABI is
+// the raw JSON ABI and is empty for unverified contracts.
+type ContractSource struct {
+	Address         string
+	Verified        bool
+	ContractName    string
+	CompilerVersion string
+	ABI             string
+	// Implementation is the explorer-detected proxy implementation, if any.
+	Implementation string
+}
+
+// ContractSourceProvider looks up source verification for a contract.
+type ContractSourceProvider interface {
+	Provider
+	ContractSource(ctx context.Context, chain id.Chain, address string) (ContractSource, error)
+}
+
 type YieldRequest struct {
 	Chain             id.Chain
 	Asset             id.Asset
@@ -183,6 +398,15 @@ type SwapQuoteRequest struct {
 	TradeType       SwapTradeType
 	SlippagePct     *float64
 	Swapper         string
+	// Inputs lists every input leg of a multi-input swap. When set,
+	// FromAsset and AmountBaseUnits describe the first leg.
+	Inputs []SwapInput
+}
+
+type SwapInput struct {
+	Asset           id.Asset
+	AmountBaseUnits string
+	AmountDecimal   string
 }
 
 type SwapExecutionOptions struct {
@@ -192,3 +416,36 @@ type SwapExecutionOptions struct {
 	Simulate    bool
 	RPCURL      string
 }
+
+// LimitOrderProvider places and tracks resting limit orders in an off-chain
+// order book.
+type LimitOrderProvider interface {
+	Provider
+	// CreateLimitOrder places an order. Protocols with off-chain signed
+	// orders call sign with the order digest; protocols that create orders
+	// on-chain return the unsigned transaction with a pending_signature
+	// status instead and ignore sign.
+	CreateLimitOrder(ctx context.Context, req LimitOrderRequest, sign LimitOrderSigner) (execution.LimitOrder, error)
+	// RefreshLimitOrder updates the status and filled amount of order.
+	RefreshLimitOrder(ctx context.Context, chain id.Chain, order *execution.LimitOrder) error
+	// CancelLimitOrder returns the steps that cancel order on-chain, or sets
+	// order.UnsignedTransaction and returns no steps when the maker must sign
+	// the cancellation with their own wallet.
+	CancelLimitOrder(ctx context.Context, chain id.Chain, order *execution.LimitOrder) ([]execution.ActionStep, error)
+}
+
+// LimitOrderSigner signs a 32-byte order digest and returns the 65-byte
+// [R || S || V] signature with V in {0, 1}.
+type LimitOrderSigner func(digest []byte) ([]byte, error)
+
+type LimitOrderRequest struct {
+	Chain            id.Chain
+	FromAsset        id.Asset
+	ToAsset          id.Asset
+	MakingAmount     string
+	TakingAmount     string
+	Maker            string
+	Receiver         string
+	ExpiresAt        time.Time
+	AllowPartialFill bool
+}
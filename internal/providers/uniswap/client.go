@@ -8,6 +8,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/ggonzalez94/defi-cli/internal/clock"
 	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
 	"github.com/ggonzalez94/defi-cli/internal/httpx"
 	"github.com/ggonzalez94/defi-cli/internal/id"
@@ -15,17 +16,27 @@ import (
 	"github.com/ggonzalez94/defi-cli/internal/providers"
 )
 
-const defaultBase = "https://trade-api.gateway.uniswap.org"
+const (
+	defaultBase = "https://trade-api.gateway.uniswap.org"
+	// nativeToken is the address the Trading API uses for the chain's gas token.
+	nativeToken = "0x0000000000000000000000000000000000000000"
+)
 
 type Client struct {
 	http    *httpx.Client
 	baseURL string
 	apiKey  string
 	now     func() time.Time
+	// subgraphHTTP and subgraphKey serve pools list/details.
+	subgraphHTTP *httpx.Client
+	subgraphKey  string
+	// subgraphEndpoint resolves the pool subgraph URL for a protocol version
+	// and chain.
+	subgraphEndpoint func(version string, chainID int64) (string, bool)
 }
 
 func New(httpClient *httpx.Client, apiKey string) *Client {
-	return &Client{http: httpClient, baseURL: defaultBase, apiKey: apiKey, now: time.Now}
+	return &Client{http: httpClient, baseURL: defaultBase, apiKey: apiKey, now: clock.Now, subgraphHTTP: httpClient, subgraphEndpoint: defaultSubgraphEndpoint}
 }
 
 func (c *Client) Info() model.ProviderInfo {
@@ -36,12 +47,27 @@ func (c *Client) Info() model.ProviderInfo {
 		KeyEnvVarName: "DEFI_UNISWAP_API_KEY",
 		Capabilities: []string{
 			"swap.quote",
+			"pools.list",
+			"pools.details",
 		},
 		CapabilityAuth: []model.ProviderCapabilityAuth{
 			{
 				Capability: "swap.quote",
 				KeyEnvVar:  "DEFI_UNISWAP_API_KEY",
 			},
+			{
+				Capability: "pools.list",
+				KeyEnvVar:  "DEFI_THEGRAPH_API_KEY",
+			},
+			{
+				Capability: "pools.details",
+				KeyEnvVar:  "DEFI_THEGRAPH_API_KEY",
+			},
+		},
+		Support: []model.CapabilitySupport{
+			{Capability: "swap.quote", Chains: []string{providers.AnyEVMChain}, Features: []string{providers.FeatureExactInput, providers.FeatureExactOutput}},
+			{Capability: "pools.list", Chains: []string{providers.AnyEVMChain}},
+			{Capability: "pools.details", Chains: []string{providers.AnyEVMChain}},
 		},
 	}
 }
@@ -55,6 +81,7 @@ type quoteResponse struct {
 			Amount string `json:"amount"`
 		} `json:"output"`
 		GasFeeUSD json.RawMessage `json:"gasFeeUSD"`
+		Slippage  json.RawMessage `json:"slippage"`
 	} `json:"quote"`
 	AmountIn  string          `json:"amountIn"`
 	AmountOut string          `json:"amountOut"`
@@ -86,8 +113,8 @@ func (c *Client) QuoteSwap(ctx context.Context, req providers.SwapQuoteRequest)
 	payload := map[string]any{
 		"tokenInChainId":  req.Chain.EVMChainID,
 		"tokenOutChainId": req.Chain.EVMChainID,
-		"tokenIn":         req.FromAsset.Address,
-		"tokenOut":        req.ToAsset.Address,
+		"tokenIn":         tokenAddress(req.FromAsset),
+		"tokenOut":        tokenAddress(req.ToAsset),
 		"amount":          req.AmountBaseUnits,
 		"type":            uniswapTradeType(tradeType),
 		"swapper":         swapper,
@@ -146,7 +173,13 @@ func (c *Client) QuoteSwap(ctx context.Context, req providers.SwapQuoteRequest)
 		}
 	}
 
-	return model.SwapQuote{
+	// With auto slippage, the quote reports the tolerance it picked.
+	slippagePct := providers.SwapSlippagePct(req)
+	if autoSlippage, err := parseJSONFloat(resp.Quote.Slippage); err == nil && autoSlippage > 0 && req.SlippagePct == nil {
+		slippagePct = autoSlippage
+	}
+
+	quote := model.SwapQuote{
 		Provider:    "uniswap",
 		ChainID:     req.Chain.CAIP2,
 		FromAssetID: req.FromAsset.AssetID,
@@ -167,7 +200,9 @@ func (c *Client) QuoteSwap(ctx context.Context, req providers.SwapQuoteRequest)
 		Route:           "uniswap",
 		SourceURL:       "https://app.uniswap.org",
 		FetchedAt:       c.now().UTC().Format(time.RFC3339),
-	}, nil
+	}
+	providers.ApplySwapSlippage(&quote, slippagePct)
+	return quote, nil
 }
 
 func parseJSONFloat(raw json.RawMessage) (float64, error) {
@@ -202,3 +237,32 @@ func uniswapTradeType(t providers.SwapTradeType) string {
 	}
 	return "EXACT_INPUT"
 }
+
+// tokenAddress maps the registry's native placeholder to the Trading API's
+// zero-address sentinel.
+func tokenAddress(asset id.Asset) string {
+	if asset.IsNative() {
+		return nativeToken
+	}
+	return asset.Address
+}
+
+// VerifyKey checks the API key with an approval check for native ETH, which
+// never needs an approval and so does no routing.
+func (c *Client) VerifyKey(ctx context.Context) error {
+	if c.apiKey == "" {
+		return providers.ErrNoAPIKey
+	}
+	buf, err := json.Marshal(map[string]any{
+		"walletAddress": "0x0000000000000000000000000000000000000001",
+		"token":         "0x0000000000000000000000000000000000000000",
+		"amount":        "1",
+		"chainId":       1,
+	})
+	if err != nil {
+		return clierr.Wrap(clierr.CodeInternal, "marshal uniswap approval request", err)
+	}
+	var resp json.RawMessage
+	_, err = httpx.DoBodyJSON(ctx, c.http, http.MethodPost, c.baseURL+"/v1/check_approval", buf, map[string]string{"x-api-key": c.apiKey}, &resp)
+	return err
+}
@@ -322,3 +322,42 @@ func TestQuoteSwapRejectsNonEVMChain(t *testing.T) {
 		t.Fatal("expected unsupported chain error")
 	}
 }
+
+func TestQuoteSwapMapsNativeInputToZeroAddress(t *testing.T) {
+	chain, _ := id.ParseChain("ethereum")
+	assetIn, err := id.ParseAsset("ETH", chain)
+	if err != nil {
+		t.Fatalf("ParseAsset(ETH) failed: %v", err)
+	}
+	assetOut, _ := id.ParseAsset("USDC", chain)
+
+	var got struct {
+		TokenIn  string `json:"tokenIn"`
+		TokenOut string `json:"tokenOut"`
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			http.Error(w, "invalid JSON payload", http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"quote":{"output":{"amount":"3000000000"},"gasFeeUSD":"0.1"}}`)
+	}))
+	defer srv.Close()
+
+	c := New(httpx.New(1*time.Second, 0), "test-key")
+	c.baseURL = srv.URL
+	if _, err := c.QuoteSwap(context.Background(), providers.SwapQuoteRequest{
+		Chain:           chain,
+		FromAsset:       assetIn,
+		ToAsset:         assetOut,
+		AmountBaseUnits: "1000000000000000000",
+		AmountDecimal:   "1",
+		Swapper:         testSwapper,
+	}); err != nil {
+		t.Fatalf("QuoteSwap failed: %v", err)
+	}
+	if got.TokenIn != nativeToken || got.TokenOut != assetOut.Address {
+		t.Fatalf("expected native input as the zero address, got tokenIn=%s tokenOut=%s", got.TokenIn, got.TokenOut)
+	}
+}
@@ -0,0 +1,291 @@
+package uniswap
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/httpx"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/model"
+	"github.com/ggonzalez94/defi-cli/internal/providers"
+	"github.com/ggonzalez94/defi-cli/internal/registry"
+)
+
+// poolVersions are the protocol versions with pool subgraphs, newest last.
+var poolVersions = []string{"v3", "v4"}
+
+const poolFields = `
+    id
+    feeTier
+    tick
+    liquidity
+    token1Price
+    totalValueLockedUSD
+    token0 { id symbol decimals }
+    token1 { id symbol decimals }
+    poolHourData(first: 24, orderBy: periodStartUnix, orderDirection: desc) { periodStartUnix volumeUSD feesUSD }`
+
+// v4 pools add a tick spacing and hooks contract that v3 pools derive from
+// the fee tier or lack.
+const poolFieldsV4 = poolFields + `
+    tickSpacing
+    hooks`
+
+type subgraphPool struct {
+	ID                  string        `json:"id"`
+	FeeTier             string        `json:"feeTier"`
+	Tick                *string       `json:"tick"`
+	Liquidity           string        `json:"liquidity"`
+	Token1Price         string        `json:"token1Price"`
+	TotalValueLockedUSD string        `json:"totalValueLockedUSD"`
+	Token0              subgraphToken `json:"token0"`
+	Token1              subgraphToken `json:"token1"`
+	TickSpacing         string        `json:"tickSpacing"`
+	Hooks               string        `json:"hooks"`
+	PoolHourData        []struct {
+		PeriodStartUnix int64  `json:"periodStartUnix"`
+		VolumeUSD       string `json:"volumeUSD"`
+		FeesUSD         string `json:"feesUSD"`
+	} `json:"poolHourData"`
+}
+
+type subgraphToken struct {
+	ID       string `json:"id"`
+	Symbol   string `json:"symbol"`
+	Decimals string `json:"decimals"`
+}
+
+type subgraphError struct {
+	Message string `json:"message"`
+}
+
+// SetSubgraph configures the HTTP client and The Graph API key used by pools
+// list/details, which read the Uniswap subgraphs rather than the Trading API.
+func (c *Client) SetSubgraph(httpClient *httpx.Client, apiKey string) {
+	if httpClient != nil {
+		c.subgraphHTTP = httpClient
+	}
+	c.subgraphKey = strings.TrimSpace(apiKey)
+}
+
+func defaultSubgraphEndpoint(version string, chainID int64) (string, bool) {
+	subgraphID, ok := registry.UniswapSubgraphID(version, chainID)
+	if !ok {
+		return "", false
+	}
+	return registry.TheGraphGatewayURL + subgraphID, true
+}
+
+func (c *Client) ListPools(ctx context.Context, req providers.PoolsRequest) ([]model.DexPool, error) {
+	if !req.Chain.IsEVM() {
+		return nil, clierr.New(clierr.CodeUnsupported, "uniswap pools support only EVM chains")
+	}
+	versions := poolVersions
+	if req.Version != "" {
+		versions = []string{req.Version}
+	}
+	limit := req.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+
+	out := []model.DexPool{}
+	queried := 0
+	for _, version := range versions {
+		endpoint, ok := c.subgraphEndpoint(version, req.Chain.EVMChainID)
+		if !ok {
+			continue
+		}
+		queried++
+		tokens := []string{poolTokenAddress(version, req.Chain, req.TokenA), poolTokenAddress(version, req.Chain, req.TokenB)}
+		query := fmt.Sprintf(`query Pools($tokens: [String!]!, $first: Int!) {
+  pools(first: $first, orderBy: totalValueLockedUSD, orderDirection: desc, where: {token0_in: $tokens, token1_in: $tokens}) {%s
+  }
+}`, versionPoolFields(version))
+		var resp struct {
+			Data struct {
+				Pools []subgraphPool `json:"pools"`
+			} `json:"data"`
+			Errors []subgraphError `json:"errors"`
+		}
+		if err := c.querySubgraph(ctx, endpoint, query, map[string]any{"tokens": tokens, "first": limit}, &resp); err != nil {
+			return nil, err
+		}
+		if len(resp.Errors) > 0 {
+			return nil, clierr.New(clierr.CodeUnavailable, fmt.Sprintf("uniswap %s subgraph error: %s", version, resp.Errors[0].Message))
+		}
+		for _, pool := range resp.Data.Pools {
+			out = append(out, c.dexPool(req.Chain, version, pool))
+		}
+	}
+	if queried == 0 {
+		return nil, clierr.New(clierr.CodeUnsupported, "no uniswap pool subgraph for requested chain/version")
+	}
+	if len(out) == 0 {
+		return nil, clierr.New(clierr.CodeUnsupported, "no uniswap pools for requested pair")
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].TVLUSD != out[j].TVLUSD {
+			return out[i].TVLUSD > out[j].TVLUSD
+		}
+		return out[i].PoolID < out[j].PoolID
+	})
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+// PoolDetails reads one pool. v3 pools are addressed by contract address and
+// v4 pools by their 32-byte pool ID.
+func (c *Client) PoolDetails(ctx context.Context, chain id.Chain, poolID string) (model.DexPool, error) {
+	if !chain.IsEVM() {
+		return model.DexPool{}, clierr.New(clierr.CodeUnsupported, "uniswap pools support only EVM chains")
+	}
+	poolID = strings.ToLower(strings.TrimSpace(poolID))
+	var version string
+	switch len(poolID) {
+	case 42:
+		version = "v3"
+	case 66:
+		version = "v4"
+	default:
+		return model.DexPool{}, clierr.New(clierr.CodeUsage, "--pool must be a v3 pool address or a v4 pool id")
+	}
+	endpoint, ok := c.subgraphEndpoint(version, chain.EVMChainID)
+	if !ok {
+		return model.DexPool{}, clierr.New(clierr.CodeUnsupported, fmt.Sprintf("no uniswap %s pool subgraph for requested chain", version))
+	}
+	query := fmt.Sprintf(`query Pool($id: ID!) {
+  pool(id: $id) {%s
+  }
+}`, versionPoolFields(version))
+	var resp struct {
+		Data struct {
+			Pool *subgraphPool `json:"pool"`
+		} `json:"data"`
+		Errors []subgraphError `json:"errors"`
+	}
+	if err := c.querySubgraph(ctx, endpoint, query, map[string]any{"id": poolID}, &resp); err != nil {
+		return model.DexPool{}, err
+	}
+	if len(resp.Errors) > 0 {
+		return model.DexPool{}, clierr.New(clierr.CodeUnavailable, fmt.Sprintf("uniswap %s subgraph error: %s", version, resp.Errors[0].Message))
+	}
+	if resp.Data.Pool == nil {
+		return model.DexPool{}, clierr.New(clierr.CodeUnsupported, fmt.Sprintf("uniswap %s pool %s not found", version, poolID))
+	}
+	return c.dexPool(chain, version, *resp.Data.Pool), nil
+}
+
+func (c *Client) querySubgraph(ctx context.Context, endpoint, query string, variables map[string]any, out any) error {
+	if c.subgraphKey == "" {
+		return clierr.New(clierr.CodeAuth, "missing required API key for The Graph (DEFI_THEGRAPH_API_KEY)")
+	}
+	body, err := json.Marshal(map[string]any{"query": query, "variables": variables})
+	if err != nil {
+		return clierr.Wrap(clierr.CodeInternal, "marshal uniswap subgraph query", err)
+	}
+	headers := map[string]string{"Authorization": "Bearer " + c.subgraphKey}
+	_, err = httpx.DoBodyJSON(ctx, c.subgraphHTTP, http.MethodPost, endpoint, body, headers, out)
+	return err
+}
+
+func (c *Client) dexPool(chain id.Chain, version string, pool subgraphPool) model.DexPool {
+	now := c.now().UTC()
+	var volume, fees float64
+	for _, hour := range pool.PoolHourData {
+		if now.Sub(time.Unix(hour.PeriodStartUnix, 0)) > 24*time.Hour {
+			continue
+		}
+		volume += parseSubgraphFloat(hour.VolumeUSD)
+		fees += parseSubgraphFloat(hour.FeesUSD)
+	}
+	tvl := parseSubgraphFloat(pool.TotalValueLockedUSD)
+	feeAPR := 0.0
+	if tvl > 0 {
+		feeAPR = fees * 365 / tvl * 100
+	}
+	feeTier := parseSubgraphInt(pool.FeeTier)
+	out := model.DexPool{
+		Dex:          "uniswap",
+		Version:      version,
+		ChainID:      chain.CAIP2,
+		PoolID:       strings.ToLower(pool.ID),
+		Token0:       poolToken(chain, pool.Token0),
+		Token1:       poolToken(chain, pool.Token1),
+		FeeTier:      feeTier,
+		FeeTierPct:   float64(feeTier) / 10_000,
+		TickSpacing:  parseSubgraphInt(pool.TickSpacing),
+		Liquidity:    strings.TrimSpace(pool.Liquidity),
+		Price:        parseSubgraphFloat(pool.Token1Price),
+		TVLUSD:       tvl,
+		Volume24hUSD: volume,
+		Fees24hUSD:   fees,
+		FeeAPR:       feeAPR,
+		SourceURL:    "https://app.uniswap.org/explore/pools",
+		FetchedAt:    now.Format(time.RFC3339),
+	}
+	if pool.Tick != nil {
+		out.Tick = parseSubgraphInt(*pool.Tick)
+	}
+	if hooks := strings.ToLower(strings.TrimSpace(pool.Hooks)); hooks != "" && hooks != nativeToken {
+		out.Hooks = hooks
+	}
+	return out
+}
+
+func versionPoolFields(version string) string {
+	if version == "v4" {
+		return poolFieldsV4
+	}
+	return poolFields
+}
+
+// poolTokenAddress returns the subgraph token ID for asset. v3 pools hold
+// the wrapped gas token; v4 pools hold native ETH at the zero address.
+func poolTokenAddress(version string, chain id.Chain, asset id.Asset) string {
+	if asset.IsNative() {
+		if version == "v4" {
+			return nativeToken
+		}
+		if wrapped, ok := id.WrappedNativeToken(chain); ok {
+			return strings.ToLower(wrapped.Address)
+		}
+	}
+	return strings.ToLower(strings.TrimSpace(asset.Address))
+}
+
+func poolToken(chain id.Chain, token subgraphToken) model.DexPoolToken {
+	addr := strings.ToLower(strings.TrimSpace(token.ID))
+	assetID := fmt.Sprintf("%s/erc20:%s", chain.CAIP2, addr)
+	symbol := token.Symbol
+	if addr == nativeToken {
+		native := id.NativeAsset(chain)
+		assetID, symbol = native.AssetID, native.Symbol
+	}
+	return model.DexPoolToken{AssetID: assetID, Symbol: symbol, Decimals: int(parseSubgraphInt(token.Decimals))}
+}
+
+func parseSubgraphFloat(v string) float64 {
+	f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+	if err != nil {
+		return 0
+	}
+	return f
+}
+
+func parseSubgraphInt(v string) int64 {
+	n, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return n
+}
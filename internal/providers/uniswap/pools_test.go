@@ -0,0 +1,161 @@
+package uniswap
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/httpx"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/providers"
+)
+
+const testPoolV3 = "0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640"
+
+func newPoolsTestClient(t *testing.T, handler http.HandlerFunc) (*Client, time.Time) {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	now := time.Date(2026, time.October, 1, 12, 0, 0, 0, time.UTC)
+	c := New(httpx.New(2*time.Second, 0), "")
+	c.SetSubgraph(nil, "graph-key")
+	c.now = func() time.Time { return now }
+	c.subgraphEndpoint = func(version string, chainID int64) (string, bool) {
+		if chainID != 1 {
+			return "", false
+		}
+		return srv.URL + "/" + version, true
+	}
+	return c, now
+}
+
+func hourData(now time.Time, volumes ...float64) string {
+	parts := make([]string, 0, len(volumes)+1)
+	for i, v := range volumes {
+		start := now.Add(-time.Duration(i+1) * time.Hour).Unix()
+		parts = append(parts, fmt.Sprintf(`{"periodStartUnix":%d,"volumeUSD":"%g","feesUSD":"%g"}`, start, v, v*0.0005))
+	}
+	// Older than 24h; must not count toward the daily totals.
+	parts = append(parts, fmt.Sprintf(`{"periodStartUnix":%d,"volumeUSD":"999999","feesUSD":"999"}`, now.Add(-30*time.Hour).Unix()))
+	return "[" + strings.Join(parts, ",") + "]"
+}
+
+func TestListPoolsMergesVersionsByTVL(t *testing.T) {
+	var now time.Time
+	var gotTokens map[string][]string
+	c, now := newPoolsTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer graph-key" {
+			http.Error(w, "missing key", http.StatusUnauthorized)
+			return
+		}
+		var body struct {
+			Query     string `json:"query"`
+			Variables struct {
+				Tokens []string `json:"tokens"`
+			} `json:"variables"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if gotTokens == nil {
+			gotTokens = map[string][]string{}
+		}
+		gotTokens[r.URL.Path] = body.Variables.Tokens
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v3":
+			if strings.Contains(body.Query, "hooks") {
+				http.Error(w, "v3 has no hooks", http.StatusBadRequest)
+				return
+			}
+			_, _ = io.WriteString(w, `{"data":{"pools":[{"id":"`+testPoolV3+`","feeTier":"500","tick":"198000","liquidity":"12345678901234567890","token1Price":"2500.5","totalValueLockedUSD":"100000000",
+				"token0":{"id":"0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48","symbol":"USDC","decimals":"6"},
+				"token1":{"id":"0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2","symbol":"WETH","decimals":"18"},
+				"poolHourData":`+hourData(now, 1000000, 1000000)+`}]}}`)
+		case "/v4":
+			_, _ = io.WriteString(w, `{"data":{"pools":[{"id":"0x21c67e77068de97969ba93d4aab21826d33ca12bb9f565d8496e8fda8a82ca27","feeTier":"500","tick":"-198000","token1Price":"0.0004","totalValueLockedUSD":"5000000",
+				"tickSpacing":"10","hooks":"0x0000000000000000000000000000000000000000",
+				"token0":{"id":"0x0000000000000000000000000000000000000000","symbol":"ETH","decimals":"18"},
+				"token1":{"id":"0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48","symbol":"USDC","decimals":"6"},
+				"poolHourData":[]}]}}`)
+		default:
+			http.Error(w, "unexpected path", http.StatusNotFound)
+		}
+	})
+
+	chain, _ := id.ParseChain("ethereum")
+	usdc, _ := id.ParseAsset("USDC", chain)
+	pools, err := c.ListPools(context.Background(), providers.PoolsRequest{Chain: chain, TokenA: usdc, TokenB: id.NativeAsset(chain), Limit: 10})
+	if err != nil {
+		t.Fatalf("ListPools failed: %v", err)
+	}
+	if len(pools) != 2 || pools[0].Version != "v3" || pools[1].Version != "v4" {
+		t.Fatalf("expected v3 then v4 pools ordered by TVL, got %+v", pools)
+	}
+	v3 := pools[0]
+	if v3.FeeTierPct != 0.05 || v3.Tick != 198000 || v3.Price != 2500.5 || v3.Token1.Symbol != "WETH" || v3.Token0.Decimals != 6 || v3.Liquidity != "12345678901234567890" {
+		t.Fatalf("unexpected v3 pool fields %+v", v3)
+	}
+	if v3.Volume24hUSD != 2000000 || v3.Fees24hUSD != 1000 {
+		t.Fatalf("expected only the last 24h of volume and fees, got volume=%v fees=%v", v3.Volume24hUSD, v3.Fees24hUSD)
+	}
+	if math.Abs(v3.FeeAPR-0.365) > 1e-9 {
+		t.Fatalf("expected fee APR 0.365%%, got %v", v3.FeeAPR)
+	}
+	v4 := pools[1]
+	if v4.TickSpacing != 10 || v4.Hooks != "" || v4.Token0.AssetID != id.NativeAsset(chain).AssetID {
+		t.Fatalf("unexpected v4 pool fields %+v", v4)
+	}
+	if gotTokens["/v3"][1] != "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2" || gotTokens["/v4"][1] != nativeToken {
+		t.Fatalf("expected native ETH as WETH on v3 and the zero address on v4, got %v", gotTokens)
+	}
+}
+
+func TestPoolDetailsInfersVersionFromID(t *testing.T) {
+	var gotPath, gotID string
+	c, _ := newPoolsTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Variables struct {
+				ID string `json:"id"`
+			} `json:"variables"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		gotPath, gotID = r.URL.Path, body.Variables.ID
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"data":{"pool":null}}`)
+	})
+	chain, _ := id.ParseChain("ethereum")
+
+	_, err := c.PoolDetails(context.Background(), chain, strings.ToUpper(testPoolV3[:2])+testPoolV3[2:])
+	if cErr, ok := clierr.As(err); !ok || cErr.Code != clierr.CodeUnsupported {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+	if gotPath != "/v3" || gotID != testPoolV3 {
+		t.Fatalf("expected v3 lookup of %s, got %s %s", testPoolV3, gotPath, gotID)
+	}
+
+	_, err = c.PoolDetails(context.Background(), chain, "0x1234")
+	if cErr, ok := clierr.As(err); !ok || cErr.Code != clierr.CodeUsage {
+		t.Fatalf("expected usage error for malformed pool id, got %v", err)
+	}
+}
+
+func TestListPoolsRequiresGraphKey(t *testing.T) {
+	c, _ := newPoolsTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request without a key")
+	})
+	c.SetSubgraph(nil, "")
+	chain, _ := id.ParseChain("ethereum")
+	usdc, _ := id.ParseAsset("USDC", chain)
+	weth, _ := id.ParseAsset("WETH", chain)
+	_, err := c.ListPools(context.Background(), providers.PoolsRequest{Chain: chain, TokenA: usdc, TokenB: weth})
+	if cErr, ok := clierr.As(err); !ok || cErr.Code != clierr.CodeAuth {
+		t.Fatalf("expected auth error, got %v", err)
+	}
+}
@@ -0,0 +1,384 @@
+package wormhole
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ggonzalez94/defi-cli/internal/clock"
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/execution"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/model"
+	"github.com/ggonzalez94/defi-cli/internal/providers"
+	"github.com/ggonzalez94/defi-cli/internal/registry"
+	"github.com/ggonzalez94/defi-cli/internal/rpcx"
+)
+
+// Portal token bridge transfers carry at most 8 decimals; wrapped tokens are
+// created with min(origin decimals, 8).
+const maxTransferDecimals = 8
+
+var (
+	erc20ABI       = mustABI(registry.ERC20MinimalABI)
+	tokenBridgeABI = mustABI(registry.WormholeTokenBridgeABI)
+	coreABI        = mustABI(registry.WormholeCoreABI)
+
+	solanaTokenBridge  = mustSolanaKey(registry.WormholeSolanaTokenBridgeProgram)
+	solanaTokenProgram = mustSolanaKey(registry.SolanaTokenProgram)
+	solanaATAProgram   = mustSolanaKey(registry.SolanaAssociatedTokenProgram)
+)
+
+// Client quotes and plans Portal token bridge transfers between EVM chains
+// and Solana. Quotes are computed locally: manual-redeem Portal transfers
+// charge no bridge fee and deliver the Wormhole-wrapped token 1:1 after
+// decimal truncation.
+type Client struct {
+	now    func() time.Time
+	rpcURL func(override string, chainID int64) (string, error)
+}
+
+func New() *Client {
+	return &Client{now: clock.Now, rpcURL: registry.ResolveRPCURL}
+}
+
+func (c *Client) Info() model.ProviderInfo {
+	return model.ProviderInfo{
+		Name:        "wormhole",
+		Type:        "bridge",
+		RequiresKey: false,
+		Capabilities: []string{
+			"bridge.quote",
+			"bridge.plan",
+			"bridge.execute",
+		},
+		// Quotes also cover Solana; executing a transfer from Solana needs a
+		// Solana signer, so plans are limited to EVM source chains.
+		Support: []model.CapabilitySupport{
+			{Capability: "bridge.quote", Chains: append(providers.EVMChains(registry.WormholeEVMChainIDs()), id.SolanaMainnetCAIP2)},
+			{Capability: "bridge.plan", Chains: providers.EVMChains(registry.WormholeEVMChainIDs())},
+			{Capability: "bridge.execute", Chains: providers.EVMChains(registry.WormholeEVMChainIDs())},
+		},
+	}
+}
+
+// route is a resolved Portal transfer: the wrapped asset delivered on the
+// destination and the amounts after truncation to 8 decimals.
+type route struct {
+	toAsset   id.Asset
+	amountIn  *big.Int
+	amountOut *big.Int
+	finalityS int64
+	sourceEVM registry.WormholeEVMChain
+}
+
+func (c *Client) QuoteBridge(ctx context.Context, req providers.BridgeQuoteRequest) (model.BridgeQuote, error) {
+	r, err := c.resolveRoute(ctx, req)
+	if err != nil {
+		return model.BridgeQuote{}, err
+	}
+	return model.BridgeQuote{
+		Provider:    "wormhole",
+		FromChainID: req.FromChain.CAIP2,
+		ToChainID:   req.ToChain.CAIP2,
+		FromAssetID: req.FromAsset.AssetID,
+		ToAssetID:   r.toAsset.AssetID,
+		InputAmount: model.AmountInfo{
+			AmountBaseUnits: r.amountIn.String(),
+			AmountDecimal:   id.FormatDecimalCompat(r.amountIn.String(), req.FromAsset.Decimals),
+			Decimals:        req.FromAsset.Decimals,
+		},
+		EstimatedOut: model.AmountInfo{
+			AmountBaseUnits: r.amountOut.String(),
+			AmountDecimal:   id.FormatDecimalCompat(r.amountOut.String(), r.toAsset.Decimals),
+			Decimals:        r.toAsset.Decimals,
+		},
+		EstimatedFeeUSD: 0,
+		EstimatedTimeS:  r.finalityS,
+		Route:           fmt.Sprintf("%s->%s", req.FromChain.Slug, req.ToChain.Slug),
+		SourceURL:       "https://portalbridge.com",
+		FetchedAt:       c.now().UTC().Format(time.RFC3339),
+	}, nil
+}
+
+func (c *Client) resolveRoute(ctx context.Context, req providers.BridgeQuoteRequest) (route, error) {
+	if req.FromAsset.Decimals <= 0 {
+		return route{}, clierr.New(clierr.CodeUsage, "wormhole quotes require a source token with known decimals")
+	}
+	amount, ok := new(big.Int).SetString(strings.TrimSpace(req.AmountBaseUnits), 10)
+	if !ok || amount.Sign() <= 0 {
+		return route{}, clierr.New(clierr.CodeUsage, "amount must be a positive integer in base units")
+	}
+	outDecimals := req.FromAsset.Decimals
+	scale := big.NewInt(1)
+	if outDecimals > maxTransferDecimals {
+		scale = new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(outDecimals-maxTransferDecimals)), nil)
+		outDecimals = maxTransferDecimals
+	}
+	amountOut := new(big.Int).Quo(amount, scale)
+	if amountOut.Sign() == 0 {
+		return route{}, clierr.New(clierr.CodeUsage, "amount is below the smallest unit the Portal token bridge can transfer (8 decimals)")
+	}
+	r := route{amountOut: amountOut, amountIn: new(big.Int).Mul(amountOut, scale)}
+
+	switch {
+	case req.FromChain.IsEVM() && req.ToChain.IsSolana():
+		src, ok := registry.WormholeEVM(req.FromChain.EVMChainID)
+		if !ok {
+			return route{}, clierr.New(clierr.CodeUnsupported, "wormhole does not support source chain "+req.FromChain.Slug)
+		}
+		if !common.IsHexAddress(req.FromAsset.Address) || isNative(req.FromAsset) {
+			return route{}, clierr.New(clierr.CodeUnsupported, "wormhole transfers require an ERC-20 source token; wrap native gas tokens first")
+		}
+		mint, err := wrappedMint(src.ChainID, common.BytesToHash(common.HexToAddress(req.FromAsset.Address).Bytes()), solanaTokenBridge)
+		if err != nil {
+			return route{}, clierr.Wrap(clierr.CodeInternal, "derive wrapped mint", err)
+		}
+		toAsset, err := id.ParseAsset(mint.String(), req.ToChain)
+		if err != nil {
+			return route{}, err
+		}
+		toAsset.Decimals = outDecimals
+		r.toAsset, r.finalityS, r.sourceEVM = toAsset, src.FinalityS, src
+		return r, nil
+	case req.FromChain.IsSolana() && req.ToChain.IsEVM():
+		dst, ok := registry.WormholeEVM(req.ToChain.EVMChainID)
+		if !ok {
+			return route{}, clierr.New(clierr.CodeUnsupported, "wormhole does not support destination chain "+req.ToChain.Slug)
+		}
+		mint, err := parseSolanaKey(req.FromAsset.Address)
+		if err != nil {
+			return route{}, clierr.Wrap(clierr.CodeUsage, "parse source mint", err)
+		}
+		wrapped, err := c.wrappedEVMAsset(ctx, req.ToChain.EVMChainID, dst.TokenBridge, registry.WormholeSolanaChainID, mint)
+		if err != nil {
+			return route{}, err
+		}
+		toAsset, err := id.ParseAsset(wrapped.Hex(), req.ToChain)
+		if err != nil {
+			return route{}, err
+		}
+		toAsset.Decimals = outDecimals
+		r.toAsset, r.finalityS = toAsset, registry.WormholeSolanaFinalityS
+		return r, nil
+	default:
+		return route{}, clierr.New(clierr.CodeUnsupported, "wormhole routes support EVM<->Solana transfers; use across or lifi between EVM chains")
+	}
+}
+
+func (c *Client) BuildBridgeAction(ctx context.Context, req providers.BridgeQuoteRequest, opts providers.BridgeExecutionOptions) (execution.Action, error) {
+	if req.FromChain.IsSolana() {
+		return execution.Action{}, clierr.New(clierr.CodeUnsupported, "wormhole execution from Solana needs a Solana signer, which is not available; use bridge quote only")
+	}
+	sender := strings.TrimSpace(opts.Sender)
+	if sender == "" {
+		return execution.Action{}, clierr.New(clierr.CodeUsage, "bridge execution requires sender address")
+	}
+	if !common.IsHexAddress(sender) {
+		return execution.Action{}, clierr.New(clierr.CodeUsage, "bridge execution sender must be a valid EVM address")
+	}
+	if strings.TrimSpace(opts.Recipient) == "" {
+		return execution.Action{}, clierr.New(clierr.CodeUsage, "wormhole transfers to Solana require --recipient with the Solana wallet address")
+	}
+	owner, err := parseSolanaKey(opts.Recipient)
+	if err != nil {
+		return execution.Action{}, clierr.Wrap(clierr.CodeUsage, "recipient must be a Solana wallet address", err)
+	}
+	// Associated token accounts are off-curve; deriving one from another
+	// would strand the funds in an account nobody can sign for.
+	if !isOnCurve(owner) {
+		return execution.Action{}, clierr.New(clierr.CodeUsage, "recipient must be a Solana wallet address, not a token account")
+	}
+
+	r, err := c.resolveRoute(ctx, req)
+	if err != nil {
+		return execution.Action{}, err
+	}
+	mint, err := parseSolanaKey(r.toAsset.Address)
+	if err != nil {
+		return execution.Action{}, clierr.Wrap(clierr.CodeInternal, "parse wrapped mint", err)
+	}
+	tokenAccount, err := associatedTokenAddress(owner, mint, solanaTokenProgram, solanaATAProgram)
+	if err != nil {
+		return execution.Action{}, clierr.Wrap(clierr.CodeInternal, "derive recipient token account", err)
+	}
+
+	rpcURL, err := c.rpcURL(opts.RPCURL, req.FromChain.EVMChainID)
+	if err != nil {
+		return execution.Action{}, clierr.Wrap(clierr.CodeUsage, "resolve rpc url", err)
+	}
+	client, err := rpcx.DialContext(ctx, rpcURL)
+	if err != nil {
+		return execution.Action{}, clierr.Wrap(clierr.CodeUnavailable, "connect rpc", err)
+	}
+	defer client.Close()
+
+	senderAddr := common.HexToAddress(sender)
+	token := common.HexToAddress(req.FromAsset.Address)
+	tokenBridge := common.HexToAddress(r.sourceEVM.TokenBridge)
+	wrapped, err := callBool(ctx, client, tokenBridge, tokenBridgeABI, "isWrappedAsset", token)
+	if err != nil {
+		return execution.Action{}, err
+	}
+	if wrapped {
+		return execution.Action{}, clierr.New(clierr.CodeUnsupported, "source token is itself Wormhole-wrapped; returning it to its origin chain is not supported yet")
+	}
+	messageFee, err := callUint(ctx, client, common.HexToAddress(r.sourceEVM.CoreBridge), coreABI, "messageFee")
+	if err != nil {
+		return execution.Action{}, err
+	}
+	allowance, err := callUint(ctx, client, token, erc20ABI, "allowance", senderAddr, tokenBridge)
+	if err != nil {
+		return execution.Action{}, err
+	}
+
+	// The nonce only groups messages into batch VAAs, which Portal does not use.
+	transferData, err := tokenBridgeABI.Pack("transferTokens", token, r.amountIn, registry.WormholeSolanaChainID, [32]byte(tokenAccount), big.NewInt(0), uint32(0))
+	if err != nil {
+		return execution.Action{}, clierr.Wrap(clierr.CodeInternal, "pack transferTokens calldata", err)
+	}
+
+	action := execution.NewAction(execution.NewActionID(), "bridge", req.FromChain.CAIP2, execution.Constraints{
+		Simulate: opts.Simulate,
+	})
+	action.Provider = "wormhole"
+	action.FromAddress = senderAddr.Hex()
+	action.ToAddress = owner.String()
+	action.InputAmount = r.amountIn.String()
+	action.Metadata = map[string]any{
+		"to_chain_id":               req.ToChain.CAIP2,
+		"from_asset_id":             req.FromAsset.AssetID,
+		"to_asset_id":               r.toAsset.AssetID,
+		"route":                     "wormhole",
+		"recipient_token_account":   tokenAccount.String(),
+		"redeem":                    "manual",
+		"wormhole_source_chain":     strconv.Itoa(int(r.sourceEVM.ChainID)),
+		"wormhole_destination_mint": mint.String(),
+	}
+
+	if allowance.Cmp(r.amountIn) < 0 {
+		approveData, err := erc20ABI.Pack("approve", tokenBridge, r.amountIn)
+		if err != nil {
+			return execution.Action{}, clierr.Wrap(clierr.CodeInternal, "pack approve calldata", err)
+		}
+		action.Steps = append(action.Steps, execution.ActionStep{
+			StepID:      "approve-wormhole-token-bridge",
+			Type:        execution.StepTypeApproval,
+			Status:      execution.StepStatusPending,
+			ChainID:     req.FromChain.CAIP2,
+			RPCURL:      rpcURL,
+			Description: "Approve Wormhole token bridge for source token",
+			Target:      token.Hex(),
+			Data:        "0x" + common.Bytes2Hex(approveData),
+			Value:       "0",
+		})
+	}
+	action.Steps = append(action.Steps, execution.ActionStep{
+		StepID:      "bridge-transfer",
+		Type:        execution.StepTypeBridge,
+		Status:      execution.StepStatusPending,
+		ChainID:     req.FromChain.CAIP2,
+		RPCURL:      rpcURL,
+		Description: "Bridge transfer via Wormhole Portal token bridge",
+		Target:      tokenBridge.Hex(),
+		Data:        "0x" + common.Bytes2Hex(transferData),
+		Value:       messageFee.String(),
+		ExpectedOutputs: map[string]string{
+			"to_amount_min":                r.amountOut.String(),
+			"settlement_provider":          "wormhole",
+			"settlement_status_endpoint":   registry.WormholeSettlementURL,
+			"settlement_recipient":         owner.String(),
+			"settlement_destination_chain": strconv.Itoa(int(registry.WormholeSolanaChainID)),
+		},
+	})
+	return action, nil
+}
+
+func (c *Client) wrappedEVMAsset(ctx context.Context, chainID int64, tokenBridge string, originChain uint16, origin solanaKey) (common.Address, error) {
+	rpcURL, err := c.rpcURL("", chainID)
+	if err != nil {
+		return common.Address{}, clierr.Wrap(clierr.CodeUsage, "resolve rpc url", err)
+	}
+	client, err := rpcx.DialContext(ctx, rpcURL)
+	if err != nil {
+		return common.Address{}, clierr.Wrap(clierr.CodeUnavailable, "connect rpc", err)
+	}
+	defer client.Close()
+	data, err := tokenBridgeABI.Pack("wrappedAsset", originChain, [32]byte(origin))
+	if err != nil {
+		return common.Address{}, clierr.Wrap(clierr.CodeInternal, "pack wrappedAsset call", err)
+	}
+	bridge := common.HexToAddress(tokenBridge)
+	out, err := client.CallContract(ctx, ethereum.CallMsg{To: &bridge, Data: data}, nil)
+	if err != nil {
+		return common.Address{}, clierr.Wrap(clierr.CodeUnavailable, "read wrapped asset", err)
+	}
+	values, err := tokenBridgeABI.Unpack("wrappedAsset", out)
+	if err != nil || len(values) == 0 {
+		return common.Address{}, clierr.Wrap(clierr.CodeUnavailable, "decode wrapped asset", err)
+	}
+	wrapped, ok := values[0].(common.Address)
+	if !ok || wrapped == (common.Address{}) {
+		return common.Address{}, clierr.New(clierr.CodeUnsupported, "token has not been attested to the destination chain on Wormhole")
+	}
+	return wrapped, nil
+}
+
+func callBool(ctx context.Context, client *ethclient.Client, target common.Address, contract abi.ABI, method string, args ...any) (bool, error) {
+	values, err := call(ctx, client, target, contract, method, args...)
+	if err != nil {
+		return false, err
+	}
+	v, ok := values[0].(bool)
+	if !ok {
+		return false, clierr.New(clierr.CodeUnavailable, "invalid "+method+" response")
+	}
+	return v, nil
+}
+
+func callUint(ctx context.Context, client *ethclient.Client, target common.Address, contract abi.ABI, method string, args ...any) (*big.Int, error) {
+	values, err := call(ctx, client, target, contract, method, args...)
+	if err != nil {
+		return nil, err
+	}
+	v, ok := values[0].(*big.Int)
+	if !ok || v == nil {
+		return nil, clierr.New(clierr.CodeUnavailable, "invalid "+method+" response")
+	}
+	return v, nil
+}
+
+func call(ctx context.Context, client *ethclient.Client, target common.Address, contract abi.ABI, method string, args ...any) ([]any, error) {
+	data, err := contract.Pack(method, args...)
+	if err != nil {
+		return nil, clierr.Wrap(clierr.CodeInternal, "pack "+method+" call", err)
+	}
+	out, err := client.CallContract(ctx, ethereum.CallMsg{To: &target, Data: data}, nil)
+	if err != nil {
+		return nil, clierr.Wrap(clierr.CodeUnavailable, "read "+method, err)
+	}
+	values, err := contract.Unpack(method, out)
+	if err != nil || len(values) == 0 {
+		return nil, clierr.Wrap(clierr.CodeUnavailable, "decode "+method, err)
+	}
+	return values, nil
+}
+
+func isNative(asset id.Asset) bool {
+	return strings.EqualFold(strings.TrimSpace(asset.Address), "0xEeeeeEeeeEeEeeEeEeEeeEEEeeeeEeeeeeeeEEeE")
+}
+
+func mustABI(raw string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(raw))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
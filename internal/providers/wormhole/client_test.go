@@ -0,0 +1,161 @@
+package wormhole
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/providers"
+	"github.com/ggonzalez94/defi-cli/internal/registry"
+)
+
+const testSolanaWallet = "9WzDXwBbmkg8ZTbNMqUxvQRAyrZzDsGYdLVL9zYtAWWM"
+
+// newZeroRPCServer answers every eth_call with a zero word: the token is not
+// wrapped, the message fee is zero and there is no allowance.
+func newZeroRPCServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		w.Header().Set("Content-Type", "application/json")
+		if req.Method != "eth_call" {
+			_, _ = fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"error":{"code":-32601,"message":"unsupported"}}`, req.ID)
+			return
+		}
+		_, _ = fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":"0x%064d"}`, req.ID, 0)
+	}))
+}
+
+func ethereumToSolana(t *testing.T, symbol, amount string) providers.BridgeQuoteRequest {
+	t.Helper()
+	fromChain, err := id.ParseChain("ethereum")
+	if err != nil {
+		t.Fatalf("parse chain: %v", err)
+	}
+	toChain, err := id.ParseChain("solana")
+	if err != nil {
+		t.Fatalf("parse chain: %v", err)
+	}
+	fromAsset, err := id.ParseAsset(symbol, fromChain)
+	if err != nil {
+		t.Fatalf("parse asset: %v", err)
+	}
+	return providers.BridgeQuoteRequest{
+		FromChain:       fromChain,
+		ToChain:         toChain,
+		FromAsset:       fromAsset,
+		AmountBaseUnits: amount,
+	}
+}
+
+func TestQuoteBridgeTruncatesToPortalDecimals(t *testing.T) {
+	c := New()
+	c.now = func() time.Time { return time.Unix(1_800_000_000, 0) }
+	quote, err := c.QuoteBridge(context.Background(), ethereumToSolana(t, "WETH", "1234567891234567891"))
+	if err != nil {
+		t.Fatalf("QuoteBridge failed: %v", err)
+	}
+	if quote.InputAmount.AmountBaseUnits != "1234567890000000000" {
+		t.Fatalf("expected input truncated to 8 decimals, got %s", quote.InputAmount.AmountBaseUnits)
+	}
+	if quote.EstimatedOut.AmountBaseUnits != "123456789" || quote.EstimatedOut.Decimals != 8 {
+		t.Fatalf("unexpected output amount: %+v", quote.EstimatedOut)
+	}
+	if !strings.HasSuffix(quote.ToAssetID, "7vfCXTUXx5WJV5JADk17DUJ4ksgau7utNKj4b963voxs") {
+		t.Fatalf("expected Portal WETH mint, got %s", quote.ToAssetID)
+	}
+	if quote.EstimatedFeeUSD != 0 || quote.EstimatedTimeS <= 0 {
+		t.Fatalf("unexpected fee/time: %+v", quote)
+	}
+}
+
+func TestQuoteBridgeRejectsEVMToEVM(t *testing.T) {
+	req := ethereumToSolana(t, "USDC", "1000000")
+	req.ToChain, _ = id.ParseChain("base")
+	if _, err := New().QuoteBridge(context.Background(), req); err == nil {
+		t.Fatal("expected unsupported route error")
+	}
+}
+
+func TestQuoteBridgeRejectsDust(t *testing.T) {
+	if _, err := New().QuoteBridge(context.Background(), ethereumToSolana(t, "WETH", "9999999999")); err == nil {
+		t.Fatal("expected error for amount below 8-decimal precision")
+	}
+}
+
+func TestBuildBridgeAction(t *testing.T) {
+	rpc := newZeroRPCServer()
+	defer rpc.Close()
+
+	action, err := New().BuildBridgeAction(context.Background(), ethereumToSolana(t, "USDC", "2500000"), providers.BridgeExecutionOptions{
+		Sender:    "0x00000000000000000000000000000000000000AA",
+		Recipient: testSolanaWallet,
+		Simulate:  true,
+		RPCURL:    rpc.URL,
+	})
+	if err != nil {
+		t.Fatalf("BuildBridgeAction failed: %v", err)
+	}
+	if action.Provider != "wormhole" || action.ToAddress != testSolanaWallet {
+		t.Fatalf("unexpected action: provider=%s to=%s", action.Provider, action.ToAddress)
+	}
+	if len(action.Steps) != 2 {
+		t.Fatalf("expected approval + bridge steps, got %d", len(action.Steps))
+	}
+	src, _ := registry.WormholeEVM(1)
+	bridge := action.Steps[1]
+	if !strings.EqualFold(bridge.Target, src.TokenBridge) || !strings.EqualFold(action.Steps[0].Target, "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48") {
+		t.Fatalf("unexpected step targets: %s %s", action.Steps[0].Target, bridge.Target)
+	}
+	if !registry.IsAllowedBridgeExecutionTarget("wormhole", 1, bridge.Target) {
+		t.Fatalf("token bridge %s is not an allowed execution target", bridge.Target)
+	}
+	if bridge.ExpectedOutputs["settlement_provider"] != "wormhole" || bridge.ExpectedOutputs["to_amount_min"] != "2500000" {
+		t.Fatalf("unexpected expected outputs: %#v", bridge.ExpectedOutputs)
+	}
+
+	// transferTokens(token, amount, recipientChain, recipient, arbiterFee, nonce)
+	args, err := tokenBridgeABI.Methods["transferTokens"].Inputs.Unpack(common.FromHex(bridge.Data)[4:])
+	if err != nil {
+		t.Fatalf("decode transferTokens: %v", err)
+	}
+	if args[2].(uint16) != registry.WormholeSolanaChainID {
+		t.Fatalf("unexpected recipient chain: %v", args[2])
+	}
+	recipient := solanaKey(args[3].([32]byte))
+	if recipient.String() != action.Metadata["recipient_token_account"] {
+		t.Fatalf("transfer recipient %s does not match token account %v", recipient, action.Metadata["recipient_token_account"])
+	}
+	if recipient.String() == testSolanaWallet || isOnCurve(recipient) {
+		t.Fatal("expected transfer to the owner's associated token account")
+	}
+}
+
+func TestBuildBridgeActionRequiresSolanaWallet(t *testing.T) {
+	req := ethereumToSolana(t, "USDC", "2500000")
+	opts := providers.BridgeExecutionOptions{Sender: "0x00000000000000000000000000000000000000AA"}
+	if _, err := New().BuildBridgeAction(context.Background(), req, opts); err == nil || !strings.Contains(err.Error(), "--recipient") {
+		t.Fatalf("expected missing recipient error, got %v", err)
+	}
+
+	// A token account (off-curve PDA) must not be accepted as the owner.
+	opts.Recipient = "5Q544fKrFoe6tsEbD7S8EmxGTJYAKtTVhAW5Q5pge4j1"
+	if _, err := New().BuildBridgeAction(context.Background(), req, opts); err == nil || !strings.Contains(err.Error(), "token account") {
+		t.Fatalf("expected token account rejection, got %v", err)
+	}
+
+	opts.Recipient = "0x00000000000000000000000000000000000000BB"
+	if _, err := New().BuildBridgeAction(context.Background(), req, opts); err == nil {
+		t.Fatal("expected EVM recipient to be rejected")
+	}
+}
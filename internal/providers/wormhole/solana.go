@@ -0,0 +1,113 @@
+package wormhole
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"math/big"
+	"strings"
+
+	"github.com/ggonzalez94/defi-cli/internal/id"
+)
+
+// Solana addresses are base58-encoded 32-byte keys. Program-derived
+// addresses (PDAs) are computed here so transfers to Solana can name the
+// wrapped mint and the recipient's associated token account without a
+// Solana RPC.
+
+var (
+	// edwards25519 field prime p = 2^255 - 19 and curve constant d.
+	curveP = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 255), big.NewInt(19))
+	curveD = func() *big.Int {
+		num := big.NewInt(-121665)
+		den := new(big.Int).ModInverse(big.NewInt(121666), curveP)
+		d := new(big.Int).Mul(num, den)
+		return d.Mod(d, curveP)
+	}()
+	legendreExp = new(big.Int).Rsh(new(big.Int).Sub(curveP, big.NewInt(1)), 1)
+)
+
+type solanaKey [32]byte
+
+func (k solanaKey) String() string {
+	return id.EncodeBase58(k[:])
+}
+
+func parseSolanaKey(raw string) (solanaKey, error) {
+	decoded, err := id.DecodeBase58(strings.TrimSpace(raw))
+	if err != nil {
+		return solanaKey{}, err
+	}
+	if len(decoded) != 32 {
+		return solanaKey{}, errors.New("solana address must decode to 32 bytes")
+	}
+	var key solanaKey
+	copy(key[:], decoded)
+	return key, nil
+}
+
+func mustSolanaKey(raw string) solanaKey {
+	key, err := parseSolanaKey(raw)
+	if err != nil {
+		panic(err)
+	}
+	return key
+}
+
+// findProgramAddress mirrors Pubkey::find_program_address: the first bump
+// from 255 down whose hash is not a valid curve point.
+func findProgramAddress(seeds [][]byte, program solanaKey) (solanaKey, error) {
+	for bump := 255; bump >= 0; bump-- {
+		h := sha256.New()
+		for _, seed := range seeds {
+			h.Write(seed)
+		}
+		h.Write([]byte{byte(bump)})
+		h.Write(program[:])
+		h.Write([]byte("ProgramDerivedAddress"))
+		var candidate solanaKey
+		copy(candidate[:], h.Sum(nil))
+		if !isOnCurve(candidate) {
+			return candidate, nil
+		}
+	}
+	return solanaKey{}, errors.New("no viable program address bump")
+}
+
+// wrappedMint is the Portal token bridge mint on Solana for a token that
+// originates on tokenChain.
+func wrappedMint(tokenChain uint16, tokenAddress [32]byte, tokenBridge solanaKey) (solanaKey, error) {
+	chain := make([]byte, 2)
+	binary.BigEndian.PutUint16(chain, tokenChain)
+	return findProgramAddress([][]byte{[]byte("wrapped"), chain, tokenAddress[:]}, tokenBridge)
+}
+
+func associatedTokenAddress(owner, mint, tokenProgram, ataProgram solanaKey) (solanaKey, error) {
+	return findProgramAddress([][]byte{owner[:], tokenProgram[:], mint[:]}, ataProgram)
+}
+
+// isOnCurve reports whether key decompresses to an edwards25519 point, i.e.
+// x^2 = (y^2 - 1) / (d*y^2 + 1) has a square root mod p.
+func isOnCurve(key solanaKey) bool {
+	le := key
+	le[31] &= 0x7f
+	be := make([]byte, 32)
+	for i := range le {
+		be[31-i] = le[i]
+	}
+	y := new(big.Int).SetBytes(be)
+	y.Mod(y, curveP)
+	y2 := new(big.Int).Mul(y, y)
+	y2.Mod(y2, curveP)
+	u := new(big.Int).Sub(y2, big.NewInt(1))
+	u.Mod(u, curveP)
+	v := new(big.Int).Mul(curveD, y2)
+	v.Add(v, big.NewInt(1))
+	v.Mod(v, curveP)
+	x2 := new(big.Int).Mul(u, new(big.Int).ModInverse(v, curveP))
+	x2.Mod(x2, curveP)
+	if x2.Sign() == 0 {
+		return true
+	}
+	return new(big.Int).Exp(x2, legendreExp, curveP).Cmp(big.NewInt(1)) == 0
+}
@@ -0,0 +1,32 @@
+package wormhole
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestWrappedMintMatchesPortalMints(t *testing.T) {
+	bridge := mustSolanaKey("wormDTUJ6AWPNvk59vGQbDvGJmqbDTdgWgAqcLBCgUb")
+	cases := map[string]string{
+		"0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48": "A9mUU4qviSctJVPJdBJWkb28deg915LYJKrzQ19ji3FM",
+		"0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2": "7vfCXTUXx5WJV5JADk17DUJ4ksgau7utNKj4b963voxs",
+		"0xdAC17F958D2ee523a2206206994597C13D831ec7": "Dn4noZ5jgGfkntzcQSUZ8czkreiZ1ForXYoV2H8Dm7S1",
+	}
+	for token, want := range cases {
+		mint, err := wrappedMint(2, common.BytesToHash(common.HexToAddress(token).Bytes()), bridge)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if mint.String() != want {
+			t.Errorf("%s: got %s want %s", token, mint, want)
+		}
+	}
+	var base solanaKey
+	b, _ := hex.DecodeString("5866666666666666666666666666666666666666666666666666666666666666")
+	copy(base[:], b)
+	if !isOnCurve(base) {
+		t.Fatal("base point should be on curve")
+	}
+}
@@ -0,0 +1,103 @@
+package yieldutil
+
+import (
+	"math"
+
+	"github.com/ggonzalez94/defi-cli/internal/model"
+)
+
+// APYDiscrepancyThreshold is the max/min ratio between two APYs for the same
+// market above which the values almost certainly use different units.
+const APYDiscrepancyThreshold = 10.0
+
+// PercentFromRatio converts a provider APY fraction (0.05) into the percentage
+// points (5) that every command emits. Non-finite inputs map to zero.
+func PercentFromRatio(ratio float64) float64 {
+	if math.IsNaN(ratio) || math.IsInf(ratio, 0) {
+		return 0
+	}
+	return ratio * 100
+}
+
+// RatioFromPercent is the inverse of PercentFromRatio.
+func RatioFromPercent(percent float64) float64 {
+	if math.IsNaN(percent) || math.IsInf(percent, 0) {
+		return 0
+	}
+	return percent / 100
+}
+
+// APYDiscrepancy returns the max/min ratio between two positive APYs, or 0
+// when either value is not positive and the pair cannot be compared.
+func APYDiscrepancy(a, b float64) float64 {
+	if !(a > 0) || !(b > 0) || math.IsInf(a, 0) || math.IsInf(b, 0) {
+		return 0
+	}
+	return math.Max(a, b) / math.Min(a, b)
+}
+
+// Rate bases: whether a provider's rate already includes compounding.
+const (
+	RateBasisAPR = "apr"
+	RateBasisAPY = "apy"
+)
+
+// Compounding frequencies of a rate. CompoundingNone means earnings are paid
+// out rather than reinvested, so an APR is also the effective APY.
+const (
+	CompoundingNone       = "none"
+	CompoundingDaily      = "daily"
+	CompoundingPerSecond  = "per_second"
+	CompoundingContinuous = "continuous"
+)
+
+// compoundingPeriods is the number of compounding periods per year.
+var compoundingPeriods = map[string]float64{
+	CompoundingDaily:     365,
+	CompoundingPerSecond: 365 * 24 * 60 * 60,
+}
+
+// EffectiveAPY converts a rate in percentage points on basis, compounded at
+// compounding, into a compounded APY. APYs and rates that are not
+// reinvested are returned unchanged.
+func EffectiveAPY(ratePct float64, basis, compounding string) float64 {
+	if math.IsNaN(ratePct) || math.IsInf(ratePct, 0) {
+		return 0
+	}
+	if basis != RateBasisAPR {
+		return ratePct
+	}
+	apr := RatioFromPercent(ratePct)
+	if compounding == CompoundingContinuous {
+		return PercentFromRatio(math.Expm1(apr))
+	}
+	periods, ok := compoundingPeriods[compounding]
+	if !ok || apr/periods <= -1 {
+		return ratePct
+	}
+	return PercentFromRatio(math.Pow(1+apr/periods, periods) - 1)
+}
+
+// ApplyEffectiveAPY sets apy_effective on every item from apy_total. Items
+// without a rate_basis are taken to report an APY.
+func ApplyEffectiveAPY(items []model.YieldOpportunity) {
+	for i := range items {
+		if items[i].RateBasis == "" {
+			items[i].RateBasis = RateBasisAPY
+		}
+		items[i].APYEffective = EffectiveAPY(items[i].APYTotal, items[i].RateBasis, items[i].Compounding)
+	}
+}
+
+// ApplyLendRateEffectiveAPY sets supply_apy_effective and
+// borrow_apy_effective on every rate. Rates without a rate_basis are taken to
+// be APYs.
+func ApplyLendRateEffectiveAPY(rates []model.LendRate) {
+	for i := range rates {
+		if rates[i].RateBasis == "" {
+			rates[i].RateBasis = RateBasisAPY
+		}
+		rates[i].SupplyAPYEffective = EffectiveAPY(rates[i].SupplyAPY, rates[i].RateBasis, rates[i].Compounding)
+		rates[i].BorrowAPYEffective = EffectiveAPY(rates[i].BorrowAPY, rates[i].RateBasis, rates[i].Compounding)
+	}
+}
@@ -0,0 +1,113 @@
+package yieldutil
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/ggonzalez94/defi-cli/internal/model"
+)
+
+// Yield opportunity types of liquidity pool positions.
+const (
+	TypeLPVolatile = "lp_volatile"
+	TypeLPStable   = "lp_stable"
+)
+
+// DefaultILMoves are the price moves of asset A against asset B, in percent,
+// that LP analytics report impermanent loss for.
+var DefaultILMoves = []float64{-50, -25, -10, 10, 25, 50, 100}
+
+// IsLP reports whether an opportunity type is a liquidity pool position.
+func IsLP(kind string) bool {
+	return kind == TypeLPVolatile || kind == TypeLPStable
+}
+
+// ImpermanentLossPct is the loss, in percent of holding both assets, of a
+// 50/50 constant-product position after asset A moves priceMovePct against
+// asset B. It is zero or negative; a move to zero loses everything.
+func ImpermanentLossPct(priceMovePct float64) float64 {
+	ratio := 1 + priceMovePct/100
+	if ratio <= 0 {
+		return -100
+	}
+	return (2*math.Sqrt(ratio)/(1+ratio) - 1) * 100
+}
+
+// PairVolatilityPct is the annualized volatility, in percent, of the price of
+// a in terms of b. Points are matched by timestamp truncated to interval and
+// the standard deviation of log returns is scaled by the number of intervals
+// in a year. It returns false with fewer than three matched points.
+func PairVolatilityPct(a, b []model.MarketHistoryPoint, interval time.Duration) (float64, bool) {
+	if interval <= 0 {
+		return 0, false
+	}
+	bByTime := make(map[int64]float64, len(b))
+	for _, point := range b {
+		if ts, err := time.Parse(time.RFC3339, point.Timestamp); err == nil && point.Value > 0 {
+			bByTime[ts.Truncate(interval).Unix()] = point.Value
+		}
+	}
+	type sample struct {
+		at    int64
+		ratio float64
+	}
+	samples := make([]sample, 0, len(a))
+	seen := map[int64]bool{}
+	for _, point := range a {
+		ts, err := time.Parse(time.RFC3339, point.Timestamp)
+		if err != nil || point.Value <= 0 {
+			continue
+		}
+		at := ts.Truncate(interval).Unix()
+		if priceB, ok := bByTime[at]; ok && !seen[at] {
+			seen[at] = true
+			samples = append(samples, sample{at: at, ratio: point.Value / priceB})
+		}
+	}
+	if len(samples) < 3 {
+		return 0, false
+	}
+	sort.Slice(samples, func(i, j int) bool { return samples[i].at < samples[j].at })
+	returns := make([]float64, 0, len(samples)-1)
+	for i := 1; i < len(samples); i++ {
+		returns = append(returns, math.Log(samples[i].ratio/samples[i-1].ratio))
+	}
+	var sum float64
+	for _, r := range returns {
+		sum += r
+	}
+	mean := sum / float64(len(returns))
+	var sqDiff float64
+	for _, r := range returns {
+		sqDiff += (r - mean) * (r - mean)
+	}
+	stddev := math.Sqrt(sqDiff / float64(len(returns)-1))
+	periodsPerYear := float64(365*24*time.Hour) / float64(interval)
+	return stddev * math.Sqrt(periodsPerYear) * 100, true
+}
+
+// LPAnalytics computes impermanent loss scenarios for moves and, when the
+// pair volatility is known, the loss after a one standard deviation annual
+// move. Fees are the base APY and rewards the reward APY of the pool.
+func LPAnalytics(volatilityPct float64, windowDays int, moves []float64, apyBase, apyReward float64) *model.YieldLPAnalytics {
+	out := &model.YieldLPAnalytics{
+		PairVolatilityPct:    round2(volatilityPct),
+		VolatilityWindowDays: windowDays,
+		ILScenarios:          make([]model.YieldILScenario, 0, len(moves)),
+		FeeAPR:               apyBase,
+		RewardAPR:            apyReward,
+	}
+	for _, move := range moves {
+		out.ILScenarios = append(out.ILScenarios, model.YieldILScenario{PriceMovePct: move, ILPct: round2(ImpermanentLossPct(move))})
+	}
+	if volatilityPct > 0 {
+		out.ExpectedILPct = round2(ImpermanentLossPct((math.Exp(volatilityPct/100) - 1) * 100))
+	}
+	out.NetAPYAfterIL = round2(apyBase + apyReward + out.ExpectedILPct)
+	return out
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
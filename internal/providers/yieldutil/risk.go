@@ -0,0 +1,206 @@
+package yieldutil
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ggonzalez94/defi-cli/internal/model"
+)
+
+// Risk levels derived from risk_score.
+const (
+	RiskLow    = "low"
+	RiskMedium = "medium"
+	RiskHigh   = "high"
+)
+
+// Asset volatility classes of risk_factors.asset_class.
+const (
+	AssetClassStable   = "stable"
+	AssetClassMajor    = "major"
+	AssetClassVolatile = "volatile"
+)
+
+// Factor weights of risk_score. Every factor scores 0 (safest) to 100.
+const (
+	protocolWeight    = 0.30
+	tvlWeight         = 0.25
+	utilizationWeight = 0.20
+	assetWeight       = 0.25
+)
+
+// auditedSince is when each protocol's audited contracts went live on
+// mainnet. Protocols missing here score as unaudited unless their security
+// metadata lists audits.
+var auditedSince = map[string]time.Time{
+	"aave":       time.Date(2020, 1, 8, 0, 0, 0, 0, time.UTC),
+	"lido":       time.Date(2020, 12, 18, 0, 0, 0, 0, time.UTC),
+	"rocketpool": time.Date(2021, 11, 9, 0, 0, 0, 0, time.UTC),
+	"moonwell":   time.Date(2022, 5, 1, 0, 0, 0, 0, time.UTC),
+	"spark":      time.Date(2023, 5, 9, 0, 0, 0, 0, time.UTC),
+	"etherfi":    time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC),
+	"velodrome":  time.Date(2023, 6, 15, 0, 0, 0, 0, time.UTC),
+	"aerodrome":  time.Date(2023, 8, 28, 0, 0, 0, 0, time.UTC),
+	"kamino":     time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC),
+	"morpho":     time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+}
+
+var stableSymbols = map[string]bool{
+	"USDC": true, "USDC.E": true, "USDBC": true, "USDT": true, "USDT0": true, "DAI": true, "USDS": true,
+	"SDAI": true, "SUSDS": true, "USDE": true, "SUSDE": true, "FRAX": true, "PYUSD": true, "GHO": true,
+	"LUSD": true, "CRVUSD": true, "RLUSD": true, "USDG": true, "EURC": true, "EURS": true,
+}
+
+var majorSymbols = map[string]bool{
+	"ETH": true, "WETH": true, "STETH": true, "WSTETH": true, "RETH": true, "CBETH": true, "EETH": true,
+	"WEETH": true, "BTC": true, "WBTC": true, "CBBTC": true, "TBTC": true, "LBTC": true, "SOL": true,
+	"WSOL": true, "MSOL": true, "JITOSOL": true, "BSOL": true, "JUPSOL": true,
+}
+
+// ApplyRisk sets risk_score, risk_level, and risk_factors on every item from
+// the same inputs for every provider: how long the protocol has run audited
+// code, its known exploits, TVL, utilization (the share of TVL that cannot be
+// withdrawn now), and the volatility class of the backing assets. Unknown
+// inputs score as risky, except utilization, which scores neutral. Call it
+// again after attaching security to rescore.
+func ApplyRisk(items []model.YieldOpportunity) {
+	for i := range items {
+		now, err := time.Parse(time.RFC3339, items[i].FetchedAt)
+		if err != nil {
+			now = time.Now()
+		}
+		factors := riskFactors(items[i], now)
+		score := protocolWeight*factors.ProtocolScore + tvlWeight*factors.TVLScore +
+			utilizationWeight*factors.UtilizationScore + assetWeight*factors.AssetScore
+		items[i].RiskScore = math.Round(score*10) / 10
+		items[i].RiskLevel = RiskLevel(items[i].RiskScore)
+		items[i].RiskFactors = &factors
+	}
+}
+
+// RiskLevel buckets a 0-100 risk score.
+func RiskLevel(score float64) string {
+	switch {
+	case score < 35:
+		return RiskLow
+	case score < 65:
+		return RiskMedium
+	default:
+		return RiskHigh
+	}
+}
+
+// ParseMaxRisk parses --max-risk: a level (low|medium|high) or a score from
+// 0 to 100. It returns the highest risk_score allowed.
+func ParseMaxRisk(raw string) (float64, error) {
+	raw = strings.ToLower(strings.TrimSpace(raw))
+	switch raw {
+	case RiskLow:
+		return math.Nextafter(35, 0), nil
+	case RiskMedium:
+		return math.Nextafter(65, 0), nil
+	case RiskHigh:
+		return 100, nil
+	}
+	score, err := strconv.ParseFloat(raw, 64)
+	if err != nil || math.IsNaN(score) || score < 0 || score > 100 {
+		return 0, fmt.Errorf("expected low, medium, high, or a score from 0 to 100, got %q", raw)
+	}
+	return score, nil
+}
+
+func riskFactors(item model.YieldOpportunity, now time.Time) model.YieldRiskFactors {
+	factors := model.YieldRiskFactors{ProtocolScore: 100, TVLScore: 100, UtilizationScore: 50}
+	if since, ok := auditedSince[strings.ToLower(strings.TrimSpace(item.Protocol))]; ok {
+		factors.AuditAgeYears = math.Round(now.Sub(since).Hours()/24/365.25*10) / 10
+	} else if item.Security != nil && item.Security.Audits > 0 {
+		factors.AuditAgeYears = math.Round(float64(item.Security.ListingAgeDays)/365.25*10) / 10
+	}
+	if factors.AuditAgeYears > 0 {
+		// Five audited years in production count as fully proven.
+		factors.ProtocolScore = clampScore(100 - factors.AuditAgeYears*20)
+	}
+	if item.Security != nil && item.Security.Exploits > 0 {
+		factors.Exploits = item.Security.Exploits
+		// Each known exploit takes back two proven years.
+		factors.ProtocolScore = clampScore(factors.ProtocolScore + float64(factors.Exploits)*40)
+	}
+	if tvl := PositiveFirst(item.TVLUSD); tvl > 0 {
+		// $1M or less scores 100, $1B or more scores 0, log-linear between.
+		factors.TVLScore = clampScore((9 - math.Log10(tvl)) / 3 * 100)
+	}
+	if item.ExitLiquidity != nil {
+		utilization := 100 - item.ExitLiquidity.ImmediatePct
+		factors.UtilizationPct = math.Round(utilization*10) / 10
+		// Utilization up to 50% leaves ample exit liquidity; 100% leaves none.
+		factors.UtilizationScore = clampScore((utilization - 50) * 2)
+	}
+	factors.AssetClass, factors.AssetScore = assetRisk(item.BackingAssets)
+	return factors
+}
+
+// assetRisk returns the most volatile class among assets and their
+// share-weighted class score. Without assets the class is volatile.
+func assetRisk(assets []model.YieldBackingAsset) (string, float64) {
+	worst, weighted, total := "", 0.0, 0.0
+	for _, asset := range assets {
+		class := AssetClass(asset.Symbol)
+		if classRank(class) > classRank(worst) {
+			worst = class
+		}
+		share := asset.SharePct
+		if share <= 0 {
+			share = 1
+		}
+		weighted += share * classScore(class)
+		total += share
+	}
+	if total == 0 {
+		return AssetClassVolatile, classScore(AssetClassVolatile)
+	}
+	return worst, math.Round(weighted/total*10) / 10
+}
+
+// AssetClass classifies a token symbol as stable, major, or volatile.
+func AssetClass(symbol string) string {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+	switch {
+	case stableSymbols[symbol]:
+		return AssetClassStable
+	case majorSymbols[symbol]:
+		return AssetClassMajor
+	default:
+		return AssetClassVolatile
+	}
+}
+
+func classRank(class string) int {
+	switch class {
+	case AssetClassStable:
+		return 1
+	case AssetClassMajor:
+		return 2
+	case AssetClassVolatile:
+		return 3
+	default:
+		return 0
+	}
+}
+
+func classScore(class string) float64 {
+	switch class {
+	case AssetClassStable:
+		return 10
+	case AssetClassMajor:
+		return 35
+	default:
+		return 80
+	}
+}
+
+func clampScore(score float64) float64 {
+	return math.Round(math.Max(0, math.Min(100, score))*10) / 10
+}
@@ -17,6 +17,27 @@ func PositiveFirst(values ...float64) float64 {
 	return 0
 }
 
+// ExitLiquidity estimates how much of an opportunity's TVL could be withdrawn
+// immediately versus queued behind missing liquidity or a lockup period.
+// It returns nil when TVL is unknown.
+func ExitLiquidity(tvlUSD, liquidityUSD, lockupDays float64) *model.YieldExitLiquidity {
+	tvlUSD = PositiveFirst(tvlUSD)
+	if tvlUSD == 0 {
+		return nil
+	}
+	lockupDays = PositiveFirst(lockupDays)
+	immediate := math.Min(PositiveFirst(liquidityUSD), tvlUSD)
+	if lockupDays > 0 {
+		immediate = 0
+	}
+	return &model.YieldExitLiquidity{
+		ImmediateUSD: immediate,
+		QueuedUSD:    tvlUSD - immediate,
+		ImmediatePct: immediate / tvlUSD * 100,
+		QueueDays:    lockupDays,
+	}
+}
+
 func Sort(items []model.YieldOpportunity, sortBy string) {
 	sortBy = strings.ToLower(strings.TrimSpace(sortBy))
 	if sortBy == "" {
@@ -30,6 +51,10 @@ func Sort(items []model.YieldOpportunity, sortBy string) {
 			if a.APYTotal != b.APYTotal {
 				return a.APYTotal > b.APYTotal
 			}
+		case "apy_effective":
+			if a.APYEffective != b.APYEffective {
+				return a.APYEffective > b.APYEffective
+			}
 		case "tvl_usd":
 			if a.TVLUSD != b.TVLUSD {
 				return a.TVLUSD > b.TVLUSD
@@ -38,6 +63,10 @@ func Sort(items []model.YieldOpportunity, sortBy string) {
 			if a.LiquidityUSD != b.LiquidityUSD {
 				return a.LiquidityUSD > b.LiquidityUSD
 			}
+		case "score", "risk_score":
+			if a.RiskScore != b.RiskScore {
+				return a.RiskScore < b.RiskScore
+			}
 		default:
 			if a.APYTotal != b.APYTotal {
 				return a.APYTotal > b.APYTotal
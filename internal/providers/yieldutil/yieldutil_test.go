@@ -3,6 +3,7 @@ package yieldutil
 import (
 	"math"
 	"testing"
+	"time"
 
 	"github.com/ggonzalez94/defi-cli/internal/model"
 )
@@ -25,3 +26,237 @@ func TestSort(t *testing.T) {
 		t.Fatalf("unexpected sort order: %#v", items)
 	}
 }
+
+func TestExitLiquidity(t *testing.T) {
+	if got := ExitLiquidity(0, 10, 0); got != nil {
+		t.Fatalf("expected nil exit liquidity without tvl, got %+v", got)
+	}
+
+	got := ExitLiquidity(200, 50, 0)
+	if got == nil || got.ImmediateUSD != 50 || got.QueuedUSD != 150 || got.ImmediatePct != 25 || got.QueueDays != 0 {
+		t.Fatalf("unexpected partial exit liquidity: %+v", got)
+	}
+
+	got = ExitLiquidity(100, 500, 0)
+	if got == nil || got.ImmediateUSD != 100 || got.QueuedUSD != 0 || got.ImmediatePct != 100 {
+		t.Fatalf("expected liquidity capped at tvl, got %+v", got)
+	}
+
+	got = ExitLiquidity(100, 100, 7)
+	if got == nil || got.ImmediateUSD != 0 || got.QueuedUSD != 100 || got.QueueDays != 7 {
+		t.Fatalf("expected lockup to queue full tvl, got %+v", got)
+	}
+}
+
+func TestAPYPercentRoundTrip(t *testing.T) {
+	for _, ratio := range []float64{0, 0.0001, 0.0325, 0.5, 1.2} {
+		pct := PercentFromRatio(ratio)
+		if math.Abs(pct-ratio*100) > 1e-12 {
+			t.Fatalf("expected %v to map to percent points, got %v", ratio, pct)
+		}
+		if back := RatioFromPercent(pct); math.Abs(back-ratio) > 1e-12 {
+			t.Fatalf("round trip mismatch for %v: got %v", ratio, back)
+		}
+	}
+	if PercentFromRatio(math.NaN()) != 0 || PercentFromRatio(math.Inf(1)) != 0 {
+		t.Fatal("expected non-finite ratios to map to zero")
+	}
+}
+
+func TestAPYDiscrepancy(t *testing.T) {
+	if got := APYDiscrepancy(4.5, 0.045); math.Abs(got-100) > 1e-9 {
+		t.Fatalf("expected 100x discrepancy for percent vs fraction, got %v", got)
+	}
+	if got := APYDiscrepancy(4, 5); got >= APYDiscrepancyThreshold {
+		t.Fatalf("expected consistent values below threshold, got %v", got)
+	}
+	if APYDiscrepancy(0, 5) != 0 {
+		t.Fatal("expected zero APY to be incomparable")
+	}
+}
+
+func TestEffectiveAPY(t *testing.T) {
+	for _, tc := range []struct {
+		rate        float64
+		basis       string
+		compounding string
+		want        float64
+	}{
+		{10, RateBasisAPY, CompoundingPerSecond, 10},
+		{10, RateBasisAPR, CompoundingNone, 10},
+		{10, RateBasisAPR, "", 10},
+		{10, RateBasisAPR, CompoundingDaily, 10.515578},
+		{10, RateBasisAPR, CompoundingPerSecond, 10.517092},
+		{10, RateBasisAPR, CompoundingContinuous, 10.517092},
+		{math.NaN(), RateBasisAPR, CompoundingDaily, 0},
+	} {
+		if got := EffectiveAPY(tc.rate, tc.basis, tc.compounding); math.Abs(got-tc.want) > 1e-5 {
+			t.Fatalf("EffectiveAPY(%v, %s, %s) = %v, want %v", tc.rate, tc.basis, tc.compounding, got, tc.want)
+		}
+	}
+
+	items := []model.YieldOpportunity{
+		{OpportunityID: "apr", APYTotal: 10, RateBasis: RateBasisAPR, Compounding: CompoundingDaily},
+		{OpportunityID: "apy", APYTotal: 10.5},
+	}
+	ApplyEffectiveAPY(items)
+	if items[1].RateBasis != RateBasisAPY || items[1].APYEffective != 10.5 {
+		t.Fatalf("expected an unmarked rate to be treated as an APY, got %+v", items[1])
+	}
+	Sort(items, "apy_effective")
+	if items[0].OpportunityID != "apr" {
+		t.Fatalf("expected the daily-compounded 10%% APR to outrank a 10.5%% APY, got %+v", items)
+	}
+
+	rates := []model.LendRate{{SupplyAPY: 3, BorrowAPY: 5, RateBasis: RateBasisAPR, Compounding: CompoundingPerSecond}}
+	ApplyLendRateEffectiveAPY(rates)
+	if math.Abs(rates[0].SupplyAPYEffective-3.045453) > 1e-5 || math.Abs(rates[0].BorrowAPYEffective-5.127110) > 1e-5 {
+		t.Fatalf("unexpected effective lend rates %+v", rates[0])
+	}
+}
+
+func TestApplyRisk(t *testing.T) {
+	items := []model.YieldOpportunity{
+		{
+			OpportunityID: "aave-usdc",
+			Protocol:      "aave",
+			TVLUSD:        2_000_000_000,
+			ExitLiquidity: &model.YieldExitLiquidity{ImmediatePct: 60},
+			BackingAssets: []model.YieldBackingAsset{{Symbol: "USDC", SharePct: 100}},
+			FetchedAt:     "2026-01-01T00:00:00Z",
+		},
+		{
+			OpportunityID: "new-meme",
+			Protocol:      "unknown-protocol",
+			TVLUSD:        500_000,
+			ExitLiquidity: &model.YieldExitLiquidity{ImmediatePct: 2},
+			BackingAssets: []model.YieldBackingAsset{{Symbol: "PEPE", SharePct: 100}},
+			FetchedAt:     "2026-01-01T00:00:00Z",
+		},
+		{
+			OpportunityID: "morpho-mixed",
+			Protocol:      "morpho",
+			TVLUSD:        10_000_000,
+			BackingAssets: []model.YieldBackingAsset{{Symbol: "USDC", SharePct: 50}, {Symbol: "WETH", SharePct: 50}},
+			FetchedAt:     "2026-01-01T00:00:00Z",
+		},
+	}
+	ApplyRisk(items)
+
+	safe := items[0]
+	if safe.RiskLevel != RiskLow || safe.RiskFactors == nil || safe.RiskFactors.ProtocolScore != 0 || safe.RiskFactors.TVLScore != 0 {
+		t.Fatalf("expected mature, deep stable market to be low risk, got %+v %+v", safe, safe.RiskFactors)
+	}
+	if safe.RiskFactors.AssetClass != AssetClassStable || safe.RiskFactors.UtilizationPct != 40 || safe.RiskFactors.UtilizationScore != 0 {
+		t.Fatalf("unexpected factors for stable market: %+v", safe.RiskFactors)
+	}
+	risky := items[1]
+	if risky.RiskLevel != RiskHigh || risky.RiskScore != 94.2 {
+		t.Fatalf("expected unaudited thin volatile market to score 94.2, got %v %s", risky.RiskScore, risky.RiskLevel)
+	}
+	mixed := items[2].RiskFactors
+	if mixed.AssetClass != AssetClassMajor || mixed.AssetScore != 22.5 || mixed.UtilizationScore != 50 {
+		t.Fatalf("expected share-weighted asset score and neutral utilization, got %+v", mixed)
+	}
+	if mixed.AuditAgeYears != 2 || mixed.ProtocolScore != 60 {
+		t.Fatalf("expected two audited years for morpho, got %+v", mixed)
+	}
+
+	Sort(items, "score")
+	if items[0].OpportunityID != "aave-usdc" || items[2].OpportunityID != "new-meme" {
+		t.Fatalf("expected lowest risk first, got %s, %s, %s", items[0].OpportunityID, items[1].OpportunityID, items[2].OpportunityID)
+	}
+}
+
+func TestApplyRiskUsesSecurity(t *testing.T) {
+	items := []model.YieldOpportunity{
+		{
+			OpportunityID: "audited-newcomer",
+			Protocol:      "newcomer",
+			Security:      &model.YieldSecurity{Audits: 2, ListingAgeDays: 730},
+			FetchedAt:     "2026-01-01T00:00:00Z",
+		},
+		{
+			OpportunityID: "exploited-aave",
+			Protocol:      "aave",
+			Security:      &model.YieldSecurity{Audits: 3, ListingAgeDays: 2000, Exploits: 1},
+			FetchedAt:     "2026-01-01T00:00:00Z",
+		},
+		{
+			OpportunityID: "unaudited",
+			Protocol:      "newcomer",
+			Security:      &model.YieldSecurity{ListingAgeDays: 730},
+			FetchedAt:     "2026-01-01T00:00:00Z",
+		},
+	}
+	ApplyRisk(items)
+
+	if factors := items[0].RiskFactors; factors.AuditAgeYears != 2 || factors.ProtocolScore != 60 {
+		t.Fatalf("expected audited listing age to count as audited years, got %+v", factors)
+	}
+	if factors := items[1].RiskFactors; factors.Exploits != 1 || factors.ProtocolScore != 40 {
+		t.Fatalf("expected an exploit to add 40 to the protocol score, got %+v", factors)
+	}
+	if factors := items[2].RiskFactors; factors.AuditAgeYears != 0 || factors.ProtocolScore != 100 {
+		t.Fatalf("expected an unaudited protocol to score as unaudited, got %+v", factors)
+	}
+}
+
+func TestParseMaxRisk(t *testing.T) {
+	for raw, want := range map[string]float64{"high": 100, "42.5": 42.5, " 0 ": 0} {
+		got, err := ParseMaxRisk(raw)
+		if err != nil || got != want {
+			t.Fatalf("ParseMaxRisk(%q) = %v, %v; want %v", raw, got, err, want)
+		}
+	}
+	if got, _ := ParseMaxRisk("low"); RiskLevel(got) != RiskLow {
+		t.Fatalf("expected low bound to stay within the low level, got %v", got)
+	}
+	for _, raw := range []string{"", "extreme", "-1", "101", "NaN"} {
+		if _, err := ParseMaxRisk(raw); err == nil {
+			t.Fatalf("expected %q to be rejected", raw)
+		}
+	}
+}
+
+func TestImpermanentLossPct(t *testing.T) {
+	cases := map[float64]float64{0: 0, 100: -5.719, -50: -5.719, 300: -20, -100: -100}
+	for move, want := range cases {
+		if got := ImpermanentLossPct(move); math.Abs(got-want) > 0.001 {
+			t.Fatalf("ImpermanentLossPct(%v) = %v, want %v", move, got, want)
+		}
+	}
+}
+
+func TestPairVolatilityPct(t *testing.T) {
+	day := func(i int, v float64) model.MarketHistoryPoint {
+		return model.MarketHistoryPoint{Timestamp: time.Date(2026, 1, i, 12, 0, 0, 0, time.UTC).Format(time.RFC3339), Value: v}
+	}
+	flat := []model.MarketHistoryPoint{day(1, 1), day(2, 1), day(3, 1), day(4, 1)}
+	if vol, ok := PairVolatilityPct(flat, flat, 24*time.Hour); !ok || vol != 0 {
+		t.Fatalf("expected zero volatility for identical series, got %v %v", vol, ok)
+	}
+	moving := []model.MarketHistoryPoint{day(4, 2), day(1, 2), day(2, 2*math.E), day(3, 2)}
+	vol, ok := PairVolatilityPct(moving, flat, 24*time.Hour)
+	// Log returns +1, -1, 0 have sample stddev 1, annualized by sqrt(365).
+	if !ok || math.Abs(vol-math.Sqrt(365)*100) > 1e-6 {
+		t.Fatalf("unexpected pair volatility %v %v", vol, ok)
+	}
+	if _, ok := PairVolatilityPct(moving[:2], flat, 24*time.Hour); ok {
+		t.Fatal("expected too few matched points to be rejected")
+	}
+}
+
+func TestLPAnalytics(t *testing.T) {
+	got := LPAnalytics(0, 30, []float64{100}, 10, 2)
+	if got.ExpectedILPct != 0 || got.NetAPYAfterIL != 12 || len(got.ILScenarios) != 1 || got.ILScenarios[0].ILPct != -5.72 {
+		t.Fatalf("unexpected analytics without volatility: %+v", got)
+	}
+	got = LPAnalytics(math.Log(2)*100, 30, nil, 10, 2)
+	if got.ExpectedILPct != -5.72 || got.NetAPYAfterIL != 6.28 {
+		t.Fatalf("expected a one sigma move to double the price, got %+v", got)
+	}
+	if !IsLP(TypeLPStable) || IsLP("lend") {
+		t.Fatal("unexpected IsLP classification")
+	}
+}
@@ -0,0 +1,60 @@
+package quotes
+
+import (
+	"math/big"
+
+	"github.com/ggonzalez94/defi-cli/internal/model"
+)
+
+// FromSwapQuote converts a swap quote into an archive entry.
+func FromSwapQuote(q model.SwapQuote, observedAt string) model.ArchivedQuote {
+	return model.ArchivedQuote{
+		Kind:         KindSwap,
+		Provider:     q.Provider,
+		ChainID:      q.ChainID,
+		ToChainID:    q.ChainID,
+		FromAssetID:  q.FromAssetID,
+		ToAssetID:    q.ToAssetID,
+		TradeType:    q.TradeType,
+		InputAmount:  q.InputAmount,
+		EstimatedOut: q.EstimatedOut,
+		Price:        effectivePrice(q.InputAmount, q.EstimatedOut),
+		FeeUSD:       q.EstimatedGasUSD,
+		Route:        q.Route,
+		ObservedAt:   observedAt,
+	}
+}
+
+// FromBridgeQuote converts a bridge quote into an archive entry. ChainID is the
+// source chain; ToChainID is the destination chain.
+func FromBridgeQuote(q model.BridgeQuote, observedAt string) model.ArchivedQuote {
+	return model.ArchivedQuote{
+		Kind:         KindBridge,
+		Provider:     q.Provider,
+		ChainID:      q.FromChainID,
+		ToChainID:    q.ToChainID,
+		FromAssetID:  q.FromAssetID,
+		ToAssetID:    q.ToAssetID,
+		InputAmount:  q.InputAmount,
+		EstimatedOut: q.EstimatedOut,
+		Price:        effectivePrice(q.InputAmount, q.EstimatedOut),
+		FeeUSD:       q.EstimatedFeeUSD,
+		Route:        q.Route,
+		ObservedAt:   observedAt,
+	}
+}
+
+// effectivePrice returns output units received per input unit using decimal
+// amounts. It returns 0 when either side is missing or zero.
+func effectivePrice(in, out model.AmountInfo) float64 {
+	inAmount, ok := new(big.Float).SetString(in.AmountDecimal)
+	if !ok || inAmount.Sign() <= 0 {
+		return 0
+	}
+	outAmount, ok := new(big.Float).SetString(out.AmountDecimal)
+	if !ok || outAmount.Sign() < 0 {
+		return 0
+	}
+	price, _ := new(big.Float).Quo(outAmount, inAmount).Float64()
+	return price
+}
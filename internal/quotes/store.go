@@ -0,0 +1,178 @@
+package quotes
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ggonzalez94/defi-cli/internal/model"
+	"github.com/gofrs/flock"
+	_ "modernc.org/sqlite"
+)
+
+const (
+	KindSwap   = "swap"
+	KindBridge = "bridge"
+)
+
+// Store persists quotes returned by swap/bridge quote commands so execution
+// quality can later be compared against what the caller actually saw.
+type Store struct {
+	db   *sql.DB
+	lock *flock.Flock
+}
+
+// Query filters archived quotes. Empty fields match everything.
+type Query struct {
+	Kind        string
+	Provider    string
+	ChainID     string
+	FromAssetID string
+	ToAssetID   string
+	Start       time.Time
+	End         time.Time
+	Limit       int
+}
+
+func OpenStore(path, lockPath string) (*Store, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("create quote archive directory: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(lockPath), 0o755); err != nil {
+		return nil, fmt.Errorf("create quote archive lock directory: %w", err)
+	}
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("open quote archive sqlite: %w", err)
+	}
+
+	queries := []string{
+		"PRAGMA journal_mode=WAL;",
+		"PRAGMA synchronous=NORMAL;",
+		"PRAGMA busy_timeout=5000;",
+		`CREATE TABLE IF NOT EXISTS quotes (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			kind TEXT NOT NULL,
+			provider TEXT NOT NULL,
+			chain_id TEXT NOT NULL,
+			from_asset_id TEXT NOT NULL,
+			to_asset_id TEXT NOT NULL,
+			observed_at INTEGER NOT NULL,
+			payload BLOB NOT NULL
+		);`,
+		"CREATE INDEX IF NOT EXISTS idx_quotes_pair_observed ON quotes(chain_id, from_asset_id, to_asset_id, observed_at DESC);",
+	}
+	for _, q := range queries {
+		if _, err := db.Exec(q); err != nil {
+			_ = db.Close()
+			return nil, fmt.Errorf("init quote archive schema: %w", err)
+		}
+	}
+	return &Store{db: db, lock: flock.New(lockPath)}, nil
+}
+
+func (s *Store) Close() error {
+	if s == nil || s.db == nil {
+		return nil
+	}
+	return s.db.Close()
+}
+
+func (s *Store) Record(entry model.ArchivedQuote) error {
+	if strings.TrimSpace(entry.Kind) == "" || strings.TrimSpace(entry.Provider) == "" {
+		return fmt.Errorf("record quote: missing kind or provider")
+	}
+	observed, err := time.Parse(time.RFC3339, entry.ObservedAt)
+	if err != nil {
+		return fmt.Errorf("record quote: parse observed_at: %w", err)
+	}
+	locked, err := s.lock.TryLockContext(context.Background(), 5*time.Second)
+	if err != nil {
+		return fmt.Errorf("lock quote archive: %w", err)
+	}
+	if !locked {
+		return fmt.Errorf("lock quote archive: timeout acquiring lock")
+	}
+	defer func() { _ = s.lock.Unlock() }()
+
+	payload, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("marshal archived quote: %w", err)
+	}
+	_, err = s.db.Exec(`
+		INSERT INTO quotes (kind, provider, chain_id, from_asset_id, to_asset_id, observed_at, payload)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, entry.Kind, entry.Provider, entry.ChainID, strings.ToLower(entry.FromAssetID), strings.ToLower(entry.ToAssetID), observed.UTC().Unix(), payload)
+	if err != nil {
+		return fmt.Errorf("record quote: %w", err)
+	}
+	return nil
+}
+
+// History returns archived quotes matching q ordered by observation time
+// (oldest first) so callers can treat the result as a time series.
+func (s *Store) History(q Query) ([]model.ArchivedQuote, error) {
+	clauses := []string{"1=1"}
+	args := []any{}
+	add := func(clause string, value any) {
+		clauses = append(clauses, clause)
+		args = append(args, value)
+	}
+	if v := strings.TrimSpace(q.Kind); v != "" {
+		add("kind = ?", v)
+	}
+	if v := strings.TrimSpace(q.Provider); v != "" {
+		add("provider = ?", v)
+	}
+	if v := strings.TrimSpace(q.ChainID); v != "" {
+		add("chain_id = ?", v)
+	}
+	if v := strings.TrimSpace(q.FromAssetID); v != "" {
+		add("from_asset_id = ?", strings.ToLower(v))
+	}
+	if v := strings.TrimSpace(q.ToAssetID); v != "" {
+		add("to_asset_id = ?", strings.ToLower(v))
+	}
+	if !q.Start.IsZero() {
+		add("observed_at >= ?", q.Start.UTC().Unix())
+	}
+	if !q.End.IsZero() {
+		add("observed_at <= ?", q.End.UTC().Unix())
+	}
+	query := "SELECT payload FROM quotes WHERE " + strings.Join(clauses, " AND ") + " ORDER BY observed_at DESC, id DESC"
+	if q.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, q.Limit)
+	}
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query quote archive: %w", err)
+	}
+	defer rows.Close()
+
+	out := make([]model.ArchivedQuote, 0)
+	for rows.Next() {
+		var payload []byte
+		if err := rows.Scan(&payload); err != nil {
+			return nil, fmt.Errorf("scan archived quote: %w", err)
+		}
+		var entry model.ArchivedQuote
+		if err := json.Unmarshal(payload, &entry); err != nil {
+			return nil, fmt.Errorf("decode archived quote: %w", err)
+		}
+		out = append(out, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate archived quotes: %w", err)
+	}
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return out, nil
+}
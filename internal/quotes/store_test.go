@@ -0,0 +1,83 @@
+package quotes
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ggonzalez94/defi-cli/internal/model"
+)
+
+func TestStoreRecordHistory(t *testing.T) {
+	dir := t.TempDir()
+	store, err := OpenStore(filepath.Join(dir, "quotes.db"), filepath.Join(dir, "quotes.lock"))
+	if err != nil {
+		t.Fatalf("OpenStore failed: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	usdc := "eip155:8453/erc20:0x833589fcd6edb6e08f4c7c32d4f71b54bda02913"
+	weth := "eip155:8453/erc20:0x4200000000000000000000000000000000000006"
+	for i, provider := range []string{"1inch", "uniswap", "1inch"} {
+		entry := FromSwapQuote(model.SwapQuote{
+			Provider:     provider,
+			ChainID:      "eip155:8453",
+			FromAssetID:  usdc,
+			ToAssetID:    weth,
+			TradeType:    "exact-input",
+			InputAmount:  model.AmountInfo{AmountBaseUnits: "1000000", AmountDecimal: "1", Decimals: 6},
+			EstimatedOut: model.AmountInfo{AmountBaseUnits: "500000000000000", AmountDecimal: "0.0005", Decimals: 18},
+		}, base.Add(time.Duration(i)*time.Hour).Format(time.RFC3339))
+		if err := store.Record(entry); err != nil {
+			t.Fatalf("Record failed: %v", err)
+		}
+	}
+
+	all, err := store.History(Query{ChainID: "eip155:8453", FromAssetID: usdc, ToAssetID: weth})
+	if err != nil {
+		t.Fatalf("History failed: %v", err)
+	}
+	if len(all) != 3 {
+		t.Fatalf("expected three archived quotes, got %d", len(all))
+	}
+	if all[0].ObservedAt != base.Format(time.RFC3339) {
+		t.Fatalf("expected oldest quote first, got %+v", all[0])
+	}
+	if all[0].Price != 0.0005 {
+		t.Fatalf("expected effective price 0.0005, got %v", all[0].Price)
+	}
+
+	filtered, err := store.History(Query{Provider: "1inch", Start: base.Add(30 * time.Minute)})
+	if err != nil {
+		t.Fatalf("History failed: %v", err)
+	}
+	if len(filtered) != 1 || filtered[0].Provider != "1inch" {
+		t.Fatalf("expected one 1inch quote after start, got %+v", filtered)
+	}
+
+	reversed, err := store.History(Query{FromAssetID: weth, ToAssetID: usdc})
+	if err != nil {
+		t.Fatalf("History failed: %v", err)
+	}
+	if len(reversed) != 0 {
+		t.Fatalf("expected pair direction to matter, got %+v", reversed)
+	}
+}
+
+func TestFromBridgeQuoteUsesSourceChain(t *testing.T) {
+	entry := FromBridgeQuote(model.BridgeQuote{
+		Provider:        "across",
+		FromChainID:     "eip155:1",
+		ToChainID:       "eip155:8453",
+		InputAmount:     model.AmountInfo{AmountDecimal: "100"},
+		EstimatedOut:    model.AmountInfo{AmountDecimal: "99.5"},
+		EstimatedFeeUSD: 0.5,
+	}, "2026-03-01T12:00:00Z")
+	if entry.Kind != KindBridge || entry.ChainID != "eip155:1" || entry.ToChainID != "eip155:8453" {
+		t.Fatalf("unexpected bridge archive entry: %+v", entry)
+	}
+	if entry.Price != 0.995 || entry.FeeUSD != 0.5 {
+		t.Fatalf("unexpected bridge price/fee: %+v", entry)
+	}
+}
@@ -0,0 +1,56 @@
+// Package randx is the process-wide source of request, action, and trace IDs
+// and retry jitter. It reads crypto/rand unless --seed installs a
+// deterministic source, which makes IDs reproducible across runs.
+package randx
+
+import (
+	crand "crypto/rand"
+	"math/rand"
+	"sync"
+)
+
+var (
+	mu     sync.Mutex
+	seeded *rand.Rand
+)
+
+// Seed switches to a deterministic source seeded with seed.
+func Seed(seed int64) {
+	mu.Lock()
+	defer mu.Unlock()
+	seeded = rand.New(rand.NewSource(seed))
+}
+
+// Reset restores crypto/rand.
+func Reset() {
+	mu.Lock()
+	defer mu.Unlock()
+	seeded = nil
+}
+
+// Seeded reports whether a deterministic source is installed.
+func Seeded() bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return seeded != nil
+}
+
+// Read fills b with random bytes.
+func Read(b []byte) (int, error) {
+	mu.Lock()
+	defer mu.Unlock()
+	if seeded != nil {
+		return seeded.Read(b)
+	}
+	return crand.Read(b)
+}
+
+// Intn returns a random int in [0, n).
+func Intn(n int) int {
+	mu.Lock()
+	defer mu.Unlock()
+	if seeded != nil {
+		return seeded.Intn(n)
+	}
+	return rand.Intn(n)
+}
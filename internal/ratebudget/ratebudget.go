@@ -0,0 +1,300 @@
+// Package ratebudget tracks the request budget providers advertise in their
+// rate-limit headers. Requests to a provider close to its limit are paced
+// until the window resets, and requests to an exhausted provider fail fast
+// instead of drawing a 429, so multi-provider commands route around it. The
+// table is persisted so separate invocations share it.
+package ratebudget
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+)
+
+// ErrExhausted marks errors returned for requests skipped because the
+// provider's budget is spent until a reset too far away to wait for.
+var ErrExhausted = errors.New("rate limit budget exhausted")
+
+// Budget states reported by Budget.State.
+const (
+	StateOK        = "ok"
+	StateLow       = "low"
+	StateExhausted = "exhausted"
+)
+
+// MaxWait is the longest a request is delayed for its provider's budget. A
+// reset further away than this fails the request instead.
+const MaxWait = 5 * time.Second
+
+// Budget is the last rate-limit state a provider reported. Limit is 0 when
+// the provider does not advertise it; ResetAt is zero when the reset time is
+// unknown, in which case the budget is informational only.
+type Budget struct {
+	Provider   string    `json:"provider"`
+	Limit      int       `json:"limit,omitempty"`
+	Remaining  int       `json:"remaining"`
+	ResetAt    time.Time `json:"reset_at"`
+	ObservedAt time.Time `json:"observed_at"`
+}
+
+// State reports whether b is ok, low (requests are paced), or exhausted at t.
+// A budget whose window has reset is ok again.
+func (b Budget) State(t time.Time) string {
+	if b.ResetAt.IsZero() || !b.ResetAt.After(t) {
+		return StateOK
+	}
+	switch {
+	case b.Remaining <= 0:
+		return StateExhausted
+	case b.Remaining <= b.lowWater():
+		return StateLow
+	default:
+		return StateOK
+	}
+}
+
+// lowWater is the remaining count below which requests are spread over the
+// rest of the window: a tenth of the limit, and at least 2.
+func (b Budget) lowWater() int {
+	return max(2, b.Limit/10)
+}
+
+var (
+	mu      sync.Mutex
+	budgets = map[string]Budget{}
+	dirty   bool
+	now     = time.Now
+)
+
+// Reserve accounts for one request to provider and returns how long to wait
+// before sending it: until the reset when the budget is spent, or an even
+// share of the remaining window when it is low. It returns a rate-limited
+// error wrapping ErrExhausted when the budget is spent and resets more than
+// MaxWait from now.
+func Reserve(provider string) (time.Duration, error) {
+	mu.Lock()
+	defer mu.Unlock()
+	b, ok := budgets[provider]
+	t := now()
+	if !ok || b.ResetAt.IsZero() || !b.ResetAt.After(t) {
+		return 0, nil
+	}
+	untilReset := b.ResetAt.Sub(t)
+	if b.Remaining <= 0 {
+		if untilReset > MaxWait {
+			msg := fmt.Sprintf("provider %s skipped: rate limit budget exhausted until %s", provider, b.ResetAt.UTC().Format(time.RFC3339))
+			return 0, clierr.Wrap(clierr.CodeRateLimited, msg, ErrExhausted)
+		}
+		return untilReset, nil
+	}
+	var delay time.Duration
+	if b.Remaining <= b.lowWater() {
+		delay = min(untilReset/time.Duration(b.Remaining+1), MaxWait)
+	}
+	// Count the request now so a burst sees the budget shrink before the
+	// responses report it.
+	b.Remaining--
+	budgets[provider] = b
+	dirty = true
+	return delay, nil
+}
+
+// Observe records the rate-limit headers of a provider response. It reads
+// X-RateLimit-* and RateLimit-* limit, remaining, and reset headers, and
+// treats a 429 as an empty budget until Retry-After or the reset. Responses
+// without rate-limit headers leave the budget unchanged.
+func Observe(provider string, status int, header http.Header) {
+	t := now()
+	remaining, hasRemaining := headerInt(header, "X-RateLimit-Remaining", "RateLimit-Remaining")
+	limit, _ := headerInt(header, "X-RateLimit-Limit", "RateLimit-Limit")
+	resetAt, hasReset := parseReset(header, t)
+	if status == http.StatusTooManyRequests {
+		remaining, hasRemaining = 0, true
+		if retryAt, ok := parseRetryAfter(header.Get("Retry-After"), t); ok && retryAt.After(resetAt) {
+			resetAt, hasReset = retryAt, true
+		}
+	}
+	if !hasRemaining {
+		return
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	b := budgets[provider]
+	b.Provider = provider
+	b.Remaining = remaining
+	if limit > 0 {
+		b.Limit = limit
+	}
+	b.ResetAt = time.Time{}
+	if hasReset {
+		b.ResetAt = resetAt.UTC()
+	}
+	b.ObservedAt = t.UTC()
+	budgets[provider] = b
+	dirty = true
+}
+
+// headerInt reads the first integer of the first present header among
+// names. Structured values such as "100;w=60" keep their leading number.
+func headerInt(header http.Header, names ...string) (int, bool) {
+	for _, name := range names {
+		raw := strings.TrimSpace(header.Get(name))
+		if raw == "" {
+			continue
+		}
+		if i := strings.IndexAny(raw, ",;"); i >= 0 {
+			raw = strings.TrimSpace(raw[:i])
+		}
+		value, err := strconv.Atoi(raw)
+		if err != nil || value < 0 {
+			continue
+		}
+		return value, true
+	}
+	return 0, false
+}
+
+// parseReset reads X-RateLimit-Reset or RateLimit-Reset, which providers
+// send as seconds until the reset, a unix timestamp in seconds, or one in
+// milliseconds.
+func parseReset(header http.Header, t time.Time) (time.Time, bool) {
+	for _, name := range []string{"X-RateLimit-Reset", "RateLimit-Reset"} {
+		raw := strings.TrimSpace(header.Get(name))
+		if raw == "" {
+			continue
+		}
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil || value < 0 || math.IsInf(value, 0) {
+			continue
+		}
+		switch {
+		case value >= 1e12:
+			return time.UnixMilli(int64(value)), true
+		case value >= 1e9:
+			return time.Unix(int64(value), 0), true
+		default:
+			return t.Add(time.Duration(value * float64(time.Second))), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// parseRetryAfter reads a Retry-After value in seconds or as an HTTP date.
+func parseRetryAfter(raw string, t time.Time) (time.Time, bool) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return time.Time{}, false
+	}
+	if seconds, err := strconv.Atoi(raw); err == nil && seconds >= 0 {
+		return t.Add(time.Duration(seconds) * time.Second), true
+	}
+	if at, err := http.ParseTime(raw); err == nil {
+		return at, true
+	}
+	return time.Time{}, false
+}
+
+// Get returns the budget of provider.
+func Get(provider string) (Budget, bool) {
+	mu.Lock()
+	defer mu.Unlock()
+	b, ok := budgets[provider]
+	return b, ok
+}
+
+// Budgets returns every recorded budget sorted by provider.
+func Budgets() []Budget {
+	mu.Lock()
+	out := make([]Budget, 0, len(budgets))
+	for _, b := range budgets {
+		out = append(out, b)
+	}
+	mu.Unlock()
+	sort.Slice(out, func(i, j int) bool { return out[i].Provider < out[j].Provider })
+	return out
+}
+
+// Reset forgets the budget of provider, or all budgets when provider is
+// empty, and returns how many were removed.
+func Reset(provider string) int {
+	mu.Lock()
+	defer mu.Unlock()
+	removed := 0
+	for k, b := range budgets {
+		if provider == "" || b.Provider == provider {
+			delete(budgets, k)
+			removed++
+		}
+	}
+	if removed > 0 {
+		dirty = true
+	}
+	return removed
+}
+
+// Load replaces the in-memory table with the one saved at path by an earlier
+// invocation. A missing file leaves an empty table.
+func Load(path string) error {
+	buf, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	var saved []Budget
+	if len(buf) > 0 {
+		if err := json.Unmarshal(buf, &saved); err != nil {
+			return fmt.Errorf("decode provider budgets: %w", err)
+		}
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	budgets = make(map[string]Budget, len(saved))
+	for _, b := range saved {
+		budgets[b.Provider] = b
+	}
+	dirty = false
+	return nil
+}
+
+// Save writes the table to path when it changed since the last load or save.
+func Save(path string) error {
+	mu.Lock()
+	if !dirty {
+		mu.Unlock()
+		return nil
+	}
+	dirty = false
+	mu.Unlock()
+
+	buf, err := json.MarshalIndent(Budgets(), "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".provider-budgets-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(buf); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,122 @@
+package ratebudget
+
+import (
+	"errors"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+)
+
+func resetBudgets(t *testing.T) {
+	t.Helper()
+	mu.Lock()
+	budgets = map[string]Budget{}
+	dirty = false
+	mu.Unlock()
+	t.Cleanup(func() {
+		mu.Lock()
+		budgets = map[string]Budget{}
+		dirty = false
+		now = time.Now
+		mu.Unlock()
+	})
+}
+
+func TestReservePacesLowBudgetsAndFailsFastWhenExhausted(t *testing.T) {
+	resetBudgets(t)
+	clock := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	now = func() time.Time { return clock }
+
+	header := http.Header{}
+	header.Set("X-RateLimit-Limit", "100")
+	header.Set("X-RateLimit-Remaining", "50")
+	header.Set("X-RateLimit-Reset", "4")
+	Observe("odos", http.StatusOK, header)
+	if delay, err := Reserve("odos"); err != nil || delay != 0 {
+		t.Fatalf("expected a healthy budget not to delay, got %s %v", delay, err)
+	}
+	if b, _ := Get("odos"); b.Remaining != 49 || b.State(clock) != StateOK {
+		t.Fatalf("expected the reservation to be counted, got %+v", b)
+	}
+
+	header.Set("X-RateLimit-Remaining", "3")
+	Observe("odos", http.StatusOK, header)
+	if delay, err := Reserve("odos"); err != nil || delay != time.Second {
+		t.Fatalf("expected 3 remaining over 4s to be paced 1s apart, got %s %v", delay, err)
+	}
+
+	// A 429 empties the budget until Retry-After, which is further away
+	// than the reset header.
+	header.Set("Retry-After", "60")
+	Observe("odos", http.StatusTooManyRequests, header)
+	_, err := Reserve("odos")
+	if !errors.Is(err, ErrExhausted) {
+		t.Fatalf("expected an exhausted budget, got %v", err)
+	}
+	if cErr, ok := clierr.As(err); !ok || cErr.Code != clierr.CodeRateLimited {
+		t.Fatalf("expected a rate-limited error, got %v", err)
+	}
+	if b, _ := Get("odos"); b.State(clock) != StateExhausted || !b.ResetAt.Equal(clock.Add(time.Minute)) {
+		t.Fatalf("unexpected exhausted budget %+v", b)
+	}
+
+	clock = clock.Add(58 * time.Second)
+	if delay, err := Reserve("odos"); err != nil || delay != 2*time.Second {
+		t.Fatalf("expected a reset within MaxWait to be waited for, got %s %v", delay, err)
+	}
+	clock = clock.Add(3 * time.Second)
+	if delay, err := Reserve("odos"); err != nil || delay != 0 {
+		t.Fatalf("expected the budget to refill after the reset, got %s %v", delay, err)
+	}
+}
+
+func TestObserveParsesResetFormats(t *testing.T) {
+	resetBudgets(t)
+	clock := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	now = func() time.Time { return clock }
+
+	for _, tc := range []struct {
+		reset string
+		want  time.Time
+	}{
+		{"30", clock.Add(30 * time.Second)},
+		{"1767225660", time.Unix(1767225660, 0)},
+		{"1767225660000", time.UnixMilli(1767225660000)},
+	} {
+		header := http.Header{}
+		header.Set("RateLimit-Remaining", "10;w=60")
+		header.Set("RateLimit-Reset", tc.reset)
+		Observe("lifi", http.StatusOK, header)
+		if b, _ := Get("lifi"); b.Remaining != 10 || !b.ResetAt.Equal(tc.want) {
+			t.Fatalf("reset %s: unexpected budget %+v", tc.reset, b)
+		}
+	}
+
+	Observe("lifi", http.StatusOK, http.Header{})
+	if b, _ := Get("lifi"); b.Remaining != 10 {
+		t.Fatalf("expected responses without headers to keep the budget, got %+v", b)
+	}
+}
+
+func TestSaveAndLoadRoundTrip(t *testing.T) {
+	resetBudgets(t)
+	header := http.Header{}
+	header.Set("X-RateLimit-Remaining", "0")
+	header.Set("X-RateLimit-Reset", "120")
+	Observe("uniswap", http.StatusOK, header)
+
+	path := filepath.Join(t.TempDir(), "provider-budgets.json")
+	if err := Save(path); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	Reset("")
+	if err := Load(path); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if _, err := Reserve("uniswap"); !errors.Is(err, ErrExhausted) {
+		t.Fatalf("expected the loaded budget to stay exhausted, got %v", err)
+	}
+}
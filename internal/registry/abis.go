@@ -8,6 +8,25 @@ const (
 		{"name":"transfer","type":"function","stateMutability":"nonpayable","inputs":[{"name":"to","type":"address"},{"name":"amount","type":"uint256"}],"outputs":[{"name":"","type":"bool"}]}
 	]`
 
+	ERC20MetadataABI = `[
+		{"name":"name","type":"function","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"string"}]},
+		{"name":"symbol","type":"function","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"string"}]},
+		{"name":"decimals","type":"function","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint8"}]}
+	]`
+
+	ERC20SupplyABI = `[
+		{"name":"totalSupply","type":"function","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint256"}]}
+	]`
+
+	OwnableABI = `[
+		{"name":"owner","type":"function","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"address"}]}
+	]`
+
+	// ProxyBeaconABI is the EIP-1967 beacon read used to follow beacon proxies.
+	ProxyBeaconABI = `[
+		{"name":"implementation","type":"function","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"address"}]}
+	]`
+
 	ERC4626VaultABI = `[
 		{"name":"asset","type":"function","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"address"}]},
 		{"name":"deposit","type":"function","stateMutability":"nonpayable","inputs":[{"name":"assets","type":"uint256"},{"name":"receiver","type":"address"}],"outputs":[{"name":"shares","type":"uint256"}]},
@@ -36,18 +55,66 @@ const (
 
 	AavePoolAddressProviderABI = `[
 		{"name":"getPool","type":"function","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"address"}]},
-		{"name":"getAddress","type":"function","stateMutability":"view","inputs":[{"name":"id","type":"bytes32"}],"outputs":[{"name":"","type":"address"}]}
+		{"name":"getAddress","type":"function","stateMutability":"view","inputs":[{"name":"id","type":"bytes32"}],"outputs":[{"name":"","type":"address"}]},
+		{"name":"getPriceOracle","type":"function","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"address"}]}
+	]`
+
+	// AaveOracleABI reads reserve prices in USD with 8 decimals.
+	AaveOracleABI = `[
+		{"name":"getAssetPrice","type":"function","stateMutability":"view","inputs":[{"name":"asset","type":"address"}],"outputs":[{"name":"","type":"uint256"}]}
 	]`
 
 	AavePoolABI = `[
 		{"name":"supply","type":"function","stateMutability":"nonpayable","inputs":[{"name":"asset","type":"address"},{"name":"amount","type":"uint256"},{"name":"onBehalfOf","type":"address"},{"name":"referralCode","type":"uint16"}],"outputs":[]},
 		{"name":"withdraw","type":"function","stateMutability":"nonpayable","inputs":[{"name":"asset","type":"address"},{"name":"amount","type":"uint256"},{"name":"to","type":"address"}],"outputs":[{"name":"","type":"uint256"}]},
 		{"name":"borrow","type":"function","stateMutability":"nonpayable","inputs":[{"name":"asset","type":"address"},{"name":"amount","type":"uint256"},{"name":"interestRateMode","type":"uint256"},{"name":"referralCode","type":"uint16"},{"name":"onBehalfOf","type":"address"}],"outputs":[]},
-		{"name":"repay","type":"function","stateMutability":"nonpayable","inputs":[{"name":"asset","type":"address"},{"name":"amount","type":"uint256"},{"name":"interestRateMode","type":"uint256"},{"name":"onBehalfOf","type":"address"}],"outputs":[{"name":"","type":"uint256"}]}
+		{"name":"repay","type":"function","stateMutability":"nonpayable","inputs":[{"name":"asset","type":"address"},{"name":"amount","type":"uint256"},{"name":"interestRateMode","type":"uint256"},{"name":"onBehalfOf","type":"address"}],"outputs":[{"name":"","type":"uint256"}]},
+		{"name":"getReserveData","type":"function","stateMutability":"view","inputs":[{"name":"asset","type":"address"}],"outputs":[{"name":"","type":"tuple","components":[{"name":"configuration","type":"uint256"},{"name":"liquidityIndex","type":"uint128"},{"name":"currentLiquidityRate","type":"uint128"},{"name":"variableBorrowIndex","type":"uint128"},{"name":"currentVariableBorrowRate","type":"uint128"},{"name":"currentStableBorrowRate","type":"uint128"},{"name":"lastUpdateTimestamp","type":"uint40"},{"name":"id","type":"uint16"},{"name":"aTokenAddress","type":"address"},{"name":"stableDebtTokenAddress","type":"address"},{"name":"variableDebtTokenAddress","type":"address"},{"name":"interestRateStrategyAddress","type":"address"},{"name":"accruedToTreasury","type":"uint128"},{"name":"unbacked","type":"uint128"},{"name":"isolationModeTotalDebt","type":"uint128"}]}]}
+	]`
+
+	// CometABI covers the Compound v3 (Comet) views used for on-chain rates.
+	// Rates are per second, scaled by 1e18.
+	CometABI = `[
+		{"name":"baseToken","type":"function","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"address"}]},
+		{"name":"getUtilization","type":"function","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint256"}]},
+		{"name":"getSupplyRate","type":"function","stateMutability":"view","inputs":[{"name":"utilization","type":"uint256"}],"outputs":[{"name":"","type":"uint64"}]},
+		{"name":"getBorrowRate","type":"function","stateMutability":"view","inputs":[{"name":"utilization","type":"uint256"}],"outputs":[{"name":"","type":"uint64"}]}
+	]`
+
+	// VE33VoterABI and VE33RewardABI cover the Aerodrome/Velodrome v2 views
+	// used to read gauge bribes. Bribes are credited per weekly epoch.
+	VE33VoterABI = `[
+		{"name":"gauges","type":"function","stateMutability":"view","inputs":[{"name":"pool","type":"address"}],"outputs":[{"name":"","type":"address"}]},
+		{"name":"gaugeToBribe","type":"function","stateMutability":"view","inputs":[{"name":"gauge","type":"address"}],"outputs":[{"name":"","type":"address"}]}
+	]`
+
+	VE33RewardABI = `[
+		{"name":"rewardsListLength","type":"function","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint256"}]},
+		{"name":"rewards","type":"function","stateMutability":"view","inputs":[{"name":"index","type":"uint256"}],"outputs":[{"name":"","type":"address"}]},
+		{"name":"tokenRewardsPerEpoch","type":"function","stateMutability":"view","inputs":[{"name":"token","type":"address"},{"name":"epochStart","type":"uint256"}],"outputs":[{"name":"","type":"uint256"}]}
 	]`
 
 	AaveRewardsABI = `[
-		{"name":"claimRewards","type":"function","stateMutability":"nonpayable","inputs":[{"name":"assets","type":"address[]"},{"name":"amount","type":"uint256"},{"name":"to","type":"address"},{"name":"reward","type":"address"}],"outputs":[{"name":"","type":"uint256"}]}
+		{"name":"claimRewards","type":"function","stateMutability":"nonpayable","inputs":[{"name":"assets","type":"address[]"},{"name":"amount","type":"uint256"},{"name":"to","type":"address"},{"name":"reward","type":"address"}],"outputs":[{"name":"","type":"uint256"}]},
+		{"name":"getAllUserRewards","type":"function","stateMutability":"view","inputs":[{"name":"assets","type":"address[]"},{"name":"user","type":"address"}],"outputs":[{"name":"rewardsList","type":"address[]"},{"name":"unclaimedAmounts","type":"uint256[]"}]}
+	]`
+
+	MerklDistributorABI = `[
+		{"name":"claim","type":"function","stateMutability":"nonpayable","inputs":[{"name":"users","type":"address[]"},{"name":"tokens","type":"address[]"},{"name":"amounts","type":"uint256[]"},{"name":"proofs","type":"bytes32[][]"}],"outputs":[]}
+	]`
+
+	// SkySavingsABI covers the sDAI/sUSDS views used for the savings rate.
+	// ssr exists only on sUSDS; sDAI accrues the dsr of the Pot at pot().
+	// Both rates are per-second multipliers scaled by 1e27.
+	SkySavingsABI = `[
+		{"name":"asset","type":"function","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"address"}]},
+		{"name":"totalAssets","type":"function","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint256"}]},
+		{"name":"ssr","type":"function","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint256"}]},
+		{"name":"pot","type":"function","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"address"}]}
+	]`
+
+	MakerPotABI = `[
+		{"name":"dsr","type":"function","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint256"}]}
 	]`
 
 	MoonwellComptrollerABI = `[
@@ -86,7 +153,8 @@ const (
 	]`
 
 	Multicall3ABI = `[
-		{"name":"aggregate3","type":"function","stateMutability":"payable","inputs":[{"name":"calls","type":"tuple[]","components":[{"name":"target","type":"address"},{"name":"allowFailure","type":"bool"},{"name":"callData","type":"bytes"}]}],"outputs":[{"name":"returnData","type":"tuple[]","components":[{"name":"success","type":"bool"},{"name":"returnData","type":"bytes"}]}]}
+		{"name":"aggregate3","type":"function","stateMutability":"payable","inputs":[{"name":"calls","type":"tuple[]","components":[{"name":"target","type":"address"},{"name":"allowFailure","type":"bool"},{"name":"callData","type":"bytes"}]}],"outputs":[{"name":"returnData","type":"tuple[]","components":[{"name":"success","type":"bool"},{"name":"returnData","type":"bytes"}]}]},
+		{"name":"getEthBalance","type":"function","stateMutability":"view","inputs":[{"name":"addr","type":"address"}],"outputs":[{"name":"balance","type":"uint256"}]}
 	]`
 
 	MorphoBlueABI = `[
@@ -95,4 +163,78 @@ const (
 		{"name":"borrow","type":"function","stateMutability":"nonpayable","inputs":[{"name":"marketParams","type":"tuple","components":[{"name":"loanToken","type":"address"},{"name":"collateralToken","type":"address"},{"name":"oracle","type":"address"},{"name":"irm","type":"address"},{"name":"lltv","type":"uint256"}]},{"name":"assets","type":"uint256"},{"name":"shares","type":"uint256"},{"name":"onBehalf","type":"address"},{"name":"receiver","type":"address"}],"outputs":[{"name":"assetsBorrowed","type":"uint256"},{"name":"sharesBorrowed","type":"uint256"}]},
 		{"name":"repay","type":"function","stateMutability":"nonpayable","inputs":[{"name":"marketParams","type":"tuple","components":[{"name":"loanToken","type":"address"},{"name":"collateralToken","type":"address"},{"name":"oracle","type":"address"},{"name":"irm","type":"address"},{"name":"lltv","type":"uint256"}]},{"name":"assets","type":"uint256"},{"name":"shares","type":"uint256"},{"name":"onBehalf","type":"address"},{"name":"data","type":"bytes"}],"outputs":[{"name":"assetsRepaid","type":"uint256"},{"name":"sharesRepaid","type":"uint256"}]}
 	]`
+
+	WormholeTokenBridgeABI = `[
+		{"name":"transferTokens","type":"function","stateMutability":"payable","inputs":[{"name":"token","type":"address"},{"name":"amount","type":"uint256"},{"name":"recipientChain","type":"uint16"},{"name":"recipient","type":"bytes32"},{"name":"arbiterFee","type":"uint256"},{"name":"nonce","type":"uint32"}],"outputs":[{"name":"sequence","type":"uint64"}]},
+		{"name":"isWrappedAsset","type":"function","stateMutability":"view","inputs":[{"name":"token","type":"address"}],"outputs":[{"name":"","type":"bool"}]},
+		{"name":"wrappedAsset","type":"function","stateMutability":"view","inputs":[{"name":"tokenChainId","type":"uint16"},{"name":"tokenAddress","type":"bytes32"}],"outputs":[{"name":"","type":"address"}]}
+	]`
+
+	WormholeCoreABI = `[
+		{"name":"messageFee","type":"function","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint256"}]}
+	]`
+
+	AcrossSpokePoolABI = `[
+		{"name":"depositV3","type":"function","stateMutability":"payable","inputs":[{"name":"depositor","type":"address"},{"name":"recipient","type":"address"},{"name":"inputToken","type":"address"},{"name":"outputToken","type":"address"},{"name":"inputAmount","type":"uint256"},{"name":"outputAmount","type":"uint256"},{"name":"destinationChainId","type":"uint256"},{"name":"exclusiveRelayer","type":"address"},{"name":"quoteTimestamp","type":"uint32"},{"name":"fillDeadline","type":"uint32"},{"name":"exclusivityDeadline","type":"uint32"},{"name":"message","type":"bytes"}],"outputs":[]}
+	]`
+
+	CCTPTokenMessengerV2ABI = `[
+		{"name":"depositForBurn","type":"function","stateMutability":"nonpayable","inputs":[{"name":"amount","type":"uint256"},{"name":"destinationDomain","type":"uint32"},{"name":"mintRecipient","type":"bytes32"},{"name":"burnToken","type":"address"},{"name":"destinationCaller","type":"bytes32"},{"name":"maxFee","type":"uint256"},{"name":"minFinalityThreshold","type":"uint32"}],"outputs":[]}
+	]`
+
+	OneInchLimitOrderRouterABI = `[
+		{"name":"cancelOrder","type":"function","stateMutability":"nonpayable","inputs":[{"name":"makerTraits","type":"uint256"},{"name":"orderHash","type":"bytes32"}],"outputs":[]}
+	]`
+
+	CCTPMessageTransmitterV2ABI = `[
+		{"name":"receiveMessage","type":"function","stateMutability":"nonpayable","inputs":[{"name":"message","type":"bytes"},{"name":"attestation","type":"bytes"}],"outputs":[{"name":"success","type":"bool"}]}
+	]`
+
+	ChainlinkAggregatorV3ABI = `[
+		{"name":"decimals","type":"function","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint8"}]},
+		{"name":"latestRoundData","type":"function","stateMutability":"view","inputs":[],"outputs":[{"name":"roundId","type":"uint80"},{"name":"answer","type":"int256"},{"name":"startedAt","type":"uint256"},{"name":"updatedAt","type":"uint256"},{"name":"answeredInRound","type":"uint80"}]}
+	]`
+
+	ENSRegistryABI = `[
+		{"name":"resolver","type":"function","stateMutability":"view","inputs":[{"name":"node","type":"bytes32"}],"outputs":[{"name":"","type":"address"}]}
+	]`
+
+	ENSResolverABI = `[
+		{"name":"addr","type":"function","stateMutability":"view","inputs":[{"name":"node","type":"bytes32"}],"outputs":[{"name":"","type":"address"}]}
+	]`
+
+	// Event ABIs decoded from confirmed step receipts.
+	ERC20EventsABI = `[
+		{"name":"Transfer","type":"event","anonymous":false,"inputs":[{"name":"from","type":"address","indexed":true},{"name":"to","type":"address","indexed":true},{"name":"value","type":"uint256","indexed":false}]},
+		{"name":"Approval","type":"event","anonymous":false,"inputs":[{"name":"owner","type":"address","indexed":true},{"name":"spender","type":"address","indexed":true},{"name":"value","type":"uint256","indexed":false}]}
+	]`
+
+	WETHEventsABI = `[
+		{"name":"Deposit","type":"event","anonymous":false,"inputs":[{"name":"dst","type":"address","indexed":true},{"name":"wad","type":"uint256","indexed":false}]},
+		{"name":"Withdrawal","type":"event","anonymous":false,"inputs":[{"name":"src","type":"address","indexed":true},{"name":"wad","type":"uint256","indexed":false}]}
+	]`
+
+	UniswapV3PoolEventsABI = `[
+		{"name":"Swap","type":"event","anonymous":false,"inputs":[{"name":"sender","type":"address","indexed":true},{"name":"recipient","type":"address","indexed":true},{"name":"amount0","type":"int256","indexed":false},{"name":"amount1","type":"int256","indexed":false},{"name":"sqrtPriceX96","type":"uint160","indexed":false},{"name":"liquidity","type":"uint128","indexed":false},{"name":"tick","type":"int24","indexed":false}]}
+	]`
+
+	UniswapV2PairEventsABI = `[
+		{"name":"Swap","type":"event","anonymous":false,"inputs":[{"name":"sender","type":"address","indexed":true},{"name":"amount0In","type":"uint256","indexed":false},{"name":"amount1In","type":"uint256","indexed":false},{"name":"amount0Out","type":"uint256","indexed":false},{"name":"amount1Out","type":"uint256","indexed":false},{"name":"to","type":"address","indexed":true}]}
+	]`
+
+	AcrossSpokePoolEventsABI = `[
+		{"name":"V3FundsDeposited","type":"event","anonymous":false,"inputs":[{"name":"inputToken","type":"address","indexed":false},{"name":"outputToken","type":"address","indexed":false},{"name":"inputAmount","type":"uint256","indexed":false},{"name":"outputAmount","type":"uint256","indexed":false},{"name":"destinationChainId","type":"uint256","indexed":true},{"name":"depositId","type":"uint32","indexed":true},{"name":"quoteTimestamp","type":"uint32","indexed":false},{"name":"fillDeadline","type":"uint32","indexed":false},{"name":"exclusivityDeadline","type":"uint32","indexed":false},{"name":"depositor","type":"address","indexed":true},{"name":"recipient","type":"address","indexed":false},{"name":"exclusiveRelayer","type":"address","indexed":false},{"name":"message","type":"bytes","indexed":false}]},
+		{"name":"FundsDeposited","type":"event","anonymous":false,"inputs":[{"name":"inputToken","type":"bytes32","indexed":false},{"name":"outputToken","type":"bytes32","indexed":false},{"name":"inputAmount","type":"uint256","indexed":false},{"name":"outputAmount","type":"uint256","indexed":false},{"name":"destinationChainId","type":"uint256","indexed":true},{"name":"depositId","type":"uint256","indexed":true},{"name":"quoteTimestamp","type":"uint32","indexed":false},{"name":"fillDeadline","type":"uint32","indexed":false},{"name":"exclusivityDeadline","type":"uint32","indexed":false},{"name":"depositor","type":"bytes32","indexed":true},{"name":"recipient","type":"bytes32","indexed":false},{"name":"exclusiveRelayer","type":"bytes32","indexed":false},{"name":"message","type":"bytes","indexed":false}]}
+	]`
+
+	GovernorBravoABI = `[
+		{"name":"proposalCount","type":"function","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint256"}]},
+		{"name":"quorumVotes","type":"function","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint256"}]},
+		{"name":"state","type":"function","stateMutability":"view","inputs":[{"name":"proposalId","type":"uint256"}],"outputs":[{"name":"","type":"uint8"}]},
+		{"name":"proposals","type":"function","stateMutability":"view","inputs":[{"name":"","type":"uint256"}],"outputs":[{"name":"id","type":"uint256"},{"name":"proposer","type":"address"},{"name":"eta","type":"uint256"},{"name":"startBlock","type":"uint256"},{"name":"endBlock","type":"uint256"},{"name":"forVotes","type":"uint256"},{"name":"againstVotes","type":"uint256"},{"name":"abstainVotes","type":"uint256"},{"name":"canceled","type":"bool"},{"name":"executed","type":"bool"}]}
+	]`
+
+	CCTPTokenMessengerV2EventsABI = `[
+		{"name":"DepositForBurn","type":"event","anonymous":false,"inputs":[{"name":"burnToken","type":"address","indexed":true},{"name":"amount","type":"uint256","indexed":false},{"name":"depositor","type":"address","indexed":true},{"name":"mintRecipient","type":"bytes32","indexed":false},{"name":"destinationDomain","type":"uint32","indexed":false},{"name":"destinationTokenMessenger","type":"bytes32","indexed":false},{"name":"destinationCaller","type":"bytes32","indexed":false},{"name":"maxFee","type":"uint256","indexed":false},{"name":"minFinalityThreshold","type":"uint32","indexed":true},{"name":"hookData","type":"bytes","indexed":false}]}
+	]`
 )
@@ -139,6 +139,26 @@ var bridgeExecutionTargets = map[string]map[int64]map[string]struct{}{
 			"0x10D8b8DaA26d307489803e10477De69C0492B610",
 		),
 	},
+	"wormhole": {
+		1:     addressSet("0x3ee18B2214AFF97000D974cf647E7C347E8fa585"),
+		10:    addressSet("0x1D68124e65faFC907325e3EDbF8c4d84499DAa8b"),
+		56:    addressSet("0xB6F6D86a8f9879A9c87f643768d9efc38c1Da6E7"),
+		137:   addressSet("0x5a58505a96D1dbf8dF91cB21B54419FC36e93fdE"),
+		8453:  addressSet("0x8d2de8d2f73F1F4cAB472AC9A881C9b123C79627"),
+		42161: addressSet("0x0b2402144Bb366A632D14B83F244D2e0e21bD39c"),
+		43114: addressSet("0x0e082F06FF657D94310cB8cE8B0D9a04541d8052"),
+	},
+	"cctp": {
+		1:     addressSet(CCTPTokenMessengerV2),
+		10:    addressSet(CCTPTokenMessengerV2),
+		137:   addressSet(CCTPTokenMessengerV2),
+		146:   addressSet(CCTPTokenMessengerV2),
+		480:   addressSet(CCTPTokenMessengerV2),
+		8453:  addressSet(CCTPTokenMessengerV2),
+		42161: addressSet(CCTPTokenMessengerV2),
+		43114: addressSet(CCTPTokenMessengerV2),
+		59144: addressSet(CCTPTokenMessengerV2),
+	},
 }
 
 func HasBridgeExecutionTargetPolicy(provider string, chainID int64) bool {
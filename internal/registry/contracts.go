@@ -1,5 +1,10 @@
 package registry
 
+import (
+	"slices"
+	"strings"
+)
+
 // Canonical Uniswap V3-compatible contracts used by swap execution/quoting.
 // Today this map includes Taiko deployments and can be extended chain-by-chain.
 var uniswapV3ContractsByChainID = map[int64]struct {
@@ -24,6 +29,42 @@ func UniswapV3Contracts(chainID int64) (quoterV2 string, router string, ok bool)
 	return contracts.QuoterV2, contracts.Router, true
 }
 
+// UniswapV3ChainIDs lists the chains with UniswapV3Contracts.
+func UniswapV3ChainIDs() []int64 {
+	return sortedChainIDs(uniswapV3ContractsByChainID)
+}
+
+// sortedChainIDs returns the keys of a per-chain table in ascending order,
+// for provider capability metadata.
+func sortedChainIDs[V any](byChainID map[int64]V) []int64 {
+	ids := make([]int64, 0, len(byChainID))
+	for chainID := range byChainID {
+		ids = append(ids, chainID)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// Canonical Uniswap Labs QuoterV2 deployments used as an independent on-chain
+// price reference (see `verify quote`). These chains are not execution targets.
+var uniswapV3ReferenceQuoterByChainID = map[int64]string{
+	1:     "0x61fFE014bA17989E743c5F6cB21bF9697530B21e", // Ethereum
+	10:    "0x61fFE014bA17989E743c5F6cB21bF9697530B21e", // Optimism
+	137:   "0x61fFE014bA17989E743c5F6cB21bF9697530B21e", // Polygon
+	8453:  "0x3d4e44Eb1374240CE5F1B871ab261CD16335B76a", // Base
+	42161: "0x61fFE014bA17989E743c5F6cB21bF9697530B21e", // Arbitrum
+}
+
+// UniswapV3ReferenceQuoter returns a QuoterV2 for read-only price checks on
+// chainID, including chains that only have execution contracts registered.
+func UniswapV3ReferenceQuoter(chainID int64) (string, bool) {
+	if quoter, ok := uniswapV3ReferenceQuoterByChainID[chainID]; ok {
+		return quoter, true
+	}
+	quoter, _, ok := UniswapV3Contracts(chainID)
+	return quoter, ok
+}
+
 // Canonical Aave V3 PoolAddressesProvider contracts used by planners.
 var aavePoolAddressProviderByChainID = map[int64]string{
 	1:     "0x2f39d218133AFaB8F2B819B1066c7E434Ad94E9e", // Ethereum
@@ -39,6 +80,62 @@ func AavePoolAddressProvider(chainID int64) (string, bool) {
 	return value, ok
 }
 
+// Compound v3 (Comet) markets read by the on-chain lend rates fallback. The
+// base asset is read from each market's baseToken().
+var cometMarketsByChainID = map[int64][]string{
+	1: {
+		"0xc3d688B66703497DAA19211EEdff47f25384cdc3", // cUSDCv3
+		"0xA17581A9E3356d9A858b789D68B4d866e593aE94", // cWETHv3
+	},
+	137: {"0xF25212E676D1F7F89Cd72fFEe66158f541246445"}, // cUSDCv3
+	8453: {
+		"0xb125E6687d4313864e53df431d5425969c15Eb2F", // cUSDCv3
+		"0x46e6b214b524310239732D51387075E0e70970bf", // cWETHv3
+	},
+	42161: {"0x9c4ec768c28520B50860ea7a15bd7213a9fF58bf"}, // cUSDCv3
+}
+
+// CometMarkets returns the Compound v3 market contracts on chainID.
+func CometMarkets(chainID int64) []string {
+	return append([]string(nil), cometMarketsByChainID[chainID]...)
+}
+
+// ve(3,3) DEX Voter contracts. Aerodrome is a fork of Velodrome v2, so gauges
+// and their bribe reward contracts are found the same way on both.
+var ve33VoterByChainID = map[int64]string{
+	10:   "0x41C914ee0c7E1A5edCD0295623e6dC557B5aBf3C", // Velodrome v2 (Optimism)
+	8453: "0x16613524e02ad97eDfeF371bC883F2F5d6C480A5", // Aerodrome (Base)
+}
+
+func VE33Voter(chainID int64) (string, bool) {
+	value, ok := ve33VoterByChainID[chainID]
+	return value, ok
+}
+
+// Merkl Distributor contracts. Morpho distributes its incentives through Merkl.
+var merklDistributorByChainID = map[int64]string{
+	1:     "0x3Ef3D8bA38EBe18DB133cEc108f4D14CE00Dd9Ae", // Ethereum
+	10:    "0x3Ef3D8bA38EBe18DB133cEc108f4D14CE00Dd9Ae", // Optimism
+	137:   "0x3Ef3D8bA38EBe18DB133cEc108f4D14CE00Dd9Ae", // Polygon
+	8453:  "0x3Ef3D8bA38EBe18DB133cEc108f4D14CE00Dd9Ae", // Base
+	42161: "0x3Ef3D8bA38EBe18DB133cEc108f4D14CE00Dd9Ae", // Arbitrum
+}
+
+func MerklDistributor(chainID int64) (string, bool) {
+	value, ok := merklDistributorByChainID[chainID]
+	return value, ok
+}
+
+// Uniswap GovernorBravo, where UNI holders vote on protocol changes.
+var uniswapGovernorByChainID = map[int64]string{
+	1: "0x408ED6354d4973f66138C91495F2f2FCbd8724C3", // Ethereum
+}
+
+func UniswapGovernor(chainID int64) (string, bool) {
+	value, ok := uniswapGovernorByChainID[chainID]
+	return value, ok
+}
+
 // Canonical Moonwell Comptroller (Unitroller) contracts per chain.
 var moonwellComptrollerByChainID = map[int64]string{
 	8453: "0xfBb21d0380beE3312B33c4353c8936a0F13EF26C", // Base
@@ -50,6 +147,49 @@ func MoonwellComptroller(chainID int64) (string, bool) {
 	return value, ok
 }
 
+// MoonwellChainIDs lists the chains with a MoonwellComptroller.
+func MoonwellChainIDs() []int64 {
+	return sortedChainIDs(moonwellComptrollerByChainID)
+}
+
+// SparkLend PoolAddressesProvider contracts. SparkLend is an Aave v3 fork, so
+// its pool, reserves and oracle read the same way as Aave's.
+var sparkPoolAddressProviderByChainID = map[int64]string{
+	1: "0x02C3eA4e34C0cBd694D2adFa2c690EECbC1793eE", // Ethereum
+}
+
+func SparkPoolAddressProvider(chainID int64) (string, bool) {
+	value, ok := sparkPoolAddressProviderByChainID[chainID]
+	return value, ok
+}
+
+// SparkChainIDs lists the chains with a SparkPoolAddressProvider.
+func SparkChainIDs() []int64 {
+	return sortedChainIDs(sparkPoolAddressProviderByChainID)
+}
+
+// SavingsVault is an ERC-4626 token whose share price grows at the Sky
+// savings rate. RateSource names the view the rate is read from: "ssr" on
+// the vault itself, or "pot" for the Maker Pot's dsr().
+type SavingsVault struct {
+	Address    string
+	Symbol     string
+	RateSource string
+}
+
+// Sky savings vaults distributed through Spark.
+var sparkSavingsVaultsByChainID = map[int64][]SavingsVault{
+	1: {
+		{Address: "0x83F20F44975D03b1b09e64809B757c47f942BEeA", Symbol: "sDAI", RateSource: "pot"},
+		{Address: "0xa3931d71877C0E7a3148CB7Eb4463524FEc27fbD", Symbol: "sUSDS", RateSource: "ssr"},
+	},
+}
+
+// SparkSavingsVaults returns the savings vaults on chainID.
+func SparkSavingsVaults(chainID int64) []SavingsVault {
+	return append([]SavingsVault(nil), sparkSavingsVaultsByChainID[chainID]...)
+}
+
 const tempoStablecoinDEXAddress = "0xdec0000000000000000000000000000000000000"
 
 var tempoChainIDs = map[int64]struct{}{
@@ -65,6 +205,11 @@ func TempoStablecoinDEX(chainID int64) (string, bool) {
 	return tempoStablecoinDEXAddress, true
 }
 
+// TempoChainIDs lists the chains with the Tempo stablecoin DEX.
+func TempoChainIDs() []int64 {
+	return sortedChainIDs(tempoChainIDs)
+}
+
 // Canonical fee token addresses for Tempo chains.
 var tempoFeeTokenByChainID = map[int64]string{
 	4217:  "0x20c000000000000000000000b9537d11c60e8b50",
@@ -77,3 +222,234 @@ func TempoFeeToken(chainID int64) (string, bool) {
 	addr, ok := tempoFeeTokenByChainID[chainID]
 	return addr, ok
 }
+
+// CoW Protocol contracts share deterministic addresses on every supported chain.
+const (
+	cowSwapSettlementAddress   = "0x9008D19f58AAbD9eD0D60971565AA8510560ab41"
+	cowSwapVaultRelayerAddress = "0xC92E8bdf79f0507f65a392b0ab4667716BFE0110"
+)
+
+// CowSwapContracts returns the GPv2 settlement contract (EIP-712 verifying
+// contract for orders) and the vault relayer that pulls sell tokens.
+func CowSwapContracts(chainID int64) (settlement string, vaultRelayer string, ok bool) {
+	if _, ok := CowSwapOrderBookURL(chainID); !ok {
+		return "", "", false
+	}
+	return cowSwapSettlementAddress, cowSwapVaultRelayerAddress, true
+}
+
+// ParaSwap Augustus v6.2 uses one address on every supported chain and is
+// also the spender for sell-token approvals.
+const paraSwapAugustusV6Address = "0x6A000F20005980200259B80c5102003040001068"
+
+var paraSwapChainIDs = map[int64]struct{}{
+	1:     {},
+	10:    {},
+	56:    {},
+	100:   {},
+	137:   {},
+	1101:  {},
+	8453:  {},
+	42161: {},
+	43114: {},
+}
+
+// ParaSwapAugustus returns the Augustus v6.2 router for chainID.
+func ParaSwapAugustus(chainID int64) (string, bool) {
+	if _, ok := paraSwapChainIDs[chainID]; !ok {
+		return "", false
+	}
+	return paraSwapAugustusV6Address, true
+}
+
+// ParaSwapChainIDs lists the chains with ParaSwapAugustus.
+func ParaSwapChainIDs() []int64 {
+	return sortedChainIDs(paraSwapChainIDs)
+}
+
+// Odos Router V2 shares one address across the supported chains and is the
+// spender for input-token approvals.
+const odosRouterV2Address = "0xCf5540fFFCdC3d510B18bFcA6d2b9987b0772559"
+
+var odosChainIDs = map[int64]struct{}{
+	1:     {},
+	10:    {},
+	56:    {},
+	137:   {},
+	8453:  {},
+	42161: {},
+	43114: {},
+}
+
+// OdosRouter returns the Odos Router V2 for chainID.
+func OdosRouter(chainID int64) (string, bool) {
+	if _, ok := odosChainIDs[chainID]; !ok {
+		return "", false
+	}
+	return odosRouterV2Address, true
+}
+
+// OdosChainIDs lists the chains with OdosRouter.
+func OdosChainIDs() []int64 {
+	return sortedChainIDs(odosChainIDs)
+}
+
+// The 1inch Aggregation Router v6 hosts Limit Order Protocol v4: it is the
+// EIP-712 verifying contract of orders, the spender makers approve, and the
+// contract orders are cancelled on. zkSync Era has its own deployment.
+const oneInchRouterV6Address = "0x111111125421cA6dc452d289314280a0f8842A65"
+
+var oneInchRouterByChainID = map[int64]string{
+	1:     oneInchRouterV6Address,
+	10:    oneInchRouterV6Address,
+	56:    oneInchRouterV6Address,
+	100:   oneInchRouterV6Address,
+	137:   oneInchRouterV6Address,
+	324:   "0x6fd4383cB451173D5f9304F041C7BCBf27d561fF",
+	8453:  oneInchRouterV6Address,
+	42161: oneInchRouterV6Address,
+	43114: oneInchRouterV6Address,
+	59144: oneInchRouterV6Address,
+}
+
+// OneInchLimitOrderRouter returns the 1inch router hosting limit orders on
+// chainID.
+func OneInchLimitOrderRouter(chainID int64) (string, bool) {
+	addr, ok := oneInchRouterByChainID[chainID]
+	return addr, ok
+}
+
+// OneInchLimitOrderChainIDs lists the chains with OneInchLimitOrderRouter.
+func OneInchLimitOrderChainIDs() []int64 {
+	return sortedChainIDs(oneInchRouterByChainID)
+}
+
+// Chainlink USD price feeds keyed by chain and upper-case token symbol.
+// Wrapped gas tokens share the native feed.
+var chainlinkUSDFeedsByChainID = map[int64]map[string]string{
+	1: { // Ethereum
+		"ETH":  "0x5f4eC3Df9cbd43714FE2740f5E3616155c5b8419",
+		"WETH": "0x5f4eC3Df9cbd43714FE2740f5E3616155c5b8419",
+		"USDC": "0x8fFfFfd4AfB6115b954Bd326cbe7B4BA576818f6",
+		"USDT": "0x3E7d1eAB13ad0104d2750B8863b489D65364e32D",
+		"DAI":  "0xAed0c38402a5d19df6E4c03F4E2DceD6e29c1ee9",
+	},
+	10: { // Optimism
+		"ETH":  "0x13e3Ee699D1909E989722E753853AE30b17e08c5",
+		"WETH": "0x13e3Ee699D1909E989722E753853AE30b17e08c5",
+		"USDC": "0x16a9FA2FDa030272Ce99B29CF780dFA30361E0f3",
+	},
+	8453: { // Base
+		"ETH":  "0x71041dddad3595F9CEd3DcCFBe3D1F4b0a16Bb70",
+		"WETH": "0x71041dddad3595F9CEd3DcCFBe3D1F4b0a16Bb70",
+		"USDC": "0x7e860098F58bBFC8648a4311b374B1D669a2bc6B",
+	},
+	42161: { // Arbitrum
+		"ETH":  "0x639Fe6ab55C921f74e7fac1ee960C0B6293ba612",
+		"WETH": "0x639Fe6ab55C921f74e7fac1ee960C0B6293ba612",
+		"USDC": "0x50834F3163758fcC1Df9973b6e91f0F0F0434aD3",
+	},
+}
+
+// ChainlinkUSDFeed returns the Chainlink <symbol>/USD aggregator on chainID.
+func ChainlinkUSDFeed(chainID int64, symbol string) (string, bool) {
+	feed, ok := chainlinkUSDFeedsByChainID[chainID][strings.ToUpper(strings.TrimSpace(symbol))]
+	return feed, ok
+}
+
+// Multicall3Address is the deterministic Multicall3 deployment shared by all
+// major EVM chains.
+const Multicall3Address = "0xcA11bde05977b3631167028862bE2a173976CA11"
+
+// SolanaWrappedSOLMint is the wrapped SOL mint; price sources key native SOL
+// by it.
+const SolanaWrappedSOLMint = "So11111111111111111111111111111111111111112"
+
+// ENSRegistryAddress is the ENS registry (with fallback) on Ethereum mainnet.
+const ENSRegistryAddress = "0x00000000000C2E074eC69A0bFb2997BA6C7d2e1e"
+
+// Wormhole identifies chains by its own uint16 IDs inside VAAs and token
+// bridge transfers; Solana is chain 1.
+const (
+	WormholeSolanaChainID            uint16 = 1
+	WormholeSolanaTokenBridgeProgram        = "wormDTUJ6AWPNvk59vGQbDvGJmqbDTdgWgAqcLBCgUb"
+	SolanaTokenProgram                      = "TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA"
+	SolanaAssociatedTokenProgram            = "ATokenGPvbdGVxr1b2hvZbsiqW5xWH25efTNsLJA8knL"
+	// WormholeSolanaFinalityS is how long guardians wait for a finalized
+	// Solana slot before signing.
+	WormholeSolanaFinalityS int64 = 15
+)
+
+// WormholeEVMChain holds the Portal token bridge and core contracts for an
+// EVM chain. FinalityS approximates how long guardians wait before signing a
+// VAA for a transfer from that chain.
+type WormholeEVMChain struct {
+	ChainID     uint16
+	TokenBridge string
+	CoreBridge  string
+	FinalityS   int64
+}
+
+var wormholeEVMChains = map[int64]WormholeEVMChain{
+	1:     {ChainID: 2, TokenBridge: "0x3ee18B2214AFF97000D974cf647E7C347E8fa585", CoreBridge: "0x98f3c9e6E3fAce36bAAd05FE09d375Ef1464288B", FinalityS: 1000},
+	10:    {ChainID: 24, TokenBridge: "0x1D68124e65faFC907325e3EDbF8c4d84499DAa8b", CoreBridge: "0xEe91C335eab126dF5fDB3797EA9d6aD93aeC9722", FinalityS: 1100},
+	56:    {ChainID: 4, TokenBridge: "0xB6F6D86a8f9879A9c87f643768d9efc38c1Da6E7", CoreBridge: "0x98f3c9e6E3fAce36bAAd05FE09d375Ef1464288B", FinalityS: 60},
+	137:   {ChainID: 5, TokenBridge: "0x5a58505a96D1dbf8dF91cB21B54419FC36e93fdE", CoreBridge: "0x7A4B5a56256163F07b2C80A7cA55aBE66c4ec4d7", FinalityS: 120},
+	8453:  {ChainID: 30, TokenBridge: "0x8d2de8d2f73F1F4cAB472AC9A881C9b123C79627", CoreBridge: "0xbebdb6C8ddC678FfA9f8748f85C815C556Dd8ac6", FinalityS: 1100},
+	42161: {ChainID: 23, TokenBridge: "0x0b2402144Bb366A632D14B83F244D2e0e21bD39c", CoreBridge: "0xa5f208e072434bC67592E4C49C1B991BA79BCA46", FinalityS: 1100},
+	43114: {ChainID: 6, TokenBridge: "0x0e082F06FF657D94310cB8cE8B0D9a04541d8052", CoreBridge: "0x54a8e5f9c4CbA08F9943965859F6c34eAF03E26c", FinalityS: 5},
+}
+
+// WormholeEVM returns the Wormhole deployment for an EVM chain.
+func WormholeEVM(chainID int64) (WormholeEVMChain, bool) {
+	chain, ok := wormholeEVMChains[chainID]
+	return chain, ok
+}
+
+// WormholeEVMChainIDs lists the EVM chains with WormholeEVM deployments.
+func WormholeEVMChainIDs() []int64 {
+	return sortedChainIDs(wormholeEVMChains)
+}
+
+// CCTP v2 contracts share one CREATE2 address on every supported EVM chain.
+const (
+	CCTPTokenMessengerV2     = "0x28b5a0e9C621a5BadaA536219b3a228C8168cf5d"
+	CCTPMessageTransmitterV2 = "0x81D40F21F12A8F0E3252Bccb954D722d4c464B64"
+	// CCTP finality thresholds: fast transfers are attested at soft finality
+	// for a fee, standard transfers wait for hard finality.
+	CCTPFinalityFast     uint32 = 1000
+	CCTPFinalityStandard uint32 = 2000
+	// CCTPFastTransferS approximates attestation time for fast transfers.
+	CCTPFastTransferS int64 = 20
+)
+
+// CCTPChain is a CCTP v2 deployment. Domain is Circle's chain identifier and
+// StandardFinalityS approximates attestation time at hard finality.
+type CCTPChain struct {
+	Domain            uint32
+	USDC              string
+	StandardFinalityS int64
+}
+
+var cctpChains = map[int64]CCTPChain{
+	1:     {Domain: 0, USDC: "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48", StandardFinalityS: 1140},
+	10:    {Domain: 2, USDC: "0x0b2C639c533813f4Aa9D7837CAf62653d097Ff85", StandardFinalityS: 1140},
+	137:   {Domain: 7, USDC: "0x3c499c542cEF5E3811e1192ce70d8cC03d5c3359", StandardFinalityS: 8},
+	146:   {Domain: 13, USDC: "0x29219dd400f2Bf60E5a23d13Be72B486D4038894", StandardFinalityS: 8},
+	480:   {Domain: 14, USDC: "0x79A02482A880bCE3F13e09Da970dC34db4CD24d1", StandardFinalityS: 1140},
+	8453:  {Domain: 6, USDC: "0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913", StandardFinalityS: 1140},
+	42161: {Domain: 3, USDC: "0xaf88d065e77c8cC2239327C5EDb3A432268e5831", StandardFinalityS: 1140},
+	43114: {Domain: 1, USDC: "0xB97EF9Ef8734C71904D8002F8b6Bc66Dd9c48a6E", StandardFinalityS: 8},
+	59144: {Domain: 11, USDC: "0x176211869cA2b568f2A7D4EE941E073a821EE1ff", StandardFinalityS: 28800},
+}
+
+// CCTP returns the CCTP v2 deployment for an EVM chain.
+func CCTP(chainID int64) (CCTPChain, bool) {
+	chain, ok := cctpChains[chainID]
+	return chain, ok
+}
+
+// CCTPChainIDs lists the chains with CCTP deployments.
+func CCTPChainIDs() []int64 {
+	return sortedChainIDs(cctpChains)
+}
@@ -13,16 +13,142 @@ const (
 	AcrossBaseURL       = "https://app.across.to/api"
 	AcrossSettlementURL = "https://app.across.to/api/deposit/status"
 
+	// Wormholescan indexes guardian-signed VAAs and their redemptions.
+	WormholeSettlementURL = "https://api.wormholescan.io/api/v1/operations"
+	WormholeScanTxURL     = "https://wormholescan.io/#/tx/"
+
+	// Circle's Iris API quotes CCTP fees and serves burn attestations.
+	CCTPIrisBaseURL   = "https://iris-api.circle.com"
+	CCTPSettlementURL = "https://iris-api.circle.com/v2/messages"
+
 	// Shared GraphQL endpoint used by Morpho adapter and execution planner.
 	MorphoGraphQLEndpoint = "https://api.morpho.org/graphql"
+
+	// Merkl serves claimable rewards and Merkle proofs for its distributor,
+	// which Morpho uses for incentives.
+	MerklAPIBaseURL = "https://api.merkl.xyz/v4"
+
+	// Private transaction relays keep Ethereum mainnet transactions out of
+	// the public mempool. Flashbots Protect also serves per-tx status.
+	FlashbotsProtectRPCURL    = "https://rpc.flashbots.net/fast"
+	FlashbotsProtectStatusURL = "https://protect.flashbots.net/tx/"
+	MEVBlockerRPCURL          = "https://rpc.mevblocker.io"
+
+	// Etherscan V2 serves every supported EVM chain from one endpoint,
+	// selected by the chainid query parameter.
+	EtherscanV2BaseURL = "https://api.etherscan.io/v2/api"
+
+	// The Graph's decentralized network gateway. Subgraphs are addressed by
+	// deployment ID and the API key is sent as a bearer token.
+	TheGraphGatewayURL = "https://gateway.thegraph.com/api/subgraphs/id/"
+
+	// Public Solana mainnet JSON-RPC endpoint for balance reads.
+	SolanaMainnetRPCURL = "https://api.mainnet-beta.solana.com"
+
+	// Bonfida's SNS proxy resolves .sol names without a Solana RPC round trip
+	// per account lookup.
+	SNSProxyBaseURL = "https://sns-sdk-proxy.bonfida.workers.dev"
+
+	// Uniswap's default token list, used to look up symbols missing from the
+	// registry when --resolve-unknown is set.
+	DefaultTokenListURL = "https://tokens.uniswap.org"
+
+	// ethereum-lists chain registry (the data behind chainlist.org), used by
+	// `chains sync` to learn chains missing from the built-in registry.
+	DefaultChainListURL = "https://chainid.network/chains.json"
 )
 
+// CoW Protocol order book API base URLs by chain.
+var cowSwapOrderBookByChainID = map[int64]string{
+	1:        "https://api.cow.fi/mainnet",
+	100:      "https://api.cow.fi/xdai",
+	8453:     "https://api.cow.fi/base",
+	42161:    "https://api.cow.fi/arbitrum_one",
+	11155111: "https://api.cow.fi/sepolia",
+}
+
+func CowSwapOrderBookURL(chainID int64) (string, bool) {
+	value, ok := cowSwapOrderBookByChainID[chainID]
+	return value, ok
+}
+
+// CowSwapChainIDs lists the chains with a CowSwapOrderBookURL.
+func CowSwapChainIDs() []int64 {
+	return sortedChainIDs(cowSwapOrderBookByChainID)
+}
+
+// IsAllowedCowSwapOrderBookURL reports whether endpoint is the canonical order
+// book for chainID. Loopback endpoints are accepted for local testing.
+func IsAllowedCowSwapOrderBookURL(chainID int64, endpoint string) bool {
+	parsed, err := url.Parse(strings.TrimSpace(endpoint))
+	if err != nil || strings.TrimSpace(parsed.Hostname()) == "" {
+		return false
+	}
+	if isLoopbackHost(parsed.Hostname()) {
+		return true
+	}
+	allowedRaw, ok := CowSwapOrderBookURL(chainID)
+	if !ok {
+		return false
+	}
+	allowed, err := url.Parse(allowedRaw)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(parsed.Scheme, allowed.Scheme) &&
+		strings.EqualFold(parsed.Hostname(), allowed.Hostname()) &&
+		normalizedURLPort(parsed) == normalizedURLPort(allowed) &&
+		normalizedURLPath(parsed.Path) == normalizedURLPath(allowed.Path)
+}
+
+// Aave v3 protocol subgraphs on The Graph network by chain, used by
+// --backend subgraph.
+var aaveV3SubgraphByChainID = map[int64]string{
+	1:     "Cd2gEDVeqnjBn1hSeqFMitw8Q1iiyV9FYUZkLNRcL87g",
+	10:    "DSfLz8oQBUeU5atALgUFQKMTSYV9mZAVYp4noLSXAfvb",
+	137:   "Co2URyXjnxaw8WqxKyVHdirq9Ahhm5vcTs4dMedAq211",
+	8453:  "GQFbb95cE6d8mV989mL5figjaGaKCQB3xqYrr1bRyXqF",
+	42161: "DLuE98kEb5pQNXAcKFQGQgfSQ57Xdou4jnVbAEqMfy3B",
+	43114: "2h9woxy8RTjHu1HJsCEnmzpPHFArU33avmUh4f71JpVn",
+}
+
+func AaveV3SubgraphID(chainID int64) (string, bool) {
+	value, ok := aaveV3SubgraphByChainID[chainID]
+	return value, ok
+}
+
+// Uniswap protocol subgraphs on The Graph network by version and chain,
+// used by pools list/details.
+var uniswapSubgraphByVersion = map[string]map[int64]string{
+	"v3": {
+		1:     "5zvR82QoaXYFyDEKLZ9t6v9adgnptxYpKpSbxtgVENFV",
+		10:    "Cghf4LfVqPiFw6fp6Y5X5Ubc8UpmUhSfJL82zwiBFLaj",
+		137:   "3hCPRGf4z88VC5rsBKU5AA9FBBq5nF3jbKJG7VZCbhjm",
+		8453:  "43Hwfi3dJSoGpyas9VwNoDAv55yjgGrPpNSmbQZArzMG",
+		42161: "FbCGRftH4a3yZugY7TnbYgPJVEv2LvMT6oF1fxPe9aJM",
+	},
+	"v4": {
+		1:     "DiYPVdygkfjDWhbxGSqAQxwBKmfKnkWQojqeM2rkLb3G",
+		8453:  "HNCFA9TyBqpo5qpe6QreQABAA1kV8g46mhkCcicu6v2R",
+		42161: "G5TsTKNi8yhPSV7kycaE23oWbqv9zzNqR49FoEQjzq1r",
+	},
+}
+
+func UniswapSubgraphID(version string, chainID int64) (string, bool) {
+	value, ok := uniswapSubgraphByVersion[version][chainID]
+	return value, ok
+}
+
 func BridgeSettlementURL(provider string) (string, bool) {
 	switch strings.ToLower(strings.TrimSpace(provider)) {
 	case "lifi":
 		return LiFiSettlementURL, true
 	case "across":
 		return AcrossSettlementURL, true
+	case "wormhole":
+		return WormholeSettlementURL, true
+	case "cctp":
+		return CCTPSettlementURL, true
 	default:
 		return "", false
 	}
@@ -0,0 +1,166 @@
+package registry
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Address label categories.
+const (
+	LabelCategoryRouter     = "router"
+	LabelCategorySettlement = "settlement"
+	LabelCategoryBridge     = "bridge"
+	LabelCategoryLending    = "lending"
+	LabelCategoryDEX        = "dex"
+	LabelCategoryGovernance = "governance"
+	LabelCategoryRewards    = "rewards"
+	LabelCategoryUtility    = "utility"
+	LabelCategoryMultisig   = "multisig"
+)
+
+// AddressLabel names a well-known contract. ChainID 0 marks a deterministic
+// deployment that has the same address on every EVM chain.
+type AddressLabel struct {
+	ChainID  int64
+	Address  string
+	Label    string
+	Category string
+	Protocol string
+}
+
+// Permit2 is Uniswap's shared token approval contract; routers pull tokens
+// through it once a token is approved to it.
+const permit2Address = "0x000000000022D473030F116dDEE9F6B43aC78BA3"
+
+// Aave V3 Pool proxies, the contracts supply/borrow transactions target. The
+// planners resolve them from the PoolAddressesProvider at run time; they are
+// listed here only so previews and activity can name them.
+var aavePoolByChainID = map[int64]string{
+	1:     "0x87870Bca3F3fD6335C3F4ce8392D69350B4fA4E2",
+	10:    "0x794a61358D6845594F94dc1DB02A252b5b4814aD",
+	137:   "0x794a61358D6845594F94dc1DB02A252b5b4814aD",
+	8453:  "0xA238Dd80C259a72e81d7e4664a9801593F98d1c5",
+	42161: "0x794a61358D6845594F94dc1DB02A252b5b4814aD",
+	43114: "0x794a61358D6845594F94dc1DB02A252b5b4814aD",
+}
+
+var bridgeProviderNames = map[string]string{
+	"lifi":     "LI.FI Diamond",
+	"across":   "Across SpokePool",
+	"wormhole": "Wormhole Token Bridge",
+	"cctp":     "CCTP TokenMessengerV2",
+}
+
+// AddressLabels returns the bundled address directory: every contract this
+// registry knows about, plus a few chain-agnostic deployments. Entries are
+// sorted by chain and address; an address that serves several roles on one
+// chain keeps the first label registered for it.
+func AddressLabels() []AddressLabel {
+	var out []AddressLabel
+	seen := map[string]bool{}
+	add := func(chainID int64, address, label, category, protocol string) {
+		if !common.IsHexAddress(address) {
+			return
+		}
+		address = common.HexToAddress(address).Hex()
+		key := fmt.Sprintf("%d|%s", chainID, address)
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		out = append(out, AddressLabel{ChainID: chainID, Address: address, Label: label, Category: category, Protocol: protocol})
+	}
+
+	add(0, permit2Address, "Uniswap Permit2", LabelCategoryUtility, "uniswap")
+	add(0, Multicall3Address, "Multicall3", LabelCategoryUtility, "multicall")
+	add(1, ENSRegistryAddress, "ENS Registry", LabelCategoryUtility, "ens")
+	for chainID, contracts := range uniswapV3ContractsByChainID {
+		add(chainID, contracts.Router, "Uniswap V3 SwapRouter02", LabelCategoryRouter, "uniswap")
+		add(chainID, contracts.QuoterV2, "Uniswap V3 QuoterV2", LabelCategoryDEX, "uniswap")
+	}
+	for chainID, quoter := range uniswapV3ReferenceQuoterByChainID {
+		add(chainID, quoter, "Uniswap V3 QuoterV2", LabelCategoryDEX, "uniswap")
+	}
+	for chainID, pool := range aavePoolByChainID {
+		add(chainID, pool, "Aave V3 Pool", LabelCategoryLending, "aave")
+	}
+	for chainID, provider := range aavePoolAddressProviderByChainID {
+		add(chainID, provider, "Aave V3 PoolAddressesProvider", LabelCategoryLending, "aave")
+	}
+	for chainID, markets := range cometMarketsByChainID {
+		for _, market := range markets {
+			add(chainID, market, "Compound V3 Comet market", LabelCategoryLending, "compound")
+		}
+	}
+	for chainID, comptroller := range moonwellComptrollerByChainID {
+		add(chainID, comptroller, "Moonwell Comptroller", LabelCategoryLending, "moonwell")
+	}
+	for chainID, provider := range sparkPoolAddressProviderByChainID {
+		add(chainID, provider, "SparkLend PoolAddressesProvider", LabelCategoryLending, "spark")
+	}
+	for chainID, vaults := range sparkSavingsVaultsByChainID {
+		for _, vault := range vaults {
+			add(chainID, vault.Address, "Spark "+vault.Symbol+" savings vault", LabelCategoryLending, "spark")
+		}
+	}
+	for chainID, voter := range ve33VoterByChainID {
+		protocol, label := "aerodrome", "Aerodrome Voter"
+		if chainID == 10 {
+			protocol, label = "velodrome", "Velodrome Voter"
+		}
+		add(chainID, voter, label, LabelCategoryRewards, protocol)
+	}
+	for chainID, distributor := range merklDistributorByChainID {
+		add(chainID, distributor, "Merkl Distributor", LabelCategoryRewards, "merkl")
+	}
+	for chainID, governor := range uniswapGovernorByChainID {
+		add(chainID, governor, "Uniswap Governor Bravo", LabelCategoryGovernance, "uniswap")
+	}
+	for chainID := range tempoChainIDs {
+		add(chainID, tempoStablecoinDEXAddress, "Tempo Stablecoin DEX", LabelCategoryDEX, "tempo")
+	}
+	for chainID := range cowSwapOrderBookByChainID {
+		add(chainID, cowSwapSettlementAddress, "CoW Protocol GPv2Settlement", LabelCategorySettlement, "cowswap")
+		add(chainID, cowSwapVaultRelayerAddress, "CoW Protocol Vault Relayer", LabelCategorySettlement, "cowswap")
+	}
+	for chainID := range paraSwapChainIDs {
+		add(chainID, paraSwapAugustusV6Address, "ParaSwap Augustus V6.2", LabelCategoryRouter, "paraswap")
+	}
+	for chainID := range odosChainIDs {
+		add(chainID, odosRouterV2Address, "Odos Router V2", LabelCategoryRouter, "odos")
+	}
+	for chainID, router := range oneInchRouterByChainID {
+		add(chainID, router, "1inch Aggregation Router V6", LabelCategoryRouter, "1inch")
+	}
+	for chainID, chain := range wormholeEVMChains {
+		add(chainID, chain.TokenBridge, "Wormhole Token Bridge", LabelCategoryBridge, "wormhole")
+		add(chainID, chain.CoreBridge, "Wormhole Core Bridge", LabelCategoryBridge, "wormhole")
+	}
+	for chainID := range cctpChains {
+		add(chainID, CCTPTokenMessengerV2, "CCTP TokenMessengerV2", LabelCategoryBridge, "cctp")
+		add(chainID, CCTPMessageTransmitterV2, "CCTP MessageTransmitterV2", LabelCategoryBridge, "cctp")
+	}
+	providers := make([]string, 0, len(bridgeExecutionTargets))
+	for provider := range bridgeExecutionTargets {
+		providers = append(providers, provider)
+	}
+	sort.Strings(providers)
+	for _, provider := range providers {
+		for chainID, targets := range bridgeExecutionTargets[provider] {
+			for target := range targets {
+				add(chainID, target, bridgeProviderNames[provider], LabelCategoryBridge, provider)
+			}
+		}
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].ChainID != out[j].ChainID {
+			return out[i].ChainID < out[j].ChainID
+		}
+		return strings.ToLower(out[i].Address) < strings.ToLower(out[j].Address)
+	})
+	return out
+}
@@ -37,12 +37,22 @@ func TestAavePoolAddressProvider(t *testing.T) {
 func TestExecutionABIConstantsParse(t *testing.T) {
 	abis := []string{
 		ERC20MinimalABI,
+		OwnableABI,
+		ProxyBeaconABI,
 		UniswapV3QuoterV2ABI,
 		UniswapV3RouterABI,
 		AavePoolAddressProviderABI,
 		AavePoolABI,
+		AaveOracleABI,
 		AaveRewardsABI,
+		CometABI,
+		ERC20SupplyABI,
+		VE33VoterABI,
+		VE33RewardABI,
+		MerklDistributorABI,
 		MorphoBlueABI,
+		SkySavingsABI,
+		MakerPotABI,
 	}
 	for _, raw := range abis {
 		if _, err := abi.JSON(strings.NewReader(raw)); err != nil {
@@ -85,6 +95,28 @@ func TestResolveRPCURL(t *testing.T) {
 	}
 }
 
+func TestConfiguredRPCURLsTakePrecedence(t *testing.T) {
+	SetConfiguredRPCURLs(map[int64][]string{8453: {" https://base.example ", "https://base-2.example"}, 999999: {"https://custom.example"}})
+	defer SetConfiguredRPCURLs(nil)
+
+	if rpc, err := ResolveRPCURL("", 8453); err != nil || rpc != "https://base.example" {
+		t.Fatalf("expected configured base rpc, got %q err=%v", rpc, err)
+	}
+	if rpc, err := ResolveRPCURL("https://flag.example", 8453); err != nil || rpc != "https://flag.example" {
+		t.Fatalf("expected --rpc-url to win, got %q err=%v", rpc, err)
+	}
+	if rpc, ok := DefaultRPCURL(999999); !ok || rpc != "https://custom.example" {
+		t.Fatalf("expected configured rpc for unlisted chain, got %q", rpc)
+	}
+	builtin, _ := BuiltinRPCURL(8453)
+	if got := RPCEndpoints(8453); len(got) != 3 || got[1] != "https://base-2.example" || got[2] != builtin {
+		t.Fatalf("expected configured endpoints then the built-in default, got %v", got)
+	}
+	if chainID, ok := RPCEndpointChainID("https://base-2.example"); !ok || chainID != 8453 {
+		t.Fatalf("expected base-2 to map to 8453, got %d ok=%v", chainID, ok)
+	}
+}
+
 func TestBridgeSettlementURL(t *testing.T) {
 	got, ok := BridgeSettlementURL("lifi")
 	if !ok || got != LiFiSettlementURL {
@@ -2,6 +2,7 @@ package registry
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 )
 
@@ -34,11 +35,122 @@ var defaultRPCByChainID = map[int64]string{
 	534352: "https://rpc.scroll.io",
 }
 
-func DefaultRPCURL(chainID int64) (string, bool) {
+// configuredRPCByChainID holds RPC URLs from the config file or active
+// profile, primary first. They take precedence over the built-in defaults,
+// which stay in the pool as the last fallback.
+var configuredRPCByChainID = map[int64][]string{}
+
+// SetConfiguredRPCURLs replaces the configured RPC URLs. The CLI calls it
+// once per invocation after loading configuration.
+func SetConfiguredRPCURLs(urls map[int64][]string) {
+	configured := make(map[int64][]string, len(urls))
+	for chainID, list := range urls {
+		for _, url := range list {
+			if url = strings.TrimSpace(url); url != "" && !slices.Contains(configured[chainID], url) {
+				configured[chainID] = append(configured[chainID], url)
+			}
+		}
+	}
+	configuredRPCByChainID = configured
+}
+
+// syncedRPCByChainID holds public RPC URLs from the synced chain list. They
+// are used only for chains with no configured or built-in endpoint.
+var syncedRPCByChainID = map[int64][]string{}
+
+// SetSyncedRPCURLs replaces the RPC URLs learned from the synced chain list.
+func SetSyncedRPCURLs(urls map[int64][]string) {
+	synced := make(map[int64][]string, len(urls))
+	for chainID, list := range urls {
+		for _, url := range list {
+			if url = strings.TrimSpace(url); url != "" && !slices.Contains(synced[chainID], url) {
+				synced[chainID] = append(synced[chainID], url)
+			}
+		}
+	}
+	syncedRPCByChainID = synced
+}
+
+// SyncedRPCURLs returns the synced chain list endpoints for chainID.
+func SyncedRPCURLs(chainID int64) []string {
+	return slices.Clone(syncedRPCByChainID[chainID])
+}
+
+// ConfiguredRPCURLs returns the configured endpoints for chainID.
+func ConfiguredRPCURLs(chainID int64) []string {
+	return slices.Clone(configuredRPCByChainID[chainID])
+}
+
+// BuiltinRPCURL returns the built-in default endpoint for chainID, ignoring
+// configuration.
+func BuiltinRPCURL(chainID int64) (string, bool) {
 	value, ok := defaultRPCByChainID[chainID]
 	return value, ok
 }
 
+// RPCEndpoints returns every endpoint registered for chainID: configured
+// endpoints in order, then the built-in default. Chains with neither fall
+// back to the synced chain list endpoints.
+func RPCEndpoints(chainID int64) []string {
+	endpoints := ConfiguredRPCURLs(chainID)
+	if value, ok := defaultRPCByChainID[chainID]; ok && !slices.Contains(endpoints, value) {
+		endpoints = append(endpoints, value)
+	}
+	if len(endpoints) == 0 {
+		endpoints = SyncedRPCURLs(chainID)
+	}
+	return endpoints
+}
+
+// RPCEndpointChainID reports which chain's endpoint pool contains url.
+func RPCEndpointChainID(url string) (int64, bool) {
+	url = strings.TrimSpace(url)
+	for chainID, list := range configuredRPCByChainID {
+		if slices.Contains(list, url) {
+			return chainID, true
+		}
+	}
+	for chainID, value := range defaultRPCByChainID {
+		if value == url {
+			return chainID, true
+		}
+	}
+	for chainID, list := range syncedRPCByChainID {
+		if slices.Contains(list, url) {
+			return chainID, true
+		}
+	}
+	return 0, false
+}
+
+// RPCChainIDs lists every chain with a configured or built-in endpoint.
+func RPCChainIDs() []int64 {
+	ids := make([]int64, 0, len(defaultRPCByChainID)+len(configuredRPCByChainID))
+	for chainID := range defaultRPCByChainID {
+		ids = append(ids, chainID)
+	}
+	for chainID := range configuredRPCByChainID {
+		if _, ok := defaultRPCByChainID[chainID]; !ok {
+			ids = append(ids, chainID)
+		}
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+func DefaultRPCURL(chainID int64) (string, bool) {
+	if list := configuredRPCByChainID[chainID]; len(list) > 0 {
+		return list[0], true
+	}
+	if value, ok := defaultRPCByChainID[chainID]; ok {
+		return value, true
+	}
+	if list := syncedRPCByChainID[chainID]; len(list) > 0 {
+		return list[0], true
+	}
+	return "", false
+}
+
 func ResolveRPCURL(override string, chainID int64) (string, error) {
 	if strings.TrimSpace(override) != "" {
 		return strings.TrimSpace(override), nil
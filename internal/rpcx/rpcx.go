@@ -0,0 +1,364 @@
+// Package rpcx dials EVM JSON-RPC endpoints with failover across every
+// endpoint registered for a chain. It keeps per-endpoint health (latency and
+// recent failures) so the fastest healthy endpoint is tried first.
+package rpcx
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ggonzalez94/defi-cli/internal/httpx"
+	"github.com/ggonzalez94/defi-cli/internal/registry"
+)
+
+const (
+	// attemptTimeout bounds each try while another endpoint remains, so a
+	// hanging primary cannot consume the whole command timeout.
+	attemptTimeout = 8 * time.Second
+	// failureCooldown moves a failing endpoint behind healthy ones. It grows
+	// with consecutive failures up to maxCooldownSteps times.
+	failureCooldown  = time.Minute
+	maxCooldownSteps = 5
+	// latencyWeight is the EWMA weight of the newest latency sample.
+	latencyWeight = 0.3
+)
+
+// Health is the observed state of one endpoint.
+type Health struct {
+	URL           string    `json:"url"`
+	LatencyMS     int64     `json:"latency_ms,omitempty"`
+	Failures      int       `json:"failures,omitempty"`
+	LastError     string    `json:"last_error,omitempty"`
+	CheckedAt     time.Time `json:"checked_at"`
+	CooldownUntil time.Time `json:"cooldown_until"`
+}
+
+var (
+	healthMu sync.Mutex
+	health   = map[string]Health{}
+	dirty    bool
+	now      = time.Now
+)
+
+// DialContext connects an ethclient to rawURL. When rawURL belongs to a
+// chain's endpoint pool, requests fail over to the other endpoints of that
+// chain on transport errors, timeouts, and 429/5xx responses.
+func DialContext(ctx context.Context, rawURL string) (*ethclient.Client, error) {
+	client, err := DialRPC(ctx, rawURL)
+	if err != nil {
+		return nil, err
+	}
+	return ethclient.NewClient(client), nil
+}
+
+// DialRPC is DialContext for callers that need the raw RPC client.
+func DialRPC(ctx context.Context, rawURL string) (*rpc.Client, error) {
+	rawURL = strings.TrimSpace(rawURL)
+	chainID, pooled := registry.RPCEndpointChainID(rawURL)
+	if !isHTTP(rawURL) {
+		if httpx.Offline() {
+			return nil, httpx.ErrOffline
+		}
+		return rpc.DialContext(ctx, rawURL)
+	}
+	if !pooled {
+		return rpc.DialOptions(ctx, rawURL, rpc.WithHTTPClient(&http.Client{Transport: httpx.Transport(http.DefaultTransport)}))
+	}
+	transport := &failoverTransport{endpoints: registry.RPCEndpoints(chainID), base: httpx.Transport(http.DefaultTransport)}
+	return rpc.DialOptions(ctx, rawURL, rpc.WithHTTPClient(&http.Client{Transport: transport}))
+}
+
+// Endpoints returns the endpoint pool of chainID in the order requests try
+// them.
+func Endpoints(chainID int64) []string {
+	return Order(registry.RPCEndpoints(chainID))
+}
+
+// Order sorts endpoints for selection: endpoints outside a failure cooldown
+// first, then by observed latency. Endpoints without a latency sample keep
+// their configured position ahead of measured ones so they get measured.
+func Order(endpoints []string) []string {
+	healthMu.Lock()
+	defer healthMu.Unlock()
+	t := now()
+	ordered := append([]string(nil), endpoints...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		hi, hj := health[ordered[i]], health[ordered[j]]
+		ci, cj := hi.CooldownUntil.After(t), hj.CooldownUntil.After(t)
+		if ci != cj {
+			return !ci
+		}
+		if (hi.LatencyMS == 0) != (hj.LatencyMS == 0) {
+			return hi.LatencyMS == 0
+		}
+		return hi.LatencyMS < hj.LatencyMS
+	})
+	return ordered
+}
+
+// Record stores the outcome of one request to endpoint.
+func Record(endpoint string, latency time.Duration, err error) {
+	healthMu.Lock()
+	defer healthMu.Unlock()
+	h := health[endpoint]
+	h.URL = endpoint
+	h.CheckedAt = now().UTC()
+	if err != nil {
+		h.Failures++
+		h.LastError = err.Error()
+		steps := min(h.Failures, maxCooldownSteps)
+		h.CooldownUntil = h.CheckedAt.Add(time.Duration(steps) * failureCooldown)
+	} else {
+		ms := max(latency.Milliseconds(), 1)
+		if h.LatencyMS == 0 {
+			h.LatencyMS = ms
+		} else {
+			h.LatencyMS = int64(latencyWeight*float64(ms) + (1-latencyWeight)*float64(h.LatencyMS))
+		}
+		h.Failures = 0
+		h.LastError = ""
+		h.CooldownUntil = time.Time{}
+	}
+	health[endpoint] = h
+	dirty = true
+}
+
+// HealthOf returns the recorded health of endpoint.
+func HealthOf(endpoint string) (Health, bool) {
+	healthMu.Lock()
+	defer healthMu.Unlock()
+	h, ok := health[endpoint]
+	return h, ok
+}
+
+// LoadHealth merges health saved by earlier invocations. A missing file is
+// not an error.
+func LoadHealth(path string) error {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	var saved []Health
+	if err := json.Unmarshal(buf, &saved); err != nil {
+		return fmt.Errorf("decode rpc health: %w", err)
+	}
+	healthMu.Lock()
+	defer healthMu.Unlock()
+	for _, h := range saved {
+		if current, ok := health[h.URL]; !ok || current.CheckedAt.Before(h.CheckedAt) {
+			health[h.URL] = h
+		}
+	}
+	return nil
+}
+
+// SaveHealth writes the health table to path when it changed since the last
+// load or save.
+func SaveHealth(path string) error {
+	healthMu.Lock()
+	if !dirty {
+		healthMu.Unlock()
+		return nil
+	}
+	saved := make([]Health, 0, len(health))
+	for _, h := range health {
+		saved = append(saved, h)
+	}
+	dirty = false
+	healthMu.Unlock()
+
+	sort.Slice(saved, func(i, j int) bool { return saved[i].URL < saved[j].URL })
+	buf, err := json.MarshalIndent(saved, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".rpc-health-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(buf); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Probe is the result of checking one endpoint directly.
+type Probe struct {
+	URL         string
+	ChainID     int64
+	BlockNumber uint64
+	Latency     time.Duration
+	Err         error
+}
+
+// ProbeEndpoint reads eth_chainId and eth_blockNumber from endpoint without
+// failover and records the outcome. A chain ID other than wantChainID counts
+// as a failure.
+func ProbeEndpoint(ctx context.Context, endpoint string, wantChainID int64) Probe {
+	probe := Probe{URL: endpoint}
+	start := now()
+	probe.Err = func() error {
+		if httpx.Offline() {
+			return httpx.ErrOffline
+		}
+		client, err := ethclient.DialContext(ctx, endpoint)
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+		chainID, err := client.ChainID(ctx)
+		if err != nil {
+			return err
+		}
+		probe.ChainID = chainID.Int64()
+		if wantChainID != 0 && probe.ChainID != wantChainID {
+			return fmt.Errorf("endpoint serves chain id %d, expected %d", probe.ChainID, wantChainID)
+		}
+		probe.BlockNumber, err = client.BlockNumber(ctx)
+		return err
+	}()
+	probe.Latency = now().Sub(start)
+	Record(endpoint, probe.Latency, probe.Err)
+	return probe
+}
+
+// Redact hides credentials that RPC providers embed in URLs: user info,
+// query values, and long path segments such as API keys.
+func Redact(rawURL string) string {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || u.Host == "" {
+		return rawURL
+	}
+	u.User = nil
+	segments := strings.Split(u.Path, "/")
+	for i, segment := range segments {
+		if len(segment) >= 16 {
+			segments[i] = "***"
+		}
+	}
+	u.Path = strings.Join(segments, "/")
+	u.RawPath = ""
+	if u.RawQuery != "" {
+		query := u.Query()
+		for key := range query {
+			query.Set(key, "***")
+		}
+		u.RawQuery = query.Encode()
+	}
+	return strings.ReplaceAll(u.String(), "%2A%2A%2A", "***")
+}
+
+func isHTTP(rawURL string) bool {
+	lower := strings.ToLower(rawURL)
+	return strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://")
+}
+
+// failoverTransport sends each JSON-RPC request to the endpoints of a pool in
+// health order until one answers.
+type failoverTransport struct {
+	endpoints []string
+	base      http.RoundTripper
+}
+
+func (t *failoverTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	ordered := Order(t.endpoints)
+	var lastErr error
+	for i, endpoint := range ordered {
+		target, err := url.Parse(endpoint)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		last := i == len(ordered)-1
+		ctx, cancel := req.Context(), context.CancelFunc(func() {})
+		if !last {
+			ctx, cancel = context.WithTimeout(ctx, attemptTimeout)
+		}
+		attempt := req.Clone(ctx)
+		attempt.URL = target
+		attempt.Host = ""
+		attempt.Body = io.NopCloser(bytes.NewReader(body))
+		attempt.ContentLength = int64(len(body))
+
+		start := now()
+		resp, err := t.base.RoundTrip(attempt)
+		if errors.Is(err, httpx.ErrOffline) {
+			cancel()
+			return nil, err
+		}
+		if err == nil && !retryableStatus(resp.StatusCode) {
+			Record(endpoint, now().Sub(start), nil)
+			resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+			return resp, nil
+		}
+		if err == nil {
+			err = fmt.Errorf("rpc endpoint returned status %d", resp.StatusCode)
+			if last {
+				Record(endpoint, 0, err)
+				resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+				return resp, nil
+			}
+			_ = resp.Body.Close()
+		}
+		cancel()
+		Record(endpoint, 0, err)
+		lastErr = err
+		if req.Context().Err() != nil {
+			return nil, req.Context().Err()
+		}
+	}
+	if lastErr == nil {
+		lastErr = errors.New("no rpc endpoints configured")
+	}
+	return nil, lastErr
+}
+
+func retryableStatus(status int) bool {
+	return status == http.StatusTooManyRequests || status >= http.StatusInternalServerError
+}
+
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
@@ -0,0 +1,195 @@
+package schema
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// JSONSchemaDialect is the JSON Schema draft used by output model documents.
+const JSONSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// OutputModelsDocument is a JSON Schema document describing the data models
+// the CLI emits. The root validates an Envelope; every other model lives in
+// Defs keyed by its Go type name.
+type OutputModelsDocument struct {
+	Schema      string                 `json:"$schema"`
+	ID          string                 `json:"$id"`
+	Title       string                 `json:"title"`
+	Version     string                 `json:"version"`
+	Description string                 `json:"description,omitempty"`
+	Ref         string                 `json:"$ref,omitempty"`
+	Models      []string               `json:"models"`
+	Defs        map[string]*JSONSchema `json:"$defs"`
+}
+
+// JSONSchema is the subset of JSON Schema 2020-12 emitted for output models.
+// Unit and semantic annotations use x- extension keywords.
+type JSONSchema struct {
+	Ref                  string                 `json:"$ref,omitempty"`
+	Type                 any                    `json:"type,omitempty"`
+	Format               string                 `json:"format,omitempty"`
+	Enum                 []string               `json:"enum,omitempty"`
+	Properties           map[string]*JSONSchema `json:"properties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	Items                *JSONSchema            `json:"items,omitempty"`
+	AdditionalProperties *JSONSchema            `json:"additionalProperties,omitempty"`
+	AnyOf                []*JSONSchema          `json:"anyOf,omitempty"`
+	Unit                 string                 `json:"x-unit,omitempty"`
+	Semantic             string                 `json:"x-semantic,omitempty"`
+}
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// OutputModels builds a JSON Schema document for models, which must be struct
+// values. Nested struct types become shared definitions referenced with $ref.
+// The first model is the document root. Fields are required unless tagged
+// omitempty; pointer, slice, and map fields without omitempty may be null.
+func OutputModels(version string, models ...any) OutputModelsDocument {
+	b := &jsonSchemaBuilder{defs: map[string]*JSONSchema{}, names: map[reflect.Type]string{}}
+	doc := OutputModelsDocument{
+		Schema:  JSONSchemaDialect,
+		ID:      "urn:defi-cli:output-models:" + version,
+		Title:   "defi-cli output models",
+		Version: version,
+		Defs:    b.defs,
+	}
+	for i, value := range models {
+		typ := reflect.TypeOf(value)
+		for typ != nil && typ.Kind() == reflect.Pointer {
+			typ = typ.Elem()
+		}
+		if typ == nil || typ.Kind() != reflect.Struct {
+			continue
+		}
+		ref := b.schemaFor(typ)
+		name := strings.TrimPrefix(ref.Ref, "#/$defs/")
+		if i == 0 {
+			doc.Ref = ref.Ref
+		}
+		doc.Models = append(doc.Models, name)
+	}
+	return doc
+}
+
+type jsonSchemaBuilder struct {
+	defs  map[string]*JSONSchema
+	names map[reflect.Type]string
+}
+
+func (b *jsonSchemaBuilder) schemaFor(typ reflect.Type) *JSONSchema {
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	if typ == reflect.TypeOf(time.Time{}) {
+		return &JSONSchema{Type: "string", Format: "date-time"}
+	}
+	if reflect.PointerTo(typ).Implements(jsonMarshalerType) {
+		return &JSONSchema{}
+	}
+	if reflect.PointerTo(typ).Implements(textMarshalerType) {
+		return &JSONSchema{Type: "string"}
+	}
+
+	switch typ.Kind() {
+	case reflect.Bool:
+		return &JSONSchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &JSONSchema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &JSONSchema{Type: "number"}
+	case reflect.String:
+		return &JSONSchema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		return &JSONSchema{Type: "array", Items: b.schemaFor(typ.Elem())}
+	case reflect.Map:
+		return &JSONSchema{Type: "object", AdditionalProperties: b.schemaFor(typ.Elem())}
+	case reflect.Struct:
+		return &JSONSchema{Ref: "#/$defs/" + b.define(typ)}
+	default:
+		return &JSONSchema{}
+	}
+}
+
+// define registers typ under $defs and returns its name. Types are named after
+// the Go type; a name already taken by another package is qualified with the
+// package name.
+func (b *jsonSchemaBuilder) define(typ reflect.Type) string {
+	if name, ok := b.names[typ]; ok {
+		return name
+	}
+	name := typ.Name()
+	if _, taken := b.defs[name]; taken || name == "" {
+		name = pathBase(typ.PkgPath()) + "." + name
+	}
+	b.names[typ] = name
+	def := &JSONSchema{Type: "object", Properties: map[string]*JSONSchema{}}
+	b.defs[name] = def
+
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		jsonName := jsonFieldName(field)
+		if jsonName == "" {
+			continue
+		}
+		omitEmpty := strings.Contains(field.Tag.Get("json"), ",omitempty")
+		prop := b.schemaFor(field.Type)
+		annotateJSONSchemaUnits(field, jsonName, prop)
+		if !omitEmpty {
+			def.Required = append(def.Required, jsonName)
+			prop = nullable(field.Type, prop)
+		}
+		def.Properties[jsonName] = prop
+	}
+	sort.Strings(def.Required)
+	return name
+}
+
+// nullable widens prop to accept null for Go types that encode nil as null.
+func nullable(typ reflect.Type, prop *JSONSchema) *JSONSchema {
+	switch typ.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Map:
+	default:
+		return prop
+	}
+	if prop.Ref != "" {
+		return &JSONSchema{AnyOf: []*JSONSchema{prop, {Type: "null"}}}
+	}
+	if typeName, ok := prop.Type.(string); ok {
+		prop.Type = []string{typeName, "null"}
+	}
+	return prop
+}
+
+// annotateJSONSchemaUnits copies the unit and semantic inferred for a field
+// onto its JSON Schema, or onto the items of a scalar array.
+func annotateJSONSchemaUnits(field reflect.StructField, jsonName string, prop *JSONSchema) {
+	target := prop
+	if prop.Type == "array" && prop.Items != nil {
+		target = prop.Items
+	}
+	typeName, _ := target.Type.(string)
+	if !isScalarSchemaType(typeName) {
+		return
+	}
+	probe := TypeSchema{Type: typeName}
+	applyFieldUnits(field, jsonName, &probe)
+	target.Unit, target.Semantic = probe.Unit, probe.Semantic
+}
+
+func pathBase(pkgPath string) string {
+	if i := strings.LastIndex(pkgPath, "/"); i >= 0 {
+		return pkgPath[i+1:]
+	}
+	return pkgPath
+}
@@ -50,6 +50,8 @@ type FlagMetadata struct {
 type TypeSchema struct {
 	Type                 string        `json:"type"`
 	Format               string        `json:"format,omitempty"`
+	Unit                 string        `json:"unit,omitempty"`
+	Semantic             string        `json:"semantic,omitempty"`
 	Description          string        `json:"description,omitempty"`
 	Enum                 []string      `json:"enum,omitempty"`
 	Fields               []SchemaField `json:"fields,omitempty"`
@@ -156,6 +158,7 @@ func SchemaFromFlagBindings(cmd *cobra.Command, binding any) (TypeSchema, error)
 			Required: strings.EqualFold(strings.TrimSpace(field.Tag.Get("required")), "true"),
 			Schema:   schemaFromReflectType(field.Type),
 		}
+		applyFieldUnits(field, jsonName, &fieldSchema.Schema)
 		if flag != nil {
 			fieldSchema.Description = flag.Usage
 			fieldSchema.Default = parseFlagDefault(flag)
@@ -238,6 +241,7 @@ func schemaFromReflectTypeSeen(typ reflect.Type, seen map[reflect.Type]bool) Typ
 				Required: !strings.Contains(field.Tag.Get("json"), ",omitempty"),
 				Schema:   schemaFromReflectTypeSeen(field.Type, seen),
 			}
+			applyFieldUnits(field, jsonName, &fieldSchema.Schema)
 			fields = append(fields, fieldSchema)
 		}
 		return TypeSchema{Type: "object", Fields: fields}
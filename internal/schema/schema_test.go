@@ -75,3 +75,82 @@ func TestBuildSchema(t *testing.T) {
 		t.Fatalf("unexpected provider enum: %#v", got)
 	}
 }
+
+func TestSchemaFromTypeAnnotatesUnits(t *testing.T) {
+	s := SchemaFromType(struct {
+		APYTotal    float64  `json:"apy_total"`
+		TVLUSD      float64  `json:"tvl_usd"`
+		SlippageBps int      `json:"slippage_bps"`
+		Utilization float64  `json:"utilization"`
+		BaseUnits   string   `json:"amount_base_units"`
+		ChainID     string   `json:"chain_id"`
+		Score       float64  `json:"score" unit:"ratio" semantic:"risk-score"`
+		Name        string   `json:"name"`
+	}{})
+	want := map[string][2]string{
+		"apy_total":         {UnitPercent, ""},
+		"tvl_usd":           {UnitUSD, ""},
+		"slippage_bps":      {UnitBPS, ""},
+		"utilization":       {UnitRatio, ""},
+		"amount_base_units": {UnitBaseUnits, SemanticIntegerString},
+		"chain_id":          {"", SemanticCAIP2},
+		"score":             {"ratio", "risk-score"},
+		"name":              {"", ""},
+	}
+	for _, field := range s.Fields {
+		expected, ok := want[field.Name]
+		if !ok {
+			continue
+		}
+		if field.Schema.Unit != expected[0] || field.Schema.Semantic != expected[1] {
+			t.Fatalf("field %s: expected unit=%q semantic=%q, got %q/%q", field.Name, expected[0], expected[1], field.Schema.Unit, field.Schema.Semantic)
+		}
+	}
+}
+
+func TestOutputModelsBuildsJSONSchemaDefs(t *testing.T) {
+	type leg struct {
+		AmountUSD float64 `json:"amount_usd"`
+	}
+	type quote struct {
+		ChainID string   `json:"chain_id"`
+		Legs    []leg    `json:"legs"`
+		Best    *leg     `json:"best"`
+		Note    string   `json:"note,omitempty"`
+		Tags    []string `json:"tags,omitempty"`
+	}
+	type envelope struct {
+		Version string `json:"version"`
+		Data    any    `json:"data,omitempty"`
+	}
+
+	doc := OutputModels("v1", envelope{}, quote{})
+	if doc.Schema != JSONSchemaDialect || doc.Version != "v1" || doc.ID != "urn:defi-cli:output-models:v1" {
+		t.Fatalf("unexpected document header: %+v", doc)
+	}
+	if doc.Ref != "#/$defs/envelope" {
+		t.Fatalf("expected envelope root ref, got %q", doc.Ref)
+	}
+	if len(doc.Models) != 2 || doc.Models[0] != "envelope" || doc.Models[1] != "quote" {
+		t.Fatalf("unexpected models: %#v", doc.Models)
+	}
+	def := doc.Defs["quote"]
+	if def == nil {
+		t.Fatalf("expected quote definition, got %#v", doc.Defs)
+	}
+	if got := def.Required; len(got) != 3 || got[0] != "best" || got[1] != "chain_id" || got[2] != "legs" {
+		t.Fatalf("unexpected required fields: %#v", got)
+	}
+	if got := def.Properties["chain_id"]; got.Type != "string" || got.Semantic != SemanticCAIP2 {
+		t.Fatalf("unexpected chain_id schema: %+v", got)
+	}
+	if got := def.Properties["legs"]; got.Items == nil || got.Items.Ref != "#/$defs/leg" {
+		t.Fatalf("expected legs to reference leg, got %+v", got)
+	}
+	if got := def.Properties["best"]; len(got.AnyOf) != 2 || got.AnyOf[0].Ref != "#/$defs/leg" || got.AnyOf[1].Type != "null" {
+		t.Fatalf("expected nullable best ref, got %+v", got)
+	}
+	if got := doc.Defs["leg"].Properties["amount_usd"]; got.Type != "number" || got.Unit != UnitUSD {
+		t.Fatalf("unexpected amount_usd schema: %+v", got)
+	}
+}
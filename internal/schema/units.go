@@ -0,0 +1,116 @@
+package schema
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Units reported in TypeSchema.Unit.
+const (
+	UnitPercent      = "percent"    // percentage points: 2.3 means 2.3%
+	UnitRatio        = "ratio"      // fraction in [0,1]: 0.023 means 2.3%
+	UnitBPS          = "bps"        // basis points: 23 means 0.23%
+	UnitUSD          = "usd"        // US dollars
+	UnitBaseUnits    = "base-units" // integer token amount in the smallest unit
+	UnitTokenDecimal = "token"      // human-readable token amount
+	UnitGwei         = "gwei"
+	UnitSeconds      = "seconds"
+	UnitMilliseconds = "milliseconds"
+	UnitDays         = "days"
+)
+
+// Semantic types reported in TypeSchema.Semantic.
+const (
+	SemanticCAIP2         = "caip2"
+	SemanticCAIP19        = "caip19"
+	SemanticAddress       = "address"
+	SemanticEVMChainID    = "evm-chain-id"
+	SemanticIntegerString = "integer-string"
+	SemanticDecimalString = "decimal-string"
+	SemanticTimestamp     = "timestamp"
+	SemanticUnixTimestamp = "unix-timestamp"
+	SemanticTokenDecimals = "token-decimals"
+	SemanticTxHash        = "tx-hash"
+	SemanticURL           = "url"
+)
+
+// applyFieldUnits fills Unit and Semantic on a field schema. Explicit `unit`
+// and `semantic` struct tags win; otherwise both are inferred from the JSON
+// field name using the repo-wide naming conventions (_pct, _usd, _bps, ...).
+func applyFieldUnits(field reflect.StructField, jsonName string, s *TypeSchema) {
+	unit, semantic := inferFieldUnits(jsonName, s.Type)
+	if tag := strings.TrimSpace(field.Tag.Get("unit")); tag != "" {
+		unit = tag
+	}
+	if tag := strings.TrimSpace(field.Tag.Get("semantic")); tag != "" {
+		semantic = tag
+	}
+	if s.Type == "array" && s.Items != nil && isScalarSchemaType(s.Items.Type) {
+		s.Items.Unit, s.Items.Semantic = unit, semantic
+		return
+	}
+	if !isScalarSchemaType(s.Type) {
+		return
+	}
+	s.Unit, s.Semantic = unit, semantic
+}
+
+func inferFieldUnits(name, typ string) (unit, semantic string) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	numeric := typ == "number" || typ == "integer"
+	switch {
+	case name == "utilization":
+		return UnitRatio, ""
+	case numeric && (strings.HasSuffix(name, "_pct") || name == "apy" || strings.HasPrefix(name, "apy_") || strings.HasSuffix(name, "_apy")):
+		return UnitPercent, ""
+	case numeric && strings.HasSuffix(name, "_bps"):
+		return UnitBPS, ""
+	case numeric && strings.HasSuffix(name, "_usd"):
+		return UnitUSD, ""
+	case numeric && strings.HasSuffix(name, "_gwei"):
+		return UnitGwei, ""
+	case numeric && strings.HasSuffix(name, "_ms"):
+		return UnitMilliseconds, ""
+	case numeric && strings.HasSuffix(name, "_unix"):
+		return UnitSeconds, SemanticUnixTimestamp
+	case numeric && (strings.HasSuffix(name, "_s") || strings.HasSuffix(name, "_seconds")):
+		return UnitSeconds, ""
+	case numeric && strings.HasSuffix(name, "_days"):
+		return UnitDays, ""
+	case name == "decimals":
+		return "", SemanticTokenDecimals
+	case name == "evm_chain_id":
+		return "", SemanticEVMChainID
+	}
+	if typ != "string" {
+		return "", ""
+	}
+	switch {
+	case strings.HasSuffix(name, "base_units"):
+		return UnitBaseUnits, SemanticIntegerString
+	case strings.HasSuffix(name, "_decimal"):
+		return UnitTokenDecimal, SemanticDecimalString
+	case name == "caip2" || name == "chain_id" || strings.HasSuffix(name, "_chain_id"):
+		return "", SemanticCAIP2
+	case name == "asset_id" || strings.HasSuffix(name, "_asset_id"):
+		return "", SemanticCAIP19
+	case name == "address" || strings.HasSuffix(name, "_address"):
+		return "", SemanticAddress
+	case name == "tx_hash" || strings.HasSuffix(name, "_tx_hash"):
+		return "", SemanticTxHash
+	case name == "timestamp" || strings.HasSuffix(name, "_at") || strings.HasSuffix(name, "_time"):
+		return "", SemanticTimestamp
+	case name == "url" || strings.HasSuffix(name, "_url"):
+		return "", SemanticURL
+	}
+	return "", ""
+}
+
+func isScalarSchemaType(typ string) bool {
+	switch typ {
+	case "number", "integer", "string":
+		return true
+	default:
+		return false
+	}
+}
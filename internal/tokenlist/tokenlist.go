@@ -0,0 +1,221 @@
+// Package tokenlist reads and writes the user token list that extends the
+// bootstrap token registry in internal/id. The file uses the Uniswap token
+// list format so published lists can be imported as-is.
+package tokenlist
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ggonzalez94/defi-cli/internal/httpx"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+)
+
+// SolanaChainID is the chainId Solana token lists use for mainnet.
+const SolanaChainID = 101
+
+// ListUser is the token list name recorded for tokens added one at a time.
+const ListUser = "user"
+
+// List is a Uniswap-style token list.
+type List struct {
+	Name      string  `json:"name"`
+	Timestamp string  `json:"timestamp,omitempty"`
+	Tokens    []Token `json:"tokens"`
+}
+
+// Token is one token list entry. Source is a defi-cli extension naming the
+// list or URL the token was imported from.
+type Token struct {
+	ChainID  int64    `json:"chainId"`
+	Address  string   `json:"address"`
+	Symbol   string   `json:"symbol"`
+	Name     string   `json:"name,omitempty"`
+	Decimals int      `json:"decimals"`
+	LogoURI  string   `json:"logoURI,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+	Source   string   `json:"source,omitempty"`
+}
+
+// Chain returns the registry chain of the token.
+func (t Token) Chain() (id.Chain, error) {
+	if t.ChainID == SolanaChainID {
+		return id.ParseChain("solana")
+	}
+	return id.ParseChain(strconv.FormatInt(t.ChainID, 10))
+}
+
+// Normalize validates t and returns it with a canonical address and an
+// upper-case symbol.
+func (t Token) Normalize() (Token, error) {
+	chain, err := t.Chain()
+	if err != nil {
+		return Token{}, err
+	}
+	// ParseAsset also accepts symbols, so the parsed address must echo the
+	// input. Only the address is needed, so unknown tokens are not looked up.
+	opts := id.DefaultResolveOptions()
+	opts.ResolveUnknown = false
+	asset, err := id.ParseAssetWithOptions(t.Address, chain, opts)
+	if err != nil || !strings.EqualFold(asset.Address, strings.TrimSpace(t.Address)) {
+		return Token{}, fmt.Errorf("invalid token address %q for %s", t.Address, chain.Slug)
+	}
+	t.Address = asset.Address
+	t.Symbol = strings.ToUpper(strings.TrimSpace(t.Symbol))
+	t.Name = strings.TrimSpace(t.Name)
+	if t.Symbol == "" || strings.ContainsAny(t.Symbol, " /:") {
+		return Token{}, fmt.Errorf("invalid token symbol %q", t.Symbol)
+	}
+	if t.Decimals < 0 || t.Decimals > 255 {
+		return Token{}, fmt.Errorf("token decimals must be between 0 and 255, got %d", t.Decimals)
+	}
+	return t, nil
+}
+
+// Load reads the token list at path. A missing file is an empty list.
+func Load(path string) (List, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return List{Name: ListUser}, nil
+		}
+		return List{}, fmt.Errorf("read token list: %w", err)
+	}
+	list, err := Parse(buf)
+	if err != nil {
+		return List{}, fmt.Errorf("token list %s: %w", path, err)
+	}
+	return list, nil
+}
+
+// Parse decodes a token list document.
+func Parse(buf []byte) (List, error) {
+	var list List
+	if err := json.Unmarshal(buf, &list); err != nil {
+		return List{}, fmt.Errorf("decode token list: %w", err)
+	}
+	if list.Tokens == nil {
+		return List{}, errors.New("decode token list: missing tokens array")
+	}
+	return list, nil
+}
+
+// Fetch downloads a token list from url.
+func Fetch(ctx context.Context, client *httpx.Client, url string) (List, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return List{}, err
+	}
+	var list List
+	if _, err := client.DoJSON(ctx, req, &list); err != nil {
+		return List{}, err
+	}
+	if list.Tokens == nil {
+		return List{}, fmt.Errorf("%s is not a token list: missing tokens array", url)
+	}
+	return list, nil
+}
+
+// Save writes the list to path, sorted by chain and symbol.
+func Save(path string, list List) error {
+	sort.SliceStable(list.Tokens, func(i, j int) bool {
+		a, b := list.Tokens[i], list.Tokens[j]
+		if a.ChainID != b.ChainID {
+			return a.ChainID < b.ChainID
+		}
+		if a.Symbol != b.Symbol {
+			return a.Symbol < b.Symbol
+		}
+		return a.Address < b.Address
+	})
+	buf, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create token list dir: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tokens-*")
+	if err != nil {
+		return fmt.Errorf("write token list: %w", err)
+	}
+	if _, err := tmp.Write(append(buf, '\n')); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("write token list: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("write token list: %w", err)
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Upsert adds t to the list or replaces the entry with the same chain and
+// address. It reports whether an entry was replaced.
+func (l *List) Upsert(t Token) bool {
+	for i, existing := range l.Tokens {
+		if existing.ChainID == t.ChainID && strings.EqualFold(existing.Address, t.Address) {
+			l.Tokens[i] = t
+			return true
+		}
+	}
+	l.Tokens = append(l.Tokens, t)
+	return false
+}
+
+// Remove drops entries on chainID whose address or symbol matches asset and
+// returns them.
+func (l *List) Remove(chainID int64, asset string) []Token {
+	asset = strings.TrimSpace(asset)
+	var removed []Token
+	kept := l.Tokens[:0]
+	for _, t := range l.Tokens {
+		if t.ChainID == chainID && (strings.EqualFold(t.Address, asset) || strings.EqualFold(t.Symbol, asset)) {
+			removed = append(removed, t)
+			continue
+		}
+		kept = append(kept, t)
+	}
+	l.Tokens = kept
+	return removed
+}
+
+// Registry converts the list to registry tokens keyed by CAIP-2 chain ID.
+// Entries that fail validation are skipped.
+func (l List) Registry() map[string][]id.Token {
+	out := map[string][]id.Token{}
+	for _, t := range l.Tokens {
+		t, err := t.Normalize()
+		if err != nil {
+			continue
+		}
+		chain, _ := t.Chain()
+		source := t.Source
+		if source == "" {
+			source = ListUser
+		}
+		out[chain.CAIP2] = append(out[chain.CAIP2], id.Token{Symbol: t.Symbol, Address: t.Address, Decimals: t.Decimals, Lists: []string{source}})
+	}
+	return out
+}
+
+// TokenChainID returns the token list chainId of chain.
+func TokenChainID(chain id.Chain) (int64, error) {
+	switch {
+	case chain.IsEVM():
+		return chain.EVMChainID, nil
+	case chain.IsSolana():
+		return SolanaChainID, nil
+	default:
+		return 0, fmt.Errorf("unsupported chain %s", chain.CAIP2)
+	}
+}
@@ -0,0 +1,87 @@
+package tokenlist
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ggonzalez94/defi-cli/internal/httpx"
+)
+
+func TestNormalize(t *testing.T) {
+	token, err := Token{ChainID: 8453, Address: "0x4ED4E862860beD51a9570b96d89aF5E1B0Efefed", Symbol: " degen ", Decimals: 18}.Normalize()
+	if err != nil {
+		t.Fatalf("Normalize failed: %v", err)
+	}
+	if token.Address != "0x4ed4e862860bed51a9570b96d89af5e1b0efefed" || token.Symbol != "DEGEN" {
+		t.Fatalf("unexpected token %+v", token)
+	}
+
+	invalid := []Token{
+		{ChainID: 8453, Address: "USDC", Symbol: "USDC", Decimals: 6},
+		{ChainID: 8453, Address: "0x1234", Symbol: "BAD", Decimals: 6},
+		{ChainID: 8453, Address: "0x4ED4E862860beD51a9570b96d89aF5E1B0Efefed", Symbol: "", Decimals: 18},
+		{ChainID: 8453, Address: "0x4ED4E862860beD51a9570b96d89aF5E1B0Efefed", Symbol: "DEGEN", Decimals: 300},
+	}
+	for _, tc := range invalid {
+		if _, err := tc.Normalize(); err == nil {
+			t.Fatalf("expected %+v to be rejected", tc)
+		}
+	}
+}
+
+func TestSaveLoadAndEdit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tokens.json")
+	list, err := Load(path)
+	if err != nil || len(list.Tokens) != 0 {
+		t.Fatalf("expected empty list for a missing file, got %+v err=%v", list, err)
+	}
+
+	list.Upsert(Token{ChainID: 8453, Address: "0x4ed4e862860bed51a9570b96d89af5e1b0efefed", Symbol: "DEGEN", Decimals: 18})
+	list.Upsert(Token{ChainID: SolanaChainID, Address: "DezXAZ8z7PnrnRJjz3wXBoRgixCa6xjnB7YaB1pPB263", Symbol: "BONK", Decimals: 5, Source: "Jupiter"})
+	if replaced := list.Upsert(Token{ChainID: 8453, Address: "0x4ED4E862860beD51a9570b96d89aF5E1B0Efefed", Symbol: "DEGEN", Name: "Degen", Decimals: 18}); !replaced {
+		t.Fatal("expected same chain and address to replace the entry")
+	}
+	if err := Save(path, list); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil || len(loaded.Tokens) != 2 || loaded.Tokens[0].ChainID != SolanaChainID || loaded.Tokens[1].Name != "Degen" {
+		t.Fatalf("unexpected loaded list %+v err=%v", loaded, err)
+	}
+	registry := loaded.Registry()
+	bonk := registry["solana:5eykt4UsFv8P8NJdTREpY1vzqKqZKvdp"]
+	if len(bonk) != 1 || bonk[0].Symbol != "BONK" || bonk[0].Lists[0] != "Jupiter" {
+		t.Fatalf("unexpected solana registry entry %+v", bonk)
+	}
+	if base := registry["eip155:8453"]; len(base) != 1 || base[0].Lists[0] != ListUser {
+		t.Fatalf("unexpected base registry entry %+v", base)
+	}
+
+	if removed := loaded.Remove(8453, "degen"); len(removed) != 1 || len(loaded.Tokens) != 1 {
+		t.Fatalf("expected DEGEN removed by symbol, got %+v", removed)
+	}
+}
+
+func TestFetch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"name":"Test List","timestamp":"2026-01-01T00:00:00Z","version":{"major":1,"minor":0,"patch":0},"tokens":[{"chainId":1,"address":"0x6B175474E89094C44Da98b954EedeAC495271d0F","symbol":"DAI","name":"Dai","decimals":18,"logoURI":"https://example.com/dai.png"}]}`))
+	}))
+	defer srv.Close()
+
+	list, err := Fetch(context.Background(), httpx.New(2*time.Second, 0), srv.URL)
+	if err != nil {
+		t.Fatalf("Fetch failed: %v", err)
+	}
+	if list.Name != "Test List" || len(list.Tokens) != 1 || list.Tokens[0].Symbol != "DAI" {
+		t.Fatalf("unexpected list %+v", list)
+	}
+	if _, err := Parse([]byte(`{"name":"no tokens"}`)); err == nil {
+		t.Fatal("expected a document without tokens to be rejected")
+	}
+}
@@ -0,0 +1,282 @@
+// Package tokenlookup resolves tokens that are missing from the registry:
+// addresses through ERC-20 metadata calls and symbols through a token list.
+// Results are not curated, so callers surface every lookup to the user.
+package tokenlookup
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/httpx"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/registry"
+	"github.com/ggonzalez94/defi-cli/internal/rpcx"
+	"github.com/ggonzalez94/defi-cli/internal/tokenlist"
+)
+
+// Lookup sources.
+const (
+	SourceOnChain   = "onchain"
+	SourceTokenList = "token_list"
+)
+
+var erc20ABI = mustABI(registry.ERC20MetadataABI)
+
+// Lookup records one resolution outside the registry.
+type Lookup struct {
+	ChainID  string
+	Input    string
+	Symbol   string
+	Name     string
+	Address  string
+	Decimals int
+	Source   string
+	// List is the token list name for token list lookups.
+	List string
+	Err  error
+}
+
+// DialFunc connects to an RPC endpoint of chainID. The returned function
+// releases the connection.
+type DialFunc func(ctx context.Context, chainID int64) (ethereum.ContractCaller, func(), error)
+
+// Resolver implements id.UnknownResolver. Lookups are memoized for the life
+// of the resolver and the token list is fetched at most once.
+type Resolver struct {
+	http         *httpx.Client
+	tokenListURL string
+	timeout      time.Duration
+	dial         DialFunc
+
+	mu      sync.Mutex
+	list    *tokenlist.List
+	listErr error
+	memo    map[string]Lookup
+	lookups []Lookup
+}
+
+// New returns a resolver that reads token metadata from the chain's default
+// RPC and looks symbols up in the token list at tokenListURL
+// (registry.DefaultTokenListURL when empty).
+func New(client *httpx.Client, tokenListURL string, timeout time.Duration) *Resolver {
+	if strings.TrimSpace(tokenListURL) == "" {
+		tokenListURL = registry.DefaultTokenListURL
+	}
+	return &Resolver{http: client, tokenListURL: tokenListURL, timeout: timeout, dial: dialRPC, memo: map[string]Lookup{}}
+}
+
+// WithDial replaces how the resolver connects to chains.
+func (r *Resolver) WithDial(dial DialFunc) *Resolver {
+	r.dial = dial
+	return r
+}
+
+// Lookups returns every distinct lookup made so far, in order.
+func (r *Resolver) Lookups() []Lookup {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Lookup(nil), r.lookups...)
+}
+
+// ResolveAddress reads symbol, name, and decimals from the ERC-20 contract at
+// address.
+func (r *Resolver) ResolveAddress(chain id.Chain, address string) (id.Token, error) {
+	key := chain.CAIP2 + "|address|" + strings.ToLower(address)
+	if lookup, ok := r.cached(key); ok {
+		return lookupToken(lookup)
+	}
+	lookup := Lookup{ChainID: chain.CAIP2, Input: address, Address: address, Source: SourceOnChain}
+	if !chain.IsEVM() {
+		lookup.Err = fmt.Errorf("on-chain token lookup supports EVM chains only")
+	} else {
+		ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+		defer cancel()
+		caller, release, err := r.dial(ctx, chain.EVMChainID)
+		if err != nil {
+			lookup.Err = err
+		} else {
+			lookup.Name, lookup.Symbol, lookup.Decimals, lookup.Err = ReadERC20(ctx, caller, common.HexToAddress(address))
+			release()
+		}
+	}
+	r.record(key, lookup)
+	return lookupToken(lookup)
+}
+
+// ResolveSymbol finds symbol on chain in the token list. More than one match
+// is a usage error listing the candidates.
+func (r *Resolver) ResolveSymbol(chain id.Chain, symbol string) (id.Token, error) {
+	key := chain.CAIP2 + "|symbol|" + strings.ToUpper(symbol)
+	if lookup, ok := r.cached(key); ok {
+		return lookupToken(lookup)
+	}
+	lookup := Lookup{ChainID: chain.CAIP2, Input: symbol, Source: SourceTokenList}
+	list, err := r.tokenList()
+	switch {
+	case err != nil:
+		lookup.Err = err
+	case !chain.IsEVM():
+		lookup.Err = fmt.Errorf("token list lookup supports EVM chains only")
+	default:
+		lookup.List = list.Name
+		var matches []tokenlist.Token
+		for _, token := range list.Tokens {
+			if token.ChainID != chain.EVMChainID || !strings.EqualFold(strings.TrimSpace(token.Symbol), symbol) {
+				continue
+			}
+			if normalized, err := token.Normalize(); err == nil {
+				matches = append(matches, normalized)
+			}
+		}
+		switch len(matches) {
+		case 0:
+			lookup.Err = fmt.Errorf("not in token list %s", list.Name)
+		case 1:
+			lookup.Symbol = matches[0].Symbol
+			lookup.Name = matches[0].Name
+			lookup.Address = matches[0].Address
+			lookup.Decimals = matches[0].Decimals
+		default:
+			candidates := make([]id.AssetCandidate, 0, len(matches))
+			for _, match := range matches {
+				candidates = append(candidates, id.AssetCandidate{Address: match.Address, Symbol: match.Symbol, Decimals: match.Decimals, Lists: []string{list.Name}})
+			}
+			lookup.Err = clierr.New(clierr.CodeUsage, fmt.Sprintf("symbol %s matches %d tokens on %s in token list %s; pass the token address", symbol, len(matches), chain.CAIP2, list.Name)).
+				WithDetails(map[string]any{"symbol": symbol, "chain_id": chain.CAIP2, "candidates": candidates})
+		}
+	}
+	r.record(key, lookup)
+	return lookupToken(lookup)
+}
+
+// ReadERC20 reads name, symbol, and decimals from an ERC-20 contract. Tokens
+// that return bytes32 instead of string (MKR, SAI) are handled, and a
+// missing name is not an error.
+func ReadERC20(ctx context.Context, caller ethereum.ContractCaller, token common.Address) (name, symbol string, decimals int, err error) {
+	symbol, err = callString(ctx, caller, token, "symbol")
+	if err != nil {
+		return "", "", 0, fmt.Errorf("read symbol(): %w", err)
+	}
+	if strings.TrimSpace(symbol) == "" {
+		return "", "", 0, fmt.Errorf("token %s returned an empty symbol", token.Hex())
+	}
+	out, err := call(ctx, caller, token, "decimals")
+	if err != nil {
+		return "", "", 0, fmt.Errorf("read decimals(): %w", err)
+	}
+	values, err := erc20ABI.Unpack("decimals", out)
+	if err != nil || len(values) != 1 {
+		return "", "", 0, fmt.Errorf("decode decimals(): %v", err)
+	}
+	d, ok := values[0].(uint8)
+	if !ok {
+		return "", "", 0, fmt.Errorf("decode decimals(): unexpected type %T", values[0])
+	}
+	name, _ = callString(ctx, caller, token, "name")
+	return name, symbol, int(d), nil
+}
+
+func callString(ctx context.Context, caller ethereum.ContractCaller, token common.Address, method string) (string, error) {
+	out, err := call(ctx, caller, token, method)
+	if err != nil {
+		return "", err
+	}
+	if values, err := erc20ABI.Unpack(method, out); err == nil && len(values) == 1 {
+		if s, ok := values[0].(string); ok {
+			return strings.TrimSpace(s), nil
+		}
+	}
+	if len(out) == 32 {
+		return strings.TrimSpace(string(bytes.TrimRight(out, "\x00"))), nil
+	}
+	return "", fmt.Errorf("decode %s(): unexpected return data", method)
+}
+
+func call(ctx context.Context, caller ethereum.ContractCaller, token common.Address, method string) ([]byte, error) {
+	data, err := erc20ABI.Pack(method)
+	if err != nil {
+		return nil, err
+	}
+	out, err := caller.CallContract(ctx, ethereum.CallMsg{To: &token, Data: data}, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("no contract code or empty return at %s", token.Hex())
+	}
+	return out, nil
+}
+
+func (r *Resolver) tokenList() (tokenlist.List, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.list == nil && r.listErr == nil {
+		ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+		defer cancel()
+		list, err := tokenlist.Fetch(ctx, r.http, r.tokenListURL)
+		if err != nil {
+			r.listErr = fmt.Errorf("fetch token list %s: %w", r.tokenListURL, err)
+		} else {
+			if strings.TrimSpace(list.Name) == "" {
+				list.Name = r.tokenListURL
+			}
+			r.list = &list
+		}
+	}
+	if r.listErr != nil {
+		return tokenlist.List{}, r.listErr
+	}
+	return *r.list, nil
+}
+
+func (r *Resolver) cached(key string) (Lookup, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	lookup, ok := r.memo[key]
+	return lookup, ok
+}
+
+func (r *Resolver) record(key string, lookup Lookup) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.memo[key]; ok {
+		return
+	}
+	r.memo[key] = lookup
+	r.lookups = append(r.lookups, lookup)
+}
+
+func lookupToken(lookup Lookup) (id.Token, error) {
+	if lookup.Err != nil {
+		return id.Token{}, lookup.Err
+	}
+	return id.Token{Symbol: strings.ToUpper(lookup.Symbol), Address: lookup.Address, Decimals: lookup.Decimals}, nil
+}
+
+func dialRPC(ctx context.Context, chainID int64) (ethereum.ContractCaller, func(), error) {
+	rpcURL, err := registry.ResolveRPCURL("", chainID)
+	if err != nil {
+		return nil, nil, err
+	}
+	client, err := rpcx.DialContext(ctx, rpcURL)
+	if err != nil {
+		return nil, nil, err
+	}
+	return client, client.Close, nil
+}
+
+func mustABI(raw string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(raw))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
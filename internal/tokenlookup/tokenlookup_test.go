@@ -0,0 +1,121 @@
+package tokenlookup
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/httpx"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+)
+
+type fakeCaller map[string][]byte
+
+func (f fakeCaller) CallContract(ctx context.Context, msg ethereum.CallMsg, block *big.Int) ([]byte, error) {
+	method, err := erc20ABI.MethodById(msg.Data[:4])
+	if err != nil {
+		return nil, err
+	}
+	out, ok := f[method.Name]
+	if !ok {
+		return nil, errors.New("execution reverted")
+	}
+	return out, nil
+}
+
+func packOutput(t *testing.T, method string, value any) []byte {
+	t.Helper()
+	out, err := erc20ABI.Methods[method].Outputs.Pack(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+func TestResolveAddressReadsERC20Metadata(t *testing.T) {
+	base, _ := id.ParseChain("base")
+	caller := fakeCaller{
+		"symbol":   packOutput(t, "symbol", "DEGEN"),
+		"name":     packOutput(t, "name", "Degen"),
+		"decimals": packOutput(t, "decimals", uint8(18)),
+	}
+	var dials atomic.Int32
+	r := New(nil, "", time.Second).WithDial(func(ctx context.Context, chainID int64) (ethereum.ContractCaller, func(), error) {
+		dials.Add(1)
+		if chainID != 8453 {
+			t.Fatalf("unexpected chain %d", chainID)
+		}
+		return caller, func() {}, nil
+	})
+
+	address := "0x4ed4e862860bed51a9570b96d89af5e1b0efefed"
+	token, err := r.ResolveAddress(base, address)
+	if err != nil || token.Symbol != "DEGEN" || token.Decimals != 18 {
+		t.Fatalf("unexpected token %+v err=%v", token, err)
+	}
+	if _, err := r.ResolveAddress(base, address); err != nil || dials.Load() != 1 {
+		t.Fatalf("expected memoized lookup, dials=%d err=%v", dials.Load(), err)
+	}
+	lookups := r.Lookups()
+	if len(lookups) != 1 || lookups[0].Source != SourceOnChain || lookups[0].Name != "Degen" {
+		t.Fatalf("unexpected lookups %+v", lookups)
+	}
+}
+
+func TestReadERC20Bytes32Symbol(t *testing.T) {
+	var symbol [32]byte
+	copy(symbol[:], "MKR")
+	caller := fakeCaller{
+		"symbol":   symbol[:],
+		"decimals": packOutput(t, "decimals", uint8(18)),
+	}
+	name, sym, decimals, err := ReadERC20(context.Background(), caller, common.HexToAddress("0x9f8F72aA9304c8B593d555F12eF6589cC3A579A2"))
+	if err != nil || sym != "MKR" || decimals != 18 || name != "" {
+		t.Fatalf("unexpected metadata name=%q symbol=%q decimals=%d err=%v", name, sym, decimals, err)
+	}
+	if _, _, _, err := ReadERC20(context.Background(), fakeCaller{}, common.HexToAddress("0x9f8F72aA9304c8B593d555F12eF6589cC3A579A2")); err == nil {
+		t.Fatal("expected an error for a contract without symbol()")
+	}
+}
+
+func TestResolveSymbolFromTokenList(t *testing.T) {
+	var fetches atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"name":"Test List","tokens":[
+			{"chainId":8453,"address":"0x4ED4E862860beD51a9570b96d89aF5E1B0Efefed","symbol":"DEGEN","name":"Degen","decimals":18},
+			{"chainId":8453,"address":"0x0000000000000000000000000000000000000a01","symbol":"DUP","decimals":18},
+			{"chainId":8453,"address":"0x0000000000000000000000000000000000000a02","symbol":"DUP","decimals":6}
+		]}`))
+	}))
+	defer srv.Close()
+	base, _ := id.ParseChain("base")
+	r := New(httpx.New(time.Second, 0), srv.URL, time.Second)
+
+	token, err := r.ResolveSymbol(base, "degen")
+	if err != nil || token.Address != "0x4ed4e862860bed51a9570b96d89af5e1b0efefed" || token.Decimals != 18 {
+		t.Fatalf("unexpected token %+v err=%v", token, err)
+	}
+	_, err = r.ResolveSymbol(base, "DUP")
+	if cErr, ok := clierr.As(err); !ok || cErr.Code != clierr.CodeUsage {
+		t.Fatalf("expected usage error for ambiguous symbol, got %v", err)
+	}
+	if _, err := r.ResolveSymbol(base, "NOPE"); err == nil {
+		t.Fatal("expected missing symbol error")
+	}
+	if fetches.Load() != 1 {
+		t.Fatalf("expected token list fetched once, got %d", fetches.Load())
+	}
+	if lookups := r.Lookups(); len(lookups) != 3 || lookups[0].List != "Test List" {
+		t.Fatalf("unexpected lookups %+v", lookups)
+	}
+}
@@ -0,0 +1,357 @@
+// Package tokensafety inspects ERC-20 contracts before agents trade them:
+// on-chain metadata, proxy and ownership state, and heuristic flags for
+// honeypot and transfer-tax patterns.
+package tokensafety
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	clierr "github.com/ggonzalez94/defi-cli/internal/errors"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/model"
+	"github.com/ggonzalez94/defi-cli/internal/registry"
+	"github.com/ggonzalez94/defi-cli/internal/tokenlookup"
+)
+
+// Flag severities; the report's risk level is the highest one present.
+const (
+	SeverityLow    = "low"
+	SeverityMedium = "medium"
+	SeverityHigh   = "high"
+)
+
+// Verification states.
+const (
+	Verified   = "verified"
+	Unverified = "unverified"
+	Unknown    = "unknown"
+)
+
+var (
+	// EIP-1967 storage slots for the implementation and beacon addresses.
+	implementationSlot = common.HexToHash("0x360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbc")
+	beaconSlot         = common.HexToHash("0xa3f0ad74e5423aebfd80d3ef4346578335a9a72aeaee59ff6cb3582b35133d50")
+
+	supplyABI  = mustABI(registry.ERC20SupplyABI)
+	ownableABI = mustABI(registry.OwnableABI)
+	beaconABI  = mustABI(registry.ProxyBeaconABI)
+)
+
+// Backend is the RPC surface Inspect needs; *ethclient.Client satisfies it.
+type Backend interface {
+	ethereum.ContractCaller
+	CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error)
+	StorageAt(ctx context.Context, account common.Address, key common.Hash, blockNumber *big.Int) ([]byte, error)
+}
+
+// rule is one privileged-function pattern. Pattern matches function names in
+// a verified ABI; Signatures are looked up as PUSH4 selectors in bytecode so
+// unverified tokens are checked too.
+type rule struct {
+	Code       string
+	Severity   string
+	Message    string
+	Pattern    *regexp.Regexp
+	Signatures []string
+}
+
+var rules = []rule{
+	{
+		Code:       "transfer_tax",
+		Severity:   SeverityHigh,
+		Message:    "a privileged account can set transfer fees or taxes",
+		Pattern:    regexp.MustCompile(`(?i)^(set|update|change)\w*(fee|tax)`),
+		Signatures: []string{"setFee(uint256)", "setFees(uint256,uint256)", "setTaxFeePercent(uint256)", "setBuyFee(uint256)", "setSellFee(uint256)", "setTaxes(uint256,uint256)", "updateFees(uint256,uint256)"},
+	},
+	{
+		Code:       "trading_switch",
+		Severity:   SeverityHigh,
+		Message:    "trading must be switched on by the owner; transfers or sells can be blocked until then",
+		Pattern:    regexp.MustCompile(`(?i)^(enable|open|start|set)trading`),
+		Signatures: []string{"enableTrading()", "openTrading()", "startTrading()", "setTradingEnabled(bool)"},
+	},
+	{
+		Code:       "blacklist",
+		Severity:   SeverityMedium,
+		Message:    "a privileged account can block addresses from transferring",
+		Pattern:    regexp.MustCompile(`(?i)(blacklist|blocklist|^(set|add)bots?$)`),
+		Signatures: []string{"blacklist(address)", "addToBlacklist(address)", "setBlacklist(address,bool)", "setBots(address[])", "addBots(address[])"},
+	},
+	{
+		Code:       "transfer_limits",
+		Severity:   SeverityMedium,
+		Message:    "a privileged account can cap transaction or wallet sizes",
+		Pattern:    regexp.MustCompile(`(?i)^(set|update)\w*max(tx|txn|transaction|wallet)`),
+		Signatures: []string{"setMaxTxAmount(uint256)", "setMaxTxPercent(uint256)", "setMaxWalletSize(uint256)", "updateMaxTxnAmount(uint256)"},
+	},
+	{
+		Code:       "pausable",
+		Severity:   SeverityMedium,
+		Message:    "transfers can be paused",
+		Pattern:    regexp.MustCompile(`^pause$`),
+		Signatures: []string{"pause()"},
+	},
+	{
+		Code:       "mintable",
+		Severity:   SeverityLow,
+		Message:    "supply can be increased by a privileged account",
+		Pattern:    regexp.MustCompile(`^mint$`),
+		Signatures: []string{"mint(address,uint256)", "mint(uint256)"},
+	},
+}
+
+// Inspect reads token metadata, total supply, owner, and EIP-1967 proxy
+// state. It returns the runtime bytecode of the logic contract (the
+// implementation for proxies) for Assess.
+func Inspect(ctx context.Context, backend Backend, chain id.Chain, token common.Address) (model.TokenSafetyReport, []byte, error) {
+	code, err := backend.CodeAt(ctx, token, nil)
+	if err != nil {
+		return model.TokenSafetyReport{}, nil, clierr.Wrap(clierr.CodeUnavailable, "read token code", err)
+	}
+	if len(code) == 0 {
+		return model.TokenSafetyReport{}, nil, clierr.New(clierr.CodeUsage, fmt.Sprintf("%s has no contract code on %s", token.Hex(), chain.CAIP2))
+	}
+	name, symbol, decimals, err := tokenlookup.ReadERC20(ctx, backend, token)
+	if err != nil {
+		return model.TokenSafetyReport{}, nil, clierr.Wrap(clierr.CodeUnsupported, "read token metadata", err)
+	}
+	supply, err := callUint(ctx, backend, token, supplyABI, "totalSupply")
+	if err != nil {
+		return model.TokenSafetyReport{}, nil, clierr.Wrap(clierr.CodeUnsupported, "read totalSupply()", err)
+	}
+
+	address := strings.ToLower(token.Hex())
+	report := model.TokenSafetyReport{
+		ChainID:  chain.CAIP2,
+		AssetID:  fmt.Sprintf("%s/erc20:%s", chain.CAIP2, address),
+		Address:  address,
+		Name:     name,
+		Symbol:   symbol,
+		Decimals: decimals,
+		TotalSupply: model.AmountInfo{
+			AmountBaseUnits: supply.String(),
+			AmountDecimal:   id.FormatDecimalCompat(supply.String(), decimals),
+			Decimals:        decimals,
+		},
+		Verification: Unknown,
+		Flags:        []model.TokenSafetyFlag{},
+	}
+	// Tokens without owner() are not an error; Ownable is optional.
+	if owner, err := callAddress(ctx, backend, token, ownableABI, "owner"); err == nil {
+		report.Owner = strings.ToLower(owner.Hex())
+		report.OwnerRenounced = owner == (common.Address{})
+	}
+
+	implementation, err := proxyImplementation(ctx, backend, token)
+	if err != nil {
+		return model.TokenSafetyReport{}, nil, err
+	}
+	if implementation != (common.Address{}) {
+		report.IsProxy = true
+		report.Implementation = strings.ToLower(implementation.Hex())
+		if code, err = backend.CodeAt(ctx, implementation, nil); err != nil {
+			return model.TokenSafetyReport{}, nil, clierr.Wrap(clierr.CodeUnavailable, "read implementation code", err)
+		}
+	}
+	return report, code, nil
+}
+
+// proxyImplementation follows the EIP-1967 implementation slot, then the
+// beacon slot. It returns the zero address for non-proxies.
+func proxyImplementation(ctx context.Context, backend Backend, token common.Address) (common.Address, error) {
+	raw, err := backend.StorageAt(ctx, token, implementationSlot, nil)
+	if err != nil {
+		return common.Address{}, clierr.Wrap(clierr.CodeUnavailable, "read proxy implementation slot", err)
+	}
+	if implementation := common.BytesToAddress(raw); implementation != (common.Address{}) {
+		return implementation, nil
+	}
+	raw, err = backend.StorageAt(ctx, token, beaconSlot, nil)
+	if err != nil {
+		return common.Address{}, clierr.Wrap(clierr.CodeUnavailable, "read proxy beacon slot", err)
+	}
+	beacon := common.BytesToAddress(raw)
+	if beacon == (common.Address{}) {
+		return common.Address{}, nil
+	}
+	implementation, err := callAddress(ctx, backend, beacon, beaconABI, "implementation")
+	if err != nil {
+		return common.Address{}, clierr.Wrap(clierr.CodeUnavailable, "read beacon implementation", err)
+	}
+	return implementation, nil
+}
+
+// Assess adds heuristic flags to report from the logic contract's bytecode
+// and, for verified contracts, the ABIs the explorer returned. It sets the
+// report's risk level; callers set Verification first.
+func Assess(report *model.TokenSafetyReport, code []byte, abis ...string) {
+	flags := []model.TokenSafetyFlag{}
+	add := func(code, severity, message string) {
+		for _, flag := range flags {
+			if flag.Code == code {
+				return
+			}
+		}
+		flags = append(flags, model.TokenSafetyFlag{Code: code, Severity: severity, Message: message})
+	}
+
+	switch report.Verification {
+	case Unverified:
+		add("unverified_source", SeverityHigh, "contract source is not verified on the explorer; behavior cannot be reviewed")
+	case Unknown:
+		add("verification_unknown", SeverityMedium, "explorer verification could not be checked")
+	}
+	if report.IsProxy && !report.OwnerRenounced {
+		add("upgradeable", SeverityMedium, "token is an upgradeable proxy; its logic can change")
+	}
+	if report.Owner != "" && !report.OwnerRenounced {
+		add("owner_controlled", SeverityLow, "token has an active owner "+report.Owner)
+	}
+
+	selectors := pushedSelectors(code)
+	names := abiFunctionNames(abis)
+	for _, r := range rules {
+		if matchesRule(r, selectors, names) {
+			add(r.Code, r.Severity, r.Message)
+		}
+	}
+
+	report.Flags = flags
+	report.RiskLevel = SeverityLow
+	for _, flag := range flags {
+		if severityRank(flag.Severity) > severityRank(report.RiskLevel) {
+			report.RiskLevel = flag.Severity
+		}
+	}
+}
+
+func matchesRule(r rule, selectors map[[4]byte]bool, names []string) bool {
+	for _, signature := range r.Signatures {
+		var selector [4]byte
+		copy(selector[:], crypto.Keccak256([]byte(signature))[:4])
+		if selectors[selector] {
+			return true
+		}
+	}
+	for _, name := range names {
+		if r.Pattern.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// pushedSelectors collects every PUSH4 operand in runtime bytecode, which is
+// how Solidity dispatchers compare function selectors. Other PUSH operands
+// are skipped so data bytes are not read as opcodes.
+func pushedSelectors(code []byte) map[[4]byte]bool {
+	out := map[[4]byte]bool{}
+	for i := 0; i < len(code); i++ {
+		op := code[i]
+		if op < 0x60 || op > 0x7f {
+			continue
+		}
+		size := int(op-0x60) + 1
+		if op == 0x63 && i+4 < len(code) {
+			var selector [4]byte
+			copy(selector[:], code[i+1:i+5])
+			out[selector] = true
+		}
+		i += size
+	}
+	return out
+}
+
+// abiFunctionNames lists non-view function names across ABIs; read-only
+// getters such as buyFee() say nothing about who can change the value.
+func abiFunctionNames(abis []string) []string {
+	var names []string
+	for _, raw := range abis {
+		var entries []struct {
+			Type            string `json:"type"`
+			Name            string `json:"name"`
+			StateMutability string `json:"stateMutability"`
+			Constant        bool   `json:"constant"`
+		}
+		if err := json.Unmarshal([]byte(raw), &entries); err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if entry.Type != "function" || entry.Constant || entry.StateMutability == "view" || entry.StateMutability == "pure" {
+				continue
+			}
+			names = append(names, entry.Name)
+		}
+	}
+	return names
+}
+
+func severityRank(severity string) int {
+	switch severity {
+	case SeverityHigh:
+		return 3
+	case SeverityMedium:
+		return 2
+	case SeverityLow:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func callUint(ctx context.Context, caller ethereum.ContractCaller, target common.Address, contract abi.ABI, method string) (*big.Int, error) {
+	values, err := call(ctx, caller, target, contract, method)
+	if err != nil {
+		return nil, err
+	}
+	value, ok := values[0].(*big.Int)
+	if !ok {
+		return nil, fmt.Errorf("decode %s(): unexpected type %T", method, values[0])
+	}
+	return value, nil
+}
+
+func callAddress(ctx context.Context, caller ethereum.ContractCaller, target common.Address, contract abi.ABI, method string) (common.Address, error) {
+	values, err := call(ctx, caller, target, contract, method)
+	if err != nil {
+		return common.Address{}, err
+	}
+	value, ok := values[0].(common.Address)
+	if !ok {
+		return common.Address{}, fmt.Errorf("decode %s(): unexpected type %T", method, values[0])
+	}
+	return value, nil
+}
+
+func call(ctx context.Context, caller ethereum.ContractCaller, target common.Address, contract abi.ABI, method string) ([]any, error) {
+	data, err := contract.Pack(method)
+	if err != nil {
+		return nil, err
+	}
+	out, err := caller.CallContract(ctx, ethereum.CallMsg{To: &target, Data: data}, nil)
+	if err != nil {
+		return nil, err
+	}
+	values, err := contract.Unpack(method, out)
+	if err != nil || len(values) != 1 {
+		return nil, fmt.Errorf("decode %s(): %v", method, err)
+	}
+	return values, nil
+}
+
+func mustABI(raw string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(raw))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
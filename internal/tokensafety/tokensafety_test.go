@@ -0,0 +1,147 @@
+package tokensafety
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ggonzalez94/defi-cli/internal/id"
+	"github.com/ggonzalez94/defi-cli/internal/model"
+)
+
+var (
+	testToken = common.HexToAddress("0x00000000000000000000000000000000000000a1")
+	testImpl  = common.HexToAddress("0x00000000000000000000000000000000000000b2")
+	testOwner = common.HexToAddress("0x00000000000000000000000000000000000000c3")
+	metaABI   = mustABI(`[
+		{"name":"name","type":"function","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"string"}]},
+		{"name":"symbol","type":"function","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"string"}]},
+		{"name":"decimals","type":"function","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint8"}]}
+	]`)
+)
+
+// dispatcher returns bytecode that PUSH4es each selector, the way a Solidity
+// function dispatcher does.
+func dispatcher(signatures ...string) []byte {
+	code := []byte{0x60, 0x80, 0x60, 0x40, 0x52} // PUSH1 0x80 PUSH1 0x40 MSTORE
+	for _, signature := range signatures {
+		code = append(code, 0x63)
+		code = append(code, crypto.Keccak256([]byte(signature))[:4]...)
+		code = append(code, 0x14) // EQ
+	}
+	return code
+}
+
+type fakeBackend struct {
+	code    map[common.Address][]byte
+	storage map[common.Hash]common.Hash
+	owner   *common.Address
+}
+
+func (f fakeBackend) CodeAt(_ context.Context, account common.Address, _ *big.Int) ([]byte, error) {
+	return f.code[account], nil
+}
+
+func (f fakeBackend) StorageAt(_ context.Context, _ common.Address, key common.Hash, _ *big.Int) ([]byte, error) {
+	value := f.storage[key]
+	return value.Bytes(), nil
+}
+
+func (f fakeBackend) CallContract(_ context.Context, msg ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	for _, contract := range []abi.ABI{metaABI, supplyABI, ownableABI} {
+		method, err := contract.MethodById(msg.Data[:4])
+		if err != nil {
+			continue
+		}
+		switch method.Name {
+		case "name":
+			return method.Outputs.Pack("Moon Token")
+		case "symbol":
+			return method.Outputs.Pack("MOON")
+		case "decimals":
+			return method.Outputs.Pack(uint8(9))
+		case "totalSupply":
+			return method.Outputs.Pack(new(big.Int).Mul(big.NewInt(1_000_000), big.NewInt(1_000_000_000)))
+		case "owner":
+			if f.owner == nil {
+				return nil, errors.New("execution reverted")
+			}
+			return method.Outputs.Pack(*f.owner)
+		}
+	}
+	return nil, errors.New("execution reverted")
+}
+
+func TestInspectFollowsProxyAndReadsMetadata(t *testing.T) {
+	chain, _ := id.ParseChain("base")
+	backend := fakeBackend{
+		code: map[common.Address][]byte{
+			testToken: {0x36, 0x3d},
+			testImpl:  dispatcher("transfer(address,uint256)", "setFee(uint256)"),
+		},
+		storage: map[common.Hash]common.Hash{implementationSlot: common.BytesToHash(testImpl.Bytes())},
+		owner:   &testOwner,
+	}
+	report, code, err := Inspect(context.Background(), backend, chain, testToken)
+	if err != nil {
+		t.Fatalf("Inspect failed: %v", err)
+	}
+	if report.Symbol != "MOON" || report.Decimals != 9 || report.TotalSupply.AmountDecimal != "1000000" {
+		t.Fatalf("unexpected metadata %+v", report)
+	}
+	if !report.IsProxy || report.Implementation != "0x00000000000000000000000000000000000000b2" || report.OwnerRenounced {
+		t.Fatalf("expected an owned EIP-1967 proxy, got %+v", report)
+	}
+
+	report.Verification = Verified
+	Assess(&report, code)
+	codes := map[string]string{}
+	for _, flag := range report.Flags {
+		codes[flag.Code] = flag.Severity
+	}
+	if codes["transfer_tax"] != SeverityHigh || codes["upgradeable"] != SeverityMedium || codes["owner_controlled"] != SeverityLow {
+		t.Fatalf("expected tax, upgradeable, and owner flags from implementation bytecode, got %+v", report.Flags)
+	}
+	if report.RiskLevel != SeverityHigh {
+		t.Fatalf("expected high risk, got %s", report.RiskLevel)
+	}
+}
+
+func TestAssessUsesVerifiedABINamesAndIgnoresGetters(t *testing.T) {
+	report := model.TokenSafetyReport{Verification: Verified, Owner: "0x0000000000000000000000000000000000000000", OwnerRenounced: true}
+	Assess(&report, dispatcher("transfer(address,uint256)"), `[
+		{"type":"function","name":"buyTaxBps","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint256"}]},
+		{"type":"function","name":"addToBlocklist","stateMutability":"nonpayable","inputs":[{"name":"account","type":"address"}],"outputs":[]}
+	]`)
+	if len(report.Flags) != 1 || report.Flags[0].Code != "blacklist" || report.RiskLevel != SeverityMedium {
+		t.Fatalf("expected only the blocklist flag, got %+v", report)
+	}
+
+	clean := model.TokenSafetyReport{Verification: Unverified}
+	Assess(&clean, nil)
+	if clean.RiskLevel != SeverityHigh || clean.Flags[0].Code != "unverified_source" {
+		t.Fatalf("expected unverified source to be high risk, got %+v", clean)
+	}
+}
+
+func TestPushedSelectorsSkipsPushData(t *testing.T) {
+	selector := crypto.Keccak256([]byte("pause()"))[:4]
+	// The selector bytes sit inside a PUSH32 operand and must not be read as
+	// a PUSH4.
+	code := append([]byte{0x7f, 0x63}, selector...)
+	code = append(code, make([]byte, 27)...)
+	selectors := pushedSelectors(code)
+	var key [4]byte
+	copy(key[:], selector)
+	if selectors[key] {
+		t.Fatalf("read a selector out of PUSH32 data")
+	}
+	if !pushedSelectors(dispatcher("pause()"))[key] {
+		t.Fatalf("expected the dispatcher selector to be found")
+	}
+}
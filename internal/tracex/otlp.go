@@ -0,0 +1,187 @@
+package tracex
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ggonzalez94/defi-cli/internal/model"
+	"github.com/ggonzalez94/defi-cli/internal/version"
+)
+
+// OTLP span kinds and status codes.
+const (
+	otlpKindInternal = 1
+	otlpKindClient   = 3
+	otlpStatusOK     = 1
+	otlpStatusError  = 2
+)
+
+type otlpRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+type otlpSpan struct {
+	TraceID           string          `json:"traceId"`
+	SpanID            string          `json:"spanId"`
+	ParentSpanID      string          `json:"parentSpanId,omitempty"`
+	Name              string          `json:"name"`
+	Kind              int             `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []otlpAttribute `json:"attributes,omitempty"`
+	Status            otlpStatus      `json:"status"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpAttribute struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+}
+
+// Export posts trace to an OTLP/HTTP traces endpoint as JSON. The invocation
+// is the root span, named after command, and each HTTP attempt is a client
+// child span carrying its phase timings.
+func Export(ctx context.Context, client *http.Client, endpoint string, headers map[string]string, command string, failure error, trace model.Trace) error {
+	body, err := json.Marshal(otlpPayload(command, failure, trace))
+	if err != nil {
+		return fmt.Errorf("encode otlp trace: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build otlp request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("export trace: %w", err)
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("export trace: collector returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func otlpPayload(command string, failure error, trace model.Trace) otlpRequest {
+	end := trace.StartedAt.Add(time.Duration(trace.DurationMS * float64(time.Millisecond)))
+	root := otlpSpan{
+		TraceID:           trace.TraceID,
+		SpanID:            trace.SpanID,
+		ParentSpanID:      trace.ParentSpanID,
+		Name:              command,
+		Kind:              otlpKindInternal,
+		StartTimeUnixNano: unixNano(trace.StartedAt),
+		EndTimeUnixNano:   unixNano(end),
+		Attributes: []otlpAttribute{
+			stringAttr("defi.command", command),
+			intAttr("defi.http.requests", len(trace.Requests)),
+			intAttr("defi.cache.operations", len(trace.Cache)),
+		},
+		Status: otlpStatus{Code: otlpStatusOK},
+	}
+	if failure != nil {
+		root.Status = otlpStatus{Code: otlpStatusError, Message: failure.Error()}
+	}
+	spans := []otlpSpan{root}
+	for _, req := range trace.Requests {
+		span := otlpSpan{
+			TraceID:           trace.TraceID,
+			SpanID:            req.SpanID,
+			ParentSpanID:      trace.SpanID,
+			Name:              req.Method,
+			Kind:              otlpKindClient,
+			StartTimeUnixNano: unixNano(req.StartedAt),
+			EndTimeUnixNano:   unixNano(req.StartedAt.Add(time.Duration(req.TotalMS * float64(time.Millisecond)))),
+			Attributes: []otlpAttribute{
+				stringAttr("http.request.method", req.Method),
+				stringAttr("url.full", req.URL),
+				stringAttr("server.address", req.Host),
+				intAttr("http.request.resend_count", req.Attempt-1),
+				boolAttr("defi.http.reused_conn", req.ReusedConn),
+				doubleAttr("defi.http.dns_ms", req.DNSMS),
+				doubleAttr("defi.http.connect_ms", req.ConnectMS),
+				doubleAttr("defi.http.tls_ms", req.TLSMS),
+				doubleAttr("defi.http.ttfb_ms", req.TTFBMS),
+			},
+			Status: otlpStatus{Code: otlpStatusOK},
+		}
+		if req.Status > 0 {
+			span.Attributes = append(span.Attributes, intAttr("http.response.status_code", req.Status))
+		}
+		if req.Error != "" || req.Status >= 400 {
+			span.Status = otlpStatus{Code: otlpStatusError, Message: req.Error}
+		}
+		spans = append(spans, span)
+	}
+	return otlpRequest{ResourceSpans: []otlpResourceSpans{{
+		Resource: otlpResource{Attributes: []otlpAttribute{
+			stringAttr("service.name", version.CLIName),
+			stringAttr("service.version", version.CLIVersion),
+		}},
+		ScopeSpans: []otlpScopeSpans{{
+			Scope: otlpScope{Name: "github.com/ggonzalez94/defi-cli", Version: version.CLIVersion},
+			Spans: spans,
+		}},
+	}}}
+}
+
+func unixNano(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
+
+func stringAttr(key, value string) otlpAttribute {
+	return otlpAttribute{Key: key, Value: otlpValue{StringValue: &value}}
+}
+
+func intAttr(key string, value int) otlpAttribute {
+	s := strconv.Itoa(value)
+	return otlpAttribute{Key: key, Value: otlpValue{IntValue: &s}}
+}
+
+func doubleAttr(key string, value float64) otlpAttribute {
+	return otlpAttribute{Key: key, Value: otlpValue{DoubleValue: &value}}
+}
+
+func boolAttr(key string, value bool) otlpAttribute {
+	return otlpAttribute{Key: key, Value: otlpValue{BoolValue: &value}}
+}
@@ -0,0 +1,194 @@
+// Package tracex records the opt-in request trace reported in meta.trace
+// (--trace): per-attempt HTTP phase timings from net/http/httptrace and cache
+// operation timings. Trace and span IDs follow W3C Trace Context so a trace
+// joins the caller's when TRACEPARENT is set, and Export sends it to an OTLP
+// collector.
+package tracex
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptrace"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ggonzalez94/defi-cli/internal/logx"
+	"github.com/ggonzalez94/defi-cli/internal/model"
+	"github.com/ggonzalez94/defi-cli/internal/randx"
+)
+
+// Recorder collects the spans of one invocation.
+type Recorder struct {
+	mu       sync.Mutex
+	trace    model.Trace
+	started  time.Time
+	finished bool
+}
+
+var active atomic.Pointer[Recorder]
+
+// New starts a trace. A valid traceparent makes the trace a child of the
+// caller's span; an invalid one is ignored, as W3C Trace Context requires.
+func New(traceparent string) *Recorder {
+	traceID, parentID, ok := ParseTraceparent(traceparent)
+	if !ok {
+		traceID, parentID = newID(16), ""
+	}
+	now := time.Now()
+	return &Recorder{
+		started: now,
+		trace: model.Trace{
+			TraceID:      traceID,
+			SpanID:       newID(8),
+			ParentSpanID: parentID,
+			StartedAt:    now.UTC(),
+		},
+	}
+}
+
+// Set installs the process-wide recorder. nil stops tracing.
+func Set(r *Recorder) {
+	active.Store(r)
+}
+
+// Active returns the process-wide recorder, or nil when tracing is off.
+func Active() *Recorder {
+	return active.Load()
+}
+
+// Finish ends the root span and returns the trace. Later calls return the
+// same trace with the original duration.
+func (r *Recorder) Finish() model.Trace {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.finished {
+		r.trace.DurationMS = millis(time.Since(r.started))
+		r.finished = true
+	}
+	out := r.trace
+	out.Requests = append([]model.TraceRequest(nil), r.trace.Requests...)
+	out.Cache = append([]model.TraceCache(nil), r.trace.Cache...)
+	return out
+}
+
+// RecordCache records a cache operation on the active recorder, if any.
+func RecordCache(op, status string, d time.Duration) {
+	r := Active()
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	r.trace.Cache = append(r.trace.Cache, model.TraceCache{Op: op, Status: status, DurationMS: millis(d)})
+	r.mu.Unlock()
+}
+
+// StartRequest traces one HTTP attempt on the active recorder. It returns a
+// context carrying an httptrace.ClientTrace and a function that records the
+// outcome. Without an active recorder ctx is returned unchanged and done is a
+// no-op.
+func StartRequest(ctx context.Context, req *http.Request, attempt int) (context.Context, func(status, bytes int, err error)) {
+	r := Active()
+	if r == nil {
+		return ctx, func(int, int, error) {}
+	}
+	span := model.TraceRequest{
+		SpanID:    newID(8),
+		Method:    req.Method,
+		URL:       logx.SanitizeURL(req.URL.String()),
+		Host:      req.URL.Host,
+		Attempt:   attempt,
+		StartedAt: time.Now().UTC(),
+	}
+	var (
+		mu                                  sync.Mutex
+		start                               = time.Now()
+		dnsStart, connectStart, tlsStart    time.Time
+		dnsDur, connectDur, tlsDur, ttfbDur time.Duration
+		reused                              bool
+	)
+	stamp := func(at *time.Time) {
+		mu.Lock()
+		*at = time.Now()
+		mu.Unlock()
+	}
+	since := func(d *time.Duration, from *time.Time) {
+		mu.Lock()
+		*d = time.Since(*from)
+		mu.Unlock()
+	}
+	clientTrace := &httptrace.ClientTrace{
+		DNSStart:          func(httptrace.DNSStartInfo) { stamp(&dnsStart) },
+		DNSDone:           func(httptrace.DNSDoneInfo) { since(&dnsDur, &dnsStart) },
+		ConnectStart:      func(string, string) { stamp(&connectStart) },
+		ConnectDone:       func(string, string, error) { since(&connectDur, &connectStart) },
+		TLSHandshakeStart: func() { stamp(&tlsStart) },
+		TLSHandshakeDone:  func(tls.ConnectionState, error) { since(&tlsDur, &tlsStart) },
+		GotConn: func(info httptrace.GotConnInfo) {
+			mu.Lock()
+			reused = info.Reused
+			mu.Unlock()
+		},
+		GotFirstResponseByte: func() { since(&ttfbDur, &start) },
+	}
+	done := func(status, bytes int, err error) {
+		mu.Lock()
+		span.Status = status
+		span.Bytes = bytes
+		if err != nil {
+			span.Error = err.Error()
+		}
+		span.ReusedConn = reused
+		span.DNSMS = millis(dnsDur)
+		span.ConnectMS = millis(connectDur)
+		span.TLSMS = millis(tlsDur)
+		span.TTFBMS = millis(ttfbDur)
+		span.TotalMS = millis(time.Since(start))
+		mu.Unlock()
+		r.mu.Lock()
+		r.trace.Requests = append(r.trace.Requests, span)
+		r.mu.Unlock()
+	}
+	return httptrace.WithClientTrace(ctx, clientTrace), done
+}
+
+// ParseTraceparent extracts the trace ID and parent span ID from a W3C
+// traceparent header value (version-traceid-spanid-flags).
+func ParseTraceparent(raw string) (traceID, parentID string, ok bool) {
+	parts := strings.Split(strings.TrimSpace(raw), "-")
+	if len(parts) < 4 {
+		return "", "", false
+	}
+	version, traceID, parentID, flags := parts[0], strings.ToLower(parts[1]), strings.ToLower(parts[2]), parts[3]
+	if !isHex(version, 2) || version == "ff" || (version == "00" && len(parts) != 4) {
+		return "", "", false
+	}
+	if !isHex(traceID, 32) || !isHex(parentID, 16) || !isHex(flags, 2) {
+		return "", "", false
+	}
+	if strings.Trim(traceID, "0") == "" || strings.Trim(parentID, "0") == "" {
+		return "", "", false
+	}
+	return traceID, parentID, true
+}
+
+func isHex(s string, n int) bool {
+	if len(s) != n {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
+
+func newID(n int) string {
+	buf := make([]byte, n)
+	_, _ = randx.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+func millis(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
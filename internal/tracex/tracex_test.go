@@ -0,0 +1,123 @@
+package tracex
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ggonzalez94/defi-cli/internal/model"
+)
+
+func TestParseTraceparent(t *testing.T) {
+	traceID, parentID, ok := ParseTraceparent("00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01")
+	if !ok || traceID != "4bf92f3577b34da6a3ce929d0e0e4736" || parentID != "00f067aa0ba902b7" {
+		t.Fatalf("unexpected parse: %q %q %v", traceID, parentID, ok)
+	}
+	for _, raw := range []string{
+		"",
+		"garbage",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+	} {
+		if _, _, ok := ParseTraceparent(raw); ok {
+			t.Fatalf("expected %q to be rejected", raw)
+		}
+	}
+	rec := New("not-a-traceparent")
+	if len(rec.trace.TraceID) != 32 || len(rec.trace.SpanID) != 16 || rec.trace.ParentSpanID != "" {
+		t.Fatalf("expected fresh trace IDs, got %+v", rec.trace)
+	}
+}
+
+func TestStartRequestRecordsPhases(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer srv.Close()
+
+	ctx, done := StartRequest(context.Background(), httptest.NewRequest(http.MethodGet, srv.URL, nil), 1)
+	done(200, 0, nil) // no active recorder: a no-op
+	if ctx != context.Background() {
+		t.Fatal("expected ctx to be unchanged without an active recorder")
+	}
+
+	rec := New("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	Set(rec)
+	t.Cleanup(func() { Set(nil) })
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+"/x?apikey=secret", nil)
+	ctx, done = StartRequest(context.Background(), req, 2)
+	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	done(resp.StatusCode, len(body), nil)
+	RecordCache("get", "miss", 1500*time.Microsecond)
+
+	trace := rec.Finish()
+	if trace.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" || trace.ParentSpanID != "00f067aa0ba902b7" {
+		t.Fatalf("expected trace to join the traceparent, got %+v", trace)
+	}
+	if len(trace.Requests) != 1 {
+		t.Fatalf("expected one request span, got %+v", trace.Requests)
+	}
+	span := trace.Requests[0]
+	if span.Status != 200 || span.Attempt != 2 || span.Bytes != len(body) || span.TotalMS <= 0 || span.TTFBMS <= 0 || span.ConnectMS <= 0 {
+		t.Fatalf("unexpected request span: %+v", span)
+	}
+	if span.URL != srv.URL+"/x?apikey=REDACTED" {
+		t.Fatalf("expected sanitized URL, got %s", span.URL)
+	}
+	if len(trace.Cache) != 1 || trace.Cache[0].Status != "miss" || trace.Cache[0].DurationMS != 1.5 {
+		t.Fatalf("unexpected cache spans: %+v", trace.Cache)
+	}
+}
+
+func TestExportPostsOTLPJSON(t *testing.T) {
+	var got otlpRequest
+	var contentType, auth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType, auth = r.Header.Get("Content-Type"), r.Header.Get("Authorization")
+		_ = json.NewDecoder(r.Body).Decode(&got)
+	}))
+	defer srv.Close()
+
+	rec := New("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	trace := rec.Finish()
+	trace.Requests = []model.TraceRequest{{SpanID: "b7ad6b7169203331", Method: http.MethodGet, URL: "https://api.example.com/q", Host: "api.example.com", Attempt: 2, Status: 502, StartedAt: trace.StartedAt, TotalMS: 12.5}}
+	err := Export(context.Background(), srv.Client(), srv.URL, map[string]string{"Authorization": "Bearer x"}, "swap quote", errors.New("boom"), trace)
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	if contentType != "application/json" || auth != "Bearer x" {
+		t.Fatalf("unexpected headers: %q %q", contentType, auth)
+	}
+	spans := got.ResourceSpans[0].ScopeSpans[0].Spans
+	if len(spans) != 2 {
+		t.Fatalf("expected root and request spans, got %d", len(spans))
+	}
+	root, child := spans[0], spans[1]
+	if root.TraceID != trace.TraceID || root.ParentSpanID != "00f067aa0ba902b7" || root.Name != "swap quote" || root.Status.Code != otlpStatusError {
+		t.Fatalf("unexpected root span: %+v", root)
+	}
+	if child.ParentSpanID != root.SpanID || child.Kind != otlpKindClient || child.Status.Code != otlpStatusError {
+		t.Fatalf("unexpected request span: %+v", child)
+	}
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer failing.Close()
+	if err := Export(context.Background(), failing.Client(), failing.URL, nil, "swap quote", nil, trace); err == nil {
+		t.Fatal("expected export error on collector failure")
+	}
+}